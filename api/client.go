@@ -5,11 +5,28 @@ package api
 import (
 	"context"
 	"fmt"
+	"io"
 	"sync"
+	"time"
 
 	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system"
+	"github.com/Corphon/daoflow/system/common/scheduler"
+	"github.com/Corphon/daoflow/system/control/modulation"
+	"github.com/Corphon/daoflow/system/control/watchdog"
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/evolution/audit"
+	"github.com/Corphon/daoflow/system/evolution/causal"
+	"github.com/Corphon/daoflow/system/evolution/constraint"
+	"github.com/Corphon/daoflow/system/evolution/pattern"
+	"github.com/Corphon/daoflow/system/meta"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/monitor/drift"
+	"github.com/Corphon/daoflow/system/monitor/flow"
+	"github.com/Corphon/daoflow/system/monitor/trace"
+	"github.com/Corphon/daoflow/system/observer"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -227,7 +244,8 @@ func (c *Client) Shutdown(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 	// 关闭整个系统并释放所有资源，包括停止所有子系统、组件和服务。
-	// 此方法执行有序关闭，确保数据完整性和资源正确释放。
+	// 此方法执行有序关闭，确保数据完整性和资源正确释放: 停止前先排空(见Drain)，
+	// 排空报告可通过GetSystem().LastDrainReport()获取。
 	// 会等待所有组件完成关闭操作或超时(默认30秒)。
 	//
 	// 参数:
@@ -258,6 +276,22 @@ func (c *Client) Shutdown(ctx context.Context) error {
 	return c.sys.Shutdown(ctx)
 }
 
+// Drain 关闭前排空
+func (c *Client) Drain(ctx context.Context) system.DrainReport {
+	// 不再接收外部事件和观测注入, 等待进行中的模式检测、学习周期和定时任务完成,
+	// 并刷写场状态快照日志、追踪记录和基线。各组件依次排空, ctx到期后其余组件记为timed_out。
+	// 排空后系统仍在运行, 完成时发出 system.drained 事件; Shutdown会自动先排空。
+	//
+	// 示例:
+	//   ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	//   defer cancel()
+	//   report := client.Drain(ctx)
+	//   for _, c := range report.Components {
+	//       fmt.Printf("%s: %s (%s)\n", c.Component, c.Status, c.Duration)
+	//   }
+	return c.sys.Drain(ctx)
+}
+
 //------------------------------------------
 
 // GetSystem 获取system实例
@@ -467,6 +501,728 @@ func (c *Client) UnregisterModel(name string) error {
 	return c.sys.UnregisterModel(name)
 }
 
+// ModelCapabilities 获取模型支持的能力
+func (c *Client) ModelCapabilities(name string) ([]model.Capability, error) {
+	// 返回模型按接口检测到的能力以及模型自行声明的能力，按名称排序。
+	//
+	// 示例:
+	//   caps, err := client.ModelCapabilities("integrate")
+	//   if err == nil && model.HasCapability(m, model.CapTransactional) {
+	//       // 模型支持两阶段转换
+	//   }
+	return c.sys.ModelCapabilities(name)
+}
+
+// FindModels 查找支持指定能力的模型
+func (c *Client) FindModels(capability model.Capability) []string {
+	// 返回支持该能力的模型名称列表，按名称排序。
+	// 需要具体接口时可使用 system.GetModelsWithCapability[T]。
+	//
+	// 示例:
+	//   for _, name := range client.FindModels(model.CapEnergyBearing) {
+	//       fmt.Printf("可调整能量的模型: %s\n", name)
+	//   }
+	return c.sys.FindModels(capability)
+}
+
+// SetModulationCurve 设置参数时间调制曲线
+func (c *Client) SetModulationCurve(target string, curve types.CurveConfig) error {
+	// 让检测灵敏度、模式阈值或学习率随时间周期变化，曲线值为作用于基准值的倍率。
+	// 调制目标: system.ModulateSensitivity、ModulateMinConfidence、
+	// ModulatePatternThreshold、ModulateLearningRate。需在控制配置中启用Modulation。
+	//
+	// 示例:
+	//   // 夜间(子时前后)提高检测灵敏度
+	//   err := client.SetModulationCurve(system.ModulateSensitivity, types.CurveConfig{
+	//       Kind:    types.CurveGanZhi,
+	//       Cycle:   "hour",
+	//       Factors: []float64{1.2, 1.1, 1, 1, 1, 0.9, 0.9, 0.9, 1, 1, 1, 1.1},
+	//   })
+	return c.sys.SetModulationCurve(target, curve)
+}
+
+// ModulationValues 获取参数调制结果
+func (c *Client) ModulationValues() []modulation.Value {
+	// 返回各调制目标最近一次的基准值、倍率和调制后的值，按名称排序。
+	return c.sys.ModulationValues()
+}
+
+// PatternCorrelations 计算活跃模式间的互相关矩阵
+func (c *Client) PatternCorrelations(opts emergence.CorrelationOptions) (emergence.CorrelationMatrix, error) {
+	// 在分析窗口内对齐各活跃模式的演化序列，计算带滞后的两两相关系数，
+	// 并标记相关显著且存在滞后的领先/滞后关系，用于发现驱动其他模式的模式。
+	//
+	// 示例:
+	//   matrix, err := client.PatternCorrelations(emergence.CorrelationOptions{
+	//       Series: emergence.SeriesEnergy,
+	//       Window: 30 * time.Minute,
+	//   })
+	//   for _, r := range matrix.Relations {
+	//       fmt.Printf("%s 领先 %s %v (r=%.2f)\n", r.Leader, r.Follower, r.Delay, r.Correlation)
+	//   }
+	return c.sys.PatternCorrelations(opts)
+}
+
+// ExportPatterns 分页导出全局和各命名空间的活跃模式
+func (c *Client) ExportPatterns(q emergence.ExportQuery) ([]emergence.PatternRecord, string, error) {
+	// 模式按命名空间和ID排序，返回的游标传入下一次查询的Cursor续取，游标为空表示已取完。
+	// 启用仪表盘后同样的数据以NDJSON形式在/api/export/patterns提供，下一页游标在X-Next-Cursor响应头中。
+	//
+	// 示例:
+	//   q := emergence.ExportQuery{Since: time.Now().Add(-time.Hour), Limit: 500}
+	//   for {
+	//       records, next, err := client.ExportPatterns(q)
+	//       if err != nil {
+	//           return err
+	//       }
+	//       save(records)
+	//       if next == "" {
+	//           break
+	//       }
+	//       q.Cursor = next
+	//   }
+	return c.sys.ExportPatterns(q)
+}
+
+// ExportEvolution 分页导出活跃模式的演化历史
+func (c *Client) ExportEvolution(q emergence.ExportQuery) ([]emergence.EvolutionRecord, string, error) {
+	// 演化状态按命名空间、模式ID和时间排序，较早部分为降采样摘要(Summary为true，Samples为合并的状态数)。
+	// 仪表盘端点为/api/export/evolution，分页方式与ExportPatterns相同。
+	return c.sys.ExportEvolution(q)
+}
+
+// Backfill 以记录的场状态快照离线重新执行模式检测
+func (c *Client) Backfill(ctx context.Context, ns types.Namespace, source emergence.SnapshotSource, opts emergence.BackfillOptions) (*emergence.BackfillResult, error) {
+	// 配置MetaConfig.Emergence.Journal后，每次检测取得的场状态按行写入快照日志。
+	// 回填按时间顺序消费快照，以新的阈值重新检测，检测时间取快照时间；
+	// 活跃模式、演化历史和检测历史写入新建的命名空间ns，不影响在线检测，可用ExportPatterns等按命名空间导出。
+	//
+	// 示例:
+	//   f, _ := os.Open("field.journal")
+	//   defer f.Close()
+	//   result, err := client.Backfill(ctx, "backfill_0.6", emergence.NewJournalSource(f),
+	//       emergence.BackfillOptions{Sensitivity: 0.6, From: time.Now().Add(-24 * time.Hour)})
+	//   fmt.Printf("%d 个快照, 检测到 %d 个模式\n", result.Snapshots, result.Detected)
+	return c.sys.Backfill(ctx, ns, source, opts)
+}
+
+// SetObjective 设置适应目标
+func (c *Client) SetObjective(objective adaptation.Objective) error {
+	// 目标定义系统状态指标的期望方向和权重, 策略执行后按目标达成度评分,
+	// 评分替代执行成功标志用于策略有效性、规则评分和学习反馈。
+	//
+	// 示例:
+	//   client.SetObjective(adaptation.Objective{Name: "stable", Metric: "stability", Direction: adaptation.DirectionMaximize})
+	//   client.SetObjective(adaptation.Objective{Name: "energy", Metric: "energy", Direction: adaptation.DirectionRange, Min: 0.4, Max: 0.6, Weight: 2})
+	return c.sys.SetObjective(objective)
+}
+
+// RemoveObjective 移除适应目标
+func (c *Client) RemoveObjective(name string) {
+	c.sys.RemoveObjective(name)
+}
+
+// Objectives 获取适应目标
+func (c *Client) Objectives() []adaptation.Objective {
+	return c.sys.Objectives()
+}
+
+// BanditStats 获取策略选择的多臂老虎机统计
+func (c *Client) BanditStats() (adaptation.BanditStats, bool) {
+	// 配置EvoConfig.Bandit.Policy后, 每个执行周期由老虎机从适用策略中选择一个执行:
+	// epsilon_greedy以适应学习的ExplorationRate随机探索, ucb按置信上界探索。
+	// 执行得分(定义适应目标时为目标达成度)作为奖励计入策略及其关联规则。
+	//
+	// 示例:
+	//   if stats, ok := client.BanditStats(); ok {
+	//       for _, arm := range stats.Strategies {
+	//           fmt.Printf("%s: %d次, 平均奖励%.2f\n", arm.ID, arm.Pulls, arm.Mean)
+	//       }
+	//   }
+	return c.sys.BanditStats()
+}
+
+// EvaluateCounterfactuals 评估适应决策的反事实处理效应
+func (c *Client) EvaluateCounterfactuals(ctx context.Context, since time.Time) (*adaptation.CounterfactualReport, error) {
+	// 对since之后执行完成且按适应目标评分的决策, 在模拟沙箱中从决策前记录的状态推演不执行动作的结果,
+	// 实际目标达成度减去推演的达成度为处理效应。默认沙箱按未执行策略的周期之间的指标变化率外推,
+	// 可通过SetCounterfactualSimulator替换。按策略和规则汇总的效应中, Retire标记决策数足够而效应
+	// 置信上界不为正的策略或规则, 可作为退役候选。
+	//
+	// 示例:
+	//   report, _ := client.EvaluateCounterfactuals(ctx, time.Now().Add(-24*time.Hour))
+	//   for _, rule := range report.Rules {
+	//       if rule.Retire {
+	//           fmt.Printf("%s: %d次决策, 平均效应%.3f\n", rule.ID, rule.Decisions, rule.Mean)
+	//       }
+	//   }
+	return c.sys.EvaluateCounterfactuals(ctx, since)
+}
+
+// EvaluateCounterfactual 评估单个适应决策的反事实处理效应
+func (c *Client) EvaluateCounterfactual(ctx context.Context, decision adaptation.StrategyEvent) (adaptation.Counterfactual, error) {
+	return c.sys.EvaluateCounterfactual(ctx, decision)
+}
+
+// SetCounterfactualSimulator 设置反事实回放的模拟沙箱
+func (c *Client) SetCounterfactualSimulator(simulator adaptation.Simulator) error {
+	return c.sys.SetCounterfactualSimulator(simulator)
+}
+
+// SetReinforcementPolicy 设置强化学习的价值模型
+func (c *Client) SetReinforcementPolicy(policy adaptation.ReinforcementPolicy) error {
+	// 强化学习模式以系统状态特征(energy, stability, entropy, harmony, balance)为观测,
+	// 策略数值参数的增减为动作, 适应目标的达成度为奖励。可通过AdaptationConfig.Reinforcement
+	// 启用内置的tabular或neural模型, 也可传入自定义模型。
+	//
+	// 示例:
+	//   client.SetReinforcementPolicy(adaptation.NewNeuralPolicy(16))
+	return c.sys.SetReinforcementPolicy(policy)
+}
+
+// ReinforcementStats 获取强化学习统计
+func (c *Client) ReinforcementStats() (adaptation.ReinforcementStats, bool) {
+	return c.sys.ReinforcementStats()
+}
+
+// FeatureReport 获取学习模型的特征报告
+func (c *Client) FeatureReport(top int) []adaptation.FeatureReport {
+	// 每次训练后在留出数据上计算特征的权重比例和置换重要性,
+	// 权重和置换重要性都低于Model.PruneThreshold的特征会被自动剪除。
+	//
+	// 示例:
+	//   for _, report := range client.FeatureReport(5) {
+	//       for _, f := range report.Top {
+	//           fmt.Printf("%s %s: 权重%.3f 置换重要性%.4f\n", report.ModelID, f.Feature, f.Weight, f.Permutation)
+	//       }
+	//   }
+	return c.sys.FeatureReport(top)
+}
+
+// SetMode 切换系统运行模式
+func (c *Client) SetMode(mode types.OperatingMode, reason string) error {
+	// 运行模式:
+	//   normal      正常运行
+	//   degraded    检测和因果发现循环放慢, 突变处理和适应策略执行停用, 外部转换被拒绝; 监控照常
+	//   maintenance 外部触发的转换返回ErrMaintenanceMode, 内部循环照常
+	//   read_only   外部转换返回ErrReadOnlyMode, 突变处理和适应策略执行停用
+	// 看门狗在子系统恢复重试耗尽时自动切换到degraded, 子系统恢复后自动回到normal。
+	//
+	// 示例:
+	//   client.SetMode(types.ModeMaintenance, "升级模型参数")
+	//   defer client.SetMode(types.ModeNormal, "维护完成")
+	return c.sys.SetMode(mode, reason)
+}
+
+// Mode 获取系统运行模式
+func (c *Client) Mode() types.ModeStatus {
+	return c.sys.Mode()
+}
+
+// OnAnalysis 订阅追踪分析结果
+func (c *Client) OnAnalysis(handler func(*trace.TraceAnalysis), filter ...trace.AnalysisFilter) (string, error) {
+	// 每个追踪分析完成后在订阅者独立的协程中异步投递, 缓冲满时丢弃新结果。
+	// 过滤条件可限定含异常的结果、异常严重程度下限以及追踪属性(跨度标签)。
+	//
+	// 示例:
+	//   id, err := client.OnAnalysis(func(a *trace.TraceAnalysis) {
+	//       dashboard.Push(a)
+	//   }, trace.AnalysisFilter{MinSeverity: 0.7, Attributes: map[string]string{"service": "flow"}})
+	//   defer client.RemoveAnalysisHandler(id)
+	return c.sys.OnAnalysis(handler, filter...)
+}
+
+// RemoveAnalysisHandler 取消追踪分析结果订阅
+func (c *Client) RemoveAnalysisHandler(id string) bool {
+	return c.sys.RemoveAnalysisHandler(id)
+}
+
+// RegisterAnalysisStage 注册自定义追踪分析阶段
+func (c *Client) RegisterAnalysisStage(name string, stage trace.Stage, options trace.StageOptions) error {
+	// 自定义阶段收到跨度和已完成前序阶段的分析结果副本, 计算结果写入Extensions。
+	// 默认在内置的系统、模型、量子和场动力学阶段之后执行, After可指定插入位置;
+	// 阶段受时间预算约束, 超时记入SkippedStages, 出错不影响其他阶段。
+	//
+	// 示例:
+	//   client.RegisterAnalysisStage("kpi", trace.StageFunc(func(a *trace.TraceAnalysis, spans []*trace.Span) error {
+	//       a.Extensions["error_rate"] = errorRate(spans)
+	//       return nil
+	//   }), trace.StageOptions{After: trace.StageSystem, Timeout: time.Second})
+	return c.sys.RegisterAnalysisStage(name, stage, options)
+}
+
+// UnregisterAnalysisStage 注销自定义追踪分析阶段
+func (c *Client) UnregisterAnalysisStage(name string) error {
+	return c.sys.UnregisterAnalysisStage(name)
+}
+
+// TraceRollups 查询追踪分析结果的小时或天汇总
+func (c *Client) TraceRollups(q trace.RollupQuery) []trace.Rollup {
+	// 每个时间桶汇总追踪数、按类型的异常数、平均纠缠度和相干性、按资源的瓶颈次数和按类型的模式次数。
+	// 汇总在分析结果缓存时累计, 不受缓存驱逐影响; 保留的桶数由MonitorConfig.Trace.Rollup配置。
+	//
+	// 示例:
+	//   for _, r := range client.TraceRollups(trace.RollupQuery{Granularity: trace.GranularityDay}) {
+	//       fmt.Printf("%s 追踪%d 异常%d\n", r.Start.Format("2006-01-02"), r.Traces, r.Anomalies)
+	//   }
+	return c.sys.TraceRollups(q)
+}
+
+// ExportTraceRollups 导出追踪分析结果汇总
+func (c *Client) ExportTraceRollups(w io.Writer, format string, q trace.RollupQuery) error {
+	// format为trace.FormatJSON或trace.FormatCSV。
+	//
+	// 示例:
+	//   f, _ := os.Create("rollups.csv")
+	//   defer f.Close()
+	//   client.ExportTraceRollups(f, trace.FormatCSV, trace.RollupQuery{From: time.Now().Add(-24 * time.Hour)})
+	return c.sys.ExportTraceRollups(w, format, q)
+}
+
+// TraceLatency 查询追踪耗时的分位数
+func (c *Client) TraceLatency(q trace.RollupQuery) types.LatencyQuantiles {
+	// 每个时间桶以对数分桶的分位数草图记录追踪耗时(相对误差由TraceRollupConfig.SketchAccuracy配置, 默认1%),
+	// 查询时合并范围内各时间桶的草图, 得到p50/p90/p99/p999而非只有平均值。
+	//
+	// 示例:
+	//   latency := client.TraceLatency(trace.RollupQuery{From: time.Now().Add(-24 * time.Hour)})
+	//   fmt.Printf("p50=%s p99=%s p999=%s\n", latency.P50, latency.P99, latency.P999)
+	return c.sys.TraceLatency(q)
+}
+
+// FlameGraph 获取追踪的火焰图数据
+func (c *Client) FlameGraph(ctx context.Context, traceID types.TraceID) (*trace.FlameGraph, error) {
+	// 按父子关系把跨度合并为调用栈, 每帧给出自身耗时和总耗时; 名称路径相同的跨度合并计数。
+	//
+	// 示例:
+	//   graph, err := client.FlameGraph(ctx, traceID)
+	//   for _, f := range graph.Frames {
+	//       fmt.Printf("%s self=%s total=%s\n", strings.Join(f.Stack, ";"), f.Self, f.Total)
+	//   }
+	return c.sys.FlameGraph(ctx, traceID)
+}
+
+// ExportFlameGraph 导出追踪的火焰图数据
+func (c *Client) ExportFlameGraph(ctx context.Context, w io.Writer, format string, traceID types.TraceID) error {
+	// format为trace.FormatFolded(默认)或trace.FormatJSON; 折叠栈格式以微秒为单位,
+	// 可直接交给flamegraph.pl或在speedscope中打开。
+	//
+	// 示例:
+	//   f, _ := os.Create("trace.folded")
+	//   defer f.Close()
+	//   client.ExportFlameGraph(ctx, f, trace.FormatFolded, traceID)
+	//   // flamegraph.pl trace.folded > trace.svg
+	return c.sys.ExportFlameGraph(ctx, w, format, traceID)
+}
+
+// CompareTraceWindows 比较两个时间窗口内的追踪
+func (c *Client) CompareTraceWindows(ctx context.Context, before, after trace.Window) (*trace.WindowComparison, error) {
+	// 给出耗时分位数(p50/p90/p99/max)的变化、新出现和已消失的瓶颈、各模式类型每个追踪的出现频率变化,
+	// 以及纠缠度和相干性的趋势, 适合在调整配置或适应策略前后对比。
+	//
+	// 示例:
+	//   changedAt := time.Now().Add(-time.Hour)
+	//   diff, err := client.CompareTraceWindows(ctx,
+	//       trace.Window{From: changedAt.Add(-time.Hour), To: changedAt},
+	//       trace.Window{From: changedAt, To: time.Now()})
+	//   for _, l := range diff.Latency {
+	//       fmt.Printf("%s: %s -> %s (%+.1f%%)\n", l.Percentile, l.Before, l.After, l.Change*100)
+	//   }
+	//   fmt.Println("new bottlenecks:", diff.NewBottlenecks)
+	return c.sys.CompareTraceWindows(ctx, before, after)
+}
+
+// EnergyFlow 查询时间窗口内的能量流向
+func (c *Client) EnergyFlow(q flow.SankeyQuery) (flow.SankeyReport, error) {
+	// 将能量转移聚合为桑基图数据: 源为五行元素和统一场, 汇为按类型聚合的模式和模型,
+	// 连线权重为窗口内的转移量合计。转移记录的保留时长由MonitorConfig.EnergyFlow配置。
+	//
+	// 示例:
+	//   report, err := client.EnergyFlow(flow.SankeyQuery{From: time.Now().Add(-time.Hour)})
+	//   for _, l := range report.Links {
+	//       fmt.Printf("%s -> %s %.3f\n", l.Source, l.Target, l.Value)
+	//   }
+	return c.sys.EnergyFlow(q)
+}
+
+// ExportEnergyFlow 以JSON导出能量流向
+func (c *Client) ExportEnergyFlow(w io.Writer, q flow.SankeyQuery) error {
+	// 输出的nodes和links可直接用于常见的桑基图组件。
+	//
+	// 示例:
+	//   f, _ := os.Create("energy_flow.json")
+	//   defer f.Close()
+	//   client.ExportEnergyFlow(f, flow.SankeyQuery{From: time.Now().Add(-24 * time.Hour)})
+	return c.sys.ExportEnergyFlow(w, q)
+}
+
+// DriftHistory 获取检测到的分布漂移
+func (c *Client) DriftHistory(since time.Time) ([]drift.Drift, error) {
+	// 在MonitorConfig.Drift中启用后, 系统按采样间隔观测活跃模式中各类型的占比、演化匹配的环境因素
+	// 和学习模型的准确率, 以Page-Hinkley或ADWIN检测均值变化并发出 monitor.drift_detected 事件。
+	// 配置Recalibrate时漂移触发共振匹配阈值的重新校准, 配置Retrain时重新训练学习模型。
+	//
+	// 示例:
+	//   drifts, err := client.DriftHistory(time.Now().Add(-time.Hour))
+	//   for _, d := range drifts {
+	//       fmt.Printf("%s: %.3f -> %.3f\n", d.Stream, d.Before, d.After)
+	//   }
+	return c.sys.DriftHistory(since)
+}
+
+// ScheduledJobs 获取定时任务的计划和执行统计
+func (c *Client) ScheduledJobs() []scheduler.JobStats {
+	// 子系统的周期任务(缓存软上限检查、漂移检测采样等)和通过ScheduleJob注册的自定义任务统一由
+	// 公共管理器的调度器触发: 同一任务上次执行未结束时跳过本次触发并计入Skipped, 失败、超时或崩溃
+	// 发出 scheduler.job_failed 事件。CommonConfig.Scheduler.Jobs可按任务名覆盖计划、随机延迟和超时, 或停用任务。
+	//
+	// 示例:
+	//   for _, job := range client.ScheduledJobs() {
+	//       fmt.Printf("%s %s next=%s runs=%d failures=%d\n", job.Name, job.Spec, job.Next, job.Runs, job.Failures)
+	//   }
+	return c.sys.ScheduledJobs()
+}
+
+// ScheduleJob 注册自定义定时任务
+func (c *Client) ScheduleJob(job scheduler.Job) error {
+	// 计划为五段cron表达式(分 时 日 月 周)或@every、@hourly、@daily等描述符。
+	//
+	// 示例:
+	//   err := client.ScheduleJob(scheduler.Job{
+	//       Name:   "app.nightly_report",
+	//       Spec:   "30 2 * * *",
+	//       Jitter: 5 * time.Minute,
+	//       Run:    func(ctx context.Context) error { return writeReport(ctx, client) },
+	//   })
+	return c.sys.ScheduleJob(job)
+}
+
+// AttachObserver 附加只读观察者
+func (c *Client) AttachObserver(opts observer.Options) (*observer.Observer, error) {
+	// 观察者可选择指标流、系统状态快照和活跃模式快照三种视图, 适用于仪表盘和分析旁路。
+	// 视图由系统按Config.Observers.Interval发布并缓存, 观察者读取时不持有系统的锁, 也无法修改系统状态;
+	// 快照请求按RequestsPerSecond限流, 指标样本来不及接收时丢弃并计入Dropped。
+	//
+	// 示例:
+	//   obs, err := client.AttachObserver(observer.Options{
+	//       Name:  "analytics",
+	//       Views: []string{types.ObserverViewMetrics, types.ObserverViewPatterns},
+	//   })
+	//   defer obs.Close()
+	//   for sample := range obs.Metrics() {
+	//       fmt.Printf("%s health=%.2f\n", sample.Timestamp, sample.Values["health"])
+	//   }
+	return c.sys.AttachObserver(opts)
+}
+
+// TriggerJob 立即执行定时任务
+func (c *Client) TriggerJob(name string) error {
+	// 不影响计划的下次触发; 任务正在执行时返回错误。
+	//
+	// 示例:
+	//   err := client.TriggerJob(system.JobMemoryLimits)
+	return c.sys.TriggerJob(name)
+}
+
+// MemoryReport 获取缓存的近似内存占用
+func (c *Client) MemoryReport() system.MemoryReport {
+	// 汇总追踪分析结果、活跃模式、检测历史、学习经验和知识单元的条目数与近似字节数。
+	// 在Config.MemoryLimits中按缓存名称配置软上限后系统周期检查: Evict为true时按超出比例驱逐较早的条目
+	// 并发出 system.memory_evicted 事件, 否则发出 system.memory_limit_exceeded 警告事件。
+	//
+	// 示例:
+	//   for _, c := range client.MemoryReport().Caches {
+	//       fmt.Printf("%s: %d条, 约%dKB\n", c.Cache, c.Entries, c.Bytes/1024)
+	//   }
+	return c.sys.MemoryReport()
+}
+
+// RunDiagnostics 执行系统自检
+func (c *Client) RunDiagnostics(ctx context.Context) system.DiagnosticsReport {
+	// 自检项: 各子系统运行状态和健康度、量子态合理性、统一场属性写读往返和状态读取、
+	// 事件总线投递、合成统一场上的模式检测试运行。系统未运行时依赖运行的检查记为skipped。
+	// 失败的检查以 system.diagnostic_failed 事件发出; 设置Config.DiagnosticsOnStart可在启动后自动自检。
+	//
+	// 示例:
+	//   report := client.RunDiagnostics(ctx)
+	//   for _, check := range report.Checks {
+	//       if check.Result == system.DiagnosticFailed {
+	//           fmt.Printf("%s 失败: %s\n", check.Name, check.Error)
+	//       }
+	//   }
+	return c.sys.RunDiagnostics(ctx)
+}
+
+// WatchdogStatus 获取子系统看门狗的监视状态
+func (c *Client) WatchdogStatus() []watchdog.Status {
+	// 启用ControlConfig.Watchdog后, 子系统未运行或健康度低于MinHealth的检查连续达到FailureThreshold次时
+	// 自动调用RestoreSubsystem, 按指数退避重试; 重试MaxRetries次仍未恢复时子系统进入降级,
+	// 系统自动切换到degraded运行模式, 直到子系统重新恢复正常。
+	//
+	// 示例:
+	//   for _, st := range client.WatchdogStatus() {
+	//       fmt.Printf("%s: %s, 重试%d次\n", st.Name, st.State, st.Retries)
+	//   }
+	return c.sys.WatchdogStatus()
+}
+
+// RegisterHealthContributor 注册健康度贡献者
+func (c *Client) RegisterHealthContributor(name string, contributor types.HealthContributor) error {
+	// 贡献者以加权分量报告健康信号, 分量的加权平均与各子系统健康度同权计入系统健康度。
+	// 演化子系统已按学习成功率、模型准确率及其趋势和知识增长贡献健康度分量。
+	//
+	// 示例:
+	//   client.RegisterHealthContributor("queue", queueHealth) // queueHealth实现HealthComponents()
+	return c.sys.RegisterHealthContributor(name, contributor)
+}
+
+// UnregisterHealthContributor 移除健康度贡献者
+func (c *Client) UnregisterHealthContributor(name string) {
+	c.sys.UnregisterHealthContributor(name)
+}
+
+// HealthComponents 获取各贡献者的健康度分量
+func (c *Client) HealthComponents() map[string][]types.HealthComponent {
+	return c.sys.HealthComponents()
+}
+
+// RuleGenerationStats 获取学习周期生成规则的统计
+func (c *Client) RuleGenerationStats() (adaptation.RuleGenerationStats, bool) {
+	// 学习周期从经验中生成的规则与已有规则的条件和动作相同时被丢弃;
+	// AdaptationConfig.RuleGeneration限制每周期注册的规则数和同一目标的生成间隔。
+	//
+	// 示例:
+	//   if stats, ok := client.RuleGenerationStats(); ok {
+	//       fmt.Printf("生成%d条, 丢弃%d条(重复%d)\n", stats.Generated, stats.Suppressed(), stats.Duplicates)
+	//   }
+	return c.sys.RuleGenerationStats()
+}
+
+// AuditRecords 查询策略参数和规则变更的审计记录
+func (c *Client) AuditRecords(q audit.Query) []audit.Record {
+	// 每次参数更新、规则注册/更新/优化都会记录变更前后的值和决策来源:
+	// 来源(外部调用、学习、强化学习、优化器)、触发的学习经验、各模型版本和目标达成度。
+	// 配置EvoConfig.Audit.Path后记录同时以每行一条JSON追加写入文件。
+	//
+	// 示例:
+	//   records := client.AuditRecords(audit.Query{Since: time.Now().Add(-time.Hour), Target: "strategy_1"})
+	//   for _, r := range records {
+	//       fmt.Printf("%s %s 来源:%s 经验:%v\n", r.Timestamp.Format(time.RFC3339), r.Kind, r.Lineage.Source, r.Lineage.Experiences)
+	//   }
+	return c.sys.AuditRecords(q)
+}
+
+// ExportAudit 以JSON数组导出审计记录
+func (c *Client) ExportAudit(w io.Writer, q audit.Query) error {
+	return c.sys.ExportAudit(w, q)
+}
+
+// RegisterEnvironmentProvider 注册外部环境来源
+func (c *Client) RegisterEnvironmentProvider(provider pattern.EnvironmentProvider, opts pattern.ProviderOptions) error {
+	// 来源的因素以"来源名.因素名"并入演化匹配的环境上下文, 参与上下文影响度和环境相似度计算。
+	// 读取失败时沿用旧值, 权重在MaxAge内线性衰减, 过期后移除。
+	//
+	// 示例:
+	//   client.RegisterEnvironmentProvider(pattern.EnvironmentProviderFunc{
+	//       ProviderName: "load",
+	//       Read: func(ctx context.Context) (map[string]float64, error) {
+	//           return map[string]float64{"cpu": cpuUsage()}, nil
+	//       },
+	//   }, pattern.ProviderOptions{Weight: 0.3, Interval: 10 * time.Second, MaxAge: time.Minute})
+	return c.sys.RegisterEnvironmentProvider(provider, opts)
+}
+
+// UnregisterEnvironmentProvider 移除外部环境来源
+func (c *Client) UnregisterEnvironmentProvider(name string) {
+	c.sys.UnregisterEnvironmentProvider(name)
+}
+
+// EnvironmentProviders 获取外部环境来源的状态
+func (c *Client) EnvironmentProviders() []pattern.ProviderStatus {
+	return c.sys.EnvironmentProviders()
+}
+
+// PatternFamilies 获取已识别模式的族统计
+func (c *Client) PatternFamilies() []pattern.FamilyStats {
+	// 启用RecognitionConfig.Families后, 同类型的近似重复模式按签名特征向量在线聚类为模式族,
+	// 族统计包括族中心、成员、内聚度以及成员的平均置信度和稳定性。
+	// 已识别模式的Family字段为其所属族ID, 可据此按族而非单个模式进行适应。
+	//
+	// 示例:
+	//   for _, f := range client.PatternFamilies() {
+	//       fmt.Printf("%s %s 成员%d 内聚度%.2f\n", f.ID, f.Type, f.Size, f.Cohesion)
+	//   }
+	return c.sys.PatternFamilies()
+}
+
+// SetSimilarityMetric 设置模式识别比较签名时维度的相似度度量
+func (c *Client) SetSimilarityMetric(dimension string, metric pattern.SimilarityMetric) error {
+	// dimension为pattern.FactorComponent、FactorStructure、FactorDynamics或FactorContext。
+	// 内置度量有HeuristicMetric(默认)、CosineMetric、EuclideanMetric和MahalanobisMetric,
+	// 也可通过RecognitionConfig.Similarity按维度配置; 实现pattern.LearningMetric的度量会收到识别到的签名。
+	// metric为nil时恢复为默认度量。
+	//
+	// 示例:
+	//   client.SetSimilarityMetric(pattern.FactorDynamics, pattern.NewMahalanobisMetric(500))
+	return c.sys.SetSimilarityMetric(dimension, metric)
+}
+
+// ExplainPatternSimilarity 分解两个已识别模式的签名相似度
+func (c *Client) ExplainPatternSimilarity(id1, id2 string) (pattern.SimilarityExplanation, error) {
+	// 返回组件、结构、动态和上下文各因子的相似度与贡献, 以及最相似和最不相似的组件对,
+	// 用于说明两个模式为何被或未被识别为同一模式或相互关联。相似度以第一个模式的组件为基准。
+	// 不属于已识别模式的签名可直接使用pattern.ExplainSimilarity。
+	//
+	// 示例:
+	//   exp, err := client.ExplainPatternSimilarity("pat_a", "pat_b")
+	//   for _, f := range exp.Factors {
+	//       fmt.Printf("%s %.2f (损失 %.2f)\n", f.Factor, f.Similarity, f.Loss)
+	//   }
+	return c.sys.ExplainPatternSimilarity(id1, id2)
+}
+
+// AddConstraint 添加适应动作约束
+func (c *Client) AddConstraint(con constraint.Constraint) error {
+	// 策略执行的每个动作在执行前按约束检查参数: clip模式裁剪到允许范围, reject模式拒绝整个动作。
+	// 规则注册、更新和优化时同样检查规则动作参数。违反记录为constraint_violation策略事件。
+	//
+	// 示例:
+	//   // 禁止把系统能量推高到0.9以上
+	//   client.AddConstraint(constraint.Constraint{Name: "energy_cap", Kind: constraint.KindBound, Parameter: "energy", Min: 0, Max: 0.9})
+	//   // 学习率每小时调整不超过10%, 超出时拒绝
+	//   client.AddConstraint(constraint.Constraint{Name: "lr_rate", Kind: constraint.KindRate, Target: "learning",
+	//       Parameter: "learning_rate", MaxChange: 0.1, Window: time.Hour, Mode: constraint.ModeReject})
+	return c.sys.AddConstraint(con)
+}
+
+// RemoveConstraint 移除适应动作约束
+func (c *Client) RemoveConstraint(name string) {
+	c.sys.RemoveConstraint(name)
+}
+
+// Constraints 获取适应动作约束
+func (c *Client) Constraints() []constraint.Constraint {
+	return c.sys.Constraints()
+}
+
+// ConstraintViolations 获取最近的约束违反记录
+func (c *Client) ConstraintViolations() []constraint.Violation {
+	return c.sys.ConstraintViolations()
+}
+
+// CausalGraph 获取模式与适应动作间的有向影响图
+func (c *Client) CausalGraph(refresh bool) *causal.Graph {
+	// 基于格兰杰检验判断一个节点(模式或适应策略动作)的历史是否显著改善对另一模式的预测，
+	// 显著的影响构成有向边，置信度为1-p值。Roots返回不受其他节点影响的上游原因。
+	// refresh为false时返回周期发现的最近结果，需在演化配置中启用Causal。
+	//
+	// 示例:
+	//   graph := client.CausalGraph(true)
+	//   for _, root := range graph.Roots() {
+	//       fmt.Printf("上游原因: %s 影响力 %.2f\n", root, graph.Influence(root))
+	//   }
+	return c.sys.CausalGraph(refresh)
+}
+
+// CouplingGraph 获取统一场的耦合网络
+func (c *Client) CouplingGraph() (field.CouplingGraph, error) {
+	// 返回场组件(scalar/vector/metric/quantum)、各耦合的方向和强度以及配置的强度阈值。
+	// 耦合强度越过阈值时发布field.coupling_threshold事件, 阈值由MetaConfig.CouplingThresholds配置。
+	//
+	// 示例:
+	//   id, _ := client.AddCoupling(field.ComponentScalar, field.ComponentQuantum, 0.4, true)
+	//   client.SetCouplingStrength(id, 0.8) // 越过默认阈值0.5
+	//   graph, _ := client.CouplingGraph()
+	//   for _, e := range graph.Couplings {
+	//       fmt.Printf("%s %.2f %s\n", e.ID, e.Strength, e.Type)
+	//   }
+	return c.sys.CouplingGraph()
+}
+
+// AddCoupling 在统一场的两个组件间添加耦合, 返回耦合ID
+func (c *Client) AddCoupling(source, target string, strength float64, bidirectional bool) (string, error) {
+	return c.sys.AddCoupling(source, target, strength, bidirectional)
+}
+
+// RemoveCoupling 移除统一场的耦合
+func (c *Client) RemoveCoupling(id string) error {
+	return c.sys.RemoveCoupling(id)
+}
+
+// SetCouplingStrength 调整统一场的耦合强度
+func (c *Client) SetCouplingStrength(id string, strength float64) error {
+	return c.sys.SetCouplingStrength(id, strength)
+}
+
+// PerturbField 立即在统一场的区域内注入能量扰动, duration后撤销
+func (c *Client) PerturbField(region field.Region, deltaEnergy float64, duration time.Duration) (field.Perturbation, error) {
+	// 能量平均分配到区域内与中心欧氏距离不超过半径的格点, 叠加在观测能量分布上, 模式检测随之感知;
+	// 撤销时只移除扰动自身的能量, 不影响期间的观测。duration为0时持续到CancelPerturbation。
+	// 扰动的计划、施加、撤销和取消分别发布field.perturbation_*事件, 负载为field.Perturbation;
+	// 施加的扰动同时作为异常关联的候选原因, 可在异常的ProbableCauses中看到。
+	//
+	// 示例:
+	//   p, _ := client.PerturbField(field.Region{Center: core.Point{X: 4, Y: 4}, Radius: 2}, 5.0, 30*time.Second)
+	//   fmt.Printf("%s %s\n", p.ID, p.State)
+	return c.sys.PerturbField(region, deltaEnergy, duration)
+}
+
+// SchedulePerturbation 计划在at时刻对统一场施加能量或相位扰动
+func (c *Client) SchedulePerturbation(at time.Time, spec field.PerturbationSpec) (field.Perturbation, error) {
+	// at不晚于当前时间时立即施加; 之后的扰动随场演化周期开始和撤销。PhaseShift按区域的X、Y坐标
+	// 旋转量子场分量的相位, Label作为异常关联的类别, 便于区分不同实验。
+	//
+	// 示例:
+	//   // 一分钟后在原点附近施加0.5弧度的相位偏移, 持续10秒
+	//   p, err := client.SchedulePerturbation(time.Now().Add(time.Minute), field.PerturbationSpec{
+	//       Region:     field.Region{Radius: 1},
+	//       PhaseShift: 0.5,
+	//       Duration:   10 * time.Second,
+	//       Label:      "phase-probe",
+	//   })
+	return c.sys.SchedulePerturbation(at, spec)
+}
+
+// CancelPerturbation 取消统一场的扰动, 已施加的扰动立即撤销
+func (c *Client) CancelPerturbation(id string) error {
+	return c.sys.CancelPerturbation(id)
+}
+
+// Perturbations 获取统一场的扰动记录, 按计划开始时间排序
+func (c *Client) Perturbations() ([]field.Perturbation, error) {
+	return c.sys.Perturbations()
+}
+
+// Fields 列出多场联邦的成员场
+func (c *Client) Fields() []meta.FieldInfo {
+	return c.sys.Fields()
+}
+
+// LinkFields 定义两个命名空间场域之间的耦合
+func (c *Client) LinkFields(cfg types.FieldLinkConfig) (string, error) {
+	// 每个命名空间拥有独立的统一场和模式检测器, 场参数可在NamespaceConfig.Field中单独配置。
+	// 场间耦合按MetaConfig.Federation.Interval周期同步: 目标场的属性(默认energy)向源场
+	// 靠拢差值的Strength比例, 双向耦合时两个场相互靠拢。移除命名空间时相关耦合一并移除。
+	//
+	// 示例:
+	//   client.GetSystem().CreateNamespace(&types.NamespaceConfig{Name: "grid"})
+	//   client.GetSystem().CreateNamespace(&types.NamespaceConfig{Name: "market"})
+	//   id, err := client.LinkFields(types.FieldLinkConfig{
+	//       Source: "grid", Target: "market", Property: "phase", Strength: 0.2, Bidirectional: true,
+	//   })
+	return c.sys.LinkFields(cfg)
+}
+
+// UnlinkFields 移除场间耦合
+func (c *Client) UnlinkFields(id string) error {
+	return c.sys.UnlinkFields(id)
+}
+
+// FieldLinks 获取场间耦合状态
+func (c *Client) FieldLinks() []meta.FieldLinkStatus {
+	return c.sys.FieldLinks()
+}
+
 // ModelAPI实现
 // TransformModel 执行模型转换操作
 func (c *Client) TransformModel(ctx context.Context, pattern model.TransformPattern) error {
@@ -608,6 +1364,12 @@ func (c *Client) Subscribe(eventType types.EventType, handler types.EventHandler
 	//     - types.EventModelChange: 模型变更事件
 	//   - handler: 事件处理器,需实现types.EventHandler接口
 	//
+	// 投递语义(只对携带幂等键的事件生效):
+	//   - types.DeliveryAtLeastOnce: 默认，每次发布都投递，包括去重窗口内的重复发布(event.Duplicate为true)
+	//   - types.DeliveryExactlyOnce: 窗口内同一幂等键最多成功处理一次，处理返回错误时生产方重试可再次投递；
+	//     去重状态只保存在内存中，超出窗口或重启后不保证
+	//   处理器实现types.DeliveryModeHandler或经types.WithDeliveryMode包装以声明投递语义
+	//
 	// 返回值:
 	//   - error: 订阅失败时返回错误
 	//
@@ -617,6 +1379,10 @@ func (c *Client) Subscribe(eventType types.EventType, handler types.EventHandler
 	//       return nil
 	//   })
 	//   err := client.Subscribe(types.EventStateChange, handler)
+	//
+	//   // 计费等不可重复的处理按恰好一次投递
+	//   billing := types.WithDeliveryMode(billingHandler, types.DeliveryExactlyOnce)
+	//   err = client.Subscribe(types.EventFlowComplete, billing)
 	return c.sys.Subscribe(eventType, handler)
 }
 
@@ -923,11 +1689,14 @@ func (c *Client) HandleEvent(event types.SystemEvent) error {
 	//     - Data: 事件数据(可选)
 	//     - Source: 事件源(可选)
 	//     - Timestamp: 时间戳(可选，默认为当前时间)
+	//     - IdempotencyKey: 幂等键(可选)，重试时保持不变；去重窗口内的重复发布标记为Duplicate，
+	//       不计入事件历史和追踪，只按处理器的投递语义投递
 	//
 	// 返回值:
 	//   - error: 事件提交失败时返回错误
-	//     - types.ErrState: 系统未运行
+	//     - types.ErrState: 系统未运行或已开始关闭前排空
 	//     - types.ErrQueue: 事件队列已满
+	//     - types.ErrValidation: 事件类型已注册负载结构而Data不符
 	//
 	// 示例:
 	//   // 创建并处理系统状态变更事件
@@ -945,9 +1714,54 @@ func (c *Client) HandleEvent(event types.SystemEvent) error {
 	//   if err := client.HandleEvent(event); err != nil {
 	//       log.Printf("事件处理失败: %v", err)
 	//   }
+	if c.sys.Draining() {
+		return types.NewSystemError(types.ErrState, "system draining", nil)
+	}
 	return c.sys.HandleEvent(event)
 }
 
+// EventDedupStats 获取事件幂等去重统计
+func (c *Client) EventDedupStats() types.DedupStats {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	// 返回值:
+	//   - types.DedupStats: 去重窗口、窗口内的幂等键数、重复发布次数、
+	//     对恰好一次处理器跳过的投递次数和因键数上限淘汰的键数
+	//
+	// 示例:
+	//   stats := client.EventDedupStats()
+	//   fmt.Printf("窗口 %v 内重复发布 %d 次, 跳过投递 %d 次\n", stats.Window, stats.Duplicates, stats.Skipped)
+	return c.sys.EventDedupStats()
+}
+
+// EventSchemas 获取事件负载结构注册表
+func (c *Client) EventSchemas() *types.EventSchemaRegistry {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	// 已注册负载结构的事件类型在发布时校验Data, 订阅方可用types.Decode取得类型化负载。
+	// 使用方可为自定义事件注册负载结构, 同一事件类型可注册多个版本。
+	//
+	// 返回值:
+	//   - *types.EventSchemaRegistry: 事件负载结构注册表
+	//
+	// 示例:
+	//   // 为自定义事件注册负载结构
+	//   schemas := client.EventSchemas()
+	//   err := types.RegisterEventPayload[OrderPlaced](schemas, "shop.order_placed", 1, "placed order")
+	//
+	//   // 在处理器中解码负载
+	//   state, err := types.Decode[types.ThrottleState](event)
+	//   if err == nil {
+	//       fmt.Printf("%s 节流: %v\n", state.Subsystem, state.Throttled)
+	//   }
+	//
+	//   // 列出已注册的结构
+	//   for _, schema := range schemas.Schemas() {
+	//       fmt.Printf("%s v%d: %s\n", schema.Type, schema.Version, schema.PayloadName)
+	//   }
+	return c.sys.EventSchemas()
+}
+
 // Optimize 执行系统优化
 func (c *Client) Optimize(params types.OptimizationParams) error {
 	c.mu.Lock()
@@ -0,0 +1,318 @@
+// api/rbac.go
+
+package api
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Role 访问角色, 高级角色包含低级角色的全部权限
+type Role string
+
+const (
+	RoleViewer   Role = "viewer"   // 只读查询
+	RoleOperator Role = "operator" // 运行控制: 转换、能量调整、模式切换、调制覆盖
+	RoleAdmin    Role = "admin"    // 启停系统和审计查询
+)
+
+// rank 角色等级, 未知角色为0
+func (r Role) rank() int {
+	switch r {
+	case RoleViewer:
+		return 1
+	case RoleOperator:
+		return 2
+	case RoleAdmin:
+		return 3
+	default:
+		return 0
+	}
+}
+
+// Valid 是否为已知角色
+func (r Role) Valid() bool {
+	return r.rank() > 0
+}
+
+// Allows 该角色是否满足required的权限要求
+func (r Role) Allows(required Role) bool {
+	return r.Valid() && r.rank() >= required.rank()
+}
+
+// 认证方式
+const (
+	AuthAPIKey = "api_key"
+	AuthJWT    = "jwt"
+)
+
+// Principal 已认证的调用方
+type Principal struct {
+	Subject string `json:"subject"` // 调用方标识
+	Role    Role   `json:"role"`
+	Auth    string `json:"auth"` // 认证方式
+}
+
+// 认证请求头
+const (
+	APIKeyHeader        = "X-API-Key"
+	AuthorizationHeader = "Authorization"
+)
+
+// Authenticator 请求认证
+// 请求未携带该方式的凭据时返回(nil, nil), 以便依次尝试其他方式; 凭据无效时返回错误
+type Authenticator interface {
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// errUnauthenticated 认证失败
+func errUnauthenticated(reason string) error {
+	return types.NewSystemError(types.ErrSecurity, "authentication failed", nil).
+		WithContext("reason", reason)
+}
+
+// APIKeyConfig API密钥配置
+type APIKeyConfig struct {
+	Key     string `json:"key"`
+	Subject string `json:"subject"`
+	Role    Role   `json:"role"`
+}
+
+// APIKeyAuthenticator 以APIKeyHeader请求头认证
+// 只保存密钥的SHA-256摘要, 查找不依赖密钥内容的逐字节比较
+type APIKeyAuthenticator struct {
+	mu   sync.RWMutex
+	keys map[[sha256.Size]byte]Principal
+}
+
+// NewAPIKeyAuthenticator 创建API密钥认证
+func NewAPIKeyAuthenticator(keys ...APIKeyConfig) (*APIKeyAuthenticator, error) {
+	a := &APIKeyAuthenticator{keys: make(map[[sha256.Size]byte]Principal)}
+	for _, k := range keys {
+		if err := a.Add(k); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+// Add 添加密钥
+func (a *APIKeyAuthenticator) Add(k APIKeyConfig) error {
+	if k.Key == "" {
+		return types.NewSystemError(types.ErrInvalid, "empty api key", nil).
+			WithContext("subject", k.Subject)
+	}
+	if !k.Role.Valid() {
+		return types.NewSystemError(types.ErrInvalid, "unknown role", nil).
+			WithContext("subject", k.Subject).
+			WithContext("role", k.Role)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.keys[sha256.Sum256([]byte(k.Key))] = Principal{Subject: k.Subject, Role: k.Role, Auth: AuthAPIKey}
+	return nil
+}
+
+// Revoke 吊销密钥
+func (a *APIKeyAuthenticator) Revoke(key string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	delete(a.keys, sha256.Sum256([]byte(key)))
+}
+
+// Authenticate 认证请求
+func (a *APIKeyAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	key := r.Header.Get(APIKeyHeader)
+	if key == "" {
+		return nil, nil
+	}
+
+	a.mu.RLock()
+	p, ok := a.keys[sha256.Sum256([]byte(key))]
+	a.mu.RUnlock()
+	if !ok {
+		return nil, errUnauthenticated("unknown api key")
+	}
+	return &p, nil
+}
+
+// JWTConfig JWT认证配置, 只接受HS256签名
+type JWTConfig struct {
+	Secret   string        `json:"secret"`   // HMAC密钥
+	Issuer   string        `json:"issuer"`   // 非空时要求iss一致
+	Audience string        `json:"audience"` // 非空时要求aud一致
+	Leeway   time.Duration `json:"leeway"`   // exp/nbf允许的时钟偏差
+}
+
+// Claims JWT声明
+type Claims struct {
+	Subject   string `json:"sub"`
+	Role      Role   `json:"role"`
+	Issuer    string `json:"iss,omitempty"`
+	Audience  string `json:"aud,omitempty"`
+	ExpiresAt int64  `json:"exp,omitempty"` // Unix秒
+	NotBefore int64  `json:"nbf,omitempty"`
+	IssuedAt  int64  `json:"iat,omitempty"`
+}
+
+// JWTAuthenticator 以"Authorization: Bearer <token>"认证
+type JWTAuthenticator struct {
+	config  JWTConfig
+	nowFunc func() time.Time
+}
+
+// NewJWTAuthenticator 创建JWT认证
+func NewJWTAuthenticator(cfg JWTConfig) (*JWTAuthenticator, error) {
+	if cfg.Secret == "" {
+		return nil, types.NewSystemError(types.ErrInvalid, "empty jwt secret", nil)
+	}
+	return &JWTAuthenticator{
+		config:  cfg,
+		nowFunc: time.Now,
+	}, nil
+}
+
+// jwtHeader HS256令牌头
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// SignJWT 以HS256签发令牌, 供运维工具和测试使用
+func SignJWT(secret string, claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signing := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signing + "." + jwtSignature(secret, signing), nil
+}
+
+// jwtSignature HS256签名
+func jwtSignature(secret, signing string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(signing))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// Authenticate 认证请求
+func (a *JWTAuthenticator) Authenticate(r *http.Request) (*Principal, error) {
+	auth := r.Header.Get(AuthorizationHeader)
+	if !strings.HasPrefix(auth, "Bearer ") {
+		return nil, nil
+	}
+	claims, err := a.Verify(strings.TrimSpace(strings.TrimPrefix(auth, "Bearer ")))
+	if err != nil {
+		return nil, err
+	}
+	return &Principal{Subject: claims.Subject, Role: claims.Role, Auth: AuthJWT}, nil
+}
+
+// Verify 校验令牌签名、算法、有效期、签发方、受众和角色
+func (a *JWTAuthenticator) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errUnauthenticated("malformed token")
+	}
+
+	header, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return nil, errUnauthenticated("malformed token header")
+	}
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil || h.Alg != "HS256" {
+		return nil, errUnauthenticated("unsupported token algorithm")
+	}
+
+	expected := jwtSignature(a.config.Secret, parts[0]+"."+parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return nil, errUnauthenticated("invalid token signature")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, errUnauthenticated("malformed token payload")
+	}
+	var claims Claims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return nil, errUnauthenticated("malformed token claims")
+	}
+
+	now := a.nowFunc()
+	if claims.ExpiresAt != 0 && now.After(time.Unix(claims.ExpiresAt, 0).Add(a.config.Leeway)) {
+		return nil, errUnauthenticated("token expired")
+	}
+	if claims.NotBefore != 0 && now.Add(a.config.Leeway).Before(time.Unix(claims.NotBefore, 0)) {
+		return nil, errUnauthenticated("token not yet valid")
+	}
+	if a.config.Issuer != "" && claims.Issuer != a.config.Issuer {
+		return nil, errUnauthenticated("unexpected token issuer")
+	}
+	if a.config.Audience != "" && claims.Audience != a.config.Audience {
+		return nil, errUnauthenticated("unexpected token audience")
+	}
+	if claims.Subject == "" || !claims.Role.Valid() {
+		return nil, errUnauthenticated("token missing subject or role")
+	}
+	return &claims, nil
+}
+
+// Authenticators 依次尝试多种认证方式, 首个识别出凭据的方式决定结果
+type Authenticators []Authenticator
+
+// Authenticate 认证请求, 均未携带凭据时返回(nil, nil)
+func (as Authenticators) Authenticate(r *http.Request) (*Principal, error) {
+	for _, a := range as {
+		p, err := a.Authenticate(r)
+		if err != nil || p != nil {
+			return p, err
+		}
+	}
+	return nil, nil
+}
+
+// Policy 各端点要求的最低角色, 键为"METHOD /path"
+type Policy map[string]Role
+
+// defaultPolicy 控制端点的默认权限
+var defaultPolicy = Policy{
+	"GET /api/v1/status":      RoleViewer,
+	"GET /api/v1/mode":        RoleViewer,
+	"PUT /api/v1/mode":        RoleOperator,
+	"POST /api/v1/energy":     RoleOperator,
+	"GET /api/v1/energy/flow": RoleViewer,
+	"POST /api/v1/transform":  RoleOperator,
+	"PUT /api/v1/modulation":  RoleOperator,
+	"POST /api/v1/start":      RoleAdmin,
+	"POST /api/v1/stop":       RoleAdmin,
+	"GET /api/v1/audit":       RoleAdmin,
+}
+
+// DefaultPolicy 默认端点权限的副本
+func DefaultPolicy() Policy {
+	p := make(Policy, len(defaultPolicy))
+	for k, v := range defaultPolicy {
+		p[k] = v
+	}
+	return p
+}
+
+// Required 端点要求的最低角色; 未登记的端点要求admin
+// HEAD请求由ServeMux路由到GET端点, 按GET端点的权限检查
+func (p Policy) Required(method, path string) Role {
+	if method == http.MethodHead {
+		method = http.MethodGet
+	}
+	if role, ok := p[method+" "+path]; ok {
+		return role
+	}
+	return RoleAdmin
+}
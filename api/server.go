@@ -0,0 +1,482 @@
+// api/server.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/evolution/audit"
+	"github.com/Corphon/daoflow/system/monitor/flow"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 请求体大小上限
+const maxRequestBody = 1 << 20
+
+// ServerConfig 远程控制接口配置
+type ServerConfig struct {
+	Addr    string         `json:"addr"`     // 监听地址
+	APIKeys []APIKeyConfig `json:"api_keys"` // API密钥
+	JWT     *JWTConfig     `json:"jwt"`      // JWT认证, 为nil时不启用
+	Policy  Policy         `json:"policy"`   // 覆盖默认的端点权限
+}
+
+// transformPatterns 转换模式名称
+var transformPatterns = map[string]model.TransformPattern{
+	"normal":  model.PatternNormal,
+	"forward": model.PatternForward,
+	"reverse": model.PatternReverse,
+	"balance": model.PatternBalance,
+	"mutate":  model.PatternMutate,
+}
+
+// ControlServer 远程控制接口
+// 每个请求先认证再按Policy授权, 要求viewer以上角色的调用和所有被拒绝的调用记入审计日志
+type ControlServer struct {
+	mu sync.RWMutex
+
+	client *Client
+	auth   Authenticator
+	policy Policy
+
+	// 运行状态
+	state struct {
+		running bool
+		addr    string
+	}
+
+	server *http.Server
+	wg     sync.WaitGroup
+}
+
+// NewControlServer 创建远程控制接口, 至少需要配置一种认证方式
+func NewControlServer(client *Client, cfg ServerConfig) (*ControlServer, error) {
+	if client == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil client", nil)
+	}
+
+	var auth Authenticators
+	if len(cfg.APIKeys) > 0 {
+		keys, err := NewAPIKeyAuthenticator(cfg.APIKeys...)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, keys)
+	}
+	if cfg.JWT != nil {
+		jwt, err := NewJWTAuthenticator(*cfg.JWT)
+		if err != nil {
+			return nil, err
+		}
+		auth = append(auth, jwt)
+	}
+	if len(auth) == 0 {
+		return nil, types.NewSystemError(types.ErrConfig, "control server requires api keys or jwt", nil)
+	}
+
+	policy := DefaultPolicy()
+	for endpoint, role := range cfg.Policy {
+		if !role.Valid() {
+			return nil, types.NewSystemError(types.ErrConfig, "unknown role in policy", nil).
+				WithContext("endpoint", endpoint).
+				WithContext("role", role)
+		}
+		policy[endpoint] = role
+	}
+
+	s := &ControlServer{
+		client: client,
+		auth:   auth,
+		policy: policy,
+	}
+	s.state.addr = cfg.Addr
+	return s, nil
+}
+
+// Handler 控制接口HTTP处理器, 可挂载到已有的路由
+func (s *ControlServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	s.route(mux, http.MethodGet, "/api/v1/status", s.handleStatus)
+	s.route(mux, http.MethodGet, "/api/v1/mode", s.handleGetMode)
+	s.route(mux, http.MethodPut, "/api/v1/mode", s.handleSetMode)
+	s.route(mux, http.MethodPost, "/api/v1/energy", s.handleAdjustEnergy)
+	s.route(mux, http.MethodGet, "/api/v1/energy/flow", s.handleEnergyFlow)
+	s.route(mux, http.MethodPost, "/api/v1/transform", s.handleTransform)
+	s.route(mux, http.MethodPut, "/api/v1/modulation", s.handleModulation)
+	s.route(mux, http.MethodPost, "/api/v1/start", s.handleStart)
+	s.route(mux, http.MethodPost, "/api/v1/stop", s.handleStop)
+	s.route(mux, http.MethodGet, "/api/v1/audit", s.handleAudit)
+	return mux
+}
+
+// Start 监听地址, addr为空时使用配置地址
+func (s *ControlServer) Start(addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state.running {
+		return types.ErrAlreadyRunning
+	}
+	if addr == "" {
+		addr = s.state.addr
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return types.NewSystemError(types.ErrNetwork, "failed to listen for control api", err).
+			WithContext("addr", addr)
+	}
+
+	s.server = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	s.state.running = true
+	s.state.addr = listener.Addr().String()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.server.Serve(listener)
+	}()
+	return nil
+}
+
+// Addr 实际监听地址
+func (s *ControlServer) Addr() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.addr
+}
+
+// Stop 停止服务
+func (s *ControlServer) Stop() error {
+	s.mu.Lock()
+	if !s.state.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.state.running = false
+	server := s.server
+	s.mu.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := server.Shutdown(ctx)
+
+	s.wg.Wait()
+	return err
+}
+
+// principalKey 请求上下文中的调用方
+type principalKey struct{}
+
+// PrincipalFromContext 获取请求的已认证调用方
+func PrincipalFromContext(ctx context.Context) (*Principal, bool) {
+	p, ok := ctx.Value(principalKey{}).(*Principal)
+	return p, ok
+}
+
+// statusRecorder 记录响应状态码
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader 记录状态码
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// route 注册端点, 依次进行认证、授权和审计; 方法不匹配时由路由返回405
+func (s *ControlServer) route(mux *http.ServeMux, method, path string, handler http.HandlerFunc) {
+	mux.HandleFunc(method+" "+path, func(w http.ResponseWriter, r *http.Request) {
+		s.serve(w, r, handler)
+	})
+}
+
+// serve 认证、授权并执行处理器, 特权调用和被拒绝的调用记入审计日志
+// 请求体只在通过授权后读取, 认证或授权未通过的调用只记录方法、路径、来源地址和状态码
+func (s *ControlServer) serve(w http.ResponseWriter, r *http.Request, handler http.HandlerFunc) {
+	required := s.policy.Required(r.Method, r.URL.Path)
+	rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+
+	principal, err := s.auth.Authenticate(r)
+	switch {
+	case err != nil:
+		writeError(rec, http.StatusUnauthorized, err)
+	case principal == nil:
+		writeError(rec, http.StatusUnauthorized, errors.New("missing credentials"))
+	case !principal.Role.Allows(required):
+		writeError(rec, http.StatusForbidden, types.NewSystemError(types.ErrPermission, "insufficient role", nil).
+			WithContext("required", required).
+			WithContext("role", principal.Role))
+	}
+	if rec.status == http.StatusUnauthorized || rec.status == http.StatusForbidden {
+		s.audit(r, nil, nil, rec.status)
+		return
+	}
+
+	var params map[string]interface{}
+	if r.Body != nil && r.Method != http.MethodGet {
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxRequestBody))
+		if err != nil {
+			writeError(rec, http.StatusBadRequest, err)
+			return
+		}
+		json.Unmarshal(body, &params)
+		r.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	handler(rec, r.WithContext(context.WithValue(r.Context(), principalKey{}, principal)))
+
+	if required != RoleViewer || rec.status == http.StatusUnauthorized || rec.status == http.StatusForbidden {
+		s.audit(r, principal, params, rec.status)
+	}
+}
+
+// audit 记录一次控制调用
+func (s *ControlServer) audit(r *http.Request, principal *Principal, params map[string]interface{}, status int) {
+	result := map[string]interface{}{
+		"status":  status,
+		"allowed": status != http.StatusUnauthorized && status != http.StatusForbidden,
+		"remote":  r.RemoteAddr,
+	}
+	if principal != nil {
+		result["subject"] = principal.Subject
+		result["role"] = string(principal.Role)
+		result["auth"] = principal.Auth
+	}
+
+	s.client.GetSystem().RecordAudit(audit.Record{
+		Kind:    audit.KindAPICall,
+		Target:  r.Method + " " + r.URL.Path,
+		Before:  params,
+		After:   result,
+		Lineage: audit.Lineage{Source: audit.SourceAPI},
+	})
+}
+
+// handleStatus 系统状态
+func (s *ControlServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	status := map[string]interface{}{
+		"status": s.client.GetSystemStatus(),
+		"mode":   s.client.Mode(),
+	}
+	// 核心引擎初始化前能量系统尚未创建
+	if s.client.GetSystem().GetEnergySystem() != nil {
+		status["energy"] = s.client.GetEnergy()
+	}
+	writeJSON(w, http.StatusOK, status)
+}
+
+// handleGetMode 当前运行模式
+func (s *ControlServer) handleGetMode(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, s.client.Mode())
+}
+
+// handleSetMode 切换运行模式
+func (s *ControlServer) handleSetMode(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Mode   types.OperatingMode `json:"mode"`
+		Reason string              `json:"reason"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if p, ok := PrincipalFromContext(r.Context()); ok && req.Reason == "" {
+		req.Reason = "set by " + p.Subject
+	}
+	if err := s.client.SetMode(req.Mode, req.Reason); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.client.Mode())
+}
+
+// handleAdjustEnergy 调整能量
+func (s *ControlServer) handleAdjustEnergy(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Delta float64 `json:"delta"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if err := s.client.AdjustEnergy(req.Delta); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]float64{"energy": s.client.GetEnergy()})
+}
+
+// handleEnergyFlow 查询能量流向的桑基图数据, 支持from、to(RFC3339)、window、namespace、min参数
+// 只给出window时查询截至当前的窗口
+func (s *ControlServer) handleEnergyFlow(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	q := flow.SankeyQuery{Namespace: values.Get("namespace")}
+	for key, t := range map[string]*time.Time{"from": &q.From, "to": &q.To} {
+		if v := values.Get(key); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				writeError(w, http.StatusBadRequest, err)
+				return
+			}
+			*t = parsed
+		}
+	}
+	if v := values.Get("window"); v != "" {
+		window, err := time.ParseDuration(v)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		if q.To.IsZero() {
+			q.To = time.Now()
+		}
+		q.From = q.To.Add(-window)
+	}
+	if v := values.Get("min"); v != "" {
+		min, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		q.MinValue = min
+	}
+
+	report, err := s.client.EnergyFlow(q)
+	if err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	writeJSON(w, http.StatusOK, report)
+}
+
+// handleTransform 执行模型转换
+func (s *ControlServer) handleTransform(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Pattern string `json:"pattern"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	pattern, ok := transformPatterns[req.Pattern]
+	if !ok {
+		writeError(w, http.StatusBadRequest, types.NewSystemError(types.ErrInvalid, "unknown transform pattern", nil).
+			WithContext("pattern", req.Pattern))
+		return
+	}
+	if err := s.client.TransformModel(r.Context(), pattern); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleModulation 覆盖调制曲线
+func (s *ControlServer) handleModulation(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Target string            `json:"target"`
+		Curve  types.CurveConfig `json:"curve"`
+	}
+	if !decodeRequest(w, r, &req) {
+		return
+	}
+	if err := s.client.SetModulationCurve(req.Target, req.Curve); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleStart 启动系统
+func (s *ControlServer) handleStart(w http.ResponseWriter, r *http.Request) {
+	if err := s.client.Start(); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleStop 停止系统
+func (s *ControlServer) handleStop(w http.ResponseWriter, r *http.Request) {
+	if err := s.client.Stop(); err != nil {
+		writeError(w, errorStatus(err), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// handleAudit 查询审计记录, 支持kind、target、source、limit参数
+func (s *ControlServer) handleAudit(w http.ResponseWriter, r *http.Request) {
+	values := r.URL.Query()
+	q := audit.Query{
+		Kind:   values.Get("kind"),
+		Target: values.Get("target"),
+		Source: values.Get("source"),
+	}
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, err)
+			return
+		}
+		q.Limit = n
+	}
+	writeJSON(w, http.StatusOK, s.client.AuditRecords(q))
+}
+
+// decodeRequest 解析JSON请求体, 失败时写出400
+func decodeRequest(w http.ResponseWriter, r *http.Request, v interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return false
+	}
+	return true
+}
+
+// errorStatus 错误对应的HTTP状态码
+func errorStatus(err error) int {
+	switch types.GetErrorCode(err) {
+	case types.ErrInvalid, types.ErrValidation:
+		return http.StatusBadRequest
+	case types.ErrSecurity:
+		return http.StatusUnauthorized
+	case types.ErrPermission:
+		return http.StatusForbidden
+	case types.ErrNotFound:
+		return http.StatusNotFound
+	case types.ErrState, types.ErrExists:
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON 写出JSON响应
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError 写出错误响应, 系统错误只返回错误码和消息, 不暴露上下文和调用栈
+func writeError(w http.ResponseWriter, status int, err error) {
+	var se *types.SystemError
+	if errors.As(err, &se) && se != nil {
+		writeJSON(w, status, map[string]string{"error": se.Message, "code": string(se.Code)})
+		return
+	}
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -34,6 +34,9 @@ type QuantumConfig struct {
 	DecoherenceRate  float64       // 退相干率
 	EntanglementRate float64       // 纠缠率
 	UpdateInterval   time.Duration // 更新间隔
+
+	// 环境噪声, 作为未单独设置噪声模型的量子态的默认噪声模型
+	Noise *NoiseModel
 }
 
 // EnergyConfig 能量配置
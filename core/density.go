@@ -0,0 +1,252 @@
+// core/density.go
+
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sort"
+)
+
+// 密度矩阵计算精度
+const (
+	densityTolerance     = 1e-9
+	densityJacobiSweeps  = 100
+	densityJacobiEpsilon = 1e-14
+)
+
+// DensityMatrix n能级密度矩阵
+type DensityMatrix [][]complex128
+
+// NewDensityMatrix 创建n能级零矩阵
+func NewDensityMatrix(n int) DensityMatrix {
+	rho := make(DensityMatrix, n)
+	for i := range rho {
+		rho[i] = make([]complex128, n)
+	}
+	return rho
+}
+
+// PureDensityMatrix 由振幅构造纯态密度矩阵 ρ = |ψ⟩⟨ψ|, 振幅自动归一化
+func PureDensityMatrix(amplitudes []complex128) (DensityMatrix, error) {
+	norm := 0.0
+	for _, a := range amplitudes {
+		norm += real(a)*real(a) + imag(a)*imag(a)
+	}
+	if len(amplitudes) == 0 || norm == 0 || math.IsNaN(norm) || math.IsInf(norm, 0) {
+		return nil, NewCoreErrorWithCode(ErrQuantum, "amplitudes must have a finite non-zero norm")
+	}
+
+	scale := complex(1/math.Sqrt(norm), 0)
+	rho := NewDensityMatrix(len(amplitudes))
+	for i, a := range amplitudes {
+		for j, b := range amplitudes {
+			rho[i][j] = a * scale * cmplx.Conj(b*scale)
+		}
+	}
+	return rho, nil
+}
+
+// MixedDensityMatrix 由各能级布居、相位和相干度构造密度矩阵
+// ρ = c·|ψ⟩⟨ψ| + (1-c)·diag(p), 其中ψ_k = √p_k·e^{iφ_k}; 布居自动归一化, phases可为nil
+func MixedDensityMatrix(populations, phases []float64, coherence float64) (DensityMatrix, error) {
+	if phases != nil && len(phases) != len(populations) {
+		return nil, NewCoreErrorWithCode(ErrQuantum, "phases and populations length mismatch")
+	}
+	if coherence < 0 || coherence > 1 || math.IsNaN(coherence) {
+		return nil, NewCoreErrorWithCode(ErrRange, fmt.Sprintf("coherence out of range [0,1]: %v", coherence))
+	}
+
+	total := 0.0
+	for _, p := range populations {
+		if p < 0 || math.IsNaN(p) || math.IsInf(p, 0) {
+			return nil, NewCoreErrorWithCode(ErrRange, fmt.Sprintf("invalid population: %v", p))
+		}
+		total += p
+	}
+	if len(populations) == 0 || total == 0 {
+		return nil, NewCoreErrorWithCode(ErrQuantum, "populations must have a non-zero sum")
+	}
+
+	n := len(populations)
+	rho := NewDensityMatrix(n)
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			magnitude := math.Sqrt(populations[i]*populations[j]) / total
+			if i == j {
+				rho[i][j] = complex(magnitude, 0)
+				continue
+			}
+			phase := 0.0
+			if phases != nil {
+				phase = phases[i] - phases[j]
+			}
+			rho[i][j] = cmplx.Rect(coherence*magnitude, phase)
+		}
+	}
+	return rho, nil
+}
+
+// Dim 能级数
+func (rho DensityMatrix) Dim() int {
+	return len(rho)
+}
+
+// Trace 迹(实部)
+func (rho DensityMatrix) Trace() float64 {
+	trace := 0.0
+	for i := range rho {
+		trace += real(rho[i][i])
+	}
+	return trace
+}
+
+// Validate 验证密度矩阵: 方阵、厄米、迹为1且半正定
+func (rho DensityMatrix) Validate() error {
+	n := len(rho)
+	if n == 0 {
+		return NewCoreErrorWithCode(ErrQuantum, "empty density matrix")
+	}
+	for i := range rho {
+		if len(rho[i]) != n {
+			return NewCoreErrorWithCode(ErrQuantum, "density matrix must be square")
+		}
+	}
+	for i := 0; i < n; i++ {
+		for j := i; j < n; j++ {
+			if cmplx.Abs(rho[i][j]-cmplx.Conj(rho[j][i])) > densityTolerance {
+				return NewCoreErrorWithCode(ErrQuantum, "density matrix must be hermitian")
+			}
+		}
+	}
+	if math.Abs(rho.Trace()-1) > densityTolerance {
+		return NewCoreErrorWithCode(ErrQuantum, fmt.Sprintf("density matrix trace must be 1: %v", rho.Trace()))
+	}
+	for _, lambda := range rho.Eigenvalues() {
+		if lambda < -densityTolerance {
+			return NewCoreErrorWithCode(ErrQuantum, "density matrix must be positive semidefinite")
+		}
+	}
+	return nil
+}
+
+// Purity 纯度 Tr(ρ²), 纯态为1, n能级最大混合态为1/n
+func (rho DensityMatrix) Purity() float64 {
+	// 厄米矩阵 Tr(ρ²) = Σ|ρ_ij|²
+	purity := 0.0
+	for i := range rho {
+		for _, v := range rho[i] {
+			purity += real(v)*real(v) + imag(v)*imag(v)
+		}
+	}
+	return purity
+}
+
+// Eigenvalues 厄米矩阵的特征值, 升序
+// 将 H = A + iB 嵌入实对称矩阵 [[A, -B], [B, A]], 其特征值为H的特征值各重复一次
+func (rho DensityMatrix) Eigenvalues() []float64 {
+	n := len(rho)
+	if n == 0 {
+		return nil
+	}
+
+	m := 2 * n
+	a := make([][]float64, m)
+	for i := range a {
+		a[i] = make([]float64, m)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			re, im := real(rho[i][j]), imag(rho[i][j])
+			a[i][j], a[i+n][j+n] = re, re
+			a[i][j+n], a[i+n][j] = -im, im
+		}
+	}
+
+	all := jacobiEigenvalues(a)
+	sort.Float64s(all)
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = all[2*i]
+	}
+	return values
+}
+
+// VonNeumannEntropy 冯·诺依曼熵 S = -Tr(ρ ln ρ), 单位为奈特
+func (rho DensityMatrix) VonNeumannEntropy() float64 {
+	entropy := 0.0
+	for _, lambda := range rho.Eigenvalues() {
+		if lambda > densityTolerance {
+			entropy -= lambda * math.Log(lambda)
+		}
+	}
+	return math.Max(0, entropy)
+}
+
+// NormalizedEntropy 归一化到[0,1]的冯·诺依曼熵, 以ln n为上限
+func (rho DensityMatrix) NormalizedEntropy() float64 {
+	if len(rho) < 2 {
+		return 0
+	}
+	return math.Min(1, rho.VonNeumannEntropy()/math.Log(float64(len(rho))))
+}
+
+// DensityMatrix 量子态的密度矩阵, 按退相干程度混合纯态与对角布居
+func (qs *QuantumState) DensityMatrix() (DensityMatrix, error) {
+	qs.mu.RLock()
+	defer qs.mu.RUnlock()
+
+	populations := make([]float64, len(qs.amplitude))
+	phases := make([]float64, len(qs.amplitude))
+	for i, a := range qs.amplitude {
+		populations[i] = real(a)*real(a) + imag(a)*imag(a)
+		phases[i] = cmplx.Phase(a)
+	}
+	return MixedDensityMatrix(populations, phases, 1-qs.decoherence)
+}
+
+// jacobiEigenvalues 循环Jacobi法求实对称矩阵的特征值, 会修改输入矩阵
+func jacobiEigenvalues(a [][]float64) []float64 {
+	n := len(a)
+	for sweep := 0; sweep < densityJacobiSweeps; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off < densityJacobiEpsilon {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if math.Abs(a[p][q]) < densityJacobiEpsilon {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p] = c*akp - s*akq
+					a[k][q] = s*akp + c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k] = c*apk - s*aqk
+					a[q][k] = s*apk + c*aqk
+				}
+			}
+		}
+	}
+
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = a[i][i]
+	}
+	return values
+}
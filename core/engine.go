@@ -82,6 +82,13 @@ func NewEngine(cfg *Config) (*Engine, error) {
 		config: cfg,
 	}
 
+	// 设置环境噪声
+	if cfg.Quantum != nil && cfg.Quantum.Noise != nil {
+		if err := SetDefaultNoiseModel(cfg.Quantum.Noise); err != nil {
+			return nil, err
+		}
+	}
+
 	// 初始化组件
 	e.components.field = NewField(ScalarField, cfg.Field.Dimension)
 	e.components.quantum = NewQuantumState()
@@ -118,12 +125,14 @@ func (e *Engine) GetTotalEnergy() float64 {
 	e.mu.RLock()
 	defer e.mu.RUnlock()
 
-	// 基础能量系统
-	baseEnergy := e.energySystem.GetTotalEnergy()
-
 	// 获取各个子系统的能量
 	var totalEnergy float64
 
+	// 基础能量系统
+	if e.energySystem != nil {
+		totalEnergy += e.energySystem.GetTotalEnergy()
+	}
+
 	// 量子系统能量
 	if e.quantumSystem != nil {
 		for _, state := range e.quantumSystem.GetStates() {
@@ -142,7 +151,7 @@ func (e *Engine) GetTotalEnergy() float64 {
 	}
 
 	// 返回归一化的总能量 (0-1范围)
-	systemEnergy := (baseEnergy + totalEnergy) / e.maxEnergy
+	systemEnergy := totalEnergy / e.maxEnergy
 	return math.Max(0.0, math.Min(1.0, systemEnergy))
 }
 
@@ -223,6 +232,32 @@ func (e *Engine) GetState() map[string]interface{} {
 	return state
 }
 
+// ExportEnergy 导出引擎能量分量
+func (e *Engine) ExportEnergy() map[EnergyType]float64 {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	energy := make(map[EnergyType]float64)
+	if e.components.energy == nil {
+		return energy
+	}
+	for typ := range FlowTypeMap {
+		energy[typ] = e.components.energy.GetEnergy(typ)
+	}
+	return energy
+}
+
+// RestoreEnergy 恢复引擎能量分量
+func (e *Engine) RestoreEnergy(energy map[EnergyType]float64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.components.energy == nil {
+		e.components.energy = NewEnergySystem(e.config.Base.MaxEnergy)
+	}
+	return e.components.energy.TransformEnergy(energy)
+}
+
 // GetEnergySystem 获取能量系统
 func (e *Engine) GetEnergySystem() *EnergySystem {
 	e.mu.RLock()
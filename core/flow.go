@@ -4,8 +4,6 @@ package core
 
 import (
 	"context"
-	"crypto/rand"
-	"encoding/hex"
 	"fmt"
 	"sync"
 	"time"
@@ -44,15 +42,9 @@ type Flow struct {
 }
 
 // ------------------------------------------
-// generateID 生成唯一标识符
+// GenerateID 以当前ID生成器生成无前缀的唯一标识符
 func GenerateID() string {
-	b := make([]byte, 16)
-	_, err := rand.Read(b)
-	if err != nil {
-		// 如果随机数生成失败，使用时间戳作为备选方案
-		return fmt.Sprintf("%d", time.Now().UnixNano())
-	}
-	return hex.EncodeToString(b)
+	return NewID("")
 }
 
 // FlowSource 定义流的源接口
@@ -0,0 +1,265 @@
+// core/geometry.go
+
+package core
+
+import (
+	"math"
+)
+
+// Boundary 网格边界条件
+type Boundary string
+
+const (
+	BoundaryOpen       Boundary = "open"       // 开放边界, 越界点被丢弃
+	BoundaryPeriodic   Boundary = "periodic"   // 周期边界, 越界点环绕到对侧
+	BoundaryReflective Boundary = "reflective" // 反射边界, 越界点镜像回网格内
+)
+
+// Adjacency 网格邻接方式
+type Adjacency string
+
+const (
+	AdjacencyVonNeumann Adjacency = "von_neumann" // 4邻域(三维6邻域)
+	AdjacencyMoore      Adjacency = "moore"       // 8邻域(三维26邻域)
+	AdjacencyHexagonal  Adjacency = "hexagonal"   // 六边形邻域, 轴向坐标(三维再加上下层)
+)
+
+// Geometry 场网格几何
+// 尺寸为0的轴不设边界, 仅开放边界允许; 零值即无界的二维4邻域网格
+type Geometry struct {
+	Dimensions int       `json:"dimensions"` // 维数: 2或3, 0视为2
+	Width      int       `json:"width"`      // X轴格数
+	Height     int       `json:"height"`     // Y轴格数
+	Depth      int       `json:"depth"`      // Z轴格数, 仅三维有效
+	Boundary   Boundary  `json:"boundary"`   // 边界条件
+	Adjacency  Adjacency `json:"adjacency"`  // 邻接方式
+}
+
+// 各邻接方式的平面偏移
+var (
+	vonNeumannOffsets = [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
+	mooreOffsets      = [][2]int{{-1, -1}, {0, -1}, {1, -1}, {-1, 0}, {1, 0}, {-1, 1}, {0, 1}, {1, 1}}
+	hexagonalOffsets  = [][2]int{{1, 0}, {-1, 0}, {0, 1}, {0, -1}, {1, -1}, {-1, 1}}
+)
+
+// DefaultGeometry 默认网格几何
+func DefaultGeometry() Geometry {
+	return Geometry{
+		Dimensions: 2,
+		Boundary:   BoundaryOpen,
+		Adjacency:  AdjacencyVonNeumann,
+	}
+}
+
+// Validate 验证网格几何
+func (g Geometry) Validate() error {
+	if g.Dimensions != 0 && g.Dimensions != 2 && g.Dimensions != 3 {
+		return NewCoreErrorWithCode(ErrRange, "geometry dimensions must be 2 or 3")
+	}
+	if g.Width < 0 || g.Height < 0 || g.Depth < 0 {
+		return NewCoreErrorWithCode(ErrRange, "geometry size must not be negative")
+	}
+
+	switch g.boundary() {
+	case BoundaryOpen:
+	case BoundaryPeriodic, BoundaryReflective:
+		for _, size := range g.sizes() {
+			if size == 0 {
+				return NewCoreErrorWithCode(ErrInvalid, "bounded geometry requires a size for every axis")
+			}
+		}
+	default:
+		return NewCoreErrorWithCode(ErrInvalid, "unknown geometry boundary: "+string(g.Boundary))
+	}
+
+	switch g.adjacency() {
+	case AdjacencyVonNeumann, AdjacencyMoore, AdjacencyHexagonal:
+	default:
+		return NewCoreErrorWithCode(ErrInvalid, "unknown geometry adjacency: "+string(g.Adjacency))
+	}
+	return nil
+}
+
+// Is3D 是否为三维网格
+func (g Geometry) Is3D() bool {
+	return g.Dimensions == 3
+}
+
+// Contains 判断点是否位于网格内
+func (g Geometry) Contains(p Point) bool {
+	coords := g.coords(p)
+	for i, size := range g.sizes() {
+		if size > 0 && (coords[i] < 0 || coords[i] >= size) {
+			return false
+		}
+	}
+	return g.Is3D() || p.Z == 0
+}
+
+// Normalize 按边界条件将点映射到网格内
+// 开放边界下越界点返回false; 二维网格忽略Z坐标
+func (g Geometry) Normalize(p Point) (Point, bool) {
+	if !g.Is3D() {
+		p.Z = 0
+	}
+	coords := g.coords(p)
+	for i, size := range g.sizes() {
+		if size == 0 || coords[i] >= 0 && coords[i] < size {
+			continue
+		}
+		switch g.boundary() {
+		case BoundaryPeriodic:
+			coords[i] = wrapCoord(coords[i], size)
+		case BoundaryReflective:
+			coords[i] = reflectCoord(coords[i], size)
+		default:
+			return p, false
+		}
+	}
+	return g.point(coords), true
+}
+
+// Neighbors 获取点在网格内的相邻点, 已去重且不含自身
+func (g Geometry) Neighbors(p Point) []Point {
+	var offsets [][2]int
+	switch g.adjacency() {
+	case AdjacencyMoore:
+		offsets = mooreOffsets
+	case AdjacencyHexagonal:
+		offsets = hexagonalOffsets
+	default:
+		offsets = vonNeumannOffsets
+	}
+
+	layers := []int{0}
+	if g.Is3D() {
+		layers = []int{-1, 0, 1}
+	}
+
+	neighbors := make([]Point, 0, len(offsets)*len(layers)+2)
+	seen := map[Point]bool{p: true}
+	add := func(dx, dy, dz int) {
+		n, ok := g.Normalize(Point{X: p.X + dx, Y: p.Y + dy, Z: p.Z + dz})
+		if ok && !seen[n] {
+			seen[n] = true
+			neighbors = append(neighbors, n)
+		}
+	}
+
+	for _, dz := range layers {
+		switch {
+		case dz == 0:
+			for _, d := range offsets {
+				add(d[0], d[1], 0)
+			}
+		case g.adjacency() == AdjacencyMoore:
+			// 三维Moore邻域: 上下层的全部9个格
+			add(0, 0, dz)
+			for _, d := range offsets {
+				add(d[0], d[1], dz)
+			}
+		default:
+			// 4邻域和六边形邻域仅连接正上下方
+			add(0, 0, dz)
+		}
+	}
+	return neighbors
+}
+
+// Vector 计算从p1到p2的笛卡尔位移
+// 周期边界取最近镜像; 六边形网格由轴向坐标换算为平面坐标
+func (g Geometry) Vector(p1, p2 Point) (float64, float64, float64) {
+	a, b := g.coords(p1), g.coords(p2)
+	var d [3]int
+	for i, size := range g.sizes() {
+		d[i] = b[i] - a[i]
+		if g.boundary() == BoundaryPeriodic && size > 0 {
+			d[i] = wrapCoord(d[i], size)
+			if 2*d[i] > size {
+				d[i] -= size
+			}
+		}
+	}
+
+	x, y, z := float64(d[0]), float64(d[1]), float64(d[2])
+	if g.adjacency() == AdjacencyHexagonal {
+		x, y = x+y/2, y*math.Sqrt(3)/2
+	}
+	return x, y, z
+}
+
+// Distance 计算两点间距离, 相邻格距离为1(Moore邻域的对角格除外)
+func (g Geometry) Distance(p1, p2 Point) float64 {
+	x, y, z := g.Vector(p1, p2)
+	return math.Sqrt(x*x + y*y + z*z)
+}
+
+// Project 将能量分布投影到网格上, 映射到同一格的能量累加, 开放边界下越界点被丢弃
+func (g Geometry) Project(dist map[Point]float64) map[Point]float64 {
+	projected := make(map[Point]float64, len(dist))
+	for p, energy := range dist {
+		if n, ok := g.Normalize(p); ok {
+			projected[n] += energy
+		}
+	}
+	return projected
+}
+
+// boundary 边界条件, 空值视为开放边界
+func (g Geometry) boundary() Boundary {
+	if g.Boundary == "" {
+		return BoundaryOpen
+	}
+	return g.Boundary
+}
+
+// adjacency 邻接方式, 空值视为4邻域
+func (g Geometry) adjacency() Adjacency {
+	if g.Adjacency == "" {
+		return AdjacencyVonNeumann
+	}
+	return g.Adjacency
+}
+
+// sizes 有效轴的格数
+func (g Geometry) sizes() []int {
+	if g.Is3D() {
+		return []int{g.Width, g.Height, g.Depth}
+	}
+	return []int{g.Width, g.Height}
+}
+
+// coords 点坐标
+func (g Geometry) coords(p Point) [3]int {
+	return [3]int{p.X, p.Y, p.Z}
+}
+
+// point 由坐标构造点
+func (g Geometry) point(coords [3]int) Point {
+	if !g.Is3D() {
+		coords[2] = 0
+	}
+	return Point{X: coords[0], Y: coords[1], Z: coords[2]}
+}
+
+// wrapCoord 周期环绕到[0,size)
+func wrapCoord(v, size int) int {
+	v %= size
+	if v < 0 {
+		v += size
+	}
+	return v
+}
+
+// reflectCoord 镜像反射到[0,size), 边界格不重复
+func reflectCoord(v, size int) int {
+	if size == 1 {
+		return 0
+	}
+	period := 2 * (size - 1)
+	v = wrapCoord(v, period)
+	if v >= size {
+		v = period - v
+	}
+	return v
+}
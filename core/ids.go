@@ -0,0 +1,215 @@
+// core/ids.go
+
+package core
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ID生成策略
+const (
+	IDStrategyULID   = "ulid"   // 按毫秒时间排序的ULID, 同一毫秒内单调递增
+	IDStrategyUUIDv7 = "uuidv7" // RFC 9562 UUIDv7, 按毫秒时间排序
+	IDStrategySeeded = "seeded" // 由种子和序号确定, 相同种子和生成顺序得到相同ID, 用于可复现运行
+)
+
+// IDGenerator ID生成器, 并发安全; 各组件生成的ID为前缀加NextID
+type IDGenerator interface {
+	NextID() string
+}
+
+// IDConfig ID生成配置
+type IDConfig struct {
+	Strategy string `json:"strategy"` // 为空时使用IDStrategyULID
+	Seed     uint64 `json:"seed"`     // IDStrategySeeded的种子
+}
+
+// defaultIDs 进程内生成ID使用的生成器, 为空时使用ULID
+var defaultIDs atomic.Pointer[idGeneratorHolder]
+
+// idGeneratorHolder 接口值不能直接存入atomic.Pointer
+type idGeneratorHolder struct {
+	gen IDGenerator
+}
+
+// fallbackIDs 未设置生成器时使用的ULID生成器
+var fallbackIDs = NewULIDGenerator()
+
+// NewIDGenerator 按配置创建ID生成器
+func NewIDGenerator(cfg IDConfig) (IDGenerator, error) {
+	switch cfg.Strategy {
+	case "", IDStrategyULID:
+		return NewULIDGenerator(), nil
+	case IDStrategyUUIDv7:
+		return NewUUIDv7Generator(), nil
+	case IDStrategySeeded:
+		return NewSeededIDGenerator(cfg.Seed), nil
+	default:
+		return nil, NewCoreErrorWithCode(ErrInvalid, fmt.Sprintf("unknown id strategy: %s", cfg.Strategy))
+	}
+}
+
+// SetIDGenerator 设置进程内的ID生成器, nil表示恢复默认的ULID
+// 生成器由进程内所有调用方共享, 再次设置会替换之前的生成器
+func SetIDGenerator(gen IDGenerator) {
+	if gen == nil {
+		defaultIDs.Store(nil)
+		return
+	}
+	defaultIDs.Store(&idGeneratorHolder{gen: gen})
+}
+
+// CurrentIDGenerator 获取进程内的ID生成器
+func CurrentIDGenerator() IDGenerator {
+	if holder := defaultIDs.Load(); holder != nil {
+		return holder.gen
+	}
+	return fallbackIDs
+}
+
+// NewID 以当前ID生成器生成带前缀的ID
+func NewID(prefix string) string {
+	return prefix + CurrentIDGenerator().NextID()
+}
+
+// ULIDGenerator ULID生成器: 48位毫秒时间戳加80位随机数, Crockford Base32编码为26个字符
+// 同一毫秒内随机部分递增, 保证唯一且按生成顺序排序
+type ULIDGenerator struct {
+	mu      sync.Mutex
+	lastMs  uint64
+	entropy [10]byte
+}
+
+// NewULIDGenerator 创建ULID生成器
+func NewULIDGenerator() *ULIDGenerator {
+	return &ULIDGenerator{}
+}
+
+// crockford Crockford Base32字母表
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// NextID 生成ULID
+func (g *ULIDGenerator) NextID() string {
+	g.mu.Lock()
+	ms := uint64(time.Now().UnixMilli())
+	if ms > g.lastMs {
+		g.lastMs = ms
+		readRandom(g.entropy[:])
+	} else {
+		// 时钟未前进或回拨时沿用上次时间戳, 随机部分加一
+		for i := len(g.entropy) - 1; i >= 0; i-- {
+			g.entropy[i]++
+			if g.entropy[i] != 0 {
+				break
+			}
+		}
+	}
+	var raw [16]byte
+	binary.BigEndian.PutUint16(raw[0:2], uint16(g.lastMs>>32))
+	binary.BigEndian.PutUint32(raw[2:6], uint32(g.lastMs))
+	copy(raw[6:], g.entropy[:])
+	g.mu.Unlock()
+
+	// 128位按5位一组编码, 首字符只含高3位
+	hi := binary.BigEndian.Uint64(raw[0:8])
+	lo := binary.BigEndian.Uint64(raw[8:16])
+	var out [26]byte
+	for i := 25; i >= 0; i-- {
+		out[i] = crockford[lo&0x1f]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// UUIDv7Generator UUIDv7生成器: 48位毫秒时间戳、版本和变体位, 其余为随机数
+// 同一毫秒内12位rand_a作为计数器递增, 溢出时借用下一毫秒
+type UUIDv7Generator struct {
+	mu      sync.Mutex
+	lastMs  uint64
+	counter uint16
+}
+
+// NewUUIDv7Generator 创建UUIDv7生成器
+func NewUUIDv7Generator() *UUIDv7Generator {
+	return &UUIDv7Generator{}
+}
+
+// NextID 生成UUIDv7的标准36字符形式
+func (g *UUIDv7Generator) NextID() string {
+	var raw [16]byte
+	readRandom(raw[:])
+
+	g.mu.Lock()
+	ms := uint64(time.Now().UnixMilli())
+	if ms > g.lastMs {
+		g.lastMs = ms
+		g.counter = binary.BigEndian.Uint16(raw[6:8]) & 0x07ff // 留出高位空间避免立即溢出
+	} else {
+		g.counter++
+		if g.counter > 0x0fff {
+			g.lastMs++
+			g.counter = 0
+		}
+	}
+	ms, counter := g.lastMs, g.counter
+	g.mu.Unlock()
+
+	binary.BigEndian.PutUint16(raw[0:2], uint16(ms>>32))
+	binary.BigEndian.PutUint32(raw[2:6], uint32(ms))
+	binary.BigEndian.PutUint16(raw[6:8], 0x7000|counter)
+	raw[8] = raw[8]&0x3f | 0x80
+
+	var out [36]byte
+	hex.Encode(out[0:8], raw[0:4])
+	out[8] = '-'
+	hex.Encode(out[9:13], raw[4:6])
+	out[13] = '-'
+	hex.Encode(out[14:18], raw[6:8])
+	out[18] = '-'
+	hex.Encode(out[19:23], raw[8:10])
+	out[23] = '-'
+	hex.Encode(out[24:], raw[10:])
+	return string(out[:])
+}
+
+// SeededIDGenerator 确定性ID生成器: 第n个ID为种子和n经SplitMix64混合后的16位十六进制数
+// 混合函数是双射, 同一种子下2^64个ID互不相同; 并发时ID集合确定, 分配给哪个调用方取决于调用顺序
+type SeededIDGenerator struct {
+	seed    uint64
+	counter atomic.Uint64
+}
+
+// NewSeededIDGenerator 创建确定性ID生成器
+func NewSeededIDGenerator(seed uint64) *SeededIDGenerator {
+	return &SeededIDGenerator{seed: seed}
+}
+
+// NextID 生成下一个ID
+func (g *SeededIDGenerator) NextID() string {
+	z := g.seed + g.counter.Add(1)*0x9e3779b97f4a7c15
+	z = (z ^ z>>30) * 0xbf58476d1ce4e5b9
+	z = (z ^ z>>27) * 0x94d049bb133111eb
+	z ^= z >> 31
+	return fmt.Sprintf("%016x", z)
+}
+
+// readRandom 读取加密随机数, 失败时以时间派生的数据填充
+func readRandom(b []byte) {
+	if _, err := rand.Read(b); err == nil {
+		return
+	}
+	x := uint64(time.Now().UnixNano())
+	for i := range b {
+		x ^= x << 13
+		x ^= x >> 7
+		x ^= x << 17
+		b[i] = byte(x)
+	}
+}
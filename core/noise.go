@@ -0,0 +1,193 @@
+// core/noise.go
+
+package core
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"sync/atomic"
+	"time"
+)
+
+// NoiseChannelType 噪声信道类型
+type NoiseChannelType string
+
+const (
+	NoiseDephasing        NoiseChannelType = "dephasing"         // 退相位: 相干项衰减, 布居不变
+	NoiseAmplitudeDamping NoiseChannelType = "amplitude_damping" // 振幅阻尼: 激发态布居向基态弛豫
+	NoiseDepolarizing     NoiseChannelType = "depolarizing"      // 退极化: 趋向最大混合态
+)
+
+// DefaultNoiseStep 每次演化对应的默认时长
+const DefaultNoiseStep = 10 * time.Millisecond
+
+// NoiseChannel 噪声信道
+type NoiseChannel struct {
+	Type NoiseChannelType `json:"type"`
+	Rate float64          `json:"rate"` // 每秒速率, 时长t内的作用强度为1-exp(-Rate·t)
+}
+
+// NoiseModel 噪声模型, 信道按顺序作用
+type NoiseModel struct {
+	Channels []NoiseChannel `json:"channels"`
+	Step     time.Duration  `json:"step"` // 每次演化对应的时长, 0为DefaultNoiseStep
+}
+
+// defaultNoise 未单独设置噪声模型的量子态使用的噪声模型
+var defaultNoise atomic.Pointer[NoiseModel]
+
+// Validate 验证噪声模型
+func (nm *NoiseModel) Validate() error {
+	if nm.Step < 0 {
+		return NewCoreErrorWithCode(ErrRange, "noise step must not be negative")
+	}
+	for _, ch := range nm.Channels {
+		switch ch.Type {
+		case NoiseDephasing, NoiseAmplitudeDamping, NoiseDepolarizing:
+		default:
+			return NewCoreErrorWithCode(ErrQuantum, fmt.Sprintf("unknown noise channel: %s", ch.Type))
+		}
+		if ch.Rate < 0 || math.IsNaN(ch.Rate) || math.IsInf(ch.Rate, 0) {
+			return NewCoreErrorWithCode(ErrRange, fmt.Sprintf("invalid %s rate: %v", ch.Type, ch.Rate))
+		}
+	}
+	return nil
+}
+
+// step 每次演化对应的时长
+func (nm *NoiseModel) step() time.Duration {
+	if nm.Step > 0 {
+		return nm.Step
+	}
+	return DefaultNoiseStep
+}
+
+// clone 复制噪声模型
+func (nm *NoiseModel) clone() *NoiseModel {
+	if nm == nil {
+		return nil
+	}
+	c := *nm
+	c.Channels = append([]NoiseChannel(nil), nm.Channels...)
+	return &c
+}
+
+// SetDefaultNoiseModel 设置默认噪声模型, nil表示无噪声
+func SetDefaultNoiseModel(model *NoiseModel) error {
+	if model != nil {
+		if err := model.Validate(); err != nil {
+			return err
+		}
+	}
+	defaultNoise.Store(model.clone())
+	return nil
+}
+
+// DefaultNoiseModel 获取默认噪声模型
+func DefaultNoiseModel() *NoiseModel {
+	return defaultNoise.Load().clone()
+}
+
+// SetNoiseModel 设置量子态的噪声模型, 演化时作用; nil表示使用默认噪声模型
+func (qs *QuantumState) SetNoiseModel(model *NoiseModel) error {
+	if model != nil {
+		if err := model.Validate(); err != nil {
+			return err
+		}
+	}
+
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	qs.noise = model.clone()
+	return nil
+}
+
+// GetNoiseModel 获取量子态生效的噪声模型
+func (qs *QuantumState) GetNoiseModel() *NoiseModel {
+	qs.mu.RLock()
+	defer qs.mu.RUnlock()
+	return qs.noiseModel().clone()
+}
+
+// GetDecoherence 获取退相干程度(0-1), 0为纯态, 1为相干项完全消失
+func (qs *QuantumState) GetDecoherence() float64 {
+	qs.mu.RLock()
+	defer qs.mu.RUnlock()
+	return qs.decoherence
+}
+
+// SetDecoherence 设置退相干程度(0-1), 用于从记录的快照恢复量子态
+func (qs *QuantumState) SetDecoherence(decoherence float64) error {
+	if decoherence < 0 || decoherence > 1 || math.IsNaN(decoherence) {
+		return NewCoreErrorWithCode(ErrRange, fmt.Sprintf("decoherence out of range [0,1]: %v", decoherence))
+	}
+
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+	qs.decoherence = decoherence
+	return nil
+}
+
+// ApplyNoise 按生效的噪声模型作用时长dt
+func (qs *QuantumState) ApplyNoise(dt time.Duration) error {
+	if dt < 0 {
+		return NewCoreErrorWithCode(ErrRange, "noise duration must not be negative")
+	}
+
+	qs.mu.Lock()
+	defer qs.mu.Unlock()
+
+	if model := qs.noiseModel(); model != nil {
+		qs.applyNoise(model, dt)
+	}
+	return nil
+}
+
+// noiseModel 生效的噪声模型, 调用方须持有锁
+func (qs *QuantumState) noiseModel() *NoiseModel {
+	if qs.noise != nil {
+		return qs.noise
+	}
+	return defaultNoise.Load()
+}
+
+// applyNoise 作用噪声信道, 调用方须持有锁
+// 量子态视为二能级系统: 激发态布居为probability, 相干项|ρ01| = (1-decoherence)·√(p(1-p))
+func (qs *QuantumState) applyNoise(model *NoiseModel, dt time.Duration) {
+	p := qs.probability
+	offDiagonal := (1 - qs.decoherence) * math.Sqrt(p*(1-p))
+
+	for _, ch := range model.Channels {
+		lambda := 1 - math.Exp(-ch.Rate*dt.Seconds())
+		if lambda <= 0 {
+			continue
+		}
+		switch ch.Type {
+		case NoiseDephasing:
+			offDiagonal *= 1 - lambda
+		case NoiseAmplitudeDamping:
+			p *= 1 - lambda
+			offDiagonal *= math.Sqrt(1 - lambda)
+		case NoiseDepolarizing:
+			p = (1-lambda)*p + lambda/2
+			offDiagonal *= 1 - lambda
+		}
+	}
+	p = math.Max(MinProbability, math.Min(MaxProbability, p))
+
+	// 布居在0或1时相干项必为0, 退相干程度保持不变
+	if maxOffDiagonal := math.Sqrt(p * (1 - p)); maxOffDiagonal > 0 {
+		qs.decoherence = math.Max(0, math.Min(1, 1-offDiagonal/maxOffDiagonal))
+	}
+
+	// 布居变化同步到能量和振幅, 与Evolve一致
+	qs.energy *= 1 + (p - qs.probability)
+	qs.probability = p
+	if len(qs.amplitude) > 0 {
+		if currentAmp := cmplx.Abs(qs.amplitude[0]); currentAmp > 0 {
+			qs.amplitude[0] *= complex(math.Sqrt(p)/currentAmp, 0)
+		}
+	}
+	qs.updateEntropy()
+}
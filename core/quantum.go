@@ -21,6 +21,8 @@ type QuantumState struct {
 	entropy        float64      // 系统熵
 	amplitude      []complex128 // 改为私有
 	phaseVariation float64      // 相位变化率
+	decoherence    float64      // 退相干程度 (0-1), 0为纯态
+	noise          *NoiseModel  // 噪声模型, nil时使用默认噪声模型
 }
 
 // QuantumPattern 常量 - 量子态演化模式
@@ -178,6 +180,7 @@ func (qs *QuantumState) Initialize() error {
 	qs.phase = DefaultPhase
 	qs.energy = DefaultEnergy
 	qs.entropy = DefaultEntropy
+	qs.decoherence = 0
 	qs.amplitude = make([]complex128, 1)
 	qs.amplitude[0] = complex(1, 0) // 初始化为基态
 
@@ -397,6 +400,11 @@ func (qs *QuantumState) Evolve(pattern QuantumPattern) error {
 	// 更新熵
 	qs.updateEntropy()
 
+	// 作用环境噪声
+	if model := qs.noiseModel(); model != nil {
+		qs.applyNoise(model, model.step())
+	}
+
 	return nil
 }
 
@@ -413,6 +421,7 @@ func (qs *QuantumState) Collapse() error {
 	}
 
 	qs.phase = DefaultPhase
+	qs.decoherence = 0
 
 	// 更新振幅为对应的本征态
 	qs.amplitude = make([]complex128, 1)
@@ -464,8 +473,8 @@ func (qs *QuantumState) GetCoherence() float64 {
 	phaseContribution := math.Cos(qs.phase)   // 相位对相干性的贡献
 	probabilityContribution := qs.probability // 概率对相干性的贡献
 
-	// 相干性在 [0,1] 范围内
-	coherence := (phaseContribution + 1) * probabilityContribution / 2
+	// 相干性在 [0,1] 范围内, 随退相干衰减
+	coherence := (phaseContribution + 1) * probabilityContribution / 2 * (1 - qs.decoherence)
 	return math.Max(0, math.Min(1, coherence))
 }
 
@@ -548,8 +557,8 @@ func (qs *QuantumState) GetEntanglement() float64 {
 	phaseContribution := math.Cos(qs.phase)
 	amplitudeContribution := qs.probability * qs.probability
 
-	// 归一化到[0,1]区间
-	entanglement := (phaseContribution + 1.0) * amplitudeContribution / 2.0
+	// 归一化到[0,1]区间, 随退相干衰减
+	entanglement := (phaseContribution + 1.0) * amplitudeContribution / 2.0 * (1 - qs.decoherence)
 	return math.Max(0, math.Min(1, entanglement))
 }
 
@@ -76,10 +76,11 @@ type EmergentProperty struct {
 	Created    time.Time          // 创建时间
 }
 
-// Point 二维点结构
+// Point 网格点结构, 二维网格中Z恒为0
 type Point struct {
 	X int
 	Y int
+	Z int
 }
 
 // -----------------------------------------------
@@ -3,11 +3,12 @@
 package model
 
 import (
-	"fmt"
 	"math"
 	"sort"
 	"sync"
 	"time"
+
+	"github.com/Corphon/daoflow/core"
 )
 
 const (
@@ -84,6 +85,27 @@ type Span interface {
 	GetMetrics() map[string]float64
 }
 
+// analyzerMetrics 分析器计算的指标定义, 见ModelMetrics.Values
+var analyzerMetrics = []MetricDesc{
+	{Name: "model.spans", Unit: UnitCount, Description: "分析的span数"},
+	{Name: "model.error_rate", Unit: UnitRatio, Description: "出错span比例", Bounded: true, Min: 0, Max: 1},
+	{Name: "model.latency", Unit: UnitSeconds, Description: "span平均耗时"},
+	{Name: "model.energy.total", Unit: UnitEnergy, Description: "span总能量"},
+	{Name: "model.energy.average", Unit: UnitEnergy, Description: "span平均能量"},
+	{Name: "model.energy.variance", Unit: UnitNone, Description: "span能量方差"},
+	{Name: "model.state.transitions", Unit: UnitCount, Description: "状态转换次数"},
+	{Name: "model.state.stability", Unit: UnitRatio, Description: "状态稳定性", Bounded: true, Min: 0, Max: 1},
+	{Name: "model.state.uptime", Unit: UnitSeconds, Description: "span覆盖的运行时间"},
+	{Name: "model.performance.throughput", Unit: UnitPerSecond, Description: "每秒完成的span数"},
+	{Name: "model.performance.qps", Unit: UnitPerSecond, Description: "每秒请求数"},
+}
+
+func init() {
+	if err := DefaultMetricRegistry().Register(analyzerMetrics...); err != nil {
+		panic(err)
+	}
+}
+
 // -------------------------------------------------------
 // NewAnalyzer 创建新的模型分析器
 func NewAnalyzer() *Analyzer {
@@ -465,7 +487,7 @@ func (sp *StatePredictor) PredictNext(metrics ModelMetrics) (ModelState, error)
 
 // generateTimeSeriesID 生成时间序列ID
 func generateTimeSeriesID(metricType string) string {
-	return fmt.Sprintf("ts_%s_%d", metricType, time.Now().UnixNano())
+	return core.NewID("ts_" + metricType + "_")
 }
 
 // detectCyclicPattern 检测周期性模式
@@ -474,8 +496,9 @@ func detectCyclicPattern(series TimeSeries) *FlowPattern {
 		return nil
 	}
 
-	// 计算自相关性来检测周期
-	periods := detectPeriods(series.Points)
+	// 通过功率谱检测周期
+	spectrum := SpectrumOf(series.Points)
+	periods := spectrum.Periods()
 	if len(periods) == 0 {
 		return nil
 	}
@@ -491,8 +514,10 @@ func detectCyclicPattern(series TimeSeries) *FlowPattern {
 			Duration:   series.EndTime.Sub(series.StartTime),
 		},
 		Properties: map[string]interface{}{
-			"periods": periods,
-			"phases":  detectPhases(series.Points, periods[0]),
+			"periods":   periods,
+			"phases":    detectPhases(series.Points, periods[0]),
+			"harmonics": spectrum.Harmonics(),
+			"spectrum":  spectrum.Method,
 		},
 		Created: time.Now(),
 	}
@@ -500,7 +525,7 @@ func detectCyclicPattern(series TimeSeries) *FlowPattern {
 
 // generatePatternID 生成模式ID
 func generatePatternID() string {
-	return fmt.Sprintf("pattern_%d", time.Now().UnixNano())
+	return core.NewID("pattern_")
 }
 
 // calculateFrequency 计算周期频率
@@ -802,52 +827,6 @@ func determineState(value, transitionValue float64) string {
 
 // 辅助函数
 
-// detectPeriods 检测时间序列中的周期
-func detectPeriods(points []TimeSeriesPoint) []float64 {
-	if len(points) < 4 {
-		return nil
-	}
-
-	// 计算自相关序列
-	maxLag := len(points) / 2
-	autocorr := make([]float64, maxLag)
-	mean := 0.0
-
-	// 计算均值
-	for _, p := range points {
-		mean += p.Value
-	}
-	mean /= float64(len(points))
-
-	// 计算自相关系数
-	for lag := 0; lag < maxLag; lag++ {
-		sum := 0.0
-		count := 0
-		for i := 0; i < len(points)-lag; i++ {
-			sum += (points[i].Value - mean) * (points[i+lag].Value - mean)
-			count++
-		}
-		if count > 0 {
-			autocorr[lag] = sum / float64(count)
-		}
-	}
-
-	// 查找峰值作为周期候选
-	periods := make([]float64, 0)
-	for i := 2; i < len(autocorr)-1; i++ {
-		if autocorr[i] > autocorr[i-1] && autocorr[i] > autocorr[i+1] {
-			timeDiff := points[i].Timestamp.Sub(points[0].Timestamp).Seconds()
-			if timeDiff > 0 {
-				periods = append(periods, timeDiff)
-			}
-		}
-	}
-
-	// 按周期长度排序
-	sort.Float64s(periods)
-	return periods
-}
-
 // calculateTrendLine 计算趋势线
 func calculateTrendLine(points []TimeSeriesPoint) (slope float64, r2 float64) {
 	if len(points) < 2 {
@@ -206,6 +206,44 @@ func (b *BaseFlowModel) SetEnergy(energy float64) error {
 	return b.stateManager.UpdateState()
 }
 
+// RestoreState 恢复模型状态, 包括能量、相位、属性、健康度和扩展属性
+func (b *BaseFlowModel) RestoreState(state ModelState) error {
+	if !ValidateEnergy(state.Energy) {
+		return NewModelError(ErrCodeOperation, "invalid energy value", nil)
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// 模型组件和状态管理器各自持有能量系统和量子态, 一并恢复
+	energyMap := map[core.EnergyType]float64{
+		core.PotentialEnergy: state.Energy / 4,
+		core.KineticEnergy:   state.Energy / 4,
+		core.ThermalEnergy:   state.Energy / 4,
+		core.FieldEnergy:     state.Energy / 4,
+	}
+	for _, energy := range []*core.EnergySystem{b.components.energy, b.stateManager.internal.energy} {
+		if err := energy.TransformEnergy(energyMap); err != nil {
+			return err
+		}
+	}
+	for _, quantum := range []*core.QuantumState{b.components.quantum, b.stateManager.internal.quantum} {
+		if err := quantum.SetPhase(float64(state.Phase)); err != nil {
+			return err
+		}
+	}
+
+	b.state.Energy = state.Energy
+	b.state.Phase = state.Phase
+	b.state.UpdateTime = time.Now()
+
+	if err := b.stateManager.UpdateState(); err != nil {
+		return err
+	}
+	b.stateManager.restoreModelState(state)
+	return nil
+}
+
 // initializeState 初始化状态
 func (b *BaseFlowModel) initializeState() error {
 	// 初始化量子态
@@ -0,0 +1,118 @@
+// model/capability.go
+
+package model
+
+import (
+	"sort"
+)
+
+// Capability 模型能力
+type Capability string
+
+const (
+	CapTransformable  Capability = "transformable"  // 可执行模式转换
+	CapTransactional  Capability = "transactional"  // 支持两阶段转换
+	CapEnergyBearing  Capability = "energy"         // 承载并可调整能量
+	CapObservable     Capability = "observable"     // 可观测模型与系统状态
+	CapCoreState      Capability = "core_state"     // 暴露core层状态
+	CapLifecycle      Capability = "lifecycle"      // 可启停
+	CapSynchronizable Capability = "synchronizable" // 接入时自行同步系统状态
+)
+
+// Transformable 可执行模式转换的模型
+type Transformable interface {
+	Transform(pattern TransformPattern) error
+}
+
+// EnergyBearing 承载能量的模型
+type EnergyBearing interface {
+	GetState() ModelState
+	SetEnergy(energy float64) error
+	AdjustEnergy(delta float64) error
+}
+
+// StateRestorer 可从快照恢复完整模型状态的模型
+type StateRestorer interface {
+	RestoreState(state ModelState) error
+}
+
+// Observable 可观测的模型
+type Observable interface {
+	GetState() ModelState
+	GetSystemState() SystemState
+}
+
+// CoreStateful 暴露core层状态的模型
+type CoreStateful interface {
+	GetCoreState() CoreState
+	UpdateCoreState(state CoreState) error
+	ValidateCoreState() error
+}
+
+// Startable 可启停的模型
+type Startable interface {
+	Start() error
+	Stop() error
+}
+
+// Synchronizable 接入运行中系统时自行同步系统状态的模型
+type Synchronizable interface {
+	SyncSystemState(state SystemState) error
+}
+
+// CapabilityProvider 声明额外能力的模型
+// 接口检测无法表达的能力(如只读、可并行转换)由模型自行声明
+type CapabilityProvider interface {
+	Capabilities() []Capability
+}
+
+// CapabilitiesOf 获取模型支持的能力, 按名称排序
+// 包含按接口检测到的能力和模型通过 CapabilityProvider 声明的能力
+func CapabilitiesOf(m interface{}) []Capability {
+	set := make(map[Capability]struct{})
+	if _, ok := m.(Transformable); ok {
+		set[CapTransformable] = struct{}{}
+	}
+	if _, ok := m.(TransactionalModel); ok {
+		set[CapTransactional] = struct{}{}
+	}
+	if _, ok := m.(EnergyBearing); ok {
+		set[CapEnergyBearing] = struct{}{}
+	}
+	if _, ok := m.(Observable); ok {
+		set[CapObservable] = struct{}{}
+	}
+	if _, ok := m.(CoreStateful); ok {
+		set[CapCoreState] = struct{}{}
+	}
+	if _, ok := m.(Startable); ok {
+		set[CapLifecycle] = struct{}{}
+	}
+	if _, ok := m.(Synchronizable); ok {
+		set[CapSynchronizable] = struct{}{}
+	}
+	if p, ok := m.(CapabilityProvider); ok {
+		for _, c := range p.Capabilities() {
+			set[c] = struct{}{}
+		}
+	}
+
+	caps := make([]Capability, 0, len(set))
+	for c := range set {
+		caps = append(caps, c)
+	}
+	sort.Slice(caps, func(i, j int) bool {
+		return caps[i] < caps[j]
+	})
+	return caps
+}
+
+// HasCapability 检查模型是否支持指定能力
+func HasCapability(m interface{}, capability Capability) bool {
+	for _, c := range CapabilitiesOf(m) {
+		if c == capability {
+			return true
+		}
+	}
+	return false
+}
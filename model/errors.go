@@ -3,6 +3,7 @@
 package model
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
@@ -107,6 +108,20 @@ func (e *ModelError) Error() string {
 	return b.String()
 }
 
+// Unwrap 返回原因错误, 支持errors.Is/As沿错误链查找
+func (e *ModelError) Unwrap() error {
+	return e.Cause
+}
+
+// Is 错误码相同即视为匹配; 目标带消息时消息也需相同
+func (e *ModelError) Is(target error) bool {
+	t, ok := target.(*ModelError)
+	if !ok {
+		return false
+	}
+	return e.Code == t.Code && (t.Message == "" || e.Message == t.Message)
+}
+
 // LogError 记录错误信息
 func LogError(err error) {
 	if err == nil {
@@ -183,15 +198,16 @@ func WrapError(err error, code ErrorCode, message string) *ModelError {
 	return NewModelError(code, message, err)
 }
 
-// IsModelError 检查是否为模型错误
+// IsModelError 检查错误链中是否包含模型错误
 func IsModelError(err error) bool {
-	_, ok := err.(*ModelError)
-	return ok
+	var modelErr *ModelError
+	return errors.As(err, &modelErr)
 }
 
-// GetErrorCode 获取错误码
+// GetErrorCode 获取错误链中首个模型错误的错误码
 func GetErrorCode(err error) ErrorCode {
-	if modelErr, ok := err.(*ModelError); ok {
+	var modelErr *ModelError
+	if errors.As(err, &modelErr) {
 		return modelErr.Code
 	}
 	return ""
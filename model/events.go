@@ -3,8 +3,9 @@
 package model
 
 import (
-	"fmt"
 	"time"
+
+	"github.com/Corphon/daoflow/core"
 )
 
 // ModelEventType 模型事件类型
@@ -95,5 +96,5 @@ func (e *ModelEvent) AddStateChange(field string, oldValue, newValue interface{}
 
 // generateEventID 生成事件ID
 func generateEventID() string {
-	return fmt.Sprintf("evt_%d", time.Now().UnixNano())
+	return core.NewID("evt_")
 }
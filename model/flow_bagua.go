@@ -185,6 +185,75 @@ func (f *BaGuaFlow) Transform(pattern TransformPattern) error {
 	}
 }
 
+// PrepareTransform 准备八卦转换, 回滚时恢复基础状态、卦象状态、变化记录及其量子态
+func (f *BaGuaFlow) PrepareTransform(pattern TransformPattern) (TransformTx, error) {
+	if !ValidateTransformPattern(pattern) {
+		return nil, NewModelError(ErrCodeInvalid, "invalid transform pattern", nil)
+	}
+	return f.prepareTransform(pattern)
+}
+
+// prepareTransform 保存八卦状态快照
+func (f *BaGuaFlow) prepareTransform(pattern TransformPattern) (*transformTx, error) {
+	base := copyModelState(f.BaseFlowModel.GetState())
+	if !ValidateEnergy(base.Energy) {
+		return nil, NewModelError(ErrCodeState, "model energy out of range", nil)
+	}
+
+	f.mu.RLock()
+	trigrams := copyTrigrams(f.state.trigrams)
+	resonance, harmony := f.state.resonance, f.state.harmony
+	changes := append([]Change(nil), f.state.changes...)
+	quanta := make(map[Trigram]quantumSnapshot, len(f.components.states))
+	for tri, qs := range f.components.states {
+		quanta[tri] = snapshotQuantum(qs)
+	}
+	f.mu.RUnlock()
+
+	return &transformTx{
+		pattern: pattern,
+		apply:   f.Transform,
+		restore: func() error {
+			if err := f.BaseFlowModel.RestoreState(base); err != nil {
+				return err
+			}
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.state.trigrams = copyTrigrams(trigrams)
+			f.state.resonance = resonance
+			f.state.harmony = harmony
+			f.state.changes = append([]Change(nil), changes...)
+			for tri, snapshot := range quanta {
+				if err := snapshot.restore(f.components.states[tri]); err != nil {
+					return err
+				}
+			}
+			// 卦象场由卦象能量决定, 按恢复后的能量重建
+			for tri, state := range f.state.trigrams {
+				if err := f.components.fields[tri].Update(state.Energy); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// copyTrigrams 深拷贝卦象状态
+func copyTrigrams(trigrams map[Trigram]*TrigramState) map[Trigram]*TrigramState {
+	copied := make(map[Trigram]*TrigramState, len(trigrams))
+	for tri, state := range trigrams {
+		c := *state
+		c.Relations = make(map[Trigram]float64, len(state.Relations))
+		for k, v := range state.Relations {
+			c.Relations[k] = v
+		}
+		copied[tri] = &c
+	}
+	return copied
+}
+
 // balanceTrigrams 平衡卦象
 func (f *BaGuaFlow) balanceTrigrams() error {
 	// 计算总能量
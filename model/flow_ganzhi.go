@@ -186,6 +186,91 @@ func (f *GanZhiFlow) Transform(pattern TransformPattern) error {
 	}
 }
 
+// PrepareTransform 准备干支转换, 回滚时恢复基础状态、干支状态、周期及其量子态
+func (f *GanZhiFlow) PrepareTransform(pattern TransformPattern) (TransformTx, error) {
+	if !ValidateTransformPattern(pattern) {
+		return nil, NewModelError(ErrCodeInvalid, "invalid transform pattern", nil)
+	}
+	return f.prepareTransform(pattern)
+}
+
+// prepareTransform 保存干支状态快照
+func (f *GanZhiFlow) prepareTransform(pattern TransformPattern) (*transformTx, error) {
+	base := copyModelState(f.BaseFlowModel.GetState())
+	if !ValidateEnergy(base.Energy) {
+		return nil, NewModelError(ErrCodeState, "model energy out of range", nil)
+	}
+
+	f.mu.RLock()
+	stems, branches := copyStems(f.state.stems), copyBranches(f.state.branches)
+	cycle, harmony := f.state.cycle, f.state.harmony
+	stemQuanta := make(map[HeavenlyStem]quantumSnapshot, len(f.components.stemStates))
+	for stem, qs := range f.components.stemStates {
+		stemQuanta[stem] = snapshotQuantum(qs)
+	}
+	branchQuanta := make(map[EarthlyBranch]quantumSnapshot, len(f.components.branchStates))
+	for branch, qs := range f.components.branchStates {
+		branchQuanta[branch] = snapshotQuantum(qs)
+	}
+	f.mu.RUnlock()
+
+	return &transformTx{
+		pattern: pattern,
+		apply:   f.Transform,
+		restore: func() error {
+			if err := f.BaseFlowModel.RestoreState(base); err != nil {
+				return err
+			}
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.state.stems = copyStems(stems)
+			f.state.branches = copyBranches(branches)
+			f.state.cycle = cycle
+			f.state.harmony = harmony
+			for stem, snapshot := range stemQuanta {
+				if err := snapshot.restore(f.components.stemStates[stem]); err != nil {
+					return err
+				}
+			}
+			for branch, snapshot := range branchQuanta {
+				if err := snapshot.restore(f.components.branchStates[branch]); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// copyStems 深拷贝天干状态
+func copyStems(stems map[HeavenlyStem]*StemState) map[HeavenlyStem]*StemState {
+	copied := make(map[HeavenlyStem]*StemState, len(stems))
+	for stem, state := range stems {
+		c := *state
+		c.Relations = make(map[EarthlyBranch]float64, len(state.Relations))
+		for k, v := range state.Relations {
+			c.Relations[k] = v
+		}
+		copied[stem] = &c
+	}
+	return copied
+}
+
+// copyBranches 深拷贝地支状态
+func copyBranches(branches map[EarthlyBranch]*BranchState) map[EarthlyBranch]*BranchState {
+	copied := make(map[EarthlyBranch]*BranchState, len(branches))
+	for branch, state := range branches {
+		c := *state
+		c.Relations = make(map[HeavenlyStem]float64, len(state.Relations))
+		for k, v := range state.Relations {
+			c.Relations[k] = v
+		}
+		copied[branch] = &c
+	}
+	return copied
+}
+
 // getCurrentGanZhi 获取当前干支组合
 func (f *GanZhiFlow) getCurrentGanZhi() (HeavenlyStem, EarthlyBranch) {
 	// 从当前周期计算天干和地支
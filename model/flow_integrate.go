@@ -3,6 +3,7 @@
 package model
 
 import (
+	"errors"
 	"log"
 	"math"
 	"sync"
@@ -169,6 +170,53 @@ func (im *IntegrateFlow) Transform(pattern TransformPattern) error {
 	return nil
 }
 
+// PrepareTransform 准备集成转换
+// 保存各子模型快照, 任一子模型转换失败回滚时全部子模型恢复到转换前状态
+func (im *IntegrateFlow) PrepareTransform(pattern TransformPattern) (TransformTx, error) {
+	if !ValidateTransformPattern(pattern) {
+		return nil, NewModelError(ErrCodeInvalid, "invalid transform pattern", nil)
+	}
+
+	im.mu.RLock()
+	running := im.running
+	saved := im.systemState
+	im.mu.RUnlock()
+	if !running {
+		return nil, NewModelError(ErrCodeOperation, "model not running", nil)
+	}
+
+	subs := []*transformTx{im.yinyang.prepareTransform(pattern)}
+	for _, prepare := range []func(TransformPattern) (*transformTx, error){
+		im.wuxing.prepareTransform,
+		im.bagua.prepareTransform,
+		im.ganzhi.prepareTransform,
+	} {
+		tx, err := prepare(pattern)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, tx)
+	}
+
+	return &transformTx{
+		pattern: pattern,
+		apply:   im.Transform,
+		restore: func() error {
+			errs := make([]error, 0)
+			for i := len(subs) - 1; i >= 0; i-- {
+				if err := subs[i].restore(); err != nil {
+					errs = append(errs, err)
+				}
+			}
+
+			im.mu.Lock()
+			im.systemState = saved
+			im.mu.Unlock()
+			return errors.Join(errs...)
+		},
+	}, nil
+}
+
 // synchronizeModels 同步子模型
 func (im *IntegrateFlow) synchronizeModels() {
 	// 阴阳与五行同步
@@ -337,6 +337,77 @@ func (f *WuXingFlow) Transform(pattern TransformPattern) error {
 	}
 }
 
+// PrepareTransform 准备五行转换, 回滚时恢复基础状态、各元素状态及其量子态
+func (f *WuXingFlow) PrepareTransform(pattern TransformPattern) (TransformTx, error) {
+	if !ValidateTransformPattern(pattern) {
+		return nil, NewModelError(ErrCodeInvalid, "invalid transform pattern", nil)
+	}
+	return f.prepareTransform(pattern)
+}
+
+// prepareTransform 保存五行状态快照
+func (f *WuXingFlow) prepareTransform(pattern TransformPattern) (*transformTx, error) {
+	base := copyModelState(f.BaseFlowModel.GetState())
+	if !ValidateEnergy(base.Energy) {
+		return nil, NewModelError(ErrCodeState, "model energy out of range", nil)
+	}
+
+	f.mu.RLock()
+	elements := copyWuXingElements(f.state.WuXingElements)
+	cycle, strength := f.state.cycle, f.state.strength
+	quanta := make(map[WuXingElement]quantumSnapshot, len(f.components.states))
+	for elem, qs := range f.components.states {
+		quanta[elem] = snapshotQuantum(qs)
+	}
+	f.mu.RUnlock()
+
+	return &transformTx{
+		pattern: pattern,
+		apply:   f.Transform,
+		restore: func() error {
+			if err := f.BaseFlowModel.RestoreState(base); err != nil {
+				return err
+			}
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.state.WuXingElements = copyWuXingElements(elements)
+			f.state.cycle = cycle
+			f.state.strength = strength
+			for elem, snapshot := range quanta {
+				if err := snapshot.restore(f.components.states[elem]); err != nil {
+					return err
+				}
+			}
+			// 元素场由元素能量决定, 按恢复后的能量重建
+			for elem, state := range f.state.WuXingElements {
+				if err := f.components.fields[elem].Update(state.Energy); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// copyWuXingElements 深拷贝元素状态
+func copyWuXingElements(elements map[WuXingElement]*WuXingElementState) map[WuXingElement]*WuXingElementState {
+	copied := make(map[WuXingElement]*WuXingElementState, len(elements))
+	for elem, state := range elements {
+		c := *state
+		c.Relations = make(map[WuXingElement]float64, len(state.Relations))
+		for k, v := range state.Relations {
+			c.Relations[k] = v
+		}
+		c.Properties = make(map[string]float64, len(state.Properties))
+		for k, v := range state.Properties {
+			c.Properties[k] = v
+		}
+		copied[elem] = &c
+	}
+	return copied
+}
+
 // generateTransform 相生转换
 func (f *WuXingFlow) generateTransform() error {
 	sequence := []WuXingElement{Wood, Fire, Earth, Metal, Water}
@@ -645,6 +716,79 @@ func (f *WuXingFlow) AdjustEnergy(delta float64) error {
 	return f.updateWuXingElementStates()
 }
 
+// ElementFlow 元素间能量流动
+// 相生时From的能量转入To; 相克时To按ConstraintRatio损失能量, From按ConstraintCost消耗能量
+type ElementFlow struct {
+	From   WuXingElement `json:"from"`
+	To     WuXingElement `json:"to"`
+	Amount float64       `json:"amount"`
+	Cycle  CycleType     `json:"cycle"`
+}
+
+// ElementEnergies 获取各元素能量
+func (f *WuXingFlow) ElementEnergies() map[WuXingElement]float64 {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	energies := make(map[WuXingElement]float64, len(f.state.WuXingElements))
+	for elem, state := range f.state.WuXingElements {
+		energies[elem] = state.Energy
+	}
+	return energies
+}
+
+// ApplyElementFlows 批量应用元素能量流动
+// 先计算全部流动后的能量, 任一元素能量为负或超出上限时不做任何修改
+func (f *WuXingFlow) ApplyElementFlows(flows []ElementFlow) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	next := make(map[WuXingElement]float64, len(f.state.WuXingElements))
+	for elem, state := range f.state.WuXingElements {
+		next[elem] = state.Energy
+	}
+
+	for _, flow := range flows {
+		if _, ok := next[flow.From]; !ok {
+			return NewModelError(ErrCodeInvalid, "invalid WuXingElement", nil)
+		}
+		if _, ok := next[flow.To]; !ok {
+			return NewModelError(ErrCodeInvalid, "invalid WuXingElement", nil)
+		}
+		if flow.Amount < 0 {
+			return NewModelError(ErrCodeRange, "negative element flow", nil)
+		}
+
+		switch flow.Cycle {
+		case GeneratingCycle:
+			next[flow.From] -= flow.Amount
+			next[flow.To] += flow.Amount
+		case ConstrainingCycle:
+			next[flow.To] -= flow.Amount * ConstraintRatio
+			next[flow.From] -= flow.Amount * ConstraintCost
+		default:
+			return NewModelError(ErrCodeInvalid, "unsupported element flow cycle", nil)
+		}
+	}
+
+	for _, energy := range next {
+		if energy < 0 || energy > MaxWuXingElementEnergy {
+			return NewModelError(ErrCodeRange, "WuXingElement energy out of range", nil)
+		}
+	}
+
+	for elem, energy := range next {
+		f.state.WuXingElements[elem].Energy = energy
+		if err := f.components.states[elem].SetEnergy(energy); err != nil {
+			return err
+		}
+	}
+	if len(flows) > 0 {
+		f.state.cycle = flows[len(flows)-1].Cycle
+	}
+	return f.updateWuXingElementStates()
+}
+
 // validateWuXingElement 验证元素状态
 func (f *WuXingFlow) validateWuXingElement(elem WuXingElement) error {
 	state, exists := f.state.WuXingElements[elem]
@@ -659,6 +803,19 @@ func (f *WuXingFlow) validateWuXingElement(elem WuXingElement) error {
 	return nil
 }
 
+// WuXingSequence 五行相生顺序: 木、火、土、金、水
+var WuXingSequence = []WuXingElement{Wood, Fire, Earth, Metal, Water}
+
+// Generates 相生目标: 木生火、火生土、土生金、金生水、水生木
+func (we WuXingElement) Generates() WuXingElement {
+	return WuXingSequence[(int(we)+1)%len(WuXingSequence)]
+}
+
+// Overcomes 相克目标: 木克土、火克金、土克水、金克木、水克火
+func (we WuXingElement) Overcomes() WuXingElement {
+	return WuXingSequence[(int(we)+2)%len(WuXingSequence)]
+}
+
 // WuXingElementFromString 从字符串转换为WuXingElement类型
 func WuXingElementFromString(s string) (WuXingElement, bool) {
 	switch s {
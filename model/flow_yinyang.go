@@ -203,6 +203,37 @@ func (f *YinYangFlow) Transform(pattern TransformPattern) error {
 	}
 }
 
+// PrepareTransform 准备阴阳转换, 回滚时同时恢复阴阳能量与极性
+func (f *YinYangFlow) PrepareTransform(pattern TransformPattern) (TransformTx, error) {
+	if !ValidateTransformPattern(pattern) {
+		return nil, NewModelError(ErrCodeInvalid, "invalid transform pattern", nil)
+	}
+	return f.prepareTransform(pattern), nil
+}
+
+// prepareTransform 保存阴阳状态快照
+func (f *YinYangFlow) prepareTransform(pattern TransformPattern) *transformTx {
+	base := copyModelState(f.BaseFlowModel.GetState())
+	f.mu.RLock()
+	saved := f.state
+	f.mu.RUnlock()
+
+	return &transformTx{
+		pattern: pattern,
+		apply:   f.Transform,
+		restore: func() error {
+			if err := f.BaseFlowModel.RestoreState(base); err != nil {
+				return err
+			}
+
+			f.mu.Lock()
+			defer f.mu.Unlock()
+			f.state = saved
+			return f.updateQuantumStates()
+		},
+	}
+}
+
 // balanceTransform 平衡转换
 func (f *YinYangFlow) balanceTransform() error {
 	// 计算总能量
@@ -375,6 +406,26 @@ func (f *YinYangFlow) AdjustEnergy(delta float64) error {
 	return nil
 }
 
+// RestoreState 恢复阴阳模型状态, 阴阳能量和平衡度一并恢复
+func (f *YinYangFlow) RestoreState(state ModelState) error {
+	if err := f.BaseFlowModel.RestoreState(state); err != nil {
+		return err
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.state.yinEnergy = state.YinEnergy
+	f.state.yangEnergy = state.YangEnergy
+	f.state.balance = state.Balance
+	// 极性大小由平衡度确定, 方向取能量较多的一方
+	f.state.polarity = 1 - state.Balance
+	if state.YinEnergy > state.YangEnergy {
+		f.state.polarity = -f.state.polarity
+	}
+	return f.updateQuantumStates()
+}
+
 // GetState 获取阴阳模型状态
 func (f *YinYangFlow) GetState() ModelState {
 	f.mu.RLock()
@@ -308,6 +308,9 @@ type FieldState struct {
 	Properties map[string]float64 // 属性集
 	Timestamp  time.Time          // 时间戳
 	Quantum    *core.QuantumState // 量子态
+
+	// 外部注入的能量分布
+	Distribution map[core.Point]float64
 }
 
 // Element 元素结构体定义
@@ -391,6 +394,11 @@ func (fs *FieldState) GetEnergyDistribution() map[core.Point]float64 {
 		}
 	}
 
+	// 叠加外部注入的能量
+	for point, energy := range fs.Distribution {
+		distribution[point] += energy
+	}
+
 	return distribution
 }
 
@@ -153,3 +153,20 @@ func (m *ModelMetrics) GetPreviousEnergy() float64 {
 	}
 	return m.Energy.Total - m.Energy.Variance
 }
+
+// Values 分析器计算的指标, 名称与DefaultMetricRegistry中登记的定义一致
+func (m *ModelMetrics) Values() map[string]float64 {
+	return map[string]float64{
+		"model.spans":                  float64(m.Basic.TotalSpans),
+		"model.error_rate":             m.Basic.ErrorRate,
+		"model.latency":                m.Basic.Latency,
+		"model.energy.total":           m.Energy.Total,
+		"model.energy.average":         m.Energy.Average,
+		"model.energy.variance":        m.Energy.Variance,
+		"model.state.transitions":      float64(m.State.Transitions),
+		"model.state.stability":        m.State.Stability,
+		"model.state.uptime":           m.State.Uptime,
+		"model.performance.throughput": m.Performance.Throughput,
+		"model.performance.qps":        m.Performance.QPS,
+	}
+}
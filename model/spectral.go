@@ -0,0 +1,379 @@
+// model/spectral.go
+
+package model
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"time"
+)
+
+// 谱分析方法
+const (
+	SpectralFFT         = "fft"          // 重采样到均匀网格后做快速傅里叶变换
+	SpectralLombScargle = "lomb_scargle" // 不规则采样的Lomb-Scargle周期图
+)
+
+// 谱分析参数
+const (
+	minSpectralPoints     = 4    // 谱分析所需的最少样本数
+	maxSpectralPeaks      = 5    // 保留的谱峰数
+	irregularSamplingCV   = 0.1  // 采样间隔变异系数超过该值视为不规则采样
+	lombScargleOversample = 4    // Lomb-Scargle频率网格过采样倍数
+	peakSignificance      = 4.0  // 谱峰功率至少为平均功率的倍数
+	harmonicTolerance     = 0.05 // 谐波频率比与整数的相对偏差容限
+)
+
+// SpectralPeak 谱峰
+type SpectralPeak struct {
+	Frequency float64 `json:"frequency"` // 频率(Hz)
+	Period    float64 `json:"period"`    // 周期(秒)
+	Power     float64 `json:"power"`     // 归一化功率, 占总功率的比例
+	Harmonic  int     `json:"harmonic"`  // 相对主频的谐波次数, 主频为1, 与主频无谐波关系时为0
+}
+
+// Spectrum 功率谱
+type Spectrum struct {
+	Method      string         `json:"method"`
+	Frequencies []float64      `json:"frequencies"`
+	Power       []float64      `json:"power"` // 与Frequencies一一对应, 总和为1
+	Peaks       []SpectralPeak `json:"peaks"` // 显著谱峰, 按功率降序
+}
+
+// Dominant 主频谱峰
+func (s Spectrum) Dominant() (SpectralPeak, bool) {
+	if len(s.Peaks) == 0 {
+		return SpectralPeak{}, false
+	}
+	return s.Peaks[0], true
+}
+
+// Harmonics 主频的高次谐波谱峰(不含主频), 按谐波次数排序
+func (s Spectrum) Harmonics() []SpectralPeak {
+	harmonics := make([]SpectralPeak, 0)
+	for _, p := range s.Peaks {
+		if p.Harmonic > 1 {
+			harmonics = append(harmonics, p)
+		}
+	}
+	sort.Slice(harmonics, func(i, j int) bool {
+		return harmonics[i].Harmonic < harmonics[j].Harmonic
+	})
+	return harmonics
+}
+
+// Periods 显著谱峰的周期(秒), 按功率降序
+func (s Spectrum) Periods() []float64 {
+	periods := make([]float64, 0, len(s.Peaks))
+	for _, p := range s.Peaks {
+		periods = append(periods, p.Period)
+	}
+	return periods
+}
+
+// SpectrumOf 计算时间序列点的功率谱
+func SpectrumOf(points []TimeSeriesPoint) Spectrum {
+	times := make([]time.Time, len(points))
+	values := make([]float64, len(points))
+	for i, p := range points {
+		times[i] = p.Timestamp
+		values[i] = p.Value
+	}
+	return AnalyzeSpectrum(times, values)
+}
+
+// AnalyzeSpectrum 计算序列的功率谱并提取谱峰及其谐波关系
+// 近似均匀采样时重采样后做FFT, 采样间隔不规则时使用Lomb-Scargle周期图;
+// 样本不足、时间跨度为零或序列为常数时返回空谱
+func AnalyzeSpectrum(times []time.Time, values []float64) Spectrum {
+	n := len(values)
+	if len(times) < n {
+		n = len(times)
+	}
+	if n < minSpectralPoints {
+		return Spectrum{}
+	}
+
+	// 按时间排序并换算为相对首个样本的秒数
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return times[order[i]].Before(times[order[j]])
+	})
+	t := make([]float64, n)
+	y := make([]float64, n)
+	start := times[order[0]]
+	for i, idx := range order {
+		t[i] = times[idx].Sub(start).Seconds()
+		y[i] = values[idx]
+	}
+	if t[n-1] <= 0 || variance(y) == 0 {
+		return Spectrum{}
+	}
+
+	var spectrum Spectrum
+	if samplingCV(t) > irregularSamplingCV {
+		freqs := lombScargleGrid(t)
+		spectrum = Spectrum{Method: SpectralLombScargle, Frequencies: freqs, Power: LombScargle(t, y, freqs)}
+	} else {
+		resampled, dt := Resample(t, y, n)
+		freqs, power := PowerSpectrum(resampled, dt)
+		spectrum = Spectrum{Method: SpectralFFT, Frequencies: freqs, Power: power}
+	}
+
+	normalizePower(spectrum.Power)
+	spectrum.Peaks = findSpectralPeaks(spectrum.Frequencies, spectrum.Power)
+	return spectrum
+}
+
+// Resample 将按时间t(秒, 升序)采样的序列线性插值到n个等间隔点, 返回重采样序列和采样间隔
+func Resample(t, y []float64, n int) ([]float64, float64) {
+	if n < 2 || len(t) < 2 || len(t) != len(y) {
+		return nil, 0
+	}
+	span := t[len(t)-1] - t[0]
+	dt := span / float64(n-1)
+	result := make([]float64, n)
+
+	j := 0
+	for i := 0; i < n; i++ {
+		target := t[0] + float64(i)*dt
+		for j < len(t)-2 && t[j+1] < target {
+			j++
+		}
+		width := t[j+1] - t[j]
+		if width <= 0 {
+			result[i] = y[j+1]
+			continue
+		}
+		ratio := math.Max(0, math.Min(1, (target-t[j])/width))
+		result[i] = y[j] + ratio*(y[j+1]-y[j])
+	}
+	return result, dt
+}
+
+// PowerSpectrum 计算均匀采样序列的单边功率谱(不含直流分量)
+// 序列去均值后补零到2的幂长度做FFT, 返回频率(Hz)和对应功率
+func PowerSpectrum(y []float64, dt float64) ([]float64, []float64) {
+	if len(y) < 2 || dt <= 0 {
+		return nil, nil
+	}
+	mean := 0.0
+	for _, v := range y {
+		mean += v
+	}
+	mean /= float64(len(y))
+
+	size := 1
+	for size < len(y) {
+		size <<= 1
+	}
+	x := make([]complex128, size)
+	for i, v := range y {
+		x[i] = complex(v-mean, 0)
+	}
+	spectrum := FFT(x)
+
+	half := size / 2
+	freqs := make([]float64, 0, half)
+	power := make([]float64, 0, half)
+	for k := 1; k <= half; k++ {
+		freqs = append(freqs, float64(k)/(float64(size)*dt))
+		a := cmplx.Abs(spectrum[k])
+		power = append(power, a*a)
+	}
+	return freqs, power
+}
+
+// FFT 基2快速傅里叶变换, 输入长度不是2的幂时补零
+func FFT(x []complex128) []complex128 {
+	size := 1
+	for size < len(x) {
+		size <<= 1
+	}
+	data := make([]complex128, size)
+	copy(data, x)
+	if size < 2 {
+		return data
+	}
+
+	// 位反转置换
+	for i, j := 1, 0; i < size; i++ {
+		bit := size >> 1
+		for ; j&bit != 0; bit >>= 1 {
+			j ^= bit
+		}
+		j ^= bit
+		if i < j {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+
+	// 蝶形运算
+	for length := 2; length <= size; length <<= 1 {
+		w := cmplx.Exp(complex(0, -2*math.Pi/float64(length)))
+		for i := 0; i < size; i += length {
+			wk := complex(1, 0)
+			for k := 0; k < length/2; k++ {
+				u := data[i+k]
+				v := data[i+k+length/2] * wk
+				data[i+k] = u + v
+				data[i+k+length/2] = u - v
+				wk *= w
+			}
+		}
+	}
+	return data
+}
+
+// LombScargle 计算不规则采样序列在给定频率(Hz)上的归一化Lomb-Scargle周期图
+func LombScargle(t, y, freqs []float64) []float64 {
+	power := make([]float64, len(freqs))
+	n := len(t)
+	if n < 2 || len(y) != n {
+		return power
+	}
+	mean := 0.0
+	for _, v := range y {
+		mean += v
+	}
+	mean /= float64(n)
+	v := variance(y)
+	if v == 0 {
+		return power
+	}
+
+	for fi, f := range freqs {
+		omega := 2 * math.Pi * f
+		if omega == 0 {
+			continue
+		}
+
+		// 时间偏移τ使正弦和余弦项正交
+		var s2, c2 float64
+		for _, ti := range t {
+			s2 += math.Sin(2 * omega * ti)
+			c2 += math.Cos(2 * omega * ti)
+		}
+		tau := math.Atan2(s2, c2) / (2 * omega)
+
+		var yc, ys, cc, ss float64
+		for i, ti := range t {
+			c := math.Cos(omega * (ti - tau))
+			s := math.Sin(omega * (ti - tau))
+			d := y[i] - mean
+			yc += d * c
+			ys += d * s
+			cc += c * c
+			ss += s * s
+		}
+		p := 0.0
+		if cc > 0 {
+			p += yc * yc / cc
+		}
+		if ss > 0 {
+			p += ys * ys / ss
+		}
+		power[fi] = p / (2 * v)
+	}
+	return power
+}
+
+// lombScargleGrid Lomb-Scargle频率网格, 从1/T到平均采样间隔对应的伪奈奎斯特频率
+func lombScargleGrid(t []float64) []float64 {
+	span := t[len(t)-1] - t[0]
+	fmin := 1 / span
+	fmax := float64(len(t)-1) / (2 * span)
+	step := fmin / lombScargleOversample
+
+	freqs := make([]float64, 0)
+	for f := fmin; f <= fmax+step/2; f += step {
+		freqs = append(freqs, f)
+	}
+	return freqs
+}
+
+// findSpectralPeaks 提取显著谱峰并标注与主频的谐波关系
+func findSpectralPeaks(freqs, power []float64) []SpectralPeak {
+	if len(power) == 0 {
+		return nil
+	}
+	threshold := peakSignificance / float64(len(power))
+
+	peaks := make([]SpectralPeak, 0)
+	for i, p := range power {
+		if p < threshold || freqs[i] <= 0 {
+			continue
+		}
+		if (i > 0 && power[i-1] >= p) || (i < len(power)-1 && power[i+1] > p) {
+			continue
+		}
+		peaks = append(peaks, SpectralPeak{Frequency: freqs[i], Period: 1 / freqs[i], Power: p})
+	}
+	sort.Slice(peaks, func(i, j int) bool {
+		return peaks[i].Power > peaks[j].Power
+	})
+	if len(peaks) > maxSpectralPeaks {
+		peaks = peaks[:maxSpectralPeaks]
+	}
+
+	if len(peaks) > 0 {
+		base := peaks[0].Frequency
+		for i := range peaks {
+			ratio := peaks[i].Frequency / base
+			order := math.Round(ratio)
+			if order >= 1 && math.Abs(ratio-order) <= harmonicTolerance*order {
+				peaks[i].Harmonic = int(order)
+			}
+		}
+	}
+	return peaks
+}
+
+// normalizePower 将功率归一化为总和为1
+func normalizePower(power []float64) {
+	total := 0.0
+	for _, p := range power {
+		total += p
+	}
+	if total <= 0 {
+		return
+	}
+	for i := range power {
+		power[i] /= total
+	}
+}
+
+// samplingCV 采样间隔的变异系数
+func samplingCV(t []float64) float64 {
+	intervals := make([]float64, 0, len(t)-1)
+	for i := 1; i < len(t); i++ {
+		intervals = append(intervals, t[i]-t[i-1])
+	}
+	mean := (t[len(t)-1] - t[0]) / float64(len(intervals))
+	if mean <= 0 {
+		return 0
+	}
+	return math.Sqrt(variance(intervals)) / mean
+}
+
+// variance 总体方差
+func variance(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	mean := 0.0
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return sum / float64(len(values))
+}
@@ -124,6 +124,22 @@ func (sm *StateManager) updateFromInternal() error {
 	return nil
 }
 
+// restoreModelState 恢复快照中的相位、属性、健康度和扩展属性
+func (sm *StateManager) restoreModelState(state ModelState) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	properties := make(map[string]interface{}, len(state.Properties))
+	for k, v := range state.Properties {
+		properties[k] = v
+	}
+	sm.modelState.Phase = state.Phase
+	sm.modelState.Nature = state.Nature
+	sm.modelState.Health = state.Health
+	sm.modelState.Properties = properties
+	sm.systemState.Phase = state.Phase
+}
+
 // calculateHarmony 计算和谐度
 func (sm *StateManager) calculateHarmony(fieldStrength float64) float64 {
 	// 和谐度与场强度和量子相干性相关
@@ -0,0 +1,144 @@
+// model/transaction.go
+
+package model
+
+import (
+	"sync"
+
+	"github.com/Corphon/daoflow/core"
+)
+
+// TransformTx 已准备的模型转换
+// Commit 执行转换, Rollback 撤销已提交或提交失败的转换, 未提交时为空操作
+type TransformTx interface {
+	Commit() error
+	Rollback() error
+}
+
+// TransactionalModel 支持两阶段转换的模型
+type TransactionalModel interface {
+	// PrepareTransform 检查转换能否执行并保存回滚所需状态, 不修改模型
+	PrepareTransform(pattern TransformPattern) (TransformTx, error)
+}
+
+// PrepareTransform 为模型准备转换
+// 模型实现了 TransactionalModel 时使用其自身协议, 否则以状态快照适配,
+// 回滚时恢复转换前的模型状态
+func PrepareTransform(m Model, pattern TransformPattern) (TransformTx, error) {
+	if !ValidateTransformPattern(pattern) {
+		return nil, NewModelError(ErrCodeInvalid, "invalid transform pattern", nil)
+	}
+	if tm, ok := m.(TransactionalModel); ok {
+		return tm.PrepareTransform(pattern)
+	}
+
+	tx, err := snapshotTransform(m, pattern)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// transformable 可转换并恢复能量的模型
+type transformable interface {
+	Transformable
+	EnergyBearing
+}
+
+// snapshotTransform 保存模型状态快照并准备转换
+// 模型实现了 StateRestorer 时回滚恢复完整模型状态, 否则只恢复能量
+func snapshotTransform(m transformable, pattern TransformPattern) (*transformTx, error) {
+	state := copyModelState(m.GetState())
+	if !ValidateEnergy(state.Energy) {
+		return nil, NewModelError(ErrCodeState, "model energy out of range", nil)
+	}
+
+	return &transformTx{
+		pattern: pattern,
+		apply:   m.Transform,
+		restore: func() error {
+			if r, ok := m.(StateRestorer); ok {
+				return r.RestoreState(state)
+			}
+			return m.SetEnergy(state.Energy)
+		},
+	}, nil
+}
+
+// copyModelState 复制模型状态, 扩展属性不与模型共享
+func copyModelState(state ModelState) ModelState {
+	if state.Properties != nil {
+		properties := make(map[string]interface{}, len(state.Properties))
+		for k, v := range state.Properties {
+			properties[k] = v
+		}
+		state.Properties = properties
+	}
+	return state
+}
+
+// quantumSnapshot 量子态的能量和相位快照
+type quantumSnapshot struct {
+	energy float64
+	phase  float64
+}
+
+// snapshotQuantum 保存量子态快照
+func snapshotQuantum(qs *core.QuantumState) quantumSnapshot {
+	return quantumSnapshot{energy: qs.GetEnergy(), phase: qs.GetPhase()}
+}
+
+// restore 恢复量子态的能量和相位
+func (s quantumSnapshot) restore(qs *core.QuantumState) error {
+	if err := qs.SetEnergy(s.energy); err != nil {
+		return err
+	}
+	return qs.SetPhase(s.phase)
+}
+
+// transformTx 基于快照的转换事务
+type transformTx struct {
+	mu sync.Mutex
+
+	pattern TransformPattern
+	apply   func(pattern TransformPattern) error
+	restore func() error
+
+	committed  bool // 已尝试提交, 失败的提交也可能留下部分修改
+	rolledBack bool
+}
+
+// Commit 执行转换, 只能提交一次
+func (tx *transformTx) Commit() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.committed || tx.rolledBack {
+		return NewModelError(ErrCodeOperation, "transform already finished", nil)
+	}
+	tx.committed = true
+
+	if err := tx.apply(tx.pattern); err != nil {
+		return WrapError(err, ErrCodeTransform, "transform commit failed")
+	}
+	return nil
+}
+
+// Rollback 恢复转换前状态
+func (tx *transformTx) Rollback() error {
+	tx.mu.Lock()
+	defer tx.mu.Unlock()
+
+	if tx.rolledBack {
+		return nil
+	}
+	tx.rolledBack = true
+	if !tx.committed {
+		return nil
+	}
+
+	if err := tx.restore(); err != nil {
+		return WrapError(err, ErrCodeTransform, "transform rollback failed")
+	}
+	return nil
+}
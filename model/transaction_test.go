@@ -0,0 +1,381 @@
+// model/transaction_test.go
+
+package model
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// wuxingSnapshot 五行模型可观察的内部状态
+type wuxingSnapshot struct {
+	base     ModelState
+	elements map[WuXingElement]*WuXingElementState
+	quanta   map[WuXingElement]quantumSnapshot
+	cycle    CycleType
+	strength float64
+}
+
+func captureWuXing(f *WuXingFlow) wuxingSnapshot {
+	base := copyModelState(f.BaseFlowModel.GetState())
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	s := wuxingSnapshot{
+		base:     base,
+		elements: copyWuXingElements(f.state.WuXingElements),
+		quanta:   make(map[WuXingElement]quantumSnapshot),
+		cycle:    f.state.cycle,
+		strength: f.state.strength,
+	}
+	for elem, qs := range f.components.states {
+		s.quanta[elem] = snapshotQuantum(qs)
+	}
+	return s
+}
+
+// baguaSnapshot 八卦模型可观察的内部状态
+type baguaSnapshot struct {
+	base      ModelState
+	trigrams  map[Trigram]*TrigramState
+	quanta    map[Trigram]quantumSnapshot
+	resonance float64
+	harmony   float64
+	changes   []Change
+}
+
+func captureBaGua(f *BaGuaFlow) baguaSnapshot {
+	base := copyModelState(f.BaseFlowModel.GetState())
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	s := baguaSnapshot{
+		base:      base,
+		trigrams:  copyTrigrams(f.state.trigrams),
+		quanta:    make(map[Trigram]quantumSnapshot),
+		resonance: f.state.resonance,
+		harmony:   f.state.harmony,
+		changes:   append([]Change(nil), f.state.changes...),
+	}
+	for tri, qs := range f.components.states {
+		s.quanta[tri] = snapshotQuantum(qs)
+	}
+	return s
+}
+
+// ganzhiSnapshot 干支模型可观察的内部状态
+type ganzhiSnapshot struct {
+	base     ModelState
+	stems    map[HeavenlyStem]*StemState
+	branches map[EarthlyBranch]*BranchState
+	quanta   []quantumSnapshot
+	cycle    int
+	harmony  float64
+}
+
+func captureGanZhi(f *GanZhiFlow) ganzhiSnapshot {
+	base := copyModelState(f.BaseFlowModel.GetState())
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+	s := ganzhiSnapshot{
+		base:     base,
+		stems:    copyStems(f.state.stems),
+		branches: copyBranches(f.state.branches),
+		cycle:    f.state.cycle,
+		harmony:  f.state.harmony,
+	}
+	for stem := HeavenlyStem(0); int(stem) < len(f.components.stemStates); stem++ {
+		s.quanta = append(s.quanta, snapshotQuantum(f.components.stemStates[stem]))
+	}
+	for branch := EarthlyBranch(0); int(branch) < len(f.components.branchStates); branch++ {
+		s.quanta = append(s.quanta, snapshotQuantum(f.components.branchStates[branch]))
+	}
+	return s
+}
+
+// 基础状态中随时间变化的字段不参与比较
+func comparableState(state ModelState) ModelState {
+	state.UpdateTime = ModelState{}.UpdateTime
+	return state
+}
+
+// setBaseEnergy 设置基础模型能量, 基础模型能量为零时常规转换不可执行
+func setBaseEnergy(t *testing.T, b *BaseFlowModel, energy float64) {
+	t.Helper()
+	state := b.GetState()
+	state.Energy = energy
+	if err := b.RestoreState(state); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// skewWuXing 使五行元素能量不均, 平衡转换会改变每个元素
+func skewWuXing(t *testing.T, f *WuXingFlow) {
+	t.Helper()
+	setBaseEnergy(t, f.BaseFlowModel, 20)
+	if err := f.ApplyElementFlows([]ElementFlow{
+		{From: Wood, To: Fire, Amount: 1, Cycle: GeneratingCycle},
+		{From: Metal, To: Water, Amount: 0.5, Cycle: GeneratingCycle},
+	}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// skewBaGua 使卦象能量不均
+func skewBaGua(t *testing.T, f *BaGuaFlow) {
+	t.Helper()
+	setBaseEnergy(t, f.BaseFlowModel, 20)
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for tri, state := range f.state.trigrams {
+		state.Energy = float64(tri+1) * 1.5
+		state.Relations[(tri+1)%8] = float64(tri) * 0.1
+		if err := f.components.states[tri].SetEnergy(state.Energy); err != nil {
+			t.Fatal(err)
+		}
+	}
+	f.state.changes = append(f.state.changes, Change{From: Qian, To: Kun, Type: NaturalChange})
+}
+
+func assertWuXingRestored(t *testing.T, before, after wuxingSnapshot) {
+	t.Helper()
+	if !reflect.DeepEqual(comparableState(before.base), comparableState(after.base)) {
+		t.Errorf("wuxing base state not restored:\nbefore %+v\nafter  %+v", before.base, after.base)
+	}
+	if !reflect.DeepEqual(before.elements, after.elements) {
+		t.Errorf("wuxing elements not restored")
+	}
+	if !reflect.DeepEqual(before.quanta, after.quanta) {
+		t.Errorf("wuxing quantum states not restored:\nbefore %v\nafter  %v", before.quanta, after.quanta)
+	}
+	if before.cycle != after.cycle || before.strength != after.strength {
+		t.Errorf("wuxing cycle/strength not restored: %v/%v -> %v/%v", before.cycle, before.strength, after.cycle, after.strength)
+	}
+}
+
+func assertBaGuaRestored(t *testing.T, before, after baguaSnapshot) {
+	t.Helper()
+	if !reflect.DeepEqual(comparableState(before.base), comparableState(after.base)) {
+		t.Errorf("bagua base state not restored:\nbefore %+v\nafter  %+v", before.base, after.base)
+	}
+	if !reflect.DeepEqual(before.trigrams, after.trigrams) {
+		t.Errorf("bagua trigrams not restored")
+	}
+	if !reflect.DeepEqual(before.quanta, after.quanta) {
+		t.Errorf("bagua quantum states not restored")
+	}
+	if before.resonance != after.resonance || before.harmony != after.harmony {
+		t.Errorf("bagua resonance/harmony not restored")
+	}
+	if !reflect.DeepEqual(before.changes, after.changes) {
+		t.Errorf("bagua changes not restored: %d -> %d", len(before.changes), len(after.changes))
+	}
+}
+
+func assertGanZhiRestored(t *testing.T, before, after ganzhiSnapshot) {
+	t.Helper()
+	if !reflect.DeepEqual(comparableState(before.base), comparableState(after.base)) {
+		t.Errorf("ganzhi base state not restored:\nbefore %+v\nafter  %+v", before.base, after.base)
+	}
+	if !reflect.DeepEqual(before.stems, after.stems) || !reflect.DeepEqual(before.branches, after.branches) {
+		t.Errorf("ganzhi stems/branches not restored")
+	}
+	if !reflect.DeepEqual(before.quanta, after.quanta) {
+		t.Errorf("ganzhi quantum states not restored")
+	}
+	if before.cycle != after.cycle || before.harmony != after.harmony {
+		t.Errorf("ganzhi cycle/harmony not restored")
+	}
+}
+
+// failAfterApply 模拟转换修改模型后失败的提交
+func failAfterApply(tx *transformTx) {
+	apply := tx.apply
+	tx.apply = func(pattern TransformPattern) error {
+		if err := apply(pattern); err != nil {
+			return err
+		}
+		return errors.New("injected failure")
+	}
+}
+
+// failAfterMutate 以给定修改代替转换, 修改后提交失败
+func failAfterMutate(tx *transformTx, mutate func() error) {
+	tx.apply = func(TransformPattern) error {
+		if err := mutate(); err != nil {
+			return err
+		}
+		return errors.New("injected failure")
+	}
+}
+
+// shiftBaGua 修改卦象能量、量子态和变化记录
+func shiftBaGua(f *BaGuaFlow) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for tri, state := range f.state.trigrams {
+		state.Energy = float64(8-tri) * 1.2
+		state.Relations[(tri+2)%8] = 0.9
+		if err := f.components.states[tri].SetEnergy(state.Energy); err != nil {
+			return err
+		}
+	}
+	f.state.harmony = 0.5
+	f.state.changes = append(f.state.changes, Change{From: Kan, To: Li, Type: NaturalChange})
+	return nil
+}
+
+func TestWuXingRollbackAfterFailedCommit(t *testing.T) {
+	f := NewWuXingFlow()
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+	skewWuXing(t, f)
+	before := captureWuXing(f)
+
+	tx, err := f.prepareTransform(PatternNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failAfterApply(tx)
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected commit failure")
+	}
+	if reflect.DeepEqual(before.elements, captureWuXing(f).elements) {
+		t.Fatal("transform did not modify elements, test is vacuous")
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	assertWuXingRestored(t, before, captureWuXing(f))
+}
+
+func TestBaGuaRollbackAfterFailedCommit(t *testing.T) {
+	f := NewBaGuaFlow()
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+	skewBaGua(t, f)
+	before := captureBaGua(f)
+
+	tx, err := f.prepareTransform(PatternNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failAfterMutate(tx, func() error { return shiftBaGua(f) })
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected commit failure")
+	}
+	if reflect.DeepEqual(before.trigrams, captureBaGua(f).trigrams) {
+		t.Fatal("transform did not modify trigrams, test is vacuous")
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	assertBaGuaRestored(t, before, captureBaGua(f))
+}
+
+func TestGanZhiRollbackAfterFailedCommit(t *testing.T) {
+	f := NewGanZhiFlow()
+	if err := f.Start(); err != nil {
+		t.Fatal(err)
+	}
+	setBaseEnergy(t, f.BaseFlowModel, 20)
+	before := captureGanZhi(f)
+
+	tx, err := f.prepareTransform(PatternForward)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failAfterApply(tx)
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected commit failure")
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	assertGanZhiRestored(t, before, captureGanZhi(f))
+}
+
+// 集成转换中途失败: 阴阳、五行和八卦已转换, 干支转换失败
+func TestIntegrateRollbackAfterPartialFailure(t *testing.T) {
+	im := NewIntegrateFlow()
+	if err := im.Start(); err != nil {
+		t.Fatal(err)
+	}
+	skewWuXing(t, im.wuxing)
+	skewBaGua(t, im.bagua)
+	if err := im.ganzhi.Stop(); err != nil {
+		t.Fatal(err)
+	}
+
+	yinyang := copyModelState(im.yinyang.GetState())
+	wuxing := captureWuXing(im.wuxing)
+	bagua := captureBaGua(im.bagua)
+	ganzhi := captureGanZhi(im.ganzhi)
+
+	prepared, err := im.PrepareTransform(PatternNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := prepared.(*transformTx)
+	tx.apply = func(pattern TransformPattern) error {
+		if err := im.yinyang.SetEnergy(yinyang.Energy + 10); err != nil {
+			return err
+		}
+		if err := im.wuxing.ApplyElementFlows([]ElementFlow{
+			{From: Water, To: Wood, Amount: 1, Cycle: GeneratingCycle},
+		}); err != nil {
+			return err
+		}
+		if err := shiftBaGua(im.bagua); err != nil {
+			return err
+		}
+		return im.ganzhi.Transform(pattern)
+	}
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected commit failure from stopped ganzhi model")
+	}
+	if reflect.DeepEqual(wuxing.elements, captureWuXing(im.wuxing).elements) {
+		t.Fatal("wuxing was not modified before the failure, test is vacuous")
+	}
+
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got := im.yinyang.GetState(); !reflect.DeepEqual(comparableState(yinyang), comparableState(got)) {
+		t.Errorf("yinyang state not restored:\nbefore %+v\nafter  %+v", yinyang, got)
+	}
+	assertWuXingRestored(t, wuxing, captureWuXing(im.wuxing))
+	assertBaGuaRestored(t, bagua, captureBaGua(im.bagua))
+	assertGanZhiRestored(t, ganzhi, captureGanZhi(im.ganzhi))
+}
+
+// 未实现事务协议的模型回滚时恢复完整状态
+func TestSnapshotTransformRestoresFullState(t *testing.T) {
+	b := NewBaseFlowModel(ModelYinYang, 100)
+	if err := b.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.SetEnergy(40); err != nil {
+		t.Fatal(err)
+	}
+	before := copyModelState(b.GetState())
+
+	tx, err := snapshotTransform(b, PatternNormal)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failAfterApply(tx)
+	if err := tx.Commit(); err == nil {
+		t.Fatal("expected commit failure")
+	}
+	if err := tx.Rollback(); err != nil {
+		t.Fatal(err)
+	}
+	if got := b.GetState(); !reflect.DeepEqual(comparableState(before), comparableState(got)) {
+		t.Errorf("state not restored:\nbefore %+v\nafter  %+v", before, got)
+	}
+}
@@ -0,0 +1,234 @@
+// model/units.go
+
+package model
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+)
+
+// Unit 指标单位
+type Unit string
+
+const (
+	UnitNone         Unit = ""             // 无量纲
+	UnitRatio        Unit = "ratio"        // 比例(0-1)
+	UnitPercent      Unit = "percent"      // 百分比(0-100)
+	UnitCount        Unit = "count"        // 计数
+	UnitSeconds      Unit = "seconds"      // 秒
+	UnitMilliseconds Unit = "milliseconds" // 毫秒
+	UnitMicroseconds Unit = "microseconds" // 微秒
+	UnitBytes        Unit = "bytes"        // 字节
+	UnitKilobytes    Unit = "kilobytes"    // KB(1024字节)
+	UnitMegabytes    Unit = "megabytes"    // MB(1024*1024字节)
+	UnitPerSecond    Unit = "per_second"   // 每秒
+	UnitEnergy       Unit = "energy"       // 场能量单位
+	UnitRadians      Unit = "radians"      // 弧度
+)
+
+// Dimension 单位的量纲, 同量纲的单位可互相换算
+type Dimension string
+
+const (
+	DimensionNone   Dimension = "dimensionless"
+	DimensionRatio  Dimension = "ratio"
+	DimensionCount  Dimension = "count"
+	DimensionTime   Dimension = "time"
+	DimensionData   Dimension = "data"
+	DimensionRate   Dimension = "rate"
+	DimensionEnergy Dimension = "energy"
+	DimensionAngle  Dimension = "angle"
+)
+
+// unitInfo 单位的量纲和到基本单位的换算系数
+type unitInfo struct {
+	dimension Dimension
+	factor    float64 // 值 * factor = 基本单位的值
+	base      Unit
+}
+
+var units = map[Unit]unitInfo{
+	UnitNone:         {DimensionNone, 1, UnitNone},
+	UnitRatio:        {DimensionRatio, 1, UnitRatio},
+	UnitPercent:      {DimensionRatio, 0.01, UnitRatio},
+	UnitCount:        {DimensionCount, 1, UnitCount},
+	UnitSeconds:      {DimensionTime, 1, UnitSeconds},
+	UnitMilliseconds: {DimensionTime, 1e-3, UnitSeconds},
+	UnitMicroseconds: {DimensionTime, 1e-6, UnitSeconds},
+	UnitBytes:        {DimensionData, 1, UnitBytes},
+	UnitKilobytes:    {DimensionData, 1 << 10, UnitBytes},
+	UnitMegabytes:    {DimensionData, 1 << 20, UnitBytes},
+	UnitPerSecond:    {DimensionRate, 1, UnitPerSecond},
+	UnitEnergy:       {DimensionEnergy, 1, UnitEnergy},
+	UnitRadians:      {DimensionAngle, 1, UnitRadians},
+}
+
+// Dimension 单位的量纲, 未知单位返回空
+func (u Unit) Dimension() Dimension {
+	return units[u].dimension
+}
+
+// Base 同量纲的基本单位
+func (u Unit) Base() Unit {
+	if info, ok := units[u]; ok {
+		return info.base
+	}
+	return u
+}
+
+// Valid 是否为已知单位
+func (u Unit) Valid() bool {
+	_, ok := units[u]
+	return ok
+}
+
+// ConvertUnit 在同量纲的单位间换算
+func ConvertUnit(v float64, from, to Unit) (float64, error) {
+	src, ok := units[from]
+	if !ok {
+		return 0, NewModelError(ErrCodeValidation, fmt.Sprintf("unknown unit: %q", from), nil)
+	}
+	dst, ok := units[to]
+	if !ok {
+		return 0, NewModelError(ErrCodeValidation, fmt.Sprintf("unknown unit: %q", to), nil)
+	}
+	if src.dimension != dst.dimension {
+		return 0, NewModelError(ErrCodeValidation,
+			fmt.Sprintf("cannot convert %s (%s) to %s (%s)", from, src.dimension, to, dst.dimension), nil)
+	}
+	return v * src.factor / dst.factor, nil
+}
+
+// ToBaseUnit 换算为同量纲的基本单位(秒、字节、比例等)
+func ToBaseUnit(v float64, u Unit) (float64, Unit) {
+	info, ok := units[u]
+	if !ok {
+		return v, u
+	}
+	return v * info.factor, info.base
+}
+
+// MetricKind 指标类型
+type MetricKind string
+
+const (
+	MetricGauge   MetricKind = "gauge"   // 可增可减的瞬时值
+	MetricCounter MetricKind = "counter" // 单调递增的累计值
+)
+
+// MetricDesc 指标定义
+type MetricDesc struct {
+	Name        string     `json:"name"`
+	Unit        Unit       `json:"unit"`
+	Kind        MetricKind `json:"kind"`
+	Description string     `json:"description"`
+	Bounded     bool       `json:"bounded"` // 是否有取值范围
+	Min         float64    `json:"min"`     // 以Unit表示的取值范围
+	Max         float64    `json:"max"`
+}
+
+// InRange 值是否在定义的取值范围内, 无范围时只要求不是NaN
+func (d MetricDesc) InRange(v float64) bool {
+	if math.IsNaN(v) {
+		return false
+	}
+	return !d.Bounded || (v >= d.Min && v <= d.Max)
+}
+
+// validate 验证指标定义
+func (d MetricDesc) validate() error {
+	if d.Name == "" {
+		return NewModelError(ErrCodeValidation, "metric name is required", nil)
+	}
+	if !d.Unit.Valid() {
+		return NewModelError(ErrCodeValidation, fmt.Sprintf("metric %s: unknown unit %q", d.Name, d.Unit), nil)
+	}
+	if d.Kind != MetricGauge && d.Kind != MetricCounter {
+		return NewModelError(ErrCodeValidation, fmt.Sprintf("metric %s: unknown kind %q", d.Name, d.Kind), nil)
+	}
+	if d.Bounded && !(d.Min <= d.Max) {
+		return NewModelError(ErrCodeValidation, fmt.Sprintf("metric %s: invalid range", d.Name), nil)
+	}
+	return nil
+}
+
+// MetricRegistry 指标定义注册表
+type MetricRegistry struct {
+	mu      sync.RWMutex
+	metrics map[string]MetricDesc
+}
+
+// NewMetricRegistry 创建指标注册表
+func NewMetricRegistry() *MetricRegistry {
+	return &MetricRegistry{metrics: make(map[string]MetricDesc)}
+}
+
+// defaultMetrics 进程内共享的指标注册表
+var defaultMetrics = NewMetricRegistry()
+
+// DefaultMetricRegistry 进程内共享的指标注册表, 各模块在此登记自己产生的指标
+func DefaultMetricRegistry() *MetricRegistry {
+	return defaultMetrics
+}
+
+// Register 登记指标定义; Kind为空时为gauge; 同名指标的定义不一致时返回错误
+func (r *MetricRegistry) Register(descs ...MetricDesc) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, d := range descs {
+		if d.Kind == "" {
+			d.Kind = MetricGauge
+		}
+		if err := d.validate(); err != nil {
+			return err
+		}
+		if existing, ok := r.metrics[d.Name]; ok && existing != d {
+			return NewModelError(ErrCodeValidation, fmt.Sprintf("metric %s already registered with a different definition", d.Name), nil)
+		}
+		r.metrics[d.Name] = d
+	}
+	return nil
+}
+
+// Describe 获取指标定义
+func (r *MetricRegistry) Describe(name string) (MetricDesc, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	d, ok := r.metrics[name]
+	return d, ok
+}
+
+// List 按名称排序的全部指标定义
+func (r *MetricRegistry) List() []MetricDesc {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	descs := make([]MetricDesc, 0, len(r.metrics))
+	for _, d := range r.metrics {
+		descs = append(descs, d)
+	}
+	sort.Slice(descs, func(i, j int) bool { return descs[i].Name < descs[j].Name })
+	return descs
+}
+
+// Convert 将指标值换算为目标单位, 未登记的指标返回错误
+func (r *MetricRegistry) Convert(name string, v float64, to Unit) (float64, error) {
+	d, ok := r.Describe(name)
+	if !ok {
+		return 0, NewModelError(ErrCodeNotFound, fmt.Sprintf("metric %s not registered", name), nil)
+	}
+	return ConvertUnit(v, d.Unit, to)
+}
+
+// Normalize 将指标值换算为基本单位, 返回值、单位和定义; 未登记的指标原样返回且ok为false
+func (r *MetricRegistry) Normalize(name string, v float64) (float64, Unit, MetricDesc, bool) {
+	d, ok := r.Describe(name)
+	if !ok {
+		return v, UnitNone, MetricDesc{Name: name, Kind: MetricGauge}, false
+	}
+	base, unit := ToBaseUnit(v, d.Unit)
+	return base, unit, d, true
+}
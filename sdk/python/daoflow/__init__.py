@@ -0,0 +1,6 @@
+"""Client for the DaoFlow bulk export endpoints."""
+
+from .client import Client, ExportError
+
+__all__ = ["Client", "ExportError"]
+__version__ = "0.1.0"
@@ -0,0 +1,90 @@
+"""HTTP client for /api/export/patterns and /api/export/evolution."""
+
+import json
+import urllib.error
+import urllib.parse
+import urllib.request
+from datetime import datetime, timezone
+
+NEXT_CURSOR_HEADER = "X-Next-Cursor"
+DEFAULT_PAGE_SIZE = 1000
+
+
+class ExportError(Exception):
+    """Raised when the server rejects an export request."""
+
+    def __init__(self, status, message):
+        super().__init__("export failed ({}): {}".format(status, message))
+        self.status = status
+        self.message = message
+
+
+class Client:
+    """Pages through pattern and evolution exports of a DaoFlow dashboard."""
+
+    def __init__(self, base_url, timeout=30.0, headers=None):
+        self.base_url = base_url.rstrip("/")
+        self.timeout = timeout
+        self.headers = dict(headers or {})
+
+    def patterns(self, since=None, types=None, page_size=DEFAULT_PAGE_SIZE):
+        """Yield active patterns, ordered by namespace and id."""
+        return self._iterate("patterns", since, types, page_size)
+
+    def evolution(self, since=None, types=None, page_size=DEFAULT_PAGE_SIZE):
+        """Yield evolution states, ordered by namespace, pattern id and time."""
+        return self._iterate("evolution", since, types, page_size)
+
+    def patterns_frame(self, **kwargs):
+        """Return patterns as a pandas DataFrame."""
+        return _frame(self.patterns(**kwargs), ["formation", "last_update"])
+
+    def evolution_frame(self, **kwargs):
+        """Return evolution states as a pandas DataFrame."""
+        return _frame(self.evolution(**kwargs), ["timestamp"])
+
+    def page(self, kind, since=None, types=None, cursor="", limit=DEFAULT_PAGE_SIZE):
+        """Fetch a single page; returns (records, next_cursor)."""
+        params = [("limit", str(limit))]
+        if since is not None:
+            params.append(("since", _format_time(since)))
+        for t in types or ():
+            params.append(("type", t))
+        if cursor:
+            params.append(("cursor", cursor))
+
+        url = "{}/api/export/{}?{}".format(self.base_url, kind, urllib.parse.urlencode(params))
+        request = urllib.request.Request(url, headers=self.headers)
+        try:
+            with urllib.request.urlopen(request, timeout=self.timeout) as response:
+                next_cursor = response.headers.get(NEXT_CURSOR_HEADER, "")
+                records = [json.loads(line) for line in response if line.strip()]
+        except urllib.error.HTTPError as err:
+            raise ExportError(err.code, err.read().decode("utf-8", "replace").strip()) from None
+        return records, next_cursor
+
+    def _iterate(self, kind, since, types, page_size):
+        cursor = ""
+        while True:
+            records, cursor = self.page(kind, since, types, cursor, page_size)
+            yield from records
+            if not cursor:
+                return
+
+
+def _format_time(value):
+    if isinstance(value, datetime):
+        if value.tzinfo is None:
+            value = value.replace(tzinfo=timezone.utc)
+        return value.isoformat()
+    return str(value)
+
+
+def _frame(records, time_columns):
+    import pandas as pd
+
+    df = pd.DataFrame.from_records(list(records))
+    for column in time_columns:
+        if column in df:
+            df[column] = pd.to_datetime(df[column], utc=True)
+    return df
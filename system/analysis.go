@@ -0,0 +1,75 @@
+// system/analysis.go
+
+package system
+
+import (
+	"io"
+
+	"github.com/Corphon/daoflow/system/monitor/trace"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// OnAnalysis 订阅追踪分析结果, 返回订阅ID
+// 订阅绑定当前的追踪分析器, 监控子系统恢复重建分析器后需重新订阅
+func (s *System) OnAnalysis(handler func(*trace.TraceAnalysis), filter ...trace.AnalysisFilter) (string, error) {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return "", types.NewSystemError(types.ErrState, "trace analyzer not available", nil)
+	}
+	return analyzer.OnAnalysis(handler, filter...)
+}
+
+// RemoveAnalysisHandler 取消追踪分析结果订阅
+func (s *System) RemoveAnalysisHandler(id string) bool {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return false
+	}
+	return analyzer.RemoveAnalysisHandler(id)
+}
+
+// RegisterAnalysisStage 注册自定义追踪分析阶段
+// 阶段绑定当前的追踪分析器, 监控子系统恢复重建分析器后需重新注册
+func (s *System) RegisterAnalysisStage(name string, stage trace.Stage, options trace.StageOptions) error {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return types.NewSystemError(types.ErrState, "trace analyzer not available", nil)
+	}
+	return analyzer.RegisterStage(name, stage, options)
+}
+
+// UnregisterAnalysisStage 注销自定义追踪分析阶段
+func (s *System) UnregisterAnalysisStage(name string) error {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return types.NewSystemError(types.ErrState, "trace analyzer not available", nil)
+	}
+	return analyzer.UnregisterStage(name)
+}
+
+// TraceRollups 查询追踪分析结果的时间桶汇总
+func (s *System) TraceRollups(q trace.RollupQuery) []trace.Rollup {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return nil
+	}
+	return analyzer.Rollups(q)
+}
+
+// TraceLatency 查询时间范围内追踪耗时的分位数, 由各时间桶的草图合并得到
+func (s *System) TraceLatency(q trace.RollupQuery) types.LatencyQuantiles {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return types.LatencyQuantiles{}
+	}
+	return analyzer.LatencyQuantiles(q)
+}
+
+// ExportTraceRollups 以JSON或CSV导出追踪分析结果的时间桶汇总
+func (s *System) ExportTraceRollups(w io.Writer, format string, q trace.RollupQuery) error {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return types.NewSystemError(types.ErrState, "trace analyzer not available", nil)
+	}
+	return analyzer.ExportRollups(w, format, q)
+}
@@ -0,0 +1,122 @@
+// system/annotations.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/evolution/pattern"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// TagPattern 合并命名空间ns中活跃模式的标签, 值为空的标签表示删除该键
+func (s *System) TagPattern(ns types.Namespace, id string, tags map[string]string) error {
+	detector, err := s.namespaceDetector(ns)
+	if err != nil {
+		return err
+	}
+	return detector.Tag(id, tags)
+}
+
+// AddPatternNote 为命名空间ns中的活跃模式追加自由文本标注
+func (s *System) AddPatternNote(ns types.Namespace, id string, note types.Note) error {
+	detector, err := s.namespaceDetector(ns)
+	if err != nil {
+		return err
+	}
+	return detector.AddNote(id, note)
+}
+
+// FindPatterns 全局和各命名空间中满足全部标签条件的活跃模式
+func (s *System) FindPatterns(tags map[string]string) []emergence.EmergentPattern {
+	patterns := make([]emergence.EmergentPattern, 0)
+	for _, detector := range s.patternDetectors() {
+		patterns = append(patterns, detector.FindPatterns(tags)...)
+	}
+	return patterns
+}
+
+// TagRecognizedPattern 合并已识别模式的标签
+func (s *System) TagRecognizedPattern(id string, tags map[string]string) error {
+	recognizer, err := s.recognizer()
+	if err != nil {
+		return err
+	}
+	return recognizer.Tag(id, tags)
+}
+
+// AddRecognizedPatternNote 为已识别模式追加自由文本标注
+func (s *System) AddRecognizedPatternNote(id string, note types.Note) error {
+	recognizer, err := s.recognizer()
+	if err != nil {
+		return err
+	}
+	return recognizer.AddNote(id, note)
+}
+
+// FindRecognizedPatterns 满足全部标签条件的已识别模式, 识别器未启动时为空
+func (s *System) FindRecognizedPatterns(tags map[string]string) []*pattern.RecognizedPattern {
+	recognizer, err := s.recognizer()
+	if err != nil {
+		return nil
+	}
+	return recognizer.FindPatterns(tags)
+}
+
+// recognizer 演化子系统的模式识别器
+func (s *System) recognizer() (*pattern.PatternRecognizer, error) {
+	recognizer := s.evolution.GetRecognizer()
+	if recognizer == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "pattern recognizer not available", nil)
+	}
+	return recognizer, nil
+}
+
+// TagEvent 合并事件历史中指定事件的标签
+func (s *System) TagEvent(id string, tags map[string]string) error {
+	return s.annotateEvent(id, func(a types.Annotations) types.Annotations {
+		return a.WithTags(tags)
+	})
+}
+
+// AddEventNote 为事件历史中的指定事件追加自由文本标注
+func (s *System) AddEventNote(id string, note types.Note) error {
+	if note.Text == "" {
+		return types.NewSystemError(types.ErrInvalid, "empty note", nil).
+			WithContext("event", id)
+	}
+	return s.annotateEvent(id, func(a types.Annotations) types.Annotations {
+		return a.WithNote(note)
+	})
+}
+
+// annotateEvent 更新事件历史中指定事件的标注
+func (s *System) annotateEvent(id string, update func(types.Annotations) types.Annotations) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i := range s.state.events {
+		if s.state.events[i].ID == id {
+			s.state.events[i].Annotations = update(s.state.events[i].Annotations)
+			return nil
+		}
+	}
+	return types.NewSystemError(types.ErrNotFound, "event not found", nil).
+		WithContext("event", id)
+}
+
+// QueryEvents 按类型、来源、时间和标签查询事件历史, 按时间顺序返回
+func (s *System) QueryEvents(q types.EventQuery) []types.SystemEvent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	events := make([]types.SystemEvent, 0)
+	for _, event := range s.state.events {
+		if q.Matches(event) {
+			events = append(events, event)
+		}
+	}
+	if q.Limit > 0 && len(events) > q.Limit {
+		events = events[len(events)-q.Limit:]
+	}
+	return events
+}
@@ -0,0 +1,24 @@
+// system/audit.go
+
+package system
+
+import (
+	"io"
+
+	"github.com/Corphon/daoflow/system/evolution/audit"
+)
+
+// AuditRecords 查询策略参数和规则变更的审计记录, 按时间顺序返回
+func (s *System) AuditRecords(q audit.Query) []audit.Record {
+	return s.evolution.GetAudit().Query(q)
+}
+
+// RecordAudit 追加一条审计记录, 如远程控制接口的特权调用
+func (s *System) RecordAudit(r audit.Record) audit.Record {
+	return s.evolution.GetAudit().Append(r)
+}
+
+// ExportAudit 以JSON数组导出满足条件的审计记录
+func (s *System) ExportAudit(w io.Writer, q audit.Query) error {
+	return s.evolution.GetAudit().Export(w, q)
+}
@@ -0,0 +1,63 @@
+// system/balance.go
+
+package system
+
+import (
+	"errors"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/control/balance"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// startBalanceControl 为阴阳平衡控制器接入读数来源、执行器和审计事件
+// 读数取自阴阳模型能量和统一场和谐度, 修正以两阶段转换作用于阴阳模型
+func (s *System) startBalanceControl() {
+	controller := s.control.GetBalanceController()
+	if controller == nil || s.modelManager == nil {
+		return
+	}
+
+	controller.SetSource(func() (balance.Reading, error) {
+		yinyang := s.modelManager.GetYinYangFlow()
+		if yinyang == nil {
+			return balance.Reading{}, types.ErrModelNotFound
+		}
+		energy := yinyang.GetYinYangEnergy()
+		return balance.Reading{
+			Yin:     energy.YinEnergy,
+			Yang:    energy.YangEnergy,
+			Harmony: s.modelManager.GetSystemState().Harmony,
+		}, nil
+	})
+
+	controller.SetActuator(func(pattern model.TransformPattern) error {
+		yinyang := s.modelManager.GetYinYangFlow()
+		if yinyang == nil {
+			return types.ErrModelNotFound
+		}
+		tx, err := yinyang.PrepareTransform(pattern)
+		if err != nil {
+			return err
+		}
+		if err := tx.Commit(); err != nil {
+			return errors.Join(err, tx.Rollback())
+		}
+		return nil
+	})
+
+	controller.OnAction(func(action balance.Action) {
+		priority := types.PriorityNormal
+		if action.Error != "" {
+			priority = types.PriorityHigh
+		}
+		s.HandleEvent(types.SystemEvent{
+			Type:      types.EventBalanceAction,
+			Source:    "control.balance",
+			Timestamp: action.Timestamp,
+			Message:   action.Reason,
+			Priority:  priority,
+			Data:      action,
+		})
+	})
+}
@@ -0,0 +1,72 @@
+// system/capability.go
+
+package system
+
+import (
+	"sort"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// ModelCapabilities 获取已注册模型支持的能力
+func (s *System) ModelCapabilities(name string) ([]model.Capability, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	m, exists := s.models[name]
+	if !exists {
+		return nil, types.ErrModelNotFound
+	}
+	return model.CapabilitiesOf(m), nil
+}
+
+// FindModels 查找支持指定能力的模型名称, 按名称排序
+func (s *System) FindModels(capability model.Capability) []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	names := make([]string, 0)
+	for name, m := range s.models {
+		if model.HasCapability(m, capability) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetModelsWithCapability 获取实现了接口T的所有已注册模型
+//
+//	for name, m := range system.GetModelsWithCapability[model.EnergyBearing](s) {
+//	    m.AdjustEnergy(delta)
+//	}
+func GetModelsWithCapability[T any](s *System) map[string]T {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	models := make(map[string]T)
+	for name, m := range s.models {
+		if typed, ok := m.(T); ok {
+			models[name] = typed
+		}
+	}
+	return models
+}
+
+// GetModelAs 按名称获取模型并转换为接口T
+func GetModelAs[T any](s *System, name string) (T, error) {
+	var zero T
+
+	m, err := s.GetModel(name)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := m.(T)
+	if !ok {
+		return zero, types.NewSystemError(types.ErrInvalid, "model does not support requested capability", nil).
+			WithContext("model", name).
+			WithContext("capabilities", model.CapabilitiesOf(m))
+	}
+	return typed, nil
+}
@@ -4,10 +4,12 @@ package common
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
 
 	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/common/scheduler"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -26,6 +28,9 @@ type Manager struct {
 		energies map[string]float64            // 共享能量
 	}
 
+	// 定时任务调度器
+	scheduler *scheduler.Scheduler
+
 	// 状态信息
 	status struct {
 		isRunning  bool
@@ -49,9 +54,10 @@ func NewManager(cfg *types.CommonConfig) (*Manager, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 
 	m := &Manager{
-		config: cfg,
-		ctx:    ctx,
-		cancel: cancel,
+		config:    cfg,
+		scheduler: scheduler.New(cfg.Scheduler),
+		ctx:       ctx,
+		cancel:    cancel,
 	}
 
 	// 初始化共享资源
@@ -102,6 +108,7 @@ func (m *Manager) Start(ctx context.Context) error {
 
 	m.status.isRunning = true
 	m.status.startTime = time.Now()
+	m.scheduler.Start(ctx)
 	return nil
 }
 
@@ -114,6 +121,7 @@ func (m *Manager) Stop() error {
 		return nil
 	}
 
+	m.scheduler.Stop()
 	m.cancel()
 	m.status.isRunning = false
 	return nil
@@ -130,6 +138,27 @@ func (m *Manager) Status() string {
 	return "stopped"
 }
 
+// Health 健康度(0-1), 未运行为0, 错误记录越多越低
+func (m *Manager) Health() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if !m.status.isRunning {
+		return 0
+	}
+	return 1 - math.Min(float64(len(m.status.errors))*0.1, 0.5)
+}
+
+// Drain 停止触发新的定时任务并等待执行中的任务结束
+func (m *Manager) Drain(ctx context.Context) error {
+	return m.scheduler.Drain(ctx)
+}
+
+// GetScheduler 获取定时任务调度器, 子系统通过它声明周期任务
+func (m *Manager) GetScheduler() *scheduler.Scheduler {
+	return m.scheduler
+}
+
 // Wait 等待管理器停止
 func (m *Manager) Wait() {
 	<-m.ctx.Done()
@@ -147,6 +176,7 @@ func (m *Manager) GetMetrics() map[string]interface{} {
 		"energy_total":   m.getTotalEnergy(),
 		"uptime":         time.Since(m.status.startTime).String(),
 		"error_count":    len(m.status.errors),
+		"scheduler":      m.scheduler.GetMetrics(),
 	}
 }
 
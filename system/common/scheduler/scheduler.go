@@ -0,0 +1,456 @@
+// system/common/scheduler/scheduler.go
+
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// idleWait 没有待触发任务时的等待上限, 注册任务会提前唤醒
+const idleWait = time.Hour
+
+// Job 定时任务声明
+type Job struct {
+	Name      string                          // 任务名, 全局唯一, 建议以子系统为前缀
+	Subsystem string                          // 声明任务的子系统
+	Spec      string                          // 计划, 见Parse
+	Jitter    time.Duration                   // 每次触发的随机延迟上限
+	Timeout   time.Duration                   // 单次执行超时, 为0时不限制
+	Run       func(ctx context.Context) error // 任务函数, 应响应上下文取消
+}
+
+// Result 一次任务执行的结果
+type Result struct {
+	Name     string
+	Start    time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// Handler 任务执行结果通知
+type Handler func(result Result)
+
+// JobStats 任务的计划和执行统计
+type JobStats struct {
+	Name         string        `json:"name"`
+	Subsystem    string        `json:"subsystem"`
+	Spec         string        `json:"spec"`
+	Disabled     bool          `json:"disabled"`
+	Running      bool          `json:"running"`
+	Next         time.Time     `json:"next"`     // 下次触发时间, 未启动、停用或不再触发时为零值
+	Runs         int64         `json:"runs"`     // 执行次数
+	Failures     int64         `json:"failures"` // 返回错误、超时或崩溃的次数
+	Skipped      int64         `json:"skipped"`  // 上次执行未结束而跳过的触发次数
+	LastStart    time.Time     `json:"last_start"`
+	LastDuration time.Duration `json:"last_duration"`
+	MeanDuration time.Duration `json:"mean_duration"`
+	MaxDuration  time.Duration `json:"max_duration"`
+	LastError    string        `json:"last_error"`
+}
+
+// entry 已注册的任务
+type entry struct {
+	job      Job
+	schedule Schedule
+	disabled bool
+	running  bool
+	next     time.Time
+	total    time.Duration // 累计执行时长
+	stats    JobStats
+}
+
+// Scheduler 定时任务调度器
+// 单个调度循环按各任务的计划触发执行, 每个任务同一时刻最多执行一次, 上次未结束时跳过本次触发
+type Scheduler struct {
+	mu sync.Mutex
+
+	// 基础配置
+	config types.SchedulerConfig
+
+	// 任务
+	jobs     map[string]*entry
+	handlers []Handler
+
+	// 运行状态, 未启动时ctx为nil
+	ctx    context.Context
+	cancel context.CancelFunc
+	wake   chan struct{}
+
+	// 排空状态: 排空期间不再触发任务, idle在执行中的任务全部结束时关闭
+	draining bool
+	inflight int
+	idle     chan struct{}
+}
+
+// New 创建调度器
+func New(config types.SchedulerConfig) *Scheduler {
+	return &Scheduler{
+		config: config,
+		jobs:   make(map[string]*entry),
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Register 注册任务, 配置中同名任务的覆盖项优先于声明; 调度器运行中注册时立即排定下次触发
+func (s *Scheduler) Register(job Job) error {
+	if job.Name == "" || job.Run == nil {
+		return types.NewSystemError(types.ErrInvalid, "job requires a name and a run function", nil)
+	}
+
+	override := s.config.Jobs[job.Name]
+	if override.Spec != "" {
+		job.Spec = override.Spec
+	}
+	if override.Jitter > 0 {
+		job.Jitter = override.Jitter
+	} else if job.Jitter <= 0 {
+		job.Jitter = s.config.Jitter
+	}
+	if override.Timeout > 0 {
+		job.Timeout = override.Timeout
+	}
+	schedule, err := Parse(job.Spec)
+	if err != nil {
+		return types.NewSystemError(types.ErrInvalid, "invalid job schedule", err).
+			WithContext("job", job.Name)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[job.Name]; exists {
+		return types.NewSystemError(types.ErrExists, "job already registered", nil).
+			WithContext("job", job.Name)
+	}
+	e := &entry{
+		job:      job,
+		schedule: schedule,
+		disabled: override.Disabled,
+		stats:    JobStats{Name: job.Name, Subsystem: job.Subsystem, Spec: job.Spec},
+	}
+	s.jobs[job.Name] = e
+	if s.ctx != nil {
+		e.next = s.nextRun(e, time.Now())
+		s.notify()
+	}
+	return nil
+}
+
+// Unregister 注销任务, 正在进行的执行不受影响; 任务不存在时返回false
+func (s *Scheduler) Unregister(name string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.jobs[name]; !exists {
+		return false
+	}
+	delete(s.jobs, name)
+	return true
+}
+
+// SetDisabled 停用或恢复任务
+func (s *Scheduler) SetDisabled(name string, disabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, exists := s.jobs[name]
+	if !exists {
+		return types.NewSystemError(types.ErrNotFound, "job not found", nil).WithContext("job", name)
+	}
+	e.disabled = disabled
+	if s.ctx != nil {
+		e.next = s.nextRun(e, time.Now())
+		s.notify()
+	}
+	return nil
+}
+
+// Trigger 立即执行任务, 不影响计划的下次触发; 调度器未运行或任务正在执行时返回错误
+func (s *Scheduler) Trigger(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, exists := s.jobs[name]
+	if !exists {
+		return types.NewSystemError(types.ErrNotFound, "job not found", nil).WithContext("job", name)
+	}
+	if s.ctx == nil {
+		return types.NewSystemError(types.ErrState, "scheduler not running", nil).WithContext("job", name)
+	}
+	if s.draining {
+		return types.NewSystemError(types.ErrState, "scheduler draining", nil).WithContext("job", name)
+	}
+	if !s.dispatch(e) {
+		return types.NewSystemError(types.ErrState, "job already running", nil).WithContext("job", name)
+	}
+	return nil
+}
+
+// Drain 停止触发新的执行并等待执行中的任务结束, 直到Start重新启动
+func (s *Scheduler) Drain(ctx context.Context) error {
+	s.mu.Lock()
+	s.draining = true
+	if s.inflight == 0 {
+		s.mu.Unlock()
+		return nil
+	}
+	if s.idle == nil {
+		s.idle = make(chan struct{})
+	}
+	idle := s.idle
+	s.mu.Unlock()
+
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// OnResult 注册任务执行结果通知, 在执行任务的协程中调用
+func (s *Scheduler) OnResult(handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers = append(s.handlers, handler)
+}
+
+// Start 在协程监管下启动调度循环
+func (s *Scheduler) Start(ctx context.Context) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx != nil {
+		return
+	}
+	s.ctx, s.cancel = context.WithCancel(ctx)
+	s.draining = false
+	now := time.Now()
+	for _, e := range s.jobs {
+		e.next = s.nextRun(e, now)
+	}
+	supervisor.Go(s.ctx, "common.scheduler", s.run)
+}
+
+// Stop 停止调度并取消正在执行的任务的上下文, 不等待任务返回
+// 任务可能正等待调用方持有的锁, 等待会导致死锁
+func (s *Scheduler) Stop() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.ctx == nil {
+		return
+	}
+	s.cancel()
+	s.ctx, s.cancel = nil, nil
+	for _, e := range s.jobs {
+		e.next = time.Time{}
+	}
+}
+
+// Jobs 获取全部任务的统计, 按名称排序
+func (s *Scheduler) Jobs() []JobStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stats := make([]JobStats, 0, len(s.jobs))
+	for _, e := range s.jobs {
+		stats = append(stats, e.snapshot())
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Name < stats[j].Name })
+	return stats
+}
+
+// Job 获取任务的统计
+func (s *Scheduler) Job(name string) (JobStats, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	e, exists := s.jobs[name]
+	if !exists {
+		return JobStats{}, false
+	}
+	return e.snapshot(), true
+}
+
+// GetMetrics 获取调度器指标
+func (s *Scheduler) GetMetrics() map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var running, disabled int
+	var runs, failures, skipped int64
+	for _, e := range s.jobs {
+		if e.running {
+			running++
+		}
+		if e.disabled {
+			disabled++
+		}
+		runs += e.stats.Runs
+		failures += e.stats.Failures
+		skipped += e.stats.Skipped
+	}
+	return map[string]interface{}{
+		"jobs":     len(s.jobs),
+		"running":  running,
+		"disabled": disabled,
+		"runs":     runs,
+		"failures": failures,
+		"skipped":  skipped,
+	}
+}
+
+// run 调度循环, 触发到期的任务后等待最近的下次触发
+func (s *Scheduler) run(ctx context.Context) {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-timer.C:
+		case <-s.wake:
+			if !timer.Stop() {
+				select {
+				case <-timer.C:
+				default:
+				}
+			}
+		}
+		timer.Reset(s.tick(time.Now()))
+	}
+}
+
+// tick 触发到期的任务并排定下次触发, 返回距最近一次触发的等待时间
+func (s *Scheduler) tick(now time.Time) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	wait := idleWait
+	for _, e := range s.jobs {
+		if e.next.IsZero() || s.draining {
+			continue
+		}
+		if !e.next.After(now) {
+			if !s.dispatch(e) {
+				e.stats.Skipped++
+			}
+			e.next = s.nextRun(e, now)
+			if e.next.IsZero() {
+				continue
+			}
+		}
+		wait = min(wait, e.next.Sub(now))
+	}
+	return wait
+}
+
+// dispatch 在新协程中执行任务, 任务正在执行时返回false(调用方持有锁)
+func (s *Scheduler) dispatch(e *entry) bool {
+	if e.running || s.ctx == nil {
+		return false
+	}
+	e.running = true
+	s.inflight++
+	go s.execute(s.ctx, e)
+	return true
+}
+
+// execute 执行一次任务并记录统计, 任务崩溃记为失败
+func (s *Scheduler) execute(ctx context.Context, e *entry) {
+	job := e.job
+	if job.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, job.Timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	err := invoke(ctx, job.Run)
+	if err == nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		err = types.NewSystemError(types.ErrTimeout, "job exceeded timeout", nil).
+			WithContext("job", job.Name).
+			WithContext("timeout", job.Timeout.String())
+	}
+	result := Result{Name: job.Name, Start: start, Duration: time.Since(start), Err: err}
+
+	s.mu.Lock()
+	e.running = false
+	s.inflight--
+	if s.inflight == 0 && s.idle != nil {
+		close(s.idle)
+		s.idle = nil
+	}
+	e.record(result)
+	handlers := append([]Handler(nil), s.handlers...)
+	s.mu.Unlock()
+
+	for _, handler := range handlers {
+		handler(result)
+	}
+}
+
+// invoke 执行任务函数, 崩溃转换为错误
+func invoke(ctx context.Context, fn func(context.Context) error) (err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			err = types.NewSystemError(types.ErrInternal, fmt.Sprintf("job panicked: %v", p), nil)
+		}
+	}()
+	return fn(ctx)
+}
+
+// nextRun 下次触发时间, 加上随机延迟; 停用或不再触发时为零值(调用方持有锁)
+func (s *Scheduler) nextRun(e *entry, now time.Time) time.Time {
+	if e.disabled {
+		return time.Time{}
+	}
+	next := e.schedule.Next(now)
+	if next.IsZero() || e.job.Jitter <= 0 {
+		return next
+	}
+	return next.Add(time.Duration(rand.Int63n(int64(e.job.Jitter))))
+}
+
+// notify 唤醒调度循环重新计算等待时间
+func (s *Scheduler) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// record 记录执行结果
+func (e *entry) record(result Result) {
+	e.stats.Runs++
+	e.stats.LastStart = result.Start
+	e.stats.LastDuration = result.Duration
+	e.stats.MaxDuration = max(e.stats.MaxDuration, result.Duration)
+	e.total += result.Duration
+	e.stats.MeanDuration = e.total / time.Duration(e.stats.Runs)
+	e.stats.LastError = ""
+	if result.Err != nil {
+		e.stats.Failures++
+		e.stats.LastError = result.Err.Error()
+	}
+}
+
+// snapshot 任务统计的副本
+func (e *entry) snapshot() JobStats {
+	stats := e.stats
+	stats.Disabled = e.disabled
+	stats.Running = e.running
+	stats.Next = e.next
+	return stats
+}
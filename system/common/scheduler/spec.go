@@ -0,0 +1,199 @@
+// system/common/scheduler/spec.go
+
+package scheduler
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// searchHorizon 查找下次触发时间的上限, 超出时视为不再触发(如2月30日)
+const searchHorizon = 5 * 365 * 24 * time.Hour
+
+// Schedule 任务计划
+type Schedule interface {
+	// Next 严格晚于t的下次触发时间, 不再触发时返回零值
+	Next(t time.Time) time.Time
+}
+
+// descriptors 计划描述符及对应的cron表达式
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+// field cron字段的取值范围
+type field struct {
+	name     string
+	min, max int
+}
+
+// cron表达式的五个字段: 分 时 日 月 周
+var fields = [5]field{
+	{"minute", 0, 59},
+	{"hour", 0, 23},
+	{"day of month", 1, 31},
+	{"month", 1, 12},
+	{"day of week", 0, 7},
+}
+
+// Parse 解析任务计划
+// 支持五段cron表达式(分 时 日 月 周, 字段可为*、数值、a-b范围、逗号分隔的列表, 以及/n步长, 周日为0或7),
+// @every <duration>固定间隔, 以及@hourly、@daily、@weekly、@monthly、@yearly描述符
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every") {
+		interval, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every")))
+		if err != nil || interval <= 0 {
+			return nil, types.NewSystemError(types.ErrInvalid, "invalid @every interval", err).
+				WithContext("spec", spec)
+		}
+		return Every(interval), nil
+	}
+	if expr, exists := descriptors[spec]; exists {
+		spec = expr
+	}
+
+	parts := strings.Fields(spec)
+	if len(parts) != len(fields) {
+		return nil, types.NewSystemError(types.ErrInvalid, "cron spec must have 5 fields", nil).
+			WithContext("spec", spec)
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, types.NewSystemError(types.ErrInvalid, "invalid cron field", err).
+				WithContext("spec", spec).
+				WithContext("field", fields[i].name)
+		}
+		bits[i] = b
+	}
+
+	// 周日可写作0或7
+	if bits[4]&(1<<7) != 0 {
+		bits[4] |= 1
+	}
+	return &cronSchedule{
+		minute:   bits[0],
+		hour:     bits[1],
+		dom:      bits[2],
+		month:    bits[3],
+		dow:      bits[4] &^ (1 << 7),
+		domStar:  strings.HasPrefix(parts[2], "*"),
+		dowStar:  strings.HasPrefix(parts[4], "*"),
+		location: time.Local,
+	}, nil
+}
+
+// parseField 解析一个字段为取值位图
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(expr, ",") {
+		rangeExpr, step := item, 1
+		if i := strings.Index(item, "/"); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return 0, types.NewSystemError(types.ErrInvalid, "invalid step", err).WithContext("item", item)
+			}
+			rangeExpr, step = item[:i], n
+		}
+
+		lo, hi := f.min, f.max
+		switch {
+		case rangeExpr == "*":
+		case strings.Contains(rangeExpr, "-"):
+			bounds := strings.SplitN(rangeExpr, "-", 2)
+			var err error
+			if lo, err = strconv.Atoi(bounds[0]); err != nil {
+				return 0, types.NewSystemError(types.ErrInvalid, "invalid range", err).WithContext("item", item)
+			}
+			if hi, err = strconv.Atoi(bounds[1]); err != nil {
+				return 0, types.NewSystemError(types.ErrInvalid, "invalid range", err).WithContext("item", item)
+			}
+		default:
+			v, err := strconv.Atoi(rangeExpr)
+			if err != nil {
+				return 0, types.NewSystemError(types.ErrInvalid, "invalid value", err).WithContext("item", item)
+			}
+			lo = v
+			// 带步长的单个数值表示从该值到字段上限
+			if step == 1 {
+				hi = v
+			}
+		}
+
+		if lo < f.min || hi > f.max || lo > hi {
+			return 0, types.NewSystemError(types.ErrInvalid, "value out of range", nil).
+				WithContext("item", item).
+				WithContext("min", f.min).
+				WithContext("max", f.max)
+		}
+		for v := lo; v <= hi; v += step {
+			bits |= 1 << uint(v)
+		}
+	}
+	return bits, nil
+}
+
+// Every 固定间隔的计划
+type Every time.Duration
+
+// Next 下次触发时间
+func (e Every) Next(t time.Time) time.Time {
+	return t.Add(time.Duration(e))
+}
+
+// cronSchedule cron表达式计划, 分钟精度, 按本地时区计算
+type cronSchedule struct {
+	minute, hour, dom, month, dow uint64
+	domStar, dowStar              bool
+	location                      *time.Location
+}
+
+// Next 下次触发时间
+// 日和周都有限定时满足其一即可, 与常见cron实现一致
+func (c *cronSchedule) Next(t time.Time) time.Time {
+	t = t.In(c.location).Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchHorizon)
+
+	for t.Before(limit) {
+		if c.month&(1<<uint(t.Month())) == 0 {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, c.location)
+			continue
+		}
+		if !c.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, c.location)
+			continue
+		}
+		if c.hour&(1<<uint(t.Hour())) == 0 {
+			t = time.Date(t.Year(), t.Month(), t.Day(), t.Hour()+1, 0, 0, 0, c.location)
+			continue
+		}
+		if c.minute&(1<<uint(t.Minute())) == 0 {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+// dayMatches 日期是否满足日和周字段
+func (c *cronSchedule) dayMatches(t time.Time) bool {
+	dom := c.dom&(1<<uint(t.Day())) != 0
+	dow := c.dow&(1<<uint(t.Weekday())) != 0
+	if c.domStar || c.dowStar {
+		return dom && dow
+	}
+	return dom || dow
+}
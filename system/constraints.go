@@ -0,0 +1,42 @@
+// system/constraints.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/evolution/constraint"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// AddConstraint 添加适应动作约束, 策略执行的动作参数按约束裁剪或拒绝
+func (s *System) AddConstraint(c constraint.Constraint) error {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return types.NewSystemError(types.ErrNotFound, "adaptation strategy not available", nil)
+	}
+	return strategy.GetConstraints().Add(c)
+}
+
+// RemoveConstraint 移除适应动作约束
+func (s *System) RemoveConstraint(name string) {
+	if strategy := s.evolution.GetStrategy(); strategy != nil {
+		strategy.GetConstraints().Remove(name)
+	}
+}
+
+// Constraints 获取适应动作约束
+func (s *System) Constraints() []constraint.Constraint {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return nil
+	}
+	return strategy.GetConstraints().List()
+}
+
+// ConstraintViolations 获取最近的约束违反记录
+func (s *System) ConstraintViolations() []constraint.Violation {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return nil
+	}
+	return strategy.GetConstraints().Violations()
+}
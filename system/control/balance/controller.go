@@ -0,0 +1,300 @@
+// system/control/balance/controller.go
+
+package balance
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultInterval     = 5 * time.Second
+	defaultBand         = 0.1
+	defaultHarmonyFloor = 0.5
+	defaultCooldown     = 10 * time.Second
+	defaultMaxActions   = 6
+	defaultWindow       = time.Minute
+	maxActionHistory    = 100
+)
+
+// 修正原因
+const (
+	ReasonYangExcess = "yang_excess" // 阳能量过剩
+	ReasonYinExcess  = "yin_excess"  // 阴能量过剩
+	ReasonLowHarmony = "low_harmony" // 和谐度过低
+)
+
+// Reading 阴阳读数
+type Reading struct {
+	Yin     float64 `json:"yin"`
+	Yang    float64 `json:"yang"`
+	Harmony float64 `json:"harmony"` // 统一场和谐度(0-1)
+}
+
+// Imbalance 失衡度, 范围[-1, 1], 正值表示阳过剩
+func (r Reading) Imbalance() float64 {
+	total := r.Yin + r.Yang
+	if total <= 0 {
+		return 0
+	}
+	return (r.Yang - r.Yin) / total
+}
+
+// Action 修正记录
+type Action struct {
+	Timestamp time.Time              `json:"timestamp"`
+	Pattern   model.TransformPattern `json:"pattern"`
+	Reason    string                 `json:"reason"`
+	Before    Reading                `json:"before"`
+	Imbalance float64                `json:"imbalance"`
+	Applied   bool                   `json:"applied"`
+	Limited   bool                   `json:"limited"` // 超出修正速率, 未执行
+	Error     string                 `json:"error,omitempty"`
+}
+
+// Controller 阴阳平衡控制器
+// 周期读取阴阳能量和统一场和谐度, 失衡超出带宽时计算修正转换,
+// 按冷却时间和窗口次数限速执行, 每次决策都通过通知处理器留痕
+type Controller struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.BalanceConfig
+
+	// 读数来源与执行器
+	source   func() (Reading, error)
+	actuator func(pattern model.TransformPattern) error
+	handlers []func(Action)
+
+	// 控制状态
+	state struct {
+		last        Reading
+		lastAction  time.Time
+		recent      []time.Time // 窗口内的修正时间
+		actions     []Action
+		checks      int64
+		corrections int64
+		limited     int64
+		failures    int64
+	}
+}
+
+// NewController 创建平衡控制器
+func NewController(config types.BalanceConfig) *Controller {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.Band <= 0 || config.Band >= 1 {
+		config.Band = defaultBand
+	}
+	if config.HarmonyFloor <= 0 {
+		config.HarmonyFloor = defaultHarmonyFloor
+	}
+	if config.Cooldown <= 0 {
+		config.Cooldown = defaultCooldown
+	}
+	if config.MaxActions <= 0 {
+		config.MaxActions = defaultMaxActions
+	}
+	if config.Window <= 0 {
+		config.Window = defaultWindow
+	}
+
+	c := &Controller{config: config}
+	c.state.recent = make([]time.Time, 0, config.MaxActions)
+	c.state.actions = make([]Action, 0)
+	return c
+}
+
+// SetSource 设置读数来源
+func (c *Controller) SetSource(source func() (Reading, error)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.source = source
+}
+
+// SetActuator 设置修正执行器
+func (c *Controller) SetActuator(actuator func(pattern model.TransformPattern) error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.actuator = actuator
+}
+
+// OnAction 注册修正通知, 包括限速未执行的决策
+func (c *Controller) OnAction(handler func(Action)) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.handlers = append(c.handlers, handler)
+}
+
+// Run 按检查间隔运行控制循环, 直到上下文取消
+func (c *Controller) Run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			c.Step()
+		}
+	}
+}
+
+// Start 在协程监管下启动控制循环
+func (c *Controller) Start(ctx context.Context) {
+	supervisor.Go(ctx, "control.balance", c.Run)
+}
+
+// Step 执行一次检查, 返回本次决策; 读数在带宽内或来源未设置时返回nil
+func (c *Controller) Step() (*Action, error) {
+	c.mu.RLock()
+	source, actuator := c.source, c.actuator
+	c.mu.RUnlock()
+	if source == nil || actuator == nil {
+		return nil, nil
+	}
+
+	// 在锁外读取, 来源可能需要获取模型的锁
+	reading, err := source()
+	if err != nil {
+		return nil, types.NewDomainError(types.DomainControl, types.ErrState, "failed to read yin-yang balance", err)
+	}
+
+	c.mu.Lock()
+	c.state.checks++
+	c.state.last = reading
+	pattern, reason, ok := c.decide(reading)
+	if !ok {
+		c.mu.Unlock()
+		return nil, nil
+	}
+
+	now := time.Now()
+	action := Action{
+		Timestamp: now,
+		Pattern:   pattern,
+		Reason:    reason,
+		Before:    reading,
+		Imbalance: reading.Imbalance(),
+	}
+	if !c.allow(now) {
+		action.Limited = true
+		c.state.limited++
+		c.record(action)
+		c.mu.Unlock()
+		c.notify(action)
+		return &action, nil
+	}
+	c.state.lastAction = now
+	c.state.recent = append(c.state.recent, now)
+	c.mu.Unlock()
+
+	// 执行修正
+	err = actuator(pattern)
+	action.Applied = err == nil
+	if err != nil {
+		action.Error = err.Error()
+		err = types.NewDomainError(types.DomainControl, types.ErrState, "failed to apply balance correction", err).
+			WithContext("pattern", pattern).
+			WithContext("reason", reason)
+	}
+
+	c.mu.Lock()
+	if action.Applied {
+		c.state.corrections++
+	} else {
+		c.state.failures++
+	}
+	c.record(action)
+	c.mu.Unlock()
+
+	c.notify(action)
+	return &action, err
+}
+
+// Actions 获取最近的修正记录, 按时间排序
+func (c *Controller) Actions() []Action {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Action(nil), c.state.actions...)
+}
+
+// GetMetrics 获取控制指标
+func (c *Controller) GetMetrics() map[string]float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	return map[string]float64{
+		"yin":          c.state.last.Yin,
+		"yang":         c.state.last.Yang,
+		"harmony":      c.state.last.Harmony,
+		"imbalance":    c.state.last.Imbalance(),
+		"band":         c.config.Band,
+		"checks":       float64(c.state.checks),
+		"corrections":  float64(c.state.corrections),
+		"rate_limited": float64(c.state.limited),
+		"failures":     float64(c.state.failures),
+	}
+}
+
+// decide 计算修正转换, 调用方需持有锁
+// 和谐度过低时整体平衡, 否则将过剩一方的能量转向另一方
+func (c *Controller) decide(r Reading) (model.TransformPattern, string, bool) {
+	if r.Harmony < c.config.HarmonyFloor {
+		return model.PatternBalance, ReasonLowHarmony, true
+	}
+
+	imbalance := r.Imbalance()
+	switch {
+	case imbalance > c.config.Band:
+		return model.PatternReverse, ReasonYangExcess, true
+	case imbalance < -c.config.Band:
+		return model.PatternForward, ReasonYinExcess, true
+	}
+	return model.PatternNone, "", false
+}
+
+// allow 检查冷却时间和窗口次数, 调用方需持有锁
+func (c *Controller) allow(now time.Time) bool {
+	if !c.state.lastAction.IsZero() && now.Sub(c.state.lastAction) < c.config.Cooldown {
+		return false
+	}
+
+	cutoff := now.Add(-c.config.Window)
+	kept := c.state.recent[:0]
+	for _, t := range c.state.recent {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	c.state.recent = kept
+	return len(c.state.recent) < c.config.MaxActions
+}
+
+// record 保存修正记录, 调用方需持有锁
+func (c *Controller) record(action Action) {
+	c.state.actions = append(c.state.actions, action)
+	if over := len(c.state.actions) - maxActionHistory; over > 0 {
+		c.state.actions = c.state.actions[over:]
+	}
+}
+
+// notify 通知修正处理器
+func (c *Controller) notify(action Action) {
+	c.mu.RLock()
+	handlers := make([]func(Action), len(c.handlers))
+	copy(handlers, c.handlers)
+	c.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(action)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/types"
 )
@@ -704,7 +705,7 @@ func (c *Coordinator) calculateConflictRate() float64 {
 	return currentRate
 }
 func generateSessionID() string {
-	return fmt.Sprintf("session_%d", time.Now().UnixNano())
+	return core.NewID("session_")
 }
 
 // checkProcessStatus 检查进程状态
@@ -931,9 +932,9 @@ func (c *Coordinator) ProcessSync(params types.SyncParams) error {
 
 // 辅助函数
 func generateEndpointID(prefix string) string {
-	return fmt.Sprintf("%s_%d", prefix, time.Now().UnixNano())
+	return core.NewID(prefix + "_")
 }
 
 func generateSyncTaskID() string {
-	return fmt.Sprintf("task_%d", time.Now().UnixNano())
+	return core.NewID("task_")
 }
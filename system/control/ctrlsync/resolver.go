@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/types"
 )
@@ -656,7 +657,7 @@ func (r *Resolver) updateMetrics() {
 }
 
 func generateResolutionID() string {
-	return fmt.Sprintf("res_%d", time.Now().UnixNano())
+	return core.NewID("res_")
 }
 
 // ResolveConflict 解决单个冲突
@@ -17,6 +17,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/types"
 )
@@ -439,7 +440,7 @@ func (s *Synchronizer) updateMetrics() {
 }
 
 func generateOperationID() string {
-	return fmt.Sprintf("op_%d", time.Now().UnixNano())
+	return core.NewID("op_")
 }
 
 const (
@@ -0,0 +1,260 @@
+// system/control/governor/governor.go
+
+package governor
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultWindow      = time.Minute
+	defaultInterval    = 5 * time.Second
+	defaultMaxSlowdown = 16.0
+)
+
+// 用量低于预算的该比例后逐步解除节流, 避免在预算附近反复切换; 每次评估倍率最多减半
+const (
+	releaseRatio = 0.8
+	releaseStep  = 0.5
+)
+
+// 节流原因
+const (
+	ReasonCPU    = "cpu"
+	ReasonMemory = "memory"
+)
+
+// MemoryFunc 循环所属缓存的近似字节数
+type MemoryFunc func() int64
+
+// Handler 节流状态变化通知
+type Handler func(state types.ThrottleState)
+
+// sample 一次循环执行
+type sample struct {
+	at   time.Time
+	busy time.Duration
+}
+
+// governed 受约束的循环
+type governed struct {
+	samples []sample
+	memory  MemoryFunc
+	state   types.ThrottleState
+}
+
+// Governor 循环资源预算
+// 记录各循环每次执行的耗时, 按窗口内执行时间占比(单核)和缓存占用与预算比较;
+// 超出预算时按超出比例放大循环间隔, 用量回落到预算的releaseRatio以下后逐步恢复
+type Governor struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.GovernorConfig
+
+	// 受约束的循环
+	loops    map[string]*governed
+	handlers []Handler
+}
+
+// New 创建资源预算
+func New(config types.GovernorConfig) *Governor {
+	if config.Window <= 0 {
+		config.Window = defaultWindow
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.MaxSlowdown <= 1 {
+		config.MaxSlowdown = defaultMaxSlowdown
+	}
+
+	g := &Governor{
+		config: config,
+		loops:  make(map[string]*governed),
+	}
+	for name, budget := range config.Budgets {
+		g.loops[name] = &governed{state: types.ThrottleState{Name: name, Budget: budget, Factor: 1}}
+	}
+	return g
+}
+
+// Register 登记循环及其所属子系统, memory可为nil; 未配置预算的循环只统计用量
+func (g *Governor) Register(name, subsystem string, memory MemoryFunc) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	loop := g.loop(name)
+	loop.state.Subsystem = subsystem
+	loop.memory = memory
+}
+
+// OnChange 注册节流开始和结束通知
+func (g *Governor) OnChange(handler Handler) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.handlers = append(g.handlers, handler)
+}
+
+// Observe 记录循环的一次执行耗时
+func (g *Governor) Observe(name string, busy time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	loop := g.loop(name)
+	loop.samples = append(loop.samples, sample{at: time.Now(), busy: busy})
+}
+
+// Factor 循环当前的间隔倍率, 未节流时为1
+func (g *Governor) Factor(name string) float64 {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+	if loop, ok := g.loops[name]; ok {
+		return loop.state.Factor
+	}
+	return 1
+}
+
+// States 各循环的用量和节流状态, 按名称排序
+func (g *Governor) States() []types.ThrottleState {
+	g.mu.RLock()
+	defer g.mu.RUnlock()
+
+	states := make([]types.ThrottleState, 0, len(g.loops))
+	for _, loop := range g.loops {
+		states = append(states, loop.state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// Run 按评估间隔运行, 直到上下文取消
+func (g *Governor) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			g.Evaluate(now)
+		}
+	}
+}
+
+// Start 在协程监管下启动评估循环
+func (g *Governor) Start(ctx context.Context) {
+	supervisor.Go(ctx, "control.governor", g.Run)
+}
+
+// Evaluate 按截至now的窗口评估各循环的用量并调整间隔倍率, 返回节流状态发生变化的循环
+func (g *Governor) Evaluate(now time.Time) []types.ThrottleState {
+	// 在锁外读取缓存占用, 来源可能需要获取组件的锁
+	g.mu.RLock()
+	memory := make(map[string]MemoryFunc, len(g.loops))
+	for name, loop := range g.loops {
+		if loop.memory != nil && loop.state.Budget.Memory > 0 {
+			memory[name] = loop.memory
+		}
+	}
+	g.mu.RUnlock()
+
+	usage := make(map[string]int64, len(memory))
+	for name, fn := range memory {
+		usage[name] = fn()
+	}
+
+	g.mu.Lock()
+	changed := make([]types.ThrottleState, 0)
+	cutoff := now.Add(-g.config.Window)
+	for name, loop := range g.loops {
+		// 丢弃窗口外的执行记录
+		keep := 0
+		for keep < len(loop.samples) && loop.samples[keep].at.Before(cutoff) {
+			keep++
+		}
+		loop.samples = append(loop.samples[:0], loop.samples[keep:]...)
+
+		var busy time.Duration
+		for _, s := range loop.samples {
+			busy += s.busy
+		}
+		state := &loop.state
+		state.CPU = busy.Seconds() / g.config.Window.Seconds()
+		state.Cycles = len(loop.samples)
+		state.Memory = usage[name]
+
+		if g.adjust(state, now) {
+			changed = append(changed, *state)
+		}
+	}
+	handlers := append([]Handler(nil), g.handlers...)
+	g.mu.Unlock()
+
+	sort.Slice(changed, func(i, j int) bool { return changed[i].Name < changed[j].Name })
+	for _, state := range changed {
+		for _, handler := range handlers {
+			handler(state)
+		}
+	}
+	return changed
+}
+
+// adjust 按用量与预算之比调整间隔倍率, 返回是否进入或解除节流
+// 超出预算时倍率按超出比例放大, 低于预算的releaseRatio时按余量比例缩小(每次最多减半), 二者之间保持不变
+func (g *Governor) adjust(state *types.ThrottleState, now time.Time) bool {
+	ratio, reason := 0.0, ""
+	if budget := state.Budget.CPU; budget > 0 {
+		ratio, reason = state.CPU/budget, ReasonCPU
+	}
+	if budget := state.Budget.Memory; budget > 0 {
+		if r := float64(state.Memory) / float64(budget); r > ratio {
+			ratio, reason = r, ReasonMemory
+		}
+	}
+
+	factor := state.Factor
+	switch {
+	case reason == "":
+		factor = 1
+	case ratio > 1:
+		factor = math.Min(factor*ratio, g.config.MaxSlowdown)
+	case ratio < releaseRatio:
+		factor = math.Max(factor*math.Max(ratio/releaseRatio, releaseStep), 1)
+	}
+	state.Factor = factor
+
+	throttled := factor > 1
+	if throttled == state.Throttled {
+		if throttled && ratio > 1 {
+			state.Reason = reason
+		}
+		return false
+	}
+	state.Throttled = throttled
+	state.Since = now
+	state.Reason = ""
+	if throttled {
+		state.Reason = reason
+	}
+	return true
+}
+
+// loop 获取或创建循环记录(调用方持有锁)
+func (g *Governor) loop(name string) *governed {
+	loop, ok := g.loops[name]
+	if !ok {
+		loop = &governed{state: types.ThrottleState{Name: name, Factor: 1}}
+		g.loops[name] = loop
+	}
+	return loop
+}
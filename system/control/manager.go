@@ -4,6 +4,7 @@ package control
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
 
@@ -11,8 +12,12 @@ import (
 	"github.com/Corphon/daoflow/model"
 
 	"github.com/Corphon/daoflow/system/common"
+	"github.com/Corphon/daoflow/system/control/balance"
 	"github.com/Corphon/daoflow/system/control/ctrlsync"
 	"github.com/Corphon/daoflow/system/control/flow"
+	"github.com/Corphon/daoflow/system/control/modulation"
+	"github.com/Corphon/daoflow/system/control/watchdog"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -30,6 +35,9 @@ type Manager struct {
 		allocator  *ResourceAlloc        // 资源分配器
 		optimizer  *FlowOptimizer        // 流优化器
 		stateCoord *ctrlsync.Coordinator // 状态协调器
+		balancer   *balance.Controller   // 阴阳平衡控制器
+		modulator  *modulation.Modulator // 参数时间调制器
+		watchdog   *watchdog.Watchdog    // 子系统看门狗
 	}
 
 	// 控制状态
@@ -214,6 +222,20 @@ func NewManager(cfg *types.ControlConfig) (*Manager, error) {
 	coordinator := ctrlsync.NewCoordinator(resolver, synchronizer)
 	m.components.stateCoord = coordinator
 
+	// 创建阴阳平衡控制器, 读数来源和执行器由系统注入
+	m.components.balancer = balance.NewController(cfg.Balance)
+
+	// 创建参数时间调制器, 调制目标由系统绑定
+	modulator, err := modulation.NewModulator(cfg.Modulation)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	m.components.modulator = modulator
+
+	// 创建子系统看门狗, 监视对象和恢复方法由系统注入
+	m.components.watchdog = watchdog.New(cfg.Watchdog)
+
 	return m, nil
 }
 
@@ -320,6 +342,31 @@ func DefaultConfig() *types.ControlConfig {
 			ReserveRatio: 0.2,
 		},
 
+		Balance: types.BalanceConfig{
+			Enabled:      false,
+			Interval:     time.Second * 5,
+			Band:         0.1,
+			HarmonyFloor: 0.5,
+			Cooldown:     time.Second * 10,
+			MaxActions:   6,
+			Window:       time.Minute,
+		},
+
+		Modulation: types.ModulationConfig{
+			Enabled:  false,
+			Interval: time.Minute,
+		},
+
+		Watchdog: types.WatchdogConfig{
+			Enabled:          false,
+			Interval:         time.Second * 10,
+			MinHealth:        0.3,
+			FailureThreshold: 3,
+			InitialBackoff:   time.Second * 5,
+			MaxBackoff:       time.Minute * 5,
+			MaxRetries:       5,
+		},
+
 		Optimization: struct {
 			Enabled    bool          `json:"enabled"`
 			Strategy   string        `json:"strategy"`
@@ -362,6 +409,11 @@ func (m *Manager) Start(ctx context.Context) error {
 		return nil
 	}
 
+	// 组件循环沿用调用方上下文中的协程监管器
+	if sup := supervisor.FromContext(ctx); sup != nil && supervisor.FromContext(m.ctx) != sup {
+		m.ctx = supervisor.NewContext(m.ctx, sup)
+	}
+
 	// 启动各组件
 	if err := m.startComponents(); err != nil {
 		return err
@@ -398,6 +450,17 @@ func (m *Manager) Status() string {
 	return m.state.status
 }
 
+// Health 健康度(0-1), 未运行为0, 错误记录越多越低
+func (m *Manager) Health() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.state.status != "running" {
+		return 0
+	}
+	return 1 - math.Min(float64(len(m.state.errors))*0.1, 0.5)
+}
+
 // Wait 等待管理器停止
 func (m *Manager) Wait() {
 	<-m.ctx.Done()
@@ -415,9 +478,33 @@ func (m *Manager) GetMetrics() map[string]interface{} {
 		"uptime":          time.Since(m.state.startTime).String(),
 		"error_count":     len(m.state.errors),
 		"last_update":     m.state.lastUpdate.Format(time.RFC3339),
+		"balance":         m.components.balancer.GetMetrics(),
+		"modulation":      m.components.modulator.GetMetrics(),
+		"watchdog":        m.components.watchdog.GetMetrics(),
 	}
 }
 
+// GetBalanceController 获取阴阳平衡控制器
+func (m *Manager) GetBalanceController() *balance.Controller {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.balancer
+}
+
+// GetWatchdog 获取子系统看门狗
+func (m *Manager) GetWatchdog() *watchdog.Watchdog {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.watchdog
+}
+
+// GetModulator 获取参数时间调制器
+func (m *Manager) GetModulator() *modulation.Modulator {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.modulator
+}
+
 // Restore 恢复管理器
 func (m *Manager) Restore(ctx context.Context) error {
 	m.mu.Lock()
@@ -436,7 +523,12 @@ func (m *Manager) Restore(ctx context.Context) error {
 // 私有辅助方法
 
 func (m *Manager) startComponents() error {
-	// TODO: 实现组件启动逻辑
+	if m.config.Balance.Enabled {
+		m.components.balancer.Start(m.ctx)
+	}
+	if m.config.Modulation.Enabled {
+		m.components.modulator.Start(m.ctx)
+	}
 	return nil
 }
 
@@ -0,0 +1,191 @@
+// system/control/modulation/curve.go
+
+package modulation
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 干支周期
+const (
+	CycleHour = "hour" // 时辰
+	CycleDay  = "day"  // 日
+	CycleYear = "year" // 年
+)
+
+// ganzhiDayEpoch 甲子日参考点(2000-01-07)
+var ganzhiDayEpoch = time.Date(2000, 1, 7, 0, 0, 0, 0, time.UTC)
+
+// Curve 调制曲线, 返回时刻t作用于基准值的倍率
+type Curve interface {
+	Value(t time.Time) float64
+}
+
+// CurveFunc 函数形式的调制曲线
+type CurveFunc func(t time.Time) float64
+
+// Value 实现Curve
+func (f CurveFunc) Value(t time.Time) float64 {
+	return f(t)
+}
+
+// DailyCurve 日周期余弦曲线, 在PeakHour(本地时间)达到1+Amplitude
+type DailyCurve struct {
+	Amplitude float64
+	PeakHour  float64
+}
+
+// Value 实现Curve
+func (c DailyCurve) Value(t time.Time) float64 {
+	hour := float64(t.Hour()) + float64(t.Minute())/60 + float64(t.Second())/3600
+	return 1 + c.Amplitude*math.Cos(2*math.Pi*(hour-c.PeakHour)/24)
+}
+
+// GanZhiCurve 干支周期曲线
+// Factors长度为10时按天干取值, 12时按地支取值, 60时按六十甲子序号取值
+type GanZhiCurve struct {
+	Cycle   string
+	Factors []float64
+}
+
+// Value 实现Curve, Factors长度不受支持时返回1
+func (c GanZhiCurve) Value(t time.Time) float64 {
+	stem, branch := GanZhiAt(c.Cycle, t)
+	switch len(c.Factors) {
+	case 10:
+		return c.Factors[stem]
+	case 12:
+		return c.Factors[branch]
+	case model.CycleLength:
+		return c.Factors[SexagenaryIndex(stem, branch)]
+	}
+	return 1
+}
+
+// GanZhiAt 计算时刻t在指定周期上的干支
+// 年按公历年近似(1984年为甲子年), 日以2000-01-07为甲子日, 时辰按日干推算时干(五鼠遁)
+func GanZhiAt(cycle string, t time.Time) (model.HeavenlyStem, model.EarthlyBranch) {
+	switch cycle {
+	case CycleYear:
+		idx := mod(t.Year()-1984, model.CycleLength)
+		return model.HeavenlyStem(idx % 10), model.EarthlyBranch(idx % 12)
+	case CycleHour:
+		dayStem, _ := GanZhiAt(CycleDay, t)
+		// 子时从23点开始, 23点后的子时属于次日
+		branch := ((t.Hour() + 1) / 2) % 12
+		if t.Hour() == 23 {
+			dayStem = (dayStem + 1) % 10
+		}
+		stem := (int(dayStem)%5*2 + branch) % 10
+		return model.HeavenlyStem(stem), model.EarthlyBranch(branch)
+	default:
+		day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		idx := mod(int(day.Sub(ganzhiDayEpoch)/(24*time.Hour)), model.CycleLength)
+		return model.HeavenlyStem(idx % 10), model.EarthlyBranch(idx % 12)
+	}
+}
+
+// SexagenaryIndex 干支在六十甲子中的序号(甲子为0)
+func SexagenaryIndex(stem model.HeavenlyStem, branch model.EarthlyBranch) int {
+	return mod(6*int(stem)-5*int(branch), model.CycleLength)
+}
+
+// ScheduleCurve 用户自定义分段线性曲线, 以Anchor为起点按Period循环
+// Period为0时不循环, 首个节点前和末个节点后取端点值
+type ScheduleCurve struct {
+	Period time.Duration
+	Anchor time.Time
+	Points []types.SchedulePoint
+}
+
+// Value 实现Curve, 没有节点时返回1
+func (c ScheduleCurve) Value(t time.Time) float64 {
+	if len(c.Points) == 0 {
+		return 1
+	}
+	anchor := c.Anchor
+	if anchor.IsZero() {
+		anchor = time.Unix(0, 0)
+	}
+	offset := t.Sub(anchor)
+	if c.Period > 0 {
+		offset %= c.Period
+		if offset < 0 {
+			offset += c.Period
+		}
+	}
+
+	points := c.Points
+	if offset <= points[0].Offset {
+		return points[0].Factor
+	}
+	for i := 1; i < len(points); i++ {
+		if offset <= points[i].Offset {
+			prev, next := points[i-1], points[i]
+			ratio := float64(offset-prev.Offset) / float64(next.Offset-prev.Offset)
+			return prev.Factor + ratio*(next.Factor-prev.Factor)
+		}
+	}
+	return points[len(points)-1].Factor
+}
+
+// NewCurve 根据配置创建调制曲线
+func NewCurve(config types.CurveConfig) (Curve, error) {
+	var curve Curve
+	switch config.Kind {
+	case types.CurveDaily:
+		curve = DailyCurve{Amplitude: config.Amplitude, PeakHour: config.PeakHour}
+	case types.CurveGanZhi:
+		switch config.Cycle {
+		case CycleHour, CycleDay, CycleYear:
+		default:
+			return nil, types.NewDomainError(types.DomainControl, types.ErrInvalidConfig, "unknown ganzhi cycle", nil).
+				WithContext("cycle", config.Cycle)
+		}
+		switch len(config.Factors) {
+		case 10, 12, model.CycleLength:
+		default:
+			return nil, types.NewDomainError(types.DomainControl, types.ErrInvalidConfig, "ganzhi factors must have 10, 12 or 60 entries", nil).
+				WithContext("factors", len(config.Factors))
+		}
+		curve = GanZhiCurve{Cycle: config.Cycle, Factors: append([]float64(nil), config.Factors...)}
+	case types.CurveSchedule:
+		if len(config.Points) == 0 {
+			return nil, types.NewDomainError(types.DomainControl, types.ErrInvalidConfig, "schedule curve requires points", nil)
+		}
+		points := append([]types.SchedulePoint(nil), config.Points...)
+		sort.Slice(points, func(i, j int) bool {
+			return points[i].Offset < points[j].Offset
+		})
+		curve = ScheduleCurve{Period: config.Period, Anchor: config.Anchor, Points: points}
+	default:
+		return nil, types.NewDomainError(types.DomainControl, types.ErrInvalidConfig, "unknown modulation curve", nil).
+			WithContext("kind", config.Kind)
+	}
+
+	if config.Min == 0 && config.Max == 0 {
+		return curve, nil
+	}
+	return Clamp(curve, config.Min, config.Max), nil
+}
+
+// Clamp 将曲线倍率限制在[min, max]内, max不大于min时只限制下界
+func Clamp(curve Curve, min, max float64) Curve {
+	return CurveFunc(func(t time.Time) float64 {
+		v := math.Max(curve.Value(t), min)
+		if max > min {
+			v = math.Min(v, max)
+		}
+		return v
+	})
+}
+
+// mod 非负取模
+func mod(a, n int) int {
+	return ((a % n) + n) % n
+}
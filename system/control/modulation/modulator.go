@@ -0,0 +1,249 @@
+// system/control/modulation/modulator.go
+
+package modulation
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultInterval = time.Minute
+)
+
+// Target 被调制的参数
+type Target struct {
+	Name  string                    // 调制目标名, 与配置中的曲线对应
+	Base  float64                   // 基准值
+	Min   float64                   // 调制后取值下限
+	Max   float64                   // 调制后取值上限, 不大于Min时不限制上界
+	Apply func(value float64) error // 写入调制后的值
+}
+
+// Value 调制结果
+type Value struct {
+	Name      string    `json:"name"`
+	Base      float64   `json:"base"`
+	Factor    float64   `json:"factor"`
+	Value     float64   `json:"value"`
+	Timestamp time.Time `json:"timestamp"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Modulator 参数时间调制器
+// 按刷新间隔对每个已绑定且配置了曲线的参数计算 基准值 × 曲线倍率 并写回,
+// 使检测灵敏度、模式阈值和学习率等随日周期、干支周期或自定义时间表变化
+type Modulator struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.ModulationConfig
+
+	// 调制目标与曲线
+	targets map[string]Target
+	curves  map[string]Curve
+
+	// 调制状态
+	state struct {
+		values   map[string]Value
+		ticks    int64
+		failures int64
+	}
+}
+
+// NewModulator 创建参数调制器, 配置中的曲线无效时返回错误
+func NewModulator(config types.ModulationConfig) (*Modulator, error) {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+
+	m := &Modulator{
+		config:  config,
+		targets: make(map[string]Target),
+		curves:  make(map[string]Curve),
+	}
+	m.state.values = make(map[string]Value)
+
+	for name, cfg := range config.Curves {
+		curve, err := NewCurve(cfg)
+		if err != nil {
+			if se, ok := err.(*types.SystemError); ok {
+				se.WithContext("target", name)
+			}
+			return nil, err
+		}
+		m.curves[name] = curve
+	}
+	return m, nil
+}
+
+// Enabled 是否启用调制
+func (m *Modulator) Enabled() bool {
+	return m.config.Enabled
+}
+
+// Bind 绑定调制目标, 同名覆盖
+func (m *Modulator) Bind(target Target) error {
+	if target.Name == "" || target.Apply == nil {
+		return types.NewDomainError(types.DomainControl, types.ErrInvalid, "modulation target requires name and apply", nil)
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.targets[target.Name] = target
+	return nil
+}
+
+// Unbind 解除调制目标, 不恢复基准值
+func (m *Modulator) Unbind(name string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.targets, name)
+	delete(m.state.values, name)
+}
+
+// SetBase 更新调制目标的基准值, 用于在运行中调整配置
+func (m *Modulator) SetBase(name string, base float64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	target, ok := m.targets[name]
+	if !ok {
+		return types.NewDomainError(types.DomainControl, types.ErrNotFound, "modulation target not found", nil).
+			WithContext("target", name)
+	}
+	target.Base = base
+	m.targets[name] = target
+	return nil
+}
+
+// SetCurve 设置调制目标的曲线, 传入nil移除
+func (m *Modulator) SetCurve(name string, curve Curve) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if curve == nil {
+		delete(m.curves, name)
+		return
+	}
+	m.curves[name] = curve
+}
+
+// Run 按刷新间隔运行, 启动时立即刷新一次, 直到上下文取消
+func (m *Modulator) Run(ctx context.Context) {
+	m.Tick(time.Now())
+
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			m.Tick(now)
+		}
+	}
+}
+
+// Start 在协程监管下启动调制循环
+func (m *Modulator) Start(ctx context.Context) {
+	supervisor.Go(ctx, "control.modulation", m.Run)
+}
+
+// Tick 按时刻now刷新所有调制目标, 返回本次调制结果, 按名称排序
+// 某个目标写入失败不影响其余目标, 错误记录在结果中
+func (m *Modulator) Tick(now time.Time) []Value {
+	type pending struct {
+		target Target
+		curve  Curve
+	}
+	m.mu.RLock()
+	list := make([]pending, 0, len(m.targets))
+	for name, target := range m.targets {
+		if curve, ok := m.curves[name]; ok {
+			list = append(list, pending{target: target, curve: curve})
+		}
+	}
+	m.mu.RUnlock()
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].target.Name < list[j].target.Name
+	})
+
+	// 在锁外写入, 目标可能需要获取其他组件的锁
+	values := make([]Value, 0, len(list))
+	failures := 0
+	for _, p := range list {
+		factor := p.curve.Value(now)
+		v := Value{
+			Name:      p.target.Name,
+			Base:      p.target.Base,
+			Factor:    factor,
+			Value:     p.target.clamp(p.target.Base * factor),
+			Timestamp: now,
+		}
+		if err := p.target.Apply(v.Value); err != nil {
+			v.Error = err.Error()
+			failures++
+		}
+		values = append(values, v)
+	}
+
+	m.mu.Lock()
+	m.state.ticks++
+	m.state.failures += int64(failures)
+	for _, v := range values {
+		if _, ok := m.targets[v.Name]; ok {
+			m.state.values[v.Name] = v
+		}
+	}
+	m.mu.Unlock()
+
+	return values
+}
+
+// Values 获取各调制目标最近一次的调制结果, 按名称排序
+func (m *Modulator) Values() []Value {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]Value, 0, len(m.state.values))
+	for _, v := range m.state.values {
+		list = append(list, v)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// GetMetrics 获取调制指标, 包括各目标当前倍率
+func (m *Modulator) GetMetrics() map[string]float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	metrics := map[string]float64{
+		"targets":  float64(len(m.targets)),
+		"curves":   float64(len(m.curves)),
+		"ticks":    float64(m.state.ticks),
+		"failures": float64(m.state.failures),
+	}
+	for name, v := range m.state.values {
+		metrics["factor."+name] = v.Factor
+	}
+	return metrics
+}
+
+// clamp 将调制后的值限制在目标范围内
+func (t Target) clamp(v float64) float64 {
+	if v < t.Min {
+		return t.Min
+	}
+	if t.Max > t.Min && v > t.Max {
+		return t.Max
+	}
+	return v
+}
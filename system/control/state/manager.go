@@ -3,10 +3,10 @@
 package state
 
 import (
-	"fmt"
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 )
 
@@ -178,7 +178,7 @@ func (sm *StateManager) UpdateState(newState *SystemState) error {
 // recordTransition 记录状态转换
 func (sm *StateManager) recordTransition(sourceID, targetID string) {
 	transition := &TransitionData{ // 使用指针
-		ID:        fmt.Sprintf("trans_%d", time.Now().UnixNano()),
+		ID:        core.NewID("trans_"),
 		SourceID:  sourceID,
 		TargetID:  targetID,
 		Type:      "state_update",
@@ -279,9 +279,9 @@ func (sm *StateManager) serializeState(
 }
 
 func generateStateID() string {
-	return fmt.Sprintf("state_%d", time.Now().UnixNano())
+	return core.NewID("state_")
 }
 
 func generateSnapshotID() string {
-	return fmt.Sprintf("snap_%d", time.Now().UnixNano())
+	return core.NewID("snap_")
 }
@@ -0,0 +1,194 @@
+// system/control/tuning/interval.go
+
+package tuning
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultTargetUtilization = 0.5
+	defaultQueueHighWater    = 0.8
+	defaultHealthFloor       = 0.5
+	defaultBackoffFactor     = 2.0
+	defaultRecoveryFactor    = 1.25
+)
+
+// Signals 外部负载信号
+type Signals struct {
+	QueueDepth float64 // 队列占用比例(0-1)
+	Health     float64 // 系统健康度(0-1)
+}
+
+// IntervalTuner 周期间隔调节器
+// 根据处理耗时、队列深度和系统健康度调节间隔: 过载时放大, 空闲时缩小, 并限制在[Min, Max]内
+type IntervalTuner struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.IntervalTuningConfig
+	base   time.Duration
+
+	// 负载信号来源
+	signals func() Signals
+
+	// 调节状态
+	state struct {
+		interval    time.Duration // 当前有效间隔
+		utilization float64       // 最近占用率
+		queueDepth  float64       // 最近队列占用
+		health      float64       // 最近健康度
+		slowdowns   int64         // 放大次数
+		speedups    int64         // 缩小次数
+		lastAdjust  time.Time     // 最后调节时间
+	}
+}
+
+// NewIntervalTuner 创建间隔调节器, base为静态配置的间隔
+func NewIntervalTuner(base time.Duration, config types.IntervalTuningConfig) *IntervalTuner {
+	if base <= 0 {
+		base = time.Second
+	}
+	if config.MinInterval <= 0 {
+		config.MinInterval = base / 4
+	}
+	if config.MaxInterval <= 0 {
+		config.MaxInterval = base * 8
+	}
+	if config.MaxInterval < config.MinInterval {
+		config.MaxInterval = config.MinInterval
+	}
+	if config.TargetUtilization <= 0 || config.TargetUtilization >= 1 {
+		config.TargetUtilization = defaultTargetUtilization
+	}
+	if config.QueueHighWater <= 0 || config.QueueHighWater > 1 {
+		config.QueueHighWater = defaultQueueHighWater
+	}
+	if config.HealthFloor <= 0 {
+		config.HealthFloor = defaultHealthFloor
+	}
+	if config.BackoffFactor <= 1 {
+		config.BackoffFactor = defaultBackoffFactor
+	}
+	if config.RecoveryFactor <= 1 {
+		config.RecoveryFactor = defaultRecoveryFactor
+	}
+
+	t := &IntervalTuner{
+		config: config,
+		base:   base,
+	}
+	t.state.interval = t.clamp(base)
+	t.state.health = 1
+	return t
+}
+
+// SetSignals 设置负载信号来源
+func (t *IntervalTuner) SetSignals(signals func() Signals) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.signals = signals
+}
+
+// Interval 当前有效间隔
+func (t *IntervalTuner) Interval() time.Duration {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.state.interval
+}
+
+// Observe 记录一次处理耗时并返回下一次的间隔
+func (t *IntervalTuner) Observe(elapsed time.Duration) time.Duration {
+	t.mu.RLock()
+	source := t.signals
+	t.mu.RUnlock()
+
+	// 在锁外读取信号, 信号来源可能需要获取其他组件的锁
+	signals := Signals{Health: 1}
+	if source != nil {
+		signals = source()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	current := t.state.interval
+	utilization := float64(elapsed) / float64(current)
+
+	t.state.utilization = utilization
+	t.state.queueDepth = signals.QueueDepth
+	t.state.health = signals.Health
+
+	overloaded := utilization > t.config.TargetUtilization ||
+		signals.QueueDepth >= t.config.QueueHighWater ||
+		signals.Health < t.config.HealthFloor
+	idle := utilization < t.config.TargetUtilization/4 &&
+		signals.QueueDepth < t.config.QueueHighWater/4 &&
+		signals.Health >= t.config.HealthFloor
+
+	next := current
+	switch {
+	case overloaded:
+		next = t.clamp(time.Duration(float64(current) * t.config.BackoffFactor))
+		// 至少保证处理耗时不超过目标占用率
+		if floor := time.Duration(float64(elapsed) / t.config.TargetUtilization); next < floor {
+			next = t.clamp(floor)
+		}
+		if next != current {
+			t.state.slowdowns++
+		}
+	case idle:
+		next = t.clamp(time.Duration(float64(current) / t.config.RecoveryFactor))
+		if next != current {
+			t.state.speedups++
+		}
+	}
+
+	if next != current {
+		t.state.interval = next
+		t.state.lastAdjust = time.Now()
+	}
+	return next
+}
+
+// Reset 恢复为静态配置的间隔
+func (t *IntervalTuner) Reset() {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.state.interval = t.clamp(t.base)
+	t.state.lastAdjust = time.Now()
+}
+
+// GetMetrics 获取调节指标
+func (t *IntervalTuner) GetMetrics() map[string]float64 {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return map[string]float64{
+		"effective_interval_ms": durationMillis(t.state.interval),
+		"base_interval_ms":      durationMillis(t.base),
+		"min_interval_ms":       durationMillis(t.config.MinInterval),
+		"max_interval_ms":       durationMillis(t.config.MaxInterval),
+		"utilization":           t.state.utilization,
+		"queue_depth":           t.state.queueDepth,
+		"health":                t.state.health,
+		"slowdowns":             float64(t.state.slowdowns),
+		"speedups":              float64(t.state.speedups),
+	}
+}
+
+// clamp 将间隔限制在[Min, Max]内
+func (t *IntervalTuner) clamp(d time.Duration) time.Duration {
+	return time.Duration(math.Max(float64(t.config.MinInterval),
+		math.Min(float64(t.config.MaxInterval), float64(d))))
+}
+
+// durationMillis 毫秒数
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
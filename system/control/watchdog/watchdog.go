@@ -0,0 +1,339 @@
+// system/control/watchdog/watchdog.go
+
+package watchdog
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultInterval         = 10 * time.Second
+	defaultMinHealth        = 0.3
+	defaultFailureThreshold = 3
+	defaultInitialBackoff   = 5 * time.Second
+	defaultMaxBackoff       = 5 * time.Minute
+	defaultMaxRetries       = 5
+)
+
+// State 子系统的看门狗状态
+type State string
+
+const (
+	StateHealthy    State = "healthy"    // 运行正常
+	StateFailing    State = "failing"    // 异常, 尚未达到恢复阈值
+	StateRecovering State = "recovering" // 已尝试恢复, 等待生效或下次重试
+	StateDegraded   State = "degraded"   // 恢复重试耗尽, 不再自动恢复
+)
+
+// Target 被监视的子系统
+type Target interface {
+	Status() string  // 运行状态, 运行中为"running"
+	Health() float64 // 健康度(0-1)
+}
+
+// RestoreFunc 恢复子系统
+type RestoreFunc func(name string) error
+
+// Status 子系统的监视状态
+type Status struct {
+	Name        string    `json:"name"`
+	State       State     `json:"state"`
+	Status      string    `json:"status"`       // 最近一次检查的运行状态
+	Health      float64   `json:"health"`       // 最近一次检查的健康度
+	Failures    int       `json:"failures"`     // 连续异常检查次数
+	Retries     int       `json:"retries"`      // 本轮异常中已尝试恢复的次数
+	Recoveries  int64     `json:"recoveries"`   // 累计恢复成功次数
+	LastError   string    `json:"last_error"`   // 最近一次恢复失败的错误
+	LastAttempt time.Time `json:"last_attempt"` // 最近一次尝试恢复的时间
+	NextAttempt time.Time `json:"next_attempt"` // 下次允许尝试恢复的时间
+	LastCheck   time.Time `json:"last_check"`
+}
+
+// Event 看门狗事件
+type Event struct {
+	Type      types.EventType `json:"type"`
+	Timestamp time.Time       `json:"timestamp"`
+	Status    Status          `json:"status"`
+}
+
+// Handler 看门狗事件通知
+type Handler func(event Event)
+
+// watched 被监视的子系统及其状态
+type watched struct {
+	target Target
+	status Status
+}
+
+// Watchdog 子系统看门狗
+// 周期检查子系统的运行状态和健康度, 连续异常达到阈值后调用恢复方法,
+// 恢复按指数退避重试, 重试耗尽后进入降级并停止自动恢复, 直到子系统重新恢复正常
+type Watchdog struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.WatchdogConfig
+
+	// 监视对象与恢复方法
+	targets  map[string]*watched
+	restore  RestoreFunc
+	handlers []Handler
+
+	// 统计
+	checks   int64
+	attempts int64
+}
+
+// New 创建看门狗
+func New(config types.WatchdogConfig) *Watchdog {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.MinHealth <= 0 || config.MinHealth > 1 {
+		config.MinHealth = defaultMinHealth
+	}
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = defaultFailureThreshold
+	}
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = defaultMaxRetries
+	}
+
+	return &Watchdog{
+		config:  config,
+		targets: make(map[string]*watched),
+	}
+}
+
+// Enabled 是否启用
+func (w *Watchdog) Enabled() bool {
+	return w.config.Enabled
+}
+
+// Watch 监视子系统, 同名子系统会被替换
+func (w *Watchdog) Watch(name string, target Target) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.targets[name] = &watched{
+		target: target,
+		status: Status{Name: name, State: StateHealthy},
+	}
+}
+
+// Unwatch 停止监视子系统
+func (w *Watchdog) Unwatch(name string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.targets, name)
+}
+
+// SetRestore 设置恢复方法
+func (w *Watchdog) SetRestore(restore RestoreFunc) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.restore = restore
+}
+
+// OnEvent 注册恢复、恢复成功和降级通知
+func (w *Watchdog) OnEvent(handler Handler) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.handlers = append(w.handlers, handler)
+}
+
+// Run 按检查间隔运行看门狗, 直到上下文取消
+func (w *Watchdog) Run(ctx context.Context) {
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(time.Now())
+		}
+	}
+}
+
+// Start 在协程监管下启动看门狗
+func (w *Watchdog) Start(ctx context.Context) {
+	supervisor.Go(ctx, "control.watchdog", w.Run)
+}
+
+// Check 检查所有子系统一次, 对持续异常的子系统尝试恢复
+// 子系统状态和恢复方法在锁外调用, 它们可能需要获取系统的锁
+func (w *Watchdog) Check(now time.Time) {
+	w.mu.Lock()
+	w.checks++
+	names := make([]string, 0, len(w.targets))
+	for name := range w.targets {
+		names = append(names, name)
+	}
+	restore := w.restore
+	w.mu.Unlock()
+	sort.Strings(names)
+
+	for _, name := range names {
+		w.checkTarget(name, restore, now)
+	}
+}
+
+// checkTarget 检查单个子系统
+func (w *Watchdog) checkTarget(name string, restore RestoreFunc, now time.Time) {
+	w.mu.RLock()
+	entry, ok := w.targets[name]
+	w.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	status, health := entry.target.Status(), entry.target.Health()
+	healthy := status == "running" && health >= w.config.MinHealth
+
+	w.mu.Lock()
+	s := &entry.status
+	s.Status, s.Health, s.LastCheck = status, health, now
+
+	if healthy {
+		recovered := s.State == StateRecovering || s.State == StateDegraded
+		if recovered {
+			s.Recoveries++
+		}
+		s.State, s.Failures, s.Retries, s.LastError = StateHealthy, 0, 0, ""
+		s.NextAttempt = time.Time{}
+		snapshot := *s
+		w.mu.Unlock()
+		if recovered {
+			w.notify(Event{Type: types.EventSubsystemRecovered, Timestamp: now, Status: snapshot})
+		}
+		return
+	}
+
+	s.Failures++
+	switch {
+	case s.State == StateDegraded:
+		w.mu.Unlock()
+		return
+	case s.Failures < w.config.FailureThreshold:
+		if s.State == StateHealthy {
+			s.State = StateFailing
+		}
+		w.mu.Unlock()
+		return
+	case now.Before(s.NextAttempt) || restore == nil:
+		w.mu.Unlock()
+		return
+	case s.Retries >= w.config.MaxRetries:
+		s.State = StateDegraded
+		snapshot := *s
+		w.mu.Unlock()
+		w.notify(Event{Type: types.EventSubsystemDegraded, Timestamp: now, Status: snapshot})
+		return
+	}
+
+	s.Retries++
+	s.State = StateRecovering
+	s.LastAttempt = now
+	s.NextAttempt = now.Add(w.backoff(s.Retries))
+	w.attempts++
+	w.mu.Unlock()
+
+	err := restore(name)
+
+	w.mu.Lock()
+	if err != nil {
+		s.LastError = err.Error()
+	} else {
+		s.LastError = ""
+	}
+	snapshot := *s
+	w.mu.Unlock()
+	w.notify(Event{Type: types.EventSubsystemRecovering, Timestamp: now, Status: snapshot})
+}
+
+// Status 获取所有子系统的监视状态, 按名称排序
+func (w *Watchdog) Status() []Status {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	list := make([]Status, 0, len(w.targets))
+	for _, entry := range w.targets {
+		list = append(list, entry.status)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Degraded 获取处于降级的子系统名称, 按名称排序
+func (w *Watchdog) Degraded() []string {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	names := make([]string, 0)
+	for name, entry := range w.targets {
+		if entry.status.State == StateDegraded {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetMetrics 获取看门狗指标
+func (w *Watchdog) GetMetrics() map[string]float64 {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	metrics := map[string]float64{
+		"watched":  float64(len(w.targets)),
+		"checks":   float64(w.checks),
+		"attempts": float64(w.attempts),
+	}
+	for _, entry := range w.targets {
+		metrics[string(entry.status.State)]++
+	}
+	return metrics
+}
+
+// backoff 第retry次重试后的等待时间
+func (w *Watchdog) backoff(retry int) time.Duration {
+	d := w.config.InitialBackoff
+	for i := 1; i < retry && d < w.config.MaxBackoff; i++ {
+		d *= 2
+	}
+	if d > w.config.MaxBackoff {
+		d = w.config.MaxBackoff
+	}
+	return d
+}
+
+// notify 通知事件处理器
+func (w *Watchdog) notify(event Event) {
+	w.mu.RLock()
+	handlers := make([]Handler, len(w.handlers))
+	copy(handlers, w.handlers)
+	w.mu.RUnlock()
+
+	for _, h := range handlers {
+		h(event)
+	}
+}
@@ -0,0 +1,49 @@
+// system/correlation.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/monitor/correlation"
+)
+
+// startCorrelation 将模式检测事件接入异常关联引擎
+func (s *System) startCorrelation() {
+	if s.monitor.GetCorrelationEngine() == nil {
+		return
+	}
+	if detector := s.meta.GetDetector(); detector != nil && detector != s.hooks.correlated {
+		s.correlateDetections(detector)
+		s.hooks.correlated = detector
+	}
+}
+
+// correlateDetections 接入检测器的检测事件
+func (s *System) correlateDetections(detector *emergence.PatternDetector) {
+	engine := s.monitor.GetCorrelationEngine()
+	if engine == nil {
+		return
+	}
+
+	namespace := detector.GetNamespace()
+	detector.OnDetectionEvent(func(events []emergence.DetectionEvent) {
+		for _, event := range events {
+			engine.Observe(correlation.Event{
+				Kind:       correlation.EventPattern,
+				Source:     namespace,
+				Reference:  event.PatternID,
+				Category:   event.Type,
+				Timestamp:  event.Timestamp,
+				Confidence: event.Confidence,
+			})
+		}
+	})
+}
+
+// observeTransform 记录模型转换调用, 供异常关联使用
+func (s *System) observeTransform(name string, pattern model.TransformPattern, err error) {
+	if engine := s.monitor.GetCorrelationEngine(); engine != nil {
+		engine.ObserveTransform(name, pattern, err)
+	}
+}
@@ -0,0 +1,39 @@
+// system/counterfactual.go
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// EvaluateCounterfactual 评估一次适应决策的处理效应: 在模拟沙箱中回放决策前的状态而不执行动作, 与实际结果比较
+func (s *System) EvaluateCounterfactual(ctx context.Context, decision adaptation.StrategyEvent) (adaptation.Counterfactual, error) {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return adaptation.Counterfactual{}, types.NewSystemError(types.ErrNotFound, "adaptation strategy not available", nil)
+	}
+	return strategy.EvaluateCounterfactual(ctx, decision)
+}
+
+// EvaluateCounterfactuals 评估since之后的适应决策, 按策略和规则汇总估计的处理效应
+func (s *System) EvaluateCounterfactuals(ctx context.Context, since time.Time) (*adaptation.CounterfactualReport, error) {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "adaptation strategy not available", nil)
+	}
+	return strategy.EvaluateCounterfactuals(ctx, since)
+}
+
+// SetCounterfactualSimulator 设置反事实回放的模拟沙箱, nil时恢复默认的漂移沙箱
+func (s *System) SetCounterfactualSimulator(simulator adaptation.Simulator) error {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return types.NewSystemError(types.ErrNotFound, "adaptation strategy not available", nil)
+	}
+	strategy.SetSimulator(simulator)
+	return nil
+}
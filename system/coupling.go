@@ -0,0 +1,69 @@
+// system/coupling.go
+
+package system
+
+import (
+	"fmt"
+
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// startCouplingEvents 将统一场的耦合阈值越过接入系统事件
+func (s *System) startCouplingEvents() {
+	f := s.meta.GetField()
+	if f == nil || f == s.hooks.coupled {
+		return
+	}
+	f.OnCouplingCrossing(func(crossing field.CouplingCrossing) {
+		direction := "below"
+		if crossing.Rising {
+			direction = "above"
+		}
+		s.HandleEvent(types.SystemEvent{
+			Type:      types.EventCouplingThreshold,
+			Source:    crossing.Coupling,
+			Timestamp: crossing.Timestamp,
+			Message:   fmt.Sprintf("coupling strength crossed %s %.3f", direction, crossing.Threshold),
+			Priority:  types.PriorityNormal,
+			Data:      crossing,
+		})
+	})
+	s.hooks.coupled = f
+}
+
+// CouplingGraph 获取统一场的耦合网络
+func (s *System) CouplingGraph() (field.CouplingGraph, error) {
+	f := s.meta.GetField()
+	if f == nil {
+		return field.CouplingGraph{}, types.NewSystemError(types.ErrState, "unified field not available", nil)
+	}
+	return f.CouplingGraph(), nil
+}
+
+// AddCoupling 在统一场的两个组件间添加耦合, 返回耦合ID
+func (s *System) AddCoupling(source, target string, strength float64, bidirectional bool) (string, error) {
+	f := s.meta.GetField()
+	if f == nil {
+		return "", types.NewSystemError(types.ErrState, "unified field not available", nil)
+	}
+	return f.AddCoupling(source, target, strength, bidirectional)
+}
+
+// RemoveCoupling 移除统一场的耦合
+func (s *System) RemoveCoupling(id string) error {
+	f := s.meta.GetField()
+	if f == nil {
+		return types.NewSystemError(types.ErrState, "unified field not available", nil)
+	}
+	return f.RemoveCoupling(id)
+}
+
+// SetCouplingStrength 调整统一场的耦合强度
+func (s *System) SetCouplingStrength(id string, strength float64) error {
+	f := s.meta.GetField()
+	if f == nil {
+		return types.NewSystemError(types.ErrState, "unified field not available", nil)
+	}
+	return f.SetCouplingStrength(id, strength)
+}
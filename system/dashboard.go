@@ -0,0 +1,160 @@
+// system/dashboard.go
+
+package system
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/dashboard"
+	"github.com/Corphon/daoflow/system/integrations"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// EnableDashboard 在addr上启动仪表盘, 展示健康度、能量、活跃模式、异常和适应决策
+// 仪表盘作为输出端接入分发器, 随Shutdown关闭
+func (s *System) EnableDashboard(addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.dashboard != nil {
+		return types.NewSystemError(types.ErrExists, "dashboard already enabled", nil).
+			WithContext("addr", s.dashboard.Addr())
+	}
+
+	var cfg types.DashboardConfig
+	if s.config.DashboardConfig != nil {
+		cfg = *s.config.DashboardConfig
+	}
+
+	server, err := dashboard.NewServer(dashboardProvider{s}, cfg)
+	if err != nil {
+		return err
+	}
+	if err := s.outputs.Register(server, integrations.RetryPolicy{MaxAttempts: 1}); err != nil {
+		return err
+	}
+	if err := server.Start(s.ctx, addr); err != nil {
+		return err
+	}
+
+	s.dashboard = server
+	return nil
+}
+
+// Dashboard 返回仪表盘服务, 未启用时为nil
+func (s *System) Dashboard() *dashboard.Server {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.dashboard
+}
+
+// stopDashboard 停止仪表盘服务
+func (s *System) stopDashboard() {
+	if server := s.Dashboard(); server != nil {
+		if err := server.Stop(); err != nil {
+			s.recordOutputError(fmt.Errorf("dashboard: %w", err))
+		}
+	}
+}
+
+// dashboardProvider 仪表盘数据来源
+type dashboardProvider struct {
+	s *System
+}
+
+// Status 系统健康快照
+func (p dashboardProvider) Status() dashboard.Status {
+	s := p.s
+
+	s.mu.RLock()
+	uptime := time.Since(s.state.startTime)
+	errorCount := len(s.state.errors)
+	s.mu.RUnlock()
+
+	// 核心引擎初始化前能量系统尚未创建
+	energy := 0.0
+	if s.GetEnergySystem() != nil {
+		energy = s.GetEnergy()
+	}
+
+	loops := s.Supervisor().GetMetrics()
+	warmup := s.WarmupStatus()
+
+	return dashboard.Status{
+		State:      s.GetStatus(),
+		Health:     s.currentHealth(),
+		Energy:     energy,
+		Uptime:     uptime,
+		ErrorCount: errorCount,
+		Goroutines: runtime.NumGoroutine(),
+		Subsystems: s.GetSubsystemStatus(),
+		Metrics: map[string]float64{
+			"event_queue_depth": s.eventQueueDepth(),
+			"models":            float64(len(s.ActiveModels())),
+			"loop_crashes":      float64(loops["crashes"].(int64)),
+			"loops_failed":      float64(loops["failed"].(int)),
+			"warmup":            boolMetric(warmup.Active),
+			"warmup_progress":   warmup.Progress,
+		},
+	}
+}
+
+// Patterns 全局和各命名空间的活跃模式
+func (p dashboardProvider) Patterns() []emergence.EmergentPattern {
+	patterns := make([]emergence.EmergentPattern, 0)
+	for _, detector := range p.s.patternDetectors() {
+		patterns = append(patterns, detector.GetActivePatternsSnapshot().Patterns()...)
+	}
+	return patterns
+}
+
+// ExportPatterns 分页导出活跃模式
+func (p dashboardProvider) ExportPatterns(q emergence.ExportQuery) ([]emergence.PatternRecord, string, error) {
+	return p.s.ExportPatterns(q)
+}
+
+// ExportEvolution 分页导出模式演化历史
+func (p dashboardProvider) ExportEvolution(q emergence.ExportQuery) ([]emergence.EvolutionRecord, string, error) {
+	return p.s.ExportEvolution(q)
+}
+
+// MetricValues 仪表盘状态指标和监控指标, 单位见model.DefaultMetricRegistry
+func (p dashboardProvider) MetricValues() map[string]float64 {
+	values := make(map[string]float64)
+	if monitor := p.s.Monitor(); monitor != nil {
+		for name, v := range monitor.MetricValues() {
+			values[name] = v
+		}
+	}
+	status := p.Status()
+	for name, v := range status.Metrics {
+		values[name] = v
+	}
+	values["health"] = status.Health
+	values["uptime"] = status.Uptime.Seconds()
+	values["errors"] = float64(status.ErrorCount)
+	return values
+}
+
+// statusMetrics 仪表盘状态指标定义
+var statusMetrics = []model.MetricDesc{
+	{Name: "health", Unit: model.UnitRatio, Description: "系统健康度", Bounded: true, Min: 0, Max: 1},
+	{Name: "uptime", Unit: model.UnitSeconds, Description: "系统运行时间"},
+	{Name: "errors", Unit: model.UnitCount, Description: "记录的错误数"},
+	{Name: "event_queue_depth", Unit: model.UnitRatio, Description: "事件队列占用比例", Bounded: true, Min: 0, Max: 1},
+	{Name: "models", Unit: model.UnitCount, Description: "活跃模型数"},
+	{Name: "loop_crashes", Unit: model.UnitCount, Kind: model.MetricCounter, Description: "受监管协程的崩溃次数"},
+	{Name: "loops_failed", Unit: model.UnitCount, Description: "超出重启预算而停止的受监管协程数"},
+	{Name: "warmup", Unit: model.UnitCount, Description: "是否处于启动预热期(1为是)", Bounded: true, Min: 0, Max: 1},
+	{Name: "warmup_progress", Unit: model.UnitRatio, Description: "启动预热进度", Bounded: true, Min: 0, Max: 1},
+}
+
+func init() {
+	if err := model.DefaultMetricRegistry().Register(statusMetrics...); err != nil {
+		panic(err)
+	}
+}
@@ -0,0 +1,211 @@
+// daoflow 仪表盘: 启动时拉取一次JSON接口, 之后跟随 /api/stream 实时事件更新
+(function () {
+  "use strict";
+
+  var MAX_FEED = 50;
+  var samples = [];
+  var palette = ["#5ec962", "#21918c", "#fde725", "#ff7f50", "#b07aa1", "#4e79a7", "#f28e2b", "#e15759"];
+  var typeColors = {};
+
+  function $(id) { return document.getElementById(id); }
+
+  function getJSON(path) {
+    return fetch(path, { cache: "no-store" }).then(function (r) { return r.json(); });
+  }
+
+  function fmt(n, digits) {
+    return typeof n === "number" ? n.toFixed(digits === undefined ? 2 : digits) : "-";
+  }
+
+  function fmtDuration(ns) {
+    var s = Math.floor(ns / 1e9);
+    var h = Math.floor(s / 3600), m = Math.floor((s % 3600) / 60);
+    return h + "h " + m + "m " + (s % 60) + "s";
+  }
+
+  function fmtTime(t) {
+    var d = new Date(t);
+    return isNaN(d) ? "" : d.toLocaleTimeString();
+  }
+
+  function colorFor(type) {
+    if (!typeColors[type]) {
+      typeColors[type] = palette[Object.keys(typeColors).length % palette.length];
+    }
+    return typeColors[type];
+  }
+
+  function setupCanvas(canvas) {
+    var ratio = window.devicePixelRatio || 1;
+    var width = canvas.clientWidth, height = canvas.getAttribute("height");
+    canvas.width = width * ratio;
+    canvas.height = height * ratio;
+    var ctx = canvas.getContext("2d");
+    ctx.setTransform(ratio, 0, 0, ratio, 0, 0);
+    return { ctx: ctx, width: width, height: +height };
+  }
+
+  function renderStatus(status) {
+    if (!status) { return; }
+    var badge = $("state");
+    badge.textContent = status.state || "unknown";
+    badge.className = "badge " + (status.state || "");
+    $("health").textContent = fmt((status.health || 0) * 100, 1) + "%";
+    $("energy").textContent = fmt(status.energy);
+    $("uptime").textContent = fmtDuration(status.uptime || 0);
+    $("errors").textContent = status.error_count || 0;
+    $("goroutines").textContent = status.goroutines || 0;
+
+    var rows = Object.keys(status.subsystems || {}).sort().map(function (name) {
+      return "<tr><td>" + name + "</td><td>" + status.subsystems[name] + "</td></tr>";
+    });
+    $("subsystems").innerHTML = rows.join("");
+  }
+
+  function drawSeries(ctx, points, key, lo, hi, area, color) {
+    if (points.length < 2) { return; }
+    var t0 = points[0].t, t1 = points[points.length - 1].t || t0 + 1;
+    ctx.strokeStyle = color;
+    ctx.lineWidth = 1.5;
+    ctx.beginPath();
+    points.forEach(function (p, i) {
+      var x = area.x + (p.t - t0) / (t1 - t0 || 1) * area.w;
+      var y = area.y + area.h - (p[key] - lo) / (hi - lo || 1) * area.h;
+      if (i === 0) { ctx.moveTo(x, y); } else { ctx.lineTo(x, y); }
+    });
+    ctx.stroke();
+  }
+
+  function renderChart() {
+    var c = setupCanvas($("chart"));
+    var ctx = c.ctx;
+    ctx.clearRect(0, 0, c.width, c.height);
+    var area = { x: 40, y: 10, w: c.width - 80, h: c.height - 30 };
+
+    var points = samples.map(function (s) {
+      return { t: new Date(s.timestamp).getTime(), health: s.health, energy: s.energy };
+    });
+    var eLo = Infinity, eHi = -Infinity;
+    points.forEach(function (p) { eLo = Math.min(eLo, p.energy); eHi = Math.max(eHi, p.energy); });
+    if (eLo === eHi) { eLo -= 1; eHi += 1; }
+
+    ctx.strokeStyle = "#2a303c";
+    ctx.fillStyle = "#8891a5";
+    ctx.font = "11px sans-serif";
+    for (var i = 0; i <= 4; i++) {
+      var y = area.y + area.h * i / 4;
+      ctx.beginPath(); ctx.moveTo(area.x, y); ctx.lineTo(area.x + area.w, y); ctx.stroke();
+      ctx.fillText((100 - i * 25) + "%", 4, y + 4);
+      ctx.fillText(fmt(eHi - (eHi - eLo) * i / 4, 1), area.x + area.w + 6, y + 4);
+    }
+
+    drawSeries(ctx, points, "health", 0, 1, area, "#5ec962");
+    drawSeries(ctx, points, "energy", eLo, eHi, area, "#fde725");
+
+    ctx.fillStyle = "#5ec962"; ctx.fillText("health", area.x, c.height - 4);
+    ctx.fillStyle = "#fde725"; ctx.fillText("energy", area.x + 60, c.height - 4);
+  }
+
+  function renderGraph(graph) {
+    var c = setupCanvas($("graph"));
+    var ctx = c.ctx;
+    ctx.clearRect(0, 0, c.width, c.height);
+    var nodes = graph.nodes || [], edges = graph.edges || [];
+
+    if (nodes.length === 0) {
+      ctx.fillStyle = "#8891a5";
+      ctx.fillText("no active patterns", 10, 20);
+      $("graph-legend").innerHTML = "";
+      return;
+    }
+
+    // 按类型分组排布在圆周上, 同类模式相邻
+    nodes.sort(function (a, b) { return a.type < b.type ? -1 : a.type > b.type ? 1 : 0; });
+    var cx = c.width / 2, cy = c.height / 2, r = Math.min(cx, cy) - 30;
+    var pos = {};
+    nodes.forEach(function (n, i) {
+      var a = 2 * Math.PI * i / nodes.length - Math.PI / 2;
+      pos[n.id] = { x: cx + r * Math.cos(a), y: cy + r * Math.sin(a) };
+    });
+
+    edges.forEach(function (e) {
+      var s = pos[e.source], t = pos[e.target];
+      if (!s || !t) { return; }
+      ctx.strokeStyle = e.relation === "shared_component" ? "rgba(255,127,80,0.8)" : "rgba(136,145,165,0.35)";
+      ctx.lineWidth = Math.max(0.5, Math.min(4, e.weight * 2));
+      ctx.beginPath(); ctx.moveTo(s.x, s.y); ctx.lineTo(t.x, t.y); ctx.stroke();
+    });
+
+    nodes.forEach(function (n) {
+      var p = pos[n.id];
+      ctx.fillStyle = colorFor(n.type);
+      ctx.beginPath();
+      ctx.arc(p.x, p.y, 4 + Math.min(10, Math.sqrt(Math.max(0, n.strength)) * 4), 0, 2 * Math.PI);
+      ctx.fill();
+    });
+
+    $("graph-legend").innerHTML = Object.keys(typeColors).map(function (type) {
+      return "<span><i style=\"background:" + typeColors[type] + "\"></i>" + type + "</span>";
+    }).join("") + "<span>" + nodes.length + " patterns, " + edges.length + " links</span>";
+  }
+
+  function feedItem(out) {
+    var li = document.createElement("li");
+    var labels = out.labels || {};
+    var title = labels.type || out.kind;
+    if (labels.metric) { title += " · " + labels.metric; }
+    if (labels.strategy) { title += " · " + labels.strategy; }
+    li.innerHTML = "<span class=\"time\">" + fmtTime(out.timestamp) + "</span>" +
+      "<span class=\"title\"></span><span class=\"score\">" + fmt(out.score) + "</span>";
+    li.querySelector(".title").textContent = title;
+    return li;
+  }
+
+  function prepend(listId, out) {
+    var list = $(listId);
+    list.insertBefore(feedItem(out), list.firstChild);
+    while (list.children.length > MAX_FEED) { list.removeChild(list.lastChild); }
+  }
+
+  function fill(listId, outputs) {
+    var list = $(listId);
+    list.innerHTML = "";
+    (outputs || []).slice(0, MAX_FEED).forEach(function (out) { list.appendChild(feedItem(out)); });
+  }
+
+  function refreshPatterns() {
+    getJSON("api/patterns").then(renderGraph).catch(function () {});
+  }
+
+  function connect() {
+    var source = new EventSource("api/stream");
+    source.onopen = function () { $("live").textContent = "live"; $("live").className = "live on"; };
+    source.onerror = function () { $("live").textContent = "reconnecting"; $("live").className = "live"; };
+
+    source.addEventListener("sample", function (e) {
+      var data = JSON.parse(e.data);
+      samples.push({ timestamp: data.timestamp, health: data.health, energy: data.energy });
+      if (samples.length > 300) { samples.shift(); }
+      renderStatus(data.status);
+      renderChart();
+    });
+    source.addEventListener("anomaly", function (e) { prepend("anomalies", JSON.parse(e.data)); });
+    source.addEventListener("decision", function (e) { prepend("decisions", JSON.parse(e.data)); });
+    source.addEventListener("pattern", refreshPatterns);
+  }
+
+  Promise.all([
+    getJSON("api/status"), getJSON("api/energy"), getJSON("api/anomalies"), getJSON("api/decisions")
+  ]).then(function (res) {
+    renderStatus(res[0]);
+    samples = res[1] || [];
+    renderChart();
+    fill("anomalies", res[2]);
+    fill("decisions", res[3]);
+  }).catch(function () {});
+
+  refreshPatterns();
+  setInterval(refreshPatterns, 10000);
+  window.addEventListener("resize", function () { renderChart(); refreshPatterns(); });
+  connect();
+})();
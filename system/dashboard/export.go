@@ -0,0 +1,86 @@
+// system/dashboard/export.go
+
+package dashboard
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 批量导出响应
+const (
+	ndjsonContentType = "application/x-ndjson"
+	NextCursorHeader  = "X-Next-Cursor" // 下一页游标, 为空表示已取完
+)
+
+// handleExport 以NDJSON分页输出导出记录
+// 查询参数: since(RFC3339), type(可重复), tag(key=value或key, 可重复), cursor, limit
+func handleExport[T any](export func(emergence.ExportQuery) ([]T, string, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		q, err := parseExportQuery(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		records, next, err := export(q)
+		if err != nil {
+			http.Error(w, exportErrorMessage(err), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", ndjsonContentType)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set(NextCursorHeader, next)
+		enc := json.NewEncoder(w)
+		for _, record := range records {
+			if err := enc.Encode(record); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// parseExportQuery 解析导出查询参数
+func parseExportQuery(r *http.Request) (emergence.ExportQuery, error) {
+	values := r.URL.Query()
+	q := emergence.ExportQuery{
+		Types:  values["type"],
+		Tags:   types.ParseTagFilter(values["tag"]),
+		Cursor: values.Get("cursor"),
+	}
+	if since := values.Get("since"); since != "" {
+		t, err := time.Parse(time.RFC3339Nano, since)
+		if err != nil {
+			return q, err
+		}
+		q.Since = t
+	}
+	if limit := values.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil {
+			return q, err
+		}
+		q.Limit = n
+	}
+	return q, nil
+}
+
+// exportErrorMessage 导出错误的响应消息, 不含堆栈
+func exportErrorMessage(err error) string {
+	var sysErr *types.SystemError
+	if errors.As(err, &sysErr) && sysErr.Message != "" {
+		return sysErr.Message
+	}
+	return err.Error()
+}
@@ -0,0 +1,199 @@
+// system/dashboard/handlers.go
+
+package dashboard
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/system/integrations"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+)
+
+// 实时流心跳间隔, 防止代理断开空闲连接
+const streamHeartbeat = 15 * time.Second
+
+// PatternNode 模式图节点
+type PatternNode struct {
+	ID        string    `json:"id"`
+	Namespace string    `json:"namespace,omitempty"`
+	Type      string    `json:"type"`
+	Strength  float64   `json:"strength"`
+	Stability float64   `json:"stability"`
+	Energy    float64   `json:"energy"`
+	Formation time.Time `json:"formation"`
+}
+
+// PatternEdge 模式图边
+type PatternEdge struct {
+	Source   string  `json:"source"`
+	Target   string  `json:"target"`
+	Relation string  `json:"relation"` // shared_component, same_type
+	Weight   float64 `json:"weight"`
+}
+
+// PatternGraph 活跃模式图
+type PatternGraph struct {
+	Nodes []PatternNode `json:"nodes"`
+	Edges []PatternEdge `json:"edges"`
+}
+
+// BuildPatternGraph 构建模式图
+// 共享组件的模式相连; 同一命名空间的同类模式按强度相近程度相连
+func BuildPatternGraph(patterns []emergence.EmergentPattern) PatternGraph {
+	sort.Slice(patterns, func(i, j int) bool {
+		return patterns[i].ID < patterns[j].ID
+	})
+
+	graph := PatternGraph{
+		Nodes: make([]PatternNode, 0, len(patterns)),
+		Edges: make([]PatternEdge, 0),
+	}
+	for _, p := range patterns {
+		graph.Nodes = append(graph.Nodes, PatternNode{
+			ID:        p.ID,
+			Namespace: p.Namespace,
+			Type:      p.Type,
+			Strength:  p.Strength,
+			Stability: p.Stability,
+			Energy:    p.Energy,
+			Formation: p.Formation,
+		})
+	}
+
+	for i := 0; i < len(patterns); i++ {
+		for j := i + 1; j < len(patterns); j++ {
+			a, b := &patterns[i], &patterns[j]
+			if weight, ok := sharedComponentWeight(a, b); ok {
+				graph.Edges = append(graph.Edges, PatternEdge{
+					Source: a.ID, Target: b.ID, Relation: "shared_component", Weight: weight,
+				})
+				continue
+			}
+			if a.Type == b.Type && a.Namespace == b.Namespace {
+				graph.Edges = append(graph.Edges, PatternEdge{
+					Source:   a.ID,
+					Target:   b.ID,
+					Relation: "same_type",
+					Weight:   1 / (1 + math.Abs(a.Strength-b.Strength)),
+				})
+			}
+		}
+	}
+	return graph
+}
+
+// sharedComponentWeight 共享组件的最小权重之和
+func sharedComponentWeight(a, b *emergence.EmergentPattern) (float64, bool) {
+	weights := make(map[string]float64, len(a.Components))
+	for _, c := range a.Components {
+		if c.ID != "" {
+			weights[c.ID] = c.Weight
+		}
+	}
+
+	total, shared := 0.0, false
+	for _, c := range b.Components {
+		if w, ok := weights[c.ID]; ok && c.ID != "" {
+			total += math.Min(w, c.Weight)
+			shared = true
+		}
+	}
+	return total, shared
+}
+
+// handleStatus 当前健康快照
+func (s *Server) handleStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	status := s.data.status
+	s.mu.RUnlock()
+	writeJSON(w, status)
+}
+
+// handleEnergy 健康和能量采样历史
+func (s *Server) handleEnergy(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	samples := append([]Sample{}, s.data.samples...)
+	s.mu.RUnlock()
+	writeJSON(w, samples)
+}
+
+// handlePatterns 活跃模式图
+func (s *Server) handlePatterns(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, BuildPatternGraph(s.provider.Patterns()))
+}
+
+// handleAnomalies 最近异常, 新的在前
+func (s *Server) handleAnomalies(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	list := reversed(s.data.anomalies)
+	s.mu.RUnlock()
+	writeJSON(w, list)
+}
+
+// handleDecisions 最近适应决策, 新的在前
+func (s *Server) handleDecisions(w http.ResponseWriter, r *http.Request) {
+	s.mu.RLock()
+	list := reversed(s.data.decisions)
+	s.mu.RUnlock()
+	writeJSON(w, list)
+}
+
+// handleStream 以Server-Sent Events推送实时更新
+func (s *Server) handleStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	updates := s.subscribe()
+	defer s.unsubscribe(updates)
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case update := <-updates:
+			data, err := json.Marshal(update.Data)
+			if err != nil {
+				continue
+			}
+			fmt.Fprintf(w, "event: %s\ndata: %s\n\n", update.Type, data)
+		}
+		flusher.Flush()
+	}
+}
+
+// writeJSON 输出JSON响应
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+// reversed 倒序复制
+func reversed(list []integrations.Output) []integrations.Output {
+	out := make([]integrations.Output, len(list))
+	for i, item := range list {
+		out[len(list)-1-i] = item
+	}
+	return out
+}
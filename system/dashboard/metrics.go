@@ -0,0 +1,143 @@
+// system/dashboard/metrics.go
+
+package dashboard
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// MetricsSource 数值指标来源, Provider同时实现该接口时启用指标端点
+// 指标名称在model.DefaultMetricRegistry中登记单位、范围和说明, 输出时统一换算为基本单位
+type MetricsSource interface {
+	// MetricValues 当前指标值, 以登记的单位表示
+	MetricValues() map[string]float64
+}
+
+// prometheusPrefix Prometheus指标名称前缀
+const prometheusPrefix = "daoflow_"
+
+// MetricValue 换算为基本单位的指标值
+type MetricValue struct {
+	Name        string           `json:"name"`
+	Value       *float64         `json:"value"` // NaN或Inf时为null
+	Unit        model.Unit       `json:"unit"`  // 基本单位
+	Kind        model.MetricKind `json:"kind"`
+	Description string           `json:"description,omitempty"`
+	Registered  bool             `json:"registered"`             // 是否已登记定义
+	OutOfRange  bool             `json:"out_of_range,omitempty"` // 超出登记的取值范围
+	raw         float64
+}
+
+// CollectMetricValues 按名称排序的指标值, 已登记的指标换算为基本单位
+func CollectMetricValues(registry *model.MetricRegistry, values map[string]float64) []MetricValue {
+	result := make([]MetricValue, 0, len(values))
+	for name, v := range values {
+		base, unit, desc, ok := registry.Normalize(name, v)
+		mv := MetricValue{
+			Name:        name,
+			Unit:        unit,
+			Kind:        desc.Kind,
+			Description: desc.Description,
+			Registered:  ok,
+			OutOfRange:  ok && !desc.InRange(v),
+			raw:         base,
+		}
+		if !math.IsNaN(base) && !math.IsInf(base, 0) {
+			value := base
+			mv.Value = &value
+		}
+		result = append(result, mv)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+// handleMetrics 以JSON输出换算为基本单位的指标
+func (s *Server) handleMetrics(source MetricsSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, CollectMetricValues(model.DefaultMetricRegistry(), source.MetricValues()))
+	}
+}
+
+// handlePrometheus 以Prometheus文本格式输出指标
+func (s *Server) handlePrometheus(source MetricsSource) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		WritePrometheus(w, CollectMetricValues(model.DefaultMetricRegistry(), source.MetricValues()))
+	}
+}
+
+// WritePrometheus 以Prometheus文本格式写出指标, 名称带基本单位后缀, 计数器带_total后缀
+func WritePrometheus(w io.Writer, values []MetricValue) {
+	var b strings.Builder
+	for _, mv := range values {
+		name := PrometheusName(mv.Name, mv.Unit, mv.Kind)
+		kind := "gauge"
+		if mv.Kind == model.MetricCounter {
+			kind = "counter"
+		}
+		if mv.Description != "" {
+			fmt.Fprintf(&b, "# HELP %s %s\n", name, escapeHelp(mv.Description))
+		}
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, kind)
+		fmt.Fprintf(&b, "%s %s\n", name, formatPrometheusValue(mv.raw))
+	}
+	io.WriteString(w, b.String())
+}
+
+// PrometheusName 指标的Prometheus名称: 前缀、合法字符、单位后缀
+func PrometheusName(name string, unit model.Unit, kind model.MetricKind) string {
+	var b strings.Builder
+	b.WriteString(prometheusPrefix)
+	for _, r := range name {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_' {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	out := b.String()
+
+	var suffix string
+	switch unit.Base() {
+	case model.UnitSeconds:
+		suffix = "_seconds"
+	case model.UnitBytes:
+		suffix = "_bytes"
+	case model.UnitRatio:
+		suffix = "_ratio"
+	}
+	if suffix != "" && !strings.HasSuffix(out, suffix) {
+		out += suffix
+	}
+	if kind == model.MetricCounter && !strings.HasSuffix(out, "_total") {
+		out += "_total"
+	}
+	return out
+}
+
+// formatPrometheusValue 格式化样本值
+func formatPrometheusValue(v float64) string {
+	switch {
+	case math.IsNaN(v):
+		return "NaN"
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+// escapeHelp 转义HELP文本中的反斜杠和换行
+func escapeHelp(s string) string {
+	return strings.NewReplacer(`\`, `\\`, "\n", `\n`).Replace(s)
+}
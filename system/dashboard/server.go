@@ -0,0 +1,332 @@
+// system/dashboard/server.go
+
+package dashboard
+
+import (
+	"context"
+	"embed"
+	"errors"
+	"io/fs"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/integrations"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultSampleInterval = 2 * time.Second
+	defaultHistorySize    = 300
+	defaultRecentItems    = 100
+	subscriberBuffer      = 64
+)
+
+// SinkName 仪表盘作为输出端注册时的名称
+const SinkName = "dashboard"
+
+//go:embed assets
+var assets embed.FS
+
+// Status 系统健康快照
+type Status struct {
+	State      string             `json:"state"`       // 系统状态
+	Health     float64            `json:"health"`      // 健康度(0-1)
+	Energy     float64            `json:"energy"`      // 系统能量
+	Uptime     time.Duration      `json:"uptime"`      // 运行时间
+	ErrorCount int                `json:"error_count"` // 错误计数
+	Goroutines int                `json:"goroutines"`  // 协程数
+	Subsystems map[string]string  `json:"subsystems"`  // 子系统状态
+	Metrics    map[string]float64 `json:"metrics"`     // 附加指标
+}
+
+// Sample 健康和能量采样点
+type Sample struct {
+	Timestamp time.Time `json:"timestamp"`
+	Health    float64   `json:"health"`
+	Energy    float64   `json:"energy"`
+}
+
+// Provider 仪表盘数据来源
+type Provider interface {
+	// Status 当前系统健康快照
+	Status() Status
+	// Patterns 当前活跃模式
+	Patterns() []emergence.EmergentPattern
+}
+
+// Exporter 批量导出数据来源, Provider同时实现该接口时启用导出端点
+type Exporter interface {
+	// ExportPatterns 分页导出活跃模式
+	ExportPatterns(q emergence.ExportQuery) ([]emergence.PatternRecord, string, error)
+	// ExportEvolution 分页导出模式演化历史
+	ExportEvolution(q emergence.ExportQuery) ([]emergence.EvolutionRecord, string, error)
+}
+
+// Update 推送给浏览器的实时更新
+type Update struct {
+	Type string      `json:"type"` // sample, pattern, anomaly, decision
+	Data interface{} `json:"data"`
+}
+
+// Server 仪表盘服务
+// 作为integrations.Sink接收模式、异常和决策输出, 并周期采样系统健康度
+type Server struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config   types.DashboardConfig
+	provider Provider
+
+	// 仪表盘数据
+	data struct {
+		status    Status
+		samples   []Sample
+		anomalies []integrations.Output
+		decisions []integrations.Output
+	}
+
+	// 实时订阅者
+	subscribers map[chan Update]struct{}
+
+	// 运行状态
+	state struct {
+		running bool
+		addr    string
+	}
+
+	server *http.Server
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewServer 创建仪表盘服务
+func NewServer(provider Provider, config types.DashboardConfig) (*Server, error) {
+	if provider == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil dashboard provider", nil)
+	}
+	if config.SampleInterval <= 0 {
+		config.SampleInterval = defaultSampleInterval
+	}
+	if config.HistorySize <= 0 {
+		config.HistorySize = defaultHistorySize
+	}
+	if config.RecentItems <= 0 {
+		config.RecentItems = defaultRecentItems
+	}
+
+	s := &Server{
+		config:      config,
+		provider:    provider,
+		subscribers: make(map[chan Update]struct{}),
+	}
+	s.data.samples = make([]Sample, 0)
+	s.data.anomalies = make([]integrations.Output, 0)
+	s.data.decisions = make([]integrations.Output, 0)
+	return s, nil
+}
+
+// Handler 仪表盘HTTP处理器, 可挂载到已有的路由
+func (s *Server) Handler() http.Handler {
+	static, _ := fs.Sub(assets, "assets")
+
+	mux := http.NewServeMux()
+	mux.Handle("/", http.FileServer(http.FS(static)))
+	mux.HandleFunc("/api/status", s.handleStatus)
+	mux.HandleFunc("/api/energy", s.handleEnergy)
+	mux.HandleFunc("/api/patterns", s.handlePatterns)
+	mux.HandleFunc("/api/anomalies", s.handleAnomalies)
+	mux.HandleFunc("/api/decisions", s.handleDecisions)
+	mux.HandleFunc("/api/stream", s.handleStream)
+	if exporter, ok := s.provider.(Exporter); ok {
+		mux.HandleFunc("/api/export/patterns", handleExport(exporter.ExportPatterns))
+		mux.HandleFunc("/api/export/evolution", handleExport(exporter.ExportEvolution))
+	}
+	if source, ok := s.provider.(MetricsSource); ok {
+		mux.HandleFunc("/api/metrics", s.handleMetrics(source))
+		mux.HandleFunc("/metrics", s.handlePrometheus(source))
+	}
+	return mux
+}
+
+// Start 监听地址并启动采样, addr为空时使用配置地址
+func (s *Server) Start(ctx context.Context, addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.state.running {
+		return types.ErrAlreadyRunning
+	}
+	if addr == "" {
+		addr = s.config.Addr
+	}
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return types.NewSystemError(types.ErrNetwork, "failed to listen for dashboard", err).
+			WithContext("addr", addr)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	s.cancel = cancel
+	s.server = &http.Server{
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+	s.state.running = true
+	s.state.addr = listener.Addr().String()
+
+	s.wg.Add(2)
+	go func() {
+		defer s.wg.Done()
+		// 关闭后返回ErrServerClosed, 其余错误说明监听已失效
+		if err := s.server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			cancel()
+		}
+	}()
+	go s.sampleLoop(ctx)
+
+	return nil
+}
+
+// Addr 实际监听地址
+func (s *Server) Addr() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.state.addr
+}
+
+// Stop 停止服务, 断开实时连接
+func (s *Server) Stop() error {
+	s.mu.Lock()
+	if !s.state.running {
+		s.mu.Unlock()
+		return nil
+	}
+	s.state.running = false
+	server, cancel := s.server, s.cancel
+	s.mu.Unlock()
+
+	// 先取消上下文, 让实时流连接结束
+	cancel()
+
+	ctx, done := context.WithTimeout(context.Background(), 5*time.Second)
+	defer done()
+	err := server.Shutdown(ctx)
+
+	s.wg.Wait()
+	return err
+}
+
+// Name 输出端名称
+func (s *Server) Name() string {
+	return SinkName
+}
+
+// Emit 接收输出, 异常和决策记入最近列表, 全部推送给实时订阅者
+func (s *Server) Emit(ctx context.Context, outputs []integrations.Output) error {
+	s.mu.Lock()
+	for _, out := range outputs {
+		switch out.Kind {
+		case integrations.OutputAnomaly:
+			s.data.anomalies = appendRecent(s.data.anomalies, out, s.config.RecentItems)
+		case integrations.OutputDecision:
+			s.data.decisions = appendRecent(s.data.decisions, out, s.config.RecentItems)
+		}
+	}
+	s.mu.Unlock()
+
+	for _, out := range outputs {
+		s.broadcast(Update{Type: string(out.Kind), Data: out})
+	}
+	return nil
+}
+
+// Close 停止服务
+func (s *Server) Close() error {
+	return s.Stop()
+}
+
+// sampleLoop 周期采样健康度和能量
+func (s *Server) sampleLoop(ctx context.Context) {
+	defer s.wg.Done()
+
+	s.sample()
+
+	ticker := time.NewTicker(s.config.SampleInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.sample()
+		}
+	}
+}
+
+// sample 采集一次系统快照
+func (s *Server) sample() {
+	status := s.provider.Status()
+	point := Sample{
+		Timestamp: time.Now(),
+		Health:    status.Health,
+		Energy:    status.Energy,
+	}
+
+	s.mu.Lock()
+	s.data.status = status
+	s.data.samples = append(s.data.samples, point)
+	if excess := len(s.data.samples) - s.config.HistorySize; excess > 0 {
+		s.data.samples = s.data.samples[excess:]
+	}
+	s.mu.Unlock()
+
+	s.broadcast(Update{Type: "sample", Data: struct {
+		Sample
+		Status Status `json:"status"`
+	}{point, status}})
+}
+
+// subscribe 注册实时订阅者
+func (s *Server) subscribe() chan Update {
+	ch := make(chan Update, subscriberBuffer)
+	s.mu.Lock()
+	s.subscribers[ch] = struct{}{}
+	s.mu.Unlock()
+	return ch
+}
+
+// unsubscribe 注销实时订阅者
+func (s *Server) unsubscribe(ch chan Update) {
+	s.mu.Lock()
+	delete(s.subscribers, ch)
+	s.mu.Unlock()
+}
+
+// broadcast 推送更新, 慢速订阅者丢弃更新而不阻塞
+func (s *Server) broadcast(update Update) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for ch := range s.subscribers {
+		select {
+		case ch <- update:
+		default:
+		}
+	}
+}
+
+// appendRecent 追加并限制长度
+func appendRecent(list []integrations.Output, out integrations.Output, limit int) []integrations.Output {
+	list = append(list, out)
+	if excess := len(list) - limit; excess > 0 {
+		list = list[excess:]
+	}
+	return list
+}
@@ -0,0 +1,26 @@
+// system/dedup.go
+
+package system
+
+import (
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// newEventDeduplicator 按配置创建事件去重器, 未配置时使用默认窗口
+func (s *System) newEventDeduplicator() *types.EventDeduplicator {
+	var config types.DedupConfig
+	if s.config.Dedup != nil {
+		config = *s.config.Dedup
+	}
+	return types.NewEventDeduplicator(config)
+}
+
+// EventDedupStats 事件幂等去重统计
+func (s *System) EventDedupStats() types.DedupStats {
+	s.mu.RLock()
+	dedup := s.events.dedup
+	s.mu.RUnlock()
+	return dedup.Stats(time.Now())
+}
@@ -0,0 +1,390 @@
+// system/diagnostics.go
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 自检结果
+const (
+	DiagnosticPassed  = "passed"
+	DiagnosticFailed  = "failed"
+	DiagnosticSkipped = "skipped" // 前置条件不满足, 如系统未运行
+)
+
+// 自检参数
+const (
+	diagnosticTimeout    = 2 * time.Second // 单项自检的超时时间
+	diagnosticProbeEvent = types.EventType("system.diagnostic_probe")
+	diagnosticTolerance  = 1e-9
+)
+
+// DiagnosticCheck 单项自检结果
+type DiagnosticCheck struct {
+	Name      string                 `json:"name"`
+	Subsystem string                 `json:"subsystem"`
+	Result    string                 `json:"result"`
+	Error     string                 `json:"error,omitempty"`
+	Duration  time.Duration          `json:"duration"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// DiagnosticsReport 自检报告
+type DiagnosticsReport struct {
+	Started  time.Time         `json:"started"`
+	Duration time.Duration     `json:"duration"`
+	Passed   bool              `json:"passed"` // 没有失败的检查
+	Failed   int               `json:"failed"`
+	Skipped  int               `json:"skipped"`
+	Checks   []DiagnosticCheck `json:"checks"`
+}
+
+// diagnostic 自检项, 返回详情; 返回skipped错误时记为跳过
+type diagnostic struct {
+	name      string
+	subsystem string
+	run       func(ctx context.Context) (map[string]interface{}, error)
+}
+
+// skipped 跳过自检的原因
+type skipped string
+
+func (s skipped) Error() string { return string(s) }
+
+// RunDiagnostics 执行各子系统的快速自检并返回报告, 失败的检查以事件发出
+// 自检不修改运行中的组件: 场和检测器的检查在合成的统一场上进行
+func (s *System) RunDiagnostics(ctx context.Context) DiagnosticsReport {
+	report := DiagnosticsReport{Started: time.Now()}
+
+	for _, d := range s.diagnostics() {
+		check := runDiagnostic(ctx, d)
+		switch check.Result {
+		case DiagnosticFailed:
+			report.Failed++
+		case DiagnosticSkipped:
+			report.Skipped++
+		}
+		report.Checks = append(report.Checks, check)
+	}
+	report.Duration = time.Since(report.Started)
+	report.Passed = report.Failed == 0
+
+	s.mu.Lock()
+	s.diagnosticsReport = &report
+	s.mu.Unlock()
+
+	for _, check := range report.Checks {
+		if check.Result != DiagnosticFailed {
+			continue
+		}
+		s.HandleEvent(types.SystemEvent{
+			Type:      types.EventDiagnosticFailed,
+			Source:    check.Subsystem,
+			Timestamp: time.Now(),
+			Message:   check.Name + ": " + check.Error,
+			Priority:  types.PriorityHigh,
+			Data:      check,
+		})
+	}
+	return report
+}
+
+// LastDiagnostics 获取最近一次自检报告, 尚未自检时返回false
+func (s *System) LastDiagnostics() (DiagnosticsReport, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.diagnosticsReport == nil {
+		return DiagnosticsReport{}, false
+	}
+	return *s.diagnosticsReport, true
+}
+
+// startDiagnostics 配置了启动自检时在监管下执行一次自检, 调用方需持有锁
+func (s *System) startDiagnostics() {
+	if !s.config.DiagnosticsOnStart {
+		return
+	}
+	s.supervisor.Go(s.ctx, "system.diagnostics", func(ctx context.Context) {
+		s.RunDiagnostics(ctx)
+	})
+}
+
+// diagnostics 自检项列表: 各子系统运行状态, 量子态, 统一场状态, 事件总线投递和检测器试运行
+func (s *System) diagnostics() []diagnostic {
+	components := s.subsystems()
+	names := make([]string, 0, len(components))
+	for name := range components {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	list := make([]diagnostic, 0, len(names)+4)
+	for _, name := range names {
+		component := components[name]
+		list = append(list, diagnostic{
+			name:      name + ".status",
+			subsystem: name,
+			run: func(context.Context) (map[string]interface{}, error) {
+				return s.checkSubsystem(component)
+			},
+		})
+	}
+	return append(list,
+		diagnostic{name: "core.quantum_state", subsystem: "core", run: s.checkQuantumState},
+		diagnostic{name: "meta.field_state", subsystem: "meta", run: s.checkFieldState},
+		diagnostic{name: "system.event_bus", subsystem: "system", run: s.checkEventBus},
+		diagnostic{name: "meta.detector_dry_run", subsystem: "meta", run: checkDetector},
+	)
+}
+
+// runDiagnostic 在超时内执行自检项, panic记为失败
+func runDiagnostic(ctx context.Context, d diagnostic) DiagnosticCheck {
+	check := DiagnosticCheck{Name: d.name, Subsystem: d.subsystem}
+	start := time.Now()
+
+	ctx, cancel := context.WithTimeout(ctx, diagnosticTimeout)
+	defer cancel()
+
+	type outcome struct {
+		details map[string]interface{}
+		err     error
+	}
+	done := make(chan outcome, 1)
+	go func() {
+		defer func() {
+			if r := recover(); r != nil {
+				done <- outcome{err: fmt.Errorf("panic: %v", r)}
+			}
+		}()
+		details, err := d.run(ctx)
+		done <- outcome{details: details, err: err}
+	}()
+
+	select {
+	case out := <-done:
+		check.Details = out.details
+		switch err := out.err.(type) {
+		case nil:
+			check.Result = DiagnosticPassed
+		case skipped:
+			check.Result = DiagnosticSkipped
+			check.Error = err.Error()
+		default:
+			check.Result = DiagnosticFailed
+			check.Error = err.Error()
+		}
+	case <-ctx.Done():
+		check.Result = DiagnosticFailed
+		check.Error = "timed out: " + ctx.Err().Error()
+	}
+	check.Duration = time.Since(start)
+	return check
+}
+
+// checkSubsystem 子系统运行状态和健康度
+func (s *System) checkSubsystem(component types.Lifecycle) (map[string]interface{}, error) {
+	status, health := component.Status(), component.Health()
+	details := map[string]interface{}{"status": status, "health": health}
+	if !s.running() {
+		return details, skipped("system not running")
+	}
+	if status != "running" {
+		return details, fmt.Errorf("subsystem status %q", status)
+	}
+	if math.IsNaN(health) || health < 0 || health > 1 {
+		return details, fmt.Errorf("health %v out of range", health)
+	}
+	return details, nil
+}
+
+// checkQuantumState 新建量子态的初始化, 以及核心引擎中量子态的概率、能量和振幅
+func (s *System) checkQuantumState(context.Context) (map[string]interface{}, error) {
+	probe := core.NewQuantumState()
+	if err := probe.Initialize(); err != nil {
+		return nil, err
+	}
+	if err := quantumSane(probe); err != nil {
+		return nil, fmt.Errorf("fresh state: %w", err)
+	}
+
+	details := map[string]interface{}{"states": 0}
+	if s.core == nil || s.core.GetQuantumSystem() == nil {
+		return details, nil
+	}
+	quantum := s.core.GetQuantumSystem()
+	states := quantum.GetStates()
+	details["states"] = len(states)
+	for i, state := range states {
+		if err := quantumSane(state); err != nil {
+			return details, fmt.Errorf("state %d: %w", i, err)
+		}
+	}
+	coherence, entanglement := quantum.GetCoherence(), quantum.GetEntanglement()
+	details["coherence"], details["entanglement"] = coherence, entanglement
+	if !unitInterval(coherence) || !unitInterval(entanglement) {
+		return details, fmt.Errorf("coherence %v or entanglement %v out of range", coherence, entanglement)
+	}
+	return details, nil
+}
+
+// quantumSane 量子态的概率在[0,1]内, 能量有限且非负, 振幅非零
+func quantumSane(state *core.QuantumState) error {
+	if p := state.GetProbability(); !unitInterval(p) {
+		return fmt.Errorf("probability %v out of range", p)
+	}
+	if e := state.GetEnergy(); math.IsNaN(e) || math.IsInf(e, 0) || e < 0 {
+		return fmt.Errorf("invalid energy %v", e)
+	}
+	norm := 0.0
+	for _, a := range state.GetAmplitude() {
+		norm += real(a)*real(a) + imag(a)*imag(a)
+	}
+	if norm == 0 || math.IsNaN(norm) || math.IsInf(norm, 0) {
+		return fmt.Errorf("invalid amplitude norm %v", norm)
+	}
+	return nil
+}
+
+// checkFieldState 合成统一场的属性写读往返和状态读取, 以及运行中统一场状态的有效性
+func (s *System) checkFieldState(context.Context) (map[string]interface{}, error) {
+	synthetic, err := field.NewUnifiedField(3)
+	if err != nil {
+		return nil, err
+	}
+	const probe = 0.625
+	if err := synthetic.SetPropertyValue("diagnostic_probe", probe); err != nil {
+		return nil, err
+	}
+	if value, ok := synthetic.GetPropertyValue("diagnostic_probe"); !ok || value != probe {
+		return nil, fmt.Errorf("property round trip returned %v, %v", value, ok)
+	}
+	state, err := synthetic.GetState()
+	if err != nil {
+		return nil, err
+	}
+	if energy := synthetic.GetEnergy(); math.Abs(state.Energy-energy) > diagnosticTolerance {
+		return nil, fmt.Errorf("state energy %v differs from field energy %v", state.Energy, energy)
+	}
+	if err := fieldStateSane(state); err != nil {
+		return nil, fmt.Errorf("synthetic field: %w", err)
+	}
+
+	details := map[string]interface{}{"synthetic_energy": state.Energy}
+	live := s.meta.GetField()
+	if live == nil {
+		return details, nil
+	}
+	liveState, err := live.GetState()
+	if err != nil {
+		return details, err
+	}
+	details["energy"] = liveState.Energy
+	details["properties"] = len(liveState.Properties)
+	if err := fieldStateSane(liveState); err != nil {
+		return details, err
+	}
+	return details, nil
+}
+
+// fieldStateSane 场状态的能量和属性为有限值
+func fieldStateSane(state *model.FieldState) error {
+	if state == nil {
+		return fmt.Errorf("nil field state")
+	}
+	if math.IsNaN(state.Energy) || math.IsInf(state.Energy, 0) {
+		return fmt.Errorf("invalid energy %v", state.Energy)
+	}
+	for name, value := range state.Properties {
+		if math.IsNaN(value) || math.IsInf(value, 0) {
+			return fmt.Errorf("invalid property %s=%v", name, value)
+		}
+	}
+	return nil
+}
+
+// checkEventBus 发送探测事件并等待临时订阅者收到
+func (s *System) checkEventBus(ctx context.Context) (map[string]interface{}, error) {
+	if !s.running() {
+		return nil, skipped("system not running")
+	}
+
+	id := core.NewID("diagnostic_")
+	received := make(chan struct{}, 1)
+	handler := types.NewEventHandler(id, []types.EventType{diagnosticProbeEvent}, types.PriorityNormal,
+		func(event types.SystemEvent) error {
+			if event.Message == id {
+				select {
+				case received <- struct{}{}:
+				default:
+				}
+			}
+			return nil
+		})
+	if err := s.Subscribe(diagnosticProbeEvent, handler); err != nil {
+		return nil, err
+	}
+	defer s.Unsubscribe(diagnosticProbeEvent, handler)
+
+	sent := time.Now()
+	if err := s.HandleEvent(types.SystemEvent{
+		Type:      diagnosticProbeEvent,
+		Source:    "system.diagnostics",
+		Timestamp: sent,
+		Message:   id,
+	}); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-received:
+		return map[string]interface{}{"latency": time.Since(sent).String()}, nil
+	case <-ctx.Done():
+		return nil, fmt.Errorf("probe event not delivered: %w", ctx.Err())
+	}
+}
+
+// checkDetector 在合成统一场上试运行模式检测
+func checkDetector(context.Context) (map[string]interface{}, error) {
+	synthetic, err := field.NewUnifiedField(3)
+	if err != nil {
+		return nil, err
+	}
+	detector := emergence.NewPatternDetector(synthetic)
+	if detector == nil {
+		return nil, fmt.Errorf("failed to create pattern detector")
+	}
+	patterns, err := detector.Detect()
+	if err != nil {
+		return nil, err
+	}
+	for _, p := range patterns {
+		if math.IsNaN(p.Strength) || math.IsNaN(p.Stability) || math.IsNaN(p.Energy) {
+			return nil, fmt.Errorf("pattern %s has invalid strength, stability or energy", p.ID)
+		}
+	}
+	return map[string]interface{}{"patterns": len(patterns)}, nil
+}
+
+// running 系统是否运行中
+func (s *System) running() bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.isRunning
+}
+
+// unitInterval 值是否在[0,1]内
+func unitInterval(v float64) bool {
+	return !math.IsNaN(v) && v >= 0 && v <= 1
+}
@@ -0,0 +1,145 @@
+// system/drift.go
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/Corphon/daoflow/system/monitor/drift"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// startDrift 启用漂移检测时周期采样模式类型频率、环境因素和模型准确率, 调用方需持有锁
+func (s *System) startDrift() {
+	monitor := s.monitor.GetDriftMonitor()
+	if monitor == nil {
+		return
+	}
+
+	s.stopDrift()
+	// 出现过的模式类型, 类型消失后其频率按0继续观测; 调度器保证同一时刻只有一次采样
+	seen := make(map[string]bool)
+	s.registerJob(JobDriftSample, "monitor", monitor.Config().Interval, func(ctx context.Context) error {
+		s.sampleDrift(monitor, seen)
+		return nil
+	})
+}
+
+// stopDrift 停止漂移采样, 调用方需持有锁
+func (s *System) stopDrift() {
+	s.common.GetScheduler().Unregister(JobDriftSample)
+}
+
+// sampleDrift 采样一次并处理检测到的漂移
+func (s *System) sampleDrift(monitor *drift.Monitor, seen map[string]bool) {
+	now := time.Now()
+	drifts := make([]drift.Drift, 0)
+	observe := func(kind, key string, value float64) {
+		if d, detected := monitor.Observe(kind, key, value, now); detected {
+			drifts = append(drifts, d)
+		}
+	}
+
+	// 模式类型在活跃模式中的占比
+	if detector := s.meta.GetDetector(); detector != nil {
+		patterns := detector.GetActivePatterns()
+		counts := make(map[string]int)
+		for _, p := range patterns {
+			counts[p.Type]++
+			seen[p.Type] = true
+		}
+		for patternType := range seen {
+			frequency := 0.0
+			if len(patterns) > 0 {
+				frequency = float64(counts[patternType]) / float64(len(patterns))
+			}
+			observe(drift.KindPatternType, patternType, frequency)
+		}
+	}
+
+	// 演化匹配的环境因素
+	if matcher := s.evolution.GetMatcher(); matcher != nil {
+		for factor, value := range matcher.GetEnvironment() {
+			observe(drift.KindEnvironment, factor, value)
+		}
+	}
+
+	// 学习模型的准确率
+	learning := s.evolution.GetLearning()
+	if learning != nil {
+		for id, accuracy := range learning.ModelAccuracy() {
+			observe(drift.KindModelAccuracy, id, accuracy)
+		}
+	}
+
+	if len(drifts) > 0 {
+		s.handleDrifts(drifts, monitor.Config())
+	}
+}
+
+// handleDrifts 发出漂移事件, 按配置重新校准匹配阈值和重新训练学习模型
+// 模式类型漂移只重新校准该类型, 其他漂移影响全部类型; 一次采样最多重新训练一次
+func (s *System) handleDrifts(drifts []drift.Drift, config types.DriftConfig) {
+	for _, d := range drifts {
+		s.HandleEvent(types.SystemEvent{
+			Type:      types.EventDriftDetected,
+			Source:    d.Stream,
+			Timestamp: d.Timestamp,
+			Message:   "distribution drift detected",
+			Priority:  types.PriorityNormal,
+			Data:      d,
+		})
+
+		if !config.Recalibrate {
+			continue
+		}
+		if matcher := s.meta.GetMatcher(); matcher != nil {
+			if d.Kind == drift.KindPatternType {
+				matcher.Recalibrate(d.Key)
+			} else {
+				matcher.Recalibrate("")
+			}
+		}
+	}
+
+	if !config.Retrain {
+		return
+	}
+	if learning := s.evolution.GetLearning(); learning != nil {
+		if err := learning.Retrain(); err != nil {
+			// 不经recordError, 其持锁调用HandleEvent; 解锁后再发出错误事件
+			err = types.NewSystemError(types.ErrInternal, "retrain after drift failed", err).
+				WithContext("drifts", len(drifts))
+			s.mu.Lock()
+			s.appendErrorLocked(err)
+			s.mu.Unlock()
+
+			s.HandleEvent(types.SystemEvent{
+				Type:      types.EventSystemError,
+				Timestamp: time.Now(),
+				Data: map[string]interface{}{
+					"error": err.Error(),
+				},
+			})
+		}
+	}
+}
+
+// DriftHistory 获取since之后检测到的漂移, since为零时返回全部
+func (s *System) DriftHistory(since time.Time) ([]drift.Drift, error) {
+	monitor := s.monitor.GetDriftMonitor()
+	if monitor == nil {
+		return nil, types.NewSystemError(types.ErrState, "drift detection not enabled", nil)
+	}
+	return monitor.History(since), nil
+}
+
+// DriftStreams 获取漂移检测各数据流的状态
+func (s *System) DriftStreams() ([]drift.StreamStatus, error) {
+	monitor := s.monitor.GetDriftMonitor()
+	if monitor == nil {
+		return nil, types.NewSystemError(types.ErrState, "drift detection not enabled", nil)
+	}
+	return monitor.Streams(), nil
+}
@@ -0,0 +1,42 @@
+// system/encryption.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/encryption"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// snapshotLabel 快照归档加密用途标识
+const snapshotLabel = "system.snapshot"
+
+// Keyring 静态数据加密钥匙串, 未配置加密时为nil
+func (s *System) Keyring() *encryption.Keyring {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.keyring
+}
+
+// EnableEncryption 启用静态数据加密, 此后写入的快照归档、场状态快照日志和审计文件以kr的当前密钥加密; nil停止加密
+func (s *System) EnableEncryption(kr *encryption.Keyring) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.setKeyring(kr)
+}
+
+// RotateEncryptionKey 添加新密钥并用于此后写入的数据, 旧密钥保留用于解密轮换前写入的数据
+func (s *System) RotateEncryptionKey(id string, key []byte) error {
+	kr := s.Keyring()
+	if kr == nil {
+		return types.NewSystemError(types.ErrConfig, "encryption not enabled", nil)
+	}
+	return kr.Rotate(id, key)
+}
+
+// setKeyring 设置钥匙串并接入各持久化组件(调用方持有锁或处于构造阶段)
+// initializeSubsystems重建子系统时同样接入s.keyring
+func (s *System) setKeyring(kr *encryption.Keyring) {
+	s.keyring = kr
+	s.meta.SetKeyring(kr)
+	s.evolution.GetAudit().SetKeyring(kr)
+}
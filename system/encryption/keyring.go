@@ -0,0 +1,275 @@
+// system/encryption/keyring.go
+
+// Package encryption 以AES-256-GCM加密持久化数据
+// 密文带有加密时使用的密钥ID, 密钥轮换后以新密钥加密新数据, 旧数据在旧密钥仍可获取时照常解密
+package encryption
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// KeySize AES-256密钥长度
+const KeySize = 32
+
+// 密文格式: magic | 密钥ID长度(1字节) | 密钥ID | nonce | 密文和认证标签
+var magic = []byte("DFE1")
+
+// KeyProvider 外部密钥来源, 如KMS; 钥匙串中没有的密钥ID向提供者获取
+type KeyProvider interface {
+	Key(id string) ([]byte, error)
+}
+
+// KeyProviderFunc 函数形式的密钥提供者
+type KeyProviderFunc func(id string) ([]byte, error)
+
+// Key 获取密钥
+func (f KeyProviderFunc) Key(id string) ([]byte, error) {
+	return f(id)
+}
+
+// Keyring 钥匙串, 持有当前加密密钥和用于解密的历史密钥
+type Keyring struct {
+	mu sync.RWMutex
+
+	keys     map[string]cipher.AEAD
+	active   string
+	provider KeyProvider
+}
+
+// NewKeyring 创建空钥匙串, provider可为nil
+func NewKeyring(provider KeyProvider) *Keyring {
+	return &Keyring{
+		keys:     make(map[string]cipher.AEAD),
+		provider: provider,
+	}
+}
+
+// KeyringFromConfig 由配置创建钥匙串, 密钥取自配置或环境变量, Active不在配置中时向provider获取
+func KeyringFromConfig(cfg types.EncryptionConfig, provider KeyProvider) (*Keyring, error) {
+	kr := NewKeyring(provider)
+	for _, k := range cfg.Keys {
+		encoded := k.Key
+		if k.Env != "" {
+			encoded = os.Getenv(k.Env)
+			if encoded == "" {
+				return nil, types.NewSystemError(types.ErrConfig, "encryption key environment variable not set", nil).
+					WithContext("key", k.ID).
+					WithContext("env", k.Env)
+			}
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, types.NewSystemError(types.ErrConfig, "invalid base64 encryption key", err).
+				WithContext("key", k.ID)
+		}
+		if err := kr.Add(k.ID, key); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Active == "" {
+		return nil, types.NewSystemError(types.ErrConfig, "no active encryption key", nil)
+	}
+	if err := kr.SetActive(cfg.Active); err != nil {
+		return nil, err
+	}
+	return kr, nil
+}
+
+// Add 添加密钥
+func (kr *Keyring) Add(id string, key []byte) error {
+	aead, err := newAEAD(id, key)
+	if err != nil {
+		return err
+	}
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.keys[id] = aead
+	return nil
+}
+
+// SetActive 设置加密新数据使用的密钥, 钥匙串中没有时向提供者获取
+func (kr *Keyring) SetActive(id string) error {
+	if _, err := kr.aead(id); err != nil {
+		return err
+	}
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	kr.active = id
+	return nil
+}
+
+// Rotate 添加新密钥并设为当前密钥, 旧密钥保留用于解密
+func (kr *Keyring) Rotate(id string, key []byte) error {
+	if err := kr.Add(id, key); err != nil {
+		return err
+	}
+	return kr.SetActive(id)
+}
+
+// Remove 移除密钥, 不能移除当前密钥; 以该密钥加密的数据此后只能经提供者解密
+func (kr *Keyring) Remove(id string) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if id == kr.active {
+		return types.NewSystemError(types.ErrInvalid, "cannot remove active encryption key", nil).
+			WithContext("key", id)
+	}
+	delete(kr.keys, id)
+	return nil
+}
+
+// Active 当前密钥ID
+func (kr *Keyring) Active() string {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return kr.active
+}
+
+// IDs 已加载的密钥ID
+func (kr *Keyring) IDs() []string {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	ids := make([]string, 0, len(kr.keys))
+	for id := range kr.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// aead 获取密钥, 钥匙串中没有时向提供者获取并缓存
+func (kr *Keyring) aead(id string) (cipher.AEAD, error) {
+	kr.mu.RLock()
+	aead, ok := kr.keys[id]
+	provider := kr.provider
+	kr.mu.RUnlock()
+	if ok {
+		return aead, nil
+	}
+
+	if provider == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "encryption key not found", nil).
+			WithContext("key", id)
+	}
+	key, err := provider.Key(id)
+	if err != nil {
+		return nil, types.NewSystemError(types.ErrSecurity, "key provider failed", err).
+			WithContext("key", id)
+	}
+	if err := kr.Add(id, key); err != nil {
+		return nil, err
+	}
+
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return kr.keys[id], nil
+}
+
+// newAEAD 创建AES-256-GCM
+func newAEAD(id string, key []byte) (cipher.AEAD, error) {
+	if id == "" || len(id) > 255 {
+		return nil, types.NewSystemError(types.ErrInvalid, "encryption key id must be 1-255 bytes", nil)
+	}
+	if len(key) != KeySize {
+		return nil, types.NewSystemError(types.ErrInvalid, "encryption key must be 32 bytes", nil).
+			WithContext("key", id).
+			WithContext("size", len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "invalid encryption key", err).
+			WithContext("key", id)
+	}
+	return cipher.NewGCM(block)
+}
+
+// IsSealed 数据是否为本包加密的密文
+func IsSealed(data []byte) bool {
+	return bytes.HasPrefix(data, magic)
+}
+
+// Seal 以当前密钥加密; label区分数据用途(如"snapshot"), 解密时必须一致, 防止密文在不同存储间被挪用
+func (kr *Keyring) Seal(label string, plaintext []byte) ([]byte, error) {
+	id := kr.Active()
+	if id == "" {
+		return nil, types.NewSystemError(types.ErrConfig, "no active encryption key", nil)
+	}
+	aead, err := kr.aead(id)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, len(magic)+1+len(id)+aead.NonceSize())
+	header = append(header, magic...)
+	header = append(header, byte(len(id)))
+	header = append(header, id...)
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, types.NewSystemError(types.ErrSecurity, "failed to generate nonce", err)
+	}
+
+	out := append(header, nonce...)
+	return aead.Seal(out, nonce, plaintext, additionalData(header, label)), nil
+}
+
+// Open 解密Seal的结果, 返回明文和加密时的密钥ID
+func (kr *Keyring) Open(label string, data []byte) ([]byte, string, error) {
+	if !IsSealed(data) || len(data) < len(magic)+1 {
+		return nil, "", types.NewSystemError(types.ErrInvalid, "data is not encrypted", nil)
+	}
+	idLen := int(data[len(magic)])
+	headerLen := len(magic) + 1 + idLen
+	if len(data) < headerLen {
+		return nil, "", types.NewSystemError(types.ErrInvalid, "truncated ciphertext", nil)
+	}
+	header, id := data[:headerLen], string(data[len(magic)+1:headerLen])
+
+	aead, err := kr.aead(id)
+	if err != nil {
+		return nil, id, err
+	}
+	rest := data[headerLen:]
+	if len(rest) < aead.NonceSize()+aead.Overhead() {
+		return nil, id, types.NewSystemError(types.ErrInvalid, "truncated ciphertext", nil).
+			WithContext("key", id)
+	}
+	nonce, ciphertext := rest[:aead.NonceSize()], rest[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, additionalData(header, label))
+	if err != nil {
+		return nil, id, types.NewSystemError(types.ErrSecurity, "failed to decrypt data", err).
+			WithContext("key", id).
+			WithContext("label", label)
+	}
+	return plaintext, id, nil
+}
+
+// Rewrap 以当前密钥重新加密, 用于轮换后迁移旧数据; 已是当前密钥时原样返回
+func (kr *Keyring) Rewrap(label string, data []byte) ([]byte, error) {
+	plaintext, id, err := kr.Open(label, data)
+	if err != nil {
+		return nil, err
+	}
+	if id == kr.Active() {
+		return data, nil
+	}
+	return kr.Seal(label, plaintext)
+}
+
+// additionalData 认证附加数据: 密文头和用途
+func additionalData(header []byte, label string) []byte {
+	ad := make([]byte, 0, len(header)+len(label))
+	ad = append(ad, header...)
+	return append(ad, label...)
+}
@@ -0,0 +1,92 @@
+// system/encryption/stream.go
+
+package encryption
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// linePrefix 按行存储的密文前缀, 密文以base64编码以免包含换行
+const linePrefix = "enc:"
+
+// SealLine 加密一行数据(不含换行符), kr为nil时原样返回
+func SealLine(kr *Keyring, label string, line []byte) ([]byte, error) {
+	if kr == nil {
+		return line, nil
+	}
+	sealed, err := kr.Seal(label, line)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, len(linePrefix)+base64.StdEncoding.EncodedLen(len(sealed)))
+	copy(out, linePrefix)
+	base64.StdEncoding.Encode(out[len(linePrefix):], sealed)
+	return out, nil
+}
+
+// OpenLine 解密SealLine的结果; 未加密的行原样返回, 以便读取启用加密之前写入的文件
+func OpenLine(kr *Keyring, label string, line []byte) ([]byte, error) {
+	if !bytes.HasPrefix(line, []byte(linePrefix)) {
+		return line, nil
+	}
+	if kr == nil {
+		return nil, types.NewSystemError(types.ErrConfig, "encrypted data requires a keyring", nil).
+			WithContext("label", label)
+	}
+	sealed := make([]byte, base64.StdEncoding.DecodedLen(len(line)-len(linePrefix)))
+	n, err := base64.StdEncoding.Decode(sealed, line[len(linePrefix):])
+	if err != nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "invalid encrypted line", err).
+			WithContext("label", label)
+	}
+	plaintext, _, err := kr.Open(label, sealed[:n])
+	return plaintext, err
+}
+
+// ReadOption 读取选项函数类型
+type ReadOption func(*readOptions)
+
+// readOptions 读取选项
+type readOptions struct {
+	allowPlaintext bool
+}
+
+// AllowPlaintext 提供钥匙串时仍接受未加密的数据, 仅用于迁移启用加密之前写入的数据
+func AllowPlaintext() ReadOption {
+	return func(o *readOptions) {
+		o.allowPlaintext = true
+	}
+}
+
+// ReadAll 读取全部数据, 已加密时解密
+// 提供钥匙串时要求数据已加密, 未加密的数据被拒绝以免被替换为明文降级; 以AllowPlaintext显式接受明文
+// 未提供钥匙串时未加密的数据原样返回
+func ReadAll(kr *Keyring, label string, r io.Reader, opts ...ReadOption) ([]byte, error) {
+	var o readOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, types.NewSystemError(types.ErrIO, "failed to read data", err).
+			WithContext("label", label)
+	}
+	if !IsSealed(data) {
+		if kr != nil && !o.allowPlaintext {
+			return nil, types.NewSystemError(types.ErrInvalid, "unencrypted data rejected while encryption is enabled", nil).
+				WithContext("label", label)
+		}
+		return data, nil
+	}
+	if kr == nil {
+		return nil, types.NewSystemError(types.ErrConfig, "encrypted data requires a keyring", nil).
+			WithContext("label", label)
+	}
+	plaintext, _, err := kr.Open(label, data)
+	return plaintext, err
+}
@@ -0,0 +1,107 @@
+// system/encryption_test.go
+
+package system
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/Corphon/daoflow/system/encryption"
+	"github.com/Corphon/daoflow/system/evolution/audit"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// newEncryptedSystem 创建启用静态数据加密的系统, 审计记录写入auditPath
+func newEncryptedSystem(t *testing.T, auditPath string) *System {
+	t.Helper()
+	cfg := DefaultConfig()
+	cfg.EvolutionConfig.Audit = &types.AuditConfig{Path: auditPath}
+	cfg.Encryption = &types.EncryptionConfig{
+		Active: "k1",
+		Keys: []types.EncryptionKey{
+			{ID: "k1", Key: base64.StdEncoding.EncodeToString(bytes.Repeat([]byte{7}, 32))},
+		},
+	}
+
+	sys, err := New(cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(sys.cancel)
+	return sys
+}
+
+// Initialize重建子系统后, 审计文件仍以配置的密钥加密
+func TestInitializeKeepsAuditEncrypted(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.log")
+	sys := newEncryptedSystem(t, path)
+	if err := sys.Initialize(sys.ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	sys.evolution.GetAudit().Append(audit.Record{
+		Kind:    audit.KindParametersUpdated,
+		Target:  "strategy",
+		Lineage: audit.Lineage{Source: audit.SourceExternal},
+	})
+
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	lines := 0
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		lines++
+		if !bytes.HasPrefix(scanner.Bytes(), []byte("enc:")) {
+			t.Errorf("audit line %d written in plaintext: %s", lines, scanner.Bytes())
+		}
+	}
+	if lines != 1 {
+		t.Fatalf("audit lines = %d, want 1", lines)
+	}
+
+	if _, err := file.Seek(0, 0); err != nil {
+		t.Fatal(err)
+	}
+	records, err := audit.ReadRecords(file, sys.Keyring())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 || records[0].Target != "strategy" {
+		t.Errorf("records = %+v, want the appended record", records)
+	}
+}
+
+// 启用加密时Restore拒绝未加密的归档, RestoreUnencrypted显式接受
+func TestRestoreRejectsUnencryptedArchive(t *testing.T) {
+	sys := newEncryptedSystem(t, "")
+
+	var sealed bytes.Buffer
+	if err := sys.Snapshot(&sealed); err != nil {
+		t.Fatal(err)
+	}
+	if !encryption.IsSealed(sealed.Bytes()) {
+		t.Fatal("snapshot written in plaintext while encryption is enabled")
+	}
+	plaintext, _, err := sys.Keyring().Open(snapshotLabel, sealed.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := sys.Restore(bytes.NewReader(sealed.Bytes())); err != nil {
+		t.Errorf("Restore(sealed) error = %v", err)
+	}
+	if err := sys.Restore(bytes.NewReader(plaintext)); types.GetErrorCode(err) != types.ErrInvalid {
+		t.Errorf("Restore(plaintext) error = %v, want %s", err, types.ErrInvalid)
+	}
+	if err := sys.RestoreUnencrypted(bytes.NewReader(plaintext)); err != nil {
+		t.Errorf("RestoreUnencrypted(plaintext) error = %v", err)
+	}
+}
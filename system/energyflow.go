@@ -0,0 +1,94 @@
+// system/energyflow.go
+
+package system
+
+import (
+	"io"
+	"time"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/monitor/flow"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// startEnergyFlow 将检测器的能量转移接入能量转移记录
+func (s *System) startEnergyFlow() {
+	if s.monitor.GetEnergyLedger() == nil {
+		return
+	}
+	if detector := s.meta.GetDetector(); detector != nil && detector != s.hooks.energy {
+		s.recordEnergyTransfers(detector)
+		s.hooks.energy = detector
+	}
+}
+
+// recordEnergyTransfers 接入检测器的能量转移, 元素流向所属模式类型
+func (s *System) recordEnergyTransfers(detector *emergence.PatternDetector) {
+	ledger := s.monitor.GetEnergyLedger()
+	if ledger == nil {
+		return
+	}
+
+	detector.OnEnergyTransfer(func(transfers []emergence.EnergyTransfer) {
+		records := make([]flow.Transfer, len(transfers))
+		for i, t := range transfers {
+			records[i] = flow.Transfer{
+				Source:    flow.Node{Kind: flow.NodeElement, Name: t.Element},
+				Target:    flow.Node{Kind: flow.NodePattern, Name: t.PatternType},
+				Amount:    t.Amount,
+				Namespace: t.Namespace,
+				Timestamp: t.Timestamp,
+			}
+		}
+		ledger.Record(records...)
+	})
+}
+
+// modelEnergies 记录模型当前能量, 调用方需持有锁
+func (s *System) modelEnergies(names []string) map[string]float64 {
+	energies := make(map[string]float64, len(names))
+	for _, name := range names {
+		energies[name] = s.models[name].GetState().Energy
+	}
+	return energies
+}
+
+// recordModelTransfers 以转换前后的能量变化记录统一场与模型之间的转移, 调用方需持有锁
+func (s *System) recordModelTransfers(before map[string]float64) {
+	ledger := s.monitor.GetEnergyLedger()
+	if ledger == nil {
+		return
+	}
+
+	now := time.Now()
+	unified := flow.Node{Kind: flow.NodeField, Name: flow.FieldUnified}
+	transfers := make([]flow.Transfer, 0, len(before))
+	for name, energy := range before {
+		transfers = append(transfers, flow.Transfer{
+			Source:    unified,
+			Target:    flow.Node{Kind: flow.NodeModel, Name: name},
+			Amount:    s.models[name].GetState().Energy - energy,
+			Timestamp: now,
+		})
+	}
+	ledger.Record(transfers...)
+}
+
+// EnergyFlow 将时间窗口内的能量转移聚合为桑基图数据
+// 源为五行元素和统一场, 汇为按类型聚合的模式和模型; 模型能量减少时记为模型流向统一场
+func (s *System) EnergyFlow(q flow.SankeyQuery) (flow.SankeyReport, error) {
+	ledger := s.monitor.GetEnergyLedger()
+	if ledger == nil {
+		return flow.SankeyReport{}, types.NewSystemError(types.ErrState, "energy flow recording not enabled", nil)
+	}
+	return ledger.Sankey(q), nil
+}
+
+// ExportEnergyFlow 以JSON导出时间窗口内的能量流向
+func (s *System) ExportEnergyFlow(w io.Writer, q flow.SankeyQuery) error {
+	report, err := s.EnergyFlow(q)
+	if err != nil {
+		return err
+	}
+	return report.WriteJSON(w)
+}
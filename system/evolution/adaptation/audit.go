@@ -0,0 +1,89 @@
+// system/evolution/adaptation/audit.go
+
+package adaptation
+
+import (
+	"github.com/Corphon/daoflow/system/evolution/audit"
+)
+
+// lineageExperiences 审计记录中引用的最近经验数
+const lineageExperiences = 20
+
+// SetAudit 设置参数和规则变更的审计日志, nil时忽略
+func (as *AdaptationStrategy) SetAudit(log *audit.Log) {
+	if log == nil {
+		return
+	}
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.auditLog = log
+}
+
+// GetAudit 获取审计日志
+func (as *AdaptationStrategy) GetAudit() *audit.Log {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+	return as.auditLog
+}
+
+// recordAudit 记录一次参数或规则变更, 调用方需持有锁
+func (as *AdaptationStrategy) recordAudit(kind, target string, before, after map[string]interface{}, lineage audit.Lineage) {
+	as.auditLog.Append(audit.Record{
+		Kind:    kind,
+		Target:  target,
+		Before:  before,
+		After:   after,
+		Lineage: lineage,
+	})
+}
+
+// ruleSnapshot 规则的可审计快照
+func ruleSnapshot(rule *StrategyRule) map[string]interface{} {
+	return map[string]interface{}{
+		"name":             rule.Name,
+		"type":             rule.Type,
+		"target":           rule.Target,
+		"condition":        rule.Condition.Expression,
+		"threshold":        rule.Condition.Threshold,
+		"condition_params": rule.Condition.Parameters,
+		"action":           rule.Action.Function,
+		"action_params":    rule.Action.Parameters,
+		"weight":           rule.Weight,
+		"enabled":          rule.Enabled,
+	}
+}
+
+// lineage 学习产生的变更来源: 最近的经验、各模型版本和经验的平均目标达成度, 调用方需持有锁
+func (al *AdaptiveLearning) lineage() audit.Lineage {
+	lineage := audit.Lineage{
+		Source:        audit.SourceLearning,
+		ModelVersions: al.modelVersions(),
+	}
+
+	experiences := al.state.experiences
+	if len(experiences) > lineageExperiences {
+		experiences = experiences[len(experiences)-lineageExperiences:]
+	}
+	sum, scored := 0.0, 0
+	for _, exp := range experiences {
+		lineage.Experiences = append(lineage.Experiences, exp.ID)
+		if exp.Scored {
+			sum += exp.Feedback
+			scored++
+		}
+	}
+	if scored > 0 {
+		lineage.ObjectiveScore = sum / float64(scored)
+		lineage.Scored = true
+	}
+	return lineage
+}
+
+// modelVersions 各学习模型的当前版本, 调用方需持有锁
+func (al *AdaptiveLearning) modelVersions() map[string]int {
+	versions := make(map[string]int, len(al.state.models))
+	for id, model := range al.state.models {
+		versions[id] = model.State.Version
+	}
+	return versions
+}
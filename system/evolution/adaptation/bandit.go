@@ -0,0 +1,271 @@
+// system/evolution/adaptation/bandit.go
+
+package adaptation
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 老虎机选择策略
+const (
+	BanditEpsilonGreedy = "epsilon_greedy" // 以探索率随机选择, 否则选择平均奖励最高的策略
+	BanditUCB           = "ucb"            // 选择平均奖励加置信上界最高的策略
+)
+
+// ArmStats 老虎机单臂(策略或规则)的奖励统计
+type ArmStats struct {
+	ID         string    `json:"id"`
+	Pulls      int64     `json:"pulls"`       // 被选中执行的次数
+	Reward     float64   `json:"reward"`      // 累计奖励
+	Mean       float64   `json:"mean"`        // 平均奖励
+	LastReward float64   `json:"last_reward"` // 最近一次奖励
+	LastPulled time.Time `json:"last_pulled"`
+}
+
+// BanditStats 老虎机统计
+type BanditStats struct {
+	Policy       string     `json:"policy"`
+	Exploration  float64    `json:"exploration"`  // 探索率
+	UCBConstant  float64    `json:"ucb_constant"` // UCB探索系数
+	Selections   int64      `json:"selections"`   // 选择次数
+	Explorations int64      `json:"explorations"` // 选择未取当前最优策略的次数
+	Strategies   []ArmStats `json:"strategies"`   // 按平均奖励降序
+	Rules        []ArmStats `json:"rules"`        // 按平均奖励降序
+}
+
+// Bandit 策略选择的多臂老虎机
+// 每个执行周期从适用策略中选择一个执行, 执行得分作为奖励计入策略及其关联规则
+type Bandit struct {
+	mu sync.Mutex
+
+	policy      string
+	exploration float64
+	ucbConstant float64
+
+	strategies   map[string]*ArmStats
+	rules        map[string]*ArmStats
+	selections   int64
+	explorations int64
+}
+
+// NewBandit 创建多臂老虎机, explorationRate为ε-greedy的探索率
+func NewBandit(cfg types.BanditConfig, explorationRate float64) (*Bandit, error) {
+	switch cfg.Policy {
+	case BanditEpsilonGreedy, BanditUCB:
+	default:
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown bandit policy", nil).
+			WithContext("policy", cfg.Policy)
+	}
+	if explorationRate < 0 || explorationRate > 1 {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "exploration rate out of range", nil).
+			WithContext("exploration_rate", explorationRate)
+	}
+	if cfg.UCBConstant <= 0 {
+		cfg.UCBConstant = math.Sqrt2
+	}
+
+	return &Bandit{
+		policy:      cfg.Policy,
+		exploration: explorationRate,
+		ucbConstant: cfg.UCBConstant,
+		strategies:  make(map[string]*ArmStats),
+		rules:       make(map[string]*ArmStats),
+	}, nil
+}
+
+// Select 从候选策略中选择一个, 返回是否为探索选择
+// 未执行过的策略优先尝试; 候选为空时返回空字符串
+func (b *Bandit) Select(candidates []string) (string, bool) {
+	if len(candidates) == 0 {
+		return "", false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	best := b.greedy(candidates)
+	choice := best
+	switch b.policy {
+	case BanditEpsilonGreedy:
+		if rand.Float64() < b.exploration {
+			choice = candidates[rand.Intn(len(candidates))]
+		}
+	case BanditUCB:
+		choice = b.upperConfidence(candidates)
+	}
+
+	b.selections++
+	explored := choice != best
+	if explored {
+		b.explorations++
+	}
+	return choice, explored
+}
+
+// Reward 记录策略执行的奖励, 同时计入策略关联的规则; 奖励范围[0,1]
+func (b *Bandit) Reward(strategyID string, rules []string, reward float64) {
+	reward = clamp01(reward)
+	now := time.Now()
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	updateArm(b.strategies, strategyID, reward, now)
+	for _, id := range rules {
+		updateArm(b.rules, id, reward, now)
+	}
+}
+
+// Stats 获取老虎机统计
+func (b *Bandit) Stats() BanditStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return BanditStats{
+		Policy:       b.policy,
+		Exploration:  b.exploration,
+		UCBConstant:  b.ucbConstant,
+		Selections:   b.selections,
+		Explorations: b.explorations,
+		Strategies:   sortedArms(b.strategies),
+		Rules:        sortedArms(b.rules),
+	}
+}
+
+// SetExplorationRate 设置探索率
+func (b *Bandit) SetExplorationRate(rate float64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.exploration = clamp01(rate)
+}
+
+// greedy 平均奖励最高的候选, 未执行过的候选视为最高奖励, 平局时取靠前的候选
+func (b *Bandit) greedy(candidates []string) string {
+	best, bestValue := candidates[0], -1.0
+	for _, id := range candidates {
+		value := 1.0
+		if arm, ok := b.strategies[id]; ok && arm.Pulls > 0 {
+			value = arm.Mean
+		}
+		if value > bestValue {
+			best, bestValue = id, value
+		}
+	}
+	return best
+}
+
+// upperConfidence 平均奖励加置信上界最高的候选, 未执行过的候选优先
+func (b *Bandit) upperConfidence(candidates []string) string {
+	total := int64(0)
+	for _, id := range candidates {
+		if arm, ok := b.strategies[id]; ok {
+			total += arm.Pulls
+		}
+	}
+
+	best, bestValue := candidates[0], math.Inf(-1)
+	for _, id := range candidates {
+		arm, ok := b.strategies[id]
+		if !ok || arm.Pulls == 0 {
+			return id
+		}
+		value := arm.Mean + b.ucbConstant*math.Sqrt(math.Log(float64(total))/float64(arm.Pulls))
+		if value > bestValue {
+			best, bestValue = id, value
+		}
+	}
+	return best
+}
+
+// updateArm 更新单臂的奖励统计
+func updateArm(arms map[string]*ArmStats, id string, reward float64, now time.Time) {
+	arm, ok := arms[id]
+	if !ok {
+		arm = &ArmStats{ID: id}
+		arms[id] = arm
+	}
+	arm.Pulls++
+	arm.Reward += reward
+	arm.Mean = arm.Reward / float64(arm.Pulls)
+	arm.LastReward = reward
+	arm.LastPulled = now
+}
+
+// sortedArms 按平均奖励降序复制统计
+func sortedArms(arms map[string]*ArmStats) []ArmStats {
+	list := make([]ArmStats, 0, len(arms))
+	for _, arm := range arms {
+		list = append(list, *arm)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Mean != list[j].Mean {
+			return list[i].Mean > list[j].Mean
+		}
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// SetBandit 设置策略选择的多臂老虎机, nil表示执行所有适用策略
+func (as *AdaptationStrategy) SetBandit(bandit *Bandit) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.bandit = bandit
+}
+
+// GetBanditStats 获取老虎机统计, 未启用时返回false
+func (as *AdaptationStrategy) GetBanditStats() (BanditStats, bool) {
+	as.mu.RLock()
+	bandit := as.bandit
+	as.mu.RUnlock()
+	if bandit == nil {
+		return BanditStats{}, false
+	}
+	return bandit.Stats(), true
+}
+
+// selectByBandit 由老虎机从按优先级排序的适用策略中选择一个, 调用方需持有锁
+func (as *AdaptationStrategy) selectByBandit(strategies []*Strategy) []*Strategy {
+	if len(strategies) == 0 {
+		return strategies
+	}
+	candidates := make([]string, len(strategies))
+	for i, strategy := range strategies {
+		candidates[i] = strategy.ID
+	}
+	id, explored := as.bandit.Select(candidates)
+	for _, strategy := range strategies {
+		if strategy.ID == id {
+			as.recordStrategyEvent(strategy, "bandit_selection", map[string]interface{}{
+				"candidates": len(candidates),
+				"explored":   explored,
+			})
+			return []*Strategy{strategy}
+		}
+	}
+	return nil
+}
+
+// rewardBandit 记录策略执行的奖励: 失败为0, 成功时为执行后的目标达成度, 未定义目标时为1; 调用方需持有锁
+func (as *AdaptationStrategy) rewardBandit(strategy *Strategy, failed bool) {
+	reward := 0.0
+	if !failed {
+		reward = 1
+		for i := len(as.state.history) - 1; i >= 0; i-- {
+			event := as.state.history[i]
+			if event.StrategyID == strategy.ID && event.Type == "execution_complete" {
+				if score, ok := event.Details[detailObjectiveScore].(float64); ok {
+					reward = score
+				}
+				break
+			}
+		}
+	}
+	as.bandit.Reward(strategy.ID, strategy.Rules, reward)
+}
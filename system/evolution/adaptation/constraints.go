@@ -0,0 +1,43 @@
+// system/evolution/adaptation/constraints.go
+
+package adaptation
+
+import (
+	"time"
+
+	"github.com/Corphon/daoflow/system/evolution/constraint"
+)
+
+// GetConstraints 获取动作约束检查器, 策略动作执行前和规则注册时按其约束裁剪或拒绝参数
+func (as *AdaptationStrategy) GetConstraints() *constraint.Checker {
+	return as.constraints
+}
+
+// constrainParameters 按约束检查动作参数, 每个违反记录一条constraint_violation事件; 调用方需持有锁
+func (as *AdaptationStrategy) constrainParameters(source interface{}, sourceID, target string, params map[string]interface{}) (map[string]interface{}, error) {
+	allowed, violations, err := as.constraints.Check(sourceID, target, params, time.Now())
+	for _, v := range violations {
+		as.recordStrategyEvent(source, "constraint_violation", map[string]interface{}{
+			"constraint": v.Constraint,
+			"target":     v.Target,
+			"parameter":  v.Parameter,
+			"value":      v.Value,
+			"allowed":    v.Allowed,
+			"result":     v.Result,
+		})
+	}
+	return allowed, err
+}
+
+// constrainRule 按约束检查规则动作参数, 裁剪后的参数写回规则; 调用方需持有锁
+func (as *AdaptationStrategy) constrainRule(rule *StrategyRule) error {
+	if len(rule.Action.Parameters) == 0 {
+		return nil
+	}
+	params, err := as.constrainParameters(rule, rule.ID, rule.Target, rule.Action.Parameters)
+	if err != nil {
+		return err
+	}
+	rule.Action.Parameters = params
+	return nil
+}
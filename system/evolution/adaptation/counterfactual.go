@@ -0,0 +1,328 @@
+// system/evolution/adaptation/counterfactual.go
+
+package adaptation
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 反事实评估参数
+const (
+	maxDriftSamples        = 200  // 保留的无动作周期状态变化样本数
+	minRetirementDecisions = 5    // 建议退役前至少评估的决策数
+	retirementZ            = 1.96 // 处理效应置信上界的z值
+)
+
+// detailRules 策略事件详情中执行时策略关联规则的键
+const detailRules = "rules"
+
+// Simulator 反事实回放的模拟沙箱
+// 从决策前记录的状态出发, 推演不执行动作时经过horizon后的状态; 实现不得修改传入的状态或作用于运行中的系统
+type Simulator interface {
+	Simulate(ctx context.Context, state *model.SystemState, horizon time.Duration) (*model.SystemState, error)
+}
+
+// DriftSimulator 默认模拟沙箱, 按未执行任何策略的相邻执行周期之间的平均指标变化率外推
+// 没有无动作样本时假定状态不变
+type DriftSimulator struct {
+	mu      sync.RWMutex
+	samples []map[string]float64 // 各指标每秒的变化率
+}
+
+// NewDriftSimulator 创建漂移模拟沙箱
+func NewDriftSimulator() *DriftSimulator {
+	return &DriftSimulator{samples: make([]map[string]float64, 0)}
+}
+
+// Observe 记录一段没有执行动作的间隔内的指标变化
+func (d *DriftSimulator) Observe(before, after map[string]float64, elapsed time.Duration) {
+	if elapsed <= 0 {
+		return
+	}
+	rates := make(map[string]float64, len(after))
+	for metric, value := range after {
+		if prev, ok := before[metric]; ok {
+			rates[metric] = (value - prev) / elapsed.Seconds()
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.samples = append(d.samples, rates)
+	if len(d.samples) > maxDriftSamples {
+		d.samples = d.samples[len(d.samples)-maxDriftSamples:]
+	}
+}
+
+// Samples 无动作样本数
+func (d *DriftSimulator) Samples() int {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return len(d.samples)
+}
+
+// Simulate 按平均变化率推演状态, 返回状态的副本
+func (d *DriftSimulator) Simulate(ctx context.Context, state *model.SystemState, horizon time.Duration) (*model.SystemState, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if state == nil {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil state", nil)
+	}
+
+	d.mu.RLock()
+	sums := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, rates := range d.samples {
+		for metric, rate := range rates {
+			sums[metric] += rate
+			counts[metric]++
+		}
+	}
+	d.mu.RUnlock()
+
+	shift := func(metric string, value float64) float64 {
+		if counts[metric] == 0 {
+			return value
+		}
+		return value + sums[metric]/float64(counts[metric])*horizon.Seconds()
+	}
+
+	predicted := *state
+	predicted.Properties = make(map[string]interface{}, len(state.Properties))
+	for k, v := range state.Properties {
+		if n, ok := v.(float64); ok {
+			v = shift(k, n)
+		}
+		predicted.Properties[k] = v
+	}
+	predicted.Energy = shift("energy", state.Energy)
+	predicted.Stability = shift("stability", state.Stability)
+	predicted.Entropy = shift("entropy", state.Entropy)
+	predicted.Harmony = shift("harmony", state.Harmony)
+	predicted.Balance = shift("balance", state.Balance)
+	predicted.Timestamp = state.Timestamp.Add(horizon)
+	return &predicted, nil
+}
+
+// Counterfactual 一次适应决策的反事实评估
+type Counterfactual struct {
+	StrategyID      string             `json:"strategy_id"`
+	Rules           []string           `json:"rules,omitempty"` // 执行时策略关联的规则
+	Timestamp       time.Time          `json:"timestamp"`
+	Horizon         time.Duration      `json:"horizon"`          // 决策前状态到结果评分的间隔
+	Before          float64            `json:"before"`           // 执行前的目标达成度
+	Actual          float64            `json:"actual"`           // 执行后实际的目标达成度
+	Predicted       float64            `json:"predicted"`        // 沙箱推演的不执行时的目标达成度
+	Effect          float64            `json:"effect"`           // 处理效应, Actual - Predicted
+	ActualValues    map[string]float64 `json:"actual_values"`    // 按目标名称的实际指标值
+	PredictedValues map[string]float64 `json:"predicted_values"` // 按目标名称的推演指标值
+}
+
+// TreatmentEffect 策略或规则的估计处理效应
+type TreatmentEffect struct {
+	ID        string  `json:"id"`
+	Decisions int     `json:"decisions"` // 评估的决策数
+	Mean      float64 `json:"mean"`      // 平均处理效应
+	StdErr    float64 `json:"std_err"`   // 平均处理效应的标准误
+	Retire    bool    `json:"retire"`    // 决策数足够且效应的置信上界不为正时建议退役
+}
+
+// CounterfactualReport 反事实评估报告
+type CounterfactualReport struct {
+	Decisions  []Counterfactual  `json:"decisions"`  // 按时间顺序
+	Skipped    int               `json:"skipped"`    // 未按目标评分或无法推演的决策数
+	Strategies []TreatmentEffect `json:"strategies"` // 按平均效应升序, 最可能无效的在前
+	Rules      []TreatmentEffect `json:"rules"`      // 按平均效应升序
+	Generated  time.Time         `json:"generated"`
+}
+
+// SetSimulator 设置反事实回放的模拟沙箱, nil时使用按无动作周期估计的漂移沙箱
+func (as *AdaptationStrategy) SetSimulator(simulator Simulator) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.simulator = simulator
+}
+
+// EvaluateCounterfactual 评估一次执行完成的决策: 在沙箱中从决策前记录的状态推演不执行动作的结果, 与实际结果比较
+// 决策需在定义适应目标时执行; 按当前目标评分, 执行后已移除的目标不参与比较
+func (as *AdaptationStrategy) EvaluateCounterfactual(ctx context.Context, decision StrategyEvent) (Counterfactual, error) {
+	as.mu.RLock()
+	simulator := as.currentSimulator()
+	objectives := as.objectiveList()
+	rules := as.decisionRules(decision)
+	as.mu.RUnlock()
+
+	return evaluateCounterfactual(ctx, simulator, objectives, decision, rules)
+}
+
+// EvaluateCounterfactuals 评估since之后执行完成的全部决策, 并按策略和规则汇总处理效应
+func (as *AdaptationStrategy) EvaluateCounterfactuals(ctx context.Context, since time.Time) (*CounterfactualReport, error) {
+	as.mu.RLock()
+	simulator := as.currentSimulator()
+	objectives := as.objectiveList()
+	decisions := make([]StrategyEvent, 0)
+	rules := make([][]string, 0)
+	for _, event := range as.state.history {
+		if event.Type == "execution_complete" && !event.Timestamp.Before(since) {
+			decisions = append(decisions, event)
+			rules = append(rules, as.decisionRules(event))
+		}
+	}
+	as.mu.RUnlock()
+
+	report := &CounterfactualReport{
+		Decisions: make([]Counterfactual, 0, len(decisions)),
+		Generated: time.Now(),
+	}
+	byStrategy := make(map[string][]float64)
+	byRule := make(map[string][]float64)
+	for i, decision := range decisions {
+		cf, err := evaluateCounterfactual(ctx, simulator, objectives, decision, rules[i])
+		if err != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, ctxErr
+			}
+			report.Skipped++
+			continue
+		}
+		report.Decisions = append(report.Decisions, cf)
+		byStrategy[cf.StrategyID] = append(byStrategy[cf.StrategyID], cf.Effect)
+		for _, rule := range cf.Rules {
+			byRule[rule] = append(byRule[rule], cf.Effect)
+		}
+	}
+	report.Strategies = treatmentEffects(byStrategy)
+	report.Rules = treatmentEffects(byRule)
+	return report, nil
+}
+
+// currentSimulator 当前模拟沙箱, 调用方需持有锁
+func (as *AdaptationStrategy) currentSimulator() Simulator {
+	if as.simulator != nil {
+		return as.simulator
+	}
+	return as.drift
+}
+
+// decisionRules 决策执行时策略关联的规则, 早于记录规则的决策取策略当前的规则; 调用方需持有锁
+func (as *AdaptationStrategy) decisionRules(decision StrategyEvent) []string {
+	if rules, ok := decision.Details[detailRules].([]string); ok {
+		return rules
+	}
+	if strategy, exists := as.state.strategies[decision.StrategyID]; exists {
+		return append([]string(nil), strategy.Rules...)
+	}
+	return nil
+}
+
+// observeCycle 记录执行周期开始时的状态, 上一周期未执行策略时作为无动作漂移样本; 调用方需持有锁
+func (as *AdaptationStrategy) observeCycle(state *model.SystemState, now time.Time) {
+	metrics := StateMetrics(state)
+	if last := as.lastCycle; !last.at.IsZero() && !last.treated {
+		as.drift.Observe(last.metrics, metrics, now.Sub(last.at))
+	}
+	as.lastCycle = cycleObservation{metrics: metrics, at: now}
+}
+
+// cycleObservation 执行周期开始时的状态指标
+type cycleObservation struct {
+	metrics map[string]float64
+	at      time.Time
+	treated bool // 周期内是否执行了策略
+}
+
+// evaluateCounterfactual 评估单个决策
+func evaluateCounterfactual(ctx context.Context, simulator Simulator, objectives []Objective, decision StrategyEvent, rules []string) (Counterfactual, error) {
+	if decision.Type != "execution_complete" {
+		return Counterfactual{}, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "decision is not a completed execution", nil).
+			WithContext("type", decision.Type)
+	}
+	state, _ := decision.Details["state"].(*model.SystemState)
+	values, scored := decision.Details[detailObjectiveValues].(map[string]float64)
+	if state == nil || !scored {
+		return Counterfactual{}, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "decision was not scored against objectives", nil).
+			WithContext("strategy_id", decision.StrategyID)
+	}
+
+	// 只比较决策评分时已有的目标, 以目标名称对应的实际值替代指标
+	compared := make([]Objective, 0, len(objectives))
+	actualMetrics := make(map[string]float64)
+	for _, o := range objectives {
+		if value, ok := values[o.Name]; ok {
+			compared = append(compared, o)
+			actualMetrics[o.Metric] = value
+		}
+	}
+	actual, ok := EvaluateObjectives(compared, actualMetrics)
+	if !ok {
+		return Counterfactual{}, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "decision objectives no longer defined", nil).
+			WithContext("strategy_id", decision.StrategyID)
+	}
+
+	horizon := decision.Timestamp.Sub(state.Timestamp)
+	if horizon < 0 {
+		horizon = 0
+	}
+	replayed, err := simulator.Simulate(ctx, state, horizon)
+	if err != nil {
+		return Counterfactual{}, err
+	}
+	predicted, ok := EvaluateObjectives(compared, StateMetrics(replayed))
+	if !ok {
+		return Counterfactual{}, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "simulated state lacks objective metrics", nil).
+			WithContext("strategy_id", decision.StrategyID)
+	}
+
+	cf := Counterfactual{
+		StrategyID:      decision.StrategyID,
+		Rules:           rules,
+		Timestamp:       decision.Timestamp,
+		Horizon:         horizon,
+		Actual:          actual.Score,
+		Predicted:       predicted.Score,
+		Effect:          actual.Score - predicted.Score,
+		ActualValues:    actual.Values,
+		PredictedValues: predicted.Values,
+	}
+	if before, ok := decision.Details[detailObjectiveBefore].(float64); ok {
+		cf.Before = before
+	}
+	return cf, nil
+}
+
+// treatmentEffects 汇总处理效应, 按平均效应升序, 相同时按ID排序
+func treatmentEffects(samples map[string][]float64) []TreatmentEffect {
+	effects := make([]TreatmentEffect, 0, len(samples))
+	for id, values := range samples {
+		n := float64(len(values))
+		sum := 0.0
+		for _, v := range values {
+			sum += v
+		}
+		effect := TreatmentEffect{ID: id, Decisions: len(values), Mean: sum / n}
+		if len(values) > 1 {
+			variance := 0.0
+			for _, v := range values {
+				variance += (v - effect.Mean) * (v - effect.Mean)
+			}
+			effect.StdErr = math.Sqrt(variance/(n-1)) / math.Sqrt(n)
+		}
+		effect.Retire = effect.Decisions >= minRetirementDecisions && effect.Mean+retirementZ*effect.StdErr <= 0
+		effects = append(effects, effect)
+	}
+	sort.Slice(effects, func(i, j int) bool {
+		if effects[i].Mean != effects[j].Mean {
+			return effects[i].Mean < effects[j].Mean
+		}
+		return effects[i].ID < effects[j].ID
+	})
+	return effects
+}
@@ -0,0 +1,32 @@
+// system/evolution/adaptation/drain.go
+
+package adaptation
+
+import (
+	"context"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// errLearningDraining 排空后拒绝新的学习周期
+func errLearningDraining() error {
+	return types.NewDomainError(types.DomainEvolution, types.ErrState, "adaptive learning draining", nil)
+}
+
+// Drain 等待进行中的学习周期完成, 此后Learn和Retrain返回错误; 在线学习的经验仍然记录
+func (al *AdaptiveLearning) Drain(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		al.mu.Lock()
+		defer al.mu.Unlock()
+		al.state.draining = true
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
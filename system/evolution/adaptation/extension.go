@@ -0,0 +1,116 @@
+// system/evolution/adaptation/extension.go
+
+package adaptation
+
+import (
+	"context"
+
+	"github.com/Corphon/daoflow/system/evolution/extension"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 外部策略
+const (
+	// StrategyTypeExternal 由扩展决策的策略类型
+	StrategyTypeExternal = "external"
+	// ParamExtension 策略参数中的扩展名称
+	ParamExtension = "extension"
+)
+
+// SetExtensions 设置扩展管理器, 启用外部策略
+func (as *AdaptationStrategy) SetExtensions(extensions *extension.Manager) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.extensions = extensions
+}
+
+// validateExternalStrategy 验证外部策略
+func (as *AdaptationStrategy) validateExternalStrategy(strategy *Strategy) error {
+	name, _ := strategy.Parameters[ParamExtension].(string)
+	if name == "" {
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "external strategy without extension name", nil)
+	}
+	return nil
+}
+
+// executeExtension 由扩展评估当前状态并执行返回的动作
+func (as *AdaptationStrategy) executeExtension(strategy *Strategy, state *types.SystemState) error {
+	if as.extensions == nil {
+		return types.NewDomainError(types.DomainEvolution, types.ErrConfig, "extensions not configured", nil)
+	}
+	name, _ := strategy.Parameters[ParamExtension].(string)
+
+	action, err := as.extensions.Evaluate(context.Background(), name, evaluationContext(strategy, state))
+	if err != nil {
+		return err
+	}
+
+	as.recordStrategyEvent(strategy, "extension_decision", map[string]interface{}{
+		"extension":  name,
+		"operation":  action.Operation,
+		"target":     action.Target,
+		"confidence": action.Confidence,
+		"reason":     action.Reason,
+	})
+
+	if action.Operation == extension.OperationNone {
+		return nil
+	}
+
+	params := make(map[string]interface{}, len(action.Parameters)+1)
+	for k, v := range action.Parameters {
+		params[k] = v
+	}
+
+	return as.executeAction(strategy, StrategyAction{
+		Type:       StrategyTypeExternal,
+		Target:     action.Target,
+		Operation:  action.Operation,
+		Parameters: params,
+	}, state)
+}
+
+// evaluationContext 构建扩展评估上下文
+func evaluationContext(strategy *Strategy, state *types.SystemState) extension.EvaluationContext {
+	ec := extension.EvaluationContext{
+		Timestamp:  state.Timestamp,
+		Energy:     state.Energy,
+		Metrics:    make(map[string]float64),
+		Labels:     make(map[string]string),
+		Parameters: make(map[string]interface{}, len(strategy.Parameters)),
+	}
+
+	ec.Metrics["entropy"] = state.Entropy
+	ec.Metrics["harmony"] = state.Harmony
+	ec.Metrics["balance"] = state.Balance
+	ec.Metrics["stability"] = state.Stability
+	ec.Metrics["yin_yang"] = state.YinYang
+	ec.Metrics["wuxing_energy"] = state.WuXingEnergy
+	ec.Metrics["bagua_energy"] = state.BaGuaEnergy
+	ec.Metrics["ganzhi_energy"] = state.GanZhiEnergy
+
+	// 只传递可序列化的标量属性
+	for k, v := range state.Properties {
+		switch val := v.(type) {
+		case float64:
+			ec.Metrics[k] = val
+		case int:
+			ec.Metrics[k] = float64(val)
+		case bool:
+			if val {
+				ec.Metrics[k] = 1
+			} else {
+				ec.Metrics[k] = 0
+			}
+		case string:
+			ec.Labels[k] = val
+		}
+	}
+
+	for k, v := range strategy.Parameters {
+		if k != ParamExtension {
+			ec.Parameters[k] = v
+		}
+	}
+	return ec
+}
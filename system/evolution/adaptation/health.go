@@ -0,0 +1,59 @@
+// system/evolution/adaptation/health.go
+
+package adaptation
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 学习健康度分量的权重
+const (
+	healthWeightSuccess  = 0.4 // 经验成功率
+	healthWeightAccuracy = 0.3 // 模型平均准确率
+	healthWeightTrend    = 0.2 // 模型准确率变化趋势
+	healthWeightGrowth   = 0.1 // 知识增长
+)
+
+// HealthComponents 学习质量的健康度分量, 尚无经验时不报告分量
+// 准确率下降和知识缩减扣分, 提升不额外加分
+func (al *AdaptiveLearning) HealthComponents() []types.HealthComponent {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	stats := al.state.statistics
+	if stats.TotalExperiences == 0 {
+		return nil
+	}
+
+	components := []types.HealthComponent{{
+		Name:   "learning_success_rate",
+		Score:  stats.SuccessRate,
+		Weight: healthWeightSuccess,
+		Detail: fmt.Sprintf("%d experiences", stats.TotalExperiences),
+	}}
+	if accuracy, ok := meanAccuracy(stats.ModelAccuracy); ok {
+		components = append(components,
+			types.HealthComponent{
+				Name:   "model_accuracy",
+				Score:  accuracy,
+				Weight: healthWeightAccuracy,
+				Detail: fmt.Sprintf("%d models", len(stats.ModelAccuracy)),
+			},
+			types.HealthComponent{
+				Name:   "model_accuracy_trend",
+				Score:  1 + math.Min(0, stats.AccuracyTrend)*2,
+				Weight: healthWeightTrend,
+				Detail: fmt.Sprintf("%+.3f", stats.AccuracyTrend),
+			})
+	}
+	components = append(components, types.HealthComponent{
+		Name:   "knowledge_growth",
+		Score:  1 + math.Min(0, stats.KnowledgeGrowth),
+		Weight: healthWeightGrowth,
+		Detail: fmt.Sprintf("%+.3f", stats.KnowledgeGrowth),
+	})
+	return components
+}
@@ -0,0 +1,172 @@
+// system/evolution/adaptation/importance.go
+
+package adaptation
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+)
+
+// 特征重要性默认参数
+const (
+	defaultPruneThreshold = 1e-3 // 权重绝对值和置换重要性都低于该值的特征被剪除
+	permutationRepeats    = 3    // 置换重要性的重复次数
+	maxPrunedHistory      = 100  // 每个模型保留的剪枝特征记录数
+)
+
+// FeatureImportance 模型特征的重要性
+type FeatureImportance struct {
+	Feature     string  `json:"feature"`
+	Weight      float64 `json:"weight"`      // 当前权重
+	Magnitude   float64 `json:"magnitude"`   // 权重绝对值占全部权重绝对值之和的比例
+	Permutation float64 `json:"permutation"` // 留出数据上打乱该特征后加权损失的增加量
+}
+
+// FeatureReport 模型的特征报告
+type FeatureReport struct {
+	ModelID      string              `json:"model_id"`
+	Type         string              `json:"type"`
+	Features     int                 `json:"features"`      // 当前特征数
+	Holdout      int                 `json:"holdout"`       // 计算置换重要性的留出样本数
+	BaselineLoss float64             `json:"baseline_loss"` // 留出数据上的加权损失
+	Top          []FeatureImportance `json:"top"`           // 按置换重要性和权重比例降序
+	Pruned       []string            `json:"pruned"`        // 最近被剪除的特征
+	Updated      time.Time           `json:"updated"`
+}
+
+// GetFeatureReport 获取各模型的特征报告, 每个模型列出前top个特征(top<=0时列出全部), 按模型ID排序
+func (al *AdaptiveLearning) GetFeatureReport(top int) []FeatureReport {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	reports := make([]FeatureReport, 0, len(al.state.models))
+	for id, model := range al.state.models {
+		importance := model.State.Importance
+		if top > 0 && len(importance) > top {
+			importance = importance[:top]
+		}
+		reports = append(reports, FeatureReport{
+			ModelID:      id,
+			Type:         model.Type,
+			Features:     len(model.State.Weights),
+			Holdout:      model.State.Holdout,
+			BaselineLoss: model.State.HoldoutLoss,
+			Top:          append([]FeatureImportance(nil), importance...),
+			Pruned:       append([]string(nil), model.State.Pruned...),
+			Updated:      model.State.ImportanceUpdated,
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].ModelID < reports[j].ModelID
+	})
+	return reports
+}
+
+// updateFeatureImportance 在验证集上计算模型的特征重要性, 并剪除接近零的特征
+// 数据不足以划分验证集时使用训练数据
+func (al *AdaptiveLearning) updateFeatureImportance(model *LearningModel) {
+	holdout := model.State.ValidationData
+	if len(holdout) == 0 {
+		holdout = model.State.TrainingData
+	}
+	rng := rand.New(rand.NewSource(int64(model.State.Version)))
+	importance, baseline := featureImportance(model.State.Weights, holdout, rng)
+
+	// 剪除权重和置换重要性都接近零的特征
+	kept := importance[:0]
+	for _, fi := range importance {
+		if math.Abs(fi.Weight) < al.config.pruneThreshold && fi.Permutation < al.config.pruneThreshold {
+			delete(model.State.Weights, fi.Feature)
+			delete(model.State.Gradients, fi.Feature)
+			delete(model.State.PrevGradients, fi.Feature)
+			model.State.Pruned = append(model.State.Pruned, fi.Feature)
+			continue
+		}
+		kept = append(kept, fi)
+	}
+	if over := len(model.State.Pruned) - maxPrunedHistory; over > 0 {
+		model.State.Pruned = append([]string(nil), model.State.Pruned[over:]...)
+	}
+
+	model.State.Importance = kept
+	model.State.Holdout = len(holdout)
+	model.State.HoldoutLoss = baseline
+	model.State.ImportanceUpdated = time.Now()
+}
+
+// featureImportance 计算各特征的权重比例和置换重要性, 返回按重要性降序的列表和留出数据的基准损失
+// 置换时只替换被打乱特征对加权和的贡献, 与forwardPropagate的计算一致
+func featureImportance(weights map[string]float64, holdout []TrainingItem, rng *rand.Rand) ([]FeatureImportance, float64) {
+	keys := getSortedKeys(weights)
+	total := 0.0
+	for _, key := range keys {
+		total += math.Abs(weights[key])
+	}
+
+	// 留出样本的特征值和加权和
+	values := make([][]float64, len(keys))
+	sums := make([]float64, len(holdout))
+	for f, key := range keys {
+		values[f] = make([]float64, len(holdout))
+		for i, item := range holdout {
+			if v, ok := item.Input[key].(float64); ok {
+				values[f][i] = v
+				sums[i] += v * weights[key]
+			}
+		}
+	}
+	baseline := holdoutLoss(holdout, sums, nil)
+
+	importance := make([]FeatureImportance, len(keys))
+	perm := make([]int, len(holdout))
+	for f, key := range keys {
+		fi := FeatureImportance{Feature: key, Weight: weights[key]}
+		if total > 0 {
+			fi.Magnitude = math.Abs(weights[key]) / total
+		}
+		if len(holdout) > 1 {
+			delta := 0.0
+			for r := 0; r < permutationRepeats; r++ {
+				for i := range perm {
+					perm[i] = i
+				}
+				rng.Shuffle(len(perm), func(i, j int) { perm[i], perm[j] = perm[j], perm[i] })
+				shifted := make([]float64, len(holdout))
+				for i := range holdout {
+					shifted[i] = (values[f][perm[i]] - values[f][i]) * weights[key]
+				}
+				delta += holdoutLoss(holdout, sums, shifted) - baseline
+			}
+			fi.Permutation = math.Max(0, delta/permutationRepeats)
+		}
+		importance[f] = fi
+	}
+
+	sort.SliceStable(importance, func(i, j int) bool {
+		if importance[i].Permutation != importance[j].Permutation {
+			return importance[i].Permutation > importance[j].Permutation
+		}
+		return importance[i].Magnitude > importance[j].Magnitude
+	})
+	return importance, baseline
+}
+
+// holdoutLoss 留出数据的加权损失, shift非空时叠加到各样本的加权和
+func holdoutLoss(holdout []TrainingItem, sums, shift []float64) float64 {
+	totalLoss, totalWeight := 0.0, 0.0
+	for i, item := range holdout {
+		sum := sums[i]
+		if shift != nil {
+			sum += shift[i]
+		}
+		pred := 1.0 / (1.0 + math.Exp(-sum))
+		totalLoss += calculateItemLoss(pred, getExpectedValue(item.Output)) * item.Weight
+		totalWeight += item.Weight
+	}
+	if totalWeight == 0 {
+		return 0
+	}
+	return totalLoss / totalWeight
+}
@@ -3,7 +3,6 @@
 package adaptation
 
 import (
-	"fmt"
 	"math"
 	"math/rand"
 	"sort"
@@ -13,6 +12,7 @@ import (
 
 	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/system/evolution/pattern"
+	"github.com/Corphon/daoflow/system/invariants"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -26,10 +26,17 @@ type AdaptiveLearning struct {
 
 	// 基础配置
 	config struct {
-		learningRate    float64 // 学习率
-		memoryCapacity  int     // 记忆容量
-		explorationRate float64 // 探索率
-		decayFactor     float64 // 衰减因子
+		learningRate    float64         // 学习率
+		memoryCapacity  int             // 记忆容量
+		explorationRate float64         // 探索率
+		decayFactor     float64         // 衰减因子
+		namespace       types.Namespace // 所属命名空间
+		validationRatio float64         // 验证集比例
+		pruneThreshold  float64         // 特征剪枝阈值
+		patience        int             // 早停前允许验证损失不改善的轮数
+		minDelta        float64         // 验证损失视为改善的最小降幅
+		online          bool            // 在线学习模式
+		onlineBatch     int             // 在线学习的小批量大小
 	}
 
 	// 学习状态
@@ -39,11 +46,25 @@ type AdaptiveLearning struct {
 		models             map[string]*LearningModel // 学习模型
 		statistics         LearningStatistics        // 学习统计
 		prevKnowledgeCount int                       // 上次知识数量
+		pending            map[string][]TrainingItem // 在线学习中未满小批量的样本
+		draining           bool                      // 关闭前排空中, 不再开始新的学习周期
 	}
 
 	// 依赖项
 	strategy *AdaptationStrategy
 	matcher  *pattern.EvolutionMatcher
+
+	// 强化学习智能体, nil时按经验模式调整策略
+	rl *reinforcementAgent
+
+	// 规则生成的去重、配额和冷却
+	ruleGen *ruleGenerator
+
+	// 共享导出的隐私保护, nil时不保护
+	privacy *Privacy
+
+	// 经验缓冲区满时的保留策略
+	retention *experienceRetention
 }
 
 // KnowledgeUnit 知识单元
@@ -59,6 +80,7 @@ type KnowledgeUnit struct {
 
 // KnowledgeMetadata 知识元数据
 type KnowledgeMetadata struct {
+	Namespace  string    // 所属命名空间
 	Source     string    // 知识来源
 	Confidence float64   // 置信度
 	Usage      int       // 使用次数
@@ -82,6 +104,7 @@ type LearningExperience struct {
 	Action    LearningAction         // 执行动作
 	Result    LearningResult         // 执行结果
 	Feedback  float64                // 反馈值
+	Scored    bool                   // 反馈是否按适应目标评分
 	Timestamp time.Time              // 记录时间
 	Context   map[string]interface{} // 上下文信息
 }
@@ -120,13 +143,22 @@ type LearningModel struct {
 
 // ModelState 模型状态
 type ModelState struct {
-	Version       int                // 版本号
-	TrainingData  []TrainingItem     // 训练数据
-	Weights       map[string]float64 // 模型权重
-	LastUpdate    time.Time          // 最后更新
-	LastLoss      float64            // 最后损失值
-	Gradients     map[string]float64 // 梯度信息
-	PrevGradients map[string]float64 // 前一次梯度(用于动量计算)
+	Version        int                // 版本号
+	TrainingData   []TrainingItem     // 训练数据
+	ValidationData []TrainingItem     // 验证数据, 不参与训练
+	Weights        map[string]float64 // 模型权重
+	LastUpdate     time.Time          // 最后更新
+	LastLoss       float64            // 最后损失值
+	Gradients      map[string]float64 // 梯度信息
+	PrevGradients  map[string]float64 // 前一次梯度(用于动量计算)
+	OnlineSamples  int                // 在线学习接收的样本数
+	OnlineUpdates  int                // 在线学习的权重更新次数
+
+	Importance        []FeatureImportance // 特征重要性, 按重要性降序
+	Pruned            []string            // 最近被剪除的特征
+	Holdout           int                 // 计算重要性的留出样本数
+	HoldoutLoss       float64             // 留出数据上的加权损失
+	ImportanceUpdated time.Time           // 重要性更新时间
 }
 
 // ModelPerformance 模型性能
@@ -135,6 +167,12 @@ type ModelPerformance struct {
 	Loss     float64            // 损失值
 	History  []PerformancePoint // 历史表现
 	Details  TrainingDetails    // 训练细节
+
+	ValidationAccuracy float64 // 验证集准确率
+	ValidationLoss     float64 // 验证集损失值
+	Epochs             int     // 最近一次训练的轮数
+	BestEpoch          int     // 验证损失最低的轮数, 训练结束时恢复该轮的权重
+	StoppedEarly       bool    // 最近一次训练是否早停
 }
 
 // PerformancePoint 性能记录点
@@ -159,8 +197,10 @@ type TrainingItem struct {
 type LearningStatistics struct {
 	TotalExperiences int                // 总经验数
 	SuccessRate      float64            // 成功率
+	ObjectiveScore   float64            // 平均反馈值, 定义了适应目标时为目标达成度
 	KnowledgeGrowth  float64            // 知识增长率
 	ModelAccuracy    map[string]float64 // 模型准确率
+	AccuracyTrend    float64            // 模型平均准确率相对上次统计的变化
 }
 
 // PatternCondition 模式条件
@@ -218,18 +258,66 @@ type ExperiencePattern struct {
 // NewAdaptiveLearning 创建新的适应性学习系统
 func NewAdaptiveLearning(matcher *pattern.EvolutionMatcher, config *types.AdaptationConfig) (*AdaptiveLearning, error) {
 	if matcher == nil {
-		return nil, fmt.Errorf("nil evolution matcher")
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil evolution matcher", nil)
 	}
 	if config == nil {
-		return nil, fmt.Errorf("nil adaptation config")
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil adaptation config", nil)
 	}
 
 	al := &AdaptiveLearning{
 		matcher: matcher,
+		ruleGen: newRuleGenerator(config.RuleGeneration),
 	}
 
-	// 初始化配置和状态
-	// ...
+	// 初始化配置
+	al.config.learningRate = config.Learning.LearningRate
+	al.config.memoryCapacity = config.Learning.MemoryCapacity
+	al.config.explorationRate = config.Learning.ExplorationRate
+	al.config.decayFactor = config.Learning.DecayFactor
+	al.config.namespace = types.DefaultNamespace
+	if al.config.memoryCapacity <= 0 {
+		al.config.memoryCapacity = types.DefaultCapacity
+	}
+	al.config.validationRatio = config.Model.ValidationRatio
+	if al.config.validationRatio <= 0 || al.config.validationRatio >= 1 {
+		al.config.validationRatio = defaultValidationRatio
+	}
+	al.config.pruneThreshold = config.Model.PruneThreshold
+	if al.config.pruneThreshold <= 0 {
+		al.config.pruneThreshold = defaultPruneThreshold
+	}
+	al.config.patience = config.Model.Patience
+	if al.config.patience <= 0 {
+		al.config.patience = defaultPatience
+	}
+	al.config.minDelta = config.Model.MinDelta
+	if al.config.minDelta <= 0 {
+		al.config.minDelta = defaultMinDelta
+	}
+	al.config.online = config.Model.Online
+	al.config.onlineBatch = config.Model.OnlineBatch
+	if al.config.onlineBatch <= 0 {
+		al.config.onlineBatch = defaultOnlineBatch
+	}
+	if config.Reinforcement.Enabled {
+		agent, err := newReinforcementAgent(config.Reinforcement)
+		if err != nil {
+			return nil, err
+		}
+		al.rl = agent
+	}
+	retention, err := newExperienceRetention(config.ExperienceRetention)
+	if err != nil {
+		return nil, err
+	}
+	al.retention = retention
+
+	// 初始化状态
+	al.state.knowledge = make(map[string]*KnowledgeUnit)
+	al.state.experiences = make([]LearningExperience, 0)
+	al.state.models = make(map[string]*LearningModel)
+	al.state.statistics.ModelAccuracy = make(map[string]float64)
+	al.state.pending = make(map[string][]TrainingItem)
 
 	return al, nil
 }
@@ -239,6 +327,10 @@ func (al *AdaptiveLearning) Learn() error {
 	al.mu.Lock()
 	defer al.mu.Unlock()
 
+	if al.state.draining {
+		return errLearningDraining()
+	}
+
 	// 收集学习经验
 	if err := al.collectExperiences(); err != nil {
 		return err
@@ -249,8 +341,10 @@ func (al *AdaptiveLearning) Learn() error {
 		return err
 	}
 
-	// 训练模型
-	if err := al.trainModels(); err != nil {
+	// 训练模型, 在线模式下权重已随经验增量更新, 只评估模型
+	if al.config.online {
+		al.evaluateModels()
+	} else if err := al.trainModels(); err != nil {
 		return err
 	}
 
@@ -265,6 +359,35 @@ func (al *AdaptiveLearning) Learn() error {
 	return nil
 }
 
+// Retrain 以累积的训练数据重新训练全部模型, 在线模式下同样执行
+// 用于数据分布漂移后使模型适应新分布
+func (al *AdaptiveLearning) Retrain() error {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if al.state.draining {
+		return errLearningDraining()
+	}
+
+	if err := al.trainModels(); err != nil {
+		return err
+	}
+	al.updateStatistics()
+	return nil
+}
+
+// ModelAccuracy 获取各模型最近一次统计的准确率
+func (al *AdaptiveLearning) ModelAccuracy() map[string]float64 {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	accuracy := make(map[string]float64, len(al.state.statistics.ModelAccuracy))
+	for id, a := range al.state.statistics.ModelAccuracy {
+		accuracy[id] = a
+	}
+	return accuracy
+}
+
 // updateStatistics 更新学习统计信息
 func (al *AdaptiveLearning) updateStatistics() {
 	stats := &al.state.statistics
@@ -272,15 +395,18 @@ func (al *AdaptiveLearning) updateStatistics() {
 	// 更新基础统计
 	stats.TotalExperiences = len(al.state.experiences)
 
-	// 计算成功率
+	// 计算成功率和平均反馈
 	successCount := 0
+	feedback := 0.0
 	for _, exp := range al.state.experiences {
-		if exp.Result.Status == "success" {
+		if experienceSucceeded(exp) {
 			successCount++
 		}
+		feedback += experienceFeedback(exp)
 	}
 	if stats.TotalExperiences > 0 {
 		stats.SuccessRate = float64(successCount) / float64(stats.TotalExperiences)
+		stats.ObjectiveScore = feedback / float64(stats.TotalExperiences)
 	}
 
 	// 计算知识增长率
@@ -291,10 +417,26 @@ func (al *AdaptiveLearning) updateStatistics() {
 	}
 	al.state.prevKnowledgeCount = currentKnowledge
 
-	// 更新模型准确率
+	// 更新模型准确率及其变化趋势
+	prevAccuracy, hadAccuracy := meanAccuracy(stats.ModelAccuracy)
 	for id, model := range al.state.models {
 		stats.ModelAccuracy[id] = model.Performance.Accuracy
 	}
+	if accuracy, ok := meanAccuracy(stats.ModelAccuracy); ok && hadAccuracy {
+		stats.AccuracyTrend = accuracy - prevAccuracy
+	}
+}
+
+// meanAccuracy 模型的平均准确率, 没有模型时返回false
+func meanAccuracy(accuracy map[string]float64) (float64, bool) {
+	if len(accuracy) == 0 {
+		return 0, false
+	}
+	total := 0.0
+	for _, a := range accuracy {
+		total += a
+	}
+	return total / float64(len(accuracy)), true
 }
 
 // collectExperiences 收集学习经验
@@ -323,6 +465,16 @@ func (al *AdaptiveLearning) GetLearningRate() float64 {
 	return al.config.learningRate
 }
 
+// SetLearningRate 直接设置学习率, 不应用性能调整和衰减, 非正值忽略
+func (al *AdaptiveLearning) SetLearningRate(rate float64) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if rate > 0 {
+		al.config.learningRate = rate
+	}
+}
+
 // UpdateLearningRate 更新学习率
 func (al *AdaptiveLearning) UpdateLearningRate(baseRate float64) {
 	al.mu.Lock()
@@ -348,10 +500,90 @@ func (al *AdaptiveLearning) UpdateLearningRate(baseRate float64) {
 	al.config.learningRate = baseRate * al.config.decayFactor
 }
 
+// SetNamespace 设置学习系统所属命名空间
+func (al *AdaptiveLearning) SetNamespace(ns types.Namespace) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.config.namespace = ns
+}
+
+// GetNamespace 获取学习系统所属命名空间
+func (al *AdaptiveLearning) GetNamespace() types.Namespace {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+	return al.config.namespace
+}
+
+// ApplyNamespaceConfig 应用命名空间配置
+func (al *AdaptiveLearning) ApplyNamespaceConfig(cfg *types.NamespaceConfig) {
+	if cfg == nil {
+		return
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	al.config.namespace = cfg.Name
+	if cfg.Learning.LearningRate > 0 {
+		al.config.learningRate = cfg.Learning.LearningRate
+	}
+	if cfg.Learning.ExplorationRate > 0 {
+		al.config.explorationRate = cfg.Learning.ExplorationRate
+	}
+}
+
+// ShareKnowledge 按命名空间策略向目标学习系统共享知识, 返回共享数量
+func (al *AdaptiveLearning) ShareKnowledge(target *AdaptiveLearning, authorizer types.NamespaceAuthorizer) (int, error) {
+	if target == nil || target == al {
+		return 0, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "invalid share target", nil)
+	}
+
+	from := al.GetNamespace()
+	to := target.GetNamespace()
+	if from != to && (authorizer == nil || !authorizer.Allows(from, to, types.NamespaceOpKnowledge)) {
+		return 0, types.NewDomainError(types.DomainEvolution, types.ErrPermission, "knowledge sharing denied", nil).
+			WithContext("from", from).
+			WithContext("to", to)
+	}
+
+	// 复制源知识
+	al.mu.RLock()
+	units := make([]*KnowledgeUnit, 0, len(al.state.knowledge))
+	for _, unit := range al.state.knowledge {
+		clone := *unit
+		clone.Connections = append([]KnowledgeLink(nil), unit.Connections...)
+		clone.Metadata.Tags = append([]string(nil), unit.Metadata.Tags...)
+		units = append(units, &clone)
+	}
+	privacy := al.privacy
+	al.mu.RUnlock()
+
+	// 跨命名空间共享时按K-匿名过滤上下文字段
+	if from != to && privacy.Enabled() {
+		units = anonymizeUnits(units, privacy.Config().KAnonymity)
+	}
+
+	// 写入目标, 保留来源命名空间
+	target.mu.Lock()
+	defer target.mu.Unlock()
+
+	shared := 0
+	for _, unit := range units {
+		if _, exists := target.state.knowledge[unit.ID]; exists {
+			continue
+		}
+		unit.Metadata.Tags = append(unit.Metadata.Tags, "shared")
+		target.state.knowledge[unit.ID] = unit
+		shared++
+	}
+
+	return shared, nil
+}
+
 // createExperience 创建学习经验
 func (al *AdaptiveLearning) createExperience(event StrategyEvent) LearningExperience {
 	experience := LearningExperience{
-		ID:        fmt.Sprintf("exp_%d", time.Now().UnixNano()),
+		ID:        core.NewID("exp_"),
 		Type:      "strategy_execution",
 		Timestamp: event.Timestamp,
 		Context:   make(map[string]interface{}),
@@ -363,6 +595,14 @@ func (al *AdaptiveLearning) createExperience(event StrategyEvent) LearningExperi
 		},
 	}
 
+	// 按适应目标评分的反馈
+	experience.Feedback, experience.Scored = eventScore(event)
+	if values, ok := event.Details[detailObjectiveValues].(map[string]float64); ok {
+		for name, v := range values {
+			experience.Result.Metrics[name] = v
+		}
+	}
+
 	// 提取上下文信息
 	if strategy, exists := al.strategy.state.strategies[event.StrategyID]; exists {
 		experience.Context["strategy_type"] = strategy.Type
@@ -373,6 +613,30 @@ func (al *AdaptiveLearning) createExperience(event StrategyEvent) LearningExperi
 	return experience
 }
 
+// experienceFeedback 经验的反馈值: 按适应目标评分时为目标达成度, 否则成功为1失败为0
+func experienceFeedback(exp LearningExperience) float64 {
+	if exp.Scored {
+		return exp.Feedback
+	}
+	if exp.Result.Status == "success" {
+		return 1
+	}
+	return 0
+}
+
+// experienceSucceeded 经验是否成功
+func experienceSucceeded(exp LearningExperience) bool {
+	return experienceFeedback(exp) >= successFeedback
+}
+
+// experienceFailed 经验是否失败, 按目标评分时达成度不足即为失败
+func experienceFailed(exp LearningExperience) bool {
+	if exp.Scored {
+		return exp.Feedback < successFeedback
+	}
+	return exp.Result.Status == "failure"
+}
+
 // updateKnowledge 更新知识库
 func (al *AdaptiveLearning) updateKnowledge() error {
 	// 分析新经验
@@ -455,7 +719,7 @@ func calculateSuccessFrequency(experiences []LearningExperience) float64 {
 
 	successCount := 0
 	for _, exp := range experiences {
-		if exp.Result.Status == "success" {
+		if experienceSucceeded(exp) {
 			successCount++
 		}
 	}
@@ -493,7 +757,7 @@ func extractSuccessConditions(experiences []LearningExperience) []PatternConditi
 
 	// 分析前置条件
 	for _, exp := range experiences {
-		if exp.Result.Status == "success" {
+		if experienceSucceeded(exp) {
 			for k, v := range exp.Context {
 				if isSignificantCondition(k, v, experiences) {
 					conditions = append(conditions, PatternCondition{
@@ -564,7 +828,7 @@ func extractPositiveOutcomes(experiences []LearningExperience) []PatternOutcome
 
 	// 分析成功经验的结果
 	for _, exp := range experiences {
-		if exp.Result.Status == "success" {
+		if experienceSucceeded(exp) {
 			if metrics := extractSignificantMetrics(exp.Result.Metrics); len(metrics) > 0 {
 				outcomes = append(outcomes, PatternOutcome{
 					Type:    "metrics",
@@ -714,7 +978,7 @@ func isSignificantCondition(key string, value interface{}, experiences []Learnin
 
 	for _, exp := range experiences {
 		if v, exists := exp.Context[key]; exists && v == value {
-			if exp.Result.Status == "success" {
+			if experienceSucceeded(exp) {
 				successCount++
 			}
 			totalCount++
@@ -730,7 +994,7 @@ func calculateConditionWeight(key string, value interface{}, experiences []Learn
 
 	for _, exp := range experiences {
 		if v, exists := exp.Context[key]; exists && v == value {
-			if exp.Result.Status == "success" {
+			if experienceSucceeded(exp) {
 				successCount++
 			}
 			totalCount++
@@ -771,7 +1035,7 @@ func calculateFailureFrequency(experiences []LearningExperience) float64 {
 
 	failureCount := 0
 	for _, exp := range experiences {
-		if exp.Result.Status == "failure" {
+		if experienceFailed(exp) {
 			failureCount++
 		}
 	}
@@ -785,7 +1049,7 @@ func extractFailureConditions(experiences []LearningExperience) []PatternConditi
 
 	// 分析失败前置条件
 	for _, exp := range experiences {
-		if exp.Result.Status == "failure" {
+		if experienceFailed(exp) {
 			for k, v := range exp.Context {
 				if isSignificantCondition(k, v, experiences) {
 					conditions = append(conditions, PatternCondition{
@@ -808,7 +1072,7 @@ func extractNegativeOutcomes(experiences []LearningExperience) []PatternOutcome
 
 	// 分析失败经验的结果
 	for _, exp := range experiences {
-		if exp.Result.Status == "failure" {
+		if experienceFailed(exp) {
 			if metrics := extractSignificantMetrics(exp.Result.Metrics); len(metrics) > 0 {
 				outcomes = append(outcomes, PatternOutcome{
 					Type:    "metrics",
@@ -945,7 +1209,7 @@ func isAdaptiveAction(exp LearningExperience) bool {
 }
 
 func isAdaptationSuccess(exp LearningExperience) bool {
-	return exp.Result.Status == "success" && isAdaptiveAction(exp)
+	return experienceSucceeded(exp) && isAdaptiveAction(exp)
 }
 
 func extractEnvironmentState(experiences []LearningExperience) map[string]float64 {
@@ -1005,7 +1269,7 @@ func extractAdaptationStrategies(experiences []LearningExperience) []string {
 func calculatePatternConfidence(experiences []LearningExperience) float64 {
 	total := 0.0
 	for _, exp := range experiences {
-		if exp.Result.Status == "success" {
+		if experienceSucceeded(exp) {
 			total += 1.0
 		}
 	}
@@ -1025,6 +1289,7 @@ func (al *AdaptiveLearning) extractKnowledge(pattern ExperiencePattern) *Knowled
 		Type:    pattern.Type,
 		Content: pattern,
 		Metadata: KnowledgeMetadata{
+			Namespace:  string(al.config.namespace),
 			Source:     "experience_analysis",
 			Confidence: pattern.Confidence,
 			Usage:      0,
@@ -1269,6 +1534,9 @@ func (al *AdaptiveLearning) trainModels() error {
 
 		// 评估模型性能
 		al.evaluateModel(model)
+
+		// 计算特征重要性并剪除接近零的特征
+		al.updateFeatureImportance(model)
 	}
 
 	return nil
@@ -1298,31 +1566,69 @@ func (al *AdaptiveLearning) prepareTrainingData(model *LearningModel) []Training
 // trainModel 执行模型训练
 func (al *AdaptiveLearning) trainModel(model *LearningModel, data []TrainingItem) error {
 	if len(data) == 0 {
-		return fmt.Errorf("no training data")
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalidState, "no training data", nil)
 	}
 
-	// 更新训练状态
+	// 更新训练状态, 划分训练集和验证集
 	model.State.Version++
-	model.State.TrainingData = data
+	train, validation := splitTrainingData(data, al.config.validationRatio, int64(model.State.Version))
+	model.State.TrainingData = train
+	model.State.ValidationData = validation
 	model.State.LastUpdate = time.Now()
 
 	// 配置训练参数
-	batchSize := calculateBatchSize(len(data))
-	iterations := calculateIterations(len(data))
+	batchSize := calculateBatchSize(len(train))
+	iterations := calculateIterations(len(train))
+	epochSize := max(1, (len(train)+batchSize-1)/batchSize)
 
-	// 执行训练
+	// 执行训练, 每轮记录训练和验证损失, 验证损失连续patience轮未改善时早停
 	startTime := time.Now()
+	stopper := newEarlyStopping(al.config.patience, al.config.minDelta)
+	epoch, completed := 0, 0
 	for i := 0; i < iterations; i++ {
-		batch := selectBatch(data, batchSize)
+		batch := selectBatch(train, batchSize)
 		if err := trainBatch(model, batch); err != nil {
 			return err
 		}
 		updateModelWeights(model)
+		completed++
+
+		if (i+1)%epochSize != 0 && i != iterations-1 {
+			continue
+		}
+		epoch++
+		trainLoss := datasetLoss(model, train)
+		point := PerformancePoint{
+			Time: time.Now(),
+			Metrics: map[string]float64{
+				"epoch":      float64(epoch),
+				"train_loss": trainLoss,
+			},
+		}
+		stop := false
+		if len(validation) > 0 {
+			validationLoss := datasetLoss(model, validation)
+			point.Metrics["validation_loss"] = validationLoss
+			stop = stopper.observe(epoch, validationLoss, model.State.Weights)
+		}
+		recordPerformance(model, point)
+		if stop {
+			break
+		}
+	}
+
+	// 恢复验证损失最低时的权重
+	model.Performance.Epochs = epoch
+	model.Performance.StoppedEarly = completed < iterations
+	model.Performance.BestEpoch = epoch
+	if stopper.weights != nil {
+		model.State.Weights = stopper.weights
+		model.Performance.BestEpoch = stopper.bestEpoch
 	}
 
 	// 记录训练详情
 	model.Performance.Details.BatchSize = batchSize
-	model.Performance.Details.Iterations = iterations
+	model.Performance.Details.Iterations = completed
 	model.Performance.Details.Duration = time.Since(startTime).Seconds()
 
 	return nil
@@ -1346,11 +1652,15 @@ func (al *AdaptiveLearning) evaluateModel(model *LearningModel) {
 		Details: model.Performance.Details,
 	}
 
-	// 维护历史记录长度
-	model.Performance.History = append(model.Performance.History, point)
-	if len(model.Performance.History) > maxModelHistory {
-		model.Performance.History = model.Performance.History[1:]
+	// 验证集表现
+	if validation := model.State.ValidationData; len(validation) > 0 {
+		model.Performance.ValidationAccuracy = datasetAccuracy(model, validation)
+		model.Performance.ValidationLoss = datasetLoss(model, validation)
+		point.Metrics["validation_accuracy"] = model.Performance.ValidationAccuracy
+		point.Metrics["validation_loss"] = model.Performance.ValidationLoss
 	}
+
+	recordPerformance(model, point)
 }
 
 // 辅助函数
@@ -1446,7 +1756,7 @@ func convertToPatternTraining(exp LearningExperience) *TrainingItem {
 
 	return &TrainingItem{
 		Input:  input,
-		Output: exp.Result.Status == "success",
+		Output: experienceFeedback(exp),
 		Weight: calculateExperienceWeight(exp),
 	}
 }
@@ -1474,7 +1784,7 @@ func convertToStrategyTraining(exp LearningExperience) *TrainingItem {
 
 	return &TrainingItem{
 		Input:  input,
-		Output: exp.Result.Status == "success",
+		Output: experienceFeedback(exp),
 		Weight: calculateExperienceWeight(exp),
 	}
 }
@@ -1556,7 +1866,7 @@ func trainBatch(model *LearningModel, batch []TrainingItem) error {
 		// 计算预测值
 		pred, err := forwardPropagate(model, item.Input)
 		if err != nil {
-			return fmt.Errorf("forward propagation failed: %v", err)
+			return types.NewDomainError(types.DomainEvolution, types.ErrRuntime, "forward propagation failed", err)
 		}
 		predictions[i] = pred
 	}
@@ -1617,14 +1927,19 @@ func updateModelWeights(model *LearningModel) {
 
 // calculateModelAccuracy 计算模型准确率
 func calculateModelAccuracy(model *LearningModel) float64 {
-	if len(model.State.TrainingData) == 0 {
+	return datasetAccuracy(model, model.State.TrainingData)
+}
+
+// datasetAccuracy 计算模型在数据集上的准确率
+func datasetAccuracy(model *LearningModel, data []TrainingItem) float64 {
+	if len(data) == 0 {
 		return 0
 	}
 
 	correctCount := 0
 	totalCount := 0
 
-	for _, item := range model.State.TrainingData {
+	for _, item := range data {
 		// 获取预测值
 		pred, err := forwardPropagate(model, item.Input)
 		if err != nil {
@@ -1647,14 +1962,19 @@ func calculateModelAccuracy(model *LearningModel) float64 {
 
 // calculateModelLoss 计算模型损失值
 func calculateModelLoss(model *LearningModel) float64 {
-	if len(model.State.TrainingData) == 0 {
+	return datasetLoss(model, model.State.TrainingData)
+}
+
+// datasetLoss 计算模型在数据集上的加权损失值
+func datasetLoss(model *LearningModel, data []TrainingItem) float64 {
+	if len(data) == 0 {
 		return 1.0
 	}
 
 	totalLoss := 0.0
 	totalWeight := 0.0
 
-	for _, item := range model.State.TrainingData {
+	for _, item := range data {
 		// 获取预测值
 		pred, err := forwardPropagate(model, item.Input)
 		if err != nil {
@@ -1686,6 +2006,17 @@ func forwardPropagate(model *LearningModel, input map[string]interface{}) (float
 		}
 	}
 
+	// 校验输入特征
+	if invariants.Validating() {
+		values := make(map[string]float64, len(features))
+		for i, key := range getSortedKeys(model.State.Weights) {
+			values[key] = features[i]
+		}
+		if err := invariants.ValidateMetrics("learning.features", values); err != nil {
+			return 0, err
+		}
+	}
+
 	// 计算加权和
 	sum := 0.0
 	for i, feature := range features {
@@ -1693,7 +2024,11 @@ func forwardPropagate(model *LearningModel, input map[string]interface{}) (float
 	}
 
 	// 应用激活函数(sigmoid)
-	return 1.0 / (1.0 + math.Exp(-sum)), nil
+	prediction := 1.0 / (1.0 + math.Exp(-sum))
+	if err := invariants.ValidateValue("learning.prediction", "sigmoid", prediction); err != nil {
+		return 0, err
+	}
+	return prediction, nil
 }
 
 func backPropagate(model *LearningModel, input map[string]interface{},
@@ -1751,6 +2086,11 @@ func getSortedKeys(m map[string]float64) []string {
 
 // applyLearning 应用学习成果
 func (al *AdaptiveLearning) applyLearning() error {
+	// 强化学习模式下由智能体调整策略参数
+	if al.rl != nil {
+		return al.applyReinforcement()
+	}
+
 	// 更新策略参数
 	if err := al.updateStrategyParameters(); err != nil {
 		return err
@@ -1779,7 +2119,7 @@ func (al *AdaptiveLearning) updateStrategyParameters() error {
 
 	// 更新策略参数
 	for _, pattern := range successParams {
-		if err := al.strategy.UpdateParameters(pattern.Type, pattern.Parameters); err != nil {
+		if err := al.strategy.updateParameters(pattern.Type, pattern.Parameters, al.lineage()); err != nil {
 			continue
 		}
 	}
@@ -1791,6 +2131,7 @@ func (al *AdaptiveLearning) updateStrategyParameters() error {
 func (al *AdaptiveLearning) generateNewRules() error {
 	// 从经验中提取规则模式
 	patterns := al.analyzeRulePatterns()
+	al.ruleGen.begin(al.strategy.ruleSignatures())
 
 	// 生成新规则
 	for _, pattern := range patterns {
@@ -1803,10 +2144,17 @@ func (al *AdaptiveLearning) generateNewRules() error {
 			Weight:    calculateRuleWeight(pattern),
 		}
 
+		// 丢弃重复、超出配额或处于冷却期的规则
+		signature := ruleSignature(rule)
+		if !al.ruleGen.admit(rule.Target, signature) {
+			continue
+		}
+
 		// 注册新规则
-		if err := al.strategy.RegisterRule(rule); err != nil {
+		if err := al.strategy.registerRule(rule, al.lineage()); err != nil {
 			continue
 		}
+		al.ruleGen.registered(rule.Target, signature)
 	}
 
 	return nil
@@ -1956,7 +2304,7 @@ func extractRuleParameters(experiences []LearningExperience, ruleType string) ma
 		totalCount++
 
 		// 基础统计
-		if exp.Result.Status == "success" {
+		if experienceSucceeded(exp) {
 			successCount++
 			// 收集成功经验的特定指标
 			if metrics, ok := exp.Result.Metrics[ruleType]; ok {
@@ -1985,7 +2333,7 @@ func extractRuleParameters(experiences []LearningExperience, ruleType string) ma
 			// 收集适应性指标
 			if isAdaptiveAction(exp) {
 				adaptationCount++
-				if exp.Result.Status == "success" {
+				if experienceSucceeded(exp) {
 					effectiveCount++
 				}
 			}
@@ -2016,7 +2364,7 @@ func calculateSuccessConfidence(experiences []LearningExperience) float64 {
 
 	successCount := 0
 	for _, exp := range experiences {
-		if exp.Result.Status == "success" {
+		if experienceSucceeded(exp) {
 			successCount++
 		}
 	}
@@ -2031,7 +2379,7 @@ func calculateFailureConfidence(experiences []LearningExperience) float64 {
 
 	failureCount := 0
 	for _, exp := range experiences {
-		if exp.Result.Status != "success" {
+		if !experienceSucceeded(exp) {
 			failureCount++
 		}
 	}
@@ -2089,7 +2437,7 @@ func (al *AdaptiveLearning) optimizeRules() error {
 			// 尝试优化规则
 			optimized := optimizeRule(rule, al.state.experiences)
 			if optimized != nil {
-				al.strategy.UpdateRule(optimized)
+				al.strategy.updateRule(optimized, al.lineage())
 			}
 		}
 	}
@@ -2168,7 +2516,7 @@ func evaluateRuleEffectiveness(rule *StrategyRule, experiences []LearningExperie
 
 	for _, exp := range experiences {
 		if isRuleApplicable(rule, exp) {
-			if exp.Result.Status == "success" {
+			if experienceSucceeded(exp) {
 				successCount++
 			}
 			totalCount++
@@ -2280,7 +2628,7 @@ func optimizeActionParameters(rule *StrategyRule, experiences []LearningExperien
 	// 收集成功动作的参数统计
 	paramStats := make(map[string][]float64)
 	for _, exp := range experiences {
-		if exp.Result.Status == "success" && exp.Action.Type == rule.Action.Function {
+		if experienceSucceeded(exp) && exp.Action.Type == rule.Action.Function {
 			for k, v := range exp.Action.Parameters {
 				if fv, ok := v.(float64); ok {
 					paramStats[k] = append(paramStats[k], fv)
@@ -2318,13 +2666,16 @@ func calculateMean(values []float64) float64 {
 
 // 辅助函数
 
-func (al *AdaptiveLearning) addExperience(experience LearningExperience) {
-	al.state.experiences = append(al.state.experiences, experience)
+func (al *AdaptiveLearning) addExperience(experience LearningExperience) bool {
+	// 缓冲区已满时由保留策略决定移除哪条经验或不保留新经验
+	var retained bool
+	al.state.experiences, retained = al.retention.admit(al.state.experiences, experience, al.config.memoryCapacity)
 
-	// 限制经验数量
-	if len(al.state.experiences) > al.config.memoryCapacity {
-		al.state.experiences = al.state.experiences[1:]
+	// 在线模式下增量更新模型, 未保留的经验同样参与训练
+	if al.config.online {
+		al.trainOnline(experience)
 	}
+	return retained
 }
 
 func (al *AdaptiveLearning) integrateKnowledge(knowledge *KnowledgeUnit) {
@@ -2451,5 +2802,5 @@ func mergeContexts(ctx1, ctx2 map[string]interface{}) map[string]interface{} {
 }
 
 func generateKnowledgeID() string {
-	return fmt.Sprintf("know_%d", time.Now().UnixNano())
+	return core.NewID("know_")
 }
@@ -0,0 +1,71 @@
+// system/evolution/adaptation/memory.go
+
+package adaptation
+
+import (
+	"sort"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 学习缓存
+const (
+	CacheExperiences = "learning.experiences" // 学习经验
+	CacheKnowledge   = "learning.knowledge"   // 知识单元
+)
+
+// MemoryUsage 学习经验和知识单元的近似内存占用
+func (al *AdaptiveLearning) MemoryUsage() []types.MemoryUsage {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	return []types.MemoryUsage{
+		{
+			Cache:   CacheExperiences,
+			Entries: len(al.state.experiences),
+			Bytes:   types.ApproxSize(al.state.experiences),
+		},
+		{
+			Cache:   CacheKnowledge,
+			Entries: len(al.state.knowledge),
+			Bytes:   types.ApproxSize(al.state.knowledge),
+		},
+	}
+}
+
+// EvictMemory 按经验保留策略驱逐经验, 或驱逐使用次数最少、最久未访问的知识单元, 保留keep条
+func (al *AdaptiveLearning) EvictMemory(cache string, keep int) int {
+	if keep < 0 {
+		keep = 0
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	switch cache {
+	case CacheExperiences:
+		var evicted int
+		al.state.experiences, evicted = al.retention.evict(al.state.experiences, keep)
+		return evicted
+	case CacheKnowledge:
+		excess := len(al.state.knowledge) - keep
+		if excess <= 0 {
+			return 0
+		}
+		units := make([]*KnowledgeUnit, 0, len(al.state.knowledge))
+		for _, unit := range al.state.knowledge {
+			units = append(units, unit)
+		}
+		sort.Slice(units, func(i, j int) bool {
+			if units[i].Metadata.Usage != units[j].Metadata.Usage {
+				return units[i].Metadata.Usage < units[j].Metadata.Usage
+			}
+			return units[i].Metadata.LastAccess.Before(units[j].Metadata.LastAccess)
+		})
+		for _, unit := range units[:excess] {
+			delete(al.state.knowledge, unit.ID)
+		}
+		return excess
+	}
+	return 0
+}
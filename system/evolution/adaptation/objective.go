@@ -0,0 +1,212 @@
+// system/evolution/adaptation/objective.go
+
+package adaptation
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 目标方向
+const (
+	DirectionMaximize = "maximize" // 越大越好, 在[Min, Max]内线性计分
+	DirectionMinimize = "minimize" // 越小越好, 在[Min, Max]内线性计分
+	DirectionRange    = "range"    // 保持在[Min, Max]内, 偏离越远得分越低
+)
+
+// 目标评分在策略事件详情中的键
+const (
+	detailObjectiveScore  = "objective_score"  // 执行后的目标达成度
+	detailObjectiveBefore = "objective_before" // 执行前的目标达成度
+	detailObjectiveValues = "objective_values" // 执行后的目标指标值
+)
+
+// successFeedback 目标达成度不低于该值的执行视为成功
+const successFeedback = 0.5
+
+// Objective 适应目标, 定义系统状态指标的期望方向和权重
+// 策略有效性、规则评分和学习反馈按目标达成度计算, 未定义目标时按执行状态计算
+type Objective struct {
+	Name      string  `json:"name"`      // 目标名称
+	Metric    string  `json:"metric"`    // 指标名, 取自系统状态(energy, stability, entropy, harmony, balance)或其数值属性
+	Direction string  `json:"direction"` // 目标方向
+	Weight    float64 `json:"weight"`    // 权重, 默认1
+	Min       float64 `json:"min"`       // 区间下界
+	Max       float64 `json:"max"`       // 区间上界, Min和Max都为0时取[0,1]
+}
+
+// ObjectiveScore 目标评估结果
+type ObjectiveScore struct {
+	Score        float64            `json:"score"`        // 加权目标达成度, 范围[0,1]
+	Values       map[string]float64 `json:"values"`       // 各目标的指标值
+	Satisfaction map[string]float64 `json:"satisfaction"` // 各目标的达成度
+}
+
+// Satisfaction 指标值对目标的达成度, 范围[0,1]
+func (o Objective) Satisfaction(value float64) float64 {
+	lo, hi := o.bounds()
+	width := hi - lo
+	switch o.Direction {
+	case DirectionMinimize:
+		return 1 - clamp01((value-lo)/width)
+	case DirectionRange:
+		if value >= lo && value <= hi {
+			return 1
+		}
+		distance := math.Max(lo-value, value-hi)
+		return clamp01(1 - distance/width)
+	default:
+		return clamp01((value - lo) / width)
+	}
+}
+
+// bounds 目标区间, 退化区间展开为单位宽度
+func (o Objective) bounds() (float64, float64) {
+	lo, hi := o.Min, o.Max
+	if lo == 0 && hi == 0 {
+		hi = 1
+	}
+	if hi <= lo {
+		hi = lo + 1
+	}
+	return lo, hi
+}
+
+// validate 校验目标
+func (o Objective) validate() error {
+	if o.Name == "" || o.Metric == "" {
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "objective name and metric required", nil)
+	}
+	switch o.Direction {
+	case DirectionMaximize, DirectionMinimize, DirectionRange:
+	default:
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown objective direction", nil).
+			WithContext("direction", o.Direction)
+	}
+	if o.Weight < 0 || o.Min > o.Max {
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "invalid objective weight or range", nil).
+			WithContext("objective", o.Name)
+	}
+	return nil
+}
+
+// EvaluateObjectives 按指标计算目标达成度, 指标缺失的目标不参与评分; 没有可评估的目标时返回false
+func EvaluateObjectives(objectives []Objective, metrics map[string]float64) (ObjectiveScore, bool) {
+	result := ObjectiveScore{
+		Values:       make(map[string]float64),
+		Satisfaction: make(map[string]float64),
+	}
+	score, total := 0.0, 0.0
+	for _, o := range objectives {
+		value, ok := metrics[o.Metric]
+		if !ok || math.IsNaN(value) {
+			continue
+		}
+		satisfaction := o.Satisfaction(value)
+		result.Values[o.Name] = value
+		result.Satisfaction[o.Name] = satisfaction
+		score += satisfaction * o.Weight
+		total += o.Weight
+	}
+	if total == 0 {
+		return result, false
+	}
+	result.Score = score / total
+	return result, true
+}
+
+// StateMetrics 提取系统状态中可作为目标指标的数值
+func StateMetrics(state *model.SystemState) map[string]float64 {
+	metrics := make(map[string]float64)
+	if state == nil {
+		return metrics
+	}
+	for k, v := range state.Properties {
+		switch n := v.(type) {
+		case float64:
+			metrics[k] = n
+		case int:
+			metrics[k] = float64(n)
+		}
+	}
+	metrics["energy"] = state.Energy
+	metrics["stability"] = state.Stability
+	metrics["entropy"] = state.Entropy
+	metrics["harmony"] = state.Harmony
+	metrics["balance"] = state.Balance
+	return metrics
+}
+
+// SetObjective 设置适应目标, 同名目标被替换
+func (as *AdaptationStrategy) SetObjective(objective Objective) error {
+	if objective.Weight == 0 {
+		objective.Weight = 1
+	}
+	if err := objective.validate(); err != nil {
+		return err
+	}
+
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.state.objectives[objective.Name] = objective
+	return nil
+}
+
+// RemoveObjective 移除适应目标
+func (as *AdaptationStrategy) RemoveObjective(name string) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	delete(as.state.objectives, name)
+}
+
+// GetObjectives 获取适应目标, 按名称排序
+func (as *AdaptationStrategy) GetObjectives() []Objective {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+	return as.objectiveList()
+}
+
+// objectiveList 按名称排序的目标列表, 调用方需持有锁
+func (as *AdaptationStrategy) objectiveList() []Objective {
+	objectives := make([]Objective, 0, len(as.state.objectives))
+	for _, o := range as.state.objectives {
+		objectives = append(objectives, o)
+	}
+	sort.Slice(objectives, func(i, j int) bool {
+		return objectives[i].Name < objectives[j].Name
+	})
+	return objectives
+}
+
+// evaluateState 按当前目标评估系统状态, 调用方需持有锁
+func (as *AdaptationStrategy) evaluateState(state *model.SystemState) (ObjectiveScore, bool) {
+	if len(as.state.objectives) == 0 {
+		return ObjectiveScore{}, false
+	}
+	return EvaluateObjectives(as.objectiveList(), StateMetrics(state))
+}
+
+// eventScore 策略事件的得分: 按目标评估过时为执行后的目标达成度, 否则成功为1失败为0
+func eventScore(event StrategyEvent) (float64, bool) {
+	if score, ok := event.Details[detailObjectiveScore].(float64); ok {
+		return score, true
+	}
+	if event.Status == "success" {
+		return 1, false
+	}
+	return 0, false
+}
+
+// eventSucceeded 策略事件是否成功
+func eventSucceeded(event StrategyEvent) bool {
+	score, _ := eventScore(event)
+	return score >= successFeedback
+}
+
+// clamp01 限制到[0,1]
+func clamp01(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
@@ -0,0 +1,97 @@
+// system/evolution/adaptation/online.go
+
+package adaptation
+
+import (
+	"math"
+	"time"
+)
+
+// defaultOnlineBatch 在线学习的默认小批量大小, 每条经验到达即更新
+const defaultOnlineBatch = 1
+
+// AddExperience 记录学习经验, 在线模式下按小批量增量更新模型权重
+// 返回经验是否被保留; 缓冲区已满时由保留策略决定, 调用方可据此放慢经验的产生
+func (al *AdaptiveLearning) AddExperience(experience LearningExperience) bool {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if experience.Timestamp.IsZero() {
+		experience.Timestamp = time.Now()
+	}
+	return al.addExperience(experience)
+}
+
+// SetOnlineLearning 切换在线学习模式, batch为触发一次权重更新的样本数, <=0时使用默认值
+// 在线模式下Learn不再以累积数据重新训练模型, 只评估模型并应用学习成果
+func (al *AdaptiveLearning) SetOnlineLearning(enabled bool, batch int) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if batch <= 0 {
+		batch = defaultOnlineBatch
+	}
+	al.config.online = enabled
+	al.config.onlineBatch = batch
+	al.state.pending = make(map[string][]TrainingItem)
+}
+
+// IsOnlineLearning 是否为在线学习模式
+func (al *AdaptiveLearning) IsOnlineLearning() bool {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+	return al.config.online
+}
+
+// trainOnline 将经验转换为各模型的样本, 攒满小批量后执行一次梯度更新; 调用方需持有锁
+// 样本按验证比例分流到验证集, 训练集和验证集都只保留最近的记忆容量个样本
+func (al *AdaptiveLearning) trainOnline(experience LearningExperience) {
+	for id, model := range al.state.models {
+		item := convertExperienceToTraining(experience, model.Type)
+		if item == nil {
+			continue
+		}
+
+		model.State.OnlineSamples++
+		n := float64(model.State.OnlineSamples)
+		if math.Floor(n*al.config.validationRatio) > math.Floor((n-1)*al.config.validationRatio) {
+			model.State.ValidationData = appendBounded(model.State.ValidationData, *item, al.config.memoryCapacity)
+			continue
+		}
+		model.State.TrainingData = appendBounded(model.State.TrainingData, *item, al.config.memoryCapacity)
+
+		pending := append(al.state.pending[id], *item)
+		if len(pending) < al.config.onlineBatch {
+			al.state.pending[id] = pending
+			continue
+		}
+		delete(al.state.pending, id)
+
+		if err := trainBatch(model, pending); err != nil {
+			continue
+		}
+		updateModelWeights(model)
+		model.State.OnlineUpdates++
+		model.State.LastUpdate = time.Now()
+	}
+}
+
+// evaluateModels 评估所有模型并更新特征重要性, 在线模式下代替重新训练
+func (al *AdaptiveLearning) evaluateModels() {
+	for _, model := range al.state.models {
+		if len(model.State.TrainingData) == 0 {
+			continue
+		}
+		al.evaluateModel(model)
+		al.updateFeatureImportance(model)
+	}
+}
+
+// appendBounded 追加样本并只保留最近的limit个
+func appendBounded(items []TrainingItem, item TrainingItem, limit int) []TrainingItem {
+	items = append(items, item)
+	if over := len(items) - limit; limit > 0 && over > 0 {
+		items = append([]TrainingItem(nil), items[over:]...)
+	}
+	return items
+}
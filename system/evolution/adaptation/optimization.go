@@ -9,7 +9,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/evolution/audit"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -182,7 +184,7 @@ func (ao *AdaptiveOptimization) RegisterObjective(
 	objective *OptimizationObjective) error {
 
 	if objective == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "nil objective")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "nil objective", nil)
 	}
 
 	ao.mu.Lock()
@@ -499,7 +501,8 @@ func (ao *AdaptiveOptimization) applyOptimizedParameters(opt *Optimization) erro
 	// 获取目标对象
 	objective := ao.state.objectives[opt.Target]
 	if objective == nil {
-		return fmt.Errorf("objective %s not found", opt.Target)
+		return types.NewDomainError(types.DomainEvolution, types.ErrNotFound, "objective not found", nil).
+			WithContext("objective", opt.Target)
 	}
 
 	// 构造应用参数
@@ -512,12 +515,13 @@ func (ao *AdaptiveOptimization) applyOptimizedParameters(opt *Optimization) erro
 	switch objective.Type {
 	case "system":
 		// 系统级参数调整
-		return ao.strategy.UpdateParameters(objective.TargetID, params) // 使用TargetID
+		return ao.strategy.updateParameters(objective.TargetID, params, audit.Lineage{Source: audit.SourceOptimizer}) // 使用TargetID
 	case "component":
 		// 组件级参数调整
 		return ao.strategy.mutationHandler.AdjustParameter(objective.TargetID, params) // 使用TargetID
 	default:
-		return fmt.Errorf("unknown optimization type: %s", objective.Type)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown optimization type", nil).
+			WithContext("type", objective.Type)
 	}
 }
 
@@ -554,11 +558,11 @@ func (ao *AdaptiveOptimization) validateObjective(
 	objective *OptimizationObjective) error {
 
 	if objective.ID == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty objective ID")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty objective ID", nil)
 	}
 
 	if objective.Evaluator.Function == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "missing evaluator function")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "missing evaluator function", nil)
 	}
 
 	return nil
@@ -583,7 +587,7 @@ func (ao *AdaptiveOptimization) updateMetrics() {
 }
 
 func generateOptimizationID() string {
-	return fmt.Sprintf("opt_%d", time.Now().UnixNano())
+	return core.NewID("opt_")
 }
 
 // RunOptimization 执行指定优化目标的优化
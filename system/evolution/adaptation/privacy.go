@@ -0,0 +1,319 @@
+// system/evolution/adaptation/privacy.go
+
+package adaptation
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"sync"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 经验数低于该值时不发布比率类统计
+const defaultPrivacyMinRecords = 10
+
+// Privacy 共享学习统计和知识时的隐私保护
+//
+// 保证范围:
+//   - 统计(LearningSnapshot.Stats)满足每次导出ε-差分隐私, 相邻数据集为增减一条学习经验;
+//     经验数的敏感度为1, 比率类统计(成功率、平均反馈、模型准确率, 取值[0,1])按至少MinRecords条经验计,
+//     敏感度为1/MinRecords; ε在各发布值之间平均分配
+//   - 比率是否发布取决于含噪经验数是否达到MinRecords, 该门限不消耗ε; 比率的ε保证仅在真实经验数
+//     不少于MinRecords时成立, 真实经验数更少时比率的发布与否及其取值都可能泄露个体信息
+//   - 多次导出按顺序组合累加ε, 设置Budget后累计超出上限的导出被拒绝
+//   - 知识(LearningSnapshot.Knowledge)只做K-匿名过滤: 上下文字段的取值出现在少于K个知识单元中时移除该字段,
+//     这不是差分隐私保证, 知识单元本身的存在仍可被观察
+//   - 模型只发布含噪准确率, 权重、损失和参数不共享; 知识增长率和准确率趋势不发布
+//
+// 系统快照(ExportSnapshot)不经过隐私保护
+type Privacy struct {
+	mu sync.Mutex
+
+	// 基础配置
+	config types.PrivacyConfig
+
+	// 已消耗的隐私预算
+	spent float64
+
+	// 噪声源
+	rng *rand.Rand
+}
+
+// NewPrivacy 创建隐私保护, 启用时ε必须为正
+func NewPrivacy(cfg types.PrivacyConfig) (*Privacy, error) {
+	if cfg.Enabled && (cfg.Epsilon <= 0 || math.IsInf(cfg.Epsilon, 0) || math.IsNaN(cfg.Epsilon)) {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "privacy epsilon must be positive and finite", nil).
+			WithContext("epsilon", cfg.Epsilon)
+	}
+	if cfg.Budget < 0 || cfg.MinRecords < 0 || cfg.KAnonymity < 0 {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "negative privacy budget, min records or k", nil)
+	}
+	if cfg.MinRecords == 0 {
+		cfg.MinRecords = defaultPrivacyMinRecords
+	}
+
+	// 噪声可预测时隐私保证失效, 使用加密随机数作为种子
+	var seed [8]byte
+	if _, err := crand.Read(seed[:]); err != nil {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to seed privacy noise", err)
+	}
+
+	return &Privacy{
+		config: cfg,
+		rng:    rand.New(rand.NewSource(int64(binary.LittleEndian.Uint64(seed[:])))),
+	}, nil
+}
+
+// Enabled 是否启用, nil视为未启用
+func (p *Privacy) Enabled() bool {
+	return p != nil && p.config.Enabled
+}
+
+// Config 隐私配置
+func (p *Privacy) Config() types.PrivacyConfig {
+	return p.config
+}
+
+// Spent 已消耗的隐私预算
+func (p *Privacy) Spent() float64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.spent
+}
+
+// Inherit 继承prev已消耗的隐私预算, 替换隐私保护时调用, 使顺序组合的累计预算不因替换而重置
+func (p *Privacy) Inherit(prev *Privacy) {
+	if p == nil || prev == nil || p == prev {
+		return
+	}
+	spent := prev.Spent()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if spent > p.spent {
+		p.spent = spent
+	}
+}
+
+// Guarantee 当前配置下的隐私保证说明, 随共享数据一并提供给接收方
+func (p *Privacy) Guarantee() string {
+	if !p.Enabled() {
+		return "none: shared learning data is not privacy protected"
+	}
+	budget := "unlimited"
+	if p.config.Budget > 0 {
+		budget = fmt.Sprintf("%g", p.config.Budget)
+	}
+	return fmt.Sprintf("statistics: %g-differential privacy per export over individual learning experiences "+
+		"(rate guarantees hold only when the true experience count is at least %d; "+
+		"rates are released based on the noisy count without a separate threshold test), cumulative budget %s under sequential composition; "+
+		"knowledge: %d-anonymity on context fields only, not differentially private",
+		p.config.Epsilon, p.config.MinRecords, budget, p.config.KAnonymity)
+}
+
+// Apply 对导出快照施加隐私保护, 返回新快照; 隐私预算不足时返回错误且不消耗预算
+func (p *Privacy) Apply(snapshot *LearningSnapshot) (*LearningSnapshot, error) {
+	if !p.Enabled() {
+		return snapshot, nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.config.Budget > 0 && p.spent+p.config.Epsilon > p.config.Budget {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrPermission, "privacy budget exhausted", nil).
+			WithContext("spent", p.spent).
+			WithContext("budget", p.config.Budget)
+	}
+	p.spent += p.config.Epsilon
+
+	shared := &LearningSnapshot{
+		Knowledge: anonymizeKnowledge(snapshot.Knowledge, p.config.KAnonymity),
+		Models:    make(map[string]ModelRecord, len(snapshot.Models)),
+		Config:    snapshot.Config,
+		Stats:     p.noiseStatistics(snapshot.Stats),
+	}
+	for id, model := range snapshot.Models {
+		accuracy, ok := shared.Stats.ModelAccuracy[id]
+		if !ok {
+			continue
+		}
+		shared.Models[id] = ModelRecord{
+			ID:       model.ID,
+			Type:     model.Type,
+			Version:  model.Version,
+			Accuracy: accuracy,
+		}
+	}
+	return shared, nil
+}
+
+// noiseStatistics 对统计加入拉普拉斯噪声(调用方持有锁)
+// 含噪经验数低于MinRecords时比率类统计置零不发布
+func (p *Privacy) noiseStatistics(stats LearningStatistics) LearningStatistics {
+	epsilon := p.config.Epsilon / float64(3+len(stats.ModelAccuracy))
+	rateSensitivity := 1 / float64(p.config.MinRecords)
+
+	noisy := LearningStatistics{
+		ModelAccuracy: make(map[string]float64, len(stats.ModelAccuracy)),
+	}
+	count := math.Round(float64(stats.TotalExperiences) + p.laplace(1/epsilon))
+	noisy.TotalExperiences = int(math.Max(count, 0))
+
+	// 噪声在判断前抽取, 使预算消耗与是否发布无关
+	successRate := clamp01(stats.SuccessRate + p.laplace(rateSensitivity/epsilon))
+	objective := clamp01(stats.ObjectiveScore + p.laplace(rateSensitivity/epsilon))
+	ids := make([]string, 0, len(stats.ModelAccuracy))
+	for id := range stats.ModelAccuracy {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	accuracy := make(map[string]float64, len(ids))
+	for _, id := range ids {
+		accuracy[id] = clamp01(stats.ModelAccuracy[id] + p.laplace(rateSensitivity/epsilon))
+	}
+
+	if noisy.TotalExperiences < p.config.MinRecords {
+		return noisy
+	}
+	noisy.SuccessRate = successRate
+	noisy.ObjectiveScore = objective
+	noisy.ModelAccuracy = accuracy
+	return noisy
+}
+
+// laplace 尺度为scale的拉普拉斯噪声(调用方持有锁)
+func (p *Privacy) laplace(scale float64) float64 {
+	u := p.rng.Float64() - 0.5
+	for u == -0.5 {
+		u = p.rng.Float64() - 0.5
+	}
+	return -scale * math.Copysign(1, u) * math.Log(1-2*math.Abs(u))
+}
+
+// anonymizeKnowledge K-匿名过滤: 移除取值出现在少于k个知识单元中的上下文字段; 无法识别上下文的内容不共享
+func anonymizeKnowledge(records []KnowledgeRecord, k int) []KnowledgeRecord {
+	contexts := make([]map[string]interface{}, len(records))
+	counts := make(map[string]int)
+	for i, record := range records {
+		ctx, ok := knowledgeContext(record.Content)
+		if !ok {
+			continue
+		}
+		contexts[i] = ctx
+		for key, value := range ctx {
+			counts[contextField(key, value)]++
+		}
+	}
+
+	shared := make([]KnowledgeRecord, 0, len(records))
+	for i, record := range records {
+		ctx, ok := knowledgeContext(record.Content)
+		if !ok {
+			continue
+		}
+		kept := make(map[string]interface{}, len(ctx))
+		for key, value := range contexts[i] {
+			if k <= 1 || counts[contextField(key, value)] >= k {
+				kept[key] = value
+			}
+		}
+		record.Content = withContext(record.Content, kept)
+		record.Connections = append([]KnowledgeLink(nil), record.Connections...)
+		record.Metadata.Tags = append([]string(nil), record.Metadata.Tags...)
+		shared = append(shared, record)
+	}
+	return shared
+}
+
+// contextField 上下文字段的计数键
+func contextField(key string, value interface{}) string {
+	return fmt.Sprintf("%s=%v", key, value)
+}
+
+// knowledgeContext 知识内容中的上下文字段, 内容可能是经验模式或JSON恢复后的映射
+func knowledgeContext(content interface{}) (map[string]interface{}, bool) {
+	switch c := content.(type) {
+	case ExperiencePattern:
+		return c.Context, true
+	case *ExperiencePattern:
+		if c == nil {
+			return nil, false
+		}
+		return c.Context, true
+	case map[string]interface{}:
+		ctx, _ := c["Context"].(map[string]interface{})
+		return ctx, true
+	default:
+		return nil, false
+	}
+}
+
+// withContext 替换上下文字段后的内容副本
+func withContext(content interface{}, ctx map[string]interface{}) interface{} {
+	switch c := content.(type) {
+	case ExperiencePattern:
+		c.Context = ctx
+		return c
+	case *ExperiencePattern:
+		clone := *c
+		clone.Context = ctx
+		return clone
+	case map[string]interface{}:
+		clone := make(map[string]interface{}, len(c))
+		for k, v := range c {
+			clone[k] = v
+		}
+		clone["Context"] = ctx
+		return clone
+	default:
+		return content
+	}
+}
+
+// SetPrivacy 设置共享导出和跨命名空间知识共享的隐私保护, nil表示不保护
+// 新的隐私保护继承被替换者已消耗的预算
+func (al *AdaptiveLearning) SetPrivacy(privacy *Privacy) {
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	privacy.Inherit(al.privacy)
+	al.privacy = privacy
+}
+
+// GetPrivacy 获取隐私保护, 未设置时返回nil
+func (al *AdaptiveLearning) GetPrivacy() *Privacy {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+	return al.privacy
+}
+
+// ExportShared 导出用于跨实例、跨租户共享的学习统计和知识, 保证范围见Privacy; 未启用隐私保护时与ExportSnapshot相同
+func (al *AdaptiveLearning) ExportShared() (*LearningSnapshot, error) {
+	return al.GetPrivacy().Apply(al.ExportSnapshot())
+}
+
+// anonymizeUnits 对知识单元做K-匿名过滤, 验证函数随单元保留
+func anonymizeUnits(units []*KnowledgeUnit, k int) []*KnowledgeUnit {
+	records := make([]KnowledgeRecord, len(units))
+	for i, unit := range units {
+		records[i] = KnowledgeRecord{ID: unit.ID, Content: unit.Content}
+	}
+	byID := make(map[string]interface{}, len(records))
+	for _, record := range anonymizeKnowledge(records, k) {
+		byID[record.ID] = record.Content
+	}
+
+	kept := make([]*KnowledgeUnit, 0, len(units))
+	for _, unit := range units {
+		if content, ok := byID[unit.ID]; ok {
+			unit.Content = content
+			kept = append(kept, unit)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,484 @@
+// system/evolution/adaptation/reinforcement.go
+
+package adaptation
+
+import (
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/evolution/audit"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 强化学习策略模型
+const (
+	ReinforcementTabular = "tabular" // 离散化观测的Q表
+	ReinforcementNeural  = "neural"  // 每个动作一个单隐层价值网络
+)
+
+// 强化学习默认参数
+const (
+	defaultReinforcementBins     = 5
+	defaultReinforcementHidden   = 8
+	defaultReinforcementStep     = 0.1
+	defaultReinforcementDiscount = 0.9
+	rewardSmoothing              = 0.1 // 平均奖励的指数平滑系数
+)
+
+// ActionNoop 不调整任何策略参数的动作
+const ActionNoop = "noop"
+
+// observationFeatures 观测特征, 按顺序取自系统状态
+var observationFeatures = []string{"energy", "stability", "entropy", "harmony", "balance"}
+
+// ReinforcementPolicy 强化学习的动作价值模型
+// 动作空间随注册的策略参数变化, 模型需接受此前未见过的动作, 其价值默认为0
+type ReinforcementPolicy interface {
+	// Values 观测下各动作的价值估计
+	Values(observation []float64, actions []string) []float64
+	// Update 以学习率rate将观测下动作的价值向target靠拢
+	Update(observation []float64, action string, target, rate float64)
+}
+
+// ReinforcementStats 强化学习统计
+type ReinforcementStats struct {
+	Policy        string    `json:"policy"`
+	Steps         int64     `json:"steps"`          // 执行的动作数
+	Updates       int64     `json:"updates"`        // 价值更新次数
+	Explorations  int64     `json:"explorations"`   // 随机探索的动作数
+	Actions       int       `json:"actions"`        // 当前动作空间大小
+	LastAction    string    `json:"last_action"`    // 最近执行的动作
+	LastReward    float64   `json:"last_reward"`    // 最近一次奖励
+	AverageReward float64   `json:"average_reward"` // 指数平滑的平均奖励
+	Updated       time.Time `json:"updated"`
+}
+
+// reinforcementAction 调整单个策略参数的动作
+type reinforcementAction struct {
+	strategyID string
+	parameter  string
+	factor     float64
+}
+
+// reinforcementStep 上一步的观测和动作, 在下次学习时按奖励更新
+type reinforcementStep struct {
+	observation []float64
+	action      string
+	taken       time.Time
+}
+
+// reinforcementAgent 强化学习智能体
+type reinforcementAgent struct {
+	config types.ReinforcementConfig
+	policy ReinforcementPolicy
+	prev   *reinforcementStep
+	stats  ReinforcementStats
+}
+
+// newReinforcementAgent 按配置创建强化学习智能体
+func newReinforcementAgent(cfg types.ReinforcementConfig) (*reinforcementAgent, error) {
+	if cfg.Policy == "" {
+		cfg.Policy = ReinforcementTabular
+	}
+	if cfg.Bins <= 0 {
+		cfg.Bins = defaultReinforcementBins
+	}
+	if cfg.Hidden <= 0 {
+		cfg.Hidden = defaultReinforcementHidden
+	}
+	if cfg.Step <= 0 || cfg.Step >= 1 {
+		cfg.Step = defaultReinforcementStep
+	}
+	if cfg.Discount < 0 || cfg.Discount >= 1 {
+		cfg.Discount = defaultReinforcementDiscount
+	}
+
+	agent := &reinforcementAgent{config: cfg}
+	switch cfg.Policy {
+	case ReinforcementTabular:
+		agent.policy = NewTabularPolicy(cfg.Bins)
+	case ReinforcementNeural:
+		agent.policy = NewNeuralPolicy(cfg.Hidden)
+	default:
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown reinforcement policy", nil).
+			WithContext("policy", cfg.Policy)
+	}
+	agent.stats.Policy = cfg.Policy
+	return agent, nil
+}
+
+// choose 按ε-greedy选择动作, 返回是否为随机探索
+func (a *reinforcementAgent) choose(observation []float64, actions []string, exploration float64) (string, bool) {
+	if rand.Float64() < exploration {
+		return actions[rand.Intn(len(actions))], true
+	}
+	values := a.policy.Values(observation, actions)
+	best := 0
+	for i := range values {
+		if values[i] > values[best] {
+			best = i
+		}
+	}
+	return actions[best], false
+}
+
+// learn 以奖励和下一观测的最大价值更新上一步动作的价值
+func (a *reinforcementAgent) learn(reward float64, observation []float64, actions []string, rate float64) {
+	next := a.policy.Values(observation, actions)
+	future := 0.0
+	for i, v := range next {
+		if i == 0 || v > future {
+			future = v
+		}
+	}
+	a.policy.Update(a.prev.observation, a.prev.action, reward+a.config.Discount*future, rate)
+
+	a.stats.Updates++
+	a.stats.LastReward = reward
+	if a.stats.Updates == 1 {
+		a.stats.AverageReward = reward
+	} else {
+		a.stats.AverageReward += rewardSmoothing * (reward - a.stats.AverageReward)
+	}
+}
+
+// SetReinforcementPolicy 设置强化学习的价值模型, 未启用强化学习时按默认配置启用
+func (al *AdaptiveLearning) SetReinforcementPolicy(policy ReinforcementPolicy) error {
+	if policy == nil {
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil reinforcement policy", nil)
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	if al.rl == nil {
+		agent, err := newReinforcementAgent(types.ReinforcementConfig{Enabled: true})
+		if err != nil {
+			return err
+		}
+		al.rl = agent
+	}
+	al.rl.policy = policy
+	al.rl.prev = nil
+	al.rl.stats.Policy = "custom"
+	return nil
+}
+
+// GetReinforcementStats 获取强化学习统计, 未启用时返回false
+func (al *AdaptiveLearning) GetReinforcementStats() (ReinforcementStats, bool) {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	if al.rl == nil {
+		return ReinforcementStats{}, false
+	}
+	return al.rl.stats, true
+}
+
+// applyReinforcement 强化学习模式下应用学习成果: 按上一动作的奖励更新价值, 再选择并执行下一动作
+func (al *AdaptiveLearning) applyReinforcement() error {
+	state, err := al.strategy.getCurrentState()
+	if err != nil {
+		return err
+	}
+	observation := observe(state)
+
+	space := al.strategy.parameterSpace()
+	ids, actions := reinforcementActions(space, al.rl.config.Step)
+
+	agent := al.rl
+	if agent.prev != nil {
+		if reward, ok := al.reinforcementReward(state, agent.prev.taken); ok {
+			agent.learn(reward, observation, ids, al.config.learningRate)
+		}
+	}
+
+	choice, explored := agent.choose(observation, ids, al.config.explorationRate)
+	agent.prev = &reinforcementStep{observation: observation, action: choice, taken: time.Now()}
+	agent.stats.Steps++
+	agent.stats.Actions = len(ids)
+	agent.stats.LastAction = choice
+	agent.stats.Updated = agent.prev.taken
+	if explored {
+		agent.stats.Explorations++
+	}
+
+	if choice == ActionNoop {
+		return nil
+	}
+	action := actions[choice]
+	lineage := audit.Lineage{
+		Source:         audit.SourceReinforcement,
+		ModelVersions:  al.modelVersions(),
+		ObjectiveScore: agent.stats.LastReward,
+		Scored:         agent.stats.Updates > 0,
+	}
+	return al.strategy.scaleParameter(action.strategyID, action.parameter, action.factor, lineage)
+}
+
+// reinforcementReward 动作的奖励: 定义适应目标时为当前状态的目标达成度,
+// 否则为动作后策略执行经验的平均反馈; 没有可用反馈时返回false
+func (al *AdaptiveLearning) reinforcementReward(state *model.SystemState, since time.Time) (float64, bool) {
+	if score, ok := al.strategy.scoreState(state); ok {
+		return score.Score, true
+	}
+	sum, n := 0.0, 0
+	for _, exp := range al.state.experiences {
+		if exp.Timestamp.After(since) {
+			sum += experienceFeedback(exp)
+			n++
+		}
+	}
+	if n == 0 {
+		return 0, false
+	}
+	return sum / float64(n), true
+}
+
+// observe 提取系统状态的观测特征
+func observe(state *model.SystemState) []float64 {
+	metrics := StateMetrics(state)
+	observation := make([]float64, len(observationFeatures))
+	for i, name := range observationFeatures {
+		observation[i] = metrics[name]
+	}
+	return observation
+}
+
+// reinforcementActions 由策略参数构造动作空间: 每个数值参数的增减各一个动作, 外加不动作
+func reinforcementActions(space map[string]map[string]float64, step float64) ([]string, map[string]reinforcementAction) {
+	ids := []string{ActionNoop}
+	actions := make(map[string]reinforcementAction)
+
+	strategyIDs := make([]string, 0, len(space))
+	for id := range space {
+		strategyIDs = append(strategyIDs, id)
+	}
+	sort.Strings(strategyIDs)
+	for _, strategyID := range strategyIDs {
+		names := make([]string, 0, len(space[strategyID]))
+		for name := range space[strategyID] {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			for _, sign := range []string{"+", "-"} {
+				factor := 1 + step
+				if sign == "-" {
+					factor = 1 - step
+				}
+				id := strategyID + "/" + name + "/" + sign
+				ids = append(ids, id)
+				actions[id] = reinforcementAction{strategyID: strategyID, parameter: name, factor: factor}
+			}
+		}
+	}
+	return ids, actions
+}
+
+// parameterSpace 各策略的数值参数
+func (as *AdaptationStrategy) parameterSpace() map[string]map[string]float64 {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	space := make(map[string]map[string]float64, len(as.state.strategies))
+	for id, strategy := range as.state.strategies {
+		params := make(map[string]float64)
+		for name, v := range strategy.Parameters {
+			if f, ok := v.(float64); ok {
+				params[name] = f
+			}
+		}
+		if len(params) > 0 {
+			space[id] = params
+		}
+	}
+	return space
+}
+
+// scaleParameter 按比例调整策略的数值参数
+func (as *AdaptationStrategy) scaleParameter(strategyID, name string, factor float64, lineage audit.Lineage) error {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	strategy, exists := as.state.strategies[strategyID]
+	if !exists {
+		return types.NewDomainError(types.DomainEvolution, types.ErrNotFound, "strategy not found", nil).
+			WithContext("strategy_id", strategyID)
+	}
+	old, ok := strategy.Parameters[name].(float64)
+	if !ok {
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "parameter not numeric", nil).
+			WithContext("parameter", name)
+	}
+
+	params := make(map[string]interface{}, len(strategy.Parameters))
+	for k, v := range strategy.Parameters {
+		params[k] = v
+	}
+	params[name] = old * factor
+	oldParams := strategy.Parameters
+	strategy.Parameters = params
+
+	as.recordStrategyEvent(strategy, "parameters_updated", map[string]interface{}{
+		"old_params": oldParams,
+		"new_params": params,
+		"source":     "reinforcement",
+	})
+	as.recordAudit(audit.KindParametersUpdated, strategy.ID, oldParams, params, lineage)
+	return nil
+}
+
+// scoreState 按当前目标评估系统状态
+func (as *AdaptationStrategy) scoreState(state *model.SystemState) (ObjectiveScore, bool) {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+	return as.evaluateState(state)
+}
+
+// tabularPolicy 离散化观测的Q表
+type tabularPolicy struct {
+	bins int
+	q    map[string]map[string]float64
+}
+
+// NewTabularPolicy 创建Q表价值模型, 每个观测特征按[0,1]划分为bins个区间
+func NewTabularPolicy(bins int) ReinforcementPolicy {
+	if bins <= 0 {
+		bins = defaultReinforcementBins
+	}
+	return &tabularPolicy{bins: bins, q: make(map[string]map[string]float64)}
+}
+
+// Values 观测下各动作的价值估计
+func (p *tabularPolicy) Values(observation []float64, actions []string) []float64 {
+	row := p.q[p.key(observation)]
+	values := make([]float64, len(actions))
+	for i, action := range actions {
+		values[i] = row[action]
+	}
+	return values
+}
+
+// Update 将观测下动作的价值向target靠拢
+func (p *tabularPolicy) Update(observation []float64, action string, target, rate float64) {
+	key := p.key(observation)
+	row, ok := p.q[key]
+	if !ok {
+		row = make(map[string]float64)
+		p.q[key] = row
+	}
+	row[action] += rate * (target - row[action])
+}
+
+// key 观测的离散化键
+func (p *tabularPolicy) key(observation []float64) string {
+	var b strings.Builder
+	for i, v := range observation {
+		bin := int(clamp01(v) * float64(p.bins))
+		if bin == p.bins {
+			bin--
+		}
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.Itoa(bin))
+	}
+	return b.String()
+}
+
+// neuralPolicy 每个动作一个单隐层价值网络
+type neuralPolicy struct {
+	hidden int
+	heads  map[string]*valueNetwork
+}
+
+// NewNeuralPolicy 创建神经网络价值模型, hidden为隐藏层单元数
+func NewNeuralPolicy(hidden int) ReinforcementPolicy {
+	if hidden <= 0 {
+		hidden = defaultReinforcementHidden
+	}
+	return &neuralPolicy{hidden: hidden, heads: make(map[string]*valueNetwork)}
+}
+
+// Values 观测下各动作的价值估计, 未训练过的动作为0
+func (p *neuralPolicy) Values(observation []float64, actions []string) []float64 {
+	values := make([]float64, len(actions))
+	for i, action := range actions {
+		if head, ok := p.heads[action]; ok && len(head.w1[0]) == len(observation) {
+			values[i], _ = head.forward(observation)
+		}
+	}
+	return values
+}
+
+// Update 以一步梯度下降将观测下动作的价值向target靠拢
+func (p *neuralPolicy) Update(observation []float64, action string, target, rate float64) {
+	head, ok := p.heads[action]
+	if !ok || len(head.w1[0]) != len(observation) {
+		head = newValueNetwork(len(observation), p.hidden)
+		p.heads[action] = head
+	}
+	head.train(observation, target, rate)
+}
+
+// valueNetwork 单隐层价值网络, 隐藏层使用tanh激活
+type valueNetwork struct {
+	w1 [][]float64
+	b1 []float64
+	w2 []float64
+	b2 float64
+}
+
+// newValueNetwork 创建价值网络, 输入层权重按输入维度缩放随机初始化
+func newValueNetwork(inputs, hidden int) *valueNetwork {
+	scale := 1 / math.Sqrt(float64(max(inputs, 1)))
+	n := &valueNetwork{
+		w1: make([][]float64, hidden),
+		b1: make([]float64, hidden),
+		w2: make([]float64, hidden),
+	}
+	for j := range n.w1 {
+		n.w1[j] = make([]float64, inputs)
+		for i := range n.w1[j] {
+			n.w1[j][i] = (rand.Float64()*2 - 1) * scale
+		}
+		n.w2[j] = (rand.Float64()*2 - 1) / math.Sqrt(float64(hidden))
+	}
+	return n
+}
+
+// forward 前向计算, 返回输出和隐藏层激活
+func (n *valueNetwork) forward(input []float64) (float64, []float64) {
+	activations := make([]float64, len(n.w1))
+	out := n.b2
+	for j, weights := range n.w1 {
+		sum := n.b1[j]
+		for i, w := range weights {
+			sum += w * input[i]
+		}
+		activations[j] = math.Tanh(sum)
+		out += n.w2[j] * activations[j]
+	}
+	return out, activations
+}
+
+// train 按平方误差对target做一步梯度下降
+func (n *valueNetwork) train(input []float64, target, rate float64) {
+	out, activations := n.forward(input)
+	delta := out - target
+	for j, h := range activations {
+		grad := delta * n.w2[j] * (1 - h*h)
+		n.w2[j] -= rate * delta * h
+		for i := range n.w1[j] {
+			n.w1[j][i] -= rate * grad * input[i]
+		}
+		n.b1[j] -= rate * grad
+	}
+	n.b2 -= rate * delta
+}
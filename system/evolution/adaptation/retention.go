@@ -0,0 +1,188 @@
+// system/evolution/adaptation/retention.go
+
+package adaptation
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// ExperienceRetentionStats 经验缓冲区的保留统计
+type ExperienceRetentionStats struct {
+	Policy   string  // 保留策略
+	Capacity int     // 缓冲区容量
+	Buffered int     // 已保留的经验数
+	Offered  int64   // 到达的经验数
+	Admitted int64   // 保留的新经验数
+	Rejected int64   // 缓冲区已满时未保留的新经验数
+	Evicted  int64   // 为新经验腾出空间或因内存上限移除的经验数
+	Pressure float64 // 缓冲区占用比例(0-1), 为1时新经验需经保留策略准入
+}
+
+// experienceRetention 经验缓冲区的保留策略(由学习系统的锁保护)
+// 缓冲区始终按到达顺序排列, 移除经验不改变其余经验的相对顺序
+type experienceRetention struct {
+	policy string
+	rng    *rand.Rand
+	stats  ExperienceRetentionStats
+}
+
+// newExperienceRetention 创建保留策略
+func newExperienceRetention(cfg types.ExperienceRetentionConfig) (*experienceRetention, error) {
+	switch cfg.Policy {
+	case "":
+		cfg.Policy = types.ExperienceRetentionOldest
+	case types.ExperienceRetentionOldest, types.ExperienceRetentionFeedback,
+		types.ExperienceRetentionStratified, types.ExperienceRetentionReservoir:
+	default:
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown experience retention policy", nil).
+			WithContext("policy", cfg.Policy)
+	}
+
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &experienceRetention{
+		policy: cfg.Policy,
+		rng:    rand.New(rand.NewSource(seed)),
+		stats:  ExperienceRetentionStats{Policy: cfg.Policy},
+	}, nil
+}
+
+// admit 将新经验加入容量为capacity的缓冲区, 返回新缓冲区和是否保留了新经验
+func (r *experienceRetention) admit(buffer []LearningExperience, experience LearningExperience, capacity int) ([]LearningExperience, bool) {
+	r.stats.Offered++
+	if len(buffer) < capacity {
+		r.stats.Admitted++
+		return append(buffer, experience), true
+	}
+
+	victim := -1
+	switch r.policy {
+	case types.ExperienceRetentionReservoir:
+		// 第n个到达的经验以capacity/n的概率替换一个均匀选出的已保留经验
+		if j := r.rng.Int63n(r.stats.Offered); j < int64(len(buffer)) {
+			victim = int(j)
+		}
+	case types.ExperienceRetentionFeedback:
+		if i := lowestFeedback(buffer); i >= 0 && experience.Feedback >= buffer[i].Feedback {
+			victim = i
+		}
+	default:
+		victim = r.victim(buffer, experience.Type)
+	}
+
+	if victim < 0 {
+		r.stats.Rejected++
+		return buffer, false
+	}
+	buffer = append(removeExperience(buffer, victim), experience)
+	r.stats.Admitted++
+	r.stats.Evicted++
+	return buffer, true
+}
+
+// evict 按保留策略移除经验至keep条, 返回新缓冲区和移除数
+func (r *experienceRetention) evict(buffer []LearningExperience, keep int) ([]LearningExperience, int) {
+	excess := len(buffer) - keep
+	if excess <= 0 {
+		return buffer, 0
+	}
+	if r.policy == types.ExperienceRetentionOldest {
+		r.stats.Evicted += int64(excess)
+		return append([]LearningExperience(nil), buffer[excess:]...), excess
+	}
+
+	buffer = append([]LearningExperience(nil), buffer...)
+	for i := 0; i < excess; i++ {
+		buffer = removeExperience(buffer, r.victim(buffer, ""))
+	}
+	r.stats.Evicted += int64(excess)
+	return buffer, excess
+}
+
+// victim 缓冲区满时移除的经验, incoming为新经验的类型(驱逐时为空)
+func (r *experienceRetention) victim(buffer []LearningExperience, incoming string) int {
+	switch r.policy {
+	case types.ExperienceRetentionFeedback:
+		return lowestFeedback(buffer)
+	case types.ExperienceRetentionStratified:
+		return oldestOfLargestType(buffer, incoming)
+	case types.ExperienceRetentionReservoir:
+		return r.rng.Intn(len(buffer))
+	default:
+		return 0
+	}
+}
+
+// lowestFeedback 反馈最低的经验, 相同时取最早的
+func lowestFeedback(buffer []LearningExperience) int {
+	lowest := -1
+	for i, exp := range buffer {
+		if lowest < 0 || exp.Feedback < buffer[lowest].Feedback {
+			lowest = i
+		}
+	}
+	return lowest
+}
+
+// oldestOfLargestType 经验最多的类型中最早的经验; 计数时包含新经验, 相同时取最早经验所在的类型
+func oldestOfLargestType(buffer []LearningExperience, incoming string) int {
+	counts := make(map[string]int)
+	for _, exp := range buffer {
+		counts[exp.Type]++
+	}
+	if incoming != "" {
+		counts[incoming]++
+	}
+
+	largest := -1
+	for _, exp := range buffer {
+		if largest < 0 || counts[exp.Type] > largest {
+			largest = counts[exp.Type]
+		}
+	}
+	for i, exp := range buffer {
+		if counts[exp.Type] == largest {
+			return i
+		}
+	}
+	return 0
+}
+
+// removeExperience 移除第i条经验, 保持其余经验的顺序
+func removeExperience(buffer []LearningExperience, i int) []LearningExperience {
+	copy(buffer[i:], buffer[i+1:])
+	buffer[len(buffer)-1] = LearningExperience{}
+	return buffer[:len(buffer)-1]
+}
+
+// SetExperienceRetention 切换经验保留策略, 已保留的经验不变; 统计重新开始
+func (al *AdaptiveLearning) SetExperienceRetention(cfg types.ExperienceRetentionConfig) error {
+	retention, err := newExperienceRetention(cfg)
+	if err != nil {
+		return err
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+	al.retention = retention
+	return nil
+}
+
+// ExperienceRetentionStats 获取经验缓冲区的保留统计
+func (al *AdaptiveLearning) ExperienceRetentionStats() ExperienceRetentionStats {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	stats := al.retention.stats
+	stats.Capacity = al.config.memoryCapacity
+	stats.Buffered = len(al.state.experiences)
+	if stats.Capacity > 0 {
+		stats.Pressure = float64(stats.Buffered) / float64(stats.Capacity)
+	}
+	return stats
+}
@@ -0,0 +1,117 @@
+// system/evolution/adaptation/rulegen.go
+
+package adaptation
+
+import (
+	"fmt"
+	"hash/fnv"
+	"strconv"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// RuleGenerationStats 规则生成统计
+type RuleGenerationStats struct {
+	MaxPerCycle int           `json:"max_per_cycle"`
+	Cooldown    time.Duration `json:"cooldown"`
+	Cycles      int64         `json:"cycles"`       // 生成周期数
+	Generated   int64         `json:"generated"`    // 注册成功的规则数
+	Duplicates  int64         `json:"duplicates"`   // 与已有规则重复而丢弃的规则数
+	OverQuota   int64         `json:"over_quota"`   // 超出周期配额而丢弃的规则数
+	CoolingDown int64         `json:"cooling_down"` // 目标处于冷却期而丢弃的规则数
+	LastCycle   time.Time     `json:"last_cycle"`
+}
+
+// Suppressed 被丢弃的规则总数
+func (s RuleGenerationStats) Suppressed() int64 {
+	return s.Duplicates + s.OverQuota + s.CoolingDown
+}
+
+// ruleGenerator 学习周期生成规则的去重、配额和冷却, 由AdaptiveLearning的锁保护
+type ruleGenerator struct {
+	config       types.RuleGenerationConfig
+	signatures   map[string]bool      // 已有规则和本周期已注册规则的签名
+	lastByTarget map[string]time.Time // 各目标最近一次生成规则的时间
+	cycleCount   int                  // 本周期已注册的规则数
+	stats        RuleGenerationStats
+}
+
+// newRuleGenerator 创建规则生成限制
+func newRuleGenerator(cfg types.RuleGenerationConfig) *ruleGenerator {
+	if cfg.MaxPerCycle < 0 {
+		cfg.MaxPerCycle = 0
+	}
+	if cfg.Cooldown < 0 {
+		cfg.Cooldown = 0
+	}
+	return &ruleGenerator{
+		config:       cfg,
+		signatures:   make(map[string]bool),
+		lastByTarget: make(map[string]time.Time),
+		stats:        RuleGenerationStats{MaxPerCycle: cfg.MaxPerCycle, Cooldown: cfg.Cooldown},
+	}
+}
+
+// begin 开始一个生成周期, existing为策略中已有规则的签名
+func (g *ruleGenerator) begin(existing map[string]bool) {
+	g.signatures = existing
+	g.cycleCount = 0
+	g.stats.Cycles++
+	g.stats.LastCycle = time.Now()
+}
+
+// admit 判断规则是否可以注册, 不可注册时计入对应的丢弃统计
+func (g *ruleGenerator) admit(target, signature string) bool {
+	if g.signatures[signature] {
+		g.stats.Duplicates++
+		return false
+	}
+	if g.config.MaxPerCycle > 0 && g.cycleCount >= g.config.MaxPerCycle {
+		g.stats.OverQuota++
+		return false
+	}
+	if last, ok := g.lastByTarget[target]; ok && g.config.Cooldown > 0 && time.Since(last) < g.config.Cooldown {
+		g.stats.CoolingDown++
+		return false
+	}
+	return true
+}
+
+// registered 记录规则注册成功
+func (g *ruleGenerator) registered(target, signature string) {
+	g.signatures[signature] = true
+	g.lastByTarget[target] = time.Now()
+	g.cycleCount++
+	g.stats.Generated++
+}
+
+// ruleSignature 规则的签名, 由类型、目标、条件和动作计算, 不含ID、名称和权重
+// 参数表按键排序格式化, 内容相同的规则签名相同
+func ruleSignature(rule *StrategyRule) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%s|%s|%s|%v|%v|%s|%v|%s",
+		rule.Type, rule.Target,
+		rule.Condition.Expression, rule.Condition.Threshold, rule.Condition.Parameters,
+		rule.Action.Function, rule.Action.Parameters, rule.Action.ResultType)
+	return strconv.FormatUint(h.Sum64(), 16)
+}
+
+// GetRuleGenerationStats 获取规则生成统计
+func (al *AdaptiveLearning) GetRuleGenerationStats() RuleGenerationStats {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+	return al.ruleGen.stats
+}
+
+// ruleSignatures 已有规则的签名
+func (as *AdaptationStrategy) ruleSignatures() map[string]bool {
+	as.mu.RLock()
+	defer as.mu.RUnlock()
+
+	signatures := make(map[string]bool, len(as.state.rules))
+	for _, rule := range as.state.rules {
+		signatures[ruleSignature(rule)] = true
+	}
+	return signatures
+}
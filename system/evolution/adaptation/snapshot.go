@@ -0,0 +1,150 @@
+//system/evolution/adaptation/snapshot.go
+
+package adaptation
+
+import (
+	"time"
+)
+
+// LearningSnapshot 学习系统快照
+type LearningSnapshot struct {
+	Knowledge []KnowledgeRecord      `json:"knowledge"` // 知识库
+	Models    map[string]ModelRecord `json:"models"`    // 学习模型
+	Config    map[string]float64     `json:"config"`    // 学习配置
+	Stats     LearningStatistics     `json:"stats"`     // 学习统计
+}
+
+// KnowledgeRecord 可序列化的知识单元(不含验证函数)
+type KnowledgeRecord struct {
+	ID          string            `json:"id"`
+	Type        string            `json:"type"`
+	Content     interface{}       `json:"content"`
+	Metadata    KnowledgeMetadata `json:"metadata"`
+	Connections []KnowledgeLink   `json:"connections"`
+	Created     time.Time         `json:"created"`
+}
+
+// ModelRecord 可序列化的学习模型(仅保留权重和性能摘要)
+type ModelRecord struct {
+	ID         string                 `json:"id"`
+	Type       string                 `json:"type"`
+	Parameters map[string]interface{} `json:"parameters"`
+	Version    int                    `json:"version"`
+	Weights    map[string]float64     `json:"weights"`
+	LastUpdate time.Time              `json:"last_update"`
+	LastLoss   float64                `json:"last_loss"`
+	Accuracy   float64                `json:"accuracy"`
+	Loss       float64                `json:"loss"`
+}
+
+// ExportSnapshot 导出知识库和模型权重
+func (al *AdaptiveLearning) ExportSnapshot() *LearningSnapshot {
+	al.mu.RLock()
+	defer al.mu.RUnlock()
+
+	snapshot := &LearningSnapshot{
+		Knowledge: make([]KnowledgeRecord, 0, len(al.state.knowledge)),
+		Models:    make(map[string]ModelRecord, len(al.state.models)),
+		Config: map[string]float64{
+			"learning_rate":    al.config.learningRate,
+			"exploration_rate": al.config.explorationRate,
+			"decay_factor":     al.config.decayFactor,
+		},
+		Stats: al.state.statistics,
+	}
+
+	for _, unit := range al.state.knowledge {
+		snapshot.Knowledge = append(snapshot.Knowledge, KnowledgeRecord{
+			ID:          unit.ID,
+			Type:        unit.Type,
+			Content:     unit.Content,
+			Metadata:    unit.Metadata,
+			Connections: unit.Connections,
+			Created:     unit.Created,
+		})
+	}
+
+	for id, model := range al.state.models {
+		weights := make(map[string]float64, len(model.State.Weights))
+		for k, v := range model.State.Weights {
+			weights[k] = v
+		}
+		snapshot.Models[id] = ModelRecord{
+			ID:         model.ID,
+			Type:       model.Type,
+			Parameters: model.Parameters,
+			Version:    model.State.Version,
+			Weights:    weights,
+			LastUpdate: model.State.LastUpdate,
+			LastLoss:   model.State.LastLoss,
+			Accuracy:   model.Performance.Accuracy,
+			Loss:       model.Performance.Loss,
+		}
+	}
+
+	return snapshot
+}
+
+// RestoreSnapshot 从快照恢复知识库和模型权重
+func (al *AdaptiveLearning) RestoreSnapshot(snapshot *LearningSnapshot) {
+	if snapshot == nil {
+		return
+	}
+
+	al.mu.Lock()
+	defer al.mu.Unlock()
+
+	// 恢复配置
+	if rate, ok := snapshot.Config["learning_rate"]; ok {
+		al.config.learningRate = rate
+	}
+	if rate, ok := snapshot.Config["exploration_rate"]; ok {
+		al.config.explorationRate = rate
+	}
+	if factor, ok := snapshot.Config["decay_factor"]; ok {
+		al.config.decayFactor = factor
+	}
+
+	// 恢复知识库
+	al.state.knowledge = make(map[string]*KnowledgeUnit, len(snapshot.Knowledge))
+	for _, record := range snapshot.Knowledge {
+		al.state.knowledge[record.ID] = &KnowledgeUnit{
+			ID:          record.ID,
+			Type:        record.Type,
+			Content:     record.Content,
+			Metadata:    record.Metadata,
+			Connections: record.Connections,
+			Created:     record.Created,
+		}
+	}
+	al.state.prevKnowledgeCount = len(al.state.knowledge)
+
+	// 恢复模型, 丢弃在线学习中未满小批量的样本
+	al.state.models = make(map[string]*LearningModel, len(snapshot.Models))
+	al.state.pending = make(map[string][]TrainingItem)
+	for id, record := range snapshot.Models {
+		model := &LearningModel{
+			ID:         record.ID,
+			Type:       record.Type,
+			Parameters: record.Parameters,
+		}
+		model.State.Version = record.Version
+		model.State.Weights = record.Weights
+		model.State.LastUpdate = record.LastUpdate
+		model.State.LastLoss = record.LastLoss
+		model.State.Gradients = make(map[string]float64)
+		model.State.PrevGradients = make(map[string]float64)
+		model.Performance.Accuracy = record.Accuracy
+		model.Performance.Loss = record.Loss
+		if model.State.Weights == nil {
+			model.State.Weights = make(map[string]float64)
+		}
+		al.state.models[id] = model
+	}
+
+	// 恢复统计
+	al.state.statistics = snapshot.Stats
+	if al.state.statistics.ModelAccuracy == nil {
+		al.state.statistics.ModelAccuracy = make(map[string]float64)
+	}
+}
@@ -1,13 +1,16 @@
 package adaptation
 
 import (
-	"fmt"
 	"math"
 	"sort"
 	"sync"
 	"time"
 
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/evolution/audit"
+	"github.com/Corphon/daoflow/system/evolution/causal"
+	"github.com/Corphon/daoflow/system/evolution/constraint"
+	"github.com/Corphon/daoflow/system/evolution/extension"
 	"github.com/Corphon/daoflow/system/evolution/mutation"
 	"github.com/Corphon/daoflow/system/evolution/pattern"
 	"github.com/Corphon/daoflow/system/types"
@@ -36,11 +39,50 @@ type AdaptationStrategy struct {
 		rules      map[string]*StrategyRule // 策略规则
 		history    []StrategyEvent          // 策略历史
 		metrics    StrategyMetrics          // 策略指标
+		objectives map[string]Objective     // 适应目标
 	}
 
 	// 依赖项
 	patternMatcher  *pattern.EvolutionMatcher
 	mutationHandler *mutation.MutationHandler
+
+	// 外部策略扩展
+	extensions *extension.Manager
+
+	// 因果影响图来源, 用于优先干预上游原因
+	causalGraph func() *causal.Graph
+
+	// 动作约束
+	constraints *constraint.Checker
+
+	// 策略选择的多臂老虎机, nil时执行所有适用策略
+	bandit *Bandit
+
+	// 参数和规则变更的审计日志
+	auditLog *audit.Log
+
+	// 运行模式停用突变时为true, 只评估不执行策略
+	suspended bool
+
+	// 启动预热, 预热期间按配置停用或减弱策略动作; 为nil时不预热
+	warmup *types.Warmup
+
+	// 决策监听器
+	listeners []func(StrategyEvent)
+
+	// 执行跨度的接收方, 为nil时只生成追踪上下文
+	reporter types.SpanReporter
+
+	// 下次执行策略的触发原因, 执行后清空; 为空时每次执行开始新追踪
+	cause types.TraceContext
+
+	// 正在执行的策略的追踪上下文, 随策略事件记录
+	trace types.TraceContext
+
+	// 反事实回放的模拟沙箱, 为nil时使用无动作周期估计的漂移沙箱
+	simulator Simulator
+	drift     *DriftSimulator
+	lastCycle cycleObservation
 }
 
 // Strategy 适应策略
@@ -108,6 +150,7 @@ type StrategyEvent struct {
 	Type       string
 	Status     string
 	Details    map[string]interface{}
+	Trace      types.TraceContext // 所属策略执行的跨度, 非执行事件为空
 }
 
 // StrategyMetrics 策略指标
@@ -129,15 +172,18 @@ type MetricPoint struct {
 // NewAdaptationStrategy 创建新的适应策略管理器
 func NewAdaptationStrategy(matcher *pattern.EvolutionMatcher, handler *mutation.MutationHandler) (*AdaptationStrategy, error) {
 	if matcher == nil {
-		return nil, fmt.Errorf("nil evolution matcher")
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil evolution matcher", nil)
 	}
 	if handler == nil {
-		return nil, fmt.Errorf("nil mutation handler")
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil mutation handler", nil)
 	}
 
 	as := &AdaptationStrategy{
 		patternMatcher:  matcher,
 		mutationHandler: handler,
+		constraints:     constraint.NewChecker(),
+		auditLog:        audit.NewLog(types.AuditConfig{}),
+		drift:           NewDriftSimulator(),
 	}
 
 	// 初始化配置
@@ -150,6 +196,7 @@ func NewAdaptationStrategy(matcher *pattern.EvolutionMatcher, handler *mutation.
 	as.state.strategies = make(map[string]*Strategy)
 	as.state.rules = make(map[string]*StrategyRule)
 	as.state.history = make([]StrategyEvent, 0)
+	as.state.objectives = make(map[string]Objective)
 	as.state.metrics = StrategyMetrics{
 		Effectiveness: make(map[string]float64),
 		History:       make([]MetricPoint, 0),
@@ -168,7 +215,7 @@ func (as *AdaptationStrategy) GetRecentResults() ([]StrategyEvent, error) {
 	// 获取最近的执行记录
 	for i := len(as.state.history) - 1; i >= 0; i-- {
 		event := as.state.history[i]
-		if event.Type == "execution" {
+		if event.Type == "execution" || event.Type == "execution_complete" {
 			results = append(results, event)
 		}
 		// 只返回最近的记录
@@ -190,9 +237,11 @@ func (as *AdaptationStrategy) Execute() error {
 		return err
 	}
 
-	// 选择和应用策略
-	if err := as.applyStrategies(); err != nil {
-		return err
+	// 选择和应用策略, 预热期间的统计尚不可靠, 可配置为不执行
+	if !as.suspended && !as.warmup.Suppressed() {
+		if err := as.applyStrategies(); err != nil {
+			return err
+		}
 	}
 
 	// 更新规则状态
@@ -209,6 +258,13 @@ func (as *AdaptationStrategy) Execute() error {
 	return nil
 }
 
+// SetSuspended 停用或恢复策略执行, 停用期间仍更新策略评估和规则状态
+func (as *AdaptationStrategy) SetSuspended(suspended bool) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.suspended = suspended
+}
+
 // updateRules 更新规则状态
 func (as *AdaptationStrategy) updateRules() error {
 	as.mu.Lock()
@@ -223,11 +279,17 @@ func (as *AdaptationStrategy) updateRules() error {
 		if effectiveness < as.config.minEffectiveness {
 			// 尝试优化规则
 			if optimized := as.optimizeRule(rule); optimized != nil {
+				// 优化后的参数同样受约束, 被拒绝时保留原规则
+				if err := as.constrainRule(optimized); err != nil {
+					continue
+				}
 				as.state.rules[id] = optimized
 				as.recordStrategyEvent(rule, "rule_optimized", map[string]interface{}{
 					"old_effectiveness": effectiveness,
 					"new_effectiveness": as.evaluateRuleEffectiveness(optimized),
 				})
+				as.recordAudit(audit.KindRuleOptimized, id, ruleSnapshot(rule), ruleSnapshot(optimized),
+					audit.Lineage{Source: audit.SourceStrategy})
 			}
 		}
 	}
@@ -244,16 +306,15 @@ func (as *AdaptationStrategy) evaluateRuleEffectiveness(rule *StrategyRule) floa
 		return 0
 	}
 
-	// 计算成功率
-	successCount := 0
+	// 计算平均得分, 定义了适应目标时为目标达成度
+	total := 0.0
 	for _, event := range ruleEvents {
-		if event.Status == "success" {
-			successCount++
-		}
+		score, _ := eventScore(event)
+		total += score
 	}
 
 	// 计算有效性得分
-	effectiveness := float64(successCount) / float64(len(ruleEvents))
+	effectiveness := total / float64(len(ruleEvents))
 
 	// 考虑规则权重
 	return effectiveness * rule.Weight
@@ -297,6 +358,7 @@ func (as *AdaptationStrategy) recordStrategyEvent(
 			Type:       eventType,
 			Status:     "completed",
 			Details:    details,
+			Trace:      as.trace,
 		}
 	case *StrategyRule:
 		event = StrategyEvent{
@@ -316,6 +378,59 @@ func (as *AdaptationStrategy) recordStrategyEvent(
 	if len(as.state.history) > maxHistoryLength {
 		as.state.history = as.state.history[1:]
 	}
+
+	// 通知决策监听器
+	for _, listener := range as.listeners {
+		listener(event)
+	}
+}
+
+// OnDecision 注册决策监听器, 在策略事件记录时调用
+// 监听器在持有策略锁时执行, 不得阻塞或回调策略方法
+func (as *AdaptationStrategy) OnDecision(listener func(StrategyEvent)) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.listeners = append(as.listeners, listener)
+}
+
+// SetWarmup 设置启动预热, nil表示不预热
+func (as *AdaptationStrategy) SetWarmup(warmup *types.Warmup) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.warmup = warmup
+}
+
+// SetSpanReporter 设置策略执行跨度的接收方, nil时只生成追踪上下文
+func (as *AdaptationStrategy) SetSpanReporter(reporter types.SpanReporter) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.reporter = reporter
+}
+
+// Trigger 记录触发下次策略执行的原因, 如模式匹配事件的追踪上下文; 多次触发时取最近一次
+func (as *AdaptationStrategy) Trigger(cause types.TraceContext) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.cause = cause
+}
+
+// emitExecutionSpan 上报策略执行跨度(调用方持有锁)
+func (as *AdaptationStrategy) emitExecutionSpan(strategy *Strategy, cause types.TraceContext, start time.Time, err error) {
+	span := types.CausalSpan{
+		Trace:     as.trace,
+		Parent:    cause.SpanID,
+		Name:      "adaptation.execute",
+		Subsystem: "evolution.adaptation",
+		Start:     start,
+		Tags: map[string]string{
+			"strategy": strategy.ID,
+			"type":     strategy.Type,
+		},
+	}
+	if err != nil {
+		span.Error = err.Error()
+	}
+	types.EmitSpan(as.reporter, span)
 }
 
 // 辅助方法
@@ -334,7 +449,7 @@ func (as *AdaptationStrategy) findOptimalThreshold(events []StrategyEvent) float
 	// 收集成功事件的阈值
 	successThresholds := make([]float64, 0)
 	for _, event := range events {
-		if event.Status == "success" {
+		if eventSucceeded(event) {
 			if threshold, ok := event.Details["threshold"].(float64); ok {
 				successThresholds = append(successThresholds, threshold)
 			}
@@ -359,7 +474,7 @@ func (as *AdaptationStrategy) optimizeActionParameters(rule *StrategyRule) map[s
 
 	// 从成功事件中提取有效参数
 	for _, event := range events {
-		if event.Status == "success" {
+		if eventSucceeded(event) {
 			if eventParams, ok := event.Details["parameters"].(map[string]interface{}); ok {
 				for k, v := range eventParams {
 					params[k] = v
@@ -443,7 +558,7 @@ func (as *AdaptationStrategy) calculateSuccessRate() float64 {
 
 	successCount := 0
 	for _, event := range as.state.history {
-		if event.Status == "success" {
+		if eventSucceeded(event) {
 			successCount++
 		}
 	}
@@ -467,7 +582,7 @@ func (as *AdaptationStrategy) calculateAverageEffectiveness() float64 {
 // RegisterStrategy 注册新策略
 func (as *AdaptationStrategy) RegisterStrategy(strategy *Strategy) error {
 	if strategy == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "nil strategy")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "nil strategy", nil)
 	}
 
 	as.mu.Lock()
@@ -572,24 +687,26 @@ func (as *AdaptationStrategy) evaluateStrategy(strategy *Strategy) float64 {
 		return 0
 	}
 
-	// 计算成功率
-	successCount := 0
+	// 计算平均得分, 定义了适应目标时为目标达成度
+	scoreSum := 0.0
 	weightedScore := 0.0
 	totalWeight := 0.0
 
 	for _, event := range events {
-		if event.Status == "success" {
-			successCount++
-			// 考虑时间衰减
-			age := time.Since(event.Timestamp).Hours()
-			weight := math.Exp(-age / 24.0) // 24小时衰减
-			weightedScore += weight
-			totalWeight += weight
+		score, _ := eventScore(event)
+		if score <= 0 {
+			continue
 		}
+		scoreSum += score
+		// 考虑时间衰减
+		age := time.Since(event.Timestamp).Hours()
+		weight := math.Exp(-age / 24.0) // 24小时衰减
+		weightedScore += score * weight
+		totalWeight += weight
 	}
 
 	// 基础有效性得分
-	baseScore := float64(successCount) / float64(len(events))
+	baseScore := scoreSum / float64(len(events))
 
 	// 加入时间加权得分
 	timeWeightedScore := 0.0
@@ -606,7 +723,7 @@ func (as *AdaptationStrategy) optimizeStrategy(strategy *Strategy) error {
 	// 获取历史执行数据
 	events := as.getStrategyEvents(strategy.ID)
 	if len(events) == 0 {
-		return fmt.Errorf("no historical data for strategy optimization")
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalidState, "no historical data for strategy optimization", nil)
 	}
 
 	// 1. 优化参数
@@ -647,7 +764,7 @@ func (as *AdaptationStrategy) optimizeStrategy(strategy *Strategy) error {
 func filterSuccessEvents(events []StrategyEvent) []StrategyEvent {
 	success := make([]StrategyEvent, 0)
 	for _, event := range events {
-		if event.Status == "success" {
+		if eventSucceeded(event) {
 			success = append(success, event)
 		}
 	}
@@ -686,7 +803,7 @@ func extractOptimalParameters(events []StrategyEvent) map[string]interface{} {
 func findOptimalConditionThreshold(events []StrategyEvent, condition StrategyCondition) float64 {
 	values := make([]float64, 0)
 	for _, event := range events {
-		if event.Status == "success" {
+		if eventSucceeded(event) {
 			if v, ok := event.Details[condition.Target].(float64); ok {
 				values = append(values, v)
 			}
@@ -706,7 +823,7 @@ func findOptimalConditionThreshold(events []StrategyEvent, condition StrategyCon
 func optimizeActionParams(events []StrategyEvent, action StrategyAction) map[string]interface{} {
 	params := make(map[string]interface{})
 	for _, event := range events {
-		if event.Status == "success" && event.Type == action.Type {
+		if eventSucceeded(event) && event.Type == action.Type {
 			if actionParams, ok := event.Details["action_params"].(map[string]interface{}); ok {
 				for k, v := range actionParams {
 					params[k] = v
@@ -723,7 +840,7 @@ func calculateOptimizedPriority(strategy *Strategy, events []StrategyEvent) int
 	if len(events) > 0 {
 		successCount := 0
 		for _, event := range events {
-			if event.Status == "success" {
+			if eventSucceeded(event) {
 				successCount++
 			}
 		}
@@ -742,21 +859,44 @@ func (as *AdaptationStrategy) applyStrategies() error {
 		return err
 	}
 
+	// 上一周期未执行策略时, 本周期的状态变化作为反事实回放的无动作样本
+	as.observeCycle(state, time.Now())
+
 	// 选择适用的策略
 	applicable := as.selectApplicableStrategies(state)
 
 	// 按优先级排序
 	sortedStrategies := as.sortStrategiesByPriority(applicable)
 
-	// 执行策略
+	// 启用老虎机时每个周期只执行其选中的策略
+	if as.bandit != nil {
+		sortedStrategies = as.selectByBandit(sortedStrategies)
+	}
+
+	// 执行策略, 每个策略的执行作为触发原因的子跨度
+	cause := as.cause
+	as.cause = types.TraceContext{}
+	defer func() { as.trace = types.TraceContext{} }()
+	as.lastCycle.treated = len(sortedStrategies) > 0
+
 	for _, strategy := range sortedStrategies {
-		if err := as.executeStrategy(strategy, state); err != nil {
+		start := time.Now()
+		as.trace = cause.Child()
+		err := as.executeStrategy(strategy, state)
+		as.emitExecutionSpan(strategy, cause, start, err)
+		if err != nil {
 			// 记录错误但继续执行其他策略
 			as.recordStrategyEvent(strategy, "execution_error", map[string]interface{}{
 				"error": err.Error(),
 			})
+			if as.bandit != nil {
+				as.rewardBandit(strategy, true)
+			}
 			continue
 		}
+		if as.bandit != nil {
+			as.rewardBandit(strategy, false)
+		}
 
 		// 更新使用时间
 		strategy.LastUsed = time.Now()
@@ -823,57 +963,140 @@ func (as *AdaptationStrategy) evaluateCondition(condition StrategyCondition, sta
 }
 
 // sortStrategiesByPriority 按优先级排序策略
+// 优先级相同时, 按因果影响图优先执行作用于上游原因的策略
 func (as *AdaptationStrategy) sortStrategiesByPriority(strategies []*Strategy) []*Strategy {
 	sorted := make([]*Strategy, len(strategies))
 	copy(sorted, strategies)
 
-	sort.Slice(sorted, func(i, j int) bool {
+	var graph *causal.Graph
+	if as.causalGraph != nil {
+		graph = as.causalGraph()
+	}
+	scores := make(map[string]float64, len(sorted))
+	for _, strategy := range sorted {
+		scores[strategy.ID] = causalScore(graph, strategy)
+	}
+
+	sort.SliceStable(sorted, func(i, j int) bool {
 		// 优先级高的排在前面
-		return sorted[i].Priority > sorted[j].Priority
+		if sorted[i].Priority != sorted[j].Priority {
+			return sorted[i].Priority > sorted[j].Priority
+		}
+		return scores[sorted[i].ID] > scores[sorted[j].ID]
 	})
 
 	return sorted
 }
 
+// SetCausalGraph 设置因果影响图来源, 来源在持有策略锁时调用, 不得阻塞
+func (as *AdaptationStrategy) SetCausalGraph(source func() *causal.Graph) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+	as.causalGraph = source
+}
+
+// causalScore 策略的干预价值, 取策略自身动作节点和各动作目标在影响图中的最大上游影响力
+func causalScore(graph *causal.Graph, strategy *Strategy) float64 {
+	if graph == nil {
+		return 0
+	}
+	score := graph.InterventionScore(causal.ActionNode(strategy.ID))
+	for _, action := range strategy.Actions {
+		score = math.Max(score, graph.InterventionScore(action.Target))
+	}
+	return score
+}
+
 // executeStrategy 执行单个策略
 func (as *AdaptationStrategy) executeStrategy(strategy *Strategy, modelState *model.SystemState) error {
 	// 记录开始执行
 	startTime := time.Now()
 	as.recordStrategyEvent(strategy, "execution_start", nil)
+	before, scored := as.evaluateState(modelState)
 
 	state := types.FromModelSystemState(modelState)
 
+	// 外部策略由扩展决定动作
+	if strategy.Type == StrategyTypeExternal {
+		if err := as.executeExtension(strategy, state); err != nil {
+			return err
+		}
+	}
+
 	// 执行每个动作
 	for _, action := range strategy.Actions {
-		if err := as.executeAction(action, state); err != nil {
+		if err := as.executeAction(strategy, action, state); err != nil {
 			return err
 		}
 	}
 
-	// 记录执行成功
-	as.recordStrategyEvent(strategy, "execution_complete", map[string]interface{}{
-		"duration": time.Since(startTime).Milliseconds(),
-		"state":    state,
-	})
+	// 记录执行成功, 定义了适应目标时按执行后的状态评分
+	details := map[string]interface{}{
+		"duration":  time.Since(startTime).Milliseconds(),
+		"state":     state,
+		detailRules: append([]string(nil), strategy.Rules...),
+	}
+	if scored {
+		if after, err := as.getCurrentState(); err == nil {
+			if score, ok := as.evaluateState(after); ok {
+				details[detailObjectiveBefore] = before.Score
+				details[detailObjectiveScore] = score.Score
+				details[detailObjectiveValues] = score.Values
+			}
+		}
+	}
+	as.recordStrategyEvent(strategy, "execution_complete", details)
 
 	return nil
 }
 
-// executeAction 执行动作
-func (as *AdaptationStrategy) executeAction(action StrategyAction, state *types.SystemState) error {
+// executeAction 执行动作, 参数先经约束裁剪, 违反拒绝约束时不执行; 预热期间数值参数按预热倍率减弱
+func (as *AdaptationStrategy) executeAction(strategy *Strategy, action StrategyAction, state *types.SystemState) error {
+	params, err := as.constrainParameters(strategy, strategy.ID, action.Target, action.Parameters)
+	if err != nil {
+		return err
+	}
+	if damping := as.warmup.Damping(); damping < 1 {
+		params = dampParameters(params, damping)
+	}
+
 	switch action.Operation {
 	case "adjust":
-		action.Parameters["current_state"] = state
-		return as.adjustSystemParameter(action.Target, action.Parameters)
+		params["current_state"] = state
+		err = as.adjustSystemParameter(action.Target, params)
 	case "optimize":
-		action.Parameters["system_state"] = state
-		return as.optimizeSystem(action.Parameters)
+		params["system_state"] = state
+		err = as.optimizeSystem(params)
 	case "transform":
-		action.Parameters["state_info"] = state
-		return as.transformSystem(action.Parameters)
+		params["state_info"] = state
+		err = as.transformSystem(params)
 	default:
-		return fmt.Errorf("unknown action operation: %s", action.Operation)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown action operation", nil).
+			WithContext("operation", action.Operation)
+	}
+	if err != nil {
+		return err
+	}
+
+	// 记录已执行的值, 作为变化率约束的基准
+	as.constraints.Commit(action.Target, params, time.Now())
+	return nil
+}
+
+// dampParameters 数值参数乘以倍率后的副本
+func dampParameters(params map[string]interface{}, damping float64) map[string]interface{} {
+	damped := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		switch n := v.(type) {
+		case float64:
+			damped[k] = n * damping
+		case float32:
+			damped[k] = float64(n) * damping
+		default:
+			damped[k] = v
+		}
 	}
+	return damped
 }
 
 // 辅助方法
@@ -896,7 +1119,13 @@ func (as *AdaptationStrategy) transformSystem(params map[string]interface{}) err
 
 func (as *AdaptationStrategy) validateStrategy(strategy *Strategy) error {
 	if strategy.ID == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty strategy ID")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty strategy ID", nil)
+	}
+
+	if strategy.Type == StrategyTypeExternal {
+		if err := as.validateExternalStrategy(strategy); err != nil {
+			return err
+		}
 	}
 
 	// 验证条件
@@ -919,15 +1148,15 @@ func (as *AdaptationStrategy) validateStrategy(strategy *Strategy) error {
 // validateCondition 验证策略条件
 func (as *AdaptationStrategy) validateCondition(condition StrategyCondition) error {
 	if condition.Type == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty condition type")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty condition type", nil)
 	}
 
 	if condition.Target == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty condition target")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty condition target", nil)
 	}
 
 	if condition.Operator == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty condition operator")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty condition operator", nil)
 	}
 
 	// 验证操作符
@@ -940,7 +1169,7 @@ func (as *AdaptationStrategy) validateCondition(condition StrategyCondition) err
 		"<=": true,
 	}
 	if !validOperators[condition.Operator] {
-		return model.WrapError(nil, model.ErrCodeValidation, "invalid operator")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "invalid operator", nil)
 	}
 
 	return nil
@@ -949,15 +1178,15 @@ func (as *AdaptationStrategy) validateCondition(condition StrategyCondition) err
 // validateAction 验证策略动作
 func (as *AdaptationStrategy) validateAction(action StrategyAction) error {
 	if action.Type == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty action type")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty action type", nil)
 	}
 
 	if action.Target == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty action target")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty action target", nil)
 	}
 
 	if action.Operation == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty action operation")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty action operation", nil)
 	}
 
 	// 验证操作类型
@@ -967,12 +1196,12 @@ func (as *AdaptationStrategy) validateAction(action StrategyAction) error {
 		"transform": true,
 	}
 	if !validOperations[action.Operation] {
-		return model.WrapError(nil, model.ErrCodeValidation, "invalid operation")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "invalid operation", nil)
 	}
 
 	// 验证超时设置
 	if action.Timeout < 0 {
-		return model.WrapError(nil, model.ErrCodeValidation, "invalid timeout")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "invalid timeout", nil)
 	}
 
 	return nil
@@ -980,6 +1209,11 @@ func (as *AdaptationStrategy) validateAction(action StrategyAction) error {
 
 // UpdateParameters 更新策略参数
 func (as *AdaptationStrategy) UpdateParameters(strategyType string, params map[string]interface{}) error {
+	return as.updateParameters(strategyType, params, audit.Lineage{Source: audit.SourceExternal})
+}
+
+// updateParameters 更新策略参数并记录决策来源
+func (as *AdaptationStrategy) updateParameters(strategyType string, params map[string]interface{}, lineage audit.Lineage) error {
 	as.mu.Lock()
 	defer as.mu.Unlock()
 
@@ -993,7 +1227,8 @@ func (as *AdaptationStrategy) UpdateParameters(strategyType string, params map[s
 	}
 
 	if targetStrategy == nil {
-		return fmt.Errorf("strategy type %s not found", strategyType)
+		return types.NewDomainError(types.DomainEvolution, types.ErrNotFound, "strategy type not found", nil).
+			WithContext("type", strategyType)
 	}
 
 	// 验证参数
@@ -1010,6 +1245,7 @@ func (as *AdaptationStrategy) UpdateParameters(strategyType string, params map[s
 		"old_params": oldParams,
 		"new_params": params,
 	})
+	as.recordAudit(audit.KindParametersUpdated, targetStrategy.ID, oldParams, params, lineage)
 
 	return nil
 }
@@ -1017,14 +1253,15 @@ func (as *AdaptationStrategy) UpdateParameters(strategyType string, params map[s
 // validateParameters 验证参数有效性
 func (as *AdaptationStrategy) validateParameters(params map[string]interface{}) error {
 	if params == nil {
-		return fmt.Errorf("nil parameters")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "nil parameters", nil)
 	}
 
 	// 验证必需参数
 	requiredParams := []string{"weight", "threshold"}
 	for _, required := range requiredParams {
 		if _, exists := params[required]; !exists {
-			return fmt.Errorf("missing required parameter: %s", required)
+			return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "missing required parameter", nil).
+				WithContext("parameter", required)
 		}
 	}
 
@@ -1033,6 +1270,11 @@ func (as *AdaptationStrategy) validateParameters(params map[string]interface{})
 
 // RegisterRule 注册新规则
 func (as *AdaptationStrategy) RegisterRule(rule *StrategyRule) error {
+	return as.registerRule(rule, audit.Lineage{Source: audit.SourceExternal})
+}
+
+// registerRule 注册新规则并记录决策来源
+func (as *AdaptationStrategy) registerRule(rule *StrategyRule, lineage audit.Lineage) error {
 	as.mu.Lock()
 	defer as.mu.Unlock()
 
@@ -1041,14 +1283,20 @@ func (as *AdaptationStrategy) RegisterRule(rule *StrategyRule) error {
 		return err
 	}
 
+	// 检查动作约束
+	if err := as.constrainRule(rule); err != nil {
+		return err
+	}
+
 	// 检查规则存在性
 	if _, exists := as.state.rules[rule.ID]; exists {
-		return fmt.Errorf("rule %s already exists", rule.ID)
+		return types.NewDomainError(types.DomainEvolution, types.ErrExists, "rule already exists", nil).
+			WithContext("rule_id", rule.ID)
 	}
 
 	// 检查规则数量限制
 	if len(as.state.rules) >= maxRules {
-		return fmt.Errorf("max rules limit reached")
+		return types.NewDomainError(types.DomainEvolution, types.ErrOverflow, "max rules limit reached", nil)
 	}
 
 	// 存储规则
@@ -1059,6 +1307,7 @@ func (as *AdaptationStrategy) RegisterRule(rule *StrategyRule) error {
 		"rule_type": rule.Type,
 		"target":    rule.Target,
 	})
+	as.recordAudit(audit.KindRuleRegistered, rule.ID, nil, ruleSnapshot(rule), lineage)
 
 	return nil
 }
@@ -1066,19 +1315,19 @@ func (as *AdaptationStrategy) RegisterRule(rule *StrategyRule) error {
 // validateRule 验证规则有效性
 func (as *AdaptationStrategy) validateRule(rule *StrategyRule) error {
 	if rule == nil {
-		return fmt.Errorf("nil rule")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "nil rule", nil)
 	}
 
 	if rule.ID == "" {
-		return fmt.Errorf("empty rule ID")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "empty rule ID", nil)
 	}
 
 	if rule.Type == "" {
-		return fmt.Errorf("empty rule type")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "empty rule type", nil)
 	}
 
 	if rule.Target == "" {
-		return fmt.Errorf("empty rule target")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "empty rule target", nil)
 	}
 
 	return nil
@@ -1099,6 +1348,11 @@ func (as *AdaptationStrategy) GetRules() []*StrategyRule {
 
 // UpdateRule 更新规则
 func (as *AdaptationStrategy) UpdateRule(rule *StrategyRule) error {
+	return as.updateRule(rule, audit.Lineage{Source: audit.SourceExternal})
+}
+
+// updateRule 更新规则并记录决策来源
+func (as *AdaptationStrategy) updateRule(rule *StrategyRule, lineage audit.Lineage) error {
 	as.mu.Lock()
 	defer as.mu.Unlock()
 
@@ -1107,10 +1361,16 @@ func (as *AdaptationStrategy) UpdateRule(rule *StrategyRule) error {
 		return err
 	}
 
+	// 检查动作约束
+	if err := as.constrainRule(rule); err != nil {
+		return err
+	}
+
 	// 检查规则存在性
 	oldRule, exists := as.state.rules[rule.ID]
 	if !exists {
-		return fmt.Errorf("rule %s not found", rule.ID)
+		return types.NewDomainError(types.DomainEvolution, types.ErrNotFound, "rule not found", nil).
+			WithContext("rule_id", rule.ID)
 	}
 
 	// 更新规则
@@ -1123,6 +1383,7 @@ func (as *AdaptationStrategy) UpdateRule(rule *StrategyRule) error {
 		"old_condition": oldRule.Condition,
 		"new_condition": rule.Condition,
 	})
+	as.recordAudit(audit.KindRuleUpdated, rule.ID, ruleSnapshot(oldRule), ruleSnapshot(rule), lineage)
 
 	return nil
 }
@@ -0,0 +1,70 @@
+// system/evolution/adaptation/validation.go
+
+package adaptation
+
+import (
+	"math"
+	"math/rand"
+)
+
+// 训练验证默认参数
+const (
+	defaultValidationRatio = 0.2  // 验证集比例
+	defaultPatience        = 5    // 早停前允许验证损失不改善的轮数
+	defaultMinDelta        = 1e-4 // 验证损失视为改善的最小降幅
+)
+
+// splitTrainingData 按种子打乱后划分训练集和验证集, 样本少于2个时不划分验证集
+func splitTrainingData(data []TrainingItem, ratio float64, seed int64) ([]TrainingItem, []TrainingItem) {
+	if len(data) < 2 {
+		return data, nil
+	}
+	shuffled := append([]TrainingItem(nil), data...)
+	rng := rand.New(rand.NewSource(seed))
+	rng.Shuffle(len(shuffled), func(i, j int) {
+		shuffled[i], shuffled[j] = shuffled[j], shuffled[i]
+	})
+
+	n := int(math.Round(float64(len(shuffled)) * ratio))
+	n = max(1, min(n, len(shuffled)-1))
+	return shuffled[n:], shuffled[:n]
+}
+
+// earlyStopping 按验证损失早停, 保留验证损失最低时的权重
+type earlyStopping struct {
+	patience  int
+	minDelta  float64
+	best      float64
+	bestEpoch int
+	weights   map[string]float64
+	waited    int
+}
+
+// newEarlyStopping 创建早停判定
+func newEarlyStopping(patience int, minDelta float64) *earlyStopping {
+	return &earlyStopping{patience: patience, minDelta: minDelta, best: math.Inf(1)}
+}
+
+// observe 记录一轮的验证损失, 返回是否应停止训练
+func (es *earlyStopping) observe(epoch int, loss float64, weights map[string]float64) bool {
+	if loss < es.best-es.minDelta {
+		es.best = loss
+		es.bestEpoch = epoch
+		es.waited = 0
+		es.weights = make(map[string]float64, len(weights))
+		for k, v := range weights {
+			es.weights[k] = v
+		}
+		return false
+	}
+	es.waited++
+	return es.waited >= es.patience
+}
+
+// recordPerformance 追加性能记录, 维护历史记录长度
+func recordPerformance(model *LearningModel, point PerformancePoint) {
+	model.Performance.History = append(model.Performance.History, point)
+	if len(model.Performance.History) > maxModelHistory {
+		model.Performance.History = model.Performance.History[1:]
+	}
+}
@@ -0,0 +1,266 @@
+// system/evolution/audit/log.go
+
+package audit
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/encryption"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 审计记录类型
+const (
+	KindParametersUpdated = "parameters_updated" // 策略参数变更
+	KindRuleRegistered    = "rule_registered"    // 规则注册
+	KindRuleUpdated       = "rule_updated"       // 规则更新
+	KindRuleOptimized     = "rule_optimized"     // 规则自动优化
+	KindAPICall           = "api_call"           // 控制接口的特权调用
+)
+
+// 变更来源
+const (
+	SourceExternal      = "external"      // 外部调用
+	SourceLearning      = "learning"      // 适应学习的经验模式
+	SourceReinforcement = "reinforcement" // 强化学习
+	SourceOptimizer     = "optimizer"     // 适应优化器
+	SourceStrategy      = "strategy"      // 策略管理器自身
+	SourceAPI           = "api"           // 远程控制接口
+)
+
+// defaultCapacity 内存中保留的审计记录数
+const defaultCapacity = 10000
+
+// auditLabel 审计文件加密用途标识
+const auditLabel = "evolution.audit"
+
+// 审计文件单行上限
+const maxAuditLine = 4 << 20
+
+// Lineage 变更的决策来源
+type Lineage struct {
+	Source         string         `json:"source"`                    // 变更来源
+	Experiences    []string       `json:"experiences,omitempty"`     // 触发变更的学习经验ID
+	ModelVersions  map[string]int `json:"model_versions,omitempty"`  // 变更时各学习模型的版本
+	ObjectiveScore float64        `json:"objective_score,omitempty"` // 变更时的目标达成度
+	Scored         bool           `json:"scored"`                    // 是否有目标达成度
+}
+
+// Record 审计记录
+type Record struct {
+	Seq       uint64                 `json:"seq"`
+	Timestamp time.Time              `json:"timestamp"`
+	Kind      string                 `json:"kind"`
+	Target    string                 `json:"target"` // 策略ID或规则ID
+	Before    map[string]interface{} `json:"before,omitempty"`
+	After     map[string]interface{} `json:"after,omitempty"`
+	Lineage   Lineage                `json:"lineage"`
+}
+
+// Query 审计查询条件, 零值字段不限制
+type Query struct {
+	Since  time.Time `json:"since"`
+	Until  time.Time `json:"until"`
+	Target string    `json:"target"`
+	Kind   string    `json:"kind"`
+	Source string    `json:"source"`
+	Limit  int       `json:"limit"` // 返回最近的Limit条
+}
+
+// matches 记录是否满足查询条件
+func (q Query) matches(r *Record) bool {
+	if !q.Since.IsZero() && r.Timestamp.Before(q.Since) {
+		return false
+	}
+	if !q.Until.IsZero() && r.Timestamp.After(q.Until) {
+		return false
+	}
+	if q.Target != "" && r.Target != q.Target {
+		return false
+	}
+	if q.Kind != "" && r.Kind != q.Kind {
+		return false
+	}
+	return q.Source == "" || r.Lineage.Source == q.Source
+}
+
+// Log 策略变更的审计日志
+// 内存中保留最近的记录供查询; 配置了文件路径时每条记录同时以一行JSON追加写入文件, 作为持久记录
+type Log struct {
+	mu sync.RWMutex
+
+	capacity int
+	path     string
+	keyring  *encryption.Keyring
+	records  []Record
+	seq      uint64
+
+	writeErrors int64
+	lastError   error
+}
+
+// NewLog 创建审计日志, capacity<=0时使用默认容量, path为空时只保留在内存中
+func NewLog(cfg types.AuditConfig) *Log {
+	if cfg.Capacity <= 0 {
+		cfg.Capacity = defaultCapacity
+	}
+	return &Log{
+		capacity: cfg.Capacity,
+		path:     cfg.Path,
+		records:  make([]Record, 0),
+	}
+}
+
+// Append 追加审计记录, 分配序号和时间戳; 写入文件失败时记录仍保留在内存中
+func (l *Log) Append(r Record) Record {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.seq++
+	r.Seq = l.seq
+	if r.Timestamp.IsZero() {
+		r.Timestamp = time.Now()
+	}
+	r.Before = cloneMap(r.Before)
+	r.After = cloneMap(r.After)
+	r.Lineage.Experiences = append([]string(nil), r.Lineage.Experiences...)
+	if r.Lineage.ModelVersions != nil {
+		versions := make(map[string]int, len(r.Lineage.ModelVersions))
+		for k, v := range r.Lineage.ModelVersions {
+			versions[k] = v
+		}
+		r.Lineage.ModelVersions = versions
+	}
+
+	l.records = append(l.records, r)
+	if over := len(l.records) - l.capacity; over > 0 {
+		l.records = append([]Record(nil), l.records[over:]...)
+	}
+
+	if l.path != "" {
+		if err := l.persist(&r); err != nil {
+			l.writeErrors++
+			l.lastError = err
+		}
+	}
+	return r
+}
+
+// SetKeyring 设置审计文件的加密钥匙串, 此后写入的记录逐行加密; nil恢复明文写入
+func (l *Log) SetKeyring(kr *encryption.Keyring) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.keyring = kr
+}
+
+// Query 查询审计记录, 按时间顺序返回
+func (l *Log) Query(q Query) []Record {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	result := make([]Record, 0)
+	for i := range l.records {
+		if q.matches(&l.records[i]) {
+			result = append(result, l.records[i])
+		}
+	}
+	if q.Limit > 0 && len(result) > q.Limit {
+		result = result[len(result)-q.Limit:]
+	}
+	return result
+}
+
+// Export 以JSON数组导出满足条件的审计记录
+func (l *Log) Export(w io.Writer, q Query) error {
+	records := l.Query(q)
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(records); err != nil {
+		return types.NewDomainError(types.DomainEvolution, types.ErrRuntime, "failed to export audit log", err)
+	}
+	return nil
+}
+
+// GetMetrics 获取审计日志指标
+func (l *Log) GetMetrics() map[string]interface{} {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	metrics := map[string]interface{}{
+		"records":      len(l.records),
+		"total":        l.seq,
+		"write_errors": l.writeErrors,
+	}
+	if l.lastError != nil {
+		metrics["last_error"] = l.lastError.Error()
+	}
+	return metrics
+}
+
+// persist 以一行JSON追加写入审计文件
+func (l *Log) persist(r *Record) error {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return types.NewDomainError(types.DomainEvolution, types.ErrRuntime, "failed to encode audit record", err)
+	}
+	if data, err = encryption.SealLine(l.keyring, auditLabel, data); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(l.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return types.NewDomainError(types.DomainEvolution, types.ErrStorage, "failed to open audit file", err).
+			WithContext("path", l.path)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return types.NewDomainError(types.DomainEvolution, types.ErrStorage, "failed to write audit record", err).
+			WithContext("path", l.path)
+	}
+	return nil
+}
+
+// ReadRecords 读取持久化的审计文件, 加密行以kr解密, 明文行照常读取
+func ReadRecords(r io.Reader, kr *encryption.Keyring) ([]Record, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxAuditLine)
+
+	records := make([]Record, 0)
+	for line := 1; scanner.Scan(); line++ {
+		data := scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+		data, err := encryption.OpenLine(kr, auditLabel, data)
+		if err != nil {
+			return nil, err
+		}
+		var record Record
+		if err := json.Unmarshal(data, &record); err != nil {
+			return nil, types.NewDomainError(types.DomainEvolution, types.ErrStorage, "invalid audit record", err).
+				WithContext("line", line)
+		}
+		records = append(records, record)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrIO, "failed to read audit file", err)
+	}
+	return records, nil
+}
+
+// cloneMap 复制参数表, 记录不随原参数表变化
+func cloneMap(m map[string]interface{}) map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	clone := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		clone[k] = v
+	}
+	return clone
+}
@@ -0,0 +1,394 @@
+// system/evolution/causal/discoverer.go
+
+package causal
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultInterval     = time.Minute
+	defaultWindow       = 30 * time.Minute
+	defaultStep         = 10 * time.Second
+	defaultMaxLag       = 2
+	defaultSignificance = 0.05
+	maxSeriesPoints     = 500 // 对齐网格的最大点数, 超出时放大步长
+)
+
+// 节点类型
+const (
+	NodePattern = "pattern" // 涌现模式
+	NodeAction  = "action"  // 适应策略动作
+)
+
+// PatternNode 模式节点ID
+func PatternNode(patternID string) string {
+	return NodePattern + ":" + patternID
+}
+
+// ActionNode 适应策略动作节点ID
+func ActionNode(strategyID string) string {
+	return NodeAction + ":" + strategyID
+}
+
+// Node 因果图节点
+type Node struct {
+	ID   string `json:"id"`
+	Kind string `json:"kind"`
+}
+
+// Edge 有向影响边, From的历史显著改善对To的预测
+type Edge struct {
+	From       string  `json:"from"`
+	To         string  `json:"to"`
+	Lag        int     `json:"lag"`
+	F          float64 `json:"f"`
+	PValue     float64 `json:"p_value"`
+	Confidence float64 `json:"confidence"` // 1 - p值
+	Gain       float64 `json:"gain"`       // 残差平方和的下降比例
+}
+
+// Graph 有向影响图
+type Graph struct {
+	Nodes     []Node        `json:"nodes"`
+	Edges     []Edge        `json:"edges"` // 按置信度降序
+	Window    time.Duration `json:"window"`
+	Step      time.Duration `json:"step"`
+	Generated time.Time     `json:"generated"`
+}
+
+// Upstream 指向节点的影响边
+func (g *Graph) Upstream(node string) []Edge {
+	edges := make([]Edge, 0)
+	for _, e := range g.Edges {
+		if e.To == node {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// Downstream 节点发出的影响边
+func (g *Graph) Downstream(node string) []Edge {
+	edges := make([]Edge, 0)
+	for _, e := range g.Edges {
+		if e.From == node {
+			edges = append(edges, e)
+		}
+	}
+	return edges
+}
+
+// Influence 节点的上游影响力, 为其发出的各影响边置信度与增益之积的和
+// 没有发出边的节点为0
+func (g *Graph) Influence(node string) float64 {
+	influence := 0.0
+	for _, e := range g.Edges {
+		if e.From == node {
+			influence += e.Confidence * e.Gain
+		}
+	}
+	return influence
+}
+
+// Roots 影响其他节点而不受其他节点影响的上游原因, 按影响力降序
+func (g *Graph) Roots() []string {
+	incoming := make(map[string]bool)
+	outgoing := make(map[string]bool)
+	for _, e := range g.Edges {
+		incoming[e.To] = true
+		outgoing[e.From] = true
+	}
+	roots := make([]string, 0)
+	for node := range outgoing {
+		if !incoming[node] {
+			roots = append(roots, node)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		wi, wj := g.Influence(roots[i]), g.Influence(roots[j])
+		if wi != wj {
+			return wi > wj
+		}
+		return roots[i] < roots[j]
+	})
+	return roots
+}
+
+// InterventionScore 以影响图评估对目标的干预价值
+// 目标可为节点ID、模式ID或策略ID, 取匹配节点的最大上游影响力
+func (g *Graph) InterventionScore(target string) float64 {
+	if g == nil || target == "" {
+		return 0
+	}
+	best := 0.0
+	for _, n := range g.Nodes {
+		if n.ID == target || strings.TrimPrefix(n.ID, n.Kind+":") == target {
+			best = math.Max(best, g.Influence(n.ID))
+		}
+	}
+	return best
+}
+
+// Discoverer 因果发现器
+// 周期性地将活跃模式的强度序列和适应策略的执行序列对齐到共同网格,
+// 对每对(原因, 结果)做格兰杰检验, 显著的影响构成有向影响图
+type Discoverer struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.CausalConfig
+
+	// 数据来源
+	patterns func() []emergence.EmergentPattern
+	actions  map[string][]time.Time // 动作节点 -> 执行时间
+
+	// 刷新间隔倍率, 降级模式和资源预算节流时放慢发现
+	slowdown float64
+
+	// 每次刷新后通知耗时, 用于资源预算统计
+	cycleObserver func(time.Duration)
+
+	// 发现状态
+	state struct {
+		graph     *Graph
+		refreshes int64
+		tests     int64
+	}
+}
+
+// NewDiscoverer 创建因果发现器
+func NewDiscoverer(config types.CausalConfig) *Discoverer {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.Window <= 0 {
+		config.Window = defaultWindow
+	}
+	if config.Step <= 0 {
+		config.Step = defaultStep
+	}
+	if minStep := config.Window / maxSeriesPoints; config.Step < minStep {
+		config.Step = minStep
+	}
+	if config.MaxLag <= 0 {
+		config.MaxLag = defaultMaxLag
+	}
+	if config.Significance <= 0 || config.Significance >= 1 {
+		config.Significance = defaultSignificance
+	}
+
+	return &Discoverer{
+		config:  config,
+		actions: make(map[string][]time.Time),
+	}
+}
+
+// SetPatternSource 设置活跃模式来源
+func (d *Discoverer) SetPatternSource(source func() []emergence.EmergentPattern) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.patterns = source
+}
+
+// RecordAction 记录适应策略的一次执行, 可在持有其他组件锁时调用
+func (d *Discoverer) RecordAction(strategyID string, at time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	node := ActionNode(strategyID)
+	cutoff := at.Add(-d.config.Window)
+	kept := d.actions[node][:0]
+	for _, t := range d.actions[node] {
+		if t.After(cutoff) {
+			kept = append(kept, t)
+		}
+	}
+	d.actions[node] = append(kept, at)
+}
+
+// Latest 最近一次发现的影响图, 尚未发现时为nil; 不触发计算, 可在持有其他组件锁时调用
+func (d *Discoverer) Latest() *Graph {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return d.state.graph
+}
+
+// SetSlowdown 设置刷新间隔倍率, factor<=1时恢复正常间隔
+func (d *Discoverer) SetSlowdown(factor float64) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.slowdown = factor
+}
+
+// SetCycleObserver 设置刷新耗时通知, nil取消
+func (d *Discoverer) SetCycleObserver(fn func(time.Duration)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.cycleObserver = fn
+}
+
+// Run 按刷新间隔运行, 直到上下文取消
+func (d *Discoverer) Run(ctx context.Context) {
+	timer := time.NewTimer(d.interval())
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-timer.C:
+			start := time.Now()
+			d.Refresh(now)
+
+			d.mu.RLock()
+			observer := d.cycleObserver
+			d.mu.RUnlock()
+			if observer != nil {
+				observer(time.Since(start))
+			}
+			timer.Reset(d.interval())
+		}
+	}
+}
+
+// interval 当前的刷新间隔
+func (d *Discoverer) interval() time.Duration {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.slowdown <= 1 {
+		return d.config.Interval
+	}
+	return time.Duration(float64(d.config.Interval) * d.slowdown)
+}
+
+// Start 在协程监管下启动发现循环
+func (d *Discoverer) Start(ctx context.Context) {
+	supervisor.Go(ctx, "evolution.causal", d.Run)
+}
+
+// Refresh 按截至now的窗口重新发现影响图
+func (d *Discoverer) Refresh(now time.Time) *Graph {
+	d.mu.RLock()
+	source := d.patterns
+	actions := make(map[string][]time.Time, len(d.actions))
+	for node, times := range d.actions {
+		actions[node] = append([]time.Time(nil), times...)
+	}
+	d.mu.RUnlock()
+
+	// 在锁外读取模式, 来源可能需要获取检测器的锁
+	var patterns []emergence.EmergentPattern
+	if source != nil {
+		patterns = source()
+	}
+
+	start := now.Add(-d.config.Window)
+	points := int(d.config.Window/d.config.Step) + 1
+	graph := &Graph{
+		Nodes:     make([]Node, 0, len(patterns)+len(actions)),
+		Edges:     make([]Edge, 0),
+		Window:    d.config.Window,
+		Step:      d.config.Step,
+		Generated: now,
+	}
+
+	// 构造节点序列: 模式既可为原因也可为结果, 动作只作为原因
+	series := make(map[string][]float64)
+	effects := make([]string, 0, len(patterns))
+	for i := range patterns {
+		id := PatternNode(patterns[i].ID)
+		series[id] = emergence.AlignSeries(&patterns[i], emergence.SeriesStrength, start, d.config.Step, points)
+		effects = append(effects, id)
+		graph.Nodes = append(graph.Nodes, Node{ID: id, Kind: NodePattern})
+	}
+	for node, times := range actions {
+		series[node] = countSeries(times, start, d.config.Step, points)
+		graph.Nodes = append(graph.Nodes, Node{ID: node, Kind: NodeAction})
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool {
+		return graph.Nodes[i].ID < graph.Nodes[j].ID
+	})
+	sort.Strings(effects)
+
+	tests := int64(0)
+	for _, cause := range graph.Nodes {
+		for _, effect := range effects {
+			if cause.ID == effect {
+				continue
+			}
+			result, ok := Granger(series[cause.ID], series[effect], d.config.MaxLag)
+			if !ok {
+				continue
+			}
+			tests++
+			if result.PValue >= d.config.Significance {
+				continue
+			}
+			graph.Edges = append(graph.Edges, Edge{
+				From:       cause.ID,
+				To:         effect,
+				Lag:        result.Lag,
+				F:          result.F,
+				PValue:     result.PValue,
+				Confidence: 1 - result.PValue,
+				Gain:       result.Gain,
+			})
+		}
+	}
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].Confidence != graph.Edges[j].Confidence {
+			return graph.Edges[i].Confidence > graph.Edges[j].Confidence
+		}
+		return graph.Edges[i].Gain > graph.Edges[j].Gain
+	})
+
+	d.mu.Lock()
+	d.state.graph = graph
+	d.state.refreshes++
+	d.state.tests += tests
+	d.mu.Unlock()
+
+	return graph
+}
+
+// GetMetrics 获取发现指标
+func (d *Discoverer) GetMetrics() map[string]float64 {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	metrics := map[string]float64{
+		"refreshes": float64(d.state.refreshes),
+		"tests":     float64(d.state.tests),
+		"actions":   float64(len(d.actions)),
+		"nodes":     0,
+		"edges":     0,
+	}
+	if g := d.state.graph; g != nil {
+		metrics["nodes"] = float64(len(g.Nodes))
+		metrics["edges"] = float64(len(g.Edges))
+	}
+	return metrics
+}
+
+// countSeries 统计每个网格区间内的事件数
+func countSeries(times []time.Time, start time.Time, step time.Duration, points int) []float64 {
+	counts := make([]float64, points)
+	for _, t := range times {
+		idx := int(t.Sub(start) / step)
+		if idx >= 0 && idx < points {
+			counts[idx]++
+		}
+	}
+	return counts
+}
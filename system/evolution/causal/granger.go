@@ -0,0 +1,244 @@
+// system/evolution/causal/granger.go
+
+package causal
+
+import (
+	"math"
+)
+
+// GrangerResult 格兰杰因果检验结果
+type GrangerResult struct {
+	Lag     int     `json:"lag"`
+	F       float64 `json:"f"`       // F统计量
+	PValue  float64 `json:"p_value"` // 原假设(X不格兰杰导致Y)成立的概率
+	Samples int     `json:"samples"` // 参与回归的样本数
+	Gain    float64 `json:"gain"`    // 加入X的滞后项后残差平方和的下降比例
+}
+
+// Granger 检验序列x是否格兰杰导致序列y
+// 比较仅含y自身lag阶滞后的受限模型与加入x的lag阶滞后的完整模型, 以F检验判断x的滞后项是否显著;
+// NaN视为缺失, 含缺失值的回归样本被跳过. 样本不足或序列退化时返回false
+func Granger(x, y []float64, lag int) (GrangerResult, bool) {
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+	if lag < 1 {
+		lag = 1
+	}
+
+	// 构造回归样本
+	restricted := make([][]float64, 0, n)
+	full := make([][]float64, 0, n)
+	target := make([]float64, 0, n)
+	for t := lag; t < n; t++ {
+		if math.IsNaN(y[t]) {
+			continue
+		}
+		rowR := make([]float64, 0, lag+1)
+		rowF := make([]float64, 0, 2*lag+1)
+		rowR = append(rowR, 1)
+		rowF = append(rowF, 1)
+		valid := true
+		for i := 1; i <= lag && valid; i++ {
+			yi, xi := y[t-i], x[t-i]
+			if math.IsNaN(yi) || math.IsNaN(xi) {
+				valid = false
+				break
+			}
+			rowR = append(rowR, yi)
+			rowF = append(rowF, yi)
+		}
+		if !valid {
+			continue
+		}
+		for i := 1; i <= lag; i++ {
+			rowF = append(rowF, x[t-i])
+		}
+		restricted = append(restricted, rowR)
+		full = append(full, rowF)
+		target = append(target, y[t])
+	}
+
+	samples := len(target)
+	dfFull := samples - (2*lag + 1)
+	if dfFull < 1 {
+		return GrangerResult{}, false
+	}
+
+	rssR, ok := leastSquaresRSS(restricted, target)
+	if !ok {
+		return GrangerResult{}, false
+	}
+	rssF, ok := leastSquaresRSS(full, target)
+	if !ok {
+		return GrangerResult{}, false
+	}
+
+	result := GrangerResult{Lag: lag, Samples: samples, PValue: 1}
+	if rssR <= 1e-12 {
+		// y可由自身滞后完全解释, x不提供额外信息
+		return result, true
+	}
+	result.Gain = math.Max(0, (rssR-rssF)/rssR)
+	if rssF <= 1e-12 {
+		result.F = math.Inf(1)
+		result.PValue = 0
+		return result, true
+	}
+
+	result.F = math.Max(0, ((rssR-rssF)/float64(lag))/(rssF/float64(dfFull)))
+	result.PValue = fSurvival(result.F, float64(lag), float64(dfFull))
+	return result, true
+}
+
+// leastSquaresRSS 最小二乘回归的残差平方和, 设计矩阵奇异时返回false
+func leastSquaresRSS(rows [][]float64, y []float64) (float64, bool) {
+	if len(rows) == 0 {
+		return 0, false
+	}
+	k := len(rows[0])
+
+	// 正规方程 (XᵀX)β = Xᵀy
+	a := make([][]float64, k)
+	for i := range a {
+		a[i] = make([]float64, k+1)
+	}
+	for r, row := range rows {
+		for i := 0; i < k; i++ {
+			for j := 0; j < k; j++ {
+				a[i][j] += row[i] * row[j]
+			}
+			a[i][k] += row[i] * y[r]
+		}
+	}
+	beta, ok := solve(a)
+	if !ok {
+		return 0, false
+	}
+
+	rss := 0.0
+	for r, row := range rows {
+		pred := 0.0
+		for i, v := range row {
+			pred += beta[i] * v
+		}
+		d := y[r] - pred
+		rss += d * d
+	}
+	return rss, true
+}
+
+// solve 以部分主元高斯消元求解增广矩阵表示的线性方程组
+func solve(a [][]float64) ([]float64, bool) {
+	k := len(a)
+	for col := 0; col < k; col++ {
+		pivot := col
+		for r := col + 1; r < k; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for r := col + 1; r < k; r++ {
+			f := a[r][col] / a[col][col]
+			for c := col; c <= k; c++ {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	x := make([]float64, k)
+	for r := k - 1; r >= 0; r-- {
+		sum := a[r][k]
+		for c := r + 1; c < k; c++ {
+			sum -= a[r][c] * x[c]
+		}
+		x[r] = sum / a[r][r]
+	}
+	return x, true
+}
+
+// fSurvival F分布的上尾概率 P(F > f)
+func fSurvival(f, d1, d2 float64) float64 {
+	if f <= 0 {
+		return 1
+	}
+	if math.IsInf(f, 1) {
+		return 0
+	}
+	return regularizedBeta(d2/(d2+d1*f), d2/2, d1/2)
+}
+
+// regularizedBeta 正则化不完全贝塔函数 I_x(a, b)
+func regularizedBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	lab, _ := math.Lgamma(a + b)
+	front := math.Exp(lab - la - lb + a*math.Log(x) + b*math.Log(1-x))
+
+	// 连分式在x < (a+1)/(a+b+2)时收敛较快, 否则利用对称性
+	if x < (a+1)/(a+b+2) {
+		return front * betaFraction(x, a, b) / a
+	}
+	return 1 - front*betaFraction(1-x, b, a)/b
+}
+
+// betaFraction 不完全贝塔函数的连分式展开(Lentz法)
+func betaFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 1e-12
+		tiny          = 1e-300
+	)
+
+	c, d := 1.0, 1-(a+b)*x/(a+1)
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+
+		// 偶数项
+		num := fm * (b - fm) * x / ((a + 2*fm - 1) * (a + 2*fm))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		// 奇数项
+		num = -(a + fm) * (a + b + fm) * x / ((a + 2*fm) * (a + 2*fm + 1))
+		d = 1 + num*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + num/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		delta := d * c
+		h *= delta
+		if math.Abs(delta-1) < epsilon {
+			break
+		}
+	}
+	return h
+}
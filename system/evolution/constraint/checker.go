@@ -0,0 +1,361 @@
+// system/evolution/constraint/checker.go
+
+package constraint
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 约束类型
+const (
+	KindBound = "bound" // 参数值须在[Min, Max]内
+	KindRate  = "rate"  // 参数在窗口内相对窗口起点的变化比例不得超过MaxChange
+)
+
+// 违反约束时的处理方式
+const (
+	ModeClip   = "clip"   // 裁剪到允许范围后执行
+	ModeReject = "reject" // 拒绝整个动作
+)
+
+// 违反处理结果
+const (
+	ResultClipped  = "clipped"
+	ResultRejected = "rejected"
+)
+
+// AnyTarget 匹配所有动作目标
+const AnyTarget = "*"
+
+// 默认参数
+const (
+	defaultRateWindow = time.Hour
+	maxViolations     = 1000
+)
+
+// Constraint 适应动作的安全约束
+// 例如 {Kind: bound, Parameter: "energy", Max: 0.9} 禁止把能量推高到0.9以上,
+// {Kind: rate, Target: "learning", Parameter: "learning_rate", MaxChange: 0.1, Window: time.Hour}
+// 限制学习率每小时的调整不超过10%
+type Constraint struct {
+	Name      string        `json:"name"`       // 约束名称
+	Kind      string        `json:"kind"`       // 约束类型
+	Target    string        `json:"target"`     // 动作目标, 空或*匹配所有目标
+	Parameter string        `json:"parameter"`  // 动作参数名
+	Min       float64       `json:"min"`        // bound: 下界
+	Max       float64       `json:"max"`        // bound: 上界
+	MaxChange float64       `json:"max_change"` // rate: 最大相对变化比例
+	Window    time.Duration `json:"window"`     // rate: 变化窗口, 默认1小时
+	Mode      string        `json:"mode"`       // 违反时的处理方式, 默认clip
+}
+
+// Violation 约束违反记录
+type Violation struct {
+	Constraint string    `json:"constraint"`
+	Target     string    `json:"target"`
+	Parameter  string    `json:"parameter"`
+	Value      float64   `json:"value"`   // 动作请求的值
+	Allowed    float64   `json:"allowed"` // 允许范围内最接近的值
+	Result     string    `json:"result"`  // clipped或rejected
+	Source     string    `json:"source"`  // 动作来源, 如策略或规则ID
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// observation 已执行的参数值
+type observation struct {
+	value float64
+	at    time.Time
+}
+
+// Checker 约束检查器
+type Checker struct {
+	mu sync.RWMutex
+
+	constraints map[string]Constraint
+	applied     map[string][]observation // 目标/参数 -> 已执行的值
+	violations  []Violation
+	counts      map[string]int64 // 约束名 -> 违反次数
+}
+
+// NewChecker 创建约束检查器
+func NewChecker() *Checker {
+	return &Checker{
+		constraints: make(map[string]Constraint),
+		applied:     make(map[string][]observation),
+		violations:  make([]Violation, 0),
+		counts:      make(map[string]int64),
+	}
+}
+
+// Add 添加约束, 同名约束被替换
+func (c *Checker) Add(constraint Constraint) error {
+	if constraint.Target == "" {
+		constraint.Target = AnyTarget
+	}
+	if constraint.Mode == "" {
+		constraint.Mode = ModeClip
+	}
+	if constraint.Kind == KindRate && constraint.Window <= 0 {
+		constraint.Window = defaultRateWindow
+	}
+	if err := validate(constraint); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.constraints[constraint.Name] = constraint
+	return nil
+}
+
+// Remove 移除约束
+func (c *Checker) Remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.constraints, name)
+}
+
+// List 获取约束, 按名称排序
+func (c *Checker) List() []Constraint {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	list := make([]Constraint, 0, len(c.constraints))
+	for _, constraint := range c.constraints {
+		list = append(list, constraint)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Check 按约束检查动作参数
+// 返回裁剪后的参数副本和违反记录; 任一违反的约束为reject时返回错误, 此时动作不应执行.
+// 非数值参数和未受约束的参数原样保留
+func (c *Checker) Check(source, target string, params map[string]interface{}, now time.Time) (map[string]interface{}, []Violation, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := make(map[string]interface{}, len(params))
+	for k, v := range params {
+		result[k] = v
+	}
+
+	violations := make([]Violation, 0)
+	rejected := false
+	for _, constraint := range c.sortedConstraints() {
+		if constraint.Target != AnyTarget && constraint.Target != target {
+			continue
+		}
+		value, ok := numeric(result[constraint.Parameter])
+		if !ok {
+			continue
+		}
+		lo, hi, limited := c.allowedRange(constraint, target, now)
+		if !limited || (value >= lo && value <= hi) {
+			continue
+		}
+
+		allowed := value
+		if allowed < lo {
+			allowed = lo
+		}
+		if allowed > hi {
+			allowed = hi
+		}
+		violation := Violation{
+			Constraint: constraint.Name,
+			Target:     target,
+			Parameter:  constraint.Parameter,
+			Value:      value,
+			Allowed:    allowed,
+			Result:     ResultClipped,
+			Source:     source,
+			Timestamp:  now,
+		}
+		if constraint.Mode == ModeReject {
+			violation.Result = ResultRejected
+			rejected = true
+		} else {
+			result[constraint.Parameter] = allowed
+		}
+		violations = append(violations, violation)
+	}
+	c.recordViolations(violations)
+
+	if rejected {
+		return nil, violations, types.NewDomainError(types.DomainEvolution, types.ErrPermission, "action rejected by constraint", nil).
+			WithContext("target", target).
+			WithContext("source", source)
+	}
+	return result, violations, nil
+}
+
+// Commit 记录已执行动作的参数值, 作为变化率约束的基准
+func (c *Checker) Commit(target string, params map[string]interface{}, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, constraint := range c.constraints {
+		if constraint.Kind != KindRate || (constraint.Target != AnyTarget && constraint.Target != target) {
+			continue
+		}
+		value, ok := numeric(params[constraint.Parameter])
+		if !ok {
+			continue
+		}
+		key := target + "/" + constraint.Parameter
+		history := c.applied[key]
+		if n := len(history); n > 0 && history[n-1].at.Equal(now) && history[n-1].value == value {
+			continue
+		}
+		c.applied[key] = append(history, observation{value: value, at: now})
+	}
+	c.pruneApplied(now)
+}
+
+// Violations 获取最近的违反记录
+func (c *Checker) Violations() []Violation {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return append([]Violation(nil), c.violations...)
+}
+
+// GetMetrics 获取约束指标
+func (c *Checker) GetMetrics() map[string]float64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	metrics := map[string]float64{
+		"constraints": float64(len(c.constraints)),
+		"violations":  0,
+		"rejected":    0,
+	}
+	for _, n := range c.counts {
+		metrics["violations"] += float64(n)
+	}
+	for _, v := range c.violations {
+		if v.Result == ResultRejected {
+			metrics["rejected"]++
+		}
+	}
+	return metrics
+}
+
+// allowedRange 约束允许的取值范围, 变化率约束在窗口内没有基准时不限制
+func (c *Checker) allowedRange(constraint Constraint, target string, now time.Time) (float64, float64, bool) {
+	switch constraint.Kind {
+	case KindBound:
+		return constraint.Min, constraint.Max, true
+	case KindRate:
+		history := c.applied[target+"/"+constraint.Parameter]
+		cutoff := now.Add(-constraint.Window)
+		for _, obs := range history {
+			if obs.at.After(cutoff) {
+				delta := constraint.MaxChange * abs(obs.value)
+				return obs.value - delta, obs.value + delta, true
+			}
+		}
+	}
+	return 0, 0, false
+}
+
+// sortedConstraints 按名称排序的约束, 保证检查顺序稳定
+func (c *Checker) sortedConstraints() []Constraint {
+	list := make([]Constraint, 0, len(c.constraints))
+	for _, constraint := range c.constraints {
+		list = append(list, constraint)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// recordViolations 记录违反, 保留最近的记录
+func (c *Checker) recordViolations(violations []Violation) {
+	for _, v := range violations {
+		c.counts[v.Constraint]++
+	}
+	c.violations = append(c.violations, violations...)
+	if over := len(c.violations) - maxViolations; over > 0 {
+		c.violations = append([]Violation(nil), c.violations[over:]...)
+	}
+}
+
+// pruneApplied 清理超出所有变化率窗口的执行记录
+func (c *Checker) pruneApplied(now time.Time) {
+	window := time.Duration(0)
+	for _, constraint := range c.constraints {
+		if constraint.Kind == KindRate && constraint.Window > window {
+			window = constraint.Window
+		}
+	}
+	cutoff := now.Add(-window)
+	for key, history := range c.applied {
+		i := 0
+		for i < len(history) && !history[i].at.After(cutoff) {
+			i++
+		}
+		if i == len(history) {
+			delete(c.applied, key)
+		} else if i > 0 {
+			c.applied[key] = append([]observation(nil), history[i:]...)
+		}
+	}
+}
+
+// validate 校验约束
+func validate(constraint Constraint) error {
+	if constraint.Name == "" || constraint.Parameter == "" {
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "constraint name and parameter required", nil)
+	}
+	if constraint.Mode != ModeClip && constraint.Mode != ModeReject {
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown constraint mode", nil).
+			WithContext("mode", constraint.Mode)
+	}
+	switch constraint.Kind {
+	case KindBound:
+		if constraint.Min > constraint.Max {
+			return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "constraint min exceeds max", nil).
+				WithContext("constraint", constraint.Name)
+		}
+	case KindRate:
+		if constraint.MaxChange < 0 {
+			return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "negative constraint max change", nil).
+				WithContext("constraint", constraint.Name)
+		}
+	default:
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown constraint kind", nil).
+			WithContext("kind", constraint.Kind)
+	}
+	return nil
+}
+
+// numeric 参数的数值
+func numeric(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// abs 绝对值
+func abs(v float64) float64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -0,0 +1,477 @@
+// system/evolution/extension/extension.go
+
+package extension
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// ABIVersion 当前扩展ABI版本
+// 扩展以JSON交换EvaluationContext和Action, 字段只增不改, 不兼容的变更递增版本
+const ABIVersion = 1
+
+// 默认参数
+const (
+	defaultTimeout        = time.Second
+	defaultMaxInputBytes  = 1 << 20
+	defaultMaxOutputBytes = 1 << 20
+	defaultMemoryPages    = 256 // 16MiB
+)
+
+// 动作操作
+const (
+	OperationNone      = "none"      // 不执行动作
+	OperationAdjust    = "adjust"    // 调整参数
+	OperationOptimize  = "optimize"  // 优化系统
+	OperationTransform = "transform" // 转换系统
+	OperationClassify  = "classify"  // 分类结果
+)
+
+// EvaluationContext 传给扩展的评估上下文
+type EvaluationContext struct {
+	ABIVersion int                    `json:"abi_version"` // ABI版本
+	Extension  string                 `json:"extension"`   // 扩展名称
+	Timestamp  time.Time              `json:"timestamp"`   // 评估时间
+	Energy     float64                `json:"energy"`      // 系统能量
+	Metrics    map[string]float64     `json:"metrics"`     // 数值状态
+	Labels     map[string]string      `json:"labels"`      // 文本状态
+	Parameters map[string]interface{} `json:"parameters"`  // 策略参数
+}
+
+// Action 扩展返回的动作
+type Action struct {
+	Operation  string                 `json:"operation"`            // 操作: none, adjust, optimize, transform, classify
+	Target     string                 `json:"target,omitempty"`     // 目标对象
+	Parameters map[string]interface{} `json:"parameters,omitempty"` // 动作参数
+	Label      string                 `json:"label,omitempty"`      // 分类标签
+	Confidence float64                `json:"confidence"`           // 置信度(0-1)
+	Reason     string                 `json:"reason,omitempty"`     // 决策说明
+}
+
+// Evaluator 已加载的扩展实例
+// 输入输出均为JSON编码, 与扩展的编译环境解耦
+type Evaluator interface {
+	// ABIVersion 扩展声明的ABI版本
+	ABIVersion() int
+	// Evaluate 执行一次评估
+	Evaluate(ctx context.Context, input []byte) ([]byte, error)
+	// Close 释放扩展资源
+	Close() error
+}
+
+// Loader 扩展加载器
+type Loader interface {
+	Load(ctx context.Context, spec types.ExtensionSpec) (Evaluator, error)
+}
+
+// Info 扩展信息
+type Info struct {
+	Spec        types.ExtensionSpec // 扩展定义
+	ABIVersion  int                 // 扩展ABI版本
+	LoadedAt    time.Time           // 加载时间
+	Reloads     int                 // 重载次数
+	Evaluations int64               // 评估次数
+	Failures    int64               // 失败次数
+	LastLatency time.Duration       // 最近一次耗时
+	LastError   string              // 最近一次错误
+}
+
+// loaded 已加载的扩展
+type loaded struct {
+	evaluator Evaluator
+	info      Info
+	inflight  sync.WaitGroup // 执行中的评估
+}
+
+// Manager 扩展生命周期管理
+type Manager struct {
+	mu sync.RWMutex
+
+	// 格式对应的加载器
+	loaders map[string]Loader
+
+	// 已加载扩展
+	extensions map[string]*loaded
+}
+
+// NewManager 创建扩展管理器, 默认支持Go插件, WASM需设置运行时
+func NewManager() *Manager {
+	return &Manager{
+		loaders: map[string]Loader{
+			types.ExtensionGoPlugin: goPluginLoader{},
+		},
+		extensions: make(map[string]*loaded),
+	}
+}
+
+// RegisterLoader 注册格式加载器, 可替换默认加载器
+func (m *Manager) RegisterLoader(format string, loader Loader) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.loaders[format] = loader
+}
+
+// SetWasmRuntime 设置WASM运行时, 启用wasm格式扩展
+func (m *Manager) SetWasmRuntime(runtime WasmRuntime) {
+	m.RegisterLoader(types.ExtensionWasm, &wasmLoader{runtime: runtime})
+}
+
+// Load 加载扩展
+func (m *Manager) Load(ctx context.Context, spec types.ExtensionSpec) error {
+	m.mu.RLock()
+	_, exists := m.extensions[spec.Name]
+	m.mu.RUnlock()
+	if exists {
+		return types.NewDomainError(types.DomainEvolution, types.ErrExists, "extension already loaded", nil).
+			WithContext("extension", spec.Name)
+	}
+
+	ext, err := m.open(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.extensions[spec.Name]; exists {
+		ext.evaluator.Close()
+		return types.NewDomainError(types.DomainEvolution, types.ErrExists, "extension already loaded", nil).
+			WithContext("extension", spec.Name)
+	}
+	m.extensions[spec.Name] = ext
+	return nil
+}
+
+// Reload 以新定义重新加载扩展, 新实例加载成功后才替换旧实例
+func (m *Manager) Reload(ctx context.Context, spec types.ExtensionSpec) error {
+	m.mu.RLock()
+	_, exists := m.extensions[spec.Name]
+	m.mu.RUnlock()
+	if !exists {
+		return types.NewDomainError(types.DomainEvolution, types.ErrNotFound, "extension not loaded", nil).
+			WithContext("extension", spec.Name)
+	}
+
+	ext, err := m.open(ctx, spec)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	old, exists := m.extensions[spec.Name]
+	if exists {
+		ext.info.Reloads = old.info.Reloads + 1
+	}
+	m.extensions[spec.Name] = ext
+	m.mu.Unlock()
+
+	if exists {
+		return old.retire()
+	}
+	return nil
+}
+
+// Unload 卸载扩展
+func (m *Manager) Unload(name string) error {
+	m.mu.Lock()
+	ext, exists := m.extensions[name]
+	delete(m.extensions, name)
+	m.mu.Unlock()
+
+	if !exists {
+		return types.NewDomainError(types.DomainEvolution, types.ErrNotFound, "extension not loaded", nil).
+			WithContext("extension", name)
+	}
+	return ext.retire()
+}
+
+// Has 判断扩展是否已加载
+func (m *Manager) Has(name string) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, exists := m.extensions[name]
+	return exists
+}
+
+// Evaluate 执行策略扩展
+func (m *Manager) Evaluate(ctx context.Context, name string, ec EvaluationContext) (Action, error) {
+	m.mu.RLock()
+	ext, exists := m.extensions[name]
+	if exists {
+		ext.inflight.Add(1)
+	}
+	m.mu.RUnlock()
+
+	if !exists {
+		return Action{}, types.NewDomainError(types.DomainEvolution, types.ErrNotFound, "extension not loaded", nil).
+			WithContext("extension", name)
+	}
+
+	ec.ABIVersion = ABIVersion
+	ec.Extension = name
+	if ec.Timestamp.IsZero() {
+		ec.Timestamp = time.Now()
+	}
+
+	start := time.Now()
+	action, err := m.evaluate(ctx, ext, ec)
+	m.recordEvaluation(name, ext, time.Since(start), err)
+	return action, err
+}
+
+// Classify 执行分类扩展, 返回标签和置信度
+func (m *Manager) Classify(ctx context.Context, name string, ec EvaluationContext) (string, float64, error) {
+	m.mu.RLock()
+	ext, exists := m.extensions[name]
+	m.mu.RUnlock()
+
+	if exists && ext.info.Spec.Kind != types.ExtensionClassifier {
+		return "", 0, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "extension is not a classifier", nil).
+			WithContext("extension", name)
+	}
+
+	action, err := m.Evaluate(ctx, name, ec)
+	if err != nil {
+		return "", 0, err
+	}
+	return action.Label, action.Confidence, nil
+}
+
+// List 列出已加载扩展
+func (m *Manager) List() []Info {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]Info, 0, len(m.extensions))
+	for _, ext := range m.extensions {
+		list = append(list, ext.info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Spec.Name < list[j].Spec.Name
+	})
+	return list
+}
+
+// Close 卸载全部扩展
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	extensions := m.extensions
+	m.extensions = make(map[string]*loaded)
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, ext := range extensions {
+		if err := ext.retire(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// open 校验定义并通过加载器打开扩展
+func (m *Manager) open(ctx context.Context, spec types.ExtensionSpec) (*loaded, error) {
+	spec, err := normalizeSpec(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.RLock()
+	loader, ok := m.loaders[spec.Format]
+	m.mu.RUnlock()
+	if !ok {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "no loader for extension format", nil).
+			WithContext("extension", spec.Name).
+			WithContext("format", spec.Format)
+	}
+
+	evaluator, err := loader.Load(ctx, spec)
+	if err != nil {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to load extension", err).
+			WithContext("extension", spec.Name).
+			WithContext("path", spec.Path)
+	}
+
+	if v := evaluator.ABIVersion(); v != ABIVersion {
+		evaluator.Close()
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unsupported extension ABI version", nil).
+			WithContext("extension", spec.Name).
+			WithContext("abi_version", v)
+	}
+
+	return &loaded{
+		evaluator: evaluator,
+		info: Info{
+			Spec:       spec,
+			ABIVersion: ABIVersion,
+			LoadedAt:   time.Now(),
+		},
+	}, nil
+}
+
+// evaluate 在超时和崩溃隔离下执行评估
+func (m *Manager) evaluate(ctx context.Context, ext *loaded, ec EvaluationContext) (action Action, err error) {
+	spec := ext.info.Spec
+
+	input, err := json.Marshal(ec)
+	if err != nil {
+		ext.inflight.Done()
+		return Action{}, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "failed to encode evaluation context", err)
+	}
+	if len(input) > spec.Sandbox.MaxInputBytes {
+		ext.inflight.Done()
+		return Action{}, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "evaluation context too large", nil).
+			WithContext("extension", spec.Name).
+			WithContext("bytes", len(input))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, spec.Timeout)
+	defer cancel()
+
+	type result struct {
+		output []byte
+		err    error
+	}
+	done := make(chan result, 1)
+	go func() {
+		// 评估协程结束后才允许关闭扩展, 超时返回不代表扩展已停止执行
+		defer ext.inflight.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				done <- result{err: types.NewDomainError(types.DomainEvolution, types.ErrRuntime, fmt.Sprintf("extension panic: %v", r), nil)}
+			}
+		}()
+		output, err := ext.evaluator.Evaluate(ctx, input)
+		done <- result{output: output, err: err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		return Action{}, types.NewDomainError(types.DomainEvolution, types.ErrTimeout, "extension evaluation timed out", ctx.Err()).
+			WithContext("extension", spec.Name)
+	}
+
+	if res.err != nil {
+		return Action{}, types.NewDomainError(types.DomainEvolution, types.ErrInternal, "extension evaluation failed", res.err).
+			WithContext("extension", spec.Name)
+	}
+	if len(res.output) > spec.Sandbox.MaxOutputBytes {
+		return Action{}, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "extension output too large", nil).
+			WithContext("extension", spec.Name).
+			WithContext("bytes", len(res.output))
+	}
+
+	if err := json.Unmarshal(res.output, &action); err != nil {
+		return Action{}, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "invalid extension output", err).
+			WithContext("extension", spec.Name)
+	}
+	if err := validateAction(spec, &action); err != nil {
+		return Action{}, err
+	}
+	return action, nil
+}
+
+// retire 等待执行中的评估结束后关闭扩展, 最多等待一个评估超时
+func (ext *loaded) retire() error {
+	idle := make(chan struct{})
+	go func() {
+		ext.inflight.Wait()
+		close(idle)
+	}()
+
+	timer := time.NewTimer(ext.info.Spec.Timeout)
+	defer timer.Stop()
+
+	select {
+	case <-idle:
+	case <-timer.C:
+	}
+	return ext.evaluator.Close()
+}
+
+// recordEvaluation 记录评估统计, 扩展已被重载或卸载时忽略
+func (m *Manager) recordEvaluation(name string, ext *loaded, latency time.Duration, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.extensions[name] != ext {
+		return
+	}
+	ext.info.Evaluations++
+	ext.info.LastLatency = latency
+	if err != nil {
+		ext.info.Failures++
+		ext.info.LastError = err.Error()
+	}
+}
+
+// normalizeSpec 校验扩展定义并填充默认值
+func normalizeSpec(spec types.ExtensionSpec) (types.ExtensionSpec, error) {
+	if spec.Name == "" {
+		return spec, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "empty extension name", nil)
+	}
+	if spec.Path == "" {
+		return spec, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "empty extension path", nil).
+			WithContext("extension", spec.Name)
+	}
+	if spec.Kind == "" {
+		spec.Kind = types.ExtensionStrategy
+	}
+	if spec.Kind != types.ExtensionStrategy && spec.Kind != types.ExtensionClassifier {
+		return spec, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unknown extension kind", nil).
+			WithContext("extension", spec.Name).
+			WithContext("kind", spec.Kind)
+	}
+	if spec.Timeout <= 0 {
+		spec.Timeout = defaultTimeout
+	}
+	if spec.Sandbox.MemoryPages == 0 {
+		spec.Sandbox.MemoryPages = defaultMemoryPages
+	}
+	if spec.Sandbox.MaxInputBytes <= 0 {
+		spec.Sandbox.MaxInputBytes = defaultMaxInputBytes
+	}
+	if spec.Sandbox.MaxOutputBytes <= 0 {
+		spec.Sandbox.MaxOutputBytes = defaultMaxOutputBytes
+	}
+	return spec, nil
+}
+
+// validateAction 校验扩展返回的动作
+func validateAction(spec types.ExtensionSpec, action *Action) error {
+	if action.Operation == "" {
+		action.Operation = OperationNone
+	}
+	if action.Confidence < 0 || action.Confidence > 1 {
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "extension confidence out of range", nil).
+			WithContext("extension", spec.Name).
+			WithContext("confidence", action.Confidence)
+	}
+
+	if spec.Kind == types.ExtensionClassifier {
+		if action.Label == "" {
+			return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "classifier returned no label", nil).
+				WithContext("extension", spec.Name)
+		}
+		action.Operation = OperationClassify
+		return nil
+	}
+
+	switch action.Operation {
+	case OperationNone, OperationAdjust, OperationOptimize, OperationTransform:
+		return nil
+	default:
+		return types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "unsupported extension operation", nil).
+			WithContext("extension", spec.Name).
+			WithContext("operation", action.Operation)
+	}
+}
@@ -0,0 +1,85 @@
+// system/evolution/extension/goplugin.go
+
+package extension
+
+import (
+	"context"
+	"fmt"
+	"plugin"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Go插件导出符号
+const (
+	// SymbolABIVersion 插件声明的ABI版本, 类型为int变量
+	SymbolABIVersion = "DaoflowABIVersion"
+	// SymbolEvaluate 评估函数, 类型为EvaluateFunc
+	SymbolEvaluate = "Evaluate"
+)
+
+// EvaluateFunc Go插件导出的评估函数签名
+// 输入为JSON编码的EvaluationContext, 输出为JSON编码的Action
+type EvaluateFunc = func(ctx context.Context, input []byte) ([]byte, error)
+
+// goPluginLoader Go插件加载器
+// Go插件与宿主运行在同一进程, 只能隔离超时和崩溃, 无法沙箱化或真正卸载;
+// 运行时按路径缓存插件, 重载新代码需使用新的文件路径
+type goPluginLoader struct{}
+
+// goPluginEvaluator Go插件实例
+type goPluginEvaluator struct {
+	abiVersion int
+	evaluate   EvaluateFunc
+}
+
+// Load 打开Go插件并解析导出符号
+func (goPluginLoader) Load(ctx context.Context, spec types.ExtensionSpec) (Evaluator, error) {
+	p, err := plugin.Open(spec.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	sym, err := p.Lookup(SymbolABIVersion)
+	if err != nil {
+		return nil, err
+	}
+	version, ok := sym.(*int)
+	if !ok {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrValidation, "unexpected symbol type", nil).
+			WithContext("symbol", SymbolABIVersion).
+			WithContext("type", fmt.Sprintf("%T", sym))
+	}
+
+	sym, err = p.Lookup(SymbolEvaluate)
+	if err != nil {
+		return nil, err
+	}
+	evaluate, ok := sym.(EvaluateFunc)
+	if !ok {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrValidation, "unexpected symbol type", nil).
+			WithContext("symbol", SymbolEvaluate).
+			WithContext("type", fmt.Sprintf("%T", sym))
+	}
+
+	return &goPluginEvaluator{
+		abiVersion: *version,
+		evaluate:   evaluate,
+	}, nil
+}
+
+// ABIVersion 插件声明的ABI版本
+func (e *goPluginEvaluator) ABIVersion() int {
+	return e.abiVersion
+}
+
+// Evaluate 调用插件评估函数
+func (e *goPluginEvaluator) Evaluate(ctx context.Context, input []byte) ([]byte, error) {
+	return e.evaluate(ctx, input)
+}
+
+// Close Go插件无法从进程中卸载, 仅解除引用
+func (e *goPluginEvaluator) Close() error {
+	e.evaluate = nil
+	return nil
+}
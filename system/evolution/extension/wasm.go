@@ -0,0 +1,117 @@
+// system/evolution/extension/wasm.go
+
+package extension
+
+import (
+	"context"
+	"encoding/binary"
+	"os"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// WASM模块导出函数
+const (
+	// ExportABIVersion 返回ABI版本, 无参数, 返回i32
+	ExportABIVersion = "daoflow_abi_version"
+	// ExportEvaluate 评估函数, 输入输出为JSON
+	ExportEvaluate = "evaluate"
+)
+
+// SandboxLimits WASM沙箱限制
+type SandboxLimits struct {
+	MemoryPages    uint32 // 最大线性内存页数(64KiB/页)
+	MaxInputBytes  int    // 最大输入字节数
+	MaxOutputBytes int    // 最大输出字节数
+}
+
+// WasmRuntime WASM运行时
+// 由宿主程序接入具体引擎, 实现须满足:
+//   - 模块只能导入运行时提供的ABI函数, 不暴露文件、网络、时钟等宿主能力
+//   - 线性内存不得超过limits.MemoryPages
+//   - ctx取消时中断执行
+type WasmRuntime interface {
+	Instantiate(ctx context.Context, module []byte, limits SandboxLimits) (WasmInstance, error)
+}
+
+// WasmInstance WASM模块实例
+type WasmInstance interface {
+	// Call 调用导出函数, 由运行时负责在线性内存中传递字节
+	Call(ctx context.Context, fn string, input []byte) ([]byte, error)
+	// Close 释放实例
+	Close(ctx context.Context) error
+}
+
+// wasmLoader WASM模块加载器
+type wasmLoader struct {
+	runtime WasmRuntime
+}
+
+// wasmEvaluator WASM扩展实例
+type wasmEvaluator struct {
+	instance   WasmInstance
+	abiVersion int
+	limits     SandboxLimits
+}
+
+// Load 读取并实例化WASM模块
+func (l *wasmLoader) Load(ctx context.Context, spec types.ExtensionSpec) (Evaluator, error) {
+	if l.runtime == nil {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrConfig, "wasm runtime not configured", nil)
+	}
+
+	module, err := os.ReadFile(spec.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	limits := SandboxLimits{
+		MemoryPages:    spec.Sandbox.MemoryPages,
+		MaxInputBytes:  spec.Sandbox.MaxInputBytes,
+		MaxOutputBytes: spec.Sandbox.MaxOutputBytes,
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, spec.Timeout)
+	defer cancel()
+
+	instance, err := l.runtime.Instantiate(ctx, module, limits)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := instance.Call(ctx, ExportABIVersion, nil)
+	if err != nil {
+		instance.Close(context.Background())
+		return nil, err
+	}
+	if len(out) != 4 {
+		instance.Close(context.Background())
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrValidation, ExportABIVersion+" must return an i32", nil).
+			WithContext("bytes", len(out))
+	}
+
+	return &wasmEvaluator{
+		instance:   instance,
+		abiVersion: int(int32(binary.LittleEndian.Uint32(out))),
+		limits:     limits,
+	}, nil
+}
+
+// ABIVersion 模块声明的ABI版本
+func (e *wasmEvaluator) ABIVersion() int {
+	return e.abiVersion
+}
+
+// Evaluate 调用模块评估函数
+func (e *wasmEvaluator) Evaluate(ctx context.Context, input []byte) ([]byte, error) {
+	if len(input) > e.limits.MaxInputBytes {
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrOverflow, "input exceeds sandbox limit", nil).
+			WithContext("bytes", len(input))
+	}
+	return e.instance.Call(ctx, ExportEvaluate, input)
+}
+
+// Close 释放模块实例
+func (e *wasmEvaluator) Close() error {
+	return e.instance.Close(context.Background())
+}
@@ -14,8 +14,13 @@ import (
 	"github.com/Corphon/daoflow/system/common"
 	"github.com/Corphon/daoflow/system/control"
 	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/evolution/audit"
+	"github.com/Corphon/daoflow/system/evolution/causal"
+	"github.com/Corphon/daoflow/system/evolution/extension"
 	"github.com/Corphon/daoflow/system/evolution/mutation"
 	"github.com/Corphon/daoflow/system/evolution/pattern"
+	"github.com/Corphon/daoflow/system/evolution/wuxing"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -52,12 +57,33 @@ type Manager struct {
 	common  *common.Manager
 	control *control.Manager
 
+	// 外部策略扩展
+	extensions *extension.Manager
+
+	// 五行能量调度
+	scheduler *wuxing.Scheduler
+
+	// 因果发现
+	causal *causal.Discoverer
+
+	// 策略变更审计
+	audit *audit.Log
+
+	// 共享学习数据的隐私保护, 组件重建时沿用, 已消耗的预算不随之重置
+	privacy *adaptation.Privacy
+
+	// 运行模式停用突变, 组件创建时同样生效
+	mutationsSuspended bool
+
 	// 上下文控制
 	ctx    context.Context
 	cancel context.CancelFunc
 
 	// 观察者列表
 	observers []types.StateObserver
+
+	// 跨命名空间访问策略
+	authorizer types.NamespaceAuthorizer
 }
 
 // NewManager 创建新的管理器实例
@@ -66,12 +92,39 @@ func NewManager(cfg *types.EvoConfig) (*Manager, error) {
 		cfg = DefaultConfig()
 	}
 
+	var privacy *adaptation.Privacy
+	if cfg.Privacy != nil {
+		var err error
+		privacy, err = adaptation.NewPrivacy(*cfg.Privacy)
+		if err != nil {
+			return nil, types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create learning privacy", err)
+		}
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
+	var schedCfg types.WuXingSchedulerConfig
+	if cfg.Scheduler != nil {
+		schedCfg = *cfg.Scheduler
+	}
+	var causalCfg types.CausalConfig
+	if cfg.Causal != nil {
+		causalCfg = *cfg.Causal
+	}
+	var auditCfg types.AuditConfig
+	if cfg.Audit != nil {
+		auditCfg = *cfg.Audit
+	}
+
 	m := &Manager{
-		config: cfg,
-		ctx:    ctx,
-		cancel: cancel,
+		config:     cfg,
+		extensions: extension.NewManager(),
+		scheduler:  wuxing.NewScheduler(schedCfg),
+		causal:     causal.NewDiscoverer(causalCfg),
+		audit:      audit.NewLog(auditCfg),
+		privacy:    privacy,
+		ctx:        ctx,
+		cancel:     cancel,
 	}
 
 	// 初始化状态
@@ -232,6 +285,18 @@ func DefaultConfig() *types.EvoConfig {
 				PruneInterval: time.Hour,
 				RetentionTime: time.Hour * 24,
 			},
+			Reinforcement: types.ReinforcementConfig{
+				Enabled:  false,
+				Policy:   "tabular",
+				Bins:     5,
+				Hidden:   8,
+				Step:     0.1,
+				Discount: 0.9,
+			},
+			RuleGeneration: types.RuleGenerationConfig{
+				MaxPerCycle: 10,
+				Cooldown:    time.Minute,
+			},
 		},
 		Strategy: &types.StrategyConfig{
 			Base: struct {
@@ -279,6 +344,29 @@ func DefaultConfig() *types.EvoConfig {
 				MinSamples:       10,
 			},
 		},
+		Scheduler: &types.WuXingSchedulerConfig{
+			Enabled:        false,
+			Period:         time.Second * 10,
+			GenerationRate: 0.1,
+			OvercomingRate: 0.05,
+			DefaultCap:     model.MaxWuXingElementEnergy,
+			MaxBias:        4,
+		},
+		Causal: &types.CausalConfig{
+			Enabled:      false,
+			Interval:     time.Minute,
+			Window:       time.Minute * 30,
+			Step:         time.Second * 10,
+			MaxLag:       2,
+			Significance: 0.05,
+		},
+		Bandit: &types.BanditConfig{
+			Policy:      "",
+			UCBConstant: math.Sqrt2,
+		},
+		Audit: &types.AuditConfig{
+			Capacity: 10000,
+		},
 	}
 }
 
@@ -291,11 +379,37 @@ func (m *Manager) Start(ctx context.Context) error {
 		return nil
 	}
 
+	// 调度循环沿用调用方上下文中的协程监管器
+	if sup := supervisor.FromContext(ctx); sup != nil && supervisor.FromContext(m.ctx) != sup {
+		m.ctx = supervisor.NewContext(m.ctx, sup)
+	}
+
+	// 加载配置的外部扩展
+	if err := m.loadExtensions(ctx); err != nil {
+		return err
+	}
+
 	// 初始化并启动所有组件
 	if err := m.initComponents(); err != nil {
 		return err
 	}
 
+	// 启动五行能量调度, 调度对象由系统注入
+	if m.config.Scheduler != nil && m.config.Scheduler.Enabled {
+		m.scheduler.Start(m.ctx)
+	}
+
+	// 启动因果发现, 模式来源由系统注入; 策略执行作为动作节点参与发现
+	m.components.adapStrat.SetCausalGraph(m.causal.Latest)
+	m.components.adapStrat.OnDecision(func(event adaptation.StrategyEvent) {
+		if event.Type == "execution_complete" {
+			m.causal.RecordAction(event.StrategyID, event.Timestamp)
+		}
+	})
+	if m.config.Causal != nil && m.config.Causal.Enabled {
+		m.causal.Start(m.ctx)
+	}
+
 	m.state.status = "running"
 	m.state.startTime = time.Now()
 	return nil
@@ -312,7 +426,7 @@ func (m *Manager) Stop() error {
 
 	m.cancel()
 	m.state.status = "stopped"
-	return nil
+	return m.extensions.Close()
 }
 
 // Status 获取管理器状态
@@ -322,6 +436,47 @@ func (m *Manager) Status() string {
 	return m.state.status
 }
 
+// Health 健康度(0-1), 运行中为1, 未运行为0
+func (m *Manager) Health() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.state.status != "running" {
+		return 0
+	}
+	if health, ok := types.WeightedHealth(m.healthComponents()); ok {
+		return health
+	}
+	return 1
+}
+
+// HealthComponents 演化子系统的健康度分量, 目前由适应学习的质量信号组成
+func (m *Manager) HealthComponents() []types.HealthComponent {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.healthComponents()
+}
+
+// healthComponents 健康度分量, 调用方需持有锁
+func (m *Manager) healthComponents() []types.HealthComponent {
+	if m.components.adapLearn == nil {
+		return nil
+	}
+	return m.components.adapLearn.HealthComponents()
+}
+
+// Drain 等待进行中的学习周期完成, 此后不再开始新的学习周期
+func (m *Manager) Drain(ctx context.Context) error {
+	m.mu.RLock()
+	learning := m.components.adapLearn
+	m.mu.RUnlock()
+
+	if learning == nil {
+		return nil
+	}
+	return learning.Drain(ctx)
+}
+
 // Wait 等待管理器停止
 func (m *Manager) Wait() {
 	<-m.ctx.Done()
@@ -332,13 +487,26 @@ func (m *Manager) GetMetrics() map[string]interface{} {
 	m.mu.RLock()
 	defer m.mu.RUnlock()
 
-	return map[string]interface{}{
+	metrics := map[string]interface{}{
 		"status":       m.state.status,
 		"uptime":       time.Since(m.state.startTime).String(),
 		"evolution":    m.state.evolution,
 		"metrics":      m.state.metrics,
 		"history_size": len(m.state.history),
+		"extensions":   m.extensions.List(),
+		"scheduler":    m.scheduler.GetMetrics(),
+		"causal":       m.causal.GetMetrics(),
+		"audit":        m.audit.GetMetrics(),
+	}
+	if m.components.adapLearn != nil {
+		metrics["rule_generation"] = m.components.adapLearn.GetRuleGenerationStats()
+	}
+	if m.components.patternRec != nil {
+		if families := m.components.patternRec.Families(); families != nil {
+			metrics["pattern_families"] = len(families)
+		}
 	}
+	return metrics
 }
 
 // InjectCore 注入核心引擎
@@ -348,6 +516,98 @@ func (m *Manager) InjectCore(core *core.Engine) {
 	m.core = core
 }
 
+// GetLearning 获取适应性学习组件(启动前为nil)
+func (m *Manager) GetLearning() *adaptation.AdaptiveLearning {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.adapLearn
+}
+
+// GetStrategy 获取适应策略组件(启动前为nil)
+func (m *Manager) GetStrategy() *adaptation.AdaptationStrategy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.adapStrat
+}
+
+// GetRecognizer 获取模式识别器(启动前为nil)
+func (m *Manager) GetRecognizer() *pattern.PatternRecognizer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.patternRec
+}
+
+// GetMatcher 获取演化匹配器(启动前为nil)
+func (m *Manager) GetMatcher() *pattern.EvolutionMatcher {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.evoMatcher
+}
+
+// GetExtensions 获取外部扩展管理器, 可在运行时加载、重载和卸载扩展
+func (m *Manager) GetExtensions() *extension.Manager {
+	return m.extensions
+}
+
+// GetScheduler 获取五行能量调度器
+func (m *Manager) GetScheduler() *wuxing.Scheduler {
+	return m.scheduler
+}
+
+// GetCausalDiscoverer 获取因果发现器
+func (m *Manager) GetCausalDiscoverer() *causal.Discoverer {
+	return m.causal
+}
+
+// SetMutationsSuspended 停用或恢复突变处理和适应策略执行
+func (m *Manager) SetMutationsSuspended(suspended bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.mutationsSuspended = suspended
+	if m.components.mutHandler != nil {
+		m.components.mutHandler.SetSuspended(suspended)
+	}
+	if m.components.adapStrat != nil {
+		m.components.adapStrat.SetSuspended(suspended)
+	}
+}
+
+// SetLearningPrivacy 设置共享学习数据的隐私保护, 新的隐私保护继承被替换者已消耗的预算
+// 启动前设置时在组件创建后生效
+func (m *Manager) SetLearningPrivacy(privacy *adaptation.Privacy) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	privacy.Inherit(m.privacy)
+	m.privacy = privacy
+	if m.components.adapLearn != nil {
+		m.components.adapLearn.SetPrivacy(privacy)
+	}
+}
+
+// GetLearningPrivacy 获取共享学习数据的隐私保护, 未配置时返回nil
+func (m *Manager) GetLearningPrivacy() *adaptation.Privacy {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.privacy
+}
+
+// GetAudit 获取策略变更审计日志
+func (m *Manager) GetAudit() *audit.Log {
+	return m.audit
+}
+
+// SetNamespaceAuthorizer 设置跨命名空间访问策略
+func (m *Manager) SetNamespaceAuthorizer(authorizer types.NamespaceAuthorizer) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.authorizer = authorizer
+	if m.components.evoMatcher != nil {
+		m.components.evoMatcher.SetNamespaceAuthorizer(authorizer)
+	}
+}
+
 // 私有方法
 
 // initComponents 初始化组件
@@ -355,62 +615,89 @@ func (m *Manager) initComponents() error {
 	// 创建模式生成器
 	patternGen, err := pattern.NewPatternGenerator(m.config.Pattern)
 	if err != nil {
-		return fmt.Errorf("failed to create pattern generator: %w", err)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create pattern generator", err)
 	}
 	m.components.patternGen = patternGen
 
 	// 创建模式识别器
 	patternRec, err := pattern.NewPatternRecognizer(m.config.Recognition)
 	if err != nil {
-		return fmt.Errorf("failed to create pattern recognizer: %w", err)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create pattern recognizer", err)
 	}
 	m.components.patternRec = patternRec
 
 	// 创建演化匹配器
 	evoMatcher, err := pattern.NewEvolutionMatcher(patternRec, m.config.Evolution)
 	if err != nil {
-		return fmt.Errorf("failed to create evolution matcher: %w", err)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create evolution matcher", err)
 	}
+	evoMatcher.SetNamespaceAuthorizer(m.authorizer)
 	m.components.evoMatcher = evoMatcher
 
 	// 创建突变检测器
 	mutDetector, err := mutation.NewMutationDetector(m.config.Mutation)
 	if err != nil {
-		return fmt.Errorf("failed to create mutation detector: %w", err)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create mutation detector", err)
 	}
 	m.components.mutDetector = mutDetector
 
 	// 创建突变处理器
 	mutHandler, err := mutation.NewMutationHandler(mutDetector, m.config.Mutation)
 	if err != nil {
-		return fmt.Errorf("failed to create mutation handler: %w", err)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create mutation handler", err)
 	}
+	mutHandler.SetSuspended(m.mutationsSuspended)
 	m.components.mutHandler = mutHandler
 
 	// 创建适应性学习组件
 	adapLearn, err := adaptation.NewAdaptiveLearning(evoMatcher, m.config.Adaptation)
 	if err != nil {
-		return fmt.Errorf("failed to create adaptive learning: %w", err)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create adaptive learning", err)
 	}
 	m.components.adapLearn = adapLearn
+	adapLearn.SetPrivacy(m.privacy)
 
 	// 创建适应策略组件
 	adapStrat, err := adaptation.NewAdaptationStrategy(evoMatcher, mutHandler)
 	if err != nil {
-		return fmt.Errorf("failed to create adaptation strategy: %w", err)
+		return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create adaptation strategy", err)
+	}
+	adapStrat.SetExtensions(m.extensions)
+	if m.config.Bandit != nil && m.config.Bandit.Policy != "" {
+		bandit, err := adaptation.NewBandit(*m.config.Bandit, m.config.Adaptation.Learning.ExplorationRate)
+		if err != nil {
+			return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create strategy bandit", err)
+		}
+		adapStrat.SetBandit(bandit)
 	}
+	adapStrat.SetAudit(m.audit)
+	adapStrat.SetSuspended(m.mutationsSuspended)
 	m.components.adapStrat = adapStrat
 
 	// 创建优化器
 	optimizer := adaptation.NewAdaptiveOptimization(adapStrat, adapLearn)
 	if optimizer == nil {
-		return fmt.Errorf("failed to create optimizer")
+		return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to create optimizer", nil)
 	}
 	m.components.optimizer = optimizer
 
 	return nil
 }
 
+// loadExtensions 加载配置中尚未加载的扩展
+func (m *Manager) loadExtensions(ctx context.Context) error {
+	for _, spec := range m.config.Extensions {
+		if m.extensions.Has(spec.Name) {
+			continue
+		}
+		if err := m.extensions.Load(ctx, spec); err != nil {
+			return types.NewDomainError(types.DomainEvolution, types.ErrInit, "failed to load extension", err).
+				WithContext("extension", spec.Name)
+		}
+	}
+	return nil
+}
+
 // updateEvolutionStatus 更新演化状态
 func (m *Manager) updateEvolutionStatus() {
 	m.mu.Lock()
@@ -689,19 +976,19 @@ func (m *Manager) InjectDependencies(core *core.Engine, common *common.Manager,
 
 	// 注入核心引擎
 	if core == nil {
-		return model.WrapError(nil, model.ErrCodeDependency, "core engine is nil")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeDependency, "core engine is nil", nil)
 	}
 	m.core = core
 
 	// 注入通用管理器
 	if common == nil {
-		return model.WrapError(nil, model.ErrCodeDependency, "common manager is nil")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeDependency, "common manager is nil", nil)
 	}
 	m.common = common
 
 	// 注入控制管理器
 	if control == nil {
-		return model.WrapError(nil, model.ErrCodeDependency, "control manager is nil")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeDependency, "control manager is nil", nil)
 	}
 	m.control = control
 
@@ -820,13 +1107,14 @@ func (m *Manager) validateOptimizationParams(params types.OptimizationParams) er
 
 	// 验证目标
 	if len(params.Goals.Targets) == 0 {
-		return fmt.Errorf("no optimization targets specified")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "no optimization targets specified", nil)
 	}
 
 	// 验证约束
 	for name, constraint := range params.Goals.Constraints {
 		if constraint.Max < constraint.Min {
-			return fmt.Errorf("invalid constraint for %s: max < min", name)
+			return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "invalid constraint: max < min", nil).
+				WithContext("target", name)
 		}
 	}
 
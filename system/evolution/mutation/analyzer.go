@@ -3,11 +3,11 @@
 package mutation
 
 import (
-	"fmt"
 	"math"
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 )
 
@@ -311,7 +311,7 @@ func (ma *MutationAnalyzer) identifyPattern(features []PatternFeature) *Mutation
 	}
 
 	pattern := &MutationPattern{
-		ID:         fmt.Sprintf("pat_%d", time.Now().UnixNano()),
+		ID:         core.NewID("pat_"),
 		Signature:  features,
 		Frequency:  calculatePatternFrequency(features),
 		Conditions: make(map[string]float64),
@@ -416,7 +416,7 @@ func (ma *MutationAnalyzer) predictPattern(pattern *MutationPattern, trend float
 	}
 
 	return &MutationPrediction{
-		ID:          fmt.Sprintf("pred_%d", time.Now().UnixNano()),
+		ID:          core.NewID("pred_"),
 		PatternID:   pattern.ID,
 		Probability: calculatePredictionProbability(pattern, trend),
 		TimeFrame:   ma.config.predictionHorizon,
@@ -936,5 +936,5 @@ func (ma *MutationAnalyzer) updateMetrics() {
 }
 
 func generateAnalysisID() string {
-	return fmt.Sprintf("ana_%d", time.Now().UnixNano())
+	return core.NewID("ana_")
 }
@@ -3,11 +3,11 @@
 package mutation
 
 import (
-	"fmt"
 	"math"
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/common"
 	"github.com/Corphon/daoflow/system/evolution/pattern"
@@ -104,7 +104,7 @@ type BaselineMetric struct {
 // NewMutationDetector 创建新的突变检测器
 func NewMutationDetector(config *types.MutationConfig) (*MutationDetector, error) {
 	if config == nil {
-		return nil, fmt.Errorf("nil mutation config")
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil mutation config", nil)
 	}
 
 	md := &MutationDetector{}
@@ -773,7 +773,7 @@ func calculateEvolutionEnergy(pattern *pattern.RecognizedPattern) float64 {
 	return math.Max(0, math.Min(1, energy/float64(len(pattern.Evolution))))
 }
 func generateMutationID() string {
-	return fmt.Sprintf("mut_%d", time.Now().UnixNano())
+	return core.NewID("mut_")
 }
 
 // 2. 添加 GetCurrentState 方法
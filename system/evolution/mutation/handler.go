@@ -3,7 +3,6 @@
 package mutation
 
 import (
-	"fmt"
 	"math"
 	"sync"
 	"time"
@@ -32,6 +31,7 @@ type MutationHandler struct {
 		active     map[string]*MutationResponse // 活跃响应
 		history    []ResponseEvent              // 响应历史
 		strategies map[string]*ResponseStrategy // 响应策略
+		suspended  bool                         // 运行模式停用突变时为true
 	}
 
 	// 依赖项
@@ -119,6 +119,10 @@ func (mh *MutationHandler) AdjustParameter(target string, params map[string]inte
 	mh.mu.Lock()
 	defer mh.mu.Unlock()
 
+	if mh.state.suspended {
+		return types.ErrMutationsDisabled
+	}
+
 	// 创建调整动作
 	action := &ResponseAction{
 		ID:   generateActionID(),
@@ -149,6 +153,10 @@ func (mh *MutationHandler) Optimize(params map[string]interface{}) error {
 	mh.mu.Lock()
 	defer mh.mu.Unlock()
 
+	if mh.state.suspended {
+		return types.ErrMutationsDisabled
+	}
+
 	// 创建优化动作
 	action := &ResponseAction{
 		ID:         generateActionID(),
@@ -176,6 +184,10 @@ func (mh *MutationHandler) Transform(params map[string]interface{}) error {
 	mh.mu.Lock()
 	defer mh.mu.Unlock()
 
+	if mh.state.suspended {
+		return types.ErrMutationsDisabled
+	}
+
 	// 创建转换动作
 	action := &ResponseAction{
 		ID:         generateActionID(),
@@ -228,7 +240,7 @@ func (mh *MutationHandler) executeSystemAction(action *ResponseAction) error {
 
 // 辅助函数
 func generateActionID() string {
-	return fmt.Sprintf("act_%d", time.Now().UnixNano())
+	return core.NewID("act_")
 }
 
 // GetCurrentState 获取当前系统状态
@@ -326,10 +338,10 @@ func (mh *MutationHandler) calculateSystemStability() float64 {
 // NewMutationHandler 创建新的突变处理器
 func NewMutationHandler(detector *MutationDetector, config *types.MutationConfig) (*MutationHandler, error) {
 	if detector == nil {
-		return nil, fmt.Errorf("nil mutation detector")
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil mutation detector", nil)
 	}
 	if config == nil {
-		return nil, fmt.Errorf("nil mutation config")
+		return nil, types.NewDomainError(types.DomainEvolution, types.ErrInvalid, "nil mutation config", nil)
 	}
 
 	mh := &MutationHandler{
@@ -350,11 +362,29 @@ func NewMutationHandler(detector *MutationDetector, config *types.MutationConfig
 	return mh, nil
 }
 
+// SetSuspended 停用或恢复突变处理, 停用时处理和调整方法返回ErrMutationsDisabled
+func (mh *MutationHandler) SetSuspended(suspended bool) {
+	mh.mu.Lock()
+	defer mh.mu.Unlock()
+	mh.state.suspended = suspended
+}
+
+// IsSuspended 突变处理是否停用
+func (mh *MutationHandler) IsSuspended() bool {
+	mh.mu.RLock()
+	defer mh.mu.RUnlock()
+	return mh.state.suspended
+}
+
 // Handle 处理突变
 func (mh *MutationHandler) Handle() error {
 	mh.mu.Lock()
 	defer mh.mu.Unlock()
 
+	if mh.state.suspended {
+		return types.ErrMutationsDisabled
+	}
+
 	// 获取待处理的突变
 	mutations, err := mh.detector.GetActiveMutations()
 	if err != nil {
@@ -381,7 +411,7 @@ func (mh *MutationHandler) Handle() error {
 // RegisterStrategy 注册响应策略
 func (mh *MutationHandler) RegisterStrategy(strategy *ResponseStrategy) error {
 	if strategy == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "nil strategy")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "nil strategy", nil)
 	}
 
 	mh.mu.Lock()
@@ -504,10 +534,15 @@ func (mh *MutationHandler) HandleMutation(mutation common.Mutation) error {
 	mh.mu.Lock()
 	defer mh.mu.Unlock()
 
+	if mh.state.suspended {
+		return types.ErrMutationsDisabled
+	}
+
 	// 为单个突变选择策略
 	strategy := mh.selectBestStrategy(mutation)
 	if strategy == nil {
-		return fmt.Errorf("no suitable strategy found for mutation %v", mutation.GetID())
+		return types.NewDomainError(types.DomainEvolution, types.ErrNotFound, "no suitable strategy found for mutation", nil).
+			WithContext("mutation_id", mutation.GetID())
 	}
 
 	// 创建响应
@@ -568,7 +603,7 @@ func (mh *MutationHandler) getLastHandledTime() time.Time {
 
 func (mh *MutationHandler) validateStrategy(strategy *ResponseStrategy) error {
 	if strategy.ID == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty strategy ID")
+		return types.NewDomainError(types.DomainEvolution, model.ErrCodeValidation, "empty strategy ID", nil)
 	}
 
 	// 验证条件
@@ -605,7 +640,7 @@ func (mh *MutationHandler) recordResponseEvent(
 	// 如果 details 中包含错误信息，则设置错误字段
 	if errVal, ok := details["error"]; ok {
 		if errStr, ok := errVal.(string); ok {
-			event.Error = fmt.Errorf("response error: %s", errStr)
+			event.Error = types.NewDomainError(types.DomainEvolution, types.ErrRuntime, "response error: "+errStr, nil)
 		}
 	}
 
@@ -696,7 +731,7 @@ func (mh *MutationHandler) createResponse(mutation interface{}, strategy *Respon
 	}
 
 	response := &MutationResponse{
-		ID:         fmt.Sprintf("resp_%d", time.Now().UnixNano()),
+		ID:         core.NewID("resp_"),
 		MutationID: mutID,
 		Strategy:   strategy,
 		Status:     "pending",
@@ -721,7 +756,7 @@ func (mh *MutationHandler) prepareExecutionContext(response *MutationResponse) m
 func (mh *MutationHandler) executeResponseActions(response *MutationResponse, context map[string]interface{}) error {
 	for _, actionTemplate := range response.Strategy.Actions {
 		action := ResponseAction{
-			ID:         fmt.Sprintf("act_%d", time.Now().UnixNano()),
+			ID:         core.NewID("act_"),
 			Type:       actionTemplate.Type,
 			Parameters: mh.resolveActionParameters(actionTemplate.Parameters, context),
 			StartTime:  time.Now(),
@@ -783,13 +818,14 @@ func (mh *MutationHandler) finalizeResponse(response *MutationResponse) {
 // validateCondition 验证响应条件
 func (mh *MutationHandler) validateCondition(condition ResponseCondition) error {
 	if condition.Type == "" {
-		return fmt.Errorf("empty condition type")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "empty condition type", nil)
 	}
 	if condition.Target == "" {
-		return fmt.Errorf("empty condition target")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "empty condition target", nil)
 	}
 	if condition.Weight < 0 || condition.Weight > 1 {
-		return fmt.Errorf("invalid condition weight: %v", condition.Weight)
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "invalid condition weight", nil).
+			WithContext("weight", condition.Weight)
 	}
 	return nil
 }
@@ -797,10 +833,11 @@ func (mh *MutationHandler) validateCondition(condition ResponseCondition) error
 // validateActionTemplate 验证动作模板
 func (mh *MutationHandler) validateActionTemplate(template ActionTemplate) error {
 	if template.Type == "" {
-		return fmt.Errorf("empty action type")
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "empty action type", nil)
 	}
 	if template.Timeout <= 0 {
-		return fmt.Errorf("invalid timeout: %v", template.Timeout)
+		return types.NewDomainError(types.DomainEvolution, types.ErrValidation, "invalid timeout", nil).
+			WithContext("timeout", template.Timeout)
 	}
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/common"
+	"github.com/Corphon/daoflow/system/invariants"
 	"github.com/Corphon/daoflow/system/meta/field"
 	"github.com/Corphon/daoflow/system/types"
 )
@@ -65,28 +66,50 @@ func NewPatternAnalyzer() *PatternAnalyzerImpl {
 // AnalyzePattern 分析单个模式
 func (pa *PatternAnalyzerImpl) AnalyzePattern(p common.SharedPattern) (float64, error) {
 	if p == nil {
-		return 0, model.WrapError(nil, model.ErrCodeValidation, "nil pattern")
+		return 0, types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "nil pattern", nil)
 	}
 
 	// 1. 基础特征分析
-	baseScore := pa.analyzeBaseFeatures(p)
+	baseScore, err := pa.analyzeBaseFeatures(p)
+	if err != nil {
+		return 0, err
+	}
 
 	// 2. 时间维度分析
-	timeScore := pa.analyzeTimeFeatures(p)
+	timeScore, err := pa.analyzeTimeFeatures(p)
+	if err != nil {
+		return 0, err
+	}
 
 	// 3. 稳定性分析
-	stabilityScore := pa.analyzeStability(p)
+	stabilityScore, err := pa.analyzeStability(p)
+	if err != nil {
+		return 0, err
+	}
 
 	// 4. 演化趋势分析
-	evolutionScore := pa.analyzeEvolution(p)
+	evolutionScore, err := pa.analyzeEvolution(p)
+	if err != nil {
+		return 0, err
+	}
 
 	// 5. 整合分析结果
-	finalScore := pa.integrateScores(map[string]float64{
+	scores := map[string]float64{
 		"base":      baseScore,
 		"time":      timeScore,
 		"stability": stabilityScore,
 		"evolution": evolutionScore,
-	})
+	}
+	if err := invariants.ValidateMetrics("pattern.analyzer", scores); err != nil {
+		return 0, err
+	}
+	finalScore, err := pa.integrateScores(scores)
+	if err != nil {
+		return 0, err
+	}
+	if err := invariants.ValidateValue("pattern.analyzer", "score", finalScore); err != nil {
+		return 0, err
+	}
 
 	// 更新分析历史
 	pa.updateAnalysisHistory(p.GetID(), finalScore)
@@ -97,7 +120,7 @@ func (pa *PatternAnalyzerImpl) AnalyzePattern(p common.SharedPattern) (float64,
 // ComparePatterns 比较两个模式
 func (pa *PatternAnalyzerImpl) ComparePatterns(p1, p2 common.SharedPattern) (float64, error) {
 	if p1 == nil || p2 == nil {
-		return 0, model.WrapError(nil, model.ErrCodeValidation, "nil pattern(s)")
+		return 0, types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "nil pattern(s)", nil)
 	}
 
 	// 1. 类型相似度
@@ -113,35 +136,51 @@ func (pa *PatternAnalyzerImpl) ComparePatterns(p1, p2 common.SharedPattern) (flo
 	timeCorrelation := pa.calculateTimeCorrelation(p1.GetTimestamp(), p2.GetTimestamp())
 
 	// 5. 整合比较结果
-	similarity := pa.integrateSimilarity(map[string]float64{
+	similarities := map[string]float64{
 		"type":      typeSimilarity,
 		"strength":  strengthSimilarity,
 		"stability": stabilitySimilarity,
 		"time":      timeCorrelation,
-	})
+	}
+	if err := invariants.ValidateMetrics("pattern.analyzer.compare", similarities); err != nil {
+		return 0, err
+	}
+	similarity, err := pa.integrateSimilarity(similarities)
+	if err != nil {
+		return 0, err
+	}
+	if err := invariants.ValidateValue("pattern.analyzer.compare", "similarity", similarity); err != nil {
+		return 0, err
+	}
 
 	return similarity, nil
 }
 
 // 内部分析方法
 
-func (pa *PatternAnalyzerImpl) analyzeBaseFeatures(p common.SharedPattern) float64 {
+func (pa *PatternAnalyzerImpl) analyzeBaseFeatures(p common.SharedPattern) (float64, error) {
 	// 分析基础特征
 	score := 0.0
 	weights := pa.config.weightFactors
 
 	// 强度评分
-	strengthScore := normalizeValue(p.GetStrength())
+	strengthScore, err := normalizeValue(p.GetStrength())
+	if err != nil {
+		return 0, err
+	}
 	score += strengthScore * weights["strength"]
 
 	// 稳定性评分
-	stabilityScore := normalizeValue(p.GetStability())
+	stabilityScore, err := normalizeValue(p.GetStability())
+	if err != nil {
+		return 0, err
+	}
 	score += stabilityScore * weights["stability"]
 
-	return score
+	return score, nil
 }
 
-func (pa *PatternAnalyzerImpl) analyzeTimeFeatures(p common.SharedPattern) float64 {
+func (pa *PatternAnalyzerImpl) analyzeTimeFeatures(p common.SharedPattern) (float64, error) {
 	// 分析时间特征
 	age := time.Since(p.GetTimestamp())
 
@@ -151,7 +190,7 @@ func (pa *PatternAnalyzerImpl) analyzeTimeFeatures(p common.SharedPattern) float
 	return normalizeValue(timeScore)
 }
 
-func (pa *PatternAnalyzerImpl) analyzeStability(p common.SharedPattern) float64 {
+func (pa *PatternAnalyzerImpl) analyzeStability(p common.SharedPattern) (float64, error) {
 	stability := p.GetStability()
 
 	// 考虑历史稳定性
@@ -163,11 +202,11 @@ func (pa *PatternAnalyzerImpl) analyzeStability(p common.SharedPattern) float64
 	return normalizeValue(stability)
 }
 
-func (pa *PatternAnalyzerImpl) analyzeEvolution(p common.SharedPattern) float64 {
+func (pa *PatternAnalyzerImpl) analyzeEvolution(p common.SharedPattern) (float64, error) {
 	// 分析演化趋势
 	history := pa.state.patterns[p.GetID()]
 	if len(history) < 2 {
-		return 0.5 // 默认中性评分
+		return 0.5, nil // 默认中性评分
 	}
 
 	// 计算趋势
@@ -179,7 +218,7 @@ func (pa *PatternAnalyzerImpl) analyzeEvolution(p common.SharedPattern) float64
 	return normalizeValue((trend + (1 - volatility)) / 2)
 }
 
-func (pa *PatternAnalyzerImpl) integrateScores(scores map[string]float64) float64 {
+func (pa *PatternAnalyzerImpl) integrateScores(scores map[string]float64) (float64, error) {
 	totalScore := 0.0
 	totalWeight := 0.0
 
@@ -190,7 +229,7 @@ func (pa *PatternAnalyzerImpl) integrateScores(scores map[string]float64) float6
 	}
 
 	if totalWeight == 0 {
-		return 0
+		return 0, nil
 	}
 
 	return normalizeValue(totalScore / totalWeight)
@@ -234,7 +273,7 @@ func (pa *PatternAnalyzerImpl) calculateTimeCorrelation(t1, t2 time.Time) float6
 	return math.Exp(-timeDiff / 24.0) // 24小时特征时间
 }
 
-func (pa *PatternAnalyzerImpl) integrateSimilarity(similarities map[string]float64) float64 {
+func (pa *PatternAnalyzerImpl) integrateSimilarity(similarities map[string]float64) (float64, error) {
 	return pa.integrateScores(similarities) // 复用分数整合逻辑
 }
 
@@ -335,6 +374,7 @@ func calculateVolatility(history []float64) float64 {
 	return math.Min(1.0, variance)
 }
 
-func normalizeValue(value float64) float64 {
-	return math.Max(0.0, math.Min(1.0, value))
+// normalizeValue 评分标准化到[0,1], 严格不变量检查下越界时返回错误
+func normalizeValue(value float64) (float64, error) {
+	return invariants.ClampUnit("pattern.analyzer.score", value)
 }
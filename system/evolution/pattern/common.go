@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/invariants"
 	"github.com/Corphon/daoflow/system/meta/emergence"
 )
 
@@ -19,9 +20,9 @@ const (
 
 // -------------------------------------------------------------------
 // calculatePatternComplexity 计算模式复杂度
-func calculatePatternComplexity(pattern *RecognizedPattern) float64 {
+func calculatePatternComplexity(pattern *RecognizedPattern) (float64, error) {
 	if pattern == nil {
-		return 0
+		return 0, nil
 	}
 
 	complexity := 0.0
@@ -44,9 +45,9 @@ func calculatePatternComplexity(pattern *RecognizedPattern) float64 {
 }
 
 // calculatePatternCoherence 计算模式相干性
-func calculatePatternCoherence(pattern *RecognizedPattern) float64 {
+func calculatePatternCoherence(pattern *RecognizedPattern) (float64, error) {
 	if pattern == nil {
-		return 0
+		return 0, nil
 	}
 
 	// 1. 时间相干性
@@ -56,7 +57,10 @@ func calculatePatternCoherence(pattern *RecognizedPattern) float64 {
 	spatialCoherence := calculateSpatialCoherence(pattern.Signature)
 
 	// 3. 量子相干性
-	quantumCoherence := calculateQuantumCoherence(pattern)
+	quantumCoherence, err := calculateQuantumCoherence(pattern)
+	if err != nil {
+		return 0, err
+	}
 
 	// 综合相干性计算
 	coherence := (temporalCoherence*0.4 +
@@ -185,11 +189,15 @@ func calculateHierarchyBalance(levels map[string]int) float64 {
 }
 
 // extractDynamicFeatures 提取动态特征
-func extractDynamicFeatures(pattern emergence.EmergentPattern) map[string]float64 {
+func extractDynamicFeatures(pattern emergence.EmergentPattern) (map[string]float64, error) {
 	features := make(map[string]float64)
 
 	// 1. 能量特征
-	features["energy"] = calculateEnergyFeatures(pattern)
+	energy, err := calculateEnergyFeatures(pattern)
+	if err != nil {
+		return nil, err
+	}
+	features["energy"] = energy
 
 	// 2. 演化特征
 	evolutionFeatures := calculateEvolutionFeatures(pattern)
@@ -203,7 +211,7 @@ func extractDynamicFeatures(pattern emergence.EmergentPattern) map[string]float6
 	// 4. 适应性特征
 	features["adaptability"] = calculateAdaptabilityFeatures(pattern)
 
-	return features
+	return features, nil
 }
 
 // calculateEvolutionFeatures 计算演化特征
@@ -215,6 +223,49 @@ func calculateEvolutionFeatures(pattern emergence.EmergentPattern) map[string]fl
 	features["directionality"] = calculateEvolutionDirectionality(pattern)
 	// 演化可预测性
 	features["predictability"] = calculateEvolutionPredictability(pattern)
+	// 演化周期性
+	for k, v := range calculateSpectralFeatures(pattern) {
+		features[k] = v
+	}
+	return features
+}
+
+// calculateSpectralFeatures 计算演化强度序列的谱特征, 用于共振类型判断
+// frequency为主频相对奈奎斯特频率的比例, periodicity为主频功率占比,
+// harmonicity为谱峰中与主频成谐波关系的功率占比
+func calculateSpectralFeatures(pattern emergence.EmergentPattern) map[string]float64 {
+	features := map[string]float64{
+		"frequency":   0,
+		"periodicity": 0,
+		"harmonicity": 0,
+	}
+
+	times := make([]time.Time, 0, len(pattern.Evolution))
+	values := make([]float64, 0, len(pattern.Evolution))
+	for _, state := range pattern.Evolution {
+		times = append(times, state.Timestamp)
+		values = append(values, state.Strength)
+	}
+	spectrum := model.AnalyzeSpectrum(times, values)
+	dominant, ok := spectrum.Dominant()
+	if !ok {
+		return features
+	}
+
+	nyquist := spectrum.Frequencies[len(spectrum.Frequencies)-1]
+	features["frequency"] = math.Min(1, dominant.Frequency/nyquist)
+	features["periodicity"] = dominant.Power
+
+	peakPower, harmonicPower := 0.0, 0.0
+	for _, p := range spectrum.Peaks {
+		peakPower += p.Power
+		if p.Harmonic > 0 {
+			harmonicPower += p.Power
+		}
+	}
+	if peakPower > 0 {
+		features["harmonicity"] = harmonicPower / peakPower
+	}
 	return features
 }
 
@@ -225,11 +276,9 @@ func calculateEvolutionRate(pattern emergence.EmergentPattern) float64 {
 	}
 	// 计算状态变化率
 	changes := 0.0
-	for i := 1; i < len(pattern.Evolution); i++ {
-		diff := calculateStateDifference(
-			convertPatternState(pattern.Evolution[i-1]),
-			convertPatternState(pattern.Evolution[i]))
-		changes += diff
+	flat := FlattenEvolution(pattern.Evolution, nil)
+	for i := 1; i < len(flat); i++ {
+		changes += flat[i-1].Difference(&flat[i])
 	}
 	// 归一化速率
 	timeSpan := pattern.Evolution[len(pattern.Evolution)-1].Timestamp.Sub(
@@ -248,14 +297,11 @@ func calculateEvolutionDirectionality(pattern emergence.EmergentPattern) float64
 	// 计算方向一致性
 	consistency := 0.0
 	prevDirection := 0.0
-	for i := 1; i < len(pattern.Evolution)-1; i++ {
+	flat := FlattenEvolution(pattern.Evolution, nil)
+	for i := 1; i < len(flat)-1; i++ {
 		// 计算相邻状态的变化方向
-		diff1 := calculateStateDifference(
-			convertPatternState(pattern.Evolution[i-1]),
-			convertPatternState(pattern.Evolution[i]))
-		diff2 := calculateStateDifference(
-			convertPatternState(pattern.Evolution[i]),
-			convertPatternState(pattern.Evolution[i+1]))
+		diff1 := flat[i-1].Difference(&flat[i])
+		diff2 := flat[i].Difference(&flat[i+1])
 		// 方向相似度
 		direction := diff2 - diff1
 		if i > 1 {
@@ -295,9 +341,12 @@ func calculateEvolutionPredictability(pattern emergence.EmergentPattern) float64
 }
 
 // determinePatternType 确定模式类型
-func determinePatternType(pattern emergence.EmergentPattern) string {
+func determinePatternType(pattern emergence.EmergentPattern) (string, error) {
 	// 1. 分析模式特征
-	features := extractFeatureVector(&pattern)
+	features, err := extractFeatureVector(&pattern)
+	if err != nil {
+		return "", err
+	}
 
 	// 2. 计算类型概率
 	probabilities := calculateTypeProbs(features)
@@ -305,11 +354,11 @@ func determinePatternType(pattern emergence.EmergentPattern) string {
 	// 3. 选择最可能的类型
 	patternType := selectMostProbableType(probabilities)
 
-	return patternType
+	return patternType, nil
 }
 
 // extractFeatureVector 提取特征向量
-func extractFeatureVector(pattern *emergence.EmergentPattern) map[string]float64 {
+func extractFeatureVector(pattern *emergence.EmergentPattern) (map[string]float64, error) {
 	features := make(map[string]float64)
 
 	// 基本特征
@@ -321,12 +370,20 @@ func extractFeatureVector(pattern *emergence.EmergentPattern) map[string]float64
 	features["coherence"] = pattern.GetStructureCoherence()
 
 	// 动态特征
-	dynamic := extractDynamicFeatures(*pattern)
+	dynamic, err := extractDynamicFeatures(*pattern)
+	if err != nil {
+		return nil, err
+	}
 	for k, v := range dynamic {
 		features[k] = v
 	}
 
-	return features
+	// 信息论特征
+	for k, v := range pattern.GetEntropyMetrics().Features() {
+		features[k] = v
+	}
+
+	return features, nil
 }
 
 // calculateInitialStability 计算初始稳定性
@@ -571,12 +628,14 @@ func calculateDynamicComplexity(dynamics map[string]float64) float64 {
 	return complexity
 }
 
-func normalizeComplexity(value float64) float64 {
-	return math.Max(0, math.Min(1, value))
+// normalizeComplexity 复杂度标准化到[0,1], 严格不变量检查下越界时返回错误
+func normalizeComplexity(value float64) (float64, error) {
+	return invariants.ClampUnit("pattern.complexity", value)
 }
 
-func normalizeCoherence(value float64) float64 {
-	return math.Max(0, math.Min(1, value))
+// normalizeCoherence 相干性标准化到[0,1], 严格不变量检查下越界时返回错误
+func normalizeCoherence(value float64) (float64, error) {
+	return invariants.ClampUnit("pattern.coherence", value)
 }
 
 // 时间相关计算
@@ -590,9 +649,10 @@ func calculateTemporalCoherence(evolution []PatternState) float64 {
 	decayFactor := 0.95 // 时间衰减因子
 
 	// 计算状态转换的连续性
-	for i := 1; i < len(evolution); i++ {
-		weight := math.Pow(decayFactor, float64(len(evolution)-i))
-		stateDiff := calculateStateDifference(evolution[i-1], evolution[i])
+	flat := FlattenStates(evolution, nil)
+	for i := 1; i < len(flat); i++ {
+		weight := math.Pow(decayFactor, float64(len(flat)-i))
+		stateDiff := flat[i-1].Difference(&flat[i])
 		coherence += (1.0 - stateDiff) * weight
 		totalWeight += weight
 	}
@@ -602,38 +662,8 @@ func calculateTemporalCoherence(evolution []PatternState) float64 {
 
 // calculateStateDifference 计算两个状态之间的差异
 func calculateStateDifference(state1, state2 PatternState) float64 {
-	// 1. 强度差异
-	strengthDiff := math.Abs(state1.Pattern.Strength - state2.Pattern.Strength)
-
-	// 2. 相位差异
-	phase1 := state1.Pattern.Properties["phase"]
-	phase2 := state2.Pattern.Properties["phase"]
-	phaseDiff := normalizePhase(phase1 - phase2)
-
-	// 3. 能量差异
-	energy1 := state1.Pattern.Properties["energy"]
-	energy2 := state2.Pattern.Properties["energy"]
-	energyDiff := math.Abs(energy1 - energy2)
-
-	// 4. 相干性差异
-	coherence1 := state1.Pattern.Properties["coherence"]
-	coherence2 := state2.Pattern.Properties["coherence"]
-	coherenceDiff := math.Abs(coherence1 - coherence2)
-
-	// 加权平均差异
-	weights := map[string]float64{
-		"strength":  0.3,
-		"phase":     0.3,
-		"energy":    0.2,
-		"coherence": 0.2,
-	}
-
-	totalDiff := strengthDiff*weights["strength"] +
-		phaseDiff*weights["phase"] +
-		energyDiff*weights["energy"] +
-		coherenceDiff*weights["coherence"]
-
-	return math.Min(1.0, totalDiff)
+	flat1, flat2 := FlattenState(state1), FlattenState(state2)
+	return flat1.Difference(&flat2)
 }
 
 // normalizePhase 将相位标准化到[-π,π]区间
@@ -740,17 +770,23 @@ func calculateFieldCoherence(dynamics map[string]float64) float64 {
 }
 
 // 量子相关计算
-func calculateQuantumCoherence(pattern *RecognizedPattern) float64 {
+func calculateQuantumCoherence(pattern *RecognizedPattern) (float64, error) {
 	// 1. 计算量子态纯度
-	purity := calculateQuantumPurity(pattern)
+	purity, err := calculateQuantumPurity(pattern)
+	if err != nil {
+		return 0, err
+	}
 
 	// 2. 计算退相干度
-	decoherence := calculateDecoherenceFactor(pattern)
+	decoherence, err := calculateDecoherenceFactor(pattern)
+	if err != nil {
+		return 0, err
+	}
 
 	// 3. 计算量子纠缠度
 	entanglement := calculateEntanglementDegree(pattern)
 
-	return (purity*0.4 + (1-decoherence)*0.3 + entanglement*0.3)
+	return (purity*0.4 + (1-decoherence)*0.3 + entanglement*0.3), nil
 }
 
 // calculateEntanglementDegree 计算纠缠度
@@ -1302,14 +1338,11 @@ func calculateTemporalSymmetry(pattern emergence.EmergentPattern) float64 {
 
 	// 计算状态序列的对称性
 	symmetry := 0.0
-	n := len(pattern.Evolution)
+	flat := FlattenEvolution(pattern.Evolution, nil)
+	n := len(flat)
 	for i := 0; i < n/2; i++ {
 		// 对比前后状态的相似度
-		diff := calculateStateDifference(
-			convertPatternState(pattern.Evolution[i]),
-			convertPatternState(pattern.Evolution[n-1-i]),
-		)
-		symmetry += 1.0 - diff
+		symmetry += 1.0 - flat[i].Difference(&flat[n-1-i])
 	}
 
 	return symmetry / float64(n/2)
@@ -1353,7 +1386,7 @@ func calculateFieldSymmetry(pattern emergence.EmergentPattern) float64 {
 }
 
 // 能量特征计算
-func calculateEnergyFeatures(pattern emergence.EmergentPattern) float64 {
+func calculateEnergyFeatures(pattern emergence.EmergentPattern) (float64, error) {
 	// 基础能量
 	baseEnergy := pattern.Energy
 
@@ -1629,11 +1662,9 @@ func calculateResponseSensitivity(pattern emergence.EmergentPattern) float64 {
 
 	// 计算状态变化的敏感度
 	sensitivity := 0.0
-	for i := 1; i < len(pattern.Evolution); i++ {
-		diff := calculateStateDifference(
-			convertPatternState(pattern.Evolution[i-1]),
-			convertPatternState(pattern.Evolution[i]))
-		sensitivity += diff
+	flat := FlattenEvolution(pattern.Evolution, nil)
+	for i := 1; i < len(flat); i++ {
+		sensitivity += flat[i-1].Difference(&flat[i])
 	}
 
 	return math.Min(1.0, sensitivity/float64(len(pattern.Evolution)-1))
@@ -1690,11 +1721,9 @@ func calculateResponseSpeed(pattern emergence.EmergentPattern) float64 {
 
 	// 计算状态变化率
 	changes := 0.0
-	for i := 1; i < len(pattern.Evolution); i++ {
-		diff := calculateStateDifference(
-			convertPatternState(pattern.Evolution[i-1]),
-			convertPatternState(pattern.Evolution[i]))
-		changes += diff
+	flat := FlattenEvolution(pattern.Evolution, nil)
+	for i := 1; i < len(flat); i++ {
+		changes += flat[i-1].Difference(&flat[i])
 	}
 
 	// 归一化速率
@@ -1714,11 +1743,9 @@ func calculateLearningRate(pattern emergence.EmergentPattern) float64 {
 
 	// 计算状态变化率
 	changes := 0.0
-	for i := 1; i < len(pattern.Evolution); i++ {
-		diff := calculateStateDifference(
-			convertPatternState(pattern.Evolution[i-1]),
-			convertPatternState(pattern.Evolution[i]))
-		changes += diff
+	flat := FlattenEvolution(pattern.Evolution, nil)
+	for i := 1; i < len(flat); i++ {
+		changes += flat[i-1].Difference(&flat[i])
 	}
 
 	// 归一化速率
@@ -1731,60 +1758,28 @@ func calculateLearningRate(pattern emergence.EmergentPattern) float64 {
 }
 
 // 量子态计算
-func calculateQuantumPurity(pattern *RecognizedPattern) float64 {
+func calculateQuantumPurity(pattern *RecognizedPattern) (float64, error) {
 	if pattern == nil || len(pattern.Evolution) == 0 {
-		return 0
+		return 0, nil
 	}
 
 	// 获取量子态信息
 	state := pattern.Evolution[len(pattern.Evolution)-1]
 
 	// 计算密度矩阵
-	densityMatrix := calculateDensityMatrix(state)
-
-	// 计算迹
-	purity := calculateMatrixTrace(densityMatrix)
-
-	return normalizeQuantumValue(purity)
-}
-
-// calculateDensityMatrix 计算量子态的密度矩阵
-func calculateDensityMatrix(state PatternState) [][]complex128 {
-	// 基于Properties中的能量和相位构造密度矩阵
-	energy := state.Properties["energy"]
-	phase := state.Properties["phase"]
-
-	densityMatrix := make([][]complex128, 2)
-	for i := range densityMatrix {
-		densityMatrix[i] = make([]complex128, 2)
+	densityMatrix, err := calculateDensityMatrix(state, GetDensityConfig())
+	if err != nil {
+		return 0, nil
 	}
 
-	// 构造简化的密度矩阵
-	theta := phase * math.Pi // 将相位转换为角度
-	p := energy              // 用能量表示概率
-
-	// 填充密度矩阵元素
-	densityMatrix[0][0] = complex(p, 0)
-	densityMatrix[0][1] = complex(math.Sqrt(p*(1-p))*math.Cos(theta), math.Sqrt(p*(1-p))*math.Sin(theta))
-	densityMatrix[1][0] = complex(math.Sqrt(p*(1-p))*math.Cos(theta), -math.Sqrt(p*(1-p))*math.Sin(theta))
-	densityMatrix[1][1] = complex(1-p, 0)
-
-	return densityMatrix
-}
-
-// calculateMatrixTrace 计算矩阵的迹
-func calculateMatrixTrace(matrix [][]complex128) float64 {
-	trace := 0.0
-	for i := range matrix {
-		trace += real(matrix[i][i])
-	}
-	return trace
+	// 计算纯度 Tr(ρ²)
+	return normalizeQuantumValue(densityMatrix.Purity())
 }
 
 // 退相干计算
-func calculateDecoherenceFactor(pattern *RecognizedPattern) float64 {
+func calculateDecoherenceFactor(pattern *RecognizedPattern) (float64, error) {
 	if len(pattern.Evolution) < 2 {
-		return 0
+		return 0, nil
 	}
 
 	decoherence := 0.0
@@ -1836,13 +1831,13 @@ func calculateQuantumStateDifference(state1, state2 PatternState) float64 {
 	return math.Min(1.0, totalDiff)
 }
 
-// 标准化函数
-func normalizeQuantumValue(value float64) float64 {
-	return math.Max(0, math.Min(1, value))
+// 标准化函数, 严格不变量检查下越界时返回错误
+func normalizeQuantumValue(value float64) (float64, error) {
+	return invariants.ClampUnit("pattern.quantum", value)
 }
 
-func normalizeEnergy(value float64) float64 {
-	return math.Max(0, math.Min(1, value/maxEnergyLevel))
+func normalizeEnergy(value float64) (float64, error) {
+	return invariants.ClampUnit("pattern.energy", value/maxEnergyLevel)
 }
 
 // calculateStructuralSymmetry 计算结构对称性
@@ -1947,6 +1942,11 @@ func extractEvolutionFeatures(pattern *RecognizedPattern) map[string]float64 {
 	features["activation_level"] = pattern.GetActivationLevel()
 	features["evolution_stage"] = float64(len(pattern.Evolution))
 
+	// 信息论特征
+	for k, v := range pattern.GetEntropyMetrics().Features() {
+		features[k] = v
+	}
+
 	// 标准化所有特征值到[0,1]区间
 	for k, v := range features {
 		features[k] = math.Max(0, math.Min(1, v))
@@ -1956,7 +1956,8 @@ func extractEvolutionFeatures(pattern *RecognizedPattern) map[string]float64 {
 }
 
 // calculateEnvironmentSimilarity 计算环境相似度
-func calculateEnvironmentSimilarity(envBase, env1, env2 map[string]float64) float64 {
+// weights为空时各因素等权, 否则按权重加权且忽略未列出的因素
+func calculateEnvironmentSimilarity(envBase, env1, env2, weights map[string]float64) float64 {
 	if len(env1) == 0 || len(env2) == 0 {
 		return 0
 	}
@@ -1966,14 +1967,21 @@ func calculateEnvironmentSimilarity(envBase, env1, env2 map[string]float64) floa
 
 	// 比较环境因素
 	for key, baseVal := range envBase {
+		weight := 1.0
+		if len(weights) > 0 {
+			weight = weights[key]
+		}
+		if weight <= 0 {
+			continue
+		}
 		if val1, ok1 := env1[key]; ok1 {
 			if val2, ok2 := env2[key]; ok2 {
 				// 相对于基准环境的变化率
 				delta1 := math.Abs(val1 - baseVal)
 				delta2 := math.Abs(val2 - baseVal)
 				// 变化率的相似度
-				similarity += 1.0 - math.Abs(delta1-delta2)/(delta1+delta2+1e-6)
-				count++
+				similarity += (1.0 - math.Abs(delta1-delta2)/(delta1+delta2+1e-6)) * weight
+				count += weight
 			}
 		}
 	}
@@ -2014,9 +2022,7 @@ func calculateEvolutionStageSimilarity(source, target *RecognizedPattern) float6
 	// 2. 最新状态相似度
 	sourceLatest := source.Evolution[len(source.Evolution)-1]
 	targetLatest := target.Evolution[len(target.Evolution)-1]
-	latestSim := 1.0 - calculateStateDifference(
-		convertPatternState(convertLocalPatternState(sourceLatest)),
-		convertPatternState(convertLocalPatternState(targetLatest)))
+	latestSim := 1.0 - calculateStateDifference(sourceLatest, targetLatest)
 
 	// 3. 演化趋势相似度
 	sourceTrend := calculateEvolutionDirectionality(convertToEmergentPattern(source))
@@ -2159,31 +2165,13 @@ func calculateEnergyVariance(pattern emergence.EmergentPattern) float64 {
 	}
 	variance /= float64(len(energies))
 
-	// 归一化方差到[0,1]区间
+	// 归一化方差到[0,1]区间, 平均能量非正时无法按均值归一化
+	if meanEnergy <= 0 {
+		return 0
+	}
 	return math.Min(1.0, variance/meanEnergy)
 }
 
-// calculateSignatureSimilarity 计算签名相似度
-func calculateSignatureSimilarity(sig1, sig2 PatternSignature) float64 {
-	// 1. 组件相似度
-	componentSimilarity := calculateComponentsSimilarity(sig1.Components, sig2.Components)
-
-	// 2. 结构相似度
-	structureSimilarity := calculateStructureMapSimilarity(sig1.Structure, sig2.Structure)
-
-	// 3. 动态特征相似度
-	dynamicSimilarity := calculatePropertySimilarity(sig1.Dynamics, sig2.Dynamics)
-
-	// 4. 上下文相似度
-	contextSimilarity := calculateContextMapSimilarity(sig1.Context, sig2.Context)
-
-	// 加权平均
-	return (componentSimilarity*0.4 +
-		structureSimilarity*0.3 +
-		dynamicSimilarity*0.2 +
-		contextSimilarity*0.1)
-}
-
 // calculateComponentsSimilarity 计算组件集合相似度
 func calculateComponentsSimilarity(comps1, comps2 []SignatureComponent) float64 {
 	if len(comps1) == 0 || len(comps2) == 0 {
@@ -2228,14 +2216,7 @@ func calculateComponentSimilarity(c1, c2 SignatureComponent) float64 {
 	propertySimilarity := calculatePropertySimilarity(c1.Properties, c2.Properties)
 
 	// 4. 角色相似度
-	roleSimilarity := 0.0
-	if c1.Role == c2.Role {
-		roleSimilarity = 1.0
-	} else if c1.Type == "element" && c2.Type == "element" {
-		// 元素类型考虑五行关系
-		relation := model.GetWuXingRelation(c1.Role, c2.Role)
-		roleSimilarity = relation.Factor
-	}
+	roleSimilarity := componentRoleSimilarity(c1, c2)
 
 	// 5. 连接相似度
 	connectionSimilarity := calculateConnectionSimilarity(c1.Connections, c2.Connections)
@@ -2248,6 +2229,18 @@ func calculateComponentSimilarity(c1, c2 SignatureComponent) float64 {
 		connectionSimilarity*0.1)
 }
 
+// componentRoleSimilarity 计算组件角色相似度, 元素类型考虑五行关系
+func componentRoleSimilarity(c1, c2 SignatureComponent) float64 {
+	if c1.Role == c2.Role {
+		return 1.0
+	}
+	if c1.Type == "element" && c2.Type == "element" {
+		relation := model.GetWuXingRelation(c1.Role, c2.Role)
+		return relation.Factor
+	}
+	return 0
+}
+
 // calculateConnectionSimilarity 计算连接相似度
 func calculateConnectionSimilarity(conns1, conns2 []ComponentConnection) float64 {
 	if len(conns1) == 0 && len(conns2) == 0 {
@@ -0,0 +1,85 @@
+// system/evolution/pattern/density.go
+
+package pattern
+
+import (
+	"math"
+	"sync"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// densitySettings 密度矩阵构造配置, 对包内所有量子分析生效
+var densitySettings = struct {
+	mu     sync.RWMutex
+	config types.DensityConfig
+}{config: DefaultDensityConfig()}
+
+// DefaultDensityConfig 默认密度矩阵配置: 以能量为布居、相位为相对相位的二能级系统
+func DefaultDensityConfig() types.DensityConfig {
+	return types.DensityConfig{
+		Levels:        []string{"energy"},
+		PhaseProperty: "phase",
+	}
+}
+
+// SetDensityConfig 设置模式量子分析的密度矩阵构造配置
+func SetDensityConfig(config types.DensityConfig) error {
+	if len(config.Levels) == 0 {
+		return types.NewSystemError(types.ErrInvalid, "density config requires at least one level", nil)
+	}
+	seen := make(map[string]bool, len(config.Levels))
+	for _, level := range config.Levels {
+		if level == "" || seen[level] {
+			return types.NewSystemError(types.ErrInvalid, "density levels must be unique non-empty properties", nil).
+				WithContext("level", level)
+		}
+		seen[level] = true
+	}
+
+	densitySettings.mu.Lock()
+	defer densitySettings.mu.Unlock()
+	config.Levels = append([]string(nil), config.Levels...)
+	densitySettings.config = config
+	return nil
+}
+
+// GetDensityConfig 获取密度矩阵构造配置
+func GetDensityConfig() types.DensityConfig {
+	densitySettings.mu.RLock()
+	defer densitySettings.mu.RUnlock()
+	config := densitySettings.config
+	config.Levels = append([]string(nil), config.Levels...)
+	return config
+}
+
+// calculateDensityMatrix 按配置由模式状态属性构造n能级密度矩阵
+func calculateDensityMatrix(state PatternState, config types.DensityConfig) (core.DensityMatrix, error) {
+	props := state.Properties
+	theta := props[config.PhaseProperty] * math.Pi
+
+	var populations []float64
+	if len(config.Levels) == 1 {
+		p := math.Max(0, math.Min(1, props[config.Levels[0]]))
+		populations = []float64{p, 1 - p}
+	} else {
+		populations = make([]float64, len(config.Levels))
+		for k, level := range config.Levels {
+			populations[k] = math.Abs(props[level])
+		}
+	}
+
+	// 相邻能级的相对相位为θ
+	phases := make([]float64, len(populations))
+	for k := range phases {
+		phases[k] = -float64(k) * theta
+	}
+
+	coherence := 1.0
+	if value, ok := props[config.CoherenceProperty]; ok && config.CoherenceProperty != "" {
+		coherence = math.Max(0, math.Min(1, value))
+	}
+
+	return core.MixedDensityMatrix(populations, phases, coherence)
+}
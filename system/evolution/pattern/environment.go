@@ -0,0 +1,288 @@
+// system/evolution/pattern/environment.go
+
+package pattern
+
+import (
+	"context"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 环境来源默认参数
+const (
+	defaultProviderWeight  = 0.2 // 与内置环境因素的权重同一量纲
+	defaultProviderTimeout = time.Second
+)
+
+// builtinFactorWeights 内置环境因素的权重
+var builtinFactorWeights = map[string]float64{
+	"time_of_day":    0.1,
+	"activity_level": 0.3,
+	"energy_level":   0.3,
+	"stability":      0.2,
+	"change_rate":    0.1,
+}
+
+// EnvironmentProvider 外部环境上下文来源, 如负载指标、天气、市场数据
+// 其因素以"来源名.因素名"写入匹配上下文的环境因素, 参与上下文影响度和环境相似度计算
+type EnvironmentProvider interface {
+	// Name 来源名称, 作为因素名前缀
+	Name() string
+	// Factors 读取当前的环境因素, 取值宜归一化到[0,1]; 应在ctx取消时尽快返回
+	Factors(ctx context.Context) (map[string]float64, error)
+}
+
+// EnvironmentProviderFunc 以函数实现的环境来源
+type EnvironmentProviderFunc struct {
+	ProviderName string
+	Read         func(ctx context.Context) (map[string]float64, error)
+}
+
+// Name 来源名称
+func (f EnvironmentProviderFunc) Name() string {
+	return f.ProviderName
+}
+
+// Factors 读取当前的环境因素
+func (f EnvironmentProviderFunc) Factors(ctx context.Context) (map[string]float64, error) {
+	return f.Read(ctx)
+}
+
+// ProviderOptions 环境来源的注册选项, 零值字段使用默认值
+type ProviderOptions struct {
+	Weight   float64       // 来源中每个因素的权重, 默认0.2
+	Interval time.Duration // 两次读取的最小间隔, 默认每次匹配都读取
+	Timeout  time.Duration // 单次读取超时, 默认1秒
+	MaxAge   time.Duration // 取值的有效期, 读取失败时沿用旧值且权重随时间线性衰减, 过期后移除; 0表示不过期
+}
+
+// ProviderStatus 环境来源状态
+type ProviderStatus struct {
+	Name      string             `json:"name"`
+	Weight    float64            `json:"weight"`    // 按新鲜度衰减后的权重
+	Freshness float64            `json:"freshness"` // 1为刚读取, 0为已过期
+	Factors   map[string]float64 `json:"factors"`   // 最近一次成功读取的因素
+	Updated   time.Time          `json:"updated"`   // 最近一次成功读取时间
+	Stale     bool               `json:"stale"`
+	Failures  int64              `json:"failures"`
+	LastError string             `json:"last_error,omitempty"`
+}
+
+// providerEntry 已注册的环境来源
+type providerEntry struct {
+	provider EnvironmentProvider
+	options  ProviderOptions
+	values   map[string]float64
+	updated  time.Time // 最近一次成功读取
+	polled   time.Time // 最近一次读取
+	failures int64
+	lastErr  error
+}
+
+// freshness 取值的新鲜度
+func (e *providerEntry) freshness(now time.Time) float64 {
+	if e.updated.IsZero() {
+		return 0
+	}
+	if e.options.MaxAge <= 0 {
+		return 1
+	}
+	age := now.Sub(e.updated)
+	return math.Max(0, 1-float64(age)/float64(e.options.MaxAge))
+}
+
+// environmentProviders 环境来源注册表, 独立于匹配器的锁, 读取外部来源时不阻塞匹配器
+type environmentProviders struct {
+	mu      sync.Mutex
+	entries map[string]*providerEntry
+}
+
+// RegisterEnvironmentProvider 注册外部环境来源, 同名来源被替换
+func (em *EvolutionMatcher) RegisterEnvironmentProvider(provider EnvironmentProvider, opts ProviderOptions) error {
+	if provider == nil {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "nil environment provider", nil)
+	}
+	name := provider.Name()
+	if name == "" || strings.Contains(name, ".") {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "invalid environment provider name", nil).
+			WithContext("provider", name)
+	}
+	if opts.Weight <= 0 {
+		opts.Weight = defaultProviderWeight
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultProviderTimeout
+	}
+
+	em.providers.mu.Lock()
+	defer em.providers.mu.Unlock()
+	if em.providers.entries == nil {
+		em.providers.entries = make(map[string]*providerEntry)
+	}
+	em.providers.entries[name] = &providerEntry{provider: provider, options: opts}
+	return nil
+}
+
+// UnregisterEnvironmentProvider 移除环境来源, 其因素在下次匹配时从上下文中移除
+func (em *EvolutionMatcher) UnregisterEnvironmentProvider(name string) {
+	em.providers.mu.Lock()
+	defer em.providers.mu.Unlock()
+	delete(em.providers.entries, name)
+}
+
+// GetEnvironmentProviders 获取各环境来源的状态, 按名称排序
+func (em *EvolutionMatcher) GetEnvironmentProviders() []ProviderStatus {
+	now := time.Now()
+	em.providers.mu.Lock()
+	defer em.providers.mu.Unlock()
+
+	statuses := make([]ProviderStatus, 0, len(em.providers.entries))
+	for name, e := range em.providers.entries {
+		freshness := e.freshness(now)
+		status := ProviderStatus{
+			Name:      name,
+			Weight:    e.options.Weight * freshness,
+			Freshness: freshness,
+			Factors:   make(map[string]float64, len(e.values)),
+			Updated:   e.updated,
+			Stale:     freshness == 0,
+			Failures:  e.failures,
+		}
+		for k, v := range e.values {
+			status.Factors[k] = v
+		}
+		if e.lastErr != nil {
+			status.LastError = e.lastErr.Error()
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// GetEnvironment 获取当前环境因素的副本
+func (em *EvolutionMatcher) GetEnvironment() map[string]float64 {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+
+	env := make(map[string]float64, len(em.state.context.Environment))
+	for k, v := range em.state.context.Environment {
+		env[k] = v
+	}
+	return env
+}
+
+// refreshProviders 读取到期的环境来源, 不持有匹配器的锁
+func (em *EvolutionMatcher) refreshProviders(now time.Time) {
+	em.providers.mu.Lock()
+	due := make([]*providerEntry, 0, len(em.providers.entries))
+	for _, e := range em.providers.entries {
+		if e.polled.IsZero() || now.Sub(e.polled) >= e.options.Interval {
+			e.polled = now
+			due = append(due, e)
+		}
+	}
+	em.providers.mu.Unlock()
+
+	for _, e := range due {
+		ctx, cancel := context.WithTimeout(context.Background(), e.options.Timeout)
+		values, err := e.provider.Factors(ctx)
+		cancel()
+
+		em.providers.mu.Lock()
+		if err != nil {
+			e.failures++
+			e.lastErr = err
+		} else {
+			e.values = values
+			e.updated = time.Now()
+			e.lastErr = nil
+		}
+		em.providers.mu.Unlock()
+	}
+}
+
+// providerFactors 各来源未过期的因素及其按新鲜度衰减后的权重
+func (em *EvolutionMatcher) providerFactors(now time.Time) (map[string]float64, map[string]float64) {
+	em.providers.mu.Lock()
+	defer em.providers.mu.Unlock()
+
+	values := make(map[string]float64)
+	weights := make(map[string]float64)
+	for name, e := range em.providers.entries {
+		freshness := e.freshness(now)
+		if freshness == 0 {
+			continue
+		}
+		for factor, v := range e.values {
+			key := name + "." + factor
+			values[key] = v
+			weights[key] = e.options.Weight * freshness
+		}
+	}
+	return values, weights
+}
+
+// applyProviderFactors 将来源因素写入环境因素, 移除过期和已注销来源的因素, 调用方需持有匹配器的锁
+func (em *EvolutionMatcher) applyProviderFactors(now time.Time) {
+	values, weights := em.providerFactors(now)
+
+	env := em.state.context.Environment
+	for key := range env {
+		if _, builtin := builtinFactorWeights[key]; builtin {
+			continue
+		}
+		if _, ok := values[key]; !ok {
+			delete(env, key)
+		}
+	}
+	for key, v := range values {
+		env[key] = v
+	}
+
+	em.state.factorWeights = make(map[string]float64, len(builtinFactorWeights)+len(weights))
+	for key, w := range builtinFactorWeights {
+		em.state.factorWeights[key] = w
+	}
+	for key, w := range weights {
+		em.state.factorWeights[key] = w
+	}
+}
+
+// observeContexts 记录模式被观测时的环境, 模式再次出现时更新, 不可见的模式被移除; 调用方需持有匹配器的锁
+func (em *EvolutionMatcher) observeContexts(patterns []*RecognizedPattern) {
+	observed := make(map[string]observedContext, len(patterns))
+	for _, p := range patterns {
+		if prev, ok := em.state.observed[p.ID]; ok && !p.LastSeen.After(prev.seen) {
+			observed[p.ID] = prev
+			continue
+		}
+		env := make(map[string]float64, len(em.state.context.Environment))
+		for k, v := range em.state.context.Environment {
+			env[k] = v
+		}
+		observed[p.ID] = observedContext{seen: p.LastSeen, factors: env}
+	}
+	em.state.observed = observed
+}
+
+// patternContext 模式被观测时的环境, 未观测过时使用模式自带的上下文
+func (em *EvolutionMatcher) patternContext(p *RecognizedPattern) map[string]float64 {
+	if observed, ok := em.state.observed[p.ID]; ok {
+		return observed.factors
+	}
+	return p.Context
+}
+
+// observedContext 模式被观测时的环境
+type observedContext struct {
+	seen    time.Time
+	factors map[string]float64
+}
@@ -0,0 +1,149 @@
+// system/evolution/pattern/explain.go
+
+package pattern
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 签名相似度因子
+const (
+	FactorComponent = "component" // 组件集合
+	FactorStructure = "structure" // 结构特征
+	FactorDynamics  = "dynamics"  // 动态特征
+	FactorContext   = "context"   // 上下文信息
+)
+
+// 签名相似度各因子的权重
+const (
+	componentFactorWeight = 0.4
+	structureFactorWeight = 0.3
+	dynamicsFactorWeight  = 0.2
+	contextFactorWeight   = 0.1
+)
+
+// 解释中列出的组件对数
+const explainTopPairs = 5
+
+// FactorContribution 单个因子对签名相似度的贡献
+type FactorContribution struct {
+	Factor       string  `json:"factor"`
+	Metric       string  `json:"metric"`       // 计算因子相似度的度量
+	Similarity   float64 `json:"similarity"`   // 因子相似度(0-1)
+	Weight       float64 `json:"weight"`       // 因子权重
+	Contribution float64 `json:"contribution"` // 计入总相似度的部分, Similarity*Weight
+	Loss         float64 `json:"loss"`         // 因子未达到完全相似而损失的部分, (1-Similarity)*Weight
+}
+
+// ComponentPair 组件集合相似度中的一对组件
+// 第一个签名的每个组件与第二个签名中最相似的组件配对
+type ComponentPair struct {
+	Source     int                `json:"source"` // 组件在第一个签名中的序号
+	Target     int                `json:"target"` // 组件在第二个签名中的序号, 第二个签名无组件时为-1
+	SourceType string             `json:"source_type"`
+	SourceRole string             `json:"source_role"`
+	TargetType string             `json:"target_type"`
+	TargetRole string             `json:"target_role"`
+	Similarity float64            `json:"similarity"`
+	Factors    map[string]float64 `json:"factors"` // 类型、权重、属性、角色和连接各项的相似度
+}
+
+// SimilarityExplanation 签名相似度的分解
+type SimilarityExplanation struct {
+	Similarity    float64              `json:"similarity"`     // 总相似度, 与识别和关联时使用的值一致
+	Factors       []FactorContribution `json:"factors"`        // 按组件、结构、动态、上下文排列
+	TopMatches    []ComponentPair      `json:"top_matches"`    // 相似度最高的组件对
+	TopMismatches []ComponentPair      `json:"top_mismatches"` // 相似度最低的组件对
+}
+
+// ExplainSimilarity 按默认的HeuristicMetric分解两个签名的相似度: 各因子的贡献, 以及最相似和最不相似的组件对
+// 相似度不对称, 以sig1的组件为基准配对
+func ExplainSimilarity(sig1, sig2 PatternSignature) SimilarityExplanation {
+	var comparator *SignatureComparator
+	return comparator.Explain(sig1, sig2)
+}
+
+// explainFactors 汇总因子贡献并列出组件对, 组件对始终按HeuristicMetric的组件规则配对
+func explainFactors(factors []FactorContribution, sig1, sig2 PatternSignature) SimilarityExplanation {
+	explanation := SimilarityExplanation{Factors: factors}
+	for _, factor := range factors {
+		explanation.Similarity += factor.Contribution
+	}
+
+	pairs := componentPairs(sig1.Components, sig2.Components)
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Similarity > pairs[j].Similarity })
+	explanation.TopMatches = append([]ComponentPair(nil), pairs[:min(explainTopPairs, len(pairs))]...)
+
+	sort.SliceStable(pairs, func(i, j int) bool { return pairs[i].Similarity < pairs[j].Similarity })
+	explanation.TopMismatches = append([]ComponentPair(nil), pairs[:min(explainTopPairs, len(pairs))]...)
+
+	return explanation
+}
+
+// newFactorContribution 计算因子贡献
+func newFactorContribution(factor string, similarity, weight float64) FactorContribution {
+	return FactorContribution{
+		Factor:       factor,
+		Similarity:   similarity,
+		Weight:       weight,
+		Contribution: similarity * weight,
+		Loss:         (1 - similarity) * weight,
+	}
+}
+
+// componentPairs 按组件集合相似度的配对方式, 为comps1的每个组件找出comps2中最相似的组件
+func componentPairs(comps1, comps2 []SignatureComponent) []ComponentPair {
+	pairs := make([]ComponentPair, 0, len(comps1))
+	for i, c1 := range comps1 {
+		pair := ComponentPair{Source: i, Target: -1, SourceType: c1.Type, SourceRole: c1.Role}
+		for j, c2 := range comps2 {
+			if sim := calculateComponentSimilarity(c1, c2); pair.Target < 0 || sim > pair.Similarity {
+				pair.Target = j
+				pair.Similarity = sim
+			}
+		}
+		if pair.Target >= 0 {
+			c2 := comps2[pair.Target]
+			pair.TargetType = c2.Type
+			pair.TargetRole = c2.Role
+			pair.Factors = componentFactors(c1, c2)
+		}
+		pairs = append(pairs, pair)
+	}
+	return pairs
+}
+
+// componentFactors 单个组件相似度的各项
+func componentFactors(c1, c2 SignatureComponent) map[string]float64 {
+	factors := map[string]float64{
+		"type":       0,
+		"weight":     1 - math.Abs(c1.Weight-c2.Weight),
+		"properties": calculatePropertySimilarity(c1.Properties, c2.Properties),
+		"role":       componentRoleSimilarity(c1, c2),
+		"connection": calculateConnectionSimilarity(c1.Connections, c2.Connections),
+	}
+	if c1.Type == c2.Type {
+		factors["type"] = 1
+	}
+	return factors
+}
+
+// ExplainSimilarity 分解两个已识别模式的签名相似度
+func (pr *PatternRecognizer) ExplainSimilarity(id1, id2 string) (SimilarityExplanation, error) {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	signatures := make([]PatternSignature, 0, 2)
+	for _, id := range []string{id1, id2} {
+		pattern, exists := pr.state.patterns[id]
+		if !exists {
+			return SimilarityExplanation{}, types.NewDomainError(types.DomainPattern, types.ErrNotFound, "recognized pattern not found", nil).
+				WithContext("pattern", id)
+		}
+		signatures = append(signatures, pattern.Signature)
+	}
+	return pr.comparator.Explain(signatures[0], signatures[1]), nil
+}
@@ -0,0 +1,349 @@
+// system/evolution/pattern/family.go
+
+package pattern
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认聚类参数
+const (
+	defaultFamilyThreshold      = 0.9
+	defaultFamilyMergeThreshold = 0.97
+	defaultMaxFamilies          = 100
+	defaultFamilyHorizon        = 100
+)
+
+// FamilyStats 模式族统计
+type FamilyStats struct {
+	ID             string             `json:"id"`
+	Type           string             `json:"type"`            // 成员的模式类型
+	Size           int                `json:"size"`            // 当前成员数
+	Members        []string           `json:"members"`         // 成员模式ID, 按ID排序
+	Observations   int64              `json:"observations"`    // 累计归入的观测次数
+	Centroid       map[string]float64 `json:"centroid"`        // 族中心特征向量
+	Cohesion       float64            `json:"cohesion"`        // 成员与族中心的平均相似度
+	MeanConfidence float64            `json:"mean_confidence"` // 成员的平均置信度
+	MeanStability  float64            `json:"mean_stability"`  // 成员的平均稳定性
+	Occurrences    int                `json:"occurrences"`     // 成员出现次数合计
+	Created        time.Time          `json:"created"`
+	Updated        time.Time          `json:"updated"`
+}
+
+// familyMember 成员的最近一次观测
+type familyMember struct {
+	similarity  float64
+	confidence  float64
+	stability   float64
+	occurrences int
+}
+
+// patternFamily 模式族
+type patternFamily struct {
+	id           string
+	patternType  string
+	centroid     map[string]float64
+	members      map[string]familyMember
+	observations int64
+	created      time.Time
+	updated      time.Time
+}
+
+// FamilyClusterer 已识别模式的在线聚类, 并发安全
+// 每次观测将模式归入同类型中最相似的族并更新族中心, 族中心接近的族合并, 成员全部移除的族删除
+type FamilyClusterer struct {
+	mu sync.RWMutex
+
+	config     types.PatternFamilyConfig
+	families   map[string]*patternFamily
+	membership map[string]string // 模式ID -> 族ID
+}
+
+// NewFamilyClusterer 创建模式族聚类, 零值参数取默认值
+func NewFamilyClusterer(config types.PatternFamilyConfig) *FamilyClusterer {
+	if config.Threshold <= 0 {
+		config.Threshold = defaultFamilyThreshold
+	}
+	if config.MergeThreshold <= 0 {
+		config.MergeThreshold = defaultFamilyMergeThreshold
+	}
+	if config.MaxFamilies <= 0 {
+		config.MaxFamilies = defaultMaxFamilies
+	}
+	if config.Horizon <= 0 {
+		config.Horizon = defaultFamilyHorizon
+	}
+
+	return &FamilyClusterer{
+		config:     config,
+		families:   make(map[string]*patternFamily),
+		membership: make(map[string]string),
+	}
+}
+
+// validateFamilyConfig 校验聚类配置
+func validateFamilyConfig(cfg types.PatternFamilyConfig) error {
+	if cfg.Threshold < 0 || cfg.Threshold > 1 || cfg.MergeThreshold < 0 || cfg.MergeThreshold > 1 {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "family thresholds must be in [0, 1]", nil)
+	}
+	if cfg.MaxFamilies < 0 || cfg.Horizon < 0 {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "negative family limit or horizon", nil)
+	}
+	return nil
+}
+
+// FamilyVector 模式聚类使用的特征向量, 由签名各维度的特征向量以"维度.特征"合并
+func FamilyVector(sig PatternSignature) map[string]float64 {
+	vector := make(map[string]float64)
+	for _, dimension := range SignatureDimensions {
+		for key, value := range SignatureVector(dimension, sig) {
+			vector[dimension+"."+key] = value
+		}
+	}
+	return vector
+}
+
+// Observe 将模式归入族, 返回族ID
+func (fc *FamilyClusterer) Observe(p *RecognizedPattern) string {
+	if fc == nil || p == nil {
+		return ""
+	}
+	vector := FamilyVector(p.Signature)
+
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+
+	now := time.Now()
+	family, similarity := fc.nearest(p.Type, vector)
+	if family == nil || (similarity < fc.config.Threshold && len(fc.families) < fc.config.MaxFamilies) {
+		family = &patternFamily{
+			id:          core.NewID("fam_"),
+			patternType: p.Type,
+			centroid:    make(map[string]float64, len(vector)),
+			members:     make(map[string]familyMember),
+			created:     now,
+		}
+		fc.families[family.id] = family
+	}
+
+	// 模式换族时从原族移除
+	if previous, exists := fc.membership[p.ID]; exists && previous != family.id {
+		fc.leave(p.ID)
+	}
+
+	fc.updateCentroid(family, vector)
+	family.members[p.ID] = familyMember{
+		similarity:  cosineVectors(vector, family.centroid),
+		confidence:  p.Confidence,
+		stability:   p.Stability,
+		occurrences: p.Occurrences,
+	}
+	family.observations++
+	family.updated = now
+	fc.membership[p.ID] = family.id
+
+	return fc.merge(family).id
+}
+
+// Remove 移除模式的族成员关系, 族无成员时删除
+func (fc *FamilyClusterer) Remove(patternID string) {
+	if fc == nil {
+		return
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	fc.leave(patternID)
+}
+
+// Prune 移除不再存在的模式
+func (fc *FamilyClusterer) Prune(exists func(patternID string) bool) {
+	if fc == nil {
+		return
+	}
+	fc.mu.Lock()
+	defer fc.mu.Unlock()
+	for patternID := range fc.membership {
+		if !exists(patternID) {
+			fc.leave(patternID)
+		}
+	}
+}
+
+// FamilyOf 获取模式所属的族ID
+func (fc *FamilyClusterer) FamilyOf(patternID string) (string, bool) {
+	if fc == nil {
+		return "", false
+	}
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+	id, exists := fc.membership[patternID]
+	return id, exists
+}
+
+// Family 获取族统计
+func (fc *FamilyClusterer) Family(id string) (FamilyStats, bool) {
+	if fc == nil {
+		return FamilyStats{}, false
+	}
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+	family, exists := fc.families[id]
+	if !exists {
+		return FamilyStats{}, false
+	}
+	return family.stats(), true
+}
+
+// Families 获取全部族统计, 按成员数降序排列
+func (fc *FamilyClusterer) Families() []FamilyStats {
+	if fc == nil {
+		return nil
+	}
+	fc.mu.RLock()
+	defer fc.mu.RUnlock()
+
+	stats := make([]FamilyStats, 0, len(fc.families))
+	for _, family := range fc.families {
+		stats = append(stats, family.stats())
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Size != stats[j].Size {
+			return stats[i].Size > stats[j].Size
+		}
+		return stats[i].ID < stats[j].ID
+	})
+	return stats
+}
+
+// nearest 同类型中与向量最相似的族(调用方持有锁)
+func (fc *FamilyClusterer) nearest(patternType string, vector map[string]float64) (*patternFamily, float64) {
+	var best *patternFamily
+	bestSimilarity := -1.0
+	for _, family := range fc.families {
+		if family.patternType != patternType {
+			continue
+		}
+		similarity := cosineVectors(vector, family.centroid)
+		if similarity > bestSimilarity || (similarity == bestSimilarity && family.id < best.id) {
+			best, bestSimilarity = family, similarity
+		}
+	}
+	return best, bestSimilarity
+}
+
+// updateCentroid 以流式均值更新族中心, 有效样本数不超过Horizon(调用方持有锁)
+func (fc *FamilyClusterer) updateCentroid(family *patternFamily, vector map[string]float64) {
+	n := float64(family.observations + 1)
+	rate := 1 / math.Min(n, float64(fc.config.Horizon))
+	for key := range family.centroid {
+		if _, exists := vector[key]; !exists {
+			family.centroid[key] -= rate * family.centroid[key]
+		}
+	}
+	for key, value := range vector {
+		family.centroid[key] += rate * (value - family.centroid[key])
+	}
+}
+
+// merge 将族与同类型中族中心接近的族合并, 返回合并后保留的族(调用方持有锁)
+// 保留观测次数较多的族, 族中心按观测次数加权
+func (fc *FamilyClusterer) merge(family *patternFamily) *patternFamily {
+	for _, other := range fc.families {
+		if other == family || other.patternType != family.patternType ||
+			cosineVectors(family.centroid, other.centroid) < fc.config.MergeThreshold {
+			continue
+		}
+
+		keep, absorbed := family, other
+		if other.observations > family.observations {
+			keep, absorbed = other, family
+		}
+		total := float64(keep.observations + absorbed.observations)
+		for _, key := range unionKeys(keep.centroid, absorbed.centroid) {
+			keep.centroid[key] = (keep.centroid[key]*float64(keep.observations) +
+				absorbed.centroid[key]*float64(absorbed.observations)) / total
+		}
+		for id, member := range absorbed.members {
+			keep.members[id] = member
+			fc.membership[id] = keep.id
+		}
+		keep.observations += absorbed.observations
+		if absorbed.created.Before(keep.created) {
+			keep.created = absorbed.created
+		}
+		if absorbed.updated.After(keep.updated) {
+			keep.updated = absorbed.updated
+		}
+		delete(fc.families, absorbed.id)
+		return fc.merge(keep)
+	}
+	return family
+}
+
+// leave 将模式移出所属的族(调用方持有锁)
+func (fc *FamilyClusterer) leave(patternID string) {
+	id, exists := fc.membership[patternID]
+	if !exists {
+		return
+	}
+	delete(fc.membership, patternID)
+	if family, exists := fc.families[id]; exists {
+		delete(family.members, patternID)
+		if len(family.members) == 0 {
+			delete(fc.families, id)
+		}
+	}
+}
+
+// stats 族统计
+func (f *patternFamily) stats() FamilyStats {
+	stats := FamilyStats{
+		ID:           f.id,
+		Type:         f.patternType,
+		Size:         len(f.members),
+		Members:      make([]string, 0, len(f.members)),
+		Observations: f.observations,
+		Centroid:     make(map[string]float64, len(f.centroid)),
+		Created:      f.created,
+		Updated:      f.updated,
+	}
+	for key, value := range f.centroid {
+		stats.Centroid[key] = value
+	}
+	for id, member := range f.members {
+		stats.Members = append(stats.Members, id)
+		stats.Cohesion += member.similarity
+		stats.MeanConfidence += member.confidence
+		stats.MeanStability += member.stability
+		stats.Occurrences += member.occurrences
+	}
+	sort.Strings(stats.Members)
+	if n := float64(len(f.members)); n > 0 {
+		stats.Cohesion /= n
+		stats.MeanConfidence /= n
+		stats.MeanStability /= n
+	}
+	return stats
+}
+
+// cosineVectors 两个特征向量的余弦相似度, 负值取0, 任一方为零向量时为0
+func cosineVectors(v1, v2 map[string]float64) float64 {
+	dot, norm1, norm2 := 0.0, 0.0, 0.0
+	for key, a := range v1 {
+		dot += a * v2[key]
+		norm1 += a * a
+	}
+	for _, b := range v2 {
+		norm2 += b * b
+	}
+	if norm1 == 0 || norm2 == 0 {
+		return 0
+	}
+	return math.Max(0, math.Min(1, dot/math.Sqrt(norm1*norm2)))
+}
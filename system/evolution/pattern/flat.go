@@ -0,0 +1,102 @@
+// system/evolution/pattern/flat.go
+
+package pattern
+
+import (
+	"math"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+)
+
+// 扁平状态分量索引
+const (
+	FlatStrength  = iota // 强度
+	FlatPhase            // 相位
+	FlatEnergy           // 能量
+	FlatCoherence        // 相干性
+
+	flatDimensions
+)
+
+// FlatState 扁平化的模式状态, 按固定索引保存状态差异计算所需的分量
+// 演化历史先整体转换为扁平状态, 之后逐对比较时不再查找属性表, 也不产生分配
+type FlatState [flatDimensions]float64
+
+// flatWeights 状态差异中各分量的权重
+var flatWeights = FlatState{
+	FlatStrength:  0.3,
+	FlatPhase:     0.3,
+	FlatEnergy:    0.2,
+	FlatCoherence: 0.2,
+}
+
+// FlattenState 转换状态为扁平状态
+// 关联模式时取模式的强度和属性, 否则取状态自身的属性
+func FlattenState(state PatternState) FlatState {
+	if state.Pattern != nil {
+		return flattenPattern(state.Pattern)
+	}
+	return FlatState{
+		FlatStrength:  state.Properties["strength"],
+		FlatPhase:     state.Properties["phase"],
+		FlatEnergy:    state.Properties["energy"],
+		FlatCoherence: state.Properties["coherence"],
+	}
+}
+
+// FlattenEmergentState 转换涌现模式的演化状态为扁平状态
+// 未关联模式时取状态记录的强度和能量
+func FlattenEmergentState(state emergence.PatternState) FlatState {
+	if state.Pattern != nil {
+		return flattenPattern(state.Pattern)
+	}
+	return FlatState{
+		FlatStrength:  state.Strength,
+		FlatPhase:     state.Properties["phase"],
+		FlatEnergy:    state.Energy,
+		FlatCoherence: state.Properties["coherence"],
+	}
+}
+
+// FlattenStates 转换状态序列, dst容量足够时复用
+func FlattenStates(states []PatternState, dst []FlatState) []FlatState {
+	if cap(dst) < len(states) {
+		dst = make([]FlatState, 0, len(states))
+	}
+	dst = dst[:0]
+	for i := range states {
+		dst = append(dst, FlattenState(states[i]))
+	}
+	return dst
+}
+
+// FlattenEvolution 转换涌现模式的演化历史, dst容量足够时复用
+func FlattenEvolution(evolution []emergence.PatternState, dst []FlatState) []FlatState {
+	if cap(dst) < len(evolution) {
+		dst = make([]FlatState, 0, len(evolution))
+	}
+	dst = dst[:0]
+	for i := range evolution {
+		dst = append(dst, FlattenEmergentState(evolution[i]))
+	}
+	return dst
+}
+
+// Difference 两个扁平状态的加权差异, 范围[0,1]
+func (s *FlatState) Difference(other *FlatState) float64 {
+	diff := math.Abs(s[FlatStrength]-other[FlatStrength])*flatWeights[FlatStrength] +
+		normalizePhase(s[FlatPhase]-other[FlatPhase])*flatWeights[FlatPhase] +
+		math.Abs(s[FlatEnergy]-other[FlatEnergy])*flatWeights[FlatEnergy] +
+		math.Abs(s[FlatCoherence]-other[FlatCoherence])*flatWeights[FlatCoherence]
+	return math.Min(1.0, diff)
+}
+
+// flattenPattern 按模式的强度和属性构造扁平状态
+func flattenPattern(pattern *emergence.EmergentPattern) FlatState {
+	return FlatState{
+		FlatStrength:  pattern.Strength,
+		FlatPhase:     pattern.Properties["phase"],
+		FlatEnergy:    pattern.Properties["energy"],
+		FlatCoherence: pattern.Properties["coherence"],
+	}
+}
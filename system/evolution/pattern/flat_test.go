@@ -0,0 +1,138 @@
+// system/evolution/pattern/flat_test.go
+
+package pattern
+
+import (
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/Corphon/daoflow/testutil/generator"
+)
+
+// benchHistory 生成识别模式的演化历史, 每个状态关联一次观测到的涌现模式
+func benchHistory(tb testing.TB, length int, seed int64) []PatternState {
+	tb.Helper()
+	spec := generator.DefaultPatternSpec("bench", length)
+	spec.Seed = seed
+	patterns, err := generator.Patterns(spec)
+	if err != nil {
+		tb.Fatal(err)
+	}
+
+	history := make([]PatternState, len(patterns))
+	for i := range patterns {
+		p := &patterns[i]
+		p.Properties["energy"] = p.Energy
+		p.Properties["phase"] = math.Mod(float64(i)*0.7, 2*math.Pi) - math.Pi
+		p.Properties["coherence"] = p.Stability
+		history[i] = PatternState{
+			Pattern:    p,
+			Active:     true,
+			Duration:   p.LastUpdate.Sub(p.Formation),
+			LastUpdate: p.LastUpdate,
+			Properties: p.Properties,
+		}
+	}
+	return history
+}
+
+var benchHistoryLengths = []int{16, 64, 256}
+
+// 两段演化历史逐对比较, 与演化关联检查中的嵌套循环相同
+func BenchmarkStateDifferencePairs(b *testing.B) {
+	for _, n := range benchHistoryLengths {
+		h1, h2 := benchHistory(b, n, 1), benchHistory(b, n, 2)
+
+		b.Run(fmt.Sprintf("map/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sum := 0.0
+				for x := range h1 {
+					for y := range h2 {
+						sum += calculateStateDifference(h1[x], h2[y])
+					}
+				}
+				benchSink = sum
+			}
+		})
+
+		b.Run(fmt.Sprintf("flat/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			var flat1, flat2 []FlatState
+			for i := 0; i < b.N; i++ {
+				flat1 = FlattenStates(h1, flat1)
+				flat2 = FlattenStates(h2, flat2)
+				sum := 0.0
+				for x := range flat1 {
+					for y := range flat2 {
+						sum += flat1[x].Difference(&flat2[y])
+					}
+				}
+				benchSink = sum
+			}
+		})
+	}
+}
+
+// 相邻状态差异累加, 与演化速率和敏感度计算相同
+func BenchmarkStateDifferenceAdjacent(b *testing.B) {
+	for _, n := range benchHistoryLengths {
+		history := benchHistory(b, n, 1)
+
+		b.Run(fmt.Sprintf("map/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				sum := 0.0
+				for x := 1; x < len(history); x++ {
+					sum += calculateStateDifference(history[x-1], history[x])
+				}
+				benchSink = sum
+			}
+		})
+
+		b.Run(fmt.Sprintf("flat/%d", n), func(b *testing.B) {
+			b.ReportAllocs()
+			var flat []FlatState
+			for i := 0; i < b.N; i++ {
+				flat = FlattenStates(history, flat)
+				sum := 0.0
+				for x := 1; x < len(flat); x++ {
+					sum += flat[x-1].Difference(&flat[x])
+				}
+				benchSink = sum
+			}
+		})
+	}
+}
+
+var benchSink float64
+
+func TestFlatDifferenceMatchesStateDifference(t *testing.T) {
+	history := benchHistory(t, 32, 1)
+	flat := FlattenStates(history, nil)
+	for i := 1; i < len(history); i++ {
+		want := calculateStateDifference(history[i-1], history[i])
+		if got := flat[i-1].Difference(&flat[i]); got != want {
+			t.Fatalf("difference %d = %v, want %v", i, got, want)
+		}
+	}
+}
+
+// 复用dst时扁平化和差异循环不产生分配
+func TestFlatDifferenceLoopDoesNotAllocate(t *testing.T) {
+	history := benchHistory(t, 64, 1)
+	dst := FlattenStates(history, nil)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		dst = FlattenStates(history, dst)
+		sum := 0.0
+		for i := 1; i < len(dst); i++ {
+			sum += dst[i-1].Difference(&dst[i])
+		}
+		benchSink = sum
+	})
+	if allocs != 0 {
+		t.Errorf("allocs per run = %v, want 0", allocs)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/meta/emergence"
 	"github.com/Corphon/daoflow/system/types"
@@ -160,7 +161,7 @@ type MetricPoint struct {
 // NewPatternGenerator 创建新的模式生成器
 func NewPatternGenerator(config *types.PatternConfig) (*PatternGenerator, error) {
 	if config == nil {
-		return nil, fmt.Errorf("nil pattern config")
+		return nil, types.NewDomainError(types.DomainPattern, types.ErrInvalid, "nil pattern config", nil)
 	}
 
 	pg := &PatternGenerator{
@@ -194,20 +195,26 @@ func (pg *PatternGenerator) Generate() error {
 	// 选择模板
 	template := pg.selectTemplate()
 	if template == nil {
-		return model.WrapError(nil, model.ErrCodeOperation, "no suitable template")
+		return types.NewDomainError(types.DomainPattern, model.ErrCodeOperation, "no suitable template", nil)
 	}
 
 	// 生成候选模式
 	candidates := pg.generateCandidates(template)
 
 	// 评估候选模式
-	evaluated := pg.evaluateCandidates(candidates)
+	evaluated, err := pg.evaluateCandidates(candidates)
+	if err != nil {
+		return err
+	}
 
 	// 选择最佳候选
 	selected := pg.selectBestCandidates(evaluated)
 
 	// 优化选中的模式
-	optimized := pg.optimizePatterns(selected)
+	optimized, err := pg.optimizePatterns(selected)
+	if err != nil {
+		return err
+	}
 
 	// 更新生成指标
 	pg.updateMetrics(optimized)
@@ -256,7 +263,7 @@ func (pg *PatternGenerator) selectTemplate() *GenerationTemplate {
 // RegisterTemplate 注册生成模板
 func (pg *PatternGenerator) RegisterTemplate(template *GenerationTemplate) error {
 	if template == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "nil template")
+		return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "nil template", nil)
 	}
 
 	pg.mu.Lock()
@@ -491,14 +498,17 @@ func balanceEnergy(pattern *emergence.EmergentPattern) {
 
 // evaluateCandidates 评估候选模式
 func (pg *PatternGenerator) evaluateCandidates(
-	candidates []*PatternCandidate) []*PatternCandidate {
+	candidates []*PatternCandidate) ([]*PatternCandidate, error) {
 
 	for _, candidate := range candidates {
 		// 计算基础分数
 		baseScore := pg.calculateBaseScore(candidate.Pattern)
 
 		// 评估复杂度
-		complexityScore := pg.evaluateComplexity(candidate.Pattern)
+		complexityScore, err := pg.evaluateComplexity(candidate.Pattern)
+		if err != nil {
+			return nil, err
+		}
 
 		// 检查能量平衡
 		energyScore := pg.checkEnergyBalance(candidate.Pattern)
@@ -507,7 +517,7 @@ func (pg *PatternGenerator) evaluateCandidates(
 		candidate.Score = pg.combineScores(baseScore, complexityScore, energyScore)
 	}
 
-	return candidates
+	return candidates, nil
 }
 
 // calculateBaseScore 计算基础分数
@@ -523,7 +533,7 @@ func (pg *PatternGenerator) calculateBaseScore(pattern *emergence.EmergentPatter
 }
 
 // evaluateComplexity 评估复杂度
-func (pg *PatternGenerator) evaluateComplexity(pattern *emergence.EmergentPattern) float64 {
+func (pg *PatternGenerator) evaluateComplexity(pattern *emergence.EmergentPattern) (float64, error) {
 	// 组件复杂度
 	componentComplexity := calculateComponentComplexity(
 		convertComponents(pattern.Components))
@@ -533,10 +543,13 @@ func (pg *PatternGenerator) evaluateComplexity(pattern *emergence.EmergentPatter
 		extractStructureMap(pattern)) // 解引用
 
 	// 动态复杂度
-	dynamicComplexity := calculateDynamicComplexity(
-		extractDynamicFeatures(*pattern)) // 解引用
+	dynamics, err := extractDynamicFeatures(*pattern) // 解引用
+	if err != nil {
+		return 0, err
+	}
+	dynamicComplexity := calculateDynamicComplexity(dynamics)
 
-	return (componentComplexity*0.4 + structuralComplexity*0.3 + dynamicComplexity*0.3)
+	return (componentComplexity*0.4 + structuralComplexity*0.3 + dynamicComplexity*0.3), nil
 }
 
 // checkEnergyBalance 检查能量平衡
@@ -611,13 +624,16 @@ func (pg *PatternGenerator) selectBestCandidates(
 
 // optimizePatterns 优化模式
 func (pg *PatternGenerator) optimizePatterns(
-	patterns []*PatternCandidate) []*PatternCandidate {
+	patterns []*PatternCandidate) ([]*PatternCandidate, error) {
 
 	optimized := make([]*PatternCandidate, 0)
 
 	for _, pattern := range patterns {
 		// 应用优化规则
-		improved := pg.optimizePattern(pattern)
+		improved, err := pg.optimizePattern(pattern)
+		if err != nil {
+			return nil, err
+		}
 
 		// 检查优化效果
 		if improved.Score > pattern.Score {
@@ -627,11 +643,11 @@ func (pg *PatternGenerator) optimizePatterns(
 		}
 	}
 
-	return optimized
+	return optimized, nil
 }
 
 // optimizePattern 优化单个模式候选
-func (pg *PatternGenerator) optimizePattern(candidate *PatternCandidate) *PatternCandidate {
+func (pg *PatternGenerator) optimizePattern(candidate *PatternCandidate) (*PatternCandidate, error) {
 	// 创建副本
 	optimized := &PatternCandidate{
 		ID:         candidate.ID + "_opt",
@@ -657,24 +673,31 @@ func (pg *PatternGenerator) optimizePattern(candidate *PatternCandidate) *Patter
 	optimizeEnergyDistribution(optimized.Pattern)
 
 	// 4. 重新评分
-	optimized.Score = pg.evaluatePattern(optimized.Pattern)
+	score, err := pg.evaluatePattern(optimized.Pattern)
+	if err != nil {
+		return nil, err
+	}
+	optimized.Score = score
 
-	return optimized
+	return optimized, nil
 }
 
 // evaluatePattern 评估单个模式
-func (pg *PatternGenerator) evaluatePattern(pattern *emergence.EmergentPattern) float64 {
+func (pg *PatternGenerator) evaluatePattern(pattern *emergence.EmergentPattern) (float64, error) {
 	// 计算基础分数
 	baseScore := pg.calculateBaseScore(pattern)
 
 	// 评估复杂度
-	complexityScore := pg.evaluateComplexity(pattern)
+	complexityScore, err := pg.evaluateComplexity(pattern)
+	if err != nil {
+		return 0, err
+	}
 
 	// 检查能量平衡
 	energyScore := pg.checkEnergyBalance(pattern)
 
 	// 组合得分
-	return pg.combineScores(baseScore, complexityScore, energyScore)
+	return pg.combineScores(baseScore, complexityScore, energyScore), nil
 }
 
 // adjustWeight 调整权重
@@ -765,7 +788,7 @@ func (pg *PatternGenerator) updateMetrics(patterns []*PatternCandidate) {
 
 func (pg *PatternGenerator) validateTemplate(template *GenerationTemplate) error {
 	if template.ID == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty template ID")
+		return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "empty template ID", nil)
 	}
 
 	// 验证结构
@@ -785,41 +808,41 @@ func (pg *PatternGenerator) validateTemplate(template *GenerationTemplate) error
 func (pg *PatternGenerator) validateStructure(structure TemplateStructure) error {
 	// 1. 验证组件规格
 	if len(structure.Components) == 0 {
-		return model.WrapError(nil, model.ErrCodeValidation, "no components defined")
+		return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "no components defined", nil)
 	}
 
 	for _, comp := range structure.Components {
 		// 验证组件类型
 		if comp.Type == "" {
-			return model.WrapError(nil, model.ErrCodeValidation, "empty component type")
+			return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "empty component type", nil)
 		}
 
 		// 验证属性范围
 		for _, rng := range comp.Properties {
 			if rng.Min > rng.Max {
-				return model.WrapError(nil, model.ErrCodeValidation, "invalid property range")
+				return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "invalid property range", nil)
 			}
 		}
 
 		// 验证数量范围
 		if comp.Quantity.Min > comp.Quantity.Max {
-			return model.WrapError(nil, model.ErrCodeValidation, "invalid quantity range")
+			return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "invalid quantity range", nil)
 		}
 	}
 
 	// 2. 验证关系规格
 	for _, rel := range structure.Relations {
 		if rel.Source == "" || rel.Target == "" {
-			return model.WrapError(nil, model.ErrCodeValidation, "invalid relation")
+			return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "invalid relation", nil)
 		}
 		if rel.Strength.Min > rel.Strength.Max {
-			return model.WrapError(nil, model.ErrCodeValidation, "invalid strength range")
+			return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "invalid strength range", nil)
 		}
 	}
 
 	// 3. 验证动态规格
 	if structure.Dynamics.TimeScale.Min > structure.Dynamics.TimeScale.Max {
-		return model.WrapError(nil, model.ErrCodeValidation, "invalid time scale")
+		return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "invalid time scale", nil)
 	}
 
 	return nil
@@ -830,23 +853,23 @@ func (pg *PatternGenerator) validateConstraints(constraints []GenerationConstrai
 	for _, constraint := range constraints {
 		// 验证约束类型
 		if constraint.Type == "" {
-			return model.WrapError(nil, model.ErrCodeValidation, "empty constraint type")
+			return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "empty constraint type", nil)
 		}
 
 		// 验证约束目标
 		if constraint.Target == "" {
-			return model.WrapError(nil, model.ErrCodeValidation, "empty constraint target")
+			return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "empty constraint target", nil)
 		}
 
 		// 验证约束条件
 		if constraint.Condition == "" {
-			return model.WrapError(nil, model.ErrCodeValidation, "empty constraint condition")
+			return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation, "empty constraint condition", nil)
 		}
 
 		// 检查条件和目标的匹配性
 		if !pg.isValidConstraintCondition(constraint.Type, constraint.Condition) {
-			return model.WrapError(nil, model.ErrCodeValidation,
-				"invalid constraint condition for type")
+			return types.NewDomainError(types.DomainPattern, model.ErrCodeValidation,
+				"invalid constraint condition for type", nil)
 		}
 	}
 
@@ -875,7 +898,7 @@ func (pg *PatternGenerator) isValidConstraintCondition(
 }
 
 func generateCandidateID() string {
-	return fmt.Sprintf("cand_%d", time.Now().UnixNano())
+	return core.NewID("cand_")
 }
 
 const (
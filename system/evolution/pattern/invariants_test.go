@@ -0,0 +1,201 @@
+// system/evolution/pattern/invariants_test.go
+
+package pattern
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/Corphon/daoflow/system/common"
+	"github.com/Corphon/daoflow/system/invariants"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+)
+
+const propertyTrials = 1000
+
+// unitInput 取值在[0, 1]内的输入, 混入越界值和NaN/Inf
+var unitInput = invariants.Edges(invariants.Floats(0, 1), true)
+
+// withInvariantMode 在测试期间切换越界处理模式, 结束时恢复截断模式
+func withInvariantMode(t *testing.T, m invariants.Mode) {
+	t.Helper()
+	invariants.SetMode(m)
+	invariants.Reset()
+	t.Cleanup(func() {
+		invariants.SetMode(invariants.ModeClamp)
+		invariants.Reset()
+	})
+}
+
+func inUnit(inputs []float64) bool {
+	for _, v := range inputs {
+		if v < 0 || v > 1 || math.IsNaN(v) {
+			return false
+		}
+	}
+	return true
+}
+
+// strictResult 严格模式下结果在[0, 1]内或以*Violation拒绝, 返回是否拒绝
+func strictResult(name string, v float64, err error) (bool, error) {
+	if err == nil {
+		return false, invariants.Unit(name, v)
+	}
+	var violation *invariants.Violation
+	if !errors.As(err, &violation) {
+		return false, fmt.Errorf("%s: error %v is not *invariants.Violation", name, err)
+	}
+	return true, nil
+}
+
+// checkStrict 严格模式下检查性质并要求至少出现一次拒绝
+func checkStrict(t *testing.T, name string, fn func(inputs []float64) (float64, error), gens ...invariants.Gen) {
+	t.Helper()
+	violations := 0
+	prop := func(inputs []float64) error {
+		v, err := fn(inputs)
+		rejected, err := strictResult(name, v, err)
+		if rejected {
+			violations++
+		}
+		return err
+	}
+	if err := invariants.ForAll(name, propertyTrials, 1, prop, gens...); err != nil {
+		t.Fatal(err)
+	}
+	if violations == 0 {
+		t.Fatalf("%s: no violations in strict mode, test is vacuous", name)
+	}
+}
+
+// checkClamp 截断模式下检查结果范围
+func checkClamp(t *testing.T, name string, fn func(inputs []float64) (float64, error), gens ...invariants.Gen) {
+	t.Helper()
+	prop := func(inputs []float64) error {
+		v, err := fn(inputs)
+		if err != nil {
+			return err
+		}
+		return invariants.Unit(name, v)
+	}
+	if err := invariants.ForAll(name, propertyTrials, 1, prop, gens...); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// analyzeScore 以输入作为强度和稳定性分析模式, 每次试验使用新模式以免历史影响评分
+func analyzeScore(pa *PatternAnalyzerImpl) func(inputs []float64) (float64, error) {
+	trial := 0
+	return func(inputs []float64) (float64, error) {
+		trial++
+		return pa.AnalyzePattern(&common.BasePattern{
+			ID:        fmt.Sprintf("p%d", trial),
+			Type:      "test",
+			Strength:  inputs[0],
+			Stability: inputs[1],
+			Created:   time.Now(),
+		})
+	}
+}
+
+func TestAnalyzePatternScoreInvariant(t *testing.T) {
+	t.Run("clamp", func(t *testing.T) {
+		withInvariantMode(t, invariants.ModeClamp)
+		checkClamp(t, "analyzer.score", analyzeScore(NewPatternAnalyzer()), unitInput, unitInput)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		withInvariantMode(t, invariants.ModeStrict)
+		score := analyzeScore(NewPatternAnalyzer())
+		checkStrict(t, "analyzer.score", func(inputs []float64) (float64, error) {
+			v, err := score(inputs)
+			// 越界的强度或稳定性必须被拒绝
+			if err == nil && !inUnit(inputs) {
+				return 0, fmt.Errorf("out-of-range inputs %v accepted with score %v", inputs, v)
+			}
+			return v, err
+		}, unitInput, unitInput)
+	})
+}
+
+// compareSimilarity 以输入作为两个模式的强度和稳定性比较模式
+func compareSimilarity(pa *PatternAnalyzerImpl) func(inputs []float64) (float64, error) {
+	return func(inputs []float64) (float64, error) {
+		now := time.Now()
+		return pa.ComparePatterns(
+			&common.BasePattern{ID: "a", Type: "test", Strength: inputs[0], Stability: inputs[1], Created: now},
+			&common.BasePattern{ID: "b", Type: "test", Strength: inputs[2], Stability: inputs[3], Created: now},
+		)
+	}
+}
+
+func TestComparePatternsSimilarityInvariant(t *testing.T) {
+	// 强度差可达到1e12量级, 使相似度越界
+	input := invariants.Edges(invariants.Floats(-2, 2), true)
+
+	t.Run("clamp", func(t *testing.T) {
+		withInvariantMode(t, invariants.ModeClamp)
+		checkClamp(t, "analyzer.similarity", compareSimilarity(NewPatternAnalyzer()), input, input, input, input)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		withInvariantMode(t, invariants.ModeStrict)
+		checkStrict(t, "analyzer.similarity", compareSimilarity(NewPatternAnalyzer()), input, input, input, input)
+	})
+}
+
+// patternComplexity 以输入作为动态特征计算模式复杂度
+func patternComplexity(inputs []float64) (float64, error) {
+	return calculatePatternComplexity(&RecognizedPattern{
+		Signature: PatternSignature{
+			Dynamics: map[string]float64{
+				"energy":       inputs[0],
+				"evolution":    inputs[1],
+				"stability":    inputs[2],
+				"adaptability": inputs[3],
+			},
+		},
+	})
+}
+
+func TestPatternComplexityInvariant(t *testing.T) {
+	t.Run("clamp", func(t *testing.T) {
+		withInvariantMode(t, invariants.ModeClamp)
+		checkClamp(t, "pattern.complexity", patternComplexity, unitInput, unitInput, unitInput, unitInput)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		withInvariantMode(t, invariants.ModeStrict)
+		checkStrict(t, "pattern.complexity", patternComplexity, unitInput, unitInput, unitInput, unitInput)
+	})
+}
+
+// energyFeature 以输入作为模式能量及量子、场贡献提取能量特征
+func energyFeature(inputs []float64) (float64, error) {
+	features, err := extractDynamicFeatures(emergence.EmergentPattern{
+		Energy: inputs[0] * maxEnergyLevel,
+		Properties: map[string]float64{
+			"quantum_energy": inputs[1] * maxEnergyLevel,
+			"field_energy":   inputs[2] * maxEnergyLevel,
+		},
+	})
+	if err != nil {
+		return 0, err
+	}
+	return features["energy"], nil
+}
+
+func TestEnergyFeatureInvariant(t *testing.T) {
+	t.Run("clamp", func(t *testing.T) {
+		withInvariantMode(t, invariants.ModeClamp)
+		checkClamp(t, "pattern.energy", energyFeature, unitInput, unitInput, unitInput)
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		withInvariantMode(t, invariants.ModeStrict)
+		checkStrict(t, "pattern.energy", energyFeature, unitInput, unitInput, unitInput)
+	})
+}
@@ -3,7 +3,6 @@
 package pattern
 
 import (
-	"fmt"
 	"math"
 	"sync"
 	"time"
@@ -20,10 +19,11 @@ type EvolutionMatcher struct {
 
 	// 基础配置
 	config struct {
-		matchThreshold float64 // 匹配阈值
-		evolutionDepth int     // 演化深度
-		adaptiveBias   float64 // 自适应偏差
-		contextWeight  float64 // 上下文权重
+		matchThreshold float64         // 匹配阈值
+		evolutionDepth int             // 演化深度
+		adaptiveBias   float64         // 自适应偏差
+		contextWeight  float64         // 上下文权重
+		namespace      types.Namespace // 所属命名空间
 	}
 
 	// 匹配状态
@@ -38,11 +38,17 @@ type EvolutionMatcher struct {
 			stability     float64
 			changeRate    float64
 		}
+		factorWeights map[string]float64         // 环境因素权重
+		observed      map[string]observedContext // 模式被观测时的环境
 	}
 
 	// 依赖项
 	recognizer *PatternRecognizer
 	matcher    *resonance.PatternMatcher
+	authorizer types.NamespaceAuthorizer // 跨命名空间访问策略
+
+	// 外部环境来源
+	providers environmentProviders
 }
 
 // EvolutionMatch 演化匹配
@@ -104,10 +110,10 @@ func NewEvolutionMatcher(
 	recognizer *PatternRecognizer,
 	config *types.EvolutionConfig) (*EvolutionMatcher, error) {
 	if recognizer == nil {
-		return nil, fmt.Errorf("nil pattern recognizer")
+		return nil, types.NewDomainError(types.DomainPattern, types.ErrInvalid, "nil pattern recognizer", nil)
 	}
 	if config == nil {
-		return nil, fmt.Errorf("nil evolution config")
+		return nil, types.NewDomainError(types.DomainPattern, types.ErrInvalid, "nil evolution config", nil)
 	}
 
 	em := &EvolutionMatcher{
@@ -119,6 +125,7 @@ func NewEvolutionMatcher(
 	em.config.evolutionDepth = config.EvolutionDepth
 	em.config.adaptiveBias = config.AdaptiveBias
 	em.config.contextWeight = config.ContextWeight
+	em.config.namespace = types.DefaultNamespace
 
 	// 初始化状态
 	em.state.matches = make(map[string]*EvolutionMatch)
@@ -129,20 +136,26 @@ func NewEvolutionMatcher(
 		History:     make([]ContextState, 0),
 		Bias:        make(map[string]float64),
 	}
+	em.state.factorWeights = make(map[string]float64)
+	em.state.observed = make(map[string]observedContext)
 
 	return em, nil
 }
 
 // Match 执行演化匹配
 func (em *EvolutionMatcher) Match() error {
+	// 读取外部环境来源, 不阻塞匹配器
+	em.refreshProviders(time.Now())
+
 	em.mu.Lock()
 	defer em.mu.Unlock()
 
 	// 更新上下文
 	em.updateContext()
 
-	// 获取当前模式
-	patterns := em.recognizer.GetPatterns()
+	// 获取当前命名空间可见的模式, 记录其被观测时的环境
+	patterns := em.filterNamespace(em.recognizer.GetPatterns())
+	em.observeContexts(patterns)
 
 	// 执行匹配
 	matches := em.matchPatterns(patterns)
@@ -156,6 +169,63 @@ func (em *EvolutionMatcher) Match() error {
 	return nil
 }
 
+// SetNamespace 设置匹配器所属命名空间
+func (em *EvolutionMatcher) SetNamespace(ns types.Namespace) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.config.namespace = ns
+}
+
+// GetNamespace 获取匹配器所属命名空间
+func (em *EvolutionMatcher) GetNamespace() types.Namespace {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	return em.config.namespace
+}
+
+// SetNamespaceAuthorizer 设置跨命名空间访问策略
+func (em *EvolutionMatcher) SetNamespaceAuthorizer(authorizer types.NamespaceAuthorizer) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.authorizer = authorizer
+}
+
+// ApplyNamespaceConfig 应用命名空间配置
+func (em *EvolutionMatcher) ApplyNamespaceConfig(cfg *types.NamespaceConfig) {
+	if cfg == nil {
+		return
+	}
+
+	em.mu.Lock()
+	defer em.mu.Unlock()
+
+	em.config.namespace = cfg.Name
+	if cfg.Matching.MatchThreshold > 0 {
+		em.config.matchThreshold = cfg.Matching.MatchThreshold
+	}
+}
+
+// filterNamespace 过滤出本命名空间及策略允许的模式
+func (em *EvolutionMatcher) filterNamespace(patterns []*RecognizedPattern) []*RecognizedPattern {
+	visible := make([]*RecognizedPattern, 0, len(patterns))
+	for _, p := range patterns {
+		ns := patternNamespace(p)
+		if ns == em.config.namespace ||
+			(em.authorizer != nil && em.authorizer.Allows(ns, em.config.namespace, types.NamespaceOpPattern)) {
+			visible = append(visible, p)
+		}
+	}
+	return visible
+}
+
+// patternNamespace 获取识别模式所属命名空间
+func patternNamespace(p *RecognizedPattern) types.Namespace {
+	if p != nil && p.Pattern != nil && p.Pattern.Namespace != "" {
+		return types.Namespace(p.Pattern.Namespace)
+	}
+	return types.DefaultNamespace
+}
+
 // matchPatterns 匹配模式
 func (em *EvolutionMatcher) matchPatterns(
 	patterns []*RecognizedPattern) []*EvolutionMatch {
@@ -498,8 +568,9 @@ func (em *EvolutionMatcher) calculateContextSimilarity(source, target *Recognize
 	// 2. 环境因素相似度
 	environmentSimilarity := calculateEnvironmentSimilarity(
 		em.state.context.Environment,
-		source.Context,
-		target.Context)
+		em.patternContext(source),
+		em.patternContext(target),
+		em.state.factorWeights)
 
 	// 3. 状态相关性
 	stateSimilarity := calculateStateSimilarity(source, target)
@@ -518,7 +589,7 @@ func (em *EvolutionMatcher) updateContext() {
 	state := ContextState{
 		Timestamp: currentTime,
 		Factors:   make(map[string]float64),
-		Influence: calculateContextInfluence(em.state.context.Environment),
+		Influence: calculateContextInfluence(em.state.context.Environment, em.state.factorWeights),
 	}
 
 	// 复制当前环境因素
@@ -549,16 +620,15 @@ func (em *EvolutionMatcher) updateEnvironmentFactors() {
 		lastState := em.state.context.History[len(em.state.context.History)-1]
 		em.state.context.Environment["change_rate"] = calculateChangeRate(lastState, em.state.context.Environment)
 	}
+
+	// 外部环境因素
+	em.applyProviderFactors(time.Now())
 }
 
-// calculateContextInfluence 计算上下文影响度
-func calculateContextInfluence(env map[string]float64) float64 {
-	weights := map[string]float64{
-		"time_of_day":    0.1,
-		"activity_level": 0.3,
-		"energy_level":   0.3,
-		"stability":      0.2,
-		"change_rate":    0.1,
+// calculateContextInfluence 计算上下文影响度, weights为空时使用内置因素的权重
+func calculateContextInfluence(env, weights map[string]float64) float64 {
+	if len(weights) == 0 {
+		weights = builtinFactorWeights
 	}
 
 	influence := 0.0
@@ -599,7 +669,7 @@ func (em *EvolutionMatcher) createMatch(
 }
 
 func generateMatchID() string {
-	return fmt.Sprintf("match_%d", time.Now().UnixNano())
+	return core.NewID("match_")
 }
 
 const (
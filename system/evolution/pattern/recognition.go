@@ -3,12 +3,12 @@
 package pattern
 
 import (
-	"fmt"
 	"math"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/common"
 	"github.com/Corphon/daoflow/system/meta/emergence"
@@ -43,6 +43,12 @@ type PatternRecognizer struct {
 	detector         *emergence.PatternDetector    // 模式检测器
 	matcher          *resonance.PatternMatcher     // 模式匹配器
 	amplifier        *resonance.ResonanceAmplifier // 共振放大器
+
+	// 签名比较的相似度度量
+	comparator *SignatureComparator
+
+	// 模式族聚类, 未启用时为nil
+	families *FamilyClusterer
 }
 
 // PatternSignature 模式特征
@@ -101,10 +107,21 @@ type StatPoint struct {
 // NewPatternRecognizer 创建新的模式识别器
 func NewPatternRecognizer(config *types.RecognitionConfig) (*PatternRecognizer, error) {
 	if config == nil {
-		return nil, fmt.Errorf("nil recognition config")
+		return nil, types.NewDomainError(types.DomainPattern, types.ErrInvalid, "nil recognition config", nil)
+	}
+
+	comparator, err := NewSignatureComparator(config.Similarity)
+	if err != nil {
+		return nil, err
 	}
 
-	pr := &PatternRecognizer{}
+	pr := &PatternRecognizer{comparator: comparator}
+	if config.Families.Enabled {
+		if err := validateFamilyConfig(config.Families); err != nil {
+			return nil, err
+		}
+		pr.families = NewFamilyClusterer(config.Families)
+	}
 
 	// 初始化配置
 	pr.config.minConfidence = config.Base.MinConfidence
@@ -130,16 +147,25 @@ func (pr *PatternRecognizer) Recognize() error {
 	defer pr.mu.Unlock()
 
 	// 获取当前模式
+	cycle := time.Now()
 	patterns, err := pr.detector.Detect()
 	if err != nil {
 		return err
 	}
 
 	// 识别新模式
-	newPatterns := pr.recognizeNewPatterns(patterns)
+	newPatterns, err := pr.recognizeNewPatterns(patterns)
+	if err != nil {
+		return err
+	}
 
 	// 更新现有模式
-	pr.updateExistingPatterns(patterns)
+	if err := pr.updateExistingPatterns(patterns); err != nil {
+		return err
+	}
+
+	// 将本周期出现的模式归入模式族
+	pr.clusterPatterns(cycle)
 
 	// 构建模式记忆
 	pr.buildPatternMemory(newPatterns)
@@ -263,18 +289,26 @@ func calculateActiveCount(patterns map[string]*RecognizedPattern) float64 {
 
 // recognizeNewPatterns 识别新模式
 func (pr *PatternRecognizer) recognizeNewPatterns(
-	patterns []emergence.EmergentPattern) []*RecognizedPattern {
+	patterns []emergence.EmergentPattern) ([]*RecognizedPattern, error) {
 
 	newPatterns := make([]*RecognizedPattern, 0)
 
 	for _, pattern := range patterns {
 		// 检查是否是新模式
-		if pr.isKnownPattern(pattern) {
+		known, err := pr.isKnownPattern(pattern)
+		if err != nil {
+			return nil, err
+		}
+		if known {
 			continue
 		}
 
 		// 提取模式特征
-		signature := pr.extractSignature(pattern)
+		signature, err := pr.extractSignature(pattern)
+		if err != nil {
+			return nil, err
+		}
+		pr.comparator.Observe(signature)
 
 		// 评估模式
 		confidence := pr.evaluatePattern(pattern, signature)
@@ -282,10 +316,15 @@ func (pr *PatternRecognizer) recognizeNewPatterns(
 			continue
 		}
 
+		patternType, err := determinePatternType(pattern)
+		if err != nil {
+			return nil, err
+		}
+
 		// 创建新的识别模式
 		recognized := &RecognizedPattern{
 			ID:          generatePatternID(),
-			Type:        determinePatternType(pattern),
+			Type:        patternType,
 			Signature:   signature,
 			Confidence:  confidence,
 			Stability:   calculateInitialStability(pattern),
@@ -293,6 +332,7 @@ func (pr *PatternRecognizer) recognizeNewPatterns(
 			LastSeen:    time.Now(),
 			Occurrences: 1,
 			Evolution:   make([]PatternState, 0),
+			Annotations: pattern.Annotations.Clone(),
 		}
 
 		// 添加到已识别模式
@@ -300,20 +340,26 @@ func (pr *PatternRecognizer) recognizeNewPatterns(
 		newPatterns = append(newPatterns, recognized)
 	}
 
-	return newPatterns
+	return newPatterns, nil
 }
 
 // updateExistingPatterns 更新现有模式
 func (pr *PatternRecognizer) updateExistingPatterns(
-	patterns []emergence.EmergentPattern) {
+	patterns []emergence.EmergentPattern) error {
 
 	for id, recognized := range pr.state.patterns {
 		// 查找匹配的当前模式
 		matched := false
 		for _, pattern := range patterns {
-			if pr.isPatternMatch(recognized, pattern) {
+			match, err := pr.isPatternMatch(recognized, pattern)
+			if err != nil {
+				return err
+			}
+			if match {
 				// 更新模式状态
-				pr.updatePatternState(recognized, pattern)
+				if err := pr.updatePatternState(recognized, pattern); err != nil {
+					return err
+				}
 				matched = true
 				break
 			}
@@ -331,28 +377,37 @@ func (pr *PatternRecognizer) updateExistingPatterns(
 			}
 		}
 	}
+	return nil
 }
 
 // isPatternMatch 检查模式是否匹配
-func (pr *PatternRecognizer) isPatternMatch(recognized *RecognizedPattern, pattern emergence.EmergentPattern) bool {
+func (pr *PatternRecognizer) isPatternMatch(recognized *RecognizedPattern, pattern emergence.EmergentPattern) (bool, error) {
 	// 1. 类型匹配
 	if recognized.Type != pattern.Type {
-		return false
+		return false, nil
 	}
 
 	// 2. 特征相似度
-	signature := pr.extractSignature(pattern)
-	similarity := calculateSignatureSimilarity(recognized.Signature, signature)
+	signature, err := pr.extractSignature(pattern)
+	if err != nil {
+		return false, err
+	}
+	similarity := pr.comparator.Similarity(recognized.Signature, signature)
 
 	// 3. 时间关联性
 	timeDiff := time.Since(recognized.LastSeen)
 	timeCorrelation := math.Exp(-timeDiff.Hours() / 24.0) // 24小时衰减
 
-	return similarity*timeCorrelation >= pr.config.minConfidence
+	return similarity*timeCorrelation >= pr.config.minConfidence, nil
 }
 
 // updatePatternState 更新模式状态
 func (pr *PatternRecognizer) updatePatternState(recognized *RecognizedPattern, pattern emergence.EmergentPattern) error {
+	signature, err := pr.extractSignature(pattern)
+	if err != nil {
+		return err
+	}
+
 	// 如果是新模式,创建一个RecognizedPattern
 	if recognized == nil {
 		recognized = &RecognizedPattern{
@@ -369,7 +424,8 @@ func (pr *PatternRecognizer) updatePatternState(recognized *RecognizedPattern, p
 	recognized.LastSeen = time.Now()
 	recognized.Occurrences++
 	recognized.Active = true
-	recognized.Signature = pr.extractSignature(pattern)
+	recognized.Signature = signature
+	pr.comparator.Observe(recognized.Signature)
 	recognized.Properties = pattern.Properties
 	recognized.Confidence = pr.evaluatePattern(pattern, recognized.Signature)
 
@@ -483,7 +539,7 @@ func (pr *PatternRecognizer) findAssociations(pattern *RecognizedPattern) []stri
 		}
 
 		// 2. 特征相似度关联
-		similarity := calculateSignatureSimilarity(pattern.Signature, other.Signature)
+		similarity := pr.comparator.Similarity(pattern.Signature, other.Signature)
 		if similarity > pr.config.minConfidence {
 			associations = append(associations, id)
 			continue
@@ -511,9 +567,11 @@ func checkEvolutionaryRelation(p1, p2 *RecognizedPattern) bool {
 
 	// 检查状态转换
 	stateTransition := false
-	for _, state1 := range p1.Evolution {
-		for _, state2 := range p2.Evolution {
-			if calculateStateDifference(state1, state2) < 0.3 {
+	flat1 := FlattenStates(p1.Evolution, nil)
+	flat2 := FlattenStates(p2.Evolution, nil)
+	for i := range flat1 {
+		for j := range flat2 {
+			if flat1[i].Difference(&flat2[j]) < 0.3 {
 				stateTransition = true
 				break
 			}
@@ -526,18 +584,22 @@ func checkEvolutionaryRelation(p1, p2 *RecognizedPattern) bool {
 // 辅助函数
 
 func (pr *PatternRecognizer) isKnownPattern(
-	pattern emergence.EmergentPattern) bool {
+	pattern emergence.EmergentPattern) (bool, error) {
 
 	for _, recognized := range pr.state.patterns {
-		if pr.isPatternMatch(recognized, pattern) {
-			return true
+		match, err := pr.isPatternMatch(recognized, pattern)
+		if err != nil {
+			return false, err
+		}
+		if match {
+			return true, nil
 		}
 	}
-	return false
+	return false, nil
 }
 
 func (pr *PatternRecognizer) extractSignature(
-	pattern emergence.EmergentPattern) PatternSignature {
+	pattern emergence.EmergentPattern) (PatternSignature, error) {
 
 	signature := PatternSignature{
 		Components: make([]SignatureComponent, 0),
@@ -567,9 +629,13 @@ func (pr *PatternRecognizer) extractSignature(
 	signature.Structure = extractStructuralFeatures(pattern)
 
 	// 提取动态特征
-	signature.Dynamics = extractDynamicFeatures(pattern)
+	dynamics, err := extractDynamicFeatures(pattern)
+	if err != nil {
+		return PatternSignature{}, err
+	}
+	signature.Dynamics = dynamics
 
-	return signature
+	return signature, nil
 }
 
 func (pr *PatternRecognizer) evaluatePattern(
@@ -676,7 +742,61 @@ func evaluateHierarchy(hierarchy map[string]float64) float64 {
 	return 0
 }
 func generatePatternID() string {
-	return fmt.Sprintf("pat_%d", time.Now().UnixNano())
+	return core.NewID("pat_")
+}
+
+// SetSimilarityMetric 设置签名维度(component、structure、dynamics、context)的相似度度量, nil恢复为HeuristicMetric
+func (pr *PatternRecognizer) SetSimilarityMetric(dimension string, metric SimilarityMetric) error {
+	return pr.comparator.SetMetric(dimension, metric)
+}
+
+// SimilarityMetric 获取签名维度的相似度度量
+func (pr *PatternRecognizer) SimilarityMetric(dimension string) SimilarityMetric {
+	return pr.comparator.Metric(dimension)
+}
+
+// clusterPatterns 将since之后出现的模式归入模式族, 移除已删除模式的成员关系并刷新各模式的族ID
+// 族合并会改变其他模式的族ID, 因此每周期刷新全部模式
+func (pr *PatternRecognizer) clusterPatterns(since time.Time) {
+	if pr.families == nil {
+		return
+	}
+	for _, pattern := range pr.state.patterns {
+		if !pattern.LastSeen.Before(since) {
+			pr.families.Observe(pattern)
+		}
+	}
+	pr.families.Prune(func(id string) bool {
+		_, exists := pr.state.patterns[id]
+		return exists
+	})
+	for id, pattern := range pr.state.patterns {
+		pattern.Family, _ = pr.families.FamilyOf(id)
+	}
+}
+
+// Families 获取模式族统计, 按成员数降序排列; 未启用聚类时为nil
+func (pr *PatternRecognizer) Families() []FamilyStats {
+	return pr.families.Families()
+}
+
+// Family 获取模式族统计
+func (pr *PatternRecognizer) Family(id string) (FamilyStats, bool) {
+	return pr.families.Family(id)
+}
+
+// FamilyPatterns 获取模式族的成员模式
+func (pr *PatternRecognizer) FamilyPatterns(id string) []*RecognizedPattern {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	patterns := make([]*RecognizedPattern, 0)
+	for _, pattern := range pr.state.patterns {
+		if pattern.Family == id && id != "" {
+			patterns = append(patterns, pattern)
+		}
+	}
+	return patterns
 }
 
 // GetPatterns 获取已识别的模式
@@ -704,6 +824,53 @@ func (pr *PatternRecognizer) GetPattern(id string) *RecognizedPattern {
 	return nil
 }
 
+// Tag 合并已识别模式的标签, 值为空的标签表示删除该键
+func (pr *PatternRecognizer) Tag(id string, tags map[string]string) error {
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pattern, exists := pr.state.patterns[id]
+	if !exists {
+		return types.NewDomainError(types.DomainPattern, types.ErrNotFound, "recognized pattern not found", nil).
+			WithContext("pattern", id)
+	}
+	pattern.Annotations = pattern.Annotations.WithTags(tags)
+	return nil
+}
+
+// AddNote 为已识别模式追加自由文本标注
+func (pr *PatternRecognizer) AddNote(id string, note types.Note) error {
+	if note.Text == "" {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "empty note", nil)
+	}
+
+	pr.mu.Lock()
+	defer pr.mu.Unlock()
+
+	pattern, exists := pr.state.patterns[id]
+	if !exists {
+		return types.NewDomainError(types.DomainPattern, types.ErrNotFound, "recognized pattern not found", nil).
+			WithContext("pattern", id)
+	}
+	pattern.Annotations = pattern.Annotations.WithNote(note)
+	return nil
+}
+
+// FindPatterns 满足全部标签条件的已识别模式, 条件为空时返回全部
+func (pr *PatternRecognizer) FindPatterns(tags map[string]string) []*RecognizedPattern {
+	pr.mu.RLock()
+	defer pr.mu.RUnlock()
+
+	patterns := make([]*RecognizedPattern, 0)
+	for _, pattern := range pr.state.patterns {
+		if pattern.Annotations.MatchTags(tags) {
+			patterns = append(patterns, pattern)
+		}
+	}
+	sort.Slice(patterns, func(i, j int) bool { return patterns[i].ID < patterns[j].ID })
+	return patterns
+}
+
 // GetActivationLevel 获取模式激活水平
 func (rp *RecognizedPattern) GetActivationLevel() float64 {
 	if !rp.Active {
@@ -724,13 +891,24 @@ func (rp *RecognizedPattern) GetActivationLevel() float64 {
 	return (activation*0.5 + usageScore*0.3) * timeDecay
 }
 
+// GetEntropyMetrics 获取原始模式的信息论指标, 无原始模式时返回零值
+func (rp *RecognizedPattern) GetEntropyMetrics() emergence.EntropyMetrics {
+	if rp.Pattern == nil {
+		return emergence.EntropyMetrics{}
+	}
+	return rp.Pattern.GetEntropyMetrics()
+}
+
 // DetectPattern 检测输入数据中的模式
 func (pr *PatternRecognizer) DetectPattern(data interface{}) (*model.FlowPattern, error) {
 	pr.mu.Lock()
 	defer pr.mu.Unlock()
 
 	// 1. 转换输入数据为特征向量
-	features := extractFeatureV(data)
+	features, err := extractFeatureV(data)
+	if err != nil {
+		return nil, err
+	}
 
 	// 2. 检测模式
 	patterns := make([]*RecognizedPattern, 0)
@@ -750,13 +928,13 @@ func (pr *PatternRecognizer) DetectPattern(data interface{}) (*model.FlowPattern
 }
 
 // extractFeatureVector 提取特征向量
-func extractFeatureV(data interface{}) map[string]float64 {
+func extractFeatureV(data interface{}) (map[string]float64, error) {
 	features := make(map[string]float64)
 
 	switch v := data.(type) {
 	case *emergence.EmergentPattern:
 		// 从EmergentPattern提取
-		features = extractFeatureVector(v)
+		return extractFeatureVector(v)
 	case map[string]interface{}:
 		// 从map提取
 		for k, val := range v {
@@ -766,7 +944,7 @@ func extractFeatureV(data interface{}) map[string]float64 {
 		}
 	}
 
-	return features
+	return features, nil
 }
 
 // matchFeatures 匹配特征
@@ -852,7 +1030,10 @@ func (pr *PatternRecognizer) AnalyzePattern(pattern *model.FlowPattern) error {
 	emergentPattern := convertFlowToEmergentPattern(pattern)
 
 	// 2. 提取模式特征
-	signature := pr.extractSignature(emergentPattern)
+	signature, err := pr.extractSignature(emergentPattern)
+	if err != nil {
+		return err
+	}
 
 	// 3. 评估模式
 	confidence := pr.evaluatePattern(emergentPattern, signature)
@@ -876,7 +1057,7 @@ func (pr *PatternRecognizer) AnalyzePattern(pattern *model.FlowPattern) error {
 
 		// 更新状态
 		if err := pr.updatePatternState(recognized, emergentPattern); err != nil {
-			return fmt.Errorf("failed to update pattern state: %w", err)
+			return types.NewDomainError(types.DomainPattern, types.ErrState, "failed to update pattern state", err)
 		}
 	}
 
@@ -0,0 +1,467 @@
+// system/evolution/pattern/similarity.go
+
+package pattern
+
+import (
+	"math"
+	"sort"
+	"sync"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// SignatureDimensions 签名的比较维度, 与相似度因子同名
+var SignatureDimensions = []string{FactorComponent, FactorStructure, FactorDynamics, FactorContext}
+
+// 默认参数
+const (
+	defaultMahalanobisWindow = 500
+	mahalanobisRidge         = 1e-3 // 协方差对角线的正则项, 避免奇异
+)
+
+// SimilarityMetric 签名单个维度的相似度度量, 返回[0,1], 须并发安全
+type SimilarityMetric interface {
+	Name() string
+	Similarity(dimension string, sig1, sig2 PatternSignature) float64
+}
+
+// LearningMetric 从识别到的签名学习参数的度量, 识别器每次提取签名时调用Observe
+type LearningMetric interface {
+	SimilarityMetric
+	Observe(dimension string, sig PatternSignature)
+}
+
+// SignatureVector 签名维度的特征向量
+// 组件按类型和角色聚合为权重之和及按权重加权的属性值之和; 字符串值以"键=值"为特征, 取值为1
+func SignatureVector(dimension string, sig PatternSignature) map[string]float64 {
+	vector := make(map[string]float64)
+	switch dimension {
+	case FactorComponent:
+		for _, c := range sig.Components {
+			key := c.Type + ":" + c.Role
+			vector[key] += c.Weight
+			for name, value := range c.Properties {
+				vector[key+"/"+name] += c.Weight * value
+			}
+		}
+	case FactorStructure:
+		for key, value := range sig.Structure {
+			switch v := value.(type) {
+			case float64:
+				vector[key] = v
+			case string:
+				vector[key+"="+v] = 1
+			}
+		}
+	case FactorDynamics:
+		for key, value := range sig.Dynamics {
+			vector[key] = value
+		}
+	case FactorContext:
+		for key, value := range sig.Context {
+			vector[key+"="+value] = 1
+		}
+	}
+	return vector
+}
+
+// HeuristicMetric 原有的规则相似度: 组件按类型、权重、属性、角色和连接加权, 其余维度按共同键比较
+type HeuristicMetric struct{}
+
+// Name 度量名称
+func (HeuristicMetric) Name() string { return types.SimilarityHeuristic }
+
+// Similarity 计算维度相似度
+func (HeuristicMetric) Similarity(dimension string, sig1, sig2 PatternSignature) float64 {
+	switch dimension {
+	case FactorComponent:
+		return calculateComponentsSimilarity(sig1.Components, sig2.Components)
+	case FactorStructure:
+		return calculateStructureMapSimilarity(sig1.Structure, sig2.Structure)
+	case FactorDynamics:
+		return calculatePropertySimilarity(sig1.Dynamics, sig2.Dynamics)
+	case FactorContext:
+		return calculateContextMapSimilarity(sig1.Context, sig2.Context)
+	default:
+		return 0
+	}
+}
+
+// CosineMetric 特征向量的余弦相似度, 负值取0
+type CosineMetric struct{}
+
+// Name 度量名称
+func (CosineMetric) Name() string { return types.SimilarityCosine }
+
+// Similarity 计算维度相似度, 任一方无特征时为0
+func (CosineMetric) Similarity(dimension string, sig1, sig2 PatternSignature) float64 {
+	return cosineVectors(SignatureVector(dimension, sig1), SignatureVector(dimension, sig2))
+}
+
+// EuclideanMetric 加权欧氏距离d换算的相似度1/(1+d)
+type EuclideanMetric struct {
+	Weights map[string]float64 // 特征权重, 未列出的特征权重为1
+}
+
+// Name 度量名称
+func (EuclideanMetric) Name() string { return types.SimilarityEuclidean }
+
+// Similarity 计算维度相似度, 任一方无特征时为0
+func (m EuclideanMetric) Similarity(dimension string, sig1, sig2 PatternSignature) float64 {
+	v1, v2 := SignatureVector(dimension, sig1), SignatureVector(dimension, sig2)
+	if len(v1) == 0 || len(v2) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, key := range unionKeys(v1, v2) {
+		weight, exists := m.Weights[key]
+		if !exists {
+			weight = 1
+		}
+		d := v1[key] - v2[key]
+		sum += weight * d * d
+	}
+	return 1 / (1 + math.Sqrt(sum))
+}
+
+// MahalanobisMetric 马氏距离d换算的相似度1/(1+d), 各维度以近期签名的特征向量学习协方差
+// 样本不足两个时退化为欧氏距离; 未在样本中出现的特征按单位方差计
+type MahalanobisMetric struct {
+	mu sync.Mutex
+
+	window     int
+	dimensions map[string]*covarianceWindow
+}
+
+// covarianceWindow 单个维度的近期特征向量及协方差逆矩阵
+type covarianceWindow struct {
+	samples []map[string]float64 // 环形缓冲
+	next    int
+	dirty   bool
+
+	index   map[string]int // 特征在矩阵中的位置
+	inverse [][]float64
+}
+
+// NewMahalanobisMetric 创建马氏距离度量, window为每个维度保留的近期签名数
+func NewMahalanobisMetric(window int) *MahalanobisMetric {
+	if window <= 0 {
+		window = defaultMahalanobisWindow
+	}
+	return &MahalanobisMetric{
+		window:     window,
+		dimensions: make(map[string]*covarianceWindow),
+	}
+}
+
+// Name 度量名称
+func (m *MahalanobisMetric) Name() string { return types.SimilarityMahalanobis }
+
+// Observe 记录签名的特征向量
+func (m *MahalanobisMetric) Observe(dimension string, sig PatternSignature) {
+	vector := SignatureVector(dimension, sig)
+	if len(vector) == 0 {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w, exists := m.dimensions[dimension]
+	if !exists {
+		w = &covarianceWindow{}
+		m.dimensions[dimension] = w
+	}
+	if len(w.samples) < m.window {
+		w.samples = append(w.samples, vector)
+	} else {
+		w.samples[w.next] = vector
+	}
+	w.next = (w.next + 1) % m.window
+	w.dirty = true
+}
+
+// Similarity 计算维度相似度, 任一方无特征时为0
+func (m *MahalanobisMetric) Similarity(dimension string, sig1, sig2 PatternSignature) float64 {
+	v1, v2 := SignatureVector(dimension, sig1), SignatureVector(dimension, sig2)
+	if len(v1) == 0 || len(v2) == 0 {
+		return 0
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	w := m.dimensions[dimension]
+	if w != nil && w.dirty {
+		w.fit()
+	}
+
+	// 已学习的特征按协方差逆矩阵计算, 其余特征按单位方差计
+	learned := w != nil && w.inverse != nil
+	sum := 0.0
+	for _, key := range unionKeys(v1, v2) {
+		if learned {
+			if _, exists := w.index[key]; exists {
+				continue
+			}
+		}
+		d := v1[key] - v2[key]
+		sum += d * d
+	}
+	if learned {
+		diff := make([]float64, len(w.index))
+		for key, i := range w.index {
+			diff[i] = v1[key] - v2[key]
+		}
+		for i, row := range w.inverse {
+			for j, value := range row {
+				sum += diff[i] * value * diff[j]
+			}
+		}
+	}
+	return 1 / (1 + math.Sqrt(math.Max(0, sum)))
+}
+
+// fit 按近期样本重新计算协方差逆矩阵, 样本不足或矩阵奇异时清空
+func (w *covarianceWindow) fit() {
+	w.dirty = false
+	w.index, w.inverse = nil, nil
+	if len(w.samples) < 2 {
+		return
+	}
+
+	keys := make([]string, 0)
+	seen := make(map[string]bool)
+	for _, sample := range w.samples {
+		for key := range sample {
+			if !seen[key] {
+				seen[key] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+
+	k := len(keys)
+	n := float64(len(w.samples))
+	mean := make([]float64, k)
+	for i, key := range keys {
+		for _, sample := range w.samples {
+			mean[i] += sample[key]
+		}
+		mean[i] /= n
+	}
+
+	cov := make([][]float64, k)
+	for i := range cov {
+		cov[i] = make([]float64, k)
+	}
+	for _, sample := range w.samples {
+		for i, ki := range keys {
+			di := sample[ki] - mean[i]
+			for j := i; j < k; j++ {
+				cov[i][j] += di * (sample[keys[j]] - mean[j])
+			}
+		}
+	}
+	for i := 0; i < k; i++ {
+		for j := i; j < k; j++ {
+			cov[i][j] /= n - 1
+			cov[j][i] = cov[i][j]
+		}
+		cov[i][i] += mahalanobisRidge
+	}
+
+	inverse, ok := invertMatrix(cov)
+	if !ok {
+		return
+	}
+	w.index = make(map[string]int, k)
+	for i, key := range keys {
+		w.index[key] = i
+	}
+	w.inverse = inverse
+}
+
+// invertMatrix 以部分主元高斯-约当消元求逆矩阵, 奇异时返回false
+func invertMatrix(m [][]float64) ([][]float64, bool) {
+	k := len(m)
+	a := make([][]float64, k)
+	for i := range m {
+		a[i] = make([]float64, 2*k)
+		copy(a[i], m[i])
+		a[i][k+i] = 1
+	}
+
+	for col := 0; col < k; col++ {
+		pivot := col
+		for r := col + 1; r < k; r++ {
+			if math.Abs(a[r][col]) > math.Abs(a[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return nil, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		p := a[col][col]
+		for c := range a[col] {
+			a[col][c] /= p
+		}
+		for r := 0; r < k; r++ {
+			if r == col || a[r][col] == 0 {
+				continue
+			}
+			f := a[r][col]
+			for c := range a[r] {
+				a[r][c] -= f * a[col][c]
+			}
+		}
+	}
+
+	inverse := make([][]float64, k)
+	for i := range a {
+		inverse[i] = a[i][k:]
+	}
+	return inverse, true
+}
+
+// unionKeys 两个向量的特征并集, 按名称排序
+func unionKeys(v1, v2 map[string]float64) []string {
+	keys := make([]string, 0, len(v1)+len(v2))
+	for key := range v1 {
+		keys = append(keys, key)
+	}
+	for key := range v2 {
+		if _, exists := v1[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// SignatureComparator 按维度选择度量比较签名, 未设置的维度使用HeuristicMetric
+type SignatureComparator struct {
+	mu sync.RWMutex
+
+	metrics map[string]SimilarityMetric
+}
+
+// NewSignatureComparator 按配置创建签名比较器
+func NewSignatureComparator(cfg types.SignatureSimilarityConfig) (*SignatureComparator, error) {
+	c := &SignatureComparator{metrics: make(map[string]SimilarityMetric)}
+
+	var mahalanobis *MahalanobisMetric
+	for dimension, name := range cfg.Metrics {
+		var metric SimilarityMetric
+		switch name {
+		case "", types.SimilarityHeuristic:
+			metric = HeuristicMetric{}
+		case types.SimilarityCosine:
+			metric = CosineMetric{}
+		case types.SimilarityEuclidean:
+			metric = EuclideanMetric{Weights: cfg.Weights}
+		case types.SimilarityMahalanobis:
+			// 各维度共用一个度量, 协方差按维度分别学习
+			if mahalanobis == nil {
+				mahalanobis = NewMahalanobisMetric(cfg.Window)
+			}
+			metric = mahalanobis
+		default:
+			return nil, types.NewDomainError(types.DomainPattern, types.ErrInvalid, "unknown similarity metric", nil).
+				WithContext("dimension", dimension).
+				WithContext("metric", name)
+		}
+		if err := c.SetMetric(dimension, metric); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// SetMetric 设置维度的相似度度量, nil恢复为HeuristicMetric
+func (c *SignatureComparator) SetMetric(dimension string, metric SimilarityMetric) error {
+	if !isSignatureDimension(dimension) {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "unknown signature dimension", nil).
+			WithContext("dimension", dimension)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if metric == nil {
+		delete(c.metrics, dimension)
+		return nil
+	}
+	c.metrics[dimension] = metric
+	return nil
+}
+
+// Metric 获取维度的相似度度量
+func (c *SignatureComparator) Metric(dimension string) SimilarityMetric {
+	if c == nil {
+		return HeuristicMetric{}
+	}
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if metric, exists := c.metrics[dimension]; exists {
+		return metric
+	}
+	return HeuristicMetric{}
+}
+
+// Similarity 计算签名相似度, 各维度按相似度因子权重加权
+func (c *SignatureComparator) Similarity(sig1, sig2 PatternSignature) float64 {
+	similarity := 0.0
+	for _, dimension := range SignatureDimensions {
+		similarity += c.Metric(dimension).Similarity(dimension, sig1, sig2) * factorWeight(dimension)
+	}
+	return similarity
+}
+
+// Explain 分解签名相似度, 因子相似度按各维度的度量计算
+func (c *SignatureComparator) Explain(sig1, sig2 PatternSignature) SimilarityExplanation {
+	factors := make([]FactorContribution, 0, len(SignatureDimensions))
+	for _, dimension := range SignatureDimensions {
+		metric := c.Metric(dimension)
+		factor := newFactorContribution(dimension, metric.Similarity(dimension, sig1, sig2), factorWeight(dimension))
+		factor.Metric = metric.Name()
+		factors = append(factors, factor)
+	}
+	return explainFactors(factors, sig1, sig2)
+}
+
+// Observe 将签名提供给各维度的学习型度量
+func (c *SignatureComparator) Observe(sig PatternSignature) {
+	for _, dimension := range SignatureDimensions {
+		if learner, ok := c.Metric(dimension).(LearningMetric); ok {
+			learner.Observe(dimension, sig)
+		}
+	}
+}
+
+// isSignatureDimension 是否为签名维度
+func isSignatureDimension(dimension string) bool {
+	for _, d := range SignatureDimensions {
+		if d == dimension {
+			return true
+		}
+	}
+	return false
+}
+
+// factorWeight 相似度因子的权重
+func factorWeight(dimension string) float64 {
+	switch dimension {
+	case FactorComponent:
+		return componentFactorWeight
+	case FactorStructure:
+		return structureFactorWeight
+	case FactorDynamics:
+		return dynamicsFactorWeight
+	case FactorContext:
+		return contextFactorWeight
+	default:
+		return 0
+	}
+}
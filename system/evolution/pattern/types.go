@@ -7,6 +7,7 @@ import (
 
 	"github.com/Corphon/daoflow/system/common"
 	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
 )
 
 // 确保实现了 SharedPattern 接口
@@ -36,6 +37,10 @@ type RecognizedPattern struct {
 	LastSeen    time.Time // 最后发现时间
 	Occurrences int       // 出现次数
 	Strength    float64
+
+	Annotations types.Annotations // 用户标注, 识别时继承原始模式的标注
+
+	Family string // 所属模式族ID, 未启用聚类时为空
 }
 
 // PatternState 模式状态
@@ -0,0 +1,347 @@
+// system/evolution/wuxing/scheduler.go
+
+package wuxing
+
+import (
+	"context"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultPeriod         = 10 * time.Second
+	defaultGenerationRate = 0.1
+	defaultOvercomingRate = 0.05
+	defaultMaxBias        = 4.0
+	maxRouteHistory       = 50
+)
+
+// Target 被调度的五行能量载体
+type Target interface {
+	ElementEnergies() map[model.WuXingElement]float64
+	ApplyElementFlows(flows []model.ElementFlow) error
+}
+
+// Bias 路由权重偏置
+// 在有效期内将指定元素在指定循环上的路由量乘以Factor, Factor为0时暂停该路由
+type Bias struct {
+	ID      string              `json:"id"`
+	Source  string              `json:"source"` // 偏置来源, 如涌现模式ID
+	Element model.WuXingElement `json:"element"`
+	Cycle   model.CycleType     `json:"cycle"`
+	Factor  float64             `json:"factor"`
+	Expires time.Time           `json:"expires"` // 零值表示不过期
+}
+
+// BiasSource 偏置来源, 每步调度前调用, 返回的偏置只作用于本步
+type BiasSource func(energies map[model.WuXingElement]float64) []Bias
+
+// Route 一次调度结果
+type Route struct {
+	Timestamp time.Time           `json:"timestamp"`
+	Element   model.WuXingElement `json:"element"` // 本步调度的源元素
+	Flows     []model.ElementFlow `json:"flows"`
+	Weights   map[string]float64  `json:"weights"` // 按循环的生效权重
+	Capped    bool                `json:"capped"`  // 是否受元素上限限制
+	Error     string              `json:"error,omitempty"`
+}
+
+// Scheduler 五行能量调度器
+// 按相生(木火土金水)顺序轮转, 每步将当前元素的能量按相生循环转入其子元素,
+// 并按相克循环克制其所克元素; 路由量受元素上限约束, 可由偏置临时调整
+type Scheduler struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.WuXingSchedulerConfig
+
+	// 调度对象
+	target Target
+
+	// 偏置
+	biases  map[string]Bias
+	sources map[string]BiasSource
+
+	// 调度状态
+	state struct {
+		position int // 下一步调度的元素在相生顺序中的位置
+		routes   []Route
+		steps    int64
+		routed   float64 // 累计相生转移量
+		overcome float64 // 累计相克量
+		capped   int64
+		failures int64
+	}
+}
+
+// NewScheduler 创建五行能量调度器
+func NewScheduler(config types.WuXingSchedulerConfig) *Scheduler {
+	if config.Period <= 0 {
+		config.Period = defaultPeriod
+	}
+	if config.GenerationRate <= 0 || config.GenerationRate >= 1 {
+		config.GenerationRate = defaultGenerationRate
+	}
+	if config.OvercomingRate <= 0 || config.OvercomingRate >= 1 {
+		config.OvercomingRate = defaultOvercomingRate
+	}
+	if config.DefaultCap <= 0 || config.DefaultCap > model.MaxWuXingElementEnergy {
+		config.DefaultCap = model.MaxWuXingElementEnergy
+	}
+	if config.MaxBias <= 0 {
+		config.MaxBias = defaultMaxBias
+	}
+
+	s := &Scheduler{
+		config:  config,
+		biases:  make(map[string]Bias),
+		sources: make(map[string]BiasSource),
+	}
+	s.state.routes = make([]Route, 0)
+	return s
+}
+
+// SetTarget 设置调度对象
+func (s *Scheduler) SetTarget(target Target) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.target = target
+}
+
+// AddBias 添加路由偏置, 同ID覆盖
+func (s *Scheduler) AddBias(bias Bias) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.biases[bias.ID] = bias
+}
+
+// RemoveBias 移除路由偏置
+func (s *Scheduler) RemoveBias(id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.biases, id)
+}
+
+// RegisterBiasSource 注册偏置来源, 同名覆盖, 传入nil移除
+func (s *Scheduler) RegisterBiasSource(name string, source BiasSource) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if source == nil {
+		delete(s.sources, name)
+		return
+	}
+	s.sources[name] = source
+}
+
+// Biases 获取仍在有效期内的偏置, 按ID排序
+func (s *Scheduler) Biases() []Bias {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	now := time.Now()
+	list := make([]Bias, 0, len(s.biases))
+	for _, b := range s.biases {
+		if b.Expires.IsZero() || now.Before(b.Expires) {
+			list = append(list, b)
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].ID < list[j].ID
+	})
+	return list
+}
+
+// Interval 每步调度间隔, 一个周期内五个元素各调度一次
+func (s *Scheduler) Interval() time.Duration {
+	return s.config.Period / time.Duration(len(model.WuXingSequence))
+}
+
+// Run 按调度间隔运行, 直到上下文取消
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval())
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			s.Step()
+		}
+	}
+}
+
+// Start 在协程监管下启动调度循环
+func (s *Scheduler) Start(ctx context.Context) {
+	supervisor.Go(ctx, "evolution.wuxing", s.Run)
+}
+
+// Step 调度一个元素, 未设置调度对象时返回nil
+func (s *Scheduler) Step() (*Route, error) {
+	s.mu.RLock()
+	target := s.target
+	sources := make([]BiasSource, 0, len(s.sources))
+	for _, src := range s.sources {
+		sources = append(sources, src)
+	}
+	s.mu.RUnlock()
+	if target == nil {
+		return nil, nil
+	}
+
+	// 在锁外读取能量和偏置来源, 二者可能需要获取其他组件的锁
+	energies := target.ElementEnergies()
+	transient := make([]Bias, 0)
+	for _, src := range sources {
+		transient = append(transient, src(energies)...)
+	}
+
+	s.mu.Lock()
+	elem := model.WuXingSequence[s.state.position]
+	s.state.position = (s.state.position + 1) % len(model.WuXingSequence)
+	s.expireBiases()
+	route := s.plan(elem, energies, transient)
+	s.mu.Unlock()
+
+	var err error
+	if len(route.Flows) > 0 {
+		if err = target.ApplyElementFlows(route.Flows); err != nil {
+			route.Error = err.Error()
+			err = types.NewDomainError(types.DomainEvolution, types.ErrState, "failed to route wuxing energy", err).
+				WithContext("element", elem.String())
+		}
+	}
+
+	s.mu.Lock()
+	s.state.steps++
+	if route.Capped {
+		s.state.capped++
+	}
+	if err != nil {
+		s.state.failures++
+	} else {
+		for _, f := range route.Flows {
+			if f.Cycle == model.GeneratingCycle {
+				s.state.routed += f.Amount
+			} else {
+				s.state.overcome += f.Amount
+			}
+		}
+	}
+	s.state.routes = append(s.state.routes, route)
+	if over := len(s.state.routes) - maxRouteHistory; over > 0 {
+		s.state.routes = s.state.routes[over:]
+	}
+	s.mu.Unlock()
+
+	return &route, err
+}
+
+// Routes 获取最近的调度记录, 按时间排序
+func (s *Scheduler) Routes() []Route {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append([]Route(nil), s.state.routes...)
+}
+
+// GetMetrics 获取调度指标
+func (s *Scheduler) GetMetrics() map[string]float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return map[string]float64{
+		"steps":     float64(s.state.steps),
+		"routed":    s.state.routed,
+		"overcome":  s.state.overcome,
+		"capped":    float64(s.state.capped),
+		"failures":  float64(s.state.failures),
+		"biases":    float64(len(s.biases)),
+		"period_ms": float64(s.config.Period) / float64(time.Millisecond),
+	}
+}
+
+// plan 计算元素的相生和相克路由, 调用方需持有锁
+func (s *Scheduler) plan(elem model.WuXingElement, energies map[model.WuXingElement]float64, transient []Bias) Route {
+	route := Route{
+		Timestamp: time.Now(),
+		Element:   elem,
+		Flows:     make([]model.ElementFlow, 0, 2),
+		Weights:   make(map[string]float64, 2),
+	}
+	source := energies[elem]
+	if source <= 0 {
+		return route
+	}
+
+	// 相生: 源元素能量转入子元素, 不超过子元素上限
+	genWeight := s.weight(elem, model.GeneratingCycle, transient)
+	route.Weights["generating"] = genWeight
+	child := elem.Generates()
+	amount := source * s.config.GenerationRate * genWeight
+	if room := s.capOf(child) - energies[child]; amount > room {
+		amount = math.Max(0, room)
+		route.Capped = true
+	}
+	if amount > 0 {
+		route.Flows = append(route.Flows, model.ElementFlow{
+			From: elem, To: child, Amount: amount, Cycle: model.GeneratingCycle,
+		})
+		source -= amount
+	}
+
+	// 相克: 克制所克元素, 源元素按ConstraintCost消耗
+	overWeight := s.weight(elem, model.ConstrainingCycle, transient)
+	route.Weights["overcoming"] = overWeight
+	victim := elem.Overcomes()
+	amount = source * s.config.OvercomingRate * overWeight
+	amount = math.Min(amount, energies[victim]/model.ConstraintRatio)
+	amount = math.Min(amount, source/model.ConstraintCost)
+	if amount > 0 {
+		route.Flows = append(route.Flows, model.ElementFlow{
+			From: elem, To: victim, Amount: amount, Cycle: model.ConstrainingCycle,
+		})
+	}
+	return route
+}
+
+// weight 元素在循环上的路由权重, 为所有生效偏置之积, 限制在[0, MaxBias]内, 调用方需持有锁
+func (s *Scheduler) weight(elem model.WuXingElement, cycle model.CycleType, transient []Bias) float64 {
+	w := 1.0
+	apply := func(b Bias) {
+		if b.Element == elem && b.Cycle == cycle {
+			w *= math.Max(0, b.Factor)
+		}
+	}
+	for _, b := range s.biases {
+		apply(b)
+	}
+	for _, b := range transient {
+		apply(b)
+	}
+	return math.Min(w, s.config.MaxBias)
+}
+
+// capOf 元素能量上限, 调用方需持有锁
+func (s *Scheduler) capOf(elem model.WuXingElement) float64 {
+	if c, ok := s.config.Caps[elem.String()]; ok && c > 0 {
+		return math.Min(c, model.MaxWuXingElementEnergy)
+	}
+	return s.config.DefaultCap
+}
+
+// expireBiases 清理过期偏置, 调用方需持有锁
+func (s *Scheduler) expireBiases() {
+	now := time.Now()
+	for id, b := range s.biases {
+		if !b.Expires.IsZero() && !now.Before(b.Expires) {
+			delete(s.biases, id)
+		}
+	}
+}
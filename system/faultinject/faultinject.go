@@ -0,0 +1,198 @@
+// system/faultinject/faultinject.go
+
+// Package faultinject 在预定义的注入点注入故障, 用于确定性地验证协程监管、看门狗等韧性机制
+// 注入需要以faultinject构建标签编译, 或经配置/Enable显式允许; 未注入故障时注入点只有一次原子读取
+package faultinject
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 注入点
+const (
+	PointSubsystemStart = "subsystem.start"    // 子系统启动, Target为子系统名称
+	PointEventQueue     = "system.event_queue" // 事件入队, 返回错误时视为队列已满, Target为事件类型
+	PointDetect         = "emergence.detect"   // 模式检测
+	PointFieldGetState  = "field.get_state"    // 统一场状态读取
+)
+
+// ErrInjected 注入的错误均包装此错误
+var ErrInjected = errors.New("injected fault")
+
+// Fault 注入点上的故障
+type Fault = types.FaultSpec
+
+// Status 已注入故障的状态
+type Status struct {
+	ID    string `json:"id"`
+	Fault Fault  `json:"fault"`
+	Hits  int    `json:"hits"`  // 命中注入点的次数
+	Fired int    `json:"fired"` // 实际触发的次数
+}
+
+// registry 故障注册表
+type registry struct {
+	mu      sync.Mutex
+	enabled bool
+	nextID  int
+	faults  []*Status // 按注入顺序
+}
+
+var (
+	reg   = &registry{}
+	armed atomic.Bool // 存在已注入的故障
+)
+
+// Enable 允许注入故障
+func Enable() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.enabled = true
+}
+
+// Disable 禁止注入故障并清除已注入的故障
+func Disable() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.enabled = false
+	reg.faults = nil
+	armed.Store(false)
+}
+
+// Enabled 是否允许注入故障
+func Enabled() bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	return buildEnabled || reg.enabled
+}
+
+// Configure 按配置允许注入并注入配置中的故障
+func Configure(config types.FaultInjectionConfig) error {
+	if config.Enabled {
+		Enable()
+	}
+	for _, fault := range config.Faults {
+		if _, err := Inject(fault); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Inject 注入故障, 返回故障ID
+func Inject(fault Fault) (string, error) {
+	if fault.Point == "" {
+		return "", types.NewSystemError(types.ErrValidation, "fault point is required", nil)
+	}
+	if fault.Error == "" && fault.Delay <= 0 && !fault.Panic {
+		return "", types.NewSystemError(types.ErrValidation, "fault has no effect", nil).
+			WithContext("point", fault.Point)
+	}
+	if fault.After < 0 || fault.Count < 0 {
+		return "", types.NewSystemError(types.ErrValidation, "negative fault after or count", nil).
+			WithContext("point", fault.Point)
+	}
+
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	if !buildEnabled && !reg.enabled {
+		return "", types.NewSystemError(types.ErrState, "fault injection disabled", nil).
+			WithContext("point", fault.Point)
+	}
+	reg.nextID++
+	id := "fault_" + strconv.Itoa(reg.nextID)
+	reg.faults = append(reg.faults, &Status{ID: id, Fault: fault})
+	armed.Store(true)
+	return id, nil
+}
+
+// Remove 移除故障
+func Remove(id string) bool {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	for i, f := range reg.faults {
+		if f.ID == id {
+			reg.faults = append(reg.faults[:i], reg.faults[i+1:]...)
+			armed.Store(len(reg.faults) > 0)
+			return true
+		}
+	}
+	return false
+}
+
+// Reset 清除所有故障
+func Reset() {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+	reg.faults = nil
+	armed.Store(false)
+}
+
+// Faults 获取已注入故障的状态, 按注入顺序
+func Faults() []Status {
+	reg.mu.Lock()
+	defer reg.mu.Unlock()
+
+	list := make([]Status, 0, len(reg.faults))
+	for _, f := range reg.faults {
+		list = append(list, *f)
+	}
+	return list
+}
+
+// Fire 命中注入点, 按注入顺序施加匹配故障的延迟, 之后panic或返回第一个错误
+// 延迟在注册表锁外执行; 调用方持有自身的锁时延迟会延长持锁时间
+func Fire(point, target string) error {
+	if !armed.Load() {
+		return nil
+	}
+
+	var (
+		delay    time.Duration
+		panicked *Status
+		failed   *Status
+	)
+	reg.mu.Lock()
+	for _, s := range reg.faults {
+		if s.Fault.Point != point || (s.Fault.Target != "" && s.Fault.Target != target) {
+			continue
+		}
+		s.Hits++
+		if s.Hits <= s.Fault.After || (s.Fault.Count > 0 && s.Fired >= s.Fault.Count) {
+			continue
+		}
+		s.Fired++
+		delay += s.Fault.Delay
+		snapshot := *s
+		if s.Fault.Panic && panicked == nil {
+			panicked = &snapshot
+		}
+		if s.Fault.Error != "" && failed == nil {
+			failed = &snapshot
+		}
+	}
+	reg.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	if panicked != nil {
+		panic(fmt.Sprintf("faultinject: %s at %s %s", panicked.ID, point, target))
+	}
+	if failed != nil {
+		return types.NewSystemError(types.ErrInternal, failed.Fault.Error, ErrInjected).
+			WithContext("fault", failed.ID).
+			WithContext("point", point).
+			WithContext("target", target)
+	}
+	return nil
+}
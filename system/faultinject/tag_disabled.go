@@ -0,0 +1,8 @@
+//go:build !faultinject
+
+// system/faultinject/tag_disabled.go
+
+package faultinject
+
+// buildEnabled 未以faultinject构建标签编译时需经配置或Enable允许注入
+const buildEnabled = false
@@ -0,0 +1,8 @@
+//go:build faultinject
+
+// system/faultinject/tag_enabled.go
+
+package faultinject
+
+// buildEnabled 以faultinject构建标签编译时总是允许注入
+const buildEnabled = true
@@ -0,0 +1,16 @@
+// system/features.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+)
+
+// FeatureReport 获取适应学习模型的特征报告, 每个模型列出前top个重要特征
+func (s *System) FeatureReport(top int) []adaptation.FeatureReport {
+	learning := s.evolution.GetLearning()
+	if learning == nil {
+		return nil
+	}
+	return learning.GetFeatureReport(top)
+}
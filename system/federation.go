@@ -0,0 +1,28 @@
+// system/federation.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/meta"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Fields 列出多场联邦的成员场, 成员场通过CreateNamespace创建
+func (s *System) Fields() []meta.FieldInfo {
+	return s.meta.Fields().List()
+}
+
+// LinkFields 定义两个命名空间场域之间的耦合, 返回耦合ID
+func (s *System) LinkFields(cfg types.FieldLinkConfig) (string, error) {
+	return s.meta.Fields().Link(cfg)
+}
+
+// UnlinkFields 移除场间耦合
+func (s *System) UnlinkFields(id string) error {
+	return s.meta.Fields().Unlink(id)
+}
+
+// FieldLinks 获取场间耦合状态
+func (s *System) FieldLinks() []meta.FieldLinkStatus {
+	return s.meta.Fields().Links()
+}
@@ -0,0 +1,101 @@
+// system/governor.go
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/Corphon/daoflow/system/control/governor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// startGovernor 配置了资源预算时统计检测和因果发现循环的执行耗时并按预算节流, 调用方需持有锁
+func (s *System) startGovernor() {
+	if s.config.Governor == nil {
+		return
+	}
+
+	s.stopGovernor()
+	g := governor.New(*s.config.Governor)
+
+	if detector := s.meta.GetDetector(); detector != nil {
+		g.Register(types.GovernedDetection, "meta", func() int64 {
+			var bytes int64
+			for _, usage := range detector.MemoryUsage() {
+				bytes += usage.Bytes
+			}
+			return bytes
+		})
+		detector.SetCycleObserver(func(d time.Duration) { g.Observe(types.GovernedDetection, d) })
+	}
+	if discoverer := s.evolution.GetCausalDiscoverer(); discoverer != nil {
+		g.Register(types.GovernedCausal, "evolution", nil)
+		discoverer.SetCycleObserver(func(d time.Duration) { g.Observe(types.GovernedCausal, d) })
+	}
+	g.OnChange(s.onThrottleChanged)
+
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.governor = g
+	s.governorCancel = cancel
+	g.Start(ctx)
+}
+
+// stopGovernor 停止资源预算评估并解除节流, 调用方需持有锁
+func (s *System) stopGovernor() {
+	if s.governorCancel == nil {
+		return
+	}
+	s.governorCancel()
+	s.governorCancel = nil
+	s.governor = nil
+
+	if detector := s.meta.GetDetector(); detector != nil {
+		detector.SetCycleObserver(nil)
+	}
+	if discoverer := s.evolution.GetCausalDiscoverer(); discoverer != nil {
+		discoverer.SetCycleObserver(nil)
+	}
+	s.applyMode()
+}
+
+// throttleFactor 循环的资源预算节流倍率, 调用方需持有锁
+func (s *System) throttleFactor(name string) float64 {
+	if s.governor == nil {
+		return 1
+	}
+	return s.governor.Factor(name)
+}
+
+// onThrottleChanged 节流开始或结束时重新设置循环间隔并发出事件
+func (s *System) onThrottleChanged(state types.ThrottleState) {
+	s.mu.Lock()
+	s.applyMode()
+	s.mu.Unlock()
+
+	message := "loop throttled by resource budget"
+	priority := types.PriorityHigh
+	if !state.Throttled {
+		message = "loop throttle released"
+		priority = types.PriorityNormal
+	}
+	s.HandleEvent(types.SystemEvent{
+		Type:      types.EventThrottleChanged,
+		Source:    state.Subsystem,
+		Timestamp: state.Since,
+		Message:   message,
+		Priority:  priority,
+		Data:      state,
+	})
+}
+
+// ThrottleStates 受资源预算约束的循环的用量和节流状态, 未配置预算时为空
+func (s *System) ThrottleStates() []types.ThrottleState {
+	s.mu.RLock()
+	g := s.governor
+	s.mu.RUnlock()
+	if g == nil {
+		return nil
+	}
+	return g.States()
+}
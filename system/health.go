@@ -0,0 +1,53 @@
+// system/health.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// RegisterHealthContributor 注册健康度贡献者, 其分量的加权平均与各子系统健康度同权计入系统健康度
+// 同名贡献者会被替换; 名称不能与子系统重名
+func (s *System) RegisterHealthContributor(name string, contributor types.HealthContributor) error {
+	if name == "" || contributor == nil {
+		return types.NewSystemError(types.ErrInvalid, "health contributor requires name and implementation", nil)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, exists := s.subsystems()[name]; exists {
+		return types.NewSystemError(types.ErrExists, "health contributor name conflicts with subsystem", nil).
+			WithContext("name", name)
+	}
+	s.healthContributors[name] = contributor
+	return nil
+}
+
+// UnregisterHealthContributor 移除健康度贡献者
+func (s *System) UnregisterHealthContributor(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.healthContributors, name)
+}
+
+// HealthComponents 获取各贡献者的健康度分量, 包括实现了HealthContributor的子系统和外部注册的贡献者
+func (s *System) HealthComponents() map[string][]types.HealthComponent {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string][]types.HealthComponent)
+	for name, component := range s.subsystems() {
+		if contributor, ok := component.(types.HealthContributor); ok {
+			if components := contributor.HealthComponents(); len(components) > 0 {
+				result[name] = components
+			}
+		}
+	}
+	for name, contributor := range s.healthContributors {
+		if components := contributor.HealthComponents(); len(components) > 0 {
+			result[name] = components
+		}
+	}
+	return result
+}
@@ -0,0 +1,493 @@
+// system/integrations/consumer.go
+
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/meta"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 消费默认参数
+const (
+	defaultConsumerBatchSize    = 100
+	defaultConsumerBatchTimeout = time.Second
+	defaultConsumerMaxInFlight  = 4
+)
+
+// Message 通用流消息
+type Message struct {
+	Topic     string            // 主题
+	Partition int               // 分区
+	Offset    int64             // 偏移量
+	Key       []byte            // 键
+	Value     []byte            // 内容
+	Headers   map[string]string // 消息头
+	Time      time.Time         // 消息时间
+
+	raw interface{} // 底层客户端消息
+}
+
+// Source 流数据源, 由具体客户端适配
+type Source interface {
+	// Fetch 拉取至多max条消息, 至少阻塞到一条消息或超时
+	Fetch(ctx context.Context, max int, timeout time.Duration) ([]*Message, error)
+	// Commit 提交已处理消息
+	Commit(ctx context.Context, msgs []*Message) error
+	// Close 关闭数据源
+	Close() error
+}
+
+// Ingester 观测注入接口, meta.Manager 实现该接口
+type Ingester interface {
+	Ingest(ctx context.Context, batch *meta.ObservationBatch) (*meta.IngestResult, error)
+}
+
+var _ Ingester = (*meta.Manager)(nil)
+
+// ConsumerConfig 消费者配置
+type ConsumerConfig struct {
+	Namespace      types.Namespace    // 目标命名空间
+	Source         string             // 数据源标识
+	BatchSize      int                // 单批最大消息数
+	BatchTimeout   time.Duration      // 攒批超时
+	MaxInFlight    int                // 待注入批次上限, 超出时暂停拉取
+	Decoders       map[string]Decoder // 主题 -> 解码器
+	DefaultDecoder Decoder            // 默认解码器
+	Offsets        OffsetStore        // 偏移量存储
+	OnError        func(error)        // 错误回调
+
+	// Retry 注入失败的重试策略, 拉取失败按同样的退避等待
+	// 注入失败的批次不提交; MaxAttempts为0时一直重试, 重试耗尽时停止消费并报告错误, 未提交的消息在重启后重新拉取
+	Retry RetryPolicy
+}
+
+// ConsumerStats 消费统计
+type ConsumerStats struct {
+	Received     int64     // 接收消息数
+	Decoded      int64     // 解码成功数
+	DecodeErrors int64     // 解码失败数
+	Skipped      int64     // 已提交而跳过的消息数
+	Batches      int64     // 注入批次数
+	IngestErrors int64     // 注入失败数
+	FetchErrors  int64     // 拉取失败数
+	Halted       bool      // 注入重试耗尽而停止消费
+	Committed    int64     // 提交消息数
+	Throttled    int64     // 背压暂停次数
+	InFlight     int       // 当前待注入批次
+	LastMessage  time.Time // 最后消息时间
+}
+
+// pendingBatch 待注入批次
+type pendingBatch struct {
+	batch    *meta.ObservationBatch
+	messages []*Message
+}
+
+// StreamConsumer 流消费者, 将消息批量解码后注入场
+type StreamConsumer struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config ConsumerConfig
+
+	// 消费状态
+	state struct {
+		running bool
+		stats   ConsumerStats
+	}
+
+	// 依赖项
+	source   Source
+	ingester Ingester
+
+	// 批次管道
+	pipeline chan *pendingBatch
+
+	// 上下文控制
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// ---------------------------------------------
+// NewStreamConsumer 创建流消费者
+func NewStreamConsumer(source Source, ingester Ingester, config ConsumerConfig) (*StreamConsumer, error) {
+	if source == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil stream source", nil)
+	}
+	if ingester == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil ingester", nil)
+	}
+
+	if config.BatchSize <= 0 {
+		config.BatchSize = defaultConsumerBatchSize
+	}
+	if config.BatchTimeout <= 0 {
+		config.BatchTimeout = defaultConsumerBatchTimeout
+	}
+	if config.MaxInFlight <= 0 {
+		config.MaxInFlight = defaultConsumerMaxInFlight
+	}
+	if config.DefaultDecoder == nil {
+		config.DefaultDecoder = JSONDecoder
+	}
+	if config.Offsets == nil {
+		config.Offsets = NewMemoryOffsetStore()
+	}
+	if config.Retry.MaxAttempts < 0 {
+		config.Retry.MaxAttempts = 0
+	}
+	if config.Retry.InitialBackoff <= 0 {
+		config.Retry.InitialBackoff = defaultRetryBackoff
+	}
+	if config.Retry.MaxBackoff <= 0 {
+		config.Retry.MaxBackoff = defaultRetryMaxBackoff
+	}
+
+	return &StreamConsumer{
+		config:   config,
+		source:   source,
+		ingester: ingester,
+	}, nil
+}
+
+// Start 启动消费
+func (sc *StreamConsumer) Start(ctx context.Context) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+
+	if sc.state.running {
+		return types.ErrAlreadyRunning
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	sc.cancel = cancel
+	sc.done = make(chan struct{})
+	sc.pipeline = make(chan *pendingBatch, sc.config.MaxInFlight)
+	sc.state.running = true
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		defer close(sc.pipeline)
+		sc.fetchLoop(ctx)
+	}()
+	go func() {
+		defer wg.Done()
+		sc.processLoop(ctx)
+	}()
+	go func() {
+		wg.Wait()
+		close(sc.done)
+	}()
+
+	return nil
+}
+
+// Stop 停止消费并关闭数据源
+func (sc *StreamConsumer) Stop() error {
+	sc.mu.Lock()
+	if !sc.state.running {
+		sc.mu.Unlock()
+		return nil
+	}
+	sc.state.running = false
+	cancel, done := sc.cancel, sc.done
+	sc.mu.Unlock()
+
+	cancel()
+	<-done
+
+	return sc.source.Close()
+}
+
+// Wait 等待消费结束
+func (sc *StreamConsumer) Wait() {
+	sc.mu.RLock()
+	done := sc.done
+	sc.mu.RUnlock()
+
+	if done != nil {
+		<-done
+	}
+}
+
+// GetStats 获取消费统计
+func (sc *StreamConsumer) GetStats() ConsumerStats {
+	sc.mu.RLock()
+	defer sc.mu.RUnlock()
+
+	stats := sc.state.stats
+	stats.InFlight = len(sc.pipeline)
+	return stats
+}
+
+// fetchLoop 拉取循环, 管道满时阻塞形成背压; 连续拉取失败时按重试策略退避
+func (sc *StreamConsumer) fetchLoop(ctx context.Context) {
+	failures := 0
+	for ctx.Err() == nil {
+		msgs, err := sc.source.Fetch(ctx, sc.config.BatchSize, sc.config.BatchTimeout)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			failures++
+			sc.mu.Lock()
+			sc.state.stats.FetchErrors++
+			sc.mu.Unlock()
+			sc.reportError(fmt.Errorf("fetch failed: %w", err))
+			if !sleepContext(ctx, sc.config.Retry.backoff(failures)) {
+				return
+			}
+			continue
+		}
+		failures = 0
+		if len(msgs) == 0 {
+			continue
+		}
+
+		pending := sc.decode(msgs)
+		if pending == nil {
+			continue
+		}
+
+		// 背压: 管道已满时记录并阻塞等待
+		if len(sc.pipeline) == cap(sc.pipeline) {
+			sc.mu.Lock()
+			sc.state.stats.Throttled++
+			sc.mu.Unlock()
+		}
+
+		select {
+		case sc.pipeline <- pending:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// processLoop 注入循环
+func (sc *StreamConsumer) processLoop(ctx context.Context) {
+	for pending := range sc.pipeline {
+		// 全部跳过的批次只需提交
+		if pending.batch.Size() == 0 {
+			sc.commit(ctx, pending.messages)
+			continue
+		}
+
+		// 注入失败的批次不提交也不跳过, 否则之后批次提交的更大偏移量会使其永久丢失
+		if !sc.ingest(ctx, pending) {
+			return
+		}
+
+		sc.mu.Lock()
+		sc.state.stats.Batches++
+		sc.mu.Unlock()
+
+		sc.commit(ctx, pending.messages)
+	}
+}
+
+// ingest 按重试策略注入批次, 成功时返回true
+// 停止中或重试耗尽时返回false, 重试耗尽时停止消费
+func (sc *StreamConsumer) ingest(ctx context.Context, pending *pendingBatch) bool {
+	for attempt := 1; ; attempt++ {
+		_, err := sc.ingester.Ingest(ctx, pending.batch)
+		if err == nil {
+			return true
+		}
+		if ctx.Err() != nil {
+			return false
+		}
+
+		sc.mu.Lock()
+		sc.state.stats.IngestErrors++
+		sc.mu.Unlock()
+		sc.reportError(fmt.Errorf("ingest failed (attempt %d): %w", attempt, err))
+
+		if max := sc.config.Retry.MaxAttempts; max > 0 && attempt >= max {
+			sc.halt(types.NewSystemError(types.ErrRuntime, "ingest retries exhausted, consumer stopped", err).
+				WithContext("source", sc.config.Source).
+				WithContext("attempts", attempt).
+				WithContext("messages", len(pending.messages)))
+			return false
+		}
+		if !sleepContext(ctx, sc.config.Retry.backoff(attempt)) {
+			return false
+		}
+	}
+}
+
+// halt 停止拉取和注入并报告错误, 数据源在Stop时关闭
+func (sc *StreamConsumer) halt(err error) {
+	sc.mu.Lock()
+	sc.state.stats.Halted = true
+	cancel := sc.cancel
+	sc.mu.Unlock()
+
+	sc.reportError(err)
+	cancel()
+}
+
+// sleepContext 等待d, 上下文结束时返回false
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// decode 解码消息并合并为观测批次, 已提交的消息被跳过
+func (sc *StreamConsumer) decode(msgs []*Message) *pendingBatch {
+	batch := &meta.ObservationBatch{
+		Namespace: sc.config.Namespace,
+		Source:    sc.config.Source,
+		Timestamp: time.Now(),
+	}
+	pending := &pendingBatch{batch: batch}
+
+	var received, decoded, failed, skipped int64
+	for _, msg := range msgs {
+		received++
+
+		if committed, ok := sc.config.Offsets.Load(msg.Topic, msg.Partition); ok && msg.Offset >= 0 && msg.Offset <= committed {
+			skipped++
+			pending.messages = append(pending.messages, msg)
+			continue
+		}
+
+		decoder := sc.config.DefaultDecoder
+		if d, ok := sc.config.Decoders[msg.Topic]; ok {
+			decoder = d
+		}
+
+		obs, err := decoder(msg)
+		if err != nil {
+			failed++
+			sc.reportError(fmt.Errorf("decode %s/%d@%d: %w", msg.Topic, msg.Partition, msg.Offset, err))
+		} else if obs != nil {
+			decoded++
+			mergeBatch(batch, obs)
+		}
+
+		// 解码失败的消息同样提交, 避免毒消息阻塞分区
+		pending.messages = append(pending.messages, msg)
+	}
+
+	sc.mu.Lock()
+	sc.state.stats.Received += received
+	sc.state.stats.Decoded += decoded
+	sc.state.stats.DecodeErrors += failed
+	sc.state.stats.Skipped += skipped
+	sc.state.stats.LastMessage = time.Now()
+	sc.mu.Unlock()
+
+	if len(pending.messages) == 0 {
+		return nil
+	}
+	return pending
+}
+
+// commit 提交偏移量
+func (sc *StreamConsumer) commit(ctx context.Context, msgs []*Message) {
+	if err := sc.source.Commit(ctx, msgs); err != nil {
+		sc.reportError(fmt.Errorf("commit failed: %w", err))
+		return
+	}
+
+	for _, msg := range msgs {
+		if err := sc.config.Offsets.Commit(msg.Topic, msg.Partition, msg.Offset); err != nil {
+			sc.reportError(fmt.Errorf("offset store failed: %w", err))
+			return
+		}
+	}
+
+	sc.mu.Lock()
+	sc.state.stats.Committed += int64(len(msgs))
+	sc.mu.Unlock()
+}
+
+// reportError 报告错误
+func (sc *StreamConsumer) reportError(err error) {
+	if sc.config.OnError != nil {
+		sc.config.OnError(err)
+	}
+}
+
+// mergeBatch 合并观测批次
+func mergeBatch(dst, src *meta.ObservationBatch) {
+	dst.Series = append(dst.Series, src.Series...)
+	dst.Points = append(dst.Points, src.Points...)
+	dst.Events = append(dst.Events, src.Events...)
+}
+
+// OffsetStore 偏移量存储
+type OffsetStore interface {
+	Load(topic string, partition int) (int64, bool)
+	Commit(topic string, partition int, offset int64) error
+}
+
+// offsetKey 偏移量键
+type offsetKey struct {
+	topic     string
+	partition int
+}
+
+// MemoryOffsetStore 内存偏移量存储
+type MemoryOffsetStore struct {
+	mu      sync.RWMutex
+	offsets map[offsetKey]int64
+}
+
+// NewMemoryOffsetStore 创建内存偏移量存储
+func NewMemoryOffsetStore() *MemoryOffsetStore {
+	return &MemoryOffsetStore{
+		offsets: make(map[offsetKey]int64),
+	}
+}
+
+// Load 读取已提交偏移量
+func (s *MemoryOffsetStore) Load(topic string, partition int) (int64, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	offset, ok := s.offsets[offsetKey{topic, partition}]
+	return offset, ok
+}
+
+// Commit 提交偏移量, 只前进不后退
+func (s *MemoryOffsetStore) Commit(topic string, partition int, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := offsetKey{topic, partition}
+	if current, ok := s.offsets[key]; !ok || offset > current {
+		s.offsets[key] = offset
+	}
+	return nil
+}
+
+// Snapshot 导出偏移量
+func (s *MemoryOffsetStore) Snapshot() map[string]map[int]int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := make(map[string]map[int]int64)
+	for key, offset := range s.offsets {
+		if result[key.topic] == nil {
+			result[key.topic] = make(map[int]int64)
+		}
+		result[key.topic][key.partition] = offset
+	}
+	return result
+}
@@ -0,0 +1,66 @@
+// system/integrations/decoder.go
+
+package integrations
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Corphon/daoflow/system/meta"
+)
+
+// Decoder 消息解码函数, 将消息翻译为场观测
+type Decoder func(msg *Message) (*meta.ObservationBatch, error)
+
+// JSONDecoder 解码JSON格式的观测批次
+func JSONDecoder(msg *Message) (*meta.ObservationBatch, error) {
+	var batch meta.ObservationBatch
+	if err := json.Unmarshal(msg.Value, &batch); err != nil {
+		return nil, fmt.Errorf("invalid observation json: %w", err)
+	}
+	return &batch, nil
+}
+
+// ScalarDecoder 将消息内容解析为单个标量, 序列名为空时使用主题名
+func ScalarDecoder(series string) Decoder {
+	return func(msg *Message) (*meta.ObservationBatch, error) {
+		value, err := strconv.ParseFloat(strings.TrimSpace(string(msg.Value)), 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid scalar: %w", err)
+		}
+
+		name := series
+		if name == "" {
+			name = msg.Topic
+		}
+
+		return &meta.ObservationBatch{
+			Series: []meta.SeriesObservation{{
+				Name:      name,
+				Value:     value,
+				Timestamp: msg.Time,
+			}},
+		}, nil
+	}
+}
+
+// CategoryDecoder 将消息内容作为分类事件, 消息键作为事件名
+func CategoryDecoder(weight float64) Decoder {
+	return func(msg *Message) (*meta.ObservationBatch, error) {
+		category := strings.TrimSpace(string(msg.Value))
+		if category == "" {
+			return nil, fmt.Errorf("empty category")
+		}
+
+		return &meta.ObservationBatch{
+			Events: []meta.CategoricalObservation{{
+				Name:      string(msg.Key),
+				Category:  category,
+				Weight:    weight,
+				Timestamp: msg.Time,
+			}},
+		}, nil
+	}
+}
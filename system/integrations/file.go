@@ -0,0 +1,82 @@
+// system/integrations/file.go
+
+package integrations
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// FileSink 文件输出端, 以NDJSON格式追加写入
+type FileSink struct {
+	mu sync.Mutex
+
+	name string
+	path string
+	file *os.File
+}
+
+// NewFileSink 创建文件输出端
+func NewFileSink(name, path string) (*FileSink, error) {
+	if path == "" {
+		return nil, types.NewSystemError(types.ErrInvalid, "empty file path", nil).
+			WithContext("sink", name)
+	}
+	if name == "" {
+		name = "file"
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "failed to open sink file", err).
+			WithContext("path", path)
+	}
+
+	return &FileSink{
+		name: name,
+		path: path,
+		file: file,
+	}, nil
+}
+
+// Name 输出端名称
+func (fs *FileSink) Name() string {
+	return fs.name
+}
+
+// Emit 每条输出写入一行JSON
+func (fs *FileSink) Emit(ctx context.Context, outputs []Output) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return os.ErrClosed
+	}
+
+	w := bufio.NewWriter(fs.file)
+	enc := json.NewEncoder(w)
+	for i := range outputs {
+		if err := enc.Encode(&outputs[i]); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// Close 关闭文件
+func (fs *FileSink) Close() error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	if fs.file == nil {
+		return nil
+	}
+	err := fs.file.Close()
+	fs.file = nil
+	return err
+}
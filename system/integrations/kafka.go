@@ -0,0 +1,173 @@
+// system/integrations/kafka.go
+
+package integrations
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// KafkaHeader Kafka消息头
+type KafkaHeader struct {
+	Key   string
+	Value []byte
+}
+
+// KafkaMessage Kafka消息
+type KafkaMessage struct {
+	Topic     string
+	Partition int
+	Offset    int64
+	Key       []byte
+	Value     []byte
+	Headers   []KafkaHeader
+	Time      time.Time
+}
+
+// KafkaReader Kafka消费客户端
+// 与常见Go客户端的Reader语义一致, 由调用方适配具体实现
+type KafkaReader interface {
+	FetchMessage(ctx context.Context) (KafkaMessage, error)
+	CommitMessages(ctx context.Context, msgs ...KafkaMessage) error
+	Close() error
+}
+
+// kafkaSource Kafka数据源
+type kafkaSource struct {
+	reader KafkaReader
+}
+
+// NewKafkaConsumer 创建Kafka消费者
+func NewKafkaConsumer(reader KafkaReader, ingester Ingester, config ConsumerConfig) (*StreamConsumer, error) {
+	if config.Source == "" {
+		config.Source = "kafka"
+	}
+	return NewStreamConsumer(&kafkaSource{reader: reader}, ingester, config)
+}
+
+// Fetch 拉取消息, 首条阻塞等待, 其余在超时内攒批
+func (ks *kafkaSource) Fetch(ctx context.Context, max int, timeout time.Duration) ([]*Message, error) {
+	first, err := ks.reader.FetchMessage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	msgs := []*Message{fromKafka(first)}
+
+	batchCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for len(msgs) < max {
+		msg, err := ks.reader.FetchMessage(batchCtx)
+		if err != nil {
+			if errors.Is(err, context.DeadlineExceeded) || batchCtx.Err() != nil {
+				break
+			}
+			return msgs, err
+		}
+		msgs = append(msgs, fromKafka(msg))
+	}
+
+	return msgs, nil
+}
+
+// Commit 提交偏移量
+func (ks *kafkaSource) Commit(ctx context.Context, msgs []*Message) error {
+	raw := make([]KafkaMessage, 0, len(msgs))
+	for _, msg := range msgs {
+		if km, ok := msg.raw.(KafkaMessage); ok {
+			raw = append(raw, km)
+		}
+	}
+	if len(raw) == 0 {
+		return nil
+	}
+	return ks.reader.CommitMessages(ctx, raw...)
+}
+
+// Close 关闭客户端
+func (ks *kafkaSource) Close() error {
+	return ks.reader.Close()
+}
+
+// fromKafka 转换Kafka消息
+func fromKafka(km KafkaMessage) *Message {
+	headers := make(map[string]string, len(km.Headers))
+	for _, h := range km.Headers {
+		headers[h.Key] = string(h.Value)
+	}
+	return &Message{
+		Topic:     km.Topic,
+		Partition: km.Partition,
+		Offset:    km.Offset,
+		Key:       km.Key,
+		Value:     km.Value,
+		Headers:   headers,
+		Time:      km.Time,
+		raw:       km,
+	}
+}
+
+// KafkaWriter Kafka生产客户端
+// 与常见Go客户端的Writer语义一致, 由调用方适配具体实现
+type KafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...KafkaMessage) error
+	Close() error
+}
+
+// KafkaSink Kafka输出端, 每条输出为一条消息, 以输出ID为键
+type KafkaSink struct {
+	name   string
+	topic  string
+	writer KafkaWriter
+}
+
+// NewKafkaSink 创建Kafka输出端
+func NewKafkaSink(name, topic string, writer KafkaWriter) (*KafkaSink, error) {
+	if writer == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil kafka writer", nil).
+			WithContext("sink", name)
+	}
+	if name == "" {
+		name = "kafka"
+	}
+	return &KafkaSink{
+		name:   name,
+		topic:  topic,
+		writer: writer,
+	}, nil
+}
+
+// Name 输出端名称
+func (ks *KafkaSink) Name() string {
+	return ks.name
+}
+
+// Emit 写入消息
+func (ks *KafkaSink) Emit(ctx context.Context, outputs []Output) error {
+	msgs := make([]KafkaMessage, 0, len(outputs))
+	for i := range outputs {
+		value, err := json.Marshal(&outputs[i])
+		if err != nil {
+			return err
+		}
+		msgs = append(msgs, KafkaMessage{
+			Topic: ks.topic,
+			Key:   []byte(outputs[i].ID),
+			Value: value,
+			Headers: []KafkaHeader{
+				{Key: "kind", Value: []byte(outputs[i].Kind)},
+			},
+			Time: outputs[i].Timestamp,
+		})
+	}
+	return ks.writer.WriteMessages(ctx, msgs...)
+}
+
+// Close 关闭生产者
+func (ks *KafkaSink) Close() error {
+	return ks.writer.Close()
+}
@@ -0,0 +1,96 @@
+// system/integrations/nats.go
+
+package integrations
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrNATSTimeout NATS拉取超时, 适配器应将客户端的超时错误转换为该错误
+var ErrNATSTimeout = errors.New("nats: fetch timeout")
+
+// NATSMsg NATS消息
+type NATSMsg struct {
+	Subject  string
+	Data     []byte
+	Header   map[string][]string
+	Sequence uint64       // 流序号, 用作偏移量
+	Time     time.Time    // 消息时间
+	Ack      func() error // 确认函数
+}
+
+// NATSPullSubscriber NATS拉取订阅客户端
+// 与JetStream拉取订阅语义一致, 由调用方适配具体实现
+type NATSPullSubscriber interface {
+	Fetch(batch int, timeout time.Duration) ([]*NATSMsg, error)
+	Unsubscribe() error
+}
+
+// natsSource NATS数据源
+type natsSource struct {
+	sub NATSPullSubscriber
+}
+
+// NewNATSConsumer 创建NATS消费者
+func NewNATSConsumer(sub NATSPullSubscriber, ingester Ingester, config ConsumerConfig) (*StreamConsumer, error) {
+	if config.Source == "" {
+		config.Source = "nats"
+	}
+	return NewStreamConsumer(&natsSource{sub: sub}, ingester, config)
+}
+
+// Fetch 拉取消息
+func (ns *natsSource) Fetch(ctx context.Context, max int, timeout time.Duration) ([]*Message, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	raw, err := ns.sub.Fetch(max, timeout)
+	if err != nil {
+		if errors.Is(err, ErrNATSTimeout) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	msgs := make([]*Message, 0, len(raw))
+	for _, nm := range raw {
+		headers := make(map[string]string, len(nm.Header))
+		for k, v := range nm.Header {
+			if len(v) > 0 {
+				headers[k] = v[0]
+			}
+		}
+		msgs = append(msgs, &Message{
+			Topic:   nm.Subject,
+			Offset:  int64(nm.Sequence),
+			Value:   nm.Data,
+			Headers: headers,
+			Time:    nm.Time,
+			raw:     nm,
+		})
+	}
+
+	return msgs, nil
+}
+
+// Commit 确认消息
+func (ns *natsSource) Commit(ctx context.Context, msgs []*Message) error {
+	for _, msg := range msgs {
+		nm, ok := msg.raw.(*NATSMsg)
+		if !ok || nm.Ack == nil {
+			continue
+		}
+		if err := nm.Ack(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Close 取消订阅
+func (ns *natsSource) Close() error {
+	return ns.sub.Unsubscribe()
+}
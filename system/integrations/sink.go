@@ -0,0 +1,594 @@
+// system/integrations/sink.go
+
+package integrations
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 输出默认参数
+const (
+	defaultDispatchQueueSize = 1024
+	defaultDispatchBatchSize = 64
+	defaultRetryAttempts     = 3
+	defaultRetryBackoff      = 200 * time.Millisecond
+	defaultRetryMaxBackoff   = 5 * time.Second
+)
+
+// OutputKind 输出类型
+type OutputKind string
+
+const (
+	OutputPattern  OutputKind = "pattern"  // 新识别模式
+	OutputDecision OutputKind = "decision" // 适应决策
+	OutputAnomaly  OutputKind = "anomaly"  // 异常
+)
+
+// Output 输出记录
+type Output struct {
+	ID        string                 `json:"id"`
+	Kind      OutputKind             `json:"kind"`
+	Namespace types.Namespace        `json:"namespace,omitempty"`
+	Source    string                 `json:"source,omitempty"`
+	Timestamp time.Time              `json:"timestamp"`
+	Score     float64                `json:"score"` // 模式强度/异常严重度等, 用于过滤
+	Labels    map[string]string      `json:"labels,omitempty"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+}
+
+// Sink 输出端
+type Sink interface {
+	// Name 输出端名称
+	Name() string
+	// Emit 投递一批输出, 返回错误时按重试策略重投
+	Emit(ctx context.Context, outputs []Output) error
+	// Close 关闭输出端
+	Close() error
+}
+
+// FilterRule 过滤规则, 各条件之间为与关系, 空条件不限制
+type FilterRule struct {
+	Kinds      []OutputKind
+	Namespaces []types.Namespace
+	Types      []string // 模式类型等, 与Labels["type"]匹配
+	MinScore   float64
+	Labels     map[string]string
+}
+
+// Matches 判断输出是否满足规则
+func (r FilterRule) Matches(out *Output) bool {
+	if len(r.Kinds) > 0 {
+		matched := false
+		for _, kind := range r.Kinds {
+			if kind == out.Kind {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.Namespaces) > 0 {
+		ns := out.Namespace
+		if ns == "" {
+			ns = types.DefaultNamespace
+		}
+		matched := false
+		for _, n := range r.Namespaces {
+			if n == ns {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if len(r.Types) > 0 {
+		matched := false
+		for _, t := range r.Types {
+			if t == out.Labels["type"] {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+
+	if out.Score < r.MinScore {
+		return false
+	}
+
+	for k, v := range r.Labels {
+		if out.Labels[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
+// RetryPolicy 投递重试策略
+type RetryPolicy struct {
+	MaxAttempts    int           // 最大尝试次数(含首次)
+	InitialBackoff time.Duration // 初始退避
+	MaxBackoff     time.Duration // 最大退避
+}
+
+// backoff 计算第n次重试前的退避时间
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	d := p.InitialBackoff
+	for i := 1; i < attempt; i++ {
+		d *= 2
+		if d >= p.MaxBackoff {
+			return p.MaxBackoff
+		}
+	}
+	return d
+}
+
+// SinkStats 输出端统计
+type SinkStats struct {
+	Delivered int64     // 成功投递数
+	Filtered  int64     // 被过滤数
+	Failed    int64     // 重试耗尽后丢弃数
+	Retries   int64     // 重试次数
+	LastError string    // 最后错误
+	LastSent  time.Time // 最后投递时间
+}
+
+// DispatcherStats 分发统计
+type DispatcherStats struct {
+	Published int64                // 发布数
+	Dropped   int64                // 队列满丢弃数
+	Queued    int                  // 当前排队数
+	Sinks     map[string]SinkStats // 输出端统计
+}
+
+// sinkEntry 已注册输出端
+type sinkEntry struct {
+	sink    Sink
+	filters []FilterRule
+	retry   RetryPolicy
+	stats   SinkStats
+}
+
+// accepts 判断输出端是否接收该输出, 任一规则匹配即接收
+func (e *sinkEntry) accepts(out *Output) bool {
+	if len(e.filters) == 0 {
+		return true
+	}
+	for _, rule := range e.filters {
+		if rule.Matches(out) {
+			return true
+		}
+	}
+	return false
+}
+
+// Dispatcher 输出分发器, 将模式、决策和异常异步投递到各输出端
+type Dispatcher struct {
+	mu sync.RWMutex
+
+	// 已注册输出端
+	sinks []*sinkEntry
+
+	// 分发状态
+	state struct {
+		running   bool
+		published int64
+		dropped   int64
+	}
+
+	// 输出队列
+	queue chan Output
+
+	// 错误回调
+	onError func(error)
+
+	// 上下文控制
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// ---------------------------------------------
+// NewDispatcher 创建输出分发器
+func NewDispatcher(queueSize int) *Dispatcher {
+	if queueSize <= 0 {
+		queueSize = defaultDispatchQueueSize
+	}
+	return &Dispatcher{
+		queue: make(chan Output, queueSize),
+	}
+}
+
+// Register 注册输出端
+func (d *Dispatcher) Register(sink Sink, retry RetryPolicy, filters ...FilterRule) error {
+	if sink == nil {
+		return types.NewSystemError(types.ErrInvalid, "nil sink", nil)
+	}
+
+	if retry.MaxAttempts <= 0 {
+		retry.MaxAttempts = defaultRetryAttempts
+	}
+	if retry.InitialBackoff <= 0 {
+		retry.InitialBackoff = defaultRetryBackoff
+	}
+	if retry.MaxBackoff <= 0 {
+		retry.MaxBackoff = defaultRetryMaxBackoff
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, entry := range d.sinks {
+		if entry.sink.Name() == sink.Name() {
+			return types.NewSystemError(types.ErrExists, "sink already registered", nil).
+				WithContext("sink", sink.Name())
+		}
+	}
+
+	d.sinks = append(d.sinks, &sinkEntry{
+		sink:    sink,
+		filters: filters,
+		retry:   retry,
+	})
+	return nil
+}
+
+// OnError 设置错误回调
+func (d *Dispatcher) OnError(fn func(error)) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.onError = fn
+}
+
+// Start 启动分发
+func (d *Dispatcher) Start(ctx context.Context) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.state.running {
+		return types.ErrAlreadyRunning
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	d.cancel = cancel
+	d.done = make(chan struct{})
+	d.state.running = true
+
+	go d.dispatchLoop(ctx)
+
+	return nil
+}
+
+// Stop 停止分发并排空队列, 输出端保持打开以便再次启动
+func (d *Dispatcher) Stop() error {
+	d.mu.Lock()
+	if !d.state.running {
+		d.mu.Unlock()
+		return nil
+	}
+	d.state.running = false
+	cancel, done := d.cancel, d.done
+	d.mu.Unlock()
+
+	cancel()
+	<-done
+	return nil
+}
+
+// Close 停止分发并关闭所有输出端
+func (d *Dispatcher) Close() error {
+	d.Stop()
+
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	var firstErr error
+	for _, entry := range d.sinks {
+		if err := entry.sink.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Publish 发布输出, 队列满时丢弃而不阻塞调用方
+func (d *Dispatcher) Publish(out Output) bool {
+	if out.Timestamp.IsZero() {
+		out.Timestamp = time.Now()
+	}
+	if out.ID == "" {
+		out.ID = core.NewID(string(out.Kind) + "_")
+	}
+
+	select {
+	case d.queue <- out:
+		d.mu.Lock()
+		d.state.published++
+		d.mu.Unlock()
+		return true
+	default:
+		d.mu.Lock()
+		d.state.dropped++
+		d.mu.Unlock()
+		return false
+	}
+}
+
+// PublishPatterns 发布新识别的模式
+func (d *Dispatcher) PublishPatterns(patterns []emergence.EmergentPattern) {
+	for _, p := range patterns {
+		ns := types.Namespace(p.Namespace)
+		d.Publish(Output{
+			ID:        p.ID,
+			Kind:      OutputPattern,
+			Namespace: ns,
+			Source:    "emergence",
+			Timestamp: p.Formation,
+			Score:     p.Strength,
+			Labels:    patternLabels(p),
+			Payload: map[string]interface{}{
+				"type":        p.Type,
+				"strength":    p.Strength,
+				"stability":   p.Stability,
+				"energy":      p.Energy,
+				"components":  len(p.Components),
+				"properties":  p.Properties,
+				"annotations": p.Annotations,
+			},
+		})
+	}
+}
+
+// patternLabels 模式输出的标签: 类型和以types.TagLabelPrefix为前缀的用户标签
+func patternLabels(p emergence.EmergentPattern) map[string]string {
+	labels := p.Annotations.Labels()
+	labels["type"] = p.Type
+	return labels
+}
+
+// PublishDecision 发布适应决策
+func (d *Dispatcher) PublishDecision(event adaptation.StrategyEvent) {
+	score := 0.0
+	if v, ok := event.Details["effectiveness"].(float64); ok {
+		score = v
+	}
+	d.Publish(Output{
+		Kind:      OutputDecision,
+		Source:    "adaptation",
+		Timestamp: event.Timestamp,
+		Score:     score,
+		Labels: map[string]string{
+			"type":     event.Type,
+			"status":   event.Status,
+			"strategy": event.StrategyID,
+		},
+		Payload: map[string]interface{}{
+			"strategy_id": event.StrategyID,
+			"type":        event.Type,
+			"status":      event.Status,
+			"details":     event.Details,
+		},
+	})
+}
+
+// PublishAnomaly 发布追踪异常
+func (d *Dispatcher) PublishAnomaly(traceID types.TraceID, anomaly types.Anomaly) {
+	d.Publish(Output{
+		Kind:      OutputAnomaly,
+		Source:    "trace",
+		Timestamp: anomaly.DetectedAt,
+		Score:     anomaly.Severity,
+		Labels: map[string]string{
+			"type":   anomaly.Type,
+			"metric": anomaly.Metric,
+		},
+		Payload: map[string]interface{}{
+			"trace_id":  string(traceID),
+			"type":      anomaly.Type,
+			"severity":  anomaly.Severity,
+			"metric":    anomaly.Metric,
+			"threshold": anomaly.Threshold,
+			"value":     anomaly.Value,
+		},
+	})
+}
+
+// GetStats 获取分发统计
+func (d *Dispatcher) GetStats() DispatcherStats {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	stats := DispatcherStats{
+		Published: d.state.published,
+		Dropped:   d.state.dropped,
+		Queued:    len(d.queue),
+		Sinks:     make(map[string]SinkStats, len(d.sinks)),
+	}
+	for _, entry := range d.sinks {
+		stats.Sinks[entry.sink.Name()] = entry.stats
+	}
+	return stats
+}
+
+// dispatchLoop 分发循环, 攒批后投递, 停止时排空队列
+func (d *Dispatcher) dispatchLoop(ctx context.Context) {
+	defer close(d.done)
+
+	for {
+		select {
+		case out := <-d.queue:
+			d.deliver(ctx, d.collect(out))
+		case <-ctx.Done():
+			// 排空剩余输出, 仅尝试一次
+			for {
+				select {
+				case out := <-d.queue:
+					d.deliver(context.Background(), d.collect(out))
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+// collect 从队列中非阻塞地收集一批输出
+func (d *Dispatcher) collect(first Output) []Output {
+	batch := []Output{first}
+	for len(batch) < defaultDispatchBatchSize {
+		select {
+		case out := <-d.queue:
+			batch = append(batch, out)
+		default:
+			return batch
+		}
+	}
+	return batch
+}
+
+// deliver 按过滤规则将批次投递到各输出端
+func (d *Dispatcher) deliver(ctx context.Context, batch []Output) {
+	d.mu.RLock()
+	entries := append([]*sinkEntry{}, d.sinks...)
+	d.mu.RUnlock()
+
+	for _, entry := range entries {
+		selected := make([]Output, 0, len(batch))
+		for i := range batch {
+			if entry.accepts(&batch[i]) {
+				selected = append(selected, batch[i])
+			}
+		}
+
+		d.mu.Lock()
+		entry.stats.Filtered += int64(len(batch) - len(selected))
+		d.mu.Unlock()
+
+		if len(selected) > 0 {
+			d.emit(ctx, entry, selected)
+		}
+	}
+}
+
+// emit 带重试投递
+func (d *Dispatcher) emit(ctx context.Context, entry *sinkEntry, outputs []Output) {
+	attempts := entry.retry.MaxAttempts
+	if ctx.Err() != nil {
+		attempts = 1
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			d.mu.Lock()
+			entry.stats.Retries++
+			d.mu.Unlock()
+
+			select {
+			case <-time.After(entry.retry.backoff(attempt - 1)):
+			case <-ctx.Done():
+				attempts = attempt // 停止中, 本次为最后一次尝试
+			}
+		}
+
+		if err = entry.sink.Emit(ctx, outputs); err == nil {
+			d.mu.Lock()
+			entry.stats.Delivered += int64(len(outputs))
+			entry.stats.LastSent = time.Now()
+			d.mu.Unlock()
+			return
+		}
+	}
+
+	d.mu.Lock()
+	entry.stats.Failed += int64(len(outputs))
+	entry.stats.LastError = err.Error()
+	onError := d.onError
+	d.mu.Unlock()
+
+	if onError != nil {
+		onError(fmt.Errorf("sink %s: delivery failed after %d attempts: %w", entry.sink.Name(), attempts, err))
+	}
+}
+
+// NewDispatcherFromConfig 根据配置创建分发器
+// kafka类型的输出端需要在writers中提供同名的生产者客户端
+func NewDispatcherFromConfig(cfg *types.IntegrationConfig, writers map[string]KafkaWriter) (*Dispatcher, error) {
+	if cfg == nil {
+		return NewDispatcher(0), nil
+	}
+
+	d := NewDispatcher(cfg.QueueSize)
+	for _, sc := range cfg.Sinks {
+		sink, err := newSinkFromConfig(sc, writers)
+		if err != nil {
+			return nil, err
+		}
+
+		retry := RetryPolicy{
+			MaxAttempts:    sc.Retry.MaxAttempts,
+			InitialBackoff: sc.Retry.InitialBackoff,
+			MaxBackoff:     sc.Retry.MaxBackoff,
+		}
+
+		rule := FilterRule{
+			Types:    sc.Filter.Types,
+			MinScore: sc.Filter.MinScore,
+			Labels:   sc.Filter.Labels,
+		}
+		for _, kind := range sc.Filter.Kinds {
+			rule.Kinds = append(rule.Kinds, OutputKind(kind))
+		}
+		for _, ns := range sc.Filter.Namespaces {
+			rule.Namespaces = append(rule.Namespaces, types.Namespace(ns))
+		}
+
+		if err := d.Register(sink, retry, rule); err != nil {
+			return nil, err
+		}
+	}
+
+	return d, nil
+}
+
+// newSinkFromConfig 创建配置的输出端
+func newSinkFromConfig(sc types.SinkConfig, writers map[string]KafkaWriter) (Sink, error) {
+	switch sc.Type {
+	case "webhook":
+		return NewWebhookSink(sc.Name, sc.URL, WebhookOptions{
+			Headers:  sc.Headers,
+			Timeout:  sc.Timeout,
+			Template: sc.Template,
+			Secret:   sc.Secret,
+		})
+	case "kafka":
+		writer, ok := writers[sc.Name]
+		if !ok {
+			return nil, types.NewSystemError(types.ErrNotFound, "kafka writer not provided", nil).
+				WithContext("sink", sc.Name)
+		}
+		return NewKafkaSink(sc.Name, sc.Topic, writer)
+	case "file":
+		return NewFileSink(sc.Name, sc.Path)
+	default:
+		return nil, types.NewSystemError(types.ErrInvalid, "unknown sink type", nil).
+			WithContext("sink", sc.Name).
+			WithContext("type", sc.Type)
+	}
+}
@@ -0,0 +1,236 @@
+// system/integrations/webhook.go
+
+package integrations
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认webhook超时
+const defaultWebhookTimeout = 10 * time.Second
+
+// webhook签名请求头
+const (
+	SignatureHeader = "X-Daoflow-Signature" // sha256=<hex>, 对"时间戳.请求体"的HMAC-SHA256
+	TimestampHeader = "X-Daoflow-Timestamp" // 签名时的Unix秒
+	DeliveryHeader  = "X-Daoflow-Delivery"  // 模板模式下的输出ID, 供接收方去重
+)
+
+// WebhookOptions webhook选项
+type WebhookOptions struct {
+	Headers map[string]string // 附加请求头
+	Timeout time.Duration     // 请求超时
+	Client  *http.Client      // 自定义客户端
+
+	// Template 请求体模板(text/template), 数据为单条Output
+	// 设置后每条输出单独POST渲染结果, 结果须为合法JSON; 为空时以JSON数组POST整批输出
+	Template string
+	// Secret 签名密钥, 非空时请求带SignatureHeader和TimestampHeader
+	Secret string
+}
+
+// WebhookSink webhook输出端, 以JSON数组POST一批输出, 或按模板逐条POST
+type WebhookSink struct {
+	mu sync.Mutex
+
+	name    string
+	url     string
+	headers map[string]string
+	client  *http.Client
+	tmpl    *template.Template
+	secret  []byte
+	sent    map[string]struct{} // 模板模式下本批已投递的输出, 重试时跳过
+	nowFunc func() time.Time
+}
+
+// webhookFuncs 模板函数
+var webhookFuncs = template.FuncMap{
+	"json": func(v interface{}) (string, error) {
+		b, err := json.Marshal(v)
+		return string(b), err
+	},
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+	"rfc3339": func(t time.Time) string {
+		return t.UTC().Format(time.RFC3339)
+	},
+}
+
+// NewWebhookSink 创建webhook输出端
+func NewWebhookSink(name, url string, opts WebhookOptions) (*WebhookSink, error) {
+	if url == "" {
+		return nil, types.NewSystemError(types.ErrInvalid, "empty webhook url", nil).
+			WithContext("sink", name)
+	}
+	if name == "" {
+		name = "webhook"
+	}
+
+	var tmpl *template.Template
+	if opts.Template != "" {
+		var err error
+		tmpl, err = template.New(name).Funcs(webhookFuncs).Option("missingkey=zero").Parse(opts.Template)
+		if err != nil {
+			return nil, types.NewSystemError(types.ErrInvalid, "invalid webhook template", err).
+				WithContext("sink", name)
+		}
+	}
+
+	client := opts.Client
+	if client == nil {
+		timeout := opts.Timeout
+		if timeout <= 0 {
+			timeout = defaultWebhookTimeout
+		}
+		client = &http.Client{Timeout: timeout}
+	}
+
+	ws := &WebhookSink{
+		name:    name,
+		url:     url,
+		headers: opts.Headers,
+		client:  client,
+		tmpl:    tmpl,
+		sent:    make(map[string]struct{}),
+		nowFunc: time.Now,
+	}
+	if opts.Secret != "" {
+		ws.secret = []byte(opts.Secret)
+	}
+	return ws, nil
+}
+
+// Name 输出端名称
+func (ws *WebhookSink) Name() string {
+	return ws.name
+}
+
+// Emit 投递输出, 非2xx响应视为失败
+// 模板模式下逐条投递, 失败后重试同一批时跳过已成功的输出
+func (ws *WebhookSink) Emit(ctx context.Context, outputs []Output) error {
+	if ws.tmpl == nil {
+		body, err := json.Marshal(outputs)
+		if err != nil {
+			return fmt.Errorf("marshal outputs: %w", err)
+		}
+		return ws.post(ctx, body, "")
+	}
+
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+
+	for i := range outputs {
+		out := &outputs[i]
+		if _, ok := ws.sent[out.ID]; ok {
+			continue
+		}
+		body, err := ws.Render(out)
+		if err != nil {
+			return err
+		}
+		if err := ws.post(ctx, body, out.ID); err != nil {
+			return err
+		}
+		ws.sent[out.ID] = struct{}{}
+	}
+
+	ws.sent = make(map[string]struct{})
+	return nil
+}
+
+// Render 按模板渲染单条输出的请求体, 未设置模板时返回该输出的JSON
+func (ws *WebhookSink) Render(out *Output) ([]byte, error) {
+	if ws.tmpl == nil {
+		return json.Marshal(out)
+	}
+
+	var buf bytes.Buffer
+	if err := ws.tmpl.Execute(&buf, out); err != nil {
+		return nil, fmt.Errorf("render webhook template: %w", err)
+	}
+	if !json.Valid(buf.Bytes()) {
+		return nil, fmt.Errorf("webhook template for output %s rendered invalid JSON", out.ID)
+	}
+	return buf.Bytes(), nil
+}
+
+// post 发送请求
+func (ws *WebhookSink) post(ctx context.Context, body []byte, delivery string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, ws.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range ws.headers {
+		req.Header.Set(k, v)
+	}
+	if delivery != "" {
+		req.Header.Set(DeliveryHeader, delivery)
+	}
+	if ws.secret != nil {
+		ts := strconv.FormatInt(ws.nowFunc().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, SignPayload(ws.secret, ts, body))
+	}
+
+	resp, err := ws.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s returned status %d", ws.url, resp.StatusCode)
+	}
+	return nil
+}
+
+// Close 关闭输出端
+func (ws *WebhookSink) Close() error {
+	ws.client.CloseIdleConnections()
+	return nil
+}
+
+// SignPayload 计算webhook签名: sha256=hex(HMAC-SHA256(secret, timestamp + "." + body))
+func SignPayload(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature 供接收方校验webhook签名; tolerance大于0时拒绝时间戳偏差超出该值的请求
+func VerifySignature(secret []byte, timestamp string, body []byte, signature string, tolerance time.Duration) bool {
+	if tolerance > 0 {
+		sec, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return false
+		}
+		skew := time.Since(time.Unix(sec, 0))
+		if skew < 0 {
+			skew = -skew
+		}
+		if skew > tolerance {
+			return false
+		}
+	}
+	expected := SignPayload(secret, timestamp, body)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
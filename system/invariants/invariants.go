@@ -0,0 +1,175 @@
+// system/invariants/invariants.go
+
+// Package invariants 检查标准化和计算结果的取值范围不变量
+// 默认模式下越界值被截断到范围内; 严格模式下越界以*Violation错误返回并记录, 以暴露本应在范围内的计算错误
+package invariants
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"sync/atomic"
+)
+
+// Mode 越界处理模式
+type Mode int32
+
+const (
+	ModeClamp  Mode = iota // 截断到范围内
+	ModeStrict             // 返回Violation错误并记录
+)
+
+// String 模式名称
+func (m Mode) String() string {
+	switch m {
+	case ModeClamp:
+		return "clamp"
+	case ModeStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("Mode(%d)", int32(m))
+	}
+}
+
+// ErrViolation 所有Violation均包装此错误
+var ErrViolation = errors.New("invariant violation")
+
+// Violation 不变量违反
+type Violation struct {
+	Name  string  // 计算名称, 如"field.coupling.strength"
+	Value float64 // 实际值
+	Min   float64
+	Max   float64
+}
+
+// Error 实现error接口
+func (v *Violation) Error() string {
+	return fmt.Sprintf("invariant violation: %s = %v, want [%v, %v]", v.Name, v.Value, v.Min, v.Max)
+}
+
+// Unwrap 支持errors.Is(err, ErrViolation)
+func (v *Violation) Unwrap() error {
+	return ErrViolation
+}
+
+// 最近违反记录数上限
+const maxRecent = 100
+
+// Stats 违反统计
+type Stats struct {
+	Mode   Mode             `json:"mode"`
+	Total  int64            `json:"total"`
+	ByName map[string]int64 `json:"by_name"`
+	Recent []Violation      `json:"recent"` // 最近的违反, 由旧到新
+}
+
+// recorder 违反记录
+type recorder struct {
+	mu     sync.Mutex
+	total  int64
+	byName map[string]int64
+	recent []Violation
+}
+
+var (
+	mode atomic.Int32
+	rec  = &recorder{byName: make(map[string]int64)}
+)
+
+// SetMode 设置越界处理模式
+func SetMode(m Mode) {
+	mode.Store(int32(m))
+}
+
+// GetMode 当前越界处理模式
+func GetMode() Mode {
+	return Mode(mode.Load())
+}
+
+// Strict 是否为严格模式
+func Strict() bool {
+	return GetMode() == ModeStrict
+}
+
+// GetStats 获取违反统计
+func GetStats() Stats {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+
+	stats := Stats{
+		Mode:   GetMode(),
+		Total:  rec.total,
+		ByName: make(map[string]int64, len(rec.byName)),
+		Recent: append([]Violation(nil), rec.recent...),
+	}
+	for name, n := range rec.byName {
+		stats.ByName[name] = n
+	}
+	return stats
+}
+
+// Reset 清除违反统计
+func Reset() {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.total = 0
+	rec.byName = make(map[string]int64)
+	rec.recent = nil
+}
+
+// record 记录违反
+func record(v Violation) {
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	rec.total++
+	rec.byName[v.Name]++
+	rec.recent = append(rec.recent, v)
+	if len(rec.recent) > maxRecent {
+		rec.recent = rec.recent[len(rec.recent)-maxRecent:]
+	}
+}
+
+// InRange 检查v在[lo, hi]内, NaN视为越界
+func InRange(name string, v, lo, hi float64) error {
+	if v >= lo && v <= hi {
+		return nil
+	}
+	return &Violation{Name: name, Value: v, Min: lo, Max: hi}
+}
+
+// Unit 检查v在[0, 1]内
+func Unit(name string, v float64) error {
+	return InRange(name, v, 0, 1)
+}
+
+// Finite 检查v不是NaN或Inf
+func Finite(name string, v float64) error {
+	return InRange(name, v, -math.MaxFloat64, math.MaxFloat64)
+}
+
+// Clamp 将v限制在[lo, hi]内; NaN在开启边界校验时原样返回以便由边界校验发现, 否则视为lo
+// 严格模式下越界时记录违反并返回*Violation, 返回值仍为截断后的值
+func Clamp(name string, v, lo, hi float64) (float64, error) {
+	if v >= lo && v <= hi {
+		return v, nil
+	}
+
+	clamped := lo
+	if v > hi {
+		clamped = hi
+	} else if math.IsNaN(v) && Validating() {
+		clamped = v
+	}
+	if !Strict() {
+		return clamped, nil
+	}
+	violation := Violation{Name: name, Value: v, Min: lo, Max: hi}
+	record(violation)
+	return clamped, &violation
+}
+
+// ClampUnit 将v限制在[0, 1]内, 见Clamp
+func ClampUnit(name string, v float64) (float64, error) {
+	return Clamp(name, v, 0, 1)
+}
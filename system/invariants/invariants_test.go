@@ -0,0 +1,149 @@
+// system/invariants/invariants_test.go
+
+package invariants
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+const propertyTrials = 2000
+
+// withMode 在测试期间切换越界处理模式, 结束时恢复截断模式并清除统计
+func withMode(t *testing.T, m Mode) {
+	t.Helper()
+	SetMode(m)
+	Reset()
+	t.Cleanup(func() {
+		SetMode(ModeClamp)
+		Reset()
+	})
+}
+
+func TestClampUnitClampMode(t *testing.T) {
+	withMode(t, ModeClamp)
+
+	prop := func(inputs []float64) error {
+		v, err := ClampUnit("test.unit", inputs[0])
+		if err != nil {
+			return err
+		}
+		return Unit("test.unit", v)
+	}
+	if err := ForAll("clamp-unit", propertyTrials, 1, prop, Edges(Floats(-2, 2), true)); err != nil {
+		t.Fatal(err)
+	}
+	if stats := GetStats(); stats.Total != 0 {
+		t.Errorf("clamp mode recorded %d violations", stats.Total)
+	}
+}
+
+// 开启边界校验时NaN原样返回, 由边界校验发现
+func TestClampPassesNaNToBoundaryValidation(t *testing.T) {
+	withMode(t, ModeClamp)
+
+	if v, err := ClampUnit("test.unit", math.NaN()); err != nil || v != 0 {
+		t.Errorf("ClampUnit(NaN) without validation = %v, %v, want 0, nil", v, err)
+	}
+
+	SetValidationMode(ValidationWarn)
+	defer SetValidationMode(ValidationOff)
+	if v, err := ClampUnit("test.unit", math.NaN()); err != nil || !math.IsNaN(v) {
+		t.Errorf("ClampUnit(NaN) with validation = %v, %v, want NaN, nil", v, err)
+	}
+}
+
+func TestClampUnitStrictMode(t *testing.T) {
+	withMode(t, ModeStrict)
+
+	violations := int64(0)
+	prop := func(inputs []float64) error {
+		v, err := ClampUnit("test.unit", inputs[0])
+		if inputs[0] >= 0 && inputs[0] <= 1 {
+			if err != nil {
+				return err
+			}
+			return Unit("test.unit", v)
+		}
+
+		var violation *Violation
+		if !errors.As(err, &violation) {
+			return errors.New("out-of-range input did not return *Violation")
+		}
+		if !errors.Is(err, ErrViolation) {
+			return errors.New("violation does not wrap ErrViolation")
+		}
+		if violation.Name != "test.unit" || violation.Min != 0 || violation.Max != 1 {
+			return errors.New("violation does not describe the check")
+		}
+		violations++
+		return nil
+	}
+	if err := ForAll("strict-unit", propertyTrials, 1, prop, Edges(Floats(-2, 2), true)); err != nil {
+		t.Fatal(err)
+	}
+	if violations == 0 {
+		t.Fatal("no out-of-range inputs generated, test is vacuous")
+	}
+
+	stats := GetStats()
+	if stats.Mode != ModeStrict || stats.Total != violations || stats.ByName["test.unit"] != violations {
+		t.Errorf("stats = %+v, want %d violations of test.unit", stats, violations)
+	}
+	if len(stats.Recent) != maxRecent {
+		t.Errorf("recent violations = %d, want %d", len(stats.Recent), maxRecent)
+	}
+}
+
+func TestForAllReportsReproducibleCounterexample(t *testing.T) {
+	prop := func(inputs []float64) error {
+		return Unit("test.sum", inputs[0]+inputs[1])
+	}
+	gens := []Gen{Floats(0, 1), Floats(0, 1)}
+
+	err := ForAll("sum", propertyTrials, 7, prop, gens...)
+	var first *Counterexample
+	if !errors.As(err, &first) {
+		t.Fatalf("ForAll() error = %v, want *Counterexample", err)
+	}
+	if first.Seed != 7 || first.Inputs[0]+first.Inputs[1] <= 1 {
+		t.Errorf("counterexample %+v does not violate the property", first)
+	}
+	var violation *Violation
+	if !errors.As(err, &violation) {
+		t.Errorf("counterexample does not unwrap to the property's *Violation")
+	}
+
+	again := ForAll("sum", propertyTrials, 7, prop, gens...).(*Counterexample)
+	if again.Trial != first.Trial || again.Inputs[0] != first.Inputs[0] || again.Inputs[1] != first.Inputs[1] {
+		t.Errorf("same seed gave %+v, then %+v", first, again)
+	}
+}
+
+func TestEdgesIncludesNonFinite(t *testing.T) {
+	seen := map[string]bool{}
+	prop := func(inputs []float64) error {
+		switch v := inputs[0]; {
+		case math.IsNaN(v):
+			seen["nan"] = true
+		case math.IsInf(v, 1):
+			seen["+inf"] = true
+		case math.IsInf(v, -1):
+			seen["-inf"] = true
+		}
+		return nil
+	}
+	if err := ForAll("edges", propertyTrials, 1, prop, Edges(Floats(0, 1), true)); err != nil {
+		t.Fatal(err)
+	}
+	if len(seen) != 3 {
+		t.Errorf("non-finite edges seen = %v, want NaN, +Inf and -Inf", seen)
+	}
+
+	if err := ForAll("finite-edges", propertyTrials, 1, func(inputs []float64) error {
+		return Finite("test.edge", inputs[0])
+	}, Edges(Floats(0, 1), false)); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,89 @@
+// system/invariants/property.go
+
+package invariants
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+)
+
+// Gen 随机输入生成器
+type Gen func(r *rand.Rand) float64
+
+// Floats [lo, hi)内均匀分布的输入
+func Floats(lo, hi float64) Gen {
+	return func(r *rand.Rand) float64 {
+		return lo + r.Float64()*(hi-lo)
+	}
+}
+
+// 边界输入
+var edgeValues = []float64{
+	0, 1, -1, 0.5, math.SmallestNonzeroFloat64, -math.SmallestNonzeroFloat64,
+	1e-12, 1 - 1e-12, 1 + 1e-12, 1e12, -1e12, math.MaxFloat64, -math.MaxFloat64,
+	math.Pi, -math.Pi, 2 * math.Pi,
+}
+
+// Edges 以一定比例混入边界值的输入, nonFinite为true时还包含NaN和Inf
+func Edges(base Gen, nonFinite bool) Gen {
+	edges := append([]float64(nil), edgeValues...)
+	if nonFinite {
+		edges = append(edges, math.NaN(), math.Inf(1), math.Inf(-1))
+	}
+	return func(r *rand.Rand) float64 {
+		if r.Intn(4) == 0 {
+			return edges[r.Intn(len(edges))]
+		}
+		return base(r)
+	}
+}
+
+// Counterexample 反例
+type Counterexample struct {
+	Property string    // 性质名称
+	Trial    int       // 第几次试验
+	Seed     int64     // 复现用的种子
+	Inputs   []float64 // 输入
+	Err      error     // 性质返回的错误
+}
+
+// Error 实现error接口
+func (c *Counterexample) Error() string {
+	return fmt.Sprintf("property %s failed at trial %d (seed %d) with inputs %v: %v",
+		c.Property, c.Trial, c.Seed, c.Inputs, c.Err)
+}
+
+// Unwrap 返回性质的错误
+func (c *Counterexample) Unwrap() error {
+	return c.Err
+}
+
+// ForAll 以gens生成n组输入检查性质prop, 返回首个反例; 相同种子生成相同输入
+// 可在测试中以 if err := invariants.ForAll(...); err != nil { t.Fatal(err) } 使用
+func ForAll(name string, n int, seed int64, prop func(inputs []float64) error, gens ...Gen) error {
+	r := rand.New(rand.NewSource(seed))
+	inputs := make([]float64, len(gens))
+	for trial := 0; trial < n; trial++ {
+		for i, gen := range gens {
+			inputs[i] = gen(r)
+		}
+		if err := prop(inputs); err != nil {
+			return &Counterexample{
+				Property: name,
+				Trial:    trial,
+				Seed:     seed,
+				Inputs:   append([]float64(nil), inputs...),
+				Err:      err,
+			}
+		}
+	}
+	return nil
+}
+
+// UnitProperty 函数输出在[0, 1]内的性质
+func UnitProperty(name string, fn func(inputs []float64) float64) func(inputs []float64) error {
+	return func(inputs []float64) error {
+		return Unit(name, fn(inputs))
+	}
+}
@@ -0,0 +1,139 @@
+// system/invariants/validation.go
+
+package invariants
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"sync/atomic"
+)
+
+// ValidationMode 模块边界上NaN/Inf的处理方式
+type ValidationMode int32
+
+const (
+	ValidationOff    ValidationMode = iota // 不检查
+	ValidationWarn                         // 记录并输出日志, 值照常传递
+	ValidationStrict                       // 记录并拒绝, 以*NonFiniteError返回
+)
+
+// String 模式名称
+func (m ValidationMode) String() string {
+	switch m {
+	case ValidationOff:
+		return "off"
+	case ValidationWarn:
+		return "warn"
+	case ValidationStrict:
+		return "strict"
+	default:
+		return fmt.Sprintf("ValidationMode(%d)", int32(m))
+	}
+}
+
+// ParseValidationMode 解析模式名称
+func ParseValidationMode(s string) (ValidationMode, error) {
+	switch s {
+	case "", "off":
+		return ValidationOff, nil
+	case "warn":
+		return ValidationWarn, nil
+	case "strict":
+		return ValidationStrict, nil
+	default:
+		return ValidationOff, fmt.Errorf("unknown validation mode: %q", s)
+	}
+}
+
+// ErrNonFinite 所有NonFiniteError均包装此错误
+var ErrNonFinite = errors.New("non-finite value")
+
+// NonFiniteError 模块边界上的NaN/Inf
+type NonFiniteError struct {
+	Boundary string  // 模块边界, 如"emergence.detector"
+	Name     string  // 产生该值的计算
+	Value    float64 // NaN或Inf
+}
+
+// Error 实现error接口
+func (e *NonFiniteError) Error() string {
+	return fmt.Sprintf("non-finite value at %s: %s = %v", e.Boundary, e.Name, e.Value)
+}
+
+// Unwrap 支持errors.Is(err, ErrNonFinite)
+func (e *NonFiniteError) Unwrap() error {
+	return ErrNonFinite
+}
+
+var validationMode atomic.Int32
+
+// SetValidationMode 设置边界校验模式
+func SetValidationMode(m ValidationMode) {
+	validationMode.Store(int32(m))
+}
+
+// GetValidationMode 当前边界校验模式
+func GetValidationMode() ValidationMode {
+	return ValidationMode(validationMode.Load())
+}
+
+// Validating 是否开启边界校验, 供调用方在组装待校验的值之前判断
+func Validating() bool {
+	return GetValidationMode() != ValidationOff
+}
+
+// ValidateValue 校验边界上的单个值
+// 关闭时不检查; 警告模式下记录并输出日志, 返回nil; 严格模式下记录并返回*NonFiniteError
+func ValidateValue(boundary, name string, v float64) error {
+	m := GetValidationMode()
+	if m == ValidationOff || !isNonFinite(v) {
+		return nil
+	}
+	return reportNonFinite(m, &NonFiniteError{Boundary: boundary, Name: name, Value: v})
+}
+
+// ValidateMetrics 校验边界上的一组值, 所有非有限值都被记录, 严格模式下返回按名称排序的首个
+func ValidateMetrics(boundary string, values map[string]float64) error {
+	m := GetValidationMode()
+	if m == ValidationOff {
+		return nil
+	}
+
+	names := make([]string, 0)
+	for name, v := range values {
+		if isNonFinite(v) {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return nil
+	}
+	sort.Strings(names)
+
+	var first error
+	for _, name := range names {
+		err := reportNonFinite(m, &NonFiniteError{Boundary: boundary, Name: name, Value: values[name]})
+		if first == nil {
+			first = err
+		}
+	}
+	return first
+}
+
+// reportNonFinite 记录非有限值, 警告模式输出日志, 严格模式返回错误
+func reportNonFinite(m ValidationMode, e *NonFiniteError) error {
+	record(Violation{Name: e.Boundary + "." + e.Name, Value: e.Value, Min: -math.MaxFloat64, Max: math.MaxFloat64})
+	if m == ValidationStrict {
+		return e
+	}
+	log.Printf("[validation] %v", e)
+	return nil
+}
+
+// isNonFinite 是否为NaN或Inf
+func isNonFinite(v float64) bool {
+	return math.IsNaN(v) || math.IsInf(v, 0)
+}
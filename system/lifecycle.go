@@ -0,0 +1,292 @@
+// system/lifecycle.go
+
+package system
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/faultinject"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 生命周期阶段
+const (
+	PhaseStart    = "start"
+	PhaseStop     = "stop"
+	PhaseRollback = "rollback"
+)
+
+// LifecycleRecord 子系统启停记录
+type LifecycleRecord struct {
+	Component    string        `json:"component"`
+	Phase        string        `json:"phase"`
+	Level        int           `json:"level"` // 依赖层级, 同层子系统并行启停
+	Dependencies []string      `json:"dependencies"`
+	StartedAt    time.Time     `json:"started_at"`
+	Duration     time.Duration `json:"duration"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// lifecycleState 最近一次启停的记录
+type lifecycleState struct {
+	mu      sync.Mutex
+	records []LifecycleRecord
+}
+
+// coreLifecycle 核心引擎的生命周期适配
+type coreLifecycle struct {
+	engine *core.Engine
+}
+
+// Start 初始化引擎
+func (c coreLifecycle) Start(ctx context.Context) error {
+	return c.engine.Initialize()
+}
+
+// Stop 关闭引擎
+func (c coreLifecycle) Stop() error {
+	return c.engine.Shutdown()
+}
+
+// Status 引擎初始化后即可使用, 视为运行中
+func (c coreLifecycle) Status() string {
+	switch status := c.engine.Status(); core.Status(status) {
+	case core.StatusInitialized, core.StatusRunning:
+		return string(core.StatusRunning)
+	default:
+		return status
+	}
+}
+
+// Health 健康度, 运行中为1
+func (c coreLifecycle) Health() float64 {
+	if c.Status() == string(core.StatusRunning) {
+		return 1
+	}
+	return 0
+}
+
+// LifecycleReport 获取最近一次子系统启停记录, 按执行顺序排列
+func (s *System) LifecycleReport() []LifecycleRecord {
+	s.lifecycle.mu.Lock()
+	defer s.lifecycle.mu.Unlock()
+	return append([]LifecycleRecord(nil), s.lifecycle.records...)
+}
+
+// subsystems 参与生命周期编排的子系统
+func (s *System) subsystems() map[string]types.Lifecycle {
+	components := make(map[string]types.Lifecycle, 6)
+	if s.core != nil {
+		components["core"] = coreLifecycle{s.core}
+	}
+	if s.common != nil {
+		components["common"] = s.common
+	}
+	if s.control != nil {
+		components["control"] = s.control
+	}
+	if s.evolution != nil {
+		components["evolution"] = s.evolution
+	}
+	if s.meta != nil {
+		components["meta"] = s.meta
+	}
+	if s.monitor != nil {
+		components["monitor"] = s.monitor
+	}
+	return components
+}
+
+// lifecycleLevels 按依赖关系分层, 同层子系统互不依赖, 层内按名称排序
+func lifecycleLevels(components map[string]types.Lifecycle, deps map[string][]string) ([][]string, error) {
+	indegree := make(map[string]int, len(components))
+	dependents := make(map[string][]string)
+	for name := range components {
+		indegree[name] = 0
+	}
+	for name := range components {
+		for _, dep := range deps[name] {
+			if _, ok := components[dep]; !ok {
+				return nil, types.NewSystemError(types.ErrConfig, "unknown subsystem dependency", nil).
+					WithContext("component", name).
+					WithContext("dependency", dep)
+			}
+			indegree[name]++
+			dependents[dep] = append(dependents[dep], name)
+		}
+	}
+
+	levels := make([][]string, 0)
+	remaining := len(components)
+	for remaining > 0 {
+		level := make([]string, 0)
+		for name, degree := range indegree {
+			if degree == 0 {
+				level = append(level, name)
+			}
+		}
+		if len(level) == 0 {
+			cycle := make([]string, 0, len(indegree))
+			for name := range indegree {
+				cycle = append(cycle, name)
+			}
+			sort.Strings(cycle)
+			return nil, types.NewSystemError(types.ErrConfig, "subsystem dependency cycle", nil).
+				WithContext("components", cycle)
+		}
+
+		sort.Strings(level)
+		for _, name := range level {
+			delete(indegree, name)
+			for _, dependent := range dependents[name] {
+				indegree[dependent]--
+			}
+		}
+		levels = append(levels, level)
+		remaining -= len(level)
+	}
+	return levels, nil
+}
+
+// startSubsystems 按依赖层级启动子系统, 同层并行
+func (s *System) startSubsystems() error {
+	return s.startLifecycle(s.subsystems(), s.GetDependencies())
+}
+
+// stopSubsystems 按依赖层级逆序停止子系统, 同层并行
+func (s *System) stopSubsystems() error {
+	return s.stopLifecycle(s.subsystems(), s.GetDependencies())
+}
+
+// startLifecycle 按依赖层级启动组件
+// 任一组件启动失败时按相反顺序停止已启动的组件, 返回每个失败组件的诊断错误
+func (s *System) startLifecycle(components map[string]types.Lifecycle, deps map[string][]string) error {
+	levels, err := lifecycleLevels(components, deps)
+	if err != nil {
+		return err
+	}
+
+	s.resetLifecycleRecords()
+	started := make([][]string, 0, len(levels))
+	for i, level := range levels {
+		records, opErrs := s.runLevel(PhaseStart, i, level, deps, func(name string) error {
+			if err := faultinject.Fire(faultinject.PointSubsystemStart, name); err != nil {
+				return err
+			}
+			return components[name].Start(s.ctx)
+		})
+
+		succeeded := make([]string, 0, len(level))
+		failed := make([]int, 0)
+		for j, rec := range records {
+			if opErrs[j] == nil {
+				succeeded = append(succeeded, rec.Component)
+			} else {
+				failed = append(failed, j)
+			}
+		}
+		started = append(started, succeeded)
+		if len(failed) == 0 {
+			continue
+		}
+
+		rollbackErr := s.stopLevels(PhaseRollback, components, deps, started)
+		rolledBack := make([]string, 0)
+		for _, names := range started {
+			rolledBack = append(rolledBack, names...)
+		}
+
+		errs := make([]error, 0, len(failed))
+		for _, j := range failed {
+			rec := records[j]
+			sysErr := types.NewSystemError(types.ErrComponent, "failed to start subsystem", opErrs[j]).
+				WithContext("component", rec.Component).
+				WithContext("dependencies", rec.Dependencies).
+				WithContext("level", rec.Level).
+				WithContext("duration", rec.Duration.String()).
+				WithContext("rolled_back", rolledBack)
+			if rollbackErr != nil {
+				sysErr.WithContext("rollback_error", rollbackErr.Error())
+			}
+			errs = append(errs, sysErr)
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// stopLifecycle 按依赖层级逆序停止组件
+// 某个组件停止失败不影响其余组件继续停止
+func (s *System) stopLifecycle(components map[string]types.Lifecycle, deps map[string][]string) error {
+	levels, err := lifecycleLevels(components, deps)
+	if err != nil {
+		return err
+	}
+
+	s.resetLifecycleRecords()
+	return s.stopLevels(PhaseStop, components, deps, levels)
+}
+
+// stopLevels 逆序停止各层子系统, 返回所有停止错误
+func (s *System) stopLevels(phase string, components map[string]types.Lifecycle, deps map[string][]string, levels [][]string) error {
+	errs := make([]error, 0)
+	for i := len(levels) - 1; i >= 0; i-- {
+		records, opErrs := s.runLevel(phase, i, levels[i], deps, func(name string) error {
+			return components[name].Stop()
+		})
+		for j, rec := range records {
+			if opErrs[j] != nil {
+				errs = append(errs, types.NewSystemError(types.ErrComponent, "failed to stop subsystem", opErrs[j]).
+					WithContext("component", rec.Component).
+					WithContext("phase", phase).
+					WithContext("level", rec.Level))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// runLevel 并行执行同一层的启停操作并记录结果, 错误与记录按下标对应
+func (s *System) runLevel(phase string, level int, names []string, deps map[string][]string, op func(name string) error) ([]LifecycleRecord, []error) {
+	records := make([]LifecycleRecord, len(names))
+	errs := make([]error, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+
+			rec := LifecycleRecord{
+				Component:    name,
+				Phase:        phase,
+				Level:        level,
+				Dependencies: append([]string(nil), deps[name]...),
+				StartedAt:    time.Now(),
+			}
+			err := op(name)
+			rec.Duration = time.Since(rec.StartedAt)
+			if err != nil {
+				rec.Error = err.Error()
+			}
+			records[i], errs[i] = rec, err
+		}(i, name)
+	}
+	wg.Wait()
+
+	s.lifecycle.mu.Lock()
+	s.lifecycle.records = append(s.lifecycle.records, records...)
+	s.lifecycle.mu.Unlock()
+	return records, errs
+}
+
+// resetLifecycleRecords 清空上一次启停记录
+func (s *System) resetLifecycleRecords() {
+	s.lifecycle.mu.Lock()
+	s.lifecycle.records = make([]LifecycleRecord, 0)
+	s.lifecycle.mu.Unlock()
+}
@@ -0,0 +1,151 @@
+// system/memory.go
+
+package system
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// defaultMemoryInterval 缓存内存检查的默认间隔
+const defaultMemoryInterval = time.Minute
+
+// MemoryReport 缓存内存占用报告
+type MemoryReport struct {
+	Timestamp time.Time           `json:"timestamp"`
+	Entries   int                 `json:"entries"`
+	Bytes     int64               `json:"bytes"` // 近似字节数
+	Caches    []types.MemoryUsage `json:"caches"`
+}
+
+// cacheUsage 缓存占用及其所属的报告者
+type cacheUsage struct {
+	usage    types.MemoryUsage
+	reporter types.MemoryReporter
+}
+
+// MemoryReport 汇总追踪分析、模式检测和学习缓存的近似内存占用, 按缓存名称排序
+// 字节数为按类型递归估算的近似值, 用于比较和软上限检查
+func (s *System) MemoryReport() MemoryReport {
+	report := MemoryReport{Timestamp: time.Now()}
+	for _, c := range s.cacheUsages() {
+		report.Entries += c.usage.Entries
+		report.Bytes += c.usage.Bytes
+		report.Caches = append(report.Caches, c.usage)
+	}
+	return report
+}
+
+// cacheUsages 各缓存的占用, 按配置填充软上限
+func (s *System) cacheUsages() []cacheUsage {
+	limits := s.memoryLimits()
+
+	usages := make([]cacheUsage, 0)
+	for _, reporter := range s.memoryReporters() {
+		for _, usage := range reporter.MemoryUsage() {
+			if limit := limits.Limits[usage.Cache]; limit > 0 {
+				usage.Limit = limit
+				usage.Exceeded = usage.Bytes > limit
+			}
+			usages = append(usages, cacheUsage{usage: usage, reporter: reporter})
+		}
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		return usages[i].usage.Cache < usages[j].usage.Cache
+	})
+	return usages
+}
+
+// memoryReporters 报告缓存占用的组件
+func (s *System) memoryReporters() []types.MemoryReporter {
+	reporters := make([]types.MemoryReporter, 0, 3)
+	if s.monitor != nil {
+		if analyzer := s.monitor.GetTraceAnalyzer(); analyzer != nil {
+			reporters = append(reporters, analyzer)
+		}
+	}
+	if s.meta != nil {
+		if detector := s.meta.GetDetector(); detector != nil {
+			reporters = append(reporters, detector)
+		}
+	}
+	if s.evolution != nil {
+		if learning := s.evolution.GetLearning(); learning != nil {
+			reporters = append(reporters, learning)
+		}
+	}
+	return reporters
+}
+
+// memoryLimits 缓存软上限配置
+func (s *System) memoryLimits() types.MemoryLimitConfig {
+	if s.config.MemoryLimits == nil {
+		return types.MemoryLimitConfig{}
+	}
+	return *s.config.MemoryLimits
+}
+
+// startMemoryLimits 配置了软上限时周期检查缓存占用, 调用方需持有锁
+func (s *System) startMemoryLimits() {
+	limits := s.memoryLimits()
+	if len(limits.Limits) == 0 {
+		return
+	}
+	interval := limits.Interval
+	if interval <= 0 {
+		interval = defaultMemoryInterval
+	}
+
+	s.stopMemoryLimits()
+	s.registerJob(JobMemoryLimits, "system", interval, func(ctx context.Context) error {
+		s.enforceMemoryLimits()
+		return nil
+	})
+}
+
+// stopMemoryLimits 停止缓存占用检查, 调用方需持有锁
+func (s *System) stopMemoryLimits() {
+	s.common.GetScheduler().Unregister(JobMemoryLimits)
+}
+
+// enforceMemoryLimits 检查缓存软上限
+// 配置驱逐时按超出比例缩减条目数并发出驱逐事件, 否则发出超限警告事件
+func (s *System) enforceMemoryLimits() {
+	evict := s.memoryLimits().Evict
+	for _, c := range s.cacheUsages() {
+		usage := c.usage
+		if !usage.Exceeded {
+			continue
+		}
+
+		if !evict || usage.Entries == 0 {
+			s.HandleEvent(types.SystemEvent{
+				Type:      types.EventMemoryLimitExceeded,
+				Source:    usage.Cache,
+				Timestamp: time.Now(),
+				Message:   "cache exceeds memory soft limit",
+				Priority:  types.PriorityNormal,
+				Data:      usage,
+			})
+			continue
+		}
+
+		keep := int(float64(usage.Entries) * float64(usage.Limit) / float64(usage.Bytes))
+		evicted := c.reporter.EvictMemory(usage.Cache, keep)
+		s.HandleEvent(types.SystemEvent{
+			Type:      types.EventMemoryEvicted,
+			Source:    usage.Cache,
+			Timestamp: time.Now(),
+			Message:   "cache entries evicted to memory soft limit",
+			Priority:  types.PriorityNormal,
+			Data: types.MemoryEviction{
+				Usage:   usage,
+				Evicted: evicted,
+				Kept:    usage.Entries - evicted,
+			},
+		})
+	}
+}
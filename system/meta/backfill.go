@@ -0,0 +1,73 @@
+// system/meta/backfill.go
+
+package meta
+
+import (
+	"context"
+
+	"github.com/Corphon/daoflow/system/encryption"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Backfill 以记录的场状态快照离线重新执行检测, 结果写入新建的命名空间ns
+// 命名空间不随管理器启动, 检测历史、活跃模式和演化历史可按命名空间查询和导出, 不再需要时以RemoveNamespace移除.
+// 回填过程中命名空间已可见; 出错时保留已处理部分的结果
+func (m *Manager) Backfill(ctx context.Context, ns types.Namespace, source emergence.SnapshotSource, opts emergence.BackfillOptions) (*emergence.BackfillResult, error) {
+	if ns == "" || ns == types.DefaultNamespace {
+		return nil, types.NewSystemError(types.ErrInvalid, "backfill requires a separate namespace", nil)
+	}
+
+	m.mu.Lock()
+	if _, exists := m.components.namespaces[ns]; exists {
+		m.mu.Unlock()
+		return nil, types.NewSystemError(types.ErrExists, "namespace already exists", nil).
+			WithContext("namespace", ns)
+	}
+	domain, err := m.newNamespaceDomain(&types.NamespaceConfig{Name: ns})
+	if err != nil {
+		m.mu.Unlock()
+		return nil, err
+	}
+	domain.offline = true
+	m.components.namespaces[ns] = domain
+	m.mu.Unlock()
+
+	return domain.detector.Backfill(ctx, source, opts)
+}
+
+// openJournal 按配置打开场状态快照日志并接入主检测器(调用方持有锁)
+func (m *Manager) openJournal() error {
+	if m.config.Emergence.Journal == "" || m.components.journal != nil {
+		return nil
+	}
+	journal, err := emergence.OpenFieldJournal(m.config.Emergence.Journal)
+	if err != nil {
+		return err
+	}
+	journal.SetKeyring(m.components.keyring)
+	m.components.journal = journal
+	m.components.detector.SetJournal(journal)
+	return nil
+}
+
+// SetKeyring 设置场状态快照日志的加密钥匙串, 对已打开的日志立即生效
+func (m *Manager) SetKeyring(kr *encryption.Keyring) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.components.keyring = kr
+	if m.components.journal != nil {
+		m.components.journal.SetKeyring(kr)
+	}
+}
+
+// closeJournal 停止记录并关闭场状态快照日志(调用方持有锁)
+func (m *Manager) closeJournal() error {
+	if m.components.journal == nil {
+		return nil
+	}
+	m.components.detector.SetJournal(nil)
+	err := m.components.journal.Close()
+	m.components.journal = nil
+	return err
+}
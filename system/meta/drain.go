@@ -0,0 +1,43 @@
+// system/meta/drain.go
+
+package meta
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Drain 停止接收观测, 等待各命名空间进行中的检测完成并同步场状态快照日志
+func (m *Manager) Drain(ctx context.Context) error {
+	m.mu.Lock()
+	m.state.draining = true
+	detectors := make(map[types.Namespace]*emergence.PatternDetector, len(m.components.namespaces)+1)
+	if m.components.detector != nil {
+		detectors[types.DefaultNamespace] = m.components.detector
+	}
+	for ns, domain := range m.components.namespaces {
+		if domain.running {
+			detectors[ns] = domain.detector
+		}
+	}
+	m.mu.Unlock()
+
+	errs := make([]error, 0)
+	for ns, detector := range detectors {
+		if err := detector.Drain(ctx); err != nil {
+			errs = append(errs, types.NewSystemError(types.ErrState, "failed to drain pattern detector", err).
+				WithContext("namespace", ns))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Draining 是否正在排空
+func (m *Manager) Draining() bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.state.draining
+}
@@ -0,0 +1,56 @@
+// system/meta/emergence/annotation.go
+
+package emergence
+
+import (
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Tag 合并活跃模式的标签, 值为空的标签表示删除该键
+func (pd *PatternDetector) Tag(id string, tags map[string]string) error {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pattern, err := pd.activePattern(id)
+	if err != nil {
+		return err
+	}
+	// 整体替换, 已取得的模式副本与检测器共享标注
+	pattern.Annotations = pattern.Annotations.WithTags(tags)
+	pd.state.version++
+	return nil
+}
+
+// AddNote 为活跃模式追加自由文本标注, 时间为零时取检测时钟
+func (pd *PatternDetector) AddNote(id string, note types.Note) error {
+	if note.Text == "" {
+		return types.NewSystemError(types.ErrInvalid, "empty note", nil).
+			WithContext("pattern", id)
+	}
+
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pattern, err := pd.activePattern(id)
+	if err != nil {
+		return err
+	}
+	if note.At.IsZero() {
+		note.At = pd.now()
+	}
+	pattern.Annotations = pattern.Annotations.WithNote(note)
+	pd.state.version++
+	return nil
+}
+
+// FindPatterns 满足全部标签条件的活跃模式, 条件为空时返回全部活跃模式
+func (pd *PatternDetector) FindPatterns(tags map[string]string) []EmergentPattern {
+	patterns := make([]EmergentPattern, 0)
+	pd.GetActivePatternsSnapshot().Range(func(p EmergentPattern) bool {
+		if p.Annotations.MatchTags(tags) {
+			patterns = append(patterns, p)
+		}
+		return true
+	})
+	return patterns
+}
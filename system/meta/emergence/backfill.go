@@ -0,0 +1,132 @@
+// system/meta/emergence/backfill.go
+
+package emergence
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// BackfillOptions 回填选项
+type BackfillOptions struct {
+	From             time.Time // 只处理不早于From的快照, 零值不限
+	To               time.Time // 只处理早于To的快照, 零值不限
+	Sensitivity      float64   // 检测灵敏度, 非正值保持检测器配置
+	MinConfidence    float64   // 最小置信度, 非正值保持检测器配置
+	PatternThreshold float64   // 模式阈值, 非正值保持检测器配置
+}
+
+// BackfillResult 回填结果
+type BackfillResult struct {
+	Namespace string        // 写入的命名空间
+	Snapshots int           // 参与检测的快照数
+	Skipped   int           // 超出时间范围、缺少时间戳或时间倒退而跳过的快照数
+	Detected  int           // 检测到的新模式数
+	Active    int           // 回填结束时的活跃模式数
+	Start     time.Time     // 首个快照时间
+	End       time.Time     // 末个快照时间
+	Elapsed   time.Duration // 回填耗时
+}
+
+// Backfill 按时间顺序以记录的场状态快照重新执行检测, 重建活跃模式、演化历史和检测历史
+// 检测时间取快照时间; 不通知监听器, 不写入快照日志. 检测器应为回填专用, 不与检测循环同时运行.
+// ctx取消或快照无法读取时返回已处理部分的结果和错误
+func (pd *PatternDetector) Backfill(ctx context.Context, source SnapshotSource, opts BackfillOptions) (*BackfillResult, error) {
+	if source == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil snapshot source", nil)
+	}
+	if !opts.From.IsZero() && !opts.To.IsZero() && !opts.From.Before(opts.To) {
+		return nil, types.NewSystemError(types.ErrInvalid, "backfill range is empty", nil)
+	}
+
+	pd.Configure(opts.Sensitivity, opts.MinConfidence, 0)
+	pd.SetPatternThreshold(opts.PatternThreshold)
+
+	started := time.Now()
+	result := &BackfillResult{Namespace: pd.GetNamespace()}
+	defer func() {
+		result.Active = len(pd.GetActivePatterns())
+		result.Elapsed = time.Since(started)
+	}()
+
+	var last time.Time
+	for {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+
+		state, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return result, nil
+		}
+		if err != nil {
+			return result, err
+		}
+
+		at := state.Timestamp
+		if at.IsZero() || at.Before(last) ||
+			(!opts.From.IsZero() && at.Before(opts.From)) ||
+			(!opts.To.IsZero() && !at.Before(opts.To)) {
+			result.Skipped++
+			continue
+		}
+		last = at
+
+		result.Detected += len(pd.backfillState(state, at))
+		result.Snapshots++
+		if result.Start.IsZero() {
+			result.Start = at
+		}
+		result.End = at
+	}
+}
+
+// backfillState 以快照时间为检测时间处理一个快照, 返回新模式
+func (pd *PatternDetector) backfillState(state *model.FieldState, at time.Time) []EmergentPattern {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pd.clock = func() time.Time { return at }
+	defer func() { pd.clock = nil }()
+
+	newPatterns, _ := pd.detectState(state, false)
+	pd.state.lastUpdate = at
+	return newPatterns
+}
+
+// ReplayStep 回放一个快照后的检测结果
+type ReplayStep struct {
+	Time     time.Time         // 快照时间
+	Detected []EmergentPattern // 检测到的新模式
+	Active   []EmergentPattern // 检测后的活跃模式
+}
+
+// Replay 以复制模板检测器的独立检测器按阈值组合回放快照, 每处理一个快照调用fn
+// 缺少时间戳或时间倒退的快照被跳过; 不影响模板
+func Replay(ctx context.Context, template *PatternDetector, snapshots []*model.FieldState, params TuningParams, fn func(ReplayStep)) error {
+	if template == nil {
+		return types.NewSystemError(types.ErrInvalid, "nil template detector", nil)
+	}
+
+	pd := template.replica()
+	params.Apply(pd)
+
+	var last time.Time
+	for _, state := range snapshots {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if state == nil || state.Timestamp.IsZero() || state.Timestamp.Before(last) {
+			continue
+		}
+		last = state.Timestamp
+		detected := pd.backfillState(state, last)
+		fn(ReplayStep{Time: last, Detected: detected, Active: pd.GetActivePatterns()})
+	}
+	return nil
+}
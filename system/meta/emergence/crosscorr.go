@@ -0,0 +1,232 @@
+// system/meta/emergence/crosscorr.go
+
+package emergence
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// 相关分析序列
+const (
+	SeriesStrength = "strength" // 模式强度序列
+	SeriesEnergy   = "energy"   // 模式能量序列
+)
+
+// 相关分析默认参数
+const (
+	defaultCorrelationWindow    = 10 * time.Minute
+	defaultCorrelationMaxLag    = 5
+	defaultCorrelationThreshold = 0.6
+	minCorrelationOverlap       = 4    // 计算相关系数所需的最少重叠样本数
+	maxCorrelationPoints        = 1000 // 对齐网格的最大点数, 超出时放大步长
+)
+
+// CorrelationOptions 模式间互相关分析参数, 零值字段使用默认值
+type CorrelationOptions struct {
+	Series    string        // 分析的序列, strength或energy
+	Window    time.Duration // 分析窗口
+	Step      time.Duration // 对齐网格的步长, 默认为检测间隔
+	MaxLag    int           // 最大滞后步数
+	Threshold float64       // 标记领先/滞后关系的最小相关系数绝对值
+}
+
+// LeadLag 领先/滞后关系, Leader的变化在Lag步后出现在Follower上
+type LeadLag struct {
+	Leader      string        `json:"leader"`
+	Follower    string        `json:"follower"`
+	Lag         int           `json:"lag"`
+	Delay       time.Duration `json:"delay"`
+	Correlation float64       `json:"correlation"`
+}
+
+// CorrelationMatrix 模式间互相关矩阵
+// Values[i][j]为模式i与模式j在各滞后上绝对值最大的相关系数,
+// Lags[i][j]为对应滞后, 正值表示模式i领先模式j
+type CorrelationMatrix struct {
+	Patterns  []string      `json:"patterns"`
+	Series    string        `json:"series"`
+	Window    time.Duration `json:"window"`
+	Step      time.Duration `json:"step"`
+	Values    [][]float64   `json:"values"`
+	Lags      [][]int       `json:"lags"`
+	Relations []LeadLag     `json:"relations"` // 按相关系数绝对值降序
+	Generated time.Time     `json:"generated"`
+}
+
+// CorrelationMatrix 计算活跃模式在窗口内的互相关矩阵
+// 各模式的演化序列按步长线性插值到共同的时间网格, 每对模式只使用双方都有数据的网格点
+func (pd *PatternDetector) CorrelationMatrix(opts CorrelationOptions) CorrelationMatrix {
+	if opts.Step <= 0 {
+		opts.Step = pd.EffectiveInterval()
+	}
+	patterns := pd.GetActivePatterns()
+	return CorrelatePatterns(patterns, opts, time.Now())
+}
+
+// CorrelatePatterns 计算给定模式在截至now的窗口内的互相关矩阵
+func CorrelatePatterns(patterns []EmergentPattern, opts CorrelationOptions, now time.Time) CorrelationMatrix {
+	if opts.Series != SeriesEnergy {
+		opts.Series = SeriesStrength
+	}
+	if opts.Window <= 0 {
+		opts.Window = defaultCorrelationWindow
+	}
+	if opts.Step <= 0 {
+		opts.Step = opts.Window / 100
+	}
+	if minStep := opts.Window / maxCorrelationPoints; opts.Step < minStep {
+		opts.Step = minStep
+	}
+	if opts.MaxLag <= 0 {
+		opts.MaxLag = defaultCorrelationMaxLag
+	}
+	if opts.Threshold <= 0 || opts.Threshold > 1 {
+		opts.Threshold = defaultCorrelationThreshold
+	}
+
+	patterns = append([]EmergentPattern(nil), patterns...)
+	sort.Slice(patterns, func(i, j int) bool {
+		return patterns[i].ID < patterns[j].ID
+	})
+	n := len(patterns)
+	matrix := CorrelationMatrix{
+		Patterns:  make([]string, n),
+		Series:    opts.Series,
+		Window:    opts.Window,
+		Step:      opts.Step,
+		Values:    make([][]float64, n),
+		Lags:      make([][]int, n),
+		Relations: make([]LeadLag, 0),
+		Generated: now,
+	}
+
+	// 对齐到共同网格
+	start := now.Add(-opts.Window)
+	points := int(opts.Window/opts.Step) + 1
+	grid := make([][]float64, n)
+	for i := range patterns {
+		matrix.Patterns[i] = patterns[i].ID
+		matrix.Values[i] = make([]float64, n)
+		matrix.Lags[i] = make([]int, n)
+		grid[i] = AlignSeries(&patterns[i], opts.Series, start, opts.Step, points)
+	}
+
+	for i := 0; i < n; i++ {
+		matrix.Values[i][i] = 1
+		for j := i + 1; j < n; j++ {
+			r, lag := CrossCorrelation(grid[i], grid[j], opts.MaxLag)
+			matrix.Values[i][j], matrix.Values[j][i] = r, r
+			matrix.Lags[i][j], matrix.Lags[j][i] = lag, -lag
+			if lag == 0 || math.Abs(r) < opts.Threshold {
+				continue
+			}
+
+			relation := LeadLag{Leader: matrix.Patterns[i], Follower: matrix.Patterns[j], Lag: lag, Correlation: r}
+			if lag < 0 {
+				relation.Leader, relation.Follower, relation.Lag = relation.Follower, relation.Leader, -lag
+			}
+			relation.Delay = time.Duration(relation.Lag) * opts.Step
+			matrix.Relations = append(matrix.Relations, relation)
+		}
+	}
+	sort.Slice(matrix.Relations, func(i, j int) bool {
+		return math.Abs(matrix.Relations[i].Correlation) > math.Abs(matrix.Relations[j].Correlation)
+	})
+	return matrix
+}
+
+// CrossCorrelation 计算两个等间隔序列在[-maxLag, maxLag]滞后范围内绝对值最大的皮尔逊相关系数
+// 滞后k>0表示x领先y k步, 即x[t]与y[t+k]相关; NaN表示缺失样本. 有效重叠不足时返回(0, 0)
+func CrossCorrelation(x, y []float64, maxLag int) (float64, int) {
+	best, bestLag := 0.0, 0
+	for lag := -maxLag; lag <= maxLag; lag++ {
+		r, ok := laggedPearson(x, y, lag)
+		if !ok {
+			continue
+		}
+		if math.Abs(r) > math.Abs(best) || (math.Abs(r) == math.Abs(best) && abs(lag) < abs(bestLag)) {
+			best, bestLag = r, lag
+		}
+	}
+	return best, bestLag
+}
+
+// laggedPearson x[t]与y[t+lag]的皮尔逊相关系数
+func laggedPearson(x, y []float64, lag int) (float64, bool) {
+	var sx, sy, sxx, syy, sxy, count float64
+	for t := range x {
+		u := t + lag
+		if u < 0 || u >= len(y) || math.IsNaN(x[t]) || math.IsNaN(y[u]) {
+			continue
+		}
+		sx += x[t]
+		sy += y[u]
+		sxx += x[t] * x[t]
+		syy += y[u] * y[u]
+		sxy += x[t] * y[u]
+		count++
+	}
+	if count < minCorrelationOverlap {
+		return 0, false
+	}
+
+	cov := sxy - sx*sy/count
+	vx := sxx - sx*sx/count
+	vy := syy - sy*sy/count
+	if vx <= 1e-12 || vy <= 1e-12 {
+		return 0, false
+	}
+	return cov / math.Sqrt(vx*vy), true
+}
+
+// AlignSeries 将模式演化序列线性插值到网格上, 超出演化历史范围的网格点为NaN
+func AlignSeries(pattern *EmergentPattern, series string, start time.Time, step time.Duration, points int) []float64 {
+	result := make([]float64, points)
+	for i := range result {
+		result[i] = math.NaN()
+	}
+
+	history := make([]PatternState, 0, len(pattern.Evolution))
+	for _, state := range pattern.Evolution {
+		if !stateTime(state).IsZero() {
+			history = append(history, state)
+		}
+	}
+	if len(history) == 0 {
+		return result
+	}
+	sort.SliceStable(history, func(i, j int) bool {
+		return stateTime(history[i]).Before(stateTime(history[j]))
+	})
+	value := func(state PatternState) float64 {
+		if series == SeriesEnergy {
+			return state.Energy
+		}
+		return state.Strength
+	}
+
+	j := 0
+	for i := 0; i < points; i++ {
+		at := start.Add(time.Duration(i) * step)
+		for j < len(history)-1 && !stateTime(history[j+1]).After(at) {
+			j++
+		}
+		first, last := stateTime(history[0]), stateTime(history[len(history)-1])
+		if at.Before(first) || at.After(last) {
+			continue
+		}
+		if j == len(history)-1 {
+			result[i] = value(history[j])
+			continue
+		}
+		t0, t1 := stateTime(history[j]), stateTime(history[j+1])
+		ratio := 0.0
+		if span := t1.Sub(t0); span > 0 {
+			ratio = float64(at.Sub(t0)) / float64(span)
+		}
+		result[i] = value(history[j]) + ratio*(value(history[j+1])-value(history[j]))
+	}
+	return result
+}
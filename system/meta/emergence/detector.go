@@ -4,15 +4,20 @@ package emergence
 
 import (
 	"context"
-	"fmt"
 	"math"
 	"math/cmplx"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/control/tuning"
+	"github.com/Corphon/daoflow/system/faultinject"
+	"github.com/Corphon/daoflow/system/invariants"
 	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
 )
 
 // PatternDetector 模式检测器
@@ -21,30 +26,93 @@ type PatternDetector struct {
 
 	// 基础配置
 	config struct {
-		sensitivity       float64       // 检测灵敏度
-		timeWindow        time.Duration // 检测时间窗口
-		minConfidence     float64       // 最小置信度
-		patternThreshold  float64       // 模式阈值
-		maxElementEnergy  float64       // 最大元素能量
-		maxClusterRadius  float64       // 最大聚集半径
-		maxEnergyLevel    float64       // 最大能量级别
-		DetectionInterval time.Duration // 检测间隔
+		sensitivity       float64               // 检测灵敏度
+		timeWindow        time.Duration         // 检测时间窗口
+		minConfidence     float64               // 最小置信度
+		patternThreshold  float64               // 模式阈值
+		maxElementEnergy  float64               // 最大元素能量
+		maxClusterRadius  float64               // 最大聚集半径
+		maxEnergyLevel    float64               // 最大能量级别
+		DetectionInterval time.Duration         // 检测间隔
+		namespace         string                // 所属命名空间
+		stabilityMode     StabilityMode         // 稳定性计算方式
+		retention         types.RetentionConfig // 演化历史和检测历史的分层保留
+		geometry          core.Geometry         // 能量分布的网格几何
 	}
 
 	// 检测状态
 	state struct {
 		activePatterns map[string]*EmergentPattern // 活跃模式
 		history        []DetectionEvent            // 检测历史
+		summaries      []DetectionSummary          // 降采样的较早检测历史
+		aggregate      DetectionSummary            // 超出摘要预算的检测历史聚合
 		lastUpdate     time.Time                   // 最后更新时间
+		version        uint64                      // 活跃模式版本, 每次检测或恢复后递增
+		pending        []EmergentPattern           // 按优先级推迟通知的新模式
+	}
+
+	// 活跃模式的最近快照
+	snapshot struct {
+		mu      sync.Mutex
+		current *PatternSnapshot
 	}
 
 	// 场引用
 	field *field.UnifiedField
+
+	// 新模式监听器
+	listeners []func([]EmergentPattern)
+
+	// 检测事件监听器
+	eventListeners []func([]DetectionEvent)
+
+	// 能量转移监听器
+	transferListeners []func([]EnergyTransfer)
+
+	// 启动预热, 为nil时不预热
+	warmup *types.Warmup
+
+	// 检测间隔调节器, 为nil时使用固定间隔
+	tuner *tuning.IntervalTuner
+
+	// 检测间隔倍率, 降级模式和资源预算节流时放慢检测
+	slowdown float64
+
+	// 每次检测循环执行后通知耗时, 用于资源预算统计
+	cycleObserver func(time.Duration)
+
+	// 新模式通知的优先级调度, 为nil时每次通知全部新模式
+	priority *PriorityScheduler
+
+	// 模式类型注册表
+	registry *PatternTypeRegistry
+
+	// 检测插件
+	plugins struct {
+		mu   sync.RWMutex
+		list []*detectorPlugin
+	}
+
+	// 检测时钟, 为nil时使用当前时间; 回填时取快照时间
+	clock func() time.Time
+
+	// 场状态快照日志, 为nil时不记录
+	journal *FieldJournal
+
+	// 检测跨度的接收方, 为nil时只生成追踪上下文
+	reporter types.SpanReporter
+
+	// 关闭前排空: 排空期间检测循环不再执行检测, cycle在检测循环执行一次检测期间持有
+	drain struct {
+		cycle    sync.Mutex
+		draining atomic.Bool
+	}
 }
 
 // EmergentPattern 涌现模式
 type EmergentPattern struct {
 	ID         string             // 模式标识
+	Namespace  string             // 所属命名空间
 	Type       string             // 模式类型
 	Components []PatternComponent // 组成成分
 	Properties map[string]float64 // 模式属性
@@ -52,8 +120,22 @@ type EmergentPattern struct {
 	Stability  float64            // 模式稳定性
 	Energy     float64            // 模式能量
 	Formation  time.Time          // 形成时间
-	Evolution  []PatternState     // 演化历史
+	Evolution  []PatternState     // 演化历史, 较早部分为降采样摘要
+	History    EvolutionAggregate // 超出保留预算的演化历史聚合
 	LastUpdate time.Time          // 最后更新时间
+
+	// 人工干预, 与计算值分开记录
+	Pin       *Provenance                 // 固定来源, 为nil时未固定
+	Overrides map[string]PropertyOverride // 人工覆盖, 键为OverrideStrength等字段名或属性名
+
+	// 用户标注
+	Annotations types.Annotations
+
+	// 检测到该模式的跨度, 下游匹配和决策以其为父跨度
+	Trace types.TraceContext
+
+	// 是否在启动预热期间检测到, 此时强度和稳定性尚不可靠; 预热结束后清除
+	Provisional bool
 }
 
 // PatternComponent 模式组件
@@ -69,11 +151,13 @@ type PatternComponent struct {
 
 // DetectionEvent 检测事件
 type DetectionEvent struct {
-	Timestamp  time.Time
-	PatternID  string
-	Type       string
-	Confidence float64
-	Changes    []StateChange
+	Timestamp   time.Time
+	PatternID   string
+	Type        string
+	Confidence  float64
+	Changes     []StateChange
+	Trace       types.TraceContext // 检测到模式的跨度
+	Provisional bool               // 是否在启动预热期间检测到
 }
 
 // StateChange 状态变化
@@ -122,7 +206,8 @@ type QuantumCoherence struct {
 // NewPatternDetector 创建新的模式检测器
 func NewPatternDetector(field *field.UnifiedField) *PatternDetector {
 	pd := &PatternDetector{
-		field: field,
+		field:    field,
+		registry: NewPatternTypeRegistry(),
 	}
 
 	// 初始化配置
@@ -134,6 +219,9 @@ func NewPatternDetector(field *field.UnifiedField) *PatternDetector {
 	pd.config.maxClusterRadius = 5.0
 	pd.config.maxEnergyLevel = 100.0
 	pd.config.DetectionInterval = 5 * time.Second
+	pd.config.namespace = "default"
+	pd.config.stabilityMode = StabilityHeuristic
+	pd.config.geometry = core.DefaultGeometry()
 
 	// 初始化状态
 	pd.state.activePatterns = make(map[string]*EmergentPattern)
@@ -145,17 +233,171 @@ func NewPatternDetector(field *field.UnifiedField) *PatternDetector {
 
 // Detect 执行模式检测
 func (pd *PatternDetector) Detect() ([]EmergentPattern, error) {
+	if err := faultinject.Fire(faultinject.PointDetect, ""); err != nil {
+		return nil, err
+	}
+
+	active, newPatterns, events, transfers, err := pd.detect()
+	if err != nil {
+		return nil, err
+	}
+
+	// 按优先级选出本次通知的新模式, 其余推迟到后续检测
+	notify := pd.scheduleNotifications(newPatterns)
+
+	// 通知监听器
+	if len(notify) > 0 || len(events) > 0 {
+		pd.mu.RLock()
+		listeners := append([]func([]EmergentPattern){}, pd.listeners...)
+		eventListeners := append([]func([]DetectionEvent){}, pd.eventListeners...)
+		pd.mu.RUnlock()
+
+		if len(notify) > 0 {
+			for _, listener := range listeners {
+				listener(notify)
+			}
+		}
+		if len(events) > 0 {
+			for _, listener := range eventListeners {
+				listener(events)
+			}
+		}
+	}
+
+	if len(transfers) > 0 {
+		pd.mu.RLock()
+		transferListeners := append([]func([]EnergyTransfer){}, pd.transferListeners...)
+		pd.mu.RUnlock()
+
+		for _, listener := range transferListeners {
+			listener(transfers)
+		}
+	}
+
+	return active, nil
+}
+
+// scheduleNotifications 合并推迟的新模式后按优先级选出本次通知的模式, 推迟期间已消失的模式不再通知
+func (pd *PatternDetector) scheduleNotifications(newPatterns []EmergentPattern) []EmergentPattern {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	if !pd.priority.Enabled() {
+		pd.state.pending = nil
+		return newPatterns
+	}
+
+	candidates := make([]EmergentPattern, 0, len(pd.state.pending)+len(newPatterns))
+	for _, p := range pd.state.pending {
+		if current, ok := pd.state.activePatterns[p.ID]; ok {
+			candidates = append(candidates, *current)
+		}
+	}
+	candidates = append(candidates, newPatterns...)
+
+	selected, deferred := pd.priority.Schedule(candidates)
+	pd.state.pending = deferred
+	return selected
+}
+
+// SetPriority 设置新模式通知的优先级调度, nil恢复每次通知全部新模式
+func (pd *PatternDetector) SetPriority(priority *PriorityScheduler) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.priority = priority
+}
+
+// GetPriority 获取新模式通知的优先级调度
+func (pd *PatternDetector) GetPriority() *PriorityScheduler {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.priority
+}
+
+// SetSpanReporter 设置检测跨度的接收方, nil时只生成追踪上下文
+func (pd *PatternDetector) SetSpanReporter(reporter types.SpanReporter) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.reporter = reporter
+}
+
+// OnNewPatterns 注册新模式监听器, 每次检测到新模式后调用; 启用优先级调度时超出配额的新模式在后续检测中通知
+func (pd *PatternDetector) OnNewPatterns(listener func([]EmergentPattern)) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.listeners = append(pd.listeners, listener)
+}
+
+// OnDetectionEvent 注册检测事件监听器, 每次检测到新模式后调用
+func (pd *PatternDetector) OnDetectionEvent(listener func([]DetectionEvent)) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.eventListeners = append(pd.eventListeners, listener)
+}
+
+// GetHistory 获取检测历史的副本
+func (pd *PatternDetector) GetHistory() []DetectionEvent {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return append([]DetectionEvent{}, pd.state.history...)
+}
+
+// GetHistorySummaries 获取较早检测历史的摘要和超出摘要预算部分的聚合
+func (pd *PatternDetector) GetHistorySummaries() ([]DetectionSummary, DetectionSummary) {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	summaries := make([]DetectionSummary, len(pd.state.summaries))
+	for i, summary := range pd.state.summaries {
+		summaries[i] = summary.clone()
+	}
+	return summaries, pd.state.aggregate.clone()
+}
+
+// detect 执行一次检测, 返回活跃模式、新模式、对应的检测事件和有监听器时的能量转移
+func (pd *PatternDetector) detect() ([]EmergentPattern, []EmergentPattern, []DetectionEvent, []EnergyTransfer, error) {
 	pd.mu.Lock()
 	defer pd.mu.Unlock()
 
 	// 获取场状态
 	fieldState, err := pd.field.GetState()
 	if err != nil {
-		return nil, model.WrapError(err, model.ErrCodeOperation, "failed to get field state")
+		return nil, nil, nil, nil, model.WrapError(err, model.ErrCodeOperation, "failed to get field state")
 	}
+	if pd.journal != nil {
+		pd.journal.Append(fieldState)
+	}
+
+	// 每次检测计为一个预热样本, 预热期间的检测结果标记为临时
+	pd.warmup.Observe()
+	provisional := pd.warmup.Active()
+	if !provisional {
+		pd.clearProvisional()
+	}
+
+	newPatterns, events := pd.detectState(fieldState, provisional)
 
+	var transfers []EnergyTransfer
+	if len(pd.transferListeners) > 0 {
+		transfers = pd.energyTransfers(fieldState)
+	}
+
+	// 返回当前活跃的模式
+	return pd.getActivePatterns(), newPatterns, events, transfers, nil
+}
+
+// detectState 以给定场状态执行一次检测, 返回新模式和对应的检测事件, 调用方需持有锁
+// provisional表示新模式和检测事件标记为临时
+func (pd *PatternDetector) detectState(fieldState *model.FieldState, provisional bool) ([]EmergentPattern, []DetectionEvent) {
 	// 检测新模式
-	newPatterns := pd.detectNewPatterns(fieldState)
+	newPatterns := validatePatterns(pd.detectNewPatterns(fieldState))
+	for i := range newPatterns {
+		newPatterns[i].Namespace = pd.config.namespace
+		newPatterns[i].Provisional = provisional
+		if newPatterns[i].Formation.IsZero() {
+			newPatterns[i].Formation = pd.now()
+		}
+	}
 
 	// 更新现有模式
 	pd.updateExistingPatterns(fieldState)
@@ -163,26 +405,83 @@ func (pd *PatternDetector) Detect() ([]EmergentPattern, error) {
 	// 移除消失的模式
 	pd.removeVanishedPatterns()
 
+	// 登记新模式, 每个新模式开始一条因果追踪
+	now := pd.now()
+	for i := range newPatterns {
+		if newPatterns[i].Trace.IsZero() {
+			newPatterns[i].Trace = types.ChildSpan(pd.reporter, types.TraceContext{}, types.CausalSpan{
+				Name:      "emergence.detect",
+				Subsystem: "meta.emergence",
+				Tags: map[string]string{
+					"pattern":   newPatterns[i].ID,
+					"type":      newPatterns[i].Type,
+					"namespace": newPatterns[i].Namespace,
+				},
+			})
+		}
+		pattern := newPatterns[i]
+		if pattern.LastUpdate.IsZero() {
+			pattern.LastUpdate = now
+		}
+		pd.state.activePatterns[pattern.ID] = &pattern
+	}
+
 	// 记录检测事件
-	pd.recordDetectionEvent(newPatterns)
+	events := pd.recordDetectionEvent(newPatterns)
+	pd.state.version++
 
-	// 返回当前活跃的模式
-	return pd.getActivePatterns(), nil
+	return newPatterns, events
+}
+
+// validatePatterns 校验新模式的数值输出, 严格校验模式下丢弃含NaN/Inf的模式
+func validatePatterns(patterns []EmergentPattern) []EmergentPattern {
+	if !invariants.Validating() {
+		return patterns
+	}
+
+	valid := patterns[:0]
+	for _, p := range patterns {
+		values := map[string]float64{
+			"strength":  p.Strength,
+			"stability": p.Stability,
+			"energy":    p.Energy,
+		}
+		for key, v := range p.Properties {
+			values["properties."+key] = v
+		}
+		if err := invariants.ValidateMetrics("emergence.detector."+p.Type, values); err != nil {
+			continue
+		}
+		valid = append(valid, p)
+	}
+	return valid
+}
+
+// now 检测时钟的当前时间
+func (pd *PatternDetector) now() time.Time {
+	if pd.clock != nil {
+		return pd.clock()
+	}
+	return time.Now()
 }
 
 // removeVanishedPatterns 移除消失的模式
 func (pd *PatternDetector) removeVanishedPatterns() {
-	currentTime := time.Now()
+	currentTime := pd.now()
 	timeout := pd.config.timeWindow
 
-	// 遍历现有模式
+	// 遍历现有模式, 固定的模式不移除
 	for id, pattern := range pd.state.activePatterns {
+		if pattern.Pin != nil {
+			continue
+		}
+
 		// 检查模式是否超时
 		if currentTime.Sub(pattern.LastUpdate) > timeout {
 			delete(pd.state.activePatterns, id)
 		}
-		// 检查模式强度
-		if pattern.Strength < pd.config.sensitivity {
+		// 检查模式强度, 有人工覆盖时取覆盖值
+		if pattern.Effective(OverrideStrength) < pd.config.sensitivity {
 			delete(pd.state.activePatterns, id)
 		}
 	}
@@ -197,6 +496,196 @@ func (pd *PatternDetector) getActivePatterns() []EmergentPattern {
 	return patterns
 }
 
+// GetActivePatterns 获取当前活跃模式的副本
+func (pd *PatternDetector) GetActivePatterns() []EmergentPattern {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	return pd.getActivePatterns()
+}
+
+// RestorePatterns 用给定模式替换活跃模式集合
+func (pd *PatternDetector) RestorePatterns(patterns []EmergentPattern) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pd.state.activePatterns = make(map[string]*EmergentPattern, len(patterns))
+	for i := range patterns {
+		pattern := patterns[i]
+		if pattern.Namespace != "" && pattern.Namespace != pd.config.namespace {
+			continue
+		}
+		pattern.Namespace = pd.config.namespace
+		pd.state.activePatterns[pattern.ID] = &pattern
+	}
+	pd.state.lastUpdate = time.Now()
+	pd.state.version++
+}
+
+// SetNamespace 设置检测器所属命名空间
+func (pd *PatternDetector) SetNamespace(namespace string) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.config.namespace = namespace
+}
+
+// GetNamespace 获取检测器所属命名空间
+func (pd *PatternDetector) GetNamespace() string {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.config.namespace
+}
+
+// Configure 调整检测参数, 非正值保持原配置
+func (pd *PatternDetector) Configure(sensitivity, minConfidence float64, interval time.Duration) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	if sensitivity > 0 {
+		pd.config.sensitivity = sensitivity
+	}
+	if minConfidence > 0 {
+		pd.config.minConfidence = minConfidence
+	}
+	if interval > 0 {
+		pd.config.DetectionInterval = interval
+	}
+}
+
+// SetStabilityMode 设置模式稳定性计算方式, 未知方式保持原配置
+func (pd *PatternDetector) SetStabilityMode(mode StabilityMode) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	switch mode {
+	case StabilityHeuristic, StabilityLyapunov:
+		pd.config.stabilityMode = mode
+	}
+}
+
+// SetRetention 设置演化历史和检测历史的分层保留预算, 在下次记录时生效
+func (pd *PatternDetector) SetRetention(config types.RetentionConfig) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.config.retention = config
+}
+
+// GetRetention 获取分层保留配置
+func (pd *PatternDetector) GetRetention() types.RetentionConfig {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.config.retention
+}
+
+// SetGeometry 设置能量分布的网格几何, 在下次检测时生效
+func (pd *PatternDetector) SetGeometry(geometry core.Geometry) error {
+	if err := geometry.Validate(); err != nil {
+		return err
+	}
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.config.geometry = geometry
+	return nil
+}
+
+// GetGeometry 获取能量分布的网格几何
+func (pd *PatternDetector) GetGeometry() core.Geometry {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.config.geometry
+}
+
+// GetStabilityMode 获取模式稳定性计算方式
+func (pd *PatternDetector) GetStabilityMode() StabilityMode {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.config.stabilityMode
+}
+
+// SetPatternThreshold 设置模式阈值, 非正值保持原配置
+func (pd *PatternDetector) SetPatternThreshold(threshold float64) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	if threshold > 0 {
+		pd.config.patternThreshold = threshold
+	}
+}
+
+// GetThresholds 获取检测灵敏度、最小置信度和模式阈值
+func (pd *PatternDetector) GetThresholds() (sensitivity, minConfidence, patternThreshold float64) {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.config.sensitivity, pd.config.minConfidence, pd.config.patternThreshold
+}
+
+// SetIntervalTuning 设置检测间隔自动调节, 未启用时恢复固定间隔
+func (pd *PatternDetector) SetIntervalTuning(config types.IntervalTuningConfig) *tuning.IntervalTuner {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	if !config.Enabled {
+		pd.tuner = nil
+		return nil
+	}
+	pd.tuner = tuning.NewIntervalTuner(pd.config.DetectionInterval, config)
+	return pd.tuner
+}
+
+// GetIntervalTuner 获取检测间隔调节器(未启用时为nil)
+func (pd *PatternDetector) GetIntervalTuner() *tuning.IntervalTuner {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.tuner
+}
+
+// SetSlowdown 设置检测间隔倍率, factor<=1时恢复正常间隔
+func (pd *PatternDetector) SetSlowdown(factor float64) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.slowdown = factor
+}
+
+// SetCycleObserver 设置检测循环耗时通知, nil取消
+func (pd *PatternDetector) SetCycleObserver(fn func(time.Duration)) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.cycleObserver = fn
+}
+
+// EffectiveInterval 当前有效的检测间隔
+func (pd *PatternDetector) EffectiveInterval() time.Duration {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	if pd.tuner != nil {
+		return slowed(pd.tuner.Interval(), pd.slowdown)
+	}
+	return slowed(pd.config.DetectionInterval, pd.slowdown)
+}
+
+// nextInterval 根据本次检测耗时计算下一次间隔
+func (pd *PatternDetector) nextInterval(elapsed time.Duration) time.Duration {
+	pd.mu.RLock()
+	tuner := pd.tuner
+	interval := pd.config.DetectionInterval
+	slowdown := pd.slowdown
+	pd.mu.RUnlock()
+
+	if tuner == nil {
+		return slowed(interval, slowdown)
+	}
+	return slowed(tuner.Observe(elapsed), slowdown)
+}
+
+// slowed 按倍率放大间隔, 倍率不大于1时不变
+func slowed(interval time.Duration, factor float64) time.Duration {
+	if factor <= 1 {
+		return interval
+	}
+	return time.Duration(float64(interval) * factor)
+}
+
 // detectNewPatterns 检测新模式
 func (pd *PatternDetector) detectNewPatterns(state *model.FieldState) []EmergentPattern {
 	newPatterns := make([]EmergentPattern, 0)
@@ -213,7 +702,64 @@ func (pd *PatternDetector) detectNewPatterns(state *model.FieldState) []Emergent
 	quantumPatterns := pd.detectQuantumPatterns(state)
 	newPatterns = append(newPatterns, quantumPatterns...)
 
-	return newPatterns
+	// 执行注册的自定义类型检测
+	newPatterns = append(newPatterns, pd.detectRegisteredPatterns(state)...)
+
+	// 执行检测插件
+	newPatterns = append(newPatterns, pd.runPlugins(state)...)
+
+	// 按类型阈值过滤
+	accepted := newPatterns[:0]
+	for _, pattern := range newPatterns {
+		if pd.registry.accepts(pattern) {
+			accepted = append(accepted, pattern)
+		}
+	}
+
+	return accepted
+}
+
+// detectRegisteredPatterns 执行注册表中自定义类型的检测函数
+func (pd *PatternDetector) detectRegisteredPatterns(state *model.FieldState) []EmergentPattern {
+	patterns := make([]EmergentPattern, 0)
+
+	for _, info := range pd.registry.detectors() {
+		for _, pattern := range info.Detect(state) {
+			if pattern.Type == "" {
+				pattern.Type = info.Name
+			}
+			if pattern.ID == "" {
+				pattern.ID = generatePatternID()
+			}
+			if pattern.Formation.IsZero() {
+				pattern.Formation = pd.now()
+			}
+			// 组件与类型定义不符的模式被丢弃
+			if err := pd.registry.Validate(pattern); err != nil {
+				continue
+			}
+			patterns = append(patterns, pattern)
+		}
+	}
+
+	return patterns
+}
+
+// SetTypeRegistry 设置模式类型注册表, 多个检测器可共享同一注册表
+func (pd *PatternDetector) SetTypeRegistry(registry *PatternTypeRegistry) {
+	if registry == nil {
+		return
+	}
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.registry = registry
+}
+
+// TypeRegistry 获取模式类型注册表
+func (pd *PatternDetector) TypeRegistry() *PatternTypeRegistry {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.registry
 }
 
 // detectElementPatterns 检测元素组合模式
@@ -273,16 +819,16 @@ func (pd *PatternDetector) analyzeElementCombination(elements []*model.Element)
 	// 创建模式
 	pattern := &EmergentPattern{
 		ID:         generatePatternID(),
-		Type:       "element_combination",
+		Type:       PatternElementCombination,
 		Strength:   interaction,
-		Formation:  time.Now(),
+		Formation:  pd.now(),
 		Components: make([]PatternComponent, len(elements)),
 	}
 
 	// 添加组件信息
 	for i, elem := range elements {
 		pattern.Components[i] = PatternComponent{
-			Type:   "element",
+			Type:   ComponentElement,
 			Role:   elem.GetType(),
 			Weight: elem.GetEnergy() / pd.config.maxElementEnergy,
 		}
@@ -293,7 +839,7 @@ func (pd *PatternDetector) analyzeElementCombination(elements []*model.Element)
 
 // generatePatternID 生成唯一的模式ID
 func generatePatternID() string {
-	return fmt.Sprintf("pat_%d", time.Now().UnixNano())
+	return core.NewID("pat_")
 }
 
 // calculateElementInteraction 计算元素间相互作用强度
@@ -356,8 +902,8 @@ func calculateSimilarity(e1, e2 *model.Element) float64 {
 func (pd *PatternDetector) detectEnergyPatterns(state *model.FieldState) []EmergentPattern {
 	patterns := make([]EmergentPattern, 0)
 
-	// 分析能量分布
-	energyDist := state.GetEnergyDistribution()
+	// 分析能量分布, 按网格几何投影
+	energyDist := pd.config.geometry.Project(state.GetEnergyDistribution())
 
 	// 检测能量聚集
 	clusters := pd.detectEnergyClusters(energyDist)
@@ -414,12 +960,12 @@ func (pd *PatternDetector) expandCluster(
 	visited[center] = true
 
 	// 查找相邻点
-	neighbors := getNeighborPoints(center)
+	neighbors := pd.config.geometry.Neighbors(center)
 	for _, p := range neighbors {
 		if energy, exists := dist[p]; exists {
 			if !visited[p] && energy >= pd.config.sensitivity {
 				// 计算到中心的距离
-				distance := calculatePointDistance(center, p)
+				distance := pd.config.geometry.Distance(center, p)
 				if distance <= pd.config.maxClusterRadius {
 					// 递归扩展
 					subCluster := pd.expandCluster(p, dist, visited)
@@ -435,47 +981,33 @@ func (pd *PatternDetector) expandCluster(
 	return cluster
 }
 
-// getNeighborPoints 获取相邻点
-func getNeighborPoints(p core.Point) []core.Point {
-	neighbors := make([]core.Point, 0)
-	// 上下左右四个方向
-	directions := [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}}
-	for _, d := range directions {
-		neighbor := core.Point{
-			X: p.X + d[0],
-			Y: p.Y + d[1],
-		}
-		neighbors = append(neighbors, neighbor)
-	}
-	return neighbors
-}
-
-// calculatePointDistance 计算两点间距离
-func calculatePointDistance(p1, p2 core.Point) float64 {
-	dx := float64(p1.X - p2.X)
-	dy := float64(p1.Y - p2.Y)
-	return math.Sqrt(dx*dx + dy*dy)
-}
-
 // analyzeEnergyCluster 分析能量聚集
 func (pd *PatternDetector) analyzeEnergyCluster(cluster EnergyCluster) *EmergentPattern {
 	return &EmergentPattern{
 		ID:       generatePatternID(),
-		Type:     "energy_cluster",
+		Type:     PatternEnergyCluster,
 		Strength: cluster.Energy,
 		Components: []PatternComponent{{
-			Type:   "energy",
+			Type:   ComponentEnergy,
 			Role:   "center",
 			Weight: cluster.Energy,
 		}},
 		Properties: map[string]float64{
 			"radius":   cluster.Radius,
 			"gradient": cluster.Gradient,
-			"density":  cluster.Energy / (math.Pi * cluster.Radius * cluster.Radius),
+			"density":  cluster.Energy / pd.clusterVolume(cluster.Radius),
 		},
 	}
 }
 
+// clusterVolume 聚集区域的面积(三维网格为体积)
+func (pd *PatternDetector) clusterVolume(radius float64) float64 {
+	if pd.config.geometry.Is3D() {
+		return 4.0 / 3.0 * math.Pi * radius * radius * radius
+	}
+	return math.Pi * radius * radius
+}
+
 // detectEnergyFlows 检测能量流动
 func (pd *PatternDetector) detectEnergyFlows(dist map[core.Point]float64) []EnergyFlow {
 	flows := make([]EnergyFlow, 0)
@@ -488,7 +1020,7 @@ func (pd *PatternDetector) detectEnergyFlows(dist map[core.Point]float64) []Ener
 					Source:    p1,
 					Target:    p2,
 					Rate:      gradient,
-					Direction: calculateDirection(p1, p2),
+					Direction: pd.calculateDirection(p1, p2),
 					Intensity: math.Abs(e1 - e2),
 				})
 			}
@@ -501,7 +1033,7 @@ func (pd *PatternDetector) detectEnergyFlows(dist map[core.Point]float64) []Ener
 // calculateEnergyGradient 计算能量梯度
 func (pd *PatternDetector) calculateEnergyGradient(p1 core.Point, e1 float64, p2 core.Point, e2 float64) float64 {
 	// 计算距离
-	distance := calculatePointDistance(p1, p2)
+	distance := pd.config.geometry.Distance(p1, p2)
 	if distance == 0 {
 		return 0
 	}
@@ -510,10 +1042,9 @@ func (pd *PatternDetector) calculateEnergyGradient(p1 core.Point, e1 float64, p2
 	return math.Abs(e2-e1) / distance
 }
 
-// calculateDirection 计算方向角度(弧度)
-func calculateDirection(p1, p2 core.Point) float64 {
-	dx := float64(p2.X - p1.X)
-	dy := float64(p2.Y - p1.Y)
+// calculateDirection 计算方向角度(弧度), 三维网格取水平面内的方位角
+func (pd *PatternDetector) calculateDirection(p1, p2 core.Point) float64 {
+	dx, dy, _ := pd.config.geometry.Vector(p1, p2)
 
 	// 使用反正切函数计算角度
 	angle := math.Atan2(dy, dx)
@@ -530,16 +1061,16 @@ func calculateDirection(p1, p2 core.Point) float64 {
 func (pd *PatternDetector) analyzeEnergyFlow(flow EnergyFlow) *EmergentPattern {
 	return &EmergentPattern{
 		ID:       generatePatternID(),
-		Type:     "energy_flow",
+		Type:     PatternEnergyFlow,
 		Strength: flow.Intensity,
 		Components: []PatternComponent{
 			{
-				Type:   "energy",
+				Type:   ComponentEnergy,
 				Role:   "source",
 				Weight: flow.Rate,
 			},
 			{
-				Type:   "energy",
+				Type:   ComponentEnergy,
 				Role:   "target",
 				Weight: flow.Rate,
 			},
@@ -601,10 +1132,10 @@ func (pd *PatternDetector) detectEntanglements(state *core.QuantumState) []Quant
 func (pd *PatternDetector) analyzeEntanglement(ent QuantumEntanglement) *EmergentPattern {
 	return &EmergentPattern{
 		ID:       generatePatternID(),
-		Type:     "quantum_entanglement",
+		Type:     PatternQuantumEntanglement,
 		Strength: ent.Strength,
 		Components: []PatternComponent{{
-			Type:   "quantum",
+			Type:   ComponentQuantum,
 			Role:   "entangled_state",
 			Weight: ent.Strength,
 		}},
@@ -649,10 +1180,10 @@ func (pd *PatternDetector) detectCoherences(state *core.QuantumState) []QuantumC
 func (pd *PatternDetector) analyzeCoherence(coh QuantumCoherence) *EmergentPattern {
 	return &EmergentPattern{
 		ID:       generatePatternID(),
-		Type:     "quantum_coherence",
+		Type:     PatternQuantumCoherence,
 		Strength: coh.Stability,
 		Components: []PatternComponent{{
-			Type:   "quantum",
+			Type:   ComponentQuantum,
 			Role:   "coherent_state",
 			Weight: coh.Amplitude,
 		}},
@@ -675,13 +1206,13 @@ func (pd *PatternDetector) updateExistingPatterns(state *model.FieldState) {
 		// 更新模式属性
 		pd.updatePatternProperties(pattern, state)
 
-		// 检查模式稳定性
-		if pattern.Stability < pd.config.minConfidence {
+		// 检查模式稳定性, 固定的模式不移除
+		if pattern.Pin == nil && pattern.Effective(OverrideStability) < pd.config.minConfidence {
 			delete(pd.state.activePatterns, id)
 			continue
 		}
 
-		pattern.LastUpdate = time.Now()
+		pattern.LastUpdate = pd.now()
 	}
 }
 
@@ -697,6 +1228,9 @@ func (pd *PatternDetector) updatePatternProperties(pattern *EmergentPattern, sta
 		}
 	}
 
+	// 记录演化状态, 供稳定性估计和模式间相关分析使用
+	pd.recordPatternState(pattern, state)
+
 	// 计算稳定性
 	pattern.Stability = pd.calculatePatternStability(pattern)
 
@@ -704,6 +1238,19 @@ func (pd *PatternDetector) updatePatternProperties(pattern *EmergentPattern, sta
 	pattern.Properties = pd.calculatePatternProperties(pattern, state)
 }
 
+// recordPatternState 记录模式当前状态到演化历史, 并按模式类型的保留预算整理
+func (pd *PatternDetector) recordPatternState(pattern *EmergentPattern, state *model.FieldState) {
+	now := pd.now()
+	pattern.Evolution = append(pattern.Evolution, PatternState{
+		Active:     true,
+		Strength:   pattern.Strength,
+		Energy:     pd.calculatePatternEnergy(pattern, state),
+		LastUpdate: now,
+		Timestamp:  now,
+	})
+	compactEvolution(pattern, retentionFor(pd.config.retention, pattern.Type), now)
+}
+
 // verifyPattern 验证模式是否仍然存在
 func (pd *PatternDetector) verifyPattern(pattern *EmergentPattern, state *model.FieldState) bool {
 	// 检查组件是否仍然存在
@@ -723,39 +1270,44 @@ func (pd *PatternDetector) verifyPattern(pattern *EmergentPattern, state *model.
 	return true
 }
 
-// recordDetectionEvent 记录检测事件
-func (pd *PatternDetector) recordDetectionEvent(newPatterns []EmergentPattern) {
-	event := DetectionEvent{
-		Timestamp: time.Now(),
-		Changes:   make([]StateChange, 0),
-	}
+// recordDetectionEvent 记录检测事件, 每个新模式对应一个事件
+func (pd *PatternDetector) recordDetectionEvent(newPatterns []EmergentPattern) []DetectionEvent {
+	now := pd.now()
+	events := make([]DetectionEvent, 0, len(newPatterns))
 
-	// 记录新模式
 	for _, pattern := range newPatterns {
-		change := StateChange{
-			Component: pattern.ID,
-			After:     pattern.Properties,
+		event := DetectionEvent{
+			Timestamp:   now,
+			PatternID:   pattern.ID,
+			Type:        pattern.Type,
+			Confidence:  pattern.Strength,
+			Trace:       pattern.Trace,
+			Provisional: pattern.Provisional,
+			Changes: []StateChange{{
+				Component: pattern.ID,
+				After:     pattern.Properties,
+				Delta:     pattern.Strength,
+			}},
 		}
-		event.Changes = append(event.Changes, change)
+		events = append(events, event)
+		pd.state.history = append(pd.state.history, event)
 	}
 
-	pd.state.history = append(pd.state.history, event)
+	// 限制历史记录长度, 较早的事件降采样为摘要
+	pd.compactDetectionHistory(now)
 
-	// 限制历史记录长度
-	if len(pd.state.history) > maxHistoryLength {
-		pd.state.history = pd.state.history[1:]
-	}
+	return events
 }
 
 // 辅助函数
 // componentExists 检查组件是否存在
 func (pd *PatternDetector) componentExists(comp PatternComponent, state *model.FieldState) bool {
 	switch comp.Type {
-	case "element":
+	case ComponentElement:
 		return state.HasElement(comp.Role)
-	case "energy":
+	case ComponentEnergy:
 		return state.HasEnergyLevel(comp.Weight)
-	case "quantum":
+	case ComponentQuantum:
 		// 检查量子态属性
 		if qs := state.GetQuantumState(); qs != nil {
 			// 逐个检查量子态属性
@@ -803,23 +1355,23 @@ func (pd *PatternDetector) calculatePatternStrength(pattern *EmergentPattern, st
 // calculateComponentStrength 计算组件强度
 func (pd *PatternDetector) calculateComponentStrength(comp PatternComponent, state *model.FieldState) float64 {
 	switch comp.Type {
-	case "element":
+	case ComponentElement:
 		// 元素组件强度
 		if element := pd.findElement(comp.Role, state); element != nil {
 			return element.Energy / pd.config.maxElementEnergy
 		}
 
-	case "energy":
+	case ComponentEnergy:
 		// 能量组件强度
 		return state.GetEnergyLevel() / pd.config.maxEnergyLevel
 
-	case "quantum":
+	case ComponentQuantum:
 		// 量子组件强度
 		if quantum := state.GetQuantumState(); quantum != nil {
 			return quantum.GetCoherence()
 		}
 
-	case "field":
+	case ComponentField:
 		// 场组件强度
 		return state.GetFieldStrength()
 	}
@@ -847,11 +1399,11 @@ func (pd *PatternDetector) findElement(elementType string, state *model.FieldSta
 func (pd *PatternDetector) getComponentState(comp PatternComponent, state *model.FieldState) map[string]float64 {
 	// 根据组件类型获取状态
 	switch comp.Type {
-	case "element":
+	case ComponentElement:
 		if element := pd.findElement(comp.Role, state); element != nil {
 			return element.Properties
 		}
-	case "energy":
+	case ComponentEnergy:
 		return map[string]float64{
 			"level": state.GetEnergyLevel(),
 			"flow":  state.GetEnergyFlow(),
@@ -860,7 +1412,15 @@ func (pd *PatternDetector) getComponentState(comp PatternComponent, state *model
 	return nil
 }
 
+// calculatePatternStability 计算模式稳定性
+// 李雅普诺夫模式下演化历史足够时按李雅普诺夫指数估计, 否则按组件状态加权估计
 func (pd *PatternDetector) calculatePatternStability(pattern *EmergentPattern) float64 {
+	if pd.config.stabilityMode == StabilityLyapunov {
+		if estimate, ok := EstimateLyapunov(pattern); ok {
+			return estimate.Stability
+		}
+	}
+
 	// 基于组件状态计算稳定性
 	stabilitySum := 0.0
 	weights := 0.0
@@ -1012,17 +1572,17 @@ func (pd *PatternDetector) calculateComponentCorrelation(c1, c2 PatternComponent
 
 	// 根据组件类型计算关联度
 	switch c1.Type {
-	case "element":
+	case ComponentElement:
 		// 元素组件关联度基于五行关系
 		relation := model.GetWuXingRelation(c1.Role, c2.Role)
 		return (relation.Factor + 1.0) / 2.0
 
-	case "energy":
+	case ComponentEnergy:
 		// 能量组件关联度基于能级差异
 		energyDiff := math.Abs(c1.Weight - c2.Weight)
 		return 1.0 / (1.0 + energyDiff)
 
-	case "quantum":
+	case ComponentQuantum:
 		// 量子组件关联度基于量子纠缠
 		if c1.Properties != nil && c2.Properties != nil {
 			ent1 := c1.Properties["entanglement"]
@@ -1429,6 +1989,7 @@ func init() {
 func (ep *EmergentPattern) Clone() *EmergentPattern {
 	clone := &EmergentPattern{
 		ID:         ep.ID + "_clone",
+		Namespace:  ep.Namespace,
 		Type:       ep.Type,
 		Strength:   ep.Strength,
 		Energy:     ep.Energy,
@@ -1475,7 +2036,8 @@ func (pd *PatternDetector) Start(ctx context.Context) error {
 	defer pd.mu.Unlock()
 
 	// 启动模式检测循环
-	go pd.detectionLoop(ctx)
+	pd.drain.draining.Store(false)
+	supervisor.Go(ctx, "meta.emergence.detection", pd.detectionLoop)
 
 	return nil
 }
@@ -1491,15 +2053,32 @@ func (pd *PatternDetector) Stop() error {
 
 // detectionLoop 检测循环
 func (pd *PatternDetector) detectionLoop(ctx context.Context) {
-	ticker := time.NewTicker(pd.config.DetectionInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(pd.EffectiveInterval())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-timer.C:
+			if pd.drain.draining.Load() {
+				timer.Reset(pd.EffectiveInterval())
+				continue
+			}
+
+			pd.drain.cycle.Lock()
+			start := time.Now()
 			pd.Detect()
+			elapsed := time.Since(start)
+			pd.drain.cycle.Unlock()
+
+			pd.mu.RLock()
+			observer := pd.cycleObserver
+			pd.mu.RUnlock()
+			if observer != nil {
+				observer(elapsed)
+			}
+			timer.Reset(pd.nextInterval(elapsed))
 		}
 	}
 }
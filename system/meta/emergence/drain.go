@@ -0,0 +1,31 @@
+// system/meta/emergence/drain.go
+
+package emergence
+
+import "context"
+
+// Drain 停止检测循环的后续检测, 等待进行中的检测完成并同步场状态快照日志
+// 显式调用Detect不受影响; 重新Start后恢复检测
+func (pd *PatternDetector) Drain(ctx context.Context) error {
+	pd.drain.draining.Store(true)
+
+	done := make(chan struct{})
+	go func() {
+		pd.drain.cycle.Lock()
+		defer pd.drain.cycle.Unlock()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	pd.mu.RLock()
+	journal := pd.journal
+	pd.mu.RUnlock()
+	if journal != nil {
+		return journal.Sync()
+	}
+	return nil
+}
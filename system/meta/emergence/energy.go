@@ -0,0 +1,65 @@
+// system/meta/emergence/energy.go
+
+package emergence
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// EnergyTransfer 一次检测中元素向模式提供的能量
+// 与模式能量的计算一致: 元素能量乘以组件权重, 按最大元素能量归一化
+type EnergyTransfer struct {
+	Timestamp   time.Time
+	Namespace   string
+	PatternID   string
+	PatternType string
+	Element     string  // 元素类型
+	Amount      float64 // 提供的能量
+}
+
+// OnEnergyTransfer 注册能量转移监听器, 每次检测后以各活跃模式从元素获得的能量调用
+func (pd *PatternDetector) OnEnergyTransfer(listener func([]EnergyTransfer)) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.transferListeners = append(pd.transferListeners, listener)
+}
+
+// energyTransfers 统计活跃模式从元素获得的能量, 按模式ID排序, 调用方需持有锁
+func (pd *PatternDetector) energyTransfers(state *model.FieldState) []EnergyTransfer {
+	ids := make([]string, 0, len(pd.state.activePatterns))
+	for id := range pd.state.activePatterns {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	now := pd.now()
+	transfers := make([]EnergyTransfer, 0)
+	for _, id := range ids {
+		pattern := pd.state.activePatterns[id]
+		for _, comp := range pattern.Components {
+			if comp.Type != ComponentElement {
+				continue
+			}
+			element := pd.findElement(comp.Role, state)
+			if element == nil {
+				continue
+			}
+			amount := element.Energy * comp.Weight / pd.config.maxElementEnergy
+			if amount <= 0 {
+				continue
+			}
+			transfers = append(transfers, EnergyTransfer{
+				Timestamp:   now,
+				Namespace:   pattern.Namespace,
+				PatternID:   pattern.ID,
+				PatternType: pattern.Type,
+				Element:     comp.Role,
+				Amount:      amount,
+			})
+		}
+	}
+	return transfers
+}
@@ -0,0 +1,228 @@
+// system/meta/emergence/entropy.go
+
+package emergence
+
+import (
+	"math"
+	"sort"
+)
+
+// 信息论计算参数
+const (
+	defaultEntropyBins = 8 // 连续序列离散化的分箱数
+	minSeriesLength    = 2 // 计算序列熵和互信息所需的最少样本数
+)
+
+// EntropyMetrics 模式信息论指标, 熵和互信息均归一化到[0,1]
+type EntropyMetrics struct {
+	ComponentEntropy    float64 // 组件类型分布(按权重)的香农熵
+	PropertyEntropy     float64 // 属性分布(按取值绝对值)的香农熵
+	EvolutionEntropy    float64 // 演化历史中强度序列的香农熵
+	MutualInformation   float64 // 演化历史中强度与能量序列的互信息
+	EffectiveComplexity float64 // 有效复杂度, 熵处于有序与随机之间时最大
+}
+
+// GetEntropyMetrics 计算模式的信息论指标
+func (ep *EmergentPattern) GetEntropyMetrics() EntropyMetrics {
+	metrics := EntropyMetrics{
+		ComponentEntropy: componentEntropy(ep.Components),
+		PropertyEntropy:  propertyEntropy(ep),
+	}
+
+	strength, energy := EvolutionSeries(ep)
+	if len(strength) >= minSeriesLength {
+		metrics.EvolutionEntropy = SeriesEntropy(strength, defaultEntropyBins)
+		metrics.MutualInformation = MutualInformation(strength, energy, defaultEntropyBins)
+	}
+
+	metrics.EffectiveComplexity = effectiveComplexity(metrics)
+	return metrics
+}
+
+// Features 以特征向量形式返回指标
+func (m EntropyMetrics) Features() map[string]float64 {
+	return map[string]float64{
+		"component_entropy":    m.ComponentEntropy,
+		"property_entropy":     m.PropertyEntropy,
+		"evolution_entropy":    m.EvolutionEntropy,
+		"mutual_information":   m.MutualInformation,
+		"effective_complexity": m.EffectiveComplexity,
+	}
+}
+
+// EvolutionSeries 提取演化历史中的强度和能量序列
+func EvolutionSeries(pattern *EmergentPattern) (strength, energy []float64) {
+	strength = make([]float64, 0, len(pattern.Evolution))
+	energy = make([]float64, 0, len(pattern.Evolution))
+	for _, state := range pattern.Evolution {
+		strength = append(strength, state.Strength)
+		energy = append(energy, state.Energy)
+	}
+	return strength, energy
+}
+
+// ShannonEntropy 计算非负权重分布的归一化香农熵
+// 权重按总和归一化为概率, 结果除以log(n), 少于两个正权重时为0
+func ShannonEntropy(weights []float64) float64 {
+	total := 0.0
+	n := 0
+	for _, w := range weights {
+		if w > 0 {
+			total += w
+			n++
+		}
+	}
+	if n < 2 {
+		return 0
+	}
+
+	h := 0.0
+	for _, w := range weights {
+		if w > 0 {
+			p := w / total
+			h -= p * math.Log(p)
+		}
+	}
+	return h / math.Log(float64(len(weights)))
+}
+
+// SeriesEntropy 将序列等宽分箱后计算归一化香农熵
+func SeriesEntropy(series []float64, bins int) float64 {
+	if bins < 2 {
+		bins = defaultEntropyBins
+	}
+	counts := make([]float64, bins)
+	for _, b := range discretize(series, bins) {
+		counts[b]++
+	}
+	return ShannonEntropy(counts)
+}
+
+// MutualInformation 计算两个等长序列的归一化互信息 I(X;Y)/min(H(X),H(Y))
+// 序列按各自取值范围等宽分箱, 长度不一致时按较短序列截断
+func MutualInformation(x, y []float64, bins int) float64 {
+	n := len(x)
+	if len(y) < n {
+		n = len(y)
+	}
+	if n < minSeriesLength {
+		return 0
+	}
+	if bins < 2 {
+		bins = defaultEntropyBins
+	}
+
+	bx := discretize(x[:n], bins)
+	by := discretize(y[:n], bins)
+	px := make([]float64, bins)
+	py := make([]float64, bins)
+	joint := make(map[[2]int]float64)
+	for i := 0; i < n; i++ {
+		px[bx[i]]++
+		py[by[i]]++
+		joint[[2]int{bx[i], by[i]}]++
+	}
+
+	total := float64(n)
+	hx, hy := rawEntropy(px, total), rawEntropy(py, total)
+	if hx == 0 || hy == 0 {
+		return 0
+	}
+
+	mi := 0.0
+	for key, c := range joint {
+		pxy := c / total
+		mi += pxy * math.Log(pxy/((px[key[0]]/total)*(py[key[1]]/total)))
+	}
+	return math.Max(0, math.Min(1, mi/math.Min(hx, hy)))
+}
+
+// componentEntropy 组件类型分布的熵, 同类型组件权重累加
+func componentEntropy(components []PatternComponent) float64 {
+	byType := make(map[string]float64)
+	for _, c := range components {
+		byType[c.Type] += math.Max(c.Weight, 0)
+	}
+	return ShannonEntropy(sortedValues(byType))
+}
+
+// propertyEntropy 模式及其组件属性分布的熵, 同名属性取值绝对值累加
+func propertyEntropy(pattern *EmergentPattern) float64 {
+	byKey := make(map[string]float64)
+	for k, v := range pattern.Properties {
+		byKey[k] += math.Abs(v)
+	}
+	for _, c := range pattern.Components {
+		for k, v := range c.Properties {
+			byKey[k] += math.Abs(v)
+		}
+	}
+	return ShannonEntropy(sortedValues(byKey))
+}
+
+// effectiveComplexity 有效复杂度
+// 以组件、属性和演化熵的均值h计算4h(1-h): 完全有序或完全随机时为0, h=0.5时为1;
+// 演化序列间的互信息体现可压缩的规律性, 按其比例提升复杂度
+func effectiveComplexity(m EntropyMetrics) float64 {
+	values := []float64{m.ComponentEntropy, m.PropertyEntropy}
+	if m.EvolutionEntropy > 0 {
+		values = append(values, m.EvolutionEntropy)
+	}
+	h := 0.0
+	for _, v := range values {
+		h += v
+	}
+	h /= float64(len(values))
+
+	return 4 * h * (1 - h) * (1 + m.MutualInformation) / 2
+}
+
+// discretize 按序列取值范围等宽分箱, 常数序列全部落入第0箱
+func discretize(series []float64, bins int) []int {
+	result := make([]int, len(series))
+	if len(series) == 0 {
+		return result
+	}
+	lo, hi := series[0], series[0]
+	for _, v := range series {
+		lo = math.Min(lo, v)
+		hi = math.Max(hi, v)
+	}
+	if hi-lo < 1e-12 {
+		return result
+	}
+	for i, v := range series {
+		b := int((v - lo) / (hi - lo) * float64(bins))
+		if b >= bins {
+			b = bins - 1
+		}
+		result[i] = b
+	}
+	return result
+}
+
+// rawEntropy 计数分布的香农熵(未归一化)
+func rawEntropy(counts []float64, total float64) float64 {
+	h := 0.0
+	for _, c := range counts {
+		if c > 0 {
+			p := c / total
+			h -= p * math.Log(p)
+		}
+	}
+	return h
+}
+
+// sortedValues 按键排序返回映射的值, 保证计算结果稳定
+func sortedValues(m map[string]float64) []float64 {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	values := make([]float64, 0, len(keys))
+	for _, k := range keys {
+		values = append(values, m[k])
+	}
+	return values
+}
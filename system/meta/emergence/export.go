@@ -0,0 +1,247 @@
+// system/meta/emergence/export.go
+
+package emergence
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 批量导出分页参数
+const (
+	DefaultExportLimit = 1000
+	MaxExportLimit     = 10000
+)
+
+// ExportQuery 批量导出查询
+// 模式按命名空间和ID排序, 演化状态按命名空间、模式ID和时间排序; 以上一页返回的游标续取
+type ExportQuery struct {
+	Since  time.Time         `json:"since,omitempty"`  // 仅导出此时间之后更新的模式或记录的演化状态
+	Types  []string          `json:"types,omitempty"`  // 模式类型过滤, 为空时不过滤
+	Tags   map[string]string `json:"tags,omitempty"`   // 标签过滤, 见types.Annotations.MatchTags
+	Cursor string            `json:"cursor,omitempty"` // 分页游标, 为空时从头开始
+	Limit  int               `json:"limit,omitempty"`  // 单页记录数, 0为DefaultExportLimit, 上限MaxExportLimit
+}
+
+// PatternRecord 导出的模式记录
+type PatternRecord struct {
+	ID              string             `json:"id"`
+	Namespace       string             `json:"namespace,omitempty"`
+	Type            string             `json:"type"`
+	Strength        float64            `json:"strength"`
+	Stability       float64            `json:"stability"`
+	Energy          float64            `json:"energy"`
+	Formation       time.Time          `json:"formation"`
+	LastUpdate      time.Time          `json:"last_update"`
+	Components      []string           `json:"components,omitempty"` // 组件ID
+	Properties      map[string]float64 `json:"properties,omitempty"`
+	EvolutionStates int                `json:"evolution_states"` // 保留的演化状态数
+	HistorySamples  int                `json:"history_samples"`  // 超出保留预算而聚合的状态数
+
+	Pin         *Provenance                 `json:"pin,omitempty"`         // 人工固定来源
+	Overrides   map[string]PropertyOverride `json:"overrides,omitempty"`   // 人工覆盖, 计算值见Strength等字段
+	Annotations types.Annotations           `json:"annotations,omitempty"` // 用户标注
+	Provisional bool                        `json:"provisional,omitempty"` // 启动预热期间检测到
+}
+
+// EvolutionRecord 导出的演化状态
+type EvolutionRecord struct {
+	PatternID  string             `json:"pattern_id"`
+	Namespace  string             `json:"namespace,omitempty"`
+	Type       string             `json:"type"`
+	Timestamp  time.Time          `json:"timestamp"`
+	Strength   float64            `json:"strength"`
+	Energy     float64            `json:"energy"`
+	Summary    bool               `json:"summary"` // 是否为降采样摘要
+	Samples    int                `json:"samples"` // 代表的原始状态数
+	Properties map[string]float64 `json:"properties,omitempty"`
+	Tags       map[string]string  `json:"tags,omitempty"` // 所属模式的标签
+}
+
+// ExportPatterns 从多个检测器分页导出活跃模式, 返回记录和下一页游标, 游标为空表示已取完
+func ExportPatterns(detectors []*PatternDetector, q ExportQuery) ([]PatternRecord, string, error) {
+	after, err := decodeExportCursor(q.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := exportLimit(q.Limit)
+
+	patterns := exportCandidates(detectors, q)
+	records := make([]PatternRecord, 0)
+	for _, p := range patterns {
+		if !q.Since.IsZero() && !p.LastUpdate.After(q.Since) {
+			continue
+		}
+		key := []string{p.Namespace, p.ID}
+		if after != nil && compareExportKeys(key, after) <= 0 {
+			continue
+		}
+		if len(records) == limit {
+			return records, encodeExportCursor([]string{records[len(records)-1].Namespace, records[len(records)-1].ID}), nil
+		}
+		records = append(records, newPatternRecord(p))
+	}
+	return records, "", nil
+}
+
+// ExportEvolution 从多个检测器分页导出活跃模式的演化历史, 返回记录和下一页游标
+func ExportEvolution(detectors []*PatternDetector, q ExportQuery) ([]EvolutionRecord, string, error) {
+	after, err := decodeExportCursor(q.Cursor)
+	if err != nil {
+		return nil, "", err
+	}
+	limit := exportLimit(q.Limit)
+
+	records := make([]EvolutionRecord, 0)
+	var lastKey []string
+	for _, p := range exportCandidates(detectors, q) {
+		// 整个模式都在游标之前时跳过
+		if after != nil && compareExportKeys([]string{p.Namespace, p.ID}, after[:2]) < 0 {
+			continue
+		}
+
+		states := append([]PatternState(nil), p.Evolution...)
+		sort.SliceStable(states, func(i, j int) bool {
+			return states[i].Timestamp.Before(states[j].Timestamp)
+		})
+		for i, state := range states {
+			if !q.Since.IsZero() && !state.Timestamp.After(q.Since) {
+				continue
+			}
+			key := []string{p.Namespace, p.ID, exportSequence(state.Timestamp, i)}
+			if after != nil && compareExportKeys(key, after) <= 0 {
+				continue
+			}
+			if len(records) == limit {
+				return records, encodeExportCursor(lastKey), nil
+			}
+			records = append(records, newEvolutionRecord(p, state))
+			lastKey = key
+		}
+	}
+	return records, "", nil
+}
+
+// exportCandidates 收集满足类型过滤的活跃模式, 按命名空间和ID排序
+func exportCandidates(detectors []*PatternDetector, q ExportQuery) []EmergentPattern {
+	allowed := make(map[string]bool, len(q.Types))
+	for _, t := range q.Types {
+		allowed[t] = true
+	}
+
+	patterns := make([]EmergentPattern, 0)
+	for _, pd := range detectors {
+		if pd == nil {
+			continue
+		}
+		for _, p := range pd.GetActivePatternsSnapshot().Patterns() {
+			if len(allowed) > 0 && !allowed[p.Type] {
+				continue
+			}
+			if !p.Annotations.MatchTags(q.Tags) {
+				continue
+			}
+			patterns = append(patterns, p)
+		}
+	}
+	sort.Slice(patterns, func(i, j int) bool {
+		return compareExportKeys([]string{patterns[i].Namespace, patterns[i].ID},
+			[]string{patterns[j].Namespace, patterns[j].ID}) < 0
+	})
+	return patterns
+}
+
+// newPatternRecord 构造模式记录
+func newPatternRecord(p EmergentPattern) PatternRecord {
+	record := PatternRecord{
+		ID:              p.ID,
+		Namespace:       p.Namespace,
+		Type:            p.Type,
+		Strength:        p.Strength,
+		Stability:       p.Stability,
+		Energy:          p.Energy,
+		Formation:       p.Formation,
+		LastUpdate:      p.LastUpdate,
+		Properties:      cloneProperties(p.Properties),
+		EvolutionStates: len(p.Evolution),
+		HistorySamples:  p.History.Samples,
+		Pin:             p.Pin,
+		Overrides:       p.Overrides,
+		Annotations:     p.Annotations.Clone(),
+		Provisional:     p.Provisional,
+	}
+	for _, c := range p.Components {
+		if c.ID != "" {
+			record.Components = append(record.Components, c.ID)
+		}
+	}
+	return record
+}
+
+// newEvolutionRecord 构造演化记录
+func newEvolutionRecord(p EmergentPattern, state PatternState) EvolutionRecord {
+	return EvolutionRecord{
+		PatternID:  p.ID,
+		Namespace:  p.Namespace,
+		Type:       p.Type,
+		Timestamp:  state.Timestamp,
+		Strength:   state.Strength,
+		Energy:     state.Energy,
+		Summary:    IsSummary(state),
+		Samples:    stateSamples(state),
+		Properties: cloneProperties(state.Properties),
+		Tags:       p.Annotations.Clone().Tags,
+	}
+}
+
+// exportSequence 演化状态在模式内的排序键: 定长时间戳加序号, 按字符串比较即按时间排序
+func exportSequence(t time.Time, index int) string {
+	return fmt.Sprintf("%020d.%06d", uint64(t.UnixNano())^(1<<63), index)
+}
+
+// compareExportKeys 按分量比较排序键, 较短的键是较长键的前缀时较小
+func compareExportKeys(a, b []string) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if c := strings.Compare(a[i], b[i]); c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// encodeExportCursor 将排序键编码为不透明游标
+func encodeExportCursor(key []string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strings.Join(key, "\x00")))
+}
+
+// decodeExportCursor 解码游标, 空游标返回nil
+func decodeExportCursor(cursor string) ([]string, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "invalid export cursor", err)
+	}
+	key := strings.Split(string(raw), "\x00")
+	if len(key) < 2 {
+		return nil, types.NewSystemError(types.ErrInvalid, "invalid export cursor", nil)
+	}
+	return key, nil
+}
+
+// exportLimit 规范化单页记录数
+func exportLimit(limit int) int {
+	if limit <= 0 {
+		return DefaultExportLimit
+	}
+	if limit > MaxExportLimit {
+		return MaxExportLimit
+	}
+	return limit
+}
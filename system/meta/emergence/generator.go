@@ -3,12 +3,12 @@
 package emergence
 
 import (
-	"fmt"
 	"math"
 	"sort"
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/meta/field"
 )
@@ -733,7 +733,7 @@ func (pg *PropertyGenerator) calculatePatternWeight(pattern EmergentPattern, pot
 	return weight
 }
 func generatePropertyID() string {
-	return fmt.Sprintf("prop_%d", time.Now().UnixNano())
+	return core.NewID("prop_")
 }
 
 func copyPropertyState(property *EmergentProperty) *EmergentProperty {
@@ -0,0 +1,309 @@
+// system/meta/emergence/journal.go
+
+package emergence
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/encryption"
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 快照日志单行上限
+const maxJournalLine = 16 << 20
+
+// journalLabel 快照日志加密用途标识
+const journalLabel = "emergence.journal"
+
+// FieldSnapshot 可序列化的场状态快照
+type FieldSnapshot struct {
+	Timestamp    time.Time                 `json:"timestamp"`
+	Energy       float64                   `json:"energy"`
+	Elements     []string                  `json:"elements,omitempty"` // 五行元素名称
+	Properties   map[string]float64        `json:"properties,omitempty"`
+	Distribution []field.DistributionPoint `json:"distribution,omitempty"` // 外部注入的能量分布
+	Quantum      *QuantumSnapshot          `json:"quantum,omitempty"`
+}
+
+// QuantumSnapshot 量子态快照
+type QuantumSnapshot struct {
+	Probability float64      `json:"probability"`
+	Phase       float64      `json:"phase"`
+	Energy      float64      `json:"energy"`
+	Decoherence float64      `json:"decoherence"`
+	Amplitude   [][2]float64 `json:"amplitude,omitempty"` // 实部和虚部
+}
+
+// NewFieldSnapshot 由场状态构造快照
+func NewFieldSnapshot(state *model.FieldState) FieldSnapshot {
+	snapshot := FieldSnapshot{
+		Timestamp:  state.Timestamp,
+		Energy:     state.Energy,
+		Properties: cloneProperties(state.Properties),
+	}
+	if snapshot.Timestamp.IsZero() {
+		snapshot.Timestamp = time.Now()
+	}
+	for _, elem := range state.Elements {
+		if elem != nil {
+			snapshot.Elements = append(snapshot.Elements, elem.String())
+		}
+	}
+
+	for p, energy := range state.Distribution {
+		snapshot.Distribution = append(snapshot.Distribution, field.DistributionPoint{X: p.X, Y: p.Y, Z: p.Z, Energy: energy})
+	}
+	sort.Slice(snapshot.Distribution, func(i, j int) bool {
+		a, b := snapshot.Distribution[i], snapshot.Distribution[j]
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+
+	if q := state.Quantum; q != nil {
+		quantum := &QuantumSnapshot{
+			Probability: q.GetProbability(),
+			Phase:       q.GetPhase(),
+			Energy:      q.GetEnergy(),
+			Decoherence: q.GetDecoherence(),
+		}
+		for _, a := range q.GetAmplitude() {
+			quantum.Amplitude = append(quantum.Amplitude, [2]float64{real(a), imag(a)})
+		}
+		snapshot.Quantum = quantum
+	}
+	return snapshot
+}
+
+// FieldState 由快照恢复场状态
+func (s FieldSnapshot) FieldState() (*model.FieldState, error) {
+	state := &model.FieldState{
+		Energy:     s.Energy,
+		Properties: cloneProperties(s.Properties),
+		Timestamp:  s.Timestamp,
+	}
+	if state.Properties == nil {
+		state.Properties = make(map[string]float64)
+	}
+
+	for _, name := range s.Elements {
+		elem, ok := model.WuXingElementFromString(name)
+		if !ok {
+			return nil, types.NewSystemError(types.ErrInvalid, "unknown element in snapshot", nil).
+				WithContext("element", name)
+		}
+		state.Elements = append(state.Elements, &elem)
+	}
+
+	if len(s.Distribution) > 0 {
+		state.Distribution = make(map[core.Point]float64, len(s.Distribution))
+		for _, p := range s.Distribution {
+			state.Distribution[core.Point{X: p.X, Y: p.Y, Z: p.Z}] += p.Energy
+		}
+	}
+
+	state.Quantum = core.NewQuantumState()
+	if q := s.Quantum; q != nil {
+		amplitude := make([]complex128, len(q.Amplitude))
+		for i, a := range q.Amplitude {
+			amplitude[i] = complex(a[0], a[1])
+		}
+		for _, err := range []error{
+			state.Quantum.SetAmplitude(amplitude),
+			state.Quantum.SetProbability(q.Probability),
+			state.Quantum.SetPhase(q.Phase),
+			state.Quantum.SetEnergy(q.Energy),
+			state.Quantum.SetDecoherence(q.Decoherence),
+		} {
+			if err != nil {
+				return nil, types.NewSystemError(types.ErrInvalid, "invalid quantum state in snapshot", err)
+			}
+		}
+	}
+	return state, nil
+}
+
+// FieldJournal 场状态快照日志, 每行一条JSON; 设置钥匙串后每行单独加密
+type FieldJournal struct {
+	mu      sync.Mutex
+	w       io.Writer
+	closer  io.Closer
+	keyring *encryption.Keyring
+	count   int64
+	err     error // 最近一次写入错误
+}
+
+// NewFieldJournal 创建写入w的快照日志
+func NewFieldJournal(w io.Writer) *FieldJournal {
+	return &FieldJournal{w: w}
+}
+
+// OpenFieldJournal 以追加方式打开快照日志文件
+func OpenFieldJournal(path string) (*FieldJournal, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, types.NewSystemError(types.ErrIO, "failed to open field journal", err).
+			WithContext("path", path)
+	}
+	return &FieldJournal{w: file, closer: file}, nil
+}
+
+// Append 追加场状态快照
+func (j *FieldJournal) Append(state *model.FieldState) error {
+	data, err := json.Marshal(NewFieldSnapshot(state))
+	if err != nil {
+		return types.NewSystemError(types.ErrIO, "failed to encode field snapshot", err)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if data, err = encryption.SealLine(j.keyring, journalLabel, data); err != nil {
+		j.err = err
+		return err
+	}
+	if _, err := j.w.Write(append(data, '\n')); err != nil {
+		j.err = err
+		return types.NewSystemError(types.ErrIO, "failed to write field snapshot", err)
+	}
+	j.count++
+	return nil
+}
+
+// SetKeyring 设置加密钥匙串, 此后写入的快照以当前密钥加密; nil恢复明文写入
+func (j *FieldJournal) SetKeyring(kr *encryption.Keyring) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.keyring = kr
+}
+
+// Stats 已写入的快照数和最近一次写入错误
+func (j *FieldJournal) Stats() (int64, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.count, j.err
+}
+
+// Sync 将已写入的快照刷写到存储, 写入目标不支持同步或已关闭时不做处理
+func (j *FieldJournal) Sync() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	syncer, ok := j.w.(interface{ Sync() error })
+	if !ok {
+		return nil
+	}
+	if err := syncer.Sync(); err != nil && !errors.Is(err, os.ErrClosed) {
+		return types.NewSystemError(types.ErrIO, "failed to sync field journal", err)
+	}
+	return nil
+}
+
+// Close 关闭日志, 由OpenFieldJournal打开时关闭文件
+func (j *FieldJournal) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.closer == nil {
+		return nil
+	}
+	err := j.closer.Close()
+	j.closer = nil
+	return err
+}
+
+// SetJournal 设置场状态快照日志, 每次检测取得的场状态写入日志; nil停止记录
+func (pd *PatternDetector) SetJournal(journal *FieldJournal) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.journal = journal
+}
+
+// SnapshotSource 已记录的场状态快照来源, 按时间顺序返回, 取完时返回io.EOF
+type SnapshotSource interface {
+	Next() (*model.FieldState, error)
+}
+
+// sliceSource 内存中的快照序列
+type sliceSource struct {
+	states []*model.FieldState
+	next   int
+}
+
+// NewSliceSource 由场状态序列创建快照来源
+func NewSliceSource(states []*model.FieldState) SnapshotSource {
+	return &sliceSource{states: states}
+}
+
+func (s *sliceSource) Next() (*model.FieldState, error) {
+	if s.next >= len(s.states) {
+		return nil, io.EOF
+	}
+	state := s.states[s.next]
+	s.next++
+	return state, nil
+}
+
+// journalSource 快照日志读取
+type journalSource struct {
+	scanner *bufio.Scanner
+	keyring *encryption.Keyring
+	line    int
+}
+
+// NewJournalSource 创建读取快照日志的快照来源, 空行被跳过
+func NewJournalSource(r io.Reader) SnapshotSource {
+	return NewEncryptedJournalSource(r, nil)
+}
+
+// NewEncryptedJournalSource 创建读取加密快照日志的快照来源, 加密行以kr解密, 明文行照常读取
+func NewEncryptedJournalSource(r io.Reader, kr *encryption.Keyring) SnapshotSource {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxJournalLine)
+	return &journalSource{scanner: scanner, keyring: kr}
+}
+
+func (s *journalSource) Next() (*model.FieldState, error) {
+	for s.scanner.Scan() {
+		s.line++
+		line := s.scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+		line, err := encryption.OpenLine(s.keyring, journalLabel, line)
+		if err != nil {
+			if sysErr, ok := err.(*types.SystemError); ok {
+				sysErr.WithContext("line", s.line)
+			}
+			return nil, err
+		}
+		var snapshot FieldSnapshot
+		if err := json.Unmarshal(line, &snapshot); err != nil {
+			return nil, types.NewSystemError(types.ErrInvalid, "invalid field snapshot", err).
+				WithContext("line", s.line)
+		}
+		state, err := snapshot.FieldState()
+		if err != nil {
+			if sysErr, ok := err.(*types.SystemError); ok {
+				sysErr.WithContext("line", s.line)
+			}
+			return nil, err
+		}
+		return state, nil
+	}
+	if err := s.scanner.Err(); err != nil {
+		return nil, types.NewSystemError(types.ErrIO, "failed to read field journal", err)
+	}
+	return nil, io.EOF
+}
@@ -0,0 +1,78 @@
+// system/meta/emergence/memory.go
+
+package emergence
+
+import (
+	"sort"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 检测器缓存
+const (
+	CachePatterns = "emergence.patterns" // 活跃模式及其演化历史
+	CacheHistory  = "emergence.history"  // 检测历史
+)
+
+// MemoryUsage 活跃模式和检测历史的近似内存占用
+func (pd *PatternDetector) MemoryUsage() []types.MemoryUsage {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	return []types.MemoryUsage{
+		{
+			Cache:   CachePatterns,
+			Entries: len(pd.state.activePatterns),
+			Bytes:   types.ApproxSize(pd.state.activePatterns),
+		},
+		{
+			Cache:   CacheHistory,
+			Entries: len(pd.state.history),
+			Bytes:   types.ApproxSize(pd.state.history) + types.ApproxSize(pd.state.summaries),
+		},
+	}
+}
+
+// EvictMemory 驱逐最久未更新的活跃模式(固定的模式除外)或最早的检测事件, 保留最近keep条
+func (pd *PatternDetector) EvictMemory(cache string, keep int) int {
+	if keep < 0 {
+		keep = 0
+	}
+
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	switch cache {
+	case CachePatterns:
+		excess := len(pd.state.activePatterns) - keep
+		if excess <= 0 {
+			return 0
+		}
+		// 固定的模式不驱逐
+		patterns := make([]*EmergentPattern, 0, len(pd.state.activePatterns))
+		for _, p := range pd.state.activePatterns {
+			if p.Pin == nil {
+				patterns = append(patterns, p)
+			}
+		}
+		if excess > len(patterns) {
+			excess = len(patterns)
+		}
+		sort.Slice(patterns, func(i, j int) bool {
+			return patterns[i].LastUpdate.Before(patterns[j].LastUpdate)
+		})
+		for _, p := range patterns[:excess] {
+			delete(pd.state.activePatterns, p.ID)
+		}
+		pd.state.version++
+		return excess
+	case CacheHistory:
+		excess := len(pd.state.history) - keep
+		if excess <= 0 {
+			return 0
+		}
+		pd.state.history = append([]DetectionEvent(nil), pd.state.history[excess:]...)
+		return excess
+	}
+	return 0
+}
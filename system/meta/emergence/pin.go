@@ -0,0 +1,208 @@
+// system/meta/emergence/pin.go
+
+package emergence
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 可覆盖的模式字段, 其余名称覆盖Properties中的同名属性
+const (
+	OverrideStrength  = "strength"
+	OverrideStability = "stability"
+	OverrideEnergy    = "energy"
+)
+
+// Provenance 人工操作的来源
+type Provenance struct {
+	Actor  string    `json:"actor"`  // 操作者
+	Reason string    `json:"reason"` // 操作原因
+	At     time.Time `json:"at"`     // 操作时间, 为零时取检测时钟
+}
+
+// PropertyOverride 人工覆盖的属性值, 计算值仍保留在模式的原字段中
+type PropertyOverride struct {
+	Value      float64    `json:"value"`
+	Computed   float64    `json:"computed"` // 设置覆盖时的计算值
+	Provenance Provenance `json:"provenance"`
+}
+
+// IsPinned 模式是否被人工固定
+func (p EmergentPattern) IsPinned() bool {
+	return p.Pin != nil
+}
+
+// Effective 字段或属性的有效值: 有人工覆盖时取覆盖值, 否则取计算值
+func (p EmergentPattern) Effective(name string) float64 {
+	if o, ok := p.Overrides[name]; ok {
+		return o.Value
+	}
+	return computedValue(p, name)
+}
+
+// EffectiveProperties 合并人工覆盖后的属性副本, 不含strength等字段覆盖
+func (p EmergentPattern) EffectiveProperties() map[string]float64 {
+	props := cloneProperties(p.Properties)
+	if props == nil && len(p.Overrides) > 0 {
+		props = make(map[string]float64, len(p.Overrides))
+	}
+	for name, o := range p.Overrides {
+		if !isFieldOverride(name) {
+			props[name] = o.Value
+		}
+	}
+	return props
+}
+
+// computedValue 字段或属性的计算值
+func computedValue(p EmergentPattern, name string) float64 {
+	switch name {
+	case OverrideStrength:
+		return p.Strength
+	case OverrideStability:
+		return p.Stability
+	case OverrideEnergy:
+		return p.Energy
+	default:
+		return p.Properties[name]
+	}
+}
+
+// isFieldOverride 是否为模式字段而非Properties属性的覆盖
+func isFieldOverride(name string) bool {
+	return name == OverrideStrength || name == OverrideStability || name == OverrideEnergy
+}
+
+// Pin 固定活跃模式, 固定的模式不因超时或强度、稳定性衰减而被移除; 已固定时更新来源
+func (pd *PatternDetector) Pin(id string, provenance Provenance) error {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pattern, err := pd.activePattern(id)
+	if err != nil {
+		return err
+	}
+	provenance = pd.stamp(provenance)
+	pattern.Pin = &provenance
+	pd.state.version++
+	return nil
+}
+
+// Unpin 取消固定, 模式此后按常规规则在下次检测时判断是否移除
+func (pd *PatternDetector) Unpin(id string) error {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pattern, err := pd.activePattern(id)
+	if err != nil {
+		return err
+	}
+	if pattern.Pin != nil {
+		pattern.Pin = nil
+		pd.state.version++
+	}
+	return nil
+}
+
+// SetOverride 人工覆盖模式的字段(OverrideStrength等)或属性, 覆盖值用于移除判断和优先级分类, 计算值照常更新
+func (pd *PatternDetector) SetOverride(id, name string, value float64, provenance Provenance) error {
+	if name == "" {
+		return types.NewSystemError(types.ErrInvalid, "empty override name", nil).
+			WithContext("pattern", id)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return types.NewSystemError(types.ErrInvalid, "override value must be finite", nil).
+			WithContext("pattern", id).
+			WithContext("name", name)
+	}
+
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pattern, err := pd.activePattern(id)
+	if err != nil {
+		return err
+	}
+
+	// 写时复制, 已取得的模式副本与检测器共享覆盖表
+	overrides := make(map[string]PropertyOverride, len(pattern.Overrides)+1)
+	for k, v := range pattern.Overrides {
+		overrides[k] = v
+	}
+	overrides[name] = PropertyOverride{
+		Value:      value,
+		Computed:   computedValue(*pattern, name),
+		Provenance: pd.stamp(provenance),
+	}
+	pattern.Overrides = overrides
+	pd.state.version++
+	return nil
+}
+
+// ClearOverride 移除人工覆盖, name为空时移除该模式的全部覆盖
+func (pd *PatternDetector) ClearOverride(id, name string) error {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	pattern, err := pd.activePattern(id)
+	if err != nil {
+		return err
+	}
+	switch {
+	case len(pattern.Overrides) == 0:
+		return nil
+	case name == "":
+		pattern.Overrides = nil
+	default:
+		if _, ok := pattern.Overrides[name]; !ok {
+			return nil
+		}
+		overrides := make(map[string]PropertyOverride, len(pattern.Overrides))
+		for k, v := range pattern.Overrides {
+			if k != name {
+				overrides[k] = v
+			}
+		}
+		pattern.Overrides = overrides
+	}
+	pd.state.version++
+	return nil
+}
+
+// PinnedPatterns 被固定的活跃模式, 按ID排序
+func (pd *PatternDetector) PinnedPatterns() []EmergentPattern {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	pinned := make([]EmergentPattern, 0)
+	for _, pattern := range pd.state.activePatterns {
+		if pattern.Pin != nil {
+			pinned = append(pinned, *pattern)
+		}
+	}
+	sort.Slice(pinned, func(i, j int) bool { return pinned[i].ID < pinned[j].ID })
+	return pinned
+}
+
+// activePattern 获取活跃模式(调用方持有锁)
+func (pd *PatternDetector) activePattern(id string) (*EmergentPattern, error) {
+	pattern, ok := pd.state.activePatterns[id]
+	if !ok {
+		return nil, types.NewSystemError(types.ErrNotFound, "pattern not found", nil).
+			WithContext("pattern", id).
+			WithContext("namespace", pd.config.namespace)
+	}
+	return pattern, nil
+}
+
+// stamp 补全操作时间(调用方持有锁)
+func (pd *PatternDetector) stamp(provenance Provenance) Provenance {
+	if provenance.At.IsZero() {
+		provenance.At = pd.now()
+	}
+	return provenance
+}
@@ -0,0 +1,209 @@
+// system/meta/emergence/plugin.go
+
+package emergence
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// 默认插件超时
+const defaultPluginTimeout = time.Second
+
+// Detector 检测插件接口
+// 每轮检测在内置检测之后并行执行全部插件, 插件不得修改传入的场状态
+type Detector interface {
+	Detect(state *model.FieldState) []EmergentPattern
+}
+
+// DetectorFunc 函数形式的检测插件
+type DetectorFunc func(state *model.FieldState) []EmergentPattern
+
+// Detect 执行检测
+func (f DetectorFunc) Detect(state *model.FieldState) []EmergentPattern {
+	return f(state)
+}
+
+// PluginMetrics 插件指标
+type PluginMetrics struct {
+	Name          string        // 插件名称
+	Timeout       time.Duration // 超时时间
+	Runs          int64         // 执行次数
+	Patterns      int64         // 产出的模式数
+	Timeouts      int64         // 超时次数
+	Panics        int64         // 崩溃次数
+	LastDuration  time.Duration // 最近一次耗时
+	TotalDuration time.Duration // 累计耗时
+	LastRun       time.Time     // 最近执行时间
+	LastError     string        // 最近一次错误
+}
+
+// detectorPlugin 已注册的插件
+type detectorPlugin struct {
+	name     string
+	detector Detector
+	timeout  time.Duration
+	metrics  PluginMetrics
+}
+
+// pluginResult 插件执行结果
+type pluginResult struct {
+	patterns []EmergentPattern
+	err      error
+}
+
+// RegisterPlugin 注册检测插件, timeout<=0时使用默认超时
+func (pd *PatternDetector) RegisterPlugin(name string, detector Detector, timeout time.Duration) error {
+	if name == "" {
+		return model.NewModelError(model.ErrCodeValidation, "empty plugin name", nil)
+	}
+	if detector == nil {
+		return model.NewModelError(model.ErrCodeValidation, "nil detector plugin", nil)
+	}
+	if timeout <= 0 {
+		timeout = defaultPluginTimeout
+	}
+
+	pd.plugins.mu.Lock()
+	defer pd.plugins.mu.Unlock()
+
+	for _, p := range pd.plugins.list {
+		if p.name == name {
+			return model.NewModelError(model.ErrCodeValidation,
+				fmt.Sprintf("detector plugin %s already registered", name), nil)
+		}
+	}
+
+	pd.plugins.list = append(pd.plugins.list, &detectorPlugin{
+		name:     name,
+		detector: detector,
+		timeout:  timeout,
+		metrics:  PluginMetrics{Name: name, Timeout: timeout},
+	})
+	return nil
+}
+
+// UnregisterPlugin 注销检测插件
+func (pd *PatternDetector) UnregisterPlugin(name string) error {
+	pd.plugins.mu.Lock()
+	defer pd.plugins.mu.Unlock()
+
+	for i, p := range pd.plugins.list {
+		if p.name == name {
+			pd.plugins.list = append(pd.plugins.list[:i], pd.plugins.list[i+1:]...)
+			return nil
+		}
+	}
+	return model.NewModelError(model.ErrCodeNotFound,
+		fmt.Sprintf("detector plugin %s not registered", name), nil)
+}
+
+// GetPluginMetrics 获取各插件指标, 按名称排序
+func (pd *PatternDetector) GetPluginMetrics() []PluginMetrics {
+	pd.plugins.mu.RLock()
+	defer pd.plugins.mu.RUnlock()
+
+	metrics := make([]PluginMetrics, 0, len(pd.plugins.list))
+	for _, p := range pd.plugins.list {
+		metrics = append(metrics, p.metrics)
+	}
+	sort.Slice(metrics, func(i, j int) bool {
+		return metrics[i].Name < metrics[j].Name
+	})
+	return metrics
+}
+
+// runPlugins 并行执行全部插件, 超时或崩溃的插件结果被丢弃
+func (pd *PatternDetector) runPlugins(state *model.FieldState) []EmergentPattern {
+	pd.plugins.mu.RLock()
+	plugins := append([]*detectorPlugin{}, pd.plugins.list...)
+	pd.plugins.mu.RUnlock()
+
+	if len(plugins) == 0 {
+		return nil
+	}
+
+	type outcome struct {
+		plugin   *detectorPlugin
+		result   pluginResult
+		duration time.Duration
+		timedOut bool
+	}
+
+	outcomes := make(chan outcome, len(plugins))
+	for _, p := range plugins {
+		go func(p *detectorPlugin) {
+			start := time.Now()
+			// 结果通道带缓冲, 超时后插件协程仍可写入并退出
+			done := make(chan pluginResult, 1)
+			go func() {
+				defer func() {
+					if r := recover(); r != nil {
+						done <- pluginResult{err: fmt.Errorf("plugin panic: %v", r)}
+					}
+				}()
+				done <- pluginResult{patterns: p.detector.Detect(state)}
+			}()
+
+			timer := time.NewTimer(p.timeout)
+			defer timer.Stop()
+
+			select {
+			case result := <-done:
+				outcomes <- outcome{plugin: p, result: result, duration: time.Since(start)}
+			case <-timer.C:
+				outcomes <- outcome{plugin: p, duration: p.timeout, timedOut: true}
+			}
+		}(p)
+	}
+
+	// 先收集结果再更新指标, 避免等待插件时阻塞指标读取
+	results := make([]outcome, 0, len(plugins))
+	for range plugins {
+		results = append(results, <-outcomes)
+	}
+
+	patterns := make([]EmergentPattern, 0)
+	now := time.Now()
+
+	pd.plugins.mu.Lock()
+	defer pd.plugins.mu.Unlock()
+
+	for _, o := range results {
+		m := &o.plugin.metrics
+		m.Runs++
+		m.LastRun = now
+		m.LastDuration = o.duration
+		m.TotalDuration += o.duration
+
+		switch {
+		case o.timedOut:
+			m.Timeouts++
+			m.LastError = fmt.Sprintf("timed out after %v", o.plugin.timeout)
+			continue
+		case o.result.err != nil:
+			m.Panics++
+			m.LastError = o.result.err.Error()
+			continue
+		}
+
+		for _, pattern := range o.result.patterns {
+			if pattern.Type == "" {
+				pattern.Type = o.plugin.name
+			}
+			if pattern.ID == "" {
+				pattern.ID = generatePatternID()
+			}
+			if pattern.Formation.IsZero() {
+				pattern.Formation = pd.now()
+			}
+			patterns = append(patterns, pattern)
+		}
+		m.Patterns += int64(len(o.result.patterns))
+	}
+
+	return patterns
+}
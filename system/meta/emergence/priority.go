@@ -0,0 +1,186 @@
+// system/meta/emergence/priority.go
+
+package emergence
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// DefaultPriorityClass 未匹配任何配置类别的模式所属类别, 优先级最低
+const DefaultPriorityClass = "default"
+
+// 连续推迟的默认周期上限
+const defaultPriorityMaxWait = 5
+
+// PriorityStats 优先级类别的处理统计
+type PriorityStats struct {
+	Class     string `json:"class"`
+	Quota     int    `json:"quota"`     // 每周期配额, 0表示不限
+	Processed uint64 `json:"processed"` // 累计处理数
+	Deferred  uint64 `json:"deferred"`  // 累计推迟次数
+	Promoted  uint64 `json:"promoted"`  // 因等待过久提前处理的次数
+	Waiting   int    `json:"waiting"`   // 当前被推迟的模式数
+	MaxWait   int    `json:"max_wait"`  // 当前被推迟模式的最长连续推迟周期数
+}
+
+// PriorityScheduler 模式处理优先级调度
+// 每个处理周期按类别从高到低、同类别内按强度从高到低排序, 超出类别配额的模式推迟到后续周期;
+// 连续推迟达到MaxWait个周期的模式排在最前且不受配额限制, 避免低优先级模式长期得不到处理
+// 每个处理阶段使用独立的调度器, 以便各自记录推迟周期
+type PriorityScheduler struct {
+	mu sync.Mutex
+
+	// 基础配置
+	config types.PriorityConfig
+
+	// 模式ID -> 连续推迟周期数
+	waits map[string]int
+
+	// 各类别统计, 最后一项为默认类别
+	stats []PriorityStats
+}
+
+// NewPriorityScheduler 创建优先级调度器
+func NewPriorityScheduler(config types.PriorityConfig) *PriorityScheduler {
+	if config.MaxWait <= 0 {
+		config.MaxWait = defaultPriorityMaxWait
+	}
+
+	ps := &PriorityScheduler{
+		config: config,
+		waits:  make(map[string]int),
+		stats:  make([]PriorityStats, len(config.Classes)+1),
+	}
+	for i, class := range config.Classes {
+		ps.stats[i] = PriorityStats{Class: class.Name, Quota: class.Quota}
+	}
+	ps.stats[len(config.Classes)] = PriorityStats{Class: DefaultPriorityClass, Quota: config.DefaultQuota}
+	return ps
+}
+
+// Enabled 是否启用优先级调度, nil调度器视为未启用
+func (ps *PriorityScheduler) Enabled() bool {
+	return ps != nil && ps.config.Enabled
+}
+
+// Classify 模式所属类别名称
+func (ps *PriorityScheduler) Classify(pattern EmergentPattern) string {
+	if ps == nil {
+		return DefaultPriorityClass
+	}
+	return ps.stats[ps.classify(pattern)].Class
+}
+
+// classify 模式所属类别的序号, 默认类别为len(Classes); 强度有人工覆盖时取覆盖值
+func (ps *PriorityScheduler) classify(pattern EmergentPattern) int {
+	strength := pattern.Effective(OverrideStrength)
+	for i, class := range ps.config.Classes {
+		if strength < class.MinStrength {
+			continue
+		}
+		if len(class.Types) == 0 || containsType(class.Types, pattern.Type) {
+			return i
+		}
+	}
+	return len(ps.config.Classes)
+}
+
+// containsType 类型列表是否包含给定类型
+func containsType(list []string, patternType string) bool {
+	for _, t := range list {
+		if t == patternType {
+			return true
+		}
+	}
+	return false
+}
+
+// Schedule 选出本周期处理的模式, 按处理顺序返回; 其余模式推迟, 下次调用时应再次传入
+// 未启用时按原顺序返回全部模式
+func (ps *PriorityScheduler) Schedule(patterns []EmergentPattern) (selected, deferred []EmergentPattern) {
+	if !ps.Enabled() {
+		return patterns, nil
+	}
+
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+
+	type entry struct {
+		pattern  EmergentPattern
+		class    int
+		strength float64
+		wait     int
+		starving bool
+	}
+	entries := make([]entry, len(patterns))
+	for i, p := range patterns {
+		wait := ps.waits[p.ID]
+		entries[i] = entry{
+			pattern:  p,
+			class:    ps.classify(p),
+			strength: p.Effective(OverrideStrength),
+			wait:     wait,
+			starving: wait >= ps.config.MaxWait,
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if a.starving != b.starving {
+			return a.starving
+		}
+		if a.class != b.class {
+			return a.class < b.class
+		}
+		if a.strength != b.strength {
+			return a.strength > b.strength
+		}
+		return a.wait > b.wait
+	})
+
+	// 推迟周期只记录仍在等待的模式, 已消失的模式随之遗忘
+	waits := make(map[string]int)
+	counts := make([]int, len(ps.stats))
+	for i := range ps.stats {
+		ps.stats[i].Waiting = 0
+		ps.stats[i].MaxWait = 0
+	}
+
+	selected = make([]EmergentPattern, 0, len(entries))
+	for _, e := range entries {
+		stats := &ps.stats[e.class]
+		if e.starving || stats.Quota <= 0 || counts[e.class] < stats.Quota {
+			// 提前处理的模式同样占用配额, 使同类别的其他模式轮流处理
+			counts[e.class]++
+			stats.Processed++
+			if e.starving {
+				stats.Promoted++
+			}
+			selected = append(selected, e.pattern)
+			continue
+		}
+
+		waits[e.pattern.ID] = e.wait + 1
+		stats.Deferred++
+		stats.Waiting++
+		if e.wait+1 > stats.MaxWait {
+			stats.MaxWait = e.wait + 1
+		}
+		deferred = append(deferred, e.pattern)
+	}
+	ps.waits = waits
+
+	return selected, deferred
+}
+
+// Stats 各类别的处理统计, 按优先级从高到低, 最后一项为默认类别
+func (ps *PriorityScheduler) Stats() []PriorityStats {
+	if ps == nil {
+		return nil
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return append([]PriorityStats(nil), ps.stats...)
+}
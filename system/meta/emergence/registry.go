@@ -0,0 +1,242 @@
+// system/meta/emergence/registry.go
+
+package emergence
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// 内置模式类型
+const (
+	PatternElementCombination  = "element_combination"
+	PatternEnergyCluster       = "energy_cluster"
+	PatternEnergyFlow          = "energy_flow"
+	PatternQuantumEntanglement = "quantum_entanglement"
+	PatternQuantumCoherence    = "quantum_coherence"
+)
+
+// 组件类型
+const (
+	ComponentElement = "element"
+	ComponentEnergy  = "energy"
+	ComponentQuantum = "quantum"
+	ComponentField   = "field"
+)
+
+// DetectFunc 自定义模式检测函数
+type DetectFunc func(state *model.FieldState) []EmergentPattern
+
+// PatternTypeInfo 模式类型元数据
+type PatternTypeInfo struct {
+	Name        string     // 类型名, 对应EmergentPattern.Type
+	DisplayName string     // 显示名称
+	Description string     // 说明
+	Components  []string   // 预期的组件类型, 为空不限制
+	MinStrength float64    // 默认强度阈值, 低于该值的模式被忽略
+	Detect      DetectFunc // 检测函数, 内置类型由检测器实现时为nil
+	BuiltIn     bool       // 是否内置类型
+}
+
+// PatternTypeRegistry 模式类型注册表
+type PatternTypeRegistry struct {
+	mu sync.RWMutex
+
+	types map[string]*PatternTypeInfo
+	order []string // 注册顺序, 决定自定义检测的执行顺序
+}
+
+// NewPatternTypeRegistry 创建注册表, 预置内置模式类型
+func NewPatternTypeRegistry() *PatternTypeRegistry {
+	r := &PatternTypeRegistry{
+		types: make(map[string]*PatternTypeInfo),
+	}
+
+	builtins := []PatternTypeInfo{
+		{
+			Name:        PatternElementCombination,
+			DisplayName: "Element Combination",
+			Description: "two elements whose interaction exceeds the pattern threshold",
+			Components:  []string{ComponentElement},
+		},
+		{
+			Name:        PatternEnergyCluster,
+			DisplayName: "Energy Cluster",
+			Description: "spatial concentration of field energy",
+			Components:  []string{ComponentEnergy},
+		},
+		{
+			Name:        PatternEnergyFlow,
+			DisplayName: "Energy Flow",
+			Description: "directed energy transfer along a gradient",
+			Components:  []string{ComponentEnergy},
+		},
+		{
+			Name:        PatternQuantumEntanglement,
+			DisplayName: "Quantum Entanglement",
+			Description: "entangled quantum state participants",
+			Components:  []string{ComponentQuantum},
+		},
+		{
+			Name:        PatternQuantumCoherence,
+			DisplayName: "Quantum Coherence",
+			Description: "stable coherent quantum state",
+			Components:  []string{ComponentQuantum},
+		},
+	}
+	for _, info := range builtins {
+		info := info
+		info.BuiltIn = true
+		r.types[info.Name] = &info
+		r.order = append(r.order, info.Name)
+	}
+
+	return r
+}
+
+// Register 注册自定义模式类型
+func (r *PatternTypeRegistry) Register(info PatternTypeInfo) error {
+	if info.Name == "" {
+		return model.NewModelError(model.ErrCodeValidation, "empty pattern type name", nil)
+	}
+	if info.MinStrength < 0 || info.MinStrength > 1 {
+		return model.NewModelError(model.ErrCodeValidation,
+			fmt.Sprintf("invalid min strength for pattern type %s", info.Name), nil)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.types[info.Name]; exists {
+		return model.NewModelError(model.ErrCodeValidation,
+			fmt.Sprintf("pattern type %s already registered", info.Name), nil)
+	}
+
+	if info.DisplayName == "" {
+		info.DisplayName = info.Name
+	}
+	info.Components = append([]string{}, info.Components...)
+	info.BuiltIn = false
+
+	r.types[info.Name] = &info
+	r.order = append(r.order, info.Name)
+	return nil
+}
+
+// Unregister 注销自定义模式类型, 内置类型不可注销
+func (r *PatternTypeRegistry) Unregister(name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	info, exists := r.types[name]
+	if !exists {
+		return model.NewModelError(model.ErrCodeNotFound,
+			fmt.Sprintf("pattern type %s not registered", name), nil)
+	}
+	if info.BuiltIn {
+		return model.NewModelError(model.ErrCodeValidation,
+			fmt.Sprintf("built-in pattern type %s cannot be unregistered", name), nil)
+	}
+
+	delete(r.types, name)
+	for i, n := range r.order {
+		if n == name {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+// Get 获取模式类型元数据
+func (r *PatternTypeRegistry) Get(name string) (PatternTypeInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	info, exists := r.types[name]
+	if !exists {
+		return PatternTypeInfo{}, false
+	}
+	return *info, true
+}
+
+// Has 判断模式类型是否已注册
+func (r *PatternTypeRegistry) Has(name string) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, exists := r.types[name]
+	return exists
+}
+
+// List 按名称排序列出全部模式类型
+func (r *PatternTypeRegistry) List() []PatternTypeInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]PatternTypeInfo, 0, len(r.types))
+	for _, info := range r.types {
+		list = append(list, *info)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// DisplayName 获取模式类型的显示名称, 未注册时返回类型名
+func (r *PatternTypeRegistry) DisplayName(name string) string {
+	if info, ok := r.Get(name); ok {
+		return info.DisplayName
+	}
+	return name
+}
+
+// Validate 校验模式是否符合其类型定义
+func (r *PatternTypeRegistry) Validate(pattern EmergentPattern) error {
+	info, ok := r.Get(pattern.Type)
+	if !ok {
+		return model.NewModelError(model.ErrCodeValidation,
+			fmt.Sprintf("unknown pattern type %s", pattern.Type), nil)
+	}
+	if len(info.Components) == 0 {
+		return nil
+	}
+
+	expected := make(map[string]bool, len(info.Components))
+	for _, c := range info.Components {
+		expected[c] = true
+	}
+	for _, comp := range pattern.Components {
+		if !expected[comp.Type] {
+			return model.NewModelError(model.ErrCodeValidation,
+				fmt.Sprintf("unexpected component type %s for pattern type %s", comp.Type, pattern.Type), nil)
+		}
+	}
+	return nil
+}
+
+// accepts 判断模式是否达到其类型的强度阈值
+func (r *PatternTypeRegistry) accepts(pattern EmergentPattern) bool {
+	info, ok := r.Get(pattern.Type)
+	if !ok {
+		return true
+	}
+	return pattern.Strength >= info.MinStrength
+}
+
+// detectors 按注册顺序获取自定义检测函数
+func (r *PatternTypeRegistry) detectors() []PatternTypeInfo {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]PatternTypeInfo, 0)
+	for _, name := range r.order {
+		if info := r.types[name]; info.Detect != nil {
+			list = append(list, *info)
+		}
+	}
+	return list
+}
@@ -0,0 +1,316 @@
+// system/meta/emergence/retention.go
+
+package emergence
+
+import (
+	"math"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 分层保留默认参数
+const (
+	defaultRetentionRecent        = 10 * time.Minute
+	defaultRetentionRecentStates  = maxEvolutionHistory
+	defaultRetentionResolution    = time.Minute
+	defaultRetentionSummaryStates = 120
+)
+
+// 摘要状态属性
+const (
+	SummarySamples     = "samples"      // 摘要合并的状态数
+	SummaryMinStrength = "min_strength" // 摘要内最小强度
+	SummaryMaxStrength = "max_strength" // 摘要内最大强度
+	SummaryMinEnergy   = "min_energy"   // 摘要内最小能量
+	SummaryMaxEnergy   = "max_energy"   // 摘要内最大能量
+)
+
+// EvolutionAggregate 超出摘要预算的演化历史聚合
+type EvolutionAggregate struct {
+	Samples      int       // 聚合的原始状态数
+	Start        time.Time // 最早状态时间
+	End          time.Time // 最晚状态时间
+	MeanStrength float64
+	MinStrength  float64
+	MaxStrength  float64
+	MeanEnergy   float64
+	MinEnergy    float64
+	MaxEnergy    float64
+}
+
+// DetectionSummary 一段时间内检测事件的摘要
+type DetectionSummary struct {
+	Start          time.Time      // 区间起点
+	End            time.Time      // 区间内最晚事件时间
+	Count          int            // 事件数
+	Types          map[string]int // 各模式类型的事件数
+	MeanConfidence float64        // 平均置信度
+}
+
+// IsSummary 演化状态是否为降采样摘要
+func IsSummary(state PatternState) bool {
+	return state.Properties[SummarySamples] > 0
+}
+
+// stateSamples 演化状态代表的原始状态数
+func stateSamples(state PatternState) int {
+	if n := int(state.Properties[SummarySamples]); n > 0 {
+		return n
+	}
+	return 1
+}
+
+// normalizeRetention 以默认值补全保留配置
+func normalizeRetention(config types.RetentionConfig) types.RetentionConfig {
+	if config.Recent <= 0 {
+		config.Recent = defaultRetentionRecent
+	}
+	if config.RecentStates <= 0 {
+		config.RecentStates = defaultRetentionRecentStates
+	}
+	if config.Resolution <= 0 {
+		config.Resolution = defaultRetentionResolution
+	}
+	if config.SummaryStates <= 0 {
+		config.SummaryStates = defaultRetentionSummaryStates
+	}
+	return config
+}
+
+// retentionFor 模式类型的保留预算, 类型覆盖中的零值字段沿用基础配置
+func retentionFor(config types.RetentionConfig, patternType string) types.RetentionConfig {
+	override, ok := config.Types[patternType]
+	if !ok {
+		return normalizeRetention(config)
+	}
+	if override.Recent <= 0 {
+		override.Recent = config.Recent
+	}
+	if override.RecentStates <= 0 {
+		override.RecentStates = config.RecentStates
+	}
+	if override.Resolution <= 0 {
+		override.Resolution = config.Resolution
+	}
+	if override.SummaryStates <= 0 {
+		override.SummaryStates = config.SummaryStates
+	}
+	override.Types = nil
+	return normalizeRetention(override)
+}
+
+// compactEvolution 按分层预算整理模式的演化历史
+// 演化历史的前缀为按时间粒度合并的摘要, 其后为完整分辨率的最近状态;
+// 超出最近窗口或状态数上限的状态并入摘要, 超出摘要上限的最早摘要并入聚合
+func compactEvolution(pattern *EmergentPattern, config types.RetentionConfig, now time.Time) {
+	history := pattern.Evolution
+	split := 0
+	for split < len(history) && IsSummary(history[split]) {
+		split++
+	}
+	recent := history[split:]
+
+	cutoff := now.Add(-config.Recent)
+	demote := 0
+	for demote < len(recent) &&
+		(len(recent)-demote > config.RecentStates || stateTime(recent[demote]).Before(cutoff)) {
+		demote++
+	}
+	if demote == 0 && split <= config.SummaryStates {
+		return
+	}
+
+	summaries := append(make([]PatternState, 0, split+demote), history[:split]...)
+	for _, state := range recent[:demote] {
+		summaries = mergeSummary(summaries, state, config.Resolution)
+	}
+	if over := len(summaries) - config.SummaryStates; over > 0 {
+		for _, summary := range summaries[:over] {
+			pattern.History.add(summary)
+		}
+		summaries = summaries[over:]
+	}
+
+	compacted := make([]PatternState, 0, len(summaries)+len(recent)-demote)
+	compacted = append(compacted, summaries...)
+	pattern.Evolution = append(compacted, recent[demote:]...)
+}
+
+// mergeSummary 将状态并入所属时间粒度的摘要, 摘要按时间升序
+func mergeSummary(summaries []PatternState, state PatternState, resolution time.Duration) []PatternState {
+	at := stateTime(state)
+	bucket := at.Truncate(resolution)
+	samples := stateSamples(state)
+
+	if n := len(summaries); n > 0 && summaries[n-1].Timestamp.Equal(bucket) {
+		// 摘要可能被活跃模式的副本共享, 属性写时复制
+		summary := &summaries[n-1]
+		summary.Properties = cloneProperties(summary.Properties)
+		total := stateSamples(*summary)
+		weight := float64(samples) / float64(total+samples)
+		summary.Strength += (state.Strength - summary.Strength) * weight
+		summary.Energy += (state.Energy - summary.Energy) * weight
+		summary.Active = summary.Active || state.Active
+		if at.After(summary.LastUpdate) {
+			summary.LastUpdate = at
+		}
+		summary.Properties[SummarySamples] = float64(total + samples)
+		summary.Properties[SummaryMinStrength] = math.Min(summary.Properties[SummaryMinStrength], summaryBound(state, SummaryMinStrength, state.Strength))
+		summary.Properties[SummaryMaxStrength] = math.Max(summary.Properties[SummaryMaxStrength], summaryBound(state, SummaryMaxStrength, state.Strength))
+		summary.Properties[SummaryMinEnergy] = math.Min(summary.Properties[SummaryMinEnergy], summaryBound(state, SummaryMinEnergy, state.Energy))
+		summary.Properties[SummaryMaxEnergy] = math.Max(summary.Properties[SummaryMaxEnergy], summaryBound(state, SummaryMaxEnergy, state.Energy))
+		return summaries
+	}
+
+	return append(summaries, PatternState{
+		Active:     state.Active,
+		Duration:   resolution,
+		Strength:   state.Strength,
+		Energy:     state.Energy,
+		LastUpdate: at,
+		Timestamp:  bucket,
+		Properties: map[string]float64{
+			SummarySamples:     float64(samples),
+			SummaryMinStrength: summaryBound(state, SummaryMinStrength, state.Strength),
+			SummaryMaxStrength: summaryBound(state, SummaryMaxStrength, state.Strength),
+			SummaryMinEnergy:   summaryBound(state, SummaryMinEnergy, state.Energy),
+			SummaryMaxEnergy:   summaryBound(state, SummaryMaxEnergy, state.Energy),
+		},
+	})
+}
+
+// cloneProperties 属性表的副本
+func cloneProperties(properties map[string]float64) map[string]float64 {
+	clone := make(map[string]float64, len(properties))
+	for k, v := range properties {
+		clone[k] = v
+	}
+	return clone
+}
+
+// summaryBound 摘要状态的极值属性, 原始状态取其自身的值
+func summaryBound(state PatternState, key string, value float64) float64 {
+	if IsSummary(state) {
+		return state.Properties[key]
+	}
+	return value
+}
+
+// add 将状态或摘要并入聚合
+func (a *EvolutionAggregate) add(state PatternState) {
+	samples := stateSamples(state)
+	start, end := stateTime(state), state.LastUpdate
+	if end.Before(start) {
+		end = start
+	}
+	minStrength := summaryBound(state, SummaryMinStrength, state.Strength)
+	maxStrength := summaryBound(state, SummaryMaxStrength, state.Strength)
+	minEnergy := summaryBound(state, SummaryMinEnergy, state.Energy)
+	maxEnergy := summaryBound(state, SummaryMaxEnergy, state.Energy)
+
+	if a.Samples == 0 {
+		*a = EvolutionAggregate{
+			Samples:      samples,
+			Start:        start,
+			End:          end,
+			MeanStrength: state.Strength,
+			MinStrength:  minStrength,
+			MaxStrength:  maxStrength,
+			MeanEnergy:   state.Energy,
+			MinEnergy:    minEnergy,
+			MaxEnergy:    maxEnergy,
+		}
+		return
+	}
+
+	weight := float64(samples) / float64(a.Samples+samples)
+	a.MeanStrength += (state.Strength - a.MeanStrength) * weight
+	a.MeanEnergy += (state.Energy - a.MeanEnergy) * weight
+	a.MinStrength = math.Min(a.MinStrength, minStrength)
+	a.MaxStrength = math.Max(a.MaxStrength, maxStrength)
+	a.MinEnergy = math.Min(a.MinEnergy, minEnergy)
+	a.MaxEnergy = math.Max(a.MaxEnergy, maxEnergy)
+	if start.Before(a.Start) {
+		a.Start = start
+	}
+	if end.After(a.End) {
+		a.End = end
+	}
+	a.Samples += samples
+}
+
+// compactDetectionHistory 按分层预算整理检测历史
+// 超出最近窗口或事件数上限的事件按时间粒度合并为摘要, 超出摘要上限的最早摘要并入聚合
+func (pd *PatternDetector) compactDetectionHistory(now time.Time) {
+	config := normalizeRetention(pd.config.retention)
+	history := pd.state.history
+
+	cutoff := now.Add(-config.Recent)
+	demote := 0
+	for demote < len(history) &&
+		(len(history)-demote > maxHistoryLength || history[demote].Timestamp.Before(cutoff)) {
+		demote++
+	}
+	if demote == 0 {
+		return
+	}
+
+	for _, event := range history[:demote] {
+		bucket := event.Timestamp.Truncate(config.Resolution)
+		n := len(pd.state.summaries)
+		if n == 0 || !pd.state.summaries[n-1].Start.Equal(bucket) {
+			pd.state.summaries = append(pd.state.summaries, DetectionSummary{
+				Start: bucket,
+				Types: make(map[string]int),
+			})
+			n++
+		}
+		pd.state.summaries[n-1].add(DetectionSummary{
+			Start:          event.Timestamp,
+			End:            event.Timestamp,
+			Count:          1,
+			Types:          map[string]int{event.Type: 1},
+			MeanConfidence: event.Confidence,
+		})
+	}
+	if over := len(pd.state.summaries) - config.SummaryStates; over > 0 {
+		for _, summary := range pd.state.summaries[:over] {
+			pd.state.aggregate.add(summary)
+		}
+		pd.state.summaries = append([]DetectionSummary(nil), pd.state.summaries[over:]...)
+	}
+	pd.state.history = append([]DetectionEvent(nil), history[demote:]...)
+}
+
+// add 将另一摘要并入当前摘要
+func (s *DetectionSummary) add(other DetectionSummary) {
+	if other.Count == 0 {
+		return
+	}
+	if s.Start.IsZero() || other.Start.Before(s.Start) {
+		s.Start = other.Start
+	}
+	if other.End.After(s.End) {
+		s.End = other.End
+	}
+	if s.Types == nil {
+		s.Types = make(map[string]int)
+	}
+	for t, c := range other.Types {
+		s.Types[t] += c
+	}
+	total := s.Count + other.Count
+	s.MeanConfidence += (other.MeanConfidence - s.MeanConfidence) * float64(other.Count) / float64(total)
+	s.Count = total
+}
+
+// clone 摘要的深拷贝
+func (s DetectionSummary) clone() DetectionSummary {
+	types := make(map[string]int, len(s.Types))
+	for t, c := range s.Types {
+		types[t] = c
+	}
+	s.Types = types
+	return s
+}
@@ -262,8 +262,8 @@ func (re *RuleEngine) evaluatePatternCondition(cond RuleCondition) bool {
 	patternName := cond.Target
 	expectedValue := cond.Value.(float64)
 
-	// 在当前模式中查找
-	for _, pattern := range re.detector.state.activePatterns {
+	// 在当前模式的快照中查找, 不与检测并发访问活跃模式
+	for _, pattern := range re.detector.GetActivePatternsSnapshot().Patterns() {
 		if pattern.Type == patternName {
 			// 根据比较操作符评估
 			switch cond.Operator {
@@ -0,0 +1,119 @@
+// system/meta/emergence/snapshot.go
+
+package emergence
+
+import (
+	"sort"
+	"time"
+)
+
+// PatternSnapshot 活跃模式集合的不可变快照
+// 快照在创建后不再改变, 可在不持有检测器锁的情况下并发遍历; 同一版本的活跃模式共享同一快照.
+// 快照中的模式与检测器持有的模式不共享可变部分, 但调用方不应修改取得的模式
+type PatternSnapshot struct {
+	version   uint64
+	namespace string
+	taken     time.Time
+	ids       []string // 按ID升序
+	patterns  map[string]EmergentPattern
+}
+
+// newPatternSnapshot 复制活跃模式构造快照, 调用方需持有检测器的读锁
+func newPatternSnapshot(active map[string]*EmergentPattern, version uint64, namespace string) *PatternSnapshot {
+	snapshot := &PatternSnapshot{
+		version:   version,
+		namespace: namespace,
+		taken:     time.Now(),
+		ids:       make([]string, 0, len(active)),
+		patterns:  make(map[string]EmergentPattern, len(active)),
+	}
+	for id, pattern := range active {
+		snapshot.ids = append(snapshot.ids, id)
+		snapshot.patterns[id] = freezePattern(pattern)
+	}
+	sort.Strings(snapshot.ids)
+	return snapshot
+}
+
+// freezePattern 复制模式中检测时会原地修改的部分
+// 演化历史只会追加或整体替换, 截断容量后即可共享
+func freezePattern(pattern *EmergentPattern) EmergentPattern {
+	frozen := *pattern
+	frozen.Components = append([]PatternComponent(nil), pattern.Components...)
+	frozen.Properties = cloneProperties(pattern.Properties)
+	frozen.Evolution = pattern.Evolution[:len(pattern.Evolution):len(pattern.Evolution)]
+	return frozen
+}
+
+// Version 快照对应的活跃模式版本, 每次检测或恢复后递增
+func (s *PatternSnapshot) Version() uint64 {
+	return s.version
+}
+
+// Namespace 快照所属命名空间
+func (s *PatternSnapshot) Namespace() string {
+	return s.namespace
+}
+
+// Taken 快照创建时间
+func (s *PatternSnapshot) Taken() time.Time {
+	return s.taken
+}
+
+// Len 快照中的模式数
+func (s *PatternSnapshot) Len() int {
+	return len(s.ids)
+}
+
+// IDs 快照中的模式ID, 按升序
+func (s *PatternSnapshot) IDs() []string {
+	return append([]string(nil), s.ids...)
+}
+
+// Get 按ID获取模式
+func (s *PatternSnapshot) Get(id string) (EmergentPattern, bool) {
+	pattern, ok := s.patterns[id]
+	return pattern, ok
+}
+
+// Range 按ID升序遍历模式, fn返回false时停止
+func (s *PatternSnapshot) Range(fn func(pattern EmergentPattern) bool) {
+	for _, id := range s.ids {
+		if !fn(s.patterns[id]) {
+			return
+		}
+	}
+}
+
+// Patterns 快照中的模式列表, 按ID升序
+func (s *PatternSnapshot) Patterns() []EmergentPattern {
+	patterns := make([]EmergentPattern, 0, len(s.ids))
+	for _, id := range s.ids {
+		patterns = append(patterns, s.patterns[id])
+	}
+	return patterns
+}
+
+// GetActivePatternsSnapshot 获取活跃模式的不可变快照
+// 活跃模式自上次快照后未变化时复用已有快照, 否则在读锁下复制一次
+func (pd *PatternDetector) GetActivePatternsSnapshot() *PatternSnapshot {
+	pd.mu.RLock()
+	version := pd.state.version
+	pd.snapshot.mu.Lock()
+	cached := pd.snapshot.current
+	pd.snapshot.mu.Unlock()
+	if cached != nil && cached.version == version {
+		pd.mu.RUnlock()
+		return cached
+	}
+	snapshot := newPatternSnapshot(pd.state.activePatterns, version, pd.config.namespace)
+	pd.mu.RUnlock()
+
+	// 并发创建时保留版本较新的快照
+	pd.snapshot.mu.Lock()
+	defer pd.snapshot.mu.Unlock()
+	if pd.snapshot.current == nil || pd.snapshot.current.version < snapshot.version {
+		pd.snapshot.current = snapshot
+	}
+	return snapshot
+}
@@ -0,0 +1,139 @@
+// system/meta/emergence/stability.go
+
+package emergence
+
+import (
+	"math"
+	"time"
+)
+
+// StabilityMode 模式稳定性计算方式
+type StabilityMode string
+
+const (
+	StabilityHeuristic StabilityMode = "heuristic" // 按组件状态加权估计(默认)
+	StabilityLyapunov  StabilityMode = "lyapunov"  // 按演化历史的有限时间李雅普诺夫指数估计
+)
+
+// 李雅普诺夫估计参数
+const (
+	minLyapunovSamples = 5     // 估计所需的最少演化状态数
+	lyapunovHorizon    = 8     // 跟踪最近邻轨迹的最大步数
+	lyapunovSeparation = 1     // 最近邻与参考状态的最小时间间隔(步)
+	chaoticExponent    = 0.05  // 指数超过该值视为混沌
+	fixedPointDistance = 1e-9  // 平均状态差低于该值视为不动点
+	divergenceEpsilon  = 1e-12 // 防止对零取对数
+)
+
+// StabilityEstimate 基于演化历史的稳定性估计
+type StabilityEstimate struct {
+	Exponent  float64 // 有限时间李雅普诺夫指数(每秒), 正值表示相邻状态差按指数发散
+	Samples   int     // 参与估计的状态数
+	Chaotic   bool    // 是否呈混沌(持续发散)
+	Stability float64 // 映射到[0,1]的稳定性, 指数为0时为0.5
+}
+
+// EstimateLyapunov 从模式的演化历史估计有限时间李雅普诺夫指数
+// 状态向量取(强度, 能量). 对每个状态找到时间上不相邻的最近状态, 跟踪两条轨迹
+// 之后若干步的状态差, 以平均对数状态差随步数增长的斜率作为指数(Rosenstein法);
+// 相近状态的轨迹收敛时指数为负, 发散时为正. 演化状态不足时返回false
+func EstimateLyapunov(pattern *EmergentPattern) (StabilityEstimate, bool) {
+	history := pattern.Evolution
+	n := len(history)
+	if n < minLyapunovSamples {
+		return StabilityEstimate{}, false
+	}
+
+	points := make([][2]float64, n)
+	for i, state := range history {
+		points[i] = [2]float64{state.Strength, state.Energy}
+	}
+	estimate := StabilityEstimate{Samples: n}
+
+	// 不动点: 状态不再变化
+	moved, elapsed := 0.0, 0.0
+	for i := 1; i < n; i++ {
+		moved += stateDistance(points[i-1], points[i])
+		elapsed += stateInterval(history[i-1], history[i])
+	}
+	if moved/float64(n-1) < fixedPointDistance {
+		estimate.Exponent = math.Inf(-1)
+		estimate.Stability = 1
+		return estimate, true
+	}
+
+	// 最近邻轨迹的平均对数状态差
+	horizon := max(1, min(lyapunovHorizon, (n-1)/3))
+	sums := make([]float64, horizon+1)
+	counts := make([]float64, horizon+1)
+	last := n - 1 - horizon
+	for i := 0; i <= last; i++ {
+		nearest, best := -1, math.Inf(1)
+		for j := 0; j <= last; j++ {
+			if abs(i-j) <= lyapunovSeparation {
+				continue
+			}
+			if d := stateDistance(points[i], points[j]); d > fixedPointDistance && d < best {
+				nearest, best = j, d
+			}
+		}
+		if nearest < 0 {
+			continue
+		}
+		for k := 0; k <= horizon; k++ {
+			d := stateDistance(points[i+k], points[nearest+k])
+			sums[k] += math.Log(d + divergenceEpsilon)
+			counts[k]++
+		}
+	}
+	if counts[0] == 0 {
+		return StabilityEstimate{}, false
+	}
+
+	// 最小二乘斜率, 按平均采样间隔换算为每秒
+	var sk, sy, skk, sky float64
+	for k := 0; k <= horizon; k++ {
+		y := sums[k] / counts[k]
+		fk := float64(k)
+		sk += fk
+		sy += y
+		skk += fk * fk
+		sky += fk * y
+	}
+	m := float64(horizon + 1)
+	slope := (m*sky - sk*sy) / (m*skk - sk*sk)
+	estimate.Exponent = slope / (elapsed / float64(n-1))
+	estimate.Chaotic = estimate.Exponent > chaoticExponent
+	estimate.Stability = 1 / (1 + math.Exp(estimate.Exponent))
+	return estimate, true
+}
+
+// stateDistance 两个状态向量的欧氏距离
+func stateDistance(a, b [2]float64) float64 {
+	return math.Hypot(a[0]-b[0], a[1]-b[1])
+}
+
+// abs 整数绝对值
+func abs(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
+
+// stateInterval 两个演化状态之间的时间间隔(秒), 时间戳缺失时按单位步长计
+func stateInterval(prev, next PatternState) float64 {
+	pt, nt := stateTime(prev), stateTime(next)
+	if pt.IsZero() || nt.IsZero() || !nt.After(pt) {
+		return 1
+	}
+	return nt.Sub(pt).Seconds()
+}
+
+// stateTime 演化状态的时间戳
+func stateTime(state PatternState) time.Time {
+	if !state.Timestamp.IsZero() {
+		return state.Timestamp
+	}
+	return state.LastUpdate
+}
@@ -0,0 +1,428 @@
+// system/meta/emergence/tuner.go
+
+package emergence
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// TuningStrategy 阈值搜索方式
+type TuningStrategy string
+
+const (
+	TuneGrid   TuningStrategy = "grid"   // 网格扫描
+	TuneRandom TuningStrategy = "random" // 在取值范围内随机采样
+)
+
+// TuningParams 检测阈值组合
+type TuningParams struct {
+	Sensitivity      float64 `json:"sensitivity"`
+	MinConfidence    float64 `json:"min_confidence"`
+	PatternThreshold float64 `json:"pattern_threshold"`
+}
+
+// Apply 将阈值组合应用到检测器
+func (p TuningParams) Apply(pd *PatternDetector) {
+	pd.Configure(p.Sensitivity, p.MinConfidence, 0)
+	pd.SetPatternThreshold(p.PatternThreshold)
+}
+
+// ApplyNamespace 将阈值组合写入命名空间检测配置
+func (p TuningParams) ApplyNamespace(cfg *types.NamespaceConfig) {
+	cfg.Detection.Sensitivity = p.Sensitivity
+	cfg.Detection.MinConfidence = p.MinConfidence
+	cfg.Detection.PatternThreshold = p.PatternThreshold
+}
+
+// TuningRange 参数取值范围
+// Values非空时直接使用; 否则网格扫描取[Min, Max]内Steps个等距值, 随机搜索在[Min, Max]内均匀采样. 零值保持模板检测器的当前值
+type TuningRange struct {
+	Min    float64   `json:"min"`
+	Max    float64   `json:"max"`
+	Steps  int       `json:"steps"`
+	Values []float64 `json:"values,omitempty"`
+}
+
+// TuningLabel 标注的模式出现区间, 区间内形成的同类型模式视为正确检测
+type TuningLabel struct {
+	Type string    `json:"type"` // 模式类型, 为空时匹配任意类型
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"` // 零值表示不设上限
+}
+
+// TuningOptions 阈值调优选项
+type TuningOptions struct {
+	Sensitivity      TuningRange
+	MinConfidence    TuningRange
+	PatternThreshold TuningRange
+
+	Strategy TuningStrategy // 默认网格扫描
+	Budget   int            // 试验数上限; 随机搜索必须设置, 网格超出时随机抽取
+	Seed     int64          // 随机种子
+
+	Labels    []TuningLabel // 标注数据
+	Tolerance time.Duration // 标注区间两端的容差
+	Beta      float64       // F-beta评分的beta, 非正值为1
+
+	// 自定义目标, 越大越好; 设置时替代F-beta评分, 可按回放检测到的模式计算下游指标
+	Objective func(trial TuningTrial, detected []EmergentPattern) float64
+}
+
+// TuningTrial 一次试验的结果
+type TuningTrial struct {
+	Params        TuningParams `json:"params"`
+	Detected      int          `json:"detected"`       // 检测到的新模式数
+	TruePositives int          `json:"true_positives"` // 落入标注区间的新模式数
+	LabelsHit     int          `json:"labels_hit"`     // 被检测到的标注数
+	Precision     float64      `json:"precision"`
+	Recall        float64      `json:"recall"`
+	FScore        float64      `json:"f_score"`
+	Score         float64      `json:"score"`    // 目标值
+	Frontier      bool         `json:"frontier"` // 是否位于精确率-召回率前沿
+}
+
+// TuningReport 阈值调优报告
+type TuningReport struct {
+	Strategy    TuningStrategy `json:"strategy"`
+	Snapshots   int            `json:"snapshots"`
+	Labels      int            `json:"labels"`
+	Recommended TuningParams   `json:"recommended"`
+	Best        TuningTrial    `json:"best"`
+	Trials      []TuningTrial  `json:"trials"`   // 按目标值降序
+	Frontier    []TuningTrial  `json:"frontier"` // 精确率-召回率前沿, 按召回率升序
+	Elapsed     time.Duration  `json:"elapsed"`
+}
+
+// WriteJSON 以JSON写出报告
+func (r *TuningReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return types.NewSystemError(types.ErrIO, "failed to write tuning report", err)
+	}
+	return nil
+}
+
+// WriteCSV 以CSV写出各试验的权衡曲线
+func (r *TuningReport) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	rows := [][]string{{"sensitivity", "min_confidence", "pattern_threshold",
+		"detected", "true_positives", "labels_hit", "precision", "recall", "f_score", "score", "frontier"}}
+	for _, t := range r.Trials {
+		rows = append(rows, []string{
+			formatTuningFloat(t.Params.Sensitivity),
+			formatTuningFloat(t.Params.MinConfidence),
+			formatTuningFloat(t.Params.PatternThreshold),
+			strconv.Itoa(t.Detected),
+			strconv.Itoa(t.TruePositives),
+			strconv.Itoa(t.LabelsHit),
+			formatTuningFloat(t.Precision),
+			formatTuningFloat(t.Recall),
+			formatTuningFloat(t.FScore),
+			formatTuningFloat(t.Score),
+			strconv.FormatBool(t.Frontier),
+		})
+	}
+	if err := cw.WriteAll(rows); err != nil {
+		return types.NewSystemError(types.ErrIO, "failed to write tuning curve", err)
+	}
+	return nil
+}
+
+// CollectSnapshots 读取快照来源中的全部快照, 供多次回放
+func CollectSnapshots(source SnapshotSource) ([]*model.FieldState, error) {
+	states := make([]*model.FieldState, 0)
+	for {
+		state, err := source.Next()
+		if errors.Is(err, io.EOF) {
+			return states, nil
+		}
+		if err != nil {
+			return states, err
+		}
+		states = append(states, state)
+	}
+}
+
+// TuneThresholds 以不同阈值组合回放快照并评分, 推荐得分最高的组合
+// 每次试验使用复制模板检测器配置、类型注册表和插件的独立检测器, 不影响模板; 快照须按时间顺序排列
+func TuneThresholds(ctx context.Context, template *PatternDetector, snapshots []*model.FieldState, opts TuningOptions) (*TuningReport, error) {
+	if template == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil template detector", nil)
+	}
+	if len(opts.Labels) == 0 && opts.Objective == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "tuning requires labels or an objective", nil)
+	}
+	if opts.Strategy == "" {
+		opts.Strategy = TuneGrid
+	}
+	if opts.Beta <= 0 {
+		opts.Beta = 1
+	}
+
+	candidates, err := tuningCandidates(template, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	started := time.Now()
+	report := &TuningReport{
+		Strategy:  opts.Strategy,
+		Snapshots: len(snapshots),
+		Labels:    len(opts.Labels),
+		Trials:    make([]TuningTrial, 0, len(candidates)),
+	}
+	for _, params := range candidates {
+		trial, err := runTuningTrial(ctx, template, snapshots, params, opts)
+		if err != nil {
+			return nil, err
+		}
+		report.Trials = append(report.Trials, trial)
+	}
+
+	markTuningFrontier(report)
+	sort.SliceStable(report.Trials, func(i, j int) bool {
+		return report.Trials[i].Score > report.Trials[j].Score
+	})
+	report.Best = report.Trials[0]
+	report.Recommended = report.Best.Params
+	report.Elapsed = time.Since(started)
+	return report, nil
+}
+
+// runTuningTrial 以一组阈值回放快照并评分
+func runTuningTrial(ctx context.Context, template *PatternDetector, snapshots []*model.FieldState, params TuningParams, opts TuningOptions) (TuningTrial, error) {
+	detected := make([]EmergentPattern, 0)
+	err := Replay(ctx, template, snapshots, params, func(step ReplayStep) {
+		detected = append(detected, step.Detected...)
+	})
+	if err != nil {
+		return TuningTrial{}, err
+	}
+
+	trial := TuningTrial{Params: params, Detected: len(detected)}
+	if len(opts.Labels) > 0 {
+		hit := make([]bool, len(opts.Labels))
+		for _, p := range detected {
+			matched := false
+			for i, label := range opts.Labels {
+				if label.matches(p, opts.Tolerance) {
+					hit[i] = true
+					matched = true
+				}
+			}
+			if matched {
+				trial.TruePositives++
+			}
+		}
+		for _, h := range hit {
+			if h {
+				trial.LabelsHit++
+			}
+		}
+		if trial.Detected > 0 {
+			trial.Precision = float64(trial.TruePositives) / float64(trial.Detected)
+		}
+		trial.Recall = float64(trial.LabelsHit) / float64(len(opts.Labels))
+		if b2 := opts.Beta * opts.Beta; trial.Precision+trial.Recall > 0 {
+			trial.FScore = (1 + b2) * trial.Precision * trial.Recall / (b2*trial.Precision + trial.Recall)
+		}
+	}
+
+	trial.Score = trial.FScore
+	if opts.Objective != nil {
+		trial.Score = opts.Objective(trial, detected)
+	}
+	return trial, nil
+}
+
+// matches 模式是否在标注区间内形成
+func (l TuningLabel) matches(p EmergentPattern, tolerance time.Duration) bool {
+	if l.Type != "" && l.Type != p.Type {
+		return false
+	}
+	if !l.From.IsZero() && p.Formation.Before(l.From.Add(-tolerance)) {
+		return false
+	}
+	return l.To.IsZero() || !p.Formation.After(l.To.Add(tolerance))
+}
+
+// tuningCandidates 按搜索方式生成阈值组合
+func tuningCandidates(template *PatternDetector, opts TuningOptions) ([]TuningParams, error) {
+	sensitivity, minConfidence, patternThreshold := template.GetThresholds()
+	current := TuningParams{Sensitivity: sensitivity, MinConfidence: minConfidence, PatternThreshold: patternThreshold}
+	rng := rand.New(rand.NewSource(opts.Seed))
+
+	ranges := []struct {
+		name    string
+		r       TuningRange
+		current float64
+	}{
+		{"sensitivity", opts.Sensitivity, current.Sensitivity},
+		{"min_confidence", opts.MinConfidence, current.MinConfidence},
+		{"pattern_threshold", opts.PatternThreshold, current.PatternThreshold},
+	}
+	for _, item := range ranges {
+		if err := item.r.validate(); err != nil {
+			return nil, err.WithContext("parameter", item.name)
+		}
+	}
+
+	switch opts.Strategy {
+	case TuneGrid:
+		axes := make([][]float64, len(ranges))
+		for i, item := range ranges {
+			axes[i] = item.r.grid(item.current)
+		}
+		candidates := make([]TuningParams, 0)
+		for _, s := range axes[0] {
+			for _, c := range axes[1] {
+				for _, t := range axes[2] {
+					candidates = append(candidates, TuningParams{Sensitivity: s, MinConfidence: c, PatternThreshold: t})
+				}
+			}
+		}
+		if opts.Budget > 0 && len(candidates) > opts.Budget {
+			rng.Shuffle(len(candidates), func(i, j int) {
+				candidates[i], candidates[j] = candidates[j], candidates[i]
+			})
+			candidates = candidates[:opts.Budget]
+		}
+		return candidates, nil
+
+	case TuneRandom:
+		if opts.Budget <= 0 {
+			return nil, types.NewSystemError(types.ErrInvalid, "random search requires a trial budget", nil)
+		}
+		candidates := make([]TuningParams, opts.Budget)
+		for i := range candidates {
+			candidates[i] = TuningParams{
+				Sensitivity:      ranges[0].r.sample(rng, ranges[0].current),
+				MinConfidence:    ranges[1].r.sample(rng, ranges[1].current),
+				PatternThreshold: ranges[2].r.sample(rng, ranges[2].current),
+			}
+		}
+		return candidates, nil
+
+	default:
+		return nil, types.NewSystemError(types.ErrInvalid, "unknown tuning strategy", nil).
+			WithContext("strategy", opts.Strategy)
+	}
+}
+
+// validate 检查取值范围, 阈值须为正
+func (r TuningRange) validate() *types.SystemError {
+	for _, v := range r.Values {
+		if !(v > 0) || math.IsInf(v, 0) {
+			return types.NewSystemError(types.ErrInvalid, "tuning value must be positive", nil).
+				WithContext("value", v)
+		}
+	}
+	if len(r.Values) == 0 && !r.isZero() {
+		if !(r.Min > 0) || r.Max < r.Min || math.IsInf(r.Max, 0) {
+			return types.NewSystemError(types.ErrInvalid, "invalid tuning range", nil).
+				WithContext("min", r.Min).
+				WithContext("max", r.Max)
+		}
+	}
+	return nil
+}
+
+// isZero 是否未设置范围
+func (r TuningRange) isZero() bool {
+	return len(r.Values) == 0 && r.Min == 0 && r.Max == 0
+}
+
+// grid 网格取值, 未设置时取当前值
+func (r TuningRange) grid(current float64) []float64 {
+	switch {
+	case len(r.Values) > 0:
+		return r.Values
+	case r.isZero():
+		return []float64{current}
+	case r.Steps <= 1 || r.Max == r.Min:
+		return []float64{r.Min}
+	}
+	values := make([]float64, r.Steps)
+	for i := range values {
+		values[i] = r.Min + (r.Max-r.Min)*float64(i)/float64(r.Steps-1)
+	}
+	values[r.Steps-1] = r.Max
+	return values
+}
+
+// sample 随机取值, 未设置时取当前值
+func (r TuningRange) sample(rng *rand.Rand, current float64) float64 {
+	switch {
+	case len(r.Values) > 0:
+		return r.Values[rng.Intn(len(r.Values))]
+	case r.isZero():
+		return current
+	}
+	return r.Min + rng.Float64()*(r.Max-r.Min)
+}
+
+// markTuningFrontier 标记精确率-召回率前沿: 不存在精确率和召回率都不低且至少一项更高的其他试验
+func markTuningFrontier(report *TuningReport) {
+	if report.Labels == 0 {
+		return
+	}
+	for i := range report.Trials {
+		a := &report.Trials[i]
+		a.Frontier = true
+		for j, b := range report.Trials {
+			if i != j && b.Precision >= a.Precision && b.Recall >= a.Recall &&
+				(b.Precision > a.Precision || b.Recall > a.Recall) {
+				a.Frontier = false
+				break
+			}
+		}
+		if a.Frontier {
+			report.Frontier = append(report.Frontier, *a)
+		}
+	}
+	sort.SliceStable(report.Frontier, func(i, j int) bool {
+		if report.Frontier[i].Recall != report.Frontier[j].Recall {
+			return report.Frontier[i].Recall < report.Frontier[j].Recall
+		}
+		return report.Frontier[i].Precision > report.Frontier[j].Precision
+	})
+}
+
+// replica 复制检测器配置、类型注册表和插件(不含插件统计), 状态为空, 不接入场、监听器和快照日志
+func (pd *PatternDetector) replica() *PatternDetector {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+
+	r := NewPatternDetector(nil)
+	r.config = pd.config
+	r.registry = pd.registry
+
+	pd.plugins.mu.RLock()
+	for _, plugin := range pd.plugins.list {
+		r.plugins.list = append(r.plugins.list, &detectorPlugin{
+			name:     plugin.name,
+			detector: plugin.detector,
+			timeout:  plugin.timeout,
+		})
+	}
+	pd.plugins.mu.RUnlock()
+	return r
+}
+
+// formatTuningFloat 格式化CSV数值
+func formatTuningFloat(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
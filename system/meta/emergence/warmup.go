@@ -0,0 +1,26 @@
+// system/meta/emergence/warmup.go
+
+package emergence
+
+import "github.com/Corphon/daoflow/system/types"
+
+// SetWarmup 设置启动预热, 每次检测计为一个预热样本; nil表示不预热
+func (pd *PatternDetector) SetWarmup(warmup *types.Warmup) {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+	pd.warmup = warmup
+}
+
+// GetWarmup 获取启动预热, 未设置时返回nil
+func (pd *PatternDetector) GetWarmup() *types.Warmup {
+	pd.mu.RLock()
+	defer pd.mu.RUnlock()
+	return pd.warmup
+}
+
+// clearProvisional 预热结束后清除活跃模式的临时标记, 调用方需持有锁
+func (pd *PatternDetector) clearProvisional() {
+	for _, pattern := range pd.state.activePatterns {
+		pattern.Provisional = false
+	}
+}
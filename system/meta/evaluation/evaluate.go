@@ -0,0 +1,295 @@
+// system/meta/evaluation/evaluate.go
+
+package evaluation
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Candidate 待评估的检测器配置
+type Candidate struct {
+	Name   string                 `json:"name"`
+	Params emergence.TuningParams `json:"params"` // 非正值保持模板检测器的配置
+}
+
+// Metrics 检测质量指标
+type Metrics struct {
+	Detected       int     `json:"detected"`        // 检测到的新模式数
+	TruePositives  int     `json:"true_positives"`  // 与标注匹配的新模式数
+	FalsePositives int     `json:"false_positives"` // 未与任何标注匹配的新模式数
+	Hits           int     `json:"hits"`            // 被检测到的标注数
+	Misses         int     `json:"misses"`          // 未被检测到的标注数
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+
+	MeanTimeToDetection time.Duration `json:"mean_time_to_detection"` // 命中标注的平均检测延迟
+	MaxTimeToDetection  time.Duration `json:"max_time_to_detection"`
+
+	StabilityRMSE     float64 `json:"stability_rmse"`     // 跟踪模式稳定性与期望值的均方根误差
+	StabilityCoverage float64 `json:"stability_coverage"` // 标注区间内有模式被跟踪的快照比例
+	StabilitySamples  int     `json:"stability_samples"`
+}
+
+// AnnotationResult 单个标注的评估结果
+type AnnotationResult struct {
+	Annotation
+	Detected        bool          `json:"detected"`
+	FirstDetection  time.Time     `json:"first_detection,omitempty"`
+	TimeToDetection time.Duration `json:"time_to_detection"` // 首次检测相对标注开始的延迟, 提前检测计为0
+	StabilityRMSE   float64       `json:"stability_rmse"`
+	TrackedSteps    int           `json:"tracked_steps"` // 有模式被跟踪的快照数
+	WindowSteps     int           `json:"window_steps"`  // 标注区间内的快照数
+}
+
+// Result 一个场景在一个配置下的评估结果
+type Result struct {
+	Scenario    string                 `json:"scenario"`
+	Candidate   string                 `json:"candidate"`
+	Params      emergence.TuningParams `json:"params"`
+	Metrics     Metrics                `json:"metrics"`
+	Annotations []AnnotationResult     `json:"annotations"`
+	Elapsed     time.Duration          `json:"elapsed"`
+}
+
+// Report 评估报告
+type Report struct {
+	Results []Result `json:"results"` // 按场景、配置顺序
+}
+
+// Find 查找场景和配置的评估结果
+func (r *Report) Find(scenario, candidate string) (*Result, bool) {
+	for i := range r.Results {
+		if r.Results[i].Scenario == scenario && r.Results[i].Candidate == candidate {
+			return &r.Results[i], true
+		}
+	}
+	return nil, false
+}
+
+// Comparison 两个配置在同一场景下的指标差(candidate - baseline)
+type Comparison struct {
+	Scenario        string        `json:"scenario"`
+	Baseline        string        `json:"baseline"`
+	Candidate       string        `json:"candidate"`
+	Precision       float64       `json:"precision"`
+	Recall          float64       `json:"recall"`
+	F1              float64       `json:"f1"`
+	TimeToDetection time.Duration `json:"time_to_detection"`
+	StabilityRMSE   float64       `json:"stability_rmse"`
+}
+
+// Compare 按场景比较两个配置, 跳过缺少任一结果的场景
+func (r *Report) Compare(baseline, candidate string) []Comparison {
+	comparisons := make([]Comparison, 0)
+	seen := make(map[string]bool)
+	for _, base := range r.Results {
+		if base.Candidate != baseline || seen[base.Scenario] {
+			continue
+		}
+		seen[base.Scenario] = true
+		other, ok := r.Find(base.Scenario, candidate)
+		if !ok {
+			continue
+		}
+		comparisons = append(comparisons, Comparison{
+			Scenario:        base.Scenario,
+			Baseline:        baseline,
+			Candidate:       candidate,
+			Precision:       other.Metrics.Precision - base.Metrics.Precision,
+			Recall:          other.Metrics.Recall - base.Metrics.Recall,
+			F1:              other.Metrics.F1 - base.Metrics.F1,
+			TimeToDetection: other.Metrics.MeanTimeToDetection - base.Metrics.MeanTimeToDetection,
+			StabilityRMSE:   other.Metrics.StabilityRMSE - base.Metrics.StabilityRMSE,
+		})
+	}
+	return comparisons
+}
+
+// WriteJSON 以JSON写出报告
+func (r *Report) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return types.NewSystemError(types.ErrIO, "failed to write evaluation report", err)
+	}
+	return nil
+}
+
+// Evaluator 以注册的场景评估检测器配置
+type Evaluator struct {
+	registry *Registry
+	template *emergence.PatternDetector
+}
+
+// NewEvaluator 创建评估器, 各配置在复制template的独立检测器上回放
+func NewEvaluator(registry *Registry, template *emergence.PatternDetector) *Evaluator {
+	return &Evaluator{registry: registry, template: template}
+}
+
+// Evaluate 在指定场景上评估各配置
+func (e *Evaluator) Evaluate(ctx context.Context, scenario string, candidates ...Candidate) (*Report, error) {
+	s, ok := e.registry.Get(scenario)
+	if !ok {
+		return nil, types.NewSystemError(types.ErrNotFound, "scenario not found", nil).
+			WithContext("scenario", scenario)
+	}
+	return e.evaluate(ctx, []*Scenario{s}, candidates)
+}
+
+// EvaluateAll 在全部注册的场景上评估各配置
+func (e *Evaluator) EvaluateAll(ctx context.Context, candidates ...Candidate) (*Report, error) {
+	scenarios := make([]*Scenario, 0)
+	for _, name := range e.registry.List() {
+		if s, ok := e.registry.Get(name); ok {
+			scenarios = append(scenarios, s)
+		}
+	}
+	return e.evaluate(ctx, scenarios, candidates)
+}
+
+// evaluate 逐场景逐配置回放评估
+func (e *Evaluator) evaluate(ctx context.Context, scenarios []*Scenario, candidates []Candidate) (*Report, error) {
+	if e.template == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil template detector", nil)
+	}
+	if len(candidates) == 0 {
+		candidates = []Candidate{{Name: "current"}}
+	}
+
+	report := &Report{Results: make([]Result, 0, len(scenarios)*len(candidates))}
+	for _, s := range scenarios {
+		for _, c := range candidates {
+			result, err := e.evaluateOne(ctx, s, c)
+			if err != nil {
+				return nil, err
+			}
+			report.Results = append(report.Results, result)
+		}
+	}
+	return report, nil
+}
+
+// evaluateOne 回放场景并计算指标
+func (e *Evaluator) evaluateOne(ctx context.Context, s *Scenario, c Candidate) (Result, error) {
+	started := time.Now()
+	annotations := make([]AnnotationResult, len(s.Annotations))
+	sqErrors := make([]float64, len(s.Annotations))
+	for i, a := range s.Annotations {
+		annotations[i].Annotation = a
+	}
+
+	var detected, truePositives int
+	err := emergence.Replay(ctx, e.template, s.Snapshots, c.Params, func(step emergence.ReplayStep) {
+		for _, p := range step.Detected {
+			detected++
+			matched := false
+			for i := range annotations {
+				a := &annotations[i]
+				if !a.matches(p, s.Tolerance) {
+					continue
+				}
+				matched = true
+				if !a.Detected || p.Formation.Before(a.FirstDetection) {
+					a.Detected = true
+					a.FirstDetection = p.Formation
+				}
+			}
+			if matched {
+				truePositives++
+			}
+		}
+
+		for i := range annotations {
+			a := &annotations[i]
+			if a.Stability <= 0 || !a.covers(step.Time) {
+				continue
+			}
+			a.WindowSteps++
+			if tracked, ok := trackedPattern(a.Annotation, step.Active, s.Tolerance); ok {
+				a.TrackedSteps++
+				diff := tracked.Stability - a.Stability
+				sqErrors[i] += diff * diff
+			}
+		}
+	})
+	if err != nil {
+		return Result{}, err
+	}
+
+	metrics := Metrics{
+		Detected:       detected,
+		TruePositives:  truePositives,
+		FalsePositives: detected - truePositives,
+	}
+	var totalDelay time.Duration
+	var sqError float64
+	var windowSteps int
+	for i := range annotations {
+		a := &annotations[i]
+		if a.Detected {
+			metrics.Hits++
+			if a.FirstDetection.After(a.From) {
+				a.TimeToDetection = a.FirstDetection.Sub(a.From)
+			}
+			totalDelay += a.TimeToDetection
+			if a.TimeToDetection > metrics.MaxTimeToDetection {
+				metrics.MaxTimeToDetection = a.TimeToDetection
+			}
+		} else {
+			metrics.Misses++
+		}
+		if a.TrackedSteps > 0 {
+			a.StabilityRMSE = math.Sqrt(sqErrors[i] / float64(a.TrackedSteps))
+		}
+		metrics.StabilitySamples += a.TrackedSteps
+		windowSteps += a.WindowSteps
+		sqError += sqErrors[i]
+	}
+
+	if metrics.Detected > 0 {
+		metrics.Precision = float64(metrics.TruePositives) / float64(metrics.Detected)
+	}
+	metrics.Recall = float64(metrics.Hits) / float64(len(annotations))
+	if metrics.Precision+metrics.Recall > 0 {
+		metrics.F1 = 2 * metrics.Precision * metrics.Recall / (metrics.Precision + metrics.Recall)
+	}
+	if metrics.Hits > 0 {
+		metrics.MeanTimeToDetection = totalDelay / time.Duration(metrics.Hits)
+	}
+	if metrics.StabilitySamples > 0 {
+		metrics.StabilityRMSE = math.Sqrt(sqError / float64(metrics.StabilitySamples))
+	}
+	if windowSteps > 0 {
+		metrics.StabilityCoverage = float64(metrics.StabilitySamples) / float64(windowSteps)
+	}
+
+	return Result{
+		Scenario:    s.Name,
+		Candidate:   c.Name,
+		Params:      c.Params,
+		Metrics:     metrics,
+		Annotations: annotations,
+		Elapsed:     time.Since(started),
+	}, nil
+}
+
+// trackedPattern 标注对应的被跟踪模式: 在标注区间内形成的活跃模式中强度最高者
+func trackedPattern(a Annotation, active []emergence.EmergentPattern, tolerance time.Duration) (emergence.EmergentPattern, bool) {
+	var best emergence.EmergentPattern
+	found := false
+	for _, p := range active {
+		if a.matches(p, tolerance) && (!found || p.Strength > best.Strength) {
+			best = p
+			found = true
+		}
+	}
+	return best, found
+}
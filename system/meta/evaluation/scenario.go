@@ -0,0 +1,164 @@
+// system/meta/evaluation/scenario.go
+
+package evaluation
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Annotation 真值标注: 场景中某类模式在[From, To]内出现
+type Annotation struct {
+	Type      string    `json:"type"`                // 模式类型, 为空时匹配任意类型
+	From      time.Time `json:"from"`                // 模式出现时间
+	To        time.Time `json:"to"`                  // 模式消失时间, 零值表示持续到场景结束
+	Stability float64   `json:"stability,omitempty"` // 期望稳定性, 0表示不评估稳定性跟踪
+	Note      string    `json:"note,omitempty"`
+}
+
+// matches 模式是否在标注区间内形成
+func (a Annotation) matches(p emergence.EmergentPattern, tolerance time.Duration) bool {
+	if a.Type != "" && a.Type != p.Type {
+		return false
+	}
+	if p.Formation.Before(a.From.Add(-tolerance)) {
+		return false
+	}
+	return a.To.IsZero() || !p.Formation.After(a.To.Add(tolerance))
+}
+
+// covers 时间是否在标注区间内
+func (a Annotation) covers(t time.Time) bool {
+	return !t.Before(a.From) && (a.To.IsZero() || !t.After(a.To))
+}
+
+// Scenario 记录的场景: 场状态快照序列和真值标注
+type Scenario struct {
+	Name        string
+	Description string
+	Snapshots   []*model.FieldState // 按时间顺序
+	Annotations []Annotation
+	Tolerance   time.Duration // 标注区间两端的容差
+}
+
+// NewScenario 由快照来源和标注创建场景
+func NewScenario(name string, source emergence.SnapshotSource, annotations []Annotation) (*Scenario, error) {
+	if source == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil snapshot source", nil)
+	}
+	snapshots, err := emergence.CollectSnapshots(source)
+	if err != nil {
+		return nil, err
+	}
+	scenario := &Scenario{
+		Name:        name,
+		Snapshots:   snapshots,
+		Annotations: annotations,
+	}
+	if err := scenario.Validate(); err != nil {
+		return nil, err
+	}
+	return scenario, nil
+}
+
+// Validate 验证场景
+func (s *Scenario) Validate() error {
+	if s.Name == "" {
+		return types.NewSystemError(types.ErrInvalid, "scenario name is required", nil)
+	}
+	if len(s.Snapshots) == 0 {
+		return types.NewSystemError(types.ErrInvalid, "scenario has no snapshots", nil).
+			WithContext("scenario", s.Name)
+	}
+	if len(s.Annotations) == 0 {
+		return types.NewSystemError(types.ErrInvalid, "scenario has no annotations", nil).
+			WithContext("scenario", s.Name)
+	}
+	for i, a := range s.Annotations {
+		if a.From.IsZero() || (!a.To.IsZero() && a.To.Before(a.From)) {
+			return types.NewSystemError(types.ErrInvalid, "invalid annotation interval", nil).
+				WithContext("scenario", s.Name).
+				WithContext("annotation", i)
+		}
+		if a.Stability < 0 || a.Stability > 1 {
+			return types.NewSystemError(types.ErrInvalid, "annotation stability out of range", nil).
+				WithContext("scenario", s.Name).
+				WithContext("annotation", i)
+		}
+	}
+	if s.Tolerance < 0 {
+		return types.NewSystemError(types.ErrInvalid, "negative tolerance", nil).
+			WithContext("scenario", s.Name)
+	}
+	return nil
+}
+
+// LoadAnnotations 读取JSON数组格式的标注
+func LoadAnnotations(r io.Reader) ([]Annotation, error) {
+	var annotations []Annotation
+	if err := json.NewDecoder(r).Decode(&annotations); err != nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "invalid annotations", err)
+	}
+	return annotations, nil
+}
+
+// Registry 场景注册表
+type Registry struct {
+	mu        sync.RWMutex
+	scenarios map[string]*Scenario
+}
+
+// NewRegistry 创建场景注册表
+func NewRegistry() *Registry {
+	return &Registry{scenarios: make(map[string]*Scenario)}
+}
+
+// Register 注册场景, 同名场景被替换
+func (r *Registry) Register(scenario *Scenario) error {
+	if scenario == nil {
+		return types.NewSystemError(types.ErrInvalid, "nil scenario", nil)
+	}
+	if err := scenario.Validate(); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.scenarios[scenario.Name] = scenario
+	return nil
+}
+
+// Remove 移除场景
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.scenarios, name)
+}
+
+// Get 获取场景
+func (r *Registry) Get(name string) (*Scenario, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	scenario, ok := r.scenarios[name]
+	return scenario, ok
+}
+
+// List 按名称排序的场景名
+func (r *Registry) List() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.scenarios))
+	for name := range r.scenarios {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
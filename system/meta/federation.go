@@ -0,0 +1,308 @@
+// system/meta/federation.go
+
+package meta
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// defaultFederationInterval 场间耦合的默认同步间隔
+const defaultFederationInterval = time.Second
+
+// FieldInfo 联邦成员场概况
+type FieldInfo struct {
+	Namespace types.Namespace `json:"namespace"`
+	Running   bool            `json:"running"`
+	Energy    float64         `json:"energy"`
+	Strength  float64         `json:"strength"`
+	Phase     float64         `json:"phase"`
+	Couplings int             `json:"couplings"` // 场内组件耦合数
+	Links     int             `json:"links"`     // 涉及该场的场间耦合数
+}
+
+// FieldLinkStatus 场间耦合状态
+type FieldLinkStatus struct {
+	ID        string                `json:"id"`
+	Config    types.FieldLinkConfig `json:"config"`
+	Active    bool                  `json:"active"` // 两端的场均已创建
+	Syncs     int64                 `json:"syncs"`
+	LastDelta float64               `json:"last_delta"` // 最近一次同步时目标场属性的变化量
+	LastSync  time.Time             `json:"last_sync"`
+}
+
+// fieldLink 场间耦合
+type fieldLink struct {
+	status FieldLinkStatus
+}
+
+// FieldManager 多场联邦
+// 成员场即各命名空间场域的统一场, 各自拥有独立的场配置和模式检测器; 联邦管理场间的显式耦合
+type FieldManager struct {
+	mu sync.RWMutex
+
+	meta     *Manager
+	interval time.Duration
+	links    map[string]*fieldLink
+}
+
+// newFieldManager 创建多场联邦, 配置中的场间耦合在场创建后生效
+func newFieldManager(m *Manager, cfg types.FederationConfig) (*FieldManager, error) {
+	fm := &FieldManager{
+		meta:     m,
+		interval: cfg.Interval,
+		links:    make(map[string]*fieldLink),
+	}
+	if fm.interval <= 0 {
+		fm.interval = m.config.Field.UpdateInterval
+	}
+	if fm.interval <= 0 {
+		fm.interval = defaultFederationInterval
+	}
+	for _, link := range cfg.Links {
+		if _, err := fm.addLink(link); err != nil {
+			return nil, err
+		}
+	}
+	return fm, nil
+}
+
+// Fields 获取多场联邦
+func (m *Manager) Fields() *FieldManager {
+	return m.components.federation
+}
+
+// Field 获取命名空间的统一场
+func (fm *FieldManager) Field(ns types.Namespace) *field.UnifiedField {
+	return fm.meta.GetNamespaceField(ns)
+}
+
+// Detector 获取命名空间的模式检测器
+func (fm *FieldManager) Detector(ns types.Namespace) *emergence.PatternDetector {
+	return fm.meta.GetNamespaceDetector(ns)
+}
+
+// List 列出成员场概况, 按命名空间排序
+func (fm *FieldManager) List() []FieldInfo {
+	namespaces := fm.meta.ListNamespaces()
+	sort.Slice(namespaces, func(i, j int) bool { return namespaces[i] < namespaces[j] })
+
+	fm.mu.RLock()
+	linkCount := make(map[types.Namespace]int)
+	for _, link := range fm.links {
+		linkCount[link.status.Config.Source]++
+		if link.status.Config.Target != link.status.Config.Source {
+			linkCount[link.status.Config.Target]++
+		}
+	}
+	fm.mu.RUnlock()
+
+	infos := make([]FieldInfo, 0, len(namespaces))
+	for _, ns := range namespaces {
+		f := fm.Field(ns)
+		if f == nil {
+			continue
+		}
+		info := FieldInfo{
+			Namespace: ns,
+			Running:   fm.meta.namespaceRunning(ns),
+			Couplings: len(f.CouplingGraph().Couplings),
+			Links:     linkCount[ns],
+		}
+		info.Energy, _ = f.GetPropertyValue("energy")
+		info.Strength, _ = f.GetPropertyValue("strength")
+		info.Phase, _ = f.GetPropertyValue("phase")
+		infos = append(infos, info)
+	}
+	return infos
+}
+
+// Link 定义场间耦合, 返回耦合ID; 两端的场须已创建
+func (fm *FieldManager) Link(cfg types.FieldLinkConfig) (string, error) {
+	for _, ns := range []types.Namespace{cfg.Source, cfg.Target} {
+		if fm.Field(ns) == nil {
+			return "", types.NewSystemError(types.ErrNotFound, "field not found", nil).
+				WithContext("namespace", ns)
+		}
+	}
+	return fm.addLink(cfg)
+}
+
+// Unlink 移除场间耦合
+func (fm *FieldManager) Unlink(id string) error {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	if _, ok := fm.links[id]; !ok {
+		return types.NewSystemError(types.ErrNotFound, "field link not found", nil).
+			WithContext("link", id)
+	}
+	delete(fm.links, id)
+	return nil
+}
+
+// Links 获取场间耦合状态, 按ID排序
+func (fm *FieldManager) Links() []FieldLinkStatus {
+	fm.mu.RLock()
+	defer fm.mu.RUnlock()
+
+	result := make([]FieldLinkStatus, 0, len(fm.links))
+	for _, link := range fm.links {
+		result = append(result, link.status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].ID < result[j].ID })
+	return result
+}
+
+// addLink 校验并登记场间耦合
+func (fm *FieldManager) addLink(cfg types.FieldLinkConfig) (string, error) {
+	if cfg.Source == "" {
+		cfg.Source = types.DefaultNamespace
+	}
+	if cfg.Target == "" {
+		cfg.Target = types.DefaultNamespace
+	}
+	if cfg.Property == "" {
+		cfg.Property = "energy"
+	}
+	if cfg.Source == cfg.Target {
+		return "", types.NewSystemError(types.ErrInvalid, "field link source and target must differ", nil)
+	}
+	if math.IsNaN(cfg.Strength) || cfg.Strength <= 0 || cfg.Strength > 1 {
+		return "", types.NewSystemError(types.ErrInvalid, "field link strength out of range (0,1]", nil).
+			WithContext("strength", cfg.Strength)
+	}
+
+	id := fieldLinkID(cfg)
+
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	for existing, link := range fm.links {
+		other := link.status.Config
+		if other.Property != cfg.Property {
+			continue
+		}
+		sameDirection := other.Source == cfg.Source && other.Target == cfg.Target
+		reverse := other.Source == cfg.Target && other.Target == cfg.Source
+		if sameDirection || (reverse && (other.Bidirectional || cfg.Bidirectional)) {
+			return "", types.NewSystemError(types.ErrExists, "field link conflicts with existing link", nil).
+				WithContext("link", id).
+				WithContext("existing", existing)
+		}
+	}
+
+	fm.links[id] = &fieldLink{status: FieldLinkStatus{ID: id, Config: cfg}}
+	return id, nil
+}
+
+// dropField 移除涉及命名空间的场间耦合
+func (fm *FieldManager) dropField(ns types.Namespace) {
+	fm.mu.Lock()
+	defer fm.mu.Unlock()
+
+	for id, link := range fm.links {
+		if link.status.Config.Source == ns || link.status.Config.Target == ns {
+			delete(fm.links, id)
+		}
+	}
+}
+
+// run 按间隔同步场间耦合
+func (fm *FieldManager) run(ctx context.Context) {
+	ticker := time.NewTicker(fm.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			fm.sync()
+		}
+	}
+}
+
+// sync 执行一次场间耦合
+// 目标场属性向源场靠拢差值的Strength比例, 双向耦合时两端相向靠拢; 相位差按[-π,π]计算
+func (fm *FieldManager) sync() {
+	fm.mu.RLock()
+	links := make([]FieldLinkStatus, 0, len(fm.links))
+	for _, link := range fm.links {
+		links = append(links, link.status)
+	}
+	fm.mu.RUnlock()
+	sort.Slice(links, func(i, j int) bool { return links[i].ID < links[j].ID })
+
+	now := time.Now()
+	for _, status := range links {
+		cfg := status.Config
+		source, target := fm.Field(cfg.Source), fm.Field(cfg.Target)
+		active := source != nil && target != nil
+
+		var delta float64
+		if active {
+			delta = applyFieldLink(cfg, source, target)
+		}
+
+		fm.mu.Lock()
+		if link, ok := fm.links[status.ID]; ok {
+			link.status.Active = active
+			if active {
+				link.status.Syncs++
+				link.status.LastDelta = delta
+				link.status.LastSync = now
+			}
+		}
+		fm.mu.Unlock()
+	}
+}
+
+// applyFieldLink 按耦合定义调整两端场的属性, 返回目标场属性的变化量
+func applyFieldLink(cfg types.FieldLinkConfig, source, target *field.UnifiedField) float64 {
+	from, _ := source.GetPropertyValue(cfg.Property)
+	to, _ := target.GetPropertyValue(cfg.Property)
+	if math.IsNaN(from) || math.IsInf(from, 0) || math.IsNaN(to) || math.IsInf(to, 0) {
+		return 0
+	}
+
+	phase := cfg.Property == "phase"
+	diff := from - to
+	if phase {
+		diff = math.Remainder(diff, 2*math.Pi)
+	}
+	// 双向耦合时两端各承担一半, 差值总共缩小Strength比例
+	delta := cfg.Strength * diff
+	if cfg.Bidirectional {
+		delta /= 2
+	}
+
+	set := func(f *field.UnifiedField, value float64) {
+		if phase {
+			value = math.Remainder(value, 2*math.Pi)
+		}
+		f.SetPropertyValue(cfg.Property, value)
+	}
+	set(target, to+delta)
+	if cfg.Bidirectional {
+		set(source, from-delta)
+	}
+	return delta
+}
+
+// fieldLinkID 场间耦合ID
+func fieldLinkID(cfg types.FieldLinkConfig) string {
+	arrow := "->"
+	if cfg.Bidirectional {
+		arrow = "<->"
+	}
+	return fmt.Sprintf("%s%s%s:%s", cfg.Source, arrow, cfg.Target, cfg.Property)
+}
@@ -0,0 +1,175 @@
+// system/meta/field/backend.go
+
+package field
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// 场张量计算后端
+const (
+	BackendGo   = "go"   // 纯Go实现, 总是可用
+	BackendSIMD = "simd" // SIMD加速实现, 需以fieldsimd构建标签在amd64上编译
+)
+
+// backendParityTolerance 加速后端与纯Go实现的最大相对误差
+const backendParityTolerance = 1e-9
+
+// Backend 场张量计算后端
+// 内核作用于连续存储的张量平面, 调用方保证切片长度一致
+type Backend interface {
+	// Name 后端名称
+	Name() string
+	// Norm2 计算 Σ|x|²
+	Norm2(x []complex128) float64
+	// Dist2 计算 Σ|x-y|²
+	Dist2(x, y []complex128) float64
+	// Axpy 计算 y += a·x
+	Axpy(a complex128, x, y []complex128)
+}
+
+// backendRegistry 后端注册表
+type backendRegistry struct {
+	mu       sync.Mutex
+	backends map[string]Backend
+}
+
+var (
+	backends = &backendRegistry{backends: map[string]Backend{BackendGo: goBackend{}}}
+	active   atomic.Value // 当前后端, 存储activeBackend
+)
+
+// activeBackend 当前后端的包装, atomic.Value要求存储类型一致
+type activeBackend struct {
+	Backend
+}
+
+func init() {
+	active.Store(activeBackend{goBackend{}})
+}
+
+// registerBackend 注册后端, 由构建标签控制的实现在init中调用
+func registerBackend(b Backend) {
+	backends.mu.Lock()
+	defer backends.mu.Unlock()
+	backends.backends[b.Name()] = b
+}
+
+// Backends 列出可用的后端
+func Backends() []string {
+	backends.mu.Lock()
+	defer backends.mu.Unlock()
+
+	names := make([]string, 0, len(backends.backends))
+	for name := range backends.backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// CurrentBackend 获取当前后端名称
+func CurrentBackend() string {
+	return currentBackend().Name()
+}
+
+// UseBackend 切换计算后端, 返回实际生效的后端名称
+// 空名称选择纯Go实现; 后端未编译或与纯Go实现的结果不一致时回退到纯Go实现并返回错误
+func UseBackend(name string) (string, error) {
+	if name == "" {
+		name = BackendGo
+	}
+
+	backends.mu.Lock()
+	b, ok := backends.backends[name]
+	backends.mu.Unlock()
+
+	if !ok {
+		active.Store(activeBackend{goBackend{}})
+		return BackendGo, model.NewModelError(model.ErrCodeNotFound, "field backend not available: "+name, nil)
+	}
+	if err := checkBackendParity(b); err != nil {
+		active.Store(activeBackend{goBackend{}})
+		return BackendGo, err
+	}
+	active.Store(activeBackend{b})
+	return b.Name(), nil
+}
+
+// currentBackend 获取当前后端
+func currentBackend() Backend {
+	return active.Load().(activeBackend).Backend
+}
+
+// checkBackendParity 以探测数据比较后端与纯Go实现的内核结果
+func checkBackendParity(b Backend) error {
+	if b.Name() == BackendGo {
+		return nil
+	}
+
+	// 覆盖奇偶长度和尾部处理
+	for _, n := range []int{0, 1, 2, 7, 64} {
+		x := make([]complex128, n)
+		y := make([]complex128, n)
+		for i := range x {
+			t := float64(i + 1)
+			x[i] = complex(math.Sin(t), math.Cos(3*t))
+			y[i] = complex(math.Cos(t)/t, -math.Sin(t*t))
+		}
+
+		var ref goBackend
+		if !parityClose(b.Norm2(x), ref.Norm2(x)) || !parityClose(b.Dist2(x, y), ref.Dist2(x, y)) {
+			return model.NewModelError(model.ErrCodeValidation, "field backend parity check failed: "+b.Name(), nil)
+		}
+
+		a := complex(0.75, -1.25)
+		got := append([]complex128(nil), y...)
+		want := append([]complex128(nil), y...)
+		b.Axpy(a, x, got)
+		ref.Axpy(a, x, want)
+		for i := range got {
+			if !parityClose(real(got[i]), real(want[i])) || !parityClose(imag(got[i]), imag(want[i])) {
+				return model.NewModelError(model.ErrCodeValidation, "field backend parity check failed: "+b.Name(), nil)
+			}
+		}
+	}
+	return nil
+}
+
+// parityClose 判断两值在相对误差内一致
+func parityClose(got, want float64) bool {
+	return math.Abs(got-want) <= backendParityTolerance*math.Max(1, math.Abs(want))
+}
+
+// goBackend 纯Go实现
+type goBackend struct{}
+
+func (goBackend) Name() string { return BackendGo }
+
+func (goBackend) Norm2(x []complex128) float64 {
+	var sum float64
+	for _, v := range x {
+		sum += real(v)*real(v) + imag(v)*imag(v)
+	}
+	return sum
+}
+
+func (goBackend) Dist2(x, y []complex128) float64 {
+	var sum float64
+	for i, v := range x {
+		d := v - y[i]
+		sum += real(d)*real(d) + imag(d)*imag(d)
+	}
+	return sum
+}
+
+func (goBackend) Axpy(a complex128, x, y []complex128) {
+	for i, v := range x {
+		y[i] += a * v
+	}
+}
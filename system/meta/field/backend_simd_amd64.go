@@ -0,0 +1,41 @@
+//go:build fieldsimd
+
+// system/meta/field/backend_simd_amd64.go
+
+package field
+
+// simdBackend SSE2实现, SSE2是amd64的基线指令集, 无需运行时检测
+type simdBackend struct{}
+
+func init() {
+	registerBackend(simdBackend{})
+}
+
+func (simdBackend) Name() string { return BackendSIMD }
+
+func (simdBackend) Norm2(x []complex128) float64 {
+	return norm2SSE2(x)
+}
+
+func (simdBackend) Dist2(x, y []complex128) float64 {
+	return dist2SSE2(x, y[:len(x)])
+}
+
+func (simdBackend) Axpy(a complex128, x, y []complex128) {
+	axpySSE2(real(a), imag(a), x, y[:len(x)])
+}
+
+// norm2SSE2 计算 Σ|x|²
+//
+//go:noescape
+func norm2SSE2(x []complex128) float64
+
+// dist2SSE2 计算 Σ|x-y|², 要求len(y) >= len(x)
+//
+//go:noescape
+func dist2SSE2(x, y []complex128) float64
+
+// axpySSE2 计算 y += (ar+ai·i)·x, 要求len(y) >= len(x)
+//
+//go:noescape
+func axpySSE2(ar, ai float64, x, y []complex128)
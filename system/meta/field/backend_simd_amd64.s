@@ -0,0 +1,122 @@
+//go:build fieldsimd
+
+// system/meta/field/backend_simd_amd64.s
+
+#include "textflag.h"
+
+// func norm2SSE2(x []complex128) float64
+TEXT ·norm2SSE2(SB), NOSPLIT, $0-32
+	MOVQ  x_base+0(FP), SI
+	MOVQ  x_len+8(FP), CX
+	XORPD X0, X0
+	XORPD X1, X1
+	CMPQ  CX, $2
+	JLT   norm2_tail
+
+norm2_loop:
+	// 每次处理两个复数, 两组累加器交替
+	MOVUPD 0(SI), X2
+	MOVUPD 16(SI), X3
+	MULPD  X2, X2
+	MULPD  X3, X3
+	ADDPD  X2, X0
+	ADDPD  X3, X1
+	ADDQ   $32, SI
+	SUBQ   $2, CX
+	CMPQ   CX, $2
+	JGE    norm2_loop
+
+norm2_tail:
+	TESTQ  CX, CX
+	JZ     norm2_done
+	MOVUPD 0(SI), X2
+	MULPD  X2, X2
+	ADDPD  X2, X0
+
+norm2_done:
+	ADDPD    X1, X0
+	MOVAPD   X0, X1
+	UNPCKHPD X1, X1
+	ADDSD    X1, X0
+	MOVSD    X0, ret+24(FP)
+	RET
+
+// func dist2SSE2(x, y []complex128) float64
+TEXT ·dist2SSE2(SB), NOSPLIT, $0-56
+	MOVQ  x_base+0(FP), SI
+	MOVQ  y_base+24(FP), DI
+	MOVQ  x_len+8(FP), CX
+	XORPD X0, X0
+	XORPD X1, X1
+	CMPQ  CX, $2
+	JLT   dist2_tail
+
+dist2_loop:
+	MOVUPD 0(SI), X2
+	MOVUPD 16(SI), X3
+	MOVUPD 0(DI), X4
+	MOVUPD 16(DI), X5
+	SUBPD  X4, X2
+	SUBPD  X5, X3
+	MULPD  X2, X2
+	MULPD  X3, X3
+	ADDPD  X2, X0
+	ADDPD  X3, X1
+	ADDQ   $32, SI
+	ADDQ   $32, DI
+	SUBQ   $2, CX
+	CMPQ   CX, $2
+	JGE    dist2_loop
+
+dist2_tail:
+	TESTQ  CX, CX
+	JZ     dist2_done
+	MOVUPD 0(SI), X2
+	MOVUPD 0(DI), X4
+	SUBPD  X4, X2
+	MULPD  X2, X2
+	ADDPD  X2, X0
+
+dist2_done:
+	ADDPD    X1, X0
+	MOVAPD   X0, X1
+	UNPCKHPD X1, X1
+	ADDSD    X1, X0
+	MOVSD    X0, ret+48(FP)
+	RET
+
+// func axpySSE2(ar, ai float64, x, y []complex128)
+TEXT ·axpySSE2(SB), NOSPLIT, $0-64
+	// X0 = [ar, ar], X1 = [-ai, ai]
+	MOVSD    ar+0(FP), X0
+	UNPCKLPD X0, X0
+	MOVSD    ai+8(FP), X1
+	UNPCKLPD X1, X1
+	MOVQ     $0x8000000000000000, AX
+	MOVQ     AX, X6
+	XORPD    X6, X1
+
+	MOVQ  x_base+16(FP), SI
+	MOVQ  y_base+40(FP), DI
+	MOVQ  x_len+24(FP), CX
+	TESTQ CX, CX
+	JZ    axpy_done
+
+axpy_loop:
+	// [xr, xi]·[ar, ar] + [xi, xr]·[-ai, ai] = a·x
+	MOVUPD 0(SI), X2
+	MOVAPD X2, X3
+	SHUFPD $1, X3, X3
+	MULPD  X0, X2
+	MULPD  X1, X3
+	ADDPD  X3, X2
+	MOVUPD 0(DI), X4
+	ADDPD  X2, X4
+	MOVUPD X4, 0(DI)
+	ADDQ   $16, SI
+	ADDQ   $16, DI
+	DECQ   CX
+	JNZ    axpy_loop
+
+axpy_done:
+	RET
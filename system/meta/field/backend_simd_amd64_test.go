@@ -0,0 +1,103 @@
+//go:build fieldsimd
+
+// system/meta/field/backend_simd_amd64_test.go
+
+package field
+
+import (
+	"math"
+	"math/rand"
+	"testing"
+)
+
+// parityLengths 覆盖空切片、单元素、奇偶长度和展开循环的尾部
+var parityLengths = []int{0, 1, 2, 3, 4, 5, 7, 8, 9, 15, 16, 17, 31, 32, 33, 63, 64, 65, 127, 1000, 1001}
+
+// parityVectors 生成长度为n的确定性测试向量
+func parityVectors(n int, seed int64) ([]complex128, []complex128) {
+	rng := rand.New(rand.NewSource(seed))
+	x := make([]complex128, n)
+	y := make([]complex128, n)
+	for i := range x {
+		x[i] = complex(rng.NormFloat64()*10, rng.NormFloat64())
+		y[i] = complex(rng.NormFloat64(), rng.NormFloat64()*1e-3)
+	}
+	return x, y
+}
+
+func TestSIMDBackendParityNorm2(t *testing.T) {
+	var simd simdBackend
+	var ref goBackend
+	for _, n := range parityLengths {
+		x, _ := parityVectors(n, int64(n))
+		if got, want := simd.Norm2(x), ref.Norm2(x); !parityClose(got, want) {
+			t.Errorf("Norm2 len=%d: simd=%v go=%v", n, got, want)
+		}
+	}
+}
+
+func TestSIMDBackendParityDist2(t *testing.T) {
+	var simd simdBackend
+	var ref goBackend
+	for _, n := range parityLengths {
+		x, y := parityVectors(n, int64(n))
+		if got, want := simd.Dist2(x, y), ref.Dist2(x, y); !parityClose(got, want) {
+			t.Errorf("Dist2 len=%d: simd=%v go=%v", n, got, want)
+		}
+	}
+}
+
+func TestSIMDBackendParityAxpy(t *testing.T) {
+	var simd simdBackend
+	var ref goBackend
+	for _, a := range []complex128{0, 1, complex(0.75, -1.25), complex(-3, 0), complex(0, 2)} {
+		for _, n := range parityLengths {
+			x, y := parityVectors(n, int64(n))
+			got := append([]complex128(nil), y...)
+			want := append([]complex128(nil), y...)
+			simd.Axpy(a, x, got)
+			ref.Axpy(a, x, want)
+			for i := range want {
+				if !parityClose(real(got[i]), real(want[i])) || !parityClose(imag(got[i]), imag(want[i])) {
+					t.Fatalf("Axpy a=%v len=%d index=%d: simd=%v go=%v", a, n, i, got[i], want[i])
+				}
+			}
+		}
+	}
+}
+
+// 子切片的起始地址不按16字节以外对齐, 验证内核不依赖对齐
+func TestSIMDBackendParitySubslices(t *testing.T) {
+	var simd simdBackend
+	var ref goBackend
+	x, y := parityVectors(70, 7)
+	for offset := 0; offset < 3; offset++ {
+		xs, ys := x[offset:offset+61], y[offset:offset+61]
+		if got, want := simd.Norm2(xs), ref.Norm2(xs); !parityClose(got, want) {
+			t.Errorf("Norm2 offset=%d: simd=%v go=%v", offset, got, want)
+		}
+		if got, want := simd.Dist2(xs, ys), ref.Dist2(xs, ys); !parityClose(got, want) {
+			t.Errorf("Dist2 offset=%d: simd=%v go=%v", offset, got, want)
+		}
+	}
+}
+
+func TestUseBackendSIMD(t *testing.T) {
+	defer UseBackend(BackendGo)
+
+	name, err := UseBackend(BackendSIMD)
+	if err != nil || name != BackendSIMD {
+		t.Fatalf("UseBackend(simd) = %q, %v", name, err)
+	}
+	if CurrentBackend() != BackendSIMD {
+		t.Fatalf("CurrentBackend() = %q", CurrentBackend())
+	}
+
+	// Axpy 写入范围不超出切片长度
+	x, y := parityVectors(5, 1)
+	buf := append(append([]complex128(nil), y...), complex(math.Pi, math.E))
+	currentBackend().Axpy(2, x, buf[:5])
+	if buf[5] != complex(math.Pi, math.E) {
+		t.Fatalf("Axpy wrote past the slice end: %v", buf[5])
+	}
+}
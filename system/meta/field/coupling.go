@@ -9,6 +9,7 @@ import (
 	"time"
 
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/invariants"
 )
 
 // FieldCoupling 场耦合关系
@@ -49,6 +50,14 @@ type FieldCoupling struct {
 		interaction float64 // 时空相互作用强度
 		causality   bool    // 是否满足因果性
 	}
+
+	// 拓扑特性
+	topology struct {
+		source        string // 源场组件
+		target        string // 目标场组件
+		bidirectional bool   // 是否双向耦合
+		pinned        bool   // 强度由外部设定, 更新时不重新计算
+	}
 }
 
 // CouplingState 耦合状态
@@ -219,12 +228,14 @@ func (fc *FieldCoupling) Update() error {
 
 // updateProperties 更新耦合基本特性
 func (fc *FieldCoupling) updateProperties() error {
-	// 更新强度
-	strength, err := fc.calculateStrength()
-	if err != nil {
-		return err
+	// 更新强度, 外部设定的强度保持不变
+	if !fc.topology.pinned {
+		strength, err := fc.calculateStrength()
+		if err != nil {
+			return err
+		}
+		fc.properties.strength = strength
 	}
-	fc.properties.strength = strength
 
 	// 更新类型
 	fc.properties.type_ = fc.determineType()
@@ -322,24 +333,17 @@ func (fc *FieldCoupling) calculateStrength() (float64, error) {
 	// 综合场重叠和量子重叠
 	strength := math.Sqrt(overlap * quantumOverlap)
 
-	return normalizeValue(strength), nil
+	return normalizeValue("field.coupling.strength", strength)
 }
 
-// normalizeValue 标准化值到[0,1]范围
-func normalizeValue(value float64) float64 {
-	return math.Max(0, math.Min(1, value))
+// normalizeValue 标准化值到[0,1]范围, 严格不变量检查下越界时返回错误
+func normalizeValue(name string, value float64) (float64, error) {
+	return invariants.ClampUnit(name, value)
 }
 
 // calculateNorm 计算场的范数
 func (fc *FieldCoupling) calculateNorm(field *FieldTensor) float64 {
-	norm := 0.0
-	for i := 0; i < field.dimension; i++ {
-		for j := 0; j < field.dimension; j++ {
-			val, _ := field.GetComponent([]int{i, j})
-			norm += cmplx.Abs(val) * cmplx.Abs(val)
-		}
-	}
-	return math.Sqrt(norm)
+	return field.Norm()
 }
 
 // calculateFieldOverlap 计算场重叠
@@ -369,6 +373,10 @@ func (fc *FieldCoupling) calculateFieldOverlap() (float64, error) {
 
 // calculateStateOverlap 计算量子态重叠
 func (fc *FieldCoupling) calculateStateOverlap() float64 {
+	if fc.field1.quantum.state == nil || fc.field2.quantum.state == nil {
+		return 0.0
+	}
+
 	// 使用DotProduct方法计算量子态重叠
 	overlap, err := fc.field1.quantum.state.DotProduct(fc.field2.quantum.state)
 	if err != nil {
@@ -380,6 +388,10 @@ func (fc *FieldCoupling) calculateStateOverlap() float64 {
 }
 
 func (fc *FieldCoupling) calculatePhase() (float64, error) {
+	if fc.field1.quantum.state == nil || fc.field2.quantum.state == nil {
+		return 0.0, nil
+	}
+
 	phase1 := fc.field1.quantum.state.GetPhase()
 	phase2 := fc.field2.quantum.state.GetPhase()
 
@@ -423,7 +435,7 @@ func (fc *FieldCoupling) calculateCoherence() (float64, error) {
 	phaseCoherence := math.Cos(phase)
 	strengthCoherence := math.Sqrt(strength)
 
-	return normalizeValue(math.Abs(phaseCoherence * strengthCoherence)), nil
+	return normalizeValue("field.coupling.coherence", math.Abs(phaseCoherence*strengthCoherence))
 }
 
 func (fc *FieldCoupling) calculateStability() (float64, error) {
@@ -443,7 +455,7 @@ func (fc *FieldCoupling) calculateStability() (float64, error) {
 	}
 
 	stability := 1.0 / (1.0 + strengthVar + energyVar)
-	return normalizeValue(stability), nil
+	return normalizeValue("field.coupling.stability", stability)
 }
 
 func (fc *FieldCoupling) calculateResonance() (float64, error) {
@@ -458,23 +470,12 @@ func (fc *FieldCoupling) calculateResonance() (float64, error) {
 		resonance *= (1 + fc.quantum.entanglement)
 	}
 
-	return normalizeValue(resonance), nil
+	return normalizeValue("field.coupling.resonance", resonance)
 }
 
 // 简化的场间距离计算
 func (fc *FieldCoupling) calculateDistance() (float64, error) {
-
-	dim := fc.field1.dimension
-	distance := 0.0
-
-	for i := 0; i < dim; i++ {
-		for j := 0; j < dim; j++ {
-			diff := fc.field1.data[i][j][0] - fc.field2.data[i][j][0]
-			distance += cmplx.Abs(diff) * cmplx.Abs(diff)
-		}
-	}
-
-	return math.Sqrt(distance), nil
+	return fc.field1.Distance(fc.field2)
 }
 
 func (fc *FieldCoupling) calculateInteraction() (float64, error) {
@@ -486,7 +487,7 @@ func (fc *FieldCoupling) calculateInteraction() (float64, error) {
 		interaction *= (1 + fc.quantum.entanglement)
 	}
 
-	return normalizeValue(interaction), nil
+	return normalizeValue("field.coupling.interaction", interaction)
 }
 
 // 辅助方法
@@ -505,7 +506,7 @@ func (fc *FieldCoupling) checkCausality() bool {
 	return fc.spacetime.interaction <= 1.0
 }
 
-// recordState 记录当前耦合状态
+// recordState 记录当前耦合状态, 调用方须持有耦合锁
 func (fc *FieldCoupling) recordState() {
 	state := CouplingState{
 		Timestamp: time.Now(),
@@ -527,9 +528,6 @@ func (fc *FieldCoupling) recordState() {
 		},
 	}
 
-	fc.mu.Lock()
-	defer fc.mu.Unlock()
-
 	fc.dynamics.evolution = append(fc.dynamics.evolution, state)
 
 	// 限制历史记录长度
@@ -0,0 +1,561 @@
+// system/meta/field/interchange.go
+
+package field
+
+import (
+	"encoding/binary"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"math"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+)
+
+// 能量分布和元素状态的交换格式
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatNPY  = "npy" // NumPy .npy, 分布为(N,4)的float64数组, 元素为结构化数组
+)
+
+// elementPropertyPrefix CSV和NPY中元素属性列的前缀
+const elementPropertyPrefix = "prop_"
+
+// DistributionPoint 能量分布中的点
+type DistributionPoint struct {
+	X      int     `json:"x"`      // 横坐标
+	Y      int     `json:"y"`      // 纵坐标
+	Z      int     `json:"z"`      // 深度坐标, 二维场为0
+	Energy float64 `json:"energy"` // 能量
+}
+
+// ElementRecord 场元素状态
+type ElementRecord struct {
+	Type       string             `json:"type"`                 // 元素类型
+	Energy     float64            `json:"energy"`               // 元素能量
+	X          int                `json:"x"`                    // 横坐标
+	Y          int                `json:"y"`                    // 纵坐标
+	Properties map[string]float64 `json:"properties,omitempty"` // 元素属性
+}
+
+// FormatFromPath 由文件扩展名推断交换格式
+func FormatFromPath(path string) (string, error) {
+	switch ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext {
+	case FormatJSON, FormatCSV, FormatNPY:
+		return ext, nil
+	default:
+		return "", model.NewModelError(model.ErrCodeValidation, "unsupported interchange format: "+path, nil)
+	}
+}
+
+// Distribution 获取外部观测能量分布, 按z、y、x排序
+func (uf *UnifiedField) Distribution() []DistributionPoint {
+	uf.mu.RLock()
+	defer uf.mu.RUnlock()
+
+	points := make([]DistributionPoint, 0, len(uf.state.Observed))
+	for p, energy := range uf.state.Observed {
+		points = append(points, DistributionPoint{X: p.X, Y: p.Y, Z: p.Z, Energy: energy})
+	}
+	sort.Slice(points, func(i, j int) bool {
+		a, b := points[i], points[j]
+		if a.Z != b.Z {
+			return a.Z < b.Z
+		}
+		if a.Y != b.Y {
+			return a.Y < b.Y
+		}
+		return a.X < b.X
+	})
+	return points
+}
+
+// Elements 获取场元素状态
+func (uf *UnifiedField) Elements() []ElementRecord {
+	uf.mu.RLock()
+	defer uf.mu.RUnlock()
+
+	records := make([]ElementRecord, 0, len(uf.WuXingElements))
+	for _, elem := range uf.WuXingElements {
+		record := ElementRecord{
+			Type:       elem.Type,
+			Energy:     elem.Energy,
+			X:          elem.Position.X,
+			Y:          elem.Position.Y,
+			Properties: make(map[string]float64, len(elem.Properties)),
+		}
+		for name, value := range elem.Properties {
+			record.Properties[name] = value
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// SetDistribution 以给定分布替换外部观测能量分布, 能量为0的点被忽略
+func (uf *UnifiedField) SetDistribution(points []DistributionPoint) error {
+	observed := make(map[core.Point]float64, len(points))
+	for _, p := range points {
+		if p.Energy < 0 || math.IsNaN(p.Energy) || math.IsInf(p.Energy, 0) {
+			return model.NewModelError(model.ErrCodeValidation,
+				"invalid energy "+strconv.FormatFloat(p.Energy, 'g', -1, 64), nil)
+		}
+		if p.Energy > 0 {
+			observed[core.Point{X: p.X, Y: p.Y, Z: p.Z}] += p.Energy
+		}
+	}
+
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+	uf.state.Observed = observed
+	return nil
+}
+
+// SetElements 以给定状态设置场元素, 元素不存在时创建, 未列出的元素保持不变
+func (uf *UnifiedField) SetElements(records []ElementRecord) error {
+	for _, r := range records {
+		if r.Type == "" {
+			return model.NewModelError(model.ErrCodeValidation, "element type is required", nil)
+		}
+		if r.Energy < 0 || math.IsNaN(r.Energy) || math.IsInf(r.Energy, 0) {
+			return model.NewModelError(model.ErrCodeValidation, "invalid energy for element "+r.Type, nil)
+		}
+	}
+
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	for _, r := range records {
+		var target *WuXingElement
+		for _, elem := range uf.WuXingElements {
+			if elem.Type == r.Type {
+				target = elem
+				break
+			}
+		}
+		if target == nil {
+			target = &WuXingElement{
+				Type:    r.Type,
+				History: make([]model.WuXingElementState, 0),
+			}
+			uf.WuXingElements = append(uf.WuXingElements, target)
+		}
+
+		target.Energy = math.Max(minWuXingElementEnergy, r.Energy)
+		target.Position.X = r.X
+		target.Position.Y = r.Y
+		target.Properties = make(map[string]float64, len(r.Properties))
+		for name, value := range r.Properties {
+			target.Properties[name] = value
+		}
+	}
+	return nil
+}
+
+// ExportDistribution 以指定格式导出外部观测能量分布
+// CSV列为x,y,z,energy; NPY为(N,4)的float64数组, 列顺序相同
+func (uf *UnifiedField) ExportDistribution(w io.Writer, format string) error {
+	points := uf.Distribution()
+
+	switch format {
+	case FormatJSON, "":
+		return writeJSON(w, points)
+	case FormatCSV:
+		rows := make([][]string, 0, len(points))
+		for _, p := range points {
+			rows = append(rows, []string{
+				strconv.Itoa(p.X),
+				strconv.Itoa(p.Y),
+				strconv.Itoa(p.Z),
+				strconv.FormatFloat(p.Energy, 'g', -1, 64),
+			})
+		}
+		return writeCSV(w, []string{"x", "y", "z", "energy"}, rows)
+	case FormatNPY:
+		data := make([]byte, 0, len(points)*4*8)
+		for _, p := range points {
+			for _, v := range []float64{float64(p.X), float64(p.Y), float64(p.Z), p.Energy} {
+				data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+			}
+		}
+		return writeNPY(w, "'<f8'", []int{len(points), 4}, data)
+	default:
+		return model.NewModelError(model.ErrCodeValidation, "unsupported interchange format: "+format, nil)
+	}
+}
+
+// ImportDistribution 读取指定格式的能量分布作为外部观测初始条件, 替换现有分布
+// CSV需包含x,y,energy列, z列可省略; NPY接受(N,3)或(N,4)的数值数组
+func (uf *UnifiedField) ImportDistribution(r io.Reader, format string) error {
+	var points []DistributionPoint
+
+	switch format {
+	case FormatJSON, "":
+		if err := json.NewDecoder(r).Decode(&points); err != nil {
+			return model.WrapError(err, model.ErrCodeValidation, "failed to decode distribution")
+		}
+	case FormatCSV:
+		header, rows, err := readCSV(r)
+		if err != nil {
+			return err
+		}
+		cols, err := csvColumns(header, []string{"x", "y", "energy"})
+		if err != nil {
+			return err
+		}
+		z, hasZ := indexOf(header, "z")
+		for _, row := range rows {
+			var p DistributionPoint
+			if p.X, err = parseCoordinate(row[cols[0]]); err != nil {
+				return err
+			}
+			if p.Y, err = parseCoordinate(row[cols[1]]); err != nil {
+				return err
+			}
+			if hasZ {
+				if p.Z, err = parseCoordinate(row[z]); err != nil {
+					return err
+				}
+			}
+			if p.Energy, err = strconv.ParseFloat(strings.TrimSpace(row[cols[2]]), 64); err != nil {
+				return model.WrapError(err, model.ErrCodeValidation, "invalid energy: "+row[cols[2]])
+			}
+			points = append(points, p)
+		}
+	case FormatNPY:
+		arr, err := readNPY(r)
+		if err != nil {
+			return err
+		}
+		if arr.record || len(arr.shape) != 2 || (arr.shape[1] != 3 && arr.shape[1] != 4) {
+			return model.NewModelError(model.ErrCodeValidation,
+				"distribution npy must be a numeric (N,3) or (N,4) array", nil)
+		}
+		values, err := npyNumbers(arr)
+		if err != nil {
+			return err
+		}
+		width := arr.shape[1]
+		for i := 0; i < arr.shape[0]; i++ {
+			row := values[i*width : (i+1)*width]
+			coords := make([]int, 3)
+			for j := 0; j < width-1; j++ {
+				if row[j] != math.Trunc(row[j]) || math.Abs(row[j]) > math.MaxInt32 {
+					return model.NewModelError(model.ErrCodeValidation,
+						"invalid coordinate "+strconv.FormatFloat(row[j], 'g', -1, 64), nil)
+				}
+				coords[j] = int(row[j])
+			}
+			points = append(points, DistributionPoint{X: coords[0], Y: coords[1], Z: coords[2], Energy: row[width-1]})
+		}
+	default:
+		return model.NewModelError(model.ErrCodeValidation, "unsupported interchange format: "+format, nil)
+	}
+
+	return uf.SetDistribution(points)
+}
+
+// ExportElements 以指定格式导出场元素状态
+// CSV列为type,energy,x,y及prop_前缀的属性列; NPY为同名字段的结构化数组, 缺失属性为NaN
+func (uf *UnifiedField) ExportElements(w io.Writer, format string) error {
+	records := uf.Elements()
+
+	// 属性列取所有元素属性的并集
+	seen := make(map[string]bool)
+	var properties []string
+	for _, r := range records {
+		for name := range r.Properties {
+			if !seen[name] {
+				seen[name] = true
+				properties = append(properties, name)
+			}
+		}
+	}
+	sort.Strings(properties)
+
+	switch format {
+	case FormatJSON, "":
+		return writeJSON(w, records)
+	case FormatCSV:
+		header := []string{"type", "energy", "x", "y"}
+		for _, name := range properties {
+			header = append(header, elementPropertyPrefix+name)
+		}
+		rows := make([][]string, 0, len(records))
+		for _, r := range records {
+			row := []string{
+				r.Type,
+				strconv.FormatFloat(r.Energy, 'g', -1, 64),
+				strconv.Itoa(r.X),
+				strconv.Itoa(r.Y),
+			}
+			for _, name := range properties {
+				if value, ok := r.Properties[name]; ok {
+					row = append(row, strconv.FormatFloat(value, 'g', -1, 64))
+				} else {
+					row = append(row, "")
+				}
+			}
+			rows = append(rows, row)
+		}
+		return writeCSV(w, header, rows)
+	case FormatNPY:
+		typeLen := 1
+		for _, r := range records {
+			if n := len([]rune(r.Type)); n > typeLen {
+				typeLen = n
+			}
+		}
+		typeDtype := "<U" + strconv.Itoa(typeLen)
+
+		fields := []string{"('type', '" + typeDtype + "')", "('energy', '<f8')", "('x', '<i8')", "('y', '<i8')"}
+		for _, name := range properties {
+			quoted, err := npyQuote(elementPropertyPrefix + name)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, "("+quoted+", '<f8')")
+		}
+
+		var data []byte
+		for _, r := range records {
+			runes := []rune(r.Type)
+			for i := 0; i < typeLen; i++ {
+				var c uint32
+				if i < len(runes) {
+					c = uint32(runes[i])
+				}
+				data = binary.LittleEndian.AppendUint32(data, c)
+			}
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(r.Energy))
+			data = binary.LittleEndian.AppendUint64(data, uint64(int64(r.X)))
+			data = binary.LittleEndian.AppendUint64(data, uint64(int64(r.Y)))
+			for _, name := range properties {
+				value, ok := r.Properties[name]
+				if !ok {
+					value = math.NaN()
+				}
+				data = binary.LittleEndian.AppendUint64(data, math.Float64bits(value))
+			}
+		}
+		return writeNPY(w, "["+strings.Join(fields, ", ")+"]", []int{len(records)}, data)
+	default:
+		return model.NewModelError(model.ErrCodeValidation, "unsupported interchange format: "+format, nil)
+	}
+}
+
+// ImportElements 读取指定格式的场元素状态作为初始条件
+// 需包含type和energy, 坐标可省略; 空值和NaN属性被忽略
+func (uf *UnifiedField) ImportElements(r io.Reader, format string) error {
+	var records []ElementRecord
+
+	switch format {
+	case FormatJSON, "":
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return model.WrapError(err, model.ErrCodeValidation, "failed to decode elements")
+		}
+	case FormatCSV:
+		header, rows, err := readCSV(r)
+		if err != nil {
+			return err
+		}
+		cols, err := csvColumns(header, []string{"type", "energy"})
+		if err != nil {
+			return err
+		}
+		x, hasX := indexOf(header, "x")
+		y, hasY := indexOf(header, "y")
+		for _, row := range rows {
+			record := ElementRecord{Type: strings.TrimSpace(row[cols[0]]), Properties: make(map[string]float64)}
+			if record.Energy, err = strconv.ParseFloat(strings.TrimSpace(row[cols[1]]), 64); err != nil {
+				return model.WrapError(err, model.ErrCodeValidation, "invalid energy: "+row[cols[1]])
+			}
+			if hasX {
+				if record.X, err = parseCoordinate(row[x]); err != nil {
+					return err
+				}
+			}
+			if hasY {
+				if record.Y, err = parseCoordinate(row[y]); err != nil {
+					return err
+				}
+			}
+			for i, name := range header {
+				cell := strings.TrimSpace(row[i])
+				if !strings.HasPrefix(name, elementPropertyPrefix) || cell == "" {
+					continue
+				}
+				value, err := strconv.ParseFloat(cell, 64)
+				if err != nil {
+					return model.WrapError(err, model.ErrCodeValidation, "invalid property "+name+": "+cell)
+				}
+				if !math.IsNaN(value) {
+					record.Properties[strings.TrimPrefix(name, elementPropertyPrefix)] = value
+				}
+			}
+			records = append(records, record)
+		}
+	case FormatNPY:
+		arr, err := readNPY(r)
+		if err != nil {
+			return err
+		}
+		if !arr.record || len(arr.shape) != 1 {
+			return model.NewModelError(model.ErrCodeValidation, "elements npy must be a 1-d structured array", nil)
+		}
+		records, err = npyElements(arr)
+		if err != nil {
+			return err
+		}
+	default:
+		return model.NewModelError(model.ErrCodeValidation, "unsupported interchange format: "+format, nil)
+	}
+
+	return uf.SetElements(records)
+}
+
+// npyNumbers 解码普通数值数组
+func npyNumbers(arr *npyArray) ([]float64, error) {
+	dtype := arr.fields[0].dtype
+	size, err := npyItemSize(dtype)
+	if err != nil {
+		return nil, err
+	}
+	values := make([]float64, len(arr.data)/size)
+	for i := range values {
+		if values[i], err = npyNumber(dtype, arr.data[i*size:(i+1)*size]); err != nil {
+			return nil, err
+		}
+	}
+	return values, nil
+}
+
+// npyElements 解码元素结构化数组
+func npyElements(arr *npyArray) ([]ElementRecord, error) {
+	offsets := make([]int, len(arr.fields))
+	itemSize := 0
+	hasType, hasEnergy := false, false
+	for i, f := range arr.fields {
+		offsets[i] = itemSize
+		n, err := npyItemSize(f.dtype)
+		if err != nil {
+			return nil, err
+		}
+		itemSize += n
+		hasType = hasType || f.name == "type"
+		hasEnergy = hasEnergy || f.name == "energy"
+	}
+	if !hasType || !hasEnergy {
+		return nil, model.NewModelError(model.ErrCodeValidation, "elements npy requires type and energy fields", nil)
+	}
+
+	records := make([]ElementRecord, arr.shape[0])
+	for i := range records {
+		item := arr.data[i*itemSize : (i+1)*itemSize]
+		record := ElementRecord{Properties: make(map[string]float64)}
+		for j, f := range arr.fields {
+			size, _ := npyItemSize(f.dtype)
+			b := item[offsets[j] : offsets[j]+size]
+
+			if f.name == "type" {
+				value, err := npyString(f.dtype, b)
+				if err != nil {
+					return nil, err
+				}
+				record.Type = strings.TrimSpace(value)
+				continue
+			}
+			value, err := npyNumber(f.dtype, b)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case f.name == "energy":
+				record.Energy = value
+			case f.name == "x":
+				record.X = int(value)
+			case f.name == "y":
+				record.Y = int(value)
+			case strings.HasPrefix(f.name, elementPropertyPrefix) && !math.IsNaN(value):
+				record.Properties[strings.TrimPrefix(f.name, elementPropertyPrefix)] = value
+			}
+		}
+		records[i] = record
+	}
+	return records, nil
+}
+
+// writeJSON 以缩进JSON写出
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		return model.WrapError(err, model.ErrCodeIO, "failed to write json")
+	}
+	return nil
+}
+
+// writeCSV 写出带表头的CSV
+func writeCSV(w io.Writer, header []string, rows [][]string) error {
+	cw := csv.NewWriter(w)
+	cw.Write(header)
+	cw.WriteAll(rows)
+	if err := cw.Error(); err != nil {
+		return model.WrapError(err, model.ErrCodeIO, "failed to write csv")
+	}
+	return nil
+}
+
+// readCSV 读取带表头的CSV, 表头名统一为小写
+func readCSV(r io.Reader) ([]string, [][]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, nil, model.WrapError(err, model.ErrCodeValidation, "failed to read csv")
+	}
+	if len(records) == 0 {
+		return nil, nil, model.NewModelError(model.ErrCodeValidation, "missing csv header", nil)
+	}
+	header := records[0]
+	for i, name := range header {
+		header[i] = strings.ToLower(strings.TrimSpace(name))
+	}
+	return header, records[1:], nil
+}
+
+// csvColumns 查找必需列的下标
+func csvColumns(header, required []string) ([]int, error) {
+	cols := make([]int, len(required))
+	for i, name := range required {
+		col, ok := indexOf(header, name)
+		if !ok {
+			return nil, model.NewModelError(model.ErrCodeValidation, "missing csv column: "+name, nil)
+		}
+		cols[i] = col
+	}
+	return cols, nil
+}
+
+// indexOf 查找表头列
+func indexOf(header []string, name string) (int, bool) {
+	for i, h := range header {
+		if h == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// parseCoordinate 解析整数坐标
+func parseCoordinate(s string) (int, error) {
+	v, err := strconv.Atoi(strings.TrimSpace(s))
+	if err != nil {
+		return 0, model.WrapError(err, model.ErrCodeValidation, "invalid coordinate: "+s)
+	}
+	return v, nil
+}
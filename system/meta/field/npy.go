@@ -0,0 +1,283 @@
+// system/meta/field/npy.go
+
+package field
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// NumPy .npy 格式常量
+const (
+	npyMagic     = "\x93NUMPY"
+	npyAlignment = 64      // 头部对齐字节数
+	npyMaxHeader = 1 << 20 // 读取时允许的最大头部长度
+)
+
+var (
+	npyDescrScalar = regexp.MustCompile(`'descr'\s*:\s*'([^']*)'`)
+	npyDescrRecord = regexp.MustCompile(`'descr'\s*:\s*\[(.*)\]`)
+	npyRecordField = regexp.MustCompile(`\(\s*'([^']*)'\s*,\s*'([^']*)'\s*\)`)
+	npyFortran     = regexp.MustCompile(`'fortran_order'\s*:\s*(True|False)`)
+	npyShape       = regexp.MustCompile(`'shape'\s*:\s*\(([^)]*)\)`)
+)
+
+// npyField 结构化数组的字段, 普通数组只有一个无名字段
+type npyField struct {
+	name  string
+	dtype string // 如 <f8, <i8, <U16
+}
+
+// npyArray 解码后的NumPy数组
+type npyArray struct {
+	fields []npyField
+	record bool // 是否为结构化数组
+	shape  []int
+	data   []byte
+}
+
+// npyItemSize 单个数据类型的字节数
+func npyItemSize(dtype string) (int, error) {
+	if len(dtype) < 3 {
+		return 0, model.NewModelError(model.ErrCodeValidation, "unsupported npy dtype: "+dtype, nil)
+	}
+	n, err := strconv.Atoi(dtype[2:])
+	if err != nil || n <= 0 {
+		return 0, model.NewModelError(model.ErrCodeValidation, "unsupported npy dtype: "+dtype, nil)
+	}
+
+	switch dtype[:2] {
+	case "<f", "<i", "<u":
+		if n != 1 && n != 2 && n != 4 && n != 8 {
+			break
+		}
+		return n, nil
+	case "|i", "|u", "|b":
+		if n == 1 {
+			return 1, nil
+		}
+	case "<U":
+		return 4 * n, nil
+	case "|S":
+		return n, nil
+	}
+	return 0, model.NewModelError(model.ErrCodeValidation, "unsupported npy dtype: "+dtype, nil)
+}
+
+// npyNumber 解码数值元素
+func npyNumber(dtype string, b []byte) (float64, error) {
+	switch dtype {
+	case "<f8":
+		return math.Float64frombits(binary.LittleEndian.Uint64(b)), nil
+	case "<f4":
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b))), nil
+	case "<i8":
+		return float64(int64(binary.LittleEndian.Uint64(b))), nil
+	case "<i4":
+		return float64(int32(binary.LittleEndian.Uint32(b))), nil
+	case "<i2":
+		return float64(int16(binary.LittleEndian.Uint16(b))), nil
+	case "<i1", "|i1":
+		return float64(int8(b[0])), nil
+	case "<u8":
+		return float64(binary.LittleEndian.Uint64(b)), nil
+	case "<u4":
+		return float64(binary.LittleEndian.Uint32(b)), nil
+	case "<u2":
+		return float64(binary.LittleEndian.Uint16(b)), nil
+	case "<u1", "|u1", "|b1":
+		return float64(b[0]), nil
+	}
+	return 0, model.NewModelError(model.ErrCodeValidation, "npy dtype is not numeric: "+dtype, nil)
+}
+
+// npyString 解码字符串元素, 去除尾部填充
+func npyString(dtype string, b []byte) (string, error) {
+	switch dtype[:2] {
+	case "|S":
+		return string(bytes.TrimRight(b, "\x00")), nil
+	case "<U":
+		var sb strings.Builder
+		for i := 0; i+4 <= len(b); i += 4 {
+			r := rune(binary.LittleEndian.Uint32(b[i:]))
+			if r == 0 {
+				break
+			}
+			sb.WriteRune(r)
+		}
+		return sb.String(), nil
+	}
+	return "", model.NewModelError(model.ErrCodeValidation, "npy dtype is not a string: "+dtype, nil)
+}
+
+// writeNPY 写出NumPy数组, descr为Python字面量形式的数据类型描述
+func writeNPY(w io.Writer, descr string, shape []int, data []byte) error {
+	dims := make([]string, len(shape))
+	for i, n := range shape {
+		dims[i] = strconv.Itoa(n)
+	}
+	shapeLiteral := "(" + strings.Join(dims, ", ")
+	if len(shape) == 1 {
+		shapeLiteral += ","
+	}
+	shapeLiteral += ")"
+
+	header := fmt.Sprintf("{'descr': %s, 'fortran_order': False, 'shape': %s, }", descr, shapeLiteral)
+
+	// 版本1.0头部长度为uint16, 超出时使用2.0
+	prefix := len(npyMagic) + 2 + 2
+	major := byte(1)
+	if len(header)+1+prefix+npyAlignment > math.MaxUint16 {
+		prefix += 2
+		major = 2
+	}
+	padding := npyAlignment - (prefix+len(header)+1)%npyAlignment
+	if padding == npyAlignment {
+		padding = 0
+	}
+	header += strings.Repeat(" ", padding) + "\n"
+
+	var buf bytes.Buffer
+	buf.WriteString(npyMagic)
+	buf.WriteByte(major)
+	buf.WriteByte(0)
+	if major == 1 {
+		binary.Write(&buf, binary.LittleEndian, uint16(len(header)))
+	} else {
+		binary.Write(&buf, binary.LittleEndian, uint32(len(header)))
+	}
+	buf.WriteString(header)
+	buf.Write(data)
+
+	if _, err := w.Write(buf.Bytes()); err != nil {
+		return model.WrapError(err, model.ErrCodeIO, "failed to write npy data")
+	}
+	return nil
+}
+
+// readNPY 读取C顺序的小端NumPy数组
+func readNPY(r io.Reader) (*npyArray, error) {
+	prefix := make([]byte, len(npyMagic)+2)
+	if _, err := io.ReadFull(r, prefix); err != nil {
+		return nil, model.WrapError(err, model.ErrCodeIO, "failed to read npy header")
+	}
+	if string(prefix[:len(npyMagic)]) != npyMagic {
+		return nil, model.NewModelError(model.ErrCodeValidation, "not an npy file", nil)
+	}
+
+	var headerLen int
+	switch prefix[len(npyMagic)] {
+	case 1:
+		var n uint16
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, model.WrapError(err, model.ErrCodeIO, "failed to read npy header")
+		}
+		headerLen = int(n)
+	case 2, 3:
+		var n uint32
+		if err := binary.Read(r, binary.LittleEndian, &n); err != nil {
+			return nil, model.WrapError(err, model.ErrCodeIO, "failed to read npy header")
+		}
+		headerLen = int(n)
+	default:
+		return nil, model.NewModelError(model.ErrCodeValidation,
+			fmt.Sprintf("unsupported npy version %d", prefix[len(npyMagic)]), nil)
+	}
+	if headerLen > npyMaxHeader {
+		return nil, model.NewModelError(model.ErrCodeLimit, "npy header too large", nil)
+	}
+
+	raw := make([]byte, headerLen)
+	if _, err := io.ReadFull(r, raw); err != nil {
+		return nil, model.WrapError(err, model.ErrCodeIO, "failed to read npy header")
+	}
+	if !utf8.Valid(raw) {
+		return nil, model.NewModelError(model.ErrCodeValidation, "invalid npy header encoding", nil)
+	}
+	arr, err := parseNPYHeader(string(raw))
+	if err != nil {
+		return nil, err
+	}
+
+	itemSize := 0
+	for _, f := range arr.fields {
+		n, err := npyItemSize(f.dtype)
+		if err != nil {
+			return nil, err
+		}
+		itemSize += n
+	}
+	count := 1
+	for _, n := range arr.shape {
+		count *= n
+	}
+
+	arr.data, err = io.ReadAll(r)
+	if err != nil {
+		return nil, model.WrapError(err, model.ErrCodeIO, "failed to read npy data")
+	}
+	if len(arr.data) != count*itemSize {
+		return nil, model.NewModelError(model.ErrCodeValidation,
+			fmt.Sprintf("npy data size %d does not match shape %v", len(arr.data), arr.shape), nil)
+	}
+	return arr, nil
+}
+
+// parseNPYHeader 解析头部字典
+func parseNPYHeader(header string) (*npyArray, error) {
+	arr := &npyArray{}
+
+	if m := npyDescrScalar.FindStringSubmatch(header); m != nil {
+		arr.fields = []npyField{{dtype: m[1]}}
+	} else if m := npyDescrRecord.FindStringSubmatch(header); m != nil {
+		arr.record = true
+		for _, f := range npyRecordField.FindAllStringSubmatch(m[1], -1) {
+			arr.fields = append(arr.fields, npyField{name: f[1], dtype: f[2]})
+		}
+		// 子数组等字段无法逐一匹配
+		if len(arr.fields) != strings.Count(m[1], "(") {
+			return nil, model.NewModelError(model.ErrCodeValidation, "unsupported npy record dtype", nil)
+		}
+	}
+	if len(arr.fields) == 0 {
+		return nil, model.NewModelError(model.ErrCodeValidation, "missing npy descr", nil)
+	}
+
+	if m := npyFortran.FindStringSubmatch(header); m == nil || m[1] != "False" {
+		return nil, model.NewModelError(model.ErrCodeValidation, "only C-ordered npy arrays are supported", nil)
+	}
+
+	m := npyShape.FindStringSubmatch(header)
+	if m == nil {
+		return nil, model.NewModelError(model.ErrCodeValidation, "missing npy shape", nil)
+	}
+	for _, dim := range strings.Split(m[1], ",") {
+		dim = strings.TrimSpace(dim)
+		if dim == "" {
+			continue
+		}
+		n, err := strconv.Atoi(strings.TrimSuffix(dim, "L"))
+		if err != nil || n < 0 {
+			return nil, model.NewModelError(model.ErrCodeValidation, "invalid npy shape: "+m[1], nil)
+		}
+		arr.shape = append(arr.shape, n)
+	}
+	return arr, nil
+}
+
+// npyQuote 将名称写为Python字符串字面量
+func npyQuote(name string) (string, error) {
+	if strings.ContainsAny(name, "'\\\n") {
+		return "", model.NewModelError(model.ErrCodeValidation, "name cannot be written to npy: "+name, nil)
+	}
+	return "'" + name + "'", nil
+}
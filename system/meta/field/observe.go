@@ -0,0 +1,88 @@
+//system/meta/field/observe.go
+
+package field
+
+import (
+	"math"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+)
+
+// DepositEnergy 向场中的空间点注入外部观测能量
+func (uf *UnifiedField) DepositEnergy(points map[core.Point]float64) {
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	if uf.state.Observed == nil {
+		uf.state.Observed = make(map[core.Point]float64)
+	}
+	for p, energy := range points {
+		value := math.Max(0, uf.state.Observed[p]+energy)
+		if value == 0 {
+			delete(uf.state.Observed, p)
+			continue
+		}
+		uf.state.Observed[p] = value
+	}
+}
+
+// AdjustElement 调整场元素能量和属性, 元素不存在时创建
+func (uf *UnifiedField) AdjustElement(elemType string, energyDelta float64, properties map[string]float64) {
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	var target *WuXingElement
+	for _, elem := range uf.WuXingElements {
+		if elem.Type == elemType {
+			target = elem
+			break
+		}
+	}
+	if target == nil {
+		target = &WuXingElement{
+			Type:       elemType,
+			Properties: make(map[string]float64),
+			History:    make([]model.WuXingElementState, 0),
+		}
+		target.Position.X = len(uf.WuXingElements) % 10
+		target.Position.Y = len(uf.WuXingElements) / 10
+		uf.WuXingElements = append(uf.WuXingElements, target)
+	}
+
+	target.Energy = math.Max(minWuXingElementEnergy, target.Energy+energyDelta)
+	if target.Properties == nil {
+		target.Properties = make(map[string]float64)
+	}
+	for name, value := range properties {
+		target.Properties[name] = value
+	}
+}
+
+// DecayObservations 按因子衰减外部观测能量, 低于阈值的点被移除
+func (uf *UnifiedField) DecayObservations(factor float64) {
+	if factor <= 0 || factor >= 1 {
+		return
+	}
+
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	const minObservedEnergy = 1e-6
+	for p, energy := range uf.state.Observed {
+		energy *= factor
+		if energy < minObservedEnergy {
+			delete(uf.state.Observed, p)
+			continue
+		}
+		uf.state.Observed[p] = energy
+	}
+}
+
+// GetObservedDistribution 获取外部观测能量分布副本, 包含活跃扰动
+func (uf *UnifiedField) GetObservedDistribution() map[core.Point]float64 {
+	uf.mu.RLock()
+	defer uf.mu.RUnlock()
+
+	return uf.observedDistribution()
+}
@@ -0,0 +1,325 @@
+// system/meta/field/perturb.go
+
+package field
+
+import (
+	"math"
+	"math/cmplx"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+)
+
+// 扰动状态
+const (
+	PerturbationScheduled = "scheduled" // 等待开始
+	PerturbationActive    = "active"    // 已施加
+	PerturbationReverted  = "reverted"  // 持续时间结束后已撤销
+	PerturbationCancelled = "cancelled" // 开始前或持续期间被取消
+)
+
+// 扰动参数限制
+const (
+	maxPerturbationRadius  = 16  // 区域半径上限, 限制单次扰动覆盖的格点数
+	maxPerturbationHistory = 256 // 保留的已结束扰动数
+)
+
+// Region 扰动的空间区域, 覆盖与中心欧氏距离不超过半径的格点
+type Region struct {
+	Center core.Point `json:"center"`
+	Radius int        `json:"radius"`
+}
+
+// Points 区域内的格点, 按坐标排序
+func (r Region) Points() []core.Point {
+	points := make([]core.Point, 0)
+	for dx := -r.Radius; dx <= r.Radius; dx++ {
+		for dy := -r.Radius; dy <= r.Radius; dy++ {
+			for dz := -r.Radius; dz <= r.Radius; dz++ {
+				if dx*dx+dy*dy+dz*dz > r.Radius*r.Radius {
+					continue
+				}
+				points = append(points, core.Point{X: r.Center.X + dx, Y: r.Center.Y + dy, Z: r.Center.Z + dz})
+			}
+		}
+	}
+	return points
+}
+
+// PerturbationSpec 扰动参数
+type PerturbationSpec struct {
+	Region      Region        `json:"region"`
+	DeltaEnergy float64       `json:"delta_energy"`    // 区域内注入的总能量, 平均分配到各格点; 为负时削弱观测能量
+	PhaseShift  float64       `json:"phase_shift"`     // 区域内量子场分量的相位偏移(弧度), 按格点的X、Y坐标选取分量
+	Duration    time.Duration `json:"duration"`        // 持续时间, 为0时持续到取消
+	Label       string        `json:"label,omitempty"` // 实验标签, 便于关联下游响应
+}
+
+// Perturbation 场扰动记录
+type Perturbation struct {
+	ID        string           `json:"id"`
+	Spec      PerturbationSpec `json:"spec"`
+	State     string           `json:"state"`
+	StartAt   time.Time        `json:"start_at"`             // 计划开始时间
+	AppliedAt time.Time        `json:"applied_at,omitempty"` // 实际施加时间
+	EndedAt   time.Time        `json:"ended_at,omitempty"`   // 撤销或取消时间
+}
+
+// perturbations 扰动状态, 由场锁保护
+type perturbations struct {
+	items     []*Perturbation
+	overlay   map[core.Point]float64 // 活跃扰动叠加在观测能量上的分布
+	listeners []func(Perturbation)
+}
+
+// PerturbField 立即在区域内注入能量扰动, duration后撤销
+func (uf *UnifiedField) PerturbField(region Region, deltaEnergy float64, duration time.Duration) (Perturbation, error) {
+	return uf.SchedulePerturbation(time.Now(), PerturbationSpec{
+		Region:      region,
+		DeltaEnergy: deltaEnergy,
+		Duration:    duration,
+	})
+}
+
+// SchedulePerturbation 计划在at时刻施加扰动
+// at不晚于当前时间时立即施加; 之后的扰动随场演化周期开始和撤销, 场未启动时不会开始
+func (uf *UnifiedField) SchedulePerturbation(at time.Time, spec PerturbationSpec) (Perturbation, error) {
+	if err := validatePerturbation(spec); err != nil {
+		return Perturbation{}, err
+	}
+
+	now := time.Now()
+	if at.IsZero() {
+		at = now
+	}
+	p := &Perturbation{
+		ID:      core.NewID("ptb_"),
+		Spec:    spec,
+		State:   PerturbationScheduled,
+		StartAt: at,
+	}
+
+	uf.mu.Lock()
+	uf.perturb.items = append(uf.perturb.items, p)
+	changed := []Perturbation{*p}
+	if !at.After(now) {
+		uf.applyPerturbation(p, now)
+		changed = append(changed, *p)
+	}
+	snapshot := *p
+	uf.mu.Unlock()
+
+	uf.notifyPerturbations(changed)
+	return snapshot, nil
+}
+
+// CancelPerturbation 取消扰动, 已施加的扰动立即撤销
+func (uf *UnifiedField) CancelPerturbation(id string) error {
+	uf.mu.Lock()
+	var target *Perturbation
+	for _, p := range uf.perturb.items {
+		if p.ID == id {
+			target = p
+			break
+		}
+	}
+	if target == nil {
+		uf.mu.Unlock()
+		return model.NewModelError(model.ErrCodeNotFound, "perturbation not found: "+id, nil)
+	}
+	if target.State != PerturbationScheduled && target.State != PerturbationActive {
+		uf.mu.Unlock()
+		return model.NewModelError(model.ErrCodeState, "perturbation already ended: "+id, nil)
+	}
+	uf.endPerturbation(target, PerturbationCancelled, time.Now())
+	snapshot := *target
+	uf.trimPerturbations()
+	uf.mu.Unlock()
+
+	uf.notifyPerturbations([]Perturbation{snapshot})
+	return nil
+}
+
+// Perturbations 获取扰动记录, 按计划开始时间排序
+func (uf *UnifiedField) Perturbations() []Perturbation {
+	uf.mu.RLock()
+	defer uf.mu.RUnlock()
+
+	result := make([]Perturbation, 0, len(uf.perturb.items))
+	for _, p := range uf.perturb.items {
+		result = append(result, *p)
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].StartAt.Before(result[j].StartAt)
+	})
+	return result
+}
+
+// OnPerturbation 注册扰动状态变化的监听器, 监听器在场锁外同步调用
+func (uf *UnifiedField) OnPerturbation(listener func(Perturbation)) {
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+	uf.perturb.listeners = append(uf.perturb.listeners, listener)
+}
+
+// stepPerturbations 施加到期的计划扰动并撤销超出持续时间的扰动, 返回状态变化的扰动(调用方持有锁)
+func (uf *UnifiedField) stepPerturbations(now time.Time) []Perturbation {
+	var changed []Perturbation
+	for _, p := range uf.perturb.items {
+		switch p.State {
+		case PerturbationScheduled:
+			if !p.StartAt.After(now) {
+				uf.applyPerturbation(p, now)
+				changed = append(changed, *p)
+			}
+		case PerturbationActive:
+			if p.Spec.Duration > 0 && !p.AppliedAt.Add(p.Spec.Duration).After(now) {
+				uf.endPerturbation(p, PerturbationReverted, now)
+				changed = append(changed, *p)
+			}
+		}
+	}
+	if len(changed) > 0 {
+		uf.trimPerturbations()
+	}
+	return changed
+}
+
+// applyPerturbation 施加扰动(调用方持有锁)
+func (uf *UnifiedField) applyPerturbation(p *Perturbation, now time.Time) {
+	uf.shiftPerturbation(p.Spec, 1)
+	p.State = PerturbationActive
+	p.AppliedAt = now
+}
+
+// endPerturbation 结束扰动, 已施加的扰动按相反方向撤销(调用方持有锁)
+func (uf *UnifiedField) endPerturbation(p *Perturbation, state string, now time.Time) {
+	if p.State == PerturbationActive {
+		uf.shiftPerturbation(p.Spec, -1)
+	}
+	p.State = state
+	p.EndedAt = now
+}
+
+// shiftPerturbation 按方向叠加扰动的能量和相位, direction为1时施加, 为-1时撤销(调用方持有锁)
+func (uf *UnifiedField) shiftPerturbation(spec PerturbationSpec, direction float64) {
+	if spec.DeltaEnergy != 0 {
+		if uf.perturb.overlay == nil {
+			uf.perturb.overlay = make(map[core.Point]float64)
+		}
+		points := spec.Region.Points()
+		share := direction * spec.DeltaEnergy / float64(len(points))
+		for _, point := range points {
+			value := uf.perturb.overlay[point] + share
+			// 撤销后的残差视为0, 避免浮点误差累积
+			if math.Abs(value) < 1e-12 {
+				delete(uf.perturb.overlay, point)
+				continue
+			}
+			uf.perturb.overlay[point] = value
+		}
+	}
+
+	if spec.PhaseShift != 0 && uf.components.quantum != nil {
+		rotation := cmplx.Exp(complex(0, direction*spec.PhaseShift))
+		seen := make(map[[2]int]bool)
+		for _, point := range spec.Region.Points() {
+			index := [2]int{point.X, point.Y}
+			if !seen[index] {
+				seen[index] = true
+				uf.components.quantum.rotate(point.X, point.Y, rotation)
+			}
+		}
+	}
+}
+
+// rotate 将(i, j)处各分量乘以单位复数以偏移相位, 索引超出范围时忽略
+func (ft *FieldTensor) rotate(i, j int, rotation complex128) {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if i < 0 || j < 0 || i >= ft.dimension || j >= ft.dimension {
+		return
+	}
+	for k := 0; k < ft.dimension; k++ {
+		if value := ft.at(i, j, k); value != 0 {
+			ft.put(i, j, k, value*rotation)
+		}
+	}
+}
+
+// trimPerturbations 已结束的扰动超出上限时按记录顺序丢弃较早的(调用方持有锁)
+func (uf *UnifiedField) trimPerturbations() {
+	ended := 0
+	for _, p := range uf.perturb.items {
+		if p.State == PerturbationReverted || p.State == PerturbationCancelled {
+			ended++
+		}
+	}
+	if ended <= maxPerturbationHistory {
+		return
+	}
+
+	drop := ended - maxPerturbationHistory
+	kept := uf.perturb.items[:0]
+	for _, p := range uf.perturb.items {
+		if drop > 0 && (p.State == PerturbationReverted || p.State == PerturbationCancelled) {
+			drop--
+			continue
+		}
+		kept = append(kept, p)
+	}
+	uf.perturb.items = kept
+}
+
+// observedDistribution 观测能量叠加活跃扰动后的分布, 能量不为负(调用方持有锁)
+func (uf *UnifiedField) observedDistribution() map[core.Point]float64 {
+	distribution := make(map[core.Point]float64, len(uf.state.Observed)+len(uf.perturb.overlay))
+	for p, energy := range uf.state.Observed {
+		distribution[p] = energy
+	}
+	for p, delta := range uf.perturb.overlay {
+		energy := distribution[p] + delta
+		if energy <= 0 {
+			delete(distribution, p)
+			continue
+		}
+		distribution[p] = energy
+	}
+	return distribution
+}
+
+// notifyPerturbations 通知扰动状态变化
+func (uf *UnifiedField) notifyPerturbations(changed []Perturbation) {
+	if len(changed) == 0 {
+		return
+	}
+	uf.mu.RLock()
+	listeners := append(([]func(Perturbation))(nil), uf.perturb.listeners...)
+	uf.mu.RUnlock()
+
+	for _, p := range changed {
+		for _, listener := range listeners {
+			listener(p)
+		}
+	}
+}
+
+// validatePerturbation 校验扰动参数
+func validatePerturbation(spec PerturbationSpec) error {
+	switch {
+	case spec.Region.Radius < 0 || spec.Region.Radius > maxPerturbationRadius:
+		return model.NewModelError(model.ErrCodeValidation, "perturbation radius out of range", nil)
+	case math.IsNaN(spec.DeltaEnergy) || math.IsInf(spec.DeltaEnergy, 0):
+		return model.NewModelError(model.ErrCodeValidation, "invalid perturbation energy", nil)
+	case math.IsNaN(spec.PhaseShift) || math.IsInf(spec.PhaseShift, 0):
+		return model.NewModelError(model.ErrCodeValidation, "invalid perturbation phase shift", nil)
+	case spec.Duration < 0:
+		return model.NewModelError(model.ErrCodeValidation, "negative perturbation duration", nil)
+	case spec.DeltaEnergy == 0 && spec.PhaseShift == 0:
+		return model.NewModelError(model.ErrCodeValidation, "perturbation has no energy or phase change", nil)
+	}
+	return nil
+}
@@ -0,0 +1,436 @@
+// system/meta/field/sparse.go
+
+package field
+
+import (
+	"sort"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// TensorRepresentation 场张量的存储表示
+type TensorRepresentation string
+
+const (
+	RepresentationDense  TensorRepresentation = "dense"  // 稠密表示, 存储全部分量
+	RepresentationSparse TensorRepresentation = "sparse" // 稀疏表示, 只存储非零分量
+)
+
+// 每个分量的存储开销, 稀疏存储含键和哈希表的近似开销
+const (
+	denseEntryBytes     = 16
+	denseGradientBytes  = 8
+	sparseEntryBytes    = 48
+	sparseGradientBytes = 32
+)
+
+// sparseStorage 稀疏存储, 以扁平索引(i*d+j)*d+k为键, 零值不占存储
+type sparseStorage struct {
+	entries  map[int]complex128
+	gradient map[int]float64 // 以i*d+j为键
+}
+
+// TensorStats 场张量存储统计
+type TensorStats struct {
+	Representation TensorRepresentation `json:"representation"`
+	Dimension      int                  `json:"dimension"`
+	Rank           int                  `json:"rank"`
+	NonZeros       int                  `json:"non_zeros"` // 非零分量数
+	Density        float64              `json:"density"`   // 非零分量占全部分量的比例
+	Bytes          int                  `json:"bytes"`     // 分量和梯度存储的近似字节数
+}
+
+// COOTensor 坐标格式(COO)的场张量, 分量按扁平索引升序排列
+type COOTensor struct {
+	Dimension int          `json:"dimension"`
+	Rank      int          `json:"rank"`
+	Indices   [][3]int     `json:"indices"`
+	Values    []complex128 `json:"values"`
+}
+
+// CSRMatrix 压缩行格式(CSR)的张量主平面
+type CSRMatrix struct {
+	Dimension int          `json:"dimension"`
+	RowPtr    []int        `json:"row_ptr"`   // 长度Dimension+1, 第i行的分量位于[RowPtr[i], RowPtr[i+1])
+	ColIndex  []int        `json:"col_index"` // 行内升序
+	Values    []complex128 `json:"values"`
+}
+
+// -----------------------------------------
+// NewSparseFieldTensor 创建稀疏表示的场张量
+func NewSparseFieldTensor(dimension, rank int) *FieldTensor {
+	ft := &FieldTensor{
+		dimension: dimension,
+		rank:      rank,
+		sparse:    newSparseStorage(),
+	}
+	ft.initProperties()
+	return ft
+}
+
+// NewFieldTensorFromCOO 由COO格式创建场张量
+func NewFieldTensorFromCOO(coo COOTensor, representation TensorRepresentation) (*FieldTensor, error) {
+	if len(coo.Indices) != len(coo.Values) {
+		return nil, model.NewModelError(model.ErrCodeValidation, "coo indices and values length mismatch", nil)
+	}
+	if coo.Dimension <= 0 {
+		return nil, model.NewModelError(model.ErrCodeValidation, "invalid tensor dimension", nil)
+	}
+
+	var ft *FieldTensor
+	switch representation {
+	case RepresentationSparse:
+		ft = NewSparseFieldTensor(coo.Dimension, coo.Rank)
+	case RepresentationDense, "":
+		ft = NewFieldTensor(coo.Dimension, coo.Rank)
+	default:
+		return nil, model.NewModelError(model.ErrCodeValidation, "unknown tensor representation", nil)
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	indices := make([]int, 2)
+	for n, idx := range coo.Indices {
+		for _, v := range idx {
+			if v < 0 || v >= coo.Dimension {
+				return nil, model.NewModelError(model.ErrCodeValidation, "index out of range", nil)
+			}
+		}
+		ft.put(idx[0], idx[1], idx[2], coo.Values[n])
+		if idx[2] == 0 {
+			indices[0], indices[1] = idx[0], idx[1]
+			ft.updateGradient(indices, coo.Values[n])
+		}
+	}
+	return ft, nil
+}
+
+// newSparseStorage 创建空的稀疏存储
+func newSparseStorage() *sparseStorage {
+	return &sparseStorage{
+		entries:  make(map[int]complex128),
+		gradient: make(map[int]float64),
+	}
+}
+
+// Representation 获取存储表示
+func (ft *FieldTensor) Representation() TensorRepresentation {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+	if ft.sparse != nil {
+		return RepresentationSparse
+	}
+	return RepresentationDense
+}
+
+// SetRepresentation 就地转换存储表示, 分量和梯度保持不变
+func (ft *FieldTensor) SetRepresentation(representation TensorRepresentation) error {
+	switch representation {
+	case RepresentationSparse:
+		ft.ToSparse()
+	case RepresentationDense:
+		ft.ToDense()
+	default:
+		return model.NewModelError(model.ErrCodeValidation, "unknown tensor representation", nil)
+	}
+	return nil
+}
+
+// ToSparse 就地转换为稀疏表示
+func (ft *FieldTensor) ToSparse() {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if ft.sparse != nil {
+		return
+	}
+
+	storage := newSparseStorage()
+	d := ft.dimension
+	for i := range ft.data {
+		for j := range ft.data[i] {
+			for k, value := range ft.data[i][j] {
+				if value != 0 {
+					storage.entries[(i*d+j)*d+k] = value
+				}
+			}
+			if g := ft.gradient[i][j]; g != 0 {
+				storage.gradient[i*d+j] = g
+			}
+		}
+	}
+
+	ft.sparse = storage
+	ft.data = nil
+	ft.gradient = nil
+}
+
+// ToDense 就地转换为稠密表示
+func (ft *FieldTensor) ToDense() {
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	if ft.sparse == nil {
+		return
+	}
+
+	storage := ft.sparse
+	ft.sparse = nil
+	ft.allocDenseLocked()
+
+	d := ft.dimension
+	for key, value := range storage.entries {
+		ft.data[key/(d*d)][key/d%d][key%d] = value
+	}
+	for key, g := range storage.gradient {
+		ft.gradient[key/d][key%d] = g
+	}
+}
+
+// Stats 获取存储统计
+func (ft *FieldTensor) Stats() TensorStats {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+
+	d := ft.dimension
+	stats := TensorStats{
+		Representation: RepresentationDense,
+		Dimension:      d,
+		Rank:           ft.rank,
+	}
+
+	if ft.sparse != nil {
+		stats.Representation = RepresentationSparse
+		stats.NonZeros = len(ft.sparse.entries)
+		stats.Bytes = len(ft.sparse.entries)*sparseEntryBytes + len(ft.sparse.gradient)*sparseGradientBytes
+	} else {
+		for i := range ft.data {
+			for j := range ft.data[i] {
+				for _, value := range ft.data[i][j] {
+					if value != 0 {
+						stats.NonZeros++
+					}
+				}
+			}
+		}
+		stats.Bytes = d*d*d*denseEntryBytes + d*d*denseGradientBytes
+	}
+
+	if total := d * d * d; total > 0 {
+		stats.Density = float64(stats.NonZeros) / float64(total)
+	}
+	return stats
+}
+
+// ToCOO 导出为COO格式
+func (ft *FieldTensor) ToCOO() COOTensor {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+
+	d := ft.dimension
+	coo := COOTensor{Dimension: d, Rank: ft.rank}
+
+	// 稠密表示按(i,j,k)顺序遍历即为扁平索引升序
+	if ft.sparse == nil {
+		for i := range ft.data {
+			for j := range ft.data[i] {
+				for k, value := range ft.data[i][j] {
+					if value != 0 {
+						coo.Indices = append(coo.Indices, [3]int{i, j, k})
+						coo.Values = append(coo.Values, value)
+					}
+				}
+			}
+		}
+		return coo
+	}
+
+	keys := make([]int, 0, len(ft.sparse.entries))
+	for key := range ft.sparse.entries {
+		keys = append(keys, key)
+	}
+	sort.Ints(keys)
+	coo.Indices = make([][3]int, len(keys))
+	coo.Values = make([]complex128, len(keys))
+	for n, key := range keys {
+		coo.Indices[n] = [3]int{key / (d * d), key / d % d, key % d}
+		coo.Values[n] = ft.sparse.entries[key]
+	}
+	return coo
+}
+
+// PlaneCSR 导出张量主平面为CSR格式
+func (ft *FieldTensor) PlaneCSR() *CSRMatrix {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+
+	d := ft.dimension
+	rows := make([][]int, d)
+	values := make(map[int]complex128)
+	ft.eachPlaneLocked(func(i, j int, value complex128) {
+		if value != 0 {
+			rows[i] = append(rows[i], j)
+			values[i*d+j] = value
+		}
+	})
+
+	csr := &CSRMatrix{
+		Dimension: d,
+		RowPtr:    make([]int, d+1),
+		ColIndex:  make([]int, 0, len(values)),
+		Values:    make([]complex128, 0, len(values)),
+	}
+	for i, cols := range rows {
+		sort.Ints(cols)
+		for _, j := range cols {
+			csr.ColIndex = append(csr.ColIndex, j)
+			csr.Values = append(csr.Values, values[i*d+j])
+		}
+		csr.RowPtr[i+1] = len(csr.ColIndex)
+	}
+	return csr
+}
+
+// At 获取分量, 未存储的分量为0
+func (m *CSRMatrix) At(i, j int) complex128 {
+	if i < 0 || i >= m.Dimension {
+		return 0
+	}
+	cols := m.ColIndex[m.RowPtr[i]:m.RowPtr[i+1]]
+	if n := sort.SearchInts(cols, j); n < len(cols) && cols[n] == j {
+		return m.Values[m.RowPtr[i]+n]
+	}
+	return 0
+}
+
+// NonZeros 非零分量数
+func (m *CSRMatrix) NonZeros() int {
+	return len(m.Values)
+}
+
+// MulVec 计算矩阵与向量的乘积, 计算量与非零分量数成正比
+func (m *CSRMatrix) MulVec(x []complex128) ([]complex128, error) {
+	if len(x) != m.Dimension {
+		return nil, model.NewModelError(model.ErrCodeValidation, "vector length mismatch", nil)
+	}
+	y := make([]complex128, m.Dimension)
+	for i := 0; i < m.Dimension; i++ {
+		var sum complex128
+		for n := m.RowPtr[i]; n < m.RowPtr[i+1]; n++ {
+			sum += m.Values[n] * x[m.ColIndex[n]]
+		}
+		y[i] = sum
+	}
+	return y, nil
+}
+
+// at 读取分量, 调用方须持有锁
+func (ft *FieldTensor) at(i, j, k int) complex128 {
+	if ft.sparse != nil {
+		return ft.sparse.entries[(i*ft.dimension+j)*ft.dimension+k]
+	}
+	return ft.data[i][j][k]
+}
+
+// put 写入分量, 稀疏表示下零值不占存储, 调用方须持有锁
+func (ft *FieldTensor) put(i, j, k int, value complex128) {
+	if ft.sparse != nil {
+		key := (i*ft.dimension+j)*ft.dimension + k
+		if value == 0 {
+			delete(ft.sparse.entries, key)
+		} else {
+			ft.sparse.entries[key] = value
+		}
+		return
+	}
+	ft.data[i][j][k] = value
+}
+
+// setGradient 写入梯度, 调用方须持有锁
+func (ft *FieldTensor) setGradient(i, j int, g float64) {
+	if ft.sparse != nil {
+		key := i*ft.dimension + j
+		if g == 0 {
+			delete(ft.sparse.gradient, key)
+		} else {
+			ft.sparse.gradient[key] = g
+		}
+		return
+	}
+	ft.gradient[i][j] = g
+}
+
+// eachPlaneLocked 遍历张量主平面的分量, 稀疏表示只遍历非零分量, 调用方须持有锁
+func (ft *FieldTensor) eachPlaneLocked(fn func(i, j int, value complex128)) {
+	d := ft.dimension
+	if ft.sparse != nil {
+		for key, value := range ft.sparse.entries {
+			if key%d == 0 {
+				fn(key/(d*d), key/d%d, value)
+			}
+		}
+		return
+	}
+	for i := 0; i < d; i++ {
+		for j := 0; j < d; j++ {
+			fn(i, j, ft.data[i][j][0])
+		}
+	}
+}
+
+// planeEntries 获取张量主平面的非零分量, 以i*d+j为键
+func (ft *FieldTensor) planeEntries() map[int]complex128 {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+
+	entries := make(map[int]complex128)
+	ft.eachPlaneLocked(func(i, j int, value complex128) {
+		if value != 0 {
+			entries[i*ft.dimension+j] = value
+		}
+	})
+	return entries
+}
+
+// sparsePlane 稀疏表示的二阶张量主平面的非零分量, 其他情况返回false
+// 场演化中逐分量读取的算子以二维索引访问, 只对二阶张量有值
+func sparsePlane(field *FieldTensor) (map[int]complex128, bool) {
+	if field.rank != 2 || field.Representation() != RepresentationSparse {
+		return nil, false
+	}
+	return field.planeEntries(), true
+}
+
+// SetComponentRepresentation 设置场组件的存储表示, 组件重建后保持
+func (uf *UnifiedField) SetComponentRepresentation(name string, representation TensorRepresentation) error {
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	component := uf.component(name)
+	if component == nil {
+		return model.NewModelError(model.ErrCodeNotFound, "unknown field component: "+name, nil)
+	}
+	if err := component.SetRepresentation(representation); err != nil {
+		return err
+	}
+	if uf.representations == nil {
+		uf.representations = make(map[string]TensorRepresentation)
+	}
+	uf.representations[name] = representation
+	return nil
+}
+
+// ComponentStats 获取各场组件的存储统计
+func (uf *UnifiedField) ComponentStats() map[string]TensorStats {
+	uf.mu.RLock()
+	defer uf.mu.RUnlock()
+
+	stats := make(map[string]TensorStats)
+	for _, name := range []string{ComponentScalar, ComponentVector, ComponentMetric, ComponentQuantum} {
+		if component := uf.component(name); component != nil {
+			stats[name] = component.Stats()
+		}
+	}
+	return stats
+}
@@ -0,0 +1,161 @@
+// system/meta/field/sparse_test.go
+
+package field
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// 稀疏负载: 主平面中只有少量活跃区域的分量非零
+const benchDimension = 128
+
+var benchDensity = 0.01
+
+// newBenchTensors 创建内容相同的稠密和稀疏张量
+func newBenchTensors(b *testing.B, dimension int, density float64, seed int64) (*FieldTensor, *FieldTensor) {
+	b.Helper()
+	dense := NewFieldTensor(dimension, 2)
+	sparse := NewSparseFieldTensor(dimension, 2)
+	rng := rand.New(rand.NewSource(seed))
+	count := int(float64(dimension*dimension) * density)
+	for n := 0; n < count; n++ {
+		indices := []int{rng.Intn(dimension), rng.Intn(dimension)}
+		value := complex(rng.Float64(), rng.Float64())
+		if err := dense.SetComponent(indices, value); err != nil {
+			b.Fatal(err)
+		}
+		if err := sparse.SetComponent(indices, value); err != nil {
+			b.Fatal(err)
+		}
+	}
+	return dense, sparse
+}
+
+// benchTensor 按表示选择基准测试张量
+func benchTensor(dense, sparse *FieldTensor, representation TensorRepresentation) *FieldTensor {
+	if representation == RepresentationSparse {
+		return sparse
+	}
+	return dense
+}
+
+var benchRepresentations = []TensorRepresentation{RepresentationDense, RepresentationSparse}
+
+func BenchmarkFieldTensorNorm(b *testing.B) {
+	dense, sparse := newBenchTensors(b, benchDimension, benchDensity, 1)
+	for _, representation := range benchRepresentations {
+		ft := benchTensor(dense, sparse, representation)
+		b.Run(string(representation), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				ft.Norm()
+			}
+		})
+	}
+}
+
+func BenchmarkFieldTensorDistance(b *testing.B) {
+	dense1, sparse1 := newBenchTensors(b, benchDimension, benchDensity, 1)
+	dense2, sparse2 := newBenchTensors(b, benchDimension, benchDensity, 2)
+	for _, representation := range benchRepresentations {
+		x := benchTensor(dense1, sparse1, representation)
+		y := benchTensor(dense2, sparse2, representation)
+		b.Run(string(representation), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := x.Distance(y); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFieldTensorAddPlane(b *testing.B) {
+	// 增量同样稀疏, 只触及少量分量
+	delta := make([]complex128, benchDimension*benchDimension)
+	rng := rand.New(rand.NewSource(3))
+	for n := 0; n < int(float64(len(delta))*benchDensity); n++ {
+		delta[rng.Intn(len(delta))] = complex(rng.Float64(), 0)
+	}
+	for _, representation := range benchRepresentations {
+		b.Run(string(representation), func(b *testing.B) {
+			dense, sparse := newBenchTensors(b, benchDimension, benchDensity, 1)
+			ft := benchTensor(dense, sparse, representation)
+			b.ReportAllocs()
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				if err := ft.AddPlane(1e-6, delta); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFieldTensorSetComponent(b *testing.B) {
+	for _, representation := range benchRepresentations {
+		b.Run(string(representation), func(b *testing.B) {
+			rng := rand.New(rand.NewSource(4))
+			indices := make([]int, 2)
+			count := int(float64(benchDimension*benchDimension) * benchDensity)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				var ft *FieldTensor
+				if representation == RepresentationSparse {
+					ft = NewSparseFieldTensor(benchDimension, 2)
+				} else {
+					ft = NewFieldTensor(benchDimension, 2)
+				}
+				for n := 0; n < count; n++ {
+					indices[0], indices[1] = rng.Intn(benchDimension), rng.Intn(benchDimension)
+					if err := ft.SetComponent(indices, complex(rng.Float64(), 0)); err != nil {
+						b.Fatal(err)
+					}
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkFieldTensorStorage(b *testing.B) {
+	for _, density := range []float64{0.001, 0.01, 0.1} {
+		dense, sparse := newBenchTensors(b, benchDimension, density, 5)
+		b.Run(fmt.Sprintf("density=%g", density), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				sparse.Stats()
+			}
+			b.ReportMetric(float64(dense.Stats().Bytes), "dense-bytes")
+			b.ReportMetric(float64(sparse.Stats().Bytes), "sparse-bytes")
+		})
+	}
+}
+
+// 稀疏与稠密表示的计算结果一致
+func TestSparseDenseParity(t *testing.T) {
+	dense := NewFieldTensor(16, 2)
+	sparse := NewSparseFieldTensor(16, 2)
+	rng := rand.New(rand.NewSource(6))
+	for n := 0; n < 20; n++ {
+		indices := []int{rng.Intn(16), rng.Intn(16)}
+		value := complex(rng.Float64(), rng.Float64())
+		if err := dense.SetComponent(indices, value); err != nil {
+			t.Fatal(err)
+		}
+		if err := sparse.SetComponent(indices, value); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if got, want := sparse.Norm(), dense.Norm(); !parityClose(got, want) {
+		t.Fatalf("Norm: sparse=%v dense=%v", got, want)
+	}
+	d, err := sparse.Distance(dense)
+	if err != nil || d > backendParityTolerance {
+		t.Fatalf("Distance(sparse, dense) = %v, %v", d, err)
+	}
+	if _, err := NewFieldTensorFromCOO(COOTensor{Dimension: 16, Rank: 2, Indices: [][3]int{{0, 0, 0}}}, RepresentationSparse); err == nil {
+		t.Fatal("expected length mismatch error")
+	}
+}
@@ -19,8 +19,9 @@ type FieldTensor struct {
 	// 基础属性
 	dimension int              // 张量维度
 	rank      int              // 张量阶数
-	data      [][][]complex128 // 张量数据
-	gradient  [][]float64      // 梯度数据
+	data      [][][]complex128 // 张量数据, 稀疏表示时为nil
+	gradient  [][]float64      // 梯度数据, 稀疏表示时为nil
+	sparse    *sparseStorage   // 稀疏存储, 稠密表示时为nil
 
 	// 场特性
 	properties struct {
@@ -72,7 +73,11 @@ func NewFieldTensor(dimension, rank int) *FieldTensor {
 func (ft *FieldTensor) initTensorData() {
 	ft.mu.Lock()
 	defer ft.mu.Unlock()
+	ft.allocDenseLocked()
+}
 
+// allocDenseLocked 分配稠密存储, 调用方须持有锁
+func (ft *FieldTensor) allocDenseLocked() {
 	// 初始化主张量数据为复数类型
 	ft.data = make([][][]complex128, ft.dimension)
 	for i := range ft.data {
@@ -118,9 +123,9 @@ func (ft *FieldTensor) SetComponent(indices []int, value complex128) error {
 	// 设置分量值
 	switch ft.rank {
 	case 2:
-		ft.data[indices[0]][indices[1]][0] = value
+		ft.put(indices[0], indices[1], 0, value)
 	case 3:
-		ft.data[indices[0]][indices[1]][indices[2]] = value
+		ft.put(indices[0], indices[1], indices[2], value)
 	default:
 		return model.WrapError(nil, model.ErrCodeValidation, "unsupported tensor rank")
 	}
@@ -150,9 +155,9 @@ func (ft *FieldTensor) GetComponent(indices []int) (complex128, error) {
 	// 获取分量值
 	switch ft.rank {
 	case 2:
-		return ft.data[indices[0]][indices[1]][0], nil
+		return ft.at(indices[0], indices[1], 0), nil
 	case 3:
-		return ft.data[indices[0]][indices[1]][indices[2]], nil
+		return ft.at(indices[0], indices[1], indices[2]), nil
 	default:
 		return 0, model.WrapError(nil, model.ErrCodeValidation, "unsupported tensor rank")
 	}
@@ -168,13 +173,13 @@ func (ft *FieldTensor) CalculateInvariants() []float64 {
 	// 计算第一不变量 (迹)
 	var trace complex128
 	for i := 0; i < ft.dimension; i++ {
-		trace += ft.data[i][i][0]
+		trace += ft.at(i, i, 0)
 	}
 	invariants = append(invariants, cmplx.Abs(trace))
 
 	// 计算第二不变量 (行列式)
 	if ft.rank == 2 {
-		det := calculateComplexDeterminant(ft.data)
+		det := calculateComplexDeterminant(ft.planeMatrixLocked())
 		invariants = append(invariants, cmplx.Abs(det))
 	}
 
@@ -183,20 +188,20 @@ func (ft *FieldTensor) CalculateInvariants() []float64 {
 }
 
 // calculateComplexDeterminant 计算复数矩阵行列式
-func calculateComplexDeterminant(data [][][]complex128) complex128 {
+func calculateComplexDeterminant(data [][]complex128) complex128 {
 	n := len(data)
 	if n == 2 {
-		return data[0][0][0]*data[1][1][0] - data[0][1][0]*data[1][0][0]
+		return data[0][0]*data[1][1] - data[0][1]*data[1][0]
 	}
 	var det complex128
 	for i := 0; i < n; i++ {
-		det += data[0][i][0] * calculateComplexCofactor(data, 0, i)
+		det += data[0][i] * calculateComplexCofactor(data, 0, i)
 	}
 	return det
 }
 
 // calculateComplexCofactor 计算复数矩阵余子式
-func calculateComplexCofactor(data [][][]complex128, row, col int) complex128 {
+func calculateComplexCofactor(data [][]complex128, row, col int) complex128 {
 	n := len(data)
 	minor := make([][]complex128, n-1)
 	for i := range minor {
@@ -214,7 +219,7 @@ func calculateComplexCofactor(data [][][]complex128, row, col int) complex128 {
 			if j == col {
 				continue
 			}
-			minor[mi][mj] = data[i][j][0]
+			minor[mi][mj] = data[i][j]
 			mj++
 		}
 		mi++
@@ -289,16 +294,16 @@ func (ft *FieldTensor) AnalyzeSymmetry() string {
 	symmetric := true
 	antisymmetric := true
 
-	for i := 0; i < ft.dimension; i++ {
-		for j := 0; j < ft.dimension; j++ {
-			if ft.data[i][j][0] != ft.data[j][i][0] {
-				symmetric = false
-			}
-			if ft.data[i][j][0] != -ft.data[j][i][0] {
-				antisymmetric = false
-			}
+	// 零分量对两种对称性均成立, 只需检查非零分量
+	ft.eachPlaneLocked(func(i, j int, value complex128) {
+		mirror := ft.at(j, i, 0)
+		if value != mirror {
+			symmetric = false
 		}
-	}
+		if value != -mirror {
+			antisymmetric = false
+		}
+	})
 
 	if symmetric {
 		ft.properties.symmetry = "symmetric"
@@ -321,7 +326,7 @@ func (ft *FieldTensor) DetectSingularities() map[string]Vector {
 	// 检测零点
 	for i := 0; i < ft.dimension; i++ {
 		for j := 0; j < ft.dimension; j++ {
-			magnitude := cmplx.Abs(ft.data[i][j][0]) // 使用cmplx.Abs
+			magnitude := cmplx.Abs(ft.at(i, j, 0)) // 使用cmplx.Abs
 			if magnitude < 1e-10 {
 				pos := Vector{
 					Components: []float64{float64(i), float64(j)},
@@ -384,16 +389,16 @@ func (ft *FieldTensor) updateGradient(indices []int, value complex128) {
 	// 更新相应位置的梯度
 	i, j := indices[0], indices[1]
 	if i > 0 {
-		ft.gradient[i][j] = magnitude - cmplx.Abs(ft.data[i-1][j][0])
+		ft.setGradient(i, j, magnitude-cmplx.Abs(ft.at(i-1, j, 0)))
 	}
 	if i < ft.dimension-1 {
-		ft.gradient[i][j] = magnitude - cmplx.Abs(ft.data[i+1][j][0])
+		ft.setGradient(i, j, magnitude-cmplx.Abs(ft.at(i+1, j, 0)))
 	}
 	if j > 0 {
-		ft.gradient[i][j] = magnitude - cmplx.Abs(ft.data[i][j-1][0])
+		ft.setGradient(i, j, magnitude-cmplx.Abs(ft.at(i, j-1, 0)))
 	}
 	if j < ft.dimension-1 {
-		ft.gradient[i][j] = magnitude - cmplx.Abs(ft.data[i][j+1][0])
+		ft.setGradient(i, j, magnitude-cmplx.Abs(ft.at(i, j+1, 0)))
 	}
 }
 
@@ -457,6 +462,19 @@ func calculateEnergyMomentumTensor(field *FieldTensor) [][]float64 {
 		energyMomentum[i] = make([]float64, dimension)
 	}
 
+	// 稀疏表示只计算非零分量
+	if entries, ok := sparsePlane(field); ok {
+		for key, value := range entries {
+			i, j := key/dimension, key%dimension
+			magnitude := cmplx.Abs(value)
+			energyMomentum[i][j] = magnitude * magnitude
+			if i == j {
+				energyMomentum[i][j] *= 0.25
+			}
+		}
+		return energyMomentum
+	}
+
 	// 获取场强度作为能量密度
 	for i := 0; i < dimension; i++ {
 		for j := 0; j < dimension; j++ {
@@ -505,6 +523,25 @@ func calculateLaplacian(field *FieldTensor) [][]float64 {
 		laplacian[i] = make([]float64, dimension)
 	}
 
+	// 稀疏表示只计算非零分量及其相邻点, 其余内点的拉普拉斯值为0
+	if entries, ok := sparsePlane(field); ok {
+		visited := make(map[int]bool)
+		for key := range entries {
+			ci, cj := key/dimension, key%dimension
+			for _, d := range [][2]int{{0, 0}, {-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				i, j := ci+d[0], cj+d[1]
+				if i < 1 || i >= dimension-1 || j < 1 || j >= dimension-1 || visited[i*dimension+j] {
+					continue
+				}
+				visited[i*dimension+j] = true
+				sum := entries[(i-1)*dimension+j] + entries[(i+1)*dimension+j] +
+					entries[i*dimension+j-1] + entries[i*dimension+j+1] - 4*entries[i*dimension+j]
+				laplacian[i][j] = cmplx.Abs(sum)
+			}
+		}
+		return laplacian
+	}
+
 	// 计算二阶偏导数
 	for i := 1; i < dimension-1; i++ {
 		for j := 1; j < dimension-1; j++ {
@@ -619,6 +656,15 @@ func calculatePotential(field *FieldTensor) [][]float64 {
 		potential[i] = make([]float64, dimension)
 	}
 
+	// 稀疏表示只计算非零分量
+	if entries, ok := sparsePlane(field); ok {
+		for key, value := range entries {
+			i, j := key/dimension, key%dimension
+			potential[i][j] = 0.5 * cmplx.Abs(value) * float64(i*i+j*j)
+		}
+		return potential
+	}
+
 	// 计算势能分布
 	for i := 0; i < dimension; i++ {
 		for j := 0; j < dimension; j++ {
@@ -639,13 +685,121 @@ func (ft *FieldTensor) GetMagnitude() float64 {
 	defer ft.mu.RUnlock()
 
 	var sum float64
+	ft.eachPlaneLocked(func(_, _ int, value complex128) {
+		sum += real(value * complex128(value))
+	})
+	return math.Sqrt(sum)
+}
+
+// Plane 获取张量主平面(各data[i][j][0]分量)的行优先副本
+func (ft *FieldTensor) Plane() []complex128 {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+	return ft.planeLocked()
+}
+
+// Norm 计算张量主平面的范数, 由当前计算后端执行
+func (ft *FieldTensor) Norm() float64 {
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
+
+	if ft.sparse != nil {
+		values := make([]complex128, 0, len(ft.sparse.entries))
+		ft.eachPlaneLocked(func(_, _ int, value complex128) {
+			values = append(values, value)
+		})
+		return math.Sqrt(currentBackend().Norm2(values))
+	}
+	return math.Sqrt(currentBackend().Norm2(ft.planeLocked()))
+}
+
+// Distance 计算两张量主平面间的距离, 由当前计算后端执行
+// 任一张量为稀疏表示时只对非零分量的并集计算
+func (ft *FieldTensor) Distance(other *FieldTensor) (float64, error) {
+	if ft.dimension != other.dimension {
+		return 0, model.NewModelError(model.ErrCodeValidation, "tensor dimension mismatch", nil)
+	}
+	if ft.Representation() == RepresentationDense && other.Representation() == RepresentationDense {
+		return math.Sqrt(currentBackend().Dist2(ft.Plane(), other.Plane())), nil
+	}
+
+	a, b := ft.planeEntries(), other.planeEntries()
+	x := make([]complex128, 0, len(a)+len(b))
+	y := make([]complex128, 0, len(a)+len(b))
+	for key, value := range a {
+		x = append(x, value)
+		y = append(y, b[key])
+	}
+	for key, value := range b {
+		if _, ok := a[key]; !ok {
+			x = append(x, 0)
+			y = append(y, value)
+		}
+	}
+	return math.Sqrt(currentBackend().Dist2(x, y)), nil
+}
+
+// AddPlane 按 plane += scale·delta 更新张量主平面, 由当前计算后端执行
+// delta为行优先的主平面增量; 梯度按行优先顺序更新, 与逐个SetComponent的结果一致
+func (ft *FieldTensor) AddPlane(scale complex128, delta []complex128) error {
+	if ft.rank != 2 && ft.rank != 3 {
+		return model.NewModelError(model.ErrCodeValidation, "unsupported tensor rank", nil)
+	}
+	if len(delta) != ft.dimension*ft.dimension {
+		return model.NewModelError(model.ErrCodeValidation, "invalid plane size", nil)
+	}
+
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+
+	// 稀疏表示只更新增量非零的分量及其梯度
+	if ft.sparse != nil {
+		indices := make([]int, 2)
+		for idx, d := range delta {
+			if d == 0 {
+				continue
+			}
+			i, j := idx/ft.dimension, idx%ft.dimension
+			value := ft.at(i, j, 0) + scale*d
+			ft.put(i, j, 0, value)
+			indices[0], indices[1] = i, j
+			ft.updateGradient(indices, value)
+		}
+		return nil
+	}
+
+	plane := ft.planeLocked()
+	currentBackend().Axpy(scale, delta, plane)
+
+	indices := make([]int, 2)
 	for i := 0; i < ft.dimension; i++ {
 		for j := 0; j < ft.dimension; j++ {
-			value := ft.data[i][j][0]
-			sum += real(value * complex128(value))
+			value := plane[i*ft.dimension+j]
+			ft.data[i][j][0] = value
+			indices[0], indices[1] = i, j
+			ft.updateGradient(indices, value)
 		}
 	}
-	return math.Sqrt(sum)
+	return nil
+}
+
+// planeLocked 复制张量主平面, 调用方须持有锁
+func (ft *FieldTensor) planeLocked() []complex128 {
+	plane := make([]complex128, ft.dimension*ft.dimension)
+	ft.eachPlaneLocked(func(i, j int, value complex128) {
+		plane[i*ft.dimension+j] = value
+	})
+	return plane
+}
+
+// planeMatrixLocked 以矩阵形式复制张量主平面, 调用方须持有锁
+func (ft *FieldTensor) planeMatrixLocked() [][]complex128 {
+	plane := ft.planeLocked()
+	matrix := make([][]complex128, ft.dimension)
+	for i := range matrix {
+		matrix[i] = plane[i*ft.dimension : (i+1)*ft.dimension]
+	}
+	return matrix
 }
 
 // GetCoherence 获取场张量的相干度
@@ -662,17 +816,14 @@ func (ft *FieldTensor) GetCoherence() float64 {
 	coherence := 0.0
 	count := 0.0
 
-	// 计算非对角元素的贡献
-	for i := 0; i < ft.dimension; i++ {
-		for j := 0; j < ft.dimension; j++ {
-			if i != j {
-				// 使用非对角元素的模作为相干度贡献
-				value := ft.data[i][j][0]
-				coherence += cmplx.Abs(value)
-				count++
-			}
+	// 计算非对角元素的贡献, 零分量不影响累加
+	ft.eachPlaneLocked(func(i, j int, value complex128) {
+		if i != j {
+			// 使用非对角元素的模作为相干度贡献
+			coherence += cmplx.Abs(value)
 		}
-	}
+	})
+	count = float64(ft.dimension*ft.dimension - ft.dimension)
 
 	if count > 0 {
 		coherence /= count
@@ -0,0 +1,365 @@
+//system/meta/field/topology.go
+
+package field
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 可耦合的场组件
+const (
+	ComponentScalar  = "scalar"
+	ComponentVector  = "vector"
+	ComponentMetric  = "metric"
+	ComponentQuantum = "quantum"
+)
+
+// CouplingEdge 耦合网络中的一条耦合
+type CouplingEdge struct {
+	ID            string  `json:"id"`
+	Source        string  `json:"source"`
+	Target        string  `json:"target"`
+	Bidirectional bool    `json:"bidirectional"`
+	Strength      float64 `json:"strength"`
+	Type          string  `json:"type"` // strong/medium/weak
+	Phase         float64 `json:"phase"`
+	Energy        float64 `json:"energy"`
+	Resonance     float64 `json:"resonance"`
+	Stability     float64 `json:"stability"`
+}
+
+// CouplingGraph 场耦合网络
+type CouplingGraph struct {
+	Fields     []string       `json:"fields"`
+	Couplings  []CouplingEdge `json:"couplings"`
+	Thresholds []float64      `json:"thresholds"`
+}
+
+// CouplingCrossing 耦合强度越过阈值
+type CouplingCrossing struct {
+	Coupling  string    `json:"coupling"`
+	Source    string    `json:"source"`
+	Target    string    `json:"target"`
+	Threshold float64   `json:"threshold"`
+	Previous  float64   `json:"previous"`
+	Strength  float64   `json:"strength"`
+	Rising    bool      `json:"rising"` // 向上越过为true
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// couplingAlerts 耦合阈值监测状态, 由场锁保护
+type couplingAlerts struct {
+	maxPairs   int
+	thresholds []float64
+	hysteresis float64
+	strength   map[string]float64 // 各耦合上次检查时的强度
+	above      map[string][]bool  // 各耦合在每个阈值之上
+	listeners  []func(CouplingCrossing)
+}
+
+// ConfigureCouplings 设置耦合数量上限和强度阈值, maxPairs<=0时不限数量
+func (uf *UnifiedField) ConfigureCouplings(maxPairs int, config types.CouplingThresholdConfig) error {
+	levels := append([]float64(nil), config.Levels...)
+	for _, level := range levels {
+		if math.IsNaN(level) || level < 0 || level > 1 {
+			return model.NewModelError(model.ErrCodeValidation, "coupling threshold out of range [0,1]", nil)
+		}
+	}
+	if math.IsNaN(config.Hysteresis) || config.Hysteresis < 0 {
+		return model.NewModelError(model.ErrCodeValidation, "negative coupling hysteresis", nil)
+	}
+	sort.Float64s(levels)
+
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	uf.alerts.maxPairs = maxPairs
+	uf.alerts.thresholds = levels
+	uf.alerts.hysteresis = config.Hysteresis
+	uf.alerts.above = make(map[string][]bool, len(uf.couplings))
+	uf.alerts.strength = make(map[string]float64, len(uf.couplings))
+	for id, coupling := range uf.couplings {
+		coupling.mu.RLock()
+		strength := coupling.properties.strength
+		coupling.mu.RUnlock()
+		uf.alerts.above[id] = uf.thresholdState(strength, nil)
+		uf.alerts.strength[id] = strength
+	}
+	return nil
+}
+
+// OnCouplingCrossing 注册耦合强度越过阈值的监听器, 监听器在场锁外同步调用
+func (uf *UnifiedField) OnCouplingCrossing(listener func(CouplingCrossing)) {
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+	uf.alerts.listeners = append(uf.alerts.listeners, listener)
+}
+
+// CouplingGraph 获取场耦合网络, 耦合按ID排序
+func (uf *UnifiedField) CouplingGraph() CouplingGraph {
+	uf.mu.RLock()
+	defer uf.mu.RUnlock()
+
+	graph := CouplingGraph{
+		Fields:     []string{ComponentScalar, ComponentVector, ComponentMetric, ComponentQuantum},
+		Couplings:  make([]CouplingEdge, 0, len(uf.couplings)),
+		Thresholds: append([]float64(nil), uf.alerts.thresholds...),
+	}
+	for id, coupling := range uf.couplings {
+		coupling.mu.RLock()
+		graph.Couplings = append(graph.Couplings, CouplingEdge{
+			ID:            id,
+			Source:        coupling.topology.source,
+			Target:        coupling.topology.target,
+			Bidirectional: coupling.topology.bidirectional,
+			Strength:      coupling.properties.strength,
+			Type:          coupling.properties.type_,
+			Phase:         coupling.properties.phase,
+			Energy:        coupling.properties.energy,
+			Resonance:     coupling.dynamics.resonance,
+			Stability:     coupling.dynamics.stability,
+		})
+		coupling.mu.RUnlock()
+	}
+	sort.Slice(graph.Couplings, func(i, j int) bool {
+		return graph.Couplings[i].ID < graph.Couplings[j].ID
+	})
+	return graph
+}
+
+// AddCoupling 在两个场组件间添加耦合, 返回耦合ID
+// 耦合强度由调用方设定, 演化时保持不变; 同一对组件间已有双向耦合或同向耦合时返回错误
+func (uf *UnifiedField) AddCoupling(source, target string, strength float64, bidirectional bool) (string, error) {
+	if err := validateStrength(strength); err != nil {
+		return "", err
+	}
+	if source == target {
+		return "", model.NewModelError(model.ErrCodeValidation, "coupling source and target must differ", nil)
+	}
+
+	id := couplingID(source, target, bidirectional)
+
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	f1, f2 := uf.component(source), uf.component(target)
+	if f1 == nil || f2 == nil {
+		return "", model.NewModelError(model.ErrCodeNotFound,
+			fmt.Sprintf("unknown field component in coupling %s", id), nil)
+	}
+	if uf.alerts.maxPairs > 0 && len(uf.couplings) >= uf.alerts.maxPairs {
+		return "", model.NewModelError(model.ErrCodeLimit, "coupling limit reached", nil)
+	}
+	for existing, coupling := range uf.couplings {
+		sameDirection := coupling.topology.source == source && coupling.topology.target == target
+		reverse := coupling.topology.source == target && coupling.topology.target == source
+		if sameDirection || (reverse && (bidirectional || coupling.topology.bidirectional)) {
+			return "", model.NewModelError(model.ErrCodeValidation,
+				fmt.Sprintf("coupling %s conflicts with %s", id, existing), nil)
+		}
+	}
+
+	coupling, err := NewFieldCoupling(f1, f2)
+	if err != nil {
+		return "", err
+	}
+	coupling.mu.Lock()
+	coupling.topology.source = source
+	coupling.topology.target = target
+	coupling.topology.bidirectional = bidirectional
+	coupling.topology.pinned = true
+	coupling.setStrength(strength)
+	coupling.mu.Unlock()
+
+	uf.couplings[id] = coupling
+	if uf.alerts.above == nil {
+		uf.alerts.above = make(map[string][]bool)
+	}
+	uf.alerts.above[id] = uf.thresholdState(strength, nil)
+	if uf.alerts.strength == nil {
+		uf.alerts.strength = make(map[string]float64)
+	}
+	uf.alerts.strength[id] = strength
+	return id, nil
+}
+
+// RemoveCoupling 移除耦合
+func (uf *UnifiedField) RemoveCoupling(id string) error {
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	if _, ok := uf.couplings[id]; !ok {
+		return model.NewModelError(model.ErrCodeNotFound, fmt.Sprintf("coupling %s not found", id), nil)
+	}
+	delete(uf.couplings, id)
+	delete(uf.alerts.above, id)
+	delete(uf.alerts.strength, id)
+	return nil
+}
+
+// SetCouplingStrength 调整耦合强度, 越过阈值时通知监听器
+func (uf *UnifiedField) SetCouplingStrength(id string, strength float64) error {
+	if err := validateStrength(strength); err != nil {
+		return err
+	}
+
+	var crossings []CouplingCrossing
+	defer func() { uf.notifyCrossings(crossings) }()
+
+	uf.mu.Lock()
+	defer uf.mu.Unlock()
+
+	coupling, ok := uf.couplings[id]
+	if !ok {
+		return model.NewModelError(model.ErrCodeNotFound, fmt.Sprintf("coupling %s not found", id), nil)
+	}
+	coupling.mu.Lock()
+	coupling.topology.pinned = true
+	coupling.setStrength(strength)
+	coupling.mu.Unlock()
+
+	crossings = uf.detectCrossings()
+	return nil
+}
+
+// setStrength 设定强度并更新相关特性, 调用方须持有耦合锁
+func (fc *FieldCoupling) setStrength(strength float64) {
+	fc.properties.strength = strength
+	fc.properties.type_ = fc.determineType()
+	if energy, err := fc.calculateEnergy(); err == nil {
+		fc.properties.energy = energy
+	}
+	fc.recordState()
+}
+
+// component 按名称获取场组件
+func (uf *UnifiedField) component(name string) *FieldTensor {
+	switch name {
+	case ComponentScalar:
+		return uf.components.scalar
+	case ComponentVector:
+		return uf.components.vector
+	case ComponentMetric:
+		return uf.components.metric
+	case ComponentQuantum:
+		return uf.components.quantum
+	}
+	return nil
+}
+
+// rebindCouplings 场组件重建后将耦合指向新的组件, 调用方须持有场锁
+func (uf *UnifiedField) rebindCouplings() {
+	for _, coupling := range uf.couplings {
+		f1, f2 := uf.component(coupling.topology.source), uf.component(coupling.topology.target)
+		if f1 == nil || f2 == nil {
+			continue
+		}
+		coupling.mu.Lock()
+		coupling.field1, coupling.field2 = f1, f2
+		coupling.mu.Unlock()
+	}
+}
+
+// detectCrossings 比较各耦合强度与上次检查时的阈值状态, 调用方须持有场锁
+func (uf *UnifiedField) detectCrossings() []CouplingCrossing {
+	if len(uf.alerts.thresholds) == 0 {
+		return nil
+	}
+	if uf.alerts.above == nil {
+		uf.alerts.above = make(map[string][]bool)
+	}
+	if uf.alerts.strength == nil {
+		uf.alerts.strength = make(map[string]float64)
+	}
+
+	var crossings []CouplingCrossing
+	now := time.Now()
+	for id, coupling := range uf.couplings {
+		coupling.mu.RLock()
+		strength := coupling.properties.strength
+		source, target := coupling.topology.source, coupling.topology.target
+		coupling.mu.RUnlock()
+
+		previous, seen := uf.alerts.strength[id]
+		before := uf.alerts.above[id]
+		after := uf.thresholdState(strength, before)
+		uf.alerts.above[id] = after
+		uf.alerts.strength[id] = strength
+		if !seen || len(before) != len(after) {
+			continue
+		}
+
+		for i, level := range uf.alerts.thresholds {
+			if before[i] == after[i] {
+				continue
+			}
+			crossings = append(crossings, CouplingCrossing{
+				Coupling:  id,
+				Source:    source,
+				Target:    target,
+				Threshold: level,
+				Previous:  previous,
+				Strength:  strength,
+				Rising:    after[i],
+				Timestamp: now,
+			})
+		}
+	}
+	return crossings
+}
+
+// thresholdState 强度在各阈值之上的状态
+// 已有状态时按滞回宽度判定, 强度须高于阈值加滞回才转为之上, 低于阈值减滞回才转为之下
+func (uf *UnifiedField) thresholdState(strength float64, previous []bool) []bool {
+	state := make([]bool, len(uf.alerts.thresholds))
+	h := uf.alerts.hysteresis
+	for i, level := range uf.alerts.thresholds {
+		switch {
+		case len(previous) != len(state):
+			state[i] = strength >= level
+		case previous[i]:
+			state[i] = strength >= level-h
+		default:
+			state[i] = strength >= level+h
+		}
+	}
+	return state
+}
+
+// notifyCrossings 通知阈值监听器, 须在场锁外调用
+func (uf *UnifiedField) notifyCrossings(crossings []CouplingCrossing) {
+	if len(crossings) == 0 {
+		return
+	}
+	uf.mu.RLock()
+	listeners := append(([]func(CouplingCrossing))(nil), uf.alerts.listeners...)
+	uf.mu.RUnlock()
+
+	for _, crossing := range crossings {
+		for _, listener := range listeners {
+			listener(crossing)
+		}
+	}
+}
+
+// couplingID 耦合ID, 双向耦合使用<->连接
+func couplingID(source, target string, bidirectional bool) string {
+	if bidirectional {
+		return source + "<->" + target
+	}
+	return source + "->" + target
+}
+
+// validateStrength 校验耦合强度
+func validateStrength(strength float64) error {
+	if math.IsNaN(strength) || strength < 0 || strength > 1 {
+		return model.NewModelError(model.ErrCodeValidation, "coupling strength out of range [0,1]", nil)
+	}
+	return nil
+}
@@ -10,7 +10,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/faultinject"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -50,6 +53,9 @@ type UnifiedField struct {
 		quantum *FieldTensor
 	}
 
+	// 场组件的存储表示, 未设置的组件为稠密表示; 组件重建后保持
+	representations map[string]TensorRepresentation
+
 	// 统一特性(meta层特有的高层抽象)
 	properties struct {
 		symmetry   string             // 对称性类型
@@ -62,6 +68,12 @@ type UnifiedField struct {
 	// 场耦合关系
 	couplings map[string]*FieldCoupling
 
+	// 耦合强度阈值监测
+	alerts couplingAlerts
+
+	// 受控扰动
+	perturb perturbations
+
 	// 添加元素管理
 	WuXingElements []*WuXingElement // 五行元素集合
 
@@ -71,6 +83,9 @@ type UnifiedField struct {
 		Strength float64        // 当前场强度
 		Phase    float64        // 当前相位
 		Energy   float64
+
+		// 外部观测能量分布
+		Observed map[core.Point]float64
 	}
 }
 
@@ -176,6 +191,14 @@ func NewUnifiedField(initialStrength float64) (*UnifiedField, error) {
 		return nil, err
 	}
 
+	// 初始化核心状态
+	uf.core = model.CoreState{
+		QuantumState: core.NewQuantumState(),
+		FieldState:   core.NewField(core.ScalarField, DefaultDimension),
+		EnergyState:  core.NewEnergySystem(maxWuXingElementEnergy * 100),
+		Properties:   make(map[string]float64),
+	}
+
 	// 设置初始强度
 	uf.state.Strength = initialStrength
 
@@ -212,6 +235,13 @@ func (uf *UnifiedField) initComponents(dimension int) error {
 	quantum := NewFieldTensor(dimension, 1)
 	uf.components.quantum = quantum
 
+	// 恢复组件的存储表示
+	for name, representation := range uf.representations {
+		if err := uf.component(name).SetRepresentation(representation); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -245,9 +275,19 @@ func (uf *UnifiedField) initYinYang() {
 
 // Evolve 演化统一场
 func (uf *UnifiedField) Evolve() error {
+	var crossings []CouplingCrossing
+	var perturbed []Perturbation
+	defer func() {
+		uf.notifyCrossings(crossings)
+		uf.notifyPerturbations(perturbed)
+	}()
+
 	uf.mu.Lock()
 	defer uf.mu.Unlock()
 
+	// 施加到期的计划扰动并撤销结束的扰动
+	perturbed = uf.stepPerturbations(time.Now())
+
 	// 更新场组件
 	if err := uf.evolveComponents(); err != nil {
 		return err
@@ -257,6 +297,7 @@ func (uf *UnifiedField) Evolve() error {
 	if err := uf.evolveCouplings(); err != nil {
 		return err
 	}
+	crossings = uf.detectCrossings()
 
 	// 更新五行属性
 	uf.evolveWuXingElements()
@@ -369,15 +410,13 @@ func (uf *UnifiedField) evolveScalarField() error {
 
 	stepSeconds := getEvolutionStepSeconds(evolutionTimeStep)
 	// 应用波动方程
+	delta := make([]complex128, 0, field.dimension*field.dimension)
 	for i := 0; i < field.dimension; i++ {
 		for j := 0; j < field.dimension; j++ {
-			value, _ := field.GetComponent([]int{i, j})
-			// 将float64转换为complex128
-			term := complex(laplacian[i][j]*stepSeconds, 0)
-			newValue := value + term
-			field.SetComponent([]int{i, j}, newValue)
+			delta = append(delta, complex(laplacian[i][j], 0))
 		}
 	}
+	field.AddPlane(complex(stepSeconds, 0), delta)
 
 	return nil
 }
@@ -389,15 +428,13 @@ func (uf *UnifiedField) evolveVectorField() error {
 	div := calculateDivergence(field)
 	stepSeconds := getEvolutionStepSeconds(evolutionTimeStep)
 
+	delta := make([]complex128, 0, field.dimension*field.dimension)
 	for i := 0; i < field.dimension; i++ {
 		for j := 0; j < field.dimension; j++ {
-			value, _ := field.GetComponent([]int{i, j})
-			// 将float64转为complex128
-			term := complex((curl[i][j]-div[i][j])*stepSeconds, 0)
-			newValue := value + term
-			field.SetComponent([]int{i, j}, newValue)
+			delta = append(delta, complex(curl[i][j]-div[i][j], 0))
 		}
 	}
+	field.AddPlane(complex(stepSeconds, 0), delta)
 	return nil
 }
 
@@ -408,15 +445,13 @@ func (uf *UnifiedField) evolveMetricField() error {
 	energyMomentum := calculateEnergyMomentumTensor(field)
 	stepSeconds := getEvolutionStepSeconds(evolutionTimeStep)
 
+	delta := make([]complex128, 0, field.dimension*field.dimension)
 	for i := 0; i < field.dimension; i++ {
 		for j := 0; j < field.dimension; j++ {
-			value, _ := field.GetComponent([]int{i, j})
-			// 将float64转为complex128
-			term := complex((ricci[i][j]-0.5*energyMomentum[i][j])*stepSeconds, 0)
-			newValue := value + term
-			field.SetComponent([]int{i, j}, newValue)
+			delta = append(delta, complex(ricci[i][j]-0.5*energyMomentum[i][j], 0))
 		}
 	}
+	field.AddPlane(complex(stepSeconds, 0), delta)
 	return nil
 }
 
@@ -426,14 +461,11 @@ func (uf *UnifiedField) evolveQuantumField() error {
 	hamiltonian := calculateHamiltonian(field)
 	stepSeconds := getEvolutionStepSeconds(evolutionTimeStep)
 
+	delta := make([]complex128, 0, field.dimension*field.dimension)
 	for i := 0; i < field.dimension; i++ {
-		for j := 0; j < field.dimension; j++ {
-			value, _ := field.GetComponent([]int{i, j})
-			// 使用复数直接计算,使用转换后的秒数
-			newValue := value + hamiltonian[i][j]*complex(0, -stepSeconds)
-			field.SetComponent([]int{i, j}, newValue)
-		}
+		delta = append(delta, hamiltonian[i]...)
 	}
+	field.AddPlane(complex(0, -stepSeconds), delta)
 	return nil
 }
 
@@ -448,11 +480,8 @@ func (uf *UnifiedField) evolveCouplings() error {
 	return nil
 }
 
-// evolveWuXingElements 演化五行元素
+// evolveWuXingElements 演化五行元素, 调用方须持有场锁
 func (uf *UnifiedField) evolveWuXingElements() {
-	uf.mu.Lock()
-	defer uf.mu.Unlock()
-
 	// 更新各元素状态
 	for i, WuXingElement := range uf.WuXingElements {
 		// 计算元素间相互作用
@@ -607,8 +636,10 @@ func (uf *UnifiedField) updateFieldState() {
 		totalEnergy += WuXingElement.Energy
 	}
 
-	// 更新场强度
-	uf.state.Strength = totalEnergy / float64(len(uf.WuXingElements))
+	// 更新场强度, 尚无元素时保持不变
+	if len(uf.WuXingElements) > 0 {
+		uf.state.Strength = totalEnergy / float64(len(uf.WuXingElements))
+	}
 
 	// 更新场相位
 	uf.state.Phase = uf.calculateFieldPhase()
@@ -1094,6 +1125,10 @@ func (uf *UnifiedField) GetEnergy() float64 {
 
 // GetState 替代GetPropertyValue获取状态
 func (uf *UnifiedField) GetState() (*model.FieldState, error) {
+	if err := faultinject.Fire(faultinject.PointFieldGetState, ""); err != nil {
+		return nil, err
+	}
+
 	uf.mu.RLock()
 	defer uf.mu.RUnlock()
 
@@ -1109,6 +1144,18 @@ func (uf *UnifiedField) GetState() (*model.FieldState, error) {
 			"balance":  currentState.WuXingElements.Balance,
 		},
 		Timestamp: currentState.Time,
+		Quantum:   uf.core.QuantumState,
+	}
+
+	// 叠加外部观测和活跃扰动
+	if len(uf.state.Observed) > 0 || len(uf.perturb.overlay) > 0 {
+		state.Distribution = uf.observedDistribution()
+		for _, energy := range state.Distribution {
+			state.Energy += energy
+		}
+	}
+	for _, elem := range uf.WuXingElements {
+		state.Properties["element_"+elem.Type] = elem.Energy
 	}
 
 	return state, nil
@@ -1272,7 +1319,7 @@ func (uf *UnifiedField) Start(ctx context.Context) error {
 	}
 
 	// 启动周期性演化
-	go uf.evolutionLoop(ctx)
+	supervisor.Go(ctx, "meta.field.evolution", uf.evolutionLoop)
 
 	return nil
 }
@@ -1340,5 +1387,9 @@ func (uf *UnifiedField) tryRecover() error {
 	}
 
 	// 2. 重新初始化不稳定的组件
-	return uf.initComponents(uf.properties.dimension)
+	if err := uf.initComponents(uf.properties.dimension); err != nil {
+		return err
+	}
+	uf.rebindCouplings()
+	return nil
 }
@@ -0,0 +1,474 @@
+// system/meta/ingest.go
+
+package meta
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 注入默认参数
+const (
+	defaultIngestBatchSize   = 10000
+	defaultIngestDecayFactor = 0.95
+	defaultIngestMaxLag      = time.Hour
+)
+
+// ObservationBatch 外部观测批次
+type ObservationBatch struct {
+	Namespace types.Namespace          `json:"namespace,omitempty"` // 目标命名空间, 为空时使用默认命名空间
+	Source    string                   `json:"source,omitempty"`    // 数据源
+	Timestamp time.Time                `json:"timestamp"`           // 批次时间
+	Series    []SeriesObservation      `json:"series,omitempty"`    // 标量序列
+	Points    []PointObservation       `json:"points,omitempty"`    // 点能量
+	Events    []CategoricalObservation `json:"events,omitempty"`    // 分类事件
+}
+
+// SeriesObservation 命名标量观测
+type SeriesObservation struct {
+	Name      string    `json:"name"`      // 序列名称
+	Value     float64   `json:"value"`     // 观测值
+	Timestamp time.Time `json:"timestamp"` // 观测时间
+}
+
+// PointObservation 空间点能量观测
+type PointObservation struct {
+	X         int       `json:"x"`         // 横坐标
+	Y         int       `json:"y"`         // 纵坐标
+	Energy    float64   `json:"energy"`    // 能量
+	Timestamp time.Time `json:"timestamp"` // 观测时间
+}
+
+// CategoricalObservation 分类事件观测
+type CategoricalObservation struct {
+	Name      string    `json:"name"`      // 事件名称
+	Category  string    `json:"category"`  // 事件类别
+	Weight    float64   `json:"weight"`    // 事件权重
+	Timestamp time.Time `json:"timestamp"` // 观测时间
+}
+
+// Size 批次观测总数
+func (b *ObservationBatch) Size() int {
+	return len(b.Series) + len(b.Points) + len(b.Events)
+}
+
+// FieldMutation 映射器产生的场变更
+type FieldMutation struct {
+	PointEnergy       map[core.Point]float64        // 点能量增量
+	ElementEnergy     map[string]float64            // 元素能量增量
+	ElementProperties map[string]map[string]float64 // 元素属性
+	Properties        map[string]float64            // 场属性
+}
+
+// NewFieldMutation 创建空的场变更
+func NewFieldMutation() *FieldMutation {
+	return &FieldMutation{
+		PointEnergy:       make(map[core.Point]float64),
+		ElementEnergy:     make(map[string]float64),
+		ElementProperties: make(map[string]map[string]float64),
+		Properties:        make(map[string]float64),
+	}
+}
+
+// SetElementProperty 设置元素属性
+func (fm *FieldMutation) SetElementProperty(element, name string, value float64) {
+	if fm.ElementProperties[element] == nil {
+		fm.ElementProperties[element] = make(map[string]float64)
+	}
+	fm.ElementProperties[element][name] = value
+}
+
+// ObservationMapper 观测映射器, 将观测转换为场变更
+type ObservationMapper interface {
+	Name() string
+	Map(batch *ObservationBatch, mutation *FieldMutation) error
+}
+
+// MapperFunc 映射函数适配器
+type MapperFunc struct {
+	ID string
+	Fn func(batch *ObservationBatch, mutation *FieldMutation) error
+}
+
+// Name 映射器名称
+func (m MapperFunc) Name() string { return m.ID }
+
+// Map 执行映射
+func (m MapperFunc) Map(batch *ObservationBatch, mutation *FieldMutation) error {
+	return m.Fn(batch, mutation)
+}
+
+// PointEnergyMapper 将点观测映射为场能量分布
+type PointEnergyMapper struct {
+	Scale float64 // 能量缩放
+}
+
+// Name 映射器名称
+func (m *PointEnergyMapper) Name() string { return "point_energy" }
+
+// Map 执行映射
+func (m *PointEnergyMapper) Map(batch *ObservationBatch, mutation *FieldMutation) error {
+	scale := m.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	for _, obs := range batch.Points {
+		mutation.PointEnergy[core.Point{X: obs.X, Y: obs.Y}] += obs.Energy * scale
+	}
+	return nil
+}
+
+// SeriesElementMapper 将标量序列映射为元素能量与属性
+type SeriesElementMapper struct {
+	Elements map[string]string // 序列名 -> 元素类型
+	Scale    float64           // 能量缩放
+}
+
+// Name 映射器名称
+func (m *SeriesElementMapper) Name() string { return "series_element" }
+
+// Map 执行映射
+func (m *SeriesElementMapper) Map(batch *ObservationBatch, mutation *FieldMutation) error {
+	scale := m.Scale
+	if scale == 0 {
+		scale = 1
+	}
+	for _, obs := range batch.Series {
+		element, ok := m.Elements[obs.Name]
+		if !ok {
+			continue
+		}
+		mutation.ElementEnergy[element] += obs.Value * scale
+		mutation.SetElementProperty(element, obs.Name, obs.Value)
+	}
+	return nil
+}
+
+// SeriesPropertyMapper 将标量序列直接映射为场属性
+type SeriesPropertyMapper struct {
+	Properties map[string]string // 序列名 -> 场属性名, 为空时使用序列名
+}
+
+// Name 映射器名称
+func (m *SeriesPropertyMapper) Name() string { return "series_property" }
+
+// Map 执行映射
+func (m *SeriesPropertyMapper) Map(batch *ObservationBatch, mutation *FieldMutation) error {
+	for _, obs := range batch.Series {
+		name := obs.Name
+		if len(m.Properties) > 0 {
+			mapped, ok := m.Properties[obs.Name]
+			if !ok {
+				continue
+			}
+			name = mapped
+		}
+		mutation.Properties[name] = obs.Value
+	}
+	return nil
+}
+
+// EventElementMapper 将分类事件映射为元素能量
+type EventElementMapper struct {
+	Categories map[string]string // 类别 -> 元素类型, 为空时按五行名称匹配
+	Energy     float64           // 每个事件的基础能量
+}
+
+// Name 映射器名称
+func (m *EventElementMapper) Name() string { return "event_element" }
+
+// Map 执行映射
+func (m *EventElementMapper) Map(batch *ObservationBatch, mutation *FieldMutation) error {
+	energy := m.Energy
+	if energy == 0 {
+		energy = 1
+	}
+	for _, obs := range batch.Events {
+		element, ok := m.Categories[obs.Category]
+		if !ok && len(m.Categories) == 0 {
+			element, ok = wuxingElementName(obs.Category)
+		}
+		if !ok {
+			continue
+		}
+		weight := obs.Weight
+		if weight == 0 {
+			weight = 1
+		}
+		mutation.ElementEnergy[element] += energy * weight
+	}
+	return nil
+}
+
+// wuxingElementName 按五行名称匹配类别
+func wuxingElementName(category string) (string, bool) {
+	switch strings.ToLower(category) {
+	case "wood":
+		return "Wood", true
+	case "fire":
+		return "Fire", true
+	case "earth":
+		return "Earth", true
+	case "metal":
+		return "Metal", true
+	case "water":
+		return "Water", true
+	}
+	return "", false
+}
+
+// DefaultMappers 默认映射器集合
+func DefaultMappers() []ObservationMapper {
+	return []ObservationMapper{
+		&PointEnergyMapper{Scale: 1},
+		&SeriesPropertyMapper{},
+		&EventElementMapper{Energy: 1},
+	}
+}
+
+// IngestResult 注入结果
+type IngestResult struct {
+	Namespace types.Namespace             // 命名空间
+	Accepted  int                         // 接受的观测数
+	Rejected  int                         // 拒绝的观测数(过期)
+	Detected  bool                        // 是否触发检测
+	Patterns  []emergence.EmergentPattern // 检测到的模式
+}
+
+// IngestStats 注入统计
+type IngestStats struct {
+	Batches      int64     // 批次数
+	Observations int64     // 观测数
+	Rejected     int64     // 拒绝数
+	Detections   int64     // 触发检测次数
+	LastIngest   time.Time // 最后注入时间
+}
+
+// Ingestor 观测注入器
+type Ingestor struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config struct {
+		maxBatchSize int           // 单批最大观测数
+		decayFactor  float64       // 衰减因子
+		detectEvery  int           // 检测频率
+		maxLag       time.Duration // 最大延迟
+	}
+
+	// 注入状态
+	state struct {
+		mappers []ObservationMapper     // 映射器
+		pending map[types.Namespace]int // 未检测批次数
+		stats   IngestStats             // 统计
+	}
+}
+
+// NewIngestor 创建观测注入器
+func NewIngestor(cfg *types.MetaConfig) *Ingestor {
+	ing := &Ingestor{}
+
+	ing.config.maxBatchSize = defaultIngestBatchSize
+	ing.config.decayFactor = defaultIngestDecayFactor
+	ing.config.detectEvery = 1
+	ing.config.maxLag = defaultIngestMaxLag
+	if cfg != nil {
+		if cfg.Ingest.MaxBatchSize > 0 {
+			ing.config.maxBatchSize = cfg.Ingest.MaxBatchSize
+		}
+		if cfg.Ingest.DecayFactor > 0 {
+			ing.config.decayFactor = cfg.Ingest.DecayFactor
+		}
+		if cfg.Ingest.DetectEvery > 0 {
+			ing.config.detectEvery = cfg.Ingest.DetectEvery
+		}
+		if cfg.Ingest.MaxLag > 0 {
+			ing.config.maxLag = cfg.Ingest.MaxLag
+		}
+	}
+
+	ing.state.mappers = DefaultMappers()
+	ing.state.pending = make(map[types.Namespace]int)
+
+	return ing
+}
+
+// SetMappers 替换映射器集合
+func (ing *Ingestor) SetMappers(mappers ...ObservationMapper) {
+	ing.mu.Lock()
+	defer ing.mu.Unlock()
+	ing.state.mappers = append([]ObservationMapper(nil), mappers...)
+}
+
+// AddMapper 追加映射器
+func (ing *Ingestor) AddMapper(mapper ObservationMapper) {
+	ing.mu.Lock()
+	defer ing.mu.Unlock()
+	ing.state.mappers = append(ing.state.mappers, mapper)
+}
+
+// GetStats 获取注入统计
+func (ing *Ingestor) GetStats() IngestStats {
+	ing.mu.RLock()
+	defer ing.mu.RUnlock()
+	return ing.state.stats
+}
+
+// prepare 校验批次并剔除过期观测, 返回映射后的场变更
+func (ing *Ingestor) prepare(batch *ObservationBatch) (*FieldMutation, int, error) {
+	ing.mu.RLock()
+	defer ing.mu.RUnlock()
+
+	if batch.Size() > ing.config.maxBatchSize {
+		return nil, 0, types.NewSystemError(types.ErrOverflow, "observation batch too large", nil).
+			WithContext("size", batch.Size()).
+			WithContext("max", ing.config.maxBatchSize)
+	}
+
+	// 剔除过期观测
+	rejected := 0
+	cutoff := time.Now().Add(-ing.config.maxLag)
+	fresh := func(t time.Time) bool {
+		if t.IsZero() || !t.Before(cutoff) {
+			return true
+		}
+		rejected++
+		return false
+	}
+
+	filtered := &ObservationBatch{
+		Namespace: batch.Namespace,
+		Source:    batch.Source,
+		Timestamp: batch.Timestamp,
+	}
+	for _, obs := range batch.Series {
+		if fresh(obs.Timestamp) {
+			filtered.Series = append(filtered.Series, obs)
+		}
+	}
+	for _, obs := range batch.Points {
+		if fresh(obs.Timestamp) {
+			filtered.Points = append(filtered.Points, obs)
+		}
+	}
+	for _, obs := range batch.Events {
+		if fresh(obs.Timestamp) {
+			filtered.Events = append(filtered.Events, obs)
+		}
+	}
+
+	// 执行映射
+	mutation := NewFieldMutation()
+	for _, mapper := range ing.state.mappers {
+		if err := mapper.Map(filtered, mutation); err != nil {
+			return nil, rejected, fmt.Errorf("mapper %s failed: %w", mapper.Name(), err)
+		}
+	}
+
+	return mutation, rejected, nil
+}
+
+// record 记录批次并判断是否需要检测
+func (ing *Ingestor) record(ns types.Namespace, accepted, rejected int) bool {
+	ing.mu.Lock()
+	defer ing.mu.Unlock()
+
+	ing.state.stats.Batches++
+	ing.state.stats.Observations += int64(accepted)
+	ing.state.stats.Rejected += int64(rejected)
+	ing.state.stats.LastIngest = time.Now()
+
+	ing.state.pending[ns]++
+	if ing.state.pending[ns] < ing.config.detectEvery {
+		return false
+	}
+	ing.state.pending[ns] = 0
+	ing.state.stats.Detections++
+	return true
+}
+
+// Ingest 注入观测批次, 映射到目标命名空间的场并按配置触发增量检测
+func (m *Manager) Ingest(ctx context.Context, batch *ObservationBatch) (*IngestResult, error) {
+	if batch == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil observation batch", nil)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if m.Draining() {
+		return nil, types.NewSystemError(types.ErrState, "meta system draining", nil)
+	}
+
+	ns := batch.Namespace
+	if ns == "" {
+		ns = types.DefaultNamespace
+	}
+
+	f := m.GetNamespaceField(ns)
+	detector := m.GetNamespaceDetector(ns)
+	if f == nil || detector == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "namespace not found", nil).
+			WithContext("namespace", ns)
+	}
+
+	ingestor := m.GetIngestor()
+	mutation, rejected, err := ingestor.prepare(batch)
+	if err != nil {
+		return nil, err
+	}
+
+	// 应用场变更
+	f.DecayObservations(ingestor.config.decayFactor)
+	if len(mutation.PointEnergy) > 0 {
+		f.DepositEnergy(mutation.PointEnergy)
+	}
+	for element, delta := range mutation.ElementEnergy {
+		f.AdjustElement(element, delta, mutation.ElementProperties[element])
+	}
+	for element, props := range mutation.ElementProperties {
+		if _, adjusted := mutation.ElementEnergy[element]; !adjusted {
+			f.AdjustElement(element, 0, props)
+		}
+	}
+	for name, value := range mutation.Properties {
+		if err := f.SetPropertyValue(name, value); err != nil {
+			return nil, fmt.Errorf("failed to set field property %s: %w", name, err)
+		}
+	}
+
+	result := &IngestResult{
+		Namespace: ns,
+		Accepted:  batch.Size() - rejected,
+		Rejected:  rejected,
+	}
+
+	// 增量检测
+	if ingestor.record(ns, result.Accepted, rejected) {
+		patterns, err := detector.Detect()
+		if err != nil {
+			return result, fmt.Errorf("incremental detection failed: %w", err)
+		}
+		result.Detected = true
+		result.Patterns = patterns
+	}
+
+	return result, nil
+}
+
+// GetIngestor 获取观测注入器
+func (m *Manager) GetIngestor() *Ingestor {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.components.ingestor == nil {
+		m.components.ingestor = NewIngestor(m.config)
+	}
+	return m.components.ingestor
+}
@@ -12,9 +12,12 @@ import (
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/common"
 	"github.com/Corphon/daoflow/system/control"
+	"github.com/Corphon/daoflow/system/encryption"
 	"github.com/Corphon/daoflow/system/meta/emergence"
 	"github.com/Corphon/daoflow/system/meta/field"
 	"github.com/Corphon/daoflow/system/meta/resonance"
+	"github.com/Corphon/daoflow/system/meta/visualize"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -31,16 +34,33 @@ type Manager struct {
 		detector  *emergence.PatternDetector    // 模式检测器
 		matcher   *resonance.PatternMatcher     // 模式匹配器
 		amplifier *resonance.ResonanceAmplifier // 共振放大器
+		timelapse *visualize.Timelapse          // 场演化记录器
+
+		// 命名空间场域
+		namespaces map[types.Namespace]*namespaceDomain
+
+		// 多场联邦
+		federation *FieldManager
+
+		// 观测注入器
+		ingestor *Ingestor
+
+		// 场状态快照日志及其加密钥匙串
+		journal *emergence.FieldJournal
+		keyring *encryption.Keyring
 	}
 
 	// 元系统状态
 	state struct {
-		status    string                  // 运行状态
-		startTime time.Time               // 启动时间
-		emergence []types.EmergentPattern // 涌现模式
-		resonance []common.ResonanceState // 共振状态
-		energy    float64                 // 系统能量
-		metrics   map[string]float64      // 系统指标
+		status     string                  // 运行状态
+		startTime  time.Time               // 启动时间
+		emergence  []types.EmergentPattern // 涌现模式
+		resonance  []common.ResonanceState // 共振状态
+		energy     float64                 // 系统能量
+		metrics    map[string]float64      // 系统指标
+		backend    string                  // 实际生效的场张量计算后端
+		backendErr string                  // 后端回退到纯Go实现的原因
+		draining   bool                    // 关闭前排空中, 不再接收观测
 	}
 
 	// 核心依赖
@@ -67,11 +87,28 @@ func NewManager(cfg *types.MetaConfig) (*Manager, error) {
 		cancel: cancel,
 	}
 
+	// 选择场张量计算后端, 不可用时回退到纯Go实现并记录原因
+	if cfg.FieldBackend != "" {
+		backend, err := field.UseBackend(cfg.FieldBackend)
+		m.state.backend = backend
+		if err != nil {
+			m.state.backendErr = err.Error()
+		}
+	}
+
 	// 初始化组件
 	if err := m.initComponents(); err != nil {
 		cancel()
 		return nil, err
 	}
+	m.components.namespaces = make(map[types.Namespace]*namespaceDomain)
+	m.components.ingestor = NewIngestor(cfg)
+	federation, err := newFieldManager(m, cfg.Federation)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	m.components.federation = federation
 
 	// 初始化状态
 	m.state.status = "initialized"
@@ -126,13 +163,35 @@ func (m *Manager) Start(ctx context.Context) error {
 		return nil
 	}
 
+	// 组件循环沿用调用方上下文中的协程监管器
+	if sup := supervisor.FromContext(ctx); sup != nil && supervisor.FromContext(m.ctx) != sup {
+		m.ctx = supervisor.NewContext(m.ctx, sup)
+	}
+
+	// 打开场状态快照日志
+	if err := m.openJournal(); err != nil {
+		return err
+	}
+
 	// 启动各组件
 	if err := m.startComponents(); err != nil {
+		m.closeJournal()
 		return err
 	}
 
+	// 启动命名空间场域
+	if err := m.startNamespaces(); err != nil {
+		m.stopComponents()
+		m.closeJournal()
+		return err
+	}
+
+	// 启动场间耦合同步
+	supervisor.Go(m.ctx, "meta.federation", m.components.federation.run)
+
 	m.state.status = "running"
 	m.state.startTime = time.Now()
+	m.state.draining = false
 	return nil
 }
 
@@ -152,7 +211,9 @@ func (m *Manager) Stop() error {
 
 	m.cancel()
 	m.state.status = "stopped"
-	return nil
+
+	// 组件停止后不再有检测写入日志
+	return m.closeJournal()
 }
 
 // Status 获取管理器状态
@@ -162,6 +223,17 @@ func (m *Manager) Status() string {
 	return m.state.status
 }
 
+// Health 健康度(0-1), 运行中为1, 未运行为0
+func (m *Manager) Health() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.state.status != "running" {
+		return 0
+	}
+	return 1
+}
+
 // Wait 等待管理器停止
 func (m *Manager) Wait() {
 	<-m.ctx.Done()
@@ -173,12 +245,35 @@ func (m *Manager) GetMetrics() map[string]interface{} {
 	defer m.mu.RUnlock()
 
 	return map[string]interface{}{
-		"status":    m.state.status,
-		"uptime":    time.Since(m.state.startTime).String(),
-		"energy":    m.state.energy,
-		"emergence": len(m.state.emergence),
-		"resonance": len(m.state.resonance),
-		"field":     m.components.field.GetMetrics(),
+		"status":     m.state.status,
+		"uptime":     time.Since(m.state.startTime).String(),
+		"energy":     m.state.energy,
+		"emergence":  len(m.state.emergence),
+		"resonance":  len(m.state.resonance),
+		"field":      m.components.field.GetMetrics(),
+		"namespaces": len(m.components.namespaces),
+		"links":      len(m.components.federation.Links()),
+		"detection":  detectionIntervalMetrics(m.components.detector),
+		"plugins":    m.components.detector.GetPluginMetrics(),
+		"priority":   m.priorityStats(),
+		"thresholds": m.components.matcher.ThresholdStats(),
+		"timelapse":  m.components.timelapse.GetMetrics(),
+		"backend": map[string]string{
+			"configured": m.config.FieldBackend,
+			"selected":   m.state.backend,
+			"active":     field.CurrentBackend(),
+			"error":      m.state.backendErr,
+		},
+	}
+}
+
+// detectionIntervalMetrics 模式检测间隔指标
+func detectionIntervalMetrics(detector *emergence.PatternDetector) map[string]float64 {
+	if tuner := detector.GetIntervalTuner(); tuner != nil {
+		return tuner.GetMetrics()
+	}
+	return map[string]float64{
+		"effective_interval_ms": float64(detector.EffectiveInterval()) / float64(time.Millisecond),
 	}
 }
 
@@ -189,8 +284,82 @@ func (m *Manager) InjectCore(core *core.Engine) {
 	m.core = core
 }
 
+// GetField 获取统一场
+func (m *Manager) GetField() *field.UnifiedField {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.field
+}
+
+// GetDetector 获取模式检测器
+func (m *Manager) GetDetector() *emergence.PatternDetector {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.detector
+}
+
+// GetMatcher 获取共振模式匹配器
+func (m *Manager) GetMatcher() *resonance.PatternMatcher {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.matcher
+}
+
+// GetPatternTypes 获取模式类型注册表, 注册的类型对所有命名空间的检测器生效
+func (m *Manager) GetPatternTypes() *emergence.PatternTypeRegistry {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.detector.TypeRegistry()
+}
+
+// GetTimelapse 获取场演化记录器
+func (m *Manager) GetTimelapse() *visualize.Timelapse {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.timelapse
+}
+
+// VisualizeField 生成统一场当前的可视化帧, 不记入演化历史
+func (m *Manager) VisualizeField(opts visualize.Options) (*visualize.Frame, error) {
+	m.mu.RLock()
+	field := m.components.field
+	m.mu.RUnlock()
+
+	state, err := field.GetState()
+	if err != nil {
+		return nil, err
+	}
+	if opts.MaxCells <= 0 {
+		opts.MaxCells = m.config.Visualization.MaxCells
+	}
+	return visualize.Capture(state, opts)
+}
+
 // 私有方法
 
+// configureCouplings 按配置设置场的耦合上限和强度阈值, 未配置阈值时使用Field.Coupling.Threshold
+func (m *Manager) configureCouplings(f *field.UnifiedField) error {
+	return m.configureFieldCouplings(f, m.config.Field.Coupling.MaxPairs, m.config.CouplingThresholds)
+}
+
+// configureRepresentations 按配置将场组件切换为稀疏表示
+func (m *Manager) configureRepresentations(f *field.UnifiedField) error {
+	for _, name := range m.config.SparseComponents {
+		if err := f.SetComponentRepresentation(name, field.RepresentationSparse); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// configureFieldCouplings 按给定上限和阈值设置场的耦合, 未配置阈值时使用Field.Coupling.Threshold
+func (m *Manager) configureFieldCouplings(f *field.UnifiedField, maxPairs int, thresholds types.CouplingThresholdConfig) error {
+	if len(thresholds.Levels) == 0 && m.config.Field.Coupling.Threshold > 0 {
+		thresholds.Levels = []float64{m.config.Field.Coupling.Threshold}
+	}
+	return f.ConfigureCouplings(maxPairs, thresholds)
+}
+
 // initComponents 初始化组件
 func (m *Manager) initComponents() error {
 	// 1. 初始化统一场
@@ -198,6 +367,12 @@ func (m *Manager) initComponents() error {
 	if err != nil {
 		return err
 	}
+	if err := m.configureCouplings(field); err != nil {
+		return err
+	}
+	if err := m.configureRepresentations(field); err != nil {
+		return err
+	}
 	m.components.field = field
 
 	// 2. 初始化模式检测器
@@ -205,6 +380,12 @@ func (m *Manager) initComponents() error {
 	if detector == nil {
 		return fmt.Errorf("failed to create pattern detector")
 	}
+	detector.Configure(0, 0, m.config.Emergence.DetectionInterval)
+	detector.SetIntervalTuning(m.config.Emergence.Tuning)
+	detector.SetRetention(m.config.Emergence.Retention)
+	if err := detector.SetGeometry(m.config.Geometry); err != nil {
+		return fmt.Errorf("invalid field geometry: %w", err)
+	}
 	m.components.detector = detector
 
 	// 3. 初始化属性生成器
@@ -218,6 +399,9 @@ func (m *Manager) initComponents() error {
 	if matcher == nil {
 		return fmt.Errorf("failed to create pattern matcher")
 	}
+	if err := matcher.SetThresholds(m.config.Resonance.Thresholds); err != nil {
+		return err
+	}
 	m.components.matcher = matcher
 
 	// 5. 初始化共振放大器
@@ -230,6 +414,15 @@ func (m *Manager) initComponents() error {
 	// 6. 设置匹配器的放大器引用
 	matcher.SetAmplifier(amplifier)
 
+	// 各处理阶段的模式优先级调度
+	if err := validatePriority(m.config.Emergence.Priority); err != nil {
+		return err
+	}
+	m.applyPriority()
+
+	// 7. 初始化场演化记录器
+	m.components.timelapse = visualize.NewTimelapse(field.GetState, m.config.Visualization)
+
 	return nil
 }
 
@@ -261,6 +454,17 @@ func (m *Manager) startComponents() error {
 		return fmt.Errorf("failed to start amplifier: %w", err)
 	}
 
+	// 5. 启动场演化记录
+	if m.config.Visualization.Enabled {
+		if err := m.components.timelapse.Start(m.ctx); err != nil {
+			m.components.amplifier.Stop()
+			m.components.matcher.Stop()
+			m.components.detector.Stop()
+			m.components.field.Stop()
+			return fmt.Errorf("failed to start timelapse: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -269,22 +473,34 @@ func (m *Manager) stopComponents() error {
 	var errs []error
 
 	// 按依赖关系的反序停止组件
-	// 1. 停止共振放大器
+	// 0. 停止命名空间场域
+	for ns, domain := range m.components.namespaces {
+		if err := domain.stop(); err != nil {
+			errs = append(errs, fmt.Errorf("failed to stop namespace %s: %w", ns, err))
+		}
+	}
+
+	// 1. 停止场演化记录
+	if err := m.components.timelapse.Stop(); err != nil {
+		errs = append(errs, fmt.Errorf("failed to stop timelapse: %w", err))
+	}
+
+	// 2. 停止共振放大器
 	if err := m.components.amplifier.Stop(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to stop amplifier: %w", err))
 	}
 
-	// 2. 停止模式匹配器
+	// 3. 停止模式匹配器
 	if err := m.components.matcher.Stop(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to stop matcher: %w", err))
 	}
 
-	// 3. 停止模式检测器
+	// 4. 停止模式检测器
 	if err := m.components.detector.Stop(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to stop detector: %w", err))
 	}
 
-	// 4. 停止统一场
+	// 5. 停止统一场
 	if err := m.components.field.Stop(); err != nil {
 		errs = append(errs, fmt.Errorf("failed to stop field: %w", err))
 	}
@@ -0,0 +1,215 @@
+// system/meta/namespace.go
+
+package meta
+
+import (
+	"fmt"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// namespaceDomain 命名空间场域, 拥有独立的统一场和模式检测器
+type namespaceDomain struct {
+	config   *types.NamespaceConfig
+	field    *field.UnifiedField
+	detector *emergence.PatternDetector
+	running  bool
+	offline  bool // 回填生成的场域, 不随管理器启动
+}
+
+// CreateNamespace 创建隔离的命名空间场域
+func (m *Manager) CreateNamespace(cfg *types.NamespaceConfig) error {
+	if cfg == nil || cfg.Name == "" {
+		return types.NewSystemError(types.ErrInvalid, "invalid namespace config", nil)
+	}
+	if cfg.Name == types.DefaultNamespace {
+		return types.NewSystemError(types.ErrExists, "default namespace is built in", nil)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.components.namespaces[cfg.Name]; exists {
+		return types.NewSystemError(types.ErrExists, "namespace already exists", nil).
+			WithContext("namespace", cfg.Name)
+	}
+
+	domain, err := m.newNamespaceDomain(cfg)
+	if err != nil {
+		return err
+	}
+
+	// 管理器运行中则立即启动
+	if m.state.status == "running" {
+		if err := domain.start(m); err != nil {
+			return err
+		}
+	}
+
+	m.components.namespaces[cfg.Name] = domain
+	return nil
+}
+
+// newNamespaceDomain 创建命名空间场域, 未配置的参数沿用元系统配置(调用方持有锁)
+func (m *Manager) newNamespaceDomain(cfg *types.NamespaceConfig) (*namespaceDomain, error) {
+	// 独立的统一场, 未配置的场参数沿用元系统配置
+	strength := cfg.Field.InitialStrength
+	if strength == 0 {
+		strength = m.config.Field.InitialStrength
+	}
+	f, err := field.NewUnifiedField(strength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create namespace field: %w", err)
+	}
+	maxPairs := cfg.Field.MaxCouplings
+	if maxPairs == 0 {
+		maxPairs = m.config.Field.Coupling.MaxPairs
+	}
+	thresholds := cfg.Field.CouplingThresholds
+	if len(thresholds.Levels) == 0 {
+		thresholds = m.config.CouplingThresholds
+	}
+	if err := m.configureFieldCouplings(f, maxPairs, thresholds); err != nil {
+		return nil, fmt.Errorf("failed to configure namespace field: %w", err)
+	}
+	if err := m.configureRepresentations(f); err != nil {
+		return nil, fmt.Errorf("failed to configure namespace field: %w", err)
+	}
+
+	// 独立的检测器
+	detector := emergence.NewPatternDetector(f)
+	detector.SetNamespace(string(cfg.Name))
+	detector.Configure(cfg.Detection.Sensitivity, cfg.Detection.MinConfidence, cfg.Detection.Interval)
+	detector.SetPatternThreshold(cfg.Detection.PatternThreshold)
+	detector.SetRetention(m.config.Emergence.Retention)
+	detector.SetPriority(emergence.NewPriorityScheduler(m.config.Emergence.Priority))
+	geometry := cfg.Field.Geometry
+	if geometry == (core.Geometry{}) {
+		geometry = m.config.Geometry
+	}
+	if err := detector.SetGeometry(geometry); err != nil {
+		return nil, fmt.Errorf("invalid namespace field geometry: %w", err)
+	}
+	// 共享模式类型注册表, 注册一次即对所有命名空间生效
+	detector.SetTypeRegistry(m.components.detector.TypeRegistry())
+
+	return &namespaceDomain{
+		config:   cfg,
+		field:    f,
+		detector: detector,
+	}, nil
+}
+
+// RemoveNamespace 移除命名空间场域
+func (m *Manager) RemoveNamespace(ns types.Namespace) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	domain, exists := m.components.namespaces[ns]
+	if !exists {
+		return types.NewSystemError(types.ErrNotFound, "namespace not found", nil).
+			WithContext("namespace", ns)
+	}
+
+	if err := domain.stop(); err != nil {
+		return err
+	}
+
+	delete(m.components.namespaces, ns)
+	m.components.federation.dropField(ns)
+	return nil
+}
+
+// GetNamespaceDetector 获取命名空间的模式检测器, 默认命名空间返回主检测器
+func (m *Manager) GetNamespaceDetector(ns types.Namespace) *emergence.PatternDetector {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if ns == types.DefaultNamespace || ns == "" {
+		return m.components.detector
+	}
+	if domain, exists := m.components.namespaces[ns]; exists {
+		return domain.detector
+	}
+	return nil
+}
+
+// GetNamespaceField 获取命名空间的统一场, 默认命名空间返回主场
+func (m *Manager) GetNamespaceField(ns types.Namespace) *field.UnifiedField {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if ns == types.DefaultNamespace || ns == "" {
+		return m.components.field
+	}
+	if domain, exists := m.components.namespaces[ns]; exists {
+		return domain.field
+	}
+	return nil
+}
+
+// ListNamespaces 列出已创建的命名空间场域
+func (m *Manager) ListNamespaces() []types.Namespace {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	list := make([]types.Namespace, 0, len(m.components.namespaces)+1)
+	list = append(list, types.DefaultNamespace)
+	for ns := range m.components.namespaces {
+		list = append(list, ns)
+	}
+	return list
+}
+
+// namespaceRunning 命名空间场域是否运行中, 默认命名空间随管理器运行
+func (m *Manager) namespaceRunning(ns types.Namespace) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if ns == types.DefaultNamespace || ns == "" {
+		return m.state.status == "running"
+	}
+	domain, exists := m.components.namespaces[ns]
+	return exists && domain.running
+}
+
+// startNamespaces 启动所有命名空间场域(调用方持有锁)
+func (m *Manager) startNamespaces() error {
+	for ns, domain := range m.components.namespaces {
+		if err := domain.start(m); err != nil {
+			return fmt.Errorf("failed to start namespace %s: %w", ns, err)
+		}
+	}
+	return nil
+}
+
+// start 启动场域
+func (d *namespaceDomain) start(m *Manager) error {
+	if d.running || d.offline {
+		return nil
+	}
+	if err := d.field.Start(m.ctx); err != nil {
+		return fmt.Errorf("failed to start field: %w", err)
+	}
+	if err := d.detector.Start(m.ctx); err != nil {
+		d.field.Stop()
+		return fmt.Errorf("failed to start detector: %w", err)
+	}
+	d.running = true
+	return nil
+}
+
+// stop 停止场域
+func (d *namespaceDomain) stop() error {
+	if !d.running {
+		return nil
+	}
+	d.running = false
+	if err := d.detector.Stop(); err != nil {
+		return err
+	}
+	return d.field.Stop()
+}
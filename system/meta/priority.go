@@ -0,0 +1,90 @@
+// system/meta/priority.go
+
+package meta
+
+import (
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 模式处理阶段, 每个阶段独立调度
+const (
+	PriorityStageNotify  = "notify"  // 新模式通知
+	PriorityStageMatch   = "match"   // 共振匹配
+	PriorityStageAmplify = "amplify" // 共振放大
+)
+
+// SetPriority 以新配置替换各处理阶段的优先级调度, 已记录的推迟周期和统计随之重置
+func (m *Manager) SetPriority(cfg types.PriorityConfig) error {
+	if err := validatePriority(cfg); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.config.Emergence.Priority = cfg
+	m.applyPriority()
+	return nil
+}
+
+// GetPriority 获取模式处理优先级配置
+func (m *Manager) GetPriority() types.PriorityConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config.Emergence.Priority
+}
+
+// PriorityStats 各处理阶段的优先级统计, 命名空间检测器的通知阶段以"notify/<命名空间>"为键; 未启用时返回nil
+func (m *Manager) PriorityStats() map[string][]emergence.PriorityStats {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.priorityStats()
+}
+
+// priorityStats 各处理阶段的优先级统计(调用方持有锁)
+func (m *Manager) priorityStats() map[string][]emergence.PriorityStats {
+	if !m.config.Emergence.Priority.Enabled || m.components.detector == nil {
+		return nil
+	}
+	stats := map[string][]emergence.PriorityStats{
+		PriorityStageNotify:  m.components.detector.GetPriority().Stats(),
+		PriorityStageMatch:   m.components.matcher.GetPriority().Stats(),
+		PriorityStageAmplify: m.components.amplifier.GetPriority().Stats(),
+	}
+	for ns, domain := range m.components.namespaces {
+		stats[PriorityStageNotify+"/"+string(ns)] = domain.detector.GetPriority().Stats()
+	}
+	return stats
+}
+
+// applyPriority 为各处理阶段创建新的优先级调度(调用方持有锁)
+func (m *Manager) applyPriority() {
+	cfg := m.config.Emergence.Priority
+	m.components.detector.SetPriority(emergence.NewPriorityScheduler(cfg))
+	m.components.matcher.SetPriority(emergence.NewPriorityScheduler(cfg))
+	m.components.amplifier.SetPriority(emergence.NewPriorityScheduler(cfg))
+	for _, domain := range m.components.namespaces {
+		domain.detector.SetPriority(emergence.NewPriorityScheduler(cfg))
+	}
+}
+
+// validatePriority 校验优先级配置: 类别名称非空且不重复, 配额和强度不为负
+func validatePriority(cfg types.PriorityConfig) error {
+	if cfg.DefaultQuota < 0 || cfg.MaxWait < 0 {
+		return types.NewSystemError(types.ErrInvalid, "negative priority quota or max wait", nil)
+	}
+	names := make(map[string]bool, len(cfg.Classes))
+	for _, class := range cfg.Classes {
+		if class.Name == "" || class.Name == emergence.DefaultPriorityClass || names[class.Name] {
+			return types.NewSystemError(types.ErrInvalid, "invalid or duplicate priority class name", nil).
+				WithContext("class", class.Name)
+		}
+		names[class.Name] = true
+		if class.Quota < 0 || class.MinStrength < 0 {
+			return types.NewSystemError(types.ErrInvalid, "negative priority class quota or strength", nil).
+				WithContext("class", class.Name)
+		}
+	}
+	return nil
+}
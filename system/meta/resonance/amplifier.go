@@ -9,8 +9,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/system/meta/emergence"
 	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -47,6 +49,9 @@ type ResonanceAmplifier struct {
 	field     *field.UnifiedField
 	detector  *emergence.PatternDetector
 	generator *emergence.PropertyGenerator
+
+	// 各周期参与共振检测的模式的优先级调度, 为nil时检测全部模式
+	priority *emergence.PriorityScheduler
 }
 
 // ResonanceState 共振状态
@@ -121,14 +126,17 @@ func NewResonanceAmplifier(
 
 // Amplify 执行共振放大
 func (ra *ResonanceAmplifier) Amplify() error {
+	// 检测在放大器锁外执行, 检测监听器可能回调其他子系统
+	patterns, err := ra.detector.Detect()
+	if err != nil {
+		return err
+	}
+
 	ra.mu.Lock()
 	defer ra.mu.Unlock()
 
 	// 检测新的共振
-	newResonances, err := ra.detectResonances()
-	if err != nil {
-		return err
-	}
+	newResonances := ra.detectResonances(patterns)
 
 	// 更新现有共振
 	if err := ra.updateResonances(); err != nil {
@@ -148,13 +156,10 @@ func (ra *ResonanceAmplifier) Amplify() error {
 	return nil
 }
 
-// detectResonances 检测新的共振
-func (ra *ResonanceAmplifier) detectResonances() ([]*ResonanceState, error) {
-	// 获取当前模式
-	patterns, err := ra.detector.Detect()
-	if err != nil {
-		return nil, err
-	}
+// detectResonances 检测当前模式间新的共振
+func (ra *ResonanceAmplifier) detectResonances(patterns []emergence.EmergentPattern) []*ResonanceState {
+	// 按优先级选出本周期参与共振检测的模式
+	patterns, _ = ra.priority.Schedule(patterns)
 
 	resonances := make([]*ResonanceState, 0)
 
@@ -170,7 +175,7 @@ func (ra *ResonanceAmplifier) detectResonances() ([]*ResonanceState, error) {
 		}
 	}
 
-	return resonances, nil
+	return resonances
 }
 
 // checkResonance 检查共振关系
@@ -529,7 +534,7 @@ func (ra *ResonanceAmplifier) applyDecay(resonance *ResonanceState) {
 }
 
 func generateResonanceID() string {
-	return fmt.Sprintf("res_%d", time.Now().UnixNano())
+	return core.NewID("res_")
 }
 
 func copyResonanceState(state *ResonanceState) *ResonanceState {
@@ -541,13 +546,27 @@ func copyResonanceState(state *ResonanceState) *ResonanceState {
 	return &copy
 }
 
+// SetPriority 设置共振检测的优先级调度, nil恢复每周期检测全部模式
+func (ra *ResonanceAmplifier) SetPriority(priority *emergence.PriorityScheduler) {
+	ra.mu.Lock()
+	defer ra.mu.Unlock()
+	ra.priority = priority
+}
+
+// GetPriority 获取共振检测的优先级调度
+func (ra *ResonanceAmplifier) GetPriority() *emergence.PriorityScheduler {
+	ra.mu.RLock()
+	defer ra.mu.RUnlock()
+	return ra.priority
+}
+
 // Start 启动共振放大器
 func (ra *ResonanceAmplifier) Start(ctx context.Context) error {
 	ra.mu.Lock()
 	defer ra.mu.Unlock()
 
 	// 启动放大循环
-	go ra.amplificationLoop(ctx)
+	supervisor.Go(ctx, "meta.resonance.amplification", ra.amplificationLoop)
 
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/meta/emergence"
 	"github.com/Corphon/daoflow/system/meta/field"
@@ -322,7 +323,7 @@ func (cr *CrossResonance) handleLayerResonance(resonance *LayerResonance) error
 
 // generateBridgeID 复用已有的ID生成模式
 func generateBridgeID() string {
-	return fmt.Sprintf("bridge_%d", time.Now().UnixNano())
+	return core.NewID("bridge_")
 }
 
 // calculatePhaseDifference 计算两层之间的相位差
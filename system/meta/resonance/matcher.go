@@ -9,8 +9,10 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -36,6 +38,18 @@ type PatternMatcher struct {
 	// 依赖项
 	detector  *emergence.PatternDetector
 	amplifier *ResonanceAmplifier
+
+	// 各周期匹配模式的优先级调度, 为nil时匹配全部模式
+	priority *emergence.PriorityScheduler
+
+	// 按模式类型的相似度阈值
+	thresholds *matchThresholds
+
+	// 匹配跨度的接收方, 为nil时只生成追踪上下文
+	reporter types.SpanReporter
+
+	// 首次匹配监听器
+	listeners []func(MatchState)
 }
 
 // MatchState 匹配状态
@@ -48,6 +62,7 @@ type MatchState struct {
 	StartTime  time.Time                  // 开始时间
 	LastUpdate time.Time                  // 最后更新时间
 	Properties map[string]float64         // 匹配属性
+	Trace      types.TraceContext         // 首次匹配的跨度, 同一模式和模板的后续匹配沿用
 }
 
 // MatchTemplate 匹配模板
@@ -85,6 +100,7 @@ type MatchEvent struct {
 	Pattern    string
 	Similarity float64
 	Success    bool
+	Trace      types.TraceContext
 }
 
 // ----------------------------------------
@@ -108,33 +124,52 @@ func NewPatternMatcher(
 	pm.state.matches = make(map[string]*MatchState)
 	pm.state.templates = make(map[string]*MatchTemplate)
 	pm.state.history = make([]MatchEvent, 0)
+	pm.thresholds = newMatchThresholds(types.MatchThresholdConfig{})
 
 	return pm
 }
 
 // Match 执行模式匹配
 func (pm *PatternMatcher) Match() error {
-	pm.mu.Lock()
-	defer pm.mu.Unlock()
-
-	// 获取当前模式
+	// 检测和首次匹配通知在匹配器锁外执行, 监听器可能回调其他子系统
 	patterns, err := pm.detector.Detect()
 	if err != nil {
 		return err
 	}
 
+	first, listeners := pm.match(patterns)
+	for _, match := range first {
+		for _, listener := range listeners {
+			listener(match)
+		}
+	}
+	return nil
+}
+
+// match 匹配本周期的模式, 返回首次匹配和当前的监听器
+func (pm *PatternMatcher) match(patterns []emergence.EmergentPattern) ([]MatchState, []func(MatchState)) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+
+	// 按优先级选出本周期匹配的模式
+	patterns, _ = pm.priority.Schedule(patterns)
+
 	// 对每个模式进行匹配
+	first := make([]MatchState, 0)
 	for _, pattern := range patterns {
 		matches := pm.matchPattern(pattern)
 
 		// 更新匹配状态
-		pm.updateMatches(pattern, matches)
+		first = append(first, pm.updateMatches(pattern, matches)...)
 	}
 
+	// 按本周期的相似度样本校准阈值
+	pm.thresholds.calibrate()
+
 	// 清理过期匹配
 	pm.cleanupMatches()
 
-	return nil
+	return first, append([]func(MatchState){}, pm.listeners...)
 }
 
 // RegisterTemplate 注册匹配模板
@@ -178,9 +213,9 @@ func (pm *PatternMatcher) matchAgainstTemplate(
 	pattern emergence.EmergentPattern,
 	template *MatchTemplate) *MatchState {
 
-	// 计算特征相似度
+	// 计算特征相似度, 按模式类型的阈值过滤
 	similarity := pm.calculateSimilarity(pattern, template)
-	if similarity < pm.config.minSimilarity {
+	if !pm.thresholds.accept(pattern.Type, similarity, pm.config.minSimilarity) {
 		return nil
 	}
 
@@ -431,12 +466,31 @@ func (pm *PatternMatcher) evaluateConstraint(
 }
 
 // updateMatches 更新匹配状态
+// 返回模式与模板首次匹配的匹配状态
 func (pm *PatternMatcher) updateMatches(
 	pattern emergence.EmergentPattern,
-	newMatches []*MatchState) {
+	newMatches []*MatchState) []MatchState {
+
+	first := make([]MatchState, 0)
 
 	// 记录匹配事件
 	for _, match := range newMatches {
+		// 模式与模板首次匹配时以模式的检测跨度为父跨度上报匹配跨度
+		trace, seen := pm.matchTrace(pattern.ID, match.Template.ID)
+		if !seen {
+			trace = types.ChildSpan(pm.reporter, pattern.Trace, types.CausalSpan{
+				Name:      "resonance.match",
+				Subsystem: "meta.resonance",
+				Start:     match.StartTime,
+				Tags: map[string]string{
+					"match":    match.ID,
+					"pattern":  pattern.ID,
+					"template": match.Template.ID,
+				},
+			})
+		}
+		match.Trace = trace
+
 		event := MatchEvent{
 			Timestamp:  time.Now(),
 			MatchID:    match.ID,
@@ -445,12 +499,42 @@ func (pm *PatternMatcher) updateMatches(
 			Pattern:    pattern.ID,
 			Similarity: match.Similarity,
 			Success:    true,
+			Trace:      trace,
 		}
 		pm.recordMatchEvent(event)
 
 		// 更新或添加匹配状态
 		pm.state.matches[match.ID] = match
+
+		if !seen {
+			first = append(first, *match)
+		}
 	}
+	return first
+}
+
+// matchTrace 模式与模板现有匹配的追踪上下文
+func (pm *PatternMatcher) matchTrace(patternID, templateID string) (types.TraceContext, bool) {
+	for _, match := range pm.state.matches {
+		if match.Pattern != nil && match.Pattern.ID == patternID && match.Template.ID == templateID {
+			return match.Trace, true
+		}
+	}
+	return types.TraceContext{}, false
+}
+
+// SetSpanReporter 设置匹配跨度的接收方, nil时只生成追踪上下文
+func (pm *PatternMatcher) SetSpanReporter(reporter types.SpanReporter) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.reporter = reporter
+}
+
+// OnMatch 注册首次匹配监听器, 模式与模板首次匹配时在匹配周期结束后调用
+func (pm *PatternMatcher) OnMatch(listener func(MatchState)) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.listeners = append(pm.listeners, listener)
 }
 
 // cleanupMatches 清理过期匹配
@@ -495,11 +579,25 @@ func (pm *PatternMatcher) validateTemplate(template *MatchTemplate) error {
 		return model.WrapError(nil, model.ErrCodeValidation, "weights must sum to 1")
 	}
 
+	// 按类型匹配的特征须引用已注册的模式类型
+	if pm.detector != nil {
+		registry := pm.detector.TypeRegistry()
+		for _, feature := range template.Features {
+			if feature.Type != "categorical" || feature.Name != "type" {
+				continue
+			}
+			if name, ok := feature.Value.(string); ok && !registry.Has(name) {
+				return model.NewModelError(model.ErrCodeValidation,
+					fmt.Sprintf("unknown pattern type %s", name), nil)
+			}
+		}
+	}
+
 	return nil
 }
 
 func generateMatchID() string {
-	return fmt.Sprintf("match_%d", time.Now().UnixNano())
+	return core.NewID("match_")
 }
 
 func (pm *PatternMatcher) recordMatchEvent(event MatchEvent) {
@@ -592,13 +690,27 @@ func (pm *PatternMatcher) SetAmplifier(amplifier *ResonanceAmplifier) {
 	pm.amplifier = amplifier
 }
 
+// SetPriority 设置匹配的优先级调度, nil恢复每周期匹配全部模式
+func (pm *PatternMatcher) SetPriority(priority *emergence.PriorityScheduler) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.priority = priority
+}
+
+// GetPriority 获取匹配的优先级调度
+func (pm *PatternMatcher) GetPriority() *emergence.PriorityScheduler {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.priority
+}
+
 // Start 启动模式匹配器
 func (pm *PatternMatcher) Start(ctx context.Context) error {
 	pm.mu.Lock()
 	defer pm.mu.Unlock()
 
 	// 启动匹配循环
-	go pm.matchingLoop(ctx)
+	supervisor.Go(ctx, "meta.resonance.matching", pm.matchingLoop)
 
 	return nil
 }
@@ -0,0 +1,244 @@
+// system/meta/resonance/thresholds.go
+
+package resonance
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 阈值来源
+const (
+	ThresholdGlobal     = "global"     // 匹配器的全局最小相似度
+	ThresholdConfigured = "configured" // 按类型配置的阈值
+	ThresholdCalibrated = "calibrated" // 按相似度分布自动校准的阈值
+)
+
+// 默认校准参数
+const (
+	defaultCalibrationPercentile = 0.75
+	defaultCalibrationWindow     = 1000
+	defaultCalibrationMinSamples = 100
+)
+
+// ThresholdStats 模式类型的匹配阈值统计
+type ThresholdStats struct {
+	Type      string  // 模式类型
+	Threshold float64 // 当前阈值
+	Source    string  // 阈值来源
+	Samples   int     // 保留的相似度样本数
+	Evaluated int64   // 与模板比较的次数
+	Accepted  int64   // 相似度达到阈值的次数
+}
+
+// typeThreshold 单个模式类型的阈值状态
+type typeThreshold struct {
+	samples    []float64 // 近期相似度, 环形缓冲
+	next       int       // 下一个写入位置
+	calibrated float64   // 校准阈值
+	ready      bool      // 是否已校准
+	dirty      bool      // 有新样本待校准
+	evaluated  int64
+	accepted   int64
+}
+
+// matchThresholds 按模式类型的匹配阈值(由匹配器的锁保护)
+type matchThresholds struct {
+	config types.MatchThresholdConfig
+	types  map[string]*typeThreshold
+}
+
+// newMatchThresholds 创建阈值状态, 校准参数的零值取默认值
+func newMatchThresholds(cfg types.MatchThresholdConfig) *matchThresholds {
+	if cfg.Calibration.Percentile == 0 {
+		cfg.Calibration.Percentile = defaultCalibrationPercentile
+	}
+	if cfg.Calibration.Window <= 0 {
+		cfg.Calibration.Window = defaultCalibrationWindow
+	}
+	if cfg.Calibration.MinSamples <= 0 {
+		cfg.Calibration.MinSamples = defaultCalibrationMinSamples
+	}
+	if cfg.Calibration.Ceiling == 0 {
+		cfg.Calibration.Ceiling = 1
+	}
+	return &matchThresholds{
+		config: cfg,
+		types:  make(map[string]*typeThreshold),
+	}
+}
+
+// validateThresholds 校验阈值配置
+func validateThresholds(cfg types.MatchThresholdConfig) error {
+	for name, threshold := range cfg.Types {
+		if threshold < 0 || threshold > 1 || math.IsNaN(threshold) {
+			return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "match threshold must be in [0, 1]", nil).
+				WithContext("type", name)
+		}
+	}
+	c := cfg.Calibration
+	if c.Percentile < 0 || c.Percentile > 1 || math.IsNaN(c.Percentile) {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "calibration percentile must be in [0, 1]", nil)
+	}
+	if c.Window < 0 || c.MinSamples < 0 {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "negative calibration window or min samples", nil)
+	}
+	ceiling := c.Ceiling
+	if ceiling == 0 {
+		ceiling = 1
+	}
+	if c.Floor < 0 || ceiling > 1 || c.Floor > ceiling {
+		return types.NewDomainError(types.DomainPattern, types.ErrInvalid, "calibration bounds must satisfy 0 <= floor <= ceiling <= 1", nil)
+	}
+	return nil
+}
+
+// threshold 模式类型的当前阈值及来源, global为全局最小相似度
+func (t *matchThresholds) threshold(patternType string, global float64) (float64, string) {
+	if state, exists := t.types[patternType]; exists && state.ready {
+		return state.calibrated, ThresholdCalibrated
+	}
+	if threshold, exists := t.config.Types[patternType]; exists {
+		return threshold, ThresholdConfigured
+	}
+	return global, ThresholdGlobal
+}
+
+// accept 记录相似度样本, 返回是否达到类型的阈值
+func (t *matchThresholds) accept(patternType string, similarity, global float64) bool {
+	state := t.state(patternType)
+	state.evaluated++
+	if t.config.Calibration.Enabled && !math.IsNaN(similarity) {
+		if len(state.samples) < t.config.Calibration.Window {
+			state.samples = append(state.samples, similarity)
+		} else {
+			state.samples[state.next] = similarity
+		}
+		state.next = (state.next + 1) % t.config.Calibration.Window
+		state.dirty = true
+	}
+
+	threshold, _ := t.threshold(patternType, global)
+	if similarity < threshold {
+		return false
+	}
+	state.accepted++
+	return true
+}
+
+// calibrate 按近期相似度分布重新校准有新样本的类型
+func (t *matchThresholds) calibrate() {
+	if !t.config.Calibration.Enabled {
+		return
+	}
+	c := t.config.Calibration
+	for _, state := range t.types {
+		if !state.dirty || len(state.samples) < c.MinSamples {
+			continue
+		}
+		sorted := append([]float64(nil), state.samples...)
+		sort.Float64s(sorted)
+		state.calibrated = math.Max(c.Floor, math.Min(c.Ceiling, percentile(sorted, c.Percentile)))
+		state.ready = true
+		state.dirty = false
+	}
+}
+
+// reset 丢弃类型的相似度样本和校准结果, patternType为空时作用于全部类型
+func (t *matchThresholds) reset(patternType string) {
+	for name, state := range t.types {
+		if patternType != "" && name != patternType {
+			continue
+		}
+		state.samples = nil
+		state.next = 0
+		state.calibrated = 0
+		state.ready = false
+		state.dirty = false
+	}
+}
+
+// state 获取或创建类型的阈值状态
+func (t *matchThresholds) state(patternType string) *typeThreshold {
+	state, exists := t.types[patternType]
+	if !exists {
+		state = &typeThreshold{}
+		t.types[patternType] = state
+	}
+	return state
+}
+
+// stats 各类型的阈值统计, 包括已配置但尚未出现的类型, 按类型排序
+func (t *matchThresholds) stats(global float64) []ThresholdStats {
+	names := make(map[string]bool, len(t.types)+len(t.config.Types))
+	for name := range t.types {
+		names[name] = true
+	}
+	for name := range t.config.Types {
+		names[name] = true
+	}
+
+	stats := make([]ThresholdStats, 0, len(names))
+	for name := range names {
+		threshold, source := t.threshold(name, global)
+		s := ThresholdStats{Type: name, Threshold: threshold, Source: source}
+		if state, exists := t.types[name]; exists {
+			s.Samples = len(state.samples)
+			s.Evaluated = state.evaluated
+			s.Accepted = state.accepted
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Type < stats[j].Type })
+	return stats
+}
+
+// percentile 线性插值分位数, sorted须已排序
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// SetThresholds 设置按模式类型的匹配阈值, 已有的相似度样本和校准结果清空
+func (pm *PatternMatcher) SetThresholds(cfg types.MatchThresholdConfig) error {
+	if err := validateThresholds(cfg); err != nil {
+		return err
+	}
+
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.thresholds = newMatchThresholds(cfg)
+	return nil
+}
+
+// Recalibrate 丢弃模式类型的相似度样本并重新校准, patternType为空时作用于全部类型
+// 新样本达到MinSamples前使用配置阈值或全局阈值, 用于相似度分布漂移后避免沿用过时的校准
+func (pm *PatternMatcher) Recalibrate(patternType string) {
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	pm.thresholds.reset(patternType)
+}
+
+// Threshold 获取模式类型当前的匹配阈值及来源
+func (pm *PatternMatcher) Threshold(patternType string) (float64, string) {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.thresholds.threshold(patternType, pm.config.minSimilarity)
+}
+
+// ThresholdStats 获取各模式类型的匹配阈值统计
+func (pm *PatternMatcher) ThresholdStats() []ThresholdStats {
+	pm.mu.RLock()
+	defer pm.mu.RUnlock()
+	return pm.thresholds.stats(pm.config.minSimilarity)
+}
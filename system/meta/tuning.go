@@ -0,0 +1,29 @@
+// system/meta/tuning.go
+
+package meta
+
+import (
+	"context"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// TuneThresholds 以记录的场状态快照回放调优命名空间ns的检测阈值, 默认命名空间使用主检测器
+// 试验在独立的检测器上进行, 不改变现有检测器; 推荐的阈值由调用方以TuningParams.Apply或ApplyNamespace应用
+func (m *Manager) TuneThresholds(ctx context.Context, ns types.Namespace, source emergence.SnapshotSource, opts emergence.TuningOptions) (*emergence.TuningReport, error) {
+	template := m.GetNamespaceDetector(ns)
+	if template == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "namespace not found", nil).
+			WithContext("namespace", ns)
+	}
+	if source == nil {
+		return nil, types.NewSystemError(types.ErrInvalid, "nil snapshot source", nil)
+	}
+
+	snapshots, err := emergence.CollectSnapshots(source)
+	if err != nil {
+		return nil, err
+	}
+	return emergence.TuneThresholds(ctx, template, snapshots, opts)
+}
@@ -0,0 +1,68 @@
+// system/meta/visualize/frame.go
+
+package visualize
+
+import (
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// Options 帧生成选项
+type Options struct {
+	Bounds           *Bounds // 固定网格范围, 为空时取能量分布范围
+	MaxCells         int     // 最大网格单元数
+	ClusterThreshold float64 // 聚集阈值, <=0时取非零能量均值加一个标准差
+}
+
+// Frame 场的一帧可视化数据
+type Frame struct {
+	Timestamp  time.Time          `json:"timestamp"`  // 场状态时间
+	Energy     float64            `json:"energy"`     // 场总能量
+	Properties map[string]float64 `json:"properties"` // 场属性
+	Heatmap    *Grid              `json:"heatmap"`    // 能量热力图
+	Gradient   *VectorField       `json:"gradient"`   // 能量梯度
+	Clusters   []Cluster          `json:"clusters"`   // 能量聚集
+}
+
+// Capture 由场状态生成可视化帧
+func Capture(state *model.FieldState, opts Options) (*Frame, error) {
+	if state == nil {
+		return nil, model.NewModelError(model.ErrCodeValidation, "nil field state", nil)
+	}
+
+	dist := state.GetEnergyDistribution()
+
+	var bounds Bounds
+	if opts.Bounds != nil {
+		bounds = *opts.Bounds
+	} else if b, ok := DistributionBounds(dist); ok {
+		bounds = b
+	}
+
+	grid, err := NewGrid(dist, bounds, opts.MaxCells)
+	if err != nil {
+		return nil, err
+	}
+
+	threshold := opts.ClusterThreshold
+	if threshold <= 0 {
+		threshold = grid.DefaultThreshold()
+	}
+
+	frame := &Frame{
+		Timestamp:  state.Timestamp,
+		Energy:     state.Energy,
+		Properties: make(map[string]float64, len(state.Properties)),
+		Heatmap:    grid,
+		Gradient:   grid.Gradient(),
+		Clusters:   grid.Clusters(threshold),
+	}
+	if frame.Timestamp.IsZero() {
+		frame.Timestamp = time.Now()
+	}
+	for k, v := range state.Properties {
+		frame.Properties[k] = v
+	}
+	return frame, nil
+}
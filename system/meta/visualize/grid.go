@@ -0,0 +1,321 @@
+// system/meta/visualize/grid.go
+
+package visualize
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+)
+
+// 默认最大网格单元数
+const defaultMaxCells = 256 * 256
+
+// Bounds 网格范围, 包含Min和Max
+type Bounds struct {
+	Min core.Point `json:"min"`
+	Max core.Point `json:"max"`
+}
+
+// Width 网格宽度
+func (b Bounds) Width() int {
+	return b.Max.X - b.Min.X + 1
+}
+
+// Height 网格高度
+func (b Bounds) Height() int {
+	return b.Max.Y - b.Min.Y + 1
+}
+
+// Contains 判断点是否在范围内
+func (b Bounds) Contains(p core.Point) bool {
+	return p.X >= b.Min.X && p.X <= b.Max.X && p.Y >= b.Min.Y && p.Y <= b.Max.Y
+}
+
+// Union 合并两个范围
+func (b Bounds) Union(o Bounds) Bounds {
+	return Bounds{
+		Min: core.Point{X: minInt(b.Min.X, o.Min.X), Y: minInt(b.Min.Y, o.Min.Y)},
+		Max: core.Point{X: maxInt(b.Max.X, o.Max.X), Y: maxInt(b.Max.Y, o.Max.Y)},
+	}
+}
+
+// Grid 能量热力图网格
+type Grid struct {
+	Bounds Bounds    `json:"bounds"` // 网格范围
+	Width  int       `json:"width"`  // 宽度
+	Height int       `json:"height"` // 高度
+	Values []float64 `json:"values"` // 按行存储的能量值, 下标为y*Width+x
+	Min    float64   `json:"min"`    // 最小能量
+	Max    float64   `json:"max"`    // 最大能量
+	Total  float64   `json:"total"`  // 总能量
+}
+
+// Vector 网格点上的向量
+type Vector struct {
+	X         int     `json:"x"`         // 横坐标
+	Y         int     `json:"y"`         // 纵坐标
+	DX        float64 `json:"dx"`        // 横向分量
+	DY        float64 `json:"dy"`        // 纵向分量
+	Magnitude float64 `json:"magnitude"` // 模长
+}
+
+// VectorField 能量梯度向量场
+type VectorField struct {
+	Bounds       Bounds   `json:"bounds"`        // 网格范围
+	Vectors      []Vector `json:"vectors"`       // 非零向量
+	MaxMagnitude float64  `json:"max_magnitude"` // 最大模长
+}
+
+// Cluster 能量聚集区域
+type Cluster struct {
+	ID       int        `json:"id"`       // 序号
+	Cells    int        `json:"cells"`    // 单元数
+	Energy   float64    `json:"energy"`   // 总能量
+	CenterX  float64    `json:"center_x"` // 能量加权中心横坐标
+	CenterY  float64    `json:"center_y"` // 能量加权中心纵坐标
+	Peak     core.Point `json:"peak"`     // 能量峰值点
+	Radius   float64    `json:"radius"`   // 到中心的最大距离
+	Bounds   Bounds     `json:"bounds"`   // 覆盖范围
+	Strength float64    `json:"strength"` // 平均能量
+}
+
+// DistributionBounds 计算能量分布的范围
+func DistributionBounds(dist map[core.Point]float64) (Bounds, bool) {
+	first := true
+	var b Bounds
+	for p := range dist {
+		if first {
+			b = Bounds{Min: p, Max: p}
+			first = false
+			continue
+		}
+		b = b.Union(Bounds{Min: p, Max: p})
+	}
+	return b, !first
+}
+
+// NewGrid 将能量分布栅格化为网格, 范围外的点被忽略
+func NewGrid(dist map[core.Point]float64, bounds Bounds, maxCells int) (*Grid, error) {
+	if maxCells <= 0 {
+		maxCells = defaultMaxCells
+	}
+	width, height := bounds.Width(), bounds.Height()
+	if width <= 0 || height <= 0 {
+		return nil, model.NewModelError(model.ErrCodeValidation, "empty grid bounds", nil)
+	}
+	if width*height > maxCells {
+		return nil, model.NewModelError(model.ErrCodeLimit,
+			fmt.Sprintf("grid of %dx%d exceeds %d cells", width, height, maxCells), nil)
+	}
+
+	g := &Grid{
+		Bounds: bounds,
+		Width:  width,
+		Height: height,
+		Values: make([]float64, width*height),
+	}
+	for p, energy := range dist {
+		if bounds.Contains(p) {
+			g.Values[(p.Y-bounds.Min.Y)*width+(p.X-bounds.Min.X)] += energy
+		}
+	}
+
+	g.Min, g.Max = math.Inf(1), math.Inf(-1)
+	for _, v := range g.Values {
+		g.Min = math.Min(g.Min, v)
+		g.Max = math.Max(g.Max, v)
+		g.Total += v
+	}
+	return g, nil
+}
+
+// At 获取网格坐标处的能量, 范围外为0
+func (g *Grid) At(p core.Point) float64 {
+	if !g.Bounds.Contains(p) {
+		return 0
+	}
+	return g.Values[(p.Y-g.Bounds.Min.Y)*g.Width+(p.X-g.Bounds.Min.X)]
+}
+
+// Rows 按行返回能量矩阵, 便于直接绘图
+func (g *Grid) Rows() [][]float64 {
+	rows := make([][]float64, g.Height)
+	for y := range rows {
+		rows[y] = g.Values[y*g.Width : (y+1)*g.Width]
+	}
+	return rows
+}
+
+// Gradient 计算能量梯度, 内部点使用中心差分, 边界使用单侧差分
+func (g *Grid) Gradient() *VectorField {
+	vf := &VectorField{
+		Bounds:  g.Bounds,
+		Vectors: make([]Vector, 0),
+	}
+
+	for y := 0; y < g.Height; y++ {
+		for x := 0; x < g.Width; x++ {
+			dx := g.diff(x, y, 1, 0)
+			dy := g.diff(x, y, 0, 1)
+			magnitude := math.Hypot(dx, dy)
+			if magnitude == 0 {
+				continue
+			}
+			vf.Vectors = append(vf.Vectors, Vector{
+				X:         g.Bounds.Min.X + x,
+				Y:         g.Bounds.Min.Y + y,
+				DX:        dx,
+				DY:        dy,
+				Magnitude: magnitude,
+			})
+			vf.MaxMagnitude = math.Max(vf.MaxMagnitude, magnitude)
+		}
+	}
+	return vf
+}
+
+// Clusters 提取能量不低于阈值的四连通区域, 按能量降序编号
+func (g *Grid) Clusters(threshold float64) []Cluster {
+	visited := make([]bool, len(g.Values))
+	clusters := make([]Cluster, 0)
+
+	for start, v := range g.Values {
+		if visited[start] || v <= 0 || v < threshold {
+			continue
+		}
+
+		// 广度优先扩展, 避免大区域递归过深
+		cells := []int{start}
+		visited[start] = true
+		for i := 0; i < len(cells); i++ {
+			x, y := cells[i]%g.Width, cells[i]/g.Width
+			for _, d := range [][2]int{{-1, 0}, {1, 0}, {0, -1}, {0, 1}} {
+				nx, ny := x+d[0], y+d[1]
+				if nx < 0 || ny < 0 || nx >= g.Width || ny >= g.Height {
+					continue
+				}
+				n := ny*g.Width + nx
+				if !visited[n] && g.Values[n] > 0 && g.Values[n] >= threshold {
+					visited[n] = true
+					cells = append(cells, n)
+				}
+			}
+		}
+		clusters = append(clusters, g.describeCluster(cells))
+	}
+
+	sortClusters(clusters)
+	return clusters
+}
+
+// DefaultThreshold 默认聚集阈值: 非零单元能量的均值加一个标准差
+func (g *Grid) DefaultThreshold() float64 {
+	var sum, sumSq float64
+	n := 0
+	for _, v := range g.Values {
+		if v > 0 {
+			sum += v
+			sumSq += v * v
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	mean := sum / float64(n)
+	variance := math.Max(0, sumSq/float64(n)-mean*mean)
+	return mean + math.Sqrt(variance)
+}
+
+// diff 沿方向(ox, oy)的差分
+func (g *Grid) diff(x, y, ox, oy int) float64 {
+	value := func(x, y int) (float64, bool) {
+		if x < 0 || y < 0 || x >= g.Width || y >= g.Height {
+			return 0, false
+		}
+		return g.Values[y*g.Width+x], true
+	}
+
+	next, hasNext := value(x+ox, y+oy)
+	prev, hasPrev := value(x-ox, y-oy)
+	current, _ := value(x, y)
+
+	switch {
+	case hasNext && hasPrev:
+		return (next - prev) / 2
+	case hasNext:
+		return next - current
+	case hasPrev:
+		return current - prev
+	default:
+		return 0
+	}
+}
+
+// describeCluster 计算聚集区域特征
+func (g *Grid) describeCluster(cells []int) Cluster {
+	c := Cluster{Cells: len(cells)}
+
+	peak := math.Inf(-1)
+	for i, idx := range cells {
+		x, y := idx%g.Width, idx/g.Width
+		p := core.Point{X: g.Bounds.Min.X + x, Y: g.Bounds.Min.Y + y}
+		v := g.Values[idx]
+
+		c.Energy += v
+		c.CenterX += float64(p.X) * v
+		c.CenterY += float64(p.Y) * v
+		if v > peak {
+			peak = v
+			c.Peak = p
+		}
+		if i == 0 {
+			c.Bounds = Bounds{Min: p, Max: p}
+		} else {
+			c.Bounds = c.Bounds.Union(Bounds{Min: p, Max: p})
+		}
+	}
+	c.CenterX /= c.Energy
+	c.CenterY /= c.Energy
+	c.Strength = c.Energy / float64(c.Cells)
+
+	for _, idx := range cells {
+		px := float64(g.Bounds.Min.X + idx%g.Width)
+		py := float64(g.Bounds.Min.Y + idx/g.Width)
+		c.Radius = math.Max(c.Radius, math.Hypot(px-c.CenterX, py-c.CenterY))
+	}
+	return c
+}
+
+// sortClusters 按能量降序排序并编号
+func sortClusters(clusters []Cluster) {
+	sort.SliceStable(clusters, func(i, j int) bool {
+		return clusters[i].Energy > clusters[j].Energy
+	})
+	for i := range clusters {
+		clusters[i].ID = i + 1
+	}
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// pointAt 网格局部坐标对应的场坐标
+func pointAt(b Bounds, x, y int) core.Point {
+	return core.Point{X: b.Min.X + x, Y: b.Min.Y + y}
+}
@@ -0,0 +1,209 @@
+// system/meta/visualize/render.go
+
+package visualize
+
+import (
+	"bufio"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"io"
+	"math"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// 默认参数
+const (
+	defaultScale      = 8
+	defaultFrameDelay = 200 * time.Millisecond
+)
+
+// colorStops 热力图色阶, 由低到高
+var colorStops = []color.RGBA{
+	{R: 68, G: 1, B: 84, A: 255},
+	{R: 59, G: 82, B: 139, A: 255},
+	{R: 33, G: 145, B: 140, A: 255},
+	{R: 94, G: 201, B: 98, A: 255},
+	{R: 253, G: 231, B: 37, A: 255},
+}
+
+// RenderOptions 图像输出选项
+type RenderOptions struct {
+	Scale      int           // 每个网格单元的像素数
+	Gradient   bool          // 是否绘制梯度箭头(仅SVG)
+	Clusters   bool          // 是否标注能量聚集(仅SVG)
+	FrameDelay time.Duration // 动画帧间隔(仅GIF)
+}
+
+// EncodePNG 将热力图输出为PNG
+func EncodePNG(w io.Writer, grid *Grid, opts RenderOptions) error {
+	if grid == nil {
+		return model.NewModelError(model.ErrCodeValidation, "nil grid", nil)
+	}
+	scale := scaleOf(opts)
+
+	img := image.NewRGBA(image.Rect(0, 0, grid.Width*scale, grid.Height*scale))
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			c := ColorAt(normalize(grid.Values[y*grid.Width+x], grid.Min, grid.Max))
+			fillRect(img, x*scale, y*scale, scale, c)
+		}
+	}
+	return png.Encode(w, img)
+}
+
+// EncodeSVG 将帧输出为SVG, 可叠加梯度箭头和聚集轮廓
+func EncodeSVG(w io.Writer, frame *Frame, opts RenderOptions) error {
+	if frame == nil || frame.Heatmap == nil {
+		return model.NewModelError(model.ErrCodeValidation, "nil frame", nil)
+	}
+	grid := frame.Heatmap
+	scale := scaleOf(opts)
+	s := float64(scale)
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		grid.Width*scale, grid.Height*scale, grid.Width*scale, grid.Height*scale)
+	fmt.Fprintf(bw, "<title>field %s energy=%.4f</title>\n", frame.Timestamp.Format(time.RFC3339Nano), frame.Energy)
+
+	// 热力图
+	fmt.Fprintln(bw, `<g shape-rendering="crispEdges">`)
+	for y := 0; y < grid.Height; y++ {
+		for x := 0; x < grid.Width; x++ {
+			v := grid.Values[y*grid.Width+x]
+			c := ColorAt(normalize(v, grid.Min, grid.Max))
+			fmt.Fprintf(bw, `<rect x="%d" y="%d" width="%d" height="%d" fill="#%02x%02x%02x"><title>(%d,%d) %.4f</title></rect>`+"\n",
+				x*scale, y*scale, scale, scale, c.R, c.G, c.B,
+				grid.Bounds.Min.X+x, grid.Bounds.Min.Y+y, v)
+		}
+	}
+	fmt.Fprintln(bw, "</g>")
+
+	// 梯度箭头, 长度按最大模长归一到半个单元
+	if opts.Gradient && frame.Gradient != nil && frame.Gradient.MaxMagnitude > 0 {
+		fmt.Fprintln(bw, `<g stroke="#ffffff" stroke-width="1" fill="none">`)
+		for _, v := range frame.Gradient.Vectors {
+			cx := (float64(v.X-grid.Bounds.Min.X) + 0.5) * s
+			cy := (float64(v.Y-grid.Bounds.Min.Y) + 0.5) * s
+			length := v.Magnitude / frame.Gradient.MaxMagnitude * s / 2
+			ex := cx + v.DX/v.Magnitude*length
+			ey := cy + v.DY/v.Magnitude*length
+			fmt.Fprintf(bw, `<line x1="%.2f" y1="%.2f" x2="%.2f" y2="%.2f"/>`+"\n", cx, cy, ex, ey)
+			fmt.Fprintf(bw, `<circle cx="%.2f" cy="%.2f" r="%.2f" fill="#ffffff"/>`+"\n", ex, ey, math.Max(0.5, s/16))
+		}
+		fmt.Fprintln(bw, "</g>")
+	}
+
+	// 聚集轮廓
+	if opts.Clusters {
+		fmt.Fprintln(bw, `<g stroke="#ff3b30" stroke-width="2" fill="none">`)
+		for _, c := range frame.Clusters {
+			cx := (c.CenterX - float64(grid.Bounds.Min.X) + 0.5) * s
+			cy := (c.CenterY - float64(grid.Bounds.Min.Y) + 0.5) * s
+			r := (c.Radius + 0.5) * s
+			fmt.Fprintf(bw, `<circle cx="%.2f" cy="%.2f" r="%.2f"><title>cluster %d energy=%.4f cells=%d</title></circle>`+"\n",
+				cx, cy, r, c.ID, c.Energy, c.Cells)
+		}
+		fmt.Fprintln(bw, "</g>")
+	}
+
+	fmt.Fprintln(bw, "</svg>")
+	return bw.Flush()
+}
+
+// EncodeGIF 将帧序列输出为动画GIF
+// 各帧对齐到共同范围并使用统一色阶, 便于比较不同时刻的能量
+func EncodeGIF(w io.Writer, frames []*Frame, opts RenderOptions) error {
+	if len(frames) == 0 {
+		return model.NewModelError(model.ErrCodeValidation, "no frames to encode", nil)
+	}
+	scale := scaleOf(opts)
+	delay := opts.FrameDelay
+	if delay <= 0 {
+		delay = defaultFrameDelay
+	}
+
+	// 共同范围和色阶
+	bounds := frames[0].Heatmap.Bounds
+	lo, hi := math.Inf(1), math.Inf(-1)
+	for _, f := range frames {
+		if f == nil || f.Heatmap == nil {
+			return model.NewModelError(model.ErrCodeValidation, "nil frame", nil)
+		}
+		bounds = bounds.Union(f.Heatmap.Bounds)
+		lo = math.Min(lo, f.Heatmap.Min)
+		hi = math.Max(hi, f.Heatmap.Max)
+	}
+	// 范围外的区域视为零能量
+	lo = math.Min(lo, 0)
+
+	palette := make(color.Palette, 256)
+	for i := range palette {
+		palette[i] = ColorAt(float64(i) / 255)
+	}
+
+	width, height := bounds.Width(), bounds.Height()
+	anim := &gif.GIF{}
+	for _, f := range frames {
+		img := image.NewPaletted(image.Rect(0, 0, width*scale, height*scale), palette)
+		for y := 0; y < height; y++ {
+			for x := 0; x < width; x++ {
+				v := f.Heatmap.At(pointAt(bounds, x, y))
+				idx := uint8(math.Round(normalize(v, lo, hi) * 255))
+				for dy := 0; dy < scale; dy++ {
+					for dx := 0; dx < scale; dx++ {
+						img.SetColorIndex(x*scale+dx, y*scale+dy, idx)
+					}
+				}
+			}
+		}
+		anim.Image = append(anim.Image, img)
+		anim.Delay = append(anim.Delay, int(delay/(10*time.Millisecond)))
+	}
+	return gif.EncodeAll(w, anim)
+}
+
+// ColorAt 色阶插值, t取值0-1
+func ColorAt(t float64) color.RGBA {
+	t = math.Max(0, math.Min(1, t))
+	pos := t * float64(len(colorStops)-1)
+	i := int(pos)
+	if i >= len(colorStops)-1 {
+		return colorStops[len(colorStops)-1]
+	}
+	frac := pos - float64(i)
+	a, b := colorStops[i], colorStops[i+1]
+	lerp := func(x, y uint8) uint8 {
+		return uint8(math.Round(float64(x) + (float64(y)-float64(x))*frac))
+	}
+	return color.RGBA{R: lerp(a.R, b.R), G: lerp(a.G, b.G), B: lerp(a.B, b.B), A: 255}
+}
+
+// normalize 归一化到0-1, 区间退化时返回0
+func normalize(v, lo, hi float64) float64 {
+	if hi <= lo {
+		return 0
+	}
+	return (v - lo) / (hi - lo)
+}
+
+// scaleOf 像素缩放
+func scaleOf(opts RenderOptions) int {
+	if opts.Scale <= 0 {
+		return defaultScale
+	}
+	return opts.Scale
+}
+
+// fillRect 填充像素块
+func fillRect(img *image.RGBA, x0, y0, size int, c color.RGBA) {
+	for y := y0; y < y0+size; y++ {
+		for x := x0; x < x0+size; x++ {
+			img.SetRGBA(x, y, c)
+		}
+	}
+}
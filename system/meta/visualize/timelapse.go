@@ -0,0 +1,205 @@
+// system/meta/visualize/timelapse.go
+
+package visualize
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultInterval    = 5 * time.Second
+	defaultHistorySize = 720
+)
+
+// StateSource 场状态来源
+type StateSource func() (*model.FieldState, error)
+
+// Timelapse 场演化记录器
+// 按固定间隔采样场状态并保留最近的帧, 用于回放涌现过程
+type Timelapse struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config  types.VisualizationConfig
+	options Options
+
+	// 状态来源
+	source StateSource
+
+	// 帧历史, 按时间升序
+	frames []*Frame
+
+	// 运行状态
+	state struct {
+		running   bool
+		captured  int64
+		failures  int64
+		lastError string
+	}
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewTimelapse 创建场演化记录器
+func NewTimelapse(source StateSource, config types.VisualizationConfig) *Timelapse {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.HistorySize <= 0 {
+		config.HistorySize = defaultHistorySize
+	}
+
+	return &Timelapse{
+		config:  config,
+		options: Options{MaxCells: config.MaxCells},
+		source:  source,
+		frames:  make([]*Frame, 0),
+	}
+}
+
+// SetOptions 设置帧生成选项, 回放时固定Bounds可使各帧对齐
+func (t *Timelapse) SetOptions(opts Options) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if opts.MaxCells <= 0 {
+		opts.MaxCells = t.config.MaxCells
+	}
+	t.options = opts
+}
+
+// Start 启动周期采样
+func (t *Timelapse) Start(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.state.running {
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	t.cancel = cancel
+	t.state.running = true
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		supervisor.Run(ctx, "meta.visualize.timelapse", t.captureLoop)
+	}()
+	return nil
+}
+
+// Stop 停止采样, 已记录的帧保留
+func (t *Timelapse) Stop() error {
+	t.mu.Lock()
+	if !t.state.running {
+		t.mu.Unlock()
+		return nil
+	}
+	t.state.running = false
+	t.cancel()
+	t.mu.Unlock()
+
+	t.wg.Wait()
+	return nil
+}
+
+// Capture 立即采样一帧并记录
+func (t *Timelapse) Capture() (*Frame, error) {
+	t.mu.RLock()
+	opts := t.options
+	t.mu.RUnlock()
+
+	state, err := t.source()
+	if err == nil {
+		var frame *Frame
+		if frame, err = Capture(state, opts); err == nil {
+			t.record(frame)
+			return frame, nil
+		}
+	}
+
+	t.mu.Lock()
+	t.state.failures++
+	t.state.lastError = err.Error()
+	t.mu.Unlock()
+	return nil, err
+}
+
+// Frames 获取时间范围[from, to)内的帧, 零值表示不限
+func (t *Timelapse) Frames(from, to time.Time) []*Frame {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	frames := make([]*Frame, 0)
+	for _, f := range t.frames {
+		if !from.IsZero() && f.Timestamp.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !f.Timestamp.Before(to) {
+			break
+		}
+		frames = append(frames, f)
+	}
+	return frames
+}
+
+// Latest 获取最新一帧
+func (t *Timelapse) Latest() *Frame {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if len(t.frames) == 0 {
+		return nil
+	}
+	return t.frames[len(t.frames)-1]
+}
+
+// GetMetrics 获取记录指标
+func (t *Timelapse) GetMetrics() map[string]interface{} {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return map[string]interface{}{
+		"running":    t.state.running,
+		"frames":     len(t.frames),
+		"captured":   t.state.captured,
+		"failures":   t.state.failures,
+		"last_error": t.state.lastError,
+		"interval":   t.config.Interval.String(),
+	}
+}
+
+// captureLoop 采样循环
+func (t *Timelapse) captureLoop(ctx context.Context) {
+	ticker := time.NewTicker(t.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			// 失败已记录在指标中, 下一周期重试
+			t.Capture()
+		}
+	}
+}
+
+// record 记录帧并限制历史长度
+func (t *Timelapse) record(frame *Frame) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.frames = append(t.frames, frame)
+	if excess := len(t.frames) - t.config.HistorySize; excess > 0 {
+		t.frames = t.frames[excess:]
+	}
+	t.state.captured++
+}
@@ -0,0 +1,73 @@
+// system/mode.go
+
+package system
+
+import (
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// degradedSlowdown 降级模式下检测和因果发现循环的间隔倍率
+const degradedSlowdown = 4.0
+
+// Mode 获取当前运行模式
+func (s *System) Mode() types.ModeStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.mode
+}
+
+// SetMode 切换运行模式
+// 降级模式放慢检测和学习循环并停用突变, 维护模式拒绝外部触发的转换, 只读模式同时拒绝转换和停用突变;
+// 监控在所有模式下照常运行
+func (s *System) SetMode(mode types.OperatingMode, reason string) error {
+	return s.setMode(mode, reason, false)
+}
+
+// setMode 切换运行模式, auto表示由看门狗自动切换
+func (s *System) setMode(mode types.OperatingMode, reason string, auto bool) error {
+	if !mode.Valid() {
+		return types.NewSystemError(types.ErrInvalid, "unknown operating mode", nil).
+			WithContext("mode", mode)
+	}
+
+	s.mu.Lock()
+	if s.mode.Mode == mode {
+		s.mu.Unlock()
+		return nil
+	}
+	s.mode = types.ModeStatus{
+		Mode:     mode,
+		Previous: s.mode.Mode,
+		Reason:   reason,
+		Since:    time.Now(),
+		Auto:     auto,
+	}
+	status := s.mode
+	s.applyMode()
+	s.mu.Unlock()
+
+	s.HandleEvent(types.SystemEvent{
+		Type:      types.EventModeChanged,
+		Source:    "system",
+		Timestamp: status.Since,
+		Message:   reason,
+		Priority:  types.PriorityHigh,
+		Data:      status,
+	})
+	return nil
+}
+
+// applyMode 按当前运行模式和资源预算节流设置循环间隔和突变开关, 调用方需持有锁
+func (s *System) applyMode() {
+	slowdown := 1.0
+	if s.mode.Mode == types.ModeDegraded {
+		slowdown = degradedSlowdown
+	}
+	if detector := s.meta.GetDetector(); detector != nil {
+		detector.SetSlowdown(slowdown * s.throttleFactor(types.GovernedDetection))
+	}
+	s.evolution.GetCausalDiscoverer().SetSlowdown(slowdown * s.throttleFactor(types.GovernedCausal))
+	s.evolution.SetMutationsSuspended(!s.mode.Mode.AllowsMutations())
+}
@@ -0,0 +1,81 @@
+// system/modulation.go
+
+package system
+
+import (
+	"time"
+
+	"github.com/Corphon/daoflow/system/control/modulation"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 调制目标
+const (
+	ModulateSensitivity      = "detector.sensitivity" // 模式检测灵敏度
+	ModulateMinConfidence    = "detector.confidence"  // 模式最小置信度
+	ModulatePatternThreshold = "detector.threshold"   // 模式阈值
+	ModulateLearningRate     = "learning.rate"        // 自适应学习率
+)
+
+// startModulation 为参数时间调制器绑定检测器阈值和学习率
+// 以绑定时的参数值为基准, 启用时立即按当前时刻调制一次
+func (s *System) startModulation() {
+	modulator := s.control.GetModulator()
+	if modulator == nil {
+		return
+	}
+
+	if detector := s.meta.GetDetector(); detector != nil {
+		sensitivity, confidence, threshold := detector.GetThresholds()
+		modulator.Bind(modulation.Target{
+			Name: ModulateSensitivity, Base: sensitivity, Min: 0.01, Max: 1,
+			Apply: func(v float64) error {
+				detector.Configure(v, 0, 0)
+				return nil
+			},
+		})
+		modulator.Bind(modulation.Target{
+			Name: ModulateMinConfidence, Base: confidence, Min: 0.01, Max: 1,
+			Apply: func(v float64) error {
+				detector.Configure(0, v, 0)
+				return nil
+			},
+		})
+		modulator.Bind(modulation.Target{
+			Name: ModulatePatternThreshold, Base: threshold, Min: 0.01, Max: 1,
+			Apply: func(v float64) error {
+				detector.SetPatternThreshold(v)
+				return nil
+			},
+		})
+	}
+
+	if learning := s.evolution.GetLearning(); learning != nil {
+		modulator.Bind(modulation.Target{
+			Name: ModulateLearningRate, Base: learning.GetLearningRate(), Min: 1e-6, Max: 1,
+			Apply: func(v float64) error {
+				learning.SetLearningRate(v)
+				return nil
+			},
+		})
+	}
+
+	if modulator.Enabled() {
+		modulator.Tick(time.Now())
+	}
+}
+
+// SetModulationCurve 按配置设置调制目标的曲线, 下一次刷新生效
+func (s *System) SetModulationCurve(target string, config types.CurveConfig) error {
+	curve, err := modulation.NewCurve(config)
+	if err != nil {
+		return err
+	}
+	s.control.GetModulator().SetCurve(target, curve)
+	return nil
+}
+
+// ModulationValues 获取各调制目标最近一次的调制结果
+func (s *System) ModulationValues() []modulation.Value {
+	return s.control.GetModulator().Values()
+}
@@ -8,7 +8,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -110,12 +112,12 @@ func (d *Detector) Start(ctx context.Context) error {
 	d.mu.Lock()
 	if d.status.isRunning {
 		d.mu.Unlock()
-		return model.WrapError(nil, model.ErrCodeOperation, "detector already running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "detector already running", nil)
 	}
 	d.status.isRunning = true
 	d.mu.Unlock()
 
-	go d.detectionLoop(ctx)
+	supervisor.Go(ctx, "monitor.alert.detection", d.detectionLoop)
 	return nil
 }
 
@@ -142,7 +144,7 @@ func (d *Detector) Stop() error {
 	defer d.mu.Unlock()
 
 	if !d.status.isRunning {
-		return model.WrapError(nil, model.ErrCodeOperation, "detector not running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "detector not running", nil)
 	}
 
 	d.status.isRunning = false
@@ -155,7 +157,7 @@ func (d *Detector) AddCondition(condition *AlertCondition) error {
 	defer d.mu.Unlock()
 
 	if _, exists := d.conditions[condition.ID]; exists {
-		return model.WrapError(nil, model.ErrCodeValidation, "condition already exists")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "condition already exists", nil)
 	}
 
 	d.conditions[condition.ID] = condition
@@ -175,13 +177,13 @@ func (d *Detector) check() error {
 	// 获取系统指标
 	metrics, err := d.metricsSource.GetMetrics()
 	if err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "failed to get metrics")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to get metrics", err)
 	}
 
 	// 获取模型指标
 	modelMetrics, err := d.metricsSource.GetModelMetrics()
 	if err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "failed to get model metrics")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to get model metrics", err)
 	}
 
 	// 检查每个条件
@@ -265,7 +267,7 @@ func (d *Detector) triggerAlert(condition *AlertCondition, value float64, modelM
 	case d.alertChan <- alert:
 		return nil
 	default:
-		return model.WrapError(nil, model.ErrCodeResource, "alert channel full")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeResource, "alert channel full", nil)
 	}
 }
 
@@ -286,7 +288,7 @@ func (d *Detector) resolveAlert(condition *AlertCondition) error {
 	case d.alertChan <- alert:
 		return nil
 	default:
-		return model.WrapError(nil, model.ErrCodeResource, "alert channel full")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeResource, "alert channel full", nil)
 	}
 }
 
@@ -354,7 +356,7 @@ func (d *Detector) GetAlertChannel() <-chan types.AlertData {
 
 // generateAlertID 生成告警ID
 func generateAlertID() string {
-	return fmt.Sprintf("alert-%d", time.Now().UnixNano())
+	return core.NewID("alert-")
 }
 
 // generateAlertMessage 生成告警消息
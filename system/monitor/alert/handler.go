@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -157,14 +158,14 @@ func (h *AlertHandler) Start(ctx context.Context) error {
 	h.mu.Lock()
 	if h.status.isRunning {
 		h.mu.Unlock()
-		return model.WrapError(nil, model.ErrCodeOperation, "handler already running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "handler already running", nil)
 	}
 	h.status.isRunning = true
 	h.mu.Unlock()
 
 	// 启动处理循环
 	for i := 0; i < h.config.MaxConcurrent; i++ {
-		go h.processLoop(ctx)
+		supervisor.Go(ctx, "monitor.alert.handler", h.processLoop)
 	}
 
 	return nil
@@ -176,7 +177,7 @@ func (h *AlertHandler) Stop() error {
 	defer h.mu.Unlock()
 
 	if !h.status.isRunning {
-		return model.WrapError(nil, model.ErrCodeOperation, "handler not running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "handler not running", nil)
 	}
 
 	h.status.isRunning = false
@@ -186,14 +187,14 @@ func (h *AlertHandler) Stop() error {
 // Handle 处理告警
 func (h *AlertHandler) Handle(alert types.AlertData) error {
 	if !h.status.isRunning {
-		return model.WrapError(nil, model.ErrCodeOperation, "handler not running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "handler not running", nil)
 	}
 
 	select {
 	case h.queue <- alert:
 		return nil
 	default:
-		return model.WrapError(nil, model.ErrCodeResource, "alert queue full")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeResource, "alert queue full", nil)
 	}
 }
 
@@ -264,7 +265,7 @@ func (h *AlertHandler) handleAlert(ctx context.Context, alert types.AlertData) {
 		result.Duration = result.EndTime.Sub(result.StartTime)
 		if err != nil {
 			result.Status = "failed"
-			result.Error = model.WrapError(err, model.ErrCodeOperation, "handler execution failed")
+			result.Error = types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "handler execution failed", err)
 			h.recordError(result.Error)
 		} else {
 			result.Status = "success"
@@ -281,15 +282,15 @@ func (h *AlertHandler) RegisterHandler(name string, handler HandlerFunc) error {
 
 	// 参数验证
 	if name == "" {
-		return model.WrapError(nil, model.ErrCodeValidation, "empty handler name")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "empty handler name", nil)
 	}
 	if handler == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "nil handler function")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "nil handler function", nil)
 	}
 
 	// 检查是否已存在
 	if _, exists := h.handlers[name]; exists {
-		return model.WrapError(nil, model.ErrCodeValidation, "handler already registered")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "handler already registered", nil)
 	}
 
 	// 注册处理器
@@ -323,23 +324,23 @@ func (h *AlertHandler) registerDefaultHandlers() {
 		// 序列化日志
 		data, err := json.Marshal(logEntry)
 		if err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "failed to marshal log entry")
+			return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to marshal log entry", err)
 		}
 
 		// 写入日志文件
 		logPath := filepath.Join("logs", "alerts", time.Now().Format("2006-01-02")+".log")
 		if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "failed to create log directory")
+			return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to create log directory", err)
 		}
 
 		f, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
 		if err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "failed to open log file")
+			return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to open log file", err)
 		}
 		defer f.Close()
 
 		if _, err := f.Write(append(data, '\n')); err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "failed to write log")
+			return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to write log", err)
 		}
 
 		return nil
@@ -396,22 +397,22 @@ func (h *AlertHandler) registerDefaultHandlers() {
 	h.RegisterHandler("meta", func(ctx context.Context, alert types.AlertData) error {
 		// 更新模型状态
 		if err := h.updateModelState(alert); err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "failed to update model state")
+			return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to update model state", err)
 		}
 
 		// 根据告警类型执行相应的元系统响应
 		switch alert.Type {
 		case "energy_anomaly":
 			if err := h.handleEnergyAnomaly(alert); err != nil {
-				return model.WrapError(err, model.ErrCodeOperation, "failed to handle energy anomaly")
+				return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to handle energy anomaly", err)
 			}
 		case "coherence_violation":
 			if err := h.handleCoherenceViolation(alert); err != nil {
-				return model.WrapError(err, model.ErrCodeOperation, "failed to handle coherence violation")
+				return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to handle coherence violation", err)
 			}
 		case "quantum_fluctuation":
 			if err := h.handleQuantumFluctuation(alert); err != nil {
-				return model.WrapError(err, model.ErrCodeOperation, "failed to handle quantum fluctuation")
+				return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to handle quantum fluctuation", err)
 			}
 		}
 
@@ -425,13 +426,13 @@ func (h *AlertHandler) handleEnergyAnomaly(alert types.AlertData) error {
 	defer h.mu.Unlock()
 
 	if alert.ModelData == nil || alert.ModelData.Metrics == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "missing model metrics")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "missing model metrics", nil)
 	}
 
 	// 获取能量指标
 	metrics := alert.ModelData.Metrics
 	if metrics.Energy == (model.Energy{}) {
-		return model.WrapError(nil, model.ErrCodeValidation, "invalid energy metrics")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "invalid energy metrics", nil)
 	}
 
 	energy := metrics.Energy.Total
@@ -468,7 +469,7 @@ func (h *AlertHandler) handleCoherenceViolation(alert types.AlertData) error {
 	defer h.mu.Unlock()
 
 	if alert.ModelData == nil || alert.ModelData.Quantum == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "missing quantum state")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "missing quantum state", nil)
 	}
 
 	// 获取量子状态
@@ -509,7 +510,7 @@ func (h *AlertHandler) handleQuantumFluctuation(alert types.AlertData) error {
 	defer h.mu.Unlock()
 
 	if alert.ModelData == nil || alert.ModelData.Field == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "missing field state")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "missing field state", nil)
 	}
 
 	// 获取场状态
@@ -645,7 +646,7 @@ func (h *AlertHandler) recordResult(result HandlerResult) {
 	select {
 	case h.results <- result:
 	default:
-		h.recordError(model.WrapError(nil, model.ErrCodeResource, "result buffer full"))
+		h.recordError(types.NewDomainError(types.DomainMonitor, model.ErrCodeResource, "result buffer full", nil))
 	}
 }
 
@@ -707,7 +708,7 @@ func (h *Handler) handleAlert(ctx context.Context, alert types.AlertData) {
 	handler, exists := h.handlers[alert.Type]
 	if !exists {
 		result.Status = "failed"
-		result.Error = model.WrapError(nil, model.ErrCodeValidation, "no handler for alert type")
+		result.Error = types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "no handler for alert type", nil)
 		h.recordResult(result)
 		return
 	}
@@ -753,7 +754,7 @@ func (h *Handler) recordResult(result HandlerResult) {
 	select {
 	case h.results <- result:
 	default:
-		h.recordError(model.WrapError(nil, model.ErrCodeResource, "result buffer full"))
+		h.recordError(types.NewDomainError(types.DomainMonitor, model.ErrCodeResource, "result buffer full", nil))
 	}
 }
 
@@ -783,7 +784,7 @@ func (h *Handler) Start(ctx context.Context) error {
 	h.mu.Lock()
 	if h.status.IsRunning {
 		h.mu.Unlock()
-		return model.WrapError(nil, model.ErrCodeOperation, "handler already running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "handler already running", nil)
 	}
 	h.status.IsRunning = true
 	h.mu.Unlock()
@@ -794,7 +795,7 @@ func (h *Handler) Start(ctx context.Context) error {
 
 	// 启动处理循环
 	for i := 0; i < h.config.MaxConcurrent; i++ {
-		go h.processLoop(ctx)
+		supervisor.Go(ctx, "monitor.alert.handler", h.processLoop)
 	}
 
 	return nil
@@ -806,7 +807,7 @@ func (h *Handler) Stop() error {
 	defer h.mu.Unlock()
 
 	if !h.status.IsRunning {
-		return model.WrapError(nil, model.ErrCodeOperation, "handler not running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "handler not running", nil)
 	}
 
 	// 关闭通道
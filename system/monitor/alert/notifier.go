@@ -10,6 +10,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -109,13 +111,13 @@ func (n *Notifier) Start(ctx context.Context) error {
 	n.mu.Lock()
 	if n.status.isRunning {
 		n.mu.Unlock()
-		return types.NewSystemError(types.ErrRuntime, "notifier already running", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "notifier already running", nil)
 	}
 	n.status.isRunning = true
 	n.mu.Unlock()
 
 	// 启动通知处理循环
-	go n.processLoop(ctx)
+	supervisor.Go(ctx, "monitor.alert.notifier", n.processLoop)
 
 	return nil
 }
@@ -140,7 +142,7 @@ func (n *Notifier) AddTarget(target *NotificationTarget) error {
 			return types.WrapError(err, types.ErrInvalid, "invalid target configuration")
 		}
 	} else {
-		return types.NewSystemError(types.ErrNotFound, "notification channel not found", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrNotFound, "notification channel not found", nil)
 	}
 
 	n.targets[target.ID] = target
@@ -153,7 +155,7 @@ func (n *Notifier) RemoveTarget(id string) error {
 	defer n.mu.Unlock()
 
 	if _, exists := n.targets[id]; !exists {
-		return types.NewSystemError(types.ErrNotFound, "target not found", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrNotFound, "target not found", nil)
 	}
 
 	delete(n.targets, id)
@@ -171,14 +173,14 @@ func (n *Notifier) RegisterChannel(channel NotificationChannel, handler Notifica
 // Notify 发送告警通知
 func (n *Notifier) Notify(alert types.Alert) error {
 	if !n.status.isRunning {
-		return types.NewSystemError(types.ErrRuntime, "notifier not running", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "notifier not running", nil)
 	}
 
 	select {
 	case n.queue <- alert:
 		return nil
 	default:
-		return types.NewSystemError(types.ErrOverflow, "notification queue full", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrOverflow, "notification queue full", nil)
 	}
 }
 
@@ -232,7 +234,7 @@ func (n *Notifier) shouldNotify(target *NotificationTarget, alert types.Alert) b
 func (n *Notifier) sendNotification(ctx context.Context, target *NotificationTarget, alert types.Alert) {
 	handler, exists := n.channels[target.Channel]
 	if !exists {
-		n.recordError(types.NewSystemError(types.ErrNotFound, "channel handler not found", nil))
+		n.recordError(types.NewDomainError(types.DomainMonitor, types.ErrNotFound, "channel handler not found", nil))
 		return
 	}
 
@@ -261,7 +263,7 @@ func (n *Notifier) sendNotification(ctx context.Context, target *NotificationTar
 		TargetID:  target.ID,
 		AlertID:   alert.ID,
 		Status:    "failed",
-		Error:     types.NewSystemError(types.ErrRuntime, "max retries exceeded", nil),
+		Error:     types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "max retries exceeded", nil),
 		Timestamp: time.Now(),
 	})
 }
@@ -308,7 +310,7 @@ func (n *Notifier) recordResult(result NotificationResult) {
 	select {
 	case n.results <- result:
 	default:
-		n.recordError(types.NewSystemError(types.ErrOverflow, "result buffer full", nil))
+		n.recordError(types.NewDomainError(types.DomainMonitor, types.ErrOverflow, "result buffer full", nil))
 	}
 }
 
@@ -323,7 +325,7 @@ func (n *Notifier) recordError(err error) {
 
 // generateResultID 生成结果ID
 func generateResultID() string {
-	return fmt.Sprintf("result-%d", time.Now().UnixNano())
+	return core.NewID("result-")
 }
 
 // matchFilter 匹配过滤器
@@ -380,7 +382,7 @@ func (n *WebhookNotifier) Send(ctx context.Context, target *NotificationTarget,
 // Validate 验证配置
 func (n *WebhookNotifier) Validate(config map[string]string) error {
 	if _, exists := config["url"]; !exists {
-		return types.NewSystemError(types.ErrInvalid, "webhook URL not configured", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "webhook URL not configured", nil)
 	}
 	return nil
 }
@@ -397,7 +399,7 @@ func (n *MessageNotifier) Send(ctx context.Context, target *NotificationTarget,
 // Validate 验证配置
 func (n *MessageNotifier) Validate(config map[string]string) error {
 	if _, exists := config["endpoint"]; !exists {
-		return types.NewSystemError(types.ErrInvalid, "message endpoint not configured", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "message endpoint not configured", nil)
 	}
 	return nil
 }
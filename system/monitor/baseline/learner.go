@@ -0,0 +1,326 @@
+// system/monitor/baseline/learner.go
+
+package baseline
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultWindowSize    = 1000
+	defaultWarmupSamples = 30
+	defaultSigma         = 3.0
+	defaultPercentile    = 0.99
+)
+
+// Baseline 指标基线
+type Baseline struct {
+	Metric    string    `json:"metric"`     // 指标名
+	Count     int64     `json:"count"`      // 累计样本数
+	Samples   int       `json:"samples"`    // 窗口样本数
+	Mean      float64   `json:"mean"`       // 均值
+	StdDev    float64   `json:"std_dev"`    // 标准差
+	Min       float64   `json:"min"`        // 最小值
+	Max       float64   `json:"max"`        // 最大值
+	P50       float64   `json:"p50"`        // 中位数
+	P95       float64   `json:"p95"`        // 95分位
+	P99       float64   `json:"p99"`        // 99分位
+	Threshold float64   `json:"threshold"`  // 当前阈值
+	WarmedUp  bool      `json:"warmed_up"`  // 是否完成预热
+	UpdatedAt time.Time `json:"updated_at"` // 更新时间
+}
+
+// series 单个指标的滚动窗口
+type series struct {
+	values []float64 // 环形缓冲
+	next   int       // 下一写入位置
+	count  int64     // 累计样本数
+	dirty  bool      // 统计是否需要重算
+	stats  Baseline  // 缓存的统计
+}
+
+// Learner 基线学习器, 按指标维护滚动窗口并给出自适应阈值
+type Learner struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.BaselineConfig
+
+	// 指标窗口
+	series map[string]*series
+}
+
+// NewLearner 创建基线学习器
+func NewLearner(config types.BaselineConfig) *Learner {
+	if config.WindowSize <= 0 {
+		config.WindowSize = defaultWindowSize
+	}
+	if config.WarmupSamples <= 0 {
+		config.WarmupSamples = defaultWarmupSamples
+	}
+	if config.Sigma <= 0 {
+		config.Sigma = defaultSigma
+	}
+	if config.Percentile <= 0 || config.Percentile >= 1 {
+		config.Percentile = defaultPercentile
+	}
+	if config.Method == "" {
+		config.Method = types.BaselineSigma
+	}
+
+	return &Learner{
+		config: config,
+		series: make(map[string]*series),
+	}
+}
+
+// Observe 记录指标样本
+func (l *Learner) Observe(metric string, value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, exists := l.series[metric]
+	if !exists {
+		s = &series{values: make([]float64, 0, l.config.WindowSize)}
+		l.series[metric] = s
+	}
+
+	if len(s.values) < l.config.WindowSize {
+		s.values = append(s.values, value)
+	} else {
+		s.values[s.next] = value
+	}
+	s.next = (s.next + 1) % l.config.WindowSize
+	s.count++
+	s.dirty = true
+}
+
+// Threshold 获取指标阈值, 预热完成前返回fallback和false
+func (l *Learner) Threshold(metric string, fallback float64) (float64, bool) {
+	b, ok := l.Baseline(metric)
+	if !ok || !b.WarmedUp {
+		return fallback, false
+	}
+	return b.Threshold, true
+}
+
+// IsAnomalous 判断数值是否超出阈值
+func (l *Learner) IsAnomalous(metric string, value, fallback float64) (bool, float64) {
+	threshold, _ := l.Threshold(metric, fallback)
+	return value > threshold, threshold
+}
+
+// Baseline 获取指标基线
+func (l *Learner) Baseline(metric string) (Baseline, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	s, exists := l.series[metric]
+	if !exists {
+		return Baseline{}, false
+	}
+	return l.compute(metric, s), true
+}
+
+// Baselines 获取全部指标基线
+func (l *Learner) Baselines() map[string]Baseline {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	result := make(map[string]Baseline, len(l.series))
+	for metric, s := range l.series {
+		result[metric] = l.compute(metric, s)
+	}
+	return result
+}
+
+// Reset 清除指标基线, metric为空时清除全部
+func (l *Learner) Reset(metric string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if metric == "" {
+		l.series = make(map[string]*series)
+		return
+	}
+	delete(l.series, metric)
+}
+
+// compute 重算统计, 调用方持有锁
+func (l *Learner) compute(metric string, s *series) Baseline {
+	if !s.dirty {
+		return s.stats
+	}
+
+	n := len(s.values)
+	sorted := append([]float64{}, s.values...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	mean := sum / float64(n)
+
+	var variance float64
+	for _, v := range sorted {
+		variance += (v - mean) * (v - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(n))
+
+	b := Baseline{
+		Metric:    metric,
+		Count:     s.count,
+		Samples:   n,
+		Mean:      mean,
+		StdDev:    stdDev,
+		Min:       sorted[0],
+		Max:       sorted[n-1],
+		P50:       percentile(sorted, 0.50),
+		P95:       percentile(sorted, 0.95),
+		P99:       percentile(sorted, 0.99),
+		WarmedUp:  n >= l.config.WarmupSamples,
+		UpdatedAt: time.Now(),
+	}
+
+	switch l.config.Method {
+	case types.BaselinePercentile:
+		b.Threshold = percentile(sorted, l.config.Percentile)
+	default:
+		b.Threshold = mean + l.config.Sigma*stdDev
+	}
+
+	s.stats = b
+	s.dirty = false
+	return b
+}
+
+// percentile 线性插值分位数, sorted须已排序
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := p * float64(len(sorted)-1)
+	lo := int(math.Floor(rank))
+	hi := int(math.Ceil(rank))
+	if lo == hi {
+		return sorted[lo]
+	}
+	return sorted[lo] + (sorted[hi]-sorted[lo])*(rank-float64(lo))
+}
+
+// persistedSeries 持久化的指标窗口
+type persistedSeries struct {
+	Values []float64 `json:"values"`
+	Count  int64     `json:"count"`
+}
+
+// persistedState 持久化格式
+type persistedState struct {
+	SavedAt time.Time                  `json:"saved_at"`
+	Series  map[string]persistedSeries `json:"series"`
+}
+
+// Save 导出学习到的基线
+func (l *Learner) Save(w io.Writer) error {
+	l.mu.RLock()
+	state := persistedState{
+		SavedAt: time.Now(),
+		Series:  make(map[string]persistedSeries, len(l.series)),
+	}
+	for metric, s := range l.series {
+		// 按时间顺序导出环形缓冲
+		values := make([]float64, 0, len(s.values))
+		if len(s.values) == l.config.WindowSize {
+			values = append(values, s.values[s.next:]...)
+			values = append(values, s.values[:s.next]...)
+		} else {
+			values = append(values, s.values...)
+		}
+		state.Series[metric] = persistedSeries{Values: values, Count: s.count}
+	}
+	l.mu.RUnlock()
+
+	return json.NewEncoder(w).Encode(state)
+}
+
+// Load 导入基线, 覆盖同名指标
+func (l *Learner) Load(r io.Reader) error {
+	var state persistedState
+	if err := json.NewDecoder(r).Decode(&state); err != nil {
+		return types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "invalid baseline data", err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for metric, ps := range state.Series {
+		values := ps.Values
+		if len(values) == 0 {
+			continue
+		}
+		if len(values) > l.config.WindowSize {
+			values = values[len(values)-l.config.WindowSize:]
+		}
+		s := &series{
+			values: make([]float64, len(values), l.config.WindowSize),
+			next:   len(values) % l.config.WindowSize,
+			count:  ps.Count,
+			dirty:  true,
+		}
+		copy(s.values, values)
+		l.series[metric] = s
+	}
+	return nil
+}
+
+// SaveFile 保存基线到文件
+func (l *Learner) SaveFile(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to create baseline directory", err)
+	}
+
+	tmp := path + ".tmp"
+	file, err := os.Create(tmp)
+	if err != nil {
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to create baseline file", err)
+	}
+	if err := l.Save(file); err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to write baselines", err)
+	}
+	if err := file.Close(); err != nil {
+		os.Remove(tmp)
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to close baseline file", err)
+	}
+	return os.Rename(tmp, path)
+}
+
+// LoadFile 从文件加载基线, 文件不存在时忽略
+func (l *Learner) LoadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to open baseline file", err)
+	}
+	defer file.Close()
+	return l.Load(file)
+}
@@ -0,0 +1,332 @@
+// system/monitor/correlation/engine.go
+
+package correlation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 事件类型
+const (
+	EventPattern      = "pattern"      // 模式检测事件
+	EventTransform    = "transform"    // 模型转换调用
+	EventPerturbation = "perturbation" // 受控场扰动
+	EventAnomaly      = "anomaly"      // 性能异常
+)
+
+// 默认参数
+const (
+	defaultWindow      = 5 * time.Minute
+	defaultHalfLife    = 30 * time.Second
+	defaultMinScore    = 0.1
+	defaultMaxCauses   = 3
+	defaultHistorySize = 10000
+)
+
+// transformNames 转换模式名称
+var transformNames = map[model.TransformPattern]string{
+	model.PatternNone:    "none",
+	model.PatternNormal:  "normal",
+	model.PatternForward: "forward",
+	model.PatternReverse: "reverse",
+	model.PatternBalance: "balance",
+	model.PatternMutate:  "mutate",
+}
+
+// Event 时间线上的事件
+type Event struct {
+	Kind       string    // 事件类型
+	Source     string    // 来源: 命名空间、模型名或追踪ID
+	Reference  string    // 引用: 模式ID、转换模式或异常指标
+	Category   string    // 类别: 模式类型、转换类型或异常类型
+	Timestamp  time.Time // 发生时间
+	Confidence float64   // 置信度(0-1)
+	Failed     bool      // 转换是否失败
+}
+
+// Stats 关联统计
+type Stats struct {
+	Events    int   // 时间线事件数
+	Anomalies int64 // 处理的异常数
+	Annotated int64 // 标注了原因的异常数
+}
+
+// entry 时间线条目
+type entry struct {
+	Event
+	credited map[string]bool // 已计入的异常类型
+}
+
+// causeStats 候选原因的历史统计
+type causeStats struct {
+	occurrences int64            // 出现次数
+	followed    map[string]int64 // 按异常类型统计的后续异常次数
+}
+
+// Engine 关联引擎
+// 将异常与此前的模式检测事件、模型转换按时间对齐, 结合时间邻近度和历史一致性评分
+type Engine struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.CorrelationConfig
+
+	// 事件时间线, 按时间升序
+	timeline []*entry
+
+	// 候选原因统计, 以类型/类别为键
+	causes map[string]*causeStats
+
+	// 统计
+	stats Stats
+}
+
+// NewEngine 创建关联引擎
+func NewEngine(config types.CorrelationConfig) *Engine {
+	if config.Window <= 0 {
+		config.Window = defaultWindow
+	}
+	if config.HalfLife <= 0 {
+		config.HalfLife = defaultHalfLife
+	}
+	if config.MinScore <= 0 {
+		config.MinScore = defaultMinScore
+	}
+	if config.MaxCauses <= 0 {
+		config.MaxCauses = defaultMaxCauses
+	}
+	if config.HistorySize <= 0 {
+		config.HistorySize = defaultHistorySize
+	}
+
+	return &Engine{
+		config:   config,
+		timeline: make([]*entry, 0),
+		causes:   make(map[string]*causeStats),
+	}
+}
+
+// Observe 记录候选原因事件
+func (e *Engine) Observe(event Event) {
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.insert(event)
+
+	if event.Kind == EventAnomaly {
+		return
+	}
+	key := causeKey(event)
+	cs, exists := e.causes[key]
+	if !exists {
+		cs = &causeStats{followed: make(map[string]int64)}
+		e.causes[key] = cs
+	}
+	cs.occurrences++
+}
+
+// ObserveTransform 记录模型转换调用
+func (e *Engine) ObserveTransform(modelName string, pattern model.TransformPattern, err error) {
+	name, ok := transformNames[pattern]
+	if !ok {
+		name = fmt.Sprintf("pattern_%d", pattern)
+	}
+
+	confidence := 1.0
+	if err != nil {
+		// 失败的转换可能留下部分状态, 仍作为候选原因
+		confidence = 0.5
+	}
+
+	e.Observe(Event{
+		Kind:       EventTransform,
+		Source:     modelName,
+		Reference:  name,
+		Category:   name,
+		Timestamp:  time.Now(),
+		Confidence: confidence,
+		Failed:     err != nil,
+	})
+}
+
+// Correlate 关联异常并标注可能原因, 返回标注的原因
+func (e *Engine) Correlate(traceID types.TraceID, anomaly *types.Anomaly) []types.ProbableCause {
+	if anomaly == nil {
+		return nil
+	}
+	at := anomaly.DetectedAt
+	if at.IsZero() {
+		at = time.Now()
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.stats.Anomalies++
+
+	// 窗口内发生在异常之前的事件
+	from := at.Add(-e.config.Window)
+	start := sort.Search(len(e.timeline), func(i int) bool {
+		return !e.timeline[i].Timestamp.Before(from)
+	})
+
+	causes := make([]types.ProbableCause, 0)
+	for _, ent := range e.timeline[start:] {
+		if ent.Timestamp.After(at) {
+			break
+		}
+		if ent.Kind == EventAnomaly {
+			continue
+		}
+
+		// 每个事件对同类异常只计入一次
+		cs := e.causes[causeKey(ent.Event)]
+		if cs != nil && !ent.credited[anomaly.Type] {
+			if ent.credited == nil {
+				ent.credited = make(map[string]bool)
+			}
+			ent.credited[anomaly.Type] = true
+			cs.followed[anomaly.Type]++
+		}
+
+		cause := e.score(ent.Event, cs, anomaly, at)
+		if cause.Score >= e.config.MinScore {
+			causes = append(causes, cause)
+		}
+	}
+
+	sort.SliceStable(causes, func(i, j int) bool {
+		return causes[i].Score > causes[j].Score
+	})
+	if len(causes) > e.config.MaxCauses {
+		causes = causes[:e.config.MaxCauses]
+	}
+
+	anomaly.Causes = causes
+	if len(causes) > 0 {
+		e.stats.Annotated++
+	}
+
+	e.insert(Event{
+		Kind:       EventAnomaly,
+		Source:     string(traceID),
+		Reference:  anomaly.Metric,
+		Category:   anomaly.Type,
+		Timestamp:  at,
+		Confidence: math.Min(1, anomaly.Severity),
+	})
+
+	return causes
+}
+
+// Timeline 获取时间范围[from, to)内的事件
+func (e *Engine) Timeline(from, to time.Time) []Event {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	events := make([]Event, 0)
+	for _, ent := range e.timeline {
+		if ent.Timestamp.Before(from) {
+			continue
+		}
+		if !ent.Timestamp.Before(to) {
+			break
+		}
+		events = append(events, ent.Event)
+	}
+	return events
+}
+
+// GetStats 获取关联统计
+func (e *Engine) GetStats() Stats {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+
+	stats := e.stats
+	stats.Events = len(e.timeline)
+	return stats
+}
+
+// insert 按时间顺序插入事件, 调用方持有锁
+func (e *Engine) insert(event Event) {
+	ent := &entry{Event: event}
+
+	// 事件大多按时间到达, 从尾部查找插入位置
+	i := len(e.timeline)
+	for i > 0 && e.timeline[i-1].Timestamp.After(event.Timestamp) {
+		i--
+	}
+	e.timeline = append(e.timeline, nil)
+	copy(e.timeline[i+1:], e.timeline[i:])
+	e.timeline[i] = ent
+
+	if excess := len(e.timeline) - e.config.HistorySize; excess > 0 {
+		e.timeline = e.timeline[excess:]
+	}
+}
+
+// score 计算候选原因得分
+// 时间相关性按半衰期指数衰减, 因果一致性为该类事件之后出现同类异常的平滑比例
+func (e *Engine) score(event Event, cs *causeStats, anomaly *types.Anomaly, at time.Time) types.ProbableCause {
+	lag := at.Sub(event.Timestamp)
+	temporal := math.Pow(0.5, float64(lag)/float64(e.config.HalfLife))
+
+	causal := 0.5
+	if cs != nil && cs.occurrences > 0 {
+		causal = float64(cs.followed[anomaly.Type]+1) / float64(cs.occurrences+2)
+	}
+
+	confidence := event.Confidence
+	if confidence <= 0 {
+		confidence = 1
+	}
+
+	return types.ProbableCause{
+		Kind:        event.Kind,
+		Source:      event.Source,
+		Reference:   event.Reference,
+		Category:    event.Category,
+		OccurredAt:  event.Timestamp,
+		Lag:         lag,
+		Temporal:    temporal,
+		Causal:      causal,
+		Score:       temporal * (0.5 + 0.5*causal) * math.Min(1, confidence),
+		Description: describe(event, anomaly, lag),
+	}
+}
+
+// describe 生成原因说明
+func describe(event Event, anomaly *types.Anomaly, lag time.Duration) string {
+	lag = lag.Round(time.Millisecond)
+	switch event.Kind {
+	case EventTransform:
+		outcome := "transform"
+		if event.Failed {
+			outcome = "failed transform"
+		}
+		return fmt.Sprintf("%s %s on model %s %v before %s anomaly",
+			event.Reference, outcome, event.Source, lag, anomaly.Type)
+	case EventPerturbation:
+		return fmt.Sprintf("%s perturbation %s applied to %s %v before %s anomaly",
+			event.Category, event.Reference, event.Source, lag, anomaly.Type)
+	default:
+		return fmt.Sprintf("%s pattern %s emerged in %s %v before %s anomaly",
+			event.Category, event.Reference, event.Source, lag, anomaly.Type)
+	}
+}
+
+// causeKey 候选原因统计键
+func causeKey(event Event) string {
+	return event.Kind + "/" + event.Category
+}
@@ -0,0 +1,180 @@
+// system/monitor/drift/detector.go
+
+package drift
+
+import (
+	"math"
+)
+
+// 默认参数
+const (
+	defaultMinSamples = 30
+	defaultDelta      = 0.5
+	defaultThreshold  = 20
+	defaultConfidence = 0.002
+	defaultMaxWindow  = 1000
+
+	minStdDev         = 1e-3 // 标准差下限, 避免常数数据流上的微小变化被放大
+	minADWINSubwindow = 5    // ADWIN切分后每段的最少样本数
+)
+
+// changeDetector 单个数据流的变化检测
+type changeDetector interface {
+	// add 加入一个样本, 检测到变化时返回变化前后的均值
+	add(x float64) (before, after float64, changed bool)
+	// count 当前统计包含的样本数
+	count() int
+	// mean 当前统计的均值
+	mean() float64
+}
+
+// pageHinkley 双侧Page-Hinkley检验
+// 累积量为样本与均值之差减去容许变化, 累积量高出历史最小值超过阈值时报告变化; 容许变化和阈值以标准差为单位
+type pageHinkley struct {
+	delta      float64
+	threshold  float64
+	minSamples int
+
+	n     int
+	sum   float64
+	sumSq float64
+
+	up, down cumulative
+}
+
+// cumulative 单侧累积量, 及其最近一次取得最小值后的样本(变化点估计)
+type cumulative struct {
+	value    float64
+	min      float64
+	sinceSum float64
+	sinceN   int
+}
+
+// newPageHinkley 创建Page-Hinkley检验
+func newPageHinkley(delta, threshold float64, minSamples int) *pageHinkley {
+	return &pageHinkley{delta: delta, threshold: threshold, minSamples: minSamples}
+}
+
+// add 加入样本
+func (p *pageHinkley) add(x float64) (float64, float64, bool) {
+	p.n++
+	p.sum += x
+	p.sumSq += x * x
+	mean := p.sum / float64(p.n)
+	std := p.stdDev(mean)
+
+	p.up.add(x, x-mean-p.delta*std)
+	p.down.add(x, mean-x-p.delta*std)
+	if p.n < p.minSamples {
+		return 0, 0, false
+	}
+
+	limit := p.threshold * std
+	for _, c := range []*cumulative{&p.up, &p.down} {
+		if c.value-c.min <= limit || c.sinceN == 0 || c.sinceN == p.n {
+			continue
+		}
+		before := (p.sum - c.sinceSum) / float64(p.n-c.sinceN)
+		after := c.sinceSum / float64(c.sinceN)
+		*p = pageHinkley{delta: p.delta, threshold: p.threshold, minSamples: p.minSamples}
+		return before, after, true
+	}
+	return 0, 0, false
+}
+
+// stdDev 样本标准差, 不低于minStdDev
+func (p *pageHinkley) stdDev(mean float64) float64 {
+	if p.n < 2 {
+		return minStdDev
+	}
+	variance := (p.sumSq - float64(p.n)*mean*mean) / float64(p.n-1)
+	return math.Max(minStdDev, math.Sqrt(math.Max(0, variance)))
+}
+
+// add 累加增量, 取得新的最小值时重新开始记录变化点后的样本
+func (c *cumulative) add(x, increment float64) {
+	c.value += increment
+	if c.value <= c.min {
+		c.min = c.value
+		c.sinceSum, c.sinceN = 0, 0
+		return
+	}
+	c.sinceSum += x
+	c.sinceN++
+}
+
+func (p *pageHinkley) count() int { return p.n }
+
+func (p *pageHinkley) mean() float64 {
+	if p.n == 0 {
+		return 0
+	}
+	return p.sum / float64(p.n)
+}
+
+// adwin 自适应窗口
+// 窗口任一切分的两段均值之差超过按方差计算的界时, 丢弃较早的一段并报告变化
+type adwin struct {
+	confidence float64
+	maxWindow  int
+	minSamples int
+
+	window []float64
+}
+
+// newADWIN 创建自适应窗口
+func newADWIN(confidence float64, maxWindow, minSamples int) *adwin {
+	return &adwin{confidence: confidence, maxWindow: maxWindow, minSamples: minSamples}
+}
+
+// add 加入样本
+func (a *adwin) add(x float64) (float64, float64, bool) {
+	a.window = append(a.window, x)
+	if len(a.window) > a.maxWindow {
+		a.window = append(a.window[:0], a.window[1:]...)
+	}
+	n := len(a.window)
+	if n < a.minSamples || n < 2*minADWINSubwindow {
+		return 0, 0, false
+	}
+
+	total, totalSq := 0.0, 0.0
+	for _, v := range a.window {
+		total += v
+		totalSq += v * v
+	}
+	mean := total / float64(n)
+	variance := math.Max(0, totalSq/float64(n)-mean*mean)
+	logTerm := math.Log(2 * float64(n) / a.confidence)
+
+	// 从较早的切分点开始检查, 找到的第一个切分丢弃最多的过时样本
+	head := 0.0
+	for i := 1; i < n; i++ {
+		head += a.window[i-1]
+		n0, n1 := float64(i), float64(n-i)
+		if i < minADWINSubwindow || n-i < minADWINSubwindow {
+			continue
+		}
+		m := 1 / (1/n0 + 1/n1)
+		epsilon := math.Sqrt(2/m*variance*logTerm) + 2/(3*m)*logTerm
+		before, after := head/n0, (total-head)/n1
+		if math.Abs(before-after) > epsilon {
+			a.window = append([]float64(nil), a.window[i:]...)
+			return before, after, true
+		}
+	}
+	return 0, 0, false
+}
+
+func (a *adwin) count() int { return len(a.window) }
+
+func (a *adwin) mean() float64 {
+	if len(a.window) == 0 {
+		return 0
+	}
+	total := 0.0
+	for _, v := range a.window {
+		total += v
+	}
+	return total / float64(len(a.window))
+}
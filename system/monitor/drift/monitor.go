@@ -0,0 +1,232 @@
+// system/monitor/drift/monitor.go
+
+package drift
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 数据流类型
+const (
+	KindPatternType   = "pattern_type"   // 模式类型在检测结果中的频率
+	KindEnvironment   = "environment"    // 演化匹配的环境因素
+	KindModelAccuracy = "model_accuracy" // 学习模型的准确率
+)
+
+// 默认参数
+const (
+	defaultInterval    = 10 * time.Second
+	defaultHistorySize = 1000
+)
+
+// Drift 一次分布漂移
+type Drift struct {
+	Stream    string    `json:"stream"`    // 数据流, 形如"pattern_type:energy_flow"
+	Kind      string    `json:"kind"`      // 数据流类型
+	Key       string    `json:"key"`       // 模式类型、环境因素或模型ID
+	Method    string    `json:"method"`    // 检测方法
+	Before    float64   `json:"before"`    // 变化前的均值
+	After     float64   `json:"after"`     // 变化后的均值
+	Samples   int       `json:"samples"`   // 检测到变化时统计包含的样本数
+	Timestamp time.Time `json:"timestamp"` // 检测时间
+}
+
+// Magnitude 均值变化量
+func (d Drift) Magnitude() float64 {
+	return d.After - d.Before
+}
+
+// StreamStatus 数据流状态
+type StreamStatus struct {
+	Stream    string    `json:"stream"`
+	Kind      string    `json:"kind"`
+	Key       string    `json:"key"`
+	Samples   int       `json:"samples"`    // 当前统计包含的样本数
+	Mean      float64   `json:"mean"`       // 当前统计的均值
+	Last      float64   `json:"last"`       // 最近的样本
+	Drifts    int       `json:"drifts"`     // 累计漂移次数
+	LastDrift time.Time `json:"last_drift"` // 最近一次漂移时间
+}
+
+// stream 单个数据流
+type stream struct {
+	kind, key string
+	detector  changeDetector
+	last      float64
+	drifts    int
+	lastDrift time.Time
+}
+
+// Monitor 分布漂移检测, 并发安全
+// 每个数据流独立检测, 检测到漂移后统计重新开始
+type Monitor struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.DriftConfig
+
+	// 数据流
+	streams map[string]*stream
+
+	// 漂移记录, 按时间升序
+	history []Drift
+}
+
+// NewMonitor 创建漂移检测, 零值参数取默认值
+func NewMonitor(config types.DriftConfig) *Monitor {
+	if config.Method == "" {
+		config.Method = types.DriftPageHinkley
+	}
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.MinSamples <= 0 {
+		config.MinSamples = defaultMinSamples
+	}
+	if config.Delta <= 0 {
+		config.Delta = defaultDelta
+	}
+	if config.Threshold <= 0 {
+		config.Threshold = defaultThreshold
+	}
+	if config.Confidence <= 0 {
+		config.Confidence = defaultConfidence
+	}
+	if config.MaxWindow <= 0 {
+		config.MaxWindow = defaultMaxWindow
+	}
+	if config.HistorySize <= 0 {
+		config.HistorySize = defaultHistorySize
+	}
+
+	return &Monitor{
+		config:  config,
+		streams: make(map[string]*stream),
+		history: make([]Drift, 0),
+	}
+}
+
+// ValidateConfig 校验漂移检测配置
+func ValidateConfig(config types.DriftConfig) error {
+	switch config.Method {
+	case "", types.DriftPageHinkley, types.DriftADWIN:
+	default:
+		return types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "unknown drift detection method", nil).
+			WithContext("method", config.Method)
+	}
+	if config.Confidence < 0 || config.Confidence >= 1 {
+		return types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "drift confidence must be in (0, 1)", nil)
+	}
+	if config.Delta < 0 || config.Threshold < 0 || config.MinSamples < 0 || config.MaxWindow < 0 {
+		return types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "negative drift detection parameter", nil)
+	}
+	return nil
+}
+
+// Config 获取生效的配置
+func (m *Monitor) Config() types.DriftConfig {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.config
+}
+
+// StreamName 数据流名称
+func StreamName(kind, key string) string {
+	return kind + ":" + key
+}
+
+// Observe 记录数据流的样本, 检测到漂移时返回漂移记录; NaN和Inf忽略
+func (m *Monitor) Observe(kind, key string, value float64, now time.Time) (Drift, bool) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return Drift{}, false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	name := StreamName(kind, key)
+	s, exists := m.streams[name]
+	if !exists {
+		s = &stream{kind: kind, key: key, detector: m.newDetector()}
+		m.streams[name] = s
+	}
+	s.last = value
+
+	samples := s.detector.count() + 1
+	before, after, changed := s.detector.add(value)
+	if !changed {
+		return Drift{}, false
+	}
+
+	drift := Drift{
+		Stream:    name,
+		Kind:      kind,
+		Key:       key,
+		Method:    m.config.Method,
+		Before:    before,
+		After:     after,
+		Samples:   samples,
+		Timestamp: now,
+	}
+	s.drifts++
+	s.lastDrift = now
+	m.history = append(m.history, drift)
+	if excess := len(m.history) - m.config.HistorySize; excess > 0 {
+		m.history = append(make([]Drift, 0, m.config.HistorySize), m.history[excess:]...)
+	}
+	return drift, true
+}
+
+// newDetector 按配置创建变化检测(调用方持有锁)
+func (m *Monitor) newDetector() changeDetector {
+	if m.config.Method == types.DriftADWIN {
+		return newADWIN(m.config.Confidence, m.config.MaxWindow, m.config.MinSamples)
+	}
+	return newPageHinkley(m.config.Delta, m.config.Threshold, m.config.MinSamples)
+}
+
+// History 获取since之后的漂移记录, since为零时返回全部
+func (m *Monitor) History(since time.Time) []Drift {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	start := sort.Search(len(m.history), func(i int) bool {
+		return !m.history[i].Timestamp.Before(since)
+	})
+	return append([]Drift(nil), m.history[start:]...)
+}
+
+// Streams 获取各数据流的状态, 按名称排序
+func (m *Monitor) Streams() []StreamStatus {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	statuses := make([]StreamStatus, 0, len(m.streams))
+	for name, s := range m.streams {
+		statuses = append(statuses, StreamStatus{
+			Stream:    name,
+			Kind:      s.kind,
+			Key:       s.key,
+			Samples:   s.detector.count(),
+			Mean:      s.detector.mean(),
+			Last:      s.last,
+			Drifts:    s.drifts,
+			LastDrift: s.lastDrift,
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Stream < statuses[j].Stream })
+	return statuses
+}
+
+// Reset 清空数据流和漂移记录
+func (m *Monitor) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.streams = make(map[string]*stream)
+	m.history = make([]Drift, 0)
+}
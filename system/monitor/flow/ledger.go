@@ -0,0 +1,275 @@
+// system/monitor/flow/ledger.go
+
+package flow
+
+import (
+	"encoding/json"
+	"io"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 节点类型
+const (
+	NodeElement = "element" // 五行元素, 以元素类型命名
+	NodeField   = "field"   // 场, 以命名空间命名, 全局统一场为FieldUnified
+	NodePattern = "pattern" // 涌现模式, 以模式类型命名
+	NodeModel   = "model"   // 模型, 以注册名命名
+)
+
+// FieldUnified 全局统一场的节点名称
+const FieldUnified = "unified"
+
+// 默认参数
+const (
+	defaultRetention   = time.Hour
+	defaultHistorySize = 100000
+)
+
+// Node 能量流动的端点
+type Node struct {
+	Kind string `json:"kind"`
+	Name string `json:"name"`
+}
+
+// ID 节点标识, 形如"element:wood"
+func (n Node) ID() string {
+	return n.Kind + ":" + n.Name
+}
+
+// Transfer 一次能量转移
+type Transfer struct {
+	Source    Node      // 提供能量的节点
+	Target    Node      // 获得能量的节点
+	Amount    float64   // 转移量, 负值表示反向转移
+	Namespace string    // 所属命名空间, 全局为空
+	Timestamp time.Time // 发生时间
+}
+
+// SankeyQuery 桑基图数据的查询条件
+type SankeyQuery struct {
+	From      time.Time // 起始时间(含), 为零不限
+	To        time.Time // 结束时间(不含), 为零不限
+	Namespace string    // 命名空间, 为空包含全部
+	MinValue  float64   // 合计转移量低于该值的连线不输出
+}
+
+// SankeyNode 桑基图节点
+type SankeyNode struct {
+	ID      string  `json:"id"`
+	Kind    string  `json:"kind"`
+	Name    string  `json:"name"`
+	Inflow  float64 `json:"inflow"`  // 流入合计
+	Outflow float64 `json:"outflow"` // 流出合计
+}
+
+// SankeyLink 桑基图连线, 同一源和目标的转移合并
+type SankeyLink struct {
+	Source string  `json:"source"`
+	Target string  `json:"target"`
+	Value  float64 `json:"value"` // 转移量合计
+	Count  int     `json:"count"` // 转移次数
+}
+
+// SankeyReport 时间窗口内的能量流向, 源为元素和场, 汇为模式和模型
+type SankeyReport struct {
+	From  time.Time    `json:"from"`
+	To    time.Time    `json:"to"`
+	Nodes []SankeyNode `json:"nodes"` // 按类型和名称排序
+	Links []SankeyLink `json:"links"` // 按转移量降序排列
+	Total float64      `json:"total"` // 输出连线的转移量合计
+}
+
+// WriteJSON 以JSON导出报告
+func (r SankeyReport) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(r); err != nil {
+		return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "failed to export energy flow", err)
+	}
+	return nil
+}
+
+// Stats 记录统计
+type Stats struct {
+	Transfers int   // 保留的转移记录数
+	Recorded  int64 // 累计记录的转移数
+	Dropped   int64 // 超出保留时长或数量而移除的转移数
+}
+
+// Ledger 能量转移记录
+// 按时间保留各子系统上报的转移, 查询时按时间窗口聚合为桑基图数据
+type Ledger struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.EnergyFlowConfig
+
+	// 转移记录, 按时间升序
+	transfers []Transfer
+
+	// 统计
+	stats Stats
+}
+
+// NewLedger 创建能量转移记录
+func NewLedger(config types.EnergyFlowConfig) *Ledger {
+	if config.Retention <= 0 {
+		config.Retention = defaultRetention
+	}
+	if config.HistorySize <= 0 {
+		config.HistorySize = defaultHistorySize
+	}
+
+	return &Ledger{
+		config:    config,
+		transfers: make([]Transfer, 0),
+	}
+}
+
+// Record 记录转移; 负转移量按反向转移记录, 零、NaN和Inf忽略
+func (l *Ledger) Record(transfers ...Transfer) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	for _, t := range transfers {
+		if t.Amount == 0 || math.IsNaN(t.Amount) || math.IsInf(t.Amount, 0) {
+			continue
+		}
+		if t.Amount < 0 {
+			t.Source, t.Target, t.Amount = t.Target, t.Source, -t.Amount
+		}
+		if t.Timestamp.IsZero() {
+			t.Timestamp = now
+		}
+		l.insert(t)
+		l.stats.Recorded++
+	}
+	l.prune(now)
+}
+
+// insert 按时间顺序插入(调用方持有锁)
+func (l *Ledger) insert(t Transfer) {
+	n := len(l.transfers)
+	if n == 0 || !t.Timestamp.Before(l.transfers[n-1].Timestamp) {
+		l.transfers = append(l.transfers, t)
+		return
+	}
+	i := sort.Search(n, func(i int) bool {
+		return l.transfers[i].Timestamp.After(t.Timestamp)
+	})
+	l.transfers = append(l.transfers, Transfer{})
+	copy(l.transfers[i+1:], l.transfers[i:])
+	l.transfers[i] = t
+}
+
+// prune 移除超出保留时长和数量的转移(调用方持有锁)
+func (l *Ledger) prune(now time.Time) {
+	cutoff := now.Add(-l.config.Retention)
+	start := sort.Search(len(l.transfers), func(i int) bool {
+		return !l.transfers[i].Timestamp.Before(cutoff)
+	})
+	if excess := len(l.transfers) - start - l.config.HistorySize; excess > 0 {
+		start += excess
+	}
+	if start == 0 {
+		return
+	}
+	l.stats.Dropped += int64(start)
+	l.transfers = append(make([]Transfer, 0, len(l.transfers)-start), l.transfers[start:]...)
+}
+
+// Sankey 将时间窗口内的转移聚合为桑基图数据
+func (l *Ledger) Sankey(q SankeyQuery) SankeyReport {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	report := SankeyReport{
+		From:  q.From,
+		To:    q.To,
+		Nodes: make([]SankeyNode, 0),
+		Links: make([]SankeyLink, 0),
+	}
+
+	start := 0
+	if !q.From.IsZero() {
+		start = sort.Search(len(l.transfers), func(i int) bool {
+			return !l.transfers[i].Timestamp.Before(q.From)
+		})
+	}
+
+	type linkKey struct{ source, target string }
+	links := make(map[linkKey]*SankeyLink)
+	nodes := make(map[string]Node)
+	for _, t := range l.transfers[start:] {
+		if !q.To.IsZero() && !t.Timestamp.Before(q.To) {
+			break
+		}
+		if q.Namespace != "" && t.Namespace != q.Namespace {
+			continue
+		}
+		key := linkKey{t.Source.ID(), t.Target.ID()}
+		link, exists := links[key]
+		if !exists {
+			link = &SankeyLink{Source: key.source, Target: key.target}
+			links[key] = link
+			nodes[key.source] = t.Source
+			nodes[key.target] = t.Target
+		}
+		link.Value += t.Amount
+		link.Count++
+	}
+
+	// 过滤连线并累计节点流量, 只输出仍有连线的节点
+	flows := make(map[string]*SankeyNode)
+	for _, link := range links {
+		if link.Value < q.MinValue {
+			continue
+		}
+		report.Links = append(report.Links, *link)
+		report.Total += link.Value
+		for _, id := range []string{link.Source, link.Target} {
+			if _, exists := flows[id]; !exists {
+				node := nodes[id]
+				flows[id] = &SankeyNode{ID: id, Kind: node.Kind, Name: node.Name}
+			}
+		}
+		flows[link.Source].Outflow += link.Value
+		flows[link.Target].Inflow += link.Value
+	}
+
+	for _, node := range flows {
+		report.Nodes = append(report.Nodes, *node)
+	}
+	sort.Slice(report.Nodes, func(i, j int) bool {
+		if report.Nodes[i].Kind != report.Nodes[j].Kind {
+			return report.Nodes[i].Kind < report.Nodes[j].Kind
+		}
+		return report.Nodes[i].Name < report.Nodes[j].Name
+	})
+	sort.Slice(report.Links, func(i, j int) bool {
+		if report.Links[i].Value != report.Links[j].Value {
+			return report.Links[i].Value > report.Links[j].Value
+		}
+		if report.Links[i].Source != report.Links[j].Source {
+			return report.Links[i].Source < report.Links[j].Source
+		}
+		return report.Links[i].Target < report.Links[j].Target
+	})
+
+	return report
+}
+
+// GetStats 获取记录统计
+func (l *Ledger) GetStats() Stats {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	stats := l.stats
+	stats.Transfers = len(l.transfers)
+	return stats
+}
@@ -4,6 +4,7 @@ package monitor
 
 import (
 	"context"
+	"math"
 	"sync"
 	"time"
 
@@ -11,8 +12,13 @@ import (
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/common"
 	"github.com/Corphon/daoflow/system/monitor/alert"
+	"github.com/Corphon/daoflow/system/monitor/baseline"
+	"github.com/Corphon/daoflow/system/monitor/correlation"
+	"github.com/Corphon/daoflow/system/monitor/drift"
+	"github.com/Corphon/daoflow/system/monitor/flow"
 	"github.com/Corphon/daoflow/system/monitor/metrics"
 	"github.com/Corphon/daoflow/system/monitor/trace"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -25,15 +31,19 @@ type Manager struct {
 
 	// 监控组件
 	components struct {
-		collector *metrics.Collector // 指标收集器
-		analyzer  *metrics.Analyzer  // 指标分析器
-		reporter  *metrics.Reporter  // 指标报告器
-		detector  *alert.Detector    // 告警检测器
-		handler   *alert.Handler     // 告警处理器
-		notifier  *alert.Notifier    // 告警通知器
-		tracker   *trace.Tracker     // 追踪器
-		recorder  *trace.Recorder    // 记录器
-		analyzer2 *trace.Analyzer    // 追踪分析器
+		collector  *metrics.Collector  // 指标收集器
+		analyzer   *metrics.Analyzer   // 指标分析器
+		reporter   *metrics.Reporter   // 指标报告器
+		detector   *alert.Detector     // 告警检测器
+		handler    *alert.Handler      // 告警处理器
+		notifier   *alert.Notifier     // 告警通知器
+		tracker    *trace.Tracker      // 追踪器
+		recorder   *trace.Recorder     // 记录器
+		analyzer2  *trace.Analyzer     // 追踪分析器
+		baseline   *baseline.Learner   // 基线学习器
+		correlator *correlation.Engine // 异常关联引擎
+		energy     *flow.Ledger        // 能量转移记录
+		drift      *drift.Monitor      // 分布漂移检测
 	}
 
 	// 监控状态
@@ -135,6 +145,27 @@ func DefaultConfig() *types.MonitorConfig {
 			BufferSize:    1000,
 			BatchSize:     100,
 		},
+		Baseline: types.BaselineConfig{
+			Enabled:       true,
+			WindowSize:    1000,
+			WarmupSamples: 30,
+			Method:        types.BaselineSigma,
+			Sigma:         3,
+			Percentile:    0.99,
+		},
+		Correlation: types.CorrelationConfig{
+			Enabled:     true,
+			Window:      5 * time.Minute,
+			HalfLife:    30 * time.Second,
+			MinScore:    0.1,
+			MaxCauses:   3,
+			HistorySize: 10000,
+		},
+		EnergyFlow: types.EnergyFlowConfig{
+			Enabled:     true,
+			Retention:   time.Hour,
+			HistorySize: 100000,
+		},
 	}
 }
 
@@ -147,6 +178,11 @@ func (m *Manager) Start(ctx context.Context) error {
 		return nil
 	}
 
+	// 组件循环沿用调用方上下文中的协程监管器
+	if sup := supervisor.FromContext(ctx); sup != nil && supervisor.FromContext(m.ctx) != sup {
+		m.ctx = supervisor.NewContext(m.ctx, sup)
+	}
+
 	// 启动各组件
 	if err := m.startComponents(); err != nil {
 		return err
@@ -183,6 +219,36 @@ func (m *Manager) Status() string {
 	return m.state.status
 }
 
+// Health 健康度(0-1), 未运行为0, 错误记录越多越低
+func (m *Manager) Health() float64 {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	if m.state.status != "running" {
+		return 0
+	}
+	return 1 - math.Min(float64(len(m.state.errors))*0.1, 0.5)
+}
+
+// Drain 将已排队的追踪记录写入存储并保存学习到的基线
+func (m *Manager) Drain(ctx context.Context) error {
+	m.mu.RLock()
+	recorder := m.components.recorder
+	learner := m.components.baseline
+	path := m.config.Baseline.PersistPath
+	m.mu.RUnlock()
+
+	if recorder != nil {
+		if err := recorder.Drain(ctx); err != nil {
+			return err
+		}
+	}
+	if learner != nil && path != "" {
+		return learner.SaveFile(path)
+	}
+	return nil
+}
+
 // Wait 等待管理器停止
 func (m *Manager) Wait() {
 	<-m.ctx.Done()
@@ -200,7 +266,92 @@ func (m *Manager) GetMetrics() map[string]interface{} {
 		"alerts":      len(m.components.detector.GetAlertChannel()),
 		"traces":      m.components.tracker.GetMetrics(),
 		"error_count": len(m.state.errors),
+		"analysis":    analysisIntervalMetrics(m.components.analyzer2),
+		"trace_cache": m.components.analyzer2.CacheMetrics(),
+		"stages":      m.components.analyzer2.StageMetrics(),
+	}
+}
+
+// MetricValues 收集器的数值指标和模型指标, 单位见model.DefaultMetricRegistry中的定义
+func (m *Manager) MetricValues() map[string]float64 {
+	m.mu.RLock()
+	collector := m.components.collector
+	m.mu.RUnlock()
+
+	values := make(map[string]float64)
+	if collector == nil {
+		return values
+	}
+	if current, err := collector.GetMetrics(); err == nil {
+		for name, v := range current {
+			values[name] = v
+		}
+	}
+	if modelMetrics, err := collector.GetModelMetrics(); err == nil {
+		for name, v := range modelMetrics.Values() {
+			values[name] = v
+		}
 	}
+	return values
+}
+
+// analysisIntervalMetrics 追踪分析间隔指标
+func analysisIntervalMetrics(analyzer *trace.Analyzer) map[string]float64 {
+	if tuner := analyzer.GetIntervalTuner(); tuner != nil {
+		return tuner.GetMetrics()
+	}
+	return map[string]float64{
+		"effective_interval_ms": float64(analyzer.EffectiveInterval()) / float64(time.Millisecond),
+	}
+}
+
+// GetTraceRecorder 获取追踪记录器
+func (m *Manager) GetTraceRecorder() *trace.Recorder {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.recorder
+}
+
+// GetTracker 获取追踪器, 其他子系统经此上报因果跨度
+func (m *Manager) GetTracker() *trace.Tracker {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.tracker
+}
+
+// GetEnergyLedger 获取能量转移记录(未启用时为nil)
+func (m *Manager) GetEnergyLedger() *flow.Ledger {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.energy
+}
+
+// GetDriftMonitor 获取分布漂移检测(未启用时为nil)
+func (m *Manager) GetDriftMonitor() *drift.Monitor {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.drift
+}
+
+// GetCorrelationEngine 获取异常关联引擎(未启用时为nil)
+func (m *Manager) GetCorrelationEngine() *correlation.Engine {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.correlator
+}
+
+// GetBaselineLearner 获取基线学习器(未启用时为nil)
+func (m *Manager) GetBaselineLearner() *baseline.Learner {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.baseline
+}
+
+// GetTraceAnalyzer 获取追踪分析器
+func (m *Manager) GetTraceAnalyzer() *trace.Analyzer {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return m.components.analyzer2
 }
 
 // InjectCore 注入核心引擎
@@ -264,17 +415,23 @@ func (m *Manager) initComponents() error {
 
 	// 配置转换
 	traceConfig := types.TraceConfig{
-		StoragePath:   m.config.Trace.StoragePath,
-		RetentionDays: m.config.Base.RetentionTime,
-		BatchSize:     m.config.Base.BatchSize,
-		BufferSize:    m.config.Trace.BufferSize,
-		FlushInterval: m.config.Trace.FlushInterval,
-		AsyncWrite:    true,
-		SampleRate:    m.config.Trace.SampleRate,
-		MaxQueueSize:  m.config.Trace.MaxSpans,
-		EnableMetrics: true,
-		EnableEvents:  true,
-		IncludeModel:  true,
+		StoragePath:      m.config.Trace.StoragePath,
+		RetentionDays:    m.config.Base.RetentionTime,
+		BatchSize:        m.config.Base.BatchSize,
+		BufferSize:       m.config.Trace.BufferSize,
+		FlushInterval:    m.config.Trace.FlushInterval,
+		AsyncWrite:       true,
+		SampleRate:       m.config.Trace.SampleRate,
+		Sampling:         m.config.Trace.Sampling,
+		AnalysisInterval: m.config.Trace.AnalysisInterval,
+		Tuning:           m.config.Trace.Tuning,
+		Cache:            m.config.Trace.Cache,
+		Rollup:           m.config.Trace.Rollup,
+		StageTimeouts:    m.config.Trace.StageTimeouts,
+		MaxQueueSize:     m.config.Trace.MaxSpans,
+		EnableMetrics:    true,
+		EnableEvents:     true,
+		IncludeModel:     true,
 	}
 
 	// 创建追踪器
@@ -285,15 +442,52 @@ func (m *Manager) initComponents() error {
 	recorder := trace.NewRecorder(traceConfig)
 	m.components.recorder = recorder
 
+	// 采样保留的跨度写入记录器
+	tracker.Subscribe(recorder)
+
 	// 创建追踪分析器
 	analyzer2 := trace.NewAnalyzer(tracker, recorder, traceConfig)
 	m.components.analyzer2 = analyzer2
 
+	// 创建基线学习器, 驱动自适应异常阈值
+	if m.config.Baseline.Enabled {
+		learner := baseline.NewLearner(m.config.Baseline)
+		m.components.baseline = learner
+		analyzer2.SetBaseline(learner)
+	}
+
+	// 创建关联引擎, 为异常标注可能原因
+	if m.config.Correlation.Enabled {
+		engine := correlation.NewEngine(m.config.Correlation)
+		m.components.correlator = engine
+		analyzer2.SetCorrelator(engine)
+	}
+
+	// 创建能量转移记录, 供能量流向报告使用
+	if m.config.EnergyFlow.Enabled {
+		m.components.energy = flow.NewLedger(m.config.EnergyFlow)
+	}
+
+	// 创建漂移检测, 由系统按采样间隔输入观测
+	if m.config.Drift.Enabled {
+		if err := drift.ValidateConfig(m.config.Drift); err != nil {
+			return err
+		}
+		m.components.drift = drift.NewMonitor(m.config.Drift)
+	}
+
 	return nil
 }
 
 // startComponents 启动组件
 func (m *Manager) startComponents() error {
+	// 加载持久化的基线
+	if m.components.baseline != nil && m.config.Baseline.PersistPath != "" {
+		if err := m.components.baseline.LoadFile(m.config.Baseline.PersistPath); err != nil {
+			return err
+		}
+	}
+
 	// 按依赖顺序启动
 	if err := m.components.collector.Start(m.ctx); err != nil {
 		return err
@@ -355,6 +549,13 @@ func (m *Manager) stopComponents() error {
 	if err := m.components.collector.Stop(); err != nil {
 		return err
 	}
+
+	// 保存学习到的基线
+	if m.components.baseline != nil && m.config.Baseline.PersistPath != "" {
+		if err := m.components.baseline.SaveFile(m.config.Baseline.PersistPath); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -365,13 +566,13 @@ func (m *Manager) InjectDependencies(core *core.Engine, common *common.Manager)
 
 	// 注入核心引擎
 	if core == nil {
-		return model.WrapError(nil, model.ErrCodeDependency, "core engine is nil")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeDependency, "core engine is nil", nil)
 	}
 	m.core = core
 
 	// 注入通用管理器
 	if common == nil {
-		return model.WrapError(nil, model.ErrCodeDependency, "common manager is nil")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeDependency, "common manager is nil", nil)
 	}
 	m.common = common
 
@@ -4,7 +4,6 @@ package metrics
 
 import (
 	"context"
-	"fmt"
 	"math"
 	"math/cmplx"
 	"sync"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -122,12 +122,12 @@ func (a *Analyzer) Start(ctx context.Context) error {
 	a.mu.Lock()
 	if a.status.isRunning {
 		a.mu.Unlock()
-		return model.WrapError(nil, model.ErrCodeOperation, "analyzer already running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "analyzer already running", nil)
 	}
 	a.status.isRunning = true
 	a.mu.Unlock()
 
-	go a.analysisLoop(ctx)
+	supervisor.Go(ctx, "monitor.metrics.analysis", a.analysisLoop)
 	return nil
 }
 
@@ -157,7 +157,7 @@ func (a *Analyzer) Stop() error {
 	defer a.mu.Unlock()
 
 	if !a.status.isRunning {
-		return model.WrapError(nil, model.ErrCodeOperation, "analyzer not running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "analyzer not running", nil)
 	}
 
 	a.status.isRunning = false
@@ -177,7 +177,7 @@ func (a *Analyzer) analyze(ctx context.Context) error {
 	metrics := a.collector.GetCurrentMetrics()
 	modelMetrics, err := a.collector.GetModelMetrics()
 	if err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "failed to get model metrics")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to get model metrics", err)
 	}
 	history := a.collector.GetMetricsHistory()
 
@@ -199,7 +199,7 @@ func (a *Analyzer) analyze(ctx context.Context) error {
 
 	// 执行各类分析
 	if err := a.analyzeQuantumStates(result); err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "quantum analysis failed")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "quantum analysis failed", err)
 	}
 
 	// 检查上下文
@@ -210,7 +210,7 @@ func (a *Analyzer) analyze(ctx context.Context) error {
 	}
 
 	if err := a.analyzeFieldDynamics(result); err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "field analysis failed")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "field analysis failed", err)
 	}
 
 	// 检查上下文
@@ -221,7 +221,7 @@ func (a *Analyzer) analyze(ctx context.Context) error {
 	}
 
 	if err := a.analyzeEmergentPatterns(result); err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "emergence analysis failed")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "emergence analysis failed", err)
 	}
 
 	// 检查上下文
@@ -232,7 +232,7 @@ func (a *Analyzer) analyze(ctx context.Context) error {
 	}
 
 	if err := a.generatePredictions(result); err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "prediction generation failed")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "prediction generation failed", err)
 	}
 
 	// 检查上下文
@@ -244,7 +244,7 @@ func (a *Analyzer) analyze(ctx context.Context) error {
 
 	// 生成洞察
 	if err := a.generateInsights(result); err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "insight generation failed")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "insight generation failed", err)
 	}
 
 	// 缓存结果
@@ -450,7 +450,7 @@ func detectModelPatterns(metrics model.ModelMetrics) []types.EmergentPattern {
 
 // generatePatternID 生成唯一的模式ID
 func generatePatternID() string {
-	return fmt.Sprintf("pattern_%d", time.Now().UnixNano())
+	return core.NewID("pattern_")
 }
 
 // calculateEnergyStability 计算能量稳定性
@@ -730,5 +730,5 @@ func (a *Analyzer) GetAnalysisHistory(limit int) []*AnalysisResult {
 
 // generateAnalysisID 生成分析ID
 func generateAnalysisID() string {
-	return fmt.Sprintf("analysis-%d", time.Now().UnixNano())
+	return core.NewID("analysis-")
 }
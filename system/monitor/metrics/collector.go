@@ -4,13 +4,13 @@ package metrics
 
 import (
 	"context"
-	"fmt"
 	"math"
 	"runtime"
 	"sync"
 	"time"
 
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -71,6 +71,25 @@ type MetricCollector interface {
 	Validate() error
 }
 
+// collectorMetrics 收集器输出的指标定义, 见GetMetrics
+var collectorMetrics = []model.MetricDesc{
+	{Name: "energy", Unit: model.UnitEnergy, Description: "系统能量"},
+	{Name: "field_strength", Unit: model.UnitNone, Description: "场强度"},
+	{Name: "coherence", Unit: model.UnitRatio, Description: "量子相干性", Bounded: true, Min: 0, Max: 1},
+	{Name: "collection_rate", Unit: model.UnitNone, Description: "采样数与丢弃数之比"},
+	{Name: "avg_latency", Unit: model.UnitMilliseconds, Description: "平均采集延迟"},
+	{Name: "max_latency", Unit: model.UnitMilliseconds, Description: "最大采集延迟"},
+	{Name: "success_rate", Unit: model.UnitRatio, Description: "采集成功率", Bounded: true, Min: 0, Max: 1},
+	{Name: "memory_usage", Unit: model.UnitMegabytes, Description: "已分配堆内存"},
+	{Name: "goroutines", Unit: model.UnitCount, Description: "协程数"},
+}
+
+func init() {
+	if err := model.DefaultMetricRegistry().Register(collectorMetrics...); err != nil {
+		panic(err)
+	}
+}
+
 // NewCollector 创建新的指标收集器
 func NewCollector(config types.MetricsConfig) *Collector {
 	return &Collector{
@@ -87,13 +106,13 @@ func (c *Collector) Start(ctx context.Context) error {
 	c.mu.Lock()
 	if c.status.isRunning {
 		c.mu.Unlock()
-		return types.NewSystemError(types.ErrRuntime, "collector already running", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "collector already running", nil)
 	}
 	c.status.isRunning = true
 	c.mu.Unlock()
 
 	// 启动收集循环
-	go c.collectLoop(ctx)
+	supervisor.Go(ctx, "monitor.metrics.collector", c.collectLoop)
 
 	return nil
 }
@@ -120,7 +139,7 @@ func (c *Collector) RegisterCollector(collector MetricCollector) error {
 	// 注册收集器
 	metricType := collector.Type()
 	if _, exists := c.collectors[metricType]; exists {
-		return types.NewSystemError(types.ErrExists, "collector already registered", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrExists, "collector already registered", nil)
 	}
 
 	c.collectors[metricType] = collector
@@ -434,7 +453,8 @@ func (c *Collector) GetMetric(name string) (float64, error) {
 		if val, ok := c.current.Custom[name].(float64); ok {
 			return val, nil
 		}
-		return 0, fmt.Errorf("metric %s not found", name)
+		return 0, types.NewDomainError(types.DomainMonitor, types.ErrNotFound, "metric not found", nil).
+			WithContext("metric", name)
 	}
 }
 
@@ -12,6 +12,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -85,13 +87,13 @@ func (r *Reporter) Start(ctx context.Context) error {
 	r.mu.Lock()
 	if r.status.isRunning {
 		r.mu.Unlock()
-		return types.NewSystemError(types.ErrRuntime, "reporter already running", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "reporter already running", nil)
 	}
 	r.status.isRunning = true
 	r.mu.Unlock()
 
 	// 启动报告循环
-	go r.reportLoop(ctx)
+	supervisor.Go(ctx, "monitor.metrics.reporter", r.reportLoop)
 
 	return nil
 }
@@ -112,7 +114,7 @@ func (r *Reporter) Subscribe(subscriber ReportSubscriber) error {
 
 	id := subscriber.GetID()
 	if _, exists := r.subscribers[id]; exists {
-		return types.NewSystemError(types.ErrExists, "subscriber already exists", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrExists, "subscriber already exists", nil)
 	}
 
 	r.subscribers[id] = subscriber
@@ -125,7 +127,7 @@ func (r *Reporter) Unsubscribe(id string) error {
 	defer r.mu.Unlock()
 
 	if _, exists := r.subscribers[id]; !exists {
-		return types.NewSystemError(types.ErrNotFound, "subscriber not found", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrNotFound, "subscriber not found", nil)
 	}
 
 	delete(r.subscribers, id)
@@ -202,7 +204,7 @@ func (r *Reporter) generateAndSendReport(ctx context.Context) error {
 // generateReport 生成报告
 func (r *Reporter) generateReport(current *types.MetricsData, history []*types.MetricsData) types.Report {
 	report := types.Report{
-		ID:        fmt.Sprintf("report-%d", time.Now().Unix()),
+		ID:        core.NewID("report_"),
 		Timestamp: time.Now(),
 		Period:    r.config.Interval.String(),
 	}
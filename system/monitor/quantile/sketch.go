@@ -0,0 +1,215 @@
+// system/monitor/quantile/sketch.go
+
+package quantile
+
+import (
+	"math"
+	"sort"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	DefaultAccuracy = 0.01 // 默认相对误差
+	maxBuckets      = 2048 // 桶数上限, 超出时合并最小的桶, 牺牲最低分位的精度
+)
+
+// Sketch 对数分桶的分位数草图
+// 正值按相对误差accuracy映射到几何增长的桶, 任意分位数的相对误差不超过accuracy;
+// 非正值计入零值桶. 相同精度的草图可以无损合并, 适合按时间桶汇总后再合并查询.
+// Sketch不是并发安全的, 由使用方加锁
+type Sketch struct {
+	Accuracy float64         `json:"accuracy"`
+	Buckets  map[int]float64 `json:"buckets"` // 桶索引 -> 计数
+	Zero     float64         `json:"zero"`    // 非正值的计数
+	Count    float64         `json:"count"`
+	Sum      float64         `json:"sum"`
+	Min      float64         `json:"min"`
+	Max      float64         `json:"max"`
+
+	gamma    float64
+	logGamma float64
+}
+
+// Summary 常用分位数
+type Summary struct {
+	Count float64 `json:"count"`
+	Mean  float64 `json:"mean"`
+	P50   float64 `json:"p50"`
+	P90   float64 `json:"p90"`
+	P99   float64 `json:"p99"`
+	P999  float64 `json:"p999"`
+	Max   float64 `json:"max"`
+}
+
+// New 创建草图, accuracy不在(0, 1)内时使用默认值
+func New(accuracy float64) *Sketch {
+	if accuracy <= 0 || accuracy >= 1 {
+		accuracy = DefaultAccuracy
+	}
+	s := &Sketch{Accuracy: accuracy, Buckets: make(map[int]float64)}
+	s.init()
+	return s
+}
+
+// init 由精度计算桶增长率, 反序列化后首次使用时调用
+func (s *Sketch) init() {
+	if s.logGamma != 0 {
+		return
+	}
+	if s.Accuracy <= 0 || s.Accuracy >= 1 {
+		s.Accuracy = DefaultAccuracy
+	}
+	if s.Buckets == nil {
+		s.Buckets = make(map[int]float64)
+	}
+	s.gamma = (1 + s.Accuracy) / (1 - s.Accuracy)
+	s.logGamma = math.Log(s.gamma)
+}
+
+// Add 记录一个值, NaN被忽略
+func (s *Sketch) Add(v float64) {
+	s.AddN(v, 1)
+}
+
+// AddN 记录一个值n次
+func (s *Sketch) AddN(v, n float64) {
+	if math.IsNaN(v) || n <= 0 {
+		return
+	}
+	s.init()
+
+	if s.Count == 0 || v < s.Min {
+		s.Min = v
+	}
+	if s.Count == 0 || v > s.Max {
+		s.Max = v
+	}
+	s.Count += n
+	s.Sum += v * n
+
+	if v <= 0 {
+		s.Zero += n
+		return
+	}
+	s.Buckets[s.index(v)] += n
+	s.collapse()
+}
+
+// Merge 合并另一草图, 两者精度必须相同
+func (s *Sketch) Merge(other *Sketch) error {
+	if other == nil || other.Count == 0 {
+		return nil
+	}
+	s.init()
+	if other.Accuracy != s.Accuracy {
+		return types.NewDomainError(types.DomainMonitor, types.ErrValidation, "cannot merge sketches with different accuracy", nil).
+			WithContext("accuracy", s.Accuracy).
+			WithContext("other", other.Accuracy)
+	}
+
+	if s.Count == 0 || other.Min < s.Min {
+		s.Min = other.Min
+	}
+	if s.Count == 0 || other.Max > s.Max {
+		s.Max = other.Max
+	}
+	s.Count += other.Count
+	s.Sum += other.Sum
+	s.Zero += other.Zero
+	for index, n := range other.Buckets {
+		s.Buckets[index] += n
+	}
+	s.collapse()
+	return nil
+}
+
+// Quantile 第q分位数(0 <= q <= 1), 没有数据时为0
+func (s *Sketch) Quantile(q float64) float64 {
+	if s.Count == 0 {
+		return 0
+	}
+	s.init()
+	q = math.Max(0, math.Min(1, q))
+	if q == 0 {
+		return s.Min
+	}
+	if q == 1 {
+		return s.Max
+	}
+
+	rank := q * (s.Count - 1)
+	if rank < s.Zero {
+		return math.Max(s.Min, math.Min(0, s.Max))
+	}
+	cumulative := s.Zero
+	indexes := s.indexes()
+	for _, index := range indexes {
+		cumulative += s.Buckets[index]
+		if cumulative > rank {
+			return math.Max(s.Min, math.Min(s.Max, s.value(index)))
+		}
+	}
+	return s.Max
+}
+
+// Summary 计算常用分位数
+func (s *Sketch) Summary() Summary {
+	summary := Summary{Count: s.Count}
+	if s.Count == 0 {
+		return summary
+	}
+	summary.Mean = s.Sum / s.Count
+	summary.P50 = s.Quantile(0.5)
+	summary.P90 = s.Quantile(0.9)
+	summary.P99 = s.Quantile(0.99)
+	summary.P999 = s.Quantile(0.999)
+	summary.Max = s.Max
+	return summary
+}
+
+// Clone 复制草图
+func (s *Sketch) Clone() *Sketch {
+	s.init()
+	clone := *s
+	clone.Buckets = make(map[int]float64, len(s.Buckets))
+	for index, n := range s.Buckets {
+		clone.Buckets[index] = n
+	}
+	return &clone
+}
+
+// index 值所在的桶, 桶i覆盖(gamma^(i-1), gamma^i]
+func (s *Sketch) index(v float64) int {
+	return int(math.Ceil(math.Log(v) / s.logGamma))
+}
+
+// value 桶的代表值, 与桶内任意值的相对误差不超过精度
+func (s *Sketch) value(index int) float64 {
+	return 2 * math.Pow(s.gamma, float64(index)) / (s.gamma + 1)
+}
+
+// indexes 按升序排列的桶索引
+func (s *Sketch) indexes() []int {
+	indexes := make([]int, 0, len(s.Buckets))
+	for index := range s.Buckets {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// collapse 桶数超出上限时把最小的桶并入相邻的桶
+func (s *Sketch) collapse() {
+	if len(s.Buckets) <= maxBuckets {
+		return
+	}
+	indexes := s.indexes()
+	excess := len(indexes) - maxBuckets
+	target := indexes[excess]
+	for _, index := range indexes[:excess] {
+		s.Buckets[target] += s.Buckets[index]
+		delete(s.Buckets, index)
+	}
+}
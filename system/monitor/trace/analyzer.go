@@ -4,7 +4,6 @@ package trace
 
 import (
 	"context"
-	"fmt"
 	"math"
 	"sort"
 	"sync"
@@ -12,6 +11,10 @@ import (
 
 	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/control/tuning"
+	"github.com/Corphon/daoflow/system/monitor/baseline"
+	"github.com/Corphon/daoflow/system/monitor/correlation"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -26,17 +29,25 @@ const (
 	maxFanOut     = 50  // 最大扇出度
 )
 
-// 延迟分析相关常量
+// 默认分析间隔
+const defaultAnalysisInterval = 30 * time.Second
+
+// 延迟分析相关常量, 基线预热完成前使用
 const (
-	defaultLatencyThreshold = 50 * time.Millisecond  // 默认延迟阈值
-	maxLatencyThreshold     = 100 * time.Millisecond // 最大延迟阈值
+	defaultLatencyThreshold = 50 * time.Millisecond // 默认延迟阈值
 )
 
-// 资源分析相关常量
+// 资源分析相关常量, 基线预热完成前使用
 const (
 	defaultResourceThreshold = 0.8 // 默认资源使用阈值
 )
 
+// 基线指标名
+const (
+	latencyBaselineMetric  = "trace.latency_ms"
+	resourceBaselinePrefix = "trace.resource."
+)
+
 // TraceAnalysis 追踪分析结果
 type TraceAnalysis struct {
 	ID        string
@@ -45,11 +56,22 @@ type TraceAnalysis struct {
 	Duration  time.Duration
 	SpanCount int
 
+	// 追踪属性, 合并各跨度的标签, 根跨度的标签优先
+	Attributes map[string]string
+
+	// 超出时间预算而跳过的分析阶段
+	SkippedStages []string
+
+	// 自定义分析阶段写入的扩展结果
+	Extensions map[string]interface{}
+
 	// 系统层面分析
-	Patterns    []types.TracePattern
-	Bottlenecks []types.Bottleneck
-	Metrics     map[string]float64
-	Anomalies   []types.Anomaly
+	Patterns     []types.TracePattern
+	Bottlenecks  []types.Bottleneck
+	Metrics      map[string]float64
+	Anomalies    []types.Anomaly
+	CriticalPath *CriticalPath          // 关键路径
+	Latency      types.LatencyQuantiles // 跨度耗时分位数
 
 	// 模型层面分析
 	ModelAnalysis struct {
@@ -90,7 +112,7 @@ type Analyzer struct {
 
 	// 分析缓存
 	cache struct {
-		traces    map[types.TraceID]*TraceAnalysis
+		traces    *analysisCache
 		patterns  []types.TracePattern
 		anomalies []types.Anomaly
 	}
@@ -104,6 +126,37 @@ type Analyzer struct {
 
 	// 模型分析器
 	modelAnalyzer *model.Analyzer
+
+	// 异常监听器
+	listeners []func(types.TraceID, types.Anomaly)
+
+	// 按小时和天的分析结果汇总
+	rollups rollups
+
+	// 各分析阶段的耗时统计
+	stageStats map[string]*StageStats
+
+	// 自定义分析阶段和阶段钩子
+	plugins struct {
+		stages []*customStage
+		before []StageHook
+		after  []StageHook
+	}
+
+	// 分析结果订阅
+	subscriptions struct {
+		next int
+		list map[string]*analysisSubscription
+	}
+
+	// 基线学习器, 为nil时使用固定阈值
+	baseline *baseline.Learner
+
+	// 异常关联引擎
+	correlator *correlation.Engine
+
+	// 分析间隔调节器, 为nil时使用固定间隔
+	tuner *tuning.IntervalTuner
 }
 
 // QuantumAnalysis 量子分析结果
@@ -117,19 +170,34 @@ type QuantumAnalysis struct {
 // ------------------------------------------------------------------------------------------
 // NewAnalyzer 创建新的分析器
 func NewAnalyzer(tracker *Tracker, recorder *Recorder, config types.TraceConfig) *Analyzer {
-	return &Analyzer{
+	if config.AnalysisInterval <= 0 {
+		config.AnalysisInterval = defaultAnalysisInterval
+	}
+
+	a := &Analyzer{
 		tracker:       tracker,
 		recorder:      recorder,
 		config:        config,
 		modelAnalyzer: model.NewAnalyzer(),
 		cache: struct {
-			traces    map[types.TraceID]*TraceAnalysis
+			traces    *analysisCache
 			patterns  []types.TracePattern
 			anomalies []types.Anomaly
 		}{
-			traces: make(map[types.TraceID]*TraceAnalysis),
+			traces: newAnalysisCache(config.Cache),
 		},
+		rollups: newRollups(config.Rollup),
 	}
+
+	// 分析间隔自动调节, 默认以记录队列占用作为负载信号
+	if config.Tuning.Enabled {
+		a.tuner = tuning.NewIntervalTuner(config.AnalysisInterval, config.Tuning)
+		a.tuner.SetSignals(func() tuning.Signals {
+			return tuning.Signals{QueueDepth: recorder.QueueDepth(), Health: 1}
+		})
+	}
+
+	return a
 }
 
 // Start 启动分析器
@@ -137,42 +205,75 @@ func (a *Analyzer) Start(ctx context.Context) error {
 	a.mu.Lock()
 	if a.status.isRunning {
 		a.mu.Unlock()
-		return model.WrapError(nil, model.ErrCodeOperation, "analyzer already running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "analyzer already running", nil)
 	}
 	a.status.isRunning = true
 	a.mu.Unlock()
 
-	go a.analysisLoop(ctx)
+	supervisor.Go(ctx, "monitor.trace.analysis", a.analysisLoop)
 	return nil
 }
 
 // analysisLoop 分析循环
 func (a *Analyzer) analysisLoop(ctx context.Context) {
-	ticker := time.NewTicker(a.config.AnalysisInterval)
-	defer ticker.Stop()
+	timer := time.NewTimer(a.EffectiveInterval())
+	defer timer.Stop()
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
-		case <-ticker.C:
+		case <-timer.C:
+			start := time.Now()
 			if err := a.analyze(ctx); err != nil {
 				// 记录错误但继续运行
 				a.mu.Lock()
 				a.status.errors = append(a.status.errors, err)
 				a.mu.Unlock()
 			}
+			timer.Reset(a.nextInterval(time.Since(start)))
 		}
 	}
 }
 
+// GetIntervalTuner 获取分析间隔调节器(未启用时为nil)
+func (a *Analyzer) GetIntervalTuner() *tuning.IntervalTuner {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.tuner
+}
+
+// EffectiveInterval 当前有效的分析间隔
+func (a *Analyzer) EffectiveInterval() time.Duration {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	if a.tuner != nil {
+		return a.tuner.Interval()
+	}
+	return a.config.AnalysisInterval
+}
+
+// nextInterval 根据本次分析耗时计算下一次间隔
+func (a *Analyzer) nextInterval(elapsed time.Duration) time.Duration {
+	a.mu.RLock()
+	tuner := a.tuner
+	interval := a.config.AnalysisInterval
+	a.mu.RUnlock()
+
+	if tuner == nil {
+		return interval
+	}
+	return tuner.Observe(elapsed)
+}
+
 // Stop 停止分析器
 func (a *Analyzer) Stop() error {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
 	if !a.status.isRunning {
-		return model.WrapError(nil, model.ErrCodeOperation, "analyzer not running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "analyzer not running", nil)
 	}
 
 	a.status.isRunning = false
@@ -182,71 +283,122 @@ func (a *Analyzer) Stop() error {
 // analyze 执行分析
 func (a *Analyzer) analyze(ctx context.Context) error {
 	// 获取追踪数据
-	traces := a.getTracesInWindow()
+	traces, err := a.getTracesInWindow(ctx)
+	if err != nil {
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to load traces", err)
+	}
+
+	_, err = a.analyzeTraces(ctx, traces)
+
+	// 清理过期的分析结果
+	a.mu.Lock()
+	a.cache.traces.expire(time.Now())
+	a.mu.Unlock()
+	return err
+}
+
+// AnalyzeRange 分析时间范围[from, to)内的追踪, 配置存储后端后可超出内存窗口
+func (a *Analyzer) AnalyzeRange(ctx context.Context, from, to time.Time) ([]*TraceAnalysis, error) {
+	records, err := a.recorder.QueryRange(ctx, from, to)
+	if err != nil {
+		return nil, types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to load traces", err)
+	}
+	return a.analyzeTraces(ctx, groupSpans(records))
+}
+
+// analyzeTraces 分析一组追踪
+func (a *Analyzer) analyzeTraces(ctx context.Context, traces map[types.TraceID][]*Span) ([]*TraceAnalysis, error) {
+	results := make([]*TraceAnalysis, 0, len(traces))
 
 	for traceID, spans := range traces {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return results, ctx.Err()
 		default:
 		}
 		analysis := &TraceAnalysis{
-			ID:        generateAnalysisID(),
-			Timestamp: time.Now(),
-			TraceID:   traceID,
-		}
-
-		// 系统层面分析
-		if err := a.analyzeSystemTrace(analysis, spans); err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "system analysis failed")
+			ID:         generateAnalysisID(),
+			Timestamp:  time.Now(),
+			TraceID:    traceID,
+			SpanCount:  len(spans),
+			Attributes: traceAttributes(spans),
+			Extensions: make(map[string]interface{}),
 		}
 
-		// 模型层面分析
-		if err := a.analyzeModelTrace(analysis, spans); err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "model analysis failed")
+		// 依次执行系统、模型、量子和场动力学分析及自定义阶段, 超出时间预算的阶段被跳过
+		if err := a.runStages(ctx, analysis, spans); err != nil {
+			return results, err
 		}
 
-		// 量子层面分析
-		if err := a.analyzeQuantumTrace(analysis, spans); err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "quantum analysis failed")
-		}
-
-		// 场动力学分析
-		if err := a.analyzeFieldTrace(analysis, spans); err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "field analysis failed")
-		}
+		// 标注异常的可能原因
+		a.correlateAnomalies(analysis)
 
 		// 缓存分析结果
 		a.cacheAnalysis(analysis)
+
+		// 通知异常监听器和分析结果订阅者
+		a.notifyAnomalies(analysis)
+		a.publishAnalysis(analysis)
+
+		results = append(results, analysis)
 	}
 
-	return nil
+	return results, nil
 }
 
-// getTracesInWindow 获取时间窗口内的追踪数据
-func (a *Analyzer) getTracesInWindow() map[types.TraceID][]*Span {
+// OnAnomaly 注册异常监听器, 每个追踪分析出的系统异常都会回调
+func (a *Analyzer) OnAnomaly(listener func(types.TraceID, types.Anomaly)) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.listeners = append(a.listeners, listener)
+}
+
+// notifyAnomalies 通知异常监听器
+func (a *Analyzer) notifyAnomalies(analysis *TraceAnalysis) {
+	if len(analysis.Anomalies) == 0 {
+		return
+	}
+
 	a.mu.RLock()
-	defer a.mu.RUnlock()
+	listeners := append([]func(types.TraceID, types.Anomaly){}, a.listeners...)
+	a.mu.RUnlock()
 
-	traces := make(map[types.TraceID][]*Span)
-	cutoff := time.Now().Add(-a.config.AnalysisInterval)
+	for _, anomaly := range analysis.Anomalies {
+		for _, listener := range listeners {
+			listener(analysis.TraceID, anomaly)
+		}
+	}
+}
+
+// getTracesInWindow 获取时间窗口内的追踪数据
+func (a *Analyzer) getTracesInWindow(ctx context.Context) (map[types.TraceID][]*Span, error) {
+	// 窗口跟随有效间隔, 避免间隔调节后遗漏追踪
+	window := a.EffectiveInterval()
 
 	// 从recorder获取原始数据
-	records := a.recorder.GetRecords()
+	now := time.Now()
+	records, err := a.recorder.QueryRange(ctx, now.Add(-window), now.Add(time.Nanosecond))
+	if err != nil {
+		return nil, err
+	}
 
-	// 按TraceID分组并过滤时间窗口
+	return groupSpans(records), nil
+}
+
+// groupSpans 按TraceID分组跨度记录, 非跨度记录被忽略
+func groupSpans(records []TraceRecord) map[types.TraceID][]*Span {
+	traces := make(map[types.TraceID][]*Span)
 	for _, record := range records {
-		if record.Timestamp.After(cutoff) {
-			traces[record.TraceID] = append(traces[record.TraceID], record.Data.(*Span))
+		if span, ok := recordSpan(record); ok {
+			traces[record.TraceID] = append(traces[record.TraceID], span)
 		}
 	}
-
 	return traces
 }
 
 // generateAnalysisID 生成分析ID
 func generateAnalysisID() string {
-	return fmt.Sprintf("analysis-%d", time.Now().UnixNano())
+	return core.NewID("analysis-")
 }
 
 // analyzeSystemTrace 分析系统层面的追踪
@@ -259,14 +411,31 @@ func (a *Analyzer) analyzeSystemTrace(analysis *TraceAnalysis, spans []*Span) er
 	bottlenecks := a.detectBottlenecks(spans)
 	analysis.Bottlenecks = bottlenecks
 
-	// 计算指标
+	// 计算指标, 跨度耗时分位数以毫秒计入指标
 	metrics := a.calculateSystemMetrics(spans)
+	latency := a.spanLatencySketch(spans)
+	summary := latency.Summary()
+	metrics["latency_p50"] = summary.P50
+	metrics["latency_p90"] = summary.P90
+	metrics["latency_p99"] = summary.P99
+	metrics["latency_p999"] = summary.P999
 	analysis.Metrics = metrics
+	analysis.Latency = latencyQuantiles(latency)
 
 	// 检测异常
 	anomalies := a.detectSystemAnomalies(spans, patterns)
 	analysis.Anomalies = anomalies
 
+	// 更新基线, 在检测之后观测以免当前值影响本次阈值
+	a.observeBaselines(spans)
+
+	// 提取关键路径
+	analysis.CriticalPath = extractCriticalPath(spans, a.config.CriticalPathTopN)
+	analysis.SpanCount = len(spans)
+	if analysis.CriticalPath != nil {
+		analysis.Duration = analysis.CriticalPath.Total
+	}
+
 	return nil
 }
 
@@ -607,12 +776,12 @@ func (a *Analyzer) detectBottlenecks(spans []*Span) []types.Bottleneck {
 	bottlenecks := make([]types.Bottleneck, 0)
 
 	// 检测延迟瓶颈
-	if b := detectLatencyBottleneck(spans); b != nil {
+	if b := a.detectLatencyBottleneck(spans); b != nil {
 		bottlenecks = append(bottlenecks, *b)
 	}
 
 	// 检测资源瓶颈
-	if b := detectResourceBottleneck(spans); b != nil {
+	if b := a.detectResourceBottleneck(spans); b != nil {
 		bottlenecks = append(bottlenecks, *b)
 	}
 
@@ -620,7 +789,7 @@ func (a *Analyzer) detectBottlenecks(spans []*Span) []types.Bottleneck {
 }
 
 // detectLatencyBottleneck 检测延迟瓶颈
-func detectLatencyBottleneck(spans []*Span) *types.Bottleneck {
+func (a *Analyzer) detectLatencyBottleneck(spans []*Span) *types.Bottleneck {
 	if len(spans) == 0 {
 		return nil
 	}
@@ -632,39 +801,48 @@ func detectLatencyBottleneck(spans []*Span) *types.Bottleneck {
 	}
 	avgLatency := totalLatency / time.Duration(len(spans))
 
-	// 如果平均延迟超过阈值则判定为瓶颈
-	if avgLatency > defaultLatencyThreshold {
-		return &types.Bottleneck{
+	// 如果平均延迟超过阈值则判定为瓶颈, 归因到自身耗时最多的位置
+	threshold := a.latencyThreshold()
+	if ms := durationMillis(avgLatency); ms > threshold {
+		b := &types.Bottleneck{
 			Type:     "latency",
 			Resource: "system",
-			Severity: calculateLatencySeverity(avgLatency),
+			Severity: calculateLatencySeverity(ms, threshold),
 			Duration: avgLatency,
 		}
+		b.Location, _ = attributeLatency(spans)
+		return b
 	}
 	return nil
 }
 
 // calculateLatencySeverity 计算延迟严重程度
-func calculateLatencySeverity(latency time.Duration) float64 {
-	// 根据延迟时间计算严重程度 0-1
-	normalized := float64(latency) / float64(maxLatencyThreshold)
+func calculateLatencySeverity(latency, threshold float64) float64 {
+	// 根据延迟时间计算严重程度 0-1, 达到阈值两倍时为1
+	if threshold <= 0 {
+		return 1
+	}
+	normalized := latency / (2 * threshold)
 	return math.Max(0, math.Min(1, normalized))
 }
 
 // detectResourceBottleneck 检测资源瓶颈
-func detectResourceBottleneck(spans []*Span) *types.Bottleneck {
+func (a *Analyzer) detectResourceBottleneck(spans []*Span) *types.Bottleneck {
 	// 统计资源使用
 	resourceUsage := calculateResourceUsage(spans)
 
 	// 检查是否超过阈值
 	for resource, usage := range resourceUsage {
-		if usage > defaultResourceThreshold {
-			return &types.Bottleneck{
+		threshold := a.resourceThreshold(resource)
+		if usage > threshold {
+			b := &types.Bottleneck{
 				Type:     "resource",
 				Resource: resource,
-				Severity: calculateResourceSeverity(usage),
+				Severity: calculateResourceSeverity(usage, threshold),
 				Impact:   usage,
 			}
+			b.Location, _ = attributeResource(spans, resource)
+			return b
 		}
 	}
 	return nil
@@ -697,9 +875,12 @@ func calculateResourceUsage(spans []*Span) map[string]float64 {
 }
 
 // calculateResourceSeverity 计算资源瓶颈严重程度
-func calculateResourceSeverity(usage float64) float64 {
+func calculateResourceSeverity(usage, threshold float64) float64 {
 	// 基于使用率计算严重程度 0-1
-	return math.Max(0, math.Min(1, (usage-defaultResourceThreshold)/(1-defaultResourceThreshold)))
+	if threshold >= 1 {
+		return math.Max(0, math.Min(1, usage-threshold))
+	}
+	return math.Max(0, math.Min(1, (usage-threshold)/(1-threshold)))
 }
 
 // calculateSystemMetrics 计算系统指标
@@ -745,7 +926,7 @@ func calculateAvgLatency(spans []*Span) float64 {
 		totalLatency += span.Duration
 	}
 
-	return float64(totalLatency.Milliseconds()) / float64(len(spans))
+	return durationMillis(totalLatency) / float64(len(spans))
 }
 
 // calculateCPUUsage 计算CPU使用率
@@ -785,7 +966,7 @@ func (a *Analyzer) detectSystemAnomalies(spans []*Span, patterns []types.TracePa
 	anomalies := make([]types.Anomaly, 0)
 
 	// 检测性能异常
-	if anomaly := detectPerformanceAnomaly(spans); anomaly != nil {
+	if anomaly := a.detectPerformanceAnomaly(spans); anomaly != nil {
 		anomalies = append(anomalies, *anomaly)
 	}
 
@@ -798,19 +979,20 @@ func (a *Analyzer) detectSystemAnomalies(spans []*Span, patterns []types.TracePa
 }
 
 // detectPerformanceAnomaly 检测性能异常
-func detectPerformanceAnomaly(spans []*Span) *types.Anomaly {
+func (a *Analyzer) detectPerformanceAnomaly(spans []*Span) *types.Anomaly {
 	if len(spans) == 0 {
 		return nil
 	}
 
-	// 计算平均延迟
+	// 计算平均延迟(毫秒)
 	avgLatency := calculateAvgLatency(spans)
-	if avgLatency > float64(defaultLatencyThreshold) {
+	threshold := a.latencyThreshold()
+	if avgLatency > threshold {
 		return &types.Anomaly{
 			Type:       "performance",
-			Severity:   calculateLatencySeverity(time.Duration(avgLatency) * time.Millisecond),
+			Severity:   calculateLatencySeverity(avgLatency, threshold),
 			Metric:     "latency",
-			Threshold:  float64(defaultLatencyThreshold),
+			Threshold:  threshold,
 			Value:      avgLatency,
 			DetectedAt: time.Now(),
 		}
@@ -819,6 +1001,83 @@ func detectPerformanceAnomaly(spans []*Span) *types.Anomaly {
 	return nil
 }
 
+// SetBaseline 设置基线学习器, 启用自适应阈值
+func (a *Analyzer) SetBaseline(learner *baseline.Learner) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.baseline = learner
+}
+
+// SetCorrelator 设置异常关联引擎, 为检测到的异常标注可能原因
+func (a *Analyzer) SetCorrelator(engine *correlation.Engine) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.correlator = engine
+}
+
+// correlateAnomalies 关联分析结果中的系统异常
+func (a *Analyzer) correlateAnomalies(analysis *TraceAnalysis) {
+	a.mu.RLock()
+	engine := a.correlator
+	a.mu.RUnlock()
+
+	if engine == nil {
+		return
+	}
+	for i := range analysis.Anomalies {
+		engine.Correlate(analysis.TraceID, &analysis.Anomalies[i])
+	}
+}
+
+// latencyThreshold 当前延迟阈值(毫秒)
+func (a *Analyzer) latencyThreshold() float64 {
+	fallback := durationMillis(defaultLatencyThreshold)
+
+	a.mu.RLock()
+	learner := a.baseline
+	a.mu.RUnlock()
+
+	if learner == nil {
+		return fallback
+	}
+	threshold, _ := learner.Threshold(latencyBaselineMetric, fallback)
+	return threshold
+}
+
+// resourceThreshold 当前资源使用阈值
+func (a *Analyzer) resourceThreshold(resource string) float64 {
+	a.mu.RLock()
+	learner := a.baseline
+	a.mu.RUnlock()
+
+	if learner == nil {
+		return defaultResourceThreshold
+	}
+	threshold, _ := learner.Threshold(resourceBaselinePrefix+resource, defaultResourceThreshold)
+	return threshold
+}
+
+// observeBaselines 将本次追踪的指标计入基线
+func (a *Analyzer) observeBaselines(spans []*Span) {
+	a.mu.RLock()
+	learner := a.baseline
+	a.mu.RUnlock()
+
+	if learner == nil || len(spans) == 0 {
+		return
+	}
+
+	learner.Observe(latencyBaselineMetric, calculateAvgLatency(spans))
+	for resource, usage := range calculateResourceUsage(spans) {
+		learner.Observe(resourceBaselinePrefix+resource, usage)
+	}
+}
+
+// durationMillis 时长转换为毫秒
+func durationMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
 // detectPatternAnomaly 检测模式异常
 func detectPatternAnomaly(patterns []types.TracePattern) *types.Anomaly {
 	if len(patterns) == 0 {
@@ -994,7 +1253,7 @@ func (a *Analyzer) extractQuantumStates(spans []*Span) []*core.QuantumState {
 	states := make([]*core.QuantumState, 0)
 
 	for _, span := range spans {
-		state, ok := span.Fields["quantum_state"].(*core.QuantumState)
+		state, ok := span.QuantumState()
 		if !ok {
 			continue
 		}
@@ -1012,7 +1271,7 @@ func (a *Analyzer) extractFieldEvolution(spans []*Span) []*core.FieldState {
 
 	for _, span := range spans {
 		// 获取场态
-		state, ok := span.Fields["field_state"].(*core.FieldState)
+		state, ok := span.FieldState()
 		if !ok {
 			continue
 		}
@@ -1070,7 +1329,7 @@ func (a *Analyzer) filterModelSpans(spans []*Span) []*Span {
 func (a *Analyzer) filterQuantumSpans(spans []*Span) []*Span {
 	quantumSpans := make([]*Span, 0)
 	for _, span := range spans {
-		if _, ok := span.Fields["quantum_state"]; ok {
+		if _, ok := span.QuantumState(); ok {
 			quantumSpans = append(quantumSpans, span)
 		}
 	}
@@ -1080,7 +1339,7 @@ func (a *Analyzer) filterQuantumSpans(spans []*Span) []*Span {
 func (a *Analyzer) filterFieldSpans(spans []*Span) []*Span {
 	fieldSpans := make([]*Span, 0)
 	for _, span := range spans {
-		if _, ok := span.Fields["field_state"]; ok {
+		if _, ok := span.FieldState(); ok {
 			fieldSpans = append(fieldSpans, span)
 		}
 	}
@@ -1092,7 +1351,8 @@ func (a *Analyzer) cacheAnalysis(analysis *TraceAnalysis) {
 	a.mu.Lock()
 	defer a.mu.Unlock()
 
-	a.cache.traces[analysis.TraceID] = analysis
+	a.cache.traces.put(analysis)
+	a.rollups.add(analysis)
 	a.status.lastAnalysis = analysis.Timestamp
 }
 
@@ -1108,8 +1368,8 @@ func (a *Analyzer) calculateEntanglement(spans []*Span) float64 {
 	// 计算所有量子态对之间的纠缠度
 	for i := 0; i < len(spans)-1; i++ {
 		for j := i + 1; j < len(spans); j++ {
-			state1, ok1 := spans[i].Fields["quantum_state"].(*core.QuantumState)
-			state2, ok2 := spans[j].Fields["quantum_state"].(*core.QuantumState)
+			state1, ok1 := spans[i].QuantumState()
+			state2, ok2 := spans[j].QuantumState()
 
 			if !ok1 || !ok2 {
 				continue
@@ -1157,7 +1417,7 @@ func (a *Analyzer) calculateCoherence(spans []*Span) float64 {
 	validSpans := 0
 
 	for _, span := range spans {
-		state, ok := span.Fields["quantum_state"].(*core.QuantumState)
+		state, ok := span.QuantumState()
 		if !ok {
 			continue
 		}
@@ -1187,7 +1447,7 @@ func (a *Analyzer) calculatePhase(spans []*Span) float64 {
 
 	var phases []float64
 	for _, span := range spans {
-		state, ok := span.Fields["quantum_state"].(*core.QuantumState)
+		state, ok := span.QuantumState()
 		if !ok {
 			continue
 		}
@@ -1217,7 +1477,7 @@ func (a *Analyzer) calculateFieldStrength(spans []*Span) float64 {
 	var weights float64
 
 	for _, span := range spans {
-		state, ok := span.Fields["field_state"].(*core.FieldState)
+		state, ok := span.FieldState()
 		if !ok {
 			continue
 		}
@@ -1258,8 +1518,8 @@ func (a *Analyzer) calculateFieldCoupling(spans []*Span) float64 {
 	// 计算场之间的耦合强度
 	for i := 0; i < len(spans)-1; i++ {
 		for j := i + 1; j < len(spans); j++ {
-			field1, ok1 := spans[i].Fields["field_state"].(*core.FieldState)
-			field2, ok2 := spans[j].Fields["field_state"].(*core.FieldState)
+			field1, ok1 := spans[i].FieldState()
+			field2, ok2 := spans[j].FieldState()
 
 			if !ok1 || !ok2 {
 				continue
@@ -1293,7 +1553,7 @@ func (a *Analyzer) calculateResonance(spans []*Span) float64 {
 	amplitudes := make([]float64, 0)
 
 	for _, span := range spans {
-		field, ok := span.Fields["field_state"].(*core.FieldState)
+		field, ok := span.FieldState()
 		if !ok {
 			continue
 		}
@@ -1416,8 +1676,8 @@ func calculateTimeCorrelation(start1, end1, start2, end2 time.Time) float64 {
 // calculateSpaceCorrelation 计算空间相关性
 func calculateSpaceCorrelation(span1, span2 *Span) float64 {
 	// 通过场状态分布计算空间相关性
-	if field1, ok1 := span1.Fields["field_state"].(*core.FieldState); ok1 {
-		if field2, ok2 := span2.Fields["field_state"].(*core.FieldState); ok2 {
+	if field1, ok1 := span1.FieldState(); ok1 {
+		if field2, ok2 := span2.FieldState(); ok2 {
 			return field1.CalculateOverlap(field2)
 		}
 	}
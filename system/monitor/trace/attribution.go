@@ -0,0 +1,155 @@
+// system/monitor/trace/attribution.go
+
+package trace
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// TagPhase 跨度标签中的模型相位, 优先于跨度关联的模型状态
+const TagPhase = "phase"
+
+// modelTypeNames 模型类型名称
+var modelTypeNames = map[model.ModelType]string{
+	model.ModelYinYang:   "yinyang",
+	model.ModelWuXing:    "wuxing",
+	model.ModelBaGua:     "bagua",
+	model.ModelGanZhi:    "ganzhi",
+	model.ModelIntegrate: "integrate",
+	model.ModelTypeAlert: "alert",
+}
+
+// phaseNames 模型相位名称
+var phaseNames = map[model.Phase]string{
+	model.PhaseYin:       "yin",
+	model.PhaseYang:      "yang",
+	model.PhaseYinYang:   "yinyang",
+	model.PhaseWood:      "wood",
+	model.PhaseFire:      "fire",
+	model.PhaseEarth:     "earth",
+	model.PhaseMetal:     "metal",
+	model.PhaseWater:     "water",
+	model.PhaseTransform: "transform",
+	model.Phase_Stable:   "stable",
+	model.Phase_Unstable: "unstable",
+	model.PhaseNeutral:   "neutral",
+}
+
+// resourceMetrics 资源对应的跨度指标
+var resourceMetrics = map[string]string{
+	"cpu":    "cpu_usage",
+	"memory": "memory_usage",
+}
+
+// spanLocation 由跨度的子系统标签、名称和模型信息确定位置
+// 只有模型信息的跨度归入model子系统
+func spanLocation(span *Span) types.BottleneckLocation {
+	location := types.BottleneckLocation{
+		Subsystem: span.Tags[TagSubsystem],
+		Stage:     span.Name,
+		Model:     modelTypeNames[span.ModelType],
+		Phase:     span.Tags[TagPhase],
+	}
+	if span.ModelState != nil {
+		if location.Model == "" {
+			location.Model = modelTypeNames[span.ModelState.Type]
+		}
+		if location.Phase == "" {
+			location.Phase = phaseNames[span.ModelState.Phase]
+		}
+	}
+	if location.Subsystem == "" && location.Model != "" {
+		location.Subsystem = "model"
+	}
+	return location
+}
+
+// attribute 按位置累计各跨度的权重, 返回权重最大的位置
+// 位置的Share为其权重占全部权重的比例, SpanID为该位置上权重最大的跨度; 没有正权重时返回false
+func attribute(spans []*Span, weight func(span *Span) float64) (types.BottleneckLocation, bool) {
+	type group struct {
+		total     float64
+		top       *Span
+		topWeight float64
+	}
+
+	groups := make(map[types.BottleneckLocation]*group)
+	sum := 0.0
+	for _, span := range spans {
+		w := weight(span)
+		if w <= 0 {
+			continue
+		}
+		sum += w
+
+		location := spanLocation(span)
+		g, exists := groups[location]
+		if !exists {
+			g = &group{}
+			groups[location] = g
+		}
+		g.total += w
+		if g.top == nil || w > g.topWeight {
+			g.top, g.topWeight = span, w
+		}
+	}
+	if sum == 0 {
+		return types.BottleneckLocation{}, false
+	}
+
+	// 权重相同时按位置字符串排序, 保证结果稳定
+	locations := make([]types.BottleneckLocation, 0, len(groups))
+	for location := range groups {
+		locations = append(locations, location)
+	}
+	sort.Slice(locations, func(i, j int) bool {
+		ti, tj := groups[locations[i]].total, groups[locations[j]].total
+		if ti != tj {
+			return ti > tj
+		}
+		return locations[i].String() < locations[j].String()
+	})
+
+	best := locations[0]
+	g := groups[best]
+	best.SpanID = g.top.ID
+	best.Share = g.total / sum
+	return best, true
+}
+
+// attributeLatency 按跨度自身耗时归因, 子跨度覆盖的时间归属子跨度
+func attributeLatency(spans []*Span) (types.BottleneckLocation, bool) {
+	self := selfTimes(spans)
+	return attribute(spans, func(span *Span) float64 {
+		return float64(self[span])
+	})
+}
+
+// attributeResource 按跨度上记录的资源用量归因
+func attributeResource(spans []*Span, resource string) (types.BottleneckLocation, bool) {
+	metric, ok := resourceMetrics[resource]
+	if !ok {
+		return types.BottleneckLocation{}, false
+	}
+	return attribute(spans, func(span *Span) float64 {
+		return span.Metrics[metric]
+	})
+}
+
+// selfTimes 各跨度的自身耗时, 即总耗时减去子跨度覆盖的时间
+func selfTimes(spans []*Span) map[*Span]time.Duration {
+	chain := buildCallChain(spans)
+	self := make(map[*Span]time.Duration, len(spans))
+	for _, span := range spans {
+		children := chainChildren(chain, span)
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].StartTime.Before(children[j].StartTime)
+		})
+		self[span] = spanEnd(span).Sub(span.StartTime) - coveredTime(span, children)
+	}
+	return self
+}
@@ -0,0 +1,205 @@
+// system/monitor/trace/builder.go
+
+package trace
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 分析器识别的跨度字段
+const (
+	FieldQuantumState = "quantum_state" // *core.QuantumState
+	FieldFieldState   = "field_state"   // *core.FieldState
+)
+
+// SpanBuilder 跨度构建器, 设置属性时校验类型和取值, 校验错误在Start或Build时一并返回
+type SpanBuilder struct {
+	tracker *Tracker
+	span    *Span
+	errs    []error
+}
+
+// NewSpan 创建由追踪器登记的跨度构建器
+func (t *Tracker) NewSpan(name string) *SpanBuilder {
+	b := NewSpanBuilder(name)
+	b.tracker = t
+	return b
+}
+
+// NewSpanBuilder 创建独立的跨度构建器, Build得到的跨度不登记到追踪器
+func NewSpanBuilder(name string) *SpanBuilder {
+	return &SpanBuilder{span: &Span{
+		Name:    name,
+		Status:  types.SpanStatusNone,
+		Tags:    make(map[string]string),
+		Events:  make([]SpanEvent, 0),
+		Metrics: make(map[string]float64),
+		Fields:  make(map[string]interface{}),
+	}}
+}
+
+// WithParent 设置父跨度, 跨度归入父跨度的追踪
+func (b *SpanBuilder) WithParent(parent *Span) *SpanBuilder {
+	if parent == nil {
+		return b.fail("nil parent span")
+	}
+	b.span.TraceID = parent.TraceID
+	b.span.ParentID = parent.ID
+	return b
+}
+
+// WithTag 设置标签
+func (b *SpanBuilder) WithTag(key, value string) *SpanBuilder {
+	if key == "" {
+		return b.fail("empty tag key")
+	}
+	b.span.Tags[key] = value
+	return b
+}
+
+// WithMetric 设置指标, 取值须为有限数
+func (b *SpanBuilder) WithMetric(name string, value float64) *SpanBuilder {
+	if name == "" {
+		return b.fail("empty metric name")
+	}
+	if !finite(value) {
+		return b.fail("metric " + name + " is not finite")
+	}
+	b.span.Metrics[name] = value
+	return b
+}
+
+// WithModelState 设置模型类型和状态, 状态的模型类型为空时须先设置模型类型
+func (b *SpanBuilder) WithModelState(state model.ModelState) *SpanBuilder {
+	if state.Type != model.ModelTypeNone {
+		b.span.ModelType = state.Type
+	}
+	if b.span.ModelType == model.ModelTypeNone {
+		return b.fail("model state without model type")
+	}
+	b.span.ModelState = &state
+	return b
+}
+
+// WithModelFlow 设置流状态
+func (b *SpanBuilder) WithModelFlow(flow model.FlowModel) *SpanBuilder {
+	b.span.ModelFlow = flow
+	return b
+}
+
+// WithQuantumState 设置量子态, 概率须在[0,1]内且相位和能量为有限数
+func (b *SpanBuilder) WithQuantumState(state *core.QuantumState) *SpanBuilder {
+	if state == nil {
+		return b.fail("nil quantum state")
+	}
+	if p := state.GetProbability(); math.IsNaN(p) || p < 0 || p > 1 {
+		return b.fail("quantum state probability out of range")
+	}
+	if !finite(state.GetPhase()) || !finite(state.GetEnergy()) {
+		return b.fail("quantum state phase or energy is not finite")
+	}
+	b.span.Fields[FieldQuantumState] = state
+	return b
+}
+
+// WithFieldState 设置场态, 相位、能量和振幅须为有限数
+func (b *SpanBuilder) WithFieldState(state *core.FieldState) *SpanBuilder {
+	if state == nil {
+		return b.fail("nil field state")
+	}
+	if !finite(state.Phase) || !finite(state.Energy) || !finite(state.Amplitude) {
+		return b.fail("field state phase, energy or amplitude is not finite")
+	}
+	b.span.Fields[FieldFieldState] = state
+	return b
+}
+
+// WithField 设置自定义字段, 分析器识别的字段须使用对应的方法设置
+func (b *SpanBuilder) WithField(key string, value interface{}) *SpanBuilder {
+	switch key {
+	case "":
+		return b.fail("empty field key")
+	case FieldQuantumState, FieldFieldState:
+		return b.fail("field " + key + " must be set with its typed helper")
+	}
+	b.span.Fields[key] = value
+	return b
+}
+
+// Start 在追踪器中开始跨度
+func (b *SpanBuilder) Start() (*Span, error) {
+	if b.tracker == nil {
+		return nil, types.NewDomainError(types.DomainMonitor, types.ErrState, "span builder has no tracker", nil)
+	}
+	if err := b.err(); err != nil {
+		return nil, err
+	}
+
+	built := b.span
+	span := b.tracker.StartSpan(built.Name, func(s *Span) {
+		if built.TraceID != "" {
+			s.TraceID, s.ParentID = built.TraceID, built.ParentID
+		}
+		s.Tags, s.Metrics, s.Fields = built.Tags, built.Metrics, built.Fields
+		s.ModelType, s.ModelFlow = built.ModelType, built.ModelFlow
+	})
+	// 追踪器按模型类型填充当前模型状态, 显式设置的状态优先
+	if built.ModelState != nil {
+		span.ModelState = built.ModelState
+	}
+	return span, nil
+}
+
+// Build 构建未登记的跨度, 开始时间为构建时间
+func (b *SpanBuilder) Build() (*Span, error) {
+	if err := b.err(); err != nil {
+		return nil, err
+	}
+	span := b.span
+	if span.ID == "" {
+		span.ID = types.SpanID(generateID())
+	}
+	if span.TraceID == "" {
+		span.TraceID = types.TraceID(generateID())
+	}
+	span.StartTime = time.Now()
+	return span, nil
+}
+
+// fail 记录属性错误
+func (b *SpanBuilder) fail(msg string) *SpanBuilder {
+	b.errs = append(b.errs, errors.New(msg))
+	return b
+}
+
+// err 汇总属性错误
+func (b *SpanBuilder) err() error {
+	if len(b.errs) == 0 {
+		return nil
+	}
+	return types.NewDomainError(types.DomainMonitor, types.ErrValidation, "invalid span attributes", errors.Join(b.errs...)).
+		WithContext("span", b.span.Name)
+}
+
+// QuantumState 跨度携带的量子态
+func (s *Span) QuantumState() (*core.QuantumState, bool) {
+	state, ok := s.Fields[FieldQuantumState].(*core.QuantumState)
+	return state, ok && state != nil
+}
+
+// FieldState 跨度携带的场态
+func (s *Span) FieldState() (*core.FieldState, bool) {
+	state, ok := s.Fields[FieldFieldState].(*core.FieldState)
+	return state, ok && state != nil
+}
+
+// finite 是否为有限数
+func finite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
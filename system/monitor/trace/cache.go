@@ -0,0 +1,200 @@
+// system/monitor/trace/cache.go
+
+package trace
+
+import (
+	"container/list"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 分析结果缓存默认参数
+const (
+	defaultCacheMaxEntries = 1000
+	defaultCacheTTL        = time.Hour
+)
+
+// CacheStats 分析结果缓存统计
+type CacheStats struct {
+	Entries     int           `json:"entries"`
+	MaxEntries  int           `json:"max_entries"`
+	TTL         time.Duration `json:"ttl"`
+	Hits        int64         `json:"hits"`
+	Misses      int64         `json:"misses"`
+	Evictions   int64         `json:"evictions"`   // 超出条目上限按最近最少使用驱逐的条目数
+	Expirations int64         `json:"expirations"` // 按分析时间超过TTL移除的条目数
+	Removals    int64         `json:"removals"`    // 经Evict显式移除的条目数
+}
+
+// HitRate 缓存命中率, 尚无查询时为0
+func (s CacheStats) HitRate() float64 {
+	if total := s.Hits + s.Misses; total > 0 {
+		return float64(s.Hits) / float64(total)
+	}
+	return 0
+}
+
+// analysisCache 分析结果的LRU缓存, 由Analyzer的锁保护
+// 条目数超过上限时驱逐最近最少使用的条目, 分析时间超过TTL的条目在读取或清理时移除
+type analysisCache struct {
+	maxEntries int
+	ttl        time.Duration
+	entries    map[types.TraceID]*list.Element
+	order      *list.List // 按最近使用排列, 队首最近使用
+	stats      CacheStats
+}
+
+// newAnalysisCache 创建分析结果缓存
+func newAnalysisCache(config types.TraceCacheConfig) *analysisCache {
+	if config.MaxEntries <= 0 {
+		config.MaxEntries = defaultCacheMaxEntries
+	}
+	if config.TTL <= 0 {
+		config.TTL = defaultCacheTTL
+	}
+	return &analysisCache{
+		maxEntries: config.MaxEntries,
+		ttl:        config.TTL,
+		entries:    make(map[types.TraceID]*list.Element),
+		order:      list.New(),
+	}
+}
+
+// put 写入分析结果, 同一追踪的旧结果被替换
+func (c *analysisCache) put(analysis *TraceAnalysis) {
+	if elem, ok := c.entries[analysis.TraceID]; ok {
+		elem.Value = analysis
+		c.order.MoveToFront(elem)
+		return
+	}
+	c.entries[analysis.TraceID] = c.order.PushFront(analysis)
+	for c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+		c.stats.Evictions++
+	}
+}
+
+// get 读取分析结果, 过期的结果被移除并计为未命中
+func (c *analysisCache) get(traceID types.TraceID, now time.Time) (*TraceAnalysis, bool) {
+	elem, ok := c.entries[traceID]
+	if ok && c.expired(elem.Value.(*TraceAnalysis), now) {
+		c.removeElement(elem)
+		c.stats.Expirations++
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		return nil, false
+	}
+	c.stats.Hits++
+	c.order.MoveToFront(elem)
+	return elem.Value.(*TraceAnalysis), true
+}
+
+// remove 移除分析结果
+func (c *analysisCache) remove(traceID types.TraceID) bool {
+	elem, ok := c.entries[traceID]
+	if !ok {
+		return false
+	}
+	c.removeElement(elem)
+	c.stats.Removals++
+	return true
+}
+
+// expire 移除所有过期的分析结果, 返回移除数
+func (c *analysisCache) expire(now time.Time) int {
+	removed := 0
+	for elem := c.order.Back(); elem != nil; {
+		prev := elem.Prev()
+		if c.expired(elem.Value.(*TraceAnalysis), now) {
+			c.removeElement(elem)
+			removed++
+		}
+		elem = prev
+	}
+	c.stats.Expirations += int64(removed)
+	return removed
+}
+
+// shrink 按最近最少使用驱逐到最多keep个条目, 返回驱逐数
+func (c *analysisCache) shrink(keep int) int {
+	evicted := 0
+	for c.order.Len() > keep {
+		c.removeElement(c.order.Back())
+		evicted++
+	}
+	c.stats.Evictions += int64(evicted)
+	return evicted
+}
+
+// len 条目数
+func (c *analysisCache) len() int {
+	return c.order.Len()
+}
+
+// approxBytes 分析结果的近似字节数
+func (c *analysisCache) approxBytes() int64 {
+	bytes := types.ApproxSize(c.entries)
+	for elem := c.order.Front(); elem != nil; elem = elem.Next() {
+		bytes += types.ApproxSize(elem.Value)
+	}
+	return bytes
+}
+
+// snapshot 缓存统计
+func (c *analysisCache) snapshot() CacheStats {
+	stats := c.stats
+	stats.Entries = c.order.Len()
+	stats.MaxEntries = c.maxEntries
+	stats.TTL = c.ttl
+	return stats
+}
+
+// expired 分析结果是否超过TTL
+func (c *analysisCache) expired(analysis *TraceAnalysis, now time.Time) bool {
+	return now.Sub(analysis.Timestamp) > c.ttl
+}
+
+// removeElement 移除链表元素及其索引
+func (c *analysisCache) removeElement(elem *list.Element) {
+	c.order.Remove(elem)
+	delete(c.entries, elem.Value.(*TraceAnalysis).TraceID)
+}
+
+// GetAnalysis 获取缓存的追踪分析结果, 计入命中统计
+func (a *Analyzer) GetAnalysis(traceID types.TraceID) (*TraceAnalysis, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cache.traces.get(traceID, time.Now())
+}
+
+// Evict 从缓存移除追踪的分析结果
+func (a *Analyzer) Evict(traceID types.TraceID) bool {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.cache.traces.remove(traceID)
+}
+
+// CacheStats 获取分析结果缓存统计
+func (a *Analyzer) CacheStats() CacheStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.cache.traces.snapshot()
+}
+
+// CacheMetrics 分析结果缓存指标
+func (a *Analyzer) CacheMetrics() map[string]float64 {
+	stats := a.CacheStats()
+	return map[string]float64{
+		"entries":     float64(stats.Entries),
+		"max_entries": float64(stats.MaxEntries),
+		"hits":        float64(stats.Hits),
+		"misses":      float64(stats.Misses),
+		"hit_rate":    stats.HitRate(),
+		"evictions":   float64(stats.Evictions),
+		"expirations": float64(stats.Expirations),
+		"removals":    float64(stats.Removals),
+	}
+}
@@ -0,0 +1,107 @@
+// system/monitor/trace/causal.go
+
+package trace
+
+import (
+	"context"
+	"sort"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 因果跨度标签
+const (
+	TagSubsystem = "subsystem" // 上报跨度的子系统
+	TagError     = "error"     // 失败原因
+)
+
+// CausalStep 因果链中的一步
+type CausalStep struct {
+	Span  *Span
+	Depth int // 距根跨度的层数, 根为0
+}
+
+// ReportSpan 接收其他子系统上报的已完成跨度, 经采样后与本地跨度一同记录和分析
+// 实现types.SpanReporter, 发送失败时记录错误而不阻塞调用方
+func (t *Tracker) ReportSpan(cs types.CausalSpan) {
+	span := &Span{
+		ID:        cs.Trace.SpanID,
+		TraceID:   cs.Trace.TraceID,
+		ParentID:  cs.Parent,
+		Name:      cs.Name,
+		StartTime: cs.Start,
+		EndTime:   cs.End,
+		Duration:  cs.End.Sub(cs.Start),
+		Status:    types.SpanStatusComplete,
+		Tags:      make(map[string]string, len(cs.Tags)+2),
+		Events:    make([]SpanEvent, 0),
+		Metrics:   make(map[string]float64),
+		Fields:    make(map[string]interface{}),
+	}
+	for k, v := range cs.Tags {
+		span.Tags[k] = v
+	}
+	if cs.Subsystem != "" {
+		span.Tags[TagSubsystem] = cs.Subsystem
+	}
+	if cs.Error != "" {
+		span.Status = types.SpanStatusError
+		span.Tags[TagError] = cs.Error
+	}
+
+	if err := t.sendSpan(span); err != nil {
+		t.recordError(err)
+	}
+}
+
+// CausalChain 重建追踪的端到端因果链, 按父子关系深度优先排列, 同层按开始时间排序
+// 父跨度未被记录(如被采样丢弃)的跨度作为根
+// 跨度经记录器查询, 未设置存储后端时只包含尚未刷新的跨度
+func (a *Analyzer) CausalChain(ctx context.Context, traceID types.TraceID) ([]CausalStep, error) {
+	records, err := a.recorder.QueryTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	spans := make(map[types.SpanID]*Span)
+	for _, record := range records {
+		if span, ok := recordSpan(record); ok {
+			spans[span.ID] = span
+		}
+	}
+
+	roots := make([]*Span, 0)
+	children := make(map[types.SpanID][]*Span)
+	for _, span := range spans {
+		if _, ok := spans[span.ParentID]; span.ParentID == "" || !ok {
+			roots = append(roots, span)
+			continue
+		}
+		children[span.ParentID] = append(children[span.ParentID], span)
+	}
+
+	byStart := func(list []*Span) {
+		sort.Slice(list, func(i, j int) bool {
+			if !list[i].StartTime.Equal(list[j].StartTime) {
+				return list[i].StartTime.Before(list[j].StartTime)
+			}
+			return list[i].ID < list[j].ID
+		})
+	}
+
+	steps := make([]CausalStep, 0, len(spans))
+	var walk func(span *Span, depth int)
+	walk = func(span *Span, depth int) {
+		steps = append(steps, CausalStep{Span: span, Depth: depth})
+		next := children[span.ID]
+		byStart(next)
+		for _, child := range next {
+			walk(child, depth+1)
+		}
+	}
+	byStart(roots)
+	for _, root := range roots {
+		walk(root, 0)
+	}
+	return steps, nil
+}
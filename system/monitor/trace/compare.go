@@ -0,0 +1,271 @@
+// system/monitor/trace/compare.go
+
+package trace
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 变化趋势
+const (
+	TrendRising  = "rising"
+	TrendFalling = "falling"
+	TrendStable  = "stable"
+)
+
+// trendTolerance 相对变化不超过该比例时视为稳定
+const trendTolerance = 0.05
+
+// Window 比较的时间窗口[From, To)
+type Window struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+}
+
+// LatencyPercentiles 追踪耗时分位数
+type LatencyPercentiles struct {
+	P50 time.Duration `json:"p50"`
+	P90 time.Duration `json:"p90"`
+	P99 time.Duration `json:"p99"`
+	Max time.Duration `json:"max"`
+}
+
+// WindowSummary 时间窗口内追踪的汇总
+type WindowSummary struct {
+	Window       Window             `json:"window"`
+	Traces       int                `json:"traces"`
+	Latency      LatencyPercentiles `json:"latency"`
+	Bottlenecks  map[string]int     `json:"bottlenecks"` // 按"类型:资源@位置"统计的出现次数
+	Patterns     map[string]int     `json:"patterns"`    // 按类型统计的模式出现次数
+	Entanglement float64            `json:"entanglement"`
+	Coherence    float64            `json:"coherence"`
+}
+
+// LatencyShift 耗时分位数的变化
+type LatencyShift struct {
+	Percentile string        `json:"percentile"`
+	Before     time.Duration `json:"before"`
+	After      time.Duration `json:"after"`
+	Delta      time.Duration `json:"delta"`
+	Change     float64       `json:"change"` // 相对变化, 之前为0时为0
+}
+
+// PatternShift 模式出现频率的变化, 频率为每个追踪的平均出现次数
+type PatternShift struct {
+	Type   string  `json:"type"`
+	Before float64 `json:"before"`
+	After  float64 `json:"after"`
+	Delta  float64 `json:"delta"`
+}
+
+// Trend 量子指标的变化趋势
+type Trend struct {
+	Before    float64 `json:"before"`
+	After     float64 `json:"after"`
+	Delta     float64 `json:"delta"`
+	Direction string  `json:"direction"` // TrendRising, TrendFalling, TrendStable
+}
+
+// WindowComparison 两个时间窗口的追踪对比, 用于配置或适应变更前后的分析
+type WindowComparison struct {
+	Before WindowSummary `json:"before"`
+	After  WindowSummary `json:"after"`
+
+	Latency             []LatencyShift `json:"latency"`
+	NewBottlenecks      []string       `json:"new_bottlenecks"`      // 只在后一窗口出现的瓶颈
+	ResolvedBottlenecks []string       `json:"resolved_bottlenecks"` // 只在前一窗口出现的瓶颈
+	Patterns            []PatternShift `json:"patterns"`             // 按变化幅度降序
+	Entanglement        Trend          `json:"entanglement"`
+	Coherence           Trend          `json:"coherence"`
+}
+
+// CompareWindows 比较两个时间窗口内的追踪: 耗时分位数、瓶颈的出现和消失、模式频率和纠缠度、相干性的变化
+// 直接由记录的跨度计算, 不写入分析缓存和汇总, 也不通知监听器; 瓶颈阈值使用当前基线
+func (a *Analyzer) CompareWindows(ctx context.Context, w1, w2 Window) (*WindowComparison, error) {
+	for _, w := range []Window{w1, w2} {
+		if !w.To.After(w.From) {
+			return nil, types.NewDomainError(types.DomainMonitor, types.ErrValidation, "window end must be after start", nil).
+				WithContext("from", w.From).
+				WithContext("to", w.To)
+		}
+	}
+
+	before, err := a.summarizeWindow(ctx, w1)
+	if err != nil {
+		return nil, err
+	}
+	after, err := a.summarizeWindow(ctx, w2)
+	if err != nil {
+		return nil, err
+	}
+
+	comparison := &WindowComparison{
+		Before: before,
+		After:  after,
+		Latency: []LatencyShift{
+			latencyShift("p50", before.Latency.P50, after.Latency.P50),
+			latencyShift("p90", before.Latency.P90, after.Latency.P90),
+			latencyShift("p99", before.Latency.P99, after.Latency.P99),
+			latencyShift("max", before.Latency.Max, after.Latency.Max),
+		},
+		NewBottlenecks:      missingKeys(after.Bottlenecks, before.Bottlenecks),
+		ResolvedBottlenecks: missingKeys(before.Bottlenecks, after.Bottlenecks),
+		Patterns:            patternShifts(before, after),
+		Entanglement:        trend(before.Entanglement, after.Entanglement),
+		Coherence:           trend(before.Coherence, after.Coherence),
+	}
+	return comparison, nil
+}
+
+// summarizeWindow 汇总窗口内的追踪
+func (a *Analyzer) summarizeWindow(ctx context.Context, w Window) (WindowSummary, error) {
+	summary := WindowSummary{
+		Window:      w,
+		Bottlenecks: make(map[string]int),
+		Patterns:    make(map[string]int),
+	}
+
+	records, err := a.recorder.QueryRange(ctx, w.From, w.To)
+	if err != nil {
+		return summary, types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to load traces", err)
+	}
+	traces := groupSpans(records)
+
+	durations := make([]time.Duration, 0, len(traces))
+	var entanglement, coherence float64
+	quantum := 0
+	for _, spans := range traces {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+		summary.Traces++
+
+		if path := extractCriticalPath(spans, 1); path != nil {
+			durations = append(durations, path.Total)
+		}
+		for _, b := range a.detectBottlenecks(spans) {
+			summary.Bottlenecks[bottleneckKey(b)]++
+		}
+		for _, p := range a.detectSystemPatterns(spans) {
+			summary.Patterns[p.Type]++
+		}
+		if quantumSpans := a.filterQuantumSpans(spans); len(quantumSpans) > 0 {
+			entanglement += a.calculateEntanglement(quantumSpans)
+			coherence += a.calculateCoherence(quantumSpans)
+			quantum++
+		}
+	}
+
+	summary.Latency = latencyPercentiles(durations)
+	if quantum > 0 {
+		summary.Entanglement = entanglement / float64(quantum)
+		summary.Coherence = coherence / float64(quantum)
+	}
+	return summary, nil
+}
+
+// bottleneckKey 瓶颈的类型、资源和位置, 位置未知时省略
+func bottleneckKey(b types.Bottleneck) string {
+	key := b.Type + ":" + b.Resource
+	if location := b.Location.String(); location != "" {
+		key += "@" + location
+	}
+	return key
+}
+
+// latencyPercentiles 按最近秩计算分位数
+func latencyPercentiles(durations []time.Duration) LatencyPercentiles {
+	if len(durations) == 0 {
+		return LatencyPercentiles{}
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	at := func(q float64) time.Duration {
+		rank := int(math.Ceil(q*float64(len(durations)))) - 1
+		return durations[max(0, rank)]
+	}
+	return LatencyPercentiles{
+		P50: at(0.5),
+		P90: at(0.9),
+		P99: at(0.99),
+		Max: durations[len(durations)-1],
+	}
+}
+
+// latencyShift 分位数变化
+func latencyShift(percentile string, before, after time.Duration) LatencyShift {
+	shift := LatencyShift{Percentile: percentile, Before: before, After: after, Delta: after - before}
+	if before > 0 {
+		shift.Change = float64(after-before) / float64(before)
+	}
+	return shift
+}
+
+// missingKeys 在from中而不在other中的键, 按键排序
+func missingKeys(from, other map[string]int) []string {
+	keys := make([]string, 0)
+	for key := range from {
+		if _, exists := other[key]; !exists {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// patternShifts 各模式类型每个追踪的平均出现次数变化, 按变化幅度降序
+func patternShifts(before, after WindowSummary) []PatternShift {
+	frequency := func(s WindowSummary, patternType string) float64 {
+		if s.Traces == 0 {
+			return 0
+		}
+		return float64(s.Patterns[patternType]) / float64(s.Traces)
+	}
+
+	seen := make(map[string]bool)
+	shifts := make([]PatternShift, 0)
+	for _, counts := range []map[string]int{before.Patterns, after.Patterns} {
+		for patternType := range counts {
+			if seen[patternType] {
+				continue
+			}
+			seen[patternType] = true
+			shift := PatternShift{
+				Type:   patternType,
+				Before: frequency(before, patternType),
+				After:  frequency(after, patternType),
+			}
+			shift.Delta = shift.After - shift.Before
+			shifts = append(shifts, shift)
+		}
+	}
+
+	sort.Slice(shifts, func(i, j int) bool {
+		di, dj := math.Abs(shifts[i].Delta), math.Abs(shifts[j].Delta)
+		if di != dj {
+			return di > dj
+		}
+		return shifts[i].Type < shifts[j].Type
+	})
+	return shifts
+}
+
+// trend 变化趋势, 相对变化不超过trendTolerance时为稳定
+func trend(before, after float64) Trend {
+	t := Trend{Before: before, After: after, Delta: after - before, Direction: TrendStable}
+	scale := math.Max(math.Abs(before), math.Abs(after))
+	if scale == 0 || math.Abs(t.Delta) <= trendTolerance*scale {
+		return t
+	}
+	if t.Delta > 0 {
+		t.Direction = TrendRising
+	} else {
+		t.Direction = TrendFalling
+	}
+	return t
+}
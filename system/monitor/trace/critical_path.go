@@ -0,0 +1,178 @@
+// system/monitor/trace/critical_path.go
+
+package trace
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认关键跨度数量
+const defaultCriticalTopN = 5
+
+// CriticalSpan 关键路径上的跨度
+type CriticalSpan struct {
+	SpanID   types.SpanID  // 跨度ID
+	Name     string        // 跨度名称
+	Start    time.Time     // 开始时间
+	Duration time.Duration // 跨度总耗时
+	SelfTime time.Duration // 关键路径上归属于该跨度自身的耗时
+	Share    float64       // 自身耗时占追踪总耗时的比例
+}
+
+// CriticalPath 追踪的关键路径
+type CriticalPath struct {
+	Total time.Duration  // 追踪总耗时
+	Spans []CriticalSpan // 路径上的跨度, 按开始时间排序
+	Top   []CriticalSpan // 自身耗时最大的前N个跨度
+}
+
+// extractCriticalPath 计算调用链的关键路径
+// 从根跨度结束时刻向前回溯, 每一步选取在游标前最晚结束的子跨度,
+// 子跨度之间的空隙归属于父跨度自身, 得到的路径即为带权最长路径
+func extractCriticalPath(spans []*Span, topN int) *CriticalPath {
+	if len(spans) == 0 {
+		return nil
+	}
+	if topN <= 0 {
+		topN = defaultCriticalTopN
+	}
+
+	chain := buildCallChain(spans)
+	roots := chainRoots(chain)
+	if len(roots) == 0 {
+		return nil
+	}
+
+	// 多个根跨度时以虚拟根覆盖整个追踪, 其自身耗时即空闲时间
+	root := roots[0]
+	if len(roots) > 1 {
+		root = &Span{StartTime: roots[0].StartTime}
+		for _, r := range roots {
+			if r.StartTime.Before(root.StartTime) {
+				root.StartTime = r.StartTime
+			}
+			if end := spanEnd(r); end.After(root.EndTime) {
+				root.EndTime = end
+			}
+		}
+	}
+
+	self := make(map[*Span]time.Duration)
+	walkCriticalPath(chain, root, roots, spanEnd(root), self)
+
+	path := &CriticalPath{Total: spanEnd(root).Sub(root.StartTime)}
+	for span, d := range self {
+		if span.ID == "" || d <= 0 {
+			continue
+		}
+		cs := CriticalSpan{
+			SpanID:   span.ID,
+			Name:     span.Name,
+			Start:    span.StartTime,
+			Duration: spanEnd(span).Sub(span.StartTime),
+			SelfTime: d,
+		}
+		if path.Total > 0 {
+			cs.Share = float64(d) / float64(path.Total)
+		}
+		path.Spans = append(path.Spans, cs)
+	}
+
+	sort.Slice(path.Spans, func(i, j int) bool {
+		return path.Spans[i].Start.Before(path.Spans[j].Start)
+	})
+
+	path.Top = append([]CriticalSpan{}, path.Spans...)
+	sort.SliceStable(path.Top, func(i, j int) bool {
+		return path.Top[i].SelfTime > path.Top[j].SelfTime
+	})
+	if len(path.Top) > topN {
+		path.Top = path.Top[:topN]
+	}
+
+	return path
+}
+
+// walkCriticalPath 回溯跨度[start, end]区间内的关键路径, 累计各跨度自身耗时
+func walkCriticalPath(chain *CallChain, span *Span, children []*Span, end time.Time, self map[*Span]time.Duration) {
+	cursor := end
+	remaining := append([]*Span{}, children...)
+
+	for cursor.After(span.StartTime) {
+		// 选取游标前最晚结束的子跨度
+		next := -1
+		var nextEnd time.Time
+		for i, child := range remaining {
+			if !child.StartTime.Before(cursor) {
+				continue
+			}
+			childEnd := spanEnd(child)
+			if childEnd.After(cursor) {
+				childEnd = cursor
+			}
+			if next < 0 || childEnd.After(nextEnd) {
+				next, nextEnd = i, childEnd
+			}
+		}
+		if next < 0 {
+			break
+		}
+
+		child := remaining[next]
+		remaining = append(remaining[:next], remaining[next+1:]...)
+
+		// 子跨度结束后到游标之间归属于当前跨度
+		self[span] += cursor.Sub(nextEnd)
+		walkCriticalPath(chain, child, chainChildren(chain, child), nextEnd, self)
+
+		cursor = child.StartTime
+		if cursor.Before(span.StartTime) {
+			cursor = span.StartTime
+		}
+	}
+
+	if cursor.After(span.StartTime) {
+		self[span] += cursor.Sub(span.StartTime)
+	}
+}
+
+// chainRoots 调用链的根跨度, 父跨度不在追踪内的跨度同样视为根
+func chainRoots(chain *CallChain) []*Span {
+	roots := make([]*Span, 0)
+	for _, span := range chain.Nodes {
+		if span.ParentID == "" {
+			roots = append(roots, span)
+			continue
+		}
+		if _, ok := chain.Nodes[string(span.ParentID)]; !ok {
+			roots = append(roots, span)
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		return roots[i].StartTime.Before(roots[j].StartTime)
+	})
+	return roots
+}
+
+// chainChildren 跨度的子跨度
+func chainChildren(chain *CallChain, span *Span) []*Span {
+	ids := chain.Children[string(span.ID)]
+	children := make([]*Span, 0, len(ids))
+	for _, id := range ids {
+		if child, ok := chain.Nodes[id]; ok {
+			children = append(children, child)
+		}
+	}
+	return children
+}
+
+// spanEnd 跨度结束时间, 未记录结束时间时由持续时间推算
+func spanEnd(span *Span) time.Time {
+	if !span.EndTime.IsZero() {
+		return span.EndTime
+	}
+	return span.StartTime.Add(span.Duration)
+}
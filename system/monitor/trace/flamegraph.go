@@ -0,0 +1,178 @@
+// system/monitor/trace/flamegraph.go
+
+package trace
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// FormatFolded 折叠栈格式, 每行为分号分隔的调用栈和自身耗时(微秒), 可直接用于flamegraph.pl和speedscope
+const FormatFolded = "folded"
+
+// foldedSeparator 折叠栈中的帧分隔符
+const foldedSeparator = ";"
+
+// FlameFrame 火焰图中的一个调用栈, 同一追踪内名称路径相同的跨度合并为一帧
+type FlameFrame struct {
+	Stack []string      `json:"stack"` // 从根跨度到该跨度的名称
+	Name  string        `json:"name"`  // 跨度名称
+	Self  time.Duration `json:"self"`  // 自身耗时, 即总耗时减去子跨度覆盖的时间
+	Total time.Duration `json:"total"` // 总耗时
+	Count int           `json:"count"` // 合并的跨度数
+}
+
+// FlameGraph 单个追踪的火焰图数据
+type FlameGraph struct {
+	TraceID types.TraceID `json:"trace_id"`
+	Total   time.Duration `json:"total"`  // 根跨度总耗时之和
+	Frames  []FlameFrame  `json:"frames"` // 按调用栈排序
+}
+
+// BuildFlameGraph 由追踪的跨度构建火焰图数据
+// 父跨度不在追踪内的跨度作为根; 并发子跨度按覆盖区间的并集扣除, 自身耗时不会为负
+func BuildFlameGraph(traceID types.TraceID, spans []*Span) *FlameGraph {
+	graph := &FlameGraph{TraceID: traceID, Frames: make([]FlameFrame, 0)}
+	if len(spans) == 0 {
+		return graph
+	}
+
+	chain := buildCallChain(spans)
+	frames := make(map[string]*FlameFrame)
+
+	var walk func(span *Span, stack []string)
+	walk = func(span *Span, stack []string) {
+		stack = append(stack[:len(stack):len(stack)], flameName(span.Name))
+		children := chainChildren(chain, span)
+		sort.Slice(children, func(i, j int) bool {
+			return children[i].StartTime.Before(children[j].StartTime)
+		})
+
+		total := spanEnd(span).Sub(span.StartTime)
+		key := strings.Join(stack, foldedSeparator)
+		frame, exists := frames[key]
+		if !exists {
+			frame = &FlameFrame{Stack: stack, Name: stack[len(stack)-1]}
+			frames[key] = frame
+		}
+		frame.Self += total - coveredTime(span, children)
+		frame.Total += total
+		frame.Count++
+
+		for _, child := range children {
+			walk(child, stack)
+		}
+	}
+	for _, root := range chainRoots(chain) {
+		graph.Total += spanEnd(root).Sub(root.StartTime)
+		walk(root, nil)
+	}
+
+	keys := make([]string, 0, len(frames))
+	for key := range frames {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		graph.Frames = append(graph.Frames, *frames[key])
+	}
+	return graph
+}
+
+// WriteFolded 以折叠栈格式写出, 自身耗时不足1微秒的帧省略
+func (g *FlameGraph) WriteFolded(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+	for _, frame := range g.Frames {
+		micros := frame.Self.Microseconds()
+		if micros <= 0 {
+			continue
+		}
+		fmt.Fprintf(bw, "%s %d\n", strings.Join(frame.Stack, foldedSeparator), micros)
+	}
+	return bw.Flush()
+}
+
+// FlameGraph 构建追踪的火焰图数据
+// 跨度经记录器查询, 未设置存储后端时只包含尚未刷新的跨度
+func (a *Analyzer) FlameGraph(ctx context.Context, traceID types.TraceID) (*FlameGraph, error) {
+	records, err := a.recorder.QueryTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+
+	spans := make([]*Span, 0, len(records))
+	for _, record := range records {
+		if span, ok := recordSpan(record); ok {
+			spans = append(spans, span)
+		}
+	}
+	if len(spans) == 0 {
+		return nil, types.NewDomainError(types.DomainMonitor, types.ErrNotFound, "trace not found", nil).
+			WithContext("trace_id", traceID)
+	}
+	return BuildFlameGraph(traceID, spans), nil
+}
+
+// ExportFlameGraph 以折叠栈或JSON导出追踪的火焰图数据
+func (a *Analyzer) ExportFlameGraph(ctx context.Context, w io.Writer, format string, traceID types.TraceID) error {
+	graph, err := a.FlameGraph(ctx, traceID)
+	if err != nil {
+		return err
+	}
+
+	switch format {
+	case FormatFolded, "":
+		if err := graph.WriteFolded(w); err != nil {
+			return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "failed to export flame graph", err)
+		}
+		return nil
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(graph); err != nil {
+			return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "failed to export flame graph", err)
+		}
+		return nil
+	default:
+		return types.NewDomainError(types.DomainMonitor, types.ErrValidation, "unsupported export format", nil).
+			WithContext("format", format)
+	}
+}
+
+// coveredTime 子跨度在父跨度区间内覆盖的总时长, 重叠部分只计一次
+func coveredTime(parent *Span, children []*Span) time.Duration {
+	start, end := parent.StartTime, spanEnd(parent)
+	var covered time.Duration
+	cursor := start
+	// children已按开始时间排序
+	for _, child := range children {
+		from, to := child.StartTime, spanEnd(child)
+		if from.Before(cursor) {
+			from = cursor
+		}
+		if to.After(end) {
+			to = end
+		}
+		if to.After(from) {
+			covered += to.Sub(from)
+			cursor = to
+		}
+	}
+	return covered
+}
+
+// flameName 跨度名称中的分隔符和换行会破坏折叠栈格式, 替换为冒号和空格
+func flameName(name string) string {
+	if name == "" {
+		return "unknown"
+	}
+	return strings.NewReplacer(";", ":", "\n", " ", "\r", " ").Replace(name)
+}
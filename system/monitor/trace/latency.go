@@ -0,0 +1,54 @@
+// system/monitor/trace/latency.go
+
+package trace
+
+import (
+	"time"
+
+	"github.com/Corphon/daoflow/system/monitor/quantile"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// spanLatencySketch 追踪内各跨度耗时(毫秒)的分位数草图
+func (a *Analyzer) spanLatencySketch(spans []*Span) *quantile.Sketch {
+	sketch := quantile.New(a.config.Rollup.SketchAccuracy)
+	for _, span := range spans {
+		sketch.Add(durationMillis(span.Duration))
+	}
+	return sketch
+}
+
+// LatencyQuantiles 查询追踪耗时的分位数, 合并满足条件的时间桶的草图
+// 粒度为空时按小时; From所在的时间桶整体计入
+func (a *Analyzer) LatencyQuantiles(q RollupQuery) types.LatencyQuantiles {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	series := a.rollups.series(q.Granularity)
+	merged := quantile.New(series.accuracy)
+	for _, bucket := range series.buckets {
+		if !series.inRange(bucket, q) {
+			continue
+		}
+		// 同一序列的草图精度相同, 合并不会失败
+		merged.Merge(bucket.latency)
+	}
+	return latencyQuantiles(merged)
+}
+
+// latencyQuantiles 将毫秒草图转换为耗时分位数
+func latencyQuantiles(sketch *quantile.Sketch) types.LatencyQuantiles {
+	summary := sketch.Summary()
+	millis := func(v float64) time.Duration {
+		return time.Duration(v * float64(time.Millisecond))
+	}
+	return types.LatencyQuantiles{
+		Count: int64(summary.Count),
+		Mean:  millis(summary.Mean),
+		P50:   millis(summary.P50),
+		P90:   millis(summary.P90),
+		P99:   millis(summary.P99),
+		P999:  millis(summary.P999),
+		Max:   millis(summary.Max),
+	}
+}
@@ -0,0 +1,37 @@
+// system/monitor/trace/memory.go
+
+package trace
+
+import (
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// CacheAnalyses 追踪分析结果缓存
+const CacheAnalyses = "trace.analyses"
+
+// MemoryUsage 分析结果缓存的近似内存占用
+func (a *Analyzer) MemoryUsage() []types.MemoryUsage {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return []types.MemoryUsage{{
+		Cache:   CacheAnalyses,
+		Entries: a.cache.traces.len(),
+		Bytes:   a.cache.traces.approxBytes(),
+	}}
+}
+
+// EvictMemory 按最近最少使用驱逐分析结果, 保留keep条
+func (a *Analyzer) EvictMemory(cache string, keep int) int {
+	if cache != CacheAnalyses {
+		return 0
+	}
+	if keep < 0 {
+		keep = 0
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.cache.traces.shrink(keep)
+}
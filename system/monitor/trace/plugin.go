@@ -0,0 +1,181 @@
+// system/monitor/trace/plugin.go
+
+package trace
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Stage 自定义分析阶段, 返回错误时记入分析器状态而不中断分析
+// 阶段收到已完成前序阶段的分析结果副本, 只应写入Extensions, 其余字段的修改不会保留
+type Stage interface {
+	Analyze(analysis *TraceAnalysis, spans []*Span) error
+}
+
+// StageFunc 函数形式的自定义分析阶段
+type StageFunc func(analysis *TraceAnalysis, spans []*Span) error
+
+// Analyze 执行分析
+func (f StageFunc) Analyze(analysis *TraceAnalysis, spans []*Span) error {
+	return f(analysis, spans)
+}
+
+// StageHook 分析阶段的前后钩子, 在分析协程中同步调用
+type StageHook func(stage string, analysis *TraceAnalysis, spans []*Span)
+
+// StageOptions 自定义阶段选项
+type StageOptions struct {
+	After   string        // 在该阶段之后执行, 为空时在所有阶段之后执行
+	Timeout time.Duration // 时间预算, <=0时按阶段配置或默认预算
+}
+
+// customStage 已注册的自定义阶段
+type customStage struct {
+	name    string
+	stage   Stage
+	options StageOptions
+}
+
+// RegisterStage 注册自定义分析阶段
+func (a *Analyzer) RegisterStage(name string, stage Stage, options StageOptions) error {
+	if name == "" {
+		return types.NewDomainError(types.DomainMonitor, types.ErrValidation, "empty stage name", nil)
+	}
+	if stage == nil {
+		return types.NewDomainError(types.DomainMonitor, types.ErrValidation, "nil analysis stage", nil).
+			WithContext("stage", name)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	known := map[string]bool{StageSystem: true, StageModel: true, StageQuantum: true, StageField: true}
+	for _, s := range a.plugins.stages {
+		known[s.name] = true
+	}
+	if known[name] {
+		return types.NewDomainError(types.DomainMonitor, types.ErrValidation,
+			fmt.Sprintf("analysis stage %s already registered", name), nil)
+	}
+	if options.After != "" && !known[options.After] {
+		return types.NewDomainError(types.DomainMonitor, types.ErrNotFound,
+			fmt.Sprintf("analysis stage %s not registered", options.After), nil).
+			WithContext("stage", name)
+	}
+
+	a.plugins.stages = append(a.plugins.stages, &customStage{name: name, stage: stage, options: options})
+	return nil
+}
+
+// UnregisterStage 注销自定义分析阶段, 排在其后的阶段改为在所有阶段之后执行
+func (a *Analyzer) UnregisterStage(name string) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for i, s := range a.plugins.stages {
+		if s.name != name {
+			continue
+		}
+		a.plugins.stages = append(a.plugins.stages[:i], a.plugins.stages[i+1:]...)
+		for _, other := range a.plugins.stages {
+			if other.options.After == name {
+				other.options.After = ""
+			}
+		}
+		return nil
+	}
+	return types.NewDomainError(types.DomainMonitor, types.ErrNotFound,
+		fmt.Sprintf("analysis stage %s not registered", name), nil)
+}
+
+// BeforeStage 注册阶段执行前的钩子
+func (a *Analyzer) BeforeStage(hook StageHook) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.plugins.before = append(a.plugins.before, hook)
+}
+
+// AfterStage 注册阶段执行后的钩子, 超时跳过的阶段也会调用
+func (a *Analyzer) AfterStage(hook StageHook) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.plugins.after = append(a.plugins.after, hook)
+}
+
+// withCustomStages 将自定义阶段插入内置阶段序列
+func (a *Analyzer) withCustomStages(builtin []analysisStage) []analysisStage {
+	a.mu.RLock()
+	custom := append([]*customStage(nil), a.plugins.stages...)
+	a.mu.RUnlock()
+	if len(custom) == 0 {
+		return builtin
+	}
+
+	placed := make(map[string]bool, len(custom))
+	var insert func(after string, list []analysisStage) []analysisStage
+	insert = func(after string, list []analysisStage) []analysisStage {
+		for _, c := range custom {
+			if c.options.After == after && !placed[c.name] {
+				placed[c.name] = true
+				list = append(list, c.analysisStage())
+				list = insert(c.name, list)
+			}
+		}
+		return list
+	}
+
+	stages := make([]analysisStage, 0, len(builtin)+len(custom))
+	for _, stage := range builtin {
+		stages = append(stages, stage)
+		stages = insert(stage.name, stages)
+	}
+	return insert("", stages)
+}
+
+// analysisStage 自定义阶段在分析结果的浅拷贝上运行, 按时完成后合并其Extensions
+func (c *customStage) analysisStage() analysisStage {
+	return analysisStage{
+		name:    c.name,
+		timeout: c.options.Timeout,
+		custom:  true,
+		prepare: func(analysis *TraceAnalysis) *TraceAnalysis {
+			clone := *analysis
+			clone.Extensions = make(map[string]interface{}, len(analysis.Extensions))
+			for key, value := range analysis.Extensions {
+				clone.Extensions[key] = value
+			}
+			return &clone
+		},
+		run: c.stage.Analyze,
+		merge: func(dst, src *TraceAnalysis) {
+			dst.Extensions = src.Extensions
+		},
+	}
+}
+
+// runHooks 调用阶段钩子, 钩子崩溃时记录错误并继续
+func (a *Analyzer) runHooks(after bool, stage string, analysis *TraceAnalysis, spans []*Span) {
+	a.mu.RLock()
+	hooks := a.plugins.before
+	if after {
+		hooks = a.plugins.after
+	}
+	hooks = append([]StageHook(nil), hooks...)
+	a.mu.RUnlock()
+
+	for _, hook := range hooks {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					a.mu.Lock()
+					a.status.errors = append(a.status.errors, fmt.Errorf("stage hook panic at %s: %v", stage, r))
+					a.mu.Unlock()
+				}
+			}()
+			hook(stage, analysis, spans)
+		}()
+	}
+}
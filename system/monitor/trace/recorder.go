@@ -14,6 +14,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -65,6 +67,10 @@ type Recorder struct {
 	// 通道
 	recordChan chan TraceRecord
 	flushChan  chan struct{}
+
+	// 存储后端, 为nil时按日期写入本地文件
+	storage   TraceStorage
+	retention RetentionPolicy
 }
 
 // ----------------------------------------------------
@@ -94,13 +100,13 @@ func (r *Recorder) Start(ctx context.Context) error {
 	r.mu.Lock()
 	if r.status.isRunning {
 		r.mu.Unlock()
-		return types.NewSystemError(types.ErrRuntime, "recorder already running", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "recorder already running", nil)
 	}
 	r.status.isRunning = true
 	r.mu.Unlock()
 
 	// 启动处理循环
-	go r.processLoop(ctx)
+	supervisor.Go(ctx, "monitor.trace.recorder", r.processLoop)
 
 	return nil
 }
@@ -108,29 +114,104 @@ func (r *Recorder) Start(ctx context.Context) error {
 // Stop 停止记录器
 func (r *Recorder) Stop() error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
-
 	if !r.status.isRunning {
+		r.mu.Unlock()
 		return nil
 	}
-
 	r.status.isRunning = false
+	r.mu.Unlock()
 
 	// 刷新剩余记录
 	return r.flush()
 }
 
+// Drain 处理已排队的记录并写入存储, 记录器继续运行
+func (r *Recorder) Drain(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		select {
+		case record := <-r.recordChan:
+			if err := r.processRecord(record); err != nil {
+				return err
+			}
+			continue
+		default:
+		}
+		return r.flush()
+	}
+}
+
+// SetStorage 设置存储后端和保留策略, 应在启动前调用
+func (r *Recorder) SetStorage(storage TraceStorage, retention RetentionPolicy) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if retention.CheckInterval <= 0 {
+		retention.CheckInterval = defaultRetentionInterval
+	}
+	r.storage = storage
+	r.retention = retention
+}
+
+// QueryRange 查询时间范围[from, to)内的记录, 合并存储后端与未刷新的缓冲
+func (r *Recorder) QueryRange(ctx context.Context, from, to time.Time) ([]TraceRecord, error) {
+	r.mu.RLock()
+	storage := r.storage
+	buffered := make([]TraceRecord, 0, len(r.buffer.records))
+	for _, record := range r.buffer.records {
+		if !record.Timestamp.Before(from) && record.Timestamp.Before(to) {
+			buffered = append(buffered, record)
+		}
+	}
+	r.mu.RUnlock()
+
+	if storage == nil {
+		return buffered, nil
+	}
+
+	stored, err := storage.QueryRange(ctx, from, to, 0)
+	if err != nil {
+		return nil, err
+	}
+	return append(stored, buffered...), nil
+}
+
+// QueryTrace 查询指定追踪的记录
+func (r *Recorder) QueryTrace(ctx context.Context, traceID types.TraceID) ([]TraceRecord, error) {
+	r.mu.RLock()
+	storage := r.storage
+	records := make([]TraceRecord, 0)
+	for _, record := range r.buffer.records {
+		if record.TraceID == traceID {
+			records = append(records, record)
+		}
+	}
+	r.mu.RUnlock()
+
+	if storage == nil {
+		return records, nil
+	}
+
+	stored, err := storage.QueryTrace(ctx, traceID)
+	if err != nil {
+		return nil, err
+	}
+	return append(stored, records...), nil
+}
+
 // Record 记录追踪数据
 func (r *Recorder) Record(record TraceRecord) error {
 	if !r.status.isRunning {
-		return types.NewSystemError(types.ErrRuntime, "recorder not running", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "recorder not running", nil)
 	}
 
 	select {
 	case r.recordChan <- record:
 		return nil
 	default:
-		return types.NewSystemError(types.ErrOverflow, "record buffer full", nil)
+		return types.NewDomainError(types.DomainMonitor, types.ErrOverflow, "record buffer full", nil)
 	}
 }
 
@@ -139,11 +220,18 @@ func (r *Recorder) processLoop(ctx context.Context) {
 	ticker := time.NewTicker(r.config.FlushInterval)
 	defer ticker.Stop()
 
+	retention := time.NewTicker(r.retentionInterval())
+	defer retention.Stop()
+
 	for {
 		select {
 		case <-ctx.Done():
 			r.flush()
 			return
+		case <-retention.C:
+			if err := r.applyRetention(ctx); err != nil {
+				r.recordError(err)
+			}
 		case record := <-r.recordChan:
 			if err := r.processRecord(record); err != nil {
 				r.recordError(err)
@@ -163,14 +251,15 @@ func (r *Recorder) processLoop(ctx context.Context) {
 // processRecord 处理单条记录
 func (r *Recorder) processRecord(record TraceRecord) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
 	// 添加到缓冲
 	r.buffer.records = append(r.buffer.records, record)
 	r.buffer.size += r.estimateRecordSize(record)
+	full := len(r.buffer.records) >= r.config.BatchSize
+	r.mu.Unlock()
 
 	// 检查是否需要刷新
-	if len(r.buffer.records) >= r.config.BatchSize {
+	if full {
 		return r.flush()
 	}
 
@@ -192,6 +281,9 @@ func (r *Recorder) flush() error {
 
 	// 写入存储
 	if err := r.writeRecords(records); err != nil {
+		r.mu.Lock()
+		r.status.isFlushing = false
+		r.mu.Unlock()
 		return err
 	}
 
@@ -206,6 +298,14 @@ func (r *Recorder) flush() error {
 
 // writeRecords 写入记录到存储
 func (r *Recorder) writeRecords(records []TraceRecord) error {
+	r.mu.RLock()
+	storage := r.storage
+	r.mu.RUnlock()
+
+	if storage != nil {
+		return storage.Write(context.Background(), records)
+	}
+
 	// 按日期组织文件路径
 	path := r.generateStoragePath(time.Now())
 
@@ -269,24 +369,24 @@ func (r *Recorder) writeToStorage(path string, data []byte) error {
 	// 创建目录
 	dir := filepath.Dir(path)
 	if err := os.MkdirAll(dir, 0755); err != nil {
-		return fmt.Errorf("create directory failed: %v", err)
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "create directory failed", err)
 	}
 
 	// 创建或打开文件
 	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0644)
 	if err != nil {
-		return fmt.Errorf("open file failed: %v", err)
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "open file failed", err)
 	}
 	defer file.Close()
 
 	// 写入数据
 	if _, err := file.Write(data); err != nil {
-		return fmt.Errorf("write data failed: %v", err)
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "write data failed", err)
 	}
 
 	// 强制刷新到磁盘
 	if err := file.Sync(); err != nil {
-		return fmt.Errorf("sync data failed: %v", err)
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "sync data failed", err)
 	}
 
 	return nil
@@ -339,7 +439,8 @@ func (r *Recorder) cleanOldRecords() error {
 		// 删除过期文件
 		if fileTime.Before(cutoff) {
 			if err := os.Remove(path); err != nil {
-				r.recordError(fmt.Errorf("failed to remove old trace file %s: %v", path, err))
+				r.recordError(types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to remove old trace file", err).
+					WithContext("path", path))
 				return nil // 继续处理其他文件
 			}
 
@@ -352,9 +453,47 @@ func (r *Recorder) cleanOldRecords() error {
 	})
 
 	if err != nil {
-		return types.NewSystemError(types.ErrStorage, "failed to clean old records", err)
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to clean old records", err)
+	}
+
+	return nil
+}
+
+// retentionInterval 保留检查间隔
+func (r *Recorder) retentionInterval() time.Duration {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if r.retention.CheckInterval > 0 {
+		return r.retention.CheckInterval
+	}
+	return defaultRetentionInterval
+}
+
+// applyRetention 执行保留策略
+func (r *Recorder) applyRetention(ctx context.Context) error {
+	r.mu.RLock()
+	storage, retention := r.storage, r.retention
+	r.mu.RUnlock()
+
+	if storage == nil {
+		if r.config.StoragePath == "" || r.config.RetentionDays <= 0 {
+			return nil
+		}
+		return r.cleanOldRecords()
+	}
+
+	if retention.MaxAge <= 0 && retention.MaxRecords <= 0 {
+		return nil
+	}
+	removed, err := storage.Purge(ctx, retention)
+	if err != nil {
+		return err
 	}
 
+	r.mu.Lock()
+	r.stats.totalRecords -= removed
+	r.mu.Unlock()
 	return nil
 }
 
@@ -389,6 +528,14 @@ func (r *Recorder) GetStats() struct {
 	}
 }
 
+// QueueDepth 待处理记录队列的占用比例(0-1)
+func (r *Recorder) QueueDepth() float64 {
+	if cap(r.recordChan) == 0 {
+		return 0
+	}
+	return float64(len(r.recordChan)) / float64(cap(r.recordChan))
+}
+
 // GetRecords 获取记录数据
 func (r *Recorder) GetRecords() []TraceRecord {
 	r.mu.RLock()
@@ -399,3 +546,20 @@ func (r *Recorder) GetRecords() []TraceRecord {
 	copy(records, r.buffer.records)
 	return records
 }
+
+// OnSpan 接收追踪器采样保留的跨度
+func (r *Recorder) OnSpan(span *Span) error {
+	return r.Record(TraceRecord{
+		ID:        string(span.ID),
+		Timestamp: span.StartTime,
+		TraceID:   span.TraceID,
+		SpanID:    span.ID,
+		Type:      "span",
+		Data:      span,
+	})
+}
+
+// OnModelEvent 模型事件不单独记录, 已随跨度事件保存
+func (r *Recorder) OnModelEvent(event model.ModelEvent) error {
+	return nil
+}
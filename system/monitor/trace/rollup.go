@@ -0,0 +1,303 @@
+// system/monitor/trace/rollup.go
+
+package trace
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Corphon/daoflow/system/monitor/quantile"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 汇总保留的默认时间桶数
+const (
+	defaultHourlyRetention = 7 * 24 // 一周
+	defaultDailyRetention  = 90     // 三个月
+)
+
+// Granularity 汇总粒度
+type Granularity string
+
+const (
+	GranularityHour Granularity = "hour"
+	GranularityDay  Granularity = "day"
+)
+
+// 导出格式
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+)
+
+// Rollup 一个时间桶内追踪分析结果的汇总
+type Rollup struct {
+	Granularity     Granularity    `json:"granularity"`
+	Start           time.Time      `json:"start"`
+	End             time.Time      `json:"end"`
+	Traces          int            `json:"traces"`
+	AvgDuration     time.Duration  `json:"avg_duration"`
+	Anomalies       int            `json:"anomalies"`
+	AnomaliesByType map[string]int `json:"anomalies_by_type"`
+	AvgEntanglement float64        `json:"avg_entanglement"`
+	AvgCoherence    float64        `json:"avg_coherence"`
+	Bottlenecks     map[string]int `json:"bottlenecks"` // 按资源统计的瓶颈次数
+	Patterns        map[string]int `json:"patterns"`    // 按类型统计的模式出现次数
+
+	// 追踪耗时分位数及其草图(毫秒), 草图可跨时间桶合并
+	Latency       types.LatencyQuantiles `json:"latency"`
+	LatencySketch *quantile.Sketch       `json:"latency_sketch"`
+}
+
+// RollupQuery 汇总查询条件
+type RollupQuery struct {
+	Granularity Granularity // 为空时按小时
+	From        time.Time   // 时间桶起点不早于From, 零值不限
+	To          time.Time   // 时间桶起点早于To, 零值不限
+}
+
+// rollupBucket 时间桶的累计值
+type rollupBucket struct {
+	rollup          Rollup
+	durationSum     time.Duration
+	entanglementSum float64
+	coherenceSum    float64
+	latency         *quantile.Sketch
+}
+
+// rollupSeries 单一粒度的汇总序列, 由Analyzer的锁保护
+type rollupSeries struct {
+	granularity Granularity
+	retention   int
+	accuracy    float64                 // 耗时草图的相对误差
+	buckets     map[int64]*rollupBucket // 按时间桶起点的Unix秒索引
+}
+
+// rollups 小时和天粒度的汇总
+type rollups struct {
+	hourly *rollupSeries
+	daily  *rollupSeries
+}
+
+// newRollups 创建汇总
+func newRollups(config types.TraceRollupConfig) rollups {
+	if config.HourlyRetention <= 0 {
+		config.HourlyRetention = defaultHourlyRetention
+	}
+	if config.DailyRetention <= 0 {
+		config.DailyRetention = defaultDailyRetention
+	}
+	return rollups{
+		hourly: &rollupSeries{granularity: GranularityHour, retention: config.HourlyRetention, accuracy: config.SketchAccuracy, buckets: make(map[int64]*rollupBucket)},
+		daily:  &rollupSeries{granularity: GranularityDay, retention: config.DailyRetention, accuracy: config.SketchAccuracy, buckets: make(map[int64]*rollupBucket)},
+	}
+}
+
+// add 将分析结果计入两个粒度的时间桶
+func (r rollups) add(analysis *TraceAnalysis) {
+	r.hourly.add(analysis)
+	r.daily.add(analysis)
+}
+
+// series 获取粒度对应的汇总序列
+func (r rollups) series(granularity Granularity) *rollupSeries {
+	if granularity == GranularityDay {
+		return r.daily
+	}
+	return r.hourly
+}
+
+// bucketStart 时间所在时间桶的起点(UTC)
+func (s *rollupSeries) bucketStart(t time.Time) time.Time {
+	t = t.UTC()
+	if s.granularity == GranularityDay {
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	}
+	return t.Truncate(time.Hour)
+}
+
+// add 将分析结果计入时间桶, 超出保留数时移除最早的时间桶
+func (s *rollupSeries) add(analysis *TraceAnalysis) {
+	start := s.bucketStart(analysis.Timestamp)
+	bucket, ok := s.buckets[start.Unix()]
+	if !ok {
+		end := start.Add(time.Hour)
+		if s.granularity == GranularityDay {
+			end = start.AddDate(0, 0, 1)
+		}
+		bucket = &rollupBucket{rollup: Rollup{
+			Granularity:     s.granularity,
+			Start:           start,
+			End:             end,
+			AnomaliesByType: make(map[string]int),
+			Bottlenecks:     make(map[string]int),
+			Patterns:        make(map[string]int),
+		}, latency: quantile.New(s.accuracy)}
+		s.buckets[start.Unix()] = bucket
+		s.trim()
+	}
+
+	r := &bucket.rollup
+	r.Traces++
+	bucket.durationSum += analysis.Duration
+	bucket.latency.Add(durationMillis(analysis.Duration))
+	bucket.entanglementSum += analysis.QuantumAnalysis.Entanglement
+	bucket.coherenceSum += analysis.QuantumAnalysis.Coherence
+	for _, anomaly := range analysis.Anomalies {
+		r.Anomalies++
+		r.AnomaliesByType[anomaly.Type]++
+	}
+	for _, bottleneck := range analysis.Bottlenecks {
+		r.Bottlenecks[bottleneck.Resource]++
+	}
+	for _, pattern := range analysis.Patterns {
+		r.Patterns[pattern.Type]++
+	}
+}
+
+// trim 移除超出保留数的最早时间桶
+func (s *rollupSeries) trim() {
+	if len(s.buckets) <= s.retention {
+		return
+	}
+	keys := make([]int64, 0, len(s.buckets))
+	for key := range s.buckets {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i] < keys[j] })
+	for _, key := range keys[:len(keys)-s.retention] {
+		delete(s.buckets, key)
+	}
+}
+
+// query 按时间顺序获取满足条件的汇总
+func (s *rollupSeries) query(q RollupQuery) []Rollup {
+	result := make([]Rollup, 0)
+	for _, bucket := range s.buckets {
+		if s.inRange(bucket, q) {
+			result = append(result, bucket.snapshot())
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Start.Before(result[j].Start)
+	})
+	return result
+}
+
+// inRange 时间桶是否满足查询的时间范围
+func (s *rollupSeries) inRange(bucket *rollupBucket, q RollupQuery) bool {
+	start := bucket.rollup.Start
+	if !q.From.IsZero() && start.Before(s.bucketStart(q.From)) {
+		return false
+	}
+	return q.To.IsZero() || start.Before(q.To)
+}
+
+// snapshot 汇总的副本, 计算平均值
+func (b *rollupBucket) snapshot() Rollup {
+	r := b.rollup
+	r.AnomaliesByType = copyCounts(r.AnomaliesByType)
+	r.Bottlenecks = copyCounts(r.Bottlenecks)
+	r.Patterns = copyCounts(r.Patterns)
+	r.LatencySketch = b.latency.Clone()
+	r.Latency = latencyQuantiles(b.latency)
+	if r.Traces > 0 {
+		n := float64(r.Traces)
+		r.AvgDuration = b.durationSum / time.Duration(r.Traces)
+		r.AvgEntanglement = b.entanglementSum / n
+		r.AvgCoherence = b.coherenceSum / n
+	}
+	return r
+}
+
+// Rollups 查询追踪分析结果的时间桶汇总, 按时间顺序排列
+func (a *Analyzer) Rollups(q RollupQuery) []Rollup {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return a.rollups.series(q.Granularity).query(q)
+}
+
+// ExportRollups 以JSON数组或CSV导出满足条件的汇总
+// CSV中按类型或资源的计数格式化为按键排序的"键=次数"并以分号分隔
+func (a *Analyzer) ExportRollups(w io.Writer, format string, q RollupQuery) error {
+	rollups := a.Rollups(q)
+
+	switch format {
+	case FormatJSON, "":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(rollups); err != nil {
+			return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "failed to export rollups", err)
+		}
+		return nil
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{
+			"granularity", "start", "end", "traces", "avg_duration_ms", "anomalies",
+			"avg_entanglement", "avg_coherence", "anomalies_by_type", "bottlenecks", "patterns",
+			"p50_ms", "p90_ms", "p99_ms", "p999_ms",
+		})
+		for _, r := range rollups {
+			cw.Write([]string{
+				string(r.Granularity),
+				r.Start.Format(time.RFC3339),
+				r.End.Format(time.RFC3339),
+				strconv.Itoa(r.Traces),
+				formatMillis(r.AvgDuration),
+				strconv.Itoa(r.Anomalies),
+				strconv.FormatFloat(r.AvgEntanglement, 'f', 6, 64),
+				strconv.FormatFloat(r.AvgCoherence, 'f', 6, 64),
+				formatCounts(r.AnomaliesByType),
+				formatCounts(r.Bottlenecks),
+				formatCounts(r.Patterns),
+				formatMillis(r.Latency.P50),
+				formatMillis(r.Latency.P90),
+				formatMillis(r.Latency.P99),
+				formatMillis(r.Latency.P999),
+			})
+		}
+		cw.Flush()
+		if err := cw.Error(); err != nil {
+			return types.NewDomainError(types.DomainMonitor, types.ErrRuntime, "failed to export rollups", err)
+		}
+		return nil
+	default:
+		return types.NewDomainError(types.DomainMonitor, types.ErrValidation, "unsupported export format", nil).
+			WithContext("format", format)
+	}
+}
+
+// copyCounts 复制计数表
+func copyCounts(counts map[string]int) map[string]int {
+	result := make(map[string]int, len(counts))
+	for key, count := range counts {
+		result[key] = count
+	}
+	return result
+}
+
+// formatCounts 按键排序格式化计数表
+func formatCounts(counts map[string]int) string {
+	keys := make([]string, 0, len(counts))
+	for key := range counts {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, fmt.Sprintf("%s=%d", key, counts[key]))
+	}
+	return strings.Join(parts, ";")
+}
+
+// formatMillis 以毫秒格式化耗时
+func formatMillis(d time.Duration) string {
+	return strconv.FormatFloat(durationMillis(d), 'f', 3, 64)
+}
@@ -0,0 +1,337 @@
+// system/monitor/trace/sampler.go
+
+package trace
+
+import (
+	"hash/fnv"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 尾部采样默认参数
+const (
+	defaultDecisionWait = 5 * time.Second
+	defaultMaxTraces    = 10000
+)
+
+// Sampler 跨度采样器
+type Sampler interface {
+	// Name 采样器名称
+	Name() string
+	// Offer 提交一个已结束的跨度, 返回应保留的跨度
+	// 头部采样立即返回该跨度或空, 尾部采样可能返回此前缓冲的整条追踪
+	Offer(span *Span) []*Span
+	// Flush 对等待超时的缓冲追踪做出决策
+	Flush(now time.Time) []*Span
+}
+
+// SamplingStats 采样统计
+type SamplingStats struct {
+	Strategy string // 采样策略
+	Offered  int64  // 提交跨度数
+	Sampled  int64  // 保留跨度数
+	Dropped  int64  // 丢弃跨度数
+	Buffered int    // 等待决策的跨度数
+}
+
+// NewSamplerFromConfig 根据配置创建采样器
+func NewSamplerFromConfig(config types.TraceConfig) Sampler {
+	cfg := config.Sampling
+	rate := cfg.Rate
+	if cfg.Strategy == "" || rate == 0 {
+		rate = config.SampleRate
+	}
+
+	switch cfg.Strategy {
+	case types.SamplingRateLimited:
+		return NewRateLimitingSampler(cfg.MaxPerSecond)
+	case types.SamplingTail:
+		return NewTailSampler(TailSamplingPolicy{
+			DecisionWait:     cfg.DecisionWait,
+			MaxTraces:        cfg.MaxTraces,
+			LatencyThreshold: cfg.LatencyThreshold,
+			Fallback:         NewProbabilisticSampler(rate),
+		})
+	default:
+		return NewProbabilisticSampler(rate)
+	}
+}
+
+// ProbabilisticSampler 概率采样器
+// 以TraceID哈希决策, 同一追踪的跨度要么全部保留要么全部丢弃
+type ProbabilisticSampler struct {
+	rate float64
+}
+
+// NewProbabilisticSampler 创建概率采样器
+func NewProbabilisticSampler(rate float64) *ProbabilisticSampler {
+	return &ProbabilisticSampler{rate: math.Max(0, math.Min(1, rate))}
+}
+
+// Name 采样器名称
+func (ps *ProbabilisticSampler) Name() string {
+	return types.SamplingProbabilistic
+}
+
+// Offer 提交跨度
+func (ps *ProbabilisticSampler) Offer(span *Span) []*Span {
+	if ps.keep(span.TraceID) {
+		return []*Span{span}
+	}
+	return nil
+}
+
+// Flush 概率采样无缓冲
+func (ps *ProbabilisticSampler) Flush(now time.Time) []*Span {
+	return nil
+}
+
+// keep 判断追踪是否保留
+func (ps *ProbabilisticSampler) keep(traceID types.TraceID) bool {
+	if ps.rate <= 0 {
+		return false
+	}
+	if ps.rate >= 1 {
+		return true
+	}
+
+	h := fnv.New64a()
+	h.Write([]byte(traceID))
+
+	// 混合高位, 相近ID的FNV哈希高位分布不均
+	x := h.Sum64()
+	x ^= x >> 33
+	x *= 0xff51afd7ed558ccd
+	x ^= x >> 33
+	x *= 0xc4ceb9fe1a85ec53
+	x ^= x >> 33
+
+	return float64(x)/float64(math.MaxUint64) < ps.rate
+}
+
+// RateLimitingSampler 限流采样器, 令牌桶限制每秒采样的追踪数
+// 已采样追踪的后续跨度不再消耗令牌
+type RateLimitingSampler struct {
+	mu sync.Mutex
+
+	perSecond float64
+	tokens    float64
+	last      time.Time
+
+	// 追踪决策缓存
+	decisions map[types.TraceID]rateDecision
+}
+
+// rateDecision 限流决策
+type rateDecision struct {
+	keep bool
+	at   time.Time
+}
+
+// 限流决策缓存时长
+const rateDecisionTTL = time.Minute
+
+// NewRateLimitingSampler 创建限流采样器
+func NewRateLimitingSampler(perSecond float64) *RateLimitingSampler {
+	return &RateLimitingSampler{
+		perSecond: perSecond,
+		tokens:    perSecond,
+		last:      time.Now(),
+		decisions: make(map[types.TraceID]rateDecision),
+	}
+}
+
+// Name 采样器名称
+func (rs *RateLimitingSampler) Name() string {
+	return types.SamplingRateLimited
+}
+
+// Offer 提交跨度
+func (rs *RateLimitingSampler) Offer(span *Span) []*Span {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	now := time.Now()
+	decision, exists := rs.decisions[span.TraceID]
+	if !exists {
+		// 补充令牌
+		rs.tokens = math.Min(rs.perSecond, rs.tokens+now.Sub(rs.last).Seconds()*rs.perSecond)
+		rs.last = now
+
+		decision.keep = rs.tokens >= 1
+		if decision.keep {
+			rs.tokens--
+		}
+	}
+	decision.at = now
+	rs.decisions[span.TraceID] = decision
+
+	if decision.keep {
+		return []*Span{span}
+	}
+	return nil
+}
+
+// Flush 清理过期决策
+func (rs *RateLimitingSampler) Flush(now time.Time) []*Span {
+	rs.mu.Lock()
+	defer rs.mu.Unlock()
+
+	for id, decision := range rs.decisions {
+		if now.Sub(decision.at) > rateDecisionTTL {
+			delete(rs.decisions, id)
+		}
+	}
+	return nil
+}
+
+// TailSamplingPolicy 尾部采样策略
+type TailSamplingPolicy struct {
+	DecisionWait     time.Duration // 最后一个跨度到达后等待的时间
+	MaxTraces        int           // 最多缓冲的追踪数, 超出时提前决策最早的追踪
+	LatencyThreshold time.Duration // 任一跨度超过该耗时即保留, 0表示不按耗时保留
+	Fallback         Sampler       // 普通追踪的采样器, 为nil时全部丢弃
+}
+
+// pendingTrace 等待决策的追踪
+type pendingTrace struct {
+	spans []*Span
+	first time.Time
+	last  time.Time
+}
+
+// TailSampler 尾部采样器
+// 缓冲整条追踪, 根跨度结束或等待超时后决策, 含错误或异常的追踪始终保留
+type TailSampler struct {
+	mu sync.Mutex
+
+	policy TailSamplingPolicy
+	traces map[types.TraceID]*pendingTrace
+}
+
+// NewTailSampler 创建尾部采样器
+func NewTailSampler(policy TailSamplingPolicy) *TailSampler {
+	if policy.DecisionWait <= 0 {
+		policy.DecisionWait = defaultDecisionWait
+	}
+	if policy.MaxTraces <= 0 {
+		policy.MaxTraces = defaultMaxTraces
+	}
+	return &TailSampler{
+		policy: policy,
+		traces: make(map[types.TraceID]*pendingTrace),
+	}
+}
+
+// Name 采样器名称
+func (ts *TailSampler) Name() string {
+	return types.SamplingTail
+}
+
+// Offer 缓冲跨度, 根跨度结束时立即决策
+func (ts *TailSampler) Offer(span *Span) []*Span {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	now := time.Now()
+	var kept []*Span
+
+	pending, exists := ts.traces[span.TraceID]
+	if !exists {
+		// 缓冲已满时提前决策最早的追踪
+		if len(ts.traces) >= ts.policy.MaxTraces {
+			kept = ts.evictOldest()
+		}
+		pending = &pendingTrace{first: now}
+		ts.traces[span.TraceID] = pending
+	}
+
+	pending.spans = append(pending.spans, span)
+	pending.last = now
+
+	// 根跨度结束意味着追踪完成
+	if span.ParentID == "" {
+		delete(ts.traces, span.TraceID)
+		kept = append(kept, ts.decide(pending)...)
+	}
+	return kept
+}
+
+// Flush 对等待超时的追踪做出决策
+func (ts *TailSampler) Flush(now time.Time) []*Span {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	var kept []*Span
+	for id, pending := range ts.traces {
+		if now.Sub(pending.last) >= ts.policy.DecisionWait {
+			delete(ts.traces, id)
+			kept = append(kept, ts.decide(pending)...)
+		}
+	}
+	return kept
+}
+
+// Buffered 当前缓冲的跨度数
+func (ts *TailSampler) Buffered() int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	n := 0
+	for _, pending := range ts.traces {
+		n += len(pending.spans)
+	}
+	return n
+}
+
+// evictOldest 提前决策最早的追踪
+func (ts *TailSampler) evictOldest() []*Span {
+	var (
+		oldestID types.TraceID
+		oldest   *pendingTrace
+	)
+	for id, pending := range ts.traces {
+		if oldest == nil || pending.first.Before(oldest.first) {
+			oldestID, oldest = id, pending
+		}
+	}
+	if oldest == nil {
+		return nil
+	}
+	delete(ts.traces, oldestID)
+	return ts.decide(oldest)
+}
+
+// decide 决定是否保留整条追踪
+func (ts *TailSampler) decide(pending *pendingTrace) []*Span {
+	for _, span := range pending.spans {
+		if isInteresting(span, ts.policy.LatencyThreshold) {
+			return pending.spans
+		}
+	}
+
+	if ts.policy.Fallback == nil || len(pending.spans) == 0 {
+		return nil
+	}
+	if len(ts.policy.Fallback.Offer(pending.spans[0])) == 0 {
+		return nil
+	}
+	return pending.spans
+}
+
+// isInteresting 判断跨度是否包含错误、异常标记或超出耗时阈值
+func isInteresting(span *Span, latency time.Duration) bool {
+	if span.Status == types.SpanStatusError {
+		return true
+	}
+	if span.Tags["error"] == "true" || span.Tags["anomaly"] == "true" {
+		return true
+	}
+	if flag, ok := span.Fields["anomaly"].(bool); ok && flag {
+		return true
+	}
+	return latency > 0 && span.Duration >= latency
+}
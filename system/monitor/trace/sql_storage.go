@@ -0,0 +1,280 @@
+// system/monitor/trace/sql_storage.go
+
+package trace
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// SQLDialect SQL方言
+type SQLDialect string
+
+const (
+	DialectSQLite   SQLDialect = "sqlite"
+	DialectPostgres SQLDialect = "postgres"
+)
+
+// 默认表名
+const defaultSpanTable = "trace_spans"
+
+// validTableName 表名校验, 表名会直接拼接进语句
+var validTableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLStorage 基于database/sql的追踪存储
+// 驱动由调用方注册并打开连接, 时间以Unix纳秒整数存储以保证两种方言行为一致
+type SQLStorage struct {
+	db      *sql.DB
+	dialect SQLDialect
+	table   string
+}
+
+// NewSQLStorage 创建SQL存储并初始化表结构
+func NewSQLStorage(ctx context.Context, db *sql.DB, dialect SQLDialect, table string) (*SQLStorage, error) {
+	if db == nil {
+		return nil, types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "nil database", nil)
+	}
+	if dialect != DialectSQLite && dialect != DialectPostgres {
+		return nil, types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "unsupported sql dialect", nil).
+			WithContext("dialect", dialect)
+	}
+	if table == "" {
+		table = defaultSpanTable
+	}
+	if !validTableName.MatchString(table) {
+		return nil, types.NewDomainError(types.DomainMonitor, types.ErrInvalid, "invalid table name", nil).
+			WithContext("table", table)
+	}
+
+	s := &SQLStorage{
+		db:      db,
+		dialect: dialect,
+		table:   table,
+	}
+	if err := s.migrate(ctx); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// migrate 创建表和索引
+func (s *SQLStorage) migrate(ctx context.Context) error {
+	blob := "TEXT"
+	if s.dialect == DialectPostgres {
+		blob = "JSONB"
+	}
+
+	stmts := []string{
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id          TEXT PRIMARY KEY,
+	trace_id    TEXT NOT NULL,
+	span_id     TEXT NOT NULL,
+	record_type TEXT NOT NULL,
+	ts          BIGINT NOT NULL,
+	data_kind   TEXT NOT NULL,
+	data        %s,
+	metadata    %s
+)`, s.table, blob, blob),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_trace_id ON %s (trace_id)`, s.table, s.table),
+		fmt.Sprintf(`CREATE INDEX IF NOT EXISTS idx_%s_ts ON %s (ts)`, s.table, s.table),
+	}
+
+	for _, stmt := range stmts {
+		if _, err := s.db.ExecContext(ctx, stmt); err != nil {
+			return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to migrate trace table", err).
+				WithContext("table", s.table)
+		}
+	}
+	return nil
+}
+
+// Write 写入记录
+func (s *SQLStorage) Write(ctx context.Context, records []TraceRecord) error {
+	if len(records) == 0 {
+		return nil
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.PrepareContext(ctx, s.rebind(fmt.Sprintf(
+		`INSERT INTO %s (id, trace_id, span_id, record_type, ts, data_kind, data, metadata)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?) ON CONFLICT (id) DO NOTHING`, s.table)))
+	if err != nil {
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to prepare insert", err)
+	}
+	defer stmt.Close()
+
+	for _, record := range records {
+		kind, data, err := encodeRecordData(record)
+		if err != nil {
+			return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to encode trace record", err).
+				WithContext("record_id", record.ID)
+		}
+		metadata, err := json.Marshal(record.Metadata)
+		if err != nil {
+			return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to encode trace metadata", err).
+				WithContext("record_id", record.ID)
+		}
+
+		if _, err := stmt.ExecContext(ctx,
+			recordKey(record),
+			string(record.TraceID),
+			string(record.SpanID),
+			record.Type,
+			record.Timestamp.UnixNano(),
+			kind,
+			string(data),
+			string(metadata),
+		); err != nil {
+			return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to insert trace record", err).
+				WithContext("record_id", record.ID)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to commit trace records", err)
+	}
+	return nil
+}
+
+// QueryRange 查询时间范围内的记录
+func (s *SQLStorage) QueryRange(ctx context.Context, from, to time.Time, limit int) ([]TraceRecord, error) {
+	query := fmt.Sprintf(
+		`SELECT id, trace_id, span_id, record_type, ts, data_kind, data, metadata
+FROM %s WHERE ts >= ? AND ts < ? ORDER BY ts`, s.table)
+	args := []interface{}{from.UnixNano(), to.UnixNano()}
+	if limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, limit)
+	}
+	return s.query(ctx, query, args...)
+}
+
+// QueryTrace 查询指定追踪
+func (s *SQLStorage) QueryTrace(ctx context.Context, traceID types.TraceID) ([]TraceRecord, error) {
+	query := fmt.Sprintf(
+		`SELECT id, trace_id, span_id, record_type, ts, data_kind, data, metadata
+FROM %s WHERE trace_id = ? ORDER BY ts`, s.table)
+	return s.query(ctx, query, string(traceID))
+}
+
+// Purge 按保留策略清理
+func (s *SQLStorage) Purge(ctx context.Context, policy RetentionPolicy) (int64, error) {
+	var removed int64
+
+	if policy.MaxAge > 0 {
+		cutoff := time.Now().Add(-policy.MaxAge).UnixNano()
+		res, err := s.db.ExecContext(ctx, s.rebind(fmt.Sprintf(
+			`DELETE FROM %s WHERE ts < ?`, s.table)), cutoff)
+		if err != nil {
+			return removed, types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to purge expired traces", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+
+	if policy.MaxRecords > 0 {
+		// 保留最新的MaxRecords条, 删除早于第MaxRecords条的记录
+		res, err := s.db.ExecContext(ctx, s.rebind(fmt.Sprintf(
+			`DELETE FROM %s WHERE ts < (SELECT ts FROM %s ORDER BY ts DESC LIMIT 1 OFFSET ?)`,
+			s.table, s.table)), policy.MaxRecords-1)
+		if err != nil {
+			return removed, types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to purge excess traces", err)
+		}
+		n, _ := res.RowsAffected()
+		removed += n
+	}
+
+	return removed, nil
+}
+
+// Close 关闭数据库连接
+func (s *SQLStorage) Close() error {
+	return s.db.Close()
+}
+
+// query 执行查询并解析记录
+func (s *SQLStorage) query(ctx context.Context, query string, args ...interface{}) ([]TraceRecord, error) {
+	rows, err := s.db.QueryContext(ctx, s.rebind(query), args...)
+	if err != nil {
+		return nil, types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to query traces", err)
+	}
+	defer rows.Close()
+
+	records := make([]TraceRecord, 0)
+	for rows.Next() {
+		var (
+			record         TraceRecord
+			traceID        string
+			spanID         string
+			ts             int64
+			kind           string
+			data, metadata sql.NullString
+		)
+		if err := rows.Scan(&record.ID, &traceID, &spanID, &record.Type, &ts, &kind, &data, &metadata); err != nil {
+			return nil, types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to scan trace record", err)
+		}
+
+		record.TraceID = types.TraceID(traceID)
+		record.SpanID = types.SpanID(spanID)
+		record.Timestamp = time.Unix(0, ts)
+
+		if data.Valid {
+			if record.Data, err = decodeRecordData(kind, []byte(data.String)); err != nil {
+				return nil, types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to decode trace record", err).
+					WithContext("record_id", record.ID)
+			}
+		}
+		if metadata.Valid && metadata.String != "" {
+			if err := json.Unmarshal([]byte(metadata.String), &record.Metadata); err != nil {
+				return nil, types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to decode trace metadata", err).
+					WithContext("record_id", record.ID)
+			}
+		}
+
+		records = append(records, record)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, types.NewDomainError(types.DomainMonitor, types.ErrStorage, "failed to read traces", err)
+	}
+	return records, nil
+}
+
+// rebind 将?占位符转换为方言占位符
+func (s *SQLStorage) rebind(query string) string {
+	if s.dialect != DialectPostgres {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, ch := range query {
+		if ch == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+// recordKey 记录主键, 未设置ID时由追踪、跨度和时间生成
+func recordKey(record TraceRecord) string {
+	if record.ID != "" {
+		return record.ID
+	}
+	return fmt.Sprintf("%s:%s:%d", record.TraceID, record.SpanID, record.Timestamp.UnixNano())
+}
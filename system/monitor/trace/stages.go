@@ -0,0 +1,209 @@
+// system/monitor/trace/stages.go
+
+package trace
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 分析阶段
+const (
+	StageSystem  = "system"
+	StageModel   = "model"
+	StageQuantum = "quantum"
+	StageField   = "field"
+)
+
+// defaultStageTimeout 分析阶段的默认时间预算
+const defaultStageTimeout = 5 * time.Second
+
+// StageStats 分析阶段的耗时统计
+type StageStats struct {
+	Runs     int64         `json:"runs"`     // 按时完成的次数, 含返回错误
+	Timeouts int64         `json:"timeouts"` // 超出预算而跳过的次数
+	Failures int64         `json:"failures"` // 返回错误的次数
+	Total    time.Duration `json:"total"`    // 按时完成的累计耗时
+	Max      time.Duration `json:"max"`
+	Last     time.Duration `json:"last"`
+	Budget   time.Duration `json:"budget"`
+}
+
+// Avg 按时完成的平均耗时
+func (s StageStats) Avg() time.Duration {
+	if s.Runs == 0 {
+		return 0
+	}
+	return s.Total / time.Duration(s.Runs)
+}
+
+// analysisStage 分析阶段
+// 阶段在独立的分析结果副本上运行, 按时完成后由merge合并结果, 超时的阶段结果被丢弃
+type analysisStage struct {
+	name    string
+	timeout time.Duration                                // >0时覆盖配置的时间预算
+	prepare func(analysis *TraceAnalysis) *TraceAnalysis // 为空时使用只含标识的副本
+	run     func(analysis *TraceAnalysis, spans []*Span) error
+	merge   func(dst, src *TraceAnalysis)
+	custom  bool // 自定义阶段出错时记录错误并继续后续阶段
+}
+
+// stages 按执行顺序排列的分析阶段, 含已注册的自定义阶段
+func (a *Analyzer) stages() []analysisStage {
+	return a.withCustomStages([]analysisStage{
+		{name: StageSystem, run: a.analyzeSystemTrace, merge: func(dst, src *TraceAnalysis) {
+			dst.Patterns, dst.Bottlenecks, dst.Metrics = src.Patterns, src.Bottlenecks, src.Metrics
+			dst.Anomalies, dst.CriticalPath = src.Anomalies, src.CriticalPath
+			dst.SpanCount, dst.Duration, dst.Latency = src.SpanCount, src.Duration, src.Latency
+		}},
+		{name: StageModel, run: a.analyzeModelTrace, merge: func(dst, src *TraceAnalysis) {
+			dst.ModelAnalysis = src.ModelAnalysis
+		}},
+		{name: StageQuantum, run: a.analyzeQuantumTrace, merge: func(dst, src *TraceAnalysis) {
+			dst.QuantumAnalysis = src.QuantumAnalysis
+		}},
+		{name: StageField, run: a.analyzeFieldTrace, merge: func(dst, src *TraceAnalysis) {
+			dst.FieldAnalysis = src.FieldAnalysis
+		}},
+	})
+}
+
+// runStages 依次执行各分析阶段
+// 超出时间预算的阶段被跳过并记入SkippedStages, 上下文取消时返回上下文错误
+func (a *Analyzer) runStages(ctx context.Context, analysis *TraceAnalysis, spans []*Span) error {
+	for _, stage := range a.stages() {
+		budget := stage.timeout
+		if budget <= 0 {
+			budget = a.stageBudget(stage.name)
+		}
+		a.runHooks(false, stage.name, analysis, spans)
+		elapsed, timedOut, err := runStage(ctx, stage, analysis, spans, budget)
+		a.recordStage(stage.name, budget, elapsed, err, timedOut)
+		if timedOut && ctx.Err() == nil {
+			analysis.SkippedStages = append(analysis.SkippedStages, stage.name)
+		}
+		a.runHooks(true, stage.name, analysis, spans)
+
+		switch {
+		case timedOut && ctx.Err() != nil:
+			return ctx.Err()
+		case err != nil && stage.custom:
+			a.mu.Lock()
+			a.status.errors = append(a.status.errors, fmt.Errorf("%s analysis failed: %w", stage.name, err))
+			a.mu.Unlock()
+		case err != nil:
+			return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, fmt.Sprintf("%s analysis failed", stage.name), err)
+		}
+	}
+	return nil
+}
+
+// runStage 在时间预算内执行阶段, 返回耗时、是否超时和阶段错误
+func runStage(ctx context.Context, stage analysisStage, analysis *TraceAnalysis, spans []*Span, budget time.Duration) (time.Duration, bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, budget)
+	defer cancel()
+
+	var scratch *TraceAnalysis
+	if stage.prepare != nil {
+		scratch = stage.prepare(analysis)
+	} else {
+		scratch = &TraceAnalysis{
+			ID:         analysis.ID,
+			Timestamp:  analysis.Timestamp,
+			TraceID:    analysis.TraceID,
+			SpanCount:  analysis.SpanCount,
+			Attributes: analysis.Attributes,
+		}
+	}
+	start := time.Now()
+	done := make(chan error, 1)
+	go func() {
+		// 阶段在分析循环之外的协程中运行, 崩溃按阶段错误处理
+		defer func() {
+			if r := recover(); r != nil {
+				done <- fmt.Errorf("%s stage panic: %v", stage.name, r)
+			}
+		}()
+		done <- stage.run(scratch, spans)
+	}()
+
+	select {
+	case err := <-done:
+		if err == nil {
+			stage.merge(analysis, scratch)
+		}
+		return time.Since(start), false, err
+	case <-ctx.Done():
+		return time.Since(start), true, nil
+	}
+}
+
+// stageBudget 阶段的时间预算
+func (a *Analyzer) stageBudget(stage string) time.Duration {
+	if budget := a.config.StageTimeouts.Stages[stage]; budget > 0 {
+		return budget
+	}
+	if a.config.StageTimeouts.Default > 0 {
+		return a.config.StageTimeouts.Default
+	}
+	return defaultStageTimeout
+}
+
+// recordStage 记录阶段耗时
+func (a *Analyzer) recordStage(stage string, budget, elapsed time.Duration, err error, timedOut bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.stageStats == nil {
+		a.stageStats = make(map[string]*StageStats)
+	}
+	stats, ok := a.stageStats[stage]
+	if !ok {
+		stats = &StageStats{}
+		a.stageStats[stage] = stats
+	}
+	stats.Budget = budget
+	switch {
+	case timedOut:
+		stats.Timeouts++
+		return
+	case err != nil:
+		stats.Failures++
+	}
+	stats.Runs++
+	stats.Total += elapsed
+	stats.Last = elapsed
+	if elapsed > stats.Max {
+		stats.Max = elapsed
+	}
+}
+
+// StageStats 获取各分析阶段的耗时统计
+func (a *Analyzer) StageStats() map[string]StageStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	result := make(map[string]StageStats, len(a.stageStats))
+	for stage, stats := range a.stageStats {
+		result[stage] = *stats
+	}
+	return result
+}
+
+// StageMetrics 分析阶段耗时指标, 以毫秒计
+func (a *Analyzer) StageMetrics() map[string]float64 {
+	metrics := make(map[string]float64)
+	for stage, stats := range a.StageStats() {
+		metrics[stage+"_runs"] = float64(stats.Runs)
+		metrics[stage+"_timeouts"] = float64(stats.Timeouts)
+		metrics[stage+"_failures"] = float64(stats.Failures)
+		metrics[stage+"_avg_ms"] = float64(stats.Avg()) / float64(time.Millisecond)
+		metrics[stage+"_max_ms"] = float64(stats.Max) / float64(time.Millisecond)
+		metrics[stage+"_last_ms"] = float64(stats.Last) / float64(time.Millisecond)
+	}
+	return metrics
+}
@@ -0,0 +1,123 @@
+// system/monitor/trace/storage.go
+
+package trace
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// TraceStorage 追踪记录存储后端
+type TraceStorage interface {
+	// Write 写入一批记录, 重复ID的记录被忽略
+	Write(ctx context.Context, records []TraceRecord) error
+	// QueryRange 查询时间范围[from, to)内的记录, 按时间升序, limit<=0 表示不限
+	QueryRange(ctx context.Context, from, to time.Time, limit int) ([]TraceRecord, error)
+	// QueryTrace 查询指定追踪的全部记录
+	QueryTrace(ctx context.Context, traceID types.TraceID) ([]TraceRecord, error)
+	// Purge 按保留策略清理记录, 返回删除数量
+	Purge(ctx context.Context, policy RetentionPolicy) (int64, error)
+	// Close 关闭存储
+	Close() error
+}
+
+// RetentionPolicy 保留策略
+type RetentionPolicy struct {
+	MaxAge        time.Duration // 最长保留时间, 0表示不限
+	MaxRecords    int64         // 最多保留记录数, 0表示不限
+	CheckInterval time.Duration // 检查间隔
+}
+
+// 默认保留检查间隔
+const defaultRetentionInterval = 10 * time.Minute
+
+// 记录数据类型
+const (
+	spanDataKind  = "span"  // *Span
+	valueDataKind = "value" // 任意JSON值
+)
+
+// storedSpan 可持久化的跨度, 不包含运行时的流模型引用
+type storedSpan struct {
+	ID         types.SpanID           `json:"id"`
+	TraceID    types.TraceID          `json:"trace_id"`
+	ParentID   types.SpanID           `json:"parent_id,omitempty"`
+	Name       string                 `json:"name"`
+	StartTime  time.Time              `json:"start_time"`
+	EndTime    time.Time              `json:"end_time"`
+	Duration   time.Duration          `json:"duration"`
+	Status     types.SpanStatus       `json:"status"`
+	Tags       map[string]string      `json:"tags,omitempty"`
+	Events     []SpanEvent            `json:"events,omitempty"`
+	Metrics    map[string]float64     `json:"metrics,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	ModelType  model.ModelType        `json:"model_type,omitempty"`
+	ModelState *model.ModelState      `json:"model_state,omitempty"`
+}
+
+// encodeRecordData 序列化记录数据, 跨度使用可持久化形式
+func encodeRecordData(record TraceRecord) (string, []byte, error) {
+	if span, ok := recordSpan(record); ok {
+		data, err := json.Marshal(storedSpan{
+			ID:         span.ID,
+			TraceID:    span.TraceID,
+			ParentID:   span.ParentID,
+			Name:       span.Name,
+			StartTime:  span.StartTime,
+			EndTime:    span.EndTime,
+			Duration:   span.Duration,
+			Status:     span.Status,
+			Tags:       span.Tags,
+			Events:     span.Events,
+			Metrics:    span.Metrics,
+			Fields:     span.Fields,
+			ModelType:  span.ModelType,
+			ModelState: span.ModelState,
+		})
+		return spanDataKind, data, err
+	}
+	data, err := json.Marshal(record.Data)
+	return valueDataKind, data, err
+}
+
+// decodeRecordData 反序列化记录数据, span类型还原为*Span
+func decodeRecordData(kind string, data []byte) (interface{}, error) {
+	if kind != spanDataKind {
+		var value interface{}
+		if err := json.Unmarshal(data, &value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+
+	var stored storedSpan
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil, err
+	}
+	return &Span{
+		ID:         stored.ID,
+		TraceID:    stored.TraceID,
+		ParentID:   stored.ParentID,
+		Name:       stored.Name,
+		StartTime:  stored.StartTime,
+		EndTime:    stored.EndTime,
+		Duration:   stored.Duration,
+		Status:     stored.Status,
+		Tags:       stored.Tags,
+		Events:     stored.Events,
+		Metrics:    stored.Metrics,
+		Fields:     stored.Fields,
+		ModelType:  stored.ModelType,
+		ModelState: stored.ModelState,
+	}, nil
+}
+
+// recordSpan 获取记录中的跨度
+func recordSpan(record TraceRecord) (*Span, bool) {
+	span, ok := record.Data.(*Span)
+	return span, ok && span != nil
+}
@@ -0,0 +1,200 @@
+// system/monitor/trace/subscription.go
+
+package trace
+
+import (
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// defaultSubscriptionBuffer 订阅者待投递分析结果的默认缓冲大小
+const defaultSubscriptionBuffer = 64
+
+// AnalysisFilter 分析结果的订阅过滤条件, 零值接收全部结果
+type AnalysisFilter struct {
+	AnomaliesOnly bool              // 只接收含系统异常的结果
+	MinSeverity   float64           // 至少一个系统异常的严重程度不低于此值, >0时隐含AnomaliesOnly
+	Attributes    map[string]string // 追踪属性须全部匹配
+	Buffer        int               // 待投递缓冲大小, 缓冲满时丢弃新结果; <=0时使用默认值
+}
+
+// Match 分析结果是否满足过滤条件
+func (f AnalysisFilter) Match(analysis *TraceAnalysis) bool {
+	if (f.AnomaliesOnly || f.MinSeverity > 0) && len(analysis.Anomalies) == 0 {
+		return false
+	}
+	if f.MinSeverity > 0 {
+		severe := false
+		for _, anomaly := range analysis.Anomalies {
+			if anomaly.Severity >= f.MinSeverity {
+				severe = true
+				break
+			}
+		}
+		if !severe {
+			return false
+		}
+	}
+	for key, value := range f.Attributes {
+		if analysis.Attributes[key] != value {
+			return false
+		}
+	}
+	return true
+}
+
+// SubscriptionStats 订阅投递统计
+type SubscriptionStats struct {
+	ID        string `json:"id"`
+	Delivered int64  `json:"delivered"`
+	Dropped   int64  `json:"dropped"` // 缓冲满而丢弃的结果数
+	Failed    int64  `json:"failed"`  // 处理函数panic的次数
+	Pending   int    `json:"pending"`
+}
+
+// analysisSubscription 分析结果订阅, 每个订阅者在独立协程中按到达顺序接收结果
+type analysisSubscription struct {
+	id      string
+	filter  AnalysisFilter
+	handler func(*TraceAnalysis)
+	queue   chan *TraceAnalysis
+	once    sync.Once
+
+	delivered atomic.Int64
+	dropped   atomic.Int64
+	failed    atomic.Int64
+}
+
+// run 投递分析结果直到订阅取消
+func (s *analysisSubscription) run() {
+	for analysis := range s.queue {
+		s.deliver(analysis)
+	}
+}
+
+// deliver 调用处理函数, 处理函数panic不影响后续投递
+func (s *analysisSubscription) deliver(analysis *TraceAnalysis) {
+	defer func() {
+		if r := recover(); r != nil {
+			s.failed.Add(1)
+		}
+	}()
+	s.handler(analysis)
+	s.delivered.Add(1)
+}
+
+// offer 非阻塞地加入待投递队列
+func (s *analysisSubscription) offer(analysis *TraceAnalysis) {
+	select {
+	case s.queue <- analysis:
+	default:
+		s.dropped.Add(1)
+	}
+}
+
+// close 停止投递
+func (s *analysisSubscription) close() {
+	s.once.Do(func() { close(s.queue) })
+}
+
+// OnAnalysis 订阅分析结果, 返回订阅ID
+// 结果在每个追踪分析完成并缓存后异步投递, 可选的过滤条件只取第一个
+func (a *Analyzer) OnAnalysis(handler func(*TraceAnalysis), filter ...AnalysisFilter) (string, error) {
+	if handler == nil {
+		return "", types.NewDomainError(types.DomainMonitor, types.ErrValidation, "nil analysis handler", nil)
+	}
+
+	sub := &analysisSubscription{handler: handler}
+	if len(filter) > 0 {
+		sub.filter = filter[0]
+	}
+	buffer := sub.filter.Buffer
+	if buffer <= 0 {
+		buffer = defaultSubscriptionBuffer
+	}
+	sub.queue = make(chan *TraceAnalysis, buffer)
+
+	a.mu.Lock()
+	a.subscriptions.next++
+	sub.id = "analysis_sub_" + strconv.Itoa(a.subscriptions.next)
+	if a.subscriptions.list == nil {
+		a.subscriptions.list = make(map[string]*analysisSubscription)
+	}
+	a.subscriptions.list[sub.id] = sub
+	a.mu.Unlock()
+
+	go sub.run()
+	return sub.id, nil
+}
+
+// RemoveAnalysisHandler 取消分析结果订阅, 已在队列中的结果仍会投递
+func (a *Analyzer) RemoveAnalysisHandler(id string) bool {
+	a.mu.Lock()
+	sub, ok := a.subscriptions.list[id]
+	delete(a.subscriptions.list, id)
+	a.mu.Unlock()
+
+	if ok {
+		sub.close()
+	}
+	return ok
+}
+
+// SubscriptionStats 获取各订阅的投递统计
+func (a *Analyzer) SubscriptionStats() []SubscriptionStats {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	stats := make([]SubscriptionStats, 0, len(a.subscriptions.list))
+	for id, sub := range a.subscriptions.list {
+		stats = append(stats, SubscriptionStats{
+			ID:        id,
+			Delivered: sub.delivered.Load(),
+			Dropped:   sub.dropped.Load(),
+			Failed:    sub.failed.Load(),
+			Pending:   len(sub.queue),
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ID < stats[j].ID
+	})
+	return stats
+}
+
+// publishAnalysis 将分析结果加入匹配订阅的待投递队列
+// 在分析锁下入队, 保证取消订阅关闭队列后不再写入
+func (a *Analyzer) publishAnalysis(analysis *TraceAnalysis) {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	for _, sub := range a.subscriptions.list {
+		if sub.filter.Match(analysis) {
+			sub.offer(analysis)
+		}
+	}
+}
+
+// traceAttributes 合并跨度标签为追踪属性, 根跨度的标签覆盖其他跨度的同名标签
+func traceAttributes(spans []*Span) map[string]string {
+	attributes := make(map[string]string)
+	var root *Span
+	for _, span := range spans {
+		if span.ParentID == "" {
+			root = span
+			continue
+		}
+		for key, value := range span.Tags {
+			attributes[key] = value
+		}
+	}
+	if root != nil {
+		for key, value := range root.Tags {
+			attributes[key] = value
+		}
+	}
+	return attributes
+}
@@ -4,12 +4,12 @@ package trace
 
 import (
 	"context"
-	"fmt"
-	"math/rand/v2"
 	"sync"
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -82,6 +82,13 @@ type Tracker struct {
 
 	// 新增：模型状态管理器
 	modelManager *model.StateManager
+
+	// 采样器与采样统计
+	sampler  Sampler
+	sampling struct {
+		offered int64
+		sampled int64
+	}
 }
 
 // SpanSubscriber 跨度订阅者接口
@@ -98,6 +105,7 @@ func NewTracker(config types.TraceConfig) *Tracker {
 		activeSpans:  make(map[types.SpanID]*Span),
 		spanChan:     make(chan *Span, config.BufferSize),
 		modelManager: model.NewStateManager(model.ModelTypeNone, model.MaxSystemEnergy),
+		sampler:      NewSamplerFromConfig(config),
 	}
 
 	return t
@@ -108,12 +116,12 @@ func (t *Tracker) Start(ctx context.Context) error {
 	t.mu.Lock()
 	if t.status.isRunning {
 		t.mu.Unlock()
-		return model.WrapError(nil, model.ErrCodeOperation, "tracker already running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "tracker already running", nil)
 	}
 	t.status.isRunning = true
 	t.mu.Unlock()
 
-	go t.processLoop(ctx)
+	supervisor.Go(ctx, "monitor.trace.tracker", t.processLoop)
 	return nil
 }
 
@@ -123,7 +131,7 @@ func (t *Tracker) Stop() error {
 	defer t.mu.Unlock()
 
 	if !t.status.isRunning {
-		return model.WrapError(nil, model.ErrCodeOperation, "tracker not running")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "tracker not running", nil)
 	}
 
 	t.status.isRunning = false
@@ -167,7 +175,7 @@ func (t *Tracker) StartSpan(name string, opts ...SpanOption) *Span {
 // EndSpan 结束跨度
 func (t *Tracker) EndSpan(span *Span) error {
 	if span == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "nil span")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "nil span", nil)
 	}
 
 	span.EndTime = time.Now()
@@ -176,13 +184,13 @@ func (t *Tracker) EndSpan(span *Span) error {
 	// 更新模型状态
 	if span.ModelType != model.ModelTypeNone {
 		if err := t.updateModelState(span); err != nil {
-			return model.WrapError(err, model.ErrCodeOperation, "failed to update model state")
+			return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to update model state", err)
 		}
 	}
 
 	// 发送跨度
 	if err := t.sendSpan(span); err != nil {
-		return model.WrapError(err, model.ErrCodeOperation, "failed to send span")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation, "failed to send span", err)
 	}
 
 	// 移除活跃跨度
@@ -196,7 +204,7 @@ func (t *Tracker) EndSpan(span *Span) error {
 // AddEvent 添加事件到跨度
 func (t *Tracker) AddEvent(span *Span, name string, fields map[string]interface{}, modelData *model.FlowModel) error {
 	if span == nil {
-		return model.WrapError(nil, model.ErrCodeValidation, "nil span")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeValidation, "nil span", nil)
 	}
 
 	// 创建模型事件
@@ -236,8 +244,8 @@ func (t *Tracker) updateModelState(span *Span) error {
 
 	// 使用UpdateState替代UpdateModelState
 	if err := t.modelManager.UpdateState(); err != nil {
-		return model.WrapError(err, model.ErrCodeOperation,
-			"failed to update model state")
+		return types.NewDomainError(types.DomainMonitor, model.ErrCodeOperation,
+			"failed to update model state", err)
 	}
 
 	return nil
@@ -283,31 +291,75 @@ func (t *Tracker) processLoop(ctx context.Context) {
 
 // sendSpan 发送跨度
 func (t *Tracker) sendSpan(span *Span) error {
+	t.mu.RLock()
+	sampler := t.sampler
+	t.mu.RUnlock()
+
 	// 采样检查
-	if !t.shouldSample() {
-		return nil
-	}
+	kept := sampler.Offer(span)
 
-	select {
-	case t.spanChan <- span:
-		return nil
-	default:
-		return model.WrapError(nil, model.ErrCodeResource, "span buffer full")
+	t.mu.Lock()
+	t.sampling.offered++
+	t.mu.Unlock()
+
+	return t.enqueueSpans(kept)
+}
+
+// enqueueSpans 将采样保留的跨度送入处理通道
+func (t *Tracker) enqueueSpans(spans []*Span) error {
+	for _, span := range spans {
+		select {
+		case t.spanChan <- span:
+			t.mu.Lock()
+			t.sampling.sampled++
+			t.mu.Unlock()
+		default:
+			return types.NewDomainError(types.DomainMonitor, model.ErrCodeResource, "span buffer full", nil)
+		}
 	}
+	return nil
 }
 
-// shouldSample 检查是否需要采样
-func (t *Tracker) shouldSample() bool {
-	// 如果采样率为0或1,快速返回
-	if t.config.SampleRate <= 0 {
-		return false
+// SetSampler 设置采样器, 已缓冲在旧采样器中的追踪会先做出决策
+func (t *Tracker) SetSampler(sampler Sampler) {
+	if sampler == nil {
+		return
 	}
-	if t.config.SampleRate >= 1.0 {
-		return true
+
+	t.mu.Lock()
+	previous := t.sampler
+	t.sampler = sampler
+	t.mu.Unlock()
+
+	if previous != nil {
+		// 以足够远的时间强制决策全部缓冲
+		forced := time.Now().Add(100 * 365 * 24 * time.Hour)
+		if err := t.enqueueSpans(previous.Flush(forced)); err != nil {
+			t.recordError(err)
+		}
 	}
+}
 
-	// 生成随机数判断是否采样
-	return rand.Float64() < t.config.SampleRate
+// GetSamplingStats 获取采样统计
+func (t *Tracker) GetSamplingStats() SamplingStats {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	return t.samplingStats()
+}
+
+// samplingStats 计算采样统计, 调用方持有锁
+// 丢弃数包括采样拒绝和通道满时丢弃的跨度, 不含仍在等待决策的跨度
+func (t *Tracker) samplingStats() SamplingStats {
+	stats := SamplingStats{
+		Strategy: t.sampler.Name(),
+		Offered:  t.sampling.offered,
+		Sampled:  t.sampling.sampled,
+	}
+	if buffered, ok := t.sampler.(interface{ Buffered() int }); ok {
+		stats.Buffered = buffered.Buffered()
+	}
+	stats.Dropped = stats.Offered - stats.Sampled - int64(stats.Buffered)
+	return stats
 }
 
 // processSpan 处理跨度
@@ -327,19 +379,30 @@ func (t *Tracker) processSpan(span *Span) {
 // flush 刷新所有活跃跨度
 func (t *Tracker) flush() {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-
-	t.status.lastFlush = time.Now()
+	now := time.Now()
+	t.status.lastFlush = now
+	sampler := t.sampler
+
+	// 收集超时的活跃跨度
+	expired := make([]*Span, 0)
+	for _, span := range t.activeSpans {
+		if now.Sub(span.StartTime) > t.config.FlushInterval {
+			expired = append(expired, span)
+		}
+	}
+	t.mu.Unlock()
 
-	// 结束所有超时的活跃跨度
-	for id, span := range t.activeSpans {
-		if time.Since(span.StartTime) > t.config.FlushInterval {
-			if err := t.EndSpan(span); err != nil {
-				t.recordError(err)
-			}
-			delete(t.activeSpans, id)
+	// 结束超时跨度, EndSpan 自行加锁
+	for _, span := range expired {
+		if err := t.EndSpan(span); err != nil {
+			t.recordError(err)
 		}
 	}
+
+	// 对等待超时的采样缓冲做出决策
+	if err := t.enqueueSpans(sampler.Flush(now)); err != nil {
+		t.recordError(err)
+	}
 }
 
 // recordError 记录错误
@@ -355,16 +418,22 @@ func (t *Tracker) GetMetrics() map[string]interface{} {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
 
+	sampling := t.samplingStats()
 	return map[string]interface{}{
-		"active_traces":    len(t.activeTraces),
-		"completed_traces": t.stats.completedTraces,
-		"error_traces":     t.stats.errorTraces,
-		"avg_duration":     t.stats.avgDuration.String(),
-		"total_spans":      t.stats.totalSpans,
-		"spans_per_trace":  t.stats.spansPerTrace,
-		"buffer_capacity":  t.config.BufferSize,
-		"buffer_used":      len(t.traceBuffer),
-		"last_update":      t.stats.lastUpdate.Format(time.RFC3339),
+		"active_traces":     len(t.activeTraces),
+		"completed_traces":  t.stats.completedTraces,
+		"error_traces":      t.stats.errorTraces,
+		"avg_duration":      t.stats.avgDuration.String(),
+		"total_spans":       t.stats.totalSpans,
+		"spans_per_trace":   t.stats.spansPerTrace,
+		"buffer_capacity":   t.config.BufferSize,
+		"buffer_used":       len(t.traceBuffer),
+		"last_update":       t.stats.lastUpdate.Format(time.RFC3339),
+		"sampling_strategy": sampling.Strategy,
+		"offered_spans":     sampling.Offered,
+		"sampled_spans":     sampling.Sampled,
+		"dropped_spans":     sampling.Dropped,
+		"buffered_spans":    sampling.Buffered,
 	}
 }
 
@@ -394,5 +463,5 @@ func WithModelFlow(flow model.FlowModel) SpanOption {
 
 // generateID 生成唯一ID
 func generateID() string {
-	return fmt.Sprintf("span-%d", time.Now().UnixNano())
+	return core.NewID("span-")
 }
@@ -0,0 +1,42 @@
+// system/objectives.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// SetObjective 设置适应目标, 策略有效性、规则评分和学习反馈按目标达成度计算
+func (s *System) SetObjective(objective adaptation.Objective) error {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return types.NewSystemError(types.ErrNotFound, "adaptation strategy not available", nil)
+	}
+	return strategy.SetObjective(objective)
+}
+
+// RemoveObjective 移除适应目标
+func (s *System) RemoveObjective(name string) {
+	if strategy := s.evolution.GetStrategy(); strategy != nil {
+		strategy.RemoveObjective(name)
+	}
+}
+
+// Objectives 获取适应目标
+func (s *System) Objectives() []adaptation.Objective {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return nil
+	}
+	return strategy.GetObjectives()
+}
+
+// BanditStats 获取策略选择的多臂老虎机统计, 未启用时返回false
+func (s *System) BanditStats() (adaptation.BanditStats, bool) {
+	strategy := s.evolution.GetStrategy()
+	if strategy == nil {
+		return adaptation.BanditStats{}, false
+	}
+	return strategy.GetBanditStats()
+}
@@ -0,0 +1,95 @@
+// system/observer.go
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/observer"
+)
+
+// AttachObserver 附加只读观察者, 供仪表盘、分析旁路等外部组件读取指标流、状态快照和模式快照
+// 视图由系统按发布间隔采集并缓存, 观察者读取时不持有系统的锁; 快照请求按观察者限流
+func (s *System) AttachObserver(opts observer.Options) (*observer.Observer, error) {
+	return s.observers.Attach(opts)
+}
+
+// DetachObserver 移除观察者, 观察者不存在时返回false
+func (s *System) DetachObserver(id string) bool {
+	return s.observers.Detach(id)
+}
+
+// Observers 已附加观察者的统计
+func (s *System) Observers() []observer.Stats {
+	return s.observers.Observers()
+}
+
+// startObservers 周期发布观察者视图, 调用方需持有锁
+// 没有观察者时发布不读取任何数据, 因此总是注册
+func (s *System) startObservers() {
+	s.stopObservers()
+	s.registerJob(JobObserverPublish, "system", s.observers.Config().Interval, func(ctx context.Context) error {
+		s.observers.Publish(time.Now())
+		return nil
+	})
+}
+
+// stopObservers 停止发布观察者视图, 已附加的观察者保留, 调用方需持有锁
+func (s *System) stopObservers() {
+	s.common.GetScheduler().Unregister(JobObserverPublish)
+}
+
+// observerSource 观察者视图的数据来源
+type observerSource struct {
+	s *System
+}
+
+// MetricValues 与仪表盘相同的指标
+func (p observerSource) MetricValues() map[string]float64 {
+	return dashboardProvider{p.s}.MetricValues()
+}
+
+// State 系统状态, 模型状态的扩展属性已复制
+func (p observerSource) State() observer.StateSnapshot {
+	s := p.s
+
+	s.mu.RLock()
+	state := observer.StateSnapshot{
+		Uptime:     time.Since(s.state.startTime),
+		ErrorCount: len(s.state.errors),
+		Models:     make(map[string]model.ModelState),
+	}
+	for name, m := range s.subModels() {
+		state.Models[name] = observer.CloneModelState(m.GetState())
+	}
+	for name, m := range s.models {
+		if _, exists := state.Models[name]; !exists {
+			state.Models[name] = observer.CloneModelState(m.GetState())
+		}
+	}
+	s.mu.RUnlock()
+
+	// 核心引擎初始化前能量系统尚未创建
+	if s.GetEnergySystem() != nil {
+		state.Energy = s.GetEnergy()
+	}
+	state.Status = s.GetStatus()
+	state.Health = s.currentHealth()
+	state.Mode = s.Mode()
+	state.Draining = s.Draining()
+	state.Subsystems = s.GetSubsystemStatus()
+	return state
+}
+
+// Patterns 全局和各命名空间的活跃模式快照
+func (p observerSource) Patterns() []*emergence.PatternSnapshot {
+	detectors := p.s.patternDetectors()
+	snapshots := make([]*emergence.PatternSnapshot, 0, len(detectors))
+	for _, detector := range detectors {
+		snapshots = append(snapshots, detector.GetActivePatternsSnapshot())
+	}
+	return snapshots
+}
@@ -0,0 +1,254 @@
+// system/observer/hub.go
+
+package observer
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultInterval          = time.Second
+	defaultMaxObservers      = 16
+	defaultRequestsPerSecond = 5.0
+	defaultStreamBuffer      = 16
+)
+
+// views 全部只读视图
+var views = []string{types.ObserverViewMetrics, types.ObserverViewState, types.ObserverViewPatterns}
+
+// Source 视图数据来源, 由系统在发布时调用; 返回的数据不应与系统共享可变部分
+type Source interface {
+	MetricValues() map[string]float64
+	State() StateSnapshot
+	Patterns() []*emergence.PatternSnapshot
+}
+
+// MetricsSample 指标流中的一个样本, 每个观察者收到独立的副本
+type MetricsSample struct {
+	Timestamp time.Time          `json:"timestamp"`
+	Values    map[string]float64 `json:"values"`
+}
+
+// StateSnapshot 系统状态快照
+type StateSnapshot struct {
+	Taken      time.Time                   `json:"taken"`
+	Status     string                      `json:"status"`
+	Health     float64                     `json:"health"`
+	Energy     float64                     `json:"energy"`
+	Uptime     time.Duration               `json:"uptime"`
+	Mode       types.ModeStatus            `json:"mode"`
+	Draining   bool                        `json:"draining"`
+	ErrorCount int                         `json:"error_count"`
+	Subsystems map[string]string           `json:"subsystems"`
+	Models     map[string]model.ModelState `json:"models"`
+}
+
+// PatternView 全局和各命名空间的活跃模式快照, 快照本身不可变
+type PatternView struct {
+	Taken     time.Time                    `json:"taken"`
+	Snapshots []*emergence.PatternSnapshot `json:"-"`
+}
+
+// Patterns 全部活跃模式的副本
+func (v PatternView) Patterns() []emergence.EmergentPattern {
+	patterns := make([]emergence.EmergentPattern, 0)
+	for _, snapshot := range v.Snapshots {
+		patterns = append(patterns, snapshot.Patterns()...)
+	}
+	return patterns
+}
+
+// Hub 只读观察者的附加点
+// 系统按发布间隔调用Publish, 在发布时读取一次数据来源并缓存为不可变视图;
+// 观察者只读取缓存的视图, 不会持有或等待系统的锁, 也无法取得修改系统状态的引用
+type Hub struct {
+	mu sync.Mutex
+
+	// 基础配置
+	config types.ObserverConfig
+	source Source
+
+	// 已附加的观察者
+	observers map[string]*Observer
+
+	// 最近一次发布的视图
+	state    atomic.Pointer[StateSnapshot]
+	patterns atomic.Pointer[PatternView]
+}
+
+// NewHub 创建观察者附加点
+func NewHub(source Source, config types.ObserverConfig) *Hub {
+	if config.Interval <= 0 {
+		config.Interval = defaultInterval
+	}
+	if config.MaxObservers <= 0 {
+		config.MaxObservers = defaultMaxObservers
+	}
+	if config.RequestsPerSecond <= 0 {
+		config.RequestsPerSecond = defaultRequestsPerSecond
+	}
+	if config.StreamBuffer <= 0 {
+		config.StreamBuffer = defaultStreamBuffer
+	}
+	return &Hub{
+		config:    config,
+		source:    source,
+		observers: make(map[string]*Observer),
+	}
+}
+
+// Config 生效的配置
+func (h *Hub) Config() types.ObserverConfig {
+	return h.config
+}
+
+// Attach 附加观察者
+func (h *Hub) Attach(opts Options) (*Observer, error) {
+	allowed := make(map[string]bool)
+	for _, view := range opts.Views {
+		if !validView(view) {
+			return nil, types.NewSystemError(types.ErrInvalid, "unknown observer view", nil).
+				WithContext("view", view)
+		}
+		allowed[view] = true
+	}
+	if len(allowed) == 0 {
+		for _, view := range views {
+			allowed[view] = true
+		}
+	}
+	if opts.RequestsPerSecond < 0 || opts.StreamInterval < 0 || opts.StreamBuffer < 0 {
+		return nil, types.NewSystemError(types.ErrInvalid, "observer options must not be negative", nil).
+			WithContext("name", opts.Name)
+	}
+	if opts.RequestsPerSecond == 0 {
+		opts.RequestsPerSecond = h.config.RequestsPerSecond
+	}
+	if opts.StreamBuffer == 0 {
+		opts.StreamBuffer = h.config.StreamBuffer
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.observers) >= h.config.MaxObservers {
+		return nil, types.NewSystemError(types.ErrResource, "too many observers", nil).
+			WithContext("max", h.config.MaxObservers)
+	}
+
+	o := newObserver(h, core.NewID("obs_"), opts, allowed)
+	h.observers[o.id] = o
+	return o, nil
+}
+
+// Detach 移除观察者并关闭其指标流, 观察者不存在时返回false
+func (h *Hub) Detach(id string) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	o, exists := h.observers[id]
+	if !exists {
+		return false
+	}
+	delete(h.observers, id)
+	o.close()
+	return true
+}
+
+// DetachAll 移除全部观察者
+func (h *Hub) DetachAll() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for id, o := range h.observers {
+		delete(h.observers, id)
+		o.close()
+	}
+}
+
+// Observers 已附加观察者的统计, 按附加时间排序
+func (h *Hub) Observers() []Stats {
+	h.mu.Lock()
+	list := make([]*Observer, 0, len(h.observers))
+	for _, o := range h.observers {
+		list = append(list, o)
+	}
+	h.mu.Unlock()
+
+	stats := make([]Stats, 0, len(list))
+	for _, o := range list {
+		stats = append(stats, o.Stats())
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].AttachedAt.Before(stats[j].AttachedAt)
+	})
+	return stats
+}
+
+// Publish 读取数据来源并发布视图; 只读取已附加观察者需要的视图
+// 指标样本以非阻塞方式发送, 观察者来不及接收时丢弃并计数
+func (h *Hub) Publish(now time.Time) {
+	h.mu.Lock()
+	var needState, needPatterns bool
+	streams := make([]*Observer, 0)
+	for _, o := range h.observers {
+		needState = needState || o.views[types.ObserverViewState]
+		needPatterns = needPatterns || o.views[types.ObserverViewPatterns]
+		if o.stream != nil && o.streamDue(now) {
+			streams = append(streams, o)
+		}
+	}
+	h.mu.Unlock()
+
+	// 读取数据来源时不持有附加点的锁, 来源可能需要等待系统的锁
+	if needState {
+		state := h.source.State()
+		state.Taken = now
+		h.state.Store(&state)
+	}
+	if needPatterns {
+		h.patterns.Store(&PatternView{Taken: now, Snapshots: h.source.Patterns()})
+	}
+	if len(streams) == 0 {
+		return
+	}
+	values := h.source.MetricValues()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, o := range streams {
+		// 发布期间已移除的观察者其指标流已关闭
+		if _, attached := h.observers[o.id]; !attached {
+			continue
+		}
+		o.send(MetricsSample{Timestamp: now, Values: cloneValues(values)}, now)
+	}
+}
+
+// validView 是否为已知视图
+func validView(view string) bool {
+	for _, v := range views {
+		if v == view {
+			return true
+		}
+	}
+	return false
+}
+
+// cloneValues 复制指标值
+func cloneValues(values map[string]float64) map[string]float64 {
+	clone := make(map[string]float64, len(values))
+	for name, v := range values {
+		clone[name] = v
+	}
+	return clone
+}
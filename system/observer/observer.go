@@ -0,0 +1,233 @@
+// system/observer/observer.go
+
+package observer
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Options 观察者选项
+type Options struct {
+	Name              string        // 名称, 用于统计和排查
+	Views             []string      // 附加的视图, 为空时附加全部视图
+	RequestsPerSecond float64       // 每秒的快照请求上限, 为0时使用附加点的配置
+	StreamInterval    time.Duration // 指标样本的最小间隔, 为0时每次发布都发送
+	StreamBuffer      int           // 指标流的缓冲样本数, 为0时使用附加点的配置
+}
+
+// Stats 观察者统计
+type Stats struct {
+	ID         string    `json:"id"`
+	Name       string    `json:"name"`
+	Views      []string  `json:"views"`
+	AttachedAt time.Time `json:"attached_at"`
+	Requests   int64     `json:"requests"` // 获得的快照数
+	Limited    int64     `json:"limited"`  // 因限流被拒绝的快照请求数
+	Sent       int64     `json:"sent"`     // 发送的指标样本数
+	Dropped    int64     `json:"dropped"`  // 因来不及接收而丢弃的指标样本数
+	Detached   bool      `json:"detached"`
+}
+
+// Observer 附加到系统的只读观察者
+// 只能读取附加时选择的视图; 快照请求按令牌桶限流, 指标样本按最小间隔发送
+type Observer struct {
+	mu sync.Mutex
+
+	hub        *Hub
+	id         string
+	name       string
+	views      map[string]bool
+	attachedAt time.Time
+
+	// 快照请求限流
+	perSecond float64
+	tokens    float64
+	last      time.Time
+
+	// 指标流, 未附加指标视图时为nil
+	stream         chan MetricsSample
+	streamInterval time.Duration
+	lastSent       time.Time
+
+	stats    Stats
+	detached bool
+}
+
+// newObserver 创建观察者
+func newObserver(hub *Hub, id string, opts Options, views map[string]bool) *Observer {
+	now := time.Now()
+	o := &Observer{
+		hub:            hub,
+		id:             id,
+		name:           opts.Name,
+		views:          views,
+		attachedAt:     now,
+		perSecond:      opts.RequestsPerSecond,
+		tokens:         math.Max(1, opts.RequestsPerSecond),
+		last:           now,
+		streamInterval: opts.StreamInterval,
+	}
+	if views[types.ObserverViewMetrics] {
+		o.stream = make(chan MetricsSample, opts.StreamBuffer)
+	}
+	return o
+}
+
+// ID 观察者标识
+func (o *Observer) ID() string {
+	return o.id
+}
+
+// Metrics 指标流, 观察者移除后关闭; 未附加指标视图时为nil
+func (o *Observer) Metrics() <-chan MetricsSample {
+	return o.stream
+}
+
+// State 最近一次发布的系统状态快照
+func (o *Observer) State() (StateSnapshot, error) {
+	if err := o.request(types.ObserverViewState); err != nil {
+		return StateSnapshot{}, err
+	}
+	state := o.hub.state.Load()
+	if state == nil {
+		return StateSnapshot{}, errNotPublished(types.ObserverViewState)
+	}
+	return cloneState(*state), nil
+}
+
+// Patterns 最近一次发布的活跃模式快照
+func (o *Observer) Patterns() (PatternView, error) {
+	if err := o.request(types.ObserverViewPatterns); err != nil {
+		return PatternView{}, err
+	}
+	view := o.hub.patterns.Load()
+	if view == nil {
+		return PatternView{}, errNotPublished(types.ObserverViewPatterns)
+	}
+	return PatternView{Taken: view.Taken, Snapshots: append(view.Snapshots[:0:0], view.Snapshots...)}, nil
+}
+
+// Stats 观察者统计
+func (o *Observer) Stats() Stats {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	stats := o.stats
+	stats.ID = o.id
+	stats.Name = o.name
+	stats.AttachedAt = o.attachedAt
+	stats.Detached = o.detached
+	for view := range o.views {
+		stats.Views = append(stats.Views, view)
+	}
+	sort.Strings(stats.Views)
+	return stats
+}
+
+// Close 从系统移除观察者
+func (o *Observer) Close() {
+	o.hub.Detach(o.id)
+}
+
+// request 检查视图权限并消耗一个令牌
+func (o *Observer) request(view string) error {
+	if !o.views[view] {
+		return types.NewSystemError(types.ErrPermission, "view not attached", nil).
+			WithContext("observer", o.id).
+			WithContext("view", view)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if o.detached {
+		return types.NewSystemError(types.ErrState, "observer detached", nil).
+			WithContext("observer", o.id)
+	}
+
+	// 补充令牌, 突发上限为每秒请求数(至少1)
+	now := time.Now()
+	o.tokens = math.Min(math.Max(1, o.perSecond), o.tokens+now.Sub(o.last).Seconds()*o.perSecond)
+	o.last = now
+	if o.tokens < 1 {
+		o.stats.Limited++
+		return types.NewSystemError(types.ErrResource, "observer rate limit exceeded", nil).
+			WithContext("observer", o.id).
+			WithContext("requests_per_second", o.perSecond)
+	}
+	o.tokens--
+	o.stats.Requests++
+	return nil
+}
+
+// streamDue 是否到了发送下一个指标样本的时间, 调用方需持有附加点的锁
+func (o *Observer) streamDue(now time.Time) bool {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return now.Sub(o.lastSent) >= o.streamInterval
+}
+
+// send 非阻塞发送指标样本, 调用方需持有附加点的锁
+func (o *Observer) send(sample MetricsSample, now time.Time) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	select {
+	case o.stream <- sample:
+		o.stats.Sent++
+		o.lastSent = now
+	default:
+		o.stats.Dropped++
+	}
+}
+
+// close 标记移除并关闭指标流, 调用方需持有附加点的锁
+func (o *Observer) close() {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.detached = true
+	if o.stream != nil {
+		close(o.stream)
+	}
+}
+
+// errNotPublished 视图尚未发布
+func errNotPublished(view string) error {
+	return types.NewSystemError(types.ErrState, "view not published yet", nil).
+		WithContext("view", view)
+}
+
+// cloneState 复制状态快照中的map, 观察者之间互不影响
+func cloneState(state StateSnapshot) StateSnapshot {
+	subsystems := make(map[string]string, len(state.Subsystems))
+	for name, status := range state.Subsystems {
+		subsystems[name] = status
+	}
+	state.Subsystems = subsystems
+
+	models := make(map[string]model.ModelState, len(state.Models))
+	for name, ms := range state.Models {
+		models[name] = CloneModelState(ms)
+	}
+	state.Models = models
+	return state
+}
+
+// CloneModelState 复制模型状态的扩展属性
+func CloneModelState(state model.ModelState) model.ModelState {
+	if state.Properties != nil {
+		properties := make(map[string]interface{}, len(state.Properties))
+		for k, v := range state.Properties {
+			properties[k] = v
+		}
+		state.Properties = properties
+	}
+	return state
+}
@@ -0,0 +1,187 @@
+// system/onboarding.go
+
+package system
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// ModelStage 模型接入阶段
+type ModelStage string
+
+const (
+	ModelPending ModelStage = "pending" // 已注册, 等待系统启动
+	ModelSyncing ModelStage = "syncing" // 正在同步系统状态
+	ModelActive  ModelStage = "active"  // 参与转换和指标统计
+)
+
+// onboardPattern 接入时的同步转换模式
+const onboardPattern = model.PatternBalance
+
+// ModelOnboarding 模型接入记录
+type ModelOnboarding struct {
+	Name         string      `json:"name"`
+	Stage        ModelStage  `json:"stage"`
+	RegisteredAt time.Time   `json:"registered_at"`
+	ActivatedAt  time.Time   `json:"activated_at"`
+	SyncedEnergy float64     `json:"synced_energy"` // 同步后的模型能量
+	SyncedPhase  model.Phase `json:"synced_phase"`  // 同步后的模型相位
+}
+
+// ModelOnboardingStatus 获取模型接入状态
+func (s *System) ModelOnboardingStatus(name string) (ModelOnboarding, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if _, exists := s.models[name]; !exists {
+		return ModelOnboarding{}, types.ErrModelNotFound
+	}
+	if rec, exists := s.modelStages[name]; exists {
+		return *rec, nil
+	}
+	return ModelOnboarding{Name: name, Stage: ModelActive}, nil
+}
+
+// ActiveModels 获取已完成接入的模型名称, 按名称排序
+func (s *System) ActiveModels() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.activeModelNames()
+}
+
+// activeModelNames 已完成接入的模型名称, 调用方需持有锁
+// 未登记接入记录的模型为系统内置模型, 视为已接入
+func (s *System) activeModelNames() []string {
+	names := make([]string, 0, len(s.models))
+	for name := range s.models {
+		if rec, exists := s.modelStages[name]; !exists || rec.Stage == ModelActive {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// addModel 登记模型, 系统运行时立即接入, 调用方需持有锁
+func (s *System) addModel(name string, m model.Model) (*ModelOnboarding, error) {
+	rec := &ModelOnboarding{
+		Name:         name,
+		Stage:        ModelPending,
+		RegisteredAt: time.Now(),
+	}
+	if !s.isRunning {
+		s.models[name] = m
+		s.modelStages[name] = rec
+		return rec, nil
+	}
+
+	if err := s.onboardModel(name, m, rec); err != nil {
+		return nil, err
+	}
+	s.models[name] = m
+	s.modelStages[name] = rec
+	return rec, nil
+}
+
+// onboardModel 接入新模型, 调用方需持有锁
+// 启动模型, 下发当前系统状态, 再以平衡转换使其相位与系统一致;
+// 任一步失败时停止模型, 模型不会被登记
+func (s *System) onboardModel(name string, m model.Model, rec *ModelOnboarding) error {
+	rec.Stage = ModelSyncing
+	if err := m.Start(); err != nil {
+		return types.NewSystemError(types.ErrComponent, "failed to start model", err).
+			WithContext("model", name)
+	}
+
+	fail := func(step string, err error) error {
+		stopErr := m.Stop()
+		sysErr := types.NewSystemError(types.ErrState, "failed to onboard model", err).
+			WithContext("model", name).
+			WithContext("step", step)
+		if stopErr != nil {
+			sysErr.WithContext("stop_error", stopErr.Error())
+		}
+		return sysErr
+	}
+
+	// 下发系统状态
+	state := s.getCurrentState()
+	if err := s.syncModelState(m, *state); err != nil {
+		return fail("sync_state", err)
+	}
+
+	// 同步转换, 失败时回滚
+	tx, err := model.PrepareTransform(m, onboardPattern)
+	if err != nil {
+		return fail("prepare", err)
+	}
+	if err := tx.Commit(); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			err = types.NewSystemError(types.ErrState, "failed to roll back sync transform", rbErr).
+				WithContext("commit_error", err.Error())
+		}
+		return fail("transform", err)
+	}
+
+	synced := m.GetState()
+	rec.Stage = ModelActive
+	rec.ActivatedAt = time.Now()
+	rec.SyncedEnergy = synced.Energy
+	rec.SyncedPhase = synced.Phase
+	return nil
+}
+
+// syncModelState 下发系统状态, 调用方需持有锁
+// 模型实现了 model.Synchronizable 时由其自行同步,
+// 否则将模型能量对齐到已接入模型的平均能量
+func (s *System) syncModelState(m model.Model, state model.SystemState) error {
+	if sm, ok := m.(model.Synchronizable); ok {
+		return sm.SyncSystemState(state)
+	}
+
+	active := s.activeModelNames()
+	if len(active) == 0 {
+		return nil
+	}
+	total := 0.0
+	for _, name := range active {
+		total += s.models[name].GetState().Energy
+	}
+	return m.SetEnergy(total / float64(len(active)))
+}
+
+// activatePendingModels 系统启动后将等待中的模型标记为已接入, 调用方需持有锁
+// 随系统一同启动的模型从相同初始状态开始, 无需同步
+func (s *System) activatePendingModels() {
+	now := time.Now()
+	for name, rec := range s.modelStages {
+		if rec.Stage != ModelPending {
+			continue
+		}
+		state := s.models[name].GetState()
+		rec.Stage = ModelActive
+		rec.ActivatedAt = now
+		rec.SyncedEnergy = state.Energy
+		rec.SyncedPhase = state.Phase
+	}
+}
+
+// emitModelSync 发送模型接入事件, 不可持锁调用
+func (s *System) emitModelSync(recs ...*ModelOnboarding) {
+	for _, rec := range recs {
+		if rec.Stage != ModelActive {
+			continue
+		}
+		s.HandleEvent(types.SystemEvent{
+			Type:      types.EventModelSync,
+			Source:    rec.Name,
+			Timestamp: rec.ActivatedAt,
+			Message:   "model onboarded",
+			Data:      *rec,
+		})
+	}
+}
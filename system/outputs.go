@@ -0,0 +1,75 @@
+// system/outputs.go
+
+package system
+
+import (
+	"fmt"
+
+	"github.com/Corphon/daoflow/system/common/scheduler"
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/integrations"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/meta/resonance"
+	"github.com/Corphon/daoflow/system/monitor/trace"
+)
+
+// outputHooks 已接入输出的组件, 避免组件未重建时重复注册监听器
+type outputHooks struct {
+	detector *emergence.PatternDetector
+	strategy *adaptation.AdaptationStrategy
+	analyzer *trace.Analyzer
+
+	// 已接入异常关联的检测器
+	correlated *emergence.PatternDetector
+
+	// 已接入能量转移记录的检测器
+	energy *emergence.PatternDetector
+
+	// 已接入耦合阈值事件的统一场
+	coupled *field.UnifiedField
+
+	// 已接入扰动事件的统一场
+	perturbed *field.UnifiedField
+
+	// 已接入因果追踪的检测器、匹配器和策略
+	traced         *emergence.PatternDetector
+	tracedMatcher  *resonance.PatternMatcher
+	tracedStrategy *adaptation.AdaptationStrategy
+
+	// 已接入失败事件的定时任务调度器
+	scheduled *scheduler.Scheduler
+}
+
+// Outputs 返回外部输出分发器, 可在启动前注册自定义输出端
+func (s *System) Outputs() *integrations.Dispatcher {
+	return s.outputs
+}
+
+// startOutputs 接入模式、决策和异常来源并启动分发
+func (s *System) startOutputs() error {
+	if detector := s.meta.GetDetector(); detector != nil && detector != s.hooks.detector {
+		detector.OnNewPatterns(s.outputs.PublishPatterns)
+		s.hooks.detector = detector
+	}
+
+	if strategy := s.evolution.GetStrategy(); strategy != nil && strategy != s.hooks.strategy {
+		strategy.OnDecision(s.outputs.PublishDecision)
+		s.hooks.strategy = strategy
+	}
+
+	if analyzer := s.monitor.GetTraceAnalyzer(); analyzer != nil && analyzer != s.hooks.analyzer {
+		analyzer.OnAnomaly(s.outputs.PublishAnomaly)
+		s.hooks.analyzer = analyzer
+	}
+
+	return s.outputs.Start(s.ctx)
+}
+
+// recordOutputError 记录投递错误, 在分发协程中调用
+func (s *System) recordOutputError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.appendErrorLocked(fmt.Errorf("output delivery: %w", err))
+}
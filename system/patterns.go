@@ -0,0 +1,184 @@
+// system/patterns.go
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/Corphon/daoflow/system/evolution/causal"
+	"github.com/Corphon/daoflow/system/evolution/pattern"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// PatternCorrelations 计算活跃模式强度或能量序列的互相关矩阵及领先/滞后关系
+func (s *System) PatternCorrelations(opts emergence.CorrelationOptions) (emergence.CorrelationMatrix, error) {
+	detector := s.meta.GetDetector()
+	if detector == nil {
+		return emergence.CorrelationMatrix{}, types.NewSystemError(types.ErrNotFound, "pattern detector not available", nil)
+	}
+	return detector.CorrelationMatrix(opts), nil
+}
+
+// ExportPatterns 分页导出全局和各命名空间的活跃模式
+func (s *System) ExportPatterns(q emergence.ExportQuery) ([]emergence.PatternRecord, string, error) {
+	return emergence.ExportPatterns(s.patternDetectors(), q)
+}
+
+// ExportEvolution 分页导出全局和各命名空间活跃模式的演化历史
+func (s *System) ExportEvolution(q emergence.ExportQuery) ([]emergence.EvolutionRecord, string, error) {
+	return emergence.ExportEvolution(s.patternDetectors(), q)
+}
+
+// Backfill 以记录的场状态快照离线重新执行模式检测, 结果写入新建的命名空间ns
+func (s *System) Backfill(ctx context.Context, ns types.Namespace, source emergence.SnapshotSource, opts emergence.BackfillOptions) (*emergence.BackfillResult, error) {
+	return s.meta.Backfill(ctx, ns, source, opts)
+}
+
+// TuneThresholds 以记录的场状态快照回放调优命名空间ns的检测阈值, 返回推荐阈值和权衡曲线报告
+func (s *System) TuneThresholds(ctx context.Context, ns types.Namespace, source emergence.SnapshotSource, opts emergence.TuningOptions) (*emergence.TuningReport, error) {
+	return s.meta.TuneThresholds(ctx, ns, source, opts)
+}
+
+// SetPatternPriority 设置模式处理优先级, 决定新模式通知、共振匹配和共振放大各周期的处理顺序和配额
+func (s *System) SetPatternPriority(cfg types.PriorityConfig) error {
+	return s.meta.SetPriority(cfg)
+}
+
+// PatternPriorityStats 各处理阶段按优先级类别的处理、推迟和提前处理统计, 未启用时返回nil
+func (s *System) PatternPriorityStats() map[string][]emergence.PriorityStats {
+	return s.meta.PriorityStats()
+}
+
+// PinPattern 固定命名空间ns中的活跃模式, 固定的模式不因超时、强度或稳定性衰减而移除
+func (s *System) PinPattern(ns types.Namespace, id string, provenance emergence.Provenance) error {
+	detector, err := s.namespaceDetector(ns)
+	if err != nil {
+		return err
+	}
+	return detector.Pin(id, provenance)
+}
+
+// UnpinPattern 取消固定命名空间ns中的模式
+func (s *System) UnpinPattern(ns types.Namespace, id string) error {
+	detector, err := s.namespaceDetector(ns)
+	if err != nil {
+		return err
+	}
+	return detector.Unpin(id)
+}
+
+// OverridePattern 人工覆盖命名空间ns中模式的字段或属性, 计算值与覆盖值分开保存
+func (s *System) OverridePattern(ns types.Namespace, id, name string, value float64, provenance emergence.Provenance) error {
+	detector, err := s.namespaceDetector(ns)
+	if err != nil {
+		return err
+	}
+	return detector.SetOverride(id, name, value, provenance)
+}
+
+// ClearPatternOverride 移除命名空间ns中模式的人工覆盖, name为空时移除全部覆盖
+func (s *System) ClearPatternOverride(ns types.Namespace, id, name string) error {
+	detector, err := s.namespaceDetector(ns)
+	if err != nil {
+		return err
+	}
+	return detector.ClearOverride(id, name)
+}
+
+// namespaceDetector 命名空间的模式检测器, 默认命名空间为主检测器
+func (s *System) namespaceDetector(ns types.Namespace) (*emergence.PatternDetector, error) {
+	detector := s.meta.GetNamespaceDetector(ns)
+	if detector == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "namespace not found", nil).
+			WithContext("namespace", ns)
+	}
+	return detector, nil
+}
+
+// patternDetectors 全局和各命名空间的模式检测器
+func (s *System) patternDetectors() []*emergence.PatternDetector {
+	detectors := make([]*emergence.PatternDetector, 0)
+	if detector := s.meta.GetDetector(); detector != nil {
+		detectors = append(detectors, detector)
+	}
+	for _, ns := range s.meta.ListNamespaces() {
+		if detector := s.meta.GetNamespaceDetector(ns); detector != nil {
+			detectors = append(detectors, detector)
+		}
+	}
+	return detectors
+}
+
+// startCausalDiscovery 为因果发现器接入活跃模式来源
+func (s *System) startCausalDiscovery() {
+	discoverer := s.evolution.GetCausalDiscoverer()
+	detector := s.meta.GetDetector()
+	if discoverer == nil || detector == nil {
+		return
+	}
+	discoverer.SetPatternSource(detector.GetActivePatterns)
+}
+
+// CausalGraph 获取模式和适应动作间的有向影响图
+// refresh为true时立即按当前窗口重新发现, 否则返回最近一次周期发现的结果(可能为nil)
+func (s *System) CausalGraph(refresh bool) *causal.Graph {
+	discoverer := s.evolution.GetCausalDiscoverer()
+	if refresh {
+		return discoverer.Refresh(time.Now())
+	}
+	return discoverer.Latest()
+}
+
+// PatternFamilies 获取已识别模式的族统计, 按成员数降序排列; 未启用聚类时为nil
+func (s *System) PatternFamilies() []pattern.FamilyStats {
+	recognizer, err := s.recognizer()
+	if err != nil {
+		return nil
+	}
+	return recognizer.Families()
+}
+
+// SetSimilarityMetric 设置模式识别比较签名时维度的相似度度量, nil恢复为默认的规则度量
+func (s *System) SetSimilarityMetric(dimension string, metric pattern.SimilarityMetric) error {
+	recognizer, err := s.recognizer()
+	if err != nil {
+		return err
+	}
+	return recognizer.SetSimilarityMetric(dimension, metric)
+}
+
+// ExplainPatternSimilarity 分解两个已识别模式的签名相似度
+func (s *System) ExplainPatternSimilarity(id1, id2 string) (pattern.SimilarityExplanation, error) {
+	recognizer, err := s.recognizer()
+	if err != nil {
+		return pattern.SimilarityExplanation{}, err
+	}
+	return recognizer.ExplainSimilarity(id1, id2)
+}
+
+// RegisterEnvironmentProvider 为演化匹配注册外部环境来源
+func (s *System) RegisterEnvironmentProvider(provider pattern.EnvironmentProvider, opts pattern.ProviderOptions) error {
+	matcher := s.evolution.GetMatcher()
+	if matcher == nil {
+		return types.NewSystemError(types.ErrNotFound, "evolution matcher not available", nil)
+	}
+	return matcher.RegisterEnvironmentProvider(provider, opts)
+}
+
+// UnregisterEnvironmentProvider 移除外部环境来源
+func (s *System) UnregisterEnvironmentProvider(name string) {
+	if matcher := s.evolution.GetMatcher(); matcher != nil {
+		matcher.UnregisterEnvironmentProvider(name)
+	}
+}
+
+// EnvironmentProviders 获取外部环境来源的状态
+func (s *System) EnvironmentProviders() []pattern.ProviderStatus {
+	matcher := s.evolution.GetMatcher()
+	if matcher == nil {
+		return nil
+	}
+	return matcher.GetEnvironmentProviders()
+}
@@ -0,0 +1,110 @@
+// system/perturbation.go
+
+package system
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/monitor/correlation"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// perturbationEvents 扰动状态对应的系统事件
+var perturbationEvents = map[string]types.EventType{
+	field.PerturbationScheduled: types.EventPerturbationScheduled,
+	field.PerturbationActive:    types.EventPerturbationApplied,
+	field.PerturbationReverted:  types.EventPerturbationReverted,
+	field.PerturbationCancelled: types.EventPerturbationCancelled,
+}
+
+// startPerturbationEvents 将统一场的扰动状态变化接入系统事件, 施加的扰动同时作为异常关联的候选原因
+func (s *System) startPerturbationEvents() {
+	f := s.meta.GetField()
+	if f == nil || f == s.hooks.perturbed {
+		return
+	}
+	f.OnPerturbation(func(p field.Perturbation) {
+		timestamp := p.StartAt
+		switch p.State {
+		case field.PerturbationActive:
+			timestamp = p.AppliedAt
+		case field.PerturbationReverted, field.PerturbationCancelled:
+			timestamp = p.EndedAt
+		}
+
+		s.HandleEvent(types.SystemEvent{
+			Type:      perturbationEvents[p.State],
+			Source:    "meta.field",
+			Timestamp: timestamp,
+			Message:   fmt.Sprintf("perturbation %s %s", p.ID, p.State),
+			Priority:  types.PriorityNormal,
+			Data:      p,
+		})
+
+		if p.State != field.PerturbationActive {
+			return
+		}
+		if engine := s.monitor.GetCorrelationEngine(); engine != nil {
+			engine.Observe(correlation.Event{
+				Kind:      correlation.EventPerturbation,
+				Source:    "meta.field",
+				Reference: p.ID,
+				Category:  perturbationCategory(p.Spec),
+				Timestamp: p.AppliedAt,
+			})
+		}
+	})
+	s.hooks.perturbed = f
+}
+
+// perturbationCategory 扰动的关联类别: 实验标签, 未设置时按扰动内容
+func perturbationCategory(spec field.PerturbationSpec) string {
+	switch {
+	case spec.Label != "":
+		return spec.Label
+	case spec.DeltaEnergy != 0 && spec.PhaseShift != 0:
+		return "energy_phase"
+	case spec.PhaseShift != 0:
+		return "phase"
+	default:
+		return "energy"
+	}
+}
+
+// PerturbField 立即在统一场的区域内注入能量扰动, duration后撤销
+func (s *System) PerturbField(region field.Region, deltaEnergy float64, duration time.Duration) (field.Perturbation, error) {
+	f := s.meta.GetField()
+	if f == nil {
+		return field.Perturbation{}, types.NewSystemError(types.ErrState, "unified field not available", nil)
+	}
+	return f.PerturbField(region, deltaEnergy, duration)
+}
+
+// SchedulePerturbation 计划在at时刻对统一场施加能量或相位扰动
+func (s *System) SchedulePerturbation(at time.Time, spec field.PerturbationSpec) (field.Perturbation, error) {
+	f := s.meta.GetField()
+	if f == nil {
+		return field.Perturbation{}, types.NewSystemError(types.ErrState, "unified field not available", nil)
+	}
+	return f.SchedulePerturbation(at, spec)
+}
+
+// CancelPerturbation 取消统一场的扰动, 已施加的扰动立即撤销
+func (s *System) CancelPerturbation(id string) error {
+	f := s.meta.GetField()
+	if f == nil {
+		return types.NewSystemError(types.ErrState, "unified field not available", nil)
+	}
+	return f.CancelPerturbation(id)
+}
+
+// Perturbations 获取统一场的扰动记录
+func (s *System) Perturbations() ([]field.Perturbation, error) {
+	f := s.meta.GetField()
+	if f == nil {
+		return nil, types.NewSystemError(types.ErrState, "unified field not available", nil)
+	}
+	return f.Perturbations(), nil
+}
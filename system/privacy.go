@@ -0,0 +1,40 @@
+// system/privacy.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// SharedLearning 用于跨实例、跨租户共享的学习数据及其隐私保证说明
+type SharedLearning struct {
+	Snapshot  *adaptation.LearningSnapshot `json:"snapshot"`
+	Guarantee string                       `json:"guarantee"` // 见adaptation.Privacy
+}
+
+// ExportSharedLearning 导出共享学习统计和知识, 启用隐私保护时每次导出消耗ε预算
+func (s *System) ExportSharedLearning() (*SharedLearning, error) {
+	learning := s.evolution.GetLearning()
+	if learning == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "adaptive learning not available", nil)
+	}
+	snapshot, err := learning.ExportShared()
+	if err != nil {
+		return nil, err
+	}
+	return &SharedLearning{
+		Snapshot:  snapshot,
+		Guarantee: learning.GetPrivacy().Guarantee(),
+	}, nil
+}
+
+// SetLearningPrivacy 替换共享学习数据的隐私保护配置, 已消耗的隐私预算沿用到新配置
+func (s *System) SetLearningPrivacy(cfg types.PrivacyConfig) error {
+	privacy, err := adaptation.NewPrivacy(cfg)
+	if err != nil {
+		return err
+	}
+	s.evolution.SetLearningPrivacy(privacy)
+	return nil
+}
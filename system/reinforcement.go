@@ -0,0 +1,35 @@
+// system/reinforcement.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// SetReinforcementPolicy 设置适应学习的强化学习价值模型, 未启用强化学习时按默认配置启用
+func (s *System) SetReinforcementPolicy(policy adaptation.ReinforcementPolicy) error {
+	learning := s.evolution.GetLearning()
+	if learning == nil {
+		return types.NewSystemError(types.ErrNotFound, "adaptive learning not available", nil)
+	}
+	return learning.SetReinforcementPolicy(policy)
+}
+
+// ReinforcementStats 获取强化学习统计, 未启用时返回false
+func (s *System) ReinforcementStats() (adaptation.ReinforcementStats, bool) {
+	learning := s.evolution.GetLearning()
+	if learning == nil {
+		return adaptation.ReinforcementStats{}, false
+	}
+	return learning.GetReinforcementStats()
+}
+
+// ExperienceRetentionStats 获取适应学习经验缓冲区的保留统计, 学习组件不可用时返回false
+func (s *System) ExperienceRetentionStats() (adaptation.ExperienceRetentionStats, bool) {
+	learning := s.evolution.GetLearning()
+	if learning == nil {
+		return adaptation.ExperienceRetentionStats{}, false
+	}
+	return learning.ExperienceRetentionStats(), true
+}
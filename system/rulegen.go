@@ -0,0 +1,16 @@
+// system/rulegen.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+)
+
+// RuleGenerationStats 获取学习周期生成规则的统计, 包括因重复、配额和冷却被丢弃的规则数
+func (s *System) RuleGenerationStats() (adaptation.RuleGenerationStats, bool) {
+	learning := s.evolution.GetLearning()
+	if learning == nil {
+		return adaptation.RuleGenerationStats{}, false
+	}
+	return learning.GetRuleGenerationStats(), true
+}
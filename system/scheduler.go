@@ -0,0 +1,81 @@
+// system/scheduler.go
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Corphon/daoflow/system/common/scheduler"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 系统声明的定时任务
+const (
+	JobMemoryLimits    = "system.memory_limits" // 缓存软上限检查
+	JobDriftSample     = "monitor.drift"        // 漂移检测采样
+	JobObserverPublish = "system.observers"     // 发布观察者视图
+)
+
+// startScheduler 将定时任务的失败接入系统事件
+func (s *System) startScheduler() {
+	if sched := s.common.GetScheduler(); sched != s.hooks.scheduled {
+		sched.OnResult(s.onJobResult)
+		s.hooks.scheduled = sched
+	}
+}
+
+// onJobResult 定时任务执行失败时发出事件
+func (s *System) onJobResult(result scheduler.Result) {
+	if result.Err == nil {
+		return
+	}
+	s.HandleEvent(types.SystemEvent{
+		Type:      types.EventJobFailed,
+		Source:    result.Name,
+		Timestamp: time.Now(),
+		Message:   result.Err.Error(),
+		Priority:  types.PriorityNormal,
+		Data:      result,
+	})
+}
+
+// registerJob 注册系统声明的周期任务, 调用方需持有锁
+// 注册失败(如配置覆盖的计划无效)时直接记录错误而不经recordError
+func (s *System) registerJob(name, subsystem string, interval time.Duration, run func(ctx context.Context) error) {
+	err := s.common.GetScheduler().Register(scheduler.Job{
+		Name:      name,
+		Subsystem: subsystem,
+		Spec:      everySpec(interval),
+		Run:       run,
+	})
+	if err != nil {
+		s.appendErrorLocked(fmt.Errorf("failed to schedule %s: %w", name, err))
+	}
+}
+
+// everySpec 固定间隔的计划
+func everySpec(interval time.Duration) string {
+	return "@every " + interval.String()
+}
+
+// ScheduleJob 注册自定义定时任务, 计划和执行统计见ScheduledJobs
+func (s *System) ScheduleJob(job scheduler.Job) error {
+	return s.common.GetScheduler().Register(job)
+}
+
+// UnscheduleJob 注销定时任务, 任务不存在时返回false
+func (s *System) UnscheduleJob(name string) bool {
+	return s.common.GetScheduler().Unregister(name)
+}
+
+// TriggerJob 立即执行定时任务
+func (s *System) TriggerJob(name string) error {
+	return s.common.GetScheduler().Trigger(name)
+}
+
+// ScheduledJobs 获取全部定时任务的计划和执行统计
+func (s *System) ScheduledJobs() []scheduler.JobStats {
+	return s.common.GetScheduler().Jobs()
+}
@@ -0,0 +1,60 @@
+// system/schemas.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/common/scheduler"
+	"github.com/Corphon/daoflow/system/control/balance"
+	"github.com/Corphon/daoflow/system/control/watchdog"
+	"github.com/Corphon/daoflow/system/meta/field"
+	"github.com/Corphon/daoflow/system/monitor/drift"
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// newEventSchemas 创建事件负载结构注册表并注册系统发出的事件
+func newEventSchemas() *types.EventSchemaRegistry {
+	r := types.NewEventSchemaRegistry()
+	// 内置结构的类型和版本固定, 注册不会失败
+	must := func(err error) {
+		if err != nil {
+			panic(err)
+		}
+	}
+	must(types.RegisterEventPayload[DrainReport](r, types.EventSystemDrained, 1, "per-component drain results before shutdown"))
+	must(types.RegisterEventPayload[types.ModeStatus](r, types.EventModeChanged, 1, "operating mode after the switch"))
+	must(types.RegisterEventPayload[types.ThrottleState](r, types.EventThrottleChanged, 1, "loop budget usage and throttle state"))
+	must(types.RegisterEventPayload[DiagnosticCheck](r, types.EventDiagnosticFailed, 1, "failed self-check"))
+	must(types.RegisterEventPayload[types.MemoryUsage](r, types.EventMemoryLimitExceeded, 1, "cache usage over its soft limit"))
+	must(types.RegisterEventPayload[types.MemoryEviction](r, types.EventMemoryEvicted, 1, "cache entries evicted to the soft limit"))
+	must(types.RegisterEventPayload[scheduler.Result](r, types.EventJobFailed, 1, "failed, timed out or panicked job run"))
+	must(types.RegisterEventPayload[balance.Action](r, types.EventBalanceAction, 1, "yin-yang balance correction"))
+	must(types.RegisterEventPayload[field.CouplingCrossing](r, types.EventCouplingThreshold, 1, "coupling strength threshold crossing"))
+	must(types.RegisterEventPayload[field.Perturbation](r, types.EventPerturbationScheduled, 1, "scheduled field perturbation"))
+	must(types.RegisterEventPayload[field.Perturbation](r, types.EventPerturbationApplied, 1, "applied field perturbation"))
+	must(types.RegisterEventPayload[field.Perturbation](r, types.EventPerturbationReverted, 1, "field perturbation reverted after its duration"))
+	must(types.RegisterEventPayload[field.Perturbation](r, types.EventPerturbationCancelled, 1, "cancelled field perturbation"))
+	must(types.RegisterEventPayload[drift.Drift](r, types.EventDriftDetected, 1, "detected distribution drift"))
+	must(types.RegisterEventPayload[supervisor.LoopStatus](r, types.EventLoopCrashed, 1, "supervised loop status after a crash"))
+	must(types.RegisterEventPayload[supervisor.LoopStatus](r, types.EventLoopEscalated, 1, "supervised loop status after escalation"))
+	must(types.RegisterEventPayload[watchdog.Status](r, types.EventSubsystemRecovering, 1, "subsystem health during recovery"))
+	must(types.RegisterEventPayload[watchdog.Status](r, types.EventSubsystemRecovered, 1, "subsystem health after recovery"))
+	must(types.RegisterEventPayload[watchdog.Status](r, types.EventSubsystemDegraded, 1, "subsystem health after recovery was exhausted"))
+	must(types.RegisterEventPayload[types.PatternDetection](r, types.EventPatternDetected, 1, "newly detected pattern"))
+	must(types.RegisterEventPayload[types.PatternMatch](r, types.EventPatternMatched, 1, "first match of a pattern against a template"))
+	must(types.RegisterEventPayload[ModelOnboarding](r, types.EventModelSync, 1, "onboarded model activation"))
+	return r
+}
+
+// EventSchemas 事件负载结构注册表, 子系统和使用方可注册自定义事件的负载结构
+// 已注册的事件类型在发布时校验负载, 负载不符时HandleEvent返回验证错误
+func (s *System) EventSchemas() *types.EventSchemaRegistry {
+	return s.eventSchemas
+}
+
+// Emit 以类型化负载发布事件
+// 未标注结构版本时按最新版本校验; 需按旧版本结构发布时先用types.WithSchemaVersion标注
+func Emit[T any](s *System, event types.SystemEvent, payload T) error {
+	event.Data = payload
+	return s.HandleEvent(event)
+}
@@ -0,0 +1,248 @@
+// system/snapshot.go
+
+package system
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/encryption"
+	"github.com/Corphon/daoflow/system/evolution/adaptation"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+const (
+	// SnapshotFormat 快照归档格式标识
+	SnapshotFormat = "daoflow.snapshot"
+	// SnapshotVersion 当前快照版本
+	SnapshotVersion = 1
+)
+
+// SystemSnapshot 系统完整状态快照
+type SystemSnapshot struct {
+	Format    string    `json:"format"`     // 归档格式
+	Version   int       `json:"version"`    // 快照版本
+	CreatedAt time.Time `json:"created_at"` // 创建时间
+
+	// 核心能量
+	Core struct {
+		Energy map[string]float64 `json:"energy"` // 能量分量
+	} `json:"core"`
+
+	// 模型状态
+	Models map[string]model.ModelState `json:"models"`
+
+	// 活跃模式
+	Patterns []emergence.EmergentPattern `json:"patterns"`
+
+	// 知识库与学习模型
+	Learning *adaptation.LearningSnapshot `json:"learning,omitempty"`
+}
+
+// Snapshot 将系统状态写入归档, 启用加密时整个归档以当前密钥加密
+func (s *System) Snapshot(w io.Writer) error {
+	snapshot := s.captureSnapshot()
+	keyring := s.Keyring()
+
+	out := w
+	var buf bytes.Buffer
+	if keyring != nil {
+		out = &buf
+	}
+
+	zw := gzip.NewWriter(out)
+	if err := json.NewEncoder(zw).Encode(snapshot); err != nil {
+		zw.Close()
+		return types.WrapError(err, types.ErrStorage, "failed to encode snapshot")
+	}
+	if err := zw.Close(); err != nil {
+		return types.WrapError(err, types.ErrIO, "failed to flush snapshot")
+	}
+
+	if keyring != nil {
+		sealed, err := keyring.Seal(snapshotLabel, buf.Bytes())
+		if err != nil {
+			return err
+		}
+		if _, err := w.Write(sealed); err != nil {
+			return types.WrapError(err, types.ErrIO, "failed to write snapshot")
+		}
+	}
+	return nil
+}
+
+// Restore 从归档恢复系统状态, 加密的归档自动解密; 启用加密时拒绝未加密的归档
+// 系统运行时立即应用; 未运行时暂存, 在Start完成组件启动后应用
+func (s *System) Restore(r io.Reader) error {
+	return s.restore(r)
+}
+
+// RestoreUnencrypted 与Restore相同, 但启用加密时仍接受未加密的归档, 用于迁移启用加密之前写入的归档
+func (s *System) RestoreUnencrypted(r io.Reader) error {
+	return s.restore(r, encryption.AllowPlaintext())
+}
+
+// restore 解密并应用归档
+func (s *System) restore(r io.Reader, opts ...encryption.ReadOption) error {
+	data, err := encryption.ReadAll(s.Keyring(), snapshotLabel, r, opts...)
+	if err != nil {
+		return err
+	}
+
+	zr, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return types.WrapError(err, types.ErrIO, "failed to open snapshot archive")
+	}
+	defer zr.Close()
+
+	var snapshot SystemSnapshot
+	if err := json.NewDecoder(zr).Decode(&snapshot); err != nil {
+		return types.WrapError(err, types.ErrStorage, "failed to decode snapshot")
+	}
+
+	if snapshot.Format != SnapshotFormat {
+		return types.NewSystemError(types.ErrInvalid, "unknown snapshot format", nil).
+			WithContext("format", snapshot.Format)
+	}
+	if snapshot.Version < 1 || snapshot.Version > SnapshotVersion {
+		return types.NewSystemError(types.ErrInvalid, "unsupported snapshot version", nil).
+			WithContext("version", snapshot.Version)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		s.pendingSnapshot = &snapshot
+		return nil
+	}
+
+	return s.applySnapshot(&snapshot)
+}
+
+// captureSnapshot 采集当前状态
+func (s *System) captureSnapshot() *SystemSnapshot {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	snapshot := &SystemSnapshot{
+		Format:    SnapshotFormat,
+		Version:   SnapshotVersion,
+		CreatedAt: time.Now(),
+		Models:    make(map[string]model.ModelState),
+	}
+
+	// 核心能量
+	snapshot.Core.Energy = make(map[string]float64)
+	for typ, amount := range s.core.ExportEnergy() {
+		snapshot.Core.Energy[core.FlowTypeMap[typ]] = amount
+	}
+
+	// 模型状态
+	for name, m := range s.subModels() {
+		snapshot.Models[name] = m.GetState()
+	}
+	for name, m := range s.models {
+		if _, exists := snapshot.Models[name]; !exists {
+			snapshot.Models[name] = m.GetState()
+		}
+	}
+
+	// 活跃模式, 去掉演化历史中的回指针以避免循环引用
+	if detector := s.meta.GetDetector(); detector != nil {
+		patterns := detector.GetActivePatterns()
+		for i := range patterns {
+			evolution := make([]emergence.PatternState, len(patterns[i].Evolution))
+			for j, state := range patterns[i].Evolution {
+				state.Pattern = nil
+				evolution[j] = state
+			}
+			patterns[i].Evolution = evolution
+		}
+		snapshot.Patterns = patterns
+	}
+
+	// 学习状态
+	if learning := s.evolution.GetLearning(); learning != nil {
+		snapshot.Learning = learning.ExportSnapshot()
+	}
+
+	return snapshot
+}
+
+// applySnapshot 应用快照(调用方持有锁)
+func (s *System) applySnapshot(snapshot *SystemSnapshot) error {
+	// 核心能量
+	energy := make(map[core.EnergyType]float64)
+	for typ, name := range core.FlowTypeMap {
+		energy[typ] = snapshot.Core.Energy[name]
+	}
+	if err := s.core.RestoreEnergy(energy); err != nil {
+		return types.WrapError(err, types.ErrState, "failed to restore core energy")
+	}
+
+	// 模型状态, 不支持完整恢复的模型只恢复能量
+	models := s.subModels()
+	for name, m := range s.models {
+		models[name] = m
+	}
+	for name, state := range snapshot.Models {
+		m, exists := models[name]
+		if !exists {
+			continue
+		}
+		restore := func() error { return m.SetEnergy(state.Energy) }
+		if r, ok := m.(model.StateRestorer); ok {
+			restore = func() error { return r.RestoreState(state) }
+		}
+		if err := restore(); err != nil {
+			return fmt.Errorf("failed to restore model %s: %w", name, err)
+		}
+	}
+
+	// 活跃模式
+	if detector := s.meta.GetDetector(); detector != nil {
+		detector.RestorePatterns(snapshot.Patterns)
+	}
+
+	// 学习状态
+	if learning := s.evolution.GetLearning(); learning != nil {
+		learning.RestoreSnapshot(snapshot.Learning)
+	}
+
+	return nil
+}
+
+// stateModel 可快照的模型
+type stateModel interface {
+	GetState() model.ModelState
+	SetEnergy(energy float64) error
+}
+
+// subModels 获取集成模型下的子模型
+func (s *System) subModels() map[string]stateModel {
+	models := make(map[string]stateModel)
+	if s.modelManager == nil {
+		return models
+	}
+	if m := s.modelManager.GetYinYangFlow(); m != nil {
+		models["yinyang"] = m
+	}
+	if m := s.modelManager.GetWuXingFlow(); m != nil {
+		models["wuxing"] = m
+	}
+	if m := s.modelManager.GetBaGuaFlow(); m != nil {
+		models["bagua"] = m
+	}
+	if m := s.modelManager.GetGanZhiFlow(); m != nil {
+		models["ganzhi"] = m
+	}
+	return models
+}
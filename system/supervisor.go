@@ -0,0 +1,81 @@
+// system/supervisor.go
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/Corphon/daoflow/system/supervisor"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Supervisor 返回协程监管器
+// 子系统以系统上下文启动, 其中的长期运行循环均由该监管器恢复和重启
+func (s *System) Supervisor() *supervisor.Supervisor {
+	return s.supervisor
+}
+
+// newSupervisor 创建协程监管器并接入崩溃和升级事件
+func (s *System) newSupervisor() *supervisor.Supervisor {
+	var cfg types.SupervisorConfig
+	if s.config.SupervisorConfig != nil {
+		cfg = *s.config.SupervisorConfig
+	}
+
+	sup := supervisor.New(cfg)
+	sup.OnCrash(s.onLoopCrash)
+	sup.OnEscalate(s.onLoopEscalate)
+	return sup
+}
+
+// supervisedContext 为上下文附加协程监管器
+func (s *System) supervisedContext(ctx context.Context) context.Context {
+	if supervisor.FromContext(ctx) == s.supervisor {
+		return ctx
+	}
+	return supervisor.NewContext(ctx, s.supervisor)
+}
+
+// startEventLoop 在监管下启动事件处理循环
+func (s *System) startEventLoop() {
+	s.supervisor.Go(s.ctx, "system.events", func(context.Context) {
+		s.processEvents()
+	})
+}
+
+// onLoopCrash 记录协程崩溃, 在崩溃的协程中调用
+func (s *System) onLoopCrash(status supervisor.LoopStatus) {
+	err := types.NewSystemError(types.ErrRuntime, "supervised loop panicked", nil).
+		WithSeverity(types.ErrSeverityCritical).
+		WithRetryable(status.State == supervisor.LoopRestarting).
+		WithDetails(status.LastPanic).
+		WithContext("loop", status.Name).
+		WithContext("consecutive", status.Consecutive)
+
+	// 不经recordError, 其持锁调用HandleEvent
+	s.mu.Lock()
+	s.appendErrorLocked(err)
+	s.mu.Unlock()
+
+	s.HandleEvent(types.SystemEvent{
+		Type:      types.EventLoopCrashed,
+		Source:    status.Name,
+		Timestamp: time.Now(),
+		Message:   status.LastPanic,
+		Priority:  types.PriorityHigh,
+		Data:      status,
+	})
+}
+
+// onLoopEscalate 协程反复崩溃已停止重启, 发送升级事件
+func (s *System) onLoopEscalate(status supervisor.LoopStatus) {
+	s.HandleEvent(types.SystemEvent{
+		Type:      types.EventLoopEscalated,
+		Source:    status.Name,
+		Timestamp: time.Now(),
+		Message:   "supervised loop stopped after repeated crashes",
+		Priority:  types.PriorityHighest,
+		Data:      status,
+	})
+}
@@ -0,0 +1,38 @@
+// system/supervisor/context.go
+
+package supervisor
+
+import "context"
+
+// contextKey 上下文键
+type contextKey struct{}
+
+// NewContext 返回携带监管器的上下文
+// 子系统以该上下文启动时, 通过包级Go/Run启动的循环自动受监管
+func NewContext(ctx context.Context, s *Supervisor) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext 获取上下文中的监管器, 不存在时返回nil
+func FromContext(ctx context.Context) *Supervisor {
+	s, _ := ctx.Value(contextKey{}).(*Supervisor)
+	return s
+}
+
+// Go 启动长期运行的循环, 上下文携带监管器时受其监管, 否则直接启动
+func Go(ctx context.Context, name string, fn func(context.Context)) {
+	if s := FromContext(ctx); s != nil {
+		s.Go(ctx, name, fn)
+		return
+	}
+	go fn(ctx)
+}
+
+// Run 在当前协程运行循环, 上下文携带监管器时受其监管, 否则直接运行
+func Run(ctx context.Context, name string, fn func(context.Context)) {
+	if s := FromContext(ctx); s != nil {
+		s.Run(ctx, name, fn)
+		return
+	}
+	fn(ctx)
+}
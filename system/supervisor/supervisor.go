@@ -0,0 +1,318 @@
+// system/supervisor/supervisor.go
+
+package supervisor
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 默认参数
+const (
+	defaultInitialBackoff = 100 * time.Millisecond
+	defaultMaxBackoff     = 30 * time.Second
+	defaultMaxRestarts    = 5
+	defaultStableAfter    = time.Minute
+)
+
+// LoopState 协程状态
+type LoopState string
+
+const (
+	LoopRunning    LoopState = "running"    // 运行中
+	LoopRestarting LoopState = "restarting" // 崩溃后等待重启
+	LoopStopped    LoopState = "stopped"    // 正常退出
+	LoopFailed     LoopState = "failed"     // 反复崩溃, 已停止重启
+)
+
+// LoopStatus 协程运行状态
+type LoopStatus struct {
+	Name        string    `json:"name"`
+	State       LoopState `json:"state"`
+	StartedAt   time.Time `json:"started_at"`   // 最近一次启动时间
+	Crashes     int64     `json:"crashes"`      // 累计崩溃次数
+	Restarts    int64     `json:"restarts"`     // 累计重启次数
+	Consecutive int       `json:"consecutive"`  // 连续崩溃次数
+	LastCrash   time.Time `json:"last_crash"`   // 最近崩溃时间
+	LastPanic   string    `json:"last_panic"`   // 最近崩溃的panic值
+	LastStack   string    `json:"last_stack"`   // 最近崩溃的堆栈
+	NextRestart time.Time `json:"next_restart"` // 计划重启时间
+}
+
+// Handler 崩溃或升级通知
+type Handler func(status LoopStatus)
+
+// loop 受监管的协程
+type loop struct {
+	status LoopStatus
+}
+
+// Supervisor 协程监管器
+// 为长期运行的循环提供panic恢复、指数退避重启和崩溃计数,
+// 连续重启超过上限后停止重启并升级通知
+type Supervisor struct {
+	mu sync.RWMutex
+
+	// 基础配置
+	config types.SupervisorConfig
+
+	// 受监管的协程
+	loops map[string]*loop
+
+	// 通知处理器
+	handlers struct {
+		crash    []Handler
+		escalate []Handler
+	}
+
+	wg sync.WaitGroup
+}
+
+// New 创建协程监管器
+func New(config types.SupervisorConfig) *Supervisor {
+	if config.InitialBackoff <= 0 {
+		config.InitialBackoff = defaultInitialBackoff
+	}
+	if config.MaxBackoff <= 0 {
+		config.MaxBackoff = defaultMaxBackoff
+	}
+	if config.MaxBackoff < config.InitialBackoff {
+		config.MaxBackoff = config.InitialBackoff
+	}
+	if config.MaxRestarts <= 0 {
+		config.MaxRestarts = defaultMaxRestarts
+	}
+	if config.StableAfter <= 0 {
+		config.StableAfter = defaultStableAfter
+	}
+
+	return &Supervisor{
+		config: config,
+		loops:  make(map[string]*loop),
+	}
+}
+
+// OnCrash 注册崩溃通知, 每次崩溃后调用
+func (s *Supervisor) OnCrash(handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers.crash = append(s.handlers.crash, handler)
+}
+
+// OnEscalate 注册升级通知, 协程连续重启超过上限并停止重启时调用
+func (s *Supervisor) OnEscalate(handler Handler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers.escalate = append(s.handlers.escalate, handler)
+}
+
+// Go 在新协程中监管运行fn, 返回登记名称
+// 同名协程仍在运行时名称追加序号
+func (s *Supervisor) Go(ctx context.Context, name string, fn func(context.Context)) string {
+	l := s.register(name)
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.supervise(ctx, l, fn)
+	}()
+	return l.status.Name
+}
+
+// Run 在当前协程监管运行fn, fn正常返回、上下文取消或升级后返回
+func (s *Supervisor) Run(ctx context.Context, name string, fn func(context.Context)) {
+	s.supervise(ctx, s.register(name), fn)
+}
+
+// Wait 等待所有通过Go启动的协程退出
+func (s *Supervisor) Wait() {
+	s.wg.Wait()
+}
+
+// Status 获取所有协程状态, 按名称排序
+func (s *Supervisor) Status() []LoopStatus {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	list := make([]LoopStatus, 0, len(s.loops))
+	for _, l := range s.loops {
+		list = append(list, l.status)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Loop 获取指定协程状态
+func (s *Supervisor) Loop(name string) (LoopStatus, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	l, ok := s.loops[name]
+	if !ok {
+		return LoopStatus{}, false
+	}
+	return l.status, true
+}
+
+// GetMetrics 获取监管指标
+func (s *Supervisor) GetMetrics() map[string]interface{} {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	states := make(map[LoopState]int)
+	var crashes, restarts int64
+	for _, l := range s.loops {
+		states[l.status.State]++
+		crashes += l.status.Crashes
+		restarts += l.status.Restarts
+	}
+
+	return map[string]interface{}{
+		"loops":      len(s.loops),
+		"running":    states[LoopRunning],
+		"restarting": states[LoopRestarting],
+		"failed":     states[LoopFailed],
+		"crashes":    crashes,
+		"restarts":   restarts,
+	}
+}
+
+// register 登记协程, 已退出的同名协程复用其记录
+func (s *Supervisor) register(name string) *loop {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := name
+	for i := 2; ; i++ {
+		l, exists := s.loops[key]
+		if !exists {
+			break
+		}
+		if l.status.State == LoopStopped || l.status.State == LoopFailed {
+			l.status.State = LoopRunning
+			l.status.Consecutive = 0
+			l.status.NextRestart = time.Time{}
+			return l
+		}
+		key = fmt.Sprintf("%s#%d", name, i)
+	}
+
+	l := &loop{status: LoopStatus{Name: key, State: LoopRunning}}
+	s.loops[key] = l
+	return l
+}
+
+// supervise 运行并在崩溃后按退避重启
+func (s *Supervisor) supervise(ctx context.Context, l *loop, fn func(context.Context)) {
+	for {
+		started := time.Now()
+		s.mu.Lock()
+		l.status.State = LoopRunning
+		l.status.StartedAt = started
+		l.status.NextRestart = time.Time{}
+		s.mu.Unlock()
+
+		p, stack := invoke(ctx, fn)
+		if p == nil || ctx.Err() != nil {
+			s.mu.Lock()
+			l.status.State = LoopStopped
+			s.mu.Unlock()
+			if p != nil {
+				s.recordCrash(l, p, stack, started)
+			}
+			return
+		}
+
+		delay, escalated := s.recordCrash(l, p, stack, started)
+		if escalated {
+			return
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			s.mu.Lock()
+			l.status.State = LoopStopped
+			s.mu.Unlock()
+			return
+		case <-timer.C:
+		}
+
+		s.mu.Lock()
+		l.status.Restarts++
+		s.mu.Unlock()
+	}
+}
+
+// recordCrash 记录崩溃并通知, 返回重启等待时间和是否已升级
+func (s *Supervisor) recordCrash(l *loop, p interface{}, stack string, started time.Time) (time.Duration, bool) {
+	now := time.Now()
+
+	s.mu.Lock()
+	// 稳定运行足够久后视为新的崩溃序列
+	if now.Sub(started) >= s.config.StableAfter {
+		l.status.Consecutive = 0
+	}
+	l.status.Crashes++
+	l.status.Consecutive++
+	l.status.LastCrash = now
+	l.status.LastPanic = fmt.Sprint(p)
+	l.status.LastStack = stack
+
+	delay := s.backoff(l.status.Consecutive)
+	escalated := l.status.Consecutive > s.config.MaxRestarts
+	switch {
+	case l.status.State == LoopStopped:
+		// 上下文已取消, 只记录不重启
+	case escalated:
+		l.status.State = LoopFailed
+	default:
+		l.status.State = LoopRestarting
+		l.status.NextRestart = now.Add(delay)
+	}
+
+	status := l.status
+	crash := append([]Handler(nil), s.handlers.crash...)
+	escalate := append([]Handler(nil), s.handlers.escalate...)
+	s.mu.Unlock()
+
+	for _, h := range crash {
+		h(status)
+	}
+	if status.State == LoopFailed {
+		for _, h := range escalate {
+			h(status)
+		}
+	}
+	return delay, escalated
+}
+
+// backoff 第n次连续崩溃后的重启等待时间
+func (s *Supervisor) backoff(consecutive int) time.Duration {
+	delay := s.config.InitialBackoff
+	for i := 1; i < consecutive && delay < s.config.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > s.config.MaxBackoff {
+		delay = s.config.MaxBackoff
+	}
+	return delay
+}
+
+// invoke 运行fn并捕获panic
+func invoke(ctx context.Context, fn func(context.Context)) (p interface{}, stack string) {
+	defer func() {
+		if r := recover(); r != nil {
+			p, stack = r, string(debug.Stack())
+		}
+	}()
+	fn(ctx)
+	return nil, ""
+}
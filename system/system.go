@@ -6,16 +6,27 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 	"github.com/Corphon/daoflow/system/common"
 	"github.com/Corphon/daoflow/system/control"
+	"github.com/Corphon/daoflow/system/control/governor"
+	"github.com/Corphon/daoflow/system/dashboard"
+	"github.com/Corphon/daoflow/system/encryption"
 	"github.com/Corphon/daoflow/system/evolution"
+	"github.com/Corphon/daoflow/system/integrations"
 	"github.com/Corphon/daoflow/system/meta"
 	"github.com/Corphon/daoflow/system/monitor"
+	"github.com/Corphon/daoflow/system/monitor/trace"
+	"github.com/Corphon/daoflow/system/observer"
+	"github.com/Corphon/daoflow/system/faultinject"
+	"github.com/Corphon/daoflow/system/invariants"
+	"github.com/Corphon/daoflow/system/supervisor"
 	"github.com/Corphon/daoflow/system/types"
 )
 
@@ -28,6 +39,7 @@ type System struct {
 
 	// Model components
 	models       map[string]model.Model
+	modelStages  map[string]*ModelOnboarding
 	modelManager *model.IntegrateFlow // 集成流模型管理器
 
 	// System subsystems
@@ -52,6 +64,7 @@ type System struct {
 		handlers  map[types.EventType][]types.EventHandler // 事件处理器
 		queue     chan types.SystemEvent                   // 事件队列
 		processor types.EventProcessor                     // 事件处理器
+		dedup     *types.EventDeduplicator                 // 幂等键去重
 	}
 
 	// Lifecycle management
@@ -61,6 +74,57 @@ type System struct {
 
 	// Configuration
 	config *Config
+
+	// 待应用的快照(系统未运行时恢复)
+	pendingSnapshot *SystemSnapshot
+
+	// 命名空间注册表
+	namespaces *types.NamespaceRegistry
+
+	// 外部输出分发器
+	outputs *integrations.Dispatcher
+	hooks   outputHooks
+
+	// 仪表盘服务
+	dashboard *dashboard.Server
+
+	// 长期运行协程的监管器
+	supervisor *supervisor.Supervisor
+
+	// 子系统启停记录
+	lifecycle lifecycleState
+
+	// 外部注册的健康度贡献者
+	healthContributors map[string]types.HealthContributor
+
+	// 看门狗运行期的取消函数
+	watchdogCancel context.CancelFunc
+
+	// 运行模式
+	mode types.ModeStatus
+
+	// 最近一次自检报告
+	diagnosticsReport *DiagnosticsReport
+
+	// 关闭前排空, 开始后拒绝外部事件; 最近一次排空报告
+	draining    atomic.Bool
+	drainReport *DrainReport
+
+	// 静态数据加密钥匙串, 未配置加密时为nil
+	keyring *encryption.Keyring
+
+	// 循环资源预算, 未配置时为nil
+	governor       *governor.Governor
+	governorCancel context.CancelFunc
+
+	// 启动预热, 未配置时为nil
+	warmup *types.Warmup
+
+	// 只读观察者附加点
+	observers *observer.Hub
+
+	// 事件负载结构注册表
+	eventSchemas *types.EventSchemaRegistry
 }
 
 // Config holds the system configuration
@@ -72,6 +136,55 @@ type Config struct {
 	EvolutionConfig *types.EvoConfig
 	MetaConfig      *types.MetaConfig
 	MonitorConfig   *types.MonitorConfig
+
+	// 外部集成输出配置, KafkaWriters 按输出端名称提供kafka生产者
+	IntegrationConfig *types.IntegrationConfig
+	KafkaWriters      map[string]integrations.KafkaWriter
+
+	// 仪表盘配置, 为空时使用默认值
+	DashboardConfig *types.DashboardConfig
+
+	// 协程监管配置, 为空时使用默认值
+	SupervisorConfig *types.SupervisorConfig
+
+	// 启动后执行一次自检
+	DiagnosticsOnStart bool
+
+	// 故障注入配置, 用于韧性测试; 注入的故障对进程内所有系统实例生效
+	FaultInjection *types.FaultInjectionConfig
+
+	// 严格不变量检查: 标准化结果越界时返回错误而非截断; 对进程内所有系统实例生效
+	StrictInvariants bool
+
+	// 模块边界(检测器输出、分析器输出、学习特征)的NaN/Inf校验模式; 对进程内所有系统实例生效
+	ValidationMode invariants.ValidationMode
+
+	// 缓存内存软上限, 为空时不检查
+	MemoryLimits *types.MemoryLimitConfig
+
+	// 静态数据加密配置, 为空时不加密; EncryptionKeys 提供配置中未列出的密钥, 如接入KMS
+	Encryption     *types.EncryptionConfig
+	EncryptionKeys encryption.KeyProvider
+
+	// 检测和因果发现循环的资源预算, 为空时不限制
+	Governor *types.GovernorConfig
+
+	// 模式、知识、事件等ID的生成策略, 为空时使用ULID
+	// 该设置是进程级的(core.SetIDGenerator): 后创建的系统实例若设置了IDs或IDGenerator,
+	// 会替换先前实例的生成器及其种子和序列位置, 先前实例此后生成的ID也来自新的生成器;
+	// 需要按种子复现ID时, 进程内只应有一个系统实例设置这两项
+	// IDGenerator 非空时优先于IDs, 用于接入自定义生成器
+	IDs         *core.IDConfig
+	IDGenerator core.IDGenerator
+
+	// 启动预热配置, 为空时不预热; 预热期间检测结果标记为临时, 适应动作按配置停用或减弱
+	Warmup *types.WarmupConfig
+
+	// 只读观察者配置, 为空时使用默认值
+	Observers *types.ObserverConfig
+
+	// 事件幂等去重配置, 为空时使用默认窗口
+	Dedup *types.DedupConfig
 }
 
 // --------------------------------------
@@ -81,19 +194,67 @@ func New(cfg *Config) (*System, error) {
 		cfg = DefaultConfig()
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
+	// 按配置允许并注入故障
+	if cfg.FaultInjection != nil {
+		if err := faultinject.Configure(*cfg.FaultInjection); err != nil {
+			return nil, fmt.Errorf("failed to configure fault injection: %w", err)
+		}
+	}
+	if cfg.StrictInvariants {
+		invariants.SetMode(invariants.ModeStrict)
+	}
+	if cfg.ValidationMode != invariants.ValidationOff {
+		invariants.SetValidationMode(cfg.ValidationMode)
+	}
+	switch {
+	case cfg.IDGenerator != nil:
+		core.SetIDGenerator(cfg.IDGenerator)
+	case cfg.IDs != nil:
+		ids, err := core.NewIDGenerator(*cfg.IDs)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure id generator: %w", err)
+		}
+		core.SetIDGenerator(ids)
+	}
+
+	var warmup *types.Warmup
+	if cfg.Warmup != nil {
+		if err := cfg.Warmup.Validate(); err != nil {
+			return nil, fmt.Errorf("invalid warm-up config: %w", err)
+		}
+		warmup = types.NewWarmup(*cfg.Warmup)
+	}
 
 	sys := &System{
-		models: make(map[string]model.Model),
-		ctx:    ctx,
-		cancel: cancel,
-		config: cfg,
+		warmup:      warmup,
+		models:      make(map[string]model.Model),
+		modelStages: make(map[string]*ModelOnboarding),
+		config:      cfg,
+
+		healthContributors: make(map[string]types.HealthContributor),
+	}
+
+	// 子系统循环经系统上下文接入监管
+	sys.supervisor = sys.newSupervisor()
+	ctx, cancel := context.WithCancel(sys.supervisedContext(context.Background()))
+	sys.ctx, sys.cancel = ctx, cancel
+
+	// 初始化命名空间
+	sys.namespaces = types.NewNamespaceRegistry()
+
+	// 初始化只读观察者附加点
+	var observerConfig types.ObserverConfig
+	if cfg.Observers != nil {
+		observerConfig = *cfg.Observers
 	}
+	sys.observers = observer.NewHub(observerSource{sys}, observerConfig)
 
-	// 初始化事件系统
+	// 初始化事件系统, 负载结构注册表在重置后保留
+	sys.eventSchemas = newEventSchemas()
 	sys.events.handlers = make(map[types.EventType][]types.EventHandler)
 	sys.events.queue = make(chan types.SystemEvent, 1000)
-	sys.events.processor = types.NewEventBus()
+	sys.events.dedup = sys.newEventDeduplicator()
+	sys.events.processor = sys.newEventBus()
 
 	// 初始化状态
 	sys.state.status = "initialized"
@@ -101,6 +262,7 @@ func New(cfg *Config) (*System, error) {
 	sys.state.errors = make([]error, 0)
 	sys.state.events = make([]types.SystemEvent, 0)
 	sys.state.metrics = types.SystemMetrics{}
+	sys.mode = types.ModeStatus{Mode: types.ModeNormal, Previous: types.ModeNormal, Since: sys.state.startTime}
 
 	// 初始化模型管理器
 	integrateFlow := model.NewIntegrateFlow()
@@ -121,8 +283,26 @@ func New(cfg *Config) (*System, error) {
 		return nil, fmt.Errorf("failed to initialize subsystems: %w", err)
 	}
 
+	// 静态数据加密
+	if cfg.Encryption != nil {
+		keyring, err := encryption.KeyringFromConfig(*cfg.Encryption, cfg.EncryptionKeys)
+		if err != nil {
+			cancel()
+			return nil, fmt.Errorf("failed to initialize encryption: %w", err)
+		}
+		sys.setKeyring(keyring)
+	}
+
+	// 初始化外部输出
+	sys.outputs, err = integrations.NewDispatcherFromConfig(cfg.IntegrationConfig, cfg.KafkaWriters)
+	if err != nil {
+		cancel()
+		return nil, fmt.Errorf("failed to initialize outputs: %w", err)
+	}
+	sys.outputs.OnError(sys.recordOutputError)
+
 	// 启动事件处理
-	go sys.processEvents()
+	sys.startEventLoop()
 
 	return sys, nil
 }
@@ -170,6 +350,23 @@ func (c *Config) DefaultConfig() *Config {
 	if c.MonitorConfig != nil {
 		cfg.MonitorConfig = c.MonitorConfig
 	}
+	cfg.IntegrationConfig = c.IntegrationConfig
+	cfg.KafkaWriters = c.KafkaWriters
+	cfg.DashboardConfig = c.DashboardConfig
+	cfg.SupervisorConfig = c.SupervisorConfig
+	cfg.DiagnosticsOnStart = c.DiagnosticsOnStart
+	cfg.FaultInjection = c.FaultInjection
+	cfg.StrictInvariants = c.StrictInvariants
+	cfg.ValidationMode = c.ValidationMode
+	cfg.MemoryLimits = c.MemoryLimits
+	cfg.Encryption = c.Encryption
+	cfg.EncryptionKeys = c.EncryptionKeys
+	cfg.Governor = c.Governor
+	cfg.IDs = c.IDs
+	cfg.IDGenerator = c.IDGenerator
+	cfg.Warmup = c.Warmup
+	cfg.Observers = c.Observers
+	cfg.Dedup = c.Dedup
 
 	return cfg
 }
@@ -190,17 +387,34 @@ func (s *System) initializeSubsystems() error {
 		return err
 	}
 
-	// Initialize evolution manager
+	// Initialize evolution manager, 重建时沿用已消耗的学习隐私预算
+	prev := s.evolution
 	s.evolution, err = evolution.NewManager(s.config.EvolutionConfig)
 	if err != nil {
 		return err
 	}
+	s.evolution.SetNamespaceAuthorizer(s.namespaces)
+	if prev != nil {
+		if privacy := prev.GetLearningPrivacy(); privacy != nil {
+			if current := s.evolution.GetLearningPrivacy(); current != nil {
+				current.Inherit(privacy)
+			} else {
+				s.evolution.SetLearningPrivacy(privacy)
+			}
+		}
+	}
+	if s.keyring != nil {
+		s.evolution.GetAudit().SetKeyring(s.keyring)
+	}
 
 	// Initialize meta manager
 	s.meta, err = meta.NewManager(s.config.MetaConfig)
 	if err != nil {
 		return err
 	}
+	if s.keyring != nil {
+		s.meta.SetKeyring(s.keyring)
+	}
 
 	// Initialize monitor manager
 	s.monitor, err = monitor.NewManager(s.config.MonitorConfig)
@@ -208,6 +422,9 @@ func (s *System) initializeSubsystems() error {
 		return err
 	}
 
+	// 看门狗随控制子系统重建, 接入当前的子系统实例
+	s.initWatchdog()
+
 	return nil
 }
 
@@ -221,7 +438,7 @@ func (s *System) Initialize(ctx context.Context) error {
 	}
 
 	// 初始化上下文
-	s.ctx = ctx
+	s.ctx = s.supervisedContext(ctx)
 	s.state.startTime = time.Now()
 	s.state.status = "initializing"
 
@@ -256,9 +473,20 @@ func (s *System) Start() error {
 		return fmt.Errorf("failed to start components: %w", err)
 	}
 
+	// 应用启动前恢复的快照
+	if s.pendingSnapshot != nil {
+		if err := s.applySnapshot(s.pendingSnapshot); err != nil {
+			// 已持有锁, 直接记录而不经 recordError
+			s.appendErrorLocked(fmt.Errorf("failed to apply snapshot: %w", err))
+		}
+		s.pendingSnapshot = nil
+	}
+
 	// 更新系统状态
 	s.isRunning = true
+	s.draining.Store(false)
 	s.state.status = "running"
+	s.startDiagnostics()
 
 	// 发送系统启动事件
 	s.HandleEvent(types.SystemEvent{
@@ -274,63 +502,47 @@ func (s *System) Start() error {
 
 // startComponents 启动所有组件
 func (s *System) startComponents() error {
-	// 1. 启动核心引擎
-	if err := s.core.Initialize(); err != nil {
-		return fmt.Errorf("failed to initialize core engine: %w", err)
-	}
-
-	// 2. 启动子系统,按依赖顺序启动
+	// 1. 按依赖顺序启动核心引擎和子系统
 	if err := s.startSubsystems(); err != nil {
 		return fmt.Errorf("failed to start subsystems: %w", err)
 	}
 
-	// 3. 启动所有模型
+	// 2. 启动所有模型
 	for name, m := range s.models {
 		if err := m.Start(); err != nil {
 			s.stopSubsystems()
 			return fmt.Errorf("failed to start model %s: %w", name, err)
 		}
 	}
-
-	return nil
-}
-
-// startSubsystems starts all subsystems in dependency order
-func (s *System) startSubsystems() error {
-	// 1. 启动公共子系统
-	if err := s.common.Start(s.ctx); err != nil {
-		return fmt.Errorf("failed to start common subsystem: %w", err)
-	}
-
-	// 2. 启动控制子系统
-	if err := s.control.Start(s.ctx); err != nil {
-		s.common.Stop()
-		return fmt.Errorf("failed to start control subsystem: %w", err)
-	}
-
-	// 3. 启动演化子系统
-	if err := s.evolution.Start(s.ctx); err != nil {
-		s.control.Stop()
-		s.common.Stop()
-		return fmt.Errorf("failed to start evolution subsystem: %w", err)
-	}
-
-	// 4. 启动元数据子系统
-	if err := s.meta.Start(s.ctx); err != nil {
-		s.evolution.Stop()
-		s.control.Stop()
-		s.common.Stop()
-		return fmt.Errorf("failed to start meta subsystem: %w", err)
-	}
-
-	// 5. 启动监控子系统
-	if err := s.monitor.Start(s.ctx); err != nil {
-		s.meta.Stop()
-		s.evolution.Stop()
-		s.control.Stop()
-		s.common.Stop()
-		return fmt.Errorf("failed to start monitor subsystem: %w", err)
-	}
+	s.activatePendingModels()
+
+	// 3. 接入定时任务失败事件、启动预热、异常关联、能量转移记录、耦合阈值事件、扰动事件、间隔调节信号、平衡控制、五行调度、参数调制、因果发现、看门狗、缓存软上限、资源预算、漂移检测和观察者视图发布
+	s.startScheduler()
+	s.startWarmup()
+	s.startCorrelation()
+	s.startEnergyFlow()
+	s.startCouplingEvents()
+	s.startPerturbationEvents()
+	s.startIntervalTuning()
+	s.startBalanceControl()
+	s.startWuXingScheduling()
+	s.startModulation()
+	s.startCausalDiscovery()
+	s.startWatchdog()
+	s.startMemoryLimits()
+	s.startGovernor()
+	s.startDrift()
+	s.startObservers()
+	s.applyMode()
+
+	// 4. 启动外部输出, 演化组件在启动后才存在
+	if err := s.startOutputs(); err != nil {
+		s.stopSubsystems()
+		return fmt.Errorf("failed to start outputs: %w", err)
+	}
+
+	// 5. 接入跨子系统的因果追踪
+	s.startTracing()
 
 	return nil
 }
@@ -372,14 +584,20 @@ func (s *System) stopComponents() error {
 		}
 	}
 
-	// 2. 停止所有子系统
-	if err := s.stopSubsystems(); err != nil {
-		s.recordError(fmt.Errorf("failed to stop subsystems: %w", err))
+	// 2. 停止看门狗、缓存检查、资源预算、漂移采样、观察者视图发布和外部输出, 子系统停止不应触发自动恢复
+	s.stopWatchdog()
+	s.stopMemoryLimits()
+	s.stopGovernor()
+	s.stopDrift()
+	s.stopObservers()
+	if err := s.outputs.Stop(); err != nil {
+		// Stop已持有锁, 不经recordError
+		s.appendErrorLocked(fmt.Errorf("failed to stop outputs: %w", err))
 	}
 
-	// 3. 关闭核心引擎
-	if err := s.core.Shutdown(); err != nil {
-		s.recordError(fmt.Errorf("failed to stop core engine: %w", err))
+	// 3. 按依赖逆序停止子系统和核心引擎
+	if err := s.stopSubsystems(); err != nil {
+		s.recordError(fmt.Errorf("failed to stop subsystems: %w", err))
 	}
 
 	return nil
@@ -391,6 +609,16 @@ func (s *System) Shutdown(ctx context.Context) error {
 	shutdownCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
+	// 仪表盘监听端口须随关闭释放, 即使等待组件超时
+	defer s.stopDashboard()
+	// 观察者的指标流随关闭结束
+	defer s.observers.DetachAll()
+
+	// 先排空进行中的工作, 排空超时不影响后续停止
+	if s.IsRunning() {
+		s.Drain(shutdownCtx)
+	}
+
 	// 停止系统
 	if err := s.Stop(); err != nil {
 		return err
@@ -406,6 +634,11 @@ func (s *System) Shutdown(ctx context.Context) error {
 		// 所有组件已停止
 	}
 
+	// 关闭外部输出端
+	if err := s.outputs.Close(); err != nil {
+		return fmt.Errorf("failed to close outputs: %w", err)
+	}
+
 	return nil
 }
 
@@ -433,31 +666,6 @@ func (s *System) waitForComponents() chan struct{} {
 	return done
 }
 
-// stopSubsystems stops all subsystems in reverse order
-func (s *System) stopSubsystems() error {
-	if err := s.monitor.Stop(); err != nil {
-		return err
-	}
-
-	if err := s.meta.Stop(); err != nil {
-		return err
-	}
-
-	if err := s.evolution.Stop(); err != nil {
-		return err
-	}
-
-	if err := s.control.Stop(); err != nil {
-		return err
-	}
-
-	if err := s.common.Stop(); err != nil {
-		return err
-	}
-
-	return nil
-}
-
 // Reset resets the system to its initial state
 func (s *System) Reset() error {
 	if s.isRunning {
@@ -476,10 +684,11 @@ func (s *System) Reset() error {
 	// 重置事件系统
 	s.events.handlers = make(map[types.EventType][]types.EventHandler)
 	s.events.queue = make(chan types.SystemEvent, 1000)
-	s.events.processor = types.NewEventBus()
+	s.events.dedup = s.newEventDeduplicator()
+	s.events.processor = s.newEventBus()
 
 	// 重置上下文
-	s.ctx, s.cancel = context.WithCancel(context.Background())
+	s.ctx, s.cancel = context.WithCancel(s.supervisedContext(context.Background()))
 
 	// 重新初始化所有组件
 	if err := s.initializeSubsystems(); err != nil {
@@ -520,44 +729,60 @@ func (s *System) Monitor() *monitor.Manager {
 // Model management methods
 
 // RegisterModel adds a new model to the system
+// 系统运行时新模型先同步系统状态, 完成接入后才参与转换和指标统计
 func (s *System) RegisterModel(name string, m model.Model) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
 	if _, exists := s.models[name]; exists {
+		s.mu.Unlock()
 		return types.ErrModelAlreadyExists
 	}
 
-	s.models[name] = m
+	rec, err := s.addModel(name, m)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+
+	s.emitModelSync(rec)
 	return nil
 }
 
 // RegisterModels registers multiple models at once
+// 按名称顺序逐个接入, 任一模型接入失败时已接入的模型一并移除
 func (s *System) RegisterModels(models map[string]model.Model) error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
 
 	// 预检查
+	names := make([]string, 0, len(models))
 	for name := range models {
 		if _, exists := s.models[name]; exists {
+			s.mu.Unlock()
 			return fmt.Errorf("model %s already exists", name)
 		}
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
 	// 批量注册
-	for name, m := range models {
-		s.models[name] = m
-	}
-
-	// 如果系统已运行,启动新注册的模型
-	if s.isRunning {
-		for name, m := range models {
-			if err := m.Start(); err != nil {
-				return fmt.Errorf("failed to start model %s: %w", name, err)
+	recs := make([]*ModelOnboarding, 0, len(names))
+	for _, name := range names {
+		rec, err := s.addModel(name, models[name])
+		if err != nil {
+			for _, added := range recs {
+				if added.Stage == ModelActive {
+					models[added.Name].Stop()
+				}
+				delete(s.models, added.Name)
+				delete(s.modelStages, added.Name)
 			}
+			s.mu.Unlock()
+			return err
 		}
+		recs = append(recs, rec)
 	}
+	s.mu.Unlock()
 
+	s.emitModelSync(recs...)
 	return nil
 }
 
@@ -580,6 +805,7 @@ func (s *System) UnregisterModel(name string) error {
 
 	// 移除模型
 	delete(s.models, name)
+	delete(s.modelStages, name)
 
 	return nil
 }
@@ -624,6 +850,36 @@ func (s *System) HandleEvent(event types.SystemEvent) error {
 		return types.NewSystemError(types.ErrState, "system not running", nil)
 	}
 
+	// 注入的事件队列故障视为队列已满
+	if err := faultinject.Fire(faultinject.PointEventQueue, string(event.Type)); err != nil {
+		return types.NewSystemError(types.ErrQueue, "event queue full", err)
+	}
+
+	// 已声明负载结构的事件校验负载并标注结构版本
+	version, err := s.eventSchemas.Validate(event)
+	if err != nil {
+		return err
+	}
+	if version > 0 && event.Metadata[types.EventSchemaVersionKey] == "" {
+		event = types.WithSchemaVersion(event, version)
+	}
+
+	// 补全事件ID和时间, 以便按ID标注历史事件
+	if event.Timestamp.IsZero() {
+		event.Timestamp = time.Now()
+	}
+	if event.ID == "" {
+		event.ID = core.NewID("evt_")
+	}
+
+	// 窗口内重复的幂等键只投递给处理器, 不再上报跨度和计入事件历史
+	event = s.events.dedup.Publish(event, time.Now())
+
+	// 携带追踪上下文的事件经总线时上报跨度, 处理器以事件跨度为父跨度
+	if !event.Trace.IsZero() && !event.Duplicate {
+		event.Trace = s.traceEvent(event)
+	}
+
 	// 添加到事件队列
 	select {
 	case s.events.queue <- event:
@@ -633,9 +889,11 @@ func (s *System) HandleEvent(event types.SystemEvent) error {
 	}
 
 	// 记录事件
-	s.state.events = append(s.state.events, event)
-	if len(s.state.events) > types.MaxEventHistory {
-		s.state.events = s.state.events[1:]
+	if !event.Duplicate {
+		s.state.events = append(s.state.events, event)
+		if len(s.state.events) > types.MaxEventHistory {
+			s.state.events = s.state.events[1:]
+		}
 	}
 
 	return nil
@@ -686,11 +944,15 @@ func (s *System) processEvents() {
 func (s *System) dispatchEvent(event types.SystemEvent) {
 	s.mu.RLock()
 	handlers := s.events.handlers[event.Type]
+	dedup := s.events.dedup
 	s.mu.RUnlock()
 
 	for _, handler := range handlers {
+		if !s.canDeliver(event, handler) {
+			continue
+		}
 		go func(h types.EventHandler) {
-			if err := h.HandleEvent(event); err != nil {
+			if err := dedup.Deliver(event, h, time.Now()); err != nil {
 				s.recordError(err)
 			}
 		}(handler)
@@ -702,10 +964,7 @@ func (s *System) recordError(err error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	s.state.errors = append(s.state.errors, err)
-	if len(s.state.errors) > types.MaxErrorHistory {
-		s.state.errors = s.state.errors[1:]
-	}
+	s.appendErrorLocked(err)
 
 	// 触发错误事件
 	s.HandleEvent(types.SystemEvent{
@@ -717,6 +976,14 @@ func (s *System) recordError(err error) {
 	})
 }
 
+// appendErrorLocked 记录错误并限制错误历史长度, 不触发错误事件; 调用方需持有锁
+func (s *System) appendErrorLocked(err error) {
+	s.state.errors = append(s.state.errors, err)
+	if len(s.state.errors) > types.MaxErrorHistory {
+		s.state.errors = s.state.errors[len(s.state.errors)-types.MaxErrorHistory:]
+	}
+}
+
 // updateMetrics 更新系统指标
 func (s *System) updateMetrics() {
 	s.mu.Lock()
@@ -747,6 +1014,18 @@ func (s *System) updateMetrics() {
 	s.state.metrics.Stats.SuccessCount = 0  // TODO: 实现成功计数
 	s.state.metrics.Stats.FailureCount = 0  // TODO: 实现失败计数
 
+	// 启动预热状态
+	s.state.metrics.Warmup = s.warmup.Status()
+
+	// 最近一小时追踪耗时的平均值和分位数
+	if s.monitor != nil {
+		if analyzer := s.monitor.GetTraceAnalyzer(); analyzer != nil {
+			latency := analyzer.LatencyQuantiles(trace.RollupQuery{From: now.Add(-time.Hour)})
+			s.state.metrics.Performance.Latency = latency.Mean
+			s.state.metrics.Performance.Percentiles = latency
+		}
+	}
+
 	// 更新资源指标
 	s.state.metrics.CPU = 0        // TODO: 实现CPU使用率
 	s.state.metrics.Memory = 0     // TODO: 实现内存使用率
@@ -755,44 +1034,23 @@ func (s *System) updateMetrics() {
 	// 收集子系统指标
 	s.state.metrics.Subsystems = make(map[string]types.SubsystemMetrics)
 
-	// 核心引擎指标
-	s.state.metrics.Subsystems["core"] = types.SubsystemMetrics{
-		Status:     s.state.status,
-		Health:     1.0, // 基础健康度
-		LastUpdate: now,
-		Metrics:    make(map[string]float64),
-	}
-
-	// 控制子系统指标
-	s.state.metrics.Subsystems["control"] = types.SubsystemMetrics{
-		Status:     s.state.status,
-		Health:     1.0,
-		LastUpdate: now,
-		Metrics:    make(map[string]float64),
-	}
-
-	// 演化子系统指标
-	s.state.metrics.Subsystems["evolution"] = types.SubsystemMetrics{
-		Status:     s.state.status,
-		Health:     1.0,
-		LastUpdate: now,
-		Metrics:    make(map[string]float64),
-	}
-
-	// 元数据子系统指标
-	s.state.metrics.Subsystems["meta"] = types.SubsystemMetrics{
-		Status:     s.state.status,
-		Health:     1.0,
-		LastUpdate: now,
-		Metrics:    make(map[string]float64),
+	for name, component := range s.subsystems() {
+		s.state.metrics.Subsystems[name] = types.SubsystemMetrics{
+			Status:     component.Status(),
+			Health:     component.Health(),
+			LastUpdate: now,
+			Metrics:    make(map[string]float64),
+		}
 	}
 
-	// 监控子系统指标
-	s.state.metrics.Subsystems["monitor"] = types.SubsystemMetrics{
-		Status:     s.state.status,
-		Health:     1.0,
-		LastUpdate: now,
-		Metrics:    make(map[string]float64),
+	// 资源预算节流状态
+	if s.governor != nil {
+		for _, state := range s.governor.States() {
+			if metrics, ok := s.state.metrics.Subsystems[state.Subsystem]; ok {
+				metrics.Throttle = append(metrics.Throttle, state)
+				s.state.metrics.Subsystems[state.Subsystem] = metrics
+			}
+		}
 	}
 
 	// 计算系统健康度
@@ -814,6 +1072,13 @@ func (s *System) calculateSystemHealth() float64 {
 		subsystemScores = append(subsystemScores, metrics.Health)
 	}
 
+	// 外部贡献者与子系统同权计入
+	for _, contributor := range s.healthContributors {
+		if score, ok := types.WeightedHealth(contributor.HealthComponents()); ok {
+			subsystemScores = append(subsystemScores, score)
+		}
+	}
+
 	// 计算子系统平均健康度
 	avgSubsystemHealth := 0.0
 	if len(subsystemScores) > 0 {
@@ -875,14 +1140,11 @@ func (s *System) GetSubsystemStatus() map[string]string {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return map[string]string{
-		"core":      s.core.Status(),
-		"common":    s.common.Status(),
-		"control":   s.control.Status(),
-		"evolution": s.evolution.Status(),
-		"meta":      s.meta.Status(),
-		"monitor":   s.monitor.Status(),
+	status := make(map[string]string)
+	for name, component := range s.subsystems() {
+		status[name] = component.Status()
 	}
+	return status
 }
 
 // GetDependencies 获取系统依赖关系
@@ -919,22 +1181,8 @@ func (s *System) isComponentRunning(name string) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	switch name {
-	case "core":
-		return s.core != nil && s.core.Status() == "running"
-	case "common":
-		return s.common != nil && s.common.Status() == "running"
-	case "control":
-		return s.control != nil && s.control.Status() == "running"
-	case "evolution":
-		return s.evolution != nil && s.evolution.Status() == "running"
-	case "meta":
-		return s.meta != nil && s.meta.Status() == "running"
-	case "monitor":
-		return s.monitor != nil && s.monitor.Status() == "running"
-	default:
-		return false
-	}
+	component, ok := s.subsystems()[name]
+	return ok && component.Status() == "running"
 }
 
 // injectDependencies 注入组件依赖
@@ -1017,7 +1265,11 @@ func (s *System) Coordinate() error {
 func (s *System) RestoreSubsystem(name string) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
+	return s.restoreSubsystem(name)
+}
 
+// restoreSubsystem 恢复子系统, 调用方需持有锁
+func (s *System) restoreSubsystem(name string) error {
 	switch name {
 	case "common":
 		return s.common.Restore(s.ctx)
@@ -1047,13 +1299,26 @@ func (s *System) ListModels() []string {
 }
 
 // TransformModel 执行模型转换
+// 先为所有模型准备转换, 再依次提交; 任一模型失败时回滚已执行的转换
+// ctx携带追踪上下文时(见types.ContextWithTrace), 转换跨度以其为父跨度
 func (s *System) TransformModel(ctx context.Context, pattern model.TransformPattern) error {
+	start := time.Now()
+	err := s.transformModel(ctx, pattern)
+	s.traceTransform(ctx, pattern, start, err)
+	return err
+}
+
+// transformModel 执行模型转换
+func (s *System) transformModel(ctx context.Context, pattern model.TransformPattern) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	if !s.isRunning {
 		return types.ErrNotRunning
 	}
+	if err := s.mode.Mode.TransformError(); err != nil {
+		return err
+	}
 
 	// 获取并验证当前状态
 	state := s.getCurrentState()
@@ -1062,25 +1327,15 @@ func (s *System) TransformModel(ctx context.Context, pattern model.TransformPatt
 	}
 
 	// 执行转换
-	for name, m := range s.models {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-			if err := m.Transform(pattern); err != nil {
-				return fmt.Errorf("failed to transform model %s: %w", name, err)
-			}
-		}
+	if err := s.transformModels(ctx, pattern); err != nil {
+		return err
 	}
 
 	return s.evolution.UpdateState()
 }
 
-// getCurrentState 获取当前系统状态
+// getCurrentState 获取当前系统状态, 调用方需持有锁
 func (s *System) getCurrentState() *model.SystemState {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
-
 	// 转换status为Phase
 	var phase model.Phase
 	switch s.state.status {
@@ -1149,6 +1404,10 @@ func (s *System) GetEnergySystem() *core.EnergySystem {
 
 // PublishEvent 发布系统事件
 func (s *System) PublishEvent(event types.Event) error {
+	if s.Draining() {
+		return errDraining()
+	}
+
 	// 创建SystemEvent
 	sysEvent := types.SystemEvent{
 		ID:        event.ID,
@@ -1195,7 +1454,9 @@ func (s *System) TransformYinYang(pattern model.TransformPattern) error {
 	}
 
 	// 执行转换
-	return yinyang.Transform(pattern)
+	err := yinyang.Transform(pattern)
+	s.observeTransform("yinyang", pattern, err)
+	return err
 }
 
 // GetBaGuaFlow 获取八卦模型
@@ -0,0 +1,161 @@
+// system/tracing.go
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/meta/resonance"
+	"github.com/Corphon/daoflow/system/monitor/trace"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// adaptationTriggerHandler 以模式匹配事件触发策略执行的处理器ID
+const adaptationTriggerHandler = "system.adaptation_trigger"
+
+// startTracing 接入检测、匹配和策略执行的因果追踪(调用方持有锁)
+// 新模式开始一条追踪; 检测事件和首次匹配经事件总线传播, 匹配事件作为下次策略执行的触发原因
+func (s *System) startTracing() {
+	if detector := s.meta.GetDetector(); detector != nil && detector != s.hooks.traced {
+		s.traceDetections(detector)
+		s.hooks.traced = detector
+	}
+
+	if matcher := s.meta.GetMatcher(); matcher != nil && matcher != s.hooks.tracedMatcher {
+		matcher.SetSpanReporter(s.spanReporter())
+		matcher.OnMatch(func(match resonance.MatchState) {
+			s.HandleEvent(types.SystemEvent{
+				Type:      types.EventPatternMatched,
+				Source:    "meta.resonance",
+				Timestamp: match.StartTime,
+				Message:   fmt.Sprintf("pattern %s matched template %s", match.Pattern.ID, match.Template.ID),
+				Priority:  types.PriorityNormal,
+				Data: types.PatternMatch{
+					Match:      match.ID,
+					Pattern:    match.Pattern.ID,
+					Template:   match.Template.ID,
+					Similarity: match.Similarity,
+					Confidence: match.Confidence,
+				},
+				Trace: match.Trace,
+			})
+		})
+		s.hooks.tracedMatcher = matcher
+	}
+
+	if strategy := s.evolution.GetStrategy(); strategy != nil && strategy != s.hooks.tracedStrategy {
+		strategy.SetSpanReporter(s.spanReporter())
+		// 已持有锁, 直接登记处理器而不经Subscribe
+		handler := types.NewEventHandler(adaptationTriggerHandler, []types.EventType{types.EventPatternMatched}, types.PriorityNormal,
+			func(event types.SystemEvent) error {
+				strategy.Trigger(event.Trace)
+				return nil
+			})
+		s.events.handlers[types.EventPatternMatched] = append(s.events.handlers[types.EventPatternMatched], handler)
+		s.hooks.tracedStrategy = strategy
+	}
+}
+
+// traceDetections 为检测器接入跨度上报, 并将检测事件连同追踪上下文发布到事件总线
+func (s *System) traceDetections(detector *emergence.PatternDetector) {
+	detector.SetSpanReporter(s.spanReporter())
+	detector.OnDetectionEvent(func(events []emergence.DetectionEvent) {
+		for _, event := range events {
+			s.HandleEvent(types.SystemEvent{
+				Type:      types.EventPatternDetected,
+				Source:    "meta.emergence",
+				Timestamp: event.Timestamp,
+				Message:   fmt.Sprintf("pattern %s detected", event.PatternID),
+				Priority:  types.PriorityNormal,
+				Data: types.PatternDetection{
+					Pattern:    event.PatternID,
+					Type:       event.Type,
+					Confidence: event.Confidence,
+				},
+				Trace: event.Trace,
+			})
+		}
+	})
+}
+
+// spanReporter 因果跨度的接收方, 追踪器不可用时为nil
+func (s *System) spanReporter() types.SpanReporter {
+	if s.monitor == nil {
+		return nil
+	}
+	if tracker := s.monitor.GetTracker(); tracker != nil {
+		return tracker
+	}
+	return nil
+}
+
+// traceEvent 上报事件经总线的跨度, 返回处理器使用的追踪上下文(调用方持有锁)
+func (s *System) traceEvent(event types.SystemEvent) types.TraceContext {
+	return types.ChildSpan(s.spanReporter(), event.Trace, types.CausalSpan{
+		Name:      "event." + string(event.Type),
+		Subsystem: "system.events",
+		Start:     event.Timestamp,
+		Tags: map[string]string{
+			"event":  event.ID,
+			"source": event.Source,
+		},
+	})
+}
+
+// traceTransform 上报模型转换跨度
+func (s *System) traceTransform(ctx context.Context, pattern model.TransformPattern, start time.Time, err error) {
+	parent, _ := types.TraceFromContext(ctx)
+	span := types.CausalSpan{
+		Name:      "model.transform",
+		Subsystem: "model",
+		Start:     start,
+		Tags: map[string]string{
+			"pattern": fmt.Sprint(pattern),
+		},
+	}
+	if err != nil {
+		span.Error = err.Error()
+	}
+	types.ChildSpan(s.spanReporter(), parent, span)
+}
+
+// CausalChain 重建追踪的端到端因果链, 追踪ID见事件、模式、匹配和策略事件的Trace字段
+func (s *System) CausalChain(ctx context.Context, traceID types.TraceID) ([]trace.CausalStep, error) {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "trace analyzer not available", nil)
+	}
+	return analyzer.CausalChain(ctx, traceID)
+}
+
+// FlameGraph 构建追踪的火焰图数据, 各调用栈的自身耗时和总耗时
+func (s *System) FlameGraph(ctx context.Context, traceID types.TraceID) (*trace.FlameGraph, error) {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "trace analyzer not available", nil)
+	}
+	return analyzer.FlameGraph(ctx, traceID)
+}
+
+// ExportFlameGraph 以折叠栈或JSON导出追踪的火焰图数据
+func (s *System) ExportFlameGraph(ctx context.Context, w io.Writer, format string, traceID types.TraceID) error {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return types.NewSystemError(types.ErrNotFound, "trace analyzer not available", nil)
+	}
+	return analyzer.ExportFlameGraph(ctx, w, format, traceID)
+}
+
+// CompareTraceWindows 比较两个时间窗口内的追踪, 用于配置或适应变更前后的分析
+func (s *System) CompareTraceWindows(ctx context.Context, before, after trace.Window) (*trace.WindowComparison, error) {
+	analyzer := s.monitor.GetTraceAnalyzer()
+	if analyzer == nil {
+		return nil, types.NewSystemError(types.ErrNotFound, "trace analyzer not available", nil)
+	}
+	return analyzer.CompareWindows(ctx, before, after)
+}
@@ -0,0 +1,91 @@
+// system/transform.go
+
+package system
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// 转换阶段
+const (
+	transformPrepare = "prepare"
+	transformCommit  = "commit"
+)
+
+// preparedTransform 已准备的模型转换
+type preparedTransform struct {
+	name string
+	tx   model.TransformTx
+}
+
+// transformModels 两阶段转换所有模型, 调用方需持有锁
+// 只转换已完成接入的模型. 准备阶段失败时不修改任何模型;
+// 提交阶段失败时按相反顺序回滚已提交的模型和失败的模型
+func (s *System) transformModels(ctx context.Context, pattern model.TransformPattern) error {
+	names := s.activeModelNames()
+
+	before := s.modelEnergies(names)
+
+	// 准备阶段
+	prepared := make([]preparedTransform, 0, len(names))
+	for _, name := range names {
+		tx, err := model.PrepareTransform(s.models[name], pattern)
+		if err != nil {
+			s.observeTransform(name, pattern, err)
+			return s.transformError(transformPrepare, name, err, nil, s.rollbackTransforms(prepared))
+		}
+		prepared = append(prepared, preparedTransform{name: name, tx: tx})
+	}
+
+	// 提交阶段
+	for i, p := range prepared {
+		if err := ctx.Err(); err != nil {
+			committed := prepared[:i]
+			return s.transformError(transformCommit, p.name, err, committed, s.rollbackTransforms(committed))
+		}
+
+		err := p.tx.Commit()
+		s.observeTransform(p.name, pattern, err)
+		if err != nil {
+			// 失败的提交可能已部分修改模型, 一并回滚
+			applied := prepared[:i+1]
+			return s.transformError(transformCommit, p.name, err, applied, s.rollbackTransforms(applied))
+		}
+	}
+
+	s.recordModelTransfers(before)
+	return nil
+}
+
+// rollbackTransforms 按相反顺序回滚转换, 某个模型回滚失败不影响其余模型
+func (s *System) rollbackTransforms(prepared []preparedTransform) error {
+	errs := make([]error, 0)
+	for i := len(prepared) - 1; i >= 0; i-- {
+		if err := prepared[i].tx.Rollback(); err != nil {
+			errs = append(errs, types.NewSystemError(types.ErrState, "failed to roll back model transform", err).
+				WithContext("model", prepared[i].name))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// transformError 构造转换失败的诊断错误
+func (s *System) transformError(phase, name string, cause error, rolledBack []preparedTransform, rollbackErr error) error {
+	names := make([]string, 0, len(rolledBack))
+	for _, p := range rolledBack {
+		names = append(names, p.name)
+	}
+
+	err := types.NewSystemError(types.ErrState, "failed to transform model", cause).
+		WithContext("model", name).
+		WithContext("phase", phase).
+		WithContext("rolled_back", names)
+	if rollbackErr != nil {
+		err.WithContext("rollback_error", rollbackErr.Error())
+	}
+	return err
+}
@@ -0,0 +1,55 @@
+// system/tuning.go
+
+package system
+
+import (
+	"math"
+
+	"github.com/Corphon/daoflow/system/control/tuning"
+)
+
+// startIntervalTuning 以系统健康度和队列占用驱动检测、分析间隔的自动调节
+func (s *System) startIntervalTuning() {
+	if detector := s.meta.GetDetector(); detector != nil {
+		if tuner := detector.GetIntervalTuner(); tuner != nil {
+			tuner.SetSignals(func() tuning.Signals {
+				return tuning.Signals{
+					QueueDepth: s.eventQueueDepth(),
+					Health:     s.currentHealth(),
+				}
+			})
+		}
+	}
+
+	if analyzer := s.monitor.GetTraceAnalyzer(); analyzer != nil {
+		if tuner := analyzer.GetIntervalTuner(); tuner != nil {
+			recorder := s.monitor.GetTraceRecorder()
+			tuner.SetSignals(func() tuning.Signals {
+				return tuning.Signals{
+					QueueDepth: math.Max(recorder.QueueDepth(), s.eventQueueDepth()),
+					Health:     s.currentHealth(),
+				}
+			})
+		}
+	}
+}
+
+// eventQueueDepth 事件队列占用比例(0-1)
+func (s *System) eventQueueDepth() float64 {
+	if cap(s.events.queue) == 0 {
+		return 0
+	}
+	return float64(len(s.events.queue)) / float64(cap(s.events.queue))
+}
+
+// currentHealth 当前系统健康度
+func (s *System) currentHealth() float64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// 尚未采集子系统指标时仅按错误数量计算
+	if len(s.state.metrics.Subsystems) == 0 {
+		return 1 - math.Min(float64(len(s.state.errors))*0.1, 0.5)
+	}
+	return s.calculateSystemHealth()
+}
@@ -0,0 +1,143 @@
+// system/types/annotation.go
+
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// TagLabelPrefix 标签在外部输出标签中的键前缀, 如标签"owner"输出为"tag.owner"
+const TagLabelPrefix = "tag."
+
+// Annotations 用户附加在模式和事件上的标注, 随对象保存和导出
+type Annotations struct {
+	Tags  map[string]string `json:"tags,omitempty"`  // 键值标签
+	Notes []Note            `json:"notes,omitempty"` // 自由文本, 按添加顺序
+}
+
+// Note 自由文本标注
+type Note struct {
+	Text   string    `json:"text"`
+	Author string    `json:"author,omitempty"`
+	At     time.Time `json:"at"`
+}
+
+// IsZero 是否没有任何标注
+func (a Annotations) IsZero() bool {
+	return len(a.Tags) == 0 && len(a.Notes) == 0
+}
+
+// Clone 深拷贝, 标注在对象间复制时使用
+func (a Annotations) Clone() Annotations {
+	clone := Annotations{}
+	if len(a.Tags) > 0 {
+		clone.Tags = make(map[string]string, len(a.Tags))
+		for k, v := range a.Tags {
+			clone.Tags[k] = v
+		}
+	}
+	if len(a.Notes) > 0 {
+		clone.Notes = append([]Note(nil), a.Notes...)
+	}
+	return clone
+}
+
+// WithTags 合并标签后的副本, 值为空的标签表示删除该键
+func (a Annotations) WithTags(tags map[string]string) Annotations {
+	clone := a.Clone()
+	for k, v := range tags {
+		if v == "" {
+			delete(clone.Tags, k)
+			continue
+		}
+		if clone.Tags == nil {
+			clone.Tags = make(map[string]string, len(tags))
+		}
+		clone.Tags[k] = v
+	}
+	if len(clone.Tags) == 0 {
+		clone.Tags = nil
+	}
+	return clone
+}
+
+// WithNote 追加自由文本后的副本
+func (a Annotations) WithNote(note Note) Annotations {
+	clone := a.Clone()
+	if note.At.IsZero() {
+		note.At = time.Now()
+	}
+	clone.Notes = append(clone.Notes, note)
+	return clone
+}
+
+// MatchTags 是否满足全部标签条件; 条件值为"*"时只要求存在该键
+func (a Annotations) MatchTags(filter map[string]string) bool {
+	for k, want := range filter {
+		got, ok := a.Tags[k]
+		if !ok || (want != "*" && got != want) {
+			return false
+		}
+	}
+	return true
+}
+
+// Labels 以TagLabelPrefix为前缀的标签, 用于外部输出的标签过滤
+func (a Annotations) Labels() map[string]string {
+	labels := make(map[string]string, len(a.Tags))
+	for k, v := range a.Tags {
+		labels[TagLabelPrefix+k] = v
+	}
+	return labels
+}
+
+// EventQuery 系统事件查询, 各条件之间为与关系, 空条件不限制
+type EventQuery struct {
+	Types  []EventType       `json:"types,omitempty"`
+	Source string            `json:"source,omitempty"`
+	Since  time.Time         `json:"since,omitempty"` // 仅返回此时间之后的事件
+	Tags   map[string]string `json:"tags,omitempty"`  // 标签条件, 见Annotations.MatchTags
+	Limit  int               `json:"limit,omitempty"` // 返回最近的Limit条, 0不限
+}
+
+// Matches 判断事件是否满足查询条件
+func (q EventQuery) Matches(event SystemEvent) bool {
+	if len(q.Types) > 0 {
+		matched := false
+		for _, t := range q.Types {
+			if t == event.Type {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if q.Source != "" && q.Source != event.Source {
+		return false
+	}
+	if !q.Since.IsZero() && !event.Timestamp.After(q.Since) {
+		return false
+	}
+	return event.Annotations.MatchTags(q.Tags)
+}
+
+// ParseTagFilter 解析"key=value"形式的标签条件, 只有key时等同"key=*"
+func ParseTagFilter(exprs []string) map[string]string {
+	if len(exprs) == 0 {
+		return nil
+	}
+	filter := make(map[string]string, len(exprs))
+	for _, expr := range exprs {
+		k, v, ok := strings.Cut(expr, "=")
+		if !ok {
+			v = "*"
+		}
+		if k = strings.TrimSpace(k); k != "" {
+			filter[k] = v
+		}
+	}
+	return filter
+}
@@ -5,6 +5,7 @@ package types
 import (
 	"time"
 
+	"github.com/Corphon/daoflow/core"
 	"github.com/Corphon/daoflow/model"
 )
 
@@ -63,6 +64,21 @@ type MetaConfig struct {
 		} `json:"coupling"`
 	} `json:"field"`
 
+	// 耦合强度阈值事件
+	CouplingThresholds CouplingThresholdConfig `json:"coupling_thresholds"`
+
+	// 多场联邦的场间耦合
+	Federation FederationConfig `json:"federation"`
+
+	// 场网格几何: 尺寸、边界条件、邻接方式和维数
+	Geometry core.Geometry `json:"geometry"`
+
+	// 场张量计算后端: go或simd, 后端不可用时回退到go
+	FieldBackend string `json:"field_backend"`
+
+	// 使用稀疏表示的场组件: scalar、vector、metric或quantum
+	SparseComponents []string `json:"sparse_components"`
+
 	// 量子配置
 	Quantum struct {
 		InitialState    []complex128  `json:"initial_state"`    // 初始量子态
@@ -84,6 +100,18 @@ type MetaConfig struct {
 		MinStrength       float64       `json:"min_strength"`       // 最小强度阈值
 		MaxPatterns       int           `json:"max_patterns"`       // 最大模式数
 
+		// 检测间隔自动调节
+		Tuning IntervalTuningConfig `json:"tuning"`
+
+		// 演化历史和检测历史的分层保留
+		Retention RetentionConfig `json:"retention"`
+
+		// 场状态快照日志路径, 每次检测取得的场状态以每行一条JSON追加写入, 供回填使用; 为空时不记录
+		Journal string `json:"journal"`
+
+		// 模式处理优先级, 决定检测通知、共振匹配和共振放大各周期的处理顺序和配额
+		Priority PriorityConfig `json:"priority"`
+
 		// 模式配置
 		Patterns struct {
 			MinLifetime        time.Duration `json:"min_lifetime"`        // 最小生命周期
@@ -104,7 +132,29 @@ type MetaConfig struct {
 			MinCoherence  float64 `json:"min_coherence"`   // 最小相干度
 			MaxPhaseShift float64 `json:"max_phase_shift"` // 最大相位偏移
 		} `json:"conditions"`
+
+		// 模板匹配的按模式类型相似度阈值
+		Thresholds MatchThresholdConfig `json:"thresholds"`
 	} `json:"resonance"`
+
+	// 观测注入配置
+	Ingest struct {
+		MaxBatchSize int           `json:"max_batch_size"` // 单批最大观测数
+		DecayFactor  float64       `json:"decay_factor"`   // 观测能量衰减因子
+		DetectEvery  int           `json:"detect_every"`   // 每N批触发一次检测
+		MaxLag       time.Duration `json:"max_lag"`        // 最大允许观测延迟
+	} `json:"ingest"`
+
+	// 场可视化配置
+	Visualization VisualizationConfig `json:"visualization"`
+}
+
+// VisualizationConfig 场可视化配置
+type VisualizationConfig struct {
+	Enabled     bool          `json:"enabled"`      // 是否记录场演化帧
+	Interval    time.Duration `json:"interval"`     // 采样间隔
+	HistorySize int           `json:"history_size"` // 保留帧数
+	MaxCells    int           `json:"max_cells"`    // 单帧最大网格单元数
 }
 
 // EvoConfig 演化系统配置
@@ -143,6 +193,24 @@ type EvoConfig struct {
 	//适应策略配置
 	Strategy *StrategyConfig `json:"strategy"`
 
+	// 外部策略扩展
+	Extensions []ExtensionSpec `json:"extensions"`
+
+	// 五行能量调度
+	Scheduler *WuXingSchedulerConfig `json:"scheduler"`
+
+	// 因果发现
+	Causal *CausalConfig `json:"causal"`
+
+	// 策略选择的多臂老虎机探索
+	Bandit *BanditConfig `json:"bandit"`
+
+	// 策略变更审计
+	Audit *AuditConfig `json:"audit"`
+
+	// 跨实例、跨租户共享学习统计和知识时的差分隐私保护
+	Privacy *PrivacyConfig `json:"privacy"`
+
 	// 历史记录配置
 	MaxHistorySize int `json:"max_history_size"` // 最大历史记录大小
 
@@ -152,6 +220,135 @@ type EvoConfig struct {
 	} `json:"target"`
 }
 
+// WuXingSchedulerConfig 五行能量调度配置
+type WuXingSchedulerConfig struct {
+	Enabled        bool               `json:"enabled"`         // 是否启用
+	Period         time.Duration      `json:"period"`          // 完整循环周期, 每步调度一个元素
+	GenerationRate float64            `json:"generation_rate"` // 相生时源元素能量的转出比例
+	OvercomingRate float64            `json:"overcoming_rate"` // 相克时源元素能量的克制比例
+	DefaultCap     float64            `json:"default_cap"`     // 元素能量上限
+	Caps           map[string]float64 `json:"caps"`            // 按元素名称覆盖上限
+	MaxBias        float64            `json:"max_bias"`        // 路由权重偏置上限
+}
+
+// CausalConfig 模式与适应动作的因果发现配置
+type CausalConfig struct {
+	Enabled      bool          `json:"enabled"`      // 是否启用
+	Interval     time.Duration `json:"interval"`     // 因果图刷新间隔
+	Window       time.Duration `json:"window"`       // 分析窗口
+	Step         time.Duration `json:"step"`         // 序列对齐步长
+	MaxLag       int           `json:"max_lag"`      // 格兰杰检验的滞后阶数
+	Significance float64       `json:"significance"` // 显著性水平, p值低于该值的影响计入因果图
+}
+
+// BanditConfig 策略选择的多臂老虎机配置, 探索率取自适应学习配置的ExplorationRate
+type BanditConfig struct {
+	Policy      string  `json:"policy"`       // 选择策略: epsilon_greedy, ucb; 空表示不启用, 执行所有适用策略
+	UCBConstant float64 `json:"ucb_constant"` // UCB置信上界的探索系数
+}
+
+// PrivacyConfig 学习统计和知识共享的差分隐私配置, 只作用于共享导出, 不影响系统快照
+type PrivacyConfig struct {
+	Enabled    bool    `json:"enabled"`
+	Epsilon    float64 `json:"epsilon"`     // 每次导出消耗的隐私预算ε, 越小噪声越大
+	Budget     float64 `json:"budget"`      // 累计隐私预算上限, 超出后拒绝导出; 0表示不限
+	MinRecords int     `json:"min_records"` // 经验数(含噪)低于该值时不发布比率类统计, 为0时使用默认值
+	KAnonymity int     `json:"k_anonymity"` // 知识上下文字段的取值至少出现在K个知识单元中才保留, 不大于1时不过滤
+}
+
+// AuditConfig 策略变更审计日志配置
+type AuditConfig struct {
+	Capacity int    `json:"capacity"` // 内存中保留的记录数
+	Path     string `json:"path"`     // 持久化文件路径, 每条记录追加一行JSON; 空表示只保留在内存中
+}
+
+// EncryptionConfig 静态数据加密配置, 用于系统快照归档、场状态快照日志和审计日志文件
+// 新写入的数据以Active密钥加密; 其余密钥只用于解密轮换前写入的数据
+type EncryptionConfig struct {
+	Active string          `json:"active"` // 当前加密密钥ID
+	Keys   []EncryptionKey `json:"keys"`   // 密钥, 未列出的ID可由密钥提供者(如KMS)按需获取
+}
+
+// EncryptionKey AES-256密钥, Key与Env二选一
+type EncryptionKey struct {
+	ID  string `json:"id"`  // 密钥ID, 随密文保存
+	Key string `json:"key"` // base64编码的32字节密钥
+	Env string `json:"env"` // 保存base64密钥的环境变量名
+}
+
+// RetentionConfig 演化历史的分层保留配置, 预算按单个模式计, 零值字段使用默认值
+// 最近窗口内保留完整分辨率, 更早的状态按分辨率降采样为摘要, 超出摘要预算的部分并入聚合
+type RetentionConfig struct {
+	Recent        time.Duration              `json:"recent"`         // 完整分辨率保留的最近窗口
+	RecentStates  int                        `json:"recent_states"`  // 完整分辨率状态数上限
+	Resolution    time.Duration              `json:"resolution"`     // 摘要的降采样时间粒度
+	SummaryStates int                        `json:"summary_states"` // 摘要状态数上限
+	Types         map[string]RetentionConfig `json:"types"`          // 按模式类型覆盖的预算
+}
+
+// PriorityConfig 模式处理优先级配置, 未启用时按原有顺序处理全部模式
+// 模式归入首个匹配的类别, 类别按列出顺序从高到低处理, 超出类别配额的模式推迟到后续周期
+type PriorityConfig struct {
+	Enabled      bool            `json:"enabled"`
+	Classes      []PriorityClass `json:"classes"`       // 优先级类别, 从高到低
+	DefaultQuota int             `json:"default_quota"` // 未匹配任何类别的模式每周期配额, 0表示不限
+	MaxWait      int             `json:"max_wait"`      // 连续推迟该周期数后优先处理且不受配额限制, 为0时使用默认值
+}
+
+// PriorityClass 优先级类别, 类型和强度条件同时满足时匹配
+type PriorityClass struct {
+	Name        string   `json:"name"`
+	Types       []string `json:"types"`        // 模式类型, 为空时不限类型
+	MinStrength float64  `json:"min_strength"` // 最小模式强度
+	Quota       int      `json:"quota"`        // 每周期最多处理的模式数, 0表示不限
+}
+
+// MatchThresholdConfig 模式与模板匹配的相似度阈值配置, 未配置的类型使用匹配器的全局最小相似度
+// 不同类型模式的相似度分布差异较大, 可按类型设置阈值, 或按近期相似度分布的分位数自动校准
+type MatchThresholdConfig struct {
+	Types       map[string]float64     `json:"types"`       // 按模式类型的最小相似度(0-1)
+	Calibration MatchCalibrationConfig `json:"calibration"` // 自动校准
+}
+
+// MatchCalibrationConfig 阈值自动校准配置
+// 启用后各类型样本数达到MinSamples前使用配置的阈值, 之后取近期相似度的Percentile分位数
+type MatchCalibrationConfig struct {
+	Enabled    bool    `json:"enabled"`
+	Percentile float64 `json:"percentile"`  // 阈值取相似度分布的分位数(0-1), 为零时取0.75
+	Window     int     `json:"window"`      // 每种类型保留的近期相似度样本数, 为零时取1000
+	MinSamples int     `json:"min_samples"` // 开始校准所需的样本数, 为零时取100
+	Floor      float64 `json:"floor"`       // 校准阈值下限
+	Ceiling    float64 `json:"ceiling"`     // 校准阈值上限, 为零时取1
+}
+
+// 扩展格式
+const (
+	ExtensionGoPlugin = "goplugin" // Go插件(plugin.Open)
+	ExtensionWasm     = "wasm"     // WASM模块
+)
+
+// 扩展类型
+const (
+	ExtensionStrategy   = "strategy"   // 适应策略, 返回执行动作
+	ExtensionClassifier = "classifier" // 分类器, 返回分类标签
+)
+
+// ExtensionSpec 外部扩展定义
+type ExtensionSpec struct {
+	Name    string        `json:"name"`    // 扩展名称
+	Kind    string        `json:"kind"`    // 扩展类型: strategy, classifier
+	Format  string        `json:"format"`  // 扩展格式: goplugin, wasm
+	Path    string        `json:"path"`    // 插件或模块文件路径
+	Timeout time.Duration `json:"timeout"` // 单次评估超时
+
+	// WASM沙箱限制
+	Sandbox struct {
+		MemoryPages    uint32 `json:"memory_pages"`     // 最大内存页数(64KiB/页)
+		MaxInputBytes  int    `json:"max_input_bytes"`  // 最大输入字节数
+		MaxOutputBytes int    `json:"max_output_bytes"` // 最大输出字节数
+	} `json:"sandbox"`
+}
+
 // StrategyConfig 适应策略配置
 type StrategyConfig struct {
 	// 基础配置
@@ -265,6 +462,15 @@ type ControlConfig struct {
 		MinAlloc     float64 `json:"min_alloc"`     // 最小分配量
 		ReserveRatio float64 `json:"reserve_ratio"` // 预留比例
 	} `json:"resource"`
+
+	// 阴阳平衡控制
+	Balance BalanceConfig `json:"balance"`
+
+	// 参数时间调制
+	Modulation ModulationConfig `json:"modulation"`
+
+	// 子系统看门狗
+	Watchdog WatchdogConfig `json:"watchdog"`
 }
 
 // MonitorConfig 监控系统配置
@@ -309,6 +515,18 @@ type MonitorConfig struct {
 	// 告警配置
 	Alert AlertConfig `json:"alert"`
 
+	// 基线学习配置
+	Baseline BaselineConfig `json:"baseline"`
+
+	// 异常关联配置
+	Correlation CorrelationConfig `json:"correlation"`
+
+	// 能量流动记录配置
+	EnergyFlow EnergyFlowConfig `json:"energy_flow"`
+
+	// 分布漂移检测配置
+	Drift DriftConfig `json:"drift"`
+
 	// 健康检查配置
 	Health struct {
 		CheckInterval time.Duration `json:"check_interval"` // 检查间隔
@@ -332,6 +550,20 @@ type MonitorConfig struct {
 		FlushInterval time.Duration `json:"flush_interval"` // 刷新间隔
 		StoragePath   string        `json:"storage_path"`   // 存储路径
 
+		// 采样策略
+		Sampling SamplingConfig `json:"sampling"`
+
+		// 分析间隔及其自动调节
+		AnalysisInterval time.Duration        `json:"analysis_interval"`
+		Tuning           IntervalTuningConfig `json:"tuning"`
+
+		// 分析结果缓存和汇总
+		Cache  TraceCacheConfig  `json:"cache"`
+		Rollup TraceRollupConfig `json:"rollup"`
+
+		// 分析阶段的时间预算
+		StageTimeouts StageTimeoutConfig `json:"stage_timeouts"`
+
 		// 过滤器配置
 		Filters struct {
 			MinDuration time.Duration `json:"min_duration"` // 最小持续时间
@@ -410,6 +642,78 @@ type MonitorConfig struct {
 	} `json:"monitoring"`
 }*/
 
+// DashboardConfig 仪表盘配置
+type DashboardConfig struct {
+	Addr           string        `json:"addr"`            // 监听地址
+	SampleInterval time.Duration `json:"sample_interval"` // 健康和能量采样间隔
+	HistorySize    int           `json:"history_size"`    // 保留的采样点数
+	RecentItems    int           `json:"recent_items"`    // 保留的最近异常和决策数
+}
+
+// SupervisorConfig 长期运行协程的监管配置
+type SupervisorConfig struct {
+	InitialBackoff time.Duration `json:"initial_backoff"` // 首次重启等待时间
+	MaxBackoff     time.Duration `json:"max_backoff"`     // 最长重启等待时间
+	MaxRestarts    int           `json:"max_restarts"`    // 连续重启上限, 超过后升级并停止重启
+	StableAfter    time.Duration `json:"stable_after"`    // 稳定运行多久后清零连续崩溃计数
+}
+
+// FaultInjectionConfig 故障注入配置, 用于韧性测试
+type FaultInjectionConfig struct {
+	Enabled bool        `json:"enabled"` // 允许注入故障; 以faultinject构建标签编译时总是允许
+	Faults  []FaultSpec `json:"faults"`  // 启动时注入的故障
+}
+
+// FaultSpec 注入点上的故障
+// 命中注入点时依次施加延迟、panic或返回错误, 可组合使用
+type FaultSpec struct {
+	Point  string        `json:"point"`  // 注入点
+	Target string        `json:"target"` // 注入点内的目标, 如子系统名称; 为空时匹配全部
+	Error  string        `json:"error"`  // 返回的错误信息, 为空时不返回错误
+	Delay  time.Duration `json:"delay"`  // 命中时的延迟
+	Panic  bool          `json:"panic"`  // 命中时panic
+	After  int           `json:"after"`  // 跳过前After次命中
+	Count  int           `json:"count"`  // 最多触发次数, 0为不限
+}
+
+// IntegrationConfig 外部集成输出配置
+type IntegrationConfig struct {
+	QueueSize int          `json:"queue_size"` // 输出队列大小
+	Sinks     []SinkConfig `json:"sinks"`      // 输出端配置
+}
+
+// SinkConfig 输出端配置
+type SinkConfig struct {
+	Name    string            `json:"name"`    // 输出端名称
+	Type    string            `json:"type"`    // 类型: webhook, kafka, file
+	URL     string            `json:"url"`     // webhook地址
+	Topic   string            `json:"topic"`   // kafka主题
+	Path    string            `json:"path"`    // 文件路径
+	Headers map[string]string `json:"headers"` // 附加请求头
+	Timeout time.Duration     `json:"timeout"` // 单次投递超时
+
+	// webhook请求体模板(text/template), 设置后每条输出单独投递
+	Template string `json:"template"`
+	// webhook签名密钥, 非空时请求带HMAC-SHA256签名
+	Secret string `json:"secret"`
+
+	// 过滤规则
+	Filter struct {
+		Kinds      []string          `json:"kinds"`      // 输出类型: pattern, decision, anomaly
+		Namespaces []string          `json:"namespaces"` // 命名空间
+		Types      []string          `json:"types"`      // 模式类型等(标签type)
+		MinScore   float64           `json:"min_score"`  // 最小分值
+		Labels     map[string]string `json:"labels"`     // 标签匹配
+	} `json:"filter"`
+
+	// 重试策略
+	Retry struct {
+		MaxAttempts    int           `json:"max_attempts"`    // 最大尝试次数
+		InitialBackoff time.Duration `json:"initial_backoff"` // 初始退避
+		MaxBackoff     time.Duration `json:"max_backoff"`     // 最大退避
+	} `json:"retry"`
+}
+
 // CommonConfig 公共系统配置
 type CommonConfig struct {
 	// 基础配置
@@ -441,6 +745,9 @@ type CommonConfig struct {
 		SampleRate    float64 `json:"sample_rate"`    // 采样率
 		ErrorRate     float64 `json:"error_rate"`     // 错误率阈值
 	} `json:"monitor"`
+
+	// 定时任务配置
+	Scheduler SchedulerConfig `json:"scheduler"`
 }
 
 // PatternConfig 模式生成配置
@@ -570,6 +877,37 @@ type RecognitionConfig struct {
 		MaxHistory     int     `json:"max_history"`     // 最大历史记录
 		MinAccuracy    float64 `json:"min_accuracy"`    // 最小准确度
 	} `json:"statistics"`
+
+	// 签名比较的相似度度量
+	Similarity SignatureSimilarityConfig `json:"similarity"`
+
+	// 已识别模式的在线聚类
+	Families PatternFamilyConfig `json:"families"`
+}
+
+// PatternFamilyConfig 已识别模式的在线聚类配置
+// 同类型的模式按签名特征向量的余弦相似度归入最近的族, 低于Threshold时新建族, 族中心按流式均值更新
+type PatternFamilyConfig struct {
+	Enabled        bool    `json:"enabled"`
+	Threshold      float64 `json:"threshold"`       // 加入族所需的与族中心的最小相似度(0-1], 为零时取0.9
+	MergeThreshold float64 `json:"merge_threshold"` // 两个族中心相似度达到该值时合并, 为零时取0.97
+	MaxFamilies    int     `json:"max_families"`    // 族数上限, 达到上限后归入最近的族, 为零时取100
+	Horizon        int     `json:"horizon"`         // 族中心更新的有效样本数上限, 越小越跟随近期成员, 为零时取100
+}
+
+// 签名相似度度量
+const (
+	SimilarityHeuristic   = "heuristic"   // 按组件类型、角色、权重等规则加权, 默认
+	SimilarityCosine      = "cosine"      // 特征向量的余弦相似度
+	SimilarityEuclidean   = "euclidean"   // 加权欧氏距离
+	SimilarityMahalanobis = "mahalanobis" // 以近期签名学习协方差的马氏距离
+)
+
+// SignatureSimilarityConfig 签名相似度度量配置
+type SignatureSimilarityConfig struct {
+	Metrics map[string]string  `json:"metrics"` // 按签名维度(component、structure、dynamics、context)选择的度量, 未配置的维度使用heuristic
+	Weights map[string]float64 `json:"weights"` // euclidean度量的特征权重, 未列出的特征权重为1
+	Window  int                `json:"window"`  // mahalanobis度量学习协方差的近期签名数, 为零时取500
 }
 
 // AdaptationConfig 适应性配置
@@ -597,6 +935,11 @@ type AdaptationConfig struct {
 		MaxIterations   int     `json:"max_iterations"`   // 最大迭代次数
 		MinAccuracy     float64 `json:"min_accuracy"`     // 最小准确率
 		ValidationRatio float64 `json:"validation_ratio"` // 验证比例
+		PruneThreshold  float64 `json:"prune_threshold"`  // 权重绝对值和置换重要性都低于该值的特征被剪除
+		Patience        int     `json:"patience"`         // 早停前允许验证损失不改善的轮数
+		MinDelta        float64 `json:"min_delta"`        // 验证损失视为改善的最小降幅
+		Online          bool    `json:"online"`           // 在线学习: 经验到达时增量更新权重, 不再整批重新训练
+		OnlineBatch     int     `json:"online_batch"`     // 在线学习的小批量大小
 	} `json:"model"`
 
 	// 知识配置
@@ -614,6 +957,46 @@ type AdaptationConfig struct {
 		OptimizeInterval  time.Duration `json:"optimize_interval"`  // 优化间隔
 		AdaptiveThreshold float64       `json:"adaptive_threshold"` // 适应阈值
 	} `json:"strategy"`
+
+	// 强化学习配置
+	Reinforcement ReinforcementConfig `json:"reinforcement"`
+
+	// 规则生成限制
+	RuleGeneration RuleGenerationConfig `json:"rule_generation"`
+
+	// 经验缓冲区满时的保留策略
+	ExperienceRetention ExperienceRetentionConfig `json:"experience_retention"`
+}
+
+// 经验保留策略
+const (
+	ExperienceRetentionOldest     = "oldest"     // 移除最早的经验
+	ExperienceRetentionFeedback   = "feedback"   // 移除反馈最低的经验, 反馈低于全部已有经验的新经验不保留
+	ExperienceRetentionStratified = "stratified" // 从经验最多的类型中移除最早的经验, 使各类型均衡
+	ExperienceRetentionReservoir  = "reservoir"  // 蓄水池抽样, 保留的经验是全部到达经验的均匀样本
+)
+
+// ExperienceRetentionConfig 经验缓冲区满时的保留策略, 也用于内存软上限驱逐经验
+type ExperienceRetentionConfig struct {
+	Policy string `json:"policy"` // 保留策略, 为空时为ExperienceRetentionOldest
+	Seed   int64  `json:"seed"`   // 蓄水池抽样的随机种子, 为零时随机取种
+}
+
+// RuleGenerationConfig 学习周期生成规则的限制, 与已有规则条件和动作相同的规则总是被丢弃
+type RuleGenerationConfig struct {
+	MaxPerCycle int           `json:"max_per_cycle"` // 每个学习周期最多注册的新规则数, 0表示不限制
+	Cooldown    time.Duration `json:"cooldown"`      // 同一目标两次生成规则的最小间隔, 0表示不限制
+}
+
+// ReinforcementConfig 适应学习的强化学习模式配置, 学习率和探索率取自Learning配置
+// 系统状态特征为观测, 策略参数的增减为动作, 适应目标的达成度为奖励
+type ReinforcementConfig struct {
+	Enabled  bool    `json:"enabled"`  // 是否以强化学习替代经验模式提取来调整策略
+	Policy   string  `json:"policy"`   // 策略模型: tabular, neural
+	Bins     int     `json:"bins"`     // tabular: 每个观测特征的离散化区间数
+	Hidden   int     `json:"hidden"`   // neural: 隐藏层单元数
+	Step     float64 `json:"step"`     // 每个动作对策略参数的相对调整幅度
+	Discount float64 `json:"discount"` // 未来奖励折扣因子
 }
 
 // MutationConfig 突变系统配置
@@ -401,3 +401,81 @@ type ResolutionStep struct {
 }
 
 //------------------------------------------------
+
+// IntervalTuningConfig 周期间隔自动调节配置
+type IntervalTuningConfig struct {
+	Enabled           bool          `json:"enabled"`            // 是否启用
+	MinInterval       time.Duration `json:"min_interval"`       // 最小间隔
+	MaxInterval       time.Duration `json:"max_interval"`       // 最大间隔
+	TargetUtilization float64       `json:"target_utilization"` // 目标占用率: 处理耗时/间隔
+	QueueHighWater    float64       `json:"queue_high_water"`   // 队列占用高水位(0-1)
+	HealthFloor       float64       `json:"health_floor"`       // 健康度下限, 低于该值视为过载
+	BackoffFactor     float64       `json:"backoff_factor"`     // 过载时间隔放大倍数
+	RecoveryFactor    float64       `json:"recovery_factor"`    // 空闲时间隔缩小倍数
+}
+
+// BalanceConfig 阴阳平衡闭环控制配置
+type BalanceConfig struct {
+	Enabled      bool          `json:"enabled"`       // 是否启用
+	Interval     time.Duration `json:"interval"`      // 检查间隔
+	Band         float64       `json:"band"`          // 允许的失衡带宽: |阳-阴|/(阴+阳)
+	HarmonyFloor float64       `json:"harmony_floor"` // 和谐度下限, 低于该值执行平衡转换
+	Cooldown     time.Duration `json:"cooldown"`      // 两次修正的最小间隔
+	MaxActions   int           `json:"max_actions"`   // 窗口内最多修正次数
+	Window       time.Duration `json:"window"`        // 限速窗口
+}
+
+// WatchdogConfig 子系统看门狗配置
+// 子系统未运行或健康度低于下限的检查连续达到阈值后触发恢复, 恢复按指数退避重试, 超过重试上限后进入降级
+type WatchdogConfig struct {
+	Enabled          bool          `json:"enabled"`           // 是否启用
+	Interval         time.Duration `json:"interval"`          // 检查间隔
+	MinHealth        float64       `json:"min_health"`        // 健康度下限
+	FailureThreshold int           `json:"failure_threshold"` // 触发恢复的连续异常检查次数
+	InitialBackoff   time.Duration `json:"initial_backoff"`   // 首次重试等待时间
+	MaxBackoff       time.Duration `json:"max_backoff"`       // 最长重试等待时间
+	MaxRetries       int           `json:"max_retries"`       // 恢复重试上限, 超过后进入降级
+}
+
+// ModulationConfig 参数时间调制配置
+// 被调制参数在每次刷新时取 基准值 × 曲线倍率, 曲线按调制目标名配置
+type ModulationConfig struct {
+	Enabled  bool                   `json:"enabled"`  // 是否启用
+	Interval time.Duration          `json:"interval"` // 刷新间隔
+	Curves   map[string]CurveConfig `json:"curves"`   // 调制目标 -> 曲线
+}
+
+// 调制曲线类型
+const (
+	CurveDaily    = "daily"    // 日周期余弦曲线
+	CurveGanZhi   = "ganzhi"   // 干支周期曲线
+	CurveSchedule = "schedule" // 用户自定义分段线性曲线
+)
+
+// CurveConfig 调制曲线配置, 曲线值为作用于基准值的倍率
+type CurveConfig struct {
+	Kind string `json:"kind"` // 曲线类型
+
+	// daily: 1 + Amplitude·cos(2π(h-PeakHour)/24), h为本地时间的小时数
+	Amplitude float64 `json:"amplitude"`
+	PeakHour  float64 `json:"peak_hour"`
+
+	// ganzhi: Cycle取hour/day/year, Factors长度为10(按天干)、12(按地支)或60(按六十甲子)
+	Cycle   string    `json:"cycle"`
+	Factors []float64 `json:"factors"`
+
+	// schedule: 以Anchor为起点按Period循环, 在Points间线性插值
+	Period time.Duration   `json:"period"`
+	Anchor time.Time       `json:"anchor"` // 零值为Unix纪元
+	Points []SchedulePoint `json:"points"`
+
+	// 倍率范围, 均为0时不限制
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+}
+
+// SchedulePoint 自定义曲线节点
+type SchedulePoint struct {
+	Offset time.Duration `json:"offset"` // 周期内偏移
+	Factor float64       `json:"factor"` // 倍率
+}
@@ -0,0 +1,9 @@
+// system/types/coupling.go
+
+package types
+
+// CouplingThresholdConfig 场耦合强度阈值
+type CouplingThresholdConfig struct {
+	Levels     []float64 `json:"levels"`     // 强度阈值, 耦合强度越过阈值时发出事件; 为空时使用Field.Coupling.Threshold
+	Hysteresis float64   `json:"hysteresis"` // 滞回宽度, 强度须越过阈值加减该值才视为越过, 避免在阈值附近反复触发
+}
@@ -0,0 +1,235 @@
+// system/types/dedup.go
+
+package types
+
+import (
+	"sync"
+	"time"
+)
+
+// 去重默认参数
+const (
+	DefaultDedupWindow  = 5 * time.Minute
+	DefaultDedupMaxKeys = 10000
+)
+
+// DeliveryMode 处理器的投递语义, 只对携带幂等键的事件生效
+type DeliveryMode int
+
+const (
+	// DeliveryAtLeastOnce 每次发布都投递, 包括窗口内同一幂等键的重复发布, 处理器需自行幂等
+	DeliveryAtLeastOnce DeliveryMode = iota
+	// DeliveryExactlyOnce 窗口内同一幂等键最多成功处理一次; 处理失败时释放该键, 生产方重试时再次投递.
+	// 去重状态只在内存中保留窗口时长, 超出窗口或重启后的重复发布仍会投递
+	DeliveryExactlyOnce
+)
+
+// String 投递语义名称
+func (m DeliveryMode) String() string {
+	switch m {
+	case DeliveryExactlyOnce:
+		return "exactly_once"
+	default:
+		return "at_least_once"
+	}
+}
+
+// DeliveryModeHandler 声明投递语义的事件处理器
+// 未实现该接口的处理器为至少一次投递
+type DeliveryModeHandler interface {
+	EventHandler
+	GetDeliveryMode() DeliveryMode
+}
+
+// deliveryModeHandler 为处理器附加投递语义
+type deliveryModeHandler struct {
+	EventHandler
+	mode DeliveryMode
+}
+
+// GetDeliveryMode 获取投递语义
+func (h *deliveryModeHandler) GetDeliveryMode() DeliveryMode {
+	return h.mode
+}
+
+// WithDeliveryMode 包装处理器并声明投递语义, 取消订阅时需使用返回的处理器
+func WithDeliveryMode(handler EventHandler, mode DeliveryMode) EventHandler {
+	return &deliveryModeHandler{EventHandler: handler, mode: mode}
+}
+
+// HandlerDeliveryMode 处理器的投递语义
+func HandlerDeliveryMode(handler EventHandler) DeliveryMode {
+	if h, ok := handler.(DeliveryModeHandler); ok {
+		return h.GetDeliveryMode()
+	}
+	return DeliveryAtLeastOnce
+}
+
+// DedupConfig 事件幂等去重配置
+type DedupConfig struct {
+	Window  time.Duration `json:"window"`   // 去重窗口, 为0时使用默认值
+	MaxKeys int           `json:"max_keys"` // 窗口内最多保留的键数, 超出时淘汰最早的键; 为0时使用默认值
+}
+
+// DedupStats 去重统计
+type DedupStats struct {
+	Window     time.Duration `json:"window"`
+	Keys       int           `json:"keys"`       // 窗口内的发布幂等键数
+	Duplicates int64         `json:"duplicates"` // 窗口内重复的发布次数
+	Skipped    int64         `json:"skipped"`    // 对恰好一次处理器跳过的投递次数
+	Evicted    int64         `json:"evicted"`    // 因键数上限提前淘汰的键数
+}
+
+// EventDeduplicator 按事件幂等键在时间窗口内去重
+// 重复的发布标记为Duplicate, 仍投递给至少一次处理器; 恰好一次处理器按处理器和幂等键记录成功处理
+type EventDeduplicator struct {
+	mu        sync.Mutex
+	config    DedupConfig
+	published *keyWindow
+	delivered *keyWindow
+
+	duplicates int64
+	skipped    int64
+}
+
+// NewEventDeduplicator 创建事件去重器
+func NewEventDeduplicator(config DedupConfig) *EventDeduplicator {
+	if config.Window <= 0 {
+		config.Window = DefaultDedupWindow
+	}
+	if config.MaxKeys <= 0 {
+		config.MaxKeys = DefaultDedupMaxKeys
+	}
+	return &EventDeduplicator{
+		config:    config,
+		published: newKeyWindow(config),
+		delivered: newKeyWindow(config),
+	}
+}
+
+// Publish 记录事件的发布, 窗口内已发布过同一幂等键时返回标记为重复的事件
+// 没有幂等键的事件原样返回
+func (d *EventDeduplicator) Publish(event SystemEvent, now time.Time) SystemEvent {
+	if event.IdempotencyKey == "" {
+		return event
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !d.published.add(event.IdempotencyKey, now) {
+		event.Duplicate = true
+		d.duplicates++
+	}
+	return event
+}
+
+// Deliver 按处理器的投递语义投递事件, 跳过投递时返回nil
+// 恰好一次处理器在处理前占用处理器和幂等键, 处理失败时释放, 以免并发的重复发布同时处理
+func (d *EventDeduplicator) Deliver(event SystemEvent, handler EventHandler, now time.Time) error {
+	if event.IdempotencyKey == "" || HandlerDeliveryMode(handler) != DeliveryExactlyOnce {
+		return handler.HandleEvent(event)
+	}
+
+	key := handler.GetHandlerID() + "\x00" + event.IdempotencyKey
+	d.mu.Lock()
+	claimed := d.delivered.add(key, now)
+	if !claimed {
+		d.skipped++
+	}
+	d.mu.Unlock()
+	if !claimed {
+		return nil
+	}
+
+	err := handler.HandleEvent(event)
+	if err != nil {
+		d.mu.Lock()
+		d.delivered.remove(key)
+		d.mu.Unlock()
+	}
+	return err
+}
+
+// Stats 获取去重统计
+func (d *EventDeduplicator) Stats(now time.Time) DedupStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.published.prune(now)
+	return DedupStats{
+		Window:     d.config.Window,
+		Keys:       len(d.published.seen),
+		Duplicates: d.duplicates,
+		Skipped:    d.skipped,
+		Evicted:    d.published.evicted + d.delivered.evicted,
+	}
+}
+
+// keyWindow 时间窗口内出现过的键, 按首次出现时间先进先出淘汰(调用方持有锁)
+type keyWindow struct {
+	window  time.Duration
+	maxKeys int
+	seen    map[string]time.Time
+	order   []keyEntry
+	evicted int64
+}
+
+// keyEntry 键的首次出现时间
+type keyEntry struct {
+	key string
+	at  time.Time
+}
+
+func newKeyWindow(config DedupConfig) *keyWindow {
+	return &keyWindow{
+		window:  config.Window,
+		maxKeys: config.MaxKeys,
+		seen:    make(map[string]time.Time),
+	}
+}
+
+// add 记录键, 窗口内已存在时返回false
+func (w *keyWindow) add(key string, now time.Time) bool {
+	w.prune(now)
+	if _, exists := w.seen[key]; exists {
+		return false
+	}
+
+	w.seen[key] = now
+	w.order = append(w.order, keyEntry{key: key, at: now})
+	for len(w.seen) > w.maxKeys {
+		w.evictOldest()
+		w.evicted++
+	}
+	return true
+}
+
+// remove 释放键
+func (w *keyWindow) remove(key string) {
+	delete(w.seen, key)
+}
+
+// prune 淘汰超出窗口的键
+func (w *keyWindow) prune(now time.Time) {
+	cutoff := now.Add(-w.window)
+	for len(w.order) > 0 && !w.order[0].at.After(cutoff) {
+		entry := w.order[0]
+		w.order = w.order[1:]
+		if at, exists := w.seen[entry.key]; exists && at.Equal(entry.at) {
+			delete(w.seen, entry.key)
+		}
+	}
+}
+
+// evictOldest 淘汰最早的键; 释放后重新记录的键以新的出现时间为准, 跳过旧的记录
+func (w *keyWindow) evictOldest() {
+	for len(w.order) > 0 {
+		entry := w.order[0]
+		w.order = w.order[1:]
+		if at, exists := w.seen[entry.key]; exists && at.Equal(entry.at) {
+			delete(w.seen, entry.key)
+			return
+		}
+	}
+}
@@ -0,0 +1,10 @@
+// system/types/density.go
+
+package types
+
+// DensityConfig 模式量子分析的密度矩阵构造配置
+type DensityConfig struct {
+	Levels            []string `json:"levels"`             // 作为能级布居的模式属性; 仅一项时构造布居为{p, 1-p}的二能级矩阵
+	PhaseProperty     string   `json:"phase_property"`     // 相位属性, 以π为单位, 第k能级的相位为-k·θ
+	CoherenceProperty string   `json:"coherence_property"` // 相干度属性(0-1), 为空或缺失时视为纯态
+}
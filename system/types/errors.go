@@ -3,6 +3,7 @@
 package types
 
 import (
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
@@ -64,6 +65,12 @@ var (
 	ErrInitialized    = NewSystemError(ErrState, "system already initialized", nil)
 	ErrNotInitialized = NewSystemError(ErrState, "system not initialized", nil)
 
+	// 运行模式相关错误
+	ErrMaintenanceMode   = NewSystemError(ErrState, "system in maintenance mode: external transforms are rejected", nil)
+	ErrReadOnlyMode      = NewSystemError(ErrState, "system in read-only mode: state changes are rejected", nil)
+	ErrDegradedMode      = NewSystemError(ErrState, "system in degraded mode: transforms are disabled", nil)
+	ErrMutationsDisabled = NewSystemError(ErrState, "mutations disabled by operating mode", nil)
+
 	// 模型相关错误
 	ErrModelNotFound      = NewSystemError(ErrCodeModel, "model not found", nil)
 	ErrModelAlreadyExists = NewSystemError(ErrCodeModel, "model already exists", nil)
@@ -78,14 +85,18 @@ var (
 
 // SystemError 系统错误结构
 type SystemError struct {
-	ModelErr *model.ModelError // 包含模型层错误
-	Code     ErrorCode         // 系统层错误码
-	Layer    SystemLayer       // 错误发生层
-	Message  string            // 错误消息
-	Details  string            // 详细信息
-	Time     time.Time         // 错误发生时间
-	Stack    []string          // 错误堆栈
-	Context  map[string]any    // 错误上下文
+	ModelErr  *model.ModelError // 包含模型层错误
+	Cause     error             // 原因错误
+	Code      ErrorCode         // 系统层错误码
+	Layer     SystemLayer       // 错误发生层
+	Domain    ErrorDomain       // 错误领域
+	Severity  ErrorSeverity     // 严重级别
+	Retryable bool              // 是否可重试
+	Message   string            // 错误消息
+	Details   string            // 详细信息
+	Time      time.Time         // 错误发生时间
+	Stack     []string          // 错误堆栈
+	Context   map[string]any    // 错误上下文
 }
 
 // ------------------------------------------------
@@ -95,6 +106,9 @@ func (e *SystemError) Error() string {
 
 	// 构建错误消息
 	b.WriteString(fmt.Sprintf("[System Error %s] ", e.Code))
+	if e.Domain != "" && e.Domain != DomainSystem {
+		b.WriteString(fmt.Sprintf("[Domain: %s] ", e.Domain))
+	}
 	if e.Layer != LayerNone {
 		b.WriteString(fmt.Sprintf("[Layer: %v] ", e.Layer))
 	}
@@ -103,6 +117,8 @@ func (e *SystemError) Error() string {
 	// 添加模型层错误信息
 	if e.ModelErr != nil {
 		b.WriteString(fmt.Sprintf("\nModel Error: %v", e.ModelErr))
+	} else if e.Cause != nil {
+		b.WriteString(fmt.Sprintf("\nCaused by: %v", e.Cause))
 	}
 
 	// 添加详细信息
@@ -130,27 +146,78 @@ func (e *SystemError) Error() string {
 }
 
 // NewSystemError 创建新的系统错误
-func NewSystemError(code ErrorCode, message string, modelErr error) *SystemError {
+// 领域、级别和重试标志取自错误码分类, 任意类型的原因错误都会保留在错误链中
+func NewSystemError(code ErrorCode, message string, cause error) *SystemError {
 	var mErr *model.ModelError
-	if modelErr != nil {
-		if me, ok := modelErr.(*model.ModelError); ok {
+	if cause != nil {
+		if me, ok := cause.(*model.ModelError); ok {
 			mErr = me
 		}
 	}
 
+	class, _ := LookupErrorClass(code)
 	return &SystemError{
-		ModelErr: mErr,
-		Code:     code,
-		Message:  message,
-		Time:     time.Now(),
-		Stack:    captureStack(),
-		Context:  make(map[string]any),
+		ModelErr:  mErr,
+		Cause:     cause,
+		Code:      code,
+		Domain:    class.Domain,
+		Severity:  class.Severity,
+		Retryable: class.Retryable,
+		Message:   message,
+		Time:      time.Now(),
+		Stack:     captureStack(),
+		Context:   make(map[string]any),
 	}
 }
 
-// WithLayer 设置错误层级
+// Unwrap 返回原因错误, 支持errors.Is/As沿错误链查找
+func (e *SystemError) Unwrap() error {
+	return e.Cause
+}
+
+// Is 错误码相同即视为匹配; 目标带消息时消息也需相同, 使预定义错误可用errors.Is判断
+func (e *SystemError) Is(target error) bool {
+	t, ok := target.(*SystemError)
+	if !ok || t == nil {
+		return false
+	}
+	return e.Code == t.Code && (t.Message == "" || e.Message == t.Message)
+}
+
+// GetCode 获取错误码
+func (e *SystemError) GetCode() ErrorCode {
+	return e.Code
+}
+
+// GetContext 获取错误上下文
+func (e *SystemError) GetContext() map[string]interface{} {
+	return e.Context
+}
+
+// WithLayer 设置错误层级, 未指定领域时同时设置对应领域
 func (e *SystemError) WithLayer(layer SystemLayer) *SystemError {
 	e.Layer = layer
+	if e.Domain == "" || e.Domain == DomainSystem {
+		e.Domain = layer.Domain()
+	}
+	return e
+}
+
+// WithDomain 设置错误领域
+func (e *SystemError) WithDomain(domain ErrorDomain) *SystemError {
+	e.Domain = domain
+	return e
+}
+
+// WithSeverity 设置严重级别
+func (e *SystemError) WithSeverity(severity ErrorSeverity) *SystemError {
+	e.Severity = severity
+	return e
+}
+
+// WithRetryable 设置是否可重试
+func (e *SystemError) WithRetryable(retryable bool) *SystemError {
+	e.Retryable = retryable
 	return e
 }
 
@@ -174,12 +241,14 @@ func WrapError(err error, code ErrorCode, message string) *SystemError {
 
 	sysErr := NewSystemError(code, message, err)
 
-	// 如果是系统错误，继承其上下文
-	if se, ok := err.(*SystemError); ok {
+	// 如果是系统错误，继承其上下文和领域
+	var se *SystemError
+	if errors.As(err, &se) && se != nil {
 		for k, v := range se.Context {
 			sysErr.Context[k] = v
 		}
 		sysErr.Layer = se.Layer
+		sysErr.Domain = se.Domain
 	}
 
 	return sysErr
@@ -211,23 +280,25 @@ func captureStack() []string {
 	return stack
 }
 
-// IsSystemError 检查是否为系统错误
+// IsSystemError 检查错误链中是否包含系统错误
 func IsSystemError(err error) bool {
-	_, ok := err.(*SystemError)
-	return ok
+	var se *SystemError
+	return errors.As(err, &se)
 }
 
-// IsModelError 检查是否为模型错误
+// IsModelError 检查错误链中是否包含模型错误
 func IsModelError(err error) bool {
-	if se, ok := err.(*SystemError); ok {
-		return se.ModelErr != nil
+	var se *SystemError
+	if errors.As(err, &se) && se != nil && se.ModelErr != nil {
+		return true
 	}
 	return model.IsModelError(err)
 }
 
-// GetErrorCode 获取错误码
+// GetErrorCode 获取错误链中首个系统错误或模型错误的错误码
 func GetErrorCode(err error) ErrorCode {
-	if se, ok := err.(*SystemError); ok {
+	var se *SystemError
+	if errors.As(err, &se) && se != nil {
 		return se.Code
 	}
 	return model.GetErrorCode(err)
@@ -235,7 +306,8 @@ func GetErrorCode(err error) ErrorCode {
 
 // GetErrorLayer 获取错误层级
 func GetErrorLayer(err error) SystemLayer {
-	if se, ok := err.(*SystemError); ok {
+	var se *SystemError
+	if errors.As(err, &se) && se != nil {
 		return se.Layer
 	}
 	return LayerNone
@@ -273,7 +345,8 @@ func (h *DefaultErrorHandler) Handle(err error) error {
 	}
 
 	// 处理系统错误
-	if se, ok := err.(*SystemError); ok {
+	var se *SystemError
+	if errors.As(err, &se) && se != nil {
 		// 根据错误层级选择处理策略
 		switch se.Layer {
 		case LayerMeta:
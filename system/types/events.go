@@ -27,17 +27,52 @@ const (
 	EventFlowError    EventType = "flow.error"
 
 	// 系统事件
-	EventSystemStarted  EventType = "system.started"  // 系统启动
-	EventSystemStopping EventType = "system.stopping" // 系统停止中
-	EventSystemStopped  EventType = "system.stopped"  // 系统已停止
-	EventSystemError    EventType = "system.error"    // 系统错误
-	EventSystemWarning  EventType = "system.warning"  // 系统警告
+	EventSystemStarted       EventType = "system.started"               // 系统启动
+	EventSystemStopping      EventType = "system.stopping"              // 系统停止中
+	EventSystemDrained       EventType = "system.drained"               // 关闭前排空完成, 携带各组件的排空结果
+	EventSystemStopped       EventType = "system.stopped"               // 系统已停止
+	EventSystemError         EventType = "system.error"                 // 系统错误
+	EventSystemWarning       EventType = "system.warning"               // 系统警告
+	EventModeChanged         EventType = "system.mode_changed"          // 运行模式切换
+	EventDiagnosticFailed    EventType = "system.diagnostic_failed"     // 自检失败
+	EventMemoryLimitExceeded EventType = "system.memory_limit_exceeded" // 缓存超出内存软上限
+	EventMemoryEvicted       EventType = "system.memory_evicted"        // 缓存按内存软上限驱逐
+	EventThrottleChanged     EventType = "system.throttle_changed"      // 循环因资源预算开始或停止节流
 
 	// 组件事件
 	EventComponentStarted EventType = "component.started" // 组件启动
 	EventComponentStopped EventType = "component.stopped" // 组件停止
 	EventComponentError   EventType = "component.error"   // 组件错误
 
+	// 监管事件
+	EventLoopCrashed   EventType = "supervisor.loop_crashed"   // 协程崩溃并重启
+	EventLoopEscalated EventType = "supervisor.loop_escalated" // 协程反复崩溃, 已停止重启
+
+	// 定时任务事件
+	EventJobFailed EventType = "scheduler.job_failed" // 定时任务执行失败、超时或崩溃
+
+	// 控制事件
+	EventBalanceAction EventType = "control.balance_action" // 阴阳平衡控制器执行修正
+
+	// 场事件
+	EventCouplingThreshold     EventType = "field.coupling_threshold"     // 耦合强度越过阈值
+	EventPerturbationScheduled EventType = "field.perturbation_scheduled" // 计划受控扰动
+	EventPerturbationApplied   EventType = "field.perturbation_applied"   // 施加受控扰动
+	EventPerturbationReverted  EventType = "field.perturbation_reverted"  // 扰动持续时间结束后撤销
+	EventPerturbationCancelled EventType = "field.perturbation_cancelled" // 扰动被取消
+
+	// 模式事件, 携带检测或匹配的追踪上下文
+	EventPatternDetected EventType = "pattern.detected" // 检测到新模式
+	EventPatternMatched  EventType = "pattern.matched"  // 模式首次匹配模板
+
+	// 漂移事件
+	EventDriftDetected EventType = "monitor.drift_detected" // 模式类型频率、环境因素或模型准确率的分布发生漂移
+
+	// 看门狗事件
+	EventSubsystemRecovering EventType = "watchdog.recovering" // 子系统持续异常, 已尝试恢复
+	EventSubsystemRecovered  EventType = "watchdog.recovered"  // 子系统恢复正常
+	EventSubsystemDegraded   EventType = "watchdog.degraded"   // 恢复重试耗尽, 子系统进入降级
+
 	// 状态事件
 	EventStateChanged    EventType = "state.changed"    // 状态改变
 	EventStateTransition EventType = "state.transition" // 状态转换
@@ -51,6 +86,22 @@ const (
 
 )
 
+// PatternDetection 模式检测事件的负载
+type PatternDetection struct {
+	Pattern    string  `json:"pattern"`
+	Type       string  `json:"type"`
+	Confidence float64 `json:"confidence"`
+}
+
+// PatternMatch 模式匹配事件的负载
+type PatternMatch struct {
+	Match      string  `json:"match"`
+	Pattern    string  `json:"pattern"`
+	Template   string  `json:"template"`
+	Similarity float64 `json:"similarity"`
+	Confidence float64 `json:"confidence"`
+}
+
 // EventPriority 事件优先级
 type EventPriority int
 
@@ -94,6 +145,15 @@ type EventBusImpl struct {
 	handlers map[string]EventHandler               // handlerID -> handler
 	topics   map[EventType]map[string]EventHandler // eventType -> handlerID -> handler
 
+	// 跨命名空间访问策略, 为空时命名空间严格隔离
+	authorizer NamespaceAuthorizer
+
+	// 事件负载结构, 为空时不校验
+	schemas *EventSchemaRegistry
+
+	// 幂等键去重, 为空时不去重
+	dedup *EventDeduplicator
+
 	// 配置
 	config struct {
 		bufferSize int           // 事件缓冲区大小
@@ -263,16 +323,32 @@ func (eb *EventBusImpl) Publish(event SystemEvent) error {
 		return NewSystemError(ErrInvalid, "nil handler", nil)
 	}
 
+	// 已声明负载结构的事件在投递前校验
+	if eb.schemas != nil {
+		if _, err := eb.schemas.Validate(event); err != nil {
+			return err
+		}
+	}
+
 	// 获取该事件类型的所有处理器
 	handlers, exists := eb.topics[event.Type]
 	if !exists {
 		return nil // 没有处理器，直接返回
 	}
 
+	// 标记窗口内重复的幂等键
+	now := time.Now()
+	if eb.dedup != nil {
+		event = eb.dedup.Publish(event, now)
+	}
+
 	// 调用所有相关处理器
 	for _, handler := range handlers {
+		if !eb.canDeliver(event, handler) {
+			continue
+		}
 		if handler.ShouldHandle(event) {
-			if err := handler.HandleEvent(event); err != nil {
+			if err := eb.deliver(event, handler, now); err != nil {
 				return err
 			}
 		}
@@ -281,6 +357,53 @@ func (eb *EventBusImpl) Publish(event SystemEvent) error {
 	return nil
 }
 
+// SetNamespaceAuthorizer 设置跨命名空间访问策略
+func (eb *EventBusImpl) SetNamespaceAuthorizer(authorizer NamespaceAuthorizer) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.authorizer = authorizer
+}
+
+// SetSchemaRegistry 设置事件负载结构注册表, 发布时校验已注册事件类型的负载
+func (eb *EventBusImpl) SetSchemaRegistry(schemas *EventSchemaRegistry) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.schemas = schemas
+}
+
+// SetDeduplicator 设置幂等键去重器, 按处理器的投递语义投递重复发布的事件
+func (eb *EventBusImpl) SetDeduplicator(dedup *EventDeduplicator) {
+	eb.mu.Lock()
+	defer eb.mu.Unlock()
+	eb.dedup = dedup
+}
+
+// deliver 投递事件到处理器(调用方持有锁)
+func (eb *EventBusImpl) deliver(event SystemEvent, handler EventHandler, now time.Time) error {
+	if eb.dedup == nil {
+		return handler.HandleEvent(event)
+	}
+	return eb.dedup.Deliver(event, handler, now)
+}
+
+// canDeliver 检查事件能否投递给处理器(调用方持有锁)
+func (eb *EventBusImpl) canDeliver(event SystemEvent, handler EventHandler) bool {
+	namespaced, ok := handler.(NamespacedHandler)
+	if !ok {
+		return true
+	}
+
+	from := EventNamespace(event)
+	to := namespaced.GetNamespace()
+	if from == to {
+		return true
+	}
+	if eb.authorizer == nil {
+		return false
+	}
+	return eb.authorizer.Allows(from, to, NamespaceOpEvent)
+}
+
 // Subscribe implements EventProcessor interface
 func (eb *EventBusImpl) Subscribe(eventType EventType, handler EventHandler) error {
 	return eb.doSubscribe([]EventType{eventType}, handler)
@@ -0,0 +1,21 @@
+// system/types/federation.go
+
+package types
+
+import "time"
+
+// FieldLinkConfig 场间耦合定义
+// 每次同步时目标场的属性按强度向源场靠拢, 双向耦合时两个场相向靠拢
+type FieldLinkConfig struct {
+	Source        Namespace `json:"source"`        // 源场所在命名空间
+	Target        Namespace `json:"target"`        // 目标场所在命名空间
+	Property      string    `json:"property"`      // 耦合的场属性, 为空时为energy
+	Strength      float64   `json:"strength"`      // 每次同步消除差值的比例 (0,1]
+	Bidirectional bool      `json:"bidirectional"` // 是否双向耦合
+}
+
+// FederationConfig 多场联邦配置
+type FederationConfig struct {
+	Interval time.Duration     `json:"interval"` // 场间耦合同步间隔, 为0时沿用场更新间隔
+	Links    []FieldLinkConfig `json:"links"`    // 启动时定义的场间耦合, 场尚未创建时暂不生效
+}
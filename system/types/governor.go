@@ -0,0 +1,39 @@
+// system/types/governor.go
+
+package types
+
+import "time"
+
+// 资源预算约束的循环
+const (
+	GovernedDetection = "detection" // 模式检测循环, 属于meta子系统
+	GovernedCausal    = "causal"    // 因果发现循环, 属于evolution子系统
+)
+
+// GovernorConfig 循环资源预算配置
+type GovernorConfig struct {
+	Window      time.Duration             `json:"window"`       // 用量统计窗口, 为0时使用默认值
+	Interval    time.Duration             `json:"interval"`     // 评估间隔, 为0时使用默认值
+	MaxSlowdown float64                   `json:"max_slowdown"` // 节流时的最大间隔倍率, 不大于1时使用默认值
+	Budgets     map[string]ResourceBudget `json:"budgets"`      // 各循环的预算, 键为GovernedDetection等
+}
+
+// ResourceBudget 单个循环的资源预算, 零值字段不限制
+type ResourceBudget struct {
+	CPU    float64 `json:"cpu"`    // 窗口内循环执行时间占比上限, 以单核计(0.2表示20%)
+	Memory int64   `json:"memory"` // 循环所属缓存的近似字节数上限
+}
+
+// ThrottleState 循环的资源用量和节流状态
+type ThrottleState struct {
+	Name      string         `json:"name"`      // 循环名称
+	Subsystem string         `json:"subsystem"` // 所属子系统
+	Budget    ResourceBudget `json:"budget"`    // 预算
+	CPU       float64        `json:"cpu"`       // 窗口内执行时间占比
+	Memory    int64          `json:"memory"`    // 近似内存占用, 无来源时为0
+	Cycles    int            `json:"cycles"`    // 窗口内执行次数
+	Factor    float64        `json:"factor"`    // 当前间隔倍率, 1为未节流
+	Throttled bool           `json:"throttled"` // 是否正在节流
+	Since     time.Time      `json:"since"`     // 进入当前节流状态的时间
+	Reason    string         `json:"reason"`    // 节流原因: cpu, memory
+}
@@ -4,6 +4,7 @@ package types
 
 import (
 	"context"
+	"math"
 	"time"
 
 	"github.com/Corphon/daoflow/core"
@@ -64,9 +65,15 @@ type SystemEvent struct {
 	Timestamp time.Time // 事件时间
 
 	// 事件内容
-	Message  string            // 事件消息
-	Data     interface{}       // 事件数据
-	Metadata map[string]string // 事件元数据
+	Message     string            // 事件消息
+	Data        interface{}       // 事件数据
+	Metadata    map[string]string // 事件元数据
+	Annotations Annotations       // 用户标注
+	Trace       TraceContext      // 因果追踪上下文, 为空时不追踪
+
+	// 幂等去重
+	IdempotencyKey string // 幂等键, 生产方重试时保持不变; 为空时不去重
+	Duplicate      bool   // 去重窗口内同一幂等键的重复发布, 由事件总线设置
 
 	// 事件处理
 	Priority Priority // 事件优先级
@@ -108,6 +115,13 @@ type EventHandler interface {
 	Shutdown() error
 }
 
+// NamespacedHandler 绑定命名空间的事件处理器
+// 未实现该接口的处理器视为全局处理器, 接收所有命名空间的事件
+type NamespacedHandler interface {
+	EventHandler
+	GetNamespace() Namespace
+}
+
 // 添加事件处理器基础实现
 type BaseEventHandler struct {
 	ID       string
@@ -142,6 +156,52 @@ type EventBus interface {
 	GetSubscriptions(eventType EventType) []EventHandler
 }
 
+// Lifecycle 子系统生命周期接口
+// 系统按依赖关系顺序启动和停止实现该接口的子系统
+type Lifecycle interface {
+	Start(ctx context.Context) error
+	Stop() error
+	Status() string  // 运行状态, 运行中为"running"
+	Health() float64 // 健康度(0-1), 未运行为0
+}
+
+// Drainer 支持关闭前排空的组件
+// Drain停止接收新的工作, 等待进行中的工作完成并刷写持久化; ctx到期时返回ctx的错误, 组件仍可随后停止
+type Drainer interface {
+	Drain(ctx context.Context) error
+}
+
+// HealthComponent 加权的健康度分量
+type HealthComponent struct {
+	Name   string  `json:"name"`
+	Score  float64 `json:"score"`  // 健康度(0-1)
+	Weight float64 `json:"weight"` // 权重, 非正的分量不参与计算
+	Detail string  `json:"detail,omitempty"`
+}
+
+// HealthContributor 健康度贡献者
+// 子系统或外部组件以加权分量报告自身健康信号, 系统健康度按分量的加权平均计入
+type HealthContributor interface {
+	HealthComponents() []HealthComponent
+}
+
+// WeightedHealth 分量的加权平均健康度, 没有有效分量时返回false
+func WeightedHealth(components []HealthComponent) (float64, bool) {
+	total, weight := 0.0, 0.0
+	for _, c := range components {
+		if c.Weight <= 0 {
+			continue
+		}
+		score := math.Max(0, math.Min(1, c.Score))
+		total += score * c.Weight
+		weight += c.Weight
+	}
+	if weight == 0 {
+		return 0, false
+	}
+	return total / weight, true
+}
+
 // SystemInterface 系统核心接口
 type SystemInterface interface {
 	// 生命周期管理
@@ -0,0 +1,137 @@
+// system/types/memory.go
+
+package types
+
+import (
+	"reflect"
+	"time"
+)
+
+// 内存估算参数
+const (
+	maxSizeDepth = 32 // 估算时的最大递归深度, 更深的值只计自身大小
+	mapEntryCost = 16 // map每个条目的额外开销估计(字节)
+)
+
+// MemoryLimitConfig 缓存内存占用的软上限
+type MemoryLimitConfig struct {
+	Interval time.Duration    `json:"interval"` // 检查间隔, 为0时使用默认值
+	Limits   map[string]int64 `json:"limits"`   // 各缓存的软上限(字节), 按缓存名称配置
+	Evict    bool             `json:"evict"`    // 超限时驱逐较早的条目, 否则只发出警告事件
+}
+
+// MemoryUsage 缓存的近似内存占用
+type MemoryUsage struct {
+	Cache    string `json:"cache"`
+	Entries  int    `json:"entries"`
+	Bytes    int64  `json:"bytes"`    // 近似字节数
+	Limit    int64  `json:"limit"`    // 软上限, 0为未配置
+	Exceeded bool   `json:"exceeded"` // 超出软上限
+}
+
+// MemoryEviction 按软上限驱逐缓存条目的结果
+type MemoryEviction struct {
+	Usage   MemoryUsage `json:"usage"`
+	Evicted int         `json:"evicted"`
+	Kept    int         `json:"kept"`
+}
+
+// MemoryReporter 报告缓存的内存占用, 并按需驱逐较早的条目
+type MemoryReporter interface {
+	MemoryUsage() []MemoryUsage
+	// EvictMemory 将缓存缩减到最多keep个条目, 返回驱逐的条目数
+	EvictMemory(cache string, keep int) int
+}
+
+// ApproxSize 估算值及其引用数据的字节数
+// 按类型大小递归累加字符串、切片、map和指针引用的数据, 同一指针只计一次; 函数和通道只计引用大小
+func ApproxSize(v interface{}) int64 {
+	value := reflect.ValueOf(v)
+	if !value.IsValid() {
+		return 0
+	}
+	return int64(value.Type().Size()) + indirectSize(value, make(map[uintptr]bool), 0)
+}
+
+// indirectSize 值引用的数据的字节数, 不含值自身
+func indirectSize(v reflect.Value, seen map[uintptr]bool, depth int) int64 {
+	if depth > maxSizeDepth {
+		return 0
+	}
+	depth++
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + indirectSize(elem, seen, depth)
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + indirectSize(elem, seen, depth)
+	case reflect.String:
+		return int64(v.Len())
+	case reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		if flat(v.Type().Elem()) {
+			return size
+		}
+		for i := 0; i < v.Len(); i++ {
+			size += indirectSize(v.Index(i), seen, depth)
+		}
+		return size
+	case reflect.Array:
+		if flat(v.Type().Elem()) {
+			return 0
+		}
+		size := int64(0)
+		for i := 0; i < v.Len(); i++ {
+			size += indirectSize(v.Index(i), seen, depth)
+		}
+		return size
+	case reflect.Map:
+		if v.IsNil() {
+			return 0
+		}
+		t := v.Type()
+		size := int64(v.Len()) * (int64(t.Key().Size()) + int64(t.Elem().Size()) + mapEntryCost)
+		if flat(t.Key()) && flat(t.Elem()) {
+			return size
+		}
+		iter := v.MapRange()
+		for iter.Next() {
+			size += indirectSize(iter.Key(), seen, depth) + indirectSize(iter.Value(), seen, depth)
+		}
+		return size
+	case reflect.Struct:
+		size := int64(0)
+		for i := 0; i < v.NumField(); i++ {
+			size += indirectSize(v.Field(i), seen, depth)
+		}
+		return size
+	default:
+		return 0
+	}
+}
+
+// flat 类型是否不引用其他数据
+func flat(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return true
+	case reflect.Array:
+		return flat(t.Elem())
+	default:
+		return false
+	}
+}
@@ -0,0 +1,56 @@
+// system/types/mode.go
+
+package types
+
+import "time"
+
+// OperatingMode 系统运行模式
+type OperatingMode string
+
+const (
+	ModeNormal      OperatingMode = "normal"      // 正常运行
+	ModeDegraded    OperatingMode = "degraded"    // 降级: 检测和学习循环放慢, 突变和转换停用, 监控照常
+	ModeMaintenance OperatingMode = "maintenance" // 维护: 拒绝外部触发的转换, 内部循环照常
+	ModeReadOnly    OperatingMode = "read_only"   // 只读: 拒绝外部转换并停用突变, 只观测不改变状态
+)
+
+// Valid 是否为已定义的运行模式
+func (m OperatingMode) Valid() bool {
+	switch m {
+	case ModeNormal, ModeDegraded, ModeMaintenance, ModeReadOnly:
+		return true
+	}
+	return false
+}
+
+// AllowsExternalTransforms 是否接受外部触发的转换
+func (m OperatingMode) AllowsExternalTransforms() bool {
+	return m == ModeNormal
+}
+
+// AllowsMutations 是否允许突变处理和适应策略改变系统状态
+func (m OperatingMode) AllowsMutations() bool {
+	return m == ModeNormal || m == ModeMaintenance
+}
+
+// TransformError 该模式下拒绝外部转换的错误, 允许时为nil
+func (m OperatingMode) TransformError() error {
+	switch m {
+	case ModeMaintenance:
+		return ErrMaintenanceMode
+	case ModeReadOnly:
+		return ErrReadOnlyMode
+	case ModeDegraded:
+		return ErrDegradedMode
+	}
+	return nil
+}
+
+// ModeStatus 运行模式状态
+type ModeStatus struct {
+	Mode     OperatingMode `json:"mode"`
+	Previous OperatingMode `json:"previous"`
+	Reason   string        `json:"reason"`
+	Since    time.Time     `json:"since"`
+	Auto     bool          `json:"auto"` // 是否由看门狗自动切换
+}
@@ -47,6 +47,8 @@ type SystemMetrics struct {
 		Latency    time.Duration `json:"latency"`    // 平均延迟
 		ErrorRate  float64       `json:"error_rate"` // 错误率
 		Throughput float64       `json:"throughput"` // 吞吐量
+
+		Percentiles LatencyQuantiles `json:"percentiles"` // 最近一小时追踪耗时的分位数
 	} `json:"performance"`
 
 	// 资源指标
@@ -80,6 +82,9 @@ type SystemMetrics struct {
 		LastUpdateTime time.Time `json:"last_update_time"` // 最后更新时间
 	} `json:"stats"`
 
+	// 启动预热状态
+	Warmup WarmupStatus `json:"warmup"`
+
 	// 子系统指标
 	Subsystems map[string]SubsystemMetrics `json:"subsystems"` // 子系统指标
 
@@ -118,6 +123,9 @@ type SubsystemMetrics struct {
 		Network float64 `json:"network"` // 网络使用率
 	} `json:"resources"`
 
+	// 资源预算约束的循环的节流状态
+	Throttle []ThrottleState `json:"throttle,omitempty"`
+
 	// 历史记录
 	History []MetricPoint `json:"history"` // 历史指标点
 }
@@ -188,6 +196,62 @@ type AlertConfig struct {
 	Templates map[string]string // 消息模板
 }
 
+// 基线阈值方法
+const (
+	BaselineSigma      = "sigma"      // 均值 + N倍标准差
+	BaselinePercentile = "percentile" // 滚动分位数
+)
+
+// BaselineConfig 基线学习配置
+type BaselineConfig struct {
+	Enabled       bool    `json:"enabled"`        // 是否启用
+	WindowSize    int     `json:"window_size"`    // 每个指标保留的样本数
+	WarmupSamples int     `json:"warmup_samples"` // 预热样本数, 预热期间使用默认阈值
+	Method        string  `json:"method"`         // 阈值方法: sigma, percentile
+	Sigma         float64 `json:"sigma"`          // sigma方法的标准差倍数
+	Percentile    float64 `json:"percentile"`     // percentile方法的分位数(0-1)
+	PersistPath   string  `json:"persist_path"`   // 基线持久化文件, 为空不持久化
+}
+
+// CorrelationConfig 异常关联配置
+type CorrelationConfig struct {
+	Enabled     bool          `json:"enabled"`      // 是否启用
+	Window      time.Duration `json:"window"`       // 异常前的回溯窗口
+	HalfLife    time.Duration `json:"half_life"`    // 时间相关性半衰期
+	MinScore    float64       `json:"min_score"`    // 最低得分, 低于该值不作为可能原因
+	MaxCauses   int           `json:"max_causes"`   // 每个异常最多标注的原因数
+	HistorySize int           `json:"history_size"` // 保留的事件数
+}
+
+// EnergyFlowConfig 能量流动记录配置
+type EnergyFlowConfig struct {
+	Enabled     bool          `json:"enabled"`      // 是否启用
+	Retention   time.Duration `json:"retention"`    // 转移记录的保留时长
+	HistorySize int           `json:"history_size"` // 保留的转移记录数
+}
+
+// 漂移检测方法
+const (
+	DriftPageHinkley = "page_hinkley" // Page-Hinkley累积和检验, 以数据流的标准差为单位
+	DriftADWIN       = "adwin"        // 自适应窗口, 适合频率、准确率等有界取值
+)
+
+// DriftConfig 分布漂移检测配置
+// 按采样间隔观测模式类型频率、环境因素和模型准确率, 分布显著变化时发出漂移事件
+type DriftConfig struct {
+	Enabled     bool          `json:"enabled"`      // 是否启用
+	Method      string        `json:"method"`       // 检测方法, 为空时为page_hinkley
+	Interval    time.Duration `json:"interval"`     // 采样间隔, 为零时取10秒
+	MinSamples  int           `json:"min_samples"`  // 数据流开始检测所需的样本数, 为零时取30
+	Delta       float64       `json:"delta"`        // Page-Hinkley容许的均值变化, 标准差的倍数, 为零时取0.5
+	Threshold   float64       `json:"threshold"`    // Page-Hinkley报警阈值, 标准差的倍数, 为零时取20
+	Confidence  float64       `json:"confidence"`   // ADWIN的置信参数, 为零时取0.002
+	MaxWindow   int           `json:"max_window"`   // ADWIN的窗口上限, 为零时取1000
+	HistorySize int           `json:"history_size"` // 保留的漂移记录数, 为零时取1000
+	Recalibrate bool          `json:"recalibrate"`  // 漂移时重新校准共振匹配阈值
+	Retrain     bool          `json:"retrain"`      // 模式类型频率或模型准确率漂移时重新训练学习模型
+}
+
 // AlertRule 告警规则
 type AlertRule struct {
 	Name      string        // 规则名称
@@ -499,6 +563,22 @@ type Anomaly struct {
 	Threshold  float64   // 触发阈值
 	Value      float64   // 实际值
 	DetectedAt time.Time // 检测时间
+
+	Causes []ProbableCause // 关联分析得出的可能原因
+}
+
+// ProbableCause 异常的可能原因
+type ProbableCause struct {
+	Kind        string        // 来源类型: pattern, transform, perturbation
+	Source      string        // 来源: 命名空间、模型名或meta.field
+	Reference   string        // 引用: 模式ID、转换模式或扰动ID
+	Category    string        // 类别: 模式类型、转换类型或扰动标签
+	OccurredAt  time.Time     // 发生时间
+	Lag         time.Duration // 距异常发生的时间
+	Temporal    float64       // 时间相关性(0-1)
+	Causal      float64       // 历史因果一致性(0-1)
+	Score       float64       // 综合得分(0-1)
+	Description string        // 说明
 }
 
 type PredictedValue struct {
@@ -895,14 +975,20 @@ func (sm SystemMetrics) ToMap() map[string]interface{} {
 		},
 		// 性能指标
 		"performance": map[string]interface{}{
-			"qps":        sm.Performance.QPS,
-			"latency":    sm.Performance.Latency.String(),
-			"error_rate": sm.Performance.ErrorRate,
-			"throughput": sm.Performance.Throughput,
+			"qps":          sm.Performance.QPS,
+			"latency":      sm.Performance.Latency.String(),
+			"latency_p50":  sm.Performance.Percentiles.P50.String(),
+			"latency_p90":  sm.Performance.Percentiles.P90.String(),
+			"latency_p99":  sm.Performance.Percentiles.P99.String(),
+			"latency_p999": sm.Performance.Percentiles.P999.String(),
+			"error_rate":   sm.Performance.ErrorRate,
+			"throughput":   sm.Performance.Throughput,
 		},
 		// 资源指标
 		"resources": sm.Resources,
 		// 子系统指标
 		"subsystems": sm.Subsystems,
+		// 启动预热状态
+		"warmup": sm.Warmup,
 	}
 }
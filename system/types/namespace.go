@@ -0,0 +1,296 @@
+//system/types/namespace.go
+
+package types
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+)
+
+// Namespace 命名空间(场域/租户隔离单元)
+type Namespace string
+
+const (
+	// DefaultNamespace 默认命名空间
+	DefaultNamespace Namespace = "default"
+
+	// NamespaceMetadataKey 事件元数据中的命名空间键
+	NamespaceMetadataKey = "namespace"
+)
+
+// NamespaceOperation 跨命名空间操作
+type NamespaceOperation string
+
+const (
+	NamespaceOpEvent     NamespaceOperation = "event"     // 事件投递
+	NamespaceOpPattern   NamespaceOperation = "pattern"   // 模式匹配
+	NamespaceOpKnowledge NamespaceOperation = "knowledge" // 知识共享
+)
+
+// NamespaceConfig 命名空间配置
+type NamespaceConfig struct {
+	Name        Namespace         `json:"name"`        // 名称
+	Description string            `json:"description"` // 描述
+	Labels      map[string]string `json:"labels"`      // 标签
+
+	// 场配置, 零值沿用元系统的场配置
+	Field struct {
+		InitialStrength    float64                 `json:"initial_strength"`    // 初始场强度
+		MaxCouplings       int                     `json:"max_couplings"`       // 最大耦合数
+		CouplingThresholds CouplingThresholdConfig `json:"coupling_thresholds"` // 耦合强度阈值
+		Geometry           core.Geometry           `json:"geometry"`            // 场网格几何
+	} `json:"field"`
+
+	// 检测配置
+	Detection struct {
+		Sensitivity      float64       `json:"sensitivity"`       // 检测灵敏度
+		MinConfidence    float64       `json:"min_confidence"`    // 最小置信度
+		PatternThreshold float64       `json:"pattern_threshold"` // 模式阈值
+		Interval         time.Duration `json:"interval"`          // 检测间隔
+	} `json:"detection"`
+
+	// 匹配配置
+	Matching struct {
+		MatchThreshold float64 `json:"match_threshold"` // 匹配阈值
+	} `json:"matching"`
+
+	// 学习配置
+	Learning struct {
+		LearningRate    float64 `json:"learning_rate"`    // 学习率
+		ExplorationRate float64 `json:"exploration_rate"` // 探索率
+	} `json:"learning"`
+
+	// 访问策略
+	Policy NamespacePolicy `json:"policy"`
+}
+
+// NamespacePolicy 跨命名空间访问策略
+// Allow 记录允许向本命名空间流入的源命名空间及操作
+type NamespacePolicy struct {
+	Allow map[Namespace][]NamespaceOperation `json:"allow"` // 源命名空间 -> 允许的操作
+	Open  bool                               `json:"open"`  // 是否对所有命名空间开放
+}
+
+// NamespaceAuthorizer 跨命名空间访问判定
+type NamespaceAuthorizer interface {
+	Allows(from, to Namespace, op NamespaceOperation) bool
+}
+
+// NamespaceRegistry 命名空间注册表
+type NamespaceRegistry struct {
+	mu sync.RWMutex
+
+	namespaces map[Namespace]*NamespaceConfig           // 命名空间配置
+	metrics    map[Namespace]map[string]float64         // 命名空间指标
+	denied     map[Namespace]map[NamespaceOperation]int // 被拒绝的跨域访问计数
+}
+
+// ---------------------------------------------
+// NewNamespaceRegistry 创建命名空间注册表
+func NewNamespaceRegistry() *NamespaceRegistry {
+	r := &NamespaceRegistry{
+		namespaces: make(map[Namespace]*NamespaceConfig),
+		metrics:    make(map[Namespace]map[string]float64),
+		denied:     make(map[Namespace]map[NamespaceOperation]int),
+	}
+
+	// 默认命名空间始终存在
+	r.namespaces[DefaultNamespace] = &NamespaceConfig{Name: DefaultNamespace}
+	r.metrics[DefaultNamespace] = make(map[string]float64)
+
+	return r
+}
+
+// Register 注册命名空间
+func (r *NamespaceRegistry) Register(cfg *NamespaceConfig) error {
+	if cfg == nil || cfg.Name == "" {
+		return NewSystemError(ErrInvalid, "invalid namespace config", nil)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.namespaces[cfg.Name]; exists {
+		return NewSystemError(ErrExists, "namespace already exists", nil).
+			WithContext("namespace", cfg.Name)
+	}
+
+	r.namespaces[cfg.Name] = cfg
+	r.metrics[cfg.Name] = make(map[string]float64)
+	return nil
+}
+
+// Unregister 注销命名空间
+func (r *NamespaceRegistry) Unregister(ns Namespace) error {
+	if ns == DefaultNamespace {
+		return NewSystemError(ErrInvalid, "cannot remove default namespace", nil)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, exists := r.namespaces[ns]; !exists {
+		return NewSystemError(ErrNotFound, "namespace not found", nil).
+			WithContext("namespace", ns)
+	}
+
+	delete(r.namespaces, ns)
+	delete(r.metrics, ns)
+	delete(r.denied, ns)
+	return nil
+}
+
+// Get 获取命名空间配置
+func (r *NamespaceRegistry) Get(ns Namespace) (*NamespaceConfig, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	cfg, exists := r.namespaces[ns]
+	return cfg, exists
+}
+
+// List 列出所有命名空间
+func (r *NamespaceRegistry) List() []Namespace {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Namespace, 0, len(r.namespaces))
+	for ns := range r.namespaces {
+		list = append(list, ns)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i] < list[j] })
+	return list
+}
+
+// SetPolicy 设置命名空间访问策略
+func (r *NamespaceRegistry) SetPolicy(ns Namespace, policy NamespacePolicy) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, exists := r.namespaces[ns]
+	if !exists {
+		return NewSystemError(ErrNotFound, "namespace not found", nil).
+			WithContext("namespace", ns)
+	}
+	cfg.Policy = policy
+	return nil
+}
+
+// Allow 允许源命名空间对目标命名空间执行指定操作
+func (r *NamespaceRegistry) Allow(from, to Namespace, ops ...NamespaceOperation) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cfg, exists := r.namespaces[to]
+	if !exists {
+		return NewSystemError(ErrNotFound, "namespace not found", nil).
+			WithContext("namespace", to)
+	}
+	if cfg.Policy.Allow == nil {
+		cfg.Policy.Allow = make(map[Namespace][]NamespaceOperation)
+	}
+	cfg.Policy.Allow[from] = append(cfg.Policy.Allow[from], ops...)
+	return nil
+}
+
+// Allows 判定跨命名空间访问, 同一命名空间始终允许
+func (r *NamespaceRegistry) Allows(from, to Namespace, op NamespaceOperation) bool {
+	if from == to {
+		return true
+	}
+
+	r.mu.RLock()
+	allowed := r.allows(from, to, op)
+	r.mu.RUnlock()
+
+	if !allowed {
+		r.mu.Lock()
+		if _, exists := r.denied[to]; !exists {
+			r.denied[to] = make(map[NamespaceOperation]int)
+		}
+		r.denied[to][op]++
+		r.mu.Unlock()
+	}
+
+	return allowed
+}
+
+// allows 策略判定(调用方持有锁)
+func (r *NamespaceRegistry) allows(from, to Namespace, op NamespaceOperation) bool {
+	cfg, exists := r.namespaces[to]
+	if !exists {
+		return false
+	}
+	if cfg.Policy.Open {
+		return true
+	}
+	for _, allowed := range cfg.Policy.Allow[from] {
+		if allowed == op {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordMetric 累加命名空间指标
+func (r *NamespaceRegistry) RecordMetric(ns Namespace, name string, delta float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics, exists := r.metrics[ns]
+	if !exists {
+		return
+	}
+	metrics[name] += delta
+}
+
+// SetMetric 设置命名空间指标
+func (r *NamespaceRegistry) SetMetric(ns Namespace, name string, value float64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	metrics, exists := r.metrics[ns]
+	if !exists {
+		return
+	}
+	metrics[name] = value
+}
+
+// GetMetrics 获取命名空间指标副本
+func (r *NamespaceRegistry) GetMetrics(ns Namespace) map[string]float64 {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	result := make(map[string]float64)
+	for k, v := range r.metrics[ns] {
+		result[k] = v
+	}
+	for op, count := range r.denied[ns] {
+		result["denied_"+string(op)] = float64(count)
+	}
+	return result
+}
+
+// EventNamespace 获取事件所属命名空间
+func EventNamespace(event SystemEvent) Namespace {
+	if event.Metadata != nil {
+		if ns, ok := event.Metadata[NamespaceMetadataKey]; ok && ns != "" {
+			return Namespace(ns)
+		}
+	}
+	return DefaultNamespace
+}
+
+// WithNamespace 为事件设置命名空间
+func WithNamespace(event SystemEvent, ns Namespace) SystemEvent {
+	metadata := make(map[string]string, len(event.Metadata)+1)
+	for k, v := range event.Metadata {
+		metadata[k] = v
+	}
+	metadata[NamespaceMetadataKey] = string(ns)
+	event.Metadata = metadata
+	return event
+}
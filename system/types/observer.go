@@ -0,0 +1,20 @@
+// system/types/observer.go
+
+package types
+
+import "time"
+
+// 观察者可附加的只读视图
+const (
+	ObserverViewMetrics  = "metrics"  // 指标流
+	ObserverViewState    = "state"    // 系统状态快照
+	ObserverViewPatterns = "patterns" // 活跃模式快照
+)
+
+// ObserverConfig 只读观察者配置
+type ObserverConfig struct {
+	Interval          time.Duration `json:"interval"`            // 视图发布间隔, 为0时使用默认值
+	MaxObservers      int           `json:"max_observers"`       // 最多同时附加的观察者数, 为0时使用默认值
+	RequestsPerSecond float64       `json:"requests_per_second"` // 每个观察者每秒的快照请求上限, 为0时使用默认值
+	StreamBuffer      int           `json:"stream_buffer"`       // 指标流的缓冲样本数, 为0时使用默认值
+}
@@ -0,0 +1,20 @@
+// system/types/scheduler.go
+
+package types
+
+import "time"
+
+// SchedulerConfig 定时任务配置
+// 子系统以代码声明任务的默认计划, 配置按任务名覆盖
+type SchedulerConfig struct {
+	Jitter time.Duration        `json:"jitter"` // 未单独配置的任务的默认随机延迟上限, 避免任务同时触发
+	Jobs   map[string]JobConfig `json:"jobs"`   // 按任务名的覆盖配置
+}
+
+// JobConfig 单个定时任务的覆盖配置, 零值字段沿用任务声明的值
+type JobConfig struct {
+	Spec     string        `json:"spec"`     // 计划, 五段cron表达式或@every、@hourly等描述符
+	Jitter   time.Duration `json:"jitter"`   // 每次触发的随机延迟上限
+	Timeout  time.Duration `json:"timeout"`  // 单次执行超时
+	Disabled bool          `json:"disabled"` // 停用任务
+}
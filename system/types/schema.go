@@ -0,0 +1,221 @@
+// system/types/schema.go
+
+package types
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// EventSchemaVersionKey 事件元数据中负载结构版本的键
+const EventSchemaVersionKey = "schema_version"
+
+// EventSchema 事件类型声明的负载结构
+// 同一事件类型可以注册多个版本, 发布时按元数据中的版本校验, 未标注版本时使用最新版本
+type EventSchema struct {
+	Type        EventType    `json:"type"`
+	Version     int          `json:"version"`
+	Payload     reflect.Type `json:"-"`
+	PayloadName string       `json:"payload"` // 负载类型名称, 如types.ThrottleState
+	Description string       `json:"description,omitempty"`
+
+	// Validate 负载类型匹配后的额外校验, 可为空
+	Validate func(payload interface{}) error `json:"-"`
+}
+
+// EventSchemaRegistry 事件负载结构注册表
+// 未注册的事件类型不做校验, 已注册的事件类型在发布时要求负载为声明的结构或其指针
+type EventSchemaRegistry struct {
+	mu      sync.RWMutex
+	schemas map[EventType]map[int]EventSchema
+	latest  map[EventType]int
+}
+
+// NewEventSchemaRegistry 创建事件负载结构注册表
+func NewEventSchemaRegistry() *EventSchemaRegistry {
+	return &EventSchemaRegistry{
+		schemas: make(map[EventType]map[int]EventSchema),
+		latest:  make(map[EventType]int),
+	}
+}
+
+// Register 注册事件负载结构
+// 重复注册相同版本和负载类型时忽略, 相同版本声明不同负载类型时返回错误
+func (r *EventSchemaRegistry) Register(schema EventSchema) error {
+	if schema.Type == "" || schema.Version <= 0 || schema.Payload == nil {
+		return NewSystemError(ErrValidation, "event schema requires type, positive version and payload", nil).
+			WithContext("type", schema.Type).
+			WithContext("version", schema.Version)
+	}
+	if schema.PayloadName == "" {
+		schema.PayloadName = schema.Payload.String()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	versions, exists := r.schemas[schema.Type]
+	if !exists {
+		versions = make(map[int]EventSchema)
+		r.schemas[schema.Type] = versions
+	}
+	if existing, exists := versions[schema.Version]; exists {
+		if existing.Payload != schema.Payload {
+			return NewSystemError(ErrExists, "event schema version already registered with a different payload", nil).
+				WithContext("type", schema.Type).
+				WithContext("version", schema.Version).
+				WithContext("payload", existing.PayloadName)
+		}
+		return nil
+	}
+
+	versions[schema.Version] = schema
+	if schema.Version > r.latest[schema.Type] {
+		r.latest[schema.Type] = schema.Version
+	}
+	return nil
+}
+
+// RegisterEventPayload 以T为负载结构注册事件类型的一个版本
+func RegisterEventPayload[T any](r *EventSchemaRegistry, eventType EventType, version int, description string) error {
+	return r.Register(EventSchema{
+		Type:        eventType,
+		Version:     version,
+		Payload:     reflect.TypeOf((*T)(nil)).Elem(),
+		Description: description,
+	})
+}
+
+// Lookup 查找事件类型的负载结构, version为0时返回最新版本
+func (r *EventSchemaRegistry) Lookup(eventType EventType, version int) (EventSchema, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if version == 0 {
+		version = r.latest[eventType]
+	}
+	schema, exists := r.schemas[eventType][version]
+	return schema, exists
+}
+
+// Schemas 已注册的全部负载结构, 按事件类型和版本排序
+func (r *EventSchemaRegistry) Schemas() []EventSchema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	schemas := make([]EventSchema, 0)
+	for _, versions := range r.schemas {
+		for _, schema := range versions {
+			schemas = append(schemas, schema)
+		}
+	}
+	sort.Slice(schemas, func(i, j int) bool {
+		if schemas[i].Type != schemas[j].Type {
+			return schemas[i].Type < schemas[j].Type
+		}
+		return schemas[i].Version < schemas[j].Version
+	})
+	return schemas
+}
+
+// Validate 校验事件负载, 返回校验所用的结构版本; 事件类型未注册时返回0
+func (r *EventSchemaRegistry) Validate(event SystemEvent) (int, error) {
+	r.mu.RLock()
+	versions, registered := r.schemas[event.Type]
+	latest := r.latest[event.Type]
+	r.mu.RUnlock()
+	if !registered {
+		return 0, nil
+	}
+
+	version := latest
+	if raw, ok := event.Metadata[EventSchemaVersionKey]; ok {
+		v, err := strconv.Atoi(raw)
+		if err != nil {
+			return 0, NewSystemError(ErrValidation, "invalid event schema version", err).
+				WithContext("type", event.Type).
+				WithContext("version", raw)
+		}
+		version = v
+	}
+	schema, exists := versions[version]
+	if !exists {
+		return 0, NewSystemError(ErrValidation, "unknown event schema version", nil).
+			WithContext("type", event.Type).
+			WithContext("version", version)
+	}
+
+	payload, ok := schemaPayload(event.Data, schema.Payload)
+	if !ok {
+		return 0, NewSystemError(ErrValidation, "event payload does not match schema", nil).
+			WithContext("type", event.Type).
+			WithContext("version", version).
+			WithContext("expected", schema.PayloadName).
+			WithContext("actual", reflect.TypeOf(event.Data))
+	}
+	if schema.Validate != nil {
+		if err := schema.Validate(payload); err != nil {
+			return 0, NewSystemError(ErrValidation, "event payload failed schema validation", err).
+				WithContext("type", event.Type).
+				WithContext("version", version)
+		}
+	}
+	return version, nil
+}
+
+// schemaPayload 负载为声明的结构或其非空指针时返回结构值
+func schemaPayload(data interface{}, payload reflect.Type) (interface{}, bool) {
+	value := reflect.ValueOf(data)
+	if !value.IsValid() {
+		return nil, false
+	}
+	if value.Type() == payload {
+		return data, true
+	}
+	if value.Kind() == reflect.Pointer && value.Type().Elem() == payload && !value.IsNil() {
+		return value.Elem().Interface(), true
+	}
+	return nil, false
+}
+
+// WithSchemaVersion 返回标注了负载结构版本的事件, 不修改原事件的元数据
+func WithSchemaVersion(event SystemEvent, version int) SystemEvent {
+	metadata := make(map[string]string, len(event.Metadata)+1)
+	for k, v := range event.Metadata {
+		metadata[k] = v
+	}
+	metadata[EventSchemaVersionKey] = strconv.Itoa(version)
+	event.Metadata = metadata
+	return event
+}
+
+// Decode 将事件负载解码为T
+// 负载为T或*T时直接返回; 其他负载(如经序列化传输后的map)按JSON字段转换
+func Decode[T any](event SystemEvent) (T, error) {
+	var payload T
+	switch data := event.Data.(type) {
+	case T:
+		return data, nil
+	case *T:
+		if data != nil {
+			return *data, nil
+		}
+	case nil:
+	default:
+		raw, err := json.Marshal(data)
+		if err == nil {
+			err = json.Unmarshal(raw, &payload)
+		}
+		if err != nil {
+			return payload, NewSystemError(ErrValidation, "failed to decode event payload", err).
+				WithContext("type", event.Type).
+				WithContext("payload", reflect.TypeOf((*T)(nil)).Elem().String())
+		}
+		return payload, nil
+	}
+	return payload, NewSystemError(ErrValidation, "event has no payload", nil).
+		WithContext("type", event.Type)
+}
@@ -0,0 +1,340 @@
+// system/types/taxonomy.go
+
+package types
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/Corphon/daoflow/model"
+)
+
+// ErrorDomain 错误所属领域
+type ErrorDomain string
+
+const (
+	DomainSystem      ErrorDomain = "system"      // 系统通用
+	DomainModel       ErrorDomain = "model"       // 模型层
+	DomainMeta        ErrorDomain = "meta"        // 元系统
+	DomainEvolution   ErrorDomain = "evolution"   // 演化系统
+	DomainPattern     ErrorDomain = "pattern"     // 模式识别与生成
+	DomainControl     ErrorDomain = "control"     // 控制系统
+	DomainResource    ErrorDomain = "resource"    // 资源系统
+	DomainMonitor     ErrorDomain = "monitor"     // 监控系统
+	DomainIntegration ErrorDomain = "integration" // 外部集成
+)
+
+// ErrorSeverity 错误严重级别
+type ErrorSeverity uint8
+
+const (
+	ErrSeverityInfo ErrorSeverity = iota
+	ErrSeverityWarning
+	ErrSeverityError
+	ErrSeverityCritical
+)
+
+// String 级别名称
+func (s ErrorSeverity) String() string {
+	switch s {
+	case ErrSeverityInfo:
+		return "info"
+	case ErrSeverityWarning:
+		return "warning"
+	case ErrSeverityError:
+		return "error"
+	case ErrSeverityCritical:
+		return "critical"
+	default:
+		return "unknown"
+	}
+}
+
+// AlertSeverity 对应的告警级别
+func (s ErrorSeverity) AlertSeverity() AlertSeverity {
+	switch s {
+	case ErrSeverityInfo:
+		return SeverityInfo
+	case ErrSeverityWarning:
+		return SeverityWarning
+	case ErrSeverityCritical:
+		return SeverityCritical
+	default:
+		return SeverityError
+	}
+}
+
+// GRPCCode gRPC状态码, 取值与google.golang.org/grpc/codes一致
+type GRPCCode uint32
+
+const (
+	GRPCOK                 GRPCCode = 0
+	GRPCCanceled           GRPCCode = 1
+	GRPCUnknown            GRPCCode = 2
+	GRPCInvalidArgument    GRPCCode = 3
+	GRPCDeadlineExceeded   GRPCCode = 4
+	GRPCNotFound           GRPCCode = 5
+	GRPCAlreadyExists      GRPCCode = 6
+	GRPCPermissionDenied   GRPCCode = 7
+	GRPCResourceExhausted  GRPCCode = 8
+	GRPCFailedPrecondition GRPCCode = 9
+	GRPCAborted            GRPCCode = 10
+	GRPCOutOfRange         GRPCCode = 11
+	GRPCUnimplemented      GRPCCode = 12
+	GRPCInternal           GRPCCode = 13
+	GRPCUnavailable        GRPCCode = 14
+	GRPCDataLoss           GRPCCode = 15
+	GRPCUnauthenticated    GRPCCode = 16
+)
+
+var grpcCodeNames = [...]string{
+	"OK", "Canceled", "Unknown", "InvalidArgument", "DeadlineExceeded",
+	"NotFound", "AlreadyExists", "PermissionDenied", "ResourceExhausted",
+	"FailedPrecondition", "Aborted", "OutOfRange", "Unimplemented",
+	"Internal", "Unavailable", "DataLoss", "Unauthenticated",
+}
+
+// String 状态码名称
+func (c GRPCCode) String() string {
+	if int(c) < len(grpcCodeNames) {
+		return grpcCodeNames[c]
+	}
+	return "Unknown"
+}
+
+// statusClientClosed 客户端取消请求, 非标准HTTP状态码
+const statusClientClosed = 499
+
+// ErrorClass 错误分类
+type ErrorClass struct {
+	Domain     ErrorDomain   // 所属领域
+	Severity   ErrorSeverity // 严重级别
+	Retryable  bool          // 是否可重试
+	HTTPStatus int           // HTTP状态码
+	GRPCCode   GRPCCode      // gRPC状态码
+}
+
+// defaultErrorClass 未登记错误码的分类
+var defaultErrorClass = ErrorClass{
+	Domain:     DomainSystem,
+	Severity:   ErrSeverityError,
+	HTTPStatus: http.StatusInternalServerError,
+	GRPCCode:   GRPCUnknown,
+}
+
+// errorClasses 错误码分类表
+// 系统层和模型层共用部分错误码(STATE, INIT, TIMEOUT), 只登记一次
+var errorClasses = struct {
+	mu      sync.RWMutex
+	classes map[ErrorCode]ErrorClass
+}{
+	classes: map[ErrorCode]ErrorClass{
+		// 系统层
+		ErrSystem:        {DomainSystem, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		ErrRuntime:       {DomainSystem, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		ErrComponent:     {DomainSystem, ErrSeverityError, true, http.StatusServiceUnavailable, GRPCUnavailable},
+		ErrInternal:      {DomainSystem, ErrSeverityCritical, false, http.StatusInternalServerError, GRPCInternal},
+		ErrValidation:    {DomainSystem, ErrSeverityWarning, false, http.StatusBadRequest, GRPCInvalidArgument},
+		ErrPermission:    {DomainSystem, ErrSeverityWarning, false, http.StatusForbidden, GRPCPermissionDenied},
+		ErrSecurity:      {DomainSystem, ErrSeverityCritical, false, http.StatusForbidden, GRPCPermissionDenied},
+		ErrInvalid:       {DomainSystem, ErrSeverityWarning, false, http.StatusBadRequest, GRPCInvalidArgument},
+		ErrResource:      {DomainResource, ErrSeverityError, true, http.StatusTooManyRequests, GRPCResourceExhausted},
+		ErrStorage:       {DomainResource, ErrSeverityError, true, http.StatusServiceUnavailable, GRPCUnavailable},
+		ErrNetwork:       {DomainIntegration, ErrSeverityError, true, http.StatusServiceUnavailable, GRPCUnavailable},
+		ErrOverflow:      {DomainResource, ErrSeverityError, false, http.StatusBadRequest, GRPCOutOfRange},
+		ErrExists:        {DomainSystem, ErrSeverityWarning, false, http.StatusConflict, GRPCAlreadyExists},
+		ErrNotFound:      {DomainSystem, ErrSeverityWarning, false, http.StatusNotFound, GRPCNotFound},
+		ErrConfig:        {DomainSystem, ErrSeverityError, false, http.StatusInternalServerError, GRPCFailedPrecondition},
+		ErrMonitor:       {DomainMonitor, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		ErrInvalidConfig: {DomainSystem, ErrSeverityError, false, http.StatusBadRequest, GRPCInvalidArgument},
+		ErrInvalidState:  {DomainSystem, ErrSeverityWarning, false, http.StatusConflict, GRPCFailedPrecondition},
+		ErrTimeout:       {DomainSystem, ErrSeverityError, true, http.StatusGatewayTimeout, GRPCDeadlineExceeded},
+		ErrIO:            {DomainSystem, ErrSeverityError, true, http.StatusInternalServerError, GRPCUnavailable},
+		ErrState:         {DomainSystem, ErrSeverityWarning, false, http.StatusConflict, GRPCFailedPrecondition},
+		ErrInit:          {DomainSystem, ErrSeverityCritical, false, http.StatusInternalServerError, GRPCInternal},
+		ErrCodeModel:     {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		ErrQueue:         {DomainSystem, ErrSeverityError, true, http.StatusServiceUnavailable, GRPCUnavailable},
+		ErrQueueFull:     {DomainSystem, ErrSeverityWarning, true, http.StatusTooManyRequests, GRPCResourceExhausted},
+
+		// 模型层
+		model.ErrCodeOperation:  {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeTransform:  {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeSync:       {DomainModel, ErrSeverityWarning, true, http.StatusConflict, GRPCAborted},
+		model.ErrCodeValidation: {DomainModel, ErrSeverityWarning, false, http.StatusBadRequest, GRPCInvalidArgument},
+		model.ErrCodeModel:      {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeYinYang:    {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeWuXing:     {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeBaGua:      {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeGanZhi:     {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeResource:   {DomainResource, ErrSeverityError, true, http.StatusTooManyRequests, GRPCResourceExhausted},
+		model.ErrCodeEnergy:     {DomainModel, ErrSeverityWarning, false, http.StatusConflict, GRPCFailedPrecondition},
+		model.ErrCodeField:      {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeQuantum:    {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeNotFound:   {DomainModel, ErrSeverityWarning, false, http.StatusNotFound, GRPCNotFound},
+		model.ErrCodeDuplicate:  {DomainModel, ErrSeverityWarning, false, http.StatusConflict, GRPCAlreadyExists},
+		model.ErrCodeLimit:      {DomainResource, ErrSeverityWarning, true, http.StatusTooManyRequests, GRPCResourceExhausted},
+		model.ErrCodeInvalid:    {DomainModel, ErrSeverityWarning, false, http.StatusBadRequest, GRPCInvalidArgument},
+		model.ErrCodeRange:      {DomainModel, ErrSeverityWarning, false, http.StatusBadRequest, GRPCOutOfRange},
+		model.ErrCodeInternal:   {DomainModel, ErrSeverityCritical, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeIO:         {DomainModel, ErrSeverityError, true, http.StatusInternalServerError, GRPCUnavailable},
+		model.ErrCodeComponent:  {DomainModel, ErrSeverityError, true, http.StatusServiceUnavailable, GRPCUnavailable},
+		model.ErrCodeCritical:   {DomainModel, ErrSeverityCritical, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeError:      {DomainModel, ErrSeverityError, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeWarning:    {DomainModel, ErrSeverityWarning, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeInfo:       {DomainModel, ErrSeverityInfo, false, http.StatusInternalServerError, GRPCInternal},
+		model.ErrCodeDependency: {DomainModel, ErrSeverityError, true, http.StatusServiceUnavailable, GRPCUnavailable},
+		model.ErrCodeDeadline:   {DomainModel, ErrSeverityError, true, http.StatusGatewayTimeout, GRPCDeadlineExceeded},
+		model.ErrCodeInterval:   {DomainModel, ErrSeverityWarning, false, http.StatusBadRequest, GRPCInvalidArgument},
+		model.ErrCodeConsensus:  {DomainControl, ErrSeverityError, true, http.StatusConflict, GRPCAborted},
+		model.ErrCodeQuorum:     {DomainControl, ErrSeverityError, true, http.StatusServiceUnavailable, GRPCUnavailable},
+		model.ErrCodeVote:       {DomainControl, ErrSeverityWarning, true, http.StatusConflict, GRPCAborted},
+		model.ErrCodeAgreement:  {DomainControl, ErrSeverityWarning, true, http.StatusConflict, GRPCAborted},
+	},
+}
+
+// RegisterErrorClass 登记或覆盖错误码分类, 供扩展模块定义自己的错误码
+func RegisterErrorClass(code ErrorCode, class ErrorClass) {
+	errorClasses.mu.Lock()
+	defer errorClasses.mu.Unlock()
+	errorClasses.classes[code] = class
+}
+
+// LookupErrorClass 查询错误码分类, 未登记时返回默认分类
+func LookupErrorClass(code ErrorCode) (ErrorClass, bool) {
+	errorClasses.mu.RLock()
+	defer errorClasses.mu.RUnlock()
+	class, ok := errorClasses.classes[code]
+	if !ok {
+		return defaultErrorClass, false
+	}
+	return class, true
+}
+
+// Domain 层级对应的错误领域
+func (l SystemLayer) Domain() ErrorDomain {
+	switch l {
+	case LayerMeta:
+		return DomainMeta
+	case LayerEvolution:
+		return DomainEvolution
+	case LayerControl:
+		return DomainControl
+	case LayerResource:
+		return DomainResource
+	case LayerMonitor:
+		return DomainMonitor
+	default:
+		return DomainSystem
+	}
+}
+
+// NewDomainError 创建指定领域的系统错误
+func NewDomainError(domain ErrorDomain, code ErrorCode, message string, cause error) *SystemError {
+	return NewSystemError(code, message, cause).WithDomain(domain)
+}
+
+// ClassOf 获取错误分类
+// 沿错误链查找首个系统错误或模型错误; 系统错误上显式设置的领域、级别和重试标志优先于错误码分类
+func ClassOf(err error) ErrorClass {
+	if err == nil {
+		return ErrorClass{Severity: ErrSeverityInfo, HTTPStatus: http.StatusOK, GRPCCode: GRPCOK}
+	}
+
+	var sysErr *SystemError
+	if errors.As(err, &sysErr) && sysErr != nil {
+		class, _ := LookupErrorClass(sysErr.Code)
+		class.Domain = sysErr.Domain
+		class.Severity = sysErr.Severity
+		class.Retryable = sysErr.Retryable
+		return class
+	}
+
+	var modelErr *model.ModelError
+	if errors.As(err, &modelErr) && modelErr != nil {
+		class, ok := LookupErrorClass(modelErr.Code)
+		if !ok {
+			class.Domain = DomainModel
+		}
+		return class
+	}
+
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		class, _ := LookupErrorClass(ErrTimeout)
+		return class
+	case errors.Is(err, context.Canceled):
+		return ErrorClass{
+			Domain:     DomainSystem,
+			Severity:   ErrSeverityInfo,
+			HTTPStatus: statusClientClosed,
+			GRPCCode:   GRPCCanceled,
+		}
+	}
+	return defaultErrorClass
+}
+
+// IsCode 检查错误链中是否有指定错误码的系统错误或模型错误
+func IsCode(err error, code ErrorCode) bool {
+	for _, e := range flattenErrors(err) {
+		switch v := e.(type) {
+		case *SystemError:
+			if v != nil && v.Code == code {
+				return true
+			}
+		case *model.ModelError:
+			if v != nil && v.Code == code {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// IsRetryable 检查错误是否可重试
+func IsRetryable(err error) bool {
+	return err != nil && ClassOf(err).Retryable
+}
+
+// SeverityOf 获取错误严重级别
+func SeverityOf(err error) ErrorSeverity {
+	return ClassOf(err).Severity
+}
+
+// DomainOf 获取错误领域
+func DomainOf(err error) ErrorDomain {
+	return ClassOf(err).Domain
+}
+
+// HTTPStatus 错误对应的HTTP状态码, nil为200
+func HTTPStatus(err error) int {
+	return ClassOf(err).HTTPStatus
+}
+
+// GRPCStatus 错误对应的gRPC状态码, nil为OK
+func GRPCStatus(err error) GRPCCode {
+	return ClassOf(err).GRPCCode
+}
+
+// flattenErrors 按深度优先展开错误链, 包括errors.Join产生的多重错误
+func flattenErrors(err error) []error {
+	list := make([]error, 0)
+	var walk func(error)
+	walk = func(e error) {
+		if e == nil {
+			return
+		}
+		list = append(list, e)
+		switch u := e.(type) {
+		case interface{ Unwrap() []error }:
+			for _, inner := range u.Unwrap() {
+				walk(inner)
+			}
+		case interface{ Unwrap() error }:
+			walk(u.Unwrap())
+		}
+	}
+	walk(err)
+	return list
+}
@@ -0,0 +1,94 @@
+// system/types/trace_context.go
+
+package types
+
+import (
+	"context"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+)
+
+// TraceContext 跨子系统的因果追踪上下文, 随检测结果、匹配、事件和策略决策传播
+// SpanID为产生当前对象的跨度, 下游处理以其为父跨度, 追踪分析器据此重建端到端因果链
+type TraceContext struct {
+	TraceID TraceID `json:"trace_id,omitempty"`
+	SpanID  SpanID  `json:"span_id,omitempty"`
+}
+
+// CausalSpan 子系统上报的已完成因果跨度
+type CausalSpan struct {
+	Trace     TraceContext      // 跨度自身的上下文
+	Parent    SpanID            // 父跨度, 为空时为追踪的根
+	Name      string            // 跨度名称, 如"emergence.detect"
+	Subsystem string            // 所属子系统
+	Start     time.Time         // 为零时取End
+	End       time.Time         // 为零时取上报时间
+	Tags      map[string]string // 关联对象的标识等
+	Error     string            // 失败原因, 为空表示成功
+}
+
+// SpanReporter 因果跨度的接收方, 实现须并发安全且不阻塞
+type SpanReporter interface {
+	ReportSpan(span CausalSpan)
+}
+
+// traceContextKey context.Context中追踪上下文的键
+type traceContextKey struct{}
+
+// NewTraceContext 开始新追踪的根上下文
+func NewTraceContext() TraceContext {
+	return TraceContext{
+		TraceID: TraceID(core.NewID("trace_")),
+		SpanID:  SpanID(core.NewID("span_")),
+	}
+}
+
+// IsZero 是否未关联任何追踪
+func (tc TraceContext) IsZero() bool {
+	return tc.TraceID == ""
+}
+
+// Child 同一追踪中的子跨度上下文, 未关联追踪时开始新追踪
+func (tc TraceContext) Child() TraceContext {
+	if tc.IsZero() {
+		return NewTraceContext()
+	}
+	return TraceContext{TraceID: tc.TraceID, SpanID: SpanID(core.NewID("span_"))}
+}
+
+// ContextWithTrace 将追踪上下文附加到ctx, 接受ctx的处理以其为父跨度
+func ContextWithTrace(ctx context.Context, tc TraceContext) context.Context {
+	return context.WithValue(ctx, traceContextKey{}, tc)
+}
+
+// TraceFromContext 获取ctx携带的追踪上下文
+func TraceFromContext(ctx context.Context) (TraceContext, bool) {
+	if ctx == nil {
+		return TraceContext{}, false
+	}
+	tc, ok := ctx.Value(traceContextKey{}).(TraceContext)
+	return tc, ok && !tc.IsZero()
+}
+
+// EmitSpan 补全时间后上报跨度, reporter为nil时忽略
+func EmitSpan(reporter SpanReporter, span CausalSpan) {
+	if reporter == nil {
+		return
+	}
+	if span.End.IsZero() {
+		span.End = time.Now()
+	}
+	if span.Start.IsZero() {
+		span.Start = span.End
+	}
+	reporter.ReportSpan(span)
+}
+
+// ChildSpan 以parent为父跨度上报跨度, 返回该跨度的上下文; parent未关联追踪时开始新追踪
+func ChildSpan(reporter SpanReporter, parent TraceContext, span CausalSpan) TraceContext {
+	span.Trace = parent.Child()
+	span.Parent = parent.SpanID
+	EmitSpan(reporter, span)
+	return span.Trace
+}
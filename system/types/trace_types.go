@@ -3,6 +3,7 @@
 package types
 
 import (
+	"strings"
 	"time"
 
 	"github.com/Corphon/daoflow/model"
@@ -110,13 +111,68 @@ type TraceConfig struct {
 	AsyncWrite       bool          // 异步写入
 
 	// 采样配置
-	SampleRate   float64 // 采样率
-	MaxQueueSize int     // 最大队列大小
+	SampleRate   float64        // 采样率
+	MaxQueueSize int            // 最大队列大小
+	Sampling     SamplingConfig // 采样策略
 
 	// 追踪选项
 	EnableMetrics bool // 启用指标采集
 	EnableEvents  bool // 启用事件记录
 	IncludeModel  bool // 包含模型信息
+
+	// 分析选项
+	CriticalPathTopN int                  // 关键路径保留的跨度数
+	Tuning           IntervalTuningConfig // 分析间隔自动调节
+	Cache            TraceCacheConfig     // 分析结果缓存
+	Rollup           TraceRollupConfig    // 分析结果汇总
+	StageTimeouts    StageTimeoutConfig   // 各分析阶段的时间预算
+}
+
+// StageTimeoutConfig 追踪分析阶段的时间预算, 超出预算的阶段被跳过
+type StageTimeoutConfig struct {
+	Default time.Duration            `json:"default"` // 未单独配置的阶段使用的预算, 为0时使用默认值
+	Stages  map[string]time.Duration `json:"stages"`  // 按阶段名称(system, model, quantum, field)配置的预算
+}
+
+// TraceRollupConfig 追踪分析结果汇总配置
+type TraceRollupConfig struct {
+	HourlyRetention int     `json:"hourly_retention"` // 保留的小时桶数
+	DailyRetention  int     `json:"daily_retention"`  // 保留的天桶数
+	SketchAccuracy  float64 `json:"sketch_accuracy"`  // 耗时分位数草图的相对误差, 为0时使用默认值(1%)
+}
+
+// LatencyQuantiles 耗时分位数, 由分位数草图估算, 相对误差见TraceRollupConfig.SketchAccuracy
+type LatencyQuantiles struct {
+	Count int64         `json:"count"`
+	Mean  time.Duration `json:"mean"`
+	P50   time.Duration `json:"p50"`
+	P90   time.Duration `json:"p90"`
+	P99   time.Duration `json:"p99"`
+	P999  time.Duration `json:"p999"`
+	Max   time.Duration `json:"max"`
+}
+
+// TraceCacheConfig 追踪分析结果缓存配置
+type TraceCacheConfig struct {
+	MaxEntries int           `json:"max_entries"` // 最大条目数, 超出时驱逐最近最少使用的结果
+	TTL        time.Duration `json:"ttl"`         // 按分析时间计算的保留时间
+}
+
+// 采样策略
+const (
+	SamplingProbabilistic = "probabilistic" // 按追踪概率采样
+	SamplingRateLimited   = "rate_limited"  // 按每秒追踪数限流
+	SamplingTail          = "tail"          // 追踪结束后决策, 保留错误和异常
+)
+
+// SamplingConfig 采样策略配置
+type SamplingConfig struct {
+	Strategy         string        `json:"strategy"`          // 采样策略, 为空时按SampleRate概率采样
+	Rate             float64       `json:"rate"`              // 概率采样率, 尾部采样时用于普通追踪
+	MaxPerSecond     float64       `json:"max_per_second"`    // 每秒最多采样追踪数
+	DecisionWait     time.Duration `json:"decision_wait"`     // 尾部采样等待追踪结束的时间
+	MaxTraces        int           `json:"max_traces"`        // 尾部采样最多缓冲的追踪数
+	LatencyThreshold time.Duration `json:"latency_threshold"` // 超过该耗时的追踪始终保留
 }
 
 // TracePattern 追踪模式
@@ -132,13 +188,35 @@ type TracePattern struct {
 
 // Bottleneck 系统瓶颈
 type Bottleneck struct {
-	ID         string        // 瓶颈ID
-	Type       string        // 瓶颈类型
-	Resource   string        // 资源类型
-	Severity   float64       // 严重程度
-	Impact     float64       // 影响程度
-	Duration   time.Duration // 持续时间
-	Suggestion string        // 改进建议
+	ID         string             // 瓶颈ID
+	Type       string             // 瓶颈类型
+	Resource   string             // 资源类型
+	Severity   float64            // 严重程度
+	Impact     float64            // 影响程度
+	Duration   time.Duration      // 持续时间
+	Suggestion string             // 改进建议
+	Location   BottleneckLocation // 瓶颈所在位置, 由跨度的子系统、名称和模型信息归因
+}
+
+// BottleneckLocation 瓶颈所在位置, 未知的部分为空
+type BottleneckLocation struct {
+	Subsystem string  // 子系统, 如meta.emergence
+	Stage     string  // 处理阶段即跨度名称, 如emergence.detect、resonance.match
+	Model     string  // 模型类型, 如yinyang
+	Phase     string  // 模型相位, 如transform、stable
+	SpanID    SpanID  // 该位置上贡献最大的跨度
+	Share     float64 // 该位置在追踪的耗时或资源用量中的占比
+}
+
+// String 以"/"连接位置中非空的部分
+func (l BottleneckLocation) String() string {
+	parts := make([]string, 0, 4)
+	for _, part := range []string{l.Subsystem, l.Stage, l.Model, l.Phase} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
 }
 
 //--------------------------------------
@@ -0,0 +1,180 @@
+// system/types/warmup.go
+
+package types
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// 预热期间适应动作的处理方式
+const (
+	WarmupSuppress = "suppress" // 不执行适应动作
+	WarmupDamp     = "damp"     // 执行适应动作, 数值参数按Damping缩小
+)
+
+// 默认的动作参数倍率
+const defaultWarmupDamping = 0.5
+
+// WarmupConfig 启动预热配置, Duration和Samples均为零时不预热
+// 两者都设置时须同时满足才结束预热
+type WarmupConfig struct {
+	Duration time.Duration `json:"duration"` // 预热时长
+	Samples  int           `json:"samples"`  // 预热所需的检测周期数
+	Action   string        `json:"action"`   // 预热期间的适应动作处理, 为空时为WarmupSuppress
+	Damping  float64       `json:"damping"`  // WarmupDamp时数值参数的倍率(0-1], 为零时取0.5
+}
+
+// Validate 校验配置
+func (c WarmupConfig) Validate() error {
+	if c.Duration < 0 || c.Samples < 0 {
+		return NewSystemError(ErrInvalid, "negative warm-up duration or samples", nil)
+	}
+	switch c.Action {
+	case "", WarmupSuppress, WarmupDamp:
+	default:
+		return NewSystemError(ErrInvalid, "unknown warm-up action", nil).
+			WithContext("action", c.Action)
+	}
+	if c.Damping < 0 || c.Damping > 1 || math.IsNaN(c.Damping) {
+		return NewSystemError(ErrInvalid, "warm-up damping must be in (0, 1]", nil).
+			WithContext("damping", c.Damping)
+	}
+	return nil
+}
+
+// WarmupStatus 预热状态
+type WarmupStatus struct {
+	Active          bool          `json:"active"`           // 是否处于预热期
+	Started         time.Time     `json:"started"`          // 预热开始时间
+	Completed       time.Time     `json:"completed"`        // 预热结束时间, 未结束时为零
+	Duration        time.Duration `json:"duration"`         // 配置的预热时长
+	Elapsed         time.Duration `json:"elapsed"`          // 已经过的时长
+	Samples         int           `json:"samples"`          // 已观测的检测周期数
+	RequiredSamples int           `json:"required_samples"` // 配置的检测周期数
+	Progress        float64       `json:"progress"`         // 预热进度(0-1), 取各条件中最慢的一项
+	Action          string        `json:"action"`           // 预热期间的适应动作处理
+}
+
+// Warmup 启动预热跟踪, 并发安全; nil视为无需预热
+// 预热期间检测结果标记为临时, 适应动作按配置停用或减弱
+type Warmup struct {
+	mu sync.Mutex
+
+	config    WarmupConfig
+	started   time.Time
+	completed time.Time
+	samples   int
+}
+
+// NewWarmup 创建预热跟踪, 以当前时间开始预热
+func NewWarmup(cfg WarmupConfig) *Warmup {
+	if cfg.Action == "" {
+		cfg.Action = WarmupSuppress
+	}
+	if cfg.Damping == 0 {
+		cfg.Damping = defaultWarmupDamping
+	}
+	return &Warmup{config: cfg, started: time.Now()}
+}
+
+// Restart 重新开始预热, 如系统重启后
+func (w *Warmup) Restart() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.started = time.Now()
+	w.completed = time.Time{}
+	w.samples = 0
+}
+
+// Observe 记录一个检测周期
+func (w *Warmup) Observe() {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.completed.IsZero() {
+		w.samples++
+	}
+}
+
+// Active 是否处于预热期
+func (w *Warmup) Active() bool {
+	if w == nil {
+		return false
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.active(time.Now())
+}
+
+// Suppressed 预热期间是否停用适应动作
+func (w *Warmup) Suppressed() bool {
+	return w.Active() && w.config.Action == WarmupSuppress
+}
+
+// Damping 适应动作数值参数的倍率, 预热结束或停用动作时为1
+func (w *Warmup) Damping() float64 {
+	if !w.Active() || w.config.Action != WarmupDamp {
+		return 1
+	}
+	return w.config.Damping
+}
+
+// Status 获取预热状态
+func (w *Warmup) Status() WarmupStatus {
+	if w == nil {
+		return WarmupStatus{Progress: 1}
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	now := time.Now()
+	status := WarmupStatus{
+		Active:          w.active(now),
+		Started:         w.started,
+		Completed:       w.completed,
+		Duration:        w.config.Duration,
+		Elapsed:         now.Sub(w.started),
+		Samples:         w.samples,
+		RequiredSamples: w.config.Samples,
+		Progress:        w.progress(now),
+		Action:          w.config.Action,
+	}
+	if !status.Completed.IsZero() {
+		status.Elapsed = status.Completed.Sub(w.started)
+	}
+	return status
+}
+
+// active 是否处于预热期, 条件满足时记录结束时间(调用方持有锁)
+func (w *Warmup) active(now time.Time) bool {
+	if !w.completed.IsZero() {
+		return false
+	}
+	if w.progress(now) < 1 {
+		return true
+	}
+	w.completed = now
+	return false
+}
+
+// progress 预热进度, 取各条件中最慢的一项(调用方持有锁)
+func (w *Warmup) progress(now time.Time) float64 {
+	if !w.completed.IsZero() {
+		return 1
+	}
+	progress := 1.0
+	if w.config.Duration > 0 {
+		progress = math.Min(progress, float64(now.Sub(w.started))/float64(w.config.Duration))
+	}
+	if w.config.Samples > 0 {
+		progress = math.Min(progress, float64(w.samples)/float64(w.config.Samples))
+	}
+	return math.Max(progress, 0)
+}
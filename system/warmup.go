@@ -0,0 +1,43 @@
+// system/warmup.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// startWarmup 重新开始启动预热, 接入全局检测器和适应策略(调用方持有锁)
+// 全局检测器的检测周期计为预热样本, 预热期间检测结果标记为临时, 适应动作按配置停用或减弱
+func (s *System) startWarmup() {
+	if s.warmup == nil {
+		return
+	}
+	s.warmup.Restart()
+	if detector := s.meta.GetDetector(); detector != nil {
+		detector.SetWarmup(s.warmup)
+	}
+	if strategy := s.evolution.GetStrategy(); strategy != nil {
+		strategy.SetWarmup(s.warmup)
+	}
+}
+
+// newNamespaceWarmup 新命名空间的场同样从冷启动开始, 其检测器使用独立的预热
+func (s *System) newNamespaceWarmup() *types.Warmup {
+	if s.config.Warmup == nil {
+		return nil
+	}
+	return types.NewWarmup(*s.config.Warmup)
+}
+
+// WarmupStatus 获取全局启动预热状态, 未配置预热时为已完成
+func (s *System) WarmupStatus() types.WarmupStatus {
+	return s.warmup.Status()
+}
+
+// boolMetric 布尔指标值
+func boolMetric(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,116 @@
+// system/watchdog.go
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"github.com/Corphon/daoflow/system/control/watchdog"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// restorableSubsystems 可由RestoreSubsystem恢复的子系统
+var restorableSubsystems = []string{"common", "control", "evolution", "meta", "monitor"}
+
+// initWatchdog 为看门狗接入监视对象、恢复方法和事件
+func (s *System) initWatchdog() {
+	dog := s.control.GetWatchdog()
+	if dog == nil {
+		return
+	}
+
+	components := s.subsystems()
+	for _, name := range restorableSubsystems {
+		if component, ok := components[name]; ok {
+			dog.Watch(name, component)
+		}
+	}
+	dog.SetRestore(s.watchdogRestore)
+	dog.OnEvent(s.onWatchdogEvent)
+}
+
+// startWatchdog 启动看门狗, 调用方需持有锁
+// 看门狗以系统上下文运行而非控制子系统的上下文, 控制子系统本身异常时仍能恢复它
+func (s *System) startWatchdog() {
+	dog := s.control.GetWatchdog()
+	if dog == nil || !dog.Enabled() {
+		return
+	}
+
+	s.stopWatchdog()
+	ctx, cancel := context.WithCancel(s.ctx)
+	s.watchdogCancel = cancel
+	dog.Start(ctx)
+}
+
+// stopWatchdog 停止看门狗, 调用方需持有锁
+func (s *System) stopWatchdog() {
+	if s.watchdogCancel != nil {
+		s.watchdogCancel()
+		s.watchdogCancel = nil
+	}
+}
+
+// WatchdogStatus 获取看门狗监视的子系统状态
+func (s *System) WatchdogStatus() []watchdog.Status {
+	dog := s.control.GetWatchdog()
+	if dog == nil {
+		return nil
+	}
+	return dog.Status()
+}
+
+// watchdogRestore 看门狗的恢复方法, 系统已停止时不恢复
+func (s *System) watchdogRestore(name string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.isRunning {
+		return types.ErrNotRunning
+	}
+	return s.restoreSubsystem(name)
+}
+
+// onWatchdogEvent 记录恢复失败和降级, 转发看门狗事件
+// 有子系统降级时正常运行的系统自动切换到降级模式, 全部恢复后回到正常模式; 手动设置的模式不变
+func (s *System) onWatchdogEvent(event watchdog.Event) {
+	priority := types.PriorityHigh
+	switch event.Type {
+	case types.EventSubsystemRecovered:
+		priority = types.PriorityNormal
+	case types.EventSubsystemDegraded:
+		priority = types.PriorityHighest
+	}
+
+	degraded := len(s.control.GetWatchdog().Degraded()) > 0
+	s.mu.Lock()
+	if event.Status.LastError != "" || event.Type == types.EventSubsystemDegraded {
+		// 不经recordError, 其持锁调用HandleEvent
+		err := types.NewSystemError(types.ErrRuntime, "subsystem recovery failed", nil).
+			WithSeverity(types.ErrSeverityCritical).
+			WithRetryable(event.Type != types.EventSubsystemDegraded).
+			WithDetails(event.Status.LastError).
+			WithContext("subsystem", event.Status.Name).
+			WithContext("retries", event.Status.Retries)
+		s.appendErrorLocked(err)
+	}
+	mode := s.mode
+	s.mu.Unlock()
+
+	switch {
+	case degraded && mode.Mode == types.ModeNormal:
+		s.setMode(types.ModeDegraded, "subsystem "+event.Status.Name+" degraded after recovery retries", true)
+	case !degraded && mode.Mode == types.ModeDegraded && mode.Auto:
+		s.setMode(types.ModeNormal, "degraded subsystems recovered", true)
+	}
+
+	s.HandleEvent(types.SystemEvent{
+		Type:      event.Type,
+		Source:    event.Status.Name,
+		Timestamp: time.Now(),
+		Message:   string(event.Status.State),
+		Priority:  priority,
+		Data:      event.Status,
+	})
+}
@@ -0,0 +1,47 @@
+// system/wuxing.go
+
+package system
+
+import (
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/evolution/wuxing"
+)
+
+// patternBiasSource 涌现模式偏置来源名称
+const patternBiasSource = "emergence"
+
+// startWuXingScheduling 为五行能量调度器接入五行模型和涌现模式偏置
+// 活跃模式中以元素命名的组件按模式强度和组件权重加强该元素的相生路由
+func (s *System) startWuXingScheduling() {
+	scheduler := s.evolution.GetScheduler()
+	if scheduler == nil || s.modelManager == nil {
+		return
+	}
+	if flow := s.modelManager.GetWuXingFlow(); flow != nil {
+		scheduler.SetTarget(flow)
+	}
+
+	detector := s.meta.GetDetector()
+	if detector == nil {
+		return
+	}
+	scheduler.RegisterBiasSource(patternBiasSource, func(map[model.WuXingElement]float64) []wuxing.Bias {
+		biases := make([]wuxing.Bias, 0)
+		for _, p := range detector.GetActivePatternsSnapshot().Patterns() {
+			for _, c := range p.Components {
+				elem, ok := model.WuXingElementFromString(c.Type)
+				if !ok {
+					continue
+				}
+				biases = append(biases, wuxing.Bias{
+					ID:      p.ID + "/" + c.ID,
+					Source:  p.ID,
+					Element: elem,
+					Cycle:   model.GeneratingCycle,
+					Factor:  1 + p.Strength*c.Weight,
+				})
+			}
+		}
+		return biases
+	})
+}
@@ -0,0 +1,254 @@
+// testutil/generator/field.go
+
+// Package generator 生成参数可控的合成场状态和涌现模式, 用作测试和基准的数据
+package generator
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/Corphon/daoflow/core"
+	"github.com/Corphon/daoflow/model"
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// FieldSpec 场状态生成参数
+type FieldSpec struct {
+	// 网格尺寸, Depth为0时生成二维场
+	Width  int
+	Height int
+	Depth  int
+
+	// 能量聚集
+	Clusters      int     // 聚集数
+	ClusterEnergy float64 // 聚集中心能量
+	ClusterRadius float64 // 聚集的高斯半径(格)
+	Drift         float64 // 聚集中心每步随机漂移距离(格)
+
+	// 背景能量
+	Background float64    // 背景能量
+	Gradient   [3]float64 // 沿x/y/z每格的能量梯度
+
+	// 量子相干性(0-1), 以退相干程度控制
+	Coherence float64
+
+	// 五行构成, 各元素的个数
+	Composition map[model.WuXingElement]int
+
+	// 噪声
+	EnergyNoise    float64 // 各格能量的高斯噪声标准差
+	CoherenceNoise float64 // 相干性的高斯噪声标准差
+
+	// 序列时间
+	Start time.Time
+	Step  time.Duration
+
+	Seed int64 // 随机种子, 相同参数和种子生成相同序列
+}
+
+// DefaultFieldSpec 默认生成参数: 16x16场, 3个聚集, 五行各一
+func DefaultFieldSpec() FieldSpec {
+	return FieldSpec{
+		Width:         16,
+		Height:        16,
+		Clusters:      3,
+		ClusterEnergy: 50,
+		ClusterRadius: 2,
+		Background:    1,
+		Coherence:     0.8,
+		Composition: map[model.WuXingElement]int{
+			model.Wood: 1, model.Fire: 1, model.Earth: 1, model.Metal: 1, model.Water: 1,
+		},
+		Start: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		Step:  time.Second,
+	}
+}
+
+// Validate 验证生成参数
+func (s FieldSpec) Validate() error {
+	switch {
+	case s.Width <= 0 || s.Height <= 0 || s.Depth < 0:
+		return types.NewSystemError(types.ErrInvalid, "grid size must be positive", nil)
+	case s.Clusters < 0 || s.ClusterEnergy < 0 || s.Background < 0:
+		return types.NewSystemError(types.ErrInvalid, "cluster and background energy must not be negative", nil)
+	case s.Clusters > 0 && s.ClusterRadius <= 0:
+		return types.NewSystemError(types.ErrInvalid, "cluster radius must be positive", nil)
+	case s.Coherence < 0 || s.Coherence > 1:
+		return types.NewSystemError(types.ErrInvalid, "coherence out of range [0,1]", nil).
+			WithContext("coherence", s.Coherence)
+	case s.Drift < 0 || s.EnergyNoise < 0 || s.CoherenceNoise < 0:
+		return types.NewSystemError(types.ErrInvalid, "drift and noise must not be negative", nil)
+	case s.Step < 0:
+		return types.NewSystemError(types.ErrInvalid, "negative time step", nil)
+	}
+	for elem, n := range s.Composition {
+		if n < 0 || elem > model.Water {
+			return types.NewSystemError(types.ErrInvalid, "invalid element composition", nil).
+				WithContext("element", elem.String())
+		}
+	}
+	return nil
+}
+
+// Center 聚集中心(格坐标)
+type Center struct {
+	X, Y, Z float64
+}
+
+// FieldGenerator 合成场状态生成器, 非并发安全
+type FieldGenerator struct {
+	spec    FieldSpec
+	rng     *rand.Rand
+	centers []Center
+	step    int
+}
+
+// NewFieldGenerator 创建场状态生成器
+func NewFieldGenerator(spec FieldSpec) (*FieldGenerator, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	if spec.Depth == 0 {
+		spec.Depth = 1
+	}
+	if spec.Start.IsZero() {
+		spec.Start = DefaultFieldSpec().Start
+	}
+	if spec.Step == 0 {
+		spec.Step = time.Second
+	}
+
+	g := &FieldGenerator{
+		spec: spec,
+		rng:  rand.New(rand.NewSource(spec.Seed)),
+	}
+	g.centers = make([]Center, spec.Clusters)
+	for i := range g.centers {
+		g.centers[i] = Center{
+			X: g.rng.Float64() * float64(spec.Width-1),
+			Y: g.rng.Float64() * float64(spec.Height-1),
+			Z: g.rng.Float64() * float64(spec.Depth-1),
+		}
+	}
+	return g, nil
+}
+
+// Centers 当前的聚集中心, 作为聚集检测的真值
+func (g *FieldGenerator) Centers() []Center {
+	return append([]Center(nil), g.centers...)
+}
+
+// Next 生成下一个场状态, 聚集中心随后按Drift漂移
+func (g *FieldGenerator) Next() *model.FieldState {
+	spec := g.spec
+	at := spec.Start.Add(time.Duration(g.step) * spec.Step)
+
+	distribution := make(map[core.Point]float64, spec.Width*spec.Height*spec.Depth)
+	var total float64
+	for z := 0; z < spec.Depth; z++ {
+		for y := 0; y < spec.Height; y++ {
+			for x := 0; x < spec.Width; x++ {
+				energy := g.energyAt(float64(x), float64(y), float64(z))
+				if spec.EnergyNoise > 0 {
+					energy += g.rng.NormFloat64() * spec.EnergyNoise
+				}
+				energy = math.Max(0, energy)
+				distribution[core.Point{X: x, Y: y, Z: z}] = energy
+				total += energy
+			}
+		}
+	}
+
+	coherence := spec.Coherence
+	if spec.CoherenceNoise > 0 {
+		coherence += g.rng.NormFloat64() * spec.CoherenceNoise
+	}
+	coherence = math.Max(0, math.Min(1, coherence))
+
+	state := &model.FieldState{
+		Energy:       total / float64(len(distribution)),
+		Elements:     g.elements(),
+		Timestamp:    at,
+		Quantum:      newCoherentState(coherence),
+		Distribution: distribution,
+		Properties: map[string]float64{
+			"coherence": coherence,
+			"clusters":  float64(spec.Clusters),
+			"total":     total,
+		},
+	}
+
+	g.drift()
+	g.step++
+	return state
+}
+
+// Sequence 生成n个按时间顺序的场状态
+func (g *FieldGenerator) Sequence(n int) []*model.FieldState {
+	states := make([]*model.FieldState, n)
+	for i := range states {
+		states[i] = g.Next()
+	}
+	return states
+}
+
+// Source 生成n个场状态作为快照来源, 用于回填、调优和评估
+func (g *FieldGenerator) Source(n int) emergence.SnapshotSource {
+	return emergence.NewSliceSource(g.Sequence(n))
+}
+
+// energyAt 无噪声的能量: 背景加梯度加各聚集的高斯峰
+func (g *FieldGenerator) energyAt(x, y, z float64) float64 {
+	spec := g.spec
+	energy := spec.Background + spec.Gradient[0]*x + spec.Gradient[1]*y + spec.Gradient[2]*z
+	for _, c := range g.centers {
+		d2 := (x-c.X)*(x-c.X) + (y-c.Y)*(y-c.Y) + (z-c.Z)*(z-c.Z)
+		energy += spec.ClusterEnergy * math.Exp(-d2/(2*spec.ClusterRadius*spec.ClusterRadius))
+	}
+	return energy
+}
+
+// drift 聚集中心随机漂移, 限制在网格内
+func (g *FieldGenerator) drift() {
+	if g.spec.Drift == 0 {
+		return
+	}
+	for i := range g.centers {
+		c := &g.centers[i]
+		angle := g.rng.Float64() * 2 * math.Pi
+		c.X = clamp(c.X+g.spec.Drift*math.Cos(angle), 0, float64(g.spec.Width-1))
+		c.Y = clamp(c.Y+g.spec.Drift*math.Sin(angle), 0, float64(g.spec.Height-1))
+		if g.spec.Depth > 1 {
+			c.Z = clamp(c.Z+g.spec.Drift*g.rng.NormFloat64(), 0, float64(g.spec.Depth-1))
+		}
+	}
+}
+
+// elements 按五行构成生成元素列表, 顺序固定
+func (g *FieldGenerator) elements() []*model.WuXingElement {
+	elements := make([]*model.WuXingElement, 0)
+	for elem := model.Wood; elem <= model.Water; elem++ {
+		for i := 0; i < g.spec.Composition[elem]; i++ {
+			e := elem
+			elements = append(elements, &e)
+		}
+	}
+	return elements
+}
+
+// newCoherentState 相干性为coherence的量子态: 零相位、满概率, 以退相干程度控制相干性
+func newCoherentState(coherence float64) *core.QuantumState {
+	qs := core.NewQuantumState()
+	// 参数已限制在有效范围内
+	_ = qs.SetPhase(0)
+	_ = qs.SetProbability(1)
+	_ = qs.SetDecoherence(1 - coherence)
+	return qs
+}
+
+// clamp 限制v在[lo, hi]内
+func clamp(v, lo, hi float64) float64 {
+	return math.Max(lo, math.Min(hi, v))
+}
@@ -0,0 +1,144 @@
+// testutil/generator/pattern.go
+
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/Corphon/daoflow/system/meta/emergence"
+	"github.com/Corphon/daoflow/system/types"
+)
+
+// Range 取值范围, Min等于Max时取定值
+type Range struct {
+	Min, Max float64
+}
+
+// sample 在范围内均匀取值
+func (r Range) sample(rng *rand.Rand) float64 {
+	return r.Min + rng.Float64()*(r.Max-r.Min)
+}
+
+// PatternSpec 涌现模式生成参数
+type PatternSpec struct {
+	Type       string // 模式类型
+	Namespace  string // 命名空间, 为空时为默认命名空间
+	Count      int    // 模式数
+	Components int    // 每个模式的组件数
+
+	Strength  Range // 强度
+	Stability Range // 稳定性
+	Energy    Range // 能量
+
+	Evolution int           // 每个模式的演化状态数
+	Noise     float64       // 演化中强度和能量的随机游走步长(相对值)
+	Start     time.Time     // 首个模式的形成时间
+	Step      time.Duration // 模式形成间隔和演化状态间隔
+
+	Seed int64
+}
+
+// DefaultPatternSpec 默认模式生成参数
+func DefaultPatternSpec(patternType string, count int) PatternSpec {
+	return PatternSpec{
+		Type:       patternType,
+		Count:      count,
+		Components: 3,
+		Strength:   Range{Min: 0.5, Max: 0.9},
+		Stability:  Range{Min: 0.5, Max: 0.9},
+		Energy:     Range{Min: 10, Max: 50},
+		Evolution:  10,
+		Noise:      0.05,
+		Start:      DefaultFieldSpec().Start,
+		Step:       time.Second,
+	}
+}
+
+// Validate 验证生成参数
+func (s PatternSpec) Validate() error {
+	switch {
+	case s.Type == "":
+		return types.NewSystemError(types.ErrInvalid, "pattern type is required", nil)
+	case s.Count < 0 || s.Components < 0 || s.Evolution < 0:
+		return types.NewSystemError(types.ErrInvalid, "counts must not be negative", nil)
+	case !validRange(s.Strength, 0, 1) || !validRange(s.Stability, 0, 1):
+		return types.NewSystemError(types.ErrInvalid, "strength and stability must be within [0,1]", nil)
+	case !validRange(s.Energy, 0, math.MaxFloat64):
+		return types.NewSystemError(types.ErrInvalid, "invalid energy range", nil)
+	case s.Noise < 0 || s.Step < 0:
+		return types.NewSystemError(types.ErrInvalid, "noise and step must not be negative", nil)
+	}
+	return nil
+}
+
+// Patterns 按参数生成涌现模式
+func Patterns(spec PatternSpec) ([]emergence.EmergentPattern, error) {
+	if err := spec.Validate(); err != nil {
+		return nil, err
+	}
+	if spec.Namespace == "" {
+		spec.Namespace = string(types.DefaultNamespace)
+	}
+	if spec.Start.IsZero() {
+		spec.Start = DefaultFieldSpec().Start
+	}
+	if spec.Step == 0 {
+		spec.Step = time.Second
+	}
+
+	rng := rand.New(rand.NewSource(spec.Seed))
+	patterns := make([]emergence.EmergentPattern, spec.Count)
+	for i := range patterns {
+		formation := spec.Start.Add(time.Duration(i) * spec.Step)
+		p := emergence.EmergentPattern{
+			ID:         fmt.Sprintf("synthetic_%s_%d", spec.Type, i),
+			Namespace:  spec.Namespace,
+			Type:       spec.Type,
+			Strength:   spec.Strength.sample(rng),
+			Stability:  spec.Stability.sample(rng),
+			Energy:     spec.Energy.sample(rng),
+			Formation:  formation,
+			LastUpdate: formation,
+			Properties: map[string]float64{"synthetic": 1},
+		}
+		for c := 0; c < spec.Components; c++ {
+			p.Components = append(p.Components, emergence.PatternComponent{
+				ID:     fmt.Sprintf("%s_c%d", p.ID, c),
+				Type:   spec.Type,
+				Weight: 1 / float64(spec.Components),
+				Role:   "member",
+			})
+		}
+
+		strength, energy := p.Strength, p.Energy
+		for e := 0; e < spec.Evolution; e++ {
+			at := formation.Add(time.Duration(e) * spec.Step)
+			if e > 0 {
+				strength = clamp(strength*(1+rng.NormFloat64()*spec.Noise), 0, 1)
+				energy = math.Max(0, energy*(1+rng.NormFloat64()*spec.Noise))
+			}
+			p.Evolution = append(p.Evolution, emergence.PatternState{
+				Active:     true,
+				Duration:   at.Sub(formation),
+				Strength:   strength,
+				Energy:     energy,
+				LastUpdate: at,
+				Timestamp:  at,
+			})
+			p.LastUpdate = at
+		}
+		if spec.Evolution > 0 {
+			p.Strength, p.Energy = strength, energy
+		}
+		patterns[i] = p
+	}
+	return patterns, nil
+}
+
+// validRange 范围有序且在[lo, hi]内
+func validRange(r Range, lo, hi float64) bool {
+	return r.Min >= lo && r.Max <= hi && r.Min <= r.Max
+}